@@ -16,6 +16,7 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -45,6 +46,7 @@ import (
 
 	"github.com/argoproj/argo-cd/v2/applicationset/controllers/template"
 	"github.com/argoproj/argo-cd/v2/applicationset/generators"
+	"github.com/argoproj/argo-cd/v2/applicationset/notifications"
 	"github.com/argoproj/argo-cd/v2/applicationset/status"
 	"github.com/argoproj/argo-cd/v2/applicationset/utils"
 	"github.com/argoproj/argo-cd/v2/common"
@@ -55,6 +57,7 @@ import (
 	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned"
 	argoutil "github.com/argoproj/argo-cd/v2/util/argo"
 	"github.com/argoproj/argo-cd/v2/util/argo/normalizers"
+	argosettings "github.com/argoproj/argo-cd/v2/util/settings"
 
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
 )
@@ -65,6 +68,14 @@ const (
 	//   https://github.com/argoproj-labs/argocd-notifications/blob/33d345fa838829bb50fca5c08523aba380d2c12b/pkg/controller/state.go#L17
 	NotifiedAnnotationKey             = "notified.notifications.argoproj.io"
 	ReconcileRequeueOnValidationError = time.Minute * 3
+	// ReservedMetadataKeyPrefix is the prefix of the labels and annotations reserved to Argo CD, which are never
+	// propagated from an ApplicationSet to its Applications
+	ReservedMetadataKeyPrefix = "argocd.argoproj.io/"
+	// NotificationsMetadataKeyPrefix is the prefix of the notification subscription annotations, which are never
+	// propagated so that notifications about an ApplicationSet are not sent for each of its Applications
+	NotificationsMetadataKeyPrefix = "notifications.argoproj.io/"
+	// NotifiedMetadataKeyPrefix is the prefix of the annotations recording the notifications already sent
+	NotifiedMetadataKeyPrefix = "notified."
 )
 
 var defaultPreservedAnnotations = []string{
@@ -91,6 +102,11 @@ type ApplicationSetReconciler struct {
 	GlobalPreservedAnnotations []string
 	GlobalPreservedLabels      []string
 	Cache                      cache.Cache
+	// GeneratorFailureNotifier sends notifications about generator failures. Notifications are disabled when nil.
+	GeneratorFailureNotifier *notifications.GeneratorFailureNotifier
+	// SettingsMgr provides the configured application instance label key, which is never propagated to the
+	// generated Applications. The default label key is used when nil.
+	SettingsMgr *argosettings.SettingsManager
 }
 
 // +kubebuilder:rbac:groups=argoproj.io,resources=applicationsets,verbs=get;list;watch;create;update;patch;delete
@@ -147,10 +163,16 @@ func (r *ApplicationSetReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 				Status:  argov1alpha1.ApplicationSetConditionStatusTrue,
 			}, parametersGenerated,
 		)
+		r.notifyGeneratorFailure(logCtx, &applicationSetInfo, err)
 		return ctrl.Result{RequeueAfter: ReconcileRequeueOnValidationError}, err
 	}
 
 	parametersGenerated = true
+	if r.GeneratorFailureNotifier != nil {
+		r.GeneratorFailureNotifier.Resolve(&applicationSetInfo)
+	}
+
+	propagateApplicationSetMetadata(applicationSetInfo, desiredApplications, r.getAppInstanceLabelKey(logCtx))
 
 	validateErrors, err := r.validateGeneratedApplications(ctx, desiredApplications, applicationSetInfo)
 	if err != nil {
@@ -337,6 +359,81 @@ func (r *ApplicationSetReconciler) Reconcile(ctx context.Context, req ctrl.Reque
 	}, nil
 }
 
+// notifyGeneratorFailure sends a notification about the failure of a generator of the ApplicationSet, if notifications
+// are enabled
+func (r *ApplicationSetReconciler) notifyGeneratorFailure(logCtx *log.Entry, applicationSet *argov1alpha1.ApplicationSet, err error) {
+	var generatorErr *template.GeneratorError
+	if r.GeneratorFailureNotifier == nil || !errors.As(err, &generatorErr) {
+		return
+	}
+	if err := r.GeneratorFailureNotifier.NotifyFailure(applicationSet, generatorErr.GeneratorType, generatorErr.Err); err != nil {
+		logCtx.WithError(err).Warn("failed to queue generator failure notification")
+	}
+}
+
+// getAppInstanceLabelKey returns the label key used to track the resources of an Application
+func (r *ApplicationSetReconciler) getAppInstanceLabelKey(logCtx *log.Entry) string {
+	if r.SettingsMgr == nil {
+		return common.LabelKeyAppInstance
+	}
+	labelKey, err := r.SettingsMgr.GetAppInstanceLabelKey()
+	if err != nil {
+		logCtx.WithError(err).Warnf("failed to get the application instance label key, using %s", common.LabelKeyAppInstance)
+		return common.LabelKeyAppInstance
+	}
+	return labelKey
+}
+
+// propagateApplicationSetMetadata merges the labels, and the annotations if enabled, of the ApplicationSet into the
+// generated Applications. Labels and annotations generated from the template take precedence. Reserved Argo CD keys,
+// the resource tracking label and annotation and the notification annotations are never propagated: they are set on
+// an ApplicationSet managed by another Application, and copying them would make its Applications look like resources
+// of that Application.
+func propagateApplicationSetMetadata(applicationSet argov1alpha1.ApplicationSet, applications []argov1alpha1.Application, appInstanceLabelKey string) {
+	propagateLabels := applicationSet.Spec.PropagateMetadata || applicationSet.Spec.PropagateLabels
+	propagateAnnotations := applicationSet.Spec.PropagateMetadata
+	for i := range applications {
+		if propagateLabels {
+			applications[i].Labels = mergePropagatedMetadata(applications[i].Labels, applicationSet.Labels, appInstanceLabelKey)
+		}
+		if propagateAnnotations {
+			applications[i].Annotations = mergePropagatedMetadata(applications[i].Annotations, applicationSet.Annotations, appInstanceLabelKey)
+		}
+	}
+}
+
+// isPropagatedMetadataExcluded returns whether the label or annotation key must not be propagated
+func isPropagatedMetadataExcluded(key string, appInstanceLabelKey string) bool {
+	switch {
+	case key == common.LabelKeyAppInstance, key == appInstanceLabelKey:
+		return true
+	case key == common.AnnotationKeyAppInstance, key == corev1.LastAppliedConfigAnnotation:
+		return true
+	case strings.HasPrefix(key, ReservedMetadataKeyPrefix):
+		return true
+	case strings.HasPrefix(key, NotificationsMetadataKeyPrefix), strings.HasPrefix(key, NotifiedMetadataKeyPrefix):
+		return true
+	}
+	return false
+}
+
+// mergePropagatedMetadata adds the entries of propagated which are neither excluded nor already present to generated
+func mergePropagatedMetadata(generated map[string]string, propagated map[string]string, appInstanceLabelKey string) map[string]string {
+	for key, value := range propagated {
+		if isPropagatedMetadataExcluded(key, appInstanceLabelKey) {
+			continue
+		}
+		if _, exists := generated[key]; exists {
+			continue
+		}
+		if generated == nil {
+			generated = map[string]string{}
+		}
+		generated[key] = value
+	}
+	return generated
+}
+
 func getParametersGeneratedCondition(parametersGenerated bool, message string) argov1alpha1.ApplicationSetCondition {
 	var paramtersGeneratedCondition argov1alpha1.ApplicationSetCondition
 	if parametersGenerated {
@@ -625,10 +722,10 @@ func (r *ApplicationSetReconciler) createOrUpdateInCluster(ctx context.Context,
 				}
 			}
 
-			// Preserve post-delete finalizers:
+			// Preserve post-delete and pre-delete finalizers:
 			//   https://github.com/argoproj/argo-cd/issues/17181
 			for _, finalizer := range found.ObjectMeta.Finalizers {
-				if strings.HasPrefix(finalizer, argov1alpha1.PostDeleteFinalizerName) {
+				if strings.HasPrefix(finalizer, argov1alpha1.PostDeleteFinalizerName) || finalizer == argov1alpha1.PreDeleteFinalizerName {
 					if generatedApp.Finalizers == nil {
 						generatedApp.Finalizers = []string{}
 					}
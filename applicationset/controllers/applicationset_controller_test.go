@@ -15,6 +15,7 @@ import (
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -35,11 +36,15 @@ import (
 
 	"github.com/argoproj/argo-cd/v2/applicationset/generators"
 	"github.com/argoproj/argo-cd/v2/applicationset/generators/mocks"
+	"github.com/argoproj/argo-cd/v2/applicationset/notifications"
+	notificationmocks "github.com/argoproj/argo-cd/v2/applicationset/notifications/mocks"
 	"github.com/argoproj/argo-cd/v2/applicationset/utils"
+	argocommon "github.com/argoproj/argo-cd/v2/common"
 
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned/fake"
 	dbmocks "github.com/argoproj/argo-cd/v2/util/db/mocks"
+	"github.com/argoproj/argo-cd/v2/util/settings"
 
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
 )
@@ -1467,6 +1472,68 @@ func TestRemoveOwnerReferencesOnDeleteAppSet(t *testing.T) {
 	}
 }
 
+func TestReconcileDeletionProtectedAppSet(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := v1alpha1.AddToScheme(scheme)
+	require.NoError(t, err)
+
+	newAppSet := func() *v1alpha1.ApplicationSet {
+		return &v1alpha1.ApplicationSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "name",
+				Namespace:   "argocd",
+				Annotations: map[string]string{argocommon.AnnotationKeyDeletionProtected: "true"},
+			},
+			Spec: v1alpha1.ApplicationSetSpec{
+				Template: v1alpha1.ApplicationSetTemplate{
+					Spec: v1alpha1.ApplicationSpec{
+						Project: "default",
+					},
+				},
+			},
+		}
+	}
+	newReconciler := func(appSet *v1alpha1.ApplicationSet) ApplicationSetReconciler {
+		client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(appSet).WithStatusSubresource(appSet).WithIndex(&v1alpha1.Application{}, ".metadata.controller", appControllerIndexer).Build()
+		return ApplicationSetReconciler{
+			Client:        client,
+			Scheme:        scheme,
+			Renderer:      &utils.Render{},
+			Recorder:      record.NewFakeRecorder(10),
+			Cache:         &fakeCache{},
+			Generators:    map[string]generators.Generator{},
+			KubeClientset: kubefake.NewSimpleClientset(),
+			Policy:        v1alpha1.ApplicationsSyncPolicySync,
+		}
+	}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Namespace: "argocd", Name: "name"}}
+
+	t.Run("deletion is not held by the controller", func(t *testing.T) {
+		appSet := newAppSet()
+		appSet.Finalizers = []string{v1alpha1.ResourcesFinalizerName}
+		appSet.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		r := newReconciler(appSet)
+
+		_, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+
+		var retrieved v1alpha1.ApplicationSet
+		err = r.Client.Get(context.Background(), req.NamespacedName, &retrieved)
+		assert.True(t, apierr.IsNotFound(err), "ApplicationSet should have been deleted, got %v", err)
+	})
+
+	t.Run("no finalizer is added to protected ApplicationSets", func(t *testing.T) {
+		r := newReconciler(newAppSet())
+
+		_, err := r.Reconcile(context.Background(), req)
+		require.NoError(t, err)
+
+		var retrieved v1alpha1.ApplicationSet
+		require.NoError(t, r.Client.Get(context.Background(), req.NamespacedName, &retrieved))
+		assert.Empty(t, retrieved.Finalizers)
+	})
+}
+
 func TestCreateApplications(t *testing.T) {
 	scheme := runtime.NewScheme()
 	err := v1alpha1.AddToScheme(scheme)
@@ -1875,6 +1942,128 @@ func TestGetMinRequeueAfter(t *testing.T) {
 	assert.Equal(t, time.Duration(1)*time.Second, got)
 }
 
+func TestPropagateApplicationSetMetadata(t *testing.T) {
+	appSetMeta := metav1.ObjectMeta{
+		Name:      "name",
+		Namespace: "namespace",
+		Labels: map[string]string{
+			"team":                        "platform",
+			"env":                         "appset-env",
+			"argocd.argoproj.io/instance": "appset",
+			"app.kubernetes.io/part-of":   "fleet",
+		},
+		Annotations: map[string]string{
+			"owner":                            "platform-team",
+			"argocd.argoproj.io/refresh":       "hard",
+			corev1.LastAppliedConfigAnnotation: "{}",
+		},
+	}
+	generatedApp := func() v1alpha1.Application {
+		return v1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "app",
+				Namespace: "namespace",
+				Labels:    map[string]string{"env": "generated-env"},
+			},
+		}
+	}
+
+	for _, c := range []struct {
+		name                string
+		spec                v1alpha1.ApplicationSetSpec
+		expectedLabels      map[string]string
+		expectedAnnotations map[string]string
+	}{
+		{
+			name:           "nothing is propagated by default",
+			spec:           v1alpha1.ApplicationSetSpec{},
+			expectedLabels: map[string]string{"env": "generated-env"},
+		},
+		{
+			name: "only labels are propagated with propagateLabels",
+			spec: v1alpha1.ApplicationSetSpec{PropagateLabels: true},
+			expectedLabels: map[string]string{
+				"env":                       "generated-env",
+				"team":                      "platform",
+				"app.kubernetes.io/part-of": "fleet",
+			},
+		},
+		{
+			name: "labels and annotations are propagated with propagateMetadata",
+			spec: v1alpha1.ApplicationSetSpec{PropagateMetadata: true},
+			expectedLabels: map[string]string{
+				"env":                       "generated-env",
+				"team":                      "platform",
+				"app.kubernetes.io/part-of": "fleet",
+			},
+			expectedAnnotations: map[string]string{"owner": "platform-team"},
+		},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			appSet := v1alpha1.ApplicationSet{ObjectMeta: appSetMeta, Spec: c.spec}
+			apps := []v1alpha1.Application{generatedApp(), generatedApp()}
+
+			propagateApplicationSetMetadata(appSet, apps, argocommon.LabelKeyAppInstance)
+
+			for _, app := range apps {
+				assert.Equal(t, c.expectedLabels, app.Labels)
+				assert.Equal(t, c.expectedAnnotations, app.Annotations)
+			}
+		})
+	}
+}
+
+func TestPropagateApplicationSetMetadata_AppOfAppSets(t *testing.T) {
+	// an ApplicationSet managed by a parent Application carries the tracking metadata of the parent, and the
+	// notification annotations of its own subscriptions
+	appSet := v1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "child-appset",
+			Namespace: "argocd",
+			Labels: map[string]string{
+				argocommon.LabelKeyAppInstance: "parent-app",
+				"example.com/instance":         "parent-app",
+				"team":                         "platform",
+			},
+			Annotations: map[string]string{
+				argocommon.AnnotationKeyAppInstance:                        "parent-app:argoproj.io/ApplicationSet:argocd/child-appset",
+				"notifications.argoproj.io/subscribe.on-sync-failed.slack": "alerts",
+				NotifiedAnnotationKey:                                      `{"on-sync-failed":{}}`,
+				"owner":                                                    "platform-team",
+			},
+		},
+		Spec: v1alpha1.ApplicationSetSpec{PropagateMetadata: true},
+	}
+	apps := []v1alpha1.Application{{
+		ObjectMeta: metav1.ObjectMeta{Name: "child-app", Namespace: "argocd"},
+	}}
+
+	propagateApplicationSetMetadata(appSet, apps, "example.com/instance")
+
+	assert.Equal(t, map[string]string{"team": "platform"}, apps[0].Labels)
+	assert.Equal(t, map[string]string{"owner": "platform-team"}, apps[0].Annotations)
+}
+
+func TestGetAppInstanceLabelKey(t *testing.T) {
+	logCtx := log.WithField("applicationset", "test")
+	t.Run("default without settings", func(t *testing.T) {
+		r := ApplicationSetReconciler{}
+		assert.Equal(t, argocommon.LabelKeyAppInstance, r.getAppInstanceLabelKey(logCtx))
+	})
+	t.Run("configured label key", func(t *testing.T) {
+		kubeclientset := kubefake.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      argocommon.ArgoCDConfigMapName,
+				Namespace: "argocd",
+				Labels:    map[string]string{"app.kubernetes.io/part-of": "argocd"},
+			},
+			Data: map[string]string{"application.instanceLabelKey": "example.com/instance"},
+		})
+		r := ApplicationSetReconciler{SettingsMgr: settings.NewSettingsManager(context.Background(), kubeclientset, "argocd")}
+		assert.Equal(t, "example.com/instance", r.getAppInstanceLabelKey(logCtx))
+	})
+}
+
 func TestRequeueGeneratorFails(t *testing.T) {
 	scheme := runtime.NewScheme()
 	err := v1alpha1.AddToScheme(scheme)
@@ -1927,6 +2116,79 @@ func TestRequeueGeneratorFails(t *testing.T) {
 	assert.Equal(t, ReconcileRequeueOnValidationError, res.RequeueAfter)
 }
 
+func TestGeneratorFailureNotification(t *testing.T) {
+	scheme := runtime.NewScheme()
+	err := v1alpha1.AddToScheme(scheme)
+	require.NoError(t, err)
+
+	appSet := v1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "argocd",
+		},
+		Spec: v1alpha1.ApplicationSetSpec{
+			Generators: []v1alpha1.ApplicationSetGenerator{{
+				Git: &v1alpha1.GitGenerator{},
+			}},
+		},
+	}
+	client := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&appSet).Build()
+
+	generator := v1alpha1.ApplicationSetGenerator{
+		Git: &v1alpha1.GitGenerator{},
+	}
+
+	generatorMock := mocks.Generator{}
+	generatorMock.On("GetTemplate", &generator).
+		Return(&v1alpha1.ApplicationSetTemplate{})
+	generatorMock.On("GenerateParams", &generator, mock.AnythingOfType("*v1alpha1.ApplicationSet"), mock.Anything).
+		Return([]map[string]interface{}{}, fmt.Errorf("failed to connect to git"))
+
+	sent := make(chan struct{})
+	sender := notificationmocks.NewSender(t)
+	sender.On("Send", mock.AnythingOfType("*v1alpha1.ApplicationSet"), notifications.GeneratorFailedTrigger, map[string]string{
+		"name":          "name",
+		"namespace":     "argocd",
+		"errorMessage":  "failed to connect to git",
+		"generatorType": "Git",
+	}).Return(nil).Once().Run(func(mock.Arguments) {
+		close(sent)
+	})
+	notifier := notifications.NewGeneratorFailureNotifier(sender)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go notifier.Run(ctx)
+
+	r := ApplicationSetReconciler{
+		Client:   client,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(10),
+		Cache:    &fakeCache{},
+		Generators: map[string]generators.Generator{
+			"Git": &generatorMock,
+		},
+		GeneratorFailureNotifier: notifier,
+	}
+
+	req := ctrl.Request{
+		NamespacedName: types.NamespacedName{
+			Namespace: "argocd",
+			Name:      "name",
+		},
+	}
+
+	// the generator keeps failing, but a single notification is sent
+	for i := 0; i < 2; i++ {
+		_, err = r.Reconcile(ctx, req)
+		require.Error(t, err)
+	}
+	select {
+	case <-sent:
+	case <-time.After(10 * time.Second):
+		t.Fatal("notification was not sent")
+	}
+}
+
 func TestValidateGeneratedApplications(t *testing.T) {
 	scheme := runtime.NewScheme()
 	err := v1alpha1.AddToScheme(scheme)
@@ -2,6 +2,7 @@ package template
 
 import (
 	"fmt"
+	"strings"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -13,6 +14,21 @@ import (
 	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 )
 
+// GeneratorError is returned by GenerateApplications when a generator fails to generate parameters
+type GeneratorError struct {
+	// GeneratorType is the type of the failed generator, e.g. "Git" or "Matrix"
+	GeneratorType string
+	Err           error
+}
+
+func (e *GeneratorError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *GeneratorError) Unwrap() error {
+	return e.Err
+}
+
 func GenerateApplications(logCtx *log.Entry, applicationSetInfo argov1alpha1.ApplicationSet, g map[string]generators.Generator, renderer utils.Renderer, client client.Client) ([]argov1alpha1.Application, argov1alpha1.ApplicationSetReasonType, error) {
 	var res []argov1alpha1.Application
 
@@ -25,7 +41,10 @@ func GenerateApplications(logCtx *log.Entry, applicationSetInfo argov1alpha1.App
 			logCtx.WithError(err).WithField("generator", requestedGenerator).
 				Error("error generating application from params")
 			if firstError == nil {
-				firstError = err
+				firstError = &GeneratorError{
+					GeneratorType: strings.Join(generators.GetGeneratorTypes(&requestedGenerator), ","),
+					Err:           err,
+				}
 				applicationSetReason = argov1alpha1.ApplicationSetReasonApplicationParamsGenerationError
 			}
 			continue
@@ -139,6 +139,11 @@ func TestGenerateApplications(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 			}
+			if cc.generateParamsError != nil {
+				var generatorErr *GeneratorError
+				require.ErrorAs(t, err, &generatorErr)
+				assert.Equal(t, "List", generatorErr.GeneratorType)
+			}
 			assert.Equal(t, expectedApps, got)
 			assert.Equal(t, cc.expectedReason, reason)
 			generatorMock.AssertNumberOfCalls(t, "GenerateParams", 1)
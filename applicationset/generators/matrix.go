@@ -15,20 +15,56 @@ import (
 
 var _ Generator = (*MatrixGenerator)(nil)
 
+const (
+	// DefaultMatrixMaxDepth is the default maximum number of matrix generators nested within each other
+	DefaultMatrixMaxDepth = 5
+	// DefaultMatrixMaxParams is the default maximum number of parameter combinations a matrix generator may produce
+	DefaultMatrixMaxParams = 10000
+)
+
 var (
-	ErrMoreThanTwoGenerators      = fmt.Errorf("found more than two generators, Matrix support only two")
-	ErrLessThanTwoGenerators      = fmt.Errorf("found less than two generators, Matrix support only two")
+	ErrLessThanTwoGenerators      = fmt.Errorf("found less than two generators, Matrix requires two or more")
 	ErrMoreThenOneInnerGenerators = fmt.Errorf("found more than one generator in matrix.Generators")
 )
 
+// MatrixConfig bounds the parameters a matrix generator may produce
+type MatrixConfig struct {
+	// maxDepth is the maximum number of matrix generators nested within each other, including the outermost one
+	maxDepth int
+	// maxParams is the maximum number of parameter combinations produced by a matrix generator
+	maxParams int
+}
+
+func NewMatrixConfig(maxDepth int, maxParams int) MatrixConfig {
+	return MatrixConfig{
+		maxDepth:  maxDepth,
+		maxParams: maxParams,
+	}
+}
+
+// DefaultMatrixConfig returns the MatrixConfig used when no limits are configured
+func DefaultMatrixConfig() MatrixConfig {
+	return NewMatrixConfig(DefaultMatrixMaxDepth, DefaultMatrixMaxParams)
+}
+
 type MatrixGenerator struct {
 	// The inner generators supported by the matrix generator (cluster, git, list...)
 	supportedGenerators map[string]Generator
+	config              MatrixConfig
 }
 
+// NewMatrixGenerator returns a MatrixGenerator which allows the given supportedGenerators as child generators,
+// using the default limits.
 func NewMatrixGenerator(supportedGenerators map[string]Generator) Generator {
+	return NewMatrixGeneratorWithConfig(supportedGenerators, DefaultMatrixConfig())
+}
+
+// NewMatrixGeneratorWithConfig returns a MatrixGenerator which allows the given supportedGenerators as child
+// generators and enforces the limits of the given config.
+func NewMatrixGeneratorWithConfig(supportedGenerators map[string]Generator, config MatrixConfig) Generator {
 	m := &MatrixGenerator{
 		supportedGenerators: supportedGenerators,
+		config:              config,
 	}
 	return m
 }
@@ -42,42 +78,93 @@ func (m *MatrixGenerator) GenerateParams(appSetGenerator *argoprojiov1alpha1.App
 		return nil, ErrLessThanTwoGenerators
 	}
 
-	if len(appSetGenerator.Matrix.Generators) > 2 {
-		return nil, ErrMoreThanTwoGenerators
+	depth, err := matrixDepth(appSetGenerator.Matrix.Generators)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing nested matrix generators: %w", err)
+	}
+	if m.config.maxDepth > 0 && depth > m.config.maxDepth {
+		return nil, fmt.Errorf("matrix generator is nested %d levels deep, which exceeds the maximum of %d", depth, m.config.maxDepth)
 	}
 
 	res := []map[string]interface{}{}
+	if err := m.product(appSetGenerator.Matrix.Generators, appSet, nil, client, &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// product recursively computes the cartesian product of the parameters of the given generators. The parameters
+// generated so far are passed to the next generator so that it can interpolate them, and are merged into each of
+// its parameter sets. Complete combinations are appended to res.
+func (m *MatrixGenerator) product(generators []argoprojiov1alpha1.ApplicationSetNestedGenerator, appSet *argoprojiov1alpha1.ApplicationSet, params map[string]interface{}, client client.Client, res *[]map[string]interface{}) error {
+	if len(generators) == 0 {
+		if m.config.maxParams > 0 && len(*res) >= m.config.maxParams {
+			return fmt.Errorf("matrix generator produced more than the maximum of %d parameter combinations", m.config.maxParams)
+		}
+		*res = append(*res, params)
+		return nil
+	}
 
-	g0, err := m.getParams(appSetGenerator.Matrix.Generators[0], appSet, nil, client)
+	generated, err := m.getParams(generators[0], appSet, params, client)
 	if err != nil {
-		return nil, fmt.Errorf("error failed to get params for first generator in matrix generator: %w", err)
+		return fmt.Errorf("failed to get params for generator in the matrix generator: %w", err)
 	}
-	for _, a := range g0 {
-		g1, err := m.getParams(appSetGenerator.Matrix.Generators[1], appSet, a, client)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get params for second generator in the matrix generator: %w", err)
-		}
-		for _, b := range g1 {
-			if appSet.Spec.GoTemplate {
-				tmp := map[string]interface{}{}
-				if err := mergo.Merge(&tmp, b, mergo.WithOverride); err != nil {
-					return nil, fmt.Errorf("failed to merge params from the second generator in the matrix generator with temp map: %w", err)
-				}
-				if err := mergo.Merge(&tmp, a, mergo.WithOverride); err != nil {
-					return nil, fmt.Errorf("failed to merge params from the second generator in the matrix generator with the first: %w", err)
-				}
-				res = append(res, tmp)
-			} else {
-				val, err := utils.CombineStringMaps(a, b)
-				if err != nil {
-					return nil, fmt.Errorf("failed to combine string maps with merging params for the matrix generator: %w", err)
-				}
-				res = append(res, utils.ConvertToMapStringInterface(val))
+	for _, b := range generated {
+		combined := b
+		if params != nil {
+			combined, err = m.combine(params, b, appSet.Spec.GoTemplate)
+			if err != nil {
+				return err
 			}
 		}
+		if err := m.product(generators[1:], appSet, combined, client, res); err != nil {
+			return err
+		}
 	}
+	return nil
+}
 
-	return res, nil
+// combine merges the parameters of a generator into the parameters generated by the preceding generators, which
+// take precedence
+func (m *MatrixGenerator) combine(a map[string]interface{}, b map[string]interface{}, goTemplate bool) (map[string]interface{}, error) {
+	if goTemplate {
+		tmp := map[string]interface{}{}
+		if err := mergo.Merge(&tmp, b, mergo.WithOverride); err != nil {
+			return nil, fmt.Errorf("failed to merge params from the second generator in the matrix generator with temp map: %w", err)
+		}
+		if err := mergo.Merge(&tmp, a, mergo.WithOverride); err != nil {
+			return nil, fmt.Errorf("failed to merge params from the second generator in the matrix generator with the first: %w", err)
+		}
+		return tmp, nil
+	}
+	val, err := utils.CombineStringMaps(a, b)
+	if err != nil {
+		return nil, fmt.Errorf("failed to combine string maps with merging params for the matrix generator: %w", err)
+	}
+	return utils.ConvertToMapStringInterface(val), nil
+}
+
+// matrixDepth returns the number of matrix generators nested within each other, counting the matrix generator
+// owning the given child generators
+func matrixDepth(generators []argoprojiov1alpha1.ApplicationSetNestedGenerator) (int, error) {
+	depth := 1
+	for _, g := range generators {
+		nested, err := getMatrixGenerator(g)
+		if err != nil {
+			return 0, err
+		}
+		if nested == nil {
+			continue
+		}
+		nestedDepth, err := matrixDepth(nested.Generators)
+		if err != nil {
+			return 0, err
+		}
+		if nestedDepth+1 > depth {
+			depth = nestedDepth + 1
+		}
+	}
+	return depth, nil
 }
 
 func (m *MatrixGenerator) getParams(appSetBaseGenerator argoprojiov1alpha1.ApplicationSetNestedGenerator, appSet *argoprojiov1alpha1.ApplicationSet, params map[string]interface{}, client client.Client) ([]map[string]interface{}, error) {
@@ -92,19 +92,22 @@ func TestMatrixGenerate(t *testing.T) {
 			expectedErr: ErrLessThanTwoGenerators,
 		},
 		{
-			name: "returns error if there is more than two base generators",
+			name: "happy flow - generate params from three lists",
 			baseGenerators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
-				{
-					List: listGenerator,
-				},
-				{
-					List: listGenerator,
-				},
-				{
-					List: listGenerator,
-				},
+				getNestedListGeneratorMultiple([]string{`{"a": "1"}`, `{"a": "2"}`}),
+				getNestedListGeneratorMultiple([]string{`{"b": "1"}`, `{"b": "2"}`}),
+				getNestedListGeneratorMultiple([]string{`{"c": "1"}`, `{"c": "2"}`}),
+			},
+			expected: []map[string]interface{}{
+				{"a": "1", "b": "1", "c": "1"},
+				{"a": "1", "b": "1", "c": "2"},
+				{"a": "1", "b": "2", "c": "1"},
+				{"a": "1", "b": "2", "c": "2"},
+				{"a": "2", "b": "1", "c": "1"},
+				{"a": "2", "b": "1", "c": "2"},
+				{"a": "2", "b": "2", "c": "1"},
+				{"a": "2", "b": "2", "c": "2"},
 			},
-			expectedErr: ErrMoreThanTwoGenerators,
 		},
 		{
 			name: "returns error if there is more than one inner generator in the first base generator",
@@ -299,19 +302,22 @@ func TestMatrixGenerateGoTemplate(t *testing.T) {
 			expectedErr: ErrLessThanTwoGenerators,
 		},
 		{
-			name: "returns error if there is more than two base generators",
+			name: "happy flow - generate params from three lists",
 			baseGenerators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
-				{
-					List: listGenerator,
-				},
-				{
-					List: listGenerator,
-				},
-				{
-					List: listGenerator,
-				},
+				getNestedListGeneratorMultiple([]string{`{"a": "1"}`, `{"a": "2"}`}),
+				getNestedListGeneratorMultiple([]string{`{"b": "1"}`, `{"b": "2"}`}),
+				getNestedListGeneratorMultiple([]string{`{"c": "1"}`, `{"c": "2"}`}),
+			},
+			expected: []map[string]interface{}{
+				{"a": "1", "b": "1", "c": "1"},
+				{"a": "1", "b": "1", "c": "2"},
+				{"a": "1", "b": "2", "c": "1"},
+				{"a": "1", "b": "2", "c": "2"},
+				{"a": "2", "b": "1", "c": "1"},
+				{"a": "2", "b": "1", "c": "2"},
+				{"a": "2", "b": "2", "c": "1"},
+				{"a": "2", "b": "2", "c": "2"},
 			},
-			expectedErr: ErrMoreThanTwoGenerators,
 		},
 		{
 			name: "returns error if there is more than one inner generator in the first base generator",
@@ -405,6 +411,96 @@ func TestMatrixGenerateGoTemplate(t *testing.T) {
 	}
 }
 
+func TestMatrixGenerateNestedMatrices(t *testing.T) {
+	// cluster x (environment x namespace), nested three levels deep
+	threeLevels := []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+		getNestedListGeneratorMultiple([]string{`{"cluster": "c1"}`, `{"cluster": "c2"}`}),
+		{
+			Matrix: toAPIExtensionsJSON(t, &argoprojiov1alpha1.NestedMatrixGenerator{
+				Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+					getNestedListGeneratorMultiple([]string{`{"env": "dev"}`, `{"env": "prod"}`}),
+					{
+						Matrix: toAPIExtensionsJSON(t, &argoprojiov1alpha1.NestedMatrixGenerator{
+							Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+								getNestedListGeneratorMultiple([]string{`{"namespace": "default"}`}),
+								getNestedListGeneratorMultiple([]string{`{"team": "a"}`, `{"team": "b"}`}),
+							},
+						}),
+					},
+				},
+			}),
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		baseGenerators []argoprojiov1alpha1.ApplicationSetNestedGenerator
+		config         MatrixConfig
+		expectedErr    string
+		expected       []map[string]interface{}
+	}{
+		{
+			name:           "three levels of nesting",
+			baseGenerators: threeLevels,
+			config:         DefaultMatrixConfig(),
+			expected: []map[string]interface{}{
+				{"cluster": "c1", "env": "dev", "namespace": "default", "team": "a"},
+				{"cluster": "c1", "env": "dev", "namespace": "default", "team": "b"},
+				{"cluster": "c1", "env": "prod", "namespace": "default", "team": "a"},
+				{"cluster": "c1", "env": "prod", "namespace": "default", "team": "b"},
+				{"cluster": "c2", "env": "dev", "namespace": "default", "team": "a"},
+				{"cluster": "c2", "env": "dev", "namespace": "default", "team": "b"},
+				{"cluster": "c2", "env": "prod", "namespace": "default", "team": "a"},
+				{"cluster": "c2", "env": "prod", "namespace": "default", "team": "b"},
+			},
+		},
+		{
+			name:           "nesting deeper than the maximum depth",
+			baseGenerators: threeLevels,
+			config:         NewMatrixConfig(2, DefaultMatrixMaxParams),
+			expectedErr:    "matrix generator is nested 3 levels deep, which exceeds the maximum of 2",
+		},
+		{
+			name:           "more parameter combinations than the maximum",
+			baseGenerators: threeLevels,
+			config:         NewMatrixConfig(DefaultMatrixMaxDepth, 7),
+			expectedErr:    "matrix generator produced more than the maximum of 7 parameter combinations",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCaseCopy := testCase // Since tests may run in parallel
+
+		t.Run(testCaseCopy.name, func(t *testing.T) {
+			appSet := &argoprojiov1alpha1.ApplicationSet{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "set",
+				},
+			}
+
+			// nested matrix generators support matrix generators themselves
+			supportedGenerators := map[string]Generator{
+				"List": &ListGenerator{},
+			}
+			supportedGenerators["Matrix"] = NewMatrixGeneratorWithConfig(supportedGenerators, testCaseCopy.config)
+			matrixGenerator := NewMatrixGeneratorWithConfig(supportedGenerators, testCaseCopy.config)
+
+			got, err := matrixGenerator.GenerateParams(&argoprojiov1alpha1.ApplicationSetGenerator{
+				Matrix: &argoprojiov1alpha1.MatrixGenerator{
+					Generators: testCaseCopy.baseGenerators,
+				},
+			}, appSet, nil)
+
+			if testCaseCopy.expectedErr != "" {
+				require.ErrorContains(t, err, testCaseCopy.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, testCaseCopy.expected, got)
+			}
+		})
+	}
+}
+
 func TestMatrixGetRequeueAfter(t *testing.T) {
 	gitGenerator := &argoprojiov1alpha1.GitGenerator{
 		RepoURL:     "RepoURL",
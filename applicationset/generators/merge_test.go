@@ -20,6 +20,20 @@ func getNestedListGenerator(json string) *argoprojiov1alpha1.ApplicationSetNeste
 	}
 }
 
+func getNestedListGeneratorMultiple(jsons []string) argoprojiov1alpha1.ApplicationSetNestedGenerator {
+	elements := make([]apiextensionsv1.JSON, len(jsons))
+
+	for i, json := range jsons {
+		elements[i] = apiextensionsv1.JSON{Raw: []byte(json)}
+	}
+
+	return argoprojiov1alpha1.ApplicationSetNestedGenerator{
+		List: &argoprojiov1alpha1.ListGenerator{
+			Elements: elements,
+		},
+	}
+}
+
 func getTerminalListGeneratorMultiple(jsons []string) argoprojiov1alpha1.ApplicationSetTerminalGenerator {
 	elements := make([]apiextensionsv1.JSON, len(jsons))
 
@@ -110,9 +124,9 @@ func TestMergeGenerate(t *testing.T) {
 			baseGenerators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
 				{
 					Matrix: toAPIExtensionsJSON(t, &argoprojiov1alpha1.NestedMatrixGenerator{
-						Generators: []argoprojiov1alpha1.ApplicationSetTerminalGenerator{
-							getTerminalListGeneratorMultiple([]string{`{"a": "1"}`, `{"a": "2"}`}),
-							getTerminalListGeneratorMultiple([]string{`{"b": "1"}`, `{"b": "2"}`}),
+						Generators: []argoprojiov1alpha1.ApplicationSetNestedGenerator{
+							getNestedListGeneratorMultiple([]string{`{"a": "1"}`, `{"a": "2"}`}),
+							getNestedListGeneratorMultiple([]string{`{"b": "1"}`, `{"b": "2"}`}),
 						},
 					}),
 				},
@@ -0,0 +1,130 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gocache "github.com/patrickmn/go-cache"
+	log "github.com/sirupsen/logrus"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+const (
+	// GeneratorFailedTrigger is the notification trigger fired when the generators of an ApplicationSet fail
+	GeneratorFailedTrigger = "on-applicationset-generator-failed"
+	// notifiedExpiration is how long a notified failure is remembered after the last failed reconciliation, so that
+	// failures of deleted ApplicationSets are eventually forgotten
+	notifiedExpiration = 24 * time.Hour
+	// queueSize is the maximum number of notifications waiting to be sent
+	queueSize = 100
+)
+
+// Sender sends notifications about ApplicationSets
+type Sender interface {
+	// Send runs the trigger against the ApplicationSet and notifies the destinations subscribed to it. The given
+	// values are added to the notification context.
+	Send(appSet *argov1alpha1.ApplicationSet, trigger string, context map[string]string) error
+}
+
+// generatorFailure is a notification about a generator failure waiting to be sent
+type generatorFailure struct {
+	appSet        *argov1alpha1.ApplicationSet
+	generatorType string
+	message       string
+}
+
+// GeneratorFailureNotifier notifies about generator failures of ApplicationSets, once per failure episode. An episode
+// starts with the first failed reconciliation of an ApplicationSet and ends with the next successful one.
+//
+// Episodes are remembered in memory. After a restart of the controller, failures whose ErrorOccurred condition last
+// changed before the notifier was created are considered already notified about, so that ongoing episodes are not
+// notified about again.
+//
+// Notifications are sent in the background by Run, so that reconciliations do not wait on notification services.
+type GeneratorFailureNotifier struct {
+	sender Sender
+	// startedAt is the time the notifier was created, truncated to the precision of condition transition times
+	startedAt time.Time
+	// notified holds the qualified names of the ApplicationSets which were notified about during their current episode
+	notified *gocache.Cache
+	queue    chan generatorFailure
+}
+
+func NewGeneratorFailureNotifier(sender Sender) *GeneratorFailureNotifier {
+	return &GeneratorFailureNotifier{
+		sender:    sender,
+		startedAt: time.Now().Truncate(time.Second),
+		notified:  gocache.New(notifiedExpiration, time.Hour),
+		queue:     make(chan generatorFailure, queueSize),
+	}
+}
+
+// Run sends the queued notifications until the context is done
+func (n *GeneratorFailureNotifier) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case failure := <-n.queue:
+			n.send(failure)
+		}
+	}
+}
+
+// NotifyFailure queues a notification about the failure of the generator, unless one was already sent during the
+// current failure episode of the ApplicationSet
+func (n *GeneratorFailureNotifier) NotifyFailure(appSet *argov1alpha1.ApplicationSet, generatorType string, failure error) error {
+	key := appSet.QualifiedName()
+	if _, notified := n.notified.Get(key); notified {
+		// remember the episode for as long as the ApplicationSet keeps failing
+		n.notified.SetDefault(key, true)
+		return nil
+	}
+	n.notified.SetDefault(key, true)
+	if since := errorOccurredSince(appSet); since != nil && since.Before(n.startedAt) {
+		// the failure was already notified about before the controller restarted
+		return nil
+	}
+	select {
+	case n.queue <- generatorFailure{appSet: appSet.DeepCopy(), generatorType: generatorType, message: failure.Error()}:
+		return nil
+	default:
+		n.notified.Delete(key)
+		return fmt.Errorf("error queuing %s notification: too many notifications waiting to be sent", GeneratorFailedTrigger)
+	}
+}
+
+// Resolve ends the current failure episode of the ApplicationSet, if any
+func (n *GeneratorFailureNotifier) Resolve(appSet *argov1alpha1.ApplicationSet) {
+	n.notified.Delete(appSet.QualifiedName())
+}
+
+func (n *GeneratorFailureNotifier) send(failure generatorFailure) {
+	appSet := failure.appSet
+	err := n.sender.Send(appSet, GeneratorFailedTrigger, map[string]string{
+		"name":          appSet.Name,
+		"namespace":     appSet.Namespace,
+		"errorMessage":  failure.message,
+		"generatorType": failure.generatorType,
+	})
+	if err != nil {
+		// forget the episode so that the next failed reconciliation retries the notification
+		n.notified.Delete(appSet.QualifiedName())
+		log.WithField("applicationset", appSet.QualifiedName()).WithError(err).Warnf("failed to send %s notification", GeneratorFailedTrigger)
+	}
+}
+
+// errorOccurredSince returns the time the ErrorOccurred condition of the failing ApplicationSet last changed, or nil if
+// it is not known
+func errorOccurredSince(appSet *argov1alpha1.ApplicationSet) *time.Time {
+	for _, condition := range appSet.Status.Conditions {
+		if condition.Type == argov1alpha1.ApplicationSetConditionErrorOccurred &&
+			condition.Status == argov1alpha1.ApplicationSetConditionStatusTrue &&
+			condition.LastTransitionTime != nil {
+			return &condition.LastTransitionTime.Time
+		}
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-cd/v2/applicationset/notifications/mocks"
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// sendQueued sends the queued notifications, as Run would
+func sendQueued(n *GeneratorFailureNotifier) {
+	for len(n.queue) > 0 {
+		n.send(<-n.queue)
+	}
+}
+
+func TestGeneratorFailureNotifier(t *testing.T) {
+	appSet := &argov1alpha1.ApplicationSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-appset",
+			Namespace: "argocd",
+		},
+	}
+	expectedContext := map[string]string{
+		"name":          "my-appset",
+		"namespace":     "argocd",
+		"errorMessage":  "failed to connect to git",
+		"generatorType": "Git",
+	}
+
+	t.Run("notifies once per failure episode", func(t *testing.T) {
+		sender := mocks.NewSender(t)
+		sender.On("Send", appSet, GeneratorFailedTrigger, expectedContext).Return(nil).Times(2)
+		notifier := NewGeneratorFailureNotifier(sender)
+
+		// first episode: three failed reconciliations
+		for i := 0; i < 3; i++ {
+			require.NoError(t, notifier.NotifyFailure(appSet, "Git", fmt.Errorf("failed to connect to git")))
+			sendQueued(notifier)
+		}
+		notifier.Resolve(appSet)
+
+		// second episode
+		require.NoError(t, notifier.NotifyFailure(appSet, "Git", fmt.Errorf("failed to connect to git")))
+		sendQueued(notifier)
+		require.NoError(t, notifier.NotifyFailure(appSet, "Git", fmt.Errorf("failed to connect to git")))
+		sendQueued(notifier)
+	})
+
+	t.Run("retries notifications which could not be sent", func(t *testing.T) {
+		sender := mocks.NewSender(t)
+		sender.On("Send", appSet, GeneratorFailedTrigger, mock.Anything).Return(fmt.Errorf("slack is down")).Once()
+		sender.On("Send", appSet, GeneratorFailedTrigger, mock.Anything).Return(nil).Once()
+		notifier := NewGeneratorFailureNotifier(sender)
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, notifier.NotifyFailure(appSet, "Git", fmt.Errorf("failed to connect to git")))
+			sendQueued(notifier)
+		}
+	})
+
+	t.Run("tracks episodes per ApplicationSet", func(t *testing.T) {
+		other := appSet.DeepCopy()
+		other.Name = "other-appset"
+		sender := mocks.NewSender(t)
+		sender.On("Send", appSet, GeneratorFailedTrigger, mock.Anything).Return(nil).Once()
+		sender.On("Send", other, GeneratorFailedTrigger, mock.Anything).Return(nil).Once()
+		notifier := NewGeneratorFailureNotifier(sender)
+
+		require.NoError(t, notifier.NotifyFailure(appSet, "Git", fmt.Errorf("failed to connect to git")))
+		require.NoError(t, notifier.NotifyFailure(other, "Git", fmt.Errorf("failed to connect to git")))
+		sendQueued(notifier)
+	})
+
+	t.Run("does not notify again about failures older than the notifier", func(t *testing.T) {
+		sender := mocks.NewSender(t)
+		notifier := NewGeneratorFailureNotifier(sender)
+		failing := appSet.DeepCopy()
+		failing.Status.Conditions = []argov1alpha1.ApplicationSetCondition{{
+			Type:               argov1alpha1.ApplicationSetConditionErrorOccurred,
+			Status:             argov1alpha1.ApplicationSetConditionStatusTrue,
+			LastTransitionTime: &metav1.Time{Time: notifier.startedAt.Add(-time.Minute)},
+		}}
+
+		require.NoError(t, notifier.NotifyFailure(failing, "Git", fmt.Errorf("failed to connect to git")))
+		sendQueued(notifier)
+
+		// a failure which started after the notifier is notified about
+		sender.On("Send", mock.Anything, GeneratorFailedTrigger, mock.Anything).Return(nil).Once()
+		notifier.Resolve(failing)
+		failing.Status.Conditions[0].LastTransitionTime = &metav1.Time{Time: notifier.startedAt.Add(time.Minute)}
+		require.NoError(t, notifier.NotifyFailure(failing, "Git", fmt.Errorf("failed to connect to git")))
+		sendQueued(notifier)
+	})
+
+	t.Run("does not block when too many notifications are waiting", func(t *testing.T) {
+		notifier := NewGeneratorFailureNotifier(mocks.NewSender(t))
+		for i := 0; i < queueSize; i++ {
+			queued := appSet.DeepCopy()
+			queued.Name = fmt.Sprintf("appset-%d", i)
+			require.NoError(t, notifier.NotifyFailure(queued, "Git", fmt.Errorf("failed to connect to git")))
+		}
+		require.ErrorContains(t, notifier.NotifyFailure(appSet, "Git", fmt.Errorf("failed to connect to git")), "too many notifications")
+		// the notification is retried on the next failed reconciliation
+		_, notified := notifier.notified.Get(appSet.QualifiedName())
+		require.False(t, notified)
+	})
+
+	t.Run("sends queued notifications until the context is done", func(t *testing.T) {
+		sent := make(chan struct{})
+		sender := mocks.NewSender(t)
+		sender.On("Send", appSet, GeneratorFailedTrigger, expectedContext).Return(nil).Once().Run(func(mock.Arguments) {
+			close(sent)
+		})
+		notifier := NewGeneratorFailureNotifier(sender)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go notifier.Run(ctx)
+
+		require.NoError(t, notifier.NotifyFailure(appSet, "Git", fmt.Errorf("failed to connect to git")))
+		select {
+		case <-sent:
+		case <-time.After(10 * time.Second):
+			t.Fatal("notification was not sent")
+		}
+	})
+}
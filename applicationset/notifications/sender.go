@@ -0,0 +1,68 @@
+package notifications
+
+import (
+	"fmt"
+
+	"github.com/argoproj/notifications-engine/pkg/api"
+	"github.com/argoproj/notifications-engine/pkg/subscriptions"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	argov1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/notification/settings"
+)
+
+// apiSender sends notifications using the notifications engine, configured by the Argo CD notifications ConfigMap
+// and Secret
+type apiSender struct {
+	factory api.Factory
+}
+
+// NewSender returns a Sender which sends notifications using the notifications API built by the factory. The factory
+// settings must be built using settings.GetApplicationSetFactorySettings.
+func NewSender(factory api.Factory) Sender {
+	return &apiSender{factory: factory}
+}
+
+func (s *apiSender) Send(appSet *argov1alpha1.ApplicationSet, trigger string, context map[string]string) error {
+	notificationsAPI, err := s.factory.GetAPI()
+	if err != nil {
+		return fmt.Errorf("error getting notifications API: %w", err)
+	}
+
+	cfg := notificationsAPI.GetConfig()
+	destinations := cfg.GetGlobalDestinations(appSet.Labels)
+	destinations.Merge(subscriptions.NewAnnotations(appSet.Annotations).GetDestinations(cfg.DefaultTriggers, cfg.ServiceDefaultTriggers))
+	destinations = destinations.Dedup()
+	if len(destinations[trigger]) == 0 {
+		return nil
+	}
+
+	un, err := runtime.DefaultUnstructuredConverter.ToUnstructured(appSet)
+	if err != nil {
+		return fmt.Errorf("error converting ApplicationSet to unstructured: %w", err)
+	}
+	obj := map[string]interface{}{
+		settings.ApplicationSetVar:        un,
+		settings.ApplicationSetContextVar: context,
+	}
+
+	results, err := notificationsAPI.RunTrigger(trigger, obj)
+	if err != nil {
+		return fmt.Errorf("error executing condition of trigger %s: %w", trigger, err)
+	}
+
+	var firstError error
+	for _, result := range results {
+		if !result.Triggered {
+			continue
+		}
+		for _, to := range destinations[trigger] {
+			log.WithField("applicationset", appSet.QualifiedName()).Infof("Sending notification about condition '%s.%s' to '%v'", trigger, result.Key, to)
+			if err := notificationsAPI.Send(obj, result.Templates, to); err != nil && firstError == nil {
+				firstError = fmt.Errorf("error notifying recipient %s: %w", to, err)
+			}
+		}
+	}
+	return firstError
+}
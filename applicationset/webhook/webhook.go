@@ -2,6 +2,7 @@ package webhook
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"html"
 	"net/http"
@@ -11,6 +12,7 @@ import (
 	"strings"
 	"sync"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -460,10 +462,26 @@ func (h *WebhookHandler) shouldRefreshMatrixGenerator(gen *v1alpha1.MatrixGenera
 	}
 
 	// Silently ignore, the ApplicationSetReconciler will log the error as part of the reconcile
-	if len(gen.Generators) < 2 || len(gen.Generators) > 2 {
+	if len(gen.Generators) < 2 {
 		return false
 	}
 
+	// A matrix of more than two generators is equivalent to a matrix of the first generator and a nested matrix of
+	// the remaining ones, which the logic below knows how to evaluate.
+	if len(gen.Generators) > 2 {
+		rest, err := json.Marshal(v1alpha1.NestedMatrixGenerator{Generators: gen.Generators[1:]})
+		if err != nil {
+			log.Errorf("Failed to marshall nested matrix generator: %v", err)
+			return false
+		}
+		gen = &v1alpha1.MatrixGenerator{
+			Generators: []v1alpha1.ApplicationSetNestedGenerator{
+				gen.Generators[0],
+				{Matrix: &apiextensionsv1.JSON{Raw: rest}},
+			},
+		}
+	}
+
 	g0 := gen.Generators[0]
 
 	// Check first child generator for Git or Pull Request Generator
@@ -10,15 +10,18 @@ import (
 	"time"
 
 	"github.com/argoproj/pkg/stats"
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	clientv3 "go.etcd.io/etcd/client/v3"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 
 	cmdutil "github.com/argoproj/argo-cd/v2/cmd/util"
 	"github.com/argoproj/argo-cd/v2/common"
 	"github.com/argoproj/argo-cd/v2/controller"
+	"github.com/argoproj/argo-cd/v2/controller/metrics"
 	"github.com/argoproj/argo-cd/v2/controller/sharding"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned"
@@ -28,9 +31,12 @@ import (
 	cacheutil "github.com/argoproj/argo-cd/v2/util/cache"
 	appstatecache "github.com/argoproj/argo-cd/v2/util/cache/appstate"
 	"github.com/argoproj/argo-cd/v2/util/cli"
+	"github.com/argoproj/argo-cd/v2/util/db"
 	"github.com/argoproj/argo-cd/v2/util/env"
 	"github.com/argoproj/argo-cd/v2/util/errors"
+	healthutil "github.com/argoproj/argo-cd/v2/util/health"
 	kubeutil "github.com/argoproj/argo-cd/v2/util/kube"
+	"github.com/argoproj/argo-cd/v2/util/profile"
 	"github.com/argoproj/argo-cd/v2/util/settings"
 	"github.com/argoproj/argo-cd/v2/util/tls"
 	"github.com/argoproj/argo-cd/v2/util/trace"
@@ -47,36 +53,73 @@ const (
 
 func NewCommand() *cobra.Command {
 	var (
-		workqueueRateLimit               ratelimiter.AppControllerRateLimiterConfig
-		clientConfig                     clientcmd.ClientConfig
-		appResyncPeriod                  int64
-		appHardResyncPeriod              int64
-		appResyncJitter                  int64
-		repoErrorGracePeriod             int64
-		repoServerAddress                string
-		repoServerTimeoutSeconds         int
-		selfHealTimeoutSeconds           int
-		statusProcessors                 int
-		operationProcessors              int
-		glogLevel                        int
-		metricsPort                      int
-		metricsCacheExpiration           time.Duration
-		metricsAplicationLabels          []string
-		kubectlParallelismLimit          int64
-		cacheSource                      func() (*appstatecache.Cache, error)
-		redisClient                      *redis.Client
-		repoServerPlaintext              bool
-		repoServerStrictTLS              bool
-		otlpAddress                      string
-		otlpInsecure                     bool
-		otlpHeaders                      map[string]string
-		otlpAttrs                        []string
-		applicationNamespaces            []string
-		persistResourceHealth            bool
-		shardingAlgorithm                string
-		enableDynamicClusterDistribution bool
-		serverSideDiff                   bool
-		ignoreNormalizerOpts             normalizers.IgnoreNormalizerOpts
+		workqueueRateLimit                 ratelimiter.AppControllerRateLimiterConfig
+		clientConfig                       clientcmd.ClientConfig
+		appResyncPeriod                    int64
+		appHardResyncPeriod                int64
+		appResyncJitter                    int64
+		repoErrorGracePeriod               int64
+		repoServerAddress                  string
+		repoServerTimeoutSeconds           int
+		selfHealTimeoutSeconds             int
+		statusProcessors                   int
+		operationProcessors                int
+		glogLevel                          int
+		metricsPort                        int
+		metricsCacheExpiration             time.Duration
+		metricsAplicationLabels            []string
+		metricsSamplingRatio               float64
+		kubectlParallelismLimit            int64
+		cacheSource                        func() (*appstatecache.Cache, error)
+		redisClient                        *redis.Client
+		repoServerPlaintext                bool
+		repoServerStrictTLS                bool
+		otlpAddress                        string
+		otlpInsecure                       bool
+		otlpHeaders                        map[string]string
+		otlpAttrs                          []string
+		applicationNamespaces              []string
+		persistResourceHealth              bool
+		shardingAlgorithm                  string
+		enableDynamicClusterDistribution   bool
+		serverSideDiff                     bool
+		ignoreNormalizerOpts               normalizers.IgnoreNormalizerOpts
+		enablePprof                        bool
+		pprofAddress                       string
+		pprofPort                          int
+		pprofHeapTriggerMB                 int
+		pprofDumpPath                      string
+		postSyncWebhookAllowedURLs         []string
+		defaultHealthForUnknownResources   string
+		disableHealthOverrides             bool
+		healthTimelineRetention            time.Duration
+		ldapSyncInterval                   time.Duration
+		ldapGroupRecursionDepth            int
+		defaultApplyRateLimit              float64
+		deletionTimeoutPerResource         time.Duration
+		globalSyncTimeout                  time.Duration
+		driftDigestSchedule                string
+		twoLevelCacheWriteThrough          bool
+		inMemoryMaxItemBytes               int64
+		compressInformerCache              bool
+		eventDedupWindow                   time.Duration
+		syncSnapshotRetention              time.Duration
+		defaultResourceApplyTimeout        time.Duration
+		enableSyncCheckpoints              bool
+		webhookConfirmationTimeout         time.Duration
+		eventFilterInterval                time.Duration
+		enableLeaderElection               bool
+		leaderElectionBackend              string
+		etcdEndpoints                      []string
+		etcdDialTimeout                    time.Duration
+		etcdLeaderTTL                      time.Duration
+		enableClusterDiscovery             bool
+		clusterDiscoveryNamespace          string
+		enableWorkStealing                 bool
+		workStealAge                       time.Duration
+		clusterDisconnectGracePeriod       time.Duration
+		queueHealthCheckWindow             time.Duration
+		disableResourceAnnotationTemplates bool
 	)
 	command := cobra.Command{
 		Use:               cliName,
@@ -84,6 +127,12 @@ func NewCommand() *cobra.Command {
 		Long:              "ArgoCD application controller is a Kubernetes controller that continuously monitors running applications and compares the current, live state against the desired target state (as specified in the repo). This command runs Application Controller in the foreground.  It can be configured by following options.",
 		DisableAutoGenTag: true,
 		RunE: func(c *cobra.Command, args []string) error {
+			if defaultApplyRateLimit != 0 && defaultApplyRateLimit < v1alpha1.MinApplyRequestsPerSecond {
+				return fmt.Errorf("invalid default apply rate limit %v: must be 0 or at least %v", defaultApplyRateLimit, v1alpha1.MinApplyRequestsPerSecond)
+			}
+			if etcdLeaderTTL < time.Second {
+				return fmt.Errorf("invalid etcd leader TTL %v: must be at least 1s", etcdLeaderTTL)
+			}
 			ctx, cancel := context.WithCancel(c.Context())
 			defer cancel()
 
@@ -141,7 +190,9 @@ func NewCommand() *cobra.Command {
 
 			cache, err := cacheSource()
 			errors.CheckError(err)
-			cache.Cache.SetClient(cacheutil.NewTwoLevelClient(cache.Cache.GetClient(), 10*time.Minute))
+			twoLevelClient := cacheutil.NewTwoLevelClient(cache.Cache.GetClient(), 10*time.Minute, twoLevelCacheWriteThrough)
+			twoLevelClient.SetInMemoryMaxItemBytes(inMemoryMaxItemBytes)
+			cache.Cache.SetClient(twoLevelClient)
 
 			var appController *controller.ApplicationController
 
@@ -151,6 +202,16 @@ func NewCommand() *cobra.Command {
 			kubectl := kubeutil.NewKubectl()
 			clusterSharding, err := sharding.GetClusterSharding(kubeClient, settingsMgr, shardingAlgorithm, enableDynamicClusterDistribution)
 			errors.CheckError(err)
+			defaultHealth, err := healthutil.ParseDefaultHealthForUnknownResources(defaultHealthForUnknownResources)
+			errors.CheckError(err)
+			var workStealingQueue *sharding.WorkStealingQueue
+			if enableWorkStealing {
+				if redisClient == nil || clusterSharding.GetReplicas() < 2 {
+					log.Warn("Work stealing is disabled, it requires Redis and more than one shard")
+				} else {
+					workStealingQueue = sharding.NewWorkStealingQueue(redisClient, clusterSharding.GetShard(), clusterSharding.GetReplicas(), workStealAge)
+				}
+			}
 			appController, err = controller.NewApplicationController(
 				namespace,
 				settingsMgr,
@@ -167,6 +228,7 @@ func NewCommand() *cobra.Command {
 				metricsPort,
 				metricsCacheExpiration,
 				metricsAplicationLabels,
+				metricsSamplingRatio,
 				kubectlParallelismLimit,
 				persistResourceHealth,
 				clusterSharding,
@@ -175,14 +237,42 @@ func NewCommand() *cobra.Command {
 				serverSideDiff,
 				enableDynamicClusterDistribution,
 				ignoreNormalizerOpts,
+				postSyncWebhookAllowedURLs,
+				defaultHealth,
+				disableHealthOverrides,
+				healthTimelineRetention,
+				defaultApplyRateLimit,
+				deletionTimeoutPerResource,
+				globalSyncTimeout,
+				driftDigestSchedule,
+				compressInformerCache,
+				eventDedupWindow,
+				syncSnapshotRetention,
+				defaultResourceApplyTimeout,
+				enableSyncCheckpoints,
+				webhookConfirmationTimeout,
+				eventFilterInterval,
+				workStealingQueue,
+				clusterDisconnectGracePeriod,
+				queueHealthCheckWindow,
+				disableResourceAnnotationTemplates,
 			)
 			errors.CheckError(err)
 			cacheutil.CollectMetrics(redisClient, appController.GetMetricsServer())
+			twoLevelClient.OnOversizedItem(appController.GetMetricsServer().IncCacheOversizedItems)
+			appController.GetMetricsServer().RegisterTwoLevelCacheStats(twoLevelClient.InMemoryStats)
 
 			stats.RegisterStackDumper()
 			stats.StartStatsTicker(10 * time.Minute)
 			stats.RegisterHeapDumper("memprofile")
 
+			if enablePprof {
+				pprofServer := profile.NewServer(pprofAddress, pprofPort)
+				log.Infof("Serving pprof endpoints on %s", pprofServer.Addr)
+				go func() { errors.CheckError(pprofServer.ListenAndServe()) }()
+				go profile.NewHeapProfiler(pprofHeapTriggerMB, pprofDumpPath).Run(ctx)
+			}
+
 			if otlpAddress != "" {
 				closeTracer, err := trace.InitTracer(ctx, "argocd-controller", otlpAddress, otlpInsecure, otlpHeaders, otlpAttrs)
 				if err != nil {
@@ -200,9 +290,31 @@ func NewCommand() *cobra.Command {
 				cancel()
 			}()
 
-			go appController.Run(ctx, statusProcessors, operationProcessors)
+			run := func(ctx context.Context) {
+				go appController.Run(ctx, statusProcessors, operationProcessors)
+				if enableClusterDiscovery {
+					discoveryNamespace := clusterDiscoveryNamespace
+					if discoveryNamespace == "" {
+						discoveryNamespace = namespace
+					}
+					go controller.NewClusterDiscovery(discoveryNamespace, kubeClient, db.NewDB(namespace, settingsMgr, kubeClient)).Run(ctx, 1)
+				}
+				if ldapSyncInterval > 0 {
+					go controller.NewLDAPGroupSync(namespace, settingsMgr, kubeClient, appClient, ldapSyncInterval, ldapGroupRecursionDepth).Run(ctx)
+				}
+			}
 
-			<-ctx.Done()
+			if enableLeaderElection {
+				leaderElection, err := newLeaderElection(kubeClient, namespace, leaderElectionBackend, etcdEndpoints, etcdDialTimeout, etcdLeaderTTL)
+				errors.CheckError(err)
+				// the leadership is released before returning once the context is done
+				if err := leaderElection.Run(ctx, run); err != nil {
+					log.Fatalf("Leader election failed: %v", err)
+				}
+			} else {
+				run(ctx)
+				<-ctx.Done()
+			}
 
 			log.Println("clean shutdown")
 
@@ -228,6 +340,7 @@ func NewCommand() *cobra.Command {
 	command.Flags().Int64Var(&kubectlParallelismLimit, "kubectl-parallelism-limit", env.ParseInt64FromEnv("ARGOCD_APPLICATION_CONTROLLER_KUBECTL_PARALLELISM_LIMIT", 20, 0, math.MaxInt64), "Number of allowed concurrent kubectl fork/execs. Any value less than 1 means no limit.")
 	command.Flags().BoolVar(&repoServerPlaintext, "repo-server-plaintext", env.ParseBoolFromEnv("ARGOCD_APPLICATION_CONTROLLER_REPO_SERVER_PLAINTEXT", false), "Disable TLS on connections to repo server")
 	command.Flags().BoolVar(&repoServerStrictTLS, "repo-server-strict-tls", env.ParseBoolFromEnv("ARGOCD_APPLICATION_CONTROLLER_REPO_SERVER_STRICT_TLS", false), "Whether to use strict validation of the TLS cert presented by the repo server")
+	command.Flags().Float64Var(&metricsSamplingRatio, "metrics-sampling-ratio", env.ParseFloat64FromEnv("ARGOCD_APPLICATION_CONTROLLER_METRICS_SAMPLING_RATIO", metrics.DefaultSamplingRatio, 0, 1), "Ratio of the recordings of the reconcile loop metrics which are kept, greater than 0 and at most 1. The syncs are always recorded")
 	command.Flags().StringSliceVar(&metricsAplicationLabels, "metrics-application-labels", []string{}, "List of Application labels that will be added to the argocd_application_labels metric")
 	command.Flags().StringVar(&otlpAddress, "otlp-address", env.StringFromEnv("ARGOCD_APPLICATION_CONTROLLER_OTLP_ADDRESS", ""), "OpenTelemetry collector address to send traces to")
 	command.Flags().BoolVar(&otlpInsecure, "otlp-insecure", env.ParseBoolFromEnv("ARGOCD_APPLICATION_CONTROLLER_OTLP_INSECURE", true), "OpenTelemetry collector insecure mode")
@@ -248,6 +361,42 @@ func NewCommand() *cobra.Command {
 	command.Flags().BoolVar(&enableDynamicClusterDistribution, "dynamic-cluster-distribution-enabled", env.ParseBoolFromEnv(common.EnvEnableDynamicClusterDistribution, false), "Enables dynamic cluster distribution.")
 	command.Flags().BoolVar(&serverSideDiff, "server-side-diff-enabled", env.ParseBoolFromEnv(common.EnvServerSideDiff, false), "Feature flag to enable ServerSide diff. Default (\"false\")")
 	command.Flags().DurationVar(&ignoreNormalizerOpts.JQExecutionTimeout, "ignore-normalizer-jq-execution-timeout-seconds", env.ParseDurationFromEnv("ARGOCD_IGNORE_NORMALIZER_JQ_TIMEOUT", 0*time.Second, 0, math.MaxInt64), "Set ignore normalizer JQ execution timeout")
+	command.Flags().BoolVar(&enablePprof, "enable-pprof", env.ParseBoolFromEnv("ARGOCD_APPLICATION_CONTROLLER_ENABLE_PPROF", false), "Serve pprof endpoints on a dedicated port and dump heap profiles when heap usage exceeds the trigger")
+	command.Flags().StringVar(&pprofAddress, "pprof-address", env.StringFromEnv("ARGOCD_APPLICATION_CONTROLLER_PPROF_ADDRESS", profile.DefaultAddress), "Listen address of the pprof server. The pprof endpoints are not authenticated.")
+	command.Flags().IntVar(&pprofPort, "pprof-port", env.ParseNumFromEnv("ARGOCD_APPLICATION_CONTROLLER_PPROF_PORT", profile.DefaultPort, 0, math.MaxInt32), "Port of the pprof server")
+	command.Flags().IntVar(&pprofHeapTriggerMB, "pprof-heap-trigger-mb", env.ParseNumFromEnv("ARGOCD_APPLICATION_CONTROLLER_PPROF_HEAP_TRIGGER_MB", profile.DefaultHeapTriggerMB, 1, math.MaxInt32), "Heap usage in megabytes above which a heap profile is written to the pprof dump path")
+	command.Flags().StringSliceVar(&postSyncWebhookAllowedURLs, "post-sync-webhook-allowed-urls", env.StringsFromEnv("ARGOCD_APPLICATION_CONTROLLER_POST_SYNC_WEBHOOK_ALLOWED_URLS", []string{}, ","), "List of glob patterns of the URLs post-sync webhooks of projects may be sent to, e.g. 'https://hooks.example.com/*'. No webhook is sent when empty.")
+	command.Flags().StringVar(&defaultHealthForUnknownResources, "default-health-for-unknown-resources", env.StringFromEnv("ARGOCD_APPLICATION_CONTROLLER_DEFAULT_HEALTH_FOR_UNKNOWN_RESOURCES", ""), "Health assumed for resources without a built-in or custom health check. One of: Healthy|Progressing|Unknown. Such resources do not affect the application health when empty.")
+	command.Flags().BoolVar(&disableHealthOverrides, "disable-health-overrides", env.ParseBoolFromEnv("ARGOCD_APPLICATION_CONTROLLER_DISABLE_HEALTH_OVERRIDES", false), "Ignore the argocd.argoproj.io/health-override annotation of resources and always use their computed health")
+	command.Flags().DurationVar(&healthTimelineRetention, "health-timeline-retention", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_HEALTH_TIMELINE_RETENTION", 7*24*time.Hour, 0, math.MaxInt64), "Duration the health changes of application resources are kept for the resource health timeline. Health changes are not recorded if set to 0")
+	command.Flags().DurationVar(&ldapSyncInterval, "ldap-sync-interval", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_LDAP_SYNC_INTERVAL", 0, 0, math.MaxInt64), "Interval of the synchronization of the members of LDAP groups referenced in the RBAC policy with local accounts. The LDAP server is configured by the LDAP connector of dex.config. Disabled if set to 0")
+	command.Flags().IntVar(&ldapGroupRecursionDepth, "ldap-group-recursion-depth", env.ParseNumFromEnv("ARGOCD_APPLICATION_CONTROLLER_LDAP_GROUP_RECURSION_DEPTH", 0, 0, math.MaxInt32), "Number of levels of nested LDAP groups whose members are synchronized")
+	command.Flags().Float64Var(&defaultApplyRateLimit, "default-apply-rate-limit", env.ParseFloat64FromEnv("ARGOCD_APPLICATION_CONTROLLER_DEFAULT_APPLY_RATE_LIMIT", 0, 0, math.MaxFloat64), "Number of requests per second which modify resources of a destination cluster during syncs, at least 0.1. The limit is shared by all applications syncing to the cluster, in addition to their own apply rate limits. Disabled if set to 0")
+	command.Flags().DurationVar(&deletionTimeoutPerResource, "deletion-timeout-per-resource", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_DELETION_TIMEOUT_PER_RESOURCE", 0, 0, math.MaxInt64), "Duration after which the resources of a cascaded application deletion whose deletion is pending are force deleted, by removing their finalizers. Disabled if set to 0")
+	command.Flags().BoolVar(&twoLevelCacheWriteThrough, "two-level-cache-write-through", env.ParseBoolFromEnv("ARGOCD_APPLICATION_CONTROLLER_TWO_LEVEL_CACHE_WRITE_THROUGH", false), "Write every cached value to Redis, even if the in-memory cache already holds it. Keeps Redis up to date when its copy expired or was overwritten by another replica, at the cost of a Redis request for every write")
+	command.Flags().Int64Var(&inMemoryMaxItemBytes, "in-memory-max-item-bytes", env.ParseInt64FromEnv("ARGOCD_APPLICATION_CONTROLLER_IN_MEMORY_MAX_ITEM_BYTES", 0, 0, math.MaxInt64), "Maximum size in bytes of the items kept in the in-memory cache. Larger items are only stored in Redis, so that they do not use up the memory of many small items. No limit applies if set to 0")
+	command.Flags().DurationVar(&globalSyncTimeout, "global-sync-timeout", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_GLOBAL_SYNC_TIMEOUT", 0, 0, math.MaxInt64), "Duration after which syncs which did not complete fail, unless the application sets spec.syncPolicy.syncTimeout. Disabled if set to 0")
+	command.Flags().StringVar(&driftDigestSchedule, "drift-digest-schedule", env.StringFromEnv("ARGOCD_APPLICATION_CONTROLLER_DRIFT_DIGEST_SCHEDULE", controller.DefaultDriftDigestSchedule), "Cron schedule of the digest of out of sync applications, sent by email if sendDriftDigest is enabled in the argocd-notifications-cm ConfigMap")
+	command.Flags().BoolVar(&compressInformerCache, "compress-informer-cache", env.ParseBoolFromEnv("ARGOCD_APPLICATION_CONTROLLER_COMPRESS_INFORMER_CACHE", false), "Store the applications of the informer cache compressed with zstd, which reduces the memory used by the applications by 60-80% at the cost of decompressing them when they are read")
+	command.Flags().DurationVar(&eventDedupWindow, "event-dedup-window", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_EVENT_DEDUP_WINDOW", 5*time.Minute, 0, math.MaxInt64), "Duration during which Kubernetes events of an application with the same reason and message are emitted only once. Disabled if set to 0")
+	command.Flags().DurationVar(&syncSnapshotRetention, "sync-snapshot-retention", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_SYNC_SNAPSHOT_RETENTION", 7*24*time.Hour, 0, math.MaxInt64), "Duration the live state of the resources of an application captured before each sync is kept to roll back to it. The live state is not captured if set to 0")
+	command.Flags().DurationVar(&defaultResourceApplyTimeout, "default-resource-apply-timeout", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_DEFAULT_RESOURCE_APPLY_TIMEOUT", 0, 0, math.MaxInt64), "Duration after which the apply of a resource fails, unless the application sets an apply timeout for the kind of the resource in spec.syncPolicy.applyTimeouts. Disabled if set to 0")
+	command.Flags().BoolVar(&enableSyncCheckpoints, "enable-sync-checkpoints", env.ParseBoolFromEnv("ARGOCD_APPLICATION_CONTROLLER_ENABLE_SYNC_CHECKPOINTS", false), "Record the resources applied by syncs in Redis, so that a sync interrupted by a restart of the controller does not apply them again when it resumes")
+	command.Flags().DurationVar(&webhookConfirmationTimeout, "webhook-confirmation-timeout", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_WEBHOOK_CONFIRMATION_TIMEOUT", 5*time.Minute, 0, math.MaxInt64), "Maximum duration succeeded syncs wait for the confirmation of post-sync webhooks which require it. Once it expires, the sync succeeds with a WebhookConfirmationTimeout warning condition")
+	command.Flags().DurationVar(&eventFilterInterval, "event-filter-interval", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_EVENT_FILTER_INTERVAL", 5*time.Second, 0, math.MaxInt64), "Interval during which the events of a watched resource are coalesced into its latest event, so that at most one event per resource is handled per interval. Deletions are handled at once. Disabled if set to 0")
+	command.Flags().BoolVar(&enableLeaderElection, "enable-leader-election", env.ParseBoolFromEnv("ARGOCD_APPLICATION_CONTROLLER_ENABLE_LEADER_ELECTION", false), "Elect a single replica reconciling the applications, the other replicas being standbys taking over once it stops. Requires a single shard")
+	command.Flags().StringVar(&leaderElectionBackend, "leader-election-backend", env.StringFromEnv("ARGOCD_APPLICATION_CONTROLLER_LEADER_ELECTION_BACKEND", controller.LeaderElectionBackendKubernetes), "Backend of the leader election. One of: k8s|etcd. The etcd backend does not depend on the latency of the Kubernetes API server")
+	command.Flags().StringSliceVar(&etcdEndpoints, "etcd-endpoints", env.StringsFromEnv("ARGOCD_APPLICATION_CONTROLLER_ETCD_ENDPOINTS", []string{}, ","), "List of the endpoints of the etcd cluster used by the etcd leader election backend")
+	command.Flags().DurationVar(&etcdDialTimeout, "etcd-dial-timeout", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_ETCD_DIAL_TIMEOUT", 5*time.Second, 0, math.MaxInt64), "Timeout of the connection to the etcd cluster used by the etcd leader election backend")
+	command.Flags().DurationVar(&etcdLeaderTTL, "etcd-leader-ttl", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_ETCD_LEADER_TTL", controller.DefaultLeaderElectionTTL, time.Second, math.MaxInt64), "Duration after which the leadership of a replica which stopped renewing its etcd lease expires, at least 1s")
+	command.Flags().BoolVar(&enableClusterDiscovery, "enable-cluster-discovery", env.ParseBoolFromEnv("ARGOCD_APPLICATION_CONTROLLER_ENABLE_CLUSTER_DISCOVERY", false), "Register the clusters whose credentials are stored in the secrets of the cluster discovery namespace labeled with argocd.argoproj.io/cluster-discovery=true, and deregister them once their secret is deleted")
+	command.Flags().StringVar(&clusterDiscoveryNamespace, "cluster-discovery-namespace", env.StringFromEnv("ARGOCD_APPLICATION_CONTROLLER_CLUSTER_DISCOVERY_NAMESPACE", ""), "Namespace of the cluster discovery secrets. Defaults to the namespace of the application controller")
+	command.Flags().BoolVar(&enableWorkStealing, "enable-work-stealing", env.ParseBoolFromEnv("ARGOCD_APPLICATION_CONTROLLER_ENABLE_WORK_STEALING", false), "Let idle shards reconcile the applications which waited in the queues of overloaded shards for longer than the work steal age. The queues of the shards are shared in Redis")
+	command.Flags().DurationVar(&workStealAge, "work-steal-age", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_WORK_STEAL_AGE", sharding.DefaultWorkStealAge, time.Second, math.MaxInt64), "Duration after which the applications waiting in the queue of a shard may be reconciled by another, idle shard")
+	command.Flags().DurationVar(&clusterDisconnectGracePeriod, "cluster-disconnect-grace-period", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_CLUSTER_DISCONNECT_GRACE_PERIOD", 30*time.Second, 0, math.MaxInt64), "Duration during which the applications keep their last known status while their cluster is unreachable, before being reported as Unknown. Set to 0 to report them as Unknown as soon as the cluster is unreachable")
+	command.Flags().DurationVar(&queueHealthCheckWindow, "queue-health-check-window", env.ParseDurationFromEnv("ARGOCD_APPLICATION_CONTROLLER_QUEUE_HEALTH_CHECK_WINDOW", 5*time.Minute, 0, math.MaxInt64), "Duration after which the /healthz/queue liveness check fails if the applications of the reconcile queue are not processed. Set to 0 to disable the check")
+	command.Flags().BoolVar(&disableResourceAnnotationTemplates, "disable-resource-annotation-templates", env.ParseBoolFromEnv("ARGOCD_APPLICATION_CONTROLLER_DISABLE_RESOURCE_ANNOTATION_TEMPLATES", false), "Ignore the templates of the argocd-resource-annotations ConfigMap and do not inject their annotations into the resources applied by syncs")
+	command.Flags().StringVar(&pprofDumpPath, "pprof-dump-path", env.StringFromEnv("ARGOCD_APPLICATION_CONTROLLER_PPROF_DUMP_PATH", os.TempDir()), "Directory in which heap profiles are written")
 	cacheSource = appstatecache.AddCacheFlagsToCmd(&command, cacheutil.Options{
 		OnClientCreated: func(client *redis.Client) {
 			redisClient = client
@@ -255,3 +404,28 @@ func NewCommand() *cobra.Command {
 	})
 	return &command
 }
+
+// newLeaderElection returns the leader election of the application controller with the given backend
+func newLeaderElection(kubeClient kubernetes.Interface, namespace, backend string, etcdEndpoints []string, etcdDialTimeout, etcdLeaderTTL time.Duration) (controller.LeaderElection, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("error getting the hostname: %w", err)
+	}
+	identity := hostname + "_" + uuid.NewString()
+	switch backend {
+	case controller.LeaderElectionBackendKubernetes:
+		return controller.NewKubernetesLeaderElection(kubeClient, namespace, common.ApplicationController, identity, controller.DefaultLeaderElectionTTL), nil
+	case controller.LeaderElectionBackendEtcd:
+		if len(etcdEndpoints) == 0 {
+			return nil, fmt.Errorf("the etcd leader election backend requires --etcd-endpoints")
+		}
+		client, err := clientv3.New(clientv3.Config{Endpoints: etcdEndpoints, DialTimeout: etcdDialTimeout})
+		if err != nil {
+			return nil, fmt.Errorf("error connecting to etcd: %w", err)
+		}
+		key := fmt.Sprintf("/argocd/%s/%s/leader", namespace, common.ApplicationController)
+		return controller.NewEtcdLeaderElection(client, key, identity, etcdLeaderTTL), nil
+	default:
+		return nil, fmt.Errorf("unknown leader election backend %q: must be one of %s|%s", backend, controller.LeaderElectionBackendKubernetes, controller.LeaderElectionBackendEtcd)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"time"
 
+	"github.com/argoproj/notifications-engine/pkg/api"
 	"github.com/argoproj/pkg/stats"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -17,12 +18,15 @@ import (
 
 	"github.com/argoproj/argo-cd/v2/applicationset/controllers"
 	"github.com/argoproj/argo-cd/v2/applicationset/generators"
+	"github.com/argoproj/argo-cd/v2/applicationset/notifications"
 	"github.com/argoproj/argo-cd/v2/applicationset/utils"
 	"github.com/argoproj/argo-cd/v2/applicationset/webhook"
 	cmdutil "github.com/argoproj/argo-cd/v2/cmd/util"
 	"github.com/argoproj/argo-cd/v2/common"
 	"github.com/argoproj/argo-cd/v2/util/env"
 	"github.com/argoproj/argo-cd/v2/util/github_app"
+	"github.com/argoproj/argo-cd/v2/util/notification/k8s"
+	notificationsettings "github.com/argoproj/argo-cd/v2/util/notification/settings"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -71,6 +75,9 @@ func NewCommand() *cobra.Command {
 		globalPreservedLabels        []string
 		enableScmProviders           bool
 		webhookParallelism           int
+		matrixMaxDepth               int
+		matrixMaxParams              int
+		enableNotifications          bool
 	)
 	scheme := runtime.NewScheme()
 	_ = clientgoscheme.AddToScheme(scheme)
@@ -183,7 +190,7 @@ func NewCommand() *cobra.Command {
 			argoCDService, err := services.NewArgoCDService(argoCDDB.GetRepository, gitSubmoduleEnabled, repoClientset, enableNewGitFileGlobbing)
 			errors.CheckError(err)
 
-			topLevelGenerators := generators.GetGenerators(ctx, mgr.GetClient(), k8sClient, namespace, argoCDService, dynamicClient, scmConfig)
+			topLevelGenerators := generators.GetGenerators(ctx, mgr.GetClient(), k8sClient, namespace, argoCDService, dynamicClient, scmConfig, generators.NewMatrixConfig(matrixMaxDepth, matrixMaxParams))
 
 			// start a webhook server that listens to incoming webhook payloads
 			webhookHandler, err := webhook.NewWebhookHandler(namespace, webhookParallelism, argoSettingsMgr, mgr.GetClient(), topLevelGenerators)
@@ -194,6 +201,17 @@ func NewCommand() *cobra.Command {
 				startWebhookServer(webhookHandler, webhookAddr)
 			}
 
+			var generatorFailureNotifier *notifications.GeneratorFailureNotifier
+			if enableNotifications {
+				secretInformer := k8s.NewSecretInformer(k8sClient, namespace, common.ArgoCDNotificationsSecretName)
+				configMapInformer := k8s.NewConfigMapInformer(k8sClient, namespace, common.ArgoCDNotificationsConfigMapName)
+				go secretInformer.Run(ctx.Done())
+				go configMapInformer.Run(ctx.Done())
+				apiFactory := api.NewFactory(notificationsettings.GetApplicationSetFactorySettings(common.ArgoCDNotificationsSecretName, common.ArgoCDNotificationsConfigMapName), namespace, secretInformer, configMapInformer)
+				generatorFailureNotifier = notifications.NewGeneratorFailureNotifier(notifications.NewSender(apiFactory))
+				go generatorFailureNotifier.Run(ctx)
+			}
+
 			if err = (&controllers.ApplicationSetReconciler{
 				Generators:                 topLevelGenerators,
 				Client:                     mgr.GetClient(),
@@ -212,6 +230,8 @@ func NewCommand() *cobra.Command {
 				GlobalPreservedAnnotations: globalPreservedAnnotations,
 				GlobalPreservedLabels:      globalPreservedLabels,
 				Cache:                      mgr.GetCache(),
+				GeneratorFailureNotifier:   generatorFailureNotifier,
+				SettingsMgr:                argoSettingsMgr,
 			}).SetupWithManager(mgr, enableProgressiveSyncs, maxConcurrentReconciliations); err != nil {
 				log.Error(err, "unable to create controller", "controller", "ApplicationSet")
 				os.Exit(1)
@@ -253,6 +273,9 @@ func NewCommand() *cobra.Command {
 	command.Flags().StringSliceVar(&globalPreservedAnnotations, "preserved-annotations", env.StringsFromEnv("ARGOCD_APPLICATIONSET_CONTROLLER_GLOBAL_PRESERVED_ANNOTATIONS", []string{}, ","), "Sets global preserved field values for annotations")
 	command.Flags().StringSliceVar(&globalPreservedLabels, "preserved-labels", env.StringsFromEnv("ARGOCD_APPLICATIONSET_CONTROLLER_GLOBAL_PRESERVED_LABELS", []string{}, ","), "Sets global preserved field values for labels")
 	command.Flags().IntVar(&webhookParallelism, "webhook-parallelism-limit", env.ParseNumFromEnv("ARGOCD_APPLICATIONSET_CONTROLLER_WEBHOOK_PARALLELISM_LIMIT", 50, 1, 1000), "Number of webhook requests processed concurrently")
+	command.Flags().IntVar(&matrixMaxDepth, "matrix-generator-max-depth", env.ParseNumFromEnv("ARGOCD_APPLICATIONSET_CONTROLLER_MATRIX_GENERATOR_MAX_DEPTH", generators.DefaultMatrixMaxDepth, 1, math.MaxInt32), "Maximum nesting depth of matrix generators")
+	command.Flags().IntVar(&matrixMaxParams, "matrix-generator-max-params", env.ParseNumFromEnv("ARGOCD_APPLICATIONSET_CONTROLLER_MATRIX_GENERATOR_MAX_PARAMS", generators.DefaultMatrixMaxParams, 1, math.MaxInt32), "Maximum number of parameter combinations a matrix generator may produce")
+	command.Flags().BoolVar(&enableNotifications, "enable-notifications", env.ParseBoolFromEnv("ARGOCD_APPLICATIONSET_CONTROLLER_ENABLE_NOTIFICATIONS", false), "Send notifications configured in the Argo CD notifications ConfigMap when the generators of an ApplicationSet fail")
 	return &command
 }
 
@@ -9,6 +9,7 @@ import (
 	"github.com/argoproj/argo-cd/v2/common"
 	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
 
+	cacheutil "github.com/argoproj/argo-cd/v2/util/cache"
 	"github.com/argoproj/argo-cd/v2/util/env"
 	"github.com/argoproj/argo-cd/v2/util/errors"
 	service "github.com/argoproj/argo-cd/v2/util/notification/argocd"
@@ -19,8 +20,10 @@ import (
 	"github.com/argoproj/notifications-engine/pkg/controller"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
@@ -57,6 +60,9 @@ func NewCommand() *cobra.Command {
 		secretName                     string
 		applicationNamespaces          []string
 		selfServiceNotificationEnabled bool
+		webhookDeduplicationEnabled    bool
+		cacheSource                    func() (*cacheutil.Cache, error)
+		redisClient                    *redis.Client
 	)
 	command := cobra.Command{
 		Use:   "controller",
@@ -140,7 +146,22 @@ func NewCommand() *cobra.Command {
 			log.Infof("serving metrics on port %d", metricsPort)
 			log.Infof("loading configuration %d", metricsPort)
 
-			ctrl := notificationscontroller.NewController(k8sClient, dynamicClient, argocdService, namespace, applicationNamespaces, appLabelSelector, registry, secretName, configMapName, selfServiceNotificationEnabled)
+			var idempotencyKeyStore notificationscontroller.IdempotencyKeyStore
+			if webhookDeduplicationEnabled {
+				secret, err := k8sClient.CoreV1().Secrets(namespace).Get(ctx, secretName, metav1.GetOptions{})
+				if err != nil {
+					return fmt.Errorf("failed to get notifications secret: %w", err)
+				}
+				if err := notificationscontroller.ValidateIdempotencyKeySecret(secret); err != nil {
+					return fmt.Errorf("webhook deduplication requires an idempotency key secret: %w", err)
+				}
+				if _, err := cacheSource(); err != nil {
+					return fmt.Errorf("failed to create Redis client: %w", err)
+				}
+				idempotencyKeyStore = notificationscontroller.NewRedisIdempotencyKeyStore(redisClient)
+			}
+
+			ctrl := notificationscontroller.NewController(k8sClient, dynamicClient, argocdService, namespace, applicationNamespaces, appLabelSelector, registry, secretName, configMapName, selfServiceNotificationEnabled, idempotencyKeyStore)
 			err = ctrl.Init(ctx)
 			if err != nil {
 				return fmt.Errorf("failed to initialize controller: %w", err)
@@ -165,5 +186,11 @@ func NewCommand() *cobra.Command {
 	command.Flags().StringVar(&secretName, "secret-name", "argocd-notifications-secret", "Set notifications Secret name")
 	command.Flags().StringSliceVar(&applicationNamespaces, "application-namespaces", env.StringsFromEnv("ARGOCD_APPLICATION_NAMESPACES", []string{}, ","), "List of additional namespaces that this controller should send notifications for")
 	command.Flags().BoolVar(&selfServiceNotificationEnabled, "self-service-notification-enabled", env.ParseBoolFromEnv("ARGOCD_NOTIFICATION_CONTROLLER_SELF_SERVICE_NOTIFICATION_ENABLED", false), "Allows the Argo CD notification controller to pull notification config from the namespace that the resource is in. This is useful for self-service notification.")
+	command.Flags().BoolVar(&webhookDeduplicationEnabled, "webhook-deduplication-enabled", env.ParseBoolFromEnv("ARGOCD_NOTIFICATIONS_CONTROLLER_WEBHOOK_DEDUPLICATION_ENABLED", false), "Record the idempotency keys of the delivered webhook notifications in Redis and count the deliveries whose key was already delivered, e.g. by another replica or before a restart. Requires the webhook.idempotencyKey.secret key of the notifications secret")
+	cacheSource = cacheutil.AddCacheFlagsToCmd(&command, cacheutil.Options{
+		OnClientCreated: func(client *redis.Client) {
+			redisClient = client
+		},
+	})
 	return &command
 }
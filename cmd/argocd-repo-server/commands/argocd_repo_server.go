@@ -33,6 +33,8 @@ import (
 	"github.com/argoproj/argo-cd/v2/util/gpg"
 	"github.com/argoproj/argo-cd/v2/util/healthz"
 	ioutil "github.com/argoproj/argo-cd/v2/util/io"
+	"github.com/argoproj/argo-cd/v2/util/kustomize"
+	"github.com/argoproj/argo-cd/v2/util/profile"
 	"github.com/argoproj/argo-cd/v2/util/tls"
 	traceutil "github.com/argoproj/argo-cd/v2/util/trace"
 )
@@ -75,6 +77,29 @@ func NewCommand() *cobra.Command {
 		helmRegistryMaxIndexSize          string
 		disableManifestMaxExtractedSize   bool
 		includeHiddenDirectories          bool
+		kustomizeVersions                 []string
+		gitShallowCloneDepth              int
+		maxShallowDeepenDepth             int
+		yttBinPath                        string
+		cueBinPath                        string
+		pulumiBinPath                     string
+		enablePulumi                      bool
+		timoniBinPath                     string
+		kustomizePluginHome               string
+		kustomizeBaseCache                bool
+		httpSourceCacheTTL                time.Duration
+		ociSourcePollInterval             time.Duration
+		manifestParseWorkers              int
+		helmTimeout                       time.Duration
+		kustomizeTimeout                  time.Duration
+		jsonnetTimeout                    time.Duration
+		pluginTimeout                     time.Duration
+		helmDepUpdateTimeout              time.Duration
+		enablePprof                       bool
+		pprofAddress                      string
+		pprofPort                         int
+		pprofHeapTriggerMB                int
+		pprofDumpPath                     string
 	)
 	command := cobra.Command{
 		Use:               cliName,
@@ -119,6 +144,51 @@ func NewCommand() *cobra.Command {
 			helmRegistryMaxIndexSizeQuantity, err := resource.ParseQuantity(helmRegistryMaxIndexSize)
 			errors.CheckError(err)
 
+			kustomizeVersionPaths, err := kustomize.ParseVersions(kustomizeVersions)
+			errors.CheckError(err)
+
+			// ytt is optional unless a binary is configured explicitly
+			yttVersion, err := repository.NewYttGenerator(yttBinPath).Version()
+			if err != nil && yttBinPath != "" {
+				errors.CheckError(err)
+			} else if err != nil {
+				log.Warnf("ytt is not available, applications of type Ytt cannot be rendered: %v", err)
+			} else {
+				log.Infof("Using ytt version %s", yttVersion)
+			}
+
+			// cue is optional unless a binary is configured explicitly
+			cueVersion, err := repository.NewCueGenerator(cueBinPath).Version()
+			if err != nil && cueBinPath != "" {
+				errors.CheckError(err)
+			} else if err != nil {
+				log.Warnf("cue is not available, applications of type Cue cannot be rendered: %v", err)
+			} else {
+				log.Infof("Using cue version %s", cueVersion)
+			}
+
+			// pulumi is only used if enabled, and optional unless a binary is configured explicitly
+			if enablePulumi {
+				pulumiVersion, err := repository.NewPulumiGenerator(pulumiBinPath, nil).Version()
+				if err != nil && pulumiBinPath != "" {
+					errors.CheckError(err)
+				} else if err != nil {
+					log.Warnf("pulumi is not available, applications of type Pulumi cannot be rendered: %v", err)
+				} else {
+					log.Infof("Using pulumi version %s", pulumiVersion)
+				}
+			}
+
+			// timoni is optional unless a binary is configured explicitly
+			timoniVersion, err := repository.NewTimoniGenerator(timoniBinPath, nil).Version()
+			if err != nil && timoniBinPath != "" {
+				errors.CheckError(err)
+			} else if err != nil {
+				log.Warnf("timoni is not available, applications of type Timoni cannot be rendered: %v", err)
+			} else {
+				log.Infof("Using timoni version %s", timoniVersion)
+			}
+
 			askPassServer := askpass.NewServer(askpass.SocketPath)
 			metricsServer := metrics.NewMetricsServer()
 			cacheutil.CollectMetrics(redisClient, metricsServer)
@@ -136,6 +206,26 @@ func NewCommand() *cobra.Command {
 				HelmManifestMaxExtractedSize:                 helmManifestMaxExtractedSizeQuantity.ToDec().Value(),
 				HelmRegistryMaxIndexSize:                     helmRegistryMaxIndexSizeQuantity.ToDec().Value(),
 				IncludeHiddenDirectories:                     includeHiddenDirectories,
+				KustomizeVersions:                            kustomizeVersionPaths,
+				GitShallowCloneDepth:                         gitShallowCloneDepth,
+				MaxShallowDeepenDepth:                        maxShallowDeepenDepth,
+				YttBinaryPath:                                yttBinPath,
+				CueBinaryPath:                                cueBinPath,
+				PulumiBinaryPath:                             pulumiBinPath,
+				EnablePulumi:                                 enablePulumi,
+				TimoniBinaryPath:                             timoniBinPath,
+				KustomizePluginHome:                          kustomizePluginHome,
+				KustomizeBaseCache:                           kustomizeBaseCache,
+				HTTPSourceCacheTTL:                           httpSourceCacheTTL,
+				OCISourcePollInterval:                        ociSourcePollInterval,
+				ManifestParseWorkers:                         manifestParseWorkers,
+				GeneratorTimeouts: repository.GeneratorTimeouts{
+					Helm:      helmTimeout,
+					Kustomize: kustomizeTimeout,
+					Jsonnet:   jsonnetTimeout,
+					Plugin:    pluginTimeout,
+				},
+				HelmDependencyUpdateTimeout: helmDepUpdateTimeout,
 			}, askPassServer)
 			errors.CheckError(err)
 
@@ -179,6 +269,13 @@ func NewCommand() *cobra.Command {
 			go func() { errors.CheckError(http.ListenAndServe(fmt.Sprintf("%s:%d", metricsHost, metricsPort), nil)) }()
 			go func() { errors.CheckError(askPassServer.Run()) }()
 
+			if enablePprof {
+				pprofServer := profile.NewServer(pprofAddress, pprofPort)
+				log.Infof("Serving pprof endpoints on %s", pprofServer.Addr)
+				go func() { errors.CheckError(pprofServer.ListenAndServe()) }()
+				go profile.NewHeapProfiler(pprofHeapTriggerMB, pprofDumpPath).Run(ctx)
+			}
+
 			if gpg.IsGPGEnabled() {
 				log.Infof("Initializing GnuPG keyring at %s", common.GetGnuPGHomePath())
 				err = gpg.InitializeGnuPG()
@@ -241,6 +338,29 @@ func NewCommand() *cobra.Command {
 	command.Flags().StringVar(&helmRegistryMaxIndexSize, "helm-registry-max-index-size", env.StringFromEnv("ARGOCD_REPO_SERVER_HELM_MANIFEST_MAX_INDEX_SIZE", "1G"), "Maximum size of registry index file")
 	command.Flags().BoolVar(&disableManifestMaxExtractedSize, "disable-helm-manifest-max-extracted-size", env.ParseBoolFromEnv("ARGOCD_REPO_SERVER_DISABLE_HELM_MANIFEST_MAX_EXTRACTED_SIZE", false), "Disable maximum size of helm manifest archives when extracted")
 	command.Flags().BoolVar(&includeHiddenDirectories, "include-hidden-directories", env.ParseBoolFromEnv("ARGOCD_REPO_SERVER_INCLUDE_HIDDEN_DIRECTORIES", false), "Include hidden directories from Git")
+	command.Flags().StringSliceVar(&kustomizeVersions, "kustomize-versions", env.StringsFromEnv("ARGOCD_REPO_SERVER_KUSTOMIZE_VERSIONS", []string{}, ","), "Kustomize binaries available to applications, as comma separated version=path pairs (e.g. v4.5.7=/custom-tools/kustomize_4_5_7)")
+	command.Flags().IntVar(&gitShallowCloneDepth, "git-shallow-clone-depth", env.ParseNumFromEnv("ARGOCD_REPO_SERVER_GIT_SHALLOW_CLONE_DEPTH", 0, 0, math.MaxInt32), "Number of commits fetched from Git repositories. Any value less than 1 fetches the full history.")
+	command.Flags().IntVar(&maxShallowDeepenDepth, "max-shallow-deepen-depth", env.ParseNumFromEnv("ARGOCD_REPO_SERVER_MAX_SHALLOW_DEEPEN_DEPTH", 0, 0, math.MaxInt32), "Maximum depth shallow clones are deepened to when a revision cannot be found. Any value less than 1 allows the full history.")
+	command.Flags().StringVar(&yttBinPath, "ytt-bin-path", env.StringFromEnv("ARGOCD_REPO_SERVER_YTT_BIN_PATH", ""), "Path of the ytt binary used to render applications of type Ytt. The binary is looked up in the PATH if empty.")
+	command.Flags().StringVar(&cueBinPath, "cue-bin-path", env.StringFromEnv("ARGOCD_REPO_SERVER_CUE_BIN_PATH", ""), "Path of the cue binary used to render applications of type Cue. The binary is looked up in the PATH if empty.")
+	command.Flags().BoolVar(&enablePulumi, "enable-pulumi", env.ParseBoolFromEnv("ARGOCD_REPO_SERVER_ENABLE_PULUMI", false), "Enable applications of type Pulumi. The programs of Pulumi applications run in the repo-server process, with access to its file system and network.")
+	command.Flags().StringVar(&pulumiBinPath, "pulumi-bin-path", env.StringFromEnv("ARGOCD_REPO_SERVER_PULUMI_BIN_PATH", ""), "Path of the pulumi binary used to preview applications of type Pulumi. The binary is looked up in the PATH if empty.")
+	command.Flags().StringVar(&timoniBinPath, "timoni-bin-path", env.StringFromEnv("ARGOCD_REPO_SERVER_TIMONI_BIN_PATH", ""), "Path of the timoni binary used to build applications of type Timoni. The binary is looked up in the PATH if empty.")
+	command.Flags().StringVar(&kustomizePluginHome, "kustomize-plugin-home", env.StringFromEnv("ARGOCD_REPO_SERVER_KUSTOMIZE_PLUGIN_HOME", ""), "Directory Kustomize looks up alpha plugins in when building applications with spec.source.kustomize.validate. The default directory of Kustomize is used if empty.")
+	command.Flags().BoolVar(&kustomizeBaseCache, "kustomize-base-cache", env.ParseBoolFromEnv("ARGOCD_REPO_SERVER_KUSTOMIZE_BASE_CACHE", false), "Cache the rendered local bases of Kustomize overlays, so that the applications sharing a base render it once per revision")
+	command.Flags().DurationVar(&httpSourceCacheTTL, "http-source-cache-ttl", env.ParseDurationFromEnv("ARGOCD_REPO_SERVER_HTTP_SOURCE_CACHE_TTL", 3*time.Minute, 0, math.MaxInt64), "Time the manifests fetched for HTTP sources are cached. Sources without a SHA256 digest are fetched again once it expires. Zero disables caching.")
+	command.Flags().DurationVar(&ociSourcePollInterval, "oci-source-poll-interval", env.ParseDurationFromEnv("ARGOCD_REPO_SERVER_OCI_SOURCE_POLL_INTERVAL", 3*time.Minute, 0, math.MaxInt64), "Interval at which the tags of OCI sources are resolved again to detect new artifacts. Zero resolves them on every manifest generation.")
+	command.Flags().IntVar(&manifestParseWorkers, "manifest-parse-workers", env.ParseNumFromEnv("ARGOCD_REPO_SERVER_MANIFEST_PARSE_WORKERS", repository.DefaultManifestParseWorkers, 0, math.MaxInt32), "Number of workers parsing the YAML documents of generated manifests concurrently, shared by all manifest generations. Values lower than 2 parse the documents sequentially.")
+	command.Flags().DurationVar(&helmTimeout, "helm-timeout", env.ParseDurationFromEnv("ARGOCD_REPO_SERVER_HELM_TIMEOUT", 5*time.Minute, 0, math.MaxInt64), "Maximum duration of the manifest generation of Helm applications, after which helm is killed. Zero disables the timeout.")
+	command.Flags().DurationVar(&kustomizeTimeout, "kustomize-timeout", env.ParseDurationFromEnv("ARGOCD_REPO_SERVER_KUSTOMIZE_TIMEOUT", 2*time.Minute, 0, math.MaxInt64), "Maximum duration of the manifest generation of Kustomize applications, after which kustomize is killed. Zero disables the timeout.")
+	command.Flags().DurationVar(&jsonnetTimeout, "jsonnet-timeout", env.ParseDurationFromEnv("ARGOCD_REPO_SERVER_JSONNET_TIMEOUT", 30*time.Second, 0, math.MaxInt64), "Maximum duration of the manifest generation of Directory applications, including the evaluation of their Jsonnet files, and of Starlark applications. Zero disables the timeout.")
+	command.Flags().DurationVar(&pluginTimeout, "plugin-timeout", env.ParseDurationFromEnv("ARGOCD_REPO_SERVER_PLUGIN_TIMEOUT", 5*time.Minute, 0, math.MaxInt64), "Maximum duration of the manifest generation of config management plugins. Zero disables the timeout.")
+	command.Flags().DurationVar(&helmDepUpdateTimeout, "helm-dep-update-timeout", env.ParseDurationFromEnv("ARGOCD_REPO_SERVER_HELM_DEP_UPDATE_TIMEOUT", 60*time.Second, 0, math.MaxInt64), "Maximum duration of `helm dependency update`, run before generating the manifests of charts declaring dependencies whose charts/ directory is empty. The dependencies are cached by the hash of the chart. Zero disables the update.")
+	command.Flags().BoolVar(&enablePprof, "enable-pprof", env.ParseBoolFromEnv("ARGOCD_REPO_SERVER_ENABLE_PPROF", false), "Serve pprof endpoints on a dedicated port and dump heap profiles when heap usage exceeds the trigger")
+	command.Flags().StringVar(&pprofAddress, "pprof-address", env.StringFromEnv("ARGOCD_REPO_SERVER_PPROF_ADDRESS", profile.DefaultAddress), "Listen address of the pprof server. The pprof endpoints are not authenticated.")
+	command.Flags().IntVar(&pprofPort, "pprof-port", env.ParseNumFromEnv("ARGOCD_REPO_SERVER_PPROF_PORT", profile.DefaultPort, 0, math.MaxInt32), "Port of the pprof server")
+	command.Flags().IntVar(&pprofHeapTriggerMB, "pprof-heap-trigger-mb", env.ParseNumFromEnv("ARGOCD_REPO_SERVER_PPROF_HEAP_TRIGGER_MB", profile.DefaultHeapTriggerMB, 1, math.MaxInt32), "Heap usage in megabytes above which a heap profile is written to the pprof dump path")
+	command.Flags().StringVar(&pprofDumpPath, "pprof-dump-path", env.StringFromEnv("ARGOCD_REPO_SERVER_PPROF_DUMP_PATH", os.TempDir()), "Directory in which heap profiles are written")
 	tlsConfigCustomizerSrc = tls.AddTLSFlagsToCmd(&command)
 	cacheSrc = reposervercache.AddCacheFlagsToCmd(&command, cacheutil.Options{
 		OnClientCreated: func(client *redis.Client) {
@@ -17,8 +17,10 @@ import (
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/argoproj/argo-cd/v2/applicationset/generators"
 	cmdutil "github.com/argoproj/argo-cd/v2/cmd/util"
 	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/controller"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned"
 	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
@@ -83,12 +85,21 @@ func NewCommand() *cobra.Command {
 		applicationNamespaces    []string
 		enableProxyExtension     bool
 		webhookParallelism       int
+		enableSchemaValidation   bool
+		schemaValidationTimeout  time.Duration
+		enableDestValidation     bool
+		destValidationPort       int
+		bootstrapAppRepo         string
+		bootstrapAppPath         string
+		enableRepositoryConfigs  bool
 
 		// ApplicationSet
 		enableNewGitFileGlobbing bool
 		scmRootCAPath            string
 		allowedScmProviders      []string
 		enableScmProviders       bool
+		matrixMaxDepth           int
+		matrixMaxParams          int
 	)
 	command := &cobra.Command{
 		Use:               cliName,
@@ -195,34 +206,40 @@ func NewCommand() *cobra.Command {
 			}
 
 			argoCDOpts := server.ArgoCDServerOpts{
-				Insecure:                insecure,
-				ListenPort:              listenPort,
-				ListenHost:              listenHost,
-				MetricsPort:             metricsPort,
-				MetricsHost:             metricsHost,
-				Namespace:               namespace,
-				BaseHRef:                baseHRef,
-				RootPath:                rootPath,
-				DynamicClientset:        dynamicClient,
-				KubeControllerClientset: controllerClient,
-				KubeClientset:           kubeclientset,
-				AppClientset:            appClientSet,
-				RepoClientset:           repoclientset,
-				DexServerAddr:           dexServerAddress,
-				DexTLSConfig:            dexTlsConfig,
-				DisableAuth:             disableAuth,
-				ContentTypes:            contentTypesList,
-				EnableGZip:              enableGZip,
-				TLSConfigCustomizer:     tlsConfigCustomizer,
-				Cache:                   cache,
-				RepoServerCache:         repoServerCache,
-				XFrameOptions:           frameOptions,
-				ContentSecurityPolicy:   contentSecurityPolicy,
-				RedisClient:             redisClient,
-				StaticAssetsDir:         staticAssetsDir,
-				ApplicationNamespaces:   applicationNamespaces,
-				EnableProxyExtension:    enableProxyExtension,
-				WebhookParallelism:      webhookParallelism,
+				Insecure:                    insecure,
+				ListenPort:                  listenPort,
+				ListenHost:                  listenHost,
+				MetricsPort:                 metricsPort,
+				MetricsHost:                 metricsHost,
+				Namespace:                   namespace,
+				BaseHRef:                    baseHRef,
+				RootPath:                    rootPath,
+				DynamicClientset:            dynamicClient,
+				KubeControllerClientset:     controllerClient,
+				KubeClientset:               kubeclientset,
+				AppClientset:                appClientSet,
+				RepoClientset:               repoclientset,
+				DexServerAddr:               dexServerAddress,
+				DexTLSConfig:                dexTlsConfig,
+				DisableAuth:                 disableAuth,
+				ContentTypes:                contentTypesList,
+				EnableGZip:                  enableGZip,
+				TLSConfigCustomizer:         tlsConfigCustomizer,
+				Cache:                       cache,
+				RepoServerCache:             repoServerCache,
+				XFrameOptions:               frameOptions,
+				ContentSecurityPolicy:       contentSecurityPolicy,
+				RedisClient:                 redisClient,
+				StaticAssetsDir:             staticAssetsDir,
+				ApplicationNamespaces:       applicationNamespaces,
+				EnableProxyExtension:        enableProxyExtension,
+				WebhookParallelism:          webhookParallelism,
+				EnableSchemaValidation:      enableSchemaValidation,
+				SchemaValidationTimeout:     schemaValidationTimeout,
+				EnableDestinationValidation: enableDestValidation,
+				DestinationValidationPort:   destValidationPort,
+				BootstrapAppRepo:            bootstrapAppRepo,
+				BootstrapAppPath:            bootstrapAppPath,
 			}
 
 			appsetOpts := server.ApplicationSetOpts{
@@ -231,6 +248,8 @@ func NewCommand() *cobra.Command {
 				ScmRootCAPath:            scmRootCAPath,
 				AllowedScmProviders:      allowedScmProviders,
 				EnableScmProviders:       enableScmProviders,
+				MatrixMaxDepth:           matrixMaxDepth,
+				MatrixMaxParams:          matrixMaxParams,
 			}
 
 			stats.RegisterStackDumper()
@@ -238,6 +257,9 @@ func NewCommand() *cobra.Command {
 			stats.RegisterHeapDumper("memprofile")
 			argocd := server.NewServer(ctx, argoCDOpts, appsetOpts)
 			argocd.Init(ctx)
+			if enableRepositoryConfigs {
+				go controller.NewRepositoryConfigController(namespace, kubeclientset, appClientSet).Run(ctx, 1)
+			}
 			lns, err := argocd.Listen()
 			errors.CheckError(err)
 			for {
@@ -297,11 +319,20 @@ func NewCommand() *cobra.Command {
 	command.Flags().StringSliceVar(&applicationNamespaces, "application-namespaces", env.StringsFromEnv("ARGOCD_APPLICATION_NAMESPACES", []string{}, ","), "List of additional namespaces where application resources can be managed in")
 	command.Flags().BoolVar(&enableProxyExtension, "enable-proxy-extension", env.ParseBoolFromEnv("ARGOCD_SERVER_ENABLE_PROXY_EXTENSION", false), "Enable Proxy Extension feature")
 	command.Flags().IntVar(&webhookParallelism, "webhook-parallelism-limit", env.ParseNumFromEnv("ARGOCD_SERVER_WEBHOOK_PARALLELISM_LIMIT", 50, 1, 1000), "Number of webhook requests processed concurrently")
+	command.Flags().BoolVar(&enableSchemaValidation, "enable-schema-validation", env.ParseBoolFromEnv("ARGOCD_SERVER_ENABLE_SCHEMA_VALIDATION", false), "Serve a validating admission webhook which validates application parameters against JSON schemas")
+	command.Flags().DurationVar(&schemaValidationTimeout, "schema-validation-timeout", env.ParseDurationFromEnv("ARGOCD_SERVER_SCHEMA_VALIDATION_TIMEOUT", 10*time.Second, 0, math.MaxInt64), "Maximum time spent retrieving the schemas of an application during admission")
+	command.Flags().BoolVar(&enableDestValidation, "enable-destination-validation", env.ParseBoolFromEnv("ARGOCD_SERVER_ENABLE_DESTINATION_VALIDATION", false), "Serve a validating admission webhook which rejects applications whose destination is not permitted by their project")
+	command.Flags().BoolVar(&enableRepositoryConfigs, "enable-repository-configs", env.ParseBoolFromEnv("ARGOCD_SERVER_ENABLE_REPOSITORY_CONFIGS", false), "Reconcile the RepositoryConfig resources of the Argo CD namespace into repository secrets")
+	command.Flags().IntVar(&destValidationPort, "destination-validation-port", common.DefaultPortAPIServerWebhook, "Listen on given port for destination validation admission requests")
+	command.Flags().StringVar(&bootstrapAppRepo, "bootstrap-app-repo", env.StringFromEnv("ARGOCD_SERVER_BOOTSTRAP_APP_REPO", ""), "Repository of the configuration of Argo CD. If set, an application managing the configuration is created on the first startup")
+	command.Flags().StringVar(&bootstrapAppPath, "bootstrap-app-path", env.StringFromEnv("ARGOCD_SERVER_BOOTSTRAP_APP_PATH", "."), "Path of the configuration of Argo CD in the bootstrap repository")
 
 	// Flags related to the applicationSet component.
 	command.Flags().StringVar(&scmRootCAPath, "appset-scm-root-ca-path", env.StringFromEnv("ARGOCD_APPLICATIONSET_CONTROLLER_SCM_ROOT_CA_PATH", ""), "Provide Root CA Path for self-signed TLS Certificates")
 	command.Flags().BoolVar(&enableScmProviders, "appset-enable-scm-providers", env.ParseBoolFromEnv("ARGOCD_APPLICATIONSET_CONTROLLER_ENABLE_SCM_PROVIDERS", true), "Enable retrieving information from SCM providers, used by the SCM and PR generators (Default: true)")
 	command.Flags().StringSliceVar(&allowedScmProviders, "appset-allowed-scm-providers", env.StringsFromEnv("ARGOCD_APPLICATIONSET_CONTROLLER_ALLOWED_SCM_PROVIDERS", []string{}, ","), "The list of allowed custom SCM provider API URLs. This restriction does not apply to SCM or PR generators which do not accept a custom API URL. (Default: Empty = all)")
+	command.Flags().IntVar(&matrixMaxDepth, "appset-matrix-generator-max-depth", env.ParseNumFromEnv("ARGOCD_APPLICATIONSET_CONTROLLER_MATRIX_GENERATOR_MAX_DEPTH", generators.DefaultMatrixMaxDepth, 1, math.MaxInt32), "Maximum nesting depth of matrix generators")
+	command.Flags().IntVar(&matrixMaxParams, "appset-matrix-generator-max-params", env.ParseNumFromEnv("ARGOCD_APPLICATIONSET_CONTROLLER_MATRIX_GENERATOR_MAX_PARAMS", generators.DefaultMatrixMaxParams, 1, math.MaxInt32), "Maximum number of parameter combinations a matrix generator may produce")
 	command.Flags().BoolVar(&enableNewGitFileGlobbing, "appset-enable-new-git-file-globbing", env.ParseBoolFromEnv("ARGOCD_APPLICATIONSET_CONTROLLER_ENABLE_NEW_GIT_FILE_GLOBBING", false), "Enable new globbing in Git files generator.")
 
 	tlsConfigCustomizerSrc = tls.AddTLSFlagsToCmd(command)
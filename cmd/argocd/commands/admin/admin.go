@@ -61,13 +61,18 @@ $ argocd admin initial-password reset
 	command.AddCommand(NewProjectsCommand())
 	command.AddCommand(NewSettingsCommand())
 	command.AddCommand(NewAppCommand(clientOpts))
-	command.AddCommand(NewRepoCommand())
+	command.AddCommand(NewRepoCommand(clientOpts))
+	command.AddCommand(NewRepoServerCommand(clientOpts))
 	command.AddCommand(NewImportCommand())
 	command.AddCommand(NewExportCommand())
 	command.AddCommand(NewDashboardCommand(clientOpts))
 	command.AddCommand(NewNotificationsCommand())
 	command.AddCommand(NewInitialPasswordCommand())
 	command.AddCommand(NewRedisInitialPasswordCommand())
+	command.AddCommand(NewDebugCommand())
+	command.AddCommand(NewHealthCommand())
+	command.AddCommand(NewWebhookCommand())
+	command.AddCommand(NewPolicyCommand())
 
 	command.Flags().StringVar(&cmdutil.LogFormat, "logformat", "text", "Set the logging format. One of: text|json")
 	command.Flags().StringVar(&cmdutil.LogLevel, "loglevel", "info", "Set the logging level. One of: debug|info|warn|error")
@@ -0,0 +1,75 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/argoproj/argo-cd/v2/util/errors"
+	argoio "github.com/argoproj/argo-cd/v2/util/io"
+	"github.com/argoproj/argo-cd/v2/util/profile"
+)
+
+// NewDebugCommand returns a new instance of the `argocd admin debug` command
+func NewDebugCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "debug",
+		Short: "Collect debugging information from Argo CD components",
+		Run: func(c *cobra.Command, args []string) {
+			c.HelpFunc()(c, args)
+		},
+	}
+	command.AddCommand(NewHeapProfileCommand())
+	return command
+}
+
+// NewHeapProfileCommand returns a new instance of the `argocd admin debug heap-profile` command
+func NewHeapProfileCommand() *cobra.Command {
+	var (
+		address string
+		output  string
+		timeout time.Duration
+	)
+	command := &cobra.Command{
+		Use:   "heap-profile",
+		Short: "Download a heap profile from a component started with --enable-pprof",
+		Example: `# Forward the pprof port of the repo server and download its heap profile
+kubectl port-forward deploy/argocd-repo-server 6060:6060
+argocd admin debug heap-profile --output repo-server-heap.pprof
+
+# Inspect the downloaded profile
+go tool pprof -top repo-server-heap.pprof`,
+		Run: func(c *cobra.Command, args []string) {
+			errors.CheckError(downloadHeapProfile(&http.Client{Timeout: timeout}, address, output))
+			fmt.Printf("Heap profile saved to %s\n", output)
+		},
+	}
+	command.Flags().StringVar(&address, "address", fmt.Sprintf("localhost:%d", profile.DefaultPort), "Address of the pprof server")
+	command.Flags().StringVarP(&output, "output", "o", "heap.pprof", "File the heap profile is written to")
+	command.Flags().DurationVar(&timeout, "timeout", time.Minute, "Timeout of the request to the pprof server")
+	return command
+}
+
+func downloadHeapProfile(client *http.Client, address, output string) error {
+	resp, err := client.Get(fmt.Sprintf("http://%s/debug/pprof/heap", address))
+	if err != nil {
+		return fmt.Errorf("error requesting heap profile: %w", err)
+	}
+	defer argoio.Close(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d requesting heap profile", resp.StatusCode)
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("error creating output file %s: %w", output, err)
+	}
+	defer argoio.Close(f)
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		return fmt.Errorf("error writing heap profile to %s: %w", output, err)
+	}
+	return nil
+}
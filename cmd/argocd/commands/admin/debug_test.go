@@ -0,0 +1,40 @@
+package admin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadHeapProfile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/debug/pprof/heap" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("profile-data"))
+	}))
+	defer srv.Close()
+	address := strings.TrimPrefix(srv.URL, "http://")
+
+	t.Run("profile is saved", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "heap.pprof")
+		require.NoError(t, downloadHeapProfile(srv.Client(), address, output))
+		data, err := os.ReadFile(output)
+		require.NoError(t, err)
+		assert.Equal(t, "profile-data", string(data))
+	})
+
+	t.Run("server unavailable", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "heap.pprof")
+		err := downloadHeapProfile(srv.Client(), "127.0.0.1:1", output)
+		require.Error(t, err)
+		assert.NoFileExists(t, output)
+	})
+}
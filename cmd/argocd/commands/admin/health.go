@@ -0,0 +1,202 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-cd/v2/util/errors"
+	healthutil "github.com/argoproj/argo-cd/v2/util/health"
+)
+
+// NewHealthCommand returns a new instance of an `argocd admin health` command
+func NewHealthCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "health",
+		Short: "Assess the health of resources using the built-in health checks",
+		Run: func(c *cobra.Command, args []string) {
+			c.HelpFunc()(c, args)
+		},
+	}
+
+	command.AddCommand(NewGatewayHealthCommand())
+	command.AddCommand(NewRolloutHealthCommand())
+	command.AddCommand(NewKEDAHealthCommand())
+	command.AddCommand(NewCrossplaneHealthCommand())
+	return command
+}
+
+// NewGatewayHealthCommand returns a new instance of an `argocd admin health gateway` command
+func NewGatewayHealthCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "gateway KIND RESOURCE_PATH",
+		Short: "Assess the health of a Gateway API resource",
+		Long:  "Assess the health of a Gateway, HTTPRoute or GRPCRoute read from a JSON or YAML file, or from the standard input if the path is '-'",
+		Example: `  # Assess the health of a HTTPRoute
+  argocd admin health gateway HTTPRoute ./httproute.json
+
+  # Assess the health of a live Gateway
+  kubectl get gateway my-gateway -o json | argocd admin health gateway Gateway -`,
+		Run: func(c *cobra.Command, args []string) {
+			if len(args) != 2 {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+
+			var data []byte
+			var err error
+			if args[1] == "-" {
+				data, err = io.ReadAll(os.Stdin)
+			} else {
+				data, err = os.ReadFile(args[1])
+			}
+			errors.CheckError(err)
+			resHealth, err := getGatewayAPIResourceHealth(args[0], data)
+			errors.CheckError(err)
+			fmt.Printf("STATUS: %s\n", resHealth.Status)
+			fmt.Printf("MESSAGE: %s\n", resHealth.Message)
+		},
+	}
+	return command
+}
+
+// getGatewayAPIResourceHealth returns the health of the Gateway API resource of the given kind
+func getGatewayAPIResourceHealth(kind string, data []byte) (*health.HealthStatus, error) {
+	healthCheck := healthutil.GetHealthCheckFunc(schema.GroupVersionKind{Group: healthutil.GatewayAPIGroup, Kind: kind})
+	if healthCheck == nil {
+		return nil, fmt.Errorf("unsupported kind %q: must be one of %s, %s or %s", kind, healthutil.GatewayKind, healthutil.HTTPRouteKind, healthutil.GRPCRouteKind)
+	}
+	res := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("error parsing the resource: %w", err)
+	}
+	return healthCheck(res)
+}
+
+// NewRolloutHealthCommand returns a new instance of an `argocd admin health rollout` command
+func NewRolloutHealthCommand() *cobra.Command {
+	var resourceJSON string
+	command := &cobra.Command{
+		Use:   "rollout",
+		Short: "Assess the health of an Argo Rollouts resource",
+		Long:  "Assess the health of a Rollout or AnalysisRun given as JSON, including its status",
+		Example: `  # Assess the health of a live Rollout
+  argocd admin health rollout --json "$(kubectl get rollout my-rollout -o json)"
+
+  # Assess the health of an AnalysisRun
+  argocd admin health rollout --json '{"apiVersion": "argoproj.io/v1alpha1", "kind": "AnalysisRun", "status": {"phase": "Successful"}}'`,
+		Run: func(c *cobra.Command, args []string) {
+			if resourceJSON == "" {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			resHealth, err := getArgoRolloutsResourceHealth([]byte(resourceJSON))
+			errors.CheckError(err)
+			fmt.Printf("STATUS: %s\n", resHealth.Status)
+			fmt.Printf("MESSAGE: %s\n", resHealth.Message)
+		},
+	}
+	command.Flags().StringVar(&resourceJSON, "json", "", "JSON of the Rollout or AnalysisRun, including its status")
+	return command
+}
+
+// getArgoRolloutsResourceHealth returns the health of the Argo Rollouts resource
+func getArgoRolloutsResourceHealth(data []byte) (*health.HealthStatus, error) {
+	res := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("error parsing the resource: %w", err)
+	}
+	gvk := res.GroupVersionKind()
+	if gvk.Group != healthutil.ArgoRolloutsGroup || gvk.Kind != healthutil.RolloutKind && gvk.Kind != healthutil.AnalysisRunKind {
+		return nil, fmt.Errorf("unsupported resource %q: must be a %s %s or %s", gvk.GroupKind(), healthutil.ArgoRolloutsGroup, healthutil.RolloutKind, healthutil.AnalysisRunKind)
+	}
+	return healthutil.GetHealthCheckFunc(gvk)(res)
+}
+
+// NewKEDAHealthCommand returns a new instance of an `argocd admin health keda` command
+func NewKEDAHealthCommand() *cobra.Command {
+	var resourceJSON string
+	command := &cobra.Command{
+		Use:   "keda",
+		Short: "Assess the health of a KEDA resource",
+		Long:  "Assess the health of a ScaledObject or ScaledJob given as JSON, including its status",
+		Example: `  # Assess the health of a live ScaledObject
+  argocd admin health keda --json "$(kubectl get scaledobject my-scaledobject -o json)"
+
+  # Assess the health of a ScaledJob
+  argocd admin health keda --json '{"apiVersion": "keda.sh/v1alpha1", "kind": "ScaledJob", "status": {"conditions": [{"type": "Ready", "status": "True"}]}}'`,
+		Run: func(c *cobra.Command, args []string) {
+			if resourceJSON == "" {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			resHealth, err := getKEDAResourceHealth([]byte(resourceJSON))
+			errors.CheckError(err)
+			fmt.Printf("STATUS: %s\n", resHealth.Status)
+			fmt.Printf("MESSAGE: %s\n", resHealth.Message)
+		},
+	}
+	command.Flags().StringVar(&resourceJSON, "json", "", "JSON of the ScaledObject or ScaledJob, including its status")
+	return command
+}
+
+// getKEDAResourceHealth returns the health of the KEDA resource
+func getKEDAResourceHealth(data []byte) (*health.HealthStatus, error) {
+	res := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("error parsing the resource: %w", err)
+	}
+	gvk := res.GroupVersionKind()
+	if gvk.Group != healthutil.KEDAGroup || gvk.Kind != healthutil.ScaledObjectKind && gvk.Kind != healthutil.ScaledJobKind {
+		return nil, fmt.Errorf("unsupported resource %q: must be a %s %s or %s", gvk.GroupKind(), healthutil.KEDAGroup, healthutil.ScaledObjectKind, healthutil.ScaledJobKind)
+	}
+	return healthutil.GetHealthCheckFunc(gvk)(res)
+}
+
+// NewCrossplaneHealthCommand returns a new instance of an `argocd admin health crossplane` command
+func NewCrossplaneHealthCommand() *cobra.Command {
+	var resourceJSON string
+	command := &cobra.Command{
+		Use:   "crossplane",
+		Short: "Assess the health of a Crossplane resource",
+		Long:  "Assess the health of a CompositeResourceDefinition, composite resource or claim given as JSON, including its status",
+		Example: `  # Assess the health of a live composite resource
+  argocd admin health crossplane --json "$(kubectl get xpostgresqlinstance my-db-7xqzk -o json)"
+
+  # Assess the health of a CompositeResourceDefinition
+  argocd admin health crossplane --json '{"apiVersion": "apiextensions.crossplane.io/v1", "kind": "CompositeResourceDefinition", "status": {"conditions": [{"type": "Established", "status": "True"}]}}'`,
+		Run: func(c *cobra.Command, args []string) {
+			if resourceJSON == "" {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			resHealth, err := getCrossplaneResourceHealth([]byte(resourceJSON))
+			errors.CheckError(err)
+			fmt.Printf("STATUS: %s\n", resHealth.Status)
+			fmt.Printf("MESSAGE: %s\n", resHealth.Message)
+		},
+	}
+	command.Flags().StringVar(&resourceJSON, "json", "", "JSON of the CompositeResourceDefinition, composite resource or claim, including its status")
+	return command
+}
+
+// getCrossplaneResourceHealth returns the health of the Crossplane resource
+func getCrossplaneResourceHealth(data []byte) (*health.HealthStatus, error) {
+	res := &unstructured.Unstructured{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("error parsing the resource: %w", err)
+	}
+	gvk := res.GroupVersionKind()
+	healthCheck := healthutil.GetResourceHealthCheckFunc(res)
+	if healthCheck == nil || gvk.Group != healthutil.CrossplaneAPIExtensionsGroup && healthutil.GetHealthCheckFunc(gvk) != nil {
+		return nil, fmt.Errorf("unsupported resource %q: must be a %s %s, or a composite resource or claim referencing its composition", gvk.GroupKind(), healthutil.CrossplaneAPIExtensionsGroup, healthutil.CompositeResourceDefinitionKind)
+	}
+	return healthCheck(res)
+}
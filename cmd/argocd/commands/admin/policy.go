@@ -0,0 +1,83 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	appwebhook "github.com/argoproj/argo-cd/v2/server/webhook"
+	"github.com/argoproj/argo-cd/v2/util/errors"
+)
+
+// NewPolicyCommand returns a new instance of an `argocd admin policy` command
+func NewPolicyCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "policy",
+		Short: "Test the Rego policies applications are validated against",
+		Run: func(c *cobra.Command, args []string) {
+			c.HelpFunc()(c, args)
+		},
+	}
+
+	command.AddCommand(NewPolicyTestCommand())
+	return command
+}
+
+// NewPolicyTestCommand returns a new instance of an `argocd admin policy test` command
+func NewPolicyTestCommand() *cobra.Command {
+	var (
+		policyPaths []string
+		appPath     string
+	)
+	command := &cobra.Command{
+		Use:   "test",
+		Short: "Test an application against Rego policies",
+		Long:  "Test an application against Rego policies, as the validating admission webhook of the argocd-app-policies ConfigMap does. Exits with status 1 if the application is denied.",
+		Example: `  # Test an application against a policy
+  argocd admin policy test --policy policy.rego --app app.yaml
+
+  # Test an application against several policies
+  argocd admin policy test --policy production.rego --policy labels.rego --app app.yaml`,
+		Run: func(c *cobra.Command, args []string) {
+			if len(policyPaths) == 0 || appPath == "" {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			appData, err := os.ReadFile(appPath)
+			errors.CheckError(err)
+			policies := map[string]string{}
+			for _, path := range policyPaths {
+				data, err := os.ReadFile(path)
+				errors.CheckError(err)
+				policies[filepath.Base(path)] = string(data)
+			}
+
+			denials, err := testApplicationPolicies(context.Background(), policies, appData)
+			errors.CheckError(err)
+			if len(denials) == 0 {
+				fmt.Println("Allowed")
+				return
+			}
+			for _, denial := range denials {
+				fmt.Printf("Denied: %s\n", denial)
+			}
+			os.Exit(1)
+		},
+	}
+	command.Flags().StringArrayVar(&policyPaths, "policy", []string{}, "Path to a Rego policy. Can be repeated")
+	command.Flags().StringVar(&appPath, "app", "", "Path to the application manifest, in YAML or JSON")
+	return command
+}
+
+// testApplicationPolicies evaluates the policies, given by file name, with the application manifest and returns the
+// messages denying it
+func testApplicationPolicies(ctx context.Context, policies map[string]string, appData []byte) ([]string, error) {
+	compiled, err := appwebhook.CompileApplicationPolicies(ctx, policies)
+	if err != nil {
+		return nil, err
+	}
+	return compiled.Evaluate(ctx, appData)
+}
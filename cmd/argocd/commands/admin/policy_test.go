@@ -0,0 +1,25 @@
+package admin
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTestApplicationPolicies(t *testing.T) {
+	policy, err := os.ReadFile("testdata/policy/production.rego")
+	require.NoError(t, err)
+	app, err := os.ReadFile("testdata/policy/app.yaml")
+	require.NoError(t, err)
+
+	denials, err := testApplicationPolicies(context.Background(), map[string]string{"production.rego": string(policy)}, app)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"production application guestbook must enable selfHeal"}, denials)
+
+	denials, err = testApplicationPolicies(context.Background(), map[string]string{"production.rego": string(policy)}, []byte("metadata:\n  name: guestbook\n  labels:\n    env: staging\n"))
+	require.NoError(t, err)
+	assert.Empty(t, denials)
+}
@@ -2,20 +2,32 @@ package admin
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
 
+	humanize "github.com/dustin/go-humanize"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	apiv1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
 
+	"github.com/argoproj/argo-cd/v2/cmd/argocd/commands/headless"
 	cmdutil "github.com/argoproj/argo-cd/v2/cmd/util"
 	"github.com/argoproj/argo-cd/v2/common"
+	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	appsv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/util/argo"
 	"github.com/argoproj/argo-cd/v2/util/cli"
 	"github.com/argoproj/argo-cd/v2/util/db"
 	"github.com/argoproj/argo-cd/v2/util/errors"
 	"github.com/argoproj/argo-cd/v2/util/git"
+	argoio "github.com/argoproj/argo-cd/v2/util/io"
 	"github.com/argoproj/argo-cd/v2/util/settings"
 )
 
@@ -24,7 +36,7 @@ const (
 	repoSecretPrefix = "repo"
 )
 
-func NewRepoCommand() *cobra.Command {
+func NewRepoCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
 	command := &cobra.Command{
 		Use:   "repo",
 		Short: "Manage repositories configuration",
@@ -33,6 +45,7 @@ func NewRepoCommand() *cobra.Command {
 		},
 	}
 	command.AddCommand(NewGenRepoSpecCommand())
+	command.AddCommand(NewRepoProfileCommand(clientOpts))
 
 	return command
 }
@@ -126,6 +139,14 @@ func NewGenRepoSpecCommand() *cobra.Command {
 			repoOpts.Repo.Insecure = repoOpts.InsecureSkipServerVerification
 			repoOpts.Repo.EnableLFS = repoOpts.EnableLfs
 			repoOpts.Repo.EnableOCI = repoOpts.EnableOci
+			if repoOpts.Repo.OCIType != "" {
+				if !repoOpts.Repo.EnableOCI {
+					errors.CheckError(fmt.Errorf("--oci-type is only supported for OCI repositories"))
+				}
+				if repoOpts.Repo.OCIType != appsv1.OCITypeHelm && repoOpts.Repo.OCIType != appsv1.OCITypeManifests {
+					errors.CheckError(fmt.Errorf("--oci-type must be %q or %q", appsv1.OCITypeHelm, appsv1.OCITypeManifests))
+				}
+			}
 
 			if repoOpts.Repo.Type == "helm" && repoOpts.Repo.Name == "" {
 				errors.CheckError(fmt.Errorf("must specify --name for repos of type 'helm'"))
@@ -167,3 +188,75 @@ func NewGenRepoSpecCommand() *cobra.Command {
 	cmdutil.AddRepoFlags(command, &repoOpts)
 	return command
 }
+
+// NewRepoProfileCommand returns a new instance of an `argocd admin repo profile` command
+func NewRepoProfileCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var (
+		outputDir    string
+		revision     string
+		appNamespace string
+		project      string
+	)
+	command := &cobra.Command{
+		Use:   "profile APPNAME",
+		Short: "Profile the manifest generation of an application",
+		Long:  "Generate the manifests of each source of an application without cache, and save the CPU profile of the repo server during the generation in the pprof format. Requires the permission to update the application.",
+		Example: `  # Profile the manifest generation of an application, and save the CPU profile in the current directory
+  argocd admin repo profile my-app
+
+  # Profile the manifest generation of an application at a revision
+  argocd admin repo profile my-app --revision 1.2.3 --output-dir /tmp/profiles
+
+  # Inspect a saved CPU profile
+  go tool pprof -top my-app.pprof`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+
+			if len(args) != 1 {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			appName, appNs := argo.ParseFromQualifiedName(args[0], appNamespace)
+
+			conn, appIf := headless.NewClientOrDie(clientOpts, c).NewApplicationClientOrDie()
+			defer argoio.Close(conn)
+
+			query := &applicationpkg.ApplicationManifestQuery{
+				Name:         &appName,
+				AppNamespace: &appNs,
+				Project:      &project,
+			}
+			if revision != "" {
+				query.Revision = &revision
+			}
+			res, err := appIf.ProfileManifests(ctx, query)
+			errors.CheckError(err)
+			errors.CheckError(saveManifestProfiles(os.Stdout, outputDir, appName, res.Items))
+		},
+	}
+	command.Flags().StringVar(&outputDir, "output-dir", ".", "Directory to save the CPU profiles to")
+	command.Flags().StringVar(&revision, "revision", "", "Revision of the application to generate the manifests of")
+	command.Flags().StringVarP(&appNamespace, "app-namespace", "N", "", "Namespace of the application")
+	command.Flags().StringVar(&project, "project", "", "Project of the application")
+	return command
+}
+
+// saveManifestProfiles saves the CPU profile of each source of the application to the directory, and prints the
+// durations and peak memories of the generations in a table
+func saveManifestProfiles(out io.Writer, dir string, appName string, profiles []*apiclient.ProfileResult) error {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "SOURCE\tREVISION\tDURATION\tPEAK MEMORY\tMANIFESTS\tPROFILE\n")
+	for i, profile := range profiles {
+		name := appName + ".pprof"
+		if len(profiles) > 1 {
+			name = fmt.Sprintf("%s-%d.pprof", appName, i+1)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, profile.CpuProfile, 0o644); err != nil {
+			return fmt.Errorf("error saving CPU profile: %w", err)
+		}
+		duration := time.Duration(profile.DurationMs) * time.Millisecond
+		_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\n", i+1, profile.Revision, duration, humanize.IBytes(uint64(profile.PeakMemoryBytes)), profile.Manifests, path)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+)
+
+func TestSaveManifestProfiles(t *testing.T) {
+	t.Run("Single source", func(t *testing.T) {
+		dir := t.TempDir()
+		var out bytes.Buffer
+		err := saveManifestProfiles(&out, dir, "guestbook", []*apiclient.ProfileResult{
+			{DurationMs: 1500, PeakMemoryBytes: 2048, CpuProfile: []byte("profile"), Revision: "abc", Manifests: 3},
+		})
+		require.NoError(t, err)
+		path := filepath.Join(dir, "guestbook.pprof")
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "profile", string(data))
+		assert.Equal(t, `SOURCE  REVISION  DURATION  PEAK MEMORY  MANIFESTS  PROFILE
+1       abc       1.5s      2.0 KiB      3          `+path+`
+`, out.String())
+	})
+
+	t.Run("Multiple sources", func(t *testing.T) {
+		dir := t.TempDir()
+		var out bytes.Buffer
+		err := saveManifestProfiles(&out, dir, "guestbook", []*apiclient.ProfileResult{
+			{CpuProfile: []byte("first")},
+			{CpuProfile: []byte("second")},
+		})
+		require.NoError(t, err)
+		for name, expected := range map[string]string{"guestbook-1.pprof": "first", "guestbook-2.pprof": "second"} {
+			data, err := os.ReadFile(filepath.Join(dir, name))
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(data))
+		}
+	})
+
+	t.Run("Missing directory", func(t *testing.T) {
+		err := saveManifestProfiles(&bytes.Buffer{}, filepath.Join(t.TempDir(), "missing"), "guestbook", []*apiclient.ProfileResult{{}})
+		require.ErrorContains(t, err, "error saving CPU profile")
+	})
+}
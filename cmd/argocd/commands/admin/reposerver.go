@@ -0,0 +1,121 @@
+package admin
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	humanize "github.com/dustin/go-humanize"
+	"github.com/spf13/cobra"
+
+	"github.com/argoproj/argo-cd/v2/cmd/argocd/commands/headless"
+	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	repositorypkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/repository"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/util/errors"
+	argoio "github.com/argoproj/argo-cd/v2/util/io"
+)
+
+// NewRepoServerCommand returns a new instance of an `argocd admin repo-server` command
+func NewRepoServerCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "repo-server",
+		Short: "Manage the repo server",
+		Run: func(c *cobra.Command, args []string) {
+			c.HelpFunc()(c, args)
+		},
+	}
+	command.AddCommand(NewRepoServerCacheCommand(clientOpts))
+	return command
+}
+
+// NewRepoServerCacheCommand returns a new instance of an `argocd admin repo-server cache` command
+func NewRepoServerCacheCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect the cache of the repo server",
+		Run: func(c *cobra.Command, args []string) {
+			c.HelpFunc()(c, args)
+		},
+	}
+	command.AddCommand(NewRepoServerCacheListCommand(clientOpts))
+	return command
+}
+
+// NewRepoServerCacheListCommand returns a new instance of an `argocd admin repo-server cache list` command
+func NewRepoServerCacheListCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var (
+		cursor uint64
+		count  int64
+		all    bool
+		output string
+	)
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List the keys stored by the repo server in the cache",
+		Long:  "List the keys stored by the repo server in the cache, along with the type of the cached data, their remaining time to live and their size. Requires the permission to update all repositories, granted by role:admin.",
+		Example: `  # List the first page of cached keys
+  argocd admin repo-server cache list
+
+  # List the next page of cached keys
+  argocd admin repo-server cache list --cursor 1234
+
+  # List all cached keys in JSON format
+  argocd admin repo-server cache list --all -o json`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+
+			if len(args) != 0 {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+
+			conn, repoIf := headless.NewClientOrDie(clientOpts, c).NewRepoClientOrDie()
+			defer argoio.Close(conn)
+
+			keys := &apiclient.CachedKeyList{Cursor: cursor}
+			for {
+				res, err := repoIf.ListCachedKeys(ctx, &repositorypkg.RepoCachedKeysQuery{Cursor: keys.Cursor, Count: count})
+				errors.CheckError(err)
+				keys.Items = append(keys.Items, res.Items...)
+				keys.Cursor = res.Cursor
+				if !all || keys.Cursor == 0 {
+					break
+				}
+			}
+
+			switch output {
+			case "json", "yaml":
+				errors.CheckError(PrintResources(output, os.Stdout, keys))
+			case "":
+				printCachedKeys(os.Stdout, keys.Items)
+				if keys.Cursor != 0 {
+					fmt.Fprintf(os.Stderr, "More keys may be cached, use --cursor %d to list them\n", keys.Cursor)
+				}
+			default:
+				errors.CheckError(fmt.Errorf("unknown output format: %s", output))
+			}
+		},
+	}
+	command.Flags().Uint64Var(&cursor, "cursor", 0, "Cursor returned by a previous listing to continue from")
+	command.Flags().Int64Var(&count, "count", 100, "Number of keys to scan per request, a page may contain fewer keys")
+	command.Flags().BoolVar(&all, "all", false, "List all pages of cached keys")
+	command.Flags().StringVarP(&output, "output", "o", "", "Output format. One of: json|yaml")
+	return command
+}
+
+// printCachedKeys prints the cached keys in a table
+func printCachedKeys(out io.Writer, keys []*apiclient.CachedKey) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "TYPE\tTTL\tSIZE\tKEY\n")
+	for _, key := range keys {
+		ttl := "-"
+		if key.TtlSeconds >= 0 {
+			ttl = (time.Duration(key.TtlSeconds) * time.Second).String()
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", key.Type, ttl, humanize.IBytes(uint64(key.SizeBytes)), key.Key)
+	}
+	_ = w.Flush()
+}
@@ -0,0 +1,22 @@
+package admin
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+)
+
+func TestPrintCachedKeys(t *testing.T) {
+	var out bytes.Buffer
+	printCachedKeys(&out, []*apiclient.CachedKey{
+		{Key: "mfst|app|HEAD|default|123|1.8.3", Type: "manifest", TtlSeconds: 3600, SizeBytes: 2048},
+		{Key: "git-refs|https://github.com/org/repo|1.8.3", Type: "revision", TtlSeconds: -1, SizeBytes: 10},
+	})
+	assert.Equal(t, `TYPE      TTL     SIZE     KEY
+manifest  1h0m0s  2.0 KiB  mfst|app|HEAD|default|123|1.8.3
+revision  -       10 B     git-refs|https://github.com/org/repo|1.8.3
+`, out.String())
+}
@@ -0,0 +1,8 @@
+package argocd.applications
+
+# production applications must heal drift automatically
+deny[msg] {
+	input.metadata.labels.env == "production"
+	not input.spec.syncPolicy.automated.selfHeal
+	msg := sprintf("production application %s must enable selfHeal", [input.metadata.name])
+}
@@ -0,0 +1,108 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	appwebhook "github.com/argoproj/argo-cd/v2/server/webhook"
+	"github.com/argoproj/argo-cd/v2/util/cli"
+	"github.com/argoproj/argo-cd/v2/util/errors"
+)
+
+// NewWebhookCommand returns a new instance of an `argocd admin webhook` command
+func NewWebhookCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "webhook",
+		Short: "Test the admission webhooks of the API server",
+		Run: func(c *cobra.Command, args []string) {
+			c.HelpFunc()(c, args)
+		},
+	}
+
+	command.AddCommand(NewWebhookTestCommand())
+	return command
+}
+
+// NewWebhookTestCommand returns a new instance of an `argocd admin webhook test` command
+func NewWebhookTestCommand() *cobra.Command {
+	var (
+		clientConfig  clientcmd.ClientConfig
+		appPath       string
+		mutationsPath string
+	)
+	command := &cobra.Command{
+		Use:   "test",
+		Short: "Preview the mutations applied to an application by the mutating admission webhook",
+		Long:  "Preview the mutations applied to an application by the mutating admission webhook, using the rules of the argocd-app-mutations ConfigMap of the cluster, or of a local file",
+		Example: `  # Preview the mutations of an application with the rules of the cluster
+  argocd admin webhook test --app app.yaml
+
+  # Preview the mutations of an application with the rules of a local ConfigMap
+  argocd admin webhook test --app app.yaml --mutations argocd-app-mutations.yaml`,
+		Run: func(c *cobra.Command, args []string) {
+			if appPath == "" {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			appData, err := os.ReadFile(appPath)
+			errors.CheckError(err)
+
+			var cm *corev1.ConfigMap
+			if mutationsPath != "" {
+				data, err := os.ReadFile(mutationsPath)
+				errors.CheckError(err)
+				cm = &corev1.ConfigMap{}
+				errors.CheckError(yaml.Unmarshal(data, cm))
+			} else {
+				config, err := clientConfig.ClientConfig()
+				errors.CheckError(err)
+				namespace, _, err := clientConfig.Namespace()
+				errors.CheckError(err)
+				cm, err = kubernetes.NewForConfigOrDie(config).CoreV1().ConfigMaps(namespace).Get(context.Background(), common.ArgoCDAppMutationsConfigMapName, v1.GetOptions{})
+				errors.CheckError(err)
+			}
+
+			mutated, warnings, err := previewApplicationMutations(appData, cm)
+			errors.CheckError(err)
+			for _, warning := range warnings {
+				_, _ = fmt.Fprintf(os.Stderr, "Warning: %s\n", warning)
+			}
+			fmt.Print(string(mutated))
+		},
+	}
+	clientConfig = cli.AddKubectlFlagsToCmd(command)
+	command.Flags().StringVar(&appPath, "app", "", "Path to the application manifest, in YAML or JSON")
+	command.Flags().StringVar(&mutationsPath, "mutations", "", "Path to an argocd-app-mutations ConfigMap to read the mutation rules from, instead of the cluster")
+	return command
+}
+
+// previewApplicationMutations applies the mutation rules of the ConfigMap to the application manifest as if it was
+// created, and returns the mutated application as YAML along with the warnings of the rules which were not applied
+func previewApplicationMutations(appData []byte, cm *corev1.ConfigMap) ([]byte, []string, error) {
+	rules, err := appwebhook.MutationRulesFromConfigMap(cm)
+	if err != nil {
+		return nil, nil, err
+	}
+	appJSON, err := yaml.YAMLToJSON(appData)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing the application: %w", err)
+	}
+	mutated, _, warnings, err := appwebhook.MutateApplication(appJSON, rules)
+	if err != nil {
+		return nil, nil, err
+	}
+	mutatedYAML, err := yaml.JSONToYAML(mutated)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error converting the mutated application to YAML: %w", err)
+	}
+	return mutatedYAML, warnings, nil
+}
@@ -6,7 +6,9 @@ import (
 	std_errors "errors"
 	"fmt"
 	"io"
+	"net/url"
 	"os"
+	"path"
 	"reflect"
 	"sort"
 	"strconv"
@@ -83,6 +85,9 @@ func NewApplicationCommand(clientOpts *argocdclient.ClientOptions) *cobra.Comman
 	command.AddCommand(NewApplicationUnsetCommand(clientOpts))
 	command.AddCommand(NewApplicationSyncCommand(clientOpts))
 	command.AddCommand(NewApplicationHistoryCommand(clientOpts))
+	command.AddCommand(NewApplicationConditionsCommand(clientOpts))
+	command.AddCommand(NewApplicationCheckDeprecationsCommand(clientOpts))
+	command.AddCommand(NewApplicationBadgeCommand(clientOpts))
 	command.AddCommand(NewApplicationRollbackCommand(clientOpts))
 	command.AddCommand(NewApplicationListCommand(clientOpts))
 	command.AddCommand(NewApplicationDeleteCommand(clientOpts))
@@ -95,9 +100,15 @@ func NewApplicationCommand(clientOpts *argocdclient.ClientOptions) *cobra.Comman
 	command.AddCommand(NewApplicationDeleteResourceCommand(clientOpts))
 	command.AddCommand(NewApplicationResourceActionsCommand(clientOpts))
 	command.AddCommand(NewApplicationListResourcesCommand(clientOpts))
+	command.AddCommand(NewApplicationResourceTimelineCommand(clientOpts))
 	command.AddCommand(NewApplicationLogsCommand(clientOpts))
+	command.AddCommand(NewApplicationExecCommand(clientOpts))
 	command.AddCommand(NewApplicationAddSourceCommand(clientOpts))
 	command.AddCommand(NewApplicationRemoveSourceCommand(clientOpts))
+	command.AddCommand(NewApplicationValidateCommand())
+	command.AddCommand(NewApplicationArtifactsCommand(clientOpts))
+	command.AddCommand(NewApplicationDryRunCommand(clientOpts))
+	command.AddCommand(NewApplicationPinRevisionCommand(clientOpts))
 	return command
 }
 
@@ -598,6 +609,9 @@ func printAppSummaryTable(app *argoappv1.Application, appURL string, windows *ar
 		}
 		for _, w := range *windows {
 			s := w.Kind + ":" + w.Schedule + ":" + w.Duration
+			if w.ChronoSchedule != "" {
+				s = w.Kind + ":" + w.ChronoSchedule
+			}
 			wds = append(wds, s)
 		}
 	} else {
@@ -660,8 +674,8 @@ func printAppConditions(w io.Writer, app *argoappv1.Application) {
 	}
 }
 
-// appURLDefault returns the default URL of an application
-func appURLDefault(acdClient argocdclient.Client, appName string) string {
+// serverURLDefault returns the default URL of the Argo CD server
+func serverURLDefault(acdClient argocdclient.Client) string {
 	var scheme string
 	opts := acdClient.ClientOptions()
 	server := opts.ServerAddr
@@ -673,20 +687,39 @@ func appURLDefault(acdClient argocdclient.Client, appName string) string {
 			server = server[0 : len(server)-4]
 		}
 	}
-	return fmt.Sprintf("%s://%s/applications/%s", scheme, server, appName)
+	return fmt.Sprintf("%s://%s", scheme, server)
 }
 
-// appURL returns the URL of an application
-func appURL(ctx context.Context, acdClient argocdclient.Client, appName string) string {
+// serverURL returns the URL of the Argo CD server
+func serverURL(ctx context.Context, acdClient argocdclient.Client) string {
 	conn, settingsIf := acdClient.NewSettingsClientOrDie()
 	defer argoio.Close(conn)
 	argoSettings, err := settingsIf.Get(ctx, &settings.SettingsQuery{})
 	errors.CheckError(err)
 
 	if argoSettings.URL != "" {
-		return fmt.Sprintf("%s/applications/%s", argoSettings.URL, appName)
+		return argoSettings.URL
+	}
+	return serverURLDefault(acdClient)
+}
+
+// appURLDefault returns the default URL of an application
+func appURLDefault(acdClient argocdclient.Client, appName string) string {
+	return fmt.Sprintf("%s/applications/%s", serverURLDefault(acdClient), appName)
+}
+
+// appURL returns the URL of an application
+func appURL(ctx context.Context, acdClient argocdclient.Client, appName string) string {
+	return fmt.Sprintf("%s/applications/%s", serverURL(ctx, acdClient), appName)
+}
+
+// appBadgeURL returns the URL of the status badge of an application
+func appBadgeURL(baseURL string, appName string, appNamespace string) string {
+	badgeURL := fmt.Sprintf("%s/api/badge/applications/%s", strings.TrimSuffix(baseURL, "/"), url.PathEscape(appName))
+	if appNamespace != "" {
+		badgeURL += "?namespace=" + url.QueryEscape(appNamespace)
 	}
-	return appURLDefault(acdClient, appName)
+	return badgeURL
 }
 
 func truncateString(str string, num int) string {
@@ -1081,7 +1114,9 @@ func (p *resourceInfoProvider) IsNamespaced(gk schema.GroupKind) (bool, error) {
 	return p.namespacedByGk[gk], nil
 }
 
-func groupObjsByKey(localObs []*unstructured.Unstructured, liveObjs []*unstructured.Unstructured, appNamespace string) map[kube.ResourceKey]*unstructured.Unstructured {
+// newResourceInfoProvider returns a resource info provider inferring whether the resources are namespaced from the
+// given live objects
+func newResourceInfoProvider(liveObjs []*unstructured.Unstructured) *resourceInfoProvider {
 	namespacedByGk := make(map[schema.GroupKind]bool)
 	for i := range liveObjs {
 		if liveObjs[i] != nil {
@@ -1089,7 +1124,11 @@ func groupObjsByKey(localObs []*unstructured.Unstructured, liveObjs []*unstructu
 			namespacedByGk[schema.GroupKind{Group: key.Group, Kind: key.Kind}] = key.Namespace != ""
 		}
 	}
-	localObs, _, err := controller.DeduplicateTargetObjects(appNamespace, localObs, &resourceInfoProvider{namespacedByGk: namespacedByGk})
+	return &resourceInfoProvider{namespacedByGk: namespacedByGk}
+}
+
+func groupObjsByKey(localObs []*unstructured.Unstructured, liveObjs []*unstructured.Unstructured, appNamespace string) map[kube.ResourceKey]*unstructured.Unstructured {
+	localObs, _, err := controller.DeduplicateTargetObjects(appNamespace, localObs, newResourceInfoProvider(liveObjs))
 	errors.CheckError(err)
 	objByKey := make(map[kube.ResourceKey]*unstructured.Unstructured)
 	for i := range localObs {
@@ -1110,18 +1149,22 @@ type objKeyLiveTarget struct {
 // NewApplicationDiffCommand returns a new instance of an `argocd app diff` command
 func NewApplicationDiffCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
 	var (
-		refresh              bool
-		hardRefresh          bool
-		exitCode             bool
-		local                string
-		revision             string
-		localRepoRoot        string
-		serverSideGenerate   bool
-		localIncludes        []string
-		appNamespace         string
-		revisions            []string
-		sourcePositions      []int64
-		ignoreNormalizerOpts normalizers.IgnoreNormalizerOpts
+		refresh                  bool
+		hardRefresh              bool
+		exitCode                 bool
+		local                    string
+		revision                 string
+		localRepoRoot            string
+		serverSideGenerate       bool
+		localIncludes            []string
+		appNamespace             string
+		revisions                []string
+		sourcePositions          []int64
+		ignoreNormalizerOpts     normalizers.IgnoreNormalizerOpts
+		ignoreAnnotationOverride bool
+		showBlame                bool
+		aggregateDiff            bool
+		serverSideDiff           bool
 	)
 	shortDesc := "Perform a diff against the target and live state."
 	command := &cobra.Command{
@@ -1151,14 +1194,44 @@ func NewApplicationDiffCommand(clientOpts *argocdclient.ClientOptions) *cobra.Co
 			})
 			errors.CheckError(err)
 
+			if serverSideDiff && (local != "" || revision != "" || len(revisions) > 0 || aggregateDiff) {
+				log.Fatal("--server-side-diff cannot be used with --local, --revision, --revisions or --aggregate-diff")
+			}
+			if !serverSideDiff && local == "" && revision == "" && len(revisions) == 0 && !aggregateDiff {
+				serverSideDiff = app.Spec.SyncPolicy != nil && app.Spec.SyncPolicy.SyncOptions.HasOption("ServerSideDiff=true")
+			}
+			if serverSideDiff {
+				res, err := appIf.ServerSideDiff(ctx, &application.ApplicationServerSideDiffQuery{Name: &appName, AppNamespace: &appNs})
+				errors.CheckError(err)
+				foundDiffs := printServerSideDiff(res.Items)
+				if foundDiffs && exitCode {
+					os.Exit(1)
+				}
+				return
+			}
+
 			resources, err := appIf.ManagedResources(ctx, &application.ResourcesQuery{ApplicationName: &appName, AppNamespace: &appNs})
 			errors.CheckError(err)
 			conn, settingsIf := clientset.NewSettingsClientOrDie()
 			defer argoio.Close(conn)
 			argoSettings, err := settingsIf.Get(ctx, &settings.SettingsQuery{})
 			errors.CheckError(err)
-			diffOption := &DifferenceOption{}
-			if app.Spec.HasMultipleSources() && len(revisions) > 0 && len(sourcePositions) > 0 {
+			diffOption := &DifferenceOption{ignoreAnnotationOverride: ignoreAnnotationOverride}
+			if aggregateDiff && app.Spec.HasMultipleSources() {
+				if local != "" {
+					log.Fatal("--aggregate-diff cannot be used with --local")
+				}
+				res, err := appIf.GetManifests(ctx, &application.ApplicationManifestQuery{
+					Name:            &appName,
+					AppNamespace:    &appNs,
+					Revisions:       revisions,
+					SourcePositions: sourcePositions,
+				})
+				errors.CheckError(err)
+
+				diffOption.res = res
+				diffOption.aggregate = true
+			} else if app.Spec.HasMultipleSources() && len(revisions) > 0 && len(sourcePositions) > 0 {
 				numOfSources := int64(len(app.Spec.GetSources()))
 				for _, pos := range sourcePositions {
 					if pos <= 0 || pos > numOfSources {
@@ -1214,6 +1287,17 @@ func NewApplicationDiffCommand(clientOpts *argocdclient.ClientOptions) *cobra.Co
 			}
 			proj := getProject(c, clientOpts, ctx, app.Spec.Project)
 			foundDiffs := findandPrintDiff(ctx, app, proj.Project, resources, argoSettings, diffOption, ignoreNormalizerOpts)
+			if foundDiffs && showBlame && local == "" {
+				blame, err := appIf.GetManifestBlame(ctx, &application.ApplicationManifestQuery{
+					Name:            &appName,
+					AppNamespace:    &appNs,
+					Revision:        &revision,
+					Revisions:       revisions,
+					SourcePositions: sourcePositions,
+				})
+				errors.CheckError(err)
+				printManifestBlame(os.Stdout, app, blame)
+			}
 			if foundDiffs && exitCode {
 				os.Exit(1)
 			}
@@ -1231,19 +1315,54 @@ func NewApplicationDiffCommand(clientOpts *argocdclient.ClientOptions) *cobra.Co
 	command.Flags().StringArrayVar(&revisions, "revisions", []string{}, "Show manifests at specific revisions for source position in source-positions")
 	command.Flags().Int64SliceVar(&sourcePositions, "source-positions", []int64{}, "List of source positions. Default is empty array. Counting start at 1.")
 	command.Flags().DurationVar(&ignoreNormalizerOpts.JQExecutionTimeout, "ignore-normalizer-jq-execution-timeout", normalizers.DefaultJQExecutionTimeout, "Set ignore normalizer JQ execution timeout")
+	command.Flags().BoolVar(&ignoreAnnotationOverride, "ignore-annotation-override", false, "Show the differences of the paths listed in the argocd.argoproj.io/ignore-diff annotation of live resources")
+	command.Flags().BoolVar(&aggregateDiff, "aggregate-diff", false, "Diff the manifests of all the sources of a multi-source application as a single set of resources. A resource defined by several sources is taken from the last of them, which takes precedence. Can be used with --revisions and --source-positions")
+	command.Flags().BoolVar(&serverSideDiff, "server-side-diff", false, "Compare the live state to the result of a server-side apply of the target state in dry-run mode, which includes the mutations of the admission webhooks of the cluster. Used by default for applications with the ServerSideDiff=true sync option")
+	command.Flags().BoolVar(&showBlame, "show-blame", false, "Show the author, date and message of the commits which last modified the manifest files of the application when a diff is found. Not supported with --local")
 	return command
 }
 
+// printManifestBlame prints the commits which last modified the manifest files of the sources of the application
+func printManifestBlame(out io.Writer, app *argoappv1.Application, res *application.ApplicationManifestBlameResponse) {
+	sources := app.Spec.GetSources()
+	for i, item := range res.Items {
+		if i >= len(sources) || len(item.Files) == 0 {
+			continue
+		}
+		source := sources[i]
+		if app.Spec.HasMultipleSources() {
+			_, _ = fmt.Fprintf(out, "\n===== Blame of source %d: %s at %s =====\n", i+1, source.RepoURL, item.Revision)
+		} else {
+			_, _ = fmt.Fprintf(out, "\n===== Blame of %s at %s =====\n", source.RepoURL, item.Revision)
+		}
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "FILE\tAUTHOR\tDATE\tCOMMIT\tMESSAGE\n")
+		for _, file := range item.Files {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+				path.Join(source.Path, file.FilePath),
+				file.LastCommitAuthor,
+				time.Unix(file.LastCommitTime, 0).UTC().Format(time.RFC3339),
+				file.LastCommitSHA[:min(7, len(file.LastCommitSHA))],
+				file.LastCommitMessage)
+		}
+		_ = w.Flush()
+	}
+}
+
 // DifferenceOption struct to store diff options
 type DifferenceOption struct {
-	local           string
-	localRepoRoot   string
-	revision        string
-	cluster         *argoappv1.Cluster
-	res             *repoapiclient.ManifestResponse
-	serversideRes   *repoapiclient.ManifestResponse
-	revisions       []string
-	sourcePositions []int64
+	local                    string
+	localRepoRoot            string
+	revision                 string
+	cluster                  *argoappv1.Cluster
+	res                      *repoapiclient.ManifestResponse
+	serversideRes            *repoapiclient.ManifestResponse
+	revisions                []string
+	sourcePositions          []int64
+	ignoreAnnotationOverride bool
+	// aggregate merges the manifests of the sources of a multi-source application, along with the positions of their
+	// sources, from res
+	aggregate bool
 }
 
 // findandPrintDiff ... Prints difference between application current state and state stored in git or locally, returns boolean as true if difference is found else returns false
@@ -1252,7 +1371,13 @@ func findandPrintDiff(ctx context.Context, app *argoappv1.Application, proj *arg
 	liveObjs, err := cmdutil.LiveObjects(resources.Items)
 	errors.CheckError(err)
 	items := make([]objKeyLiveTarget, 0)
-	if diffOptions.local != "" {
+	var sourcesByKey map[kube.ResourceKey]*resourceSources
+	if diffOptions.aggregate {
+		var objs map[kube.ResourceKey]*unstructured.Unstructured
+		objs, sourcesByKey, err = aggregateSourceObjects(diffOptions.res, liveObjs, app.Spec.Destination.Namespace)
+		errors.CheckError(err)
+		items = groupObjsForDiff(resources, objs, items, argoSettings, app.InstanceName(argoSettings.ControllerNamespace), app.Spec.Destination.Namespace)
+	} else if diffOptions.local != "" {
 		localObjs := groupObjsByKey(getLocalObjects(ctx, app, proj, diffOptions.local, diffOptions.localRepoRoot, argoSettings.AppLabelKey, diffOptions.cluster.Info.ServerVersion, diffOptions.cluster.Info.APIVersions, argoSettings.KustomizeOptions, argoSettings.TrackingMethod), liveObjs, app.Spec.Destination.Namespace)
 		items = groupObjsForDiff(resources, localObjs, items, argoSettings, app.InstanceName(argoSettings.ControllerNamespace), app.Spec.Destination.Namespace)
 	} else if diffOptions.revision != "" || (diffOptions.revisions != nil && len(diffOptions.revisions) > 0) {
@@ -1306,6 +1431,7 @@ func findandPrintDiff(ctx context.Context, app *argoappv1.Application, proj *arg
 			WithTracking(argoSettings.AppLabelKey, argoSettings.TrackingMethod).
 			WithNoCache().
 			WithLogger(logutils.NewLogrusLogger(logutils.NewWithCurrentConfig())).
+			WithIgnoreAnnotationOverride(diffOptions.ignoreAnnotationOverride).
 			Build()
 		errors.CheckError(err)
 		diffRes, err := argodiff.StateDiff(item.live, item.target, diffConfig)
@@ -1313,6 +1439,9 @@ func findandPrintDiff(ctx context.Context, app *argoappv1.Application, proj *arg
 
 		if diffRes.Modified || item.target == nil || item.live == nil {
 			fmt.Printf("\n===== %s/%s %s/%s ======\n", item.key.Group, item.key.Kind, item.key.Namespace, item.key.Name)
+			if sources, ok := sourcesByKey[item.key]; ok {
+				fmt.Println(sources.String())
+			}
 			var live *unstructured.Unstructured
 			var target *unstructured.Unstructured
 			if item.target != nil && item.live != nil {
@@ -1333,6 +1462,24 @@ func findandPrintDiff(ctx context.Context, app *argoappv1.Application, proj *arg
 	return foundDiffs
 }
 
+// printServerSideDiff prints the differences of the resources of a server-side diff, returns true if a difference is
+// found
+func printServerSideDiff(items []*argoappv1.ResourceDiff) bool {
+	foundDiffs := false
+	for _, item := range items {
+		if !item.Modified || item.Hook || item.Kind == kube.SecretKind && item.Group == "" {
+			continue
+		}
+		var live, predicted *unstructured.Unstructured
+		errors.CheckError(json.Unmarshal([]byte(item.NormalizedLiveState), &live))
+		errors.CheckError(json.Unmarshal([]byte(item.PredictedLiveState), &predicted))
+		fmt.Printf("\n===== %s/%s %s/%s ======\n", item.Group, item.Kind, item.Namespace, item.Name)
+		foundDiffs = true
+		_ = cli.PrintDiff(item.Name, live, predicted)
+	}
+	return foundDiffs
+}
+
 func groupObjsForDiff(resources *application.ManagedResourcesResponse, objs map[kube.ResourceKey]*unstructured.Unstructured, items []objKeyLiveTarget, argoSettings *settings.Settings, appName, namespace string) []objKeyLiveTarget {
 	resourceTracking := argo.NewResourceTracking()
 	for _, res := range resources.Items {
@@ -1376,6 +1523,7 @@ func NewApplicationDeleteCommand(clientOpts *argocdclient.ClientOptions) *cobra.
 		selector          string
 		wait              bool
 		appNamespace      string
+		verbose           bool
 	)
 	command := &cobra.Command{
 		Use:   "delete APPNAME",
@@ -1391,7 +1539,10 @@ func NewApplicationDeleteCommand(clientOpts *argocdclient.ClientOptions) *cobra.
   argocd app delete -l app.kubernetes.io/instance!=my-app
   argocd app delete -l app.kubernetes.io/instance
   argocd app delete -l '!app.kubernetes.io/instance'
-  argocd app delete -l 'app.kubernetes.io/instance notin (my-app,other-app)'`,
+  argocd app delete -l 'app.kubernetes.io/instance notin (my-app,other-app)'
+
+  # Print the order in which the resources of an app are deleted before deleting it
+  argocd app delete my-app --verbose`,
 		Run: func(c *cobra.Command, args []string) {
 			ctx := c.Context()
 
@@ -1429,6 +1580,13 @@ func NewApplicationDeleteCommand(clientOpts *argocdclient.ClientOptions) *cobra.
 				if c.Flag("propagation-policy").Changed {
 					appDeleteReq.PropagationPolicy = &propagationPolicy
 				}
+				if cascade && verbose {
+					app, err := appIf.Get(ctx, &application.ApplicationQuery{Name: &appName, AppNamespace: &appNs})
+					errors.CheckError(err)
+					resources, err := appIf.ManagedResources(ctx, &application.ResourcesQuery{ApplicationName: &appName, AppNamespace: &appNs})
+					errors.CheckError(err)
+					printDeletionOrder(appFullName, app.Spec.SyncPolicy, resources.Items)
+				}
 				if cascade && isTerminal && !noPrompt {
 					var lowercaseAnswer string
 					if numOfApps == 1 {
@@ -1471,9 +1629,41 @@ func NewApplicationDeleteCommand(clientOpts *argocdclient.ClientOptions) *cobra.
 	command.Flags().StringVarP(&selector, "selector", "l", "", "Delete all apps with matching label. Supports '=', '==', '!=', in, notin, exists & not exists. Matching apps must satisfy all of the specified label constraints.")
 	command.Flags().BoolVar(&wait, "wait", false, "Wait until deletion of the application(s) completes")
 	command.Flags().StringVarP(&appNamespace, "app-namespace", "N", "", "Namespace where the application will be deleted from")
+	command.Flags().BoolVar(&verbose, "verbose", false, "Print the order in which the resources of the application are deleted during a cascaded deletion")
 	return command
 }
 
+// printDeletionOrder prints the steps in which the application controller deletes the live resources of an application
+// during a cascaded deletion, along with the resources each resource depends on
+func printDeletionOrder(appName string, syncPolicy *argoappv1.SyncPolicy, resources []*argoappv1.ResourceDiff) {
+	var objs []*unstructured.Unstructured
+	for _, res := range resources {
+		if res.Hook {
+			continue
+		}
+		live := &unstructured.Unstructured{}
+		err := json.Unmarshal([]byte(res.NormalizedLiveState), &live)
+		errors.CheckError(err)
+		if live != nil {
+			objs = append(objs, live)
+		}
+	}
+
+	fmt.Printf("Deletion order of '%s' resources:\n", appName)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "STEP\tWAVE\tGROUP\tKIND\tNAMESPACE\tNAME\tDEPENDS ON\n")
+	for i, step := range controller.DeletionOrder(objs, syncPolicy) {
+		for j, obj := range step.Resources {
+			dependencies := make([]string, len(step.DependsOn[j]))
+			for k, dependency := range step.DependsOn[j] {
+				dependencies[k] = dependency.GetKind() + "/" + dependency.GetName()
+			}
+			_, _ = fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\t%s\n", i+1, step.Wave, obj.GroupVersionKind().Group, obj.GetKind(), obj.GetNamespace(), obj.GetName(), strings.Join(dependencies, ","))
+		}
+	}
+	_ = w.Flush()
+}
+
 func checkForDeleteEvent(ctx context.Context, acdClient argocdclient.Client, appFullName string) {
 	appEventCh := acdClient.WatchApplicationWithRetry(ctx, appFullName, "")
 	for appEvent := range appEventCh {
@@ -1679,6 +1869,36 @@ func parseSelectedResources(resources []string) ([]*argoappv1.SyncOperationResou
 	return selectedResources, nil
 }
 
+// destination is server=SERVER, name=NAME and namespace=NAMESPACE separated by commas, at least the server or the name
+// being set
+func parseSelectedDestinations(destinations []string) ([]*argoappv1.ApplicationDestination, error) {
+	var selectedDestinations []*argoappv1.ApplicationDestination
+	for _, destination := range destinations {
+		selected := &argoappv1.ApplicationDestination{}
+		for _, field := range strings.Split(destination, ",") {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				return nil, fmt.Errorf("Destination should have server=SERVER, name=NAME or namespace=NAMESPACE fields, but instead got: %s", destination)
+			}
+			switch key {
+			case "server":
+				selected.Server = value
+			case "name":
+				selected.Name = value
+			case "namespace":
+				selected.Namespace = value
+			default:
+				return nil, fmt.Errorf("Unknown destination field '%s' in: %s", key, destination)
+			}
+		}
+		if selected.Server == "" && selected.Name == "" {
+			return nil, fmt.Errorf("Destination should have a server or a name, but instead got: %s", destination)
+		}
+		selectedDestinations = append(selectedDestinations, selected)
+	}
+	return selectedDestinations, nil
+}
+
 func getWatchOpts(watch watchOpts) watchOpts {
 	// if no opts are defined should wait for sync,health,operation
 	if (watch == watchOpts{}) {
@@ -1828,6 +2048,9 @@ func NewApplicationSyncCommand(clientOpts *argocdclient.ClientOptions) *cobra.Co
 		output                  string
 		appNamespace            string
 		ignoreNormalizerOpts    normalizers.IgnoreNormalizerOpts
+		overrideSyncWindow      bool
+		overrideReason          string
+		destinations            []string
 	)
 	command := &cobra.Command{
 		Use:   "sync [APPNAME... | -l selector | --project project-name]",
@@ -1856,7 +2079,14 @@ func NewApplicationSyncCommand(clientOpts *argocdclient.ClientOptions) *cobra.Co
   argocd app sync my-app --resource apps:Deployment:my-service --resource :Service:my-service
   argocd app sync my-app --resource '!*:Service:*'
   # Specify namespace if the application has resources with the same name in different namespaces
-  argocd app sync my-app --resource argoproj.io:Rollout:my-namespace/my-rollout`,
+  argocd app sync my-app --resource argoproj.io:Rollout:my-namespace/my-rollout
+
+  # Override the sync windows blocking the sync of an app, e.g. to deploy an emergency fix
+  argocd app sync my-app --override-sync-window --override-reason "security patch"
+
+  # Sync specific destinations of an app deployed to multiple destinations
+  argocd app sync my-app --destination server=https://cluster-1.example.com
+  argocd app sync my-app --destination name=cluster-1 --destination name=cluster-2,namespace=my-namespace`,
 		Run: func(c *cobra.Command, args []string) {
 			ctx := c.Context()
 			if len(args) == 0 && selector == "" && len(projects) == 0 {
@@ -1881,6 +2111,10 @@ func NewApplicationSyncCommand(clientOpts *argocdclient.ClientOptions) *cobra.Co
 				}
 			}
 
+			if overrideSyncWindow && overrideReason == "" {
+				log.Fatal("--override-reason is required when using --override-sync-window")
+			}
+
 			acdClient := headless.NewClientOrDie(clientOpts, c)
 			conn, appIf := acdClient.NewApplicationClientOrDie()
 			defer argoio.Close(conn)
@@ -1959,6 +2193,9 @@ func NewApplicationSyncCommand(clientOpts *argocdclient.ClientOptions) *cobra.Co
 				selectedResources, err := parseSelectedResources(resources)
 				errors.CheckError(err)
 
+				selectedDestinations, err := parseSelectedDestinations(destinations)
+				errors.CheckError(err)
+
 				var localObjsStrings []string
 				diffOption := &DifferenceOption{}
 
@@ -2046,6 +2283,11 @@ func NewApplicationSyncCommand(clientOpts *argocdclient.ClientOptions) *cobra.Co
 					SyncOptions:     syncOptionsFactory(),
 					Revisions:       revisions,
 					SourcePositions: sourcePositions,
+					Destinations:    selectedDestinations,
+				}
+				if overrideSyncWindow {
+					syncReq.OverrideSyncWindow = &overrideSyncWindow
+					syncReq.OverrideReason = &overrideReason
 				}
 
 				switch strategy {
@@ -2146,6 +2388,9 @@ func NewApplicationSyncCommand(clientOpts *argocdclient.ClientOptions) *cobra.Co
 	command.Flags().DurationVar(&ignoreNormalizerOpts.JQExecutionTimeout, "ignore-normalizer-jq-execution-timeout", normalizers.DefaultJQExecutionTimeout, "Set ignore normalizer JQ execution timeout")
 	command.Flags().StringArrayVar(&revisions, "revisions", []string{}, "Show manifests at specific revisions for source position in source-positions")
 	command.Flags().Int64SliceVar(&sourcePositions, "source-positions", []int64{}, "List of source positions. Default is empty array. Counting start at 1.")
+	command.Flags().BoolVar(&overrideSyncWindow, "override-sync-window", false, "Override the sync windows blocking the sync if they allow it. Requires the permission to override syncs and --override-reason")
+	command.Flags().StringVar(&overrideReason, "override-reason", "", "Reason for overriding the sync windows, recorded for auditing")
+	command.Flags().StringArrayVar(&destinations, "destination", []string{}, "Sync only specific destinations of an application deployed to multiple destinations as server=SERVER or name=NAME, optionally followed by ,namespace=NAMESPACE. This option may be specified repeatedly")
 	return command
 }
 
@@ -2347,6 +2592,23 @@ func resourceParentChild(ctx context.Context, acdClient argocdclient.Client, app
 
 const waitFormatString = "%s\t%5s\t%10s\t%10s\t%20s\t%8s\t%7s\t%10s\t%s\n"
 
+// syncProgressBarWidth is the number of characters of the progress bar of the sync operations
+const syncProgressBarWidth = 30
+
+// formatSyncProgress returns a progress bar of the sync operation, followed by the time remaining until the operation
+// is expected to have applied all its resources
+func formatSyncProgress(progress *argoappv1.SyncOperationProgress, now time.Time) string {
+	filled := 0
+	if progress.ResourcesTotal > 0 {
+		filled = int(min(progress.ResourcesApplied, progress.ResourcesTotal) * syncProgressBarWidth / progress.ResourcesTotal)
+	}
+	bar := fmt.Sprintf("[%s%s] %d/%d resources applied", strings.Repeat("#", filled), strings.Repeat(".", syncProgressBarWidth-filled), progress.ResourcesApplied, progress.ResourcesTotal)
+	if progress.EstimatedCompletionTime != nil {
+		bar += fmt.Sprintf(", estimated completion in %v", max(progress.EstimatedCompletionTime.Sub(now).Round(time.Second), 0))
+	}
+	return bar
+}
+
 // waitOnApplicationStatus watches an application and blocks until either the desired watch conditions
 // are fulfilled or we reach the timeout. Returns the app once desired conditions have been filled.
 // Additionally return the operationState at time of fulfilment (which may be different than returned app).
@@ -2447,6 +2709,7 @@ func waitOnApplicationStatus(ctx context.Context, acdClient argocdclient.Client,
 	}
 
 	prevStates := make(map[string]*resourceState)
+	var prevProgress *argoappv1.SyncOperationProgress
 	conn, appClient := acdClient.NewApplicationClientOrDie()
 	defer argoio.Close(conn)
 	app, err := appClient.Get(ctx, &application.ApplicationQuery{
@@ -2533,6 +2796,14 @@ func waitOnApplicationStatus(ctx context.Context, acdClient argocdclient.Client,
 			}
 		}
 		_ = w.Flush()
+
+		// display the progress of the running sync operation each time the controller reports it
+		if printSummary && watch.operation && app.Status.OperationState != nil && app.Status.OperationState.FinishedAt == nil {
+			if progress := app.Status.OperationState.Progress; progress != nil && !reflect.DeepEqual(progress, prevProgress) {
+				fmt.Println(formatSyncProgress(progress, time.Now()))
+				prevProgress = progress.DeepCopy()
+			}
+		}
 	}
 	_ = printFinalStatus(app)
 	return nil, finalOperationState, fmt.Errorf("timed out (%ds) waiting for app %q match desired state", timeout, appName)
@@ -2673,6 +2944,146 @@ func NewApplicationHistoryCommand(clientOpts *argocdclient.ClientOptions) *cobra
 	return command
 }
 
+// NewApplicationConditionsCommand returns a new instance of an `argocd app conditions` command
+func NewApplicationConditionsCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var (
+		history      bool
+		appNamespace string
+	)
+	command := &cobra.Command{
+		Use:   "conditions APPNAME",
+		Short: "Show application conditions",
+		Example: `  # Show the current conditions of an application
+  argocd app conditions my-app
+
+  # Show the last transitions of the health, sync status and conditions of an application
+  argocd app conditions my-app --history`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+
+			if len(args) != 1 {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			conn, appIf := headless.NewClientOrDie(clientOpts, c).NewApplicationClientOrDie()
+			defer argoio.Close(conn)
+			appName, appNs := argo.ParseFromQualifiedName(args[0], appNamespace)
+			app, err := appIf.Get(ctx, &application.ApplicationQuery{
+				Name:         &appName,
+				AppNamespace: &appNs,
+			})
+			errors.CheckError(err)
+
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			if history {
+				printAppConditionHistory(w, app)
+			} else {
+				printAppConditions(w, app)
+			}
+			_ = w.Flush()
+		},
+	}
+	command.Flags().StringVarP(&appNamespace, "app-namespace", "N", "", "Only show application conditions in namespace")
+	command.Flags().BoolVar(&history, "history", false, "Show the last transitions of the health, sync status and conditions of the application, most recent first")
+	return command
+}
+
+// NewApplicationCheckDeprecationsCommand returns a new instance of an `argocd app check-deprecations` command
+func NewApplicationCheckDeprecationsCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var (
+		appNamespace string
+		output       string
+	)
+	command := &cobra.Command{
+		Use:   "check-deprecations APPNAME",
+		Short: "List the resources of an application which use deprecated Kubernetes API versions",
+		Long:  "List the target resources of an application which use API versions deprecated in Kubernetes, along with the API versions to use instead. The API versions are compared to the preferred API versions of the destination cluster.",
+		Example: `  # List the resources of an application which use deprecated API versions
+  argocd app check-deprecations my-app
+
+  # List them as JSON
+  argocd app check-deprecations my-app -o json`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+
+			if len(args) != 1 {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			conn, appIf := headless.NewClientOrDie(clientOpts, c).NewApplicationClientOrDie()
+			defer argoio.Close(conn)
+			appName, appNs := argo.ParseFromQualifiedName(args[0], appNamespace)
+			res, err := appIf.CheckDeprecations(ctx, &application.DeprecatedAPIsQuery{
+				Name:         &appName,
+				AppNamespace: &appNs,
+			})
+			errors.CheckError(err)
+			switch output {
+			case "json", "yaml":
+				errors.CheckError(PrintResourceList(res.Items, output, false))
+			case "":
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				printDeprecatedAPIs(w, res.Items)
+				_ = w.Flush()
+			default:
+				errors.CheckError(fmt.Errorf("unknown output format: %s", output))
+			}
+		},
+	}
+	command.Flags().StringVarP(&appNamespace, "app-namespace", "N", "", "Namespace of the application")
+	command.Flags().StringVarP(&output, "output", "o", "", "Output format. One of: json|yaml")
+	return command
+}
+
+// printDeprecatedAPIs prints the resources which use deprecated API versions, and the API versions to use instead
+func printDeprecatedAPIs(w io.Writer, items []*application.DeprecatedAPIUsage) {
+	_, _ = fmt.Fprintf(w, "KIND\tNAMESPACE\tNAME\tAPI VERSION\tREMOVED IN\tSERVED\tREPLACEMENT\n")
+	for _, item := range items {
+		apiVersion := schema.GroupVersion{Group: item.GetGroup(), Version: item.GetVersion()}.String()
+		replacement := item.GetReplacementAPIVersion()
+		if replacement == "" {
+			replacement = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%t\t%s\n", item.GetKind(), item.GetNamespace(), item.GetName(), apiVersion, item.GetRemovedInVersion(), item.GetServed(), replacement)
+	}
+}
+
+// NewApplicationBadgeCommand returns a new instance of an `argocd app badge` command
+func NewApplicationBadgeCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var appNamespace string
+	command := &cobra.Command{
+		Use:   "badge APPNAME",
+		Short: "Print the URL of the status badge of an application",
+		Example: `  # Print the URL of the status badge of an application
+  argocd app badge my-app
+
+  # Embed the status badge of an application in a Markdown README
+  echo "![my-app]($(argocd app badge my-app))" >> README.md`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+
+			if len(args) != 1 {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			appName, appNs := argo.ParseFromQualifiedName(args[0], appNamespace)
+			acdClient := headless.NewClientOrDie(clientOpts, c)
+			fmt.Println(appBadgeURL(serverURL(ctx, acdClient), appName, appNs))
+		},
+	}
+	command.Flags().StringVarP(&appNamespace, "app-namespace", "N", "", "Namespace of the application")
+	return command
+}
+
+// printAppConditionHistory prints the condition history of an application, most recent first
+func printAppConditionHistory(w io.Writer, app *argoappv1.Application) {
+	_, _ = fmt.Fprintf(w, "TIME\tTYPE\tSTATUS\tMESSAGE\n")
+	for i := len(app.Status.ConditionHistory) - 1; i >= 0; i-- {
+		item := app.Status.ConditionHistory[i]
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.LastTransitionTime, item.Type, item.Status, item.Message)
+	}
+}
+
 func findRevisionHistory(application *argoappv1.Application, historyId int64) (*argoappv1.RevisionHistory, error) {
 	// in case if history id not passed and need fetch previous history revision
 	if historyId == -1 {
@@ -2697,6 +3108,8 @@ func NewApplicationRollbackCommand(clientOpts *argocdclient.ClientOptions) *cobr
 		timeout      uint
 		output       string
 		appNamespace string
+		fromSnapshot bool
+		syncID       string
 	)
 	command := &cobra.Command{
 		Use:   "rollback APPNAME [ID]",
@@ -2723,16 +3136,43 @@ func NewApplicationRollbackCommand(clientOpts *argocdclient.ClientOptions) *cobr
 			})
 			errors.CheckError(err)
 
-			depInfo, err := findRevisionHistory(app, int64(depID))
-			errors.CheckError(err)
+			if fromSnapshot {
+				if len(args) > 1 {
+					log.Fatal("A history ID cannot be given with --from-snapshot")
+				}
+				if syncID == "" {
+					if app.Status.OperationState == nil || app.Status.OperationState.SyncResult == nil || app.Status.OperationState.SyncResult.SyncID == "" {
+						log.Fatalf("No snapshot was taken before the last sync of application %s", app.QualifiedName())
+					}
+					syncID = app.Status.OperationState.SyncResult.SyncID
+				}
+				snapshot, err := appIf.GetPreSyncSnapshot(ctx, &application.PreSyncSnapshotRequest{
+					Name:         &appName,
+					AppNamespace: &appNs,
+					SyncId:       &syncID,
+				})
+				errors.CheckError(err)
+				manifests, err := preSyncSnapshotManifests(snapshot)
+				errors.CheckError(err)
+				_, err = appIf.Sync(ctx, &application.ApplicationSyncRequest{
+					Name:         &appName,
+					AppNamespace: &appNs,
+					Manifests:    manifests,
+					Prune:        ptr.To(prune),
+				})
+				errors.CheckError(err)
+			} else {
+				depInfo, err := findRevisionHistory(app, int64(depID))
+				errors.CheckError(err)
 
-			_, err = appIf.Rollback(ctx, &application.ApplicationRollbackRequest{
-				Name:         &appName,
-				AppNamespace: &appNs,
-				Id:           ptr.To(depInfo.ID),
-				Prune:        ptr.To(prune),
-			})
-			errors.CheckError(err)
+				_, err = appIf.Rollback(ctx, &application.ApplicationRollbackRequest{
+					Name:         &appName,
+					AppNamespace: &appNs,
+					Id:           ptr.To(depInfo.ID),
+					Prune:        ptr.To(prune),
+				})
+				errors.CheckError(err)
+			}
 
 			_, _, err = waitOnApplicationStatus(ctx, acdClient, app.QualifiedName(), timeout, watchOpts{
 				operation: true,
@@ -2744,9 +3184,33 @@ func NewApplicationRollbackCommand(clientOpts *argocdclient.ClientOptions) *cobr
 	command.Flags().UintVar(&timeout, "timeout", defaultCheckTimeoutSeconds, "Time out after this many seconds")
 	command.Flags().StringVarP(&output, "output", "o", "wide", "Output format. One of: json|yaml|wide|tree|tree=detailed")
 	command.Flags().StringVarP(&appNamespace, "app-namespace", "N", "", "Rollback application in namespace")
+	command.Flags().BoolVar(&fromSnapshot, "from-snapshot", false, "Rollback to the live state of the resources captured before a sync instead of a previous revision")
+	command.Flags().StringVar(&syncID, "sync-id", "", "ID of the sync whose snapshot to rollback to with --from-snapshot, defaults to the last sync")
 	return command
 }
 
+// preSyncSnapshotManifests returns the live manifests of a pre-sync snapshot without the fields set by the Kubernetes
+// API server, so that they can be synced again
+func preSyncSnapshotManifests(snapshot *application.ResourceSnapshot) ([]string, error) {
+	manifests := make([]string, 0, len(snapshot.Resources))
+	for _, entry := range snapshot.Resources {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON([]byte(entry.GetLiveManifest())); err != nil {
+			return nil, fmt.Errorf("error unmarshaling the snapshot of %s %s: %w", entry.ResourceRef.Kind, entry.ResourceRef.Name, err)
+		}
+		unstructured.RemoveNestedField(obj.Object, "status")
+		for _, field := range []string{"uid", "resourceVersion", "generation", "creationTimestamp", "managedFields", "selfLink"} {
+			unstructured.RemoveNestedField(obj.Object, "metadata", field)
+		}
+		data, err := json.Marshal(obj.Object)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling the snapshot of %s %s: %w", entry.ResourceRef.Kind, entry.ResourceRef.Name, err)
+		}
+		manifests = append(manifests, string(data))
+	}
+	return manifests, nil
+}
+
 const (
 	printOpFmtStr              = "%-20s%s\n"
 	defaultCheckTimeoutSeconds = 0
@@ -2765,6 +3229,9 @@ func printOperationResult(opState *argoappv1.OperationState) {
 		}
 	}
 	fmt.Printf(printOpFmtStr, "Phase:", opState.Phase)
+	if opState.ErrorCode != "" {
+		fmt.Printf(printOpFmtStr, "Error Code:", opState.ErrorCode)
+	}
 	fmt.Printf(printOpFmtStr, "Start:", opState.StartedAt)
 	fmt.Printf(printOpFmtStr, "Finished:", opState.FinishedAt)
 	var duration time.Duration
@@ -2777,6 +3244,15 @@ func printOperationResult(opState *argoappv1.OperationState) {
 	if opState.Message != "" {
 		fmt.Printf(printOpFmtStr, "Message:", opState.Message)
 	}
+	if opState.SyncResult != nil && len(opState.SyncResult.Destinations) > 0 {
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "SERVER\tNAME\tNAMESPACE\tPHASE\tMESSAGE\n")
+		for _, dest := range opState.SyncResult.Destinations {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", dest.Destination.Server, dest.Destination.Name, dest.Destination.Namespace, dest.Phase, dest.Message)
+		}
+		_ = w.Flush()
+	}
 }
 
 // NewApplicationManifestsCommand returns a new instance of an `argocd app manifests` command
@@ -3171,3 +3647,33 @@ func NewApplicationRemoveSourceCommand(clientOpts *argocdclient.ClientOptions) *
 	command.Flags().IntVar(&sourcePosition, "source-position", -1, "Position of the source from the list of sources of the app. Counting starts at 1.")
 	return command
 }
+
+// NewApplicationValidateCommand returns a new instance of an `argocd app validate` command
+func NewApplicationValidateCommand() *cobra.Command {
+	var fileURL string
+	command := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate application manifests against the Application schema without contacting the server",
+		Example: `  # Validate the applications of a file
+  argocd app validate --file app.yaml`,
+		Run: func(c *cobra.Command, args []string) {
+			if fileURL == "" {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			data, err := os.ReadFile(fileURL)
+			errors.CheckError(err)
+			violations, err := cmdutil.ValidateApps(data)
+			errors.CheckError(err)
+			if len(violations) > 0 {
+				for _, violation := range violations {
+					fmt.Fprintln(os.Stderr, violation)
+				}
+				os.Exit(1)
+			}
+			fmt.Printf("%s is valid\n", fileURL)
+		},
+	}
+	command.Flags().StringVarP(&fileURL, "file", "f", "", "Filename or path of the application manifests to validate")
+	return command
+}
@@ -0,0 +1,169 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/argoproj/argo-cd/v2/cmd/argocd/commands/headless"
+	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	projectpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/project"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+	"github.com/argoproj/argo-cd/v2/util/artifact"
+	"github.com/argoproj/argo-cd/v2/util/errors"
+	argoio "github.com/argoproj/argo-cd/v2/util/io"
+)
+
+// artifactRegistryOptions are the options of the commands accessing the registry sync result artifacts are stored in
+type artifactRegistryOptions struct {
+	appNamespace string
+	username     string
+	password     string
+}
+
+func (o *artifactRegistryOptions) addFlags(command *cobra.Command) {
+	command.Flags().StringVarP(&o.appNamespace, "app-namespace", "N", "", "Namespace of the application")
+	command.Flags().StringVar(&o.username, "registry-username", "", "Username used to authenticate against the registry the artifacts are stored in")
+	command.Flags().StringVar(&o.password, "registry-password", "", "Password used to authenticate against the registry the artifacts are stored in")
+}
+
+// getArtifactRepository returns the qualified name of the application, and the repository its sync result artifacts
+// are stored in, as configured in the artifact storage of its project
+func getArtifactRepository(ctx context.Context, c *cobra.Command, clientOpts *argocdclient.ClientOptions, appArg string, opts artifactRegistryOptions) (string, artifact.Repository) {
+	acdClient := headless.NewClientOrDie(clientOpts, c)
+	conn, appIf := acdClient.NewApplicationClientOrDie()
+	defer argoio.Close(conn)
+	appName, appNs := argo.ParseFromQualifiedName(appArg, opts.appNamespace)
+	app, err := appIf.Get(ctx, &applicationpkg.ApplicationQuery{Name: &appName, AppNamespace: &appNs})
+	errors.CheckError(err)
+
+	projConn, projIf := acdClient.NewProjectClientOrDie()
+	defer argoio.Close(projConn)
+	proj, err := projIf.Get(ctx, &projectpkg.ProjectQuery{Name: app.Spec.Project})
+	errors.CheckError(err)
+	storage := proj.Spec.ArtifactStorage.GetOCI()
+	if storage == nil {
+		errors.CheckError(fmt.Errorf("project %s does not store sync result artifacts in an OCI registry", proj.Name))
+	}
+	return app.QualifiedName(), artifact.Repository{
+		Registry: storage.Registry,
+		Repo:     storage.Repo,
+		Username: opts.username,
+		Password: opts.password,
+	}
+}
+
+// NewApplicationArtifactsCommand returns a new instance of an `argocd app artifacts` command
+func NewApplicationArtifactsCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "artifacts",
+		Short: "Manage the manifests applied by the syncs of an application, stored in an OCI registry",
+		Run: func(c *cobra.Command, args []string) {
+			c.HelpFunc()(c, args)
+			os.Exit(1)
+		},
+	}
+	command.AddCommand(NewApplicationArtifactsListCommand(clientOpts))
+	command.AddCommand(NewApplicationArtifactsPullCommand(clientOpts))
+	return command
+}
+
+// NewApplicationArtifactsListCommand returns a new instance of an `argocd app artifacts list` command
+func NewApplicationArtifactsListCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var (
+		opts   artifactRegistryOptions
+		output string
+	)
+	command := &cobra.Command{
+		Use:   "list APPNAME",
+		Short: "List the sync result artifacts of an application",
+		Long:  "List the sync result artifacts of an application, stored in the OCI registry configured in the artifact storage of its project. The registry is accessed directly, using the given registry credentials.",
+		Example: `  # List the sync result artifacts of an application
+  argocd app artifacts list my-app
+
+  # List the sync result artifacts of an application stored in a private registry
+  argocd app artifacts list my-app --registry-username my-user --registry-password my-token`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+
+			if len(args) != 1 {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			appName, repository := getArtifactRepository(ctx, c, clientOpts, args[0], opts)
+			artifacts, err := artifact.List(ctx, repository, appName)
+			errors.CheckError(err)
+
+			switch output {
+			case "json", "yaml":
+				errors.CheckError(PrintResourceList(artifacts, output, false))
+			case "":
+				printArtifacts(os.Stdout, artifacts)
+			default:
+				errors.CheckError(fmt.Errorf("unknown output format: %s", output))
+			}
+		},
+	}
+	opts.addFlags(command)
+	command.Flags().StringVarP(&output, "output", "o", "", "Output format. One of: json|yaml")
+	return command
+}
+
+// printArtifacts prints the sync result artifacts in a table, most recent first
+func printArtifacts(out io.Writer, artifacts []artifact.Artifact) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "TAG\tSYNC TIME\tREVISION\tDIGEST\n")
+	for i := len(artifacts) - 1; i >= 0; i-- {
+		item := artifacts[i]
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Tag, item.SyncTime.Format(time.RFC3339), item.Revision, item.Digest)
+	}
+	_ = w.Flush()
+}
+
+// NewApplicationArtifactsPullCommand returns a new instance of an `argocd app artifacts pull` command
+func NewApplicationArtifactsPullCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var (
+		opts artifactRegistryOptions
+		tag  string
+	)
+	command := &cobra.Command{
+		Use:   "pull APPNAME",
+		Short: "Print the manifests applied by a sync of an application",
+		Long:  "Print the manifests applied by a sync of an application, stored in the OCI registry configured in the artifact storage of its project. The registry is accessed directly, using the given registry credentials.",
+		Example: `  # Print the manifests applied by the last sync of an application
+  argocd app artifacts pull my-app
+
+  # Print the manifests applied by the sync stored in the given artifact
+  argocd app artifacts pull my-app --tag argocd_my-app-20240301T120000Z`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+
+			if len(args) != 1 {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			appName, repository := getArtifactRepository(ctx, c, clientOpts, args[0], opts)
+			if tag == "" {
+				artifacts, err := artifact.List(ctx, repository, appName)
+				errors.CheckError(err)
+				if len(artifacts) == 0 {
+					errors.CheckError(fmt.Errorf("no sync result artifacts of application %s found in %s/%s", appName, repository.Registry, repository.Repo))
+				}
+				tag = artifacts[len(artifacts)-1].Tag
+			}
+			manifests, err := artifact.Pull(ctx, repository, tag)
+			errors.CheckError(err)
+			_, err = os.Stdout.Write(manifests)
+			errors.CheckError(err)
+		},
+	}
+	opts.addFlags(command)
+	command.Flags().StringVar(&tag, "tag", "", "Tag of the artifact to pull, defaults to the artifact of the last sync")
+	return command
+}
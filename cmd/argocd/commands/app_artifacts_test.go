@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/argoproj/argo-cd/v2/util/artifact"
+)
+
+func TestPrintArtifacts(t *testing.T) {
+	var out bytes.Buffer
+	printArtifacts(&out, []artifact.Artifact{
+		{Tag: "argocd_my-app-20240301T120000Z", Digest: "sha256:abc", Revision: "abc123", SyncTime: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{Tag: "argocd_my-app-20240301T130000Z", Digest: "sha256:def", Revision: "def456", SyncTime: time.Date(2024, 3, 1, 13, 0, 0, 0, time.UTC)},
+	})
+	assert.Equal(t, `TAG                             SYNC TIME             REVISION  DIGEST
+argocd_my-app-20240301T130000Z  2024-03-01T13:00:00Z  def456    sha256:def
+argocd_my-app-20240301T120000Z  2024-03-01T12:00:00Z  abc123    sha256:abc
+`, out.String())
+}
@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/argoproj/gitops-engine/pkg/sync/hook"
+	"github.com/argoproj/gitops-engine/pkg/sync/ignore"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	repoapiclient "github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+)
+
+// resourceSources are the sources of a multi-source application which define a resource
+type resourceSources struct {
+	// positions are the positions of the sources, counting from 1, in ascending order
+	positions []int64
+	// precedence is the position of the source whose manifest of the resource is diffed
+	precedence int64
+}
+
+func (s *resourceSources) String() string {
+	if len(s.positions) == 1 {
+		return fmt.Sprintf("Defined by source %d", s.positions[0])
+	}
+	positions := make([]string, len(s.positions))
+	for i, pos := range s.positions {
+		positions[i] = fmt.Sprint(pos)
+	}
+	return fmt.Sprintf("Defined by sources %s, the manifest of source %d takes precedence", strings.Join(positions, ", "), s.precedence)
+}
+
+// aggregateSourceObjects merges the manifests generated by the sources of a multi-source application into a single
+// set of objects to diff. A resource defined by several sources is taken from the source with the greatest precedence,
+// i.e. the last one, as done by the application controller. Returns the objects by key, along with the sources
+// defining each of them.
+func aggregateSourceObjects(res *repoapiclient.ManifestResponse, liveObjs []*unstructured.Unstructured, appNamespace string) (map[kube.ResourceKey]*unstructured.Unstructured, map[kube.ResourceKey]*resourceSources, error) {
+	if len(res.SourcePositions) != len(res.Manifests) {
+		return nil, nil, fmt.Errorf("the API server did not report the sources of the manifests, it may be too old to aggregate the diffs of the sources")
+	}
+	infoProvider := newResourceInfoProvider(liveObjs)
+	objByKey := make(map[kube.ResourceKey]*unstructured.Unstructured)
+	sourcesByKey := make(map[kube.ResourceKey]*resourceSources)
+	for i, mfst := range res.Manifests {
+		pos := res.SourcePositions[i]
+		obj, err := argoappv1.UnmarshalToUnstructured(mfst)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error unmarshaling manifest of source %d: %w", pos, err)
+		}
+		if obj == nil || hook.IsHook(obj) || ignore.Ignore(obj) {
+			continue
+		}
+		if !kube.IsNamespacedOrUnknown(infoProvider, obj.GroupVersionKind().GroupKind()) {
+			obj.SetNamespace("")
+		} else if obj.GetNamespace() == "" {
+			obj.SetNamespace(appNamespace)
+		}
+		key := kube.GetResourceKey(obj)
+		sources, ok := sourcesByKey[key]
+		if !ok {
+			sources = &resourceSources{}
+			sourcesByKey[key] = sources
+		}
+		if !slices.Contains(sources.positions, pos) {
+			sources.positions = append(sources.positions, pos)
+			slices.Sort(sources.positions)
+		}
+		if pos >= sources.precedence {
+			sources.precedence = pos
+			objByKey[key] = obj
+		}
+	}
+	return objByKey, sourcesByKey, nil
+}
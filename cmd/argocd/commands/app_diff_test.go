@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	repoapiclient "github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+)
+
+func configMapManifest(name, namespace, value string) string {
+	ns := ""
+	if namespace != "" {
+		ns = `,"namespace":"` + namespace + `"`
+	}
+	return `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"` + name + `"` + ns + `},"data":{"key":"` + value + `"}}`
+}
+
+func TestAggregateSourceObjects(t *testing.T) {
+	liveCM := &unstructured.Unstructured{}
+	liveCM.SetAPIVersion("v1")
+	liveCM.SetKind("ConfigMap")
+	liveCM.SetName("shared")
+	liveCM.SetNamespace("default")
+	liveObjs := []*unstructured.Unstructured{liveCM}
+	sharedKey := kube.NewResourceKey("", "ConfigMap", "default", "shared")
+
+	t.Run("ConflictResolvedBySourcePrecedence", func(t *testing.T) {
+		objs, sources, err := aggregateSourceObjects(&repoapiclient.ManifestResponse{
+			Manifests: []string{
+				configMapManifest("shared", "default", "from-source-1"),
+				configMapManifest("only-in-source-1", "", "value"),
+				configMapManifest("shared", "", "from-source-2"),
+				configMapManifest("shared", "default", "from-source-3"),
+			},
+			SourcePositions: []int64{1, 1, 2, 3},
+		}, liveObjs, "default")
+		require.NoError(t, err)
+		require.Len(t, objs, 2)
+
+		// the manifest of the last source defining the resource is diffed, whether its namespace is explicit or not
+		value, _, err := unstructured.NestedString(objs[sharedKey].Object, "data", "key")
+		require.NoError(t, err)
+		assert.Equal(t, "from-source-3", value)
+		assert.Equal(t, []int64{1, 2, 3}, sources[sharedKey].positions)
+		assert.Equal(t, int64(3), sources[sharedKey].precedence)
+		assert.Equal(t, "Defined by sources 1, 2, 3, the manifest of source 3 takes precedence", sources[sharedKey].String())
+
+		onlyKey := kube.NewResourceKey("", "ConfigMap", "default", "only-in-source-1")
+		require.Contains(t, objs, onlyKey)
+		assert.Equal(t, "Defined by source 1", sources[onlyKey].String())
+	})
+
+	t.Run("ConflictWithinTheSameSource", func(t *testing.T) {
+		objs, sources, err := aggregateSourceObjects(&repoapiclient.ManifestResponse{
+			Manifests: []string{
+				configMapManifest("shared", "default", "from-source-2"),
+				configMapManifest("shared", "default", "from-source-1"),
+				configMapManifest("shared", "default", "from-source-2-again"),
+			},
+			// the sources may be reported out of order, the position decides of the precedence
+			SourcePositions: []int64{2, 1, 2},
+		}, liveObjs, "default")
+		require.NoError(t, err)
+		value, _, err := unstructured.NestedString(objs[sharedKey].Object, "data", "key")
+		require.NoError(t, err)
+		assert.Equal(t, "from-source-2-again", value)
+		assert.Equal(t, []int64{1, 2}, sources[sharedKey].positions)
+	})
+
+	t.Run("HooksAreSkipped", func(t *testing.T) {
+		objs, sources, err := aggregateSourceObjects(&repoapiclient.ManifestResponse{
+			Manifests: []string{
+				`{"apiVersion":"batch/v1","kind":"Job","metadata":{"name":"migrate","namespace":"default","annotations":{"argocd.argoproj.io/hook":"PreSync"}}}`,
+				configMapManifest("shared", "default", "value"),
+			},
+			SourcePositions: []int64{1, 2},
+		}, liveObjs, "default")
+		require.NoError(t, err)
+		assert.Len(t, objs, 1)
+		assert.Len(t, sources, 1)
+	})
+
+	t.Run("SourcesNotReported", func(t *testing.T) {
+		_, _, err := aggregateSourceObjects(&repoapiclient.ManifestResponse{
+			Manifests: []string{configMapManifest("shared", "default", "value")},
+		}, liveObjs, "default")
+		assert.ErrorContains(t, err, "did not report the sources of the manifests")
+	})
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-cd/v2/cmd/argocd/commands/headless"
+	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+	"github.com/argoproj/argo-cd/v2/util/cli"
+	"github.com/argoproj/argo-cd/v2/util/errors"
+	argoio "github.com/argoproj/argo-cd/v2/util/io"
+)
+
+// NewApplicationDryRunCommand returns a new instance of an `argocd app dry-run` command
+func NewApplicationDryRunCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var (
+		appNamespace    string
+		clusterSnapshot string
+		manifestsFile   string
+		revision        string
+	)
+	command := &cobra.Command{
+		Use:   "dry-run APPNAME",
+		Short: "Perform a diff of the target state against a snapshot of the destination cluster",
+		Long:  "Perform a diff of the target state against a snapshot of the destination cluster, without contacting the cluster.\nThe snapshot is a JSON list of resources, such as the output of 'kubectl get -o json'.\nUses 'diff' to render the difference. KUBECTL_EXTERNAL_DIFF environment variable can be used to select your own diff tool.\nReturns the following exit codes: 2 on general errors, 1 when a diff is found, and 0 when no diff is found",
+		Example: `  # Compare the manifests of an application to a snapshot of its destination namespace
+  kubectl get configmaps,deployments,services -n my-namespace -o json > snapshot.json
+  argocd app dry-run my-app --cluster-snapshot snapshot.json
+
+  # Compare local manifests to the snapshot
+  argocd app dry-run my-app --cluster-snapshot snapshot.json --manifests manifests.yaml`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+
+			if len(args) != 1 || clusterSnapshot == "" {
+				c.HelpFunc()(c, args)
+				os.Exit(2)
+			}
+			appName, appNs := argo.ParseFromQualifiedName(args[0], appNamespace)
+			snapshot, err := os.ReadFile(clusterSnapshot)
+			errors.CheckError(err)
+			request := &applicationpkg.DryRunSnapshotRequest{
+				Name:         &appName,
+				AppNamespace: &appNs,
+				Snapshot:     ptr.To(string(snapshot)),
+			}
+			if revision != "" {
+				request.Revision = &revision
+			}
+			if manifestsFile != "" {
+				request.Manifests = readDryRunManifests(manifestsFile)
+			}
+
+			conn, appIf := headless.NewClientOrDie(clientOpts, c).NewApplicationClientOrDie()
+			defer argoio.Close(conn)
+			result, err := appIf.DryRunSyncFromSnapshot(ctx, request)
+			errors.CheckError(err)
+
+			printDryRunDiffs(result.Items)
+			if result.GetModified() {
+				os.Exit(1)
+			}
+		},
+	}
+	command.Flags().StringVarP(&appNamespace, "app-namespace", "N", "", "Only dry run an application in namespace")
+	command.Flags().StringVar(&clusterSnapshot, "cluster-snapshot", "", "Path to the JSON snapshot of the destination cluster")
+	command.Flags().StringVar(&manifestsFile, "manifests", "", "Path to a YAML or JSON file of manifests to compare instead of the generated manifests")
+	command.Flags().StringVar(&revision, "revision", "", "Compare the manifests generated for the given revision")
+	return command
+}
+
+// readDryRunManifests reads the manifests of the given YAML or JSON file as JSON encoded resources
+func readDryRunManifests(path string) []string {
+	data, err := os.ReadFile(path)
+	errors.CheckError(err)
+	objs, err := kube.SplitYAML(data)
+	errors.CheckError(err)
+	manifests := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		manifest, err := json.Marshal(obj)
+		errors.CheckError(err)
+		manifests = append(manifests, string(manifest))
+	}
+	return manifests
+}
+
+// printDryRunDiffs prints the difference between the live and the predicted state of each modified resource
+func printDryRunDiffs(items []*argoappv1.ResourceDiff) {
+	for _, item := range items {
+		if !item.Modified {
+			continue
+		}
+		fmt.Printf("\n===== %s/%s %s/%s ======\n", item.Group, item.Kind, item.Namespace, item.Name)
+		live := unmarshalDryRunState(item.LiveState)
+		target := unmarshalDryRunState(item.TargetState)
+		if live != nil && target != nil {
+			live = unmarshalDryRunState(item.NormalizedLiveState)
+			target = unmarshalDryRunState(item.PredictedLiveState)
+		}
+		_ = cli.PrintDiff(item.Name, live, target)
+	}
+}
+
+func unmarshalDryRunState(state string) *unstructured.Unstructured {
+	var obj *unstructured.Unstructured
+	errors.CheckError(json.Unmarshal([]byte(state), &obj))
+	return obj
+}
@@ -0,0 +1,278 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	std_errors "errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	grpc_retry "github.com/grpc-ecosystem/go-grpc-middleware/retry"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-cd/v2/cmd/argocd/commands/headless"
+	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+	"github.com/argoproj/argo-cd/v2/util/errors"
+	argoio "github.com/argoproj/argo-cd/v2/util/io"
+)
+
+// NewApplicationExecCommand returns a new instance of an `argocd app exec` command
+func NewApplicationExecCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var (
+		appNamespace string
+		podName      string
+		podSelector  string
+		tty          bool
+	)
+	command := &cobra.Command{
+		Use:   "exec APPNAME [CONTAINER] -- COMMAND [ARGS...]",
+		Short: "Execute a command in a container of a pod of an application",
+		Long:  "Execute a command in a container of a pod of an application, through the API server. The pods of the application matching the flags are listed, and the command is executed in the pod selected among them if several match. Requires the exec feature to be enabled, and the create action on the exec resource of the application.",
+		Example: `  # Execute a command in the only pod of an application
+  argocd app exec my-app -- ls /
+
+  # Open a shell in a container of a pod selected among the pods of an application
+  argocd app exec my-app nginx -t -- sh
+
+  # Execute a command in a pod of an application matching a label selector
+  argocd app exec my-app --pod-selector app.kubernetes.io/component=frontend -- env`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+
+			dash := c.ArgsLenAtDash()
+			if dash < 1 || dash > 2 || len(args) == dash {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			container := ""
+			if dash == 2 {
+				container = args[1]
+			}
+			selector, err := labels.Parse(podSelector)
+			errors.CheckError(err)
+
+			acdClient := headless.NewClientOrDie(clientOpts, c)
+			conn, appIf := acdClient.NewApplicationClientOrDie()
+			defer argoio.Close(conn)
+			appName, appNs := argo.ParseFromQualifiedName(args[0], appNamespace)
+
+			tree, err := appIf.ResourceTree(ctx, &application.ResourcesQuery{ApplicationName: &appName, AppNamespace: &appNs})
+			errors.CheckError(err)
+			pods := filterExecPods(tree, podName, selector)
+			interactive := term.IsTerminal(int(os.Stdin.Fd()))
+			pod, err := selectExecPod(pods, interactive, os.Stdin, os.Stdout)
+			errors.CheckError(err)
+
+			if tty && !interactive {
+				log.Warn("Unable to use a TTY: the standard input is not a terminal")
+				tty = false
+			}
+			var resizes chan *application.TerminalSize
+			restoreTerminal := func() {}
+			if tty {
+				oldState, err := term.MakeRaw(int(os.Stdin.Fd()))
+				errors.CheckError(err)
+				restoreTerminal = func() { _ = term.Restore(int(os.Stdin.Fd()), oldState) }
+				resizes = watchTerminalSize(ctx, int(os.Stdout.Fd()))
+			}
+
+			exitCode, err := execPod(ctx, appIf, &application.ExecPodStart{
+				Name:         &appName,
+				AppNamespace: &appNs,
+				Namespace:    ptr.To(pod.Namespace),
+				PodName:      ptr.To(pod.Name),
+				Container:    &container,
+				Command:      args[dash:],
+				Tty:          &tty,
+			}, os.Stdin, os.Stdout, os.Stderr, resizes)
+			// the terminal is restored before exiting with the exit code of the command
+			restoreTerminal()
+			errors.CheckError(err)
+			if exitCode != 0 {
+				os.Exit(exitCode)
+			}
+		},
+	}
+	command.Flags().StringVarP(&appNamespace, "app-namespace", "N", "", "Namespace of the application")
+	command.Flags().StringVar(&podName, "pod", "", "Name of the pod to execute the command in")
+	command.Flags().StringVarP(&podSelector, "pod-selector", "l", "", "Label selector the pods to execute the command in must match, e.g. app.kubernetes.io/component=frontend")
+	command.Flags().BoolVarP(&tty, "tty", "t", false, "Allocate a TTY for the command")
+	return command
+}
+
+// filterExecPods returns the pods of the application tree which have the given name, unless empty, and whose labels
+// match the selector, sorted by namespace and name
+func filterExecPods(tree *argoappv1.ApplicationTree, podName string, selector labels.Selector) []argoappv1.ResourceNode {
+	var pods []argoappv1.ResourceNode
+	for _, node := range tree.Nodes {
+		if node.Kind != kube.PodKind || node.Group != "" || node.UID == "" {
+			continue
+		}
+		if podName != "" && node.Name != podName {
+			continue
+		}
+		var podLabels map[string]string
+		if node.NetworkingInfo != nil {
+			podLabels = node.NetworkingInfo.Labels
+		}
+		if !selector.Matches(labels.Set(podLabels)) {
+			continue
+		}
+		pods = append(pods, node)
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		if pods[i].Namespace != pods[j].Namespace {
+			return pods[i].Namespace < pods[j].Namespace
+		}
+		return pods[i].Name < pods[j].Name
+	})
+	return pods
+}
+
+// selectExecPod returns the pod to execute the command in: the only pod matching, or the pod the user selects among
+// the pods matching when interactive
+func selectExecPod(pods []argoappv1.ResourceNode, interactive bool, in io.Reader, out io.Writer) (*argoappv1.ResourceNode, error) {
+	switch {
+	case len(pods) == 0:
+		return nil, std_errors.New("no pod of the application matches")
+	case len(pods) == 1:
+		return &pods[0], nil
+	case !interactive:
+		return nil, fmt.Errorf("%d pods of the application match, select one with --pod or --pod-selector", len(pods))
+	}
+
+	for i, pod := range pods {
+		health := ""
+		if pod.Health != nil {
+			health = string(pod.Health.Status)
+		}
+		_, _ = fmt.Fprintf(out, "%d. %s/%s %s\n", i+1, pod.Namespace, pod.Name, health)
+	}
+	reader := bufio.NewReader(in)
+	for {
+		_, _ = fmt.Fprintf(out, "Select a pod [1-%d]: ", len(pods))
+		line, err := reader.ReadString('\n')
+		if i, convErr := strconv.Atoi(strings.TrimSpace(line)); convErr == nil && i >= 1 && i <= len(pods) {
+			return &pods[i-1], nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("no pod selected: %w", err)
+		}
+	}
+}
+
+// watchTerminalSize returns a channel receiving the size of the terminal, then each of its resizes
+func watchTerminalSize(ctx context.Context, fd int) chan *application.TerminalSize {
+	resizes := make(chan *application.TerminalSize, 1)
+	signals := make(chan os.Signal, 1)
+	notifyTerminalResize(signals)
+	go func() {
+		defer signal.Stop(signals)
+		for {
+			if width, height, err := term.GetSize(fd); err == nil {
+				select {
+				case resizes <- &application.TerminalSize{Width: ptr.To(uint32(width)), Height: ptr.To(uint32(height))}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-signals:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return resizes
+}
+
+// execPod executes the command in the pod through the API server, proxying the standard input, the terminal resizes
+// and the output of the command, and returns its exit code. The standard input of the command is closed once stdin
+// reaches its end.
+func execPod(ctx context.Context, appIf application.ApplicationServiceClient, start *application.ExecPodStart, stdin io.Reader, stdout, stderr io.Writer, resizes <-chan *application.TerminalSize) (int, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := appIf.ExecPod(ctx, grpc_retry.Disable())
+	if err != nil {
+		return 0, err
+	}
+	var sendLock sync.Mutex
+	send := func(req *application.ExecPodRequest) error {
+		sendLock.Lock()
+		defer sendLock.Unlock()
+		return stream.Send(req)
+	}
+	if err := send(&application.ExecPodRequest{Part: &application.ExecPodRequest_Start{Start: start}}); err != nil {
+		return 0, err
+	}
+
+	go func() {
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := stdin.Read(buf)
+			if n > 0 {
+				data := append([]byte(nil), buf[:n]...)
+				if send(&application.ExecPodRequest{Part: &application.ExecPodRequest_Stdin{Stdin: data}}) != nil {
+					return
+				}
+			}
+			if err != nil {
+				sendLock.Lock()
+				defer sendLock.Unlock()
+				_ = stream.CloseSend()
+				return
+			}
+		}
+	}()
+	if resizes != nil {
+		go func() {
+			for {
+				select {
+				case size := <-resizes:
+					if send(&application.ExecPodRequest{Part: &application.ExecPodRequest_Resize{Resize: size}}) != nil {
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+
+	for {
+		res, err := stream.Recv()
+		if err != nil {
+			if std_errors.Is(err, io.EOF) {
+				return 0, std_errors.New("the connection was closed before the command exited")
+			}
+			return 0, err
+		}
+		if len(res.Stdout) > 0 {
+			if _, err := stdout.Write(res.Stdout); err != nil {
+				return 0, err
+			}
+		}
+		if len(res.Stderr) > 0 {
+			if _, err := stderr.Write(res.Stderr); err != nil {
+				return 0, err
+			}
+		}
+		if res.GetExited() {
+			return int(res.GetExitCode()), nil
+		}
+	}
+}
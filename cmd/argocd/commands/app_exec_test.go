@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/ptr"
+
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func newExecTestPod(namespace, name string, podLabels map[string]string) v1alpha1.ResourceNode {
+	return v1alpha1.ResourceNode{
+		ResourceRef:    v1alpha1.ResourceRef{Version: "v1", Kind: "Pod", Namespace: namespace, Name: name, UID: name},
+		NetworkingInfo: &v1alpha1.ResourceNetworkingInfo{Labels: podLabels},
+		Health:         &v1alpha1.HealthStatus{Status: "Healthy"},
+	}
+}
+
+func TestFilterExecPods(t *testing.T) {
+	tree := &v1alpha1.ApplicationTree{Nodes: []v1alpha1.ResourceNode{
+		newExecTestPod("default", "frontend-b", map[string]string{"component": "frontend"}),
+		newExecTestPod("default", "backend", map[string]string{"component": "backend"}),
+		newExecTestPod("default", "frontend-a", map[string]string{"component": "frontend"}),
+		{ResourceRef: v1alpha1.ResourceRef{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "default", Name: "frontend", UID: "frontend"}},
+		// pods which do not exist yet have no UID
+		{ResourceRef: v1alpha1.ResourceRef{Version: "v1", Kind: "Pod", Namespace: "default", Name: "frontend-c"}},
+	}}
+	names := func(pods []v1alpha1.ResourceNode) []string {
+		var names []string
+		for _, pod := range pods {
+			names = append(names, pod.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"backend", "frontend-a", "frontend-b"}, names(filterExecPods(tree, "", labels.Everything())))
+	selector, err := labels.Parse("component=frontend")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"frontend-a", "frontend-b"}, names(filterExecPods(tree, "", selector)))
+	assert.Equal(t, []string{"frontend-b"}, names(filterExecPods(tree, "frontend-b", selector)))
+	assert.Empty(t, filterExecPods(tree, "backend", selector))
+}
+
+func TestSelectExecPod(t *testing.T) {
+	pods := []v1alpha1.ResourceNode{newExecTestPod("default", "frontend-a", nil), newExecTestPod("default", "frontend-b", nil)}
+
+	t.Run("Single", func(t *testing.T) {
+		pod, err := selectExecPod(pods[:1], false, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "frontend-a", pod.Name)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		_, err := selectExecPod(nil, true, nil, nil)
+		assert.EqualError(t, err, "no pod of the application matches")
+	})
+
+	t.Run("Interactive", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		pod, err := selectExecPod(pods, true, strings.NewReader("3\nfoo\n2\n"), out)
+		require.NoError(t, err)
+		assert.Equal(t, "frontend-b", pod.Name)
+		assert.Equal(t, "1. default/frontend-a Healthy\n2. default/frontend-b Healthy\n"+strings.Repeat("Select a pod [1-2]: ", 3), out.String())
+	})
+
+	t.Run("NotInteractive", func(t *testing.T) {
+		_, err := selectExecPod(pods, false, nil, nil)
+		assert.EqualError(t, err, "2 pods of the application match, select one with --pod or --pod-selector")
+	})
+}
+
+// fakeExecPodClient is the client side of an ExecPod stream: once the client closed its side of the stream, it sends
+// the upper cased standard input of the command as its output, then exits with the given code
+type fakeExecPodClient struct {
+	grpc.ClientStream
+	exitCode  int32
+	lock      sync.Mutex
+	requests  []*applicationpkg.ExecPodRequest
+	closed    chan struct{}
+	responses []*applicationpkg.ExecPodResponse
+}
+
+func (c *fakeExecPodClient) Send(req *applicationpkg.ExecPodRequest) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.requests = append(c.requests, req)
+	return nil
+}
+
+func (c *fakeExecPodClient) CloseSend() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	select {
+	case <-c.closed:
+		return nil
+	default:
+	}
+	var stdin strings.Builder
+	for _, req := range c.requests {
+		stdin.Write(req.GetStdin())
+	}
+	c.responses = []*applicationpkg.ExecPodResponse{
+		{Stdout: []byte(strings.ToUpper(stdin.String()))},
+		{Stderr: []byte("done\n")},
+		{Exited: ptr.To(true), ExitCode: ptr.To(c.exitCode)},
+	}
+	close(c.closed)
+	return nil
+}
+
+func (c *fakeExecPodClient) Recv() (*applicationpkg.ExecPodResponse, error) {
+	<-c.closed
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(c.responses) == 0 {
+		return nil, io.EOF
+	}
+	res := c.responses[0]
+	c.responses = c.responses[1:]
+	return res, nil
+}
+
+type fakeExecAppServiceClient struct {
+	fakeAppServiceClient
+	stream *fakeExecPodClient
+}
+
+func (c *fakeExecAppServiceClient) ExecPod(context.Context, ...grpc.CallOption) (applicationpkg.ApplicationService_ExecPodClient, error) {
+	return c.stream, nil
+}
+
+func TestExecPod(t *testing.T) {
+	stream := &fakeExecPodClient{exitCode: 2, closed: make(chan struct{})}
+	start := &applicationpkg.ExecPodStart{Name: ptr.To("guestbook"), PodName: ptr.To("guestbook-ui"), Command: []string{"cat"}}
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+
+	exitCode, err := execPod(context.Background(), &fakeExecAppServiceClient{stream: stream}, start, strings.NewReader("hello world\n"), stdout, stderr, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, exitCode)
+	assert.Equal(t, "HELLO WORLD\n", stdout.String())
+	assert.Equal(t, "done\n", stderr.String())
+	// the command is started by the first message
+	assert.Equal(t, start, stream.requests[0].GetStart())
+}
+
+func TestExecPod_ConnectionClosed(t *testing.T) {
+	stream := &fakeExecPodClient{closed: make(chan struct{})}
+	close(stream.closed)
+	_, err := execPod(context.Background(), &fakeExecAppServiceClient{stream: stream}, &applicationpkg.ExecPodStart{}, strings.NewReader(""), io.Discard, io.Discard, nil)
+	assert.EqualError(t, err, "the connection was closed before the command exited")
+}
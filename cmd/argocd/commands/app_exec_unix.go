@@ -0,0 +1,14 @@
+//go:build !windows
+
+package commands
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// notifyTerminalResize relays the resizes of the terminal to c
+func notifyTerminalResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
@@ -0,0 +1,11 @@
+//go:build windows
+
+package commands
+
+import (
+	"os"
+)
+
+// notifyTerminalResize does nothing, since Windows has no signal for the resizes of the terminal
+func notifyTerminalResize(c chan<- os.Signal) {
+}
@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/argoproj/argo-cd/v2/cmd/argocd/commands/headless"
+	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+	"github.com/argoproj/argo-cd/v2/util/errors"
+	argoio "github.com/argoproj/argo-cd/v2/util/io"
+)
+
+// NewApplicationPinRevisionCommand returns a new instance of an `argocd app pin-revision` command
+func NewApplicationPinRevisionCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var appNamespace string
+	command := &cobra.Command{
+		Use:   "pin-revision APPNAME",
+		Short: "Pin the target revision of the git sources of an application to the commit of its last successful sync",
+		Example: `  # Pin the target revision of an application tracking a branch to the commit it is deployed from
+  argocd app pin-revision my-app`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+
+			if len(args) != 1 {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+
+			conn, appIf := headless.NewClientOrDie(clientOpts, c).NewApplicationClientOrDie()
+			defer argoio.Close(conn)
+
+			appName, appNs := argo.ParseFromQualifiedName(args[0], appNamespace)
+			app, err := appIf.Get(ctx, &applicationpkg.ApplicationQuery{Name: &appName, AppNamespace: &appNs})
+			errors.CheckError(err)
+
+			revisions, err := pinDeployedRevisions(app)
+			errors.CheckError(err)
+			if len(revisions) == 0 {
+				fmt.Printf("Application '%s' is already pinned\n", app.Name)
+				return
+			}
+
+			_, err = appIf.UpdateSpec(ctx, &applicationpkg.ApplicationUpdateSpecRequest{
+				Name:         &app.Name,
+				Spec:         &app.Spec,
+				AppNamespace: &appNs,
+			})
+			errors.CheckError(err)
+			fmt.Printf("Application '%s' pinned to %s\n", app.Name, strings.Join(revisions, ", "))
+		},
+	}
+	command.Flags().StringVarP(&appNamespace, "app-namespace", "N", "", "Namespace of the application to pin")
+	return command
+}
+
+// pinDeployedRevisions pins the target revisions of the git sources of the application spec to the revisions of the
+// last successful sync of the application, and returns the revisions which were pinned
+func pinDeployedRevisions(app *argoappv1.Application) ([]string, error) {
+	if len(app.Status.History) == 0 {
+		return nil, fmt.Errorf("application '%s' has not been synced successfully yet", app.Name)
+	}
+	deployed := app.Status.History.LastRevisionHistory()
+	return argo.PinSpecRevisions(&app.Spec, deployed.Revision, deployed.Revisions), nil
+}
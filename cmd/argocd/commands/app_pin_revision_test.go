@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func TestPinDeployedRevisions(t *testing.T) {
+	const deployedSHA = "a3b59e4b1b0b0d1d8c2e3b6b4a8c1f5e6d7c8b9a"
+	newApp := func() *argoappv1.Application {
+		return &argoappv1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: "guestbook"},
+			Spec: argoappv1.ApplicationSpec{
+				Source: &argoappv1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook", TargetRevision: "main"},
+			},
+		}
+	}
+
+	t.Run("Synced", func(t *testing.T) {
+		app := newApp()
+		app.Status.History = argoappv1.RevisionHistories{{ID: 1, Revision: "0f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c"}, {ID: 2, Revision: deployedSHA}}
+		revisions, err := pinDeployedRevisions(app)
+		require.NoError(t, err)
+		assert.Equal(t, []string{deployedSHA}, revisions)
+		assert.Equal(t, deployedSHA, app.Spec.Source.TargetRevision)
+
+		revisions, err = pinDeployedRevisions(app)
+		require.NoError(t, err)
+		assert.Empty(t, revisions)
+	})
+
+	t.Run("NotSynced", func(t *testing.T) {
+		app := newApp()
+		_, err := pinDeployedRevisions(app)
+		require.ErrorContains(t, err, "has not been synced successfully yet")
+		assert.Equal(t, "main", app.Spec.Source.TargetRevision)
+	})
+}
@@ -2,11 +2,17 @@ package commands
 
 import (
 	"bytes"
+	"errors"
+	"io"
 	"testing"
 	"text/tabwriter"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"k8s.io/utils/ptr"
 
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 )
 
@@ -120,3 +126,47 @@ func TestPrintResourcesTree(t *testing.T) {
 
 	assert.Equal(t, expectation, output)
 }
+
+type fakeResourceTreeLevelsClient struct {
+	grpc.ClientStream
+	levels []*applicationpkg.ResourceTreeLevel
+	err    error
+}
+
+func (c *fakeResourceTreeLevelsClient) Recv() (*applicationpkg.ResourceTreeLevel, error) {
+	if len(c.levels) == 0 {
+		if c.err != nil {
+			return nil, c.err
+		}
+		return nil, io.EOF
+	}
+	level := c.levels[0]
+	c.levels = c.levels[1:]
+	return level, nil
+}
+
+func TestReceiveResourceTreeLevels(t *testing.T) {
+	deploy := &v1alpha1.ResourceNode{ResourceRef: v1alpha1.ResourceRef{Group: "apps", Kind: "Deployment", Name: "guestbook"}}
+	rs := &v1alpha1.ResourceNode{
+		ResourceRef: v1alpha1.ResourceRef{Group: "apps", Kind: "ReplicaSet", Name: "guestbook-1"},
+		ParentRefs:  []v1alpha1.ResourceRef{deploy.ResourceRef},
+	}
+
+	tree, truncated, err := receiveResourceTreeLevels(&fakeResourceTreeLevelsClient{levels: []*applicationpkg.ResourceTreeLevel{
+		{Depth: ptr.To(int64(0)), Nodes: []*v1alpha1.ResourceNode{deploy}},
+		{Depth: ptr.To(int64(1)), Nodes: []*v1alpha1.ResourceNode{rs}, Truncated: ptr.To(true)},
+	}})
+	require.NoError(t, err)
+	assert.True(t, truncated)
+	assert.Equal(t, []v1alpha1.ResourceNode{*deploy, *rs}, tree.Nodes)
+
+	tree, truncated, err = receiveResourceTreeLevels(&fakeResourceTreeLevelsClient{levels: []*applicationpkg.ResourceTreeLevel{
+		{Depth: ptr.To(int64(0)), Nodes: []*v1alpha1.ResourceNode{deploy}},
+	}})
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	assert.Len(t, tree.Nodes, 1)
+
+	_, _, err = receiveResourceTreeLevels(&fakeResourceTreeLevelsClient{err: errors.New("connection reset")})
+	require.ErrorContains(t, err, "connection reset")
+}
@@ -2,14 +2,17 @@ package commands
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"text/tabwriter"
+	"time"
 
 	"github.com/argoproj/argo-cd/v2/cmd/util"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/ptr"
 
@@ -30,9 +33,15 @@ func NewApplicationPatchResourceCommand(clientOpts *argocdclient.ClientOptions)
 	var group string
 	var all bool
 	var project string
+	var dryRun string
 	command := &cobra.Command{
 		Use:   "patch-resource APPNAME",
 		Short: "Patch resource in an application",
+		Example: `  # Patch the replicas of a deployment
+  argocd app patch-resource my-app --kind Deployment --resource-name guestbook-ui --patch '{"spec": {"replicas": 2}}'
+
+  # Preview the patch of the labels of a deployment, as the Kubernetes API server would apply it
+  argocd app patch-resource my-app --kind Deployment --resource-name guestbook-ui --patch '{"metadata": {"labels": {"team": "a"}}}' --dry-run server`,
 	}
 
 	command.Flags().StringVar(&patch, "patch", "", "Patch")
@@ -47,6 +56,7 @@ func NewApplicationPatchResourceCommand(clientOpts *argocdclient.ClientOptions)
 	command.Flags().StringVar(&namespace, "namespace", "", "Namespace")
 	command.Flags().BoolVar(&all, "all", false, "Indicates whether to patch multiple matching of resources")
 	command.Flags().StringVar(&project, "project", "", `The name of the application's project - specifying this allows the command to report "not found" instead of "permission denied" if the app does not exist`)
+	command.Flags().StringVar(&dryRun, "dry-run", "none", `Print the patched resources as JSON without modifying them. One of: none|client|server. "server" submits the patches to the Kubernetes API server in dry-run mode, "client" applies them to the live resources within Argo CD`)
 	command.Run = func(c *cobra.Command, args []string) {
 		ctx := c.Context()
 
@@ -55,6 +65,9 @@ func NewApplicationPatchResourceCommand(clientOpts *argocdclient.ClientOptions)
 			os.Exit(1)
 		}
 		appName, appNs := argo.ParseFromQualifiedName(args[0], "")
+		if dryRun != "none" && dryRun != "client" && dryRun != "server" {
+			errors.CheckError(fmt.Errorf("invalid --dry-run value %q, must be one of none, client or server", dryRun))
+		}
 
 		conn, appIf := headless.NewClientOrDie(clientOpts, c).NewApplicationClientOrDie()
 		defer argoio.Close(conn)
@@ -68,7 +81,7 @@ func NewApplicationPatchResourceCommand(clientOpts *argocdclient.ClientOptions)
 		for i := range objectsToPatch {
 			obj := objectsToPatch[i]
 			gvk := obj.GroupVersionKind()
-			_, err = appIf.PatchResource(ctx, &applicationpkg.ApplicationResourcePatchRequest{
+			res, err := appIf.PatchResource(ctx, &applicationpkg.ApplicationResourcePatchRequest{
 				Name:         &appName,
 				AppNamespace: &appNs,
 				Namespace:    ptr.To(obj.GetNamespace()),
@@ -79,8 +92,13 @@ func NewApplicationPatchResourceCommand(clientOpts *argocdclient.ClientOptions)
 				Patch:        ptr.To(patch),
 				PatchType:    ptr.To(patchType),
 				Project:      ptr.To(project),
+				DryRun:       ptr.To(dryRun),
 			})
 			errors.CheckError(err)
+			if dryRun != "none" {
+				fmt.Println(res.GetManifest())
+				continue
+			}
 			log.Infof("Resource '%s' patched", obj.GetName())
 		}
 	}
@@ -249,6 +267,9 @@ func NewApplicationListResourcesCommand(clientOpts *argocdclient.ClientOptions)
 	var orphaned bool
 	var output string
 	var project string
+	var stream bool
+	var maxDepth int64
+	var nodeLimit int64
 	command := &cobra.Command{
 		Use:   "resources APPNAME",
 		Short: "List resource of application",
@@ -258,21 +279,138 @@ func NewApplicationListResourcesCommand(clientOpts *argocdclient.ClientOptions)
 				c.HelpFunc()(c, args)
 				os.Exit(1)
 			}
+			if !stream && (c.Flag("max-depth").Changed || c.Flag("node-limit").Changed) {
+				errors.Fatal(errors.ErrorGeneric, "--max-depth and --node-limit require --stream")
+			}
+			if stream && orphaned {
+				errors.Fatal(errors.ErrorGeneric, "--orphaned cannot be used with --stream")
+			}
 			listAll := !c.Flag("orphaned").Changed
 			appName, appNs := argo.ParseFromQualifiedName(args[0], "")
 			conn, appIf := headless.NewClientOrDie(clientOpts, c).NewApplicationClientOrDie()
 			defer argoio.Close(conn)
-			appResourceTree, err := appIf.ResourceTree(ctx, &applicationpkg.ResourcesQuery{
-				ApplicationName: &appName,
-				AppNamespace:    &appNs,
-				Project:         &project,
-			})
-			errors.CheckError(err)
+			var appResourceTree *v1alpha1.ApplicationTree
+			var err error
+			if stream {
+				query := &applicationpkg.ResourceTreeStreamQuery{
+					ApplicationName: &appName,
+					AppNamespace:    &appNs,
+					Project:         &project,
+					NodeLimit:       &nodeLimit,
+				}
+				if c.Flag("max-depth").Changed {
+					query.MaxDepth = &maxDepth
+				}
+				var levels applicationpkg.ApplicationService_StreamApplicationResourceTreeClient
+				levels, err = appIf.StreamApplicationResourceTree(ctx, query)
+				errors.CheckError(err)
+				var truncated bool
+				appResourceTree, truncated, err = receiveResourceTreeLevels(levels)
+				errors.CheckError(err)
+				if truncated {
+					log.Warnf("The resource tree is truncated: the maximum depth or node limit was reached")
+				}
+			} else {
+				appResourceTree, err = appIf.ResourceTree(ctx, &applicationpkg.ResourcesQuery{
+					ApplicationName: &appName,
+					AppNamespace:    &appNs,
+					Project:         &project,
+				})
+				errors.CheckError(err)
+			}
 			printResources(listAll, orphaned, appResourceTree, output)
 		},
 	}
 	command.Flags().BoolVar(&orphaned, "orphaned", false, "Lists only orphaned resources")
 	command.Flags().StringVar(&output, "output", "", "Provides the tree view of the resources")
 	command.Flags().StringVar(&project, "project", "", `The name of the application's project - specifying this allows the command to report "not found" instead of "permission denied" if the app does not exist`)
+	command.Flags().BoolVar(&stream, "stream", false, "Receive the resource tree level by level instead of in a single response, for large applications. Orphaned resources are not listed")
+	command.Flags().Int64Var(&maxDepth, "max-depth", 0, "Only receive the resources up to the given depth, 0 being the depth of the resources managed by the application. Requires --stream")
+	command.Flags().Int64Var(&nodeLimit, "node-limit", 0, "Only receive the given number of resources, all resources are received if 0. Requires --stream")
+	return command
+}
+
+// receiveResourceTreeLevels receives the levels of a resource tree stream until its end, and returns the resource tree
+// and whether it is truncated
+func receiveResourceTreeLevels(stream applicationpkg.ApplicationService_StreamApplicationResourceTreeClient) (*v1alpha1.ApplicationTree, bool, error) {
+	tree := &v1alpha1.ApplicationTree{}
+	truncated := false
+	for {
+		level, err := stream.Recv()
+		if err == io.EOF {
+			return tree, truncated, nil
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("error receiving resource tree level: %w", err)
+		}
+		for _, node := range level.Nodes {
+			tree.Nodes = append(tree.Nodes, *node)
+		}
+		truncated = truncated || level.GetTruncated()
+	}
+}
+
+func NewApplicationResourceTimelineCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var namespace string
+	var limit int64
+	var offset int64
+	var output string
+	var project string
+	command := &cobra.Command{
+		Use:   "resource-timeline APPNAME KIND[.VERSION.GROUP] RESOURCENAME",
+		Short: "Show the health timeline of an application resource",
+		Example: `  # Show the health changes of a deployment, most recent first
+  argocd app resource-timeline my-app Deployment.v1.apps my-deployment --namespace default
+
+  # Show the ten most recent health changes of a config map
+  argocd app resource-timeline my-app ConfigMap my-config --namespace default --limit 10`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+			if len(args) != 3 {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			appName, appNs := argo.ParseFromQualifiedName(args[0], "")
+			gvk, gk := schema.ParseKindArg(args[1])
+			if gvk != nil {
+				gk = gvk.GroupKind()
+			} else {
+				gvk = &schema.GroupVersionKind{Group: gk.Group, Kind: gk.Kind}
+			}
+			conn, appIf := headless.NewClientOrDie(clientOpts, c).NewApplicationClientOrDie()
+			defer argoio.Close(conn)
+			timeline, err := appIf.GetResourceHealthTimeline(ctx, &applicationpkg.ResourceHealthTimelineRequest{
+				Name:         &appName,
+				AppNamespace: &appNs,
+				Namespace:    &namespace,
+				ResourceName: ptr.To(args[2]),
+				Version:      ptr.To(gvk.Version),
+				Group:        ptr.To(gk.Group),
+				Kind:         ptr.To(gk.Kind),
+				Limit:        &limit,
+				Offset:       &offset,
+				Project:      &project,
+			})
+			errors.CheckError(err)
+			switch output {
+			case "json", "yaml":
+				errors.CheckError(PrintResource(timeline, output))
+			case "":
+				w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+				_, _ = fmt.Fprintf(w, "TIMESTAMP\tSTATUS\tMESSAGE\n")
+				for _, event := range timeline.Events {
+					_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", event.Timestamp.Format(time.RFC3339), event.GetStatus(), event.GetMessage())
+				}
+				_ = w.Flush()
+			default:
+				errors.CheckError(fmt.Errorf("unknown output format: %s", output))
+			}
+		},
+	}
+	command.Flags().StringVar(&namespace, "namespace", "", "Namespace of the resource")
+	command.Flags().Int64Var(&limit, "limit", 0, "Maximum number of events to show, all events are shown if not set")
+	command.Flags().Int64Var(&offset, "offset", 0, "Number of most recent events to skip")
+	command.Flags().StringVarP(&output, "output", "o", "", "Output format. One of: json|yaml")
+	command.Flags().StringVar(&project, "project", "", `The name of the application's project - specifying this allows the command to report "not found" instead of "permission denied" if the app does not exist`)
 	return command
 }
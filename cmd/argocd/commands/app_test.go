@@ -51,6 +51,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	k8swatch "k8s.io/apimachinery/pkg/watch"
+	"k8s.io/utils/ptr"
 )
 
 func Test_getInfos(t *testing.T) {
@@ -583,6 +584,42 @@ func TestPrintOperationResult(t *testing.T) {
 			t.Fatalf("Incorrect print operation output %q, should be %q", output, expectation)
 		}
 	})
+	t.Run("Operation state with error code", func(t *testing.T) {
+		time := metav1.Date(2020, time.November, 10, 23, 0, 0, 0, time.UTC)
+		output, _ := captureOutput(func() error {
+			printOperationResult(&v1alpha1.OperationState{
+				SyncResult: &v1alpha1.SyncOperationResult{Revision: "revision"},
+				Phase:      "Failed",
+				ErrorCode:  v1alpha1.SyncErrorCodeHookFailed,
+				FinishedAt: &time,
+				Message:    "one or more synchronization tasks completed unsuccessfully",
+			})
+			return nil
+		})
+
+		expectation := "Operation:          Sync\nSync Revision:      revision\nPhase:              Failed\nError Code:         HookFailed\nStart:              0001-01-01 00:00:00 +0000 UTC\nFinished:           2020-11-10 23:00:00 +0000 UTC\nDuration:           2333448h16m18.871345152s\nMessage:            one or more synchronization tasks completed unsuccessfully\n"
+		if output != expectation {
+			t.Fatalf("Incorrect print operation output %q, should be %q", output, expectation)
+		}
+	})
+}
+
+func TestFormatSyncProgress(t *testing.T) {
+	now := time.Date(2020, time.November, 10, 23, 0, 0, 0, time.UTC)
+	estimatedCompletionTime := metav1.NewTime(now.Add(90 * time.Second))
+	assert.Equal(t, "[######........................] 10/50 resources applied, estimated completion in 1m30s", formatSyncProgress(&v1alpha1.SyncOperationProgress{
+		ResourcesApplied:        10,
+		ResourcesTotal:          50,
+		EstimatedCompletionTime: &estimatedCompletionTime,
+	}, now))
+	assert.Equal(t, "[##############################] 50/50 resources applied", formatSyncProgress(&v1alpha1.SyncOperationProgress{ResourcesApplied: 50, ResourcesTotal: 50}, now))
+	assert.Equal(t, "[..............................] 0/0 resources applied", formatSyncProgress(&v1alpha1.SyncOperationProgress{}, now))
+	// the estimate may be exceeded
+	assert.Equal(t, "[##############################] 50/50 resources applied, estimated completion in 0s", formatSyncProgress(&v1alpha1.SyncOperationProgress{
+		ResourcesApplied:        50,
+		ResourcesTotal:          50,
+		EstimatedCompletionTime: &estimatedCompletionTime,
+	}, now.Add(time.Hour)))
 }
 
 func TestPrintApplicationHistoryTable(t *testing.T) {
@@ -917,6 +954,76 @@ func TestPrintAppConditions(t *testing.T) {
 	}
 }
 
+func TestPrintAppConditionHistory(t *testing.T) {
+	output, _ := captureOutput(func() error {
+		transitionTime := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		app := &v1alpha1.Application{
+			Status: v1alpha1.ApplicationStatus{
+				ConditionHistory: []v1alpha1.ConditionHistoryEntry{
+					{Type: v1alpha1.ConditionHistoryTypeHealth, Status: "Healthy", LastTransitionTime: &transitionTime},
+					{Type: v1alpha1.ConditionHistoryTypeHealth, Status: "Degraded", LastTransitionTime: &transitionTime, Message: "test"},
+					{Type: v1alpha1.ApplicationConditionSyncError, Status: v1alpha1.ConditionHistoryStatusTrue, Message: "test2"},
+				},
+			},
+		}
+		printAppConditionHistory(os.Stdout, app)
+		return nil
+	})
+	expectation := "TIME\tTYPE\tSTATUS\tMESSAGE\n<nil>\tSyncError\tTrue\ttest2\n2024-01-01 00:00:00 +0000 UTC\tHealth\tDegraded\ttest\n2024-01-01 00:00:00 +0000 UTC\tHealth\tHealthy\t\n"
+	if output != expectation {
+		t.Fatalf("Incorrect print app condition history output %q, should be %q", output, expectation)
+	}
+}
+
+func TestPrintDeprecatedAPIs(t *testing.T) {
+	output, _ := captureOutput(func() error {
+		printDeprecatedAPIs(os.Stdout, []*applicationpkg.DeprecatedAPIUsage{
+			{Group: ptr.To("apps"), Version: ptr.To("v1beta1"), Kind: ptr.To("Deployment"), Namespace: ptr.To("default"), Name: ptr.To("web"), ReplacementAPIVersion: ptr.To("apps/v1"), RemovedInVersion: ptr.To("v1.16")},
+			{Group: ptr.To("policy"), Version: ptr.To("v1beta1"), Kind: ptr.To("PodSecurityPolicy"), Name: ptr.To("restricted"), RemovedInVersion: ptr.To("v1.25"), Served: ptr.To(true)},
+		})
+		return nil
+	})
+	expectation := "KIND\tNAMESPACE\tNAME\tAPI VERSION\tREMOVED IN\tSERVED\tREPLACEMENT\n" +
+		"Deployment\tdefault\tweb\tapps/v1beta1\tv1.16\tfalse\tapps/v1\n" +
+		"PodSecurityPolicy\t\trestricted\tpolicy/v1beta1\tv1.25\ttrue\t-\n"
+	assert.Equal(t, expectation, output)
+}
+
+func TestPrintManifestBlame(t *testing.T) {
+	blame := &apiclient.ManifestBlame{
+		Revision: "632039659e542ed7de0c170a4fcc1c571b288fc0",
+		Files: []*apiclient.ManifestFileBlame{
+			{FilePath: "cm.yaml", LastCommitAuthor: "Alice <alice@example.com>", LastCommitTime: 1700000000, LastCommitSHA: "9d2736b44e5508869fd76914e333ac3d88a6e2ed", LastCommitMessage: "Add config map"},
+		},
+	}
+
+	t.Run("SingleSource", func(t *testing.T) {
+		app := &v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{Source: &v1alpha1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook"}}}
+		output, _ := captureOutput(func() error {
+			printManifestBlame(os.Stdout, app, &applicationpkg.ApplicationManifestBlameResponse{Items: []*apiclient.ManifestBlame{blame}})
+			return nil
+		})
+		expectation := "\n===== Blame of https://github.com/argoproj/argocd-example-apps at 632039659e542ed7de0c170a4fcc1c571b288fc0 =====\n" +
+			"FILE               AUTHOR                     DATE                  COMMIT   MESSAGE\n" +
+			"guestbook/cm.yaml  Alice <alice@example.com>  2023-11-14T22:13:20Z  9d2736b  Add config map\n"
+		assert.Equal(t, expectation, output)
+	})
+
+	t.Run("MultipleSources", func(t *testing.T) {
+		app := &v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{Sources: v1alpha1.ApplicationSources{
+			{RepoURL: "https://charts.example.com", Chart: "guestbook"},
+			{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "."},
+		}}}
+		output, _ := captureOutput(func() error {
+			printManifestBlame(os.Stdout, app, &applicationpkg.ApplicationManifestBlameResponse{Items: []*apiclient.ManifestBlame{{}, blame}})
+			return nil
+		})
+		assert.Contains(t, output, "===== Blame of source 2: https://github.com/argoproj/argocd-example-apps at 632039659e542ed7de0c170a4fcc1c571b288fc0 =====")
+		assert.NotContains(t, output, "source 1")
+		assert.Contains(t, output, "\ncm.yaml ")
+	})
+}
+
 func TestPrintParams(t *testing.T) {
 	output, _ := captureOutput(func() error {
 		app := &v1alpha1.Application{
@@ -973,6 +1080,12 @@ func TestAppUrlDefault(t *testing.T) {
 	})
 }
 
+func TestAppBadgeURL(t *testing.T) {
+	assert.Equal(t, "https://argocd.example.com/api/badge/applications/guestbook", appBadgeURL("https://argocd.example.com", "guestbook", ""))
+	assert.Equal(t, "https://argocd.example.com/api/badge/applications/guestbook", appBadgeURL("https://argocd.example.com/", "guestbook", ""))
+	assert.Equal(t, "http://localhost:8080/api/badge/applications/guestbook?namespace=team-a", appBadgeURL("http://localhost:8080", "guestbook", "team-a"))
+}
+
 func TestTruncateString(t *testing.T) {
 	result := truncateString("argocdtool", 2)
 	expectation := "ar..."
@@ -1536,6 +1649,26 @@ func TestParseSelectedResourcesEmptyList(t *testing.T) {
 	assert.Empty(t, operationResources)
 }
 
+func TestParseSelectedDestinations(t *testing.T) {
+	destinations, err := parseSelectedDestinations([]string{"server=https://cluster-1.example.com", "name=cluster-2,namespace=my-namespace"})
+	require.NoError(t, err)
+	assert.Equal(t, []*v1alpha1.ApplicationDestination{
+		{Server: "https://cluster-1.example.com"},
+		{Name: "cluster-2", Namespace: "my-namespace"},
+	}, destinations)
+}
+
+func TestParseSelectedDestinationsIncorrect(t *testing.T) {
+	_, err := parseSelectedDestinations([]string{"https://cluster-1.example.com"})
+	require.ErrorContains(t, err, "https://cluster-1.example.com")
+
+	_, err = parseSelectedDestinations([]string{"cluster=cluster-1"})
+	require.ErrorContains(t, err, "Unknown destination field 'cluster'")
+
+	_, err = parseSelectedDestinations([]string{"namespace=my-namespace"})
+	require.ErrorContains(t, err, "should have a server or a name")
+}
+
 func TestPrintApplicationTableNotWide(t *testing.T) {
 	output, err := captureOutput(func() error {
 		app := &v1alpha1.Application{
@@ -1779,6 +1912,24 @@ func TestCheckResourceStatus(t *testing.T) {
 	})
 }
 
+func Test_preSyncSnapshotManifests(t *testing.T) {
+	liveManifest := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-config","namespace":"default","uid":"123","resourceVersion":"42","creationTimestamp":"2024-01-01T00:00:00Z","managedFields":[{"manager":"argocd-controller"}],"labels":{"app":"guestbook"}},"data":{"key":"value"}}`
+	snapshot := &applicationpkg.ResourceSnapshot{Resources: []*applicationpkg.ResourceSnapshotEntry{{
+		ResourceRef:  &v1alpha1.ResourceRef{Version: "v1", Kind: "ConfigMap", Namespace: "default", Name: "my-config"},
+		LiveManifest: &liveManifest,
+	}}}
+
+	manifests, err := preSyncSnapshotManifests(snapshot)
+	require.NoError(t, err)
+	require.Len(t, manifests, 1)
+	assert.JSONEq(t, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-config","namespace":"default","labels":{"app":"guestbook"}},"data":{"key":"value"}}`, manifests[0])
+
+	invalid := "{"
+	snapshot.Resources[0].LiveManifest = &invalid
+	_, err = preSyncSnapshotManifests(snapshot)
+	assert.ErrorContains(t, err, "error unmarshaling the snapshot of ConfigMap my-config")
+}
+
 func Test_hasAppChanged(t *testing.T) {
 	type args struct {
 		appReq *v1alpha1.Application
@@ -2095,6 +2246,14 @@ func (c *fakeAppServiceClient) GetManifests(ctx context.Context, in *application
 	return nil, nil
 }
 
+func (c *fakeAppServiceClient) ProfileManifests(ctx context.Context, in *applicationpkg.ApplicationManifestQuery, opts ...grpc.CallOption) (*applicationpkg.ApplicationManifestProfileResponse, error) {
+	return nil, nil
+}
+
+func (c *fakeAppServiceClient) GetManifestBlame(ctx context.Context, in *applicationpkg.ApplicationManifestQuery, opts ...grpc.CallOption) (*applicationpkg.ApplicationManifestBlameResponse, error) {
+	return nil, nil
+}
+
 func (c *fakeAppServiceClient) GetManifestsWithFiles(ctx context.Context, opts ...grpc.CallOption) (applicationpkg.ApplicationService_GetManifestsWithFilesClient, error) {
 	return nil, nil
 }
@@ -2131,6 +2290,14 @@ func (c *fakeAppServiceClient) WatchResourceTree(ctx context.Context, in *applic
 	return nil, nil
 }
 
+func (c *fakeAppServiceClient) StreamApplicationResourceTree(ctx context.Context, in *applicationpkg.ResourceTreeStreamQuery, opts ...grpc.CallOption) (applicationpkg.ApplicationService_StreamApplicationResourceTreeClient, error) {
+	return nil, nil
+}
+
+func (c *fakeAppServiceClient) WatchResource(ctx context.Context, in *applicationpkg.WatchResourceRequest, opts ...grpc.CallOption) (applicationpkg.ApplicationService_WatchResourceClient, error) {
+	return nil, nil
+}
+
 func (c *fakeAppServiceClient) Rollback(ctx context.Context, in *applicationpkg.ApplicationRollbackRequest, opts ...grpc.CallOption) (*v1alpha1.Application, error) {
 	return nil, nil
 }
@@ -2163,6 +2330,14 @@ func (c *fakeAppServiceClient) PodLogs(ctx context.Context, in *applicationpkg.A
 	return nil, nil
 }
 
+func (c *fakeAppServiceClient) ExecPod(ctx context.Context, opts ...grpc.CallOption) (applicationpkg.ApplicationService_ExecPodClient, error) {
+	return nil, nil
+}
+
+func (c *fakeAppServiceClient) CheckDeprecations(ctx context.Context, in *applicationpkg.DeprecatedAPIsQuery, opts ...grpc.CallOption) (*applicationpkg.DeprecatedAPIsResponse, error) {
+	return nil, nil
+}
+
 func (c *fakeAppServiceClient) ListLinks(ctx context.Context, in *applicationpkg.ListAppLinksRequest, opts ...grpc.CallOption) (*applicationpkg.LinksResponse, error) {
 	return nil, nil
 }
@@ -2171,6 +2346,38 @@ func (c *fakeAppServiceClient) ListResourceLinks(ctx context.Context, in *applic
 	return nil, nil
 }
 
+func (c *fakeAppServiceClient) GetResourceHealthTimeline(ctx context.Context, in *applicationpkg.ResourceHealthTimelineRequest, opts ...grpc.CallOption) (*applicationpkg.ResourceHealthTimeline, error) {
+	return nil, nil
+}
+
+func (c *fakeAppServiceClient) GetFleetStatus(ctx context.Context, in *applicationpkg.FleetStatusRequest, opts ...grpc.CallOption) (*applicationpkg.FleetStatus, error) {
+	return nil, nil
+}
+
+func (c *fakeAppServiceClient) StoreDryRunResult(ctx context.Context, in *applicationpkg.DryRunResultStoreRequest, opts ...grpc.CallOption) (*applicationpkg.DryRunResultStoreResponse, error) {
+	return nil, nil
+}
+
+func (c *fakeAppServiceClient) GetDryRunResult(ctx context.Context, in *applicationpkg.DryRunResultQuery, opts ...grpc.CallOption) (*applicationpkg.DryRunSyncResult, error) {
+	return nil, nil
+}
+
+func (c *fakeAppServiceClient) CompareDryRunToActual(ctx context.Context, in *applicationpkg.DryRunComparisonRequest, opts ...grpc.CallOption) (*applicationpkg.DryRunComparisonResult, error) {
+	return nil, nil
+}
+
+func (c *fakeAppServiceClient) DryRunSyncFromSnapshot(ctx context.Context, in *applicationpkg.DryRunSnapshotRequest, opts ...grpc.CallOption) (*applicationpkg.DryRunSnapshotResult, error) {
+	return nil, nil
+}
+
+func (c *fakeAppServiceClient) ServerSideDiff(ctx context.Context, in *applicationpkg.ApplicationServerSideDiffQuery, opts ...grpc.CallOption) (*applicationpkg.ApplicationServerSideDiffResponse, error) {
+	return nil, nil
+}
+
+func (c *fakeAppServiceClient) GetPreSyncSnapshot(ctx context.Context, in *applicationpkg.PreSyncSnapshotRequest, opts ...grpc.CallOption) (*applicationpkg.ResourceSnapshot, error) {
+	return nil, nil
+}
+
 type fakeAcdClient struct{}
 
 func (c *fakeAcdClient) ClientOptions() argocdclient.ClientOptions {
@@ -2298,3 +2505,55 @@ func (c *fakeAcdClient) WatchApplicationWithRetry(ctx context.Context, appName s
 	}()
 	return appEventsCh
 }
+
+func TestPrintServerSideDiff(t *testing.T) {
+	configMap := func(name string, annotations string) string {
+		return `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"` + name + `","namespace":"default"` + annotations + `},"data":{"key":"value"}}`
+	}
+	items := []*v1alpha1.ResourceDiff{{
+		Kind:                "ConfigMap",
+		Namespace:           "default",
+		Name:                "mutated",
+		NormalizedLiveState: configMap("mutated", ""),
+		PredictedLiveState:  configMap("mutated", `,"annotations":{"webhook.example.com/injected":"true"}`),
+		Modified:            true,
+	}, {
+		Kind:                "ConfigMap",
+		Namespace:           "default",
+		Name:                "created",
+		NormalizedLiveState: "null",
+		PredictedLiveState:  configMap("created", ""),
+		Modified:            true,
+	}, {
+		Kind:                "ConfigMap",
+		Namespace:           "default",
+		Name:                "same",
+		NormalizedLiveState: configMap("same", ""),
+		PredictedLiveState:  configMap("same", ""),
+	}, {
+		Kind:     "Secret",
+		Name:     "secret",
+		Modified: true,
+	}}
+
+	var foundDiffs bool
+	output, err := captureOutput(func() error {
+		foundDiffs = printServerSideDiff(items)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.True(t, foundDiffs)
+	assert.Contains(t, output, "===== /ConfigMap default/mutated ======")
+	assert.Contains(t, output, "webhook.example.com/injected: \"true\"")
+	assert.Contains(t, output, "===== /ConfigMap default/created ======")
+	assert.NotContains(t, output, "default/same")
+	assert.NotContains(t, output, "secret")
+
+	output, err = captureOutput(func() error {
+		foundDiffs = printServerSideDiff(items[2:3])
+		return nil
+	})
+	require.NoError(t, err)
+	assert.False(t, foundDiffs)
+	assert.Empty(t, output)
+}
@@ -256,13 +256,19 @@ func NewApplicationSetListCommand(clientOpts *argocdclient.ClientOptions) *cobra
 
 // NewApplicationSetDeleteCommand returns a new instance of an `argocd appset delete` command
 func NewApplicationSetDeleteCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
-	var noPrompt bool
+	var (
+		noPrompt             bool
+		forceDeleteProtected bool
+	)
 	command := &cobra.Command{
 		Use:   "delete",
 		Short: "Delete one or more ApplicationSets",
 		Example: templates.Examples(`
 	# Delete an applicationset
 	argocd appset delete APPSETNAME (APPSETNAME...)
+
+	# Delete an applicationset protected by the argocd.argoproj.io/deletion-protected annotation
+	argocd appset delete APPSETNAME --force-delete-protected
 		`),
 		Run: func(c *cobra.Command, args []string) {
 			ctx := c.Context()
@@ -284,8 +290,9 @@ func NewApplicationSetDeleteCommand(clientOpts *argocdclient.ClientOptions) *cob
 				appSetName, appSetNs := argo.ParseFromQualifiedName(appSetQualifiedName, "")
 
 				appsetDeleteReq := applicationset.ApplicationSetDeleteRequest{
-					Name:            appSetName,
-					AppsetNamespace: appSetNs,
+					Name:                 appSetName,
+					AppsetNamespace:      appSetNs,
+					ForceDeleteProtected: forceDeleteProtected,
 				}
 
 				if isTerminal && !noPrompt {
@@ -318,6 +325,7 @@ func NewApplicationSetDeleteCommand(clientOpts *argocdclient.ClientOptions) *cob
 		},
 	}
 	command.Flags().BoolVarP(&noPrompt, "yes", "y", false, "Turn off prompting to confirm cascaded deletion of Application resources")
+	command.Flags().BoolVar(&forceDeleteProtected, "force-delete-protected", false, "Remove the deletion protection annotation of the ApplicationSets before deleting them")
 	return command
 }
 
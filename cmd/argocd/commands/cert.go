@@ -175,22 +175,8 @@ func NewCertAddSSHCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command
 				errors.CheckError(fmt.Errorf("No valid SSH known hosts data found."))
 			}
 
-			for _, knownHostsEntry := range sshKnownHostsLists {
-				_, certSubType, certData, err := certutil.TokenizeSSHKnownHostsEntry(knownHostsEntry)
-				errors.CheckError(err)
-				hostnameList, _, err := certutil.KnownHostsLineToPublicKey(knownHostsEntry)
-				errors.CheckError(err)
-				// Each key could be valid for multiple hostnames
-				for _, hostname := range hostnameList {
-					certificate := appsv1.RepositoryCertificate{
-						ServerName:  hostname,
-						CertType:    "ssh",
-						CertSubType: certSubType,
-						CertData:    certData,
-					}
-					certificates = append(certificates, certificate)
-				}
-			}
+			certificates, err = sshKnownHostsToCertificates(sshKnownHostsLists)
+			errors.CheckError(err)
 
 			certList := &appsv1.RepositoryCertificateList{Items: certificates}
 			response, err := certIf.CreateCertificate(ctx, &certificatepkg.RepositoryCertificateCreateRequest{
@@ -207,6 +193,43 @@ func NewCertAddSSHCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command
 	return command
 }
 
+// sshKnownHostsToCertificates converts SSH known hosts entries to repository
+// certificates. Entries marked with @cert-authority become certificates of type
+// ssh-ca, which trust the host certificates signed by the certificate authority.
+func sshKnownHostsToCertificates(sshKnownHostsLists []string) ([]appsv1.RepositoryCertificate, error) {
+	var certificates []appsv1.RepositoryCertificate
+	for _, knownHostsEntry := range sshKnownHostsLists {
+		marker, knownHostsEntry := certutil.SplitSSHKnownHostsMarker(knownHostsEntry)
+		certType := "ssh"
+		switch marker {
+		case "":
+		case certutil.SSHKnownHostsMarkerCertAuthority:
+			certType = "ssh-ca"
+		default:
+			return nil, fmt.Errorf("SSH known hosts entries marked with %s are not supported", marker)
+		}
+		_, certSubType, certData, err := certutil.TokenizeSSHKnownHostsEntry(knownHostsEntry)
+		if err != nil {
+			return nil, err
+		}
+		hostnameList, _, err := certutil.KnownHostsLineToPublicKey(knownHostsEntry)
+		if err != nil {
+			return nil, err
+		}
+		// Each key could be valid for multiple hostnames
+		for _, hostname := range hostnameList {
+			certificate := appsv1.RepositoryCertificate{
+				ServerName:  hostname,
+				CertType:    certType,
+				CertSubType: certSubType,
+				CertData:    certData,
+			}
+			certificates = append(certificates, certificate)
+		}
+	}
+	return certificates, nil
+}
+
 // NewCertRemoveCommand returns a new instance of an `argocd cert rm` command
 func NewCertRemoveCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
 	var (
@@ -252,8 +275,8 @@ func NewCertRemoveCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command
 			}
 		},
 	}
-	command.Flags().StringVar(&certType, "cert-type", "", "Only remove certs of given type (ssh, https)")
-	command.Flags().StringVar(&certSubType, "cert-sub-type", "", "Only remove certs of given sub-type (only for ssh)")
+	command.Flags().StringVar(&certType, "cert-type", "", "Only remove certs of given type (ssh, ssh-ca, https)")
+	command.Flags().StringVar(&certSubType, "cert-sub-type", "", "Only remove certs of given sub-type (only for ssh and ssh-ca)")
 	return command
 }
 
@@ -274,9 +297,10 @@ func NewCertListCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
 			if certType != "" {
 				switch certType {
 				case "ssh":
+				case "ssh-ca":
 				case "https":
 				default:
-					fmt.Println("cert-type must be either ssh or https")
+					fmt.Println("cert-type must be either ssh, ssh-ca or https")
 					os.Exit(1)
 				}
 			}
@@ -300,7 +324,7 @@ func NewCertListCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
 
 	command.Flags().StringVarP(&output, "output", "o", "wide", "Output format. One of: json|yaml|wide")
 	command.Flags().StringVar(&sortOrder, "sort", "", "Set display sort order for output format wide. One of: hostname|type")
-	command.Flags().StringVar(&certType, "cert-type", "", "Only list certificates of given type, valid: 'ssh','https'")
+	command.Flags().StringVar(&certType, "cert-type", "", "Only list certificates of given type, valid: 'ssh','ssh-ca','https'")
 	command.Flags().StringVar(&hostNamePattern, "hostname-pattern", "", "Only list certificates for hosts matching given glob-pattern")
 	return command
 }
@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	"github.com/argoproj/argo-cd/v2/util/errors"
+	"github.com/argoproj/argo-cd/v2/util/localconfig"
+)
+
+// pluginPrefix is the prefix of the executables which are invoked as `argocd <name>`
+const pluginPrefix = "argocd-"
+
+// componentBinaryNames are the Argo CD binaries sharing the plugin prefix, which must not be treated as plugins
+var componentBinaryNames = map[string]bool{
+	"argocd-server":                    true,
+	"argocd-application-controller":    true,
+	"argocd-repo-server":               true,
+	"argocd-cmp-server":                true,
+	"argocd-dex":                       true,
+	"argocd-notifications":             true,
+	"argocd-git-ask-pass":              true,
+	"argocd-applicationset-controller": true,
+	"argocd-k8s-auth":                  true,
+	"argocd-linux-amd64":               true,
+	"argocd-darwin-amd64":              true,
+	"argocd-windows-amd64":             true,
+}
+
+// Plugin is an executable on the PATH which extends the CLI with a subcommand
+type Plugin struct {
+	// Name is the subcommand under which the plugin is invoked
+	Name string
+	// Path is the path of the plugin executable
+	Path string
+}
+
+// pluginName returns the name of the plugin for an executable file name, or false if the file is not a plugin
+func pluginName(fileName string) (string, bool) {
+	if runtime.GOOS == "windows" {
+		if !strings.EqualFold(filepath.Ext(fileName), ".exe") {
+			return "", false
+		}
+		fileName = strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	}
+	if !strings.HasPrefix(fileName, pluginPrefix) || componentBinaryNames[fileName] {
+		return "", false
+	}
+	name := strings.TrimPrefix(fileName, pluginPrefix)
+	if name == "" {
+		return "", false
+	}
+	return name, true
+}
+
+// isExecutable returns whether the file can be executed by the current user
+func isExecutable(info os.FileInfo) bool {
+	if info.IsDir() {
+		return false
+	}
+	if runtime.GOOS == "windows" {
+		return true
+	}
+	return info.Mode()&0o111 != 0
+}
+
+// DiscoverPlugins returns the plugins found in the directories of the given PATH, in the order of the directories.
+// A plugin which is shadowed by a plugin of the same name in an earlier directory is returned in the second list.
+func DiscoverPlugins(path string) ([]Plugin, []Plugin) {
+	var plugins, shadowed []Plugin
+	seen := map[string]bool{}
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			log.Debugf("Skipping plugin directory %s: %v", dir, err)
+			continue
+		}
+		for _, entry := range entries {
+			name, ok := pluginName(entry.Name())
+			if !ok {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil || !isExecutable(info) {
+				continue
+			}
+			plugin := Plugin{Name: name, Path: filepath.Join(dir, entry.Name())}
+			if seen[name] {
+				shadowed = append(shadowed, plugin)
+				continue
+			}
+			seen[name] = true
+			plugins = append(plugins, plugin)
+		}
+	}
+	return plugins, shadowed
+}
+
+// lookupPlugin returns the path of the plugin with the given name, or false if no such plugin is on the PATH
+func lookupPlugin(name string) (string, bool) {
+	fileName := pluginPrefix + name
+	if componentBinaryNames[fileName] || strings.ContainsAny(name, `/\`) {
+		return "", false
+	}
+	path, err := exec.LookPath(fileName)
+	if err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// isBuiltinCommand returns whether the arguments invoke a command of the CLI rather than a plugin
+func isBuiltinCommand(command *cobra.Command, args []string) bool {
+	command.InitDefaultHelpCmd()
+	cmd, _, err := command.Find(args)
+	return err == nil && cmd != command
+}
+
+// stringFlag returns the value of a persistent flag of the root command, whose default is taken from ARGOCD_OPTS
+func stringFlag(command *cobra.Command, name string) string {
+	if flag := command.PersistentFlags().Lookup(name); flag != nil {
+		return flag.Value.String()
+	}
+	return ""
+}
+
+// pluginEnv returns the environment of a plugin, which passes the server address and auth token the CLI would use on
+// to the plugin. Like for the CLI, the ARGOCD_OPTS flags take precedence over the environment, which takes precedence
+// over the current context of the local config.
+func pluginEnv(command *cobra.Command) []string {
+	serverAddr := stringFlag(command, "server")
+	if serverAddr == "" {
+		serverAddr = os.Getenv(argocdclient.EnvArgoCDServer)
+	}
+	authToken := stringFlag(command, "auth-token")
+	if authToken == "" {
+		authToken = os.Getenv(argocdclient.EnvArgoCDAuthToken)
+	}
+	if serverAddr == "" || authToken == "" {
+		localCfg, err := localconfig.ReadLocalConfig(stringFlag(command, "config"))
+		if err != nil {
+			log.Warnf("Failed to read local config: %v", err)
+		} else if localCfg != nil {
+			if configCtx, err := localCfg.ResolveContext(stringFlag(command, "argocd-context")); err == nil {
+				if serverAddr == "" {
+					serverAddr = configCtx.Server.Server
+				}
+				if authToken == "" && configCtx.Server.Server == serverAddr {
+					authToken = configCtx.User.AuthToken
+				}
+			}
+		}
+	}
+
+	env := os.Environ()
+	if serverAddr != "" {
+		env = append(env, fmt.Sprintf("%s=%s", argocdclient.EnvArgoCDServer, serverAddr))
+	}
+	if authToken != "" {
+		env = append(env, fmt.Sprintf("%s=%s", argocdclient.EnvArgoCDAuthToken, authToken))
+	}
+	return env
+}
+
+// HandlePluginCommand executes the plugin named by the first argument if it is not a command of the CLI, and returns
+// whether a plugin was executed. The remaining arguments are passed on to the plugin.
+func HandlePluginCommand(command *cobra.Command, args []string) (bool, error) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") || isBuiltinCommand(command, args) {
+		return false, nil
+	}
+	path, ok := lookupPlugin(args[0])
+	if !ok {
+		return false, nil
+	}
+	cmd := exec.Command(path, args[1:]...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = pluginEnv(command)
+	return true, cmd.Run()
+}
+
+// NewPluginCommand returns a new instance of an `argocd plugin` command
+func NewPluginCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "plugin",
+		Short: "Manage CLI plugins",
+		Long: fmt.Sprintf(`Manage CLI plugins.
+
+Plugins are executables on the PATH whose names start with %q. They are invoked as subcommands of the CLI,
+e.g. the plugin "argocd-foo" is invoked as "argocd foo".`, pluginPrefix),
+		Run: func(c *cobra.Command, args []string) {
+			c.HelpFunc()(c, args)
+			os.Exit(1)
+		},
+	}
+	command.AddCommand(NewPluginListCommand())
+	return command
+}
+
+// NewPluginListCommand returns a new instance of an `argocd plugin list` command
+func NewPluginListCommand() *cobra.Command {
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List the plugins found on the PATH",
+		Example: `# List all plugins
+argocd plugin list`,
+		Run: func(c *cobra.Command, args []string) {
+			plugins, shadowed := DiscoverPlugins(os.Getenv("PATH"))
+			if len(plugins) == 0 {
+				errors.CheckError(fmt.Errorf("no plugins found on the PATH"))
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintf(w, "NAME\tPATH\n")
+			for _, plugin := range plugins {
+				_, _ = fmt.Fprintf(w, "%s\t%s\n", plugin.Name, plugin.Path)
+			}
+			_ = w.Flush()
+			for _, plugin := range plugins {
+				if isBuiltinCommand(c.Root(), []string{plugin.Name}) {
+					log.Warnf("Plugin %s is overshadowed by the argocd %s command and cannot be invoked", plugin.Path, plugin.Name)
+				}
+			}
+			for _, plugin := range shadowed {
+				log.Warnf("Plugin %s is overshadowed by a plugin of the same name earlier on the PATH", plugin.Path)
+			}
+		},
+	}
+	return command
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
+)
+
+func writePlugin(t *testing.T, dir string, name string, script string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0o755))
+	return path
+}
+
+func envValue(env []string, key string) string {
+	value := ""
+	for _, kv := range env {
+		if strings.HasPrefix(kv, key+"=") {
+			value = strings.TrimPrefix(kv, key+"=")
+		}
+	}
+	return value
+}
+
+func TestDiscoverPlugins(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("plugins are discovered by their .exe extension on windows")
+	}
+	first := t.TempDir()
+	second := t.TempDir()
+	hello := writePlugin(t, first, "argocd-hello", "echo hello")
+	world := writePlugin(t, second, "argocd-world", "echo world")
+	shadowed := writePlugin(t, second, "argocd-hello", "echo shadowed")
+	writePlugin(t, second, "argocd-server", "echo server")
+	writePlugin(t, second, "kubectl-hello", "echo kubectl")
+	require.NoError(t, os.WriteFile(filepath.Join(second, "argocd-notexecutable"), []byte("#!/bin/sh\n"), 0o644))
+	require.NoError(t, os.Mkdir(filepath.Join(second, "argocd-dir"), 0o755))
+
+	plugins, shadowedPlugins := DiscoverPlugins(strings.Join([]string{first, second, filepath.Join(first, "missing")}, string(os.PathListSeparator)))
+
+	assert.Equal(t, []Plugin{{Name: "hello", Path: hello}, {Name: "world", Path: world}}, plugins)
+	assert.Equal(t, []Plugin{{Name: "hello", Path: shadowed}}, shadowedPlugins)
+}
+
+func TestHandlePluginCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the test plugins are shell scripts")
+	}
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	writePlugin(t, dir, "argocd-hello", `echo "$@ $ARGOCD_SERVER $ARGOCD_AUTH_TOKEN" > `+out)
+	writePlugin(t, dir, "argocd-fail", "exit 3")
+	writePlugin(t, dir, "argocd-version", "echo plugin")
+	t.Setenv("PATH", dir)
+	t.Setenv(argocdclient.EnvArgoCDServer, "argocd.example.com:443")
+	t.Setenv(argocdclient.EnvArgoCDAuthToken, "s3cr3t")
+
+	command := NewCommand()
+
+	handled, err := HandlePluginCommand(command, []string{"hello", "--name", "world"})
+	require.NoError(t, err)
+	assert.True(t, handled)
+	output, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "--name world argocd.example.com:443 s3cr3t\n", string(output))
+
+	handled, err = HandlePluginCommand(command, []string{"fail"})
+	assert.True(t, handled)
+	assert.Error(t, err)
+
+	for _, args := range [][]string{{}, {"--help"}, {"version"}, {"help"}, {"missing"}} {
+		handled, err = HandlePluginCommand(command, args)
+		require.NoError(t, err)
+		assert.False(t, handled, "arguments %v must not be handled by a plugin", args)
+	}
+}
+
+func TestPluginEnv(t *testing.T) {
+	err := os.WriteFile(testConfigFilePath, []byte(testConfig), 0o600)
+	require.NoError(t, err)
+	defer os.Remove(testConfigFilePath)
+	t.Setenv(argocdclient.EnvArgoCDServer, "")
+	t.Setenv(argocdclient.EnvArgoCDAuthToken, "")
+
+	t.Run("LocalConfig", func(t *testing.T) {
+		command := NewCommand()
+		require.NoError(t, command.PersistentFlags().Set("config", testConfigFilePath))
+		env := pluginEnv(command)
+		assert.Equal(t, "localhost:8080", envValue(env, argocdclient.EnvArgoCDServer))
+		assert.Equal(t, "vErrYS3c3tReFRe$hToken", envValue(env, argocdclient.EnvArgoCDAuthToken))
+	})
+
+	t.Run("Context", func(t *testing.T) {
+		command := NewCommand()
+		require.NoError(t, command.PersistentFlags().Set("config", testConfigFilePath))
+		require.NoError(t, command.PersistentFlags().Set("argocd-context", "argocd1.example.com:443"))
+		env := pluginEnv(command)
+		assert.Equal(t, "argocd1.example.com:443", envValue(env, argocdclient.EnvArgoCDServer))
+	})
+
+	t.Run("Environment", func(t *testing.T) {
+		t.Setenv(argocdclient.EnvArgoCDServer, "argocd.example.com:443")
+		command := NewCommand()
+		require.NoError(t, command.PersistentFlags().Set("config", testConfigFilePath))
+		env := pluginEnv(command)
+		assert.Equal(t, "argocd.example.com:443", envValue(env, argocdclient.EnvArgoCDServer))
+		// the token of the current context is not passed on for another server
+		assert.Empty(t, envValue(env, argocdclient.EnvArgoCDAuthToken))
+	})
+
+	t.Run("Flags", func(t *testing.T) {
+		t.Setenv(argocdclient.EnvArgoCDServer, "argocd.example.com:443")
+		command := NewCommand()
+		require.NoError(t, command.PersistentFlags().Set("config", testConfigFilePath))
+		require.NoError(t, command.PersistentFlags().Set("server", "flag.example.com:443"))
+		require.NoError(t, command.PersistentFlags().Set("auth-token", "flag-token"))
+		env := pluginEnv(command)
+		assert.Equal(t, "flag.example.com:443", envValue(env, argocdclient.EnvArgoCDServer))
+		assert.Equal(t, "flag-token", envValue(env, argocdclient.EnvArgoCDAuthToken))
+	})
+}
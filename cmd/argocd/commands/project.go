@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 	"text/tabwriter"
 	"time"
@@ -14,11 +15,13 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/yaml"
 
 	"github.com/argoproj/argo-cd/v2/cmd/argocd/commands/headless"
 	cmdutil "github.com/argoproj/argo-cd/v2/cmd/util"
 	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
 	projectpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/project"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/argoproj/argo-cd/v2/util/cli"
@@ -80,6 +83,7 @@ func NewProjectCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
 	command.AddCommand(NewProjectRemoveOrphanedIgnoreCommand(clientOpts))
 	command.AddCommand(NewProjectAddSourceNamespace(clientOpts))
 	command.AddCommand(NewProjectRemoveSourceNamespace(clientOpts))
+	command.AddCommand(NewProjectQuotaCommand(clientOpts))
 	return command
 }
 
@@ -1024,6 +1028,73 @@ func NewProjectGetCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command
 	return command
 }
 
+// NewProjectQuotaCommand returns a new instance of an `argocd proj quota` command
+func NewProjectQuotaCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var projName string
+	command := &cobra.Command{
+		Use:   "quota",
+		Short: "Display the resource quota usage of a project",
+		Example: templates.Examples(`
+			# Display the number of resources managed by the applications of project staging against its quota
+			argocd proj quota --project staging
+		`),
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+
+			if projName == "" {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			clientset := headless.NewClientOrDie(clientOpts, c)
+			conn, projIf := clientset.NewProjectClientOrDie()
+			defer argoio.Close(conn)
+			proj, err := projIf.Get(ctx, &projectpkg.ProjectQuery{Name: projName})
+			errors.CheckError(err)
+
+			appConn, appIf := clientset.NewApplicationClientOrDie()
+			defer argoio.Close(appConn)
+			apps, err := appIf.List(ctx, &applicationpkg.ApplicationQuery{Projects: []string{projName}})
+			errors.CheckError(err)
+
+			printProjectQuota(proj, apps.Items)
+		},
+	}
+	command.Flags().StringVarP(&projName, "project", "p", "", "Project name")
+	return command
+}
+
+// printProjectQuota prints the number of resources of each kind managed by the given applications
+// along with the limit of the project quota
+func printProjectQuota(proj *v1alpha1.AppProject, apps []v1alpha1.Application) {
+	used := make(map[string]int64)
+	for _, app := range apps {
+		for _, res := range app.Status.Resources {
+			used[schema.GroupKind{Group: res.Group, Kind: res.Kind}.String()]++
+		}
+	}
+	kinds := make([]string, 0, len(used))
+	for kind := range used {
+		kinds = append(kinds, kind)
+	}
+	for kind := range proj.Spec.NamespaceResourceQuota {
+		if _, ok := used[kind]; !ok {
+			kinds = append(kinds, kind)
+		}
+	}
+	sort.Strings(kinds)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "KIND\tUSED\tLIMIT\n")
+	for _, kind := range kinds {
+		limit := "-"
+		if l, ok := proj.Spec.NamespaceResourceQuota[kind]; ok {
+			limit = fmt.Sprintf("%d", l)
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\n", kind, used[kind], limit)
+	}
+	_ = w.Flush()
+}
+
 func getProject(c *cobra.Command, clientOpts *argocdclient.ClientOptions, ctx context.Context, projName string) *projectpkg.DetailedProjectsResponse {
 	conn, projIf := headless.NewClientOrDie(clientOpts, c).NewProjectClientOrDie()
 	defer argoio.Close(conn)
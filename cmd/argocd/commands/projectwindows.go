@@ -140,14 +140,15 @@ argocd proj windows enable-manual-sync my-app-project --message "Manual sync ini
 // NewProjectWindowsAddWindowCommand returns a new instance of an `argocd proj windows add` command
 func NewProjectWindowsAddWindowCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
 	var (
-		kind         string
-		schedule     string
-		duration     string
-		applications []string
-		namespaces   []string
-		clusters     []string
-		manualSync   bool
-		timeZone     string
+		kind          string
+		schedule      string
+		duration      string
+		applications  []string
+		namespaces    []string
+		clusters      []string
+		manualSync    bool
+		timeZone      string
+		allowOverride bool
 	)
 	command := &cobra.Command{
 		Use:   "add PROJECT",
@@ -186,6 +187,7 @@ argocd proj windows add PROJECT \
 
 			err = proj.Spec.AddWindow(kind, schedule, duration, applications, namespaces, clusters, manualSync, timeZone)
 			errors.CheckError(err)
+			proj.Spec.SyncWindows[len(proj.Spec.SyncWindows)-1].AllowOverride = allowOverride
 
 			_, err = projIf.Update(ctx, &projectpkg.ProjectUpdateRequest{Project: proj})
 			errors.CheckError(err)
@@ -199,6 +201,7 @@ argocd proj windows add PROJECT \
 	command.Flags().StringSliceVar(&clusters, "clusters", []string{}, "Clusters that the schedule will be applied to. Comma separated, wildcards supported (e.g. --clusters prod,staging)")
 	command.Flags().BoolVar(&manualSync, "manual-sync", false, "Allow manual syncs for both deny and allow windows")
 	command.Flags().StringVar(&timeZone, "time-zone", "UTC", "Time zone of the sync window")
+	command.Flags().BoolVar(&allowOverride, "allow-override", false, "Allow users with the permission to override syncs to sync manually when the window would block the sync")
 
 	return command
 }
@@ -352,11 +355,15 @@ func printSyncWindows(proj *v1alpha1.AppProject) {
 	fmt.Fprintf(w, fmtStr, headers...)
 	if proj.Spec.SyncWindows.HasWindows() {
 		for i, window := range proj.Spec.SyncWindows {
+			schedule := window.Schedule
+			if window.ChronoSchedule != "" {
+				schedule = window.ChronoSchedule
+			}
 			vals := []interface{}{
 				strconv.Itoa(i),
 				formatBoolOutput(window.Active()),
 				window.Kind,
-				window.Schedule,
+				schedule,
 				window.Duration,
 				formatListOutput(window.Applications),
 				formatListOutput(window.Namespaces),
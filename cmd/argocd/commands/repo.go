@@ -12,8 +12,10 @@ import (
 	"github.com/argoproj/argo-cd/v2/cmd/argocd/commands/headless"
 	cmdutil "github.com/argoproj/argo-cd/v2/cmd/util"
 	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	certificatepkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/certificate"
 	repositorypkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/repository"
 	appsv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	certutil "github.com/argoproj/argo-cd/v2/util/cert"
 	"github.com/argoproj/argo-cd/v2/util/cli"
 	"github.com/argoproj/argo-cd/v2/util/errors"
 	"github.com/argoproj/argo-cd/v2/util/git"
@@ -41,6 +43,9 @@ argocd repo list
 
 # Remove Repository Credentials
 argocd repo rm https://github.com/yourusername/your-repo.git
+
+# Import a git bundle for a repository which cannot be reached from the cluster
+argocd repo import-bundle --bundle-file repo.bundle --repo-url https://gitlab.example.com/app
 `,
 	}
 
@@ -48,17 +53,25 @@ argocd repo rm https://github.com/yourusername/your-repo.git
 	command.AddCommand(NewRepoGetCommand(clientOpts))
 	command.AddCommand(NewRepoListCommand(clientOpts))
 	command.AddCommand(NewRepoRemoveCommand(clientOpts))
+	command.AddCommand(NewRepoImportBundleCommand(clientOpts))
+	command.AddCommand(NewRepoExportBundleCommand(clientOpts))
 	return command
 }
 
 // NewRepoAddCommand returns a new instance of an `argocd repo add` command
 func NewRepoAddCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
-	var repoOpts cmdutil.RepoOptions
+	var (
+		repoOpts          cmdutil.RepoOptions
+		sshKnownHostsFile string
+	)
 
 	// For better readability and easier formatting
 	repoAddExamples := `  # Add a Git repository via SSH using a private key for authentication, ignoring the server's host key:
   argocd repo add git@git.example.com:repos/repo --insecure-ignore-host-key --ssh-private-key-path ~/id_rsa
 
+  # Add a Git repository via SSH, trusting the host keys or certificate authorities of the server listed in a known hosts file:
+  argocd repo add git@git.example.com:repos/repo --ssh-private-key-path ~/id_rsa --ssh-known-hosts-file ~/known_hosts
+
   # Add a Git repository via SSH on a non-default port - need to use ssh:// style URLs here
   argocd repo add ssh://git@git.example.com:2222/repos/repo --ssh-private-key-path ~/id_rsa
 
@@ -174,6 +187,14 @@ func NewRepoAddCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
 			repoOpts.Repo.Insecure = repoOpts.InsecureSkipServerVerification
 			repoOpts.Repo.EnableLFS = repoOpts.EnableLfs
 			repoOpts.Repo.EnableOCI = repoOpts.EnableOci
+			if repoOpts.Repo.OCIType != "" {
+				if !repoOpts.Repo.EnableOCI {
+					errors.CheckError(fmt.Errorf("--oci-type is only supported for OCI repositories"))
+				}
+				if repoOpts.Repo.OCIType != appsv1.OCITypeHelm && repoOpts.Repo.OCIType != appsv1.OCITypeManifests {
+					errors.CheckError(fmt.Errorf("--oci-type must be %q or %q", appsv1.OCITypeHelm, appsv1.OCITypeManifests))
+				}
+			}
 			repoOpts.Repo.GithubAppId = repoOpts.GithubAppId
 			repoOpts.Repo.GithubAppInstallationId = repoOpts.GithubAppInstallationId
 			repoOpts.Repo.GitHubAppEnterpriseBaseURL = repoOpts.GitHubAppEnterpriseBaseURL
@@ -184,6 +205,21 @@ func NewRepoAddCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
 				errors.CheckError(fmt.Errorf("Must specify --name for repos of type 'helm'"))
 			}
 
+			// Specifying ssh-known-hosts-file is only valid for SSH repositories
+			var knownHostsCertificates []appsv1.RepositoryCertificate
+			if sshKnownHostsFile != "" {
+				if ok, _ := git.IsSSHURL(repoOpts.Repo.Repo); !ok {
+					errors.CheckError(fmt.Errorf("--ssh-known-hosts-file is only supported for SSH repositories"))
+				}
+				sshKnownHostsLists, err := certutil.ParseSSHKnownHostsFromPath(sshKnownHostsFile)
+				errors.CheckError(err)
+				if len(sshKnownHostsLists) == 0 {
+					errors.CheckError(fmt.Errorf("No valid SSH known hosts data found in %s", sshKnownHostsFile))
+				}
+				knownHostsCertificates, err = sshKnownHostsToCertificates(sshKnownHostsLists)
+				errors.CheckError(err)
+			}
+
 			conn, repoIf := headless.NewClientOrDie(clientOpts, c).NewRepoClientOrDie()
 			defer io.Close(conn)
 
@@ -193,6 +229,18 @@ func NewRepoAddCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
 				repoOpts.Repo.Password = cli.PromptPassword(repoOpts.Repo.Password)
 			}
 
+			// The known hosts must be in place before the server checks access to
+			// the repository
+			if len(knownHostsCertificates) > 0 {
+				certConn, certIf := headless.NewClientOrDie(clientOpts, c).NewCertClientOrDie()
+				defer io.Close(certConn)
+				_, err := certIf.CreateCertificate(ctx, &certificatepkg.RepositoryCertificateCreateRequest{
+					Certificates: &appsv1.RepositoryCertificateList{Items: knownHostsCertificates},
+					Upsert:       repoOpts.Upsert,
+				})
+				errors.CheckError(err)
+			}
+
 			// We let the server check access to the repository before adding it. If
 			// it is a private repo, but we cannot access with with the credentials
 			// that were supplied, we bail out.
@@ -211,6 +259,7 @@ func NewRepoAddCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
 				TlsClientCertKey:           repoOpts.Repo.TLSClientCertKey,
 				Insecure:                   repoOpts.Repo.IsInsecure(),
 				EnableOci:                  repoOpts.Repo.EnableOCI,
+				OciType:                    repoOpts.Repo.OCIType,
 				GithubAppPrivateKey:        repoOpts.Repo.GithubAppPrivateKey,
 				GithubAppID:                repoOpts.Repo.GithubAppId,
 				GithubAppInstallationID:    repoOpts.Repo.GithubAppInstallationId,
@@ -234,6 +283,7 @@ func NewRepoAddCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
 		},
 	}
 	command.Flags().BoolVar(&repoOpts.Upsert, "upsert", false, "Override an existing repository with the same name even if the spec differs")
+	command.Flags().StringVar(&sshKnownHostsFile, "ssh-known-hosts-file", "", "Path to a known hosts file whose host keys and certificate authorities are trusted for SSH repositories")
 	cmdutil.AddRepoFlags(command, &repoOpts)
 	return command
 }
@@ -385,3 +435,84 @@ func NewRepoGetCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
 	command.Flags().StringVar(&refresh, "refresh", "", "Force a cache refresh on connection status , must be one of: 'hard'")
 	return command
 }
+
+// NewRepoImportBundleCommand returns a new instance of an `argocd repo import-bundle` command
+func NewRepoImportBundleCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var (
+		bundleFile   string
+		repoURL      string
+		bundleUpdate bool
+		project      string
+	)
+	command := &cobra.Command{
+		Use:   "import-bundle",
+		Short: "Import a git bundle into the local copy of a repository kept by the repo server",
+		Example: `
+# Import a git bundle created with 'git bundle create repo.bundle --all'
+argocd repo import-bundle --bundle-file repo.bundle --repo-url https://gitlab.example.com/app
+
+# Apply an incremental bundle created with 'git bundle create update.bundle v1.0.0..main' on top of the imported one
+argocd repo import-bundle --bundle-file update.bundle --repo-url https://gitlab.example.com/app --bundle-update
+`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+
+			if len(args) != 0 || bundleFile == "" || repoURL == "" {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+			bundle, err := os.ReadFile(bundleFile)
+			errors.CheckError(err)
+
+			conn, repoIf := headless.NewClientOrDie(clientOpts, c).NewRepoClientOrDie()
+			defer io.Close(conn)
+			res, err := repoIf.ImportBundle(ctx, &repositorypkg.RepoImportBundleRequest{Repo: repoURL, Bundle: bundle, Update: bundleUpdate, AppProject: project})
+			errors.CheckError(err)
+			for _, ref := range res.Refs {
+				fmt.Println(ref)
+			}
+			fmt.Printf("Bundle '%s' imported for repository '%s'\n", bundleFile, repoURL)
+		},
+	}
+	command.Flags().StringVar(&bundleFile, "bundle-file", "", "Path to the git bundle file")
+	command.Flags().StringVar(&repoURL, "repo-url", "", "URL of the repository the bundle is imported for")
+	command.Flags().BoolVar(&bundleUpdate, "bundle-update", false, "Apply the bundle on top of the previously imported one instead of replacing it")
+	command.Flags().StringVar(&project, "project", "", "project of the repository")
+	return command
+}
+
+// NewRepoExportBundleCommand returns a new instance of an `argocd repo export-bundle` command
+func NewRepoExportBundleCommand(clientOpts *argocdclient.ClientOptions) *cobra.Command {
+	var (
+		bundleFile string
+		repoURL    string
+		project    string
+	)
+	command := &cobra.Command{
+		Use:   "export-bundle",
+		Short: "Export all references of a repository into a git bundle",
+		Example: `
+# Export a repository into a git bundle
+argocd repo export-bundle --repo-url https://gitlab.example.com/app --bundle-file repo.bundle
+`,
+		Run: func(c *cobra.Command, args []string) {
+			ctx := c.Context()
+
+			if len(args) != 0 || bundleFile == "" || repoURL == "" {
+				c.HelpFunc()(c, args)
+				os.Exit(1)
+			}
+
+			conn, repoIf := headless.NewClientOrDie(clientOpts, c).NewRepoClientOrDie()
+			defer io.Close(conn)
+			res, err := repoIf.ExportBundle(ctx, &repositorypkg.RepoQuery{Repo: repoURL, AppProject: project})
+			errors.CheckError(err)
+			errors.CheckError(os.WriteFile(bundleFile, res.Bundle, 0o644))
+			fmt.Printf("Repository '%s' exported to '%s'\n", repoURL, bundleFile)
+		},
+	}
+	command.Flags().StringVar(&bundleFile, "bundle-file", "", "Path to write the git bundle file to")
+	command.Flags().StringVar(&repoURL, "repo-url", "", "URL of the repository to export")
+	command.Flags().StringVar(&project, "project", "", "project of the repository")
+	return command
+}
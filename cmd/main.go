@@ -1,7 +1,10 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
@@ -24,6 +27,7 @@ const (
 
 func main() {
 	var command *cobra.Command
+	isCLI := false
 
 	binaryName := filepath.Base(os.Args[0])
 	if val := os.Getenv(binaryNameEnv); val != "" {
@@ -32,6 +36,7 @@ func main() {
 	switch binaryName {
 	case "argocd", "argocd-linux-amd64", "argocd-darwin-amd64", "argocd-windows-amd64.exe":
 		command = cli.NewCommand()
+		isCLI = true
 	case "argocd-server":
 		command = apiserver.NewCommand()
 	case "argocd-application-controller":
@@ -52,6 +57,20 @@ func main() {
 		command = k8sauth.NewCommand()
 	default:
 		command = cli.NewCommand()
+		isCLI = true
+	}
+
+	if isCLI {
+		if handled, err := cli.HandlePluginCommand(command, os.Args[1:]); handled {
+			var exitErr *exec.ExitError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.ExitCode())
+			} else if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
 	}
 
 	if err := command.Execute(); err != nil {
@@ -2,6 +2,7 @@ package util
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
@@ -17,8 +18,13 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
 	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
 
+	"github.com/argoproj/argo-cd/v2/config/crd/bases"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
 	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/argoproj/argo-cd/v2/util/argo"
@@ -48,12 +54,14 @@ type AppOptions struct {
 	helmVersion                     string
 	helmPassCredentials             bool
 	helmSkipCrds                    bool
+	helmValidateCRs                 bool
 	helmNamespace                   string
 	helmKubeVersion                 string
 	helmApiVersions                 []string
 	project                         string
 	syncPolicy                      string
 	syncOptions                     []string
+	conflictResolution              string
 	autoPrune                       bool
 	selfHeal                        bool
 	allowEmpty                      bool
@@ -86,6 +94,7 @@ type AppOptions struct {
 	retryBackoffMaxDuration         time.Duration
 	retryBackoffFactor              int64
 	ref                             string
+	sourceInlineFile                string
 }
 
 func AddAppFlags(command *cobra.Command, opts *AppOptions) {
@@ -109,12 +118,14 @@ func AddAppFlags(command *cobra.Command, opts *AppOptions) {
 	command.Flags().StringArrayVar(&opts.helmSetStrings, "helm-set-string", []string{}, "Helm set STRING values on the command line (can be repeated to set several values: --helm-set-string key1=val1 --helm-set-string key2=val2)")
 	command.Flags().StringArrayVar(&opts.helmSetFiles, "helm-set-file", []string{}, "Helm set values from respective files specified via the command line (can be repeated to set several values: --helm-set-file key1=path1 --helm-set-file key2=path2)")
 	command.Flags().BoolVar(&opts.helmSkipCrds, "helm-skip-crds", false, "Skip helm crd installation step")
+	command.Flags().BoolVar(&opts.helmValidateCRs, "helm-validate-crs", false, "Validate the custom resources generated by helm against the schemas of the CRDs generated alongside them")
 	command.Flags().StringVar(&opts.helmNamespace, "helm-namespace", "", "Helm namespace to use when running helm template. If not set, use app.spec.destination.namespace")
 	command.Flags().StringVar(&opts.helmKubeVersion, "helm-kube-version", "", "Helm kube-version to use when running helm template. If not set, use the kube version from the destination cluster")
 	command.Flags().StringArrayVar(&opts.helmApiVersions, "helm-api-versions", []string{}, "Helm api-versions (in format [group/]version/kind) to use when running helm template (Can be repeated to set several values: --helm-api-versions traefik.io/v1alpha1/TLSOption --helm-api-versions v1/Service). If not set, use the api-versions from the destination cluster")
 	command.Flags().StringVar(&opts.project, "project", "", "Application project name")
 	command.Flags().StringVar(&opts.syncPolicy, "sync-policy", "", "Set the sync policy (one of: manual (aliases of manual: none), automated (aliases of automated: auto, automatic))")
 	command.Flags().StringArrayVar(&opts.syncOptions, "sync-option", []string{}, "Add or remove a sync option, e.g add `Prune=false`. Remove using `!` prefix, e.g. `!Prune=false`")
+	command.Flags().StringVar(&opts.conflictResolution, "conflict-resolution", "", "Set the resolution of field manager conflicts of server-side applied resources (one of: strict, force, merge). Remove using an empty value")
 	command.Flags().BoolVar(&opts.autoPrune, "auto-prune", false, "Set automatic pruning when sync is automated")
 	command.Flags().BoolVar(&opts.selfHeal, "self-heal", false, "Set self healing when sync is automated")
 	command.Flags().BoolVar(&opts.allowEmpty, "allow-empty", false, "Set allow zero live resources when sync is automated")
@@ -147,6 +158,7 @@ func AddAppFlags(command *cobra.Command, opts *AppOptions) {
 	command.Flags().DurationVar(&opts.retryBackoffMaxDuration, "sync-retry-backoff-max-duration", argoappv1.DefaultSyncRetryMaxDuration, "Max sync retry backoff duration. Input needs to be a duration (e.g. 2m, 1h)")
 	command.Flags().Int64Var(&opts.retryBackoffFactor, "sync-retry-backoff-factor", argoappv1.DefaultSyncRetryFactor, "Factor multiplies the base duration after each failed sync retry")
 	command.Flags().StringVar(&opts.ref, "ref", "", "Ref is reference to another source within sources field")
+	command.Flags().StringVar(&opts.sourceInlineFile, "source-inline-file", "", "File containing YAML manifests which are stored inline in the application instead of a repository")
 }
 
 func SetAppSpecOptions(flags *pflag.FlagSet, spec *argoappv1.ApplicationSpec, appOpts *AppOptions, sourcePosition int) int {
@@ -218,6 +230,19 @@ func SetAppSpecOptions(flags *pflag.FlagSet, spec *argoappv1.ApplicationSpec, ap
 			if spec.SyncPolicy.IsZero() {
 				spec.SyncPolicy = nil
 			}
+		case "conflict-resolution":
+			switch appOpts.conflictResolution {
+			case "", argoappv1.ConflictResolutionStrict, argoappv1.ConflictResolutionForce, argoappv1.ConflictResolutionMerge:
+			default:
+				log.Fatalf("Invalid conflict-resolution: %s", appOpts.conflictResolution)
+			}
+			if spec.SyncPolicy == nil {
+				spec.SyncPolicy = &argoappv1.SyncPolicy{}
+			}
+			spec.SyncPolicy.ConflictResolution = appOpts.conflictResolution
+			if spec.SyncPolicy.IsZero() {
+				spec.SyncPolicy = nil
+			}
 		case "sync-retry-limit":
 			if appOpts.retryLimit > 0 {
 				if spec.SyncPolicy == nil {
@@ -358,6 +383,7 @@ type helmOpts struct {
 	helmSetFiles            []string
 	passCredentials         bool
 	skipCrds                bool
+	validateCRs             bool
 	namespace               string
 	kubeVersion             string
 	apiVersions             []string
@@ -391,6 +417,9 @@ func setHelmOpt(src *argoappv1.ApplicationSource, opts helmOpts) {
 	if opts.skipCrds {
 		src.Helm.SkipCrds = opts.skipCrds
 	}
+	if opts.validateCRs {
+		src.Helm.ValidateCRs = opts.validateCRs
+	}
 	if opts.namespace != "" {
 		src.Helm.Namespace = opts.namespace
 	}
@@ -503,6 +532,50 @@ func readApps(yml []byte, apps *[]*argoappv1.Application) error {
 	return err
 }
 
+// ValidateApps validates the applications of the given YAML or JSON manifests against the Application JSON Schema,
+// without contacting the server. It returns the violations found, prefixed with the name of the application.
+func ValidateApps(manifests []byte) ([]string, error) {
+	var schema spec.Schema
+	if err := json.Unmarshal(bases.ApplicationSchema, &schema); err != nil {
+		return nil, fmt.Errorf("error parsing application schema: %w", err)
+	}
+	validator := validate.NewSchemaValidator(&schema, nil, "", strfmt.Default)
+
+	docs, err := kube.SplitYAMLToString(manifests)
+	if err != nil {
+		return nil, fmt.Errorf("error splitting manifests: %w", err)
+	}
+	var violations []string
+	for i, doc := range docs {
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil {
+			return nil, fmt.Errorf("error parsing manifest %d: %w", i+1, err)
+		}
+		if obj == nil {
+			continue
+		}
+		name := fmt.Sprintf("manifest %d", i+1)
+		if metadata, ok := obj["metadata"].(map[string]interface{}); ok {
+			if n, ok := metadata["name"].(string); ok && n != "" {
+				name = n
+			}
+		}
+		result := validator.Validate(obj)
+		for _, err := range result.Errors {
+			violations = append(violations, fmt.Sprintf("%s: %s", name, err.Error()))
+		}
+		if len(result.Errors) > 0 {
+			continue
+		}
+		// the schema allows unknown fields, which the API server would silently prune, so decode strictly to report them
+		var app argoappv1.Application
+		if err := yaml.UnmarshalStrict([]byte(doc), &app); err != nil {
+			violations = append(violations, fmt.Sprintf("%s: %s", name, err.Error()))
+		}
+	}
+	return violations, nil
+}
+
 func readAppsFromStdin(apps *[]*argoappv1.Application) error {
 	reader := bufio.NewReader(os.Stdin)
 	data, err := io.ReadAll(reader)
@@ -658,6 +731,8 @@ func ConstructSource(source *argoappv1.ApplicationSource, appOpts AppOptions, fl
 			setHelmOpt(source, helmOpts{helmSetFiles: appOpts.helmSetFiles})
 		case "helm-skip-crds":
 			setHelmOpt(source, helmOpts{skipCrds: appOpts.helmSkipCrds})
+		case "helm-validate-crs":
+			setHelmOpt(source, helmOpts{validateCRs: appOpts.helmValidateCRs})
 		case "helm-namespace":
 			setHelmOpt(source, helmOpts{namespace: appOpts.helmNamespace})
 		case "helm-kube-version":
@@ -728,6 +803,16 @@ func ConstructSource(source *argoappv1.ApplicationSource, appOpts AppOptions, fl
 			setPluginOptEnvs(source, appOpts.pluginEnvs)
 		case "ref":
 			source.Ref = appOpts.ref
+		case "source-inline-file":
+			data, err := os.ReadFile(appOpts.sourceInlineFile)
+			errors.CheckError(err)
+			_, err = kube.SplitYAML(data)
+			errors.CheckError(err)
+			source.Inline = &argoappv1.ApplicationSourceInline{Manifests: string(data)}
+			// the repository URL of an inline source only identifies it and is never contacted
+			if source.RepoURL == "" {
+				source.RepoURL = argoappv1.InlineSourceRepoURL
+			}
 		}
 	})
 	return source, visited
@@ -2,6 +2,8 @@ package util
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	log "github.com/sirupsen/logrus"
@@ -246,6 +248,14 @@ func Test_setAppSpecOptions(t *testing.T) {
 		require.NoError(t, f.SetFlag("sync-option", "!a=1"))
 		assert.Nil(t, f.spec.SyncPolicy)
 	})
+	t.Run("ConflictResolution", func(t *testing.T) {
+		require.NoError(t, f.SetFlag("conflict-resolution", "merge"))
+		assert.Equal(t, v1alpha1.ConflictResolutionMerge, f.spec.SyncPolicy.ConflictResolution)
+
+		// remove the conflict resolution using an empty value
+		require.NoError(t, f.SetFlag("conflict-resolution", ""))
+		assert.Nil(t, f.spec.SyncPolicy)
+	})
 	t.Run("RetryLimit", func(t *testing.T) {
 		require.NoError(t, f.SetFlag("sync-retry-limit", "5"))
 		assert.Equal(t, int64(5), f.spec.SyncPolicy.Retry.Limit)
@@ -284,6 +294,14 @@ func Test_setAppSpecOptions(t *testing.T) {
 		require.NoError(t, f.SetFlag("helm-api-versions", "v2"))
 		assert.Equal(t, []string{"v1", "v2"}, f.spec.Source.Helm.APIVersions)
 	})
+	t.Run("Source Inline File", func(t *testing.T) {
+		file := filepath.Join(t.TempDir(), "manifests.yaml")
+		manifests := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n"
+		require.NoError(t, os.WriteFile(file, []byte(manifests), 0o600))
+		require.NoError(t, f.SetFlag("source-inline-file", file))
+		assert.Equal(t, manifests, f.spec.Source.Inline.Manifests)
+		assert.Equal(t, v1alpha1.InlineSourceRepoURL, f.spec.Source.RepoURL)
+	})
 }
 
 func newMultiSourceAppOptionsFixture() *appOptionsFixture {
@@ -416,6 +434,90 @@ func TestReadAppsFromURI(t *testing.T) {
 	assert.Equal(t, "sth2", apps[1].Name)
 }
 
+const validAppYaml = `
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: guestbook
+spec:
+  project: default
+  destination:
+    server: https://kubernetes.default.svc
+    namespace: guestbook
+  source:
+    repoURL: https://github.com/argoproj/argocd-example-apps.git
+    path: guestbook
+  syncPolicy:
+    retry:
+      limit: 2
+`
+
+func TestValidateApps(t *testing.T) {
+	t.Run("Valid", func(t *testing.T) {
+		violations, err := ValidateApps([]byte(validAppYaml))
+		require.NoError(t, err)
+		assert.Empty(t, violations)
+	})
+
+	t.Run("StatusSourceWithoutRepoURL", func(t *testing.T) {
+		// the sources recorded in the status of multi-source applications do not always hold a repository URL
+		manifest := validAppYaml + "status:\n  sync:\n    status: Synced\n    comparedTo:\n      destination: {}\n      sources:\n      - repoURL: \"\"\n  history:\n  - id: 0\n    deployedAt: \"2024-01-01T00:00:00Z\"\n    sources:\n    - repoURL: \"\"\n"
+		violations, err := ValidateApps([]byte(manifest))
+		require.NoError(t, err)
+		assert.Empty(t, violations)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for name, tc := range map[string]struct {
+			manifest string
+			expected string
+		}{
+			"MissingDestination": {
+				manifest: "apiVersion: argoproj.io/v1alpha1\nkind: Application\nmetadata:\n  name: guestbook\nspec:\n  project: default\n",
+				expected: "guestbook: spec.destination in body is required",
+			},
+			"EmptyRepoURL": {
+				manifest: strings.Replace(validAppYaml, "https://github.com/argoproj/argocd-example-apps.git", `""`, 1),
+				expected: "guestbook: spec.source.repoURL in body should be at least 1 chars long",
+			},
+			"EmptySourcesRepoURL": {
+				manifest: strings.Replace(validAppYaml, "  source:\n    repoURL: https://github.com/argoproj/argocd-example-apps.git", "  sources:\n  - repoURL: \"\"", 1),
+				expected: "guestbook: spec.sources[0].repoURL in body should be at least 1 chars long",
+			},
+			"InvalidRetryLimit": {
+				manifest: strings.Replace(validAppYaml, "limit: 2", "limit: two", 1),
+				expected: `guestbook: spec.syncPolicy.retry.limit in body must be of type integer: "string"`,
+			},
+			"InvalidBackoffFactor": {
+				manifest: strings.Replace(validAppYaml, "limit: 2", "limit: 2\n      backoff:\n        factor: 0", 1),
+				expected: "guestbook: spec.syncPolicy.retry.backoff.factor in body should be greater than or equal to 1",
+			},
+			"WrongKind": {
+				manifest: strings.Replace(validAppYaml, "kind: Application", "kind: AppProject", 1),
+				expected: "guestbook: kind in body should be one of [Application]",
+			},
+			"UnknownField": {
+				manifest: strings.Replace(validAppYaml, "project: default", "project: default\n  destinaton: {}", 1),
+				expected: `guestbook: error unmarshaling JSON: while decoding JSON: json: unknown field "destinaton"`,
+			},
+		} {
+			t.Run(name, func(t *testing.T) {
+				violations, err := ValidateApps([]byte(tc.manifest))
+				require.NoError(t, err)
+				assert.Equal(t, []string{tc.expected}, violations)
+			})
+		}
+	})
+
+	t.Run("MultipleDocuments", func(t *testing.T) {
+		invalid := strings.Replace(validAppYaml, "name: guestbook", "name: invalid", 1)
+		invalid = strings.Replace(invalid, "limit: 2", "limit: two", 1)
+		violations, err := ValidateApps([]byte(validAppYaml + "---" + invalid))
+		require.NoError(t, err)
+		assert.Equal(t, []string{`invalid: spec.syncPolicy.retry.limit in body must be of type integer: "string"`}, violations)
+	})
+}
+
 func TestConstructAppFromStdin(t *testing.T) {
 	file, err := os.CreateTemp(os.TempDir(), "")
 	if err != nil {
@@ -47,6 +47,18 @@ const (
 	// ArgoCDAppControllerShardConfigMapName contains the application controller to shard mapping
 	ArgoCDAppControllerShardConfigMapName = "argocd-app-controller-shard-cm"
 	ArgoCDCmdParamsConfigMapName          = "argocd-cmd-params-cm"
+	// ArgoCDSyncWindowOverridesConfigMapName contains the audit records of the syncs overriding sync windows
+	ArgoCDSyncWindowOverridesConfigMapName = "argocd-sync-window-overrides"
+	// ArgoCDAppMutationsConfigMapName contains the mutation rules applied to applications by the mutating admission webhook
+	ArgoCDAppMutationsConfigMapName = "argocd-app-mutations"
+	// ArgoCDAppPoliciesConfigMapName contains the Rego policies applications are validated against
+	ArgoCDAppPoliciesConfigMapName = "argocd-app-policies"
+	// ArgoCDHealthOverridesConfigMapName contains the Lua health checks taking precedence over all other health checks
+	ArgoCDHealthOverridesConfigMapName = "argocd-health-overrides"
+	// ArgoCDDiffPluginsConfigMapName contains the names of the diff plugins replacing the default diff of resource kinds
+	ArgoCDDiffPluginsConfigMapName = "argocd-diff-plugins"
+	// ArgoCDResourceAnnotationsConfigMapName contains the templates of the annotations injected into the resources applied by syncs
+	ArgoCDResourceAnnotationsConfigMapName = "argocd-resource-annotations"
 )
 
 // Some default configurables
@@ -62,6 +74,7 @@ const (
 	DefaultPortArgoCDMetrics          = 8082
 	DefaultPortArgoCDAPIServerMetrics = 8083
 	DefaultPortRepoServerMetrics      = 8084
+	DefaultPortAPIServerWebhook       = 8443
 )
 
 // DefaultAddressAPIServer for ArgoCD components
@@ -175,6 +188,16 @@ const (
 	LabelValueSecretTypeRepository = "repository"
 	// LabelValueSecretTypeRepoCreds indicates a secret type of repository credentials
 	LabelValueSecretTypeRepoCreds = "repo-creds"
+	// LabelValueSecretTypeRepositorySource indicates a secret holding values which RepositoryConfig resources may reference
+	LabelValueSecretTypeRepositorySource = "repository-source"
+	// LabelKeyRepositoryConfig contains the name of the RepositoryConfig a repository secret is reconciled from
+	LabelKeyRepositoryConfig = "argocd.argoproj.io/repository-config"
+	// LabelKeyClusterDiscovery marks the secrets holding the credentials of a cluster to register automatically
+	LabelKeyClusterDiscovery = "argocd.argoproj.io/cluster-discovery"
+	// AnnotationKeyAutoDiscovered marks the clusters registered from a cluster discovery secret
+	AnnotationKeyAutoDiscovered = "argocd.argoproj.io/auto-discovered"
+	// AnnotationKeyDiscoverySecret contains the namespace and name of the cluster discovery secret a cluster is registered from
+	AnnotationKeyDiscoverySecret = "argocd.argoproj.io/discovery-secret"
 
 	// AnnotationKeyAppInstance is the Argo CD application name is used as the instance name
 	AnnotationKeyAppInstance = "argocd.argoproj.io/tracking-id"
@@ -182,6 +205,24 @@ const (
 	// AnnotationCompareOptions is a comma-separated list of options for comparison
 	AnnotationCompareOptions = "argocd.argoproj.io/compare-options"
 
+	// AnnotationIgnoreDiff is a comma-separated or JSON array formatted list of paths, e.g. spec.replicas, whose
+	// differences are ignored when comparing the annotated live resource
+	AnnotationIgnoreDiff = "argocd.argoproj.io/ignore-diff"
+
+	// AnnotationHealthOverride overrides the health computed for a resource. One of: Healthy|Progressing|Degraded|Suspended
+	AnnotationHealthOverride = "argocd.argoproj.io/health-override"
+
+	// AnnotationExcludeResource makes the application controller treat the annotated live resource as unmanaged when set
+	// to "true", even if it is part of the desired state of the application
+	AnnotationExcludeResource = "argocd.argoproj.io/exclude-resource"
+
+	// AnnotationIncludeResource makes the application controller manage the annotated resource of the desired state when
+	// set to "true", even if its kind is excluded by the resource.exclusions setting
+	AnnotationIncludeResource = "argocd.argoproj.io/include-resource"
+
+	// AnnotationVaultPath is the Vault path the username and password of a repository secret are read from
+	AnnotationVaultPath = "argocd.argoproj.io/vault-path"
+
 	// AnnotationKeyManagedBy is annotation name which indicates that k8s resource is managed by an application.
 	AnnotationKeyManagedBy = "managed-by"
 	// AnnotationValueManagedByArgoCD is a 'managed-by' annotation value for resources managed by Argo CD
@@ -200,6 +241,24 @@ const (
 	// AnnotationKeyAppSkipReconcile tells the Application to skip the Application controller reconcile.
 	// Skip reconcile when the value is "true" or any other string values that can be strconv.ParseBool() to be true.
 	AnnotationKeyAppSkipReconcile = "argocd.argoproj.io/skip-reconcile"
+	// AnnotationKeyAppSkipReconcileUntil tells the Application to skip the Application controller reconcile until the
+	// given time in RFC 3339 format, e.g. for the duration of a maintenance window.
+	AnnotationKeyAppSkipReconcileUntil = "argocd.argoproj.io/skip-reconcile-until"
+	// AnnotationKeyAllowDestinationChange permits changing the destination or project of an Application protected by the
+	// destination change admission webhook, e.g. for an intentional migration
+	AnnotationKeyAllowDestinationChange = "argocd.argoproj.io/allow-destination-change"
+	// AnnotationKeyDeletionProtected protects an ApplicationSet, and thereby the Applications it generates, from being
+	// deleted while it is set to "true"
+	AnnotationKeyDeletionProtected = "argocd.argoproj.io/deletion-protected"
+	// AnnotationKeyGitOpsInventory makes the Application controller read the resources managed by an application from
+	// the inventory kept by another GitOps tool, e.g. "flux", instead of the resources tracked by Argo CD
+	AnnotationKeyGitOpsInventory = "argocd.argoproj.io/gitops-inventory"
+	// AnnotationKeyGitOpsInventoryRef references the object holding the inventory of an application as <namespace>/<name>.
+	// Defaults to the name of the application in the default namespace of the GitOps tool.
+	AnnotationKeyGitOpsInventoryRef = "argocd.argoproj.io/gitops-inventory-ref"
+	// LabelKeyBootstrapApp identifies the application created by the API server to manage the configuration of Argo CD.
+	// The application never syncs its own Application resource.
+	LabelKeyBootstrapApp = "argocd.argoproj.io/bootstrap"
 	// LabelKeyComponentRepoServer is the label key to identify the component as repo-server
 	LabelKeyComponentRepoServer = "app.kubernetes.io/component"
 	// LabelValueComponentRepoServer is the label value for the repo-server component
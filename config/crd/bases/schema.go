@@ -0,0 +1,9 @@
+// Package bases contains the JSON Schemas generated from the custom resource definitions of Argo CD
+package bases
+
+import _ "embed"
+
+// ApplicationSchema is the JSON Schema of the Application resource, generated by `make generate-app-schema`
+//
+//go:embed application.schema.json
+var ApplicationSchema []byte
@@ -44,6 +44,7 @@ import (
 
 	"github.com/argoproj/argo-cd/v2/common"
 	statecache "github.com/argoproj/argo-cd/v2/controller/cache"
+	"github.com/argoproj/argo-cd/v2/controller/integrations"
 	"github.com/argoproj/argo-cd/v2/controller/metrics"
 	"github.com/argoproj/argo-cd/v2/controller/sharding"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
@@ -61,6 +62,7 @@ import (
 	kubeerrors "k8s.io/apimachinery/pkg/api/errors"
 
 	"github.com/argoproj/argo-cd/v2/pkg/ratelimiter"
+	cacheutil "github.com/argoproj/argo-cd/v2/util/cache"
 	appstatecache "github.com/argoproj/argo-cd/v2/util/cache/appstate"
 	"github.com/argoproj/argo-cd/v2/util/db"
 	"github.com/argoproj/argo-cd/v2/util/errors"
@@ -141,6 +143,53 @@ type ApplicationController struct {
 	projByNameCache               sync.Map
 	applicationNamespaces         []string
 	ignoreNormalizerOpts          normalizers.IgnoreNormalizerOpts
+	webhookNotifier               *WebhookNotifier
+	postSyncWebhookQueue          chan postSyncWebhookRequest
+	artifactStorer                *ArtifactStorer
+	datadogNotifier               *integrations.DatadogNotifier
+	clusterHealthAggregator       *ClusterHealthAggregator
+	// projectResourceUsage aggregates the resources managed by the applications of each project
+	projectResourceUsage *projectResourceUsage
+	// healthTimelineRetention is the duration the health changes of application resources are kept, zero disables recording them
+	healthTimelineRetention time.Duration
+	// deletionTimeoutPerResource is the duration after which resources whose deletion is pending are force deleted
+	// during cascaded deletions, zero disables force deletion
+	deletionTimeoutPerResource time.Duration
+	// globalSyncTimeout is the duration after which syncs fail if they did not complete, unless the application sets
+	// its own sync timeout, zero disables the timeout
+	globalSyncTimeout time.Duration
+	// driftDigestJob periodically emails the applications which are not in sync, if enabled in the notifications
+	// ConfigMap
+	driftDigestJob *DriftDigestJob
+	// historyPruneJob periodically applies the history retention policies of the projects to their applications
+	historyPruneJob *HistoryPruneJob
+	// appOperationRetryQueue is the queue underlying appOperationQueue, which hands out the apps whose last operation
+	// failed or timed out first
+	appOperationRetryQueue *RetryPriorityQueue
+	// compressInformerCache makes the application informer store the applications compressed, to reduce memory usage
+	compressInformerCache bool
+	// webhookConfirmations are the deliveries of succeeded sync operations to post-sync webhooks which must confirm
+	// them, by application key
+	webhookConfirmations sync.Map
+	// webhookConfirmationTimeout is the maximum duration a succeeded sync operation waits for the confirmation of
+	// the post-sync webhook
+	webhookConfirmationTimeout time.Duration
+	// reconcilePanicBackoff delays the reconciliation of the applications whose reconciliation panicked
+	reconcilePanicBackoff *reconcilePanicBackoff
+	// eventFilter debounces the events of the resources watched in the clusters, nil if the filter is disabled
+	eventFilter *debounceMap
+	// workStealingQueue shares the applications waiting in the app refresh queue with the other shards, nil if work
+	// stealing is disabled
+	workStealingQueue *sharding.WorkStealingQueue
+	// stolenApps are the applications stolen from other shards which are waiting for their reconciliation
+	stolenApps *stolenApps
+	// fanOutDestinations are the servers of the additional destination clusters of the applications of the shard
+	fanOutDestinations *fanOutDestinations
+	// clusterDisconnectGracePeriod is the duration during which the applications keep their last known status while
+	// their cluster is unreachable, 0 if the status is lost as soon as the cluster is unreachable
+	clusterDisconnectGracePeriod time.Duration
+	// queueHealthMonitor detects an app refresh queue which is stuck, e.g. because all the processors are deadlocked
+	queueHealthMonitor *QueueHealthMonitor
 
 	// dynamicClusterDistributionEnabled if disabled deploymentInformer is never initialized
 	dynamicClusterDistributionEnabled bool
@@ -164,6 +213,7 @@ func NewApplicationController(
 	metricsPort int,
 	metricsCacheExpiration time.Duration,
 	metricsApplicationLabels []string,
+	metricsSamplingRatio float64,
 	kubectlParallelismLimit int64,
 	persistResourceHealth bool,
 	clusterSharding sharding.ClusterShardingCache,
@@ -172,6 +222,25 @@ func NewApplicationController(
 	serverSideDiff bool,
 	dynamicClusterDistributionEnabled bool,
 	ignoreNormalizerOpts normalizers.IgnoreNormalizerOpts,
+	postSyncWebhookAllowedURLs []string,
+	defaultHealthForUnknownResources health.HealthStatusCode,
+	disableHealthOverrides bool,
+	healthTimelineRetention time.Duration,
+	defaultApplyRateLimit float64,
+	deletionTimeoutPerResource time.Duration,
+	globalSyncTimeout time.Duration,
+	driftDigestSchedule string,
+	compressInformerCache bool,
+	eventDedupWindow time.Duration,
+	syncSnapshotRetention time.Duration,
+	defaultResourceApplyTimeout time.Duration,
+	enableSyncCheckpoints bool,
+	webhookConfirmationTimeout time.Duration,
+	eventFilterInterval time.Duration,
+	workStealingQueue *sharding.WorkStealingQueue,
+	clusterDisconnectGracePeriod time.Duration,
+	queueHealthCheckWindow time.Duration,
+	disableResourceAnnotationTemplates bool,
 ) (*ApplicationController, error) {
 	log.Infof("appResyncPeriod=%v, appHardResyncPeriod=%v, appResyncJitter=%v", appResyncPeriod, appHardResyncPeriod, appResyncJitter)
 	db := db.NewDB(namespace, settingsMgr, kubeClientset)
@@ -179,6 +248,10 @@ func NewApplicationController(
 		rateLimiterConfig = ratelimiter.GetDefaultAppRateLimiterConfig()
 		log.Info("Using default workqueue rate limiter config")
 	}
+	appOperationRetryQueue := NewRetryPriorityQueue()
+	appOperationQueue := workqueue.NewRateLimitingQueueWithConfig(ratelimiter.NewCustomAppControllerRateLimiter(rateLimiterConfig), workqueue.RateLimitingQueueConfig{
+		DelayingQueue: workqueue.NewDelayingQueueWithConfig(workqueue.DelayingQueueConfig{Name: "app_operation_processing_queue", Queue: appOperationRetryQueue}),
+	})
 	ctrl := ApplicationController{
 		cache:                             argoCache,
 		namespace:                         namespace,
@@ -187,7 +260,8 @@ func NewApplicationController(
 		applicationClientset:              applicationClientset,
 		repoClientset:                     repoClientset,
 		appRefreshQueue:                   workqueue.NewRateLimitingQueueWithConfig(ratelimiter.NewCustomAppControllerRateLimiter(rateLimiterConfig), workqueue.RateLimitingQueueConfig{Name: "app_reconciliation_queue"}),
-		appOperationQueue:                 workqueue.NewRateLimitingQueueWithConfig(ratelimiter.NewCustomAppControllerRateLimiter(rateLimiterConfig), workqueue.RateLimitingQueueConfig{Name: "app_operation_processing_queue"}),
+		appOperationQueue:                 appOperationQueue,
+		appOperationRetryQueue:            appOperationRetryQueue,
 		projectRefreshQueue:               workqueue.NewRateLimitingQueueWithConfig(ratelimiter.NewCustomAppControllerRateLimiter(rateLimiterConfig), workqueue.RateLimitingQueueConfig{Name: "project_reconciliation_queue"}),
 		appComparisonTypeRefreshQueue:     workqueue.NewRateLimitingQueue(ratelimiter.NewCustomAppControllerRateLimiter(rateLimiterConfig)),
 		db:                                db,
@@ -204,7 +278,19 @@ func NewApplicationController(
 		applicationNamespaces:             applicationNamespaces,
 		dynamicClusterDistributionEnabled: dynamicClusterDistributionEnabled,
 		ignoreNormalizerOpts:              ignoreNormalizerOpts,
-	}
+		webhookNotifier:                   NewWebhookNotifier(&http.Client{Timeout: postSyncWebhookTimeout}, postSyncWebhookBackoff, postSyncWebhookAllowedURLs),
+		postSyncWebhookQueue:              make(chan postSyncWebhookRequest, postSyncWebhookQueueSize),
+		webhookConfirmationTimeout:        webhookConfirmationTimeout,
+		artifactStorer:                    NewArtifactStorer(kubeClientset, namespace),
+		projectResourceUsage:              newProjectResourceUsage(),
+		healthTimelineRetention:           healthTimelineRetention,
+		deletionTimeoutPerResource:        deletionTimeoutPerResource,
+		globalSyncTimeout:                 globalSyncTimeout,
+		compressInformerCache:             compressInformerCache,
+		reconcilePanicBackoff:             newReconcilePanicBackoff(reconcilePanicBaseDelay, reconcilePanicMaxDelay),
+		clusterDisconnectGracePeriod:      clusterDisconnectGracePeriod,
+	}
+	ctrl.queueHealthMonitor = NewQueueHealthMonitor(ctrl.appRefreshQueue.Len, queueHealthCheckWindow)
 	if kubectlParallelismLimit > 0 {
 		ctrl.kubectlSemaphore = semaphore.NewWeighted(kubectlParallelismLimit)
 	}
@@ -289,11 +375,40 @@ func NewApplicationController(
 			return nil, err
 		}
 	}
-	stateCache := statecache.NewLiveStateCache(db, appInformer, ctrl.settingsMgr, kubectl, ctrl.metricsServer, ctrl.handleObjectUpdated, clusterSharding, argo.NewResourceTracking())
-	appStateManager := NewAppStateManager(db, applicationClientset, repoClientset, namespace, kubectl, ctrl.settingsMgr, stateCache, projInformer, ctrl.metricsServer, argoCache, ctrl.statusRefreshTimeout, argo.NewResourceTracking(), persistResourceHealth, repoErrorGracePeriod, serverSideDiff, ignoreNormalizerOpts)
+	if err := ctrl.metricsServer.SetSamplingRatio(metricsSamplingRatio); err != nil {
+		return nil, err
+	}
+	if eventDedupWindow > 0 {
+		ctrl.auditLogger.EnableEventDeduplication(eventDedupWindow, ctrl.metricsServer.IncEventsDeduplicated)
+	}
+	ctrl.datadogNotifier = integrations.NewDatadogNotifier(kubeClientset, namespace, ctrl.metricsServer)
+	ctrl.clusterHealthAggregator = NewClusterHealthAggregator(appLister, ctrl.canProcessApp)
+	if eventFilterInterval > 0 {
+		ctrl.eventFilter = newDebounceMap(eventFilterInterval, ctrl.handleObjectUpdated, ctrl.metricsServer.IncEventsFiltered)
+	}
+	if workStealingQueue != nil {
+		ctrl.workStealingQueue = workStealingQueue
+		ctrl.stolenApps = &stolenApps{servers: make(map[string]string)}
+		ctrl.appRefreshQueue = &workStealingRefreshQueue{RateLimitingInterface: ctrl.appRefreshQueue, queue: workStealingQueue}
+		workStealingQueue.OnStolen(ctrl.incWorkStolen)
+		// the live state cache also handles the destination clusters of the stolen applications
+		clusterSharding = &borrowingClusterSharding{ClusterShardingCache: clusterSharding, stolenApps: ctrl.stolenApps}
+	}
+	// the live state cache also handles the additional destination clusters of the applications of the shard
+	ctrl.fanOutDestinations = &fanOutDestinations{servers: make(map[string][]string)}
+	clusterSharding = &fanOutClusterSharding{ClusterShardingCache: clusterSharding, destinations: ctrl.fanOutDestinations}
+	stateCache := statecache.NewLiveStateCache(db, appInformer, ctrl.settingsMgr, kubectl, ctrl.metricsServer, ctrl.handleObjectEvent, ctrl.handleResourceHealthChanged, clusterSharding, argo.NewResourceTracking(), disableHealthOverrides)
+	appStateManager := NewAppStateManager(db, applicationClientset, repoClientset, namespace, kubectl, ctrl.settingsMgr, stateCache, projInformer, ctrl.metricsServer, argoCache, ctrl.statusRefreshTimeout, argo.NewResourceTracking(), persistResourceHealth, repoErrorGracePeriod, serverSideDiff, ignoreNormalizerOpts, defaultHealthForUnknownResources, disableHealthOverrides, ctrl.projectResourceUsage, ctrl.auditLogger, newApplyRateLimiters(defaultApplyRateLimit), ctrl.artifactStorer, globalSyncTimeout, syncSnapshotRetention, defaultResourceApplyTimeout, enableSyncCheckpoints, disableResourceAnnotationTemplates)
 	ctrl.appInformer = appInformer
 	ctrl.appLister = appLister
+	ctrl.driftDigestJob, err = NewDriftDigestJob(driftDigestSchedule, appLister, ctrl.canProcessApp, kubeClientset, namespace)
+	if err != nil {
+		return nil, err
+	}
 	ctrl.projInformer = projInformer
+	ctrl.historyPruneJob = NewHistoryPruneJob(
+		env.ParseDurationFromEnv(EnvVarHistoryPruneInterval, DefaultHistoryPruneInterval, 0, math.MaxInt64),
+		appLister, applisters.NewAppProjectLister(projInformer.GetIndexer()), ctrl.canProcessApp, applicationClientset, ctrl.metricsServer, namespace)
 	ctrl.deploymentInformer = deploymentInformer
 	ctrl.appStateManager = appStateManager
 	ctrl.stateCache = stateCache
@@ -389,6 +504,17 @@ func (ctrl *ApplicationController) getAppProj(app *appv1.Application) (*appv1.Ap
 	return proj, nil
 }
 
+// handleResourceHealthChanged records the health change of an application resource in its health timeline
+func (ctrl *ApplicationController) handleResourceHealthChanged(appName string, key kube.ResourceKey, resHealth *health.HealthStatus) {
+	if ctrl.healthTimelineRetention <= 0 {
+		return
+	}
+	event := appstatecache.ResourceHealthEvent{Timestamp: time.Now().UTC(), Status: resHealth.Status, Message: resHealth.Message}
+	if err := ctrl.cache.AddResourceHealthEvent(appName, key, event, ctrl.healthTimelineRetention); err != nil {
+		log.WithField("application", appName).Warnf("Failed to record health change of resource %s: %v", key.String(), err)
+	}
+}
+
 func (ctrl *ApplicationController) handleObjectUpdated(managedByApp map[string]bool, ref v1.ObjectReference) {
 	// if namespaced resource is not managed by any app it might be orphaned resource of some other apps
 	if len(managedByApp) == 0 && ref.Namespace != "" {
@@ -451,6 +577,27 @@ func (ctrl *ApplicationController) handleObjectUpdated(managedByApp map[string]b
 	}
 }
 
+// setAppResourceCount updates the metrics of the number of desired and live resources of the application. The counts
+// are kept unchanged if the live state could not be loaded, which would otherwise look like all resources are missing.
+func (ctrl *ApplicationController) setAppResourceCount(a *appv1.Application, comparisonResult *comparisonResult) {
+	if comparisonResult.syncStatus == nil || comparisonResult.syncStatus.Status == appv1.SyncStatusCodeUnknown {
+		return
+	}
+	desired, live := 0, 0
+	for _, res := range comparisonResult.managedResources {
+		if res.Hook {
+			continue
+		}
+		if res.Target != nil {
+			desired++
+		}
+		if res.Live != nil {
+			live++
+		}
+	}
+	ctrl.metricsServer.SetAppResourceCount(a, desired, live)
+}
+
 // setAppManagedResources will build a list of ResourceDiff based on the provided comparisonResult
 // and persist app resources related data in the cache. Will return the persisted ApplicationTree.
 func (ctrl *ApplicationController) setAppManagedResources(a *appv1.Application, comparisonResult *comparisonResult) (*appv1.ApplicationTree, error) {
@@ -593,7 +740,8 @@ func (ctrl *ApplicationController) getResourceTree(a *appv1.Application, managed
 	}
 	err = ctrl.stateCache.IterateHierarchyV2(a.Spec.Destination.Server, orphanedNodesKeys, func(child appv1.ResourceNode, appName string) bool {
 		belongToAnotherApp := false
-		if appName != "" {
+		// the resources tracked by the application itself are orphaned when they are annotated to be unmanaged
+		if appName != "" && appName != a.InstanceName(ctrl.namespace) {
 			appKey := ctrl.toAppKey(appName)
 			if _, exists, err := ctrl.appInformer.GetIndexer().GetByKey(appKey); exists && err == nil {
 				belongToAnotherApp = true
@@ -626,6 +774,11 @@ func (ctrl *ApplicationController) getResourceTree(a *appv1.Application, managed
 		}}
 	}
 	a.Status.SetConditions(conditions, map[appv1.ApplicationConditionType]bool{appv1.ApplicationConditionOrphanedResourceWarning: true})
+	if proj.Spec.OrphanedResources != nil {
+		ctrl.metricsServer.SetOrphanedResourcesCount(a, len(orphanedNodes))
+	} else {
+		ctrl.metricsServer.DeleteOrphanedResourcesCount(a)
+	}
 	sort.Slice(orphanedNodes, func(i, j int) bool {
 		return orphanedNodes[i].ResourceRef.String() < orphanedNodes[j].ResourceRef.String()
 	})
@@ -776,6 +929,10 @@ func (ctrl *ApplicationController) hideSecretData(app *appv1.Application, compar
 			if err != nil {
 				return nil, fmt.Errorf("error getting tracking method: %w", err)
 			}
+			diffPlugins, err := ctrl.settingsMgr.GetDiffPlugins()
+			if err != nil {
+				return nil, fmt.Errorf("error getting diff plugins: %w", err)
+			}
 
 			clusterCache, err := ctrl.stateCache.GetClusterCache(app.Spec.Destination.Server)
 			if err != nil {
@@ -784,6 +941,7 @@ func (ctrl *ApplicationController) hideSecretData(app *appv1.Application, compar
 			diffConfig, err := argodiff.NewDiffConfigBuilder().
 				WithDiffSettings(app.Spec.IgnoreDifferences, resourceOverrides, compareOptions.IgnoreAggregatedRoles, ctrl.ignoreNormalizerOpts).
 				WithTracking(appLabelKey, trackingMethod).
+				WithDiffPlugins(diffPlugins).
 				WithNoCache().
 				WithLogger(logutils.NewLogrusLogger(logutils.NewWithCurrentConfig())).
 				WithGVKParser(clusterCache.GetGVKParser()).
@@ -836,7 +994,15 @@ func (ctrl *ApplicationController) Run(ctx context.Context, statusProcessors int
 	defer ctrl.projectRefreshQueue.ShutDown()
 
 	ctrl.metricsServer.RegisterClustersInfoSource(ctx, ctrl.stateCache)
+	ctrl.metricsServer.RegisterRetryQueueDepth(ctrl.appOperationRetryQueue.RetryLen)
+	ctrl.metricsServer.RegisterQueueHealthCheck(ctrl.queueHealthMonitor.Check)
+	ctrl.metricsServer.RegisterClusterAppHealth(ctrl.clusterHealthAggregator)
+	go ctrl.clusterHealthAggregator.Run(ctx, clusterHealthResyncInterval)
+	if interval := env.ParseDurationFromEnv(metrics.EnvVarImageUpdateCheckInterval, metrics.DefaultImageUpdateCheckInterval, 0, math.MaxInt64); interval > 0 {
+		ctrl.metricsServer.RegisterImageUpdateCollector(ctx, metrics.NewRegistryTagLister(), interval)
+	}
 	ctrl.RegisterClusterSecretUpdater(ctx)
+	go ctrl.auditLogger.RunEventDeduplicationFlush(ctx)
 
 	go ctrl.appInformer.Run(ctx.Done())
 	go ctrl.projInformer.Run(ctx.Done())
@@ -882,19 +1048,50 @@ func (ctrl *ApplicationController) Run(ctx context.Context, statusProcessors int
 			}
 		}, time.Second, ctx.Done())
 	}
+	go ctrl.queueHealthMonitor.Run(ctx, queueHealthSampleInterval)
 
 	go wait.Until(func() {
 		for ctrl.processAppComparisonTypeQueueItem() {
 		}
 	}, time.Second, ctx.Done())
 
+	if ctrl.workStealingQueue != nil {
+		go wait.Until(func() { ctrl.stealWork(ctx) }, workStealInterval, ctx.Done())
+	}
+
 	go wait.Until(func() {
 		for ctrl.processProjectQueueItem() {
 		}
 	}, time.Second, ctx.Done())
+
+	for i := 0; i < postSyncWebhookWorkers; i++ {
+		go ctrl.runPostSyncWebhookWorker(ctx)
+	}
+	for i := 0; i < artifactStorageWorkers; i++ {
+		go ctrl.artifactStorer.runWorker(ctx)
+	}
+	for i := 0; i < integrations.DatadogWorkers; i++ {
+		go ctrl.datadogNotifier.RunWorker(ctx)
+	}
+	go ctrl.driftDigestJob.Run(ctx)
+	go ctrl.historyPruneJob.Run(ctx)
 	<-ctx.Done()
 }
 
+// clusterDisconnectGraceRemaining returns the remaining duration of the grace period of the given cluster, and false if
+// the cluster is reachable or has been unreachable for longer than the grace period
+func (ctrl *ApplicationController) clusterDisconnectGraceRemaining(server string) (time.Duration, bool) {
+	if ctrl.clusterDisconnectGracePeriod <= 0 {
+		return 0, false
+	}
+	disconnectedSince, ok := ctrl.stateCache.GetClusterDisconnectedSince(server)
+	if !ok {
+		return 0, false
+	}
+	remaining := ctrl.clusterDisconnectGracePeriod - time.Since(disconnectedSince)
+	return remaining, remaining > 0
+}
+
 // requestAppRefresh adds a request for given app to the refresh queue. appName
 // needs to be the qualified name of the application, i.e. <namespace>/<name>.
 func (ctrl *ApplicationController) requestAppRefresh(appName string, compareWith *CompareWith, after *time.Duration) {
@@ -954,6 +1151,10 @@ func (ctrl *ApplicationController) processAppOperationQueueItem() (processNext b
 		log.Warnf("Key '%s' in index is not an application", appKey)
 		return
 	}
+	if skip, _ := getSkipReconcile(origApp, time.Now()); skip {
+		// the skip is surfaced by the refresh queue
+		return
+	}
 	app := origApp.DeepCopy()
 	logCtx := getAppLog(app)
 	ts := stats.NewTimingStats()
@@ -1095,7 +1296,7 @@ func (ctrl *ApplicationController) removeProjectFinalizer(proj *appv1.AppProject
 
 // shouldBeDeleted returns whether a given resource obj should be deleted on cascade delete of application app
 func (ctrl *ApplicationController) shouldBeDeleted(app *appv1.Application, obj *unstructured.Unstructured) bool {
-	return !kube.IsCRD(obj) && !isSelfReferencedApp(app, kube.GetObjectRef(obj)) &&
+	return (!kube.IsCRD(obj) || isInDeletionOrder(app.Spec.SyncPolicy, obj)) && !isSelfReferencedApp(app, kube.GetObjectRef(obj)) &&
 		!resourceutil.HasAnnotationOption(obj, synccommon.AnnotationSyncOptions, synccommon.SyncOptionDisableDeletion) &&
 		!resourceutil.HasAnnotationOption(obj, helm.ResourcePolicyAnnotation, helm.ResourcePolicyKeep)
 }
@@ -1155,6 +1356,7 @@ func (ctrl *ApplicationController) finalizeApplicationDeletion(app *appv1.Applic
 	if !isValid {
 		app.UnSetCascadedDeletion()
 		app.UnSetPostDeleteFinalizer()
+		app.UnSetPreDeleteFinalizer()
 		if err := ctrl.updateFinalizers(app); err != nil {
 			return err
 		}
@@ -1163,6 +1365,23 @@ func (ctrl *ApplicationController) finalizeApplicationDeletion(app *appv1.Applic
 	}
 	config := metrics.AddMetricsTransportWrapper(ctrl.metricsServer, app, cluster.RESTConfig())
 
+	if app.HasPreDeleteFinalizer() {
+		objsMap, err := ctrl.getPermittedAppLiveObjects(app, proj, projectClusters)
+		if err != nil {
+			return err
+		}
+
+		done, err := ctrl.executePreDeleteHooks(app, proj, objsMap, config, logCtx)
+		if err != nil {
+			return err
+		}
+		if !done {
+			return nil
+		}
+		app.UnSetPreDeleteFinalizer()
+		return ctrl.updateFinalizers(app)
+	}
+
 	if app.CascadedDeletion() {
 		logCtx.Infof("Deleting resources")
 		// ApplicationDestination points to a valid cluster, so we may clean up the live objects
@@ -1172,19 +1391,26 @@ func (ctrl *ApplicationController) finalizeApplicationDeletion(app *appv1.Applic
 			return err
 		}
 
+		var pendingObjs []*unstructured.Unstructured
 		for k := range objsMap {
 			// Wait for objects pending deletion to complete before proceeding with next sync wave
 			if objsMap[k].GetDeletionTimestamp() != nil {
-				logCtx.Infof("%d objects remaining for deletion", len(objsMap))
-				return nil
+				pendingObjs = append(pendingObjs, objsMap[k])
+				continue
 			}
 
 			if ctrl.shouldBeDeleted(app, objsMap[k]) {
 				objs = append(objs, objsMap[k])
 			}
 		}
+		if len(pendingObjs) > 0 {
+			logCtx.Infof("%d objects remaining for deletion", len(objsMap))
+			return ctrl.forceDeleteHangingResources(app, pendingObjs, config, logCtx)
+		}
 
-		filteredObjs := FilterObjectsForDeletion(objs)
+		// Delete the objects of the kind which comes first in the deletion order, then of the sync wave, which no other
+		// object of the wave depends on first
+		filteredObjs := NewDeletionGraph(FilterObjectsForDeletion(filterObjectsForDeletionOrder(objs, app.Spec.SyncPolicy))).Next()
 
 		propagationPolicy := metav1.DeletePropagationForeground
 		if app.GetPropagationPolicy() == appv1.BackgroundPropagationPolicyFinalizer {
@@ -1317,6 +1543,7 @@ func (ctrl *ApplicationController) processRequestedAppOperation(app *appv1.Appli
 		if r := recover(); r != nil {
 			logCtx.Errorf("Recovered from panic: %+v\n%s", r, debug.Stack())
 			state.Phase = synccommon.OperationError
+			state.ErrorCode = appv1.SyncErrorCodeInternalError
 			if rerr, ok := r.(error); ok {
 				state.Message = rerr.Error()
 			} else {
@@ -1336,12 +1563,18 @@ func (ctrl *ApplicationController) processRequestedAppOperation(app *appv1.Appli
 	terminating := false
 	if isOperationInProgress(app) {
 		state = app.Status.OperationState.DeepCopy()
+		if state.WebhookConfirmationStartedAt != nil {
+			// the sync succeeded and waits for the confirmation of the post-sync webhook
+			ctrl.processPostSyncWebhookConfirmation(app, state)
+			return
+		}
 		terminating = state.Phase == synccommon.OperationTerminating
 		// Failed  operation with retry strategy might have be in-progress and has completion time
 		if state.FinishedAt != nil && !terminating {
-			retryAt, err := app.Status.OperationState.Operation.Retry.NextRetryAt(state.FinishedAt.Time, state.RetryCount)
+			retryAt, err := nextRetryAt(app, state, state.FinishedAt.Time)
 			if err != nil {
 				state.Phase = synccommon.OperationFailed
+				state.ErrorCode = appv1.SyncErrorCodeInvalidOperation
 				state.Message = err.Error()
 				ctrl.setOperationState(app, state)
 				return
@@ -1371,6 +1604,7 @@ func (ctrl *ApplicationController) processRequestedAppOperation(app *appv1.Appli
 
 	if err := argo.ValidateDestination(context.Background(), &app.Spec.Destination, ctrl.db); err != nil {
 		state.Phase = synccommon.OperationFailed
+		state.ErrorCode = appv1.SyncErrorCodeInvalidOperation
 		state.Message = err.Error()
 	} else {
 		ctrl.appStateManager.SyncAppState(app, state)
@@ -1382,9 +1616,14 @@ func (ctrl *ApplicationController) processRequestedAppOperation(app *appv1.Appli
 	ts.AddCheckpoint("get_app_proj_ms")
 	if err != nil {
 		state.Phase = synccommon.OperationError
+		state.ErrorCode = appv1.SyncErrorCodePermissionDenied
 		state.Message = err.Error()
 	}
 
+	if ctrl.getPostSyncWebhookRequiringConfirmation(app, state) != nil {
+		awaitPostSyncWebhookConfirmation(state)
+	}
+
 	if state.Phase == synccommon.OperationRunning {
 		// It's possible for an app to be terminated while we were operating on it. We do not want
 		// to clobber the Terminated state with Running. Get the latest app state to check for this.
@@ -1394,6 +1633,7 @@ func (ctrl *ApplicationController) processRequestedAppOperation(app *appv1.Appli
 			_, err = ctrl.getAppProj(freshApp)
 			if err != nil {
 				state.Phase = synccommon.OperationFailed
+				state.ErrorCode = appv1.SyncErrorCodePermissionDenied
 				state.Message = fmt.Sprintf("operation not allowed: %v", err)
 			}
 			if freshApp.Status.OperationState != nil && freshApp.Status.OperationState.Phase == synccommon.OperationTerminating {
@@ -1404,17 +1644,29 @@ func (ctrl *ApplicationController) processRequestedAppOperation(app *appv1.Appli
 				// cleanup (e.g. delete jobs, workflows, etc...)
 			}
 		}
+		if syncTimeout, err := getSyncTimeout(app, ctrl.globalSyncTimeout); err == nil && syncTimeout > 0 && state.WebhookConfirmationStartedAt == nil {
+			// resume the operation once the sync timeout expired, in case nothing else triggers a reconciliation
+			ctrl.requestAppRefresh(app.QualifiedName(), nil, &syncTimeout)
+		}
 	} else if state.Phase == synccommon.OperationFailed || state.Phase == synccommon.OperationError {
-		if !terminating && (state.RetryCount < state.Operation.Retry.Limit || state.Operation.Retry.Limit < 0) {
+		// failed and timed out operations are processed before the operations of the apps which did not fail
+		if key, err := cache.MetaNamespaceKeyFunc(app); err == nil {
+			ctrl.appOperationRetryQueue.SetRetry(key, int(state.RetryCount)+1, time.Now())
+		}
+		retry, pattern := shouldRetrySync(app, state)
+		if !terminating && retry {
 			now := metav1.Now()
 			state.FinishedAt = &now
-			if retryAt, err := state.Operation.Retry.NextRetryAt(now.Time, state.RetryCount); err != nil {
+			if retryAt, err := nextRetryAt(app, state, now.Time); err != nil {
 				state.Phase = synccommon.OperationFailed
 				state.Message = fmt.Sprintf("%s (failed to retry: %v)", state.Message, err)
 			} else {
 				state.Phase = synccommon.OperationRunning
 				state.RetryCount++
 				state.Message = fmt.Sprintf("%s. Retrying attempt #%d at %s.", state.Message, state.RetryCount, retryAt.Format(time.Kitchen))
+				if pattern != nil {
+					ctrl.metricsServer.IncSyncPatternRetry(pattern.ErrorPattern)
+				}
 			}
 		} else if state.RetryCount > 0 {
 			state.Message = fmt.Sprintf("%s (retried %d times).", state.Message, state.RetryCount)
@@ -1423,6 +1675,17 @@ func (ctrl *ApplicationController) processRequestedAppOperation(app *appv1.Appli
 
 	ctrl.setOperationState(app, state)
 	ts.AddCheckpoint("final_set_operation_state")
+	if state.Phase == synccommon.OperationRunning && state.WebhookConfirmationStartedAt != nil {
+		if key, err := cache.MetaNamespaceKeyFunc(app); err == nil {
+			ctrl.startPostSyncWebhookConfirmation(key, app, state)
+		}
+	}
+	if state.Phase == synccommon.OperationSucceeded {
+		if key, err := cache.MetaNamespaceKeyFunc(app); err == nil {
+			ctrl.appOperationRetryQueue.ClearRetry(key)
+		}
+		ctrl.pinAppRevisions(app, state)
+	}
 	if state.Phase.Completed() && (app.Operation.Sync != nil && !app.Operation.Sync.DryRun) {
 		// if we just completed an operation, force a refresh so that UI will report up-to-date
 		// sync/health information
@@ -1436,6 +1699,26 @@ func (ctrl *ApplicationController) processRequestedAppOperation(app *appv1.Appli
 	ts.AddCheckpoint("request_app_refresh_ms")
 }
 
+// clearAppCondition removes the conditions of the given type from the application, if any
+func (ctrl *ApplicationController) clearAppCondition(app *appv1.Application, conditionType appv1.ApplicationConditionType) {
+	evaluatedTypes := map[appv1.ApplicationConditionType]bool{conditionType: true}
+	if len(app.Status.GetConditions(evaluatedTypes)) == 0 {
+		return
+	}
+	app.Status.SetConditions(nil, evaluatedTypes)
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"conditions": app.Status.Conditions,
+		},
+	})
+	if err == nil {
+		_, err = ctrl.applicationClientset.ArgoprojV1alpha1().Applications(app.Namespace).Patch(context.Background(), app.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	}
+	if err != nil {
+		getAppLog(app).Errorf("Unable to clear application condition: %v", err)
+	}
+}
+
 func (ctrl *ApplicationController) setOperationState(app *appv1.Application, state *appv1.OperationState) {
 	logCtx := getAppLog(app)
 	if state.Phase == "" {
@@ -1485,6 +1768,7 @@ func (ctrl *ApplicationController) setOperationState(app *appv1.Application, sta
 			logCtx.Warnf("error patching application with operation state: %v", err)
 			return fmt.Errorf("error patching application with operation state: %w", err)
 		}
+		ctrl.notifyAppStatusChanged(app)
 		return nil
 	})
 
@@ -1509,6 +1793,26 @@ func (ctrl *ApplicationController) setOperationState(app *appv1.Application, sta
 		}
 		ctrl.logAppEvent(app, eventInfo, strings.Join(messages, " "), context.TODO())
 		ctrl.metricsServer.IncSync(app, state)
+		if state.Operation.Sync != nil {
+			// the post-sync webhook of syncs which waited for its confirmation was already delivered
+			if state.WebhookConfirmationStartedAt == nil {
+				ctrl.queuePostSyncWebhook(app, state)
+			}
+			ctrl.queueDatadogEvent(app, state)
+		}
+	}
+}
+
+// queueDatadogEvent queues the completed sync operation of the application, to be sent to Datadog if its project
+// configures the Datadog integration
+func (ctrl *ApplicationController) queueDatadogEvent(app *appv1.Application, state *appv1.OperationState) {
+	proj, err := ctrl.getAppProj(app)
+	if err != nil {
+		getAppLog(app).Warnf("Unable to get project for Datadog event: %v", err)
+		return
+	}
+	if integration := proj.Spec.GetDatadog(); integration != nil {
+		ctrl.datadogNotifier.Queue(integration, app, state)
 	}
 }
 
@@ -1542,15 +1846,29 @@ func (ctrl *ApplicationController) processAppRefreshQueueItem() (processNext boo
 		return
 	}
 	processNext = true
+	// The application is re-queued once the quarantine after its last panic ends
+	quarantined := ctrl.reconcilePanicBackoff.isQuarantined(appKey.(string))
+	claimed, stolen := ctrl.claimAppRefresh(appKey.(string))
 	defer func() {
 		if r := recover(); r != nil {
-			log.Errorf("Recovered from panic: %+v\n%s", r, debug.Stack())
+			ctrl.handleReconcilePanic(appKey.(string), r)
+		} else if !quarantined {
+			ctrl.reconcilePanicBackoff.forget(appKey.(string))
 		}
 		// We want to have app operation update happen after the sync, so there's no race condition
 		// and app updates not proceeding. See https://github.com/argoproj/argo-cd/issues/18500.
-		ctrl.appOperationQueue.AddRateLimited(appKey)
+		// The operations of the applications stolen from other shards are left to their shard.
+		if !stolen {
+			ctrl.appOperationQueue.AddRateLimited(appKey)
+		}
+		if claimed {
+			ctrl.releaseAppRefresh(appKey.(string), stolen)
+		}
 		ctrl.appRefreshQueue.Done(appKey)
 	}()
+	if quarantined || !claimed {
+		return
+	}
 	obj, exists, err := ctrl.appInformer.GetIndexer().GetByKey(appKey.(string))
 	if err != nil {
 		log.Errorf("Failed to get application '%s' from informer index: %+v", appKey, err)
@@ -1566,6 +1884,10 @@ func (ctrl *ApplicationController) processAppRefreshQueueItem() (processNext boo
 		return
 	}
 	origApp = origApp.DeepCopy()
+	if skip, until := getSkipReconcile(origApp, time.Now()); skip {
+		ctrl.skipReconcile(origApp, until)
+		return
+	}
 	needRefresh, refreshType, comparisonLevel := ctrl.needRefreshAppStatus(origApp, ctrl.statusRefreshTimeout, ctrl.statusHardRefreshTimeout)
 
 	if !needRefresh {
@@ -1584,6 +1906,7 @@ func (ctrl *ApplicationController) processAppRefreshQueueItem() (processNext boo
 	defer func() {
 		reconcileDuration := time.Since(startTime)
 		ctrl.metricsServer.IncReconcile(origApp, reconcileDuration)
+		ctrl.clusterHealthAggregator.Notify()
 		for k, v := range ts.Timings() {
 			logCtx = logCtx.WithField(k, v.Milliseconds())
 		}
@@ -1664,6 +1987,7 @@ func (ctrl *ApplicationController) processAppRefreshQueueItem() (processNext boo
 	}
 	now := metav1.Now()
 
+	ctrl.trackFanOutDestinations(app.QualifiedName(), app)
 	compareResult, err := ctrl.appStateManager.CompareAppState(app, project, revisions, sources,
 		refreshType == appv1.RefreshTypeHard,
 		comparisonLevel == CompareWithLatestForceResolve, localManifests, hasMultipleSources, false)
@@ -1673,6 +1997,12 @@ func (ctrl *ApplicationController) processAppRefreshQueueItem() (processNext boo
 		logCtx.Warnf("Ignoring temporary failed attempt to compare app state against repo: %v", err)
 		return // short circuit if git error is encountered
 	}
+	if remaining, ok := ctrl.clusterDisconnectGraceRemaining(app.Spec.Destination.Server); ok {
+		logCtx.Warnf("Keeping the last known status while cluster %s is unreachable, retrying in %v", app.Spec.Destination.Server, remaining)
+		ctrl.metricsServer.IncClusterDisconnectGrace(app.Spec.Destination.Server)
+		ctrl.requestAppRefresh(app.QualifiedName(), CompareWithLatest.Pointer(), &remaining)
+		return // short circuit until the cluster is reachable again or the grace period is over
+	}
 
 	for k, v := range compareResult.timings {
 		logCtx = logCtx.WithField(k, v.Milliseconds())
@@ -1688,10 +2018,15 @@ func (ctrl *ApplicationController) processAppRefreshQueueItem() (processNext boo
 	} else {
 		app.Status.Summary = tree.GetSummary(app)
 	}
+	ctrl.setAppResourceCount(app, compareResult)
 
 	if project.Spec.SyncWindows.Matches(app).CanSync(false) {
+		rollbackErrCond, rollbackMS := ctrl.autoRollback(app, compareResult.healthStatus)
 		syncErrCond, opMS := ctrl.autoSync(app, compareResult.syncStatus, compareResult.resources)
-		setOpMs = opMS
+		setOpMs = rollbackMS + opMS
+		if syncErrCond == nil {
+			syncErrCond = rollbackErrCond
+		}
 		if syncErrCond != nil {
 			app.Status.SetConditions(
 				[]appv1.ApplicationCondition{*syncErrCond},
@@ -1713,12 +2048,14 @@ func (ctrl *ApplicationController) processAppRefreshQueueItem() (processNext boo
 	}
 	app.Status.Sync = *compareResult.syncStatus
 	app.Status.Health = *compareResult.healthStatus
+	app.Status.DestinationStatuses = compareResult.destinationStatuses
 	app.Status.Resources = compareResult.resources
 	sort.Slice(app.Status.Resources, func(i, j int) bool {
 		return resourceStatusKey(app.Status.Resources[i]) < resourceStatusKey(app.Status.Resources[j])
 	})
 	app.Status.SourceType = compareResult.appSourceType
 	app.Status.SourceTypes = compareResult.appSourceTypes
+	app.Status.AutoValueFiles = compareResult.autoValueFiles
 	app.Status.ControllerNamespace = ctrl.namespace
 	ts.AddCheckpoint("app_status_update_ms")
 	patchMs = ctrl.persistAppStatus(origApp, &app.Status)
@@ -1738,6 +2075,17 @@ func (ctrl *ApplicationController) processAppRefreshQueueItem() (processNext boo
 			logCtx.Errorf("Failed to update finalizers: %v", err)
 		}
 	}
+	if compareResult.hasPreDeleteHooks != app.HasPreDeleteFinalizer() && app.GetDeletionTimestamp() == nil {
+		if compareResult.hasPreDeleteHooks {
+			app.SetPreDeleteFinalizer()
+		} else {
+			app.UnSetPreDeleteFinalizer()
+		}
+
+		if err := ctrl.updateFinalizers(app); err != nil {
+			logCtx.Errorf("Failed to update finalizers: %v", err)
+		}
+	}
 	ts.AddCheckpoint("process_finalizers_ms")
 	return
 }
@@ -1791,8 +2139,12 @@ func (ctrl *ApplicationController) needRefreshAppStatus(app *appv1.Application,
 				reason = fmt.Sprintf("comparison expired, requesting hard refresh. reconciledAt: %v, expiry: %v", reconciledAtStr, statusHardRefreshTimeout)
 				refreshType = appv1.RefreshTypeHard
 			}
+		} else if len(app.Status.GetConditions(map[appv1.ApplicationConditionType]bool{appv1.ApplicationConditionSkippedReconcile: true})) > 0 {
+			reason = "reconciliation is no longer skipped"
 		} else if !app.Spec.Destination.Equals(app.Status.Sync.ComparedTo.Destination) {
 			reason = "spec.destination differs"
+		} else if destinationsChanged(app) {
+			reason = "spec.destinations differs"
 		} else if app.HasChangedManagedNamespaceMetadata() {
 			reason = "spec.syncPolicy.managedNamespaceMetadata differs"
 		} else if !app.Spec.IgnoreDifferences.Equals(app.Status.Sync.ComparedTo.IgnoreDifferences) {
@@ -1829,6 +2181,7 @@ func (ctrl *ApplicationController) refreshAppConditions(app *appv1.Application)
 	app.Status.SetConditions(errorConditions, map[appv1.ApplicationConditionType]bool{
 		appv1.ApplicationConditionInvalidSpecError: true,
 		appv1.ApplicationConditionUnknownError:     true,
+		appv1.ApplicationConditionSkippedReconcile: true,
 	})
 	return proj, len(errorConditions) > 0
 }
@@ -1871,6 +2224,7 @@ func createMergePatch(orig, new interface{}) ([]byte, bool, error) {
 // persistAppStatus persists updates to application status. If no changes were made, it is a no-op
 func (ctrl *ApplicationController) persistAppStatus(orig *appv1.Application, newStatus *appv1.ApplicationStatus) (patchMs time.Duration) {
 	logCtx := getAppLog(orig)
+	newStatus.AddConditionHistory(conditionTransitions(&orig.Status, newStatus, metav1.Now())...)
 	if orig.Status.Sync.Status != newStatus.Sync.Status {
 		message := fmt.Sprintf("Updated sync status: %s -> %s", orig.Status.Sync.Status, newStatus.Sync.Status)
 		ctrl.logAppEvent(orig, argo.EventInfo{Reason: argo.EventReasonResourceUpdated, Type: v1.EventTypeNormal}, message, context.TODO())
@@ -1908,10 +2262,19 @@ func (ctrl *ApplicationController) persistAppStatus(orig *appv1.Application, new
 		logCtx.Warnf("Error updating application: %v", err)
 	} else {
 		logCtx.Infof("Update successful")
+		ctrl.notifyAppStatusChanged(orig)
 	}
 	return patchMs
 }
 
+// notifyAppStatusChanged notifies the API servers watching the application that its status was updated, so that they
+// do not wait for the update to reach their informer
+func (ctrl *ApplicationController) notifyAppStatusChanged(app *appv1.Application) {
+	if err := ctrl.cache.NotifyAppStatusChanged(app.InstanceName(ctrl.namespace)); err != nil {
+		getAppLog(app).Warnf("Failed to notify the status change of the application: %v", err)
+	}
+}
+
 // autoSync will initiate a sync operation for an application configured with automated sync
 func (ctrl *ApplicationController) autoSync(app *appv1.Application, syncStatus *appv1.SyncStatus, resources []appv1.ResourceStatus) (*appv1.ApplicationCondition, time.Duration) {
 	logCtx := getAppLog(app)
@@ -1967,6 +2330,14 @@ func (ctrl *ApplicationController) autoSync(app *appv1.Application, syncStatus *
 
 	desiredCommitSHA := syncStatus.Revision
 	desiredCommitSHAsMS := syncStatus.Revisions
+	desiredRevision := desiredCommitSHA
+	if app.Spec.HasMultipleSources() {
+		desiredRevision = strings.Join(desiredCommitSHAsMS, ",")
+	}
+	if isRolledBackRevision(app, desiredRevision) {
+		logCtx.Infof("Skipping auto-sync: revision %s was automatically rolled back", desiredRevision)
+		return nil, 0
+	}
 	alreadyAttempted, attemptPhase := alreadyAttemptedSync(app, desiredCommitSHA, desiredCommitSHAsMS, app.Spec.HasMultipleSources())
 	ts.AddCheckpoint("already_attempted_sync_ms")
 	op := appv1.Operation{
@@ -2119,6 +2490,45 @@ func (ctrl *ApplicationController) isAppNamespaceAllowed(app *appv1.Application)
 	return app.Namespace == ctrl.namespace || glob.MatchStringInList(ctrl.applicationNamespaces, app.Namespace, glob.REGEXP)
 }
 
+// getSkipReconcile returns whether the reconciliation of the application is skipped based on its skip-reconcile
+// annotations at the given time, and the time the skip ends if it is time-bounded
+func getSkipReconcile(app *appv1.Application, now time.Time) (bool, *time.Time) {
+	annotations := app.GetAnnotations()
+	if skipVal, ok := annotations[common.AnnotationKeyAppSkipReconcile]; ok {
+		if skipReconcile, err := strconv.ParseBool(skipVal); err == nil {
+			if skipReconcile {
+				return true, nil
+			}
+		} else {
+			getAppLog(app).Debugf("Unable to determine if Application should skip reconcile based on annotation %s: %v", common.AnnotationKeyAppSkipReconcile, err)
+		}
+	}
+	if untilVal, ok := annotations[common.AnnotationKeyAppSkipReconcileUntil]; ok {
+		until, err := time.Parse(time.RFC3339, untilVal)
+		if err != nil {
+			getAppLog(app).Warnf("Unable to determine if Application should skip reconcile based on annotation %s: %v", common.AnnotationKeyAppSkipReconcileUntil, err)
+			return false, nil
+		}
+		if now.Before(until) {
+			return true, &until
+		}
+	}
+	return false, nil
+}
+
+// skipReconcile surfaces the skipped reconciliation of the application as condition, and requeues the application
+// at the end of a time-bounded skip
+func (ctrl *ApplicationController) skipReconcile(app *appv1.Application, until *time.Time) {
+	message := fmt.Sprintf("Reconciliation is skipped based on annotation %s", common.AnnotationKeyAppSkipReconcile)
+	if until != nil {
+		message = fmt.Sprintf("Reconciliation is skipped until %s based on annotation %s", until.UTC().Format(time.RFC3339), common.AnnotationKeyAppSkipReconcileUntil)
+		after := time.Until(*until)
+		ctrl.requestAppRefresh(app.QualifiedName(), CompareWithLatest.Pointer(), &after)
+	}
+	getAppLog(app).Warn(message)
+	ctrl.setAppCondition(app, appv1.ApplicationCondition{Type: appv1.ApplicationConditionSkippedReconcile, Message: message})
+}
+
 func (ctrl *ApplicationController) canProcessApp(obj interface{}) bool {
 	app, ok := obj.(*appv1.Application)
 	if !ok {
@@ -2131,20 +2541,6 @@ func (ctrl *ApplicationController) canProcessApp(obj interface{}) bool {
 		return false
 	}
 
-	if annotations := app.GetAnnotations(); annotations != nil {
-		if skipVal, ok := annotations[common.AnnotationKeyAppSkipReconcile]; ok {
-			logCtx := getAppLog(app)
-			if skipReconcile, err := strconv.ParseBool(skipVal); err == nil {
-				if skipReconcile {
-					logCtx.Debugf("Skipping Application reconcile based on annotation %s", common.AnnotationKeyAppSkipReconcile)
-					return false
-				}
-			} else {
-				logCtx.Debugf("Unable to determine if Application should skip reconcile based on annotation %s: %v", common.AnnotationKeyAppSkipReconcile, err)
-			}
-		}
-	}
-
 	cluster, err := ctrl.db.GetCluster(context.Background(), app.Spec.Destination.Server)
 	if err != nil {
 		return ctrl.clusterSharding.IsManagedCluster(nil)
@@ -2163,6 +2559,63 @@ func (ctrl *ApplicationController) newApplicationInformerAndLister() (cache.Shar
 	if ctrl.statusHardRefreshTimeout.Seconds() != 0 && (ctrl.statusHardRefreshTimeout < ctrl.statusRefreshTimeout) {
 		refreshTimeout = ctrl.statusHardRefreshTimeout
 	}
+	indexers := cache.Indexers{
+		cache.NamespaceIndex: func(obj interface{}) ([]string, error) {
+			app, ok := obj.(*appv1.Application)
+			if ok {
+				// We only generally work with applications that are in one
+				// the allowed namespaces.
+				if ctrl.isAppNamespaceAllowed(app) {
+					// If the application is not allowed to use the project,
+					// log an error.
+					if _, err := ctrl.getAppProj(app); err != nil {
+						ctrl.setAppCondition(app, ctrl.projectErrorToCondition(err, app))
+					} else {
+						// This call to 'ValidateDestination' ensures that the .spec.destination field of all Applications
+						// returned by the informer/lister will have server field set (if not already set) based on the name.
+						// (or, if not found, an error app condition)
+
+						// If the server field is not set, set it based on the cluster name; if the cluster name can't be found,
+						// log an error as an App Condition.
+						if err := argo.ValidateDestination(context.Background(), &app.Spec.Destination, ctrl.db); err != nil {
+							ctrl.setAppCondition(app, appv1.ApplicationCondition{Type: appv1.ApplicationConditionInvalidSpecError, Message: err.Error()})
+						}
+					}
+				}
+			}
+
+			return cache.MetaNamespaceIndexFunc(obj)
+		},
+		orphanedIndex: func(obj interface{}) (i []string, e error) {
+			app, ok := obj.(*appv1.Application)
+			if !ok {
+				return nil, nil
+			}
+
+			if !ctrl.isAppNamespaceAllowed(app) {
+				return nil, nil
+			}
+
+			proj, err := ctrl.getAppProj(app)
+			if err != nil {
+				return nil, nil
+			}
+			if proj.Spec.OrphanedResources != nil {
+				return []string{app.Spec.Destination.Namespace}, nil
+			}
+			return nil, nil
+		},
+	}
+	var err error
+	// the compressed object cache makes the informer store the applications compressed, if enabled
+	var objects *cacheutil.CompressedObjectCache
+	if ctrl.compressInformerCache {
+		objects, err = cacheutil.NewCompressedObjectCache(func() apiruntime.Object { return &appv1.Application{} })
+		if err != nil {
+			return nil, nil
+		}
+		indexers = objects.Indexers(indexers)
+	}
 	informer := cache.NewSharedIndexInformer(
 		&cache.ListWatch{
 			ListFunc: func(options metav1.ListOptions) (apiruntime.Object, error) {
@@ -2187,56 +2640,16 @@ func (ctrl *ApplicationController) newApplicationInformerAndLister() (cache.Shar
 		},
 		&appv1.Application{},
 		refreshTimeout,
-		cache.Indexers{
-			cache.NamespaceIndex: func(obj interface{}) ([]string, error) {
-				app, ok := obj.(*appv1.Application)
-				if ok {
-					// We only generally work with applications that are in one
-					// the allowed namespaces.
-					if ctrl.isAppNamespaceAllowed(app) {
-						// If the application is not allowed to use the project,
-						// log an error.
-						if _, err := ctrl.getAppProj(app); err != nil {
-							ctrl.setAppCondition(app, ctrl.projectErrorToCondition(err, app))
-						} else {
-							// This call to 'ValidateDestination' ensures that the .spec.destination field of all Applications
-							// returned by the informer/lister will have server field set (if not already set) based on the name.
-							// (or, if not found, an error app condition)
-
-							// If the server field is not set, set it based on the cluster name; if the cluster name can't be found,
-							// log an error as an App Condition.
-							if err := argo.ValidateDestination(context.Background(), &app.Spec.Destination, ctrl.db); err != nil {
-								ctrl.setAppCondition(app, appv1.ApplicationCondition{Type: appv1.ApplicationConditionInvalidSpecError, Message: err.Error()})
-							}
-						}
-					}
-				}
-
-				return cache.MetaNamespaceIndexFunc(obj)
-			},
-			orphanedIndex: func(obj interface{}) (i []string, e error) {
-				app, ok := obj.(*appv1.Application)
-				if !ok {
-					return nil, nil
-				}
-
-				if !ctrl.isAppNamespaceAllowed(app) {
-					return nil, nil
-				}
-
-				proj, err := ctrl.getAppProj(app)
-				if err != nil {
-					return nil, nil
-				}
-				if proj.Spec.OrphanedResources != nil {
-					return []string{app.Spec.Destination.Namespace}, nil
-				}
-				return nil, nil
-			},
-		},
+		indexers,
 	)
+	if objects != nil {
+		informer, err = objects.Informer(informer)
+		if err != nil {
+			return nil, nil
+		}
+	}
 	lister := applisters.NewApplicationLister(informer.GetIndexer())
-	_, err := informer.AddEventHandler(
+	_, err = informer.AddEventHandler(
 		cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
 				if !ctrl.canProcessApp(obj) {
@@ -2294,10 +2707,12 @@ func (ctrl *ApplicationController) newApplicationInformerAndLister() (cache.Shar
 				if err == nil {
 					// for deletes, we immediately add to the refresh queue
 					ctrl.appRefreshQueue.Add(key)
+					ctrl.appOperationRetryQueue.ClearRetry(key)
 				}
 				delApp, delOK := obj.(*appv1.Application)
 				if err == nil && delOK {
 					ctrl.clusterSharding.DeleteApp(delApp)
+					ctrl.trackFanOutDestinations(delApp.QualifiedName(), nil)
 				}
 			},
 		},
@@ -2305,6 +2720,12 @@ func (ctrl *ApplicationController) newApplicationInformerAndLister() (cache.Shar
 	if err != nil {
 		return nil, nil
 	}
+	// resource usage is tracked for all applications, including the ones processed by other shards,
+	// since project quotas apply across shards
+	_, err = informer.AddEventHandler(ctrl.projectResourceUsage.eventHandler())
+	if err != nil {
+		return nil, nil
+	}
 	return informer, lister
 }
 
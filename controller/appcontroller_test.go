@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -22,17 +25,21 @@ import (
 	"github.com/argoproj/gitops-engine/pkg/cache/mocks"
 	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
 	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	testingutils "github.com/argoproj/gitops-engine/pkg/utils/testing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
 	kubetesting "k8s.io/client-go/testing"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/kubectl/pkg/util/openapi"
 	"sigs.k8s.io/yaml"
 
 	dbmocks "github.com/argoproj/argo-cd/v2/util/db/mocks"
@@ -64,6 +71,32 @@ type fakeData struct {
 	metricsCacheExpiration         time.Duration
 	applicationNamespaces          []string
 	updateRevisionForPathsResponse *apiclient.UpdateRevisionForPathsResponse
+	postSyncWebhookAllowedURLs     []string
+	deletionTimeoutPerResource     time.Duration
+	globalSyncTimeout              time.Duration
+	clusterLabels                  map[string]string
+	compressInformerCache          bool
+	defaultResourceApplyTimeout    time.Duration
+	enableSyncCheckpoints          bool
+	webhookConfirmationTimeout     time.Duration
+	// resourceOps overrides the resource operations used by syncs
+	resourceOps kube.ResourceOperations
+	// clusterServer overrides the URL of the API server of the fake cluster
+	clusterServer string
+	// workStealingQueue enables the work stealing across shards
+	workStealingQueue *sharding.WorkStealingQueue
+	// managedLiveObjsErr is returned when loading the live state, e.g. if the cluster is unreachable
+	managedLiveObjsErr error
+	// destinationLiveObjs are the live objects of the destinations with the given servers, managedLiveObjs are the live
+	// objects of the other destinations
+	destinationLiveObjs map[string]map[kube.ResourceKey]*unstructured.Unstructured
+	// clusterDisconnectedSince is the time since which the fake cluster is unreachable, zero if it is reachable
+	clusterDisconnectedSince     time.Time
+	clusterDisconnectGracePeriod time.Duration
+	queueHealthCheckWindow       time.Duration
+	// resourceAnnotationTemplates are the entries of the argocd-resource-annotations ConfigMap
+	resourceAnnotationTemplates        map[string]string
+	disableResourceAnnotationTemplates bool
 }
 
 type MockKubectl struct {
@@ -71,6 +104,21 @@ type MockKubectl struct {
 
 	DeletedResources []kube.ResourceKey
 	CreatedResources []*unstructured.Unstructured
+	PatchedResources []kube.ResourceKey
+	// ResourceOps overrides the resource operations returned by ManageResources
+	ResourceOps kube.ResourceOperations
+}
+
+func (m *MockKubectl) ManageResources(config *rest.Config, openAPISchema openapi.Resources) (kube.ResourceOperations, func(), error) {
+	if m.ResourceOps != nil {
+		return m.ResourceOps, func() {}, nil
+	}
+	return m.Kubectl.ManageResources(config, openAPISchema)
+}
+
+func (m *MockKubectl) PatchResource(ctx context.Context, config *rest.Config, gvk schema.GroupVersionKind, name string, namespace string, patchType types.PatchType, patchBytes []byte, subresources ...string) (*unstructured.Unstructured, error) {
+	m.PatchedResources = append(m.PatchedResources, kube.NewResourceKey(gvk.Group, gvk.Kind, namespace, name))
+	return m.Kubectl.PatchResource(ctx, config, gvk, name, namespace, patchType, patchBytes, subresources...)
 }
 
 func (m *MockKubectl) CreateResource(ctx context.Context, config *rest.Config, gvk schema.GroupVersionKind, name string, namespace string, obj *unstructured.Unstructured, createOptions metav1.CreateOptions, subresources ...string) (*unstructured.Unstructured, error) {
@@ -89,6 +137,12 @@ func newFakeController(data *fakeData, repoErr error) *ApplicationController {
 	if err != nil {
 		panic(err)
 	}
+	for k, v := range data.clusterLabels {
+		clust.Labels[k] = v
+	}
+	if data.clusterServer != "" {
+		clust.Data["server"] = []byte(data.clusterServer)
+	}
 
 	// Mock out call to GenerateManifest
 	mockRepoClient := mockrepoclient.RepoServerServiceClient{}
@@ -133,9 +187,22 @@ func newFakeController(data *fakeData, repoErr error) *ApplicationController {
 		},
 		Data: data.configMapData,
 	}
-	kubeClient := fake.NewSimpleClientset(&clust, &cm, &secret)
+	kubeObjs := []runtime.Object{&clust, &cm, &secret}
+	if data.resourceAnnotationTemplates != nil {
+		kubeObjs = append(kubeObjs, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      common.ArgoCDResourceAnnotationsConfigMapName,
+				Namespace: test.FakeArgoCDNamespace,
+				Labels: map[string]string{
+					"app.kubernetes.io/part-of": "argocd",
+				},
+			},
+			Data: data.resourceAnnotationTemplates,
+		})
+	}
+	kubeClient := fake.NewSimpleClientset(kubeObjs...)
 	settingsMgr := settings.NewSettingsManager(context.Background(), kubeClient, test.FakeArgoCDNamespace)
-	kubectl := &MockKubectl{Kubectl: &kubetest.MockKubectlCmd{}}
+	kubectl := &MockKubectl{Kubectl: &kubetest.MockKubectlCmd{}, ResourceOps: data.resourceOps}
 	ctrl, err := NewApplicationController(
 		test.FakeArgoCDNamespace,
 		settingsMgr,
@@ -155,6 +222,7 @@ func newFakeController(data *fakeData, repoErr error) *ApplicationController {
 		common.DefaultPortArgoCDMetrics,
 		data.metricsCacheExpiration,
 		[]string{},
+		1.0,
 		0,
 		true,
 		nil,
@@ -163,6 +231,25 @@ func newFakeController(data *fakeData, repoErr error) *ApplicationController {
 		false,
 		false,
 		normalizers.IgnoreNormalizerOpts{},
+		data.postSyncWebhookAllowedURLs,
+		"",
+		false,
+		time.Hour,
+		0,
+		data.deletionTimeoutPerResource,
+		data.globalSyncTimeout,
+		DefaultDriftDigestSchedule,
+		data.compressInformerCache,
+		0,
+		time.Hour,
+		data.defaultResourceApplyTimeout,
+		data.enableSyncCheckpoints,
+		data.webhookConfirmationTimeout,
+		0,
+		data.workStealingQueue,
+		data.clusterDisconnectGracePeriod,
+		data.queueHealthCheckWindow,
+		data.disableResourceAnnotationTemplates,
 	)
 	db := &dbmocks.ArgoDB{}
 	db.On("GetApplicationControllerReplicas").Return(1)
@@ -184,7 +271,18 @@ func newFakeController(data *fakeData, repoErr error) *ApplicationController {
 	ctrl.appStateManager.(*appStateManager).liveStateCache = &mockStateCache
 	ctrl.stateCache = &mockStateCache
 	mockStateCache.On("IsNamespaced", mock.Anything, mock.Anything).Return(true, nil)
-	mockStateCache.On("GetManagedLiveObjs", mock.Anything, mock.Anything).Return(data.managedLiveObjs, nil)
+	if data.destinationLiveObjs != nil {
+		mockStateCache.On("GetManagedLiveObjs", mock.Anything, mock.Anything).Return(func(a *v1alpha1.Application, _ []*unstructured.Unstructured) (map[kube.ResourceKey]*unstructured.Unstructured, error) {
+			if liveObjs, ok := data.destinationLiveObjs[a.Spec.Destination.Server]; ok {
+				return liveObjs, nil
+			}
+			return data.managedLiveObjs, data.managedLiveObjsErr
+		}, nil)
+	} else {
+		mockStateCache.On("GetManagedLiveObjs", mock.Anything, mock.Anything).Return(data.managedLiveObjs, data.managedLiveObjsErr)
+	}
+	mockStateCache.On("GetClusterDisconnectedSince", mock.Anything).Return(data.clusterDisconnectedSince, !data.clusterDisconnectedSince.IsZero())
+	mockStateCache.On("ReleaseCluster", mock.Anything).Return()
 	mockStateCache.On("GetVersionsInfo", mock.Anything).Return("v1.2.3", nil, nil)
 	response := make(map[kube.ResourceKey]v1alpha1.ResourceNode)
 	for k, v := range data.namespacedResources {
@@ -413,6 +511,44 @@ var fakePostDeleteHook = `
 }
 `
 
+var fakePreDeleteHook = `
+{
+  "apiVersion": "batch/v1",
+  "kind": "Job",
+  "metadata": {
+    "name": "pre-delete-hook",
+    "namespace": "default",
+    "labels": {
+      "app.kubernetes.io/instance": "my-app"
+    },
+    "annotations": {
+      "argocd.argoproj.io/hook": "PreDelete"
+    }
+  },
+  "spec": {
+    "template": {
+      "metadata": {
+        "name": "pre-delete-hook"
+      },
+      "spec": {
+        "containers": [
+          {
+            "name": "pre-delete-hook",
+            "image": "busybox",
+            "command": [
+              "/bin/sh",
+              "-c",
+              "echo hello from the pre-delete-hook job"
+            ]
+          }
+        ],
+        "restartPolicy": "Never"
+      }
+    }
+  }
+}
+`
+
 var fakeServiceAccount = `
 {
   "apiVersion": "v1",
@@ -520,6 +656,15 @@ func newFakePostDeleteHook() map[string]interface{} {
 	return hook
 }
 
+func newFakePreDeleteHook() map[string]interface{} {
+	var hook map[string]interface{}
+	err := yaml.Unmarshal([]byte(fakePreDeleteHook), &hook)
+	if err != nil {
+		panic(err)
+	}
+	return hook
+}
+
 func newFakeRoleBinding() map[string]interface{} {
 	var roleBinding map[string]interface{}
 	err := yaml.Unmarshal([]byte(fakeRoleBinding), &roleBinding)
@@ -917,6 +1062,28 @@ func TestFinalizeAppDeletion(t *testing.T) {
 		}
 	})
 
+	t.Run("ForceDeleteHangingResources", func(t *testing.T) {
+		app := newFakeApp()
+		app.SetCascadedDeletion(v1alpha1.ResourcesFinalizerName)
+		app.Spec.Destination.Namespace = test.FakeArgoCDNamespace
+		cm := newFakeCM()
+		hangingObj := kube.MustToUnstructured(&cm)
+		hangingObj.SetDeletionTimestamp(&metav1.Time{Time: time.Now().Add(-time.Hour)})
+		hangingObj.SetFinalizers([]string{"example.com/protection"})
+		ctrl := newFakeController(&fakeData{
+			apps:                       []runtime.Object{app, &defaultProj},
+			managedLiveObjs:            map[kube.ResourceKey]*unstructured.Unstructured{kube.GetResourceKey(hangingObj): hangingObj},
+			deletionTimeoutPerResource: time.Minute,
+		}, nil)
+
+		err := ctrl.finalizeApplicationDeletion(app, func(project string) ([]*v1alpha1.Cluster, error) {
+			return []*v1alpha1.Cluster{}, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []kube.ResourceKey{kube.GetResourceKey(hangingObj)}, ctrl.kubectl.(*MockKubectl).PatchedResources)
+		assert.Equal(t, []kube.ResourceKey{kube.GetResourceKey(hangingObj)}, ctrl.kubectl.(*MockKubectl).DeletedResources)
+	})
+
 	t.Run("DeleteWithDestinationClusterName", func(t *testing.T) {
 		app := newFakeAppWithDestName()
 		app.SetCascadedDeletion(v1alpha1.ResourcesFinalizerName)
@@ -1108,6 +1275,97 @@ func TestFinalizeAppDeletion(t *testing.T) {
 		// finalizer is not removed
 		assert.False(t, patched)
 	})
+
+	newPreDeleteController := func(app *v1alpha1.Application, liveObjs ...*unstructured.Unstructured) (*ApplicationController, *bool) {
+		managedLiveObjs := map[kube.ResourceKey]*unstructured.Unstructured{}
+		for _, obj := range liveObjs {
+			managedLiveObjs[kube.GetResourceKey(obj)] = obj
+		}
+		ctrl := newFakeController(&fakeData{
+			manifestResponses: []*apiclient.ManifestResponse{{
+				Manifests: []string{fakePreDeleteHook},
+			}},
+			apps:            []runtime.Object{app, &defaultProj},
+			managedLiveObjs: managedLiveObjs,
+		}, nil)
+		patched := false
+		fakeAppCs := ctrl.applicationClientset.(*appclientset.Clientset)
+		defaultReactor := fakeAppCs.ReactionChain[0]
+		fakeAppCs.ReactionChain = nil
+		fakeAppCs.AddReactor("get", "*", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
+			return defaultReactor.React(action)
+		})
+		fakeAppCs.AddReactor("patch", "*", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
+			patched = true
+			return true, &v1alpha1.Application{}, nil
+		})
+		return ctrl, &patched
+	}
+
+	newLivePreDeleteHook := func(conditionType string) *unstructured.Unstructured {
+		liveHook := &unstructured.Unstructured{Object: newFakePreDeleteHook()}
+		conditions := []interface{}{
+			map[string]interface{}{
+				"type":   conditionType,
+				"status": "True",
+			},
+		}
+		require.NoError(t, unstructured.SetNestedField(liveHook.Object, conditions, "status", "conditions"))
+		return liveHook
+	}
+
+	t.Run("PreDelete_HookIsCreated", func(t *testing.T) {
+		app := newFakeApp()
+		app.SetCascadedDeletion(v1alpha1.ResourcesFinalizerName)
+		app.SetPreDeleteFinalizer()
+		app.Spec.Destination.Namespace = test.FakeArgoCDNamespace
+		cm := newFakeCM()
+		liveCM := kube.MustToUnstructured(&cm)
+		ctrl, patched := newPreDeleteController(app, liveCM)
+
+		err := ctrl.finalizeApplicationDeletion(app, func(project string) ([]*v1alpha1.Cluster, error) {
+			return []*v1alpha1.Cluster{}, nil
+		})
+		require.NoError(t, err)
+		// finalizer is not removed
+		assert.False(t, *patched)
+		// pre-delete hook is created, before any resource is deleted
+		require.Len(t, ctrl.kubectl.(*MockKubectl).CreatedResources, 1)
+		require.Equal(t, "pre-delete-hook", ctrl.kubectl.(*MockKubectl).CreatedResources[0].GetName())
+		assert.Empty(t, ctrl.kubectl.(*MockKubectl).DeletedResources)
+	})
+
+	t.Run("PreDelete_HookIsExecuted", func(t *testing.T) {
+		app := newFakeApp()
+		app.SetCascadedDeletion(v1alpha1.ResourcesFinalizerName)
+		app.SetPreDeleteFinalizer()
+		app.Spec.Destination.Namespace = test.FakeArgoCDNamespace
+		ctrl, patched := newPreDeleteController(app, newLivePreDeleteHook("Complete"))
+
+		err := ctrl.finalizeApplicationDeletion(app, func(project string) ([]*v1alpha1.Cluster, error) {
+			return []*v1alpha1.Cluster{}, nil
+		})
+		require.NoError(t, err)
+		// finalizer is removed
+		assert.True(t, *patched)
+		assert.Empty(t, ctrl.kubectl.(*MockKubectl).CreatedResources)
+	})
+
+	t.Run("PreDelete_HookFailed", func(t *testing.T) {
+		app := newFakeApp()
+		app.SetCascadedDeletion(v1alpha1.ResourcesFinalizerName)
+		app.SetPreDeleteFinalizer()
+		app.Spec.Destination.Namespace = test.FakeArgoCDNamespace
+		ctrl, patched := newPreDeleteController(app, newLivePreDeleteHook("Failed"))
+
+		err := ctrl.finalizeApplicationDeletion(app, func(project string) ([]*v1alpha1.Cluster, error) {
+			return []*v1alpha1.Cluster{}, nil
+		})
+		require.EqualError(t, err, "pre-delete hook Job default/pre-delete-hook failed")
+		// finalizer is not removed
+		assert.False(t, *patched)
+		assert.Empty(t, ctrl.kubectl.(*MockKubectl).DeletedResources)
+	})
 }
 
 // TestNormalizeApplication verifies we normalize an application during reconciliation
@@ -1265,6 +1523,41 @@ func TestGetResourceTree_HasOrphanedResources(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []v1alpha1.ResourceNode{managedDeploy}, tree.Nodes)
 	assert.Equal(t, []v1alpha1.ResourceNode{orphanedDeploy1, orphanedDeploy2}, tree.OrphanedNodes)
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	ctrl.metricsServer.Handler.ServeHTTP(rr, req)
+	assert.Contains(t, rr.Body.String(), `argocd_app_orphaned_resources_count{name="my-app",namespace="`+test.FakeArgoCDNamespace+`",project="default"} 2`)
+	assert.Contains(t, rr.Body.String(), `argocd_project_orphaned_resources_count{name="default"} 2`)
+}
+
+func TestGetResourceTree_ExcludedResourcesAreOrphaned(t *testing.T) {
+	app := newFakeApp()
+	proj := defaultProj.DeepCopy()
+	proj.Spec.OrphanedResources = &v1alpha1.OrphanedResourcesMonitorSettings{}
+
+	excludedDeploy := v1alpha1.ResourceNode{
+		ResourceRef: v1alpha1.ResourceRef{Group: "apps", Kind: "Deployment", Namespace: "default", Name: "excluded"},
+	}
+	otherAppDeploy := v1alpha1.ResourceNode{
+		ResourceRef: v1alpha1.ResourceRef{Group: "apps", Kind: "Deployment", Namespace: "default", Name: "other"},
+	}
+	otherApp := newFakeApp()
+	otherApp.Name = "other-app"
+
+	ctrl := newFakeController(&fakeData{
+		apps: []runtime.Object{app, otherApp, proj},
+		namespacedResources: map[kube.ResourceKey]namespacedResource{
+			kube.NewResourceKey("apps", "Deployment", "default", "excluded"): {ResourceNode: excludedDeploy, AppName: app.Name},
+			kube.NewResourceKey("apps", "Deployment", "default", "other"):    {ResourceNode: otherAppDeploy, AppName: otherApp.Name},
+		},
+	}, nil)
+	tree, err := ctrl.getResourceTree(app, []*v1alpha1.ResourceDiff{})
+
+	require.NoError(t, err)
+	assert.Empty(t, tree.Nodes)
+	assert.Equal(t, []v1alpha1.ResourceNode{excludedDeploy}, tree.OrphanedNodes)
 }
 
 func TestSetOperationStateOnDeletedApp(t *testing.T) {
@@ -1660,6 +1953,75 @@ func TestUpdateReconciledAt(t *testing.T) {
 	})
 }
 
+func TestProcessAppRefreshQueueItem_ClusterDisconnectGracePeriod(t *testing.T) {
+	newController := func(disconnectedFor time.Duration) (*ApplicationController, *map[string]interface{}) {
+		app := newFakeApp()
+		app.Status.Sync.Status = v1alpha1.SyncStatusCodeSynced
+		ctrl := newFakeController(&fakeData{
+			apps: []runtime.Object{app, &defaultProj},
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: []string{},
+				Namespace: test.FakeDestNamespace,
+				Server:    test.FakeClusterURL,
+				Revision:  "abc123",
+			},
+			managedLiveObjsErr:           errors.New("error synchronizing cache state : dial tcp: connection refused"),
+			clusterDisconnectedSince:     time.Now().Add(-disconnectedFor),
+			clusterDisconnectGracePeriod: 30 * time.Second,
+		}, nil)
+		fakeAppCs := ctrl.applicationClientset.(*appclientset.Clientset)
+		fakeAppCs.ReactionChain = nil
+		receivedPatch := map[string]interface{}{}
+		fakeAppCs.AddReactor("patch", "*", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
+			if patchAction, ok := action.(kubetesting.PatchAction); ok {
+				require.NoError(t, json.Unmarshal(patchAction.GetPatch(), &receivedPatch))
+			}
+			return true, &v1alpha1.Application{}, nil
+		})
+		key, _ := cache.MetaNamespaceKeyFunc(app)
+		ctrl.requestAppRefresh(app.Name, CompareWithLatest.Pointer(), nil)
+		ctrl.appRefreshQueue.AddRateLimited(key)
+		return ctrl, &receivedPatch
+	}
+
+	// the counter is shared by the controllers of the tests
+	disconnectGraceCount := func(ctrl *ApplicationController) string {
+		req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		ctrl.metricsServer.Handler.ServeHTTP(rr, req)
+		for _, line := range strings.Split(rr.Body.String(), "\n") {
+			if strings.HasPrefix(line, `argocd_cluster_disconnect_grace_total{server="https://localhost:6443"}`) {
+				return line
+			}
+		}
+		return ""
+	}
+
+	t.Run("BriefDisconnection", func(t *testing.T) {
+		ctrl, receivedPatch := newController(5 * time.Second)
+		before := disconnectGraceCount(ctrl)
+
+		ctrl.processAppRefreshQueueItem()
+
+		// the last known status is kept until the cluster is reachable again or the grace period is over
+		assert.Empty(t, *receivedPatch)
+		assert.NotEqual(t, before, disconnectGraceCount(ctrl))
+	})
+
+	t.Run("ExtendedDisconnection", func(t *testing.T) {
+		ctrl, receivedPatch := newController(time.Minute)
+		before := disconnectGraceCount(ctrl)
+
+		ctrl.processAppRefreshQueueItem()
+
+		syncStatus, _, err := unstructured.NestedString(*receivedPatch, "status", "sync", "status")
+		require.NoError(t, err)
+		assert.Equal(t, string(v1alpha1.SyncStatusCodeUnknown), syncStatus)
+		assert.Equal(t, before, disconnectGraceCount(ctrl))
+	})
+}
+
 func TestProjectErrorToCondition(t *testing.T) {
 	app := newFakeApp()
 	app.Spec.Project = "wrong project"
@@ -1747,6 +2109,8 @@ func TestProcessRequestedAppOperation_FailedNoRetries(t *testing.T) {
 
 	phase, _, _ := unstructured.NestedString(receivedPatch, "status", "operationState", "phase")
 	assert.Equal(t, string(synccommon.OperationError), phase)
+	errorCode, _, _ := unstructured.NestedString(receivedPatch, "status", "operationState", "errorCode")
+	assert.Equal(t, string(v1alpha1.SyncErrorCodePermissionDenied), errorCode)
 }
 
 func TestProcessRequestedAppOperation_InvalidDestination(t *testing.T) {
@@ -1778,6 +2142,8 @@ func TestProcessRequestedAppOperation_InvalidDestination(t *testing.T) {
 	assert.Equal(t, string(synccommon.OperationFailed), phase)
 	message, _, _ := unstructured.NestedString(receivedPatch, "status", "operationState", "message")
 	assert.Contains(t, message, "application destination can't have both name and server defined: another-cluster https://localhost:6443")
+	errorCode, _, _ := unstructured.NestedString(receivedPatch, "status", "operationState", "errorCode")
+	assert.Equal(t, string(v1alpha1.SyncErrorCodeInvalidOperation), errorCode)
 }
 
 func TestProcessRequestedAppOperation_FailedHasRetries(t *testing.T) {
@@ -1846,6 +2212,92 @@ func TestProcessRequestedAppOperation_RunningPreviouslyFailed(t *testing.T) {
 	assert.Equal(t, string(synccommon.OperationSucceeded), phase)
 }
 
+func TestProcessRequestedAppOperation_PinRevision(t *testing.T) {
+	pinnedSHA := "a3b59e4b1b0b0d1d8c2e3b6b4a8c1f5e6d7c8b9a"
+	newPinnedApp := func(project string) *v1alpha1.Application {
+		app := newFakeApp()
+		app.Spec.Project = project
+		app.Spec.Source.TargetRevision = "main"
+		app.Operation = &v1alpha1.Operation{
+			Sync: &v1alpha1.SyncOperation{SyncOptions: v1alpha1.SyncOptions{"PinRevision=true"}},
+		}
+		app.Status.OperationState = nil
+		return app
+	}
+	processOperation := func(t *testing.T, app *v1alpha1.Application) []map[string]interface{} {
+		t.Helper()
+		data := &fakeData{
+			apps: []runtime.Object{app, &defaultProj},
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: []string{},
+				Namespace: test.FakeDestNamespace,
+				Server:    test.FakeClusterURL,
+				Revision:  pinnedSHA,
+			},
+		}
+		ctrl := newFakeController(data, nil)
+		fakeAppCs := ctrl.applicationClientset.(*appclientset.Clientset)
+		var receivedPatches []map[string]interface{}
+		fakeAppCs.PrependReactor("patch", "*", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
+			if patchAction, ok := action.(kubetesting.PatchAction); ok {
+				receivedPatch := map[string]interface{}{}
+				require.NoError(t, json.Unmarshal(patchAction.GetPatch(), &receivedPatch))
+				receivedPatches = append(receivedPatches, receivedPatch)
+			}
+			return true, &v1alpha1.Application{}, nil
+		})
+		ctrl.processRequestedAppOperation(app)
+		return receivedPatches
+	}
+	finalPhase := func(patches []map[string]interface{}) string {
+		var phase string
+		for _, patch := range patches {
+			if patchPhase, ok, _ := unstructured.NestedString(patch, "status", "operationState", "phase"); ok {
+				phase = patchPhase
+			}
+		}
+		return phase
+	}
+	pinnedRevision := func(patches []map[string]interface{}) string {
+		for _, patch := range patches {
+			if revision, ok, _ := unstructured.NestedString(patch, "spec", "source", "targetRevision"); ok {
+				return revision
+			}
+		}
+		return ""
+	}
+
+	t.Run("SucceededSync", func(t *testing.T) {
+		patches := processOperation(t, newPinnedApp("default"))
+		assert.Equal(t, string(synccommon.OperationSucceeded), finalPhase(patches))
+		assert.Equal(t, pinnedSHA, pinnedRevision(patches))
+	})
+
+	t.Run("FailedSync", func(t *testing.T) {
+		patches := processOperation(t, newPinnedApp("invalid-project"))
+		assert.Equal(t, string(synccommon.OperationError), finalPhase(patches))
+		assert.Empty(t, pinnedRevision(patches))
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		app := newPinnedApp("default")
+		app.Operation.Sync.DryRun = true
+		assert.Empty(t, pinnedRevision(processOperation(t, app)))
+	})
+
+	t.Run("WithoutSyncOption", func(t *testing.T) {
+		app := newPinnedApp("default")
+		app.Operation.Sync.SyncOptions = nil
+		assert.Empty(t, pinnedRevision(processOperation(t, app)))
+	})
+
+	t.Run("AlreadyPinned", func(t *testing.T) {
+		app := newPinnedApp("default")
+		app.Spec.Source.TargetRevision = "0f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c"
+		assert.Empty(t, pinnedRevision(processOperation(t, app)))
+	})
+}
+
 func TestProcessRequestedAppOperation_HasRetriesTerminated(t *testing.T) {
 	app := newFakeApp()
 	app.Operation = &v1alpha1.Operation{
@@ -1879,6 +2331,67 @@ func TestProcessRequestedAppOperation_HasRetriesTerminated(t *testing.T) {
 	assert.Equal(t, string(synccommon.OperationFailed), phase)
 }
 
+func TestProcessRequestedAppOperation_SyncTimedOut(t *testing.T) {
+	newApp := func() *v1alpha1.Application {
+		app := newFakeApp()
+		app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{SyncTimeout: "1m"}
+		// automated syncs are retried by default
+		app.Operation = &v1alpha1.Operation{
+			Sync:  &v1alpha1.SyncOperation{},
+			Retry: v1alpha1.RetryStrategy{Limit: 5},
+		}
+		app.Status.OperationState.Operation = *app.Operation
+		app.Status.OperationState.Phase = synccommon.OperationRunning
+		app.Status.OperationState.StartedAt = metav1.NewTime(time.Now().Add(-2 * time.Minute))
+		app.Status.OperationState.FinishedAt = nil
+		return app
+	}
+	processOperation := func(app *v1alpha1.Application) map[string]interface{} {
+		data := &fakeData{
+			apps: []runtime.Object{app, &defaultProj},
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: []string{},
+				Namespace: test.FakeDestNamespace,
+				Server:    test.FakeClusterURL,
+				Revision:  "abc123",
+			},
+		}
+		ctrl := newFakeController(data, nil)
+		fakeAppCs := ctrl.applicationClientset.(*appclientset.Clientset)
+		receivedPatch := map[string]interface{}{}
+		fakeAppCs.PrependReactor("patch", "*", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
+			if patchAction, ok := action.(kubetesting.PatchAction); ok {
+				require.NoError(t, json.Unmarshal(patchAction.GetPatch(), &receivedPatch))
+			}
+			return true, &v1alpha1.Application{}, nil
+		})
+		ctrl.processRequestedAppOperation(app)
+		return receivedPatch
+	}
+
+	t.Run("NotRetriedWithoutRetryStrategy", func(t *testing.T) {
+		receivedPatch := processOperation(newApp())
+
+		phase, _, _ := unstructured.NestedString(receivedPatch, "status", "operationState", "phase")
+		assert.Equal(t, string(synccommon.OperationFailed), phase)
+		message, _, _ := unstructured.NestedString(receivedPatch, "status", "operationState", "message")
+		assert.Equal(t, "SyncTimedOut: sync did not complete within 1m0s", message)
+		errorCode, _, _ := unstructured.NestedString(receivedPatch, "status", "operationState", "errorCode")
+		assert.Equal(t, string(v1alpha1.SyncErrorCodeTimeout), errorCode)
+	})
+
+	t.Run("RetriedWithRetryStrategy", func(t *testing.T) {
+		app := newApp()
+		app.Spec.SyncPolicy.Retry = &v1alpha1.RetryStrategy{Limit: 5}
+		receivedPatch := processOperation(app)
+
+		phase, _, _ := unstructured.NestedString(receivedPatch, "status", "operationState", "phase")
+		assert.Equal(t, string(synccommon.OperationRunning), phase)
+		message, _, _ := unstructured.NestedString(receivedPatch, "status", "operationState", "message")
+		assert.Contains(t, message, "SyncTimedOut: sync did not complete within 1m0s. Retrying attempt #1")
+	})
+}
+
 func TestProcessRequestedAppOperation_Successful(t *testing.T) {
 	app := newFakeApp()
 	app.Spec.Project = "default"
@@ -1909,6 +2422,46 @@ func TestProcessRequestedAppOperation_Successful(t *testing.T) {
 	assert.Equal(t, CompareWithLatestForceResolve, level)
 }
 
+func TestCompressInformerCache(t *testing.T) {
+	app := newFakeApp()
+	app.Spec.Project = "default"
+	app.Operation = &v1alpha1.Operation{
+		Sync: &v1alpha1.SyncOperation{},
+	}
+	ctrl := newFakeController(&fakeData{
+		apps: []runtime.Object{app, &defaultProj},
+		manifestResponses: []*apiclient.ManifestResponse{{
+			Manifests: []string{},
+		}},
+		compressInformerCache: true,
+	}, nil)
+
+	key, _ := cache.MetaNamespaceKeyFunc(app)
+	obj, exists, err := ctrl.appInformer.GetIndexer().GetByKey(key)
+	require.NoError(t, err)
+	require.True(t, exists)
+	cachedApp := obj.(*v1alpha1.Application)
+	assert.Equal(t, app.Spec, cachedApp.Spec)
+	apps, err := ctrl.appLister.Applications(app.Namespace).List(labels.Everything())
+	require.NoError(t, err)
+	require.Len(t, apps, 1)
+	assert.Equal(t, app.Name, apps[0].Name)
+
+	fakeAppCs := ctrl.applicationClientset.(*appclientset.Clientset)
+	receivedPatch := map[string]interface{}{}
+	fakeAppCs.PrependReactor("patch", "*", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
+		if patchAction, ok := action.(kubetesting.PatchAction); ok {
+			require.NoError(t, json.Unmarshal(patchAction.GetPatch(), &receivedPatch))
+		}
+		return true, &v1alpha1.Application{}, nil
+	})
+
+	ctrl.processRequestedAppOperation(cachedApp)
+
+	phase, _, _ := unstructured.NestedString(receivedPatch, "status", "operationState", "phase")
+	assert.Equal(t, string(synccommon.OperationSucceeded), phase)
+}
+
 func TestGetAppHosts(t *testing.T) {
 	app := newFakeApp()
 	data := &fakeData{
@@ -2041,7 +2594,8 @@ func Test_canProcessAppSkipReconcileAnnotation(t *testing.T) {
 		{"No skip reconcile annotation", newFakeApp(), true},
 		{"Contains skip reconcile annotation ", appSkipReconcileInvalid, true},
 		{"Contains skip reconcile annotation value false", appSkipReconcileFalse, true},
-		{"Contains skip reconcile annotation value true", appSkipReconcileTrue, false},
+		// skipped applications are processed to surface the SkippedReconcile condition
+		{"Contains skip reconcile annotation value true", appSkipReconcileTrue, true},
 	}
 
 	for _, tt := range tests {
@@ -2051,6 +2605,78 @@ func Test_canProcessAppSkipReconcileAnnotation(t *testing.T) {
 	}
 }
 
+func Test_getSkipReconcile(t *testing.T) {
+	now := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+	until := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		expectedSkip  bool
+		expectedUntil *time.Time
+	}{
+		{"No annotation", nil, false, nil},
+		{"Skip reconcile", map[string]string{common.AnnotationKeyAppSkipReconcile: "true"}, true, nil},
+		{"Skip reconcile false", map[string]string{common.AnnotationKeyAppSkipReconcile: "false"}, false, nil},
+		{"Skip reconcile invalid", map[string]string{common.AnnotationKeyAppSkipReconcile: "invalid-value"}, false, nil},
+		{"Skip reconcile until", map[string]string{common.AnnotationKeyAppSkipReconcileUntil: "2024-01-01T08:00:00Z"}, true, &until},
+		{"Skip reconcile until in other time zone", map[string]string{common.AnnotationKeyAppSkipReconcileUntil: "2024-01-01T09:00:00+01:00"}, true, &until},
+		{"Skip reconcile until expired", map[string]string{common.AnnotationKeyAppSkipReconcileUntil: "2024-01-01T05:59:59Z"}, false, nil},
+		{"Skip reconcile until invalid", map[string]string{common.AnnotationKeyAppSkipReconcileUntil: "tomorrow"}, false, nil},
+		{"Skip reconcile takes precedence", map[string]string{common.AnnotationKeyAppSkipReconcile: "true", common.AnnotationKeyAppSkipReconcileUntil: "2024-01-01T05:00:00Z"}, true, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newFakeApp()
+			app.Annotations = tt.annotations
+			skip, skipUntil := getSkipReconcile(app, now)
+			assert.Equal(t, tt.expectedSkip, skip)
+			if tt.expectedUntil == nil {
+				assert.Nil(t, skipUntil)
+			} else {
+				require.NotNil(t, skipUntil)
+				assert.True(t, tt.expectedUntil.Equal(*skipUntil))
+			}
+		})
+	}
+
+	t.Run("Expiry", func(t *testing.T) {
+		app := newFakeApp()
+		app.Annotations = map[string]string{common.AnnotationKeyAppSkipReconcileUntil: "2024-01-01T08:00:00Z"}
+		skip, _ := getSkipReconcile(app, until.Add(-time.Second))
+		assert.True(t, skip)
+		skip, _ = getSkipReconcile(app, until)
+		assert.False(t, skip)
+	})
+}
+
+func TestProcessAppRefreshQueueItem_SkipReconcile(t *testing.T) {
+	app := newFakeApp()
+	app.Annotations = map[string]string{common.AnnotationKeyAppSkipReconcileUntil: time.Now().Add(time.Hour).UTC().Format(time.RFC3339)}
+	ctrl := newFakeController(&fakeData{apps: []runtime.Object{app, &defaultProj}}, nil)
+	key, _ := cache.MetaNamespaceKeyFunc(app)
+	fakeAppCs := ctrl.applicationClientset.(*appclientset.Clientset)
+	fakeAppCs.ReactionChain = nil
+	receivedPatch := map[string]interface{}{}
+	fakeAppCs.AddReactor("patch", "*", func(action kubetesting.Action) (handled bool, ret runtime.Object, err error) {
+		if patchAction, ok := action.(kubetesting.PatchAction); ok {
+			require.NoError(t, json.Unmarshal(patchAction.GetPatch(), &receivedPatch))
+		}
+		return true, &v1alpha1.Application{}, nil
+	})
+
+	ctrl.appRefreshQueue.AddRateLimited(key)
+	ctrl.processAppRefreshQueueItem()
+
+	conditions, _, err := unstructured.NestedSlice(receivedPatch, "status", "conditions")
+	require.NoError(t, err)
+	require.Len(t, conditions, 1)
+	assert.Equal(t, v1alpha1.ApplicationConditionSkippedReconcile, conditions[0].(map[string]interface{})["type"])
+	// the application was not compared
+	_, compared, err := unstructured.NestedMap(receivedPatch, "status", "sync")
+	require.NoError(t, err)
+	assert.False(t, compared)
+}
+
 func Test_syncDeleteOption(t *testing.T) {
 	app := newFakeApp()
 	ctrl := newFakeController(&fakeData{apps: []runtime.Object{app}}, nil)
@@ -2072,6 +2698,13 @@ func Test_syncDeleteOption(t *testing.T) {
 		delete := ctrl.shouldBeDeleted(app, cmObj)
 		assert.False(t, delete)
 	})
+	t.Run("CRD is only deleted if listed in the deletion order", func(t *testing.T) {
+		crd := testingutils.NewCRD()
+		assert.False(t, ctrl.shouldBeDeleted(app, crd))
+		app := app.DeepCopy()
+		app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{DeletionOrder: []string{"apiextensions.k8s.io/CustomResourceDefinition"}}
+		assert.True(t, ctrl.shouldBeDeleted(app, crd))
+	})
 }
 
 func TestAddControllerNamespace(t *testing.T) {
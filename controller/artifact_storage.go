@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/artifact"
+)
+
+const (
+	// artifactStorageTimeout is the timeout of storing the manifests of a single sync in the registry
+	artifactStorageTimeout = 2 * time.Minute
+	// artifactStorageWorkers is the number of syncs whose manifests are stored concurrently
+	artifactStorageWorkers = 2
+	// artifactStorageQueueSize is the maximum number of syncs whose manifests are waiting to be stored
+	artifactStorageQueueSize = 100
+)
+
+// artifactStorageRequest is a successful sync waiting for its applied manifests to be stored in a registry
+type artifactStorageRequest struct {
+	storage appv1.OCIArtifactStorage
+	result  artifact.SyncResult
+}
+
+// ArtifactStorer stores the manifests applied by successful syncs as artifacts in the OCI registry configured in the
+// project of the application
+type ArtifactStorer struct {
+	kubeClientset kubernetes.Interface
+	// namespace is the namespace of the secrets holding the registry credentials
+	namespace string
+	queue     chan artifactStorageRequest
+	// plainHTTP accesses registries over HTTP instead of HTTPS
+	plainHTTP bool
+	push      func(ctx context.Context, repository artifact.Repository, result artifact.SyncResult) (*artifact.Artifact, error)
+}
+
+// NewArtifactStorer returns an ArtifactStorer which reads the registry credentials from secrets in the given namespace
+func NewArtifactStorer(kubeClientset kubernetes.Interface, namespace string) *ArtifactStorer {
+	return &ArtifactStorer{
+		kubeClientset: kubeClientset,
+		namespace:     namespace,
+		queue:         make(chan artifactStorageRequest, artifactStorageQueueSize),
+		push:          artifact.Push,
+	}
+}
+
+// Queue queues the manifests applied by a successful sync of the application, to be stored in the registry by
+// runWorker. The sync is dropped if too many syncs are waiting to be stored.
+func (s *ArtifactStorer) Queue(app *appv1.Application, storage *appv1.OCIArtifactStorage, revision string, syncTime time.Time, manifests []*unstructured.Unstructured) {
+	copies := make([]*unstructured.Unstructured, 0, len(manifests))
+	for _, manifest := range manifests {
+		if manifest != nil {
+			copies = append(copies, manifest.DeepCopy())
+		}
+	}
+	req := artifactStorageRequest{
+		storage: *storage,
+		result:  artifact.SyncResult{AppName: app.QualifiedName(), Revision: revision, SyncTime: syncTime, Manifests: copies},
+	}
+	select {
+	case s.queue <- req:
+	default:
+		getAppLog(app).Warn("Dropping sync result artifact: too many artifacts waiting to be stored")
+	}
+}
+
+// runWorker stores the queued sync results until the context is done
+func (s *ArtifactStorer) runWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case req := <-s.queue:
+			logCtx := log.WithField("application", req.result.AppName)
+			stored, err := s.store(ctx, req)
+			if err != nil {
+				logCtx.Warnf("Failed to store sync result artifact: %v", err)
+				continue
+			}
+			logCtx.Infof("Stored sync result artifact %s/%s:%s", req.storage.Registry, req.storage.Repo, stored.Tag)
+		}
+	}
+}
+
+// store pushes the sync result to the registry, using the credentials of the credential secret of the storage
+func (s *ArtifactStorer) store(ctx context.Context, req artifactStorageRequest) (*artifact.Artifact, error) {
+	ctx, cancel := context.WithTimeout(ctx, artifactStorageTimeout)
+	defer cancel()
+	repository := artifact.Repository{Registry: req.storage.Registry, Repo: req.storage.Repo, PlainHTTP: s.plainHTTP}
+	if req.storage.CredentialSecret != "" {
+		secret, err := s.kubeClientset.CoreV1().Secrets(s.namespace).Get(ctx, req.storage.CredentialSecret, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting credential secret %s: %w", req.storage.CredentialSecret, err)
+		}
+		repository.Username = strings.TrimSpace(string(secret.Data["username"]))
+		repository.Password = strings.TrimSpace(string(secret.Data["password"]))
+	}
+	return s.push(ctx, repository, req.result)
+}
+
+// syncedRevision returns the revision of a sync, with the revisions of multi-source applications separated by commas
+func syncedRevision(syncStatus *appv1.SyncStatus, isMultiSourceRevision bool) string {
+	if isMultiSourceRevision {
+		return strings.Join(syncStatus.Revisions, ",")
+	}
+	return syncStatus.Revision
+}
@@ -0,0 +1,122 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/test"
+	"github.com/argoproj/argo-cd/v2/util/artifact"
+)
+
+func TestSyncQueuesSyncResultArtifact(t *testing.T) {
+	storage := &v1alpha1.ArtifactStorage{OCI: &v1alpha1.OCIArtifactStorage{Registry: "registry.example.com", Repo: "argocd/sync-results"}}
+	newController := func(storage *v1alpha1.ArtifactStorage) (*ApplicationController, *v1alpha1.Application) {
+		app := newFakeApp()
+		app.Status.OperationState = nil
+		app.Status.History = nil
+		project := &v1alpha1.AppProject{
+			ObjectMeta: v1.ObjectMeta{Namespace: test.FakeArgoCDNamespace, Name: "default"},
+			Spec:       v1alpha1.AppProjectSpec{ArtifactStorage: storage},
+		}
+		data := fakeData{
+			apps: []runtime.Object{app, project},
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: []string{},
+				Namespace: test.FakeDestNamespace,
+				Server:    test.FakeClusterURL,
+				Revision:  "abc123",
+			},
+			managedLiveObjs: make(map[kube.ResourceKey]*unstructured.Unstructured),
+		}
+		return newFakeController(&data, nil), app
+	}
+
+	t.Run("Successful sync", func(t *testing.T) {
+		ctrl, app := newController(storage)
+		startedAt := v1.NewTime(time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+		opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{}}, StartedAt: startedAt}
+		ctrl.appStateManager.SyncAppState(app, opState)
+		require.Equal(t, common.OperationSucceeded, opState.Phase, opState.Message)
+
+		require.Len(t, ctrl.artifactStorer.queue, 1)
+		req := <-ctrl.artifactStorer.queue
+		assert.Equal(t, *storage.OCI, req.storage)
+		assert.Equal(t, app.QualifiedName(), req.result.AppName)
+		assert.Equal(t, "abc123", req.result.Revision)
+		assert.Equal(t, startedAt.Time, req.result.SyncTime)
+	})
+
+	t.Run("Dry run", func(t *testing.T) {
+		ctrl, app := newController(storage)
+		opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{DryRun: true}}}
+		ctrl.appStateManager.SyncAppState(app, opState)
+		require.Equal(t, common.OperationSucceeded, opState.Phase, opState.Message)
+		assert.Empty(t, ctrl.artifactStorer.queue)
+	})
+
+	t.Run("Partial sync", func(t *testing.T) {
+		ctrl, app := newController(storage)
+		opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{
+			Resources: []v1alpha1.SyncOperationResource{{Kind: "Deployment", Name: "guestbook-ui"}},
+		}}}
+		ctrl.appStateManager.SyncAppState(app, opState)
+		require.Equal(t, common.OperationSucceeded, opState.Phase, opState.Message)
+		assert.Empty(t, ctrl.artifactStorer.queue)
+	})
+
+	t.Run("No artifact storage", func(t *testing.T) {
+		ctrl, app := newController(nil)
+		opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{}}}
+		ctrl.appStateManager.SyncAppState(app, opState)
+		require.Equal(t, common.OperationSucceeded, opState.Phase, opState.Message)
+		assert.Empty(t, ctrl.artifactStorer.queue)
+	})
+}
+
+func TestArtifactStorer_Store(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{Name: "registry-creds", Namespace: test.FakeArgoCDNamespace},
+		Data:       map[string][]byte{"username": []byte("admin"), "password": []byte("secret\n")},
+	}
+	storer := NewArtifactStorer(fake.NewSimpleClientset(secret), test.FakeArgoCDNamespace)
+	var pushed artifact.Repository
+	storer.push = func(_ context.Context, repository artifact.Repository, result artifact.SyncResult) (*artifact.Artifact, error) {
+		pushed = repository
+		return &artifact.Artifact{Tag: artifact.Tag(result.AppName, result.SyncTime)}, nil
+	}
+	req := artifactStorageRequest{
+		storage: v1alpha1.OCIArtifactStorage{Registry: "registry.example.com", Repo: "argocd/sync-results", CredentialSecret: "registry-creds"},
+		result:  artifact.SyncResult{AppName: "argocd/guestbook", SyncTime: time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)},
+	}
+
+	stored, err := storer.store(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, "argocd_guestbook-20240301T120000Z", stored.Tag)
+	assert.Equal(t, artifact.Repository{Registry: "registry.example.com", Repo: "argocd/sync-results", Username: "admin", Password: "secret"}, pushed)
+
+	req.storage.CredentialSecret = "missing"
+	_, err = storer.store(context.Background(), req)
+	require.ErrorContains(t, err, "error getting credential secret missing")
+}
+
+func TestArtifactStorer_QueueFull(t *testing.T) {
+	storer := NewArtifactStorer(fake.NewSimpleClientset(), test.FakeArgoCDNamespace)
+	storage := &v1alpha1.OCIArtifactStorage{Registry: "registry.example.com", Repo: "argocd/sync-results"}
+	for i := 0; i < artifactStorageQueueSize+1; i++ {
+		storer.Queue(newFakeApp(), storage, "abc123", time.Now(), nil)
+	}
+	assert.Len(t, storer.queue, artifactStorageQueueSize)
+}
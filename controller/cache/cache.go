@@ -37,6 +37,7 @@ import (
 	"github.com/argoproj/argo-cd/v2/util/argo/normalizers"
 	"github.com/argoproj/argo-cd/v2/util/db"
 	"github.com/argoproj/argo-cd/v2/util/env"
+	healthutil "github.com/argoproj/argo-cd/v2/util/health"
 	logutils "github.com/argoproj/argo-cd/v2/util/log"
 	"github.com/argoproj/argo-cd/v2/util/lua"
 	"github.com/argoproj/argo-cd/v2/util/settings"
@@ -137,11 +138,20 @@ type LiveStateCache interface {
 	Run(ctx context.Context) error
 	// Returns information about monitored clusters
 	GetClustersInfo() []clustercache.ClusterInfo
+	// Returns the time since which the cache of the given cluster has been failing to synchronize, and false if the
+	// last synchronization of the cluster succeeded
+	GetClusterDisconnectedSince(server string) (time.Time, bool)
+	// Drops the cache of the given cluster, and stops watching its resources, if the controller does not handle the
+	// cluster anymore
+	ReleaseCluster(server string)
 	// Init must be executed before cache can be used
 	Init() error
 }
 
-type ObjectUpdatedHandler = func(managedByApp map[string]bool, ref v1.ObjectReference)
+type ObjectUpdatedHandler = func(managedByApp map[string]bool, ref v1.ObjectReference, deleted bool)
+
+// ResourceHealthChangedHandler is notified when the health status of a resource managed by an application changes
+type ResourceHealthChangedHandler = func(appName string, key kube.ResourceKey, health *health.HealthStatus)
 
 type PodInfo struct {
 	NodeName         string
@@ -177,19 +187,24 @@ func NewLiveStateCache(
 	kubectl kube.Kubectl,
 	metricsServer *metrics.MetricsServer,
 	onObjectUpdated ObjectUpdatedHandler,
+	onResourceHealthChanged ResourceHealthChangedHandler,
 	clusterSharding sharding.ClusterShardingCache,
 	resourceTracking argo.ResourceTracking,
+	disableHealthOverrides bool,
 ) LiveStateCache {
 	return &liveStateCache{
-		appInformer:      appInformer,
-		db:               db,
-		clusters:         make(map[string]clustercache.ClusterCache),
-		onObjectUpdated:  onObjectUpdated,
-		kubectl:          kubectl,
-		settingsMgr:      settingsMgr,
-		metricsServer:    metricsServer,
-		clusterSharding:  clusterSharding,
-		resourceTracking: resourceTracking,
+		appInformer:             appInformer,
+		db:                      db,
+		clusters:                make(map[string]clustercache.ClusterCache),
+		disconnectedSince:       make(map[string]time.Time),
+		onObjectUpdated:         onObjectUpdated,
+		onResourceHealthChanged: onResourceHealthChanged,
+		kubectl:                 kubectl,
+		settingsMgr:             settingsMgr,
+		metricsServer:           metricsServer,
+		clusterSharding:         clusterSharding,
+		resourceTracking:        resourceTracking,
+		disableHealthOverrides:  disableHealthOverrides,
 	}
 }
 
@@ -205,19 +220,27 @@ type cacheSettings struct {
 }
 
 type liveStateCache struct {
-	db                   db.ArgoDB
-	appInformer          cache.SharedIndexInformer
-	onObjectUpdated      ObjectUpdatedHandler
-	kubectl              kube.Kubectl
-	settingsMgr          *settings.SettingsManager
-	metricsServer        *metrics.MetricsServer
-	clusterSharding      sharding.ClusterShardingCache
-	resourceTracking     argo.ResourceTracking
-	ignoreNormalizerOpts normalizers.IgnoreNormalizerOpts
+	db              db.ArgoDB
+	appInformer     cache.SharedIndexInformer
+	onObjectUpdated ObjectUpdatedHandler
+	// onResourceHealthChanged is optional and notified about health changes of application resources
+	onResourceHealthChanged ResourceHealthChangedHandler
+	kubectl                 kube.Kubectl
+	settingsMgr             *settings.SettingsManager
+	metricsServer           *metrics.MetricsServer
+	clusterSharding         sharding.ClusterShardingCache
+	resourceTracking        argo.ResourceTracking
+	ignoreNormalizerOpts    normalizers.IgnoreNormalizerOpts
+	// disableHealthOverrides disables the health-override annotation of resources
+	disableHealthOverrides bool
 
 	clusters      map[string]clustercache.ClusterCache
 	cacheSettings cacheSettings
 	lock          sync.RWMutex
+
+	// disconnectedSince holds the time of the first failed synchronization of the clusters which are unreachable
+	disconnectedSince     map[string]time.Time
+	disconnectedSinceLock sync.RWMutex
 }
 
 func (c *liveStateCache) loadCacheSettings() (*cacheSettings, error) {
@@ -241,8 +264,16 @@ func (c *liveStateCache) loadCacheSettings() (*cacheSettings, error) {
 	if err != nil {
 		return nil, err
 	}
+	healthOverrideScripts, err := c.settingsMgr.GetHealthOverrideScripts()
+	if err != nil {
+		return nil, err
+	}
+	resourceHealthOverride := lua.NewResourceHealthOverride(healthOverrideScripts, resourceOverrides)
+	if !c.disableHealthOverrides {
+		resourceHealthOverride = healthutil.NewAnnotationHealthOverride(resourceHealthOverride)
+	}
 	clusterSettings := clustercache.Settings{
-		ResourceHealthOverride: lua.ResourceHealthOverrides(resourceOverrides),
+		ResourceHealthOverride: resourceHealthOverride,
 		ResourcesFilter:        resourcesFilter,
 	}
 
@@ -355,6 +386,28 @@ func skipResourceUpdate(oldInfo, newInfo *ResourceInfo) bool {
 	return isSameHealthStatus && isSameManifest
 }
 
+// notifyResourceHealthChanged notifies the health changed handler if the health status of a resource managed by an
+// application changed. Deleted resources are reported as missing.
+func (c *liveStateCache) notifyResourceHealthChanged(newRes *clustercache.Resource, oldRes *clustercache.Resource, namespaceResources map[kube.ResourceKey]*clustercache.Resource) {
+	var oldHealth, newHealth *health.HealthStatus
+	if oldRes != nil {
+		oldHealth = resInfo(oldRes).Health
+	}
+	res := oldRes
+	if newRes != nil {
+		res = newRes
+		newHealth = resInfo(newRes).Health
+	} else if oldHealth != nil {
+		newHealth = &health.HealthStatus{Status: health.HealthStatusMissing, Message: "Resource was deleted"}
+	}
+	if newHealth == nil || (oldHealth != nil && oldHealth.Status == newHealth.Status) {
+		return
+	}
+	if app := getApp(res, namespaceResources); app != "" {
+		c.onResourceHealthChanged(app, res.ResourceKey(), newHealth)
+	}
+}
+
 // shouldHashManifest validates if the API resource needs to be hashed.
 // If there's an app name from resource tracking, or if this is itself an app, we should generate a hash.
 // Otherwise, the hashing should be skipped to save CPU time.
@@ -521,7 +574,7 @@ func (c *liveStateCache) getCluster(server string) (clustercache.ClusterCache, e
 			cacheSettings := c.cacheSettings
 			c.lock.RUnlock()
 
-			res.Health, _ = health.GetResourceHealth(un, cacheSettings.clusterSettings.ResourceHealthOverride)
+			res.Health, _ = healthutil.GetResourceHealth(un, cacheSettings.clusterSettings.ResourceHealthOverride, "")
 
 			appName := c.resourceTracking.GetAppName(un, cacheSettings.appInstanceLabelKey, cacheSettings.trackingMethod)
 			if isRoot && appName != "" {
@@ -563,6 +616,10 @@ func (c *liveStateCache) getCluster(server string) (clustercache.ClusterCache, e
 		cacheSettings := c.cacheSettings
 		c.lock.RUnlock()
 
+		if c.onResourceHealthChanged != nil {
+			c.notifyResourceHealthChanged(newRes, oldRes, namespaceResources)
+		}
+
 		if cacheSettings.ignoreResourceUpdatesEnabled && oldRes != nil && newRes != nil && skipResourceUpdate(resInfo(oldRes), resInfo(newRes)) {
 			// Additional check for debug level so we don't need to evaluate the
 			// format string in case of non-debug scenarios
@@ -592,7 +649,7 @@ func (c *liveStateCache) getCluster(server string) (clustercache.ClusterCache, e
 			}
 			toNotify[app] = isRootAppNode(r) || toNotify[app]
 		}
-		c.onObjectUpdated(toNotify, ref)
+		c.onObjectUpdated(toNotify, ref, newRes == nil)
 	})
 
 	_ = clusterCache.OnEvent(func(event watch.EventType, un *unstructured.Unstructured) {
@@ -612,11 +669,45 @@ func (c *liveStateCache) getSyncedCluster(server string) (clustercache.ClusterCa
 	}
 	err = clusterCache.EnsureSynced()
 	if err != nil {
+		c.markClusterDisconnected(server)
 		return nil, fmt.Errorf("error synchronizing cache state : %w", err)
 	}
+	c.markClusterConnected(server)
 	return clusterCache, nil
 }
 
+// markClusterDisconnected records the time of the first failed synchronization of the given cluster, the later
+// failures keeping the original time
+func (c *liveStateCache) markClusterDisconnected(server string) {
+	c.disconnectedSinceLock.Lock()
+	defer c.disconnectedSinceLock.Unlock()
+	if c.disconnectedSince == nil {
+		c.disconnectedSince = make(map[string]time.Time)
+	}
+	if _, ok := c.disconnectedSince[server]; !ok {
+		c.disconnectedSince[server] = time.Now()
+	}
+}
+
+// markClusterConnected forgets about the failed synchronizations of the given cluster once it is reachable again
+func (c *liveStateCache) markClusterConnected(server string) {
+	c.disconnectedSinceLock.RLock()
+	_, ok := c.disconnectedSince[server]
+	c.disconnectedSinceLock.RUnlock()
+	if ok {
+		c.disconnectedSinceLock.Lock()
+		delete(c.disconnectedSince, server)
+		c.disconnectedSinceLock.Unlock()
+	}
+}
+
+func (c *liveStateCache) GetClusterDisconnectedSince(server string) (time.Time, bool) {
+	c.disconnectedSinceLock.RLock()
+	defer c.disconnectedSinceLock.RUnlock()
+	since, ok := c.disconnectedSince[server]
+	return since, ok
+}
+
 func (c *liveStateCache) invalidate(cacheSettings cacheSettings) {
 	log.Info("invalidating live state cache")
 	c.lock.Lock()
@@ -857,6 +948,32 @@ func (c *liveStateCache) handleDeleteEvent(clusterServer string) {
 		delete(c.clusters, clusterServer)
 		c.lock.Unlock()
 	}
+	c.markClusterConnected(clusterServer)
+}
+
+func (c *liveStateCache) ReleaseCluster(server string) {
+	c.lock.RLock()
+	_, ok := c.clusters[server]
+	c.lock.RUnlock()
+	if !ok {
+		return
+	}
+	cluster, err := c.db.GetCluster(context.Background(), server)
+	if err != nil {
+		log.Warnf("Failed to get cluster %s to release its cache: %v", server, err)
+		return
+	}
+	// the cluster is checked while holding the lock, so that a cache which getCluster is about to return is not dropped
+	c.lock.Lock()
+	clusterCache, ok := c.clusters[server]
+	if !ok || c.canHandleCluster(cluster) {
+		c.lock.Unlock()
+		return
+	}
+	delete(c.clusters, server)
+	c.lock.Unlock()
+	log.Infof("Releasing the cache of cluster %s", server)
+	clusterCache.Invalidate()
 }
 
 func (c *liveStateCache) GetClustersInfo() []clustercache.ClusterInfo {
@@ -22,6 +22,7 @@ import (
 	"github.com/argoproj/gitops-engine/pkg/health"
 	"github.com/argoproj/gitops-engine/pkg/utils/kube"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/argoproj/argo-cd/v2/common"
@@ -62,6 +63,85 @@ func TestHandleModEvent_HasChanges(t *testing.T) {
 	})
 }
 
+func TestGetClusterDisconnectedSince(t *testing.T) {
+	clusterCache := &mocks.ClusterCache{}
+	clusterCache.On("Invalidate", mock.Anything).Return(nil)
+	db := &dbmocks.ArgoDB{}
+	db.On("GetApplicationControllerReplicas").Return(1)
+	clustersCache := liveStateCache{
+		clusters: map[string]cache.ClusterCache{
+			"https://mycluster": clusterCache,
+		},
+		clusterSharding: sharding.NewClusterSharding(db, 0, 1, common.DefaultShardingAlgorithm),
+	}
+
+	_, disconnected := clustersCache.GetClusterDisconnectedSince("https://mycluster")
+	assert.False(t, disconnected)
+
+	// the time of the first failed synchronization is kept while the cluster is unreachable
+	clusterCache.On("EnsureSynced").Return(errors.New("connection refused")).Twice()
+	_, err := clustersCache.getSyncedCluster("https://mycluster")
+	require.Error(t, err)
+	since, disconnected := clustersCache.GetClusterDisconnectedSince("https://mycluster")
+	require.True(t, disconnected)
+	_, err = clustersCache.getSyncedCluster("https://mycluster")
+	require.Error(t, err)
+	sinceAfterRetry, disconnected := clustersCache.GetClusterDisconnectedSince("https://mycluster")
+	require.True(t, disconnected)
+	assert.Equal(t, since, sinceAfterRetry)
+
+	// the cluster is connected again once the synchronization succeeds
+	clusterCache.On("EnsureSynced").Return(nil).Once()
+	_, err = clustersCache.getSyncedCluster("https://mycluster")
+	require.NoError(t, err)
+	_, disconnected = clustersCache.GetClusterDisconnectedSince("https://mycluster")
+	assert.False(t, disconnected)
+
+	// the disconnection is forgotten when the cluster is removed
+	clusterCache.On("EnsureSynced").Return(errors.New("connection refused")).Once()
+	_, err = clustersCache.getSyncedCluster("https://mycluster")
+	require.Error(t, err)
+	clustersCache.handleDeleteEvent("https://mycluster")
+	_, disconnected = clustersCache.GetClusterDisconnectedSince("https://mycluster")
+	assert.False(t, disconnected)
+}
+
+// fixedClusterSharding manages the clusters only while managed is set
+type fixedClusterSharding struct {
+	sharding.ClusterShardingCache
+	managed bool
+}
+
+func (s *fixedClusterSharding) IsManagedCluster(_ *appv1.Cluster) bool {
+	return s.managed
+}
+
+func TestReleaseCluster(t *testing.T) {
+	clusterCache := &mocks.ClusterCache{}
+	clusterCache.On("Invalidate").Return(nil).Once()
+	db := &dbmocks.ArgoDB{}
+	db.On("GetCluster", mock.Anything, "https://mycluster").Return(&appv1.Cluster{Server: "https://mycluster"}, nil)
+	clusterSharding := &fixedClusterSharding{managed: true}
+	clustersCache := liveStateCache{
+		db:              db,
+		clusters:        map[string]cache.ClusterCache{"https://mycluster": clusterCache},
+		clusterSharding: clusterSharding,
+	}
+
+	// the cache of a cluster handled by the controller is kept
+	clustersCache.ReleaseCluster("https://mycluster")
+	assert.Len(t, clustersCache.clusters, 1)
+
+	clusterSharding.managed = false
+	clustersCache.ReleaseCluster("https://mycluster")
+	assert.Empty(t, clustersCache.clusters)
+	clusterCache.AssertExpectations(t)
+
+	// releasing a cluster without cache is a no-op
+	clustersCache.ReleaseCluster("https://mycluster")
+	db.AssertNumberOfCalls(t, "GetCluster", 2)
+}
+
 func TestHandleModEvent_ClusterExcluded(t *testing.T) {
 	clusterCache := &mocks.ClusterCache{}
 	clusterCache.On("Invalidate", mock.Anything, mock.Anything).Return(nil).Once()
@@ -71,7 +151,7 @@ func TestHandleModEvent_ClusterExcluded(t *testing.T) {
 	clustersCache := liveStateCache{
 		db:          nil,
 		appInformer: nil,
-		onObjectUpdated: func(managedByApp map[string]bool, ref v1.ObjectReference) {
+		onObjectUpdated: func(managedByApp map[string]bool, ref v1.ObjectReference, deleted bool) {
 		},
 		kubectl:       nil,
 		settingsMgr:   &argosettings.SettingsManager{},
@@ -657,6 +737,49 @@ func TestSkipResourceUpdate(t *testing.T) {
 	})
 }
 
+func TestNotifyResourceHealthChanged(t *testing.T) {
+	type notification struct {
+		app    string
+		key    kube.ResourceKey
+		status health.HealthStatusCode
+	}
+	var notifications []notification
+	c := &liveStateCache{onResourceHealthChanged: func(appName string, key kube.ResourceKey, health *health.HealthStatus) {
+		notifications = append(notifications, notification{appName, key, health.Status})
+	}}
+	newResource := func(app string, status health.HealthStatusCode) *cache.Resource {
+		info := &ResourceInfo{AppName: app}
+		if status != "" {
+			info.Health = &health.HealthStatus{Status: status}
+		}
+		return &cache.Resource{
+			Ref:  v1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: "guestbook"},
+			Info: info,
+		}
+	}
+	key := kube.NewResourceKey("apps", "Deployment", "default", "guestbook")
+
+	progressing := newResource("my-app", health.HealthStatusProgressing)
+	healthy := newResource("my-app", health.HealthStatusHealthy)
+	degraded := newResource("my-app", health.HealthStatusDegraded)
+	c.notifyResourceHealthChanged(progressing, nil, nil)
+	c.notifyResourceHealthChanged(healthy, progressing, nil)
+	// unchanged health status
+	c.notifyResourceHealthChanged(newResource("my-app", health.HealthStatusHealthy), healthy, nil)
+	c.notifyResourceHealthChanged(degraded, healthy, nil)
+	c.notifyResourceHealthChanged(nil, degraded, nil)
+	// resources without health or application are ignored
+	c.notifyResourceHealthChanged(newResource("my-app", ""), nil, nil)
+	c.notifyResourceHealthChanged(newResource("", health.HealthStatusHealthy), nil, nil)
+
+	assert.Equal(t, []notification{
+		{"my-app", key, health.HealthStatusProgressing},
+		{"my-app", key, health.HealthStatusHealthy},
+		{"my-app", key, health.HealthStatusDegraded},
+		{"my-app", key, health.HealthStatusMissing},
+	}, notifications)
+}
+
 func TestShouldHashManifest(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -15,6 +15,8 @@ import (
 
 	schema "k8s.io/apimachinery/pkg/runtime/schema"
 
+	time "time"
+
 	unstructured "k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	v1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
@@ -55,6 +57,34 @@ func (_m *LiveStateCache) GetClusterCache(server string) (cache.ClusterCache, er
 	return r0, r1
 }
 
+// GetClusterDisconnectedSince provides a mock function with given fields: server
+func (_m *LiveStateCache) GetClusterDisconnectedSince(server string) (time.Time, bool) {
+	ret := _m.Called(server)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetClusterDisconnectedSince")
+	}
+
+	var r0 time.Time
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string) (time.Time, bool)); ok {
+		return rf(server)
+	}
+	if rf, ok := ret.Get(0).(func(string) time.Time); ok {
+		r0 = rf(server)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(server)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
 // GetClustersInfo provides a mock function with given fields:
 func (_m *LiveStateCache) GetClustersInfo() []cache.ClusterInfo {
 	ret := _m.Called()
@@ -272,6 +302,11 @@ func (_m *LiveStateCache) IterateResources(server string, callback func(*cache.R
 	return r0
 }
 
+// ReleaseCluster provides a mock function with given fields: server
+func (_m *LiveStateCache) ReleaseCluster(server string) {
+	_m.Called(server)
+}
+
 // Run provides a mock function with given fields: ctx
 func (_m *LiveStateCache) Run(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -0,0 +1,311 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/db"
+)
+
+const (
+	// clusterDiscoveryResyncPeriod is the period every discovery secret is reconciled at, which registers the clusters
+	// which were not reachable when their secret was created
+	clusterDiscoveryResyncPeriod = 3 * time.Minute
+
+	// clusterDiscoveryKeyKubeconfig is the key of a discovery secret holding a kubeconfig, whose current context is registered
+	clusterDiscoveryKeyKubeconfig = "kubeconfig"
+	// clusterDiscoveryKeyServer is the key of a discovery secret holding the URL of the API server of the cluster
+	clusterDiscoveryKeyServer = "server"
+	// clusterDiscoveryKeyToken is the key of a discovery secret holding a service account token of the cluster
+	clusterDiscoveryKeyToken = "token"
+	// clusterDiscoveryKeyCA is the key of a discovery secret holding the CA certificate of the API server of the cluster
+	clusterDiscoveryKeyCA = "ca.crt"
+	// clusterDiscoveryKeyName is the key of a discovery secret holding the name of the cluster, the name of the secret by default
+	clusterDiscoveryKeyName = "name"
+)
+
+// ClusterDiscovery registers the clusters whose credentials are stored in the secrets of the discovery namespace
+// labeled with argocd.argoproj.io/cluster-discovery=true, after checking the cluster is reachable with them. A secret
+// either holds a kubeconfig, or the layout of a service account token secret (token and ca.crt) with the server of
+// the cluster. Registered clusters are annotated with argocd.argoproj.io/auto-discovered=true and deregistered once
+// their secret is deleted or no longer labeled.
+type ClusterDiscovery struct {
+	namespace     string
+	kubeClientset kubernetes.Interface
+	db            db.ArgoDB
+	informer      cache.SharedIndexInformer
+	queue         workqueue.RateLimitingInterface
+	// checkConnectivity returns an error if the cluster is not reachable with its credentials
+	checkConnectivity func(cluster *appv1.Cluster) error
+}
+
+// NewClusterDiscovery returns a new controller registering the clusters of the discovery secrets of the given namespace
+func NewClusterDiscovery(namespace string, kubeClientset kubernetes.Interface, argoDB db.ArgoDB) *ClusterDiscovery {
+	c := &ClusterDiscovery{
+		namespace:     namespace,
+		kubeClientset: kubeClientset,
+		db:            argoDB,
+		informer: coreinformers.NewFilteredSecretInformer(kubeClientset, namespace, clusterDiscoveryResyncPeriod, cache.Indexers{}, func(options *metav1.ListOptions) {
+			options.LabelSelector = common.LabelKeyClusterDiscovery + "=true"
+		}),
+		queue:             workqueue.NewRateLimitingQueueWithConfig(workqueue.DefaultControllerRateLimiter(), workqueue.RateLimitingQueueConfig{Name: "cluster_discovery_queue"}),
+		checkConnectivity: checkClusterConnectivity,
+	}
+	_, err := c.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueue,
+		UpdateFunc: func(_, new interface{}) {
+			c.enqueue(new)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+				c.queue.Add(key)
+			}
+		},
+	})
+	if err != nil {
+		log.Errorf("Failed to register the cluster discovery event handler: %v", err)
+	}
+	return c
+}
+
+func (c *ClusterDiscovery) enqueue(obj interface{}) {
+	if key, err := cache.MetaNamespaceKeyFunc(obj); err == nil {
+		c.queue.Add(key)
+	}
+}
+
+// Run registers the clusters of the discovery secrets with the given number of workers until the context is done
+func (c *ClusterDiscovery) Run(ctx context.Context, workers int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	go c.informer.Run(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), c.informer.HasSynced) {
+		log.Error("Timed out waiting for the cluster discovery cache to sync")
+		return
+	}
+	log.Infof("Discovering clusters from the secrets of namespace %s labeled with %s=true", c.namespace, common.LabelKeyClusterDiscovery)
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, func(ctx context.Context) {
+			for c.processNextItem(ctx) {
+			}
+		}, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *ClusterDiscovery) processNextItem(ctx context.Context) bool {
+	key, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.reconcile(ctx, key.(string)); err != nil {
+		log.WithField("secret", key).Warnf("Failed to register discovered cluster: %v", err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// reconcile registers the cluster of the discovery secret of the given key, or deregisters the clusters registered
+// from it if the secret no longer exists
+func (c *ClusterDiscovery) reconcile(ctx context.Context, key string) error {
+	obj, exists, err := c.informer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return fmt.Errorf("error getting secret %s: %w", key, err)
+	}
+	registered, err := c.discoveredClusters(ctx, key)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return c.deregister(ctx, key, registered)
+	}
+	secret, ok := obj.(*v1.Secret)
+	if !ok {
+		return fmt.Errorf("unexpected object of type %T for secret %s", obj, key)
+	}
+
+	cluster, err := clusterFromDiscoverySecret(secret)
+	if err != nil {
+		return err
+	}
+	// the server of the secret changed, the cluster of the previous server is deregistered
+	var previous []*appv1.Cluster
+	for _, registeredCluster := range registered {
+		if registeredCluster.Server != cluster.Server {
+			previous = append(previous, registeredCluster)
+		}
+	}
+	if err := c.deregister(ctx, key, previous); err != nil {
+		return err
+	}
+	return c.register(ctx, key, cluster)
+}
+
+// register creates or updates the cluster of a discovery secret, once it is reachable
+func (c *ClusterDiscovery) register(ctx context.Context, key string, cluster *appv1.Cluster) error {
+	existing, err := c.db.GetCluster(ctx, cluster.Server)
+	if err != nil && status.Code(err) != codes.NotFound {
+		return fmt.Errorf("error getting cluster %s: %w", cluster.Server, err)
+	}
+	if err == nil {
+		if existing.Annotations[common.AnnotationKeyAutoDiscovered] != "true" {
+			return fmt.Errorf("cluster %s is already registered and was not discovered", cluster.Server)
+		}
+		if owner := existing.Annotations[common.AnnotationKeyDiscoverySecret]; owner != key {
+			return fmt.Errorf("cluster %s is already discovered from secret %s", cluster.Server, owner)
+		}
+		if existing.Name == cluster.Name && reflect.DeepEqual(existing.Config, cluster.Config) {
+			return nil
+		}
+	}
+
+	if err := c.checkConnectivity(cluster); err != nil {
+		return fmt.Errorf("cluster %s is not reachable: %w", cluster.Server, err)
+	}
+	if existing == nil {
+		if _, err := c.db.CreateCluster(ctx, cluster); err != nil {
+			return fmt.Errorf("error registering cluster %s: %w", cluster.Server, err)
+		}
+		log.WithField("secret", key).Infof("Registered discovered cluster %s", cluster.Server)
+		return nil
+	}
+	// the settings of the cluster made after its registration, like its namespaces or project, are preserved
+	updated := existing.DeepCopy()
+	updated.Name = cluster.Name
+	updated.Config = cluster.Config
+	if _, err := c.db.UpdateCluster(ctx, updated); err != nil {
+		return fmt.Errorf("error updating cluster %s: %w", cluster.Server, err)
+	}
+	log.WithField("secret", key).Infof("Updated discovered cluster %s", cluster.Server)
+	return nil
+}
+
+// deregister deletes the given clusters discovered from the secret of the given key
+func (c *ClusterDiscovery) deregister(ctx context.Context, key string, clusters []*appv1.Cluster) error {
+	for _, cluster := range clusters {
+		if err := c.db.DeleteCluster(ctx, cluster.Server); err != nil && status.Code(err) != codes.NotFound {
+			return fmt.Errorf("error deregistering cluster %s: %w", cluster.Server, err)
+		}
+		log.WithField("secret", key).Infof("Deregistered discovered cluster %s", cluster.Server)
+	}
+	return nil
+}
+
+// discoveredClusters returns the clusters discovered from the secret of the given key
+func (c *ClusterDiscovery) discoveredClusters(ctx context.Context, key string) ([]*appv1.Cluster, error) {
+	clusters, err := c.db.ListClusters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing clusters: %w", err)
+	}
+	var discovered []*appv1.Cluster
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		if cluster.Annotations[common.AnnotationKeyAutoDiscovered] == "true" && cluster.Annotations[common.AnnotationKeyDiscoverySecret] == key {
+			discovered = append(discovered, cluster)
+		}
+	}
+	return discovered, nil
+}
+
+// clusterFromDiscoverySecret returns the cluster whose credentials are stored in a discovery secret
+func clusterFromDiscoverySecret(secret *v1.Secret) (*appv1.Cluster, error) {
+	cluster := &appv1.Cluster{
+		Name: string(secret.Data[clusterDiscoveryKeyName]),
+		Annotations: map[string]string{
+			common.AnnotationKeyAutoDiscovered:  "true",
+			common.AnnotationKeyDiscoverySecret: secret.Namespace + "/" + secret.Name,
+		},
+	}
+	if kubeconfig, ok := secret.Data[clusterDiscoveryKeyKubeconfig]; ok {
+		if err := setKubeconfigCredentials(cluster, kubeconfig); err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig of secret %s/%s: %w", secret.Namespace, secret.Name, err)
+		}
+	} else {
+		cluster.Server = string(secret.Data[clusterDiscoveryKeyServer])
+		cluster.Config.BearerToken = string(secret.Data[clusterDiscoveryKeyToken])
+		cluster.Config.CAData = secret.Data[clusterDiscoveryKeyCA]
+		if cluster.Server == "" || cluster.Config.BearerToken == "" {
+			return nil, fmt.Errorf("secret %s/%s must hold either a %s, or a %s and a %s", secret.Namespace, secret.Name, clusterDiscoveryKeyKubeconfig, clusterDiscoveryKeyServer, clusterDiscoveryKeyToken)
+		}
+	}
+	cluster.Server = strings.TrimRight(cluster.Server, "/")
+	if cluster.Name == "" {
+		cluster.Name = secret.Name
+	}
+	return cluster, nil
+}
+
+// setKubeconfigCredentials sets the server and credentials of the current context of a kubeconfig to the cluster
+func setKubeconfigCredentials(cluster *appv1.Cluster, data []byte) error {
+	kubeconfig, err := clientcmd.Load(data)
+	if err != nil {
+		return err
+	}
+	kubeContext, ok := kubeconfig.Contexts[kubeconfig.CurrentContext]
+	if !ok {
+		return fmt.Errorf("current context %q not found", kubeconfig.CurrentContext)
+	}
+	kubeCluster, ok := kubeconfig.Clusters[kubeContext.Cluster]
+	if !ok {
+		return fmt.Errorf("cluster %q of context %q not found", kubeContext.Cluster, kubeconfig.CurrentContext)
+	}
+	authInfo, ok := kubeconfig.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return fmt.Errorf("user %q of context %q not found", kubeContext.AuthInfo, kubeconfig.CurrentContext)
+	}
+	if kubeCluster.CertificateAuthority != "" || authInfo.ClientCertificate != "" || authInfo.ClientKey != "" || authInfo.TokenFile != "" {
+		return fmt.Errorf("files cannot be referenced, their content must be embedded")
+	}
+	if authInfo.Exec != nil || authInfo.AuthProvider != nil {
+		return fmt.Errorf("exec and auth provider credentials are not supported")
+	}
+	cluster.Server = kubeCluster.Server
+	cluster.Config = appv1.ClusterConfig{
+		Username:    authInfo.Username,
+		Password:    authInfo.Password,
+		BearerToken: authInfo.Token,
+		TLSClientConfig: appv1.TLSClientConfig{
+			Insecure:   kubeCluster.InsecureSkipTLSVerify,
+			ServerName: kubeCluster.TLSServerName,
+			CAData:     kubeCluster.CertificateAuthorityData,
+			CertData:   authInfo.ClientCertificateData,
+			KeyData:    authInfo.ClientKeyData,
+		},
+	}
+	return nil
+}
+
+// checkClusterConnectivity returns an error if the version of the API server of the cluster cannot be retrieved
+func checkClusterConnectivity(cluster *appv1.Cluster) error {
+	config := cluster.RESTConfig()
+	config.Timeout = 10 * time.Second
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+	_, err = clientset.Discovery().ServerVersion()
+	return err
+}
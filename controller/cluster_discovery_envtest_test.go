@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/util/db"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+// TestClusterDiscovery_Envtest registers the API server of a local control plane started by envtest, from the kubeconfig
+// of a discovery secret stored in that same control plane. It requires the control plane binaries installed by
+// setup-envtest, located by the KUBEBUILDER_ASSETS environment variable.
+func TestClusterDiscovery_Envtest(t *testing.T) {
+	if os.Getenv("KUBEBUILDER_ASSETS") == "" {
+		t.Skip("KUBEBUILDER_ASSETS is not set, run setup-envtest to install the control plane binaries")
+	}
+	testEnv := &envtest.Environment{}
+	cfg, err := testEnv.Start()
+	require.NoError(t, err)
+	defer func() { assert.NoError(t, testEnv.Stop()) }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	require.NoError(t, err)
+	const namespace = "argocd"
+	labels := map[string]string{"app.kubernetes.io/part-of": "argocd"}
+	_, err = kubeClient.CoreV1().Namespaces().Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespace}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = kubeClient.CoreV1().ConfigMaps(namespace).Create(ctx, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: common.ArgoCDConfigMapName, Labels: labels}}, metav1.CreateOptions{})
+	require.NoError(t, err)
+	_, err = kubeClient.CoreV1().Secrets(namespace).Create(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ArgoCDSecretName, Labels: labels},
+		Data:       map[string][]byte{"server.secretkey": []byte("test")},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	argoDB := db.NewDB(namespace, settings.NewSettingsManager(ctx, kubeClient, namespace), kubeClient)
+	go NewClusterDiscovery(namespace, kubeClient, argoDB).Run(ctx, 1)
+
+	kubeconfig, err := clientcmd.Write(clientcmdapi.Config{
+		CurrentContext: "envtest",
+		Contexts:       map[string]*clientcmdapi.Context{"envtest": {Cluster: "envtest", AuthInfo: "admin"}},
+		Clusters:       map[string]*clientcmdapi.Cluster{"envtest": {Server: cfg.Host, CertificateAuthorityData: cfg.CAData}},
+		AuthInfos:      map[string]*clientcmdapi.AuthInfo{"admin": {ClientCertificateData: cfg.CertData, ClientKeyData: cfg.KeyData, Token: cfg.BearerToken}},
+	})
+	require.NoError(t, err)
+	secret := newDiscoverySecret("envtest", map[string]string{"kubeconfig": string(kubeconfig)})
+	secret.Namespace = namespace
+	_, err = kubeClient.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		cluster, err := argoDB.GetCluster(ctx, cfg.Host)
+		return err == nil && cluster.Name == "envtest" && cluster.Annotations[common.AnnotationKeyAutoDiscovered] == "true"
+	}, 30*time.Second, 100*time.Millisecond, "the cluster of the discovery secret was not registered")
+
+	require.NoError(t, kubeClient.CoreV1().Secrets(namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{}))
+	require.Eventually(t, func() bool {
+		_, err := argoDB.GetCluster(ctx, cfg.Host)
+		return err != nil
+	}, 30*time.Second, 100*time.Millisecond, "the cluster of the deleted discovery secret was not deregistered")
+}
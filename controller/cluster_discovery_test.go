@@ -0,0 +1,197 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/test"
+	"github.com/argoproj/argo-cd/v2/util/db"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+func newDiscoverySecret(name string, data map[string]string) *corev1.Secret {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: test.FakeArgoCDNamespace,
+			Labels:    map[string]string{common.LabelKeyClusterDiscovery: "true"},
+		},
+		Data: map[string][]byte{},
+	}
+	for key, value := range data {
+		secret.Data[key] = []byte(value)
+	}
+	return secret
+}
+
+// newFakeClusterDiscovery returns a cluster discovery whose informer cache holds the given discovery secrets and which
+// considers every cluster reachable but those of the unreachable servers
+func newFakeClusterDiscovery(t *testing.T, secrets []*corev1.Secret, objs ...runtime.Object) (*ClusterDiscovery, db.ArgoDB, map[string]bool) {
+	t.Helper()
+	objs = append(objs, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ArgoCDConfigMapName, Namespace: test.FakeArgoCDNamespace, Labels: map[string]string{"app.kubernetes.io/part-of": "argocd"}},
+	}, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ArgoCDSecretName, Namespace: test.FakeArgoCDNamespace, Labels: map[string]string{"app.kubernetes.io/part-of": "argocd"}},
+		Data:       map[string][]byte{"server.secretkey": []byte("test")},
+	})
+	for _, secret := range secrets {
+		objs = append(objs, secret)
+	}
+	kubeClient := fake.NewSimpleClientset(objs...)
+	argoDB := db.NewDB(test.FakeArgoCDNamespace, settings.NewSettingsManager(context.Background(), kubeClient, test.FakeArgoCDNamespace), kubeClient)
+	c := NewClusterDiscovery(test.FakeArgoCDNamespace, kubeClient, argoDB)
+	for _, secret := range secrets {
+		require.NoError(t, c.informer.GetIndexer().Add(secret))
+	}
+	unreachable := map[string]bool{}
+	c.checkConnectivity = func(cluster *v1alpha1.Cluster) error {
+		if unreachable[cluster.Server] {
+			return errors.New("connection refused")
+		}
+		return nil
+	}
+	return c, argoDB, unreachable
+}
+
+func testKubeconfig(t *testing.T) string {
+	t.Helper()
+	data, err := clientcmd.Write(clientcmdapi.Config{
+		CurrentContext: "staging",
+		Contexts:       map[string]*clientcmdapi.Context{"staging": {Cluster: "staging", AuthInfo: "argocd"}},
+		Clusters:       map[string]*clientcmdapi.Cluster{"staging": {Server: "https://staging.example.com/", CertificateAuthorityData: []byte("ca")}},
+		AuthInfos:      map[string]*clientcmdapi.AuthInfo{"argocd": {ClientCertificateData: []byte("cert"), ClientKeyData: []byte("key")}},
+	})
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestClusterFromDiscoverySecret(t *testing.T) {
+	t.Run("ServiceAccountToken", func(t *testing.T) {
+		cluster, err := clusterFromDiscoverySecret(newDiscoverySecret("production", map[string]string{"server": "https://production.example.com", "token": "s3cr3t", "ca.crt": "ca"}))
+		require.NoError(t, err)
+		assert.Equal(t, "production", cluster.Name)
+		assert.Equal(t, "https://production.example.com", cluster.Server)
+		assert.Equal(t, v1alpha1.ClusterConfig{BearerToken: "s3cr3t", TLSClientConfig: v1alpha1.TLSClientConfig{CAData: []byte("ca")}}, cluster.Config)
+		assert.Equal(t, map[string]string{
+			common.AnnotationKeyAutoDiscovered:  "true",
+			common.AnnotationKeyDiscoverySecret: test.FakeArgoCDNamespace + "/production",
+		}, cluster.Annotations)
+	})
+
+	t.Run("Kubeconfig", func(t *testing.T) {
+		cluster, err := clusterFromDiscoverySecret(newDiscoverySecret("staging-credentials", map[string]string{"kubeconfig": testKubeconfig(t), "name": "staging"}))
+		require.NoError(t, err)
+		assert.Equal(t, "staging", cluster.Name)
+		assert.Equal(t, "https://staging.example.com", cluster.Server)
+		assert.Equal(t, v1alpha1.TLSClientConfig{CAData: []byte("ca"), CertData: []byte("cert"), KeyData: []byte("key")}, cluster.Config.TLSClientConfig)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := clusterFromDiscoverySecret(newDiscoverySecret("production", map[string]string{"server": "https://production.example.com"}))
+		assert.ErrorContains(t, err, "must hold either a kubeconfig, or a server and a token")
+
+		_, err = clusterFromDiscoverySecret(newDiscoverySecret("staging", map[string]string{"kubeconfig": "current-context: missing"}))
+		assert.ErrorContains(t, err, `current context "missing" not found`)
+	})
+}
+
+func TestClusterDiscovery_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	key := test.FakeArgoCDNamespace + "/production"
+
+	t.Run("Register", func(t *testing.T) {
+		secret := newDiscoverySecret("production", map[string]string{"server": "https://production.example.com", "token": "s3cr3t"})
+		c, argoDB, _ := newFakeClusterDiscovery(t, []*corev1.Secret{secret})
+		require.NoError(t, c.reconcile(ctx, key))
+
+		cluster, err := argoDB.GetCluster(ctx, "https://production.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "production", cluster.Name)
+		assert.Equal(t, "s3cr3t", cluster.Config.BearerToken)
+		assert.Equal(t, "true", cluster.Annotations[common.AnnotationKeyAutoDiscovered])
+		assert.Equal(t, key, cluster.Annotations[common.AnnotationKeyDiscoverySecret])
+
+		// the rotated token is updated, the namespaces set after the registration are preserved
+		cluster.Namespaces = []string{"guestbook"}
+		_, err = argoDB.UpdateCluster(ctx, cluster)
+		require.NoError(t, err)
+		secret = secret.DeepCopy()
+		secret.Data["token"] = []byte("rotated")
+		require.NoError(t, c.informer.GetIndexer().Update(secret))
+		require.NoError(t, c.reconcile(ctx, key))
+		cluster, err = argoDB.GetCluster(ctx, "https://production.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "rotated", cluster.Config.BearerToken)
+		assert.Equal(t, []string{"guestbook"}, cluster.Namespaces)
+	})
+
+	t.Run("Deregister", func(t *testing.T) {
+		secret := newDiscoverySecret("production", map[string]string{"server": "https://production.example.com", "token": "s3cr3t"})
+		c, argoDB, _ := newFakeClusterDiscovery(t, []*corev1.Secret{secret})
+		require.NoError(t, c.reconcile(ctx, key))
+
+		require.NoError(t, c.informer.GetIndexer().Delete(secret))
+		require.NoError(t, c.reconcile(ctx, key))
+		_, err := argoDB.GetCluster(ctx, "https://production.example.com")
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("ServerChanged", func(t *testing.T) {
+		secret := newDiscoverySecret("production", map[string]string{"server": "https://production.example.com", "token": "s3cr3t"})
+		c, argoDB, _ := newFakeClusterDiscovery(t, []*corev1.Secret{secret})
+		require.NoError(t, c.reconcile(ctx, key))
+
+		secret = secret.DeepCopy()
+		secret.Data["server"] = []byte("https://production-eu.example.com")
+		require.NoError(t, c.informer.GetIndexer().Update(secret))
+		require.NoError(t, c.reconcile(ctx, key))
+		_, err := argoDB.GetCluster(ctx, "https://production.example.com")
+		assert.ErrorContains(t, err, "not found")
+		_, err = argoDB.GetCluster(ctx, "https://production-eu.example.com")
+		assert.NoError(t, err)
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		secret := newDiscoverySecret("production", map[string]string{"server": "https://production.example.com", "token": "s3cr3t"})
+		c, argoDB, unreachable := newFakeClusterDiscovery(t, []*corev1.Secret{secret})
+		unreachable["https://production.example.com"] = true
+		assert.ErrorContains(t, c.reconcile(ctx, key), "cluster https://production.example.com is not reachable: connection refused")
+		_, err := argoDB.GetCluster(ctx, "https://production.example.com")
+		assert.ErrorContains(t, err, "not found")
+
+		delete(unreachable, "https://production.example.com")
+		require.NoError(t, c.reconcile(ctx, key))
+		_, err = argoDB.GetCluster(ctx, "https://production.example.com")
+		assert.NoError(t, err)
+	})
+
+	t.Run("ManuallyRegistered", func(t *testing.T) {
+		secret := newDiscoverySecret("production", map[string]string{"server": "https://production.example.com", "token": "s3cr3t"})
+		c, argoDB, _ := newFakeClusterDiscovery(t, []*corev1.Secret{secret})
+		_, err := argoDB.CreateCluster(ctx, &v1alpha1.Cluster{Name: "production", Server: "https://production.example.com", Config: v1alpha1.ClusterConfig{BearerToken: "manual"}})
+		require.NoError(t, err)
+
+		assert.ErrorContains(t, c.reconcile(ctx, key), "already registered and was not discovered")
+		cluster, err := argoDB.GetCluster(ctx, "https://production.example.com")
+		require.NoError(t, err)
+		assert.Equal(t, "manual", cluster.Config.BearerToken)
+
+		// a manually registered cluster is not deregistered with the discovery secret
+		require.NoError(t, c.informer.GetIndexer().Delete(secret))
+		require.NoError(t, c.reconcile(ctx, key))
+		_, err = argoDB.GetCluster(ctx, "https://production.example.com")
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	applisters "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
+)
+
+// clusterHealthResyncInterval is the interval at which the cluster health summary is recomputed even if no
+// application was reconciled, so that deleted applications are eventually removed from it
+const clusterHealthResyncInterval = 1 * time.Minute
+
+var descClusterAppHealthCount = prometheus.NewDesc(
+	"argocd_cluster_app_health_count",
+	"Number of applications deployed to the cluster per health status.",
+	[]string{"cluster", "health_status"},
+	nil,
+)
+
+// ClusterHealthSummary is the number of applications per health status of each cluster, keyed by the destination
+// server of the applications, or the destination name if the server is not set
+type ClusterHealthSummary map[string]map[health.HealthStatusCode]int
+
+// SummarizeClusterHealth counts the applications per health status of each cluster. Applications which were not
+// reconciled yet are counted as Unknown.
+func SummarizeClusterHealth(apps []*appv1.Application) ClusterHealthSummary {
+	summary := ClusterHealthSummary{}
+	for _, app := range apps {
+		cluster := app.Spec.Destination.Server
+		if cluster == "" {
+			cluster = app.Spec.Destination.Name
+		}
+		status := app.Status.Health.Status
+		if status == "" {
+			status = health.HealthStatusUnknown
+		}
+		if summary[cluster] == nil {
+			summary[cluster] = map[health.HealthStatusCode]int{}
+		}
+		summary[cluster][status]++
+	}
+	return summary
+}
+
+// ClusterHealthAggregator maintains the argocd_cluster_app_health_count gauges from the applications of the informer
+// cache. The summary is recomputed in the background after applications are reconciled.
+type ClusterHealthAggregator struct {
+	appLister applisters.ApplicationLister
+	appFilter func(obj interface{}) bool
+	// updates is signaled when an application was reconciled and the summary needs to be recomputed
+	updates chan struct{}
+
+	lock    sync.RWMutex
+	summary ClusterHealthSummary
+}
+
+// NewClusterHealthAggregator returns an aggregator of the health of the applications returned by appLister which
+// match appFilter
+func NewClusterHealthAggregator(appLister applisters.ApplicationLister, appFilter func(obj interface{}) bool) *ClusterHealthAggregator {
+	return &ClusterHealthAggregator{
+		appLister: appLister,
+		appFilter: appFilter,
+		updates:   make(chan struct{}, 1),
+		summary:   ClusterHealthSummary{},
+	}
+}
+
+// Notify requests the summary to be recomputed. It never blocks, requests made while an update is pending are merged.
+func (a *ClusterHealthAggregator) Notify() {
+	select {
+	case a.updates <- struct{}{}:
+	default:
+	}
+}
+
+// Run recomputes the summary whenever Notify is called, and every resyncInterval, until the context is done
+func (a *ClusterHealthAggregator) Run(ctx context.Context, resyncInterval time.Duration) {
+	ticker := time.NewTicker(resyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.updates:
+		case <-ticker.C:
+		}
+		if err := a.update(); err != nil {
+			log.Warnf("Failed to aggregate the health of the applications of each cluster: %v", err)
+		}
+	}
+}
+
+func (a *ClusterHealthAggregator) update() error {
+	apps, err := a.appLister.List(labels.Everything())
+	if err != nil {
+		return err
+	}
+	filtered := make([]*appv1.Application, 0, len(apps))
+	for _, app := range apps {
+		if a.appFilter == nil || a.appFilter(app) {
+			filtered = append(filtered, app)
+		}
+	}
+	summary := SummarizeClusterHealth(filtered)
+
+	a.lock.Lock()
+	a.summary = summary
+	a.lock.Unlock()
+	return nil
+}
+
+// Summary returns a copy of the last computed summary
+func (a *ClusterHealthAggregator) Summary() ClusterHealthSummary {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	summary := make(ClusterHealthSummary, len(a.summary))
+	for cluster, counts := range a.summary {
+		summary[cluster] = make(map[health.HealthStatusCode]int, len(counts))
+		for status, count := range counts {
+			summary[cluster][status] = count
+		}
+	}
+	return summary
+}
+
+// Describe implements the prometheus.Collector interface
+func (a *ClusterHealthAggregator) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descClusterAppHealthCount
+}
+
+// Collect implements the prometheus.Collector interface
+func (a *ClusterHealthAggregator) Collect(ch chan<- prometheus.Metric) {
+	a.lock.RLock()
+	defer a.lock.RUnlock()
+	for cluster, counts := range a.summary {
+		for status, count := range counts {
+			ch <- prometheus.MustNewConstMetric(descClusterAppHealthCount, prometheus.GaugeValue, float64(count), cluster, string(status))
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	applisters "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+func newClusterHealthTestApp(name, server string, status health.HealthStatusCode) *appv1.Application {
+	return &appv1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: test.FakeArgoCDNamespace},
+		Spec: appv1.ApplicationSpec{
+			Destination: appv1.ApplicationDestination{Server: server, Namespace: "default"},
+		},
+		Status: appv1.ApplicationStatus{Health: appv1.HealthStatus{Status: status}},
+	}
+}
+
+// newClusterHealthTestApps returns applications deployed to three clusters with mixed health
+func newClusterHealthTestApps() []*appv1.Application {
+	return []*appv1.Application{
+		newClusterHealthTestApp("a1", "https://cluster-a", health.HealthStatusHealthy),
+		newClusterHealthTestApp("a2", "https://cluster-a", health.HealthStatusHealthy),
+		newClusterHealthTestApp("a3", "https://cluster-a", health.HealthStatusDegraded),
+		newClusterHealthTestApp("b1", "https://cluster-b", health.HealthStatusProgressing),
+		newClusterHealthTestApp("b2", "https://cluster-b", health.HealthStatusMissing),
+		newClusterHealthTestApp("b3", "https://cluster-b", health.HealthStatusHealthy),
+		newClusterHealthTestApp("c1", "https://cluster-c", health.HealthStatusSuspended),
+		newClusterHealthTestApp("c2", "https://cluster-c", health.HealthStatusDegraded),
+		newClusterHealthTestApp("c3", "https://cluster-c", health.HealthStatusDegraded),
+		newClusterHealthTestApp("c4", "https://cluster-c", ""),
+	}
+}
+
+func newClusterHealthTestLister(t *testing.T, apps ...*appv1.Application) (applisters.ApplicationLister, cache.Indexer) {
+	t.Helper()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, app := range apps {
+		require.NoError(t, indexer.Add(app))
+	}
+	return applisters.NewApplicationLister(indexer), indexer
+}
+
+func TestSummarizeClusterHealth(t *testing.T) {
+	apps := newClusterHealthTestApps()
+	apps = append(apps, &appv1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "named", Namespace: test.FakeArgoCDNamespace},
+		Spec:       appv1.ApplicationSpec{Destination: appv1.ApplicationDestination{Name: "in-cluster"}},
+		Status:     appv1.ApplicationStatus{Health: appv1.HealthStatus{Status: health.HealthStatusHealthy}},
+	})
+
+	summary := SummarizeClusterHealth(apps)
+
+	assert.Equal(t, ClusterHealthSummary{
+		"https://cluster-a": {health.HealthStatusHealthy: 2, health.HealthStatusDegraded: 1},
+		"https://cluster-b": {health.HealthStatusProgressing: 1, health.HealthStatusMissing: 1, health.HealthStatusHealthy: 1},
+		"https://cluster-c": {health.HealthStatusSuspended: 1, health.HealthStatusDegraded: 2, health.HealthStatusUnknown: 1},
+		"in-cluster":        {health.HealthStatusHealthy: 1},
+	}, summary)
+}
+
+func TestClusterHealthAggregator(t *testing.T) {
+	t.Run("exports the summary as gauges", func(t *testing.T) {
+		lister, _ := newClusterHealthTestLister(t, newClusterHealthTestApps()...)
+		aggregator := NewClusterHealthAggregator(lister, nil)
+		require.NoError(t, aggregator.update())
+
+		expected := `
+# HELP argocd_cluster_app_health_count Number of applications deployed to the cluster per health status.
+# TYPE argocd_cluster_app_health_count gauge
+argocd_cluster_app_health_count{cluster="https://cluster-a",health_status="Degraded"} 1
+argocd_cluster_app_health_count{cluster="https://cluster-a",health_status="Healthy"} 2
+argocd_cluster_app_health_count{cluster="https://cluster-b",health_status="Healthy"} 1
+argocd_cluster_app_health_count{cluster="https://cluster-b",health_status="Missing"} 1
+argocd_cluster_app_health_count{cluster="https://cluster-b",health_status="Progressing"} 1
+argocd_cluster_app_health_count{cluster="https://cluster-c",health_status="Degraded"} 2
+argocd_cluster_app_health_count{cluster="https://cluster-c",health_status="Suspended"} 1
+argocd_cluster_app_health_count{cluster="https://cluster-c",health_status="Unknown"} 1
+`
+		require.NoError(t, testutil.CollectAndCompare(aggregator, strings.NewReader(expected)))
+	})
+
+	t.Run("skips applications not matching the filter", func(t *testing.T) {
+		lister, _ := newClusterHealthTestLister(t, newClusterHealthTestApps()...)
+		aggregator := NewClusterHealthAggregator(lister, func(obj interface{}) bool {
+			return obj.(*appv1.Application).Spec.Destination.Server != "https://cluster-b"
+		})
+		require.NoError(t, aggregator.update())
+
+		summary := aggregator.Summary()
+		assert.Len(t, summary, 2)
+		assert.NotContains(t, summary, "https://cluster-b")
+		assert.Equal(t, 2, summary["https://cluster-a"][health.HealthStatusHealthy])
+	})
+
+	t.Run("recomputes the summary when notified", func(t *testing.T) {
+		apps := newClusterHealthTestApps()
+		lister, indexer := newClusterHealthTestLister(t, apps...)
+		aggregator := NewClusterHealthAggregator(lister, nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go aggregator.Run(ctx, time.Hour)
+
+		aggregator.Notify()
+		require.Eventually(t, func() bool {
+			return aggregator.Summary()["https://cluster-a"][health.HealthStatusDegraded] == 1
+		}, 5*time.Second, 10*time.Millisecond)
+
+		recovered := apps[2].DeepCopy()
+		recovered.Status.Health.Status = health.HealthStatusHealthy
+		require.NoError(t, indexer.Update(recovered))
+		require.NoError(t, indexer.Delete(apps[3]))
+		aggregator.Notify()
+		require.Eventually(t, func() bool {
+			summary := aggregator.Summary()
+			return summary["https://cluster-a"][health.HealthStatusHealthy] == 3 &&
+				summary["https://cluster-a"][health.HealthStatusDegraded] == 0 &&
+				summary["https://cluster-b"][health.HealthStatusProgressing] == 0
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Equal(t, 6, testutil.CollectAndCount(aggregator, "argocd_cluster_app_health_count"))
+	})
+
+	t.Run("summary is a copy", func(t *testing.T) {
+		lister, _ := newClusterHealthTestLister(t, newClusterHealthTestApps()...)
+		aggregator := NewClusterHealthAggregator(lister, nil)
+		require.NoError(t, aggregator.update())
+
+		aggregator.Summary()["https://cluster-a"][health.HealthStatusHealthy] = 10
+		assert.Equal(t, 2, aggregator.Summary()["https://cluster-a"][health.HealthStatusHealthy])
+	})
+}
@@ -0,0 +1,66 @@
+package controller
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// conditionTransitions returns the transitions of the health status, the sync status and the conditions between the
+// original and the new status of an application, to be recorded in its condition history
+func conditionTransitions(orig *appv1.ApplicationStatus, newStatus *appv1.ApplicationStatus, now metav1.Time) []appv1.ConditionHistoryEntry {
+	var entries []appv1.ConditionHistoryEntry
+	if orig.Health.Status != newStatus.Health.Status {
+		entries = append(entries, appv1.ConditionHistoryEntry{
+			Type:               appv1.ConditionHistoryTypeHealth,
+			Status:             string(newStatus.Health.Status),
+			LastTransitionTime: &now,
+			Message:            newStatus.Health.Message,
+		})
+	}
+	if orig.Sync.Status != newStatus.Sync.Status {
+		entries = append(entries, appv1.ConditionHistoryEntry{
+			Type:               appv1.ConditionHistoryTypeSync,
+			Status:             string(newStatus.Sync.Status),
+			LastTransitionTime: &now,
+		})
+	}
+
+	origTypes := conditionTypes(orig.Conditions)
+	newTypes := conditionTypes(newStatus.Conditions)
+	recorded := map[appv1.ApplicationConditionType]bool{}
+	for _, condition := range newStatus.Conditions {
+		if origTypes[condition.Type] || recorded[condition.Type] {
+			continue
+		}
+		recorded[condition.Type] = true
+		entries = append(entries, appv1.ConditionHistoryEntry{
+			Type:               string(condition.Type),
+			Status:             appv1.ConditionHistoryStatusTrue,
+			LastTransitionTime: &now,
+			Message:            condition.Message,
+		})
+	}
+	for _, condition := range orig.Conditions {
+		if newTypes[condition.Type] || recorded[condition.Type] {
+			continue
+		}
+		recorded[condition.Type] = true
+		entries = append(entries, appv1.ConditionHistoryEntry{
+			Type:               string(condition.Type),
+			Status:             appv1.ConditionHistoryStatusFalse,
+			LastTransitionTime: &now,
+			Message:            condition.Message,
+		})
+	}
+	return entries
+}
+
+// conditionTypes returns the set of the types of the given conditions
+func conditionTypes(conditions []appv1.ApplicationCondition) map[appv1.ApplicationConditionType]bool {
+	types := make(map[appv1.ApplicationConditionType]bool, len(conditions))
+	for _, condition := range conditions {
+		types[condition.Type] = true
+	}
+	return types
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func TestConditionTransitions(t *testing.T) {
+	now := metav1.NewTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	orig := &appv1.ApplicationStatus{
+		Health: appv1.HealthStatus{Status: health.HealthStatusHealthy},
+		Sync:   appv1.SyncStatus{Status: appv1.SyncStatusCodeSynced},
+		Conditions: []appv1.ApplicationCondition{
+			{Type: appv1.ApplicationConditionComparisonError, Message: "comparison failed"},
+		},
+	}
+	newStatus := &appv1.ApplicationStatus{
+		Health: appv1.HealthStatus{Status: health.HealthStatusDegraded, Message: "pod crashing"},
+		Sync:   appv1.SyncStatus{Status: appv1.SyncStatusCodeSynced},
+		Conditions: []appv1.ApplicationCondition{
+			{Type: appv1.ApplicationConditionSyncError, Message: "sync failed"},
+			{Type: appv1.ApplicationConditionSyncError, Message: "sync failed again"},
+		},
+	}
+
+	assert.Equal(t, []appv1.ConditionHistoryEntry{
+		{Type: appv1.ConditionHistoryTypeHealth, Status: "Degraded", LastTransitionTime: &now, Message: "pod crashing"},
+		{Type: appv1.ApplicationConditionSyncError, Status: appv1.ConditionHistoryStatusTrue, LastTransitionTime: &now, Message: "sync failed"},
+		{Type: appv1.ApplicationConditionComparisonError, Status: appv1.ConditionHistoryStatusFalse, LastTransitionTime: &now, Message: "comparison failed"},
+	}, conditionTransitions(orig, newStatus, now))
+
+	assert.Empty(t, conditionTransitions(newStatus, newStatus.DeepCopy(), now))
+}
+
+func TestConditionTransitions_HistoryAccumulation(t *testing.T) {
+	status := &appv1.ApplicationStatus{}
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	healthStatuses := []health.HealthStatusCode{
+		health.HealthStatusProgressing,
+		health.HealthStatusHealthy,
+		health.HealthStatusHealthy,
+		health.HealthStatusDegraded,
+		health.HealthStatusHealthy,
+	}
+	for i, healthStatus := range healthStatuses {
+		newStatus := status.DeepCopy()
+		newStatus.Health.Status = healthStatus
+		newStatus.AddConditionHistory(conditionTransitions(status, newStatus, metav1.NewTime(start.Add(time.Duration(i)*time.Minute)))...)
+		status = newStatus
+	}
+
+	require.Len(t, status.ConditionHistory, 4)
+	var statuses []string
+	for _, entry := range status.ConditionHistory {
+		assert.Equal(t, appv1.ConditionHistoryTypeHealth, entry.Type)
+		statuses = append(statuses, entry.Status)
+	}
+	assert.Equal(t, []string{"Progressing", "Healthy", "Degraded", "Healthy"}, statuses)
+	assert.Equal(t, start.Add(3*time.Minute), status.ConditionHistory[2].LastTransitionTime.Time)
+	assert.Equal(t, start.Add(4*time.Minute), status.ConditionHistory[3].LastTransitionTime.Time)
+
+	// only the last transitions are kept
+	for i := 0; i < appv1.ConditionHistoryLimit; i++ {
+		newStatus := status.DeepCopy()
+		newStatus.Health.Status = healthStatuses[i%2]
+		if newStatus.Health.Status == status.Health.Status {
+			newStatus.Health.Status = health.HealthStatusMissing
+		}
+		newStatus.AddConditionHistory(conditionTransitions(status, newStatus, metav1.Now())...)
+		status = newStatus
+	}
+	require.Len(t, status.ConditionHistory, appv1.ConditionHistoryLimit)
+	assert.Equal(t, string(status.Health.Status), status.ConditionHistory[appv1.ConditionHistoryLimit-1].Status)
+}
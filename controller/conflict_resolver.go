@@ -0,0 +1,250 @@
+package controller
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/argoproj/gitops-engine/pkg/sync"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+)
+
+// MergedConflict is a field of a server-side applied resource whose value differs from the value owned by other field
+// managers, as resolved by the merge conflict resolution
+type MergedConflict struct {
+	// Field is the path of the conflicting field, e.g. .spec.replicas
+	Field string
+	// Managers are the other field managers owning the field
+	Managers []string
+	// Forced is true if the field is also owned by Argo CD and is taken over, false if it is left to the other managers
+	Forced bool
+}
+
+// ConflictResolver resolves the field manager conflicts of server-side applied resources with the merge conflict
+// resolution: the conflicting fields which are owned by Argo CD are forced, while the conflicting fields which are
+// only owned by other field managers, e.g. the replicas of a deployment scaled by a HorizontalPodAutoscaler, are
+// removed from the applied resource so that the other managers keep them.
+//
+// Conflicts are detected from the managed fields of the live resource rather than with a dry-run apply, so that the
+// applied resources can be resolved again each time the sync is resumed.
+type ConflictResolver struct {
+	// managers are the field managers of Argo CD
+	managers map[string]bool
+}
+
+// NewConflictResolver returns a ConflictResolver for which the fields owned by the given field managers are owned by
+// Argo CD
+func NewConflictResolver(managers ...string) *ConflictResolver {
+	r := &ConflictResolver{managers: map[string]bool{}}
+	for _, manager := range managers {
+		r.managers[manager] = true
+	}
+	return r
+}
+
+// Merge returns a copy of the target without the conflicting fields which are only owned by other field managers,
+// along with the conflicts of the target. The target is returned as is if it has no conflicts.
+func (r *ConflictResolver) Merge(live, target *unstructured.Unstructured) (*unstructured.Unstructured, []MergedConflict, error) {
+	owned := &fieldpath.Set{}
+	others := map[string]*fieldpath.Set{}
+	for _, entry := range live.GetManagedFields() {
+		// the status is not applied with the resource
+		if entry.Subresource == "status" || entry.FieldsV1 == nil {
+			continue
+		}
+		fields := &fieldpath.Set{}
+		if err := fields.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, nil, fmt.Errorf("error parsing managed fields of %s: %w", entry.Manager, err)
+		}
+		if r.managers[entry.Manager] {
+			owned = owned.Union(fields)
+		} else if set, ok := others[entry.Manager]; ok {
+			others[entry.Manager] = set.Union(fields)
+		} else {
+			others[entry.Manager] = fields
+		}
+	}
+
+	conflicts := map[string]*MergedConflict{}
+	var removed []fieldpath.Path
+	for manager, fields := range others {
+		fields.Leaves().Iterate(func(path fieldpath.Path) {
+			targetValue, inTarget := fieldValue(target.Object, path)
+			liveValue, inLive := fieldValue(live.Object, path)
+			if !inTarget || !inLive || value.Equals(value.NewValueInterface(targetValue), value.NewValueInterface(liveValue)) {
+				return
+			}
+			field := path.String()
+			conflict, ok := conflicts[field]
+			if !ok {
+				conflict = &MergedConflict{Field: field, Forced: owned.Has(path)}
+				conflicts[field] = conflict
+				if !conflict.Forced {
+					removed = append(removed, path.Copy())
+				}
+			}
+			conflict.Managers = append(conflict.Managers, manager)
+		})
+	}
+	if len(conflicts) == 0 {
+		return target, nil, nil
+	}
+
+	merged := target.DeepCopy()
+	for _, path := range removed {
+		merged.Object = removeField(merged.Object, path).(map[string]interface{})
+	}
+	result := make([]MergedConflict, 0, len(conflicts))
+	for _, conflict := range conflicts {
+		sort.Strings(conflict.Managers)
+		result = append(result, *conflict)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Field < result[j].Field
+	})
+	return merged, result, nil
+}
+
+// String describes the conflict, e.g. `.spec.replicas owned by "kube-controller-manager"`
+func (c MergedConflict) String() string {
+	managers := make([]string, len(c.Managers))
+	for i, manager := range c.Managers {
+		managers[i] = fmt.Sprintf("%q", manager)
+	}
+	return fmt.Sprintf("%s owned by %s", c.Field, strings.Join(managers, ", "))
+}
+
+// fieldValue returns the value of the field at the given path of an unstructured object
+func fieldValue(obj interface{}, path fieldpath.Path) (interface{}, bool) {
+	for _, element := range path {
+		switch {
+		case element.FieldName != nil:
+			m, ok := obj.(map[string]interface{})
+			if !ok {
+				return nil, false
+			}
+			if obj, ok = m[*element.FieldName]; !ok {
+				return nil, false
+			}
+		default:
+			items, ok := obj.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			i := listItemIndex(items, element)
+			if i < 0 {
+				return nil, false
+			}
+			obj = items[i]
+		}
+	}
+	return obj, true
+}
+
+// removeField returns the unstructured object without the field at the given path
+func removeField(obj interface{}, path fieldpath.Path) interface{} {
+	if len(path) == 0 {
+		return obj
+	}
+	element := path[0]
+	if element.FieldName != nil {
+		m, ok := obj.(map[string]interface{})
+		if !ok {
+			return obj
+		}
+		child, ok := m[*element.FieldName]
+		if !ok {
+			return obj
+		}
+		if len(path) == 1 {
+			delete(m, *element.FieldName)
+		} else {
+			m[*element.FieldName] = removeField(child, path[1:])
+		}
+		return m
+	}
+	items, ok := obj.([]interface{})
+	if !ok {
+		return obj
+	}
+	i := listItemIndex(items, element)
+	if i < 0 {
+		return obj
+	}
+	if len(path) == 1 {
+		return append(items[:i], items[i+1:]...)
+	}
+	items[i] = removeField(items[i], path[1:])
+	return items
+}
+
+// listItemIndex returns the index of the list item identified by the path element, or -1 if the list has no such item
+func listItemIndex(items []interface{}, element fieldpath.PathElement) int {
+	switch {
+	case element.Index != nil:
+		if *element.Index < len(items) {
+			return *element.Index
+		}
+	case element.Value != nil:
+		for i, item := range items {
+			if value.Equals(value.NewValueInterface(item), *element.Value) {
+				return i
+			}
+		}
+	case element.Key != nil:
+	next:
+		for i, item := range items {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, key := range *element.Key {
+				v, ok := m[key.Name]
+				if !ok || !value.Equals(value.NewValueInterface(v), key.Value) {
+					continue next
+				}
+			}
+			return i
+		}
+	}
+	return -1
+}
+
+// mergeConflicts resolves the field manager conflicts of the server-side applied targets with the merge conflict
+// resolution. The targets of the reconciliation result are replaced by the merged resources. The conflicts are
+// reported if report is true, with a ConflictResolved event for each forced field.
+func (m *appStateManager) mergeConflicts(app *v1alpha1.Application, syncOp v1alpha1.SyncOperation, reconciliationResult sync.ReconciliationResult, report bool, logEntry *log.Entry) error {
+	resolver := NewConflictResolver(clientSideApplyManagers...)
+	for _, i := range serverSideApplyTargets(syncOp, reconciliationResult) {
+		live := reconciliationResult.Live[i]
+		merged, conflicts, err := resolver.Merge(live, reconciliationResult.Target[i])
+		if err != nil {
+			return fmt.Errorf("error merging conflicts of %s %s/%s: %w", live.GetKind(), live.GetNamespace(), live.GetName(), err)
+		}
+		reconciliationResult.Target[i] = merged
+		if !report {
+			continue
+		}
+		resourceLog := logEntry.WithFields(log.Fields{"kind": live.GetKind(), "namespace": live.GetNamespace(), "name": live.GetName()})
+		for _, conflict := range conflicts {
+			if !conflict.Forced {
+				resourceLog.Infof("Leaving conflicting field %s to its field manager", conflict)
+				continue
+			}
+			resourceLog.Infof("Forcing conflicting field %s", conflict)
+			if m.auditLogger != nil {
+				message := fmt.Sprintf("forced field %s of %s %s/%s", conflict, live.GetKind(), live.GetNamespace(), live.GetName())
+				m.auditLogger.LogAppEvent(app, argo.EventInfo{Reason: argo.EventReasonConflictResolved, Type: corev1.EventTypeNormal}, message, "", nil)
+			}
+		}
+	}
+	return nil
+}
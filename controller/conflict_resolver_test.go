@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+func managedFieldsEntry(manager string, operation metav1.ManagedFieldsOperationType, subresource, fields string) metav1.ManagedFieldsEntry {
+	return metav1.ManagedFieldsEntry{
+		Manager:     manager,
+		Operation:   operation,
+		APIVersion:  "apps/v1",
+		FieldsType:  "FieldsV1",
+		FieldsV1:    &metav1.FieldsV1{Raw: []byte(fields)},
+		Subresource: subresource,
+	}
+}
+
+func TestConflictResolver_Merge(t *testing.T) {
+	live := test.YamlToUnstructured(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: guestbook-ui
+  namespace: default
+  labels:
+    team: guestbook
+spec:
+  replicas: 3
+  template:
+    spec:
+      containers:
+      - name: guestbook-ui
+        image: guestbook:1.0
+      - name: sidecar
+        image: busybox:1.36
+status:
+  replicas: 3
+`)
+	live.SetManagedFields([]metav1.ManagedFieldsEntry{
+		managedFieldsEntry("argocd-controller", metav1.ManagedFieldsOperationApply, "", `{"f:metadata":{"f:labels":{"f:team":{}}},"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"guestbook-ui\"}":{".":{},"f:image":{},"f:name":{}},"k:{\"name\":\"sidecar\"}":{".":{},"f:image":{},"f:name":{}}}}}}}`),
+		managedFieldsEntry("kube-controller-manager", metav1.ManagedFieldsOperationUpdate, "scale", `{"f:spec":{"f:replicas":{}}}`),
+		managedFieldsEntry("kube-controller-manager", metav1.ManagedFieldsOperationUpdate, "status", `{"f:status":{"f:replicas":{}}}`),
+		managedFieldsEntry("image-updater", metav1.ManagedFieldsOperationApply, "", `{"f:spec":{"f:template":{"f:spec":{"f:containers":{"k:{\"name\":\"guestbook-ui\"}":{".":{},"f:image":{},"f:name":{}}}}}}}`),
+		managedFieldsEntry("kubectl-label", metav1.ManagedFieldsOperationUpdate, "", `{"f:metadata":{"f:labels":{"f:team":{}}}}`),
+	})
+	resolver := NewConflictResolver(clientSideApplyManagers...)
+
+	t.Run("NoConflict", func(t *testing.T) {
+		target := test.YamlToUnstructured(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: guestbook-ui
+  namespace: default
+  labels:
+    team: guestbook
+spec:
+  template:
+    spec:
+      containers:
+      - name: guestbook-ui
+        image: guestbook:1.0
+`)
+		merged, conflicts, err := resolver.Merge(live, target)
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+		assert.Same(t, target, merged)
+	})
+
+	t.Run("Conflicts", func(t *testing.T) {
+		target := test.YamlToUnstructured(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: guestbook-ui
+  namespace: default
+  labels:
+    team: guestbook
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: guestbook-ui
+        image: guestbook:2.0
+      - name: sidecar
+        image: busybox:1.36
+`)
+		merged, conflicts, err := resolver.Merge(live, target)
+		require.NoError(t, err)
+		assert.Equal(t, []MergedConflict{
+			{Field: `.spec.replicas`, Managers: []string{"kube-controller-manager"}},
+			{Field: `.spec.template.spec.containers[name="guestbook-ui"].image`, Managers: []string{"image-updater"}, Forced: true},
+		}, conflicts)
+		assert.Equal(t, `.spec.replicas owned by "kube-controller-manager"`, conflicts[0].String())
+
+		// the replicas are left to the autoscaler, while the image owned by Argo CD is forced
+		_, found, err := unstructured.NestedFieldNoCopy(merged.Object, "spec", "replicas")
+		require.NoError(t, err)
+		assert.False(t, found)
+		containers, _, err := unstructured.NestedSlice(merged.Object, "spec", "template", "spec", "containers")
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{
+			map[string]interface{}{"name": "guestbook-ui", "image": "guestbook:2.0"},
+			map[string]interface{}{"name": "sidecar", "image": "busybox:1.36"},
+		}, containers)
+
+		// the target is not modified
+		replicas, _, _ := unstructured.NestedFieldNoCopy(target.Object, "spec", "replicas")
+		assert.EqualValues(t, 1, replicas)
+	})
+
+	t.Run("InvalidManagedFields", func(t *testing.T) {
+		invalid := live.DeepCopy()
+		invalid.SetManagedFields([]metav1.ManagedFieldsEntry{managedFieldsEntry("kubectl", metav1.ManagedFieldsOperationUpdate, "", `{"f:spec":{"k:notjson":{}}}`)})
+		_, _, err := resolver.Merge(invalid, live)
+		require.ErrorContains(t, err, "error parsing managed fields of kubectl")
+	})
+}
+
+func TestConflictResolver_MergeListItems(t *testing.T) {
+	obj := test.YamlToUnstructured(`
+spec:
+  ports:
+  - port: 80
+    protocol: TCP
+    name: http
+  - port: 443
+    protocol: TCP
+  finalizers:
+  - a
+  - b
+`)
+	resolver := NewConflictResolver("argocd-controller")
+	live := obj.DeepCopy()
+	live.Object["spec"].(map[string]interface{})["ports"].([]interface{})[0].(map[string]interface{})["name"] = "web"
+	live.Object["spec"].(map[string]interface{})["finalizers"] = []interface{}{"a", "c"}
+	live.SetManagedFields([]metav1.ManagedFieldsEntry{
+		managedFieldsEntry("other", metav1.ManagedFieldsOperationApply, "", `{"f:spec":{"f:ports":{"k:{\"port\":80,\"protocol\":\"TCP\"}":{"f:name":{}}},"f:finalizers":{"v:\"b\"":{}}}}`),
+	})
+
+	merged, conflicts, err := resolver.Merge(live, obj)
+	require.NoError(t, err)
+	assert.Equal(t, []MergedConflict{{Field: `.spec.ports[port=80,protocol="TCP"].name`, Managers: []string{"other"}}}, conflicts)
+	ports, _, err := unstructured.NestedSlice(merged.Object, "spec", "ports")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"port": float64(80), "protocol": "TCP"},
+		map[string]interface{}{"port": float64(443), "protocol": "TCP"},
+	}, ports)
+}
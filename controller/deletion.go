@@ -0,0 +1,276 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/sync/syncwaves"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+)
+
+// DeletionGraph is the graph of the dependencies between resources which are deleted together. A resource depends on
+// its owners, on the persistent volume claims mounted by its pods, and on the custom resource definition of its kind:
+// these resources cannot be deleted, or might prevent its deletion, as long as it exists.
+type DeletionGraph struct {
+	// resources are the resources of the graph
+	resources []*unstructured.Unstructured
+	// dependencies are the indexes of the resources each resource depends on
+	dependencies [][]int
+}
+
+// NewDeletionGraph returns the dependency graph of the given resources. Dependencies on resources which are not in the
+// list are ignored.
+func NewDeletionGraph(objs []*unstructured.Unstructured) *DeletionGraph {
+	byUID := map[types.UID]int{}
+	byKey := map[kube.ResourceKey]int{}
+	crds := map[string]int{}
+	for i, obj := range objs {
+		if obj.GetUID() != "" {
+			byUID[obj.GetUID()] = i
+		}
+		byKey[kube.GetResourceKey(obj)] = i
+		if kube.IsCRD(obj) {
+			group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+			kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+			crds[group+"/"+kind] = i
+		}
+	}
+
+	g := &DeletionGraph{resources: objs, dependencies: make([][]int, len(objs))}
+	for i, obj := range objs {
+		dependencies := map[int]bool{}
+		for _, ref := range obj.GetOwnerReferences() {
+			if j, ok := byUID[ref.UID]; ok {
+				dependencies[j] = true
+			}
+		}
+		for _, claim := range persistentVolumeClaims(obj.Object) {
+			if j, ok := byKey[kube.NewResourceKey("", kube.PersistentVolumeClaimKind, obj.GetNamespace(), claim)]; ok {
+				dependencies[j] = true
+			}
+		}
+		if j, ok := crds[obj.GroupVersionKind().Group+"/"+obj.GetKind()]; ok {
+			dependencies[j] = true
+		}
+		delete(dependencies, i)
+		for j := range dependencies {
+			g.dependencies[i] = append(g.dependencies[i], j)
+		}
+		sort.Ints(g.dependencies[i])
+	}
+	return g
+}
+
+// DependsOn returns the resources of the graph the resource at the given index depends on
+func (g *DeletionGraph) DependsOn(i int) []*unstructured.Unstructured {
+	dependencies := make([]*unstructured.Unstructured, len(g.dependencies[i]))
+	for k, j := range g.dependencies[i] {
+		dependencies[k] = g.resources[j]
+	}
+	return dependencies
+}
+
+// Steps returns the indexes of the resources grouped by deletion step: the resources no other resource depends on are
+// deleted first, and each resource is deleted after all the resources depending on it. The resources of a dependency
+// cycle are deleted in the same step.
+func (g *DeletionGraph) Steps() [][]int {
+	dependents := make([]int, len(g.resources))
+	for i := range g.resources {
+		for _, j := range g.dependencies[i] {
+			dependents[j]++
+		}
+	}
+	deleted := make([]bool, len(g.resources))
+	var steps [][]int
+	for remaining := len(g.resources); remaining > 0; {
+		var step []int
+		for i := range g.resources {
+			if !deleted[i] && dependents[i] == 0 {
+				step = append(step, i)
+			}
+		}
+		if len(step) == 0 {
+			// every remaining resource is part of or depends on a cycle
+			for i := range g.resources {
+				if !deleted[i] {
+					step = append(step, i)
+				}
+			}
+		}
+		for _, i := range step {
+			deleted[i] = true
+			for _, j := range g.dependencies[i] {
+				dependents[j]--
+			}
+		}
+		remaining -= len(step)
+		steps = append(steps, step)
+	}
+	return steps
+}
+
+// Next returns the resources which are deleted first, i.e. the resources no other resource of the graph depends on
+func (g *DeletionGraph) Next() []*unstructured.Unstructured {
+	steps := g.Steps()
+	if len(steps) == 0 {
+		return nil
+	}
+	next := make([]*unstructured.Unstructured, len(steps[0]))
+	for k, i := range steps[0] {
+		next[k] = g.resources[i]
+	}
+	return next
+}
+
+// deletionOrderIndex returns the position of the kind of the resource in the deletion order of the application
+func deletionOrderIndex(syncPolicy *appv1.SyncPolicy, obj *unstructured.Unstructured) int {
+	return syncPolicy.GetDeletionOrderIndex(obj.GroupVersionKind().GroupKind())
+}
+
+// isInDeletionOrder returns whether the kind of the resource is listed in the deletion order of the application
+func isInDeletionOrder(syncPolicy *appv1.SyncPolicy, obj *unstructured.Unstructured) bool {
+	return syncPolicy != nil && deletionOrderIndex(syncPolicy, obj) < len(syncPolicy.DeletionOrder)
+}
+
+// filterObjectsForDeletionOrder returns the resources whose kind comes first in the deletion order of the application.
+// The resources of kinds which are not listed are returned once all the resources of listed kinds are deleted.
+func filterObjectsForDeletionOrder(objs []*unstructured.Unstructured, syncPolicy *appv1.SyncPolicy) []*unstructured.Unstructured {
+	if syncPolicy == nil || len(syncPolicy.DeletionOrder) == 0 {
+		return objs
+	}
+	first := len(syncPolicy.DeletionOrder)
+	for _, obj := range objs {
+		first = min(first, deletionOrderIndex(syncPolicy, obj))
+	}
+	var filtered []*unstructured.Unstructured
+	for _, obj := range objs {
+		if deletionOrderIndex(syncPolicy, obj) == first {
+			filtered = append(filtered, obj)
+		}
+	}
+	return filtered
+}
+
+// DeletionStep is a set of resources of an application which are deleted together during a cascaded deletion
+type DeletionStep struct {
+	// Order is the position of the kind of the resources in the deletion order of the application, or the length of the
+	// deletion order for kinds which are not listed
+	Order int
+	// Wave is the sync wave of the resources
+	Wave int
+	// Resources are the resources deleted during the step
+	Resources []*unstructured.Unstructured
+	// DependsOn are the resources deleted in later steps of the same wave each resource depends on
+	DependsOn [][]*unstructured.Unstructured
+}
+
+// DeletionOrder returns the steps of the cascaded deletion of the given resources: the kinds are deleted in the
+// deletion order of the sync policy, the sync waves of each kind in reverse order, and the resources of each wave in
+// the order of their dependency graph.
+func DeletionOrder(objs []*unstructured.Unstructured, syncPolicy *appv1.SyncPolicy) []DeletionStep {
+	sorted := make([]*unstructured.Unstructured, len(objs))
+	copy(sorted, objs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if order, other := deletionOrderIndex(syncPolicy, sorted[i]), deletionOrderIndex(syncPolicy, sorted[j]); order != other {
+			return order < other
+		}
+		return syncwaves.Wave(sorted[i]) > syncwaves.Wave(sorted[j])
+	})
+
+	var steps []DeletionStep
+	for start := 0; start < len(sorted); {
+		order := deletionOrderIndex(syncPolicy, sorted[start])
+		wave := syncwaves.Wave(sorted[start])
+		end := start
+		for end < len(sorted) && deletionOrderIndex(syncPolicy, sorted[end]) == order && syncwaves.Wave(sorted[end]) == wave {
+			end++
+		}
+		graph := NewDeletionGraph(sorted[start:end])
+		for _, indexes := range graph.Steps() {
+			step := DeletionStep{Order: order, Wave: wave}
+			for _, i := range indexes {
+				step.Resources = append(step.Resources, graph.resources[i])
+				step.DependsOn = append(step.DependsOn, graph.DependsOn(i))
+			}
+			steps = append(steps, step)
+		}
+		start = end
+	}
+	return steps
+}
+
+// persistentVolumeClaims returns the names of the persistent volume claims mounted by the pod specs and pod templates
+// of an object
+func persistentVolumeClaims(value interface{}) []string {
+	var claims []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if volumes, ok := field.([]interface{}); ok && key == "volumes" {
+				for _, volume := range volumes {
+					if claim, _, _ := unstructured.NestedString(asMap(volume), "persistentVolumeClaim", "claimName"); claim != "" {
+						claims = append(claims, claim)
+					}
+				}
+				continue
+			}
+			claims = append(claims, persistentVolumeClaims(field)...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			claims = append(claims, persistentVolumeClaims(item)...)
+		}
+	}
+	return claims
+}
+
+func asMap(value interface{}) map[string]interface{} {
+	m, _ := value.(map[string]interface{})
+	return m
+}
+
+// forceDeleteHangingResources force deletes the resources whose deletion is pending for longer than the deletion
+// timeout per resource, by removing their finalizers and deleting them without grace period. A warning is reported for
+// each of them.
+func (ctrl *ApplicationController) forceDeleteHangingResources(app *appv1.Application, objs []*unstructured.Unstructured, config *rest.Config, logCtx *log.Entry) error {
+	if ctrl.deletionTimeoutPerResource <= 0 {
+		return nil
+	}
+	for _, obj := range objs {
+		deletionTimestamp := obj.GetDeletionTimestamp()
+		if deletionTimestamp == nil || time.Since(deletionTimestamp.Time) < ctrl.deletionTimeoutPerResource {
+			continue
+		}
+		message := fmt.Sprintf("Force deleting %s %s/%s: deletion pending for more than %v", obj.GetKind(), obj.GetNamespace(), obj.GetName(), ctrl.deletionTimeoutPerResource)
+		if finalizers := obj.GetFinalizers(); len(finalizers) > 0 {
+			message = fmt.Sprintf("%s, removing finalizers %v", message, finalizers)
+		}
+		logCtx.Warn(message)
+		ctrl.auditLogger.LogAppEvent(app, argo.EventInfo{Reason: argo.EventReasonResourceForceDeleted, Type: corev1.EventTypeWarning}, message, "", nil)
+
+		if len(obj.GetFinalizers()) > 0 {
+			_, err := ctrl.kubectl.PatchResource(context.Background(), config, obj.GroupVersionKind(), obj.GetName(), obj.GetNamespace(), types.MergePatchType, []byte(`{"metadata":{"finalizers":null}}`))
+			if err != nil && !apierr.IsNotFound(err) {
+				return fmt.Errorf("error removing finalizers of %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+			}
+		}
+		err := ctrl.kubectl.DeleteResource(context.Background(), config, obj.GroupVersionKind(), obj.GetName(), obj.GetNamespace(), metav1.DeleteOptions{GracePeriodSeconds: ptr.To(int64(0))})
+		if err != nil && !apierr.IsNotFound(err) {
+			return fmt.Errorf("error force deleting %s %s/%s: %w", obj.GetKind(), obj.GetNamespace(), obj.GetName(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,258 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	. "github.com/argoproj/gitops-engine/pkg/utils/testing"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+func newDeletionTestResource(yaml string, uid types.UID, owners ...*unstructured.Unstructured) *unstructured.Unstructured {
+	obj := test.YamlToUnstructured(yaml)
+	obj.SetUID(uid)
+	var refs []metav1.OwnerReference
+	for _, owner := range owners {
+		refs = append(refs, metav1.OwnerReference{APIVersion: owner.GetAPIVersion(), Kind: owner.GetKind(), Name: owner.GetName(), UID: owner.GetUID()})
+	}
+	obj.SetOwnerReferences(refs)
+	return obj
+}
+
+func resourceNames(objs []*unstructured.Unstructured) []string {
+	names := make([]string, len(objs))
+	for i, obj := range objs {
+		names[i] = obj.GetKind() + "/" + obj.GetName()
+	}
+	return names
+}
+
+func deletionStepNames(g *DeletionGraph) [][]string {
+	var steps [][]string
+	for _, step := range g.Steps() {
+		var names []string
+		for _, i := range step {
+			names = append(names, g.resources[i].GetKind()+"/"+g.resources[i].GetName())
+		}
+		steps = append(steps, names)
+	}
+	return steps
+}
+
+func TestDeletionGraph_OwnerReferenceChain(t *testing.T) {
+	deployment := newDeletionTestResource(`
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: default
+`, "deployment-uid")
+	replicaSet := newDeletionTestResource(`
+apiVersion: apps/v1
+kind: ReplicaSet
+metadata:
+  name: web-5d8f
+  namespace: default
+`, "replicaset-uid", deployment)
+	pod := newDeletionTestResource(`
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web-5d8f-x7k2
+  namespace: default
+`, "pod-uid", replicaSet)
+	configMap := newDeletionTestResource(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: web-config
+  namespace: default
+`, "configmap-uid")
+
+	g := NewDeletionGraph([]*unstructured.Unstructured{deployment, replicaSet, pod, configMap})
+	assert.Equal(t, [][]string{
+		{"Pod/web-5d8f-x7k2", "ConfigMap/web-config"},
+		{"ReplicaSet/web-5d8f"},
+		{"Deployment/web"},
+	}, deletionStepNames(g))
+	assert.Equal(t, []string{"ReplicaSet/web-5d8f"}, resourceNames(g.DependsOn(2)))
+	assert.Empty(t, g.DependsOn(0))
+	assert.Equal(t, []string{"Pod/web-5d8f-x7k2", "ConfigMap/web-config"}, resourceNames(g.Next()))
+
+	// owners which are not deleted together with their dependents are ignored
+	g = NewDeletionGraph([]*unstructured.Unstructured{pod, deployment})
+	assert.Equal(t, [][]string{{"Pod/web-5d8f-x7k2", "Deployment/web"}}, deletionStepNames(g))
+}
+
+func TestDeletionGraph_PersistentVolumeClaimsAndCRDs(t *testing.T) {
+	claim := newDeletionTestResource(`
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  namespace: default
+`, "claim-uid")
+	statefulSet := newDeletionTestResource(`
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: db
+  namespace: default
+spec:
+  template:
+    spec:
+      volumes:
+      - name: data
+        persistentVolumeClaim:
+          claimName: data
+`, "statefulset-uid")
+	otherNamespaceClaim := newDeletionTestResource(`
+apiVersion: v1
+kind: PersistentVolumeClaim
+metadata:
+  name: data
+  namespace: other
+`, "other-claim-uid")
+	crd := newDeletionTestResource(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: databases.example.com
+spec:
+  group: example.com
+  names:
+    kind: Database
+`, "crd-uid")
+	database := newDeletionTestResource(`
+apiVersion: example.com/v1
+kind: Database
+metadata:
+  name: db
+  namespace: default
+`, "database-uid")
+
+	g := NewDeletionGraph([]*unstructured.Unstructured{crd, claim, otherNamespaceClaim, statefulSet, database})
+	assert.Equal(t, [][]string{
+		{"PersistentVolumeClaim/data", "StatefulSet/db", "Database/db"},
+		{"CustomResourceDefinition/databases.example.com", "PersistentVolumeClaim/data"},
+	}, deletionStepNames(g))
+	assert.Equal(t, []string{"PersistentVolumeClaim/data"}, resourceNames(g.DependsOn(3)))
+	assert.Equal(t, "default", g.DependsOn(3)[0].GetNamespace())
+	assert.Equal(t, []string{"CustomResourceDefinition/databases.example.com"}, resourceNames(g.DependsOn(4)))
+}
+
+func TestDeletionGraph_Cycle(t *testing.T) {
+	a := test.YamlToUnstructured(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: a
+  namespace: default
+  uid: a-uid
+  ownerReferences:
+  - apiVersion: v1
+    kind: ConfigMap
+    name: b
+    uid: b-uid
+`)
+	b := test.YamlToUnstructured(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: b
+  namespace: default
+  uid: b-uid
+  ownerReferences:
+  - apiVersion: v1
+    kind: ConfigMap
+    name: a
+    uid: a-uid
+`)
+	c := newDeletionTestResource(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: c
+  namespace: default
+`, "c-uid", a)
+
+	g := NewDeletionGraph([]*unstructured.Unstructured{a, b, c})
+	assert.Equal(t, [][]string{{"ConfigMap/c"}, {"ConfigMap/a", "ConfigMap/b"}}, deletionStepNames(g))
+	assert.Empty(t, NewDeletionGraph(nil).Next())
+}
+
+func TestDeletionOrder(t *testing.T) {
+	owner := Annotate(NewPod(), common.AnnotationSyncWave, "1")
+	owner.SetName("owner")
+	owner.SetUID("owner-uid")
+	dependent := Annotate(NewPod(), common.AnnotationSyncWave, "1")
+	dependent.SetName("dependent")
+	dependent.SetOwnerReferences([]metav1.OwnerReference{{APIVersion: "v1", Kind: "Pod", Name: "owner", UID: "owner-uid"}})
+	first := NewPod()
+	first.SetName("first")
+
+	steps := DeletionOrder([]*unstructured.Unstructured{first, owner, dependent}, nil)
+	if assert.Len(t, steps, 3) {
+		assert.Equal(t, 1, steps[0].Wave)
+		assert.Equal(t, []string{"Pod/dependent"}, resourceNames(steps[0].Resources))
+		assert.Equal(t, []string{"Pod/owner"}, resourceNames(steps[0].DependsOn[0]))
+		assert.Equal(t, 1, steps[1].Wave)
+		assert.Equal(t, []string{"Pod/owner"}, resourceNames(steps[1].Resources))
+		assert.Equal(t, 0, steps[2].Wave)
+		assert.Equal(t, []string{"Pod/first"}, resourceNames(steps[2].Resources))
+	}
+}
+
+func newDeletionOrderTestResources() (crd, cr, pod *unstructured.Unstructured) {
+	crd = NewCRD()
+	cr = test.YamlToUnstructured(`
+apiVersion: argoproj.io/v1
+kind: TestCrd
+metadata:
+  name: cr
+  namespace: default
+`)
+	pod = Annotate(NewPod(), common.AnnotationSyncWave, "1")
+	return crd, cr, pod
+}
+
+func TestFilterObjectsForDeletionOrder(t *testing.T) {
+	crd, cr, pod := newDeletionOrderTestResources()
+	objs := []*unstructured.Unstructured{crd, cr, pod}
+
+	assert.Equal(t, objs, filterObjectsForDeletionOrder(objs, nil))
+	assert.Equal(t, objs, filterObjectsForDeletionOrder(objs, &v1alpha1.SyncPolicy{}))
+
+	syncPolicy := &v1alpha1.SyncPolicy{DeletionOrder: []string{"argoproj.io/TestCrd", "apiextensions.k8s.io/CustomResourceDefinition"}}
+	// the custom resources are deleted before their definitions, and the resources of kinds which are not listed last
+	assert.Equal(t, []string{"TestCrd/cr"}, resourceNames(filterObjectsForDeletionOrder(objs, syncPolicy)))
+	assert.Equal(t, []string{"CustomResourceDefinition/testcrds.argoproj.io"}, resourceNames(filterObjectsForDeletionOrder([]*unstructured.Unstructured{crd, pod}, syncPolicy)))
+	assert.Equal(t, []string{"Pod/my-pod"}, resourceNames(filterObjectsForDeletionOrder([]*unstructured.Unstructured{pod}, syncPolicy)))
+
+	assert.True(t, isInDeletionOrder(syncPolicy, crd))
+	assert.False(t, isInDeletionOrder(syncPolicy, pod))
+	assert.False(t, isInDeletionOrder(nil, crd))
+}
+
+func TestDeletionOrder_SyncPolicy(t *testing.T) {
+	crd, cr, pod := newDeletionOrderTestResources()
+	syncPolicy := &v1alpha1.SyncPolicy{DeletionOrder: []string{"argoproj.io/TestCrd", "apiextensions.k8s.io/CustomResourceDefinition"}}
+
+	// the deletion order takes precedence over the sync waves
+	steps := DeletionOrder([]*unstructured.Unstructured{pod, crd, cr}, syncPolicy)
+	if assert.Len(t, steps, 3) {
+		assert.Equal(t, 0, steps[0].Order)
+		assert.Equal(t, []string{"TestCrd/cr"}, resourceNames(steps[0].Resources))
+		assert.Equal(t, 1, steps[1].Order)
+		assert.Equal(t, []string{"CustomResourceDefinition/testcrds.argoproj.io"}, resourceNames(steps[1].Resources))
+		assert.Equal(t, 2, steps[2].Order)
+		assert.Equal(t, 1, steps[2].Wave)
+		assert.Equal(t, []string{"Pod/my-pod"}, resourceNames(steps[2].Resources))
+	}
+}
@@ -0,0 +1,337 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/argoproj/gitops-engine/pkg/sync"
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-cd/v2/controller/sharding"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+)
+
+// destinationApp returns a copy of the application which targets one of its additional destinations
+func destinationApp(app *v1alpha1.Application, dest v1alpha1.ApplicationDestination) *v1alpha1.Application {
+	destApp := app.DeepCopy()
+	destApp.Spec.Destination = dest
+	destApp.Spec.Destinations = nil
+	destApp.Status.DestinationStatuses = nil
+	destApp.Status.Conditions = nil
+	return destApp
+}
+
+// compareDestinations compares the manifests generated for the application to each of its additional destinations
+func (m *appStateManager) compareDestinations(app *v1alpha1.Application, project *v1alpha1.AppProject, revisions []string, sources []v1alpha1.ApplicationSource, noRevisionCache bool, hasMultipleSources bool, rollback bool, generated *generatedTargets) []destinationComparison {
+	comparisons := make([]destinationComparison, 0, len(app.Spec.Destinations))
+	for _, dest := range app.Spec.Destinations {
+		destApp := destinationApp(app, dest)
+		result, err := m.compareDestination(destApp, project, revisions, sources, noRevisionCache, hasMultipleSources, rollback, generated)
+		if err != nil {
+			now := metav1.Now()
+			destApp.Status.SetConditions([]v1alpha1.ApplicationCondition{{Type: v1alpha1.ApplicationConditionComparisonError, Message: err.Error(), LastTransitionTime: &now}},
+				map[v1alpha1.ApplicationConditionType]bool{v1alpha1.ApplicationConditionComparisonError: true})
+			result = &comparisonResult{
+				syncStatus:   &v1alpha1.SyncStatus{Status: v1alpha1.SyncStatusCodeUnknown},
+				healthStatus: &v1alpha1.HealthStatus{Status: health.HealthStatusUnknown},
+			}
+		}
+		comparisons = append(comparisons, destinationComparison{app: destApp, result: result})
+	}
+	return comparisons
+}
+
+func (m *appStateManager) compareDestination(destApp *v1alpha1.Application, project *v1alpha1.AppProject, revisions []string, sources []v1alpha1.ApplicationSource, noRevisionCache bool, hasMultipleSources bool, rollback bool, generated *generatedTargets) (*comparisonResult, error) {
+	if err := argo.ValidateDestination(context.Background(), &destApp.Spec.Destination, m.db); err != nil {
+		return nil, err
+	}
+	permitted, err := project.IsDestinationPermitted(destApp.Spec.Destination, func(project string) ([]*v1alpha1.Cluster, error) {
+		return m.db.GetProjectClusters(context.Background(), project)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !permitted {
+		return nil, fmt.Errorf("application destination server '%s' and namespace '%s' do not match any of the allowed destinations in project '%s'", destApp.Spec.Destination.Server, destApp.Spec.Destination.Namespace, project.Name)
+	}
+	// the diff cache is keyed by the application, so it is not used for the additional destinations
+	return m.compareAppState(destApp, project, revisions, sources, true, noRevisionCache, nil, hasMultipleSources, rollback, generated)
+}
+
+// aggregateDestinations aggregates the sync status and the health of the additional destinations into the comparison
+// result of the application, and returns the sync status and the health of each destination, its destination first.
+// The application is out of sync if any of its destinations is, and its health is the worst health of its destinations.
+func (res *comparisonResult) aggregateDestinations(app *v1alpha1.Application) []v1alpha1.DestinationSyncStatus {
+	statuses := []v1alpha1.DestinationSyncStatus{{Destination: app.Spec.Destination, Status: res.syncStatus.Status, Health: *res.healthStatus}}
+	for i, dest := range res.destinations {
+		statuses = append(statuses, v1alpha1.DestinationSyncStatus{
+			Destination: app.Spec.Destinations[i],
+			Status:      dest.result.syncStatus.Status,
+			Health:      *dest.result.healthStatus,
+		})
+		switch dest.result.syncStatus.Status {
+		case v1alpha1.SyncStatusCodeUnknown:
+			res.syncStatus.Status = v1alpha1.SyncStatusCodeUnknown
+		case v1alpha1.SyncStatusCodeOutOfSync:
+			if res.syncStatus.Status != v1alpha1.SyncStatusCodeUnknown {
+				res.syncStatus.Status = v1alpha1.SyncStatusCodeOutOfSync
+			}
+		}
+		if health.IsWorse(res.healthStatus.Status, dest.result.healthStatus.Status) {
+			healthStatus := *dest.result.healthStatus
+			res.healthStatus = &healthStatus
+		}
+	}
+	return statuses
+}
+
+// destinationConditions returns the comparison errors of the additional destinations, except the ones reported while
+// generating the manifests, which are already reported for the application
+func destinationConditions(destinations []destinationComparison, generated []v1alpha1.ApplicationCondition, now metav1.Time) []v1alpha1.ApplicationCondition {
+	reported := make(map[string]bool)
+	for _, condition := range generated {
+		reported[condition.Message] = true
+	}
+	var conditions []v1alpha1.ApplicationCondition
+	for _, dest := range destinations {
+		for _, condition := range dest.app.Status.Conditions {
+			if condition.Type != v1alpha1.ApplicationConditionComparisonError || reported[condition.Message] {
+				continue
+			}
+			conditions = append(conditions, v1alpha1.ApplicationCondition{
+				Type:               v1alpha1.ApplicationConditionComparisonError,
+				Message:            fmt.Sprintf("destination %s: %s", destinationKey(dest.app.Spec.Destination), condition.Message),
+				LastTransitionTime: &now,
+			})
+		}
+	}
+	return conditions
+}
+
+// destinationKey identifies a destination of an application
+func destinationKey(dest v1alpha1.ApplicationDestination) string {
+	server := dest.Server
+	if server == "" {
+		server = dest.Name
+	}
+	return fmt.Sprintf("%s/%s", server, dest.Namespace)
+}
+
+// destinationsChanged returns whether the destinations of the application differ from the destinations its status
+// was compared to
+func destinationsChanged(app *v1alpha1.Application) bool {
+	if !app.Spec.HasMultipleDestinations() {
+		return len(app.Status.DestinationStatuses) > 0
+	}
+	destinations := app.Spec.GetDestinations()
+	if len(destinations) != len(app.Status.DestinationStatuses) {
+		return true
+	}
+	for i := range destinations {
+		if !destinations[i].Equals(app.Status.DestinationStatuses[i].Destination) {
+			return true
+		}
+	}
+	return false
+}
+
+// fanOutDestinations are the servers of the additional destination clusters of the applications of the shard
+type fanOutDestinations struct {
+	lock    gosync.RWMutex
+	servers map[string][]string
+}
+
+// set sets the servers of the additional destinations of an application, and returns the servers which are no longer
+// the additional destination of any application
+func (f *fanOutDestinations) set(key string, servers []string) []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	previous := f.servers[key]
+	if len(servers) == 0 {
+		delete(f.servers, key)
+	} else {
+		f.servers[key] = servers
+	}
+	var released []string
+	for _, server := range previous {
+		if !f.hasServerLocked(server) {
+			released = append(released, server)
+		}
+	}
+	return released
+}
+
+func (f *fanOutDestinations) hasServer(server string) bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return f.hasServerLocked(server)
+}
+
+func (f *fanOutDestinations) hasServerLocked(server string) bool {
+	for _, servers := range f.servers {
+		for _, s := range servers {
+			if s == server {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// fanOutClusterSharding manages the clusters of the shard, and the additional destination clusters of the applications
+// of the shard
+type fanOutClusterSharding struct {
+	sharding.ClusterShardingCache
+	destinations *fanOutDestinations
+}
+
+func (s *fanOutClusterSharding) IsManagedCluster(c *v1alpha1.Cluster) bool {
+	return s.ClusterShardingCache.IsManagedCluster(c) || (c != nil && s.destinations.hasServer(c.Server))
+}
+
+// trackFanOutDestinations records the servers of the additional destination clusters of an application, so that the
+// shard of the application watches them, and releases the caches of the clusters which are no longer the additional
+// destination of any application of the shard
+func (ctrl *ApplicationController) trackFanOutDestinations(appKey string, app *v1alpha1.Application) {
+	var servers []string
+	if app != nil {
+		for _, dest := range app.Spec.Destinations {
+			if err := argo.ValidateDestination(context.Background(), &dest, ctrl.db); err != nil {
+				continue
+			}
+			servers = append(servers, dest.Server)
+		}
+	}
+	for _, server := range ctrl.fanOutDestinations.set(appKey, servers) {
+		ctrl.stateCache.ReleaseCluster(server)
+	}
+}
+
+// syncDestinations syncs the application to its destination and to each of its additional destinations selected by
+// the sync operation, independently. The sync of each destination is resumed until it completes, and the operation
+// completes once the sync of all the selected destinations has completed. The progress of the sync is not reported.
+func (m *appStateManager) syncDestinations(app *v1alpha1.Application, state *v1alpha1.OperationState, syncOp v1alpha1.SyncOperation, proj *v1alpha1.AppProject, compareResult *comparisonResult, sources []v1alpha1.ApplicationSource, syncTimeout time.Duration, preFlightRequirements *preFlightRequirements) sync.ReconciliationResult {
+	primary := app.Spec.Destination
+	if err := argo.ValidateDestination(context.Background(), &primary, m.db); err != nil {
+		log.WithField("application", app.QualifiedName()).Warnf("Failed to resolve the destination: %v", err)
+	}
+	destinations := []destinationSync{{dest: app.Spec.Destination, resolved: primary, app: app, compareResult: compareResult}}
+	for i, dest := range compareResult.destinations {
+		destinations = append(destinations, destinationSync{dest: app.Spec.Destinations[i], resolved: dest.app.Spec.Destination, app: dest.app, compareResult: dest.result})
+	}
+
+	// the sync of all the destinations completed if the operation is running again, so the destinations whose sync did
+	// not succeed are synced again since the operation is retried
+	retried := state.Phase == common.OperationRunning && len(state.SyncResult.Destinations) > 0
+	for _, result := range state.SyncResult.Destinations {
+		retried = retried && result.Phase.Completed()
+	}
+
+	var reconciliationResult sync.ReconciliationResult
+	results := make([]v1alpha1.DestinationSyncResult, 0, len(destinations))
+	for i, dest := range destinations {
+		if !syncOp.SyncsDestination(dest.resolved) {
+			continue
+		}
+		result := findDestinationSyncResult(state.SyncResult.Destinations, dest.dest)
+		if result == nil || (retried && !result.Phase.Successful()) {
+			result = &v1alpha1.DestinationSyncResult{Destination: dest.dest, Phase: common.OperationRunning}
+		}
+		if !result.Phase.Completed() {
+			destState := &v1alpha1.OperationState{
+				Operation:  state.Operation,
+				Phase:      result.Phase,
+				Message:    result.Message,
+				StartedAt:  state.StartedAt,
+				RetryCount: state.RetryCount,
+				SyncResult: &v1alpha1.SyncOperationResult{
+					Revision:  state.SyncResult.Revision,
+					Revisions: state.SyncResult.Revisions,
+					Resources: result.Resources,
+					SyncID:    state.SyncResult.SyncID,
+				},
+			}
+			if state.Phase == common.OperationTerminating {
+				destState.Phase = common.OperationTerminating
+			}
+			// the live state is captured before the sync of the destination of the application only
+			destResult := m.syncDestination(dest.app, destState, syncOp, proj, dest.compareResult, sources, syncTimeout, preFlightRequirements, "|"+destinationKey(dest.resolved), i == 0)
+			if i == 0 {
+				reconciliationResult = destResult
+				state.SyncResult.SyncID = destState.SyncResult.SyncID
+			}
+			result.Phase, result.Message, result.Resources = destState.Phase, destState.Message, destState.SyncResult.Resources
+			if destState.ErrorCode != "" && state.ErrorCode == "" {
+				state.ErrorCode = destState.ErrorCode
+			}
+		}
+		results = append(results, *result)
+	}
+	state.SyncResult.Destinations = results
+	state.SyncResult.Resources = nil
+	if app.Spec.SyncPolicy != nil {
+		state.SyncResult.ManagedNamespaceMetadata = app.Spec.SyncPolicy.ManagedNamespaceMetadata
+	}
+	state.Phase, state.Message = aggregateDestinationSyncResults(results, state.Phase == common.OperationTerminating)
+	if !state.Phase.Completed() || state.Phase.Successful() {
+		state.ErrorCode = ""
+	}
+	return reconciliationResult
+}
+
+// destinationSync is the sync of the application to one of its destinations
+type destinationSync struct {
+	// dest is the destination as set in the spec of the application, and resolved is the destination with its server
+	dest     v1alpha1.ApplicationDestination
+	resolved v1alpha1.ApplicationDestination
+	// app targets the destination
+	app           *v1alpha1.Application
+	compareResult *comparisonResult
+}
+
+// findDestinationSyncResult returns the result of the sync of the destination, nil if the destination was not synced
+// yet
+func findDestinationSyncResult(results []v1alpha1.DestinationSyncResult, dest v1alpha1.ApplicationDestination) *v1alpha1.DestinationSyncResult {
+	for i := range results {
+		if results[i].Destination.Server == dest.Server && results[i].Destination.Name == dest.Name && results[i].Destination.Namespace == dest.Namespace {
+			result := results[i]
+			return &result
+		}
+	}
+	return nil
+}
+
+// aggregateDestinationSyncResults returns the phase and the message of a sync operation from the results of the sync of
+// its destinations. The operation is running as long as the sync of a destination is, and otherwise fails if the sync
+// of any destination failed.
+func aggregateDestinationSyncResults(results []v1alpha1.DestinationSyncResult, terminating bool) (common.OperationPhase, string) {
+	var failed []string
+	phase := common.OperationSucceeded
+	for _, result := range results {
+		switch {
+		case !result.Phase.Completed():
+			if terminating {
+				return common.OperationTerminating, "waiting for the sync of the destinations to terminate"
+			}
+			return common.OperationRunning, "waiting for the sync of the destinations to complete"
+		case result.Phase == common.OperationError:
+			phase = common.OperationError
+		case result.Phase == common.OperationFailed && phase != common.OperationError:
+			phase = common.OperationFailed
+		}
+		if !result.Phase.Successful() {
+			failed = append(failed, fmt.Sprintf("%s: %s", destinationKey(result.Destination), result.Message))
+		}
+	}
+	if len(failed) > 0 {
+		return phase, fmt.Sprintf("sync failed for %d of %d destinations: %s", len(failed), len(results), strings.Join(failed, "; "))
+	}
+	return phase, "successfully synced all destinations"
+}
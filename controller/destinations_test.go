@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	. "github.com/argoproj/gitops-engine/pkg/utils/testing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/controller/sharding"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/test"
+	dbmocks "github.com/argoproj/argo-cd/v2/util/db/mocks"
+)
+
+const otherClusterURL = "https://other-cluster"
+
+func newFakeMultiDestinationApp() *v1alpha1.Application {
+	app := newFakeApp()
+	app.Spec.Destinations = []v1alpha1.ApplicationDestination{{Server: otherClusterURL, Namespace: test.FakeDestNamespace}}
+	return app
+}
+
+func TestCompareAppStateMultipleDestinations(t *testing.T) {
+	app := newFakeMultiDestinationApp()
+	pod := NewPod()
+	pod.SetNamespace(test.FakeDestNamespace)
+	data := fakeData{
+		manifestResponse: &apiclient.ManifestResponse{
+			Manifests: []string{},
+			Namespace: test.FakeDestNamespace,
+			Server:    test.FakeClusterURL,
+			Revision:  "abc123",
+		},
+		managedLiveObjs: make(map[kube.ResourceKey]*unstructured.Unstructured),
+		// the additional destination has an extraneous resource
+		destinationLiveObjs: map[string]map[kube.ResourceKey]*unstructured.Unstructured{
+			otherClusterURL: {kube.GetResourceKey(pod): pod},
+		},
+	}
+	// the manifests are generated once, the repo server mock returns the manifest response once
+	ctrl := newFakeController(&data, nil)
+	compRes, err := ctrl.appStateManager.CompareAppState(app, &defaultProj, []string{""}, []v1alpha1.ApplicationSource{app.Spec.GetSource()}, false, false, nil, false, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, v1alpha1.SyncStatusCodeOutOfSync, compRes.syncStatus.Status)
+	assert.Empty(t, compRes.resources)
+	require.Len(t, compRes.destinationStatuses, 2)
+	assert.Equal(t, app.Spec.Destination, compRes.destinationStatuses[0].Destination)
+	assert.Equal(t, v1alpha1.SyncStatusCodeSynced, compRes.destinationStatuses[0].Status)
+	assert.Equal(t, app.Spec.Destinations[0], compRes.destinationStatuses[1].Destination)
+	assert.Equal(t, v1alpha1.SyncStatusCodeOutOfSync, compRes.destinationStatuses[1].Status)
+	require.Len(t, compRes.destinations, 1)
+	assert.Len(t, compRes.destinations[0].result.resources, 1)
+	assert.Empty(t, app.Status.Conditions)
+}
+
+func TestCompareAppStateDestinationNotPermitted(t *testing.T) {
+	app := newFakeMultiDestinationApp()
+	data := fakeData{
+		manifestResponse: &apiclient.ManifestResponse{
+			Manifests: []string{},
+			Namespace: test.FakeDestNamespace,
+			Server:    test.FakeClusterURL,
+			Revision:  "abc123",
+		},
+		managedLiveObjs: make(map[kube.ResourceKey]*unstructured.Unstructured),
+	}
+	ctrl := newFakeController(&data, nil)
+	proj := defaultProj.DeepCopy()
+	proj.Spec.Destinations = []v1alpha1.ApplicationDestination{{Server: test.FakeClusterURL, Namespace: "*"}}
+	compRes, err := ctrl.appStateManager.CompareAppState(app, proj, []string{""}, []v1alpha1.ApplicationSource{app.Spec.GetSource()}, false, false, nil, false, false)
+	require.NoError(t, err)
+
+	assert.Equal(t, v1alpha1.SyncStatusCodeUnknown, compRes.syncStatus.Status)
+	assert.Equal(t, health.HealthStatusUnknown, compRes.healthStatus.Status)
+	require.Len(t, compRes.destinationStatuses, 2)
+	assert.Equal(t, v1alpha1.SyncStatusCodeSynced, compRes.destinationStatuses[0].Status)
+	assert.Equal(t, v1alpha1.SyncStatusCodeUnknown, compRes.destinationStatuses[1].Status)
+	require.Len(t, app.Status.Conditions, 1)
+	assert.Equal(t, v1alpha1.ApplicationConditionComparisonError, app.Status.Conditions[0].Type)
+	assert.Contains(t, app.Status.Conditions[0].Message, "destination "+otherClusterURL+"/"+test.FakeDestNamespace)
+}
+
+func TestAggregateDestinations(t *testing.T) {
+	app := newFakeMultiDestinationApp()
+	app.Spec.Destinations = append(app.Spec.Destinations, v1alpha1.ApplicationDestination{Server: "https://third-cluster", Namespace: test.FakeDestNamespace})
+	res := &comparisonResult{
+		syncStatus:   &v1alpha1.SyncStatus{Status: v1alpha1.SyncStatusCodeSynced},
+		healthStatus: &v1alpha1.HealthStatus{Status: health.HealthStatusHealthy},
+		destinations: []destinationComparison{
+			{result: &comparisonResult{
+				syncStatus:   &v1alpha1.SyncStatus{Status: v1alpha1.SyncStatusCodeOutOfSync},
+				healthStatus: &v1alpha1.HealthStatus{Status: health.HealthStatusDegraded, Message: "degraded"},
+			}},
+			{result: &comparisonResult{
+				syncStatus:   &v1alpha1.SyncStatus{Status: v1alpha1.SyncStatusCodeSynced},
+				healthStatus: &v1alpha1.HealthStatus{Status: health.HealthStatusProgressing},
+			}},
+		},
+	}
+
+	statuses := res.aggregateDestinations(app)
+
+	assert.Equal(t, v1alpha1.SyncStatusCodeOutOfSync, res.syncStatus.Status)
+	assert.Equal(t, v1alpha1.HealthStatus{Status: health.HealthStatusDegraded, Message: "degraded"}, *res.healthStatus)
+	assert.Equal(t, []v1alpha1.DestinationSyncStatus{
+		{Destination: app.Spec.Destination, Status: v1alpha1.SyncStatusCodeSynced, Health: v1alpha1.HealthStatus{Status: health.HealthStatusHealthy}},
+		{Destination: app.Spec.Destinations[0], Status: v1alpha1.SyncStatusCodeOutOfSync, Health: v1alpha1.HealthStatus{Status: health.HealthStatusDegraded, Message: "degraded"}},
+		{Destination: app.Spec.Destinations[1], Status: v1alpha1.SyncStatusCodeSynced, Health: v1alpha1.HealthStatus{Status: health.HealthStatusProgressing}},
+	}, statuses)
+}
+
+func TestDestinationsChanged(t *testing.T) {
+	app := newFakeMultiDestinationApp()
+	assert.True(t, destinationsChanged(app))
+
+	app.Status.DestinationStatuses = []v1alpha1.DestinationSyncStatus{{Destination: app.Spec.Destination}, {Destination: app.Spec.Destinations[0]}}
+	assert.False(t, destinationsChanged(app))
+
+	app.Spec.Destinations[0].Namespace = "other"
+	assert.True(t, destinationsChanged(app))
+
+	app.Spec.Destinations = nil
+	assert.True(t, destinationsChanged(app))
+
+	app.Status.DestinationStatuses = nil
+	assert.False(t, destinationsChanged(app))
+}
+
+func TestSyncAppStateMultipleDestinations(t *testing.T) {
+	newController := func(app *v1alpha1.Application) *ApplicationController {
+		return newFakeController(&fakeData{
+			apps: []runtime.Object{app, &defaultProj},
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: []string{},
+				Namespace: test.FakeDestNamespace,
+				Server:    test.FakeClusterURL,
+				Revision:  "abc123",
+			},
+			managedLiveObjs: make(map[kube.ResourceKey]*unstructured.Unstructured),
+		}, nil)
+	}
+
+	t.Run("SyncsEachDestinationIndependently", func(t *testing.T) {
+		app := newFakeMultiDestinationApp()
+		ctrl := newController(app)
+		opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{}}, Phase: synccommon.OperationRunning}
+
+		ctrl.appStateManager.SyncAppState(app, opState)
+
+		// the cluster of the additional destination is not registered
+		assert.Equal(t, synccommon.OperationError, opState.Phase)
+		assert.Equal(t, v1alpha1.SyncErrorCodeClusterError, opState.ErrorCode)
+		assert.Contains(t, opState.Message, "sync failed for 1 of 2 destinations")
+		require.Len(t, opState.SyncResult.Destinations, 2)
+		assert.Equal(t, app.Spec.Destination, opState.SyncResult.Destinations[0].Destination)
+		assert.Equal(t, synccommon.OperationSucceeded, opState.SyncResult.Destinations[0].Phase)
+		assert.Equal(t, app.Spec.Destinations[0], opState.SyncResult.Destinations[1].Destination)
+		assert.Equal(t, synccommon.OperationError, opState.SyncResult.Destinations[1].Phase)
+		assert.Nil(t, opState.SyncResult.Resources)
+	})
+
+	t.Run("SyncsSelectedDestinations", func(t *testing.T) {
+		app := newFakeMultiDestinationApp()
+		ctrl := newController(app)
+		opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{
+			Destinations: []v1alpha1.ApplicationDestination{{Server: app.Spec.Destination.Server}},
+		}}, Phase: synccommon.OperationRunning}
+
+		ctrl.appStateManager.SyncAppState(app, opState)
+
+		assert.Equal(t, synccommon.OperationSucceeded, opState.Phase)
+		assert.Empty(t, opState.ErrorCode)
+		require.Len(t, opState.SyncResult.Destinations, 1)
+		assert.Equal(t, app.Spec.Destination, opState.SyncResult.Destinations[0].Destination)
+	})
+
+	t.Run("RetriesFailedDestinations", func(t *testing.T) {
+		app := newFakeMultiDestinationApp()
+		ctrl := newController(app)
+		opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{}}, Phase: synccommon.OperationRunning, SyncResult: &v1alpha1.SyncOperationResult{
+			Destinations: []v1alpha1.DestinationSyncResult{
+				{Destination: app.Spec.Destination, Phase: synccommon.OperationSucceeded, Message: "synced"},
+				{Destination: app.Spec.Destinations[0], Phase: synccommon.OperationFailed, Message: "failed"},
+			},
+		}}
+
+		ctrl.appStateManager.SyncAppState(app, opState)
+
+		require.Len(t, opState.SyncResult.Destinations, 2)
+		// the destination which was synced is not synced again
+		assert.Equal(t, "synced", opState.SyncResult.Destinations[0].Message)
+		assert.Equal(t, synccommon.OperationError, opState.SyncResult.Destinations[1].Phase)
+		assert.NotEqual(t, "failed", opState.SyncResult.Destinations[1].Message)
+	})
+}
+
+func TestAggregateDestinationSyncResults(t *testing.T) {
+	dest := v1alpha1.ApplicationDestination{Server: test.FakeClusterURL, Namespace: test.FakeDestNamespace}
+	other := v1alpha1.ApplicationDestination{Server: otherClusterURL, Namespace: test.FakeDestNamespace}
+	result := func(dest v1alpha1.ApplicationDestination, phase synccommon.OperationPhase, message string) v1alpha1.DestinationSyncResult {
+		return v1alpha1.DestinationSyncResult{Destination: dest, Phase: phase, Message: message}
+	}
+
+	phase, message := aggregateDestinationSyncResults([]v1alpha1.DestinationSyncResult{result(dest, synccommon.OperationSucceeded, ""), result(other, synccommon.OperationSucceeded, "")}, false)
+	assert.Equal(t, synccommon.OperationSucceeded, phase)
+	assert.Equal(t, "successfully synced all destinations", message)
+
+	phase, _ = aggregateDestinationSyncResults([]v1alpha1.DestinationSyncResult{result(dest, synccommon.OperationFailed, "failed"), result(other, synccommon.OperationRunning, "")}, false)
+	assert.Equal(t, synccommon.OperationRunning, phase)
+
+	phase, _ = aggregateDestinationSyncResults([]v1alpha1.DestinationSyncResult{result(dest, synccommon.OperationSucceeded, ""), result(other, synccommon.OperationRunning, "")}, true)
+	assert.Equal(t, synccommon.OperationTerminating, phase)
+
+	phase, message = aggregateDestinationSyncResults([]v1alpha1.DestinationSyncResult{result(dest, synccommon.OperationSucceeded, ""), result(other, synccommon.OperationFailed, "one or more objects failed to apply")}, false)
+	assert.Equal(t, synccommon.OperationFailed, phase)
+	assert.Equal(t, "sync failed for 1 of 2 destinations: "+otherClusterURL+"/"+test.FakeDestNamespace+": one or more objects failed to apply", message)
+}
+
+func TestFanOutClusterSharding(t *testing.T) {
+	db := &dbmocks.ArgoDB{}
+	destinations := &fanOutDestinations{servers: make(map[string][]string)}
+	clusterSharding := &fanOutClusterSharding{ClusterShardingCache: sharding.NewClusterSharding(db, 1, 2, common.DefaultShardingAlgorithm), destinations: destinations}
+	cluster := &v1alpha1.Cluster{Server: otherClusterURL}
+	assert.False(t, clusterSharding.IsManagedCluster(cluster))
+
+	// the additional destination cluster of an application of the shard is managed
+	assert.Empty(t, destinations.set("argocd/guestbook", []string{otherClusterURL}))
+	assert.Empty(t, destinations.set("argocd/helm-guestbook", []string{otherClusterURL}))
+	assert.True(t, clusterSharding.IsManagedCluster(cluster))
+	assert.False(t, clusterSharding.IsManagedCluster(&v1alpha1.Cluster{Server: "https://third-cluster"}))
+
+	// the cluster is released once it is no longer the additional destination of any application
+	assert.Empty(t, destinations.set("argocd/guestbook", nil), "another application has the same additional destination")
+	assert.Equal(t, []string{otherClusterURL}, destinations.set("argocd/helm-guestbook", nil))
+	assert.False(t, clusterSharding.IsManagedCluster(cluster))
+}
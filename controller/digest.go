@@ -0,0 +1,270 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/argoproj/notifications-engine/pkg/api"
+	"github.com/argoproj/notifications-engine/pkg/services"
+	"github.com/robfig/cron/v3"
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	applisters "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
+)
+
+const (
+	// DefaultDriftDigestSchedule is the default cron schedule of the drift digest, every Monday at 8:00
+	DefaultDriftDigestSchedule = "0 8 * * 1"
+
+	// driftDigestEnabledKey is the key of the notifications ConfigMap which enables the drift digest
+	driftDigestEnabledKey = "sendDriftDigest"
+	// driftDigestRecipientsKey is the key of the notifications ConfigMap holding the YAML list of the email addresses
+	// the drift digest is sent to
+	driftDigestRecipientsKey = "driftDigest.recipients"
+	// driftDigestTemplateName is the name of the notification template which overrides the default drift digest email
+	driftDigestTemplateName = "drift-digest"
+	// driftDigestServiceKey is the key of the notifications ConfigMap holding the name of the email notification
+	// service sending the drift digest, `email` by default
+	driftDigestServiceKey = "driftDigest.service"
+	// defaultDriftDigestService is the name of the notification service configured with the service.email key
+	defaultDriftDigestService = "email"
+)
+
+// defaultDriftDigestTemplate is the email sent when the notifications ConfigMap does not define the drift-digest
+// template
+var defaultDriftDigestTemplate = services.Notification{
+	Email: &services.EmailNotification{
+		Subject: "Argo CD drift digest: {{.digest.appCount}} out of sync application(s)",
+		Body: `{{if not .digest.projects}}All applications are in sync.
+{{else}}{{range .digest.projects}}Project {{.name}}:
+{{range .apps}}  * {{.appName}} is {{.syncStatus}}, last synced {{if .lastSyncTime}}{{.lastSyncTime}}{{else}}never{{end}}
+{{range .driftedResources}}    - {{.}}
+{{end}}{{end}}
+{{end}}{{end}}`,
+	},
+}
+
+// DriftedApp is an application listed in the drift digest
+type DriftedApp struct {
+	AppName          string       `json:"appName"`
+	SyncStatus       string       `json:"syncStatus"`
+	DriftedResources []string     `json:"driftedResources"`
+	LastSyncTime     *metav1.Time `json:"lastSyncTime"`
+}
+
+// ProjectDriftDigest lists the out of sync applications of a project
+type ProjectDriftDigest struct {
+	Name string       `json:"name"`
+	Apps []DriftedApp `json:"apps"`
+}
+
+// DriftDigest is a summary of the applications which are not in sync, grouped by project
+type DriftDigest struct {
+	GeneratedAt metav1.Time          `json:"generatedAt"`
+	AppCount    int                  `json:"appCount"`
+	Projects    []ProjectDriftDigest `json:"projects"`
+}
+
+// DriftDigestJob periodically emails a digest of the applications which are not in sync to the recipients configured
+// in the notifications ConfigMap, if it sets sendDriftDigest to true
+type DriftDigestJob struct {
+	schedule      cron.Schedule
+	appLister     applisters.ApplicationLister
+	appFilter     func(obj interface{}) bool
+	kubeClientset kubernetes.Interface
+	// namespace is the namespace of the notifications ConfigMap and Secret
+	namespace string
+}
+
+// NewDriftDigestJob returns a job sending the drift digest of the applications accepted by appFilter on the given
+// cron schedule
+func NewDriftDigestJob(schedule string, appLister applisters.ApplicationLister, appFilter func(obj interface{}) bool, kubeClientset kubernetes.Interface, namespace string) (*DriftDigestJob, error) {
+	parsed, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid drift digest schedule %q: %w", schedule, err)
+	}
+	return &DriftDigestJob{
+		schedule:      parsed,
+		appLister:     appLister,
+		appFilter:     appFilter,
+		kubeClientset: kubeClientset,
+		namespace:     namespace,
+	}, nil
+}
+
+// Run sends the drift digest on schedule until the context is done
+func (j *DriftDigestJob) Run(ctx context.Context) {
+	c := cron.New(cron.WithChain(cron.Recover(cron.PrintfLogger(log.StandardLogger()))))
+	c.Schedule(j.schedule, cron.FuncJob(func() {
+		if err := j.send(ctx, nil); err != nil {
+			log.Errorf("Failed to send the drift digest: %v", err)
+		}
+	}))
+	c.Start()
+	<-ctx.Done()
+	c.Stop()
+}
+
+// send generates the drift digest and emails it to the configured recipients, unless the digest is disabled. The email
+// service configured in the notifications ConfigMap is used unless service is set.
+func (j *DriftDigestJob) send(ctx context.Context, service services.NotificationService) error {
+	configMap, err := j.kubeClientset.CoreV1().ConfigMaps(j.namespace).Get(ctx, common.ArgoCDNotificationsConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("error getting the notifications configmap: %w", err)
+	}
+	if configMap.Data[driftDigestEnabledKey] != "true" {
+		return nil
+	}
+	var recipients []string
+	if err := yaml.Unmarshal([]byte(configMap.Data[driftDigestRecipientsKey]), &recipients); err != nil {
+		return fmt.Errorf("error parsing %s: %w", driftDigestRecipientsKey, err)
+	}
+	if len(recipients) == 0 {
+		return fmt.Errorf("%s is enabled, but %s does not list any recipient", driftDigestEnabledKey, driftDigestRecipientsKey)
+	}
+	secret, err := j.kubeClientset.CoreV1().Secrets(j.namespace).Get(ctx, common.ArgoCDNotificationsSecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{}
+	} else if err != nil {
+		return fmt.Errorf("error getting the notifications secret: %w", err)
+	}
+	cfg, err := api.ParseConfig(configMap, secret)
+	if err != nil {
+		return fmt.Errorf("error parsing the notifications configuration: %w", err)
+	}
+	serviceName := configMap.Data[driftDigestServiceKey]
+	if serviceName == "" {
+		serviceName = defaultDriftDigestService
+	}
+	if service == nil {
+		newService, ok := cfg.Services[serviceName]
+		if !ok {
+			return fmt.Errorf("%s is enabled, but the %s notification service is not configured", driftDigestEnabledKey, serviceName)
+		}
+		if service, err = newService(); err != nil {
+			return fmt.Errorf("error creating the %s notification service: %w", serviceName, err)
+		}
+	}
+	template, ok := cfg.Templates[driftDigestTemplateName]
+	if !ok {
+		template = defaultDriftDigestTemplate
+	}
+
+	apps, err := j.appLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("error listing applications: %w", err)
+	}
+	var filtered []*appv1.Application
+	for _, app := range apps {
+		if j.appFilter(app) {
+			filtered = append(filtered, app)
+		}
+	}
+	digestContext := map[string]string{}
+	if contextYAML, ok := configMap.Data["context"]; ok {
+		if err := yaml.Unmarshal([]byte(contextYAML), &digestContext); err != nil {
+			return fmt.Errorf("error parsing the notifications context: %w", err)
+		}
+	}
+	notification, err := renderDriftDigest(template, generateDriftDigest(filtered, time.Now()), digestContext)
+	if err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		if err := service.Send(*notification, services.Destination{Service: serviceName, Recipient: recipient}); err != nil {
+			return fmt.Errorf("error sending the drift digest to %s: %w", recipient, err)
+		}
+	}
+	log.Infof("Sent the drift digest to %d recipient(s)", len(recipients))
+	return nil
+}
+
+// generateDriftDigest lists the applications which are not in sync, grouped by project and sorted by name
+func generateDriftDigest(apps []*appv1.Application, now time.Time) DriftDigest {
+	digest := DriftDigest{GeneratedAt: metav1.NewTime(now), Projects: []ProjectDriftDigest{}}
+	byProject := map[string][]DriftedApp{}
+	for _, app := range apps {
+		if app.Status.Sync.Status == appv1.SyncStatusCodeSynced {
+			continue
+		}
+		drifted := DriftedApp{
+			AppName:          app.QualifiedName(),
+			SyncStatus:       string(app.Status.Sync.Status),
+			DriftedResources: []string{},
+		}
+		if drifted.SyncStatus == "" {
+			drifted.SyncStatus = string(appv1.SyncStatusCodeUnknown)
+		}
+		for _, res := range app.Status.Resources {
+			if res.Status == appv1.SyncStatusCodeOutOfSync {
+				drifted.DriftedResources = append(drifted.DriftedResources, formatDriftedResource(res))
+			}
+		}
+		if len(app.Status.History) > 0 {
+			deployedAt := app.Status.History.LastRevisionHistory().DeployedAt
+			drifted.LastSyncTime = &deployedAt
+		}
+		byProject[app.Spec.GetProject()] = append(byProject[app.Spec.GetProject()], drifted)
+		digest.AppCount++
+	}
+	for project, projectApps := range byProject {
+		sort.Slice(projectApps, func(i, j int) bool {
+			return projectApps[i].AppName < projectApps[j].AppName
+		})
+		digest.Projects = append(digest.Projects, ProjectDriftDigest{Name: project, Apps: projectApps})
+	}
+	sort.Slice(digest.Projects, func(i, j int) bool {
+		return digest.Projects[i].Name < digest.Projects[j].Name
+	})
+	return digest
+}
+
+// formatDriftedResource formats an out of sync resource as <group>/<kind>/<namespace>/<name>, omitting the empty group
+// and namespace
+func formatDriftedResource(res appv1.ResourceStatus) string {
+	var parts []string
+	for _, part := range []string{res.Group, res.Kind, res.Namespace, res.Name} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// renderDriftDigest renders the notification template with the digest as the `digest` variable and the notifications
+// context as the `context` variable
+func renderDriftDigest(template services.Notification, digest DriftDigest, digestContext map[string]string) (*services.Notification, error) {
+	// the digest is exposed to templates like the applications, with the field names of its JSON representation
+	data, err := json.Marshal(digest)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling the drift digest: %w", err)
+	}
+	var digestVar map[string]interface{}
+	if err := json.Unmarshal(data, &digestVar); err != nil {
+		return nil, fmt.Errorf("error unmarshaling the drift digest: %w", err)
+	}
+	templater, err := template.GetTemplater(driftDigestTemplateName, sprig.TxtFuncMap())
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the %s template: %w", driftDigestTemplateName, err)
+	}
+	notification := &services.Notification{}
+	if err := templater(notification, map[string]interface{}{"digest": digestVar, "context": digestContext}); err != nil {
+		return nil, fmt.Errorf("error rendering the %s template: %w", driftDigestTemplateName, err)
+	}
+	return notification, nil
+}
@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/argoproj/notifications-engine/pkg/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	applisters "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+type fakeNotificationService struct {
+	sent []services.Destination
+	last services.Notification
+}
+
+func (s *fakeNotificationService) Send(notification services.Notification, dest services.Destination) error {
+	s.sent = append(s.sent, dest)
+	s.last = notification
+	return nil
+}
+
+func newDigestApp(name string, project string, status appv1.SyncStatusCode, resources ...appv1.ResourceStatus) *appv1.Application {
+	return &appv1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "argocd"},
+		Spec:       appv1.ApplicationSpec{Project: project},
+		Status: appv1.ApplicationStatus{
+			Sync:      appv1.SyncStatus{Status: status},
+			Resources: resources,
+		},
+	}
+}
+
+func newDigestApps() []*appv1.Application {
+	synced := newDigestApp("synced", "default", appv1.SyncStatusCodeSynced)
+	guestbook := newDigestApp("guestbook", "team-a", appv1.SyncStatusCodeOutOfSync,
+		appv1.ResourceStatus{Group: "apps", Kind: "Deployment", Namespace: "guestbook", Name: "guestbook-ui", Status: appv1.SyncStatusCodeOutOfSync},
+		appv1.ResourceStatus{Kind: "Service", Namespace: "guestbook", Name: "guestbook-ui", Status: appv1.SyncStatusCodeSynced},
+		appv1.ResourceStatus{Kind: "Namespace", Name: "guestbook", Status: appv1.SyncStatusCodeOutOfSync})
+	guestbook.Status.History = appv1.RevisionHistories{
+		{ID: 1, DeployedAt: metav1.NewTime(time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC))},
+		{ID: 2, DeployedAt: metav1.NewTime(time.Date(2024, 1, 2, 8, 0, 0, 0, time.UTC))},
+	}
+	return []*appv1.Application{
+		synced,
+		guestbook,
+		newDigestApp("api", "team-a", appv1.SyncStatusCodeUnknown),
+		newDigestApp("never-reconciled", "default", ""),
+	}
+}
+
+func TestGenerateDriftDigest(t *testing.T) {
+	now := time.Date(2024, 1, 8, 8, 0, 0, 0, time.UTC)
+	digest := generateDriftDigest(newDigestApps(), now)
+
+	assert.Equal(t, now, digest.GeneratedAt.Time)
+	assert.Equal(t, 3, digest.AppCount)
+	require.Len(t, digest.Projects, 2)
+
+	assert.Equal(t, "default", digest.Projects[0].Name)
+	require.Len(t, digest.Projects[0].Apps, 1)
+	assert.Equal(t, DriftedApp{AppName: "argocd/never-reconciled", SyncStatus: "Unknown", DriftedResources: []string{}}, digest.Projects[0].Apps[0])
+
+	assert.Equal(t, "team-a", digest.Projects[1].Name)
+	require.Len(t, digest.Projects[1].Apps, 2)
+	assert.Equal(t, "argocd/api", digest.Projects[1].Apps[0].AppName)
+	assert.Nil(t, digest.Projects[1].Apps[0].LastSyncTime)
+	guestbook := digest.Projects[1].Apps[1]
+	assert.Equal(t, "argocd/guestbook", guestbook.AppName)
+	assert.Equal(t, "OutOfSync", guestbook.SyncStatus)
+	assert.Equal(t, []string{"apps/Deployment/guestbook/guestbook-ui", "Namespace/guestbook"}, guestbook.DriftedResources)
+	require.NotNil(t, guestbook.LastSyncTime)
+	assert.Equal(t, time.Date(2024, 1, 2, 8, 0, 0, 0, time.UTC), guestbook.LastSyncTime.Time)
+}
+
+func TestRenderDriftDigest(t *testing.T) {
+	digest := generateDriftDigest(newDigestApps(), time.Now())
+
+	notification, err := renderDriftDigest(defaultDriftDigestTemplate, digest, nil)
+	require.NoError(t, err)
+	require.NotNil(t, notification.Email)
+	assert.Equal(t, "Argo CD drift digest: 3 out of sync application(s)", notification.Email.Subject)
+	assert.Equal(t, `Project default:
+  * argocd/never-reconciled is Unknown, last synced never
+
+Project team-a:
+  * argocd/api is Unknown, last synced never
+  * argocd/guestbook is OutOfSync, last synced 2024-01-02T08:00:00Z
+    - apps/Deployment/guestbook/guestbook-ui
+    - Namespace/guestbook
+
+`, notification.Email.Body)
+
+	notification, err = renderDriftDigest(defaultDriftDigestTemplate, generateDriftDigest(nil, time.Now()), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "All applications are in sync.\n", notification.Email.Body)
+
+	custom := services.Notification{Email: &services.EmailNotification{
+		Subject: "{{.digest.appCount}} apps drifted",
+		Body:    "{{range .digest.projects}}{{.name}}: {{len .apps}}, {{end}}see {{.context.argocdUrl}}",
+	}}
+	notification, err = renderDriftDigest(custom, digest, map[string]string{"argocdUrl": "https://argocd.example.com"})
+	require.NoError(t, err)
+	assert.Equal(t, "3 apps drifted", notification.Email.Subject)
+	assert.Equal(t, "default: 1, team-a: 2, see https://argocd.example.com", notification.Email.Body)
+}
+
+func newTestDriftDigestJob(t *testing.T, configMapData map[string]string) *DriftDigestJob {
+	t.Helper()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, app := range newDigestApps() {
+		require.NoError(t, indexer.Add(app))
+	}
+	kubeClientset := fake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ArgoCDNotificationsConfigMapName, Namespace: test.FakeArgoCDNamespace},
+		Data:       configMapData,
+	})
+	appFilter := func(obj interface{}) bool {
+		return obj.(*appv1.Application).Name != "api"
+	}
+	job, err := NewDriftDigestJob(DefaultDriftDigestSchedule, applisters.NewApplicationLister(indexer), appFilter, kubeClientset, test.FakeArgoCDNamespace)
+	require.NoError(t, err)
+	return job
+}
+
+func TestDriftDigestJob_Send(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		service := &fakeNotificationService{}
+		job := newTestDriftDigestJob(t, map[string]string{driftDigestRecipientsKey: "- ops@example.com"})
+		require.NoError(t, job.send(context.Background(), service))
+		assert.Empty(t, service.sent)
+	})
+
+	t.Run("Enabled", func(t *testing.T) {
+		service := &fakeNotificationService{}
+		job := newTestDriftDigestJob(t, map[string]string{
+			driftDigestEnabledKey:    "true",
+			driftDigestRecipientsKey: "- ops@example.com\n- sre@example.com",
+		})
+		require.NoError(t, job.send(context.Background(), service))
+		assert.Equal(t, []services.Destination{{Service: "email", Recipient: "ops@example.com"}, {Service: "email", Recipient: "sre@example.com"}}, service.sent)
+		// applications rejected by the filter are not listed
+		assert.Equal(t, "Argo CD drift digest: 2 out of sync application(s)", service.last.Email.Subject)
+		assert.NotContains(t, service.last.Email.Body, "argocd/api")
+	})
+
+	t.Run("CustomTemplate", func(t *testing.T) {
+		service := &fakeNotificationService{}
+		job := newTestDriftDigestJob(t, map[string]string{
+			driftDigestEnabledKey:    "true",
+			driftDigestRecipientsKey: "- ops@example.com",
+			"template.drift-digest":  "email:\n  subject: Weekly drift report\n  body: '{{.digest.appCount}} drifted'",
+		})
+		require.NoError(t, job.send(context.Background(), service))
+		assert.Equal(t, "Weekly drift report", service.last.Email.Subject)
+		assert.Equal(t, "2 drifted", service.last.Email.Body)
+	})
+
+	t.Run("NamedService", func(t *testing.T) {
+		service := &fakeNotificationService{}
+		job := newTestDriftDigestJob(t, map[string]string{
+			driftDigestEnabledKey:    "true",
+			driftDigestRecipientsKey: "- ops@example.com",
+			driftDigestServiceKey:    "gmail",
+		})
+		require.NoError(t, job.send(context.Background(), service))
+		assert.Equal(t, []services.Destination{{Service: "gmail", Recipient: "ops@example.com"}}, service.sent)
+	})
+
+	t.Run("NoRecipients", func(t *testing.T) {
+		job := newTestDriftDigestJob(t, map[string]string{driftDigestEnabledKey: "true"})
+		require.ErrorContains(t, job.send(context.Background(), &fakeNotificationService{}), "does not list any recipient")
+	})
+
+	t.Run("NoEmailService", func(t *testing.T) {
+		job := newTestDriftDigestJob(t, map[string]string{driftDigestEnabledKey: "true", driftDigestRecipientsKey: "- ops@example.com"})
+		require.ErrorContains(t, job.send(context.Background(), nil), "the email notification service is not configured")
+	})
+}
+
+func TestNewDriftDigestJob_InvalidSchedule(t *testing.T) {
+	_, err := NewDriftDigestJob("every monday", nil, nil, fake.NewSimpleClientset(), test.FakeArgoCDNamespace)
+	require.ErrorContains(t, err, `invalid drift digest schedule "every monday"`)
+}
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"sync"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// debouncedEvent is the latest event of a resource received during its debounce interval
+type debouncedEvent struct {
+	managedByApp map[string]bool
+	ref          v1.ObjectReference
+	// pending is whether an event was received since the last one was handled
+	pending bool
+	timer   *time.Timer
+}
+
+// debounceMap handles at most one event per resource per interval. The first event of a resource is handled at once,
+// the next ones received during the interval are coalesced into the latest one, which is handled when the interval
+// elapses and starts the next interval. Deletions are always handled at once, so that applications are refreshed
+// promptly when their resources are deleted.
+type debounceMap struct {
+	interval time.Duration
+	handle   func(managedByApp map[string]bool, ref v1.ObjectReference)
+	// onFiltered is called for each event coalesced into a later event of the same resource
+	onFiltered func()
+
+	lock   sync.Mutex
+	events map[v1.ObjectReference]*debouncedEvent
+}
+
+func newDebounceMap(interval time.Duration, handle func(managedByApp map[string]bool, ref v1.ObjectReference), onFiltered func()) *debounceMap {
+	return &debounceMap{
+		interval:   interval,
+		handle:     handle,
+		onFiltered: onFiltered,
+		events:     map[v1.ObjectReference]*debouncedEvent{},
+	}
+}
+
+// debounceKey identifies a resource by its reference, regardless of its resource version
+func debounceKey(ref v1.ObjectReference) v1.ObjectReference {
+	ref.ResourceVersion = ""
+	ref.FieldPath = ""
+	return ref
+}
+
+// add handles the event of a resource, or defers it until the debounce interval of the resource elapses
+func (d *debounceMap) add(managedByApp map[string]bool, ref v1.ObjectReference, deleted bool) {
+	key := debounceKey(ref)
+	d.lock.Lock()
+	event, ok := d.events[key]
+	if deleted {
+		if ok {
+			event.timer.Stop()
+			delete(d.events, key)
+			if event.pending {
+				d.onFiltered()
+			}
+		}
+		d.lock.Unlock()
+		d.handle(managedByApp, ref)
+		return
+	}
+	if !ok {
+		event = &debouncedEvent{}
+		event.timer = time.AfterFunc(d.interval, func() { d.flush(key, event) })
+		d.events[key] = event
+		d.lock.Unlock()
+		d.handle(managedByApp, ref)
+		return
+	}
+	if event.pending {
+		d.onFiltered()
+		for appName, isManagedResource := range managedByApp {
+			event.managedByApp[appName] = isManagedResource || event.managedByApp[appName]
+		}
+	} else {
+		event.managedByApp = make(map[string]bool, len(managedByApp))
+		for appName, isManagedResource := range managedByApp {
+			event.managedByApp[appName] = isManagedResource
+		}
+	}
+	event.ref = ref
+	event.pending = true
+	d.lock.Unlock()
+}
+
+// flush handles the pending event of a resource once its debounce interval elapsed, and starts the next interval. The
+// resource is forgotten if no event was received during the interval.
+func (d *debounceMap) flush(key v1.ObjectReference, event *debouncedEvent) {
+	d.lock.Lock()
+	if d.events[key] != event {
+		// the resource was deleted in the meantime
+		d.lock.Unlock()
+		return
+	}
+	if !event.pending {
+		delete(d.events, key)
+		d.lock.Unlock()
+		return
+	}
+	managedByApp, ref := event.managedByApp, event.ref
+	event.managedByApp = nil
+	event.pending = false
+	event.timer.Reset(d.interval)
+	d.lock.Unlock()
+	d.handle(managedByApp, ref)
+}
+
+// size returns the number of resources whose debounce interval did not elapse
+func (d *debounceMap) size() int {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return len(d.events)
+}
+
+// handleObjectEvent handles the events of the resources watched in the clusters, which are debounced unless the
+// event filter is disabled
+func (ctrl *ApplicationController) handleObjectEvent(managedByApp map[string]bool, ref v1.ObjectReference, deleted bool) {
+	if ctrl.eventFilter == nil {
+		ctrl.handleObjectUpdated(managedByApp, ref)
+		return
+	}
+	ctrl.eventFilter.add(managedByApp, ref, deleted)
+}
@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// recordedEvent is an event handled by a debounceMap
+type recordedEvent struct {
+	managedByApp map[string]bool
+	ref          v1.ObjectReference
+}
+
+// eventRecorder records the events handled by a debounceMap, and counts the filtered ones
+type eventRecorder struct {
+	lock     sync.Mutex
+	events   []recordedEvent
+	filtered atomic.Int32
+}
+
+func (r *eventRecorder) handle(managedByApp map[string]bool, ref v1.ObjectReference) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.events = append(r.events, recordedEvent{managedByApp, ref})
+}
+
+func (r *eventRecorder) handled() []recordedEvent {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return append([]recordedEvent{}, r.events...)
+}
+
+func newDebounceTestRef(name, resourceVersion string) v1.ObjectReference {
+	return v1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "default", Name: name, UID: types.UID("uid-" + name), ResourceVersion: resourceVersion}
+}
+
+func newTestDebounceMap(interval time.Duration) (*debounceMap, *eventRecorder) {
+	recorder := &eventRecorder{}
+	return newDebounceMap(interval, recorder.handle, func() { recorder.filtered.Add(1) }), recorder
+}
+
+func TestDebounceMap_HighFrequency(t *testing.T) {
+	d, recorder := newTestDebounceMap(200 * time.Millisecond)
+	app := map[string]bool{"argocd_guestbook": true}
+
+	// an HPA constantly adjusting the replicas of a deployment
+	const events = 1000
+	for i := 1; i <= events; i++ {
+		d.add(app, newDebounceTestRef("guestbook", string(rune('0'+i%10))), false)
+	}
+	// the first event is handled at once, the next ones are coalesced
+	handled := recorder.handled()
+	require.Len(t, handled, 1)
+	assert.Equal(t, newDebounceTestRef("guestbook", "1"), handled[0].ref)
+	assert.Equal(t, int32(events-2), recorder.filtered.Load())
+
+	// the latest event is handled once the interval elapses
+	require.Eventually(t, func() bool { return len(recorder.handled()) == 2 }, 2*time.Second, 10*time.Millisecond)
+	handled = recorder.handled()
+	assert.Equal(t, newDebounceTestRef("guestbook", "0"), handled[1].ref)
+	assert.Equal(t, app, handled[1].managedByApp)
+
+	// the resource is forgotten after an interval without events
+	require.Eventually(t, func() bool { return d.size() == 0 }, 2*time.Second, 10*time.Millisecond)
+	assert.Len(t, recorder.handled(), 2)
+	d.add(app, newDebounceTestRef("guestbook", "2"), false)
+	assert.Len(t, recorder.handled(), 3)
+}
+
+func TestDebounceMap_AtMostOneEventPerInterval(t *testing.T) {
+	const interval = 100 * time.Millisecond
+	d, recorder := newTestDebounceMap(interval)
+	app := map[string]bool{"argocd_guestbook": true}
+
+	start := time.Now()
+	for time.Since(start) < 5*interval {
+		d.add(app, newDebounceTestRef("guestbook", ""), false)
+		time.Sleep(time.Millisecond)
+	}
+	// one event at once, then one per elapsed interval
+	handled := len(recorder.handled())
+	assert.GreaterOrEqual(t, handled, 3)
+	assert.LessOrEqual(t, handled, 6)
+}
+
+func TestDebounceMap_Resources(t *testing.T) {
+	d, recorder := newTestDebounceMap(time.Hour)
+
+	d.add(map[string]bool{"argocd_guestbook": true}, newDebounceTestRef("guestbook", "1"), false)
+	d.add(map[string]bool{"argocd_helm-guestbook": true}, newDebounceTestRef("helm-guestbook", "1"), false)
+	// events of different resources are not coalesced
+	assert.Len(t, recorder.handled(), 2)
+	assert.Equal(t, 2, d.size())
+	assert.Zero(t, recorder.filtered.Load())
+}
+
+func TestDebounceMap_MergesApplications(t *testing.T) {
+	d, recorder := newTestDebounceMap(100 * time.Millisecond)
+
+	d.add(map[string]bool{"argocd_guestbook": true}, newDebounceTestRef("guestbook", "1"), false)
+	// the resource moves to another application while its events are debounced
+	d.add(map[string]bool{"argocd_guestbook": true, "argocd_other": false}, newDebounceTestRef("guestbook", "2"), false)
+	d.add(map[string]bool{"argocd_other": true}, newDebounceTestRef("guestbook", "3"), false)
+
+	require.Eventually(t, func() bool { return len(recorder.handled()) == 2 }, 2*time.Second, 10*time.Millisecond)
+	handled := recorder.handled()[1]
+	assert.Equal(t, newDebounceTestRef("guestbook", "3"), handled.ref)
+	assert.Equal(t, map[string]bool{"argocd_guestbook": true, "argocd_other": true}, handled.managedByApp)
+	assert.Equal(t, int32(1), recorder.filtered.Load())
+}
+
+func TestDebounceMap_DeleteBypassesFilter(t *testing.T) {
+	d, recorder := newTestDebounceMap(time.Hour)
+	app := map[string]bool{"argocd_guestbook": true}
+
+	d.add(app, newDebounceTestRef("guestbook", "1"), false)
+	d.add(app, newDebounceTestRef("guestbook", "2"), false)
+	require.Len(t, recorder.handled(), 1)
+
+	// the deletion is handled at once, and supersedes the pending update
+	d.add(app, newDebounceTestRef("guestbook", "3"), true)
+	handled := recorder.handled()
+	require.Len(t, handled, 2)
+	assert.Equal(t, newDebounceTestRef("guestbook", "3"), handled[1].ref)
+	assert.Equal(t, int32(1), recorder.filtered.Load())
+	assert.Zero(t, d.size())
+
+	// a resource created again with the same name is not debounced by the deleted one
+	d.add(app, newDebounceTestRef("guestbook", "4"), false)
+	assert.Len(t, recorder.handled(), 3)
+}
@@ -12,13 +12,21 @@ import (
 
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
 	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	healthutil "github.com/argoproj/argo-cd/v2/util/health"
 	"github.com/argoproj/argo-cd/v2/util/lua"
 )
 
-// setApplicationHealth updates the health statuses of all resources performed in the comparison
-func setApplicationHealth(resources []managedResource, statuses []appv1.ResourceStatus, resourceOverrides map[string]appv1.ResourceOverride, app *appv1.Application, persistResourceHealth bool) (*appv1.HealthStatus, error) {
+// setApplicationHealth updates the health statuses of all resources performed in the comparison. Resources without a
+// health check are assumed to have the given default health, or to be healthy if the application ignores missing health
+// checks. The scripts of the argocd-health-overrides ConfigMap take precedence over the resource overrides and built-in
+// health checks. Unless disabled, the health-override annotation of a resource takes precedence over its computed health.
+func setApplicationHealth(resources []managedResource, statuses []appv1.ResourceStatus, resourceOverrides map[string]appv1.ResourceOverride, healthOverrideScripts lua.HealthOverrideScripts, app *appv1.Application, persistResourceHealth bool, defaultHealthForUnknownResources health.HealthStatusCode, disableHealthOverrides bool) (*appv1.HealthStatus, error) {
+	if app.Spec.IgnoreMissingHealthChecks {
+		defaultHealthForUnknownResources = health.HealthStatusHealthy
+	}
 	var savedErr error
 	var errCount uint
+	suspended := false
 	appHealth := appv1.HealthStatus{Status: health.HealthStatusHealthy}
 	for i, res := range resources {
 		if res.Target != nil && hookutil.Skip(res.Target) {
@@ -32,6 +40,10 @@ func setApplicationHealth(resources []managedResource, statuses []appv1.Resource
 		var healthStatus *health.HealthStatus
 		var err error
 		healthOverrides := lua.ResourceHealthOverrides(resourceOverrides)
+		resourceHealthOverride := lua.NewResourceHealthOverride(healthOverrideScripts, resourceOverrides)
+		if !disableHealthOverrides {
+			resourceHealthOverride = healthutil.NewAnnotationHealthOverride(resourceHealthOverride)
+		}
 		gvk := schema.GroupVersionKind{Group: res.Group, Version: res.Version, Kind: res.Kind}
 		if res.Live == nil {
 			healthStatus = &health.HealthStatus{Status: health.HealthStatusMissing}
@@ -40,7 +52,7 @@ func setApplicationHealth(resources []managedResource, statuses []appv1.Resource
 			if isSelfReferencedApp(app, kubeutil.GetObjectRef(res.Live)) {
 				continue
 			}
-			healthStatus, err = health.GetResourceHealth(res.Live, healthOverrides)
+			healthStatus, err = healthutil.GetResourceHealth(res.Live, resourceHealthOverride, defaultHealthForUnknownResources)
 			if err != nil && savedErr == nil {
 				errCount++
 				savedErr = fmt.Errorf("failed to get resource health for %q with name %q in namespace %q: %w", res.Live.GetKind(), res.Live.GetName(), res.Live.GetNamespace(), err)
@@ -61,7 +73,9 @@ func setApplicationHealth(resources []managedResource, statuses []appv1.Resource
 		}
 
 		// Is health status is missing but resource has not built-in/custom health check then it should not affect parent app health
-		if _, hasOverride := healthOverrides[lua.GetConfigMapKey(gvk)]; healthStatus.Status == health.HealthStatusMissing && !hasOverride && health.GetHealthCheckFunc(gvk) == nil {
+		_, hasOverride := healthOverrides[lua.GetConfigMapKey(gvk)]
+		_, hasScript := healthOverrideScripts[gvk]
+		if healthStatus.Status == health.HealthStatusMissing && !hasOverride && !hasScript && healthutil.GetHealthCheckFunc(gvk) == nil {
 			continue
 		}
 
@@ -70,10 +84,21 @@ func setApplicationHealth(resources []managedResource, statuses []appv1.Resource
 			continue
 		}
 
+		// Suspended resources are intentionally not making progress, such as paused Deployments or suspended CronJobs,
+		// and do not hide the worse health of the other resources
+		if healthStatus.Status == health.HealthStatusSuspended {
+			suspended = true
+			continue
+		}
+
 		if health.IsWorse(appHealth.Status, healthStatus.Status) {
 			appHealth.Status = healthStatus.Status
 		}
 	}
+	// the application is suspended if its other resources are healthy
+	if suspended && appHealth.Status == health.HealthStatusHealthy {
+		appHealth.Status = health.HealthStatusSuspended
+	}
 	if persistResourceHealth {
 		app.Status.ResourceHealthSource = appv1.ResourceHealthLocationInline
 	} else {
@@ -14,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"sigs.k8s.io/yaml"
 
+	"github.com/argoproj/argo-cd/v2/common"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
 	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/argoproj/argo-cd/v2/util/lua"
@@ -53,7 +54,7 @@ func TestSetApplicationHealth(t *testing.T) {
 	}}
 	resourceStatuses := initStatuses(resources)
 
-	healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, app, true)
+	healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, app, true, "", false)
 	require.NoError(t, err)
 	assert.Equal(t, health.HealthStatusDegraded, healthStatus.Status)
 
@@ -62,11 +63,57 @@ func TestSetApplicationHealth(t *testing.T) {
 
 	// now mark the job as a hook and retry. it should ignore the hook and consider the app healthy
 	failedJob.SetAnnotations(map[string]string{synccommon.AnnotationKeyHook: "PreSync"})
-	healthStatus, err = setApplicationHealth(resources, resourceStatuses, nil, app, true)
+	healthStatus, err = setApplicationHealth(resources, resourceStatuses, nil, nil, app, true, "", false)
 	require.NoError(t, err)
 	assert.Equal(t, health.HealthStatusHealthy, healthStatus.Status)
 }
 
+func TestSetApplicationHealth_SuspendedResource(t *testing.T) {
+	pausedDeployment := resourceFromFile("./testdata/deployment-paused.yaml")
+	runningPod := resourceFromFile("./testdata/pod-running-restart-always.yaml")
+
+	t.Run("SuspendedResource", func(t *testing.T) {
+		resources := []managedResource{{
+			Group: "", Version: "v1", Kind: "Pod", Live: &runningPod,
+		}, {
+			Group: "apps", Version: "v1", Kind: "Deployment", Live: &pausedDeployment,
+		}}
+		resourceStatuses := initStatuses(resources)
+
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, app, true, "", false)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusSuspended, healthStatus.Status)
+		assert.Equal(t, health.HealthStatusSuspended, resourceStatuses[1].Health.Status)
+	})
+
+	t.Run("MissingResource", func(t *testing.T) {
+		resources := []managedResource{{
+			Group: "apps", Version: "v1", Kind: "Deployment", Live: &pausedDeployment,
+		}, {
+			Group: "", Version: "v1", Kind: "Pod", Target: &runningPod,
+		}}
+		resourceStatuses := initStatuses(resources)
+
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, app, true, "", false)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusMissing, healthStatus.Status)
+	})
+
+	t.Run("DegradedResource", func(t *testing.T) {
+		failedJob := resourceFromFile("./testdata/job-failed.yaml")
+		resources := []managedResource{{
+			Group: "apps", Version: "v1", Kind: "Deployment", Live: &pausedDeployment,
+		}, {
+			Group: "batch", Version: "v1", Kind: "Job", Live: &failedJob,
+		}}
+		resourceStatuses := initStatuses(resources)
+
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, app, true, "", false)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusDegraded, healthStatus.Status)
+	})
+}
+
 func TestSetApplicationHealth_ResourceHealthNotPersisted(t *testing.T) {
 	failedJob := resourceFromFile("./testdata/job-failed.yaml")
 
@@ -75,7 +122,7 @@ func TestSetApplicationHealth_ResourceHealthNotPersisted(t *testing.T) {
 	}}
 	resourceStatuses := initStatuses(resources)
 
-	healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, app, false)
+	healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, app, false, "", false)
 	require.NoError(t, err)
 	assert.Equal(t, health.HealthStatusDegraded, healthStatus.Status)
 
@@ -90,7 +137,7 @@ func TestSetApplicationHealth_MissingResource(t *testing.T) {
 	}, {}}
 	resourceStatuses := initStatuses(resources)
 
-	healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, app, true)
+	healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, app, true, "", false)
 	require.NoError(t, err)
 	assert.Equal(t, health.HealthStatusMissing, healthStatus.Status)
 }
@@ -104,7 +151,7 @@ func TestSetApplicationHealth_MissingResourceNoBuiltHealthCheck(t *testing.T) {
 	resourceStatuses := initStatuses(resources)
 
 	t.Run("NoOverride", func(t *testing.T) {
-		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, app, true)
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, app, true, "", false)
 		require.NoError(t, err)
 		assert.Equal(t, health.HealthStatusHealthy, healthStatus.Status)
 		assert.Equal(t, health.HealthStatusMissing, resourceStatuses[0].Health.Status)
@@ -115,12 +162,131 @@ func TestSetApplicationHealth_MissingResourceNoBuiltHealthCheck(t *testing.T) {
 			lua.GetConfigMapKey(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}): appv1.ResourceOverride{
 				HealthLua: "some health check",
 			},
-		}, app, true)
+		}, nil, app, true, "", false)
 		require.NoError(t, err)
 		assert.Equal(t, health.HealthStatusMissing, healthStatus.Status)
 	})
 }
 
+func TestSetApplicationHealth_UnknownResourceTypes(t *testing.T) {
+	runningPod := resourceFromFile("./testdata/pod-running-restart-always.yaml")
+	cm := resourceFromFile("./testdata/configmap.yaml")
+
+	resources := []managedResource{{
+		Group: "", Version: "v1", Kind: "Pod", Live: &runningPod,
+	}, {
+		Group: "", Version: "v1", Kind: "ConfigMap", Live: &cm,
+	}}
+
+	t.Run("NoDefaultHealth", func(t *testing.T) {
+		resourceStatuses := initStatuses(resources)
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, app, true, "", false)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusHealthy, healthStatus.Status)
+		assert.Nil(t, resourceStatuses[1].Health)
+	})
+
+	t.Run("DefaultHealthProgressing", func(t *testing.T) {
+		resourceStatuses := initStatuses(resources)
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, app, true, health.HealthStatusProgressing, false)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusProgressing, healthStatus.Status)
+		assert.Equal(t, health.HealthStatusHealthy, resourceStatuses[0].Health.Status)
+		assert.Equal(t, health.HealthStatusProgressing, resourceStatuses[1].Health.Status)
+	})
+
+	t.Run("DefaultHealthUnknown", func(t *testing.T) {
+		resourceStatuses := initStatuses(resources)
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, app, true, health.HealthStatusUnknown, false)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusUnknown, healthStatus.Status)
+	})
+
+	t.Run("DegradedResourceIsWorseThanDefaultHealth", func(t *testing.T) {
+		failedJob := resourceFromFile("./testdata/job-failed.yaml")
+		resources := append(resources, managedResource{Group: "batch", Version: "v1", Kind: "Job", Live: &failedJob})
+		resourceStatuses := initStatuses(resources)
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, app, true, health.HealthStatusProgressing, false)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusDegraded, healthStatus.Status)
+	})
+
+	t.Run("IgnoreMissingHealthChecks", func(t *testing.T) {
+		ignoringApp := &appv1.Application{Spec: appv1.ApplicationSpec{IgnoreMissingHealthChecks: true}}
+		resourceStatuses := initStatuses(resources)
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, ignoringApp, true, health.HealthStatusUnknown, false)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusHealthy, healthStatus.Status)
+		assert.Equal(t, health.HealthStatusHealthy, resourceStatuses[1].Health.Status)
+	})
+
+	t.Run("CustomHealthCheck", func(t *testing.T) {
+		resourceStatuses := initStatuses(resources)
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{
+			lua.GetConfigMapKey(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}): appv1.ResourceOverride{
+				HealthLua: `return {status = "Healthy"}`,
+			},
+		}, nil, app, true, health.HealthStatusUnknown, false)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusHealthy, healthStatus.Status)
+	})
+}
+
+func TestSetApplicationHealth_HealthOverrideScripts(t *testing.T) {
+	degradedDeployment := resourceFromFile("./testdata/live-deployment.yaml")
+	degradedDeployment.Object["status"] = map[string]interface{}{
+		"observedGeneration": degradedDeployment.GetGeneration(),
+		"conditions":         []interface{}{map[string]interface{}{"type": "Progressing", "status": "False", "reason": "ProgressDeadlineExceeded"}},
+	}
+	resources := []managedResource{{
+		Group: "apps", Version: "v1", Kind: "Deployment", Live: &degradedDeployment,
+	}}
+
+	t.Run("BuiltInHealthCheck", func(t *testing.T) {
+		resourceStatuses := initStatuses(resources)
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, nil, nil, app, true, "", false)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusDegraded, healthStatus.Status)
+	})
+
+	t.Run("OverriddenAsHealthy", func(t *testing.T) {
+		scripts, err := lua.ParseHealthOverrideScripts(map[string]string{
+			"apps_v1_Deployment": `function health_status(obj) return {status = "Healthy"} end`,
+		})
+		require.NoError(t, err)
+		resourceStatuses := initStatuses(resources)
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{
+			"apps/Deployment": appv1.ResourceOverride{HealthLua: `return {status = "Degraded"}`},
+		}, scripts, app, true, "", false)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusHealthy, healthStatus.Status)
+		assert.Equal(t, health.HealthStatusHealthy, resourceStatuses[0].Health.Status)
+	})
+}
+
+func TestSetApplicationHealth_HealthOverrideAnnotation(t *testing.T) {
+	failedJob := resourceFromFile("./testdata/job-failed.yaml")
+	failedJob.SetAnnotations(map[string]string{common.AnnotationHealthOverride: "Healthy"})
+	resources := []managedResource{{
+		Group: "batch", Version: "v1", Kind: "Job", Live: &failedJob,
+	}}
+
+	t.Run("Enabled", func(t *testing.T) {
+		resourceStatuses := initStatuses(resources)
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, app, true, "", false)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusHealthy, healthStatus.Status)
+		assert.Equal(t, health.HealthStatusHealthy, resourceStatuses[0].Health.Status)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		resourceStatuses := initStatuses(resources)
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, lua.ResourceHealthOverrides{}, nil, app, true, "", true)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusDegraded, healthStatus.Status)
+	})
+}
+
 func newAppLiveObj(status health.HealthStatusCode) *unstructured.Unstructured {
 	app := appv1.Application{
 		ObjectMeta: metav1.ObjectMeta{
@@ -166,7 +332,7 @@ return hs`,
 		}, {}}
 		resourceStatuses := initStatuses(resources)
 
-		healthStatus, err := setApplicationHealth(resources, resourceStatuses, overrides, app, true)
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, overrides, nil, app, true, "", false)
 		require.NoError(t, err)
 		assert.Equal(t, health.HealthStatusDegraded, healthStatus.Status)
 	})
@@ -178,7 +344,7 @@ return hs`,
 		}, {}}
 		resourceStatuses := initStatuses(resources)
 
-		healthStatus, err := setApplicationHealth(resources, resourceStatuses, overrides, app, true)
+		healthStatus, err := setApplicationHealth(resources, resourceStatuses, overrides, nil, app, true, "", false)
 		require.NoError(t, err)
 		assert.Equal(t, health.HealthStatusHealthy, healthStatus.Status)
 	})
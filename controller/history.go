@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/argoproj/argo-cd/v2/controller/metrics"
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned"
+	applisters "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
+)
+
+const (
+	// EnvVarHistoryPruneInterval is an environment variable which controls how often the history retention policies of
+	// the projects are applied to all their applications, 0 disables the background pruning
+	EnvVarHistoryPruneInterval = "ARGOCD_APPLICATION_CONTROLLER_HISTORY_PRUNE_INTERVAL"
+	// DefaultHistoryPruneInterval is the default interval between two background prunings of the application histories
+	DefaultHistoryPruneInterval = time.Hour
+)
+
+// pruneRevisionHistory returns the history without the entries exceeding the retention policy and the number of
+// removed entries. Entries are removed if they are beyond the maxEntries latest ones or if they completed more than
+// maxAge before now, so the more restrictive constraint applies. The latest entry is always kept.
+func pruneRevisionHistory(history appv1.RevisionHistories, policy *appv1.HistoryRetentionPolicy, now time.Time) (appv1.RevisionHistories, int) {
+	if policy.IsZero() || len(history) == 0 {
+		return history, 0
+	}
+	pruned := history
+	if policy.MaxEntries > 0 {
+		pruned = pruned.Trunc(int(policy.MaxEntries))
+	}
+	if policy.MaxAge.Duration > 0 {
+		expiredBefore := now.Add(-policy.MaxAge.Duration)
+		// the history is ordered oldest first
+		i := 0
+		for i < len(pruned)-1 && pruned[i].DeployedAt.Time.Before(expiredBefore) {
+			i++
+		}
+		pruned = pruned[i:]
+	}
+	return pruned, len(history) - len(pruned)
+}
+
+// HistoryPruneJob periodically applies the history retention policies of the projects to the history of all their
+// applications, so that entries expire even if the applications are not synced
+type HistoryPruneJob struct {
+	interval      time.Duration
+	appLister     applisters.ApplicationLister
+	projLister    applisters.AppProjectLister
+	appFilter     func(obj interface{}) bool
+	appclientset  appclientset.Interface
+	metricsServer *metrics.MetricsServer
+	// namespace is the namespace of the projects
+	namespace string
+}
+
+// NewHistoryPruneJob returns a job pruning the history of the applications accepted by appFilter at the given interval
+func NewHistoryPruneJob(interval time.Duration, appLister applisters.ApplicationLister, projLister applisters.AppProjectLister, appFilter func(obj interface{}) bool, appclientset appclientset.Interface, metricsServer *metrics.MetricsServer, namespace string) *HistoryPruneJob {
+	return &HistoryPruneJob{
+		interval:      interval,
+		appLister:     appLister,
+		projLister:    projLister,
+		appFilter:     appFilter,
+		appclientset:  appclientset,
+		metricsServer: metricsServer,
+		namespace:     namespace,
+	}
+}
+
+// Run prunes the application histories at the interval of the job until the context is done
+func (j *HistoryPruneJob) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		return
+	}
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := j.pruneAll(ctx, time.Now()); err != nil {
+			log.Errorf("Failed to prune the application histories: %v", err)
+		}
+	}, j.interval)
+}
+
+// pruneAll removes the entries exceeding the retention policy of their project from the history of each application.
+// Applications with a running operation are skipped, their history is pruned once the operation completes.
+func (j *HistoryPruneJob) pruneAll(ctx context.Context, now time.Time) error {
+	apps, err := j.appLister.List(labels.Everything())
+	if err != nil {
+		return fmt.Errorf("error listing applications: %w", err)
+	}
+	for _, app := range apps {
+		if (j.appFilter != nil && !j.appFilter(app)) || app.Operation != nil {
+			continue
+		}
+		proj, err := j.projLister.AppProjects(j.namespace).Get(app.Spec.GetProject())
+		if apierrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("error getting project %s: %w", app.Spec.GetProject(), err)
+		}
+		history, pruned := pruneRevisionHistory(app.Status.History, proj.Spec.HistoryRetentionPolicy, now)
+		if pruned == 0 {
+			continue
+		}
+		if err := patchRevisionHistory(ctx, j.appclientset, app, history); err != nil {
+			log.WithFields(log.Fields{"application": app.QualifiedName()}).Warnf("Failed to prune the history: %v", err)
+			continue
+		}
+		log.WithFields(log.Fields{"application": app.QualifiedName(), "pruned": pruned}).Info("Pruned the history")
+		if j.metricsServer != nil {
+			j.metricsServer.AddHistoryPruned(app, pruned)
+		}
+	}
+	return nil
+}
+
+// patchRevisionHistory replaces the history of the application
+func patchRevisionHistory(ctx context.Context, appclientset appclientset.Interface, app *appv1.Application, history appv1.RevisionHistories) error {
+	patch, err := json.Marshal(map[string]map[string][]appv1.RevisionHistory{
+		"status": {
+			"history": history,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling revision history patch: %w", err)
+	}
+	_, err = appclientset.ArgoprojV1alpha1().Applications(app.Namespace).Patch(ctx, app.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
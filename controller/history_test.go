@@ -0,0 +1,143 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned/fake"
+	applisters "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+// newTestHistory returns a history with an entry deployed each day of the given number of days before now, oldest
+// first
+func newTestHistory(now time.Time, days ...int) appv1.RevisionHistories {
+	var history appv1.RevisionHistories
+	for i, day := range days {
+		history = append(history, appv1.RevisionHistory{ID: int64(i), DeployedAt: metav1.NewTime(now.Add(-time.Duration(day) * 24 * time.Hour))})
+	}
+	return history
+}
+
+func historyIDs(history appv1.RevisionHistories) []int64 {
+	ids := []int64{}
+	for _, entry := range history {
+		ids = append(ids, entry.ID)
+	}
+	return ids
+}
+
+func TestPruneRevisionHistory(t *testing.T) {
+	now := time.Now()
+	history := newTestHistory(now, 10, 8, 6, 4, 2, 0)
+	days := func(n int) metav1.Duration {
+		return metav1.Duration{Duration: time.Duration(n) * 24 * time.Hour}
+	}
+
+	tests := []struct {
+		name     string
+		policy   *appv1.HistoryRetentionPolicy
+		expected []int64
+	}{
+		{name: "no policy", policy: nil, expected: []int64{0, 1, 2, 3, 4, 5}},
+		{name: "empty policy", policy: &appv1.HistoryRetentionPolicy{}, expected: []int64{0, 1, 2, 3, 4, 5}},
+		{name: "max entries", policy: &appv1.HistoryRetentionPolicy{MaxEntries: 4}, expected: []int64{2, 3, 4, 5}},
+		{name: "max age", policy: &appv1.HistoryRetentionPolicy{MaxAge: days(5)}, expected: []int64{3, 4, 5}},
+		{name: "max entries more restrictive than max age", policy: &appv1.HistoryRetentionPolicy{MaxEntries: 2, MaxAge: days(5)}, expected: []int64{4, 5}},
+		{name: "max age more restrictive than max entries", policy: &appv1.HistoryRetentionPolicy{MaxEntries: 5, MaxAge: days(3)}, expected: []int64{4, 5}},
+		{name: "latest entry is kept", policy: &appv1.HistoryRetentionPolicy{MaxAge: metav1.Duration{Duration: time.Minute}}, expected: []int64{5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pruned, count := pruneRevisionHistory(history, tt.policy, now)
+			assert.Equal(t, tt.expected, historyIDs(pruned))
+			assert.Equal(t, len(history)-len(tt.expected), count)
+		})
+	}
+
+	t.Run("expired latest entry", func(t *testing.T) {
+		pruned, count := pruneRevisionHistory(newTestHistory(now, 10, 9), &appv1.HistoryRetentionPolicy{MaxAge: days(1)}, now)
+		assert.Equal(t, []int64{1}, historyIDs(pruned))
+		assert.Equal(t, 1, count)
+	})
+}
+
+func Test_appStateManager_persistRevisionHistory_RetentionPolicy(t *testing.T) {
+	app := newFakeApp()
+	app.Status.History = newTestHistory(time.Now(), 10, 8, 6, 4, 2)
+	ctrl := newFakeController(&fakeData{apps: []runtime.Object{app, &defaultProj}}, nil)
+	manager := ctrl.appStateManager.(*appStateManager)
+
+	policy := &appv1.HistoryRetentionPolicy{MaxEntries: 4, MaxAge: metav1.Duration{Duration: 5 * 24 * time.Hour}}
+	err := manager.persistRevisionHistory(app, "my-revision", appv1.ApplicationSource{}, []string{}, []appv1.ApplicationSource{}, false, metav1.Now(), appv1.OperationInitiator{}, policy)
+	require.NoError(t, err)
+	// the entries of 10, 8 and 6 days ago are older than maxAge, which is more restrictive than maxEntries
+	assert.Equal(t, []int64{3, 4, 5}, historyIDs(app.Status.History))
+
+	updated, err := ctrl.applicationClientset.ArgoprojV1alpha1().Applications(app.Namespace).Get(context.Background(), app.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{3, 4, 5}, historyIDs(updated.Status.History))
+}
+
+func TestHistoryPruneJob_PruneAll(t *testing.T) {
+	now := time.Now()
+	newApp := func(name, project string) *appv1.Application {
+		return &appv1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: test.FakeArgoCDNamespace},
+			Spec:       appv1.ApplicationSpec{Project: project},
+			Status:     appv1.ApplicationStatus{History: newTestHistory(now, 10, 8, 6, 4, 2)},
+		}
+	}
+	apps := []*appv1.Application{
+		newApp("pruned", "retained"),
+		newApp("syncing", "retained"),
+		newApp("unlimited", "default"),
+		newApp("filtered", "retained"),
+	}
+	// the history of applications with a running operation is pruned once the operation completes
+	apps[1].Operation = &appv1.Operation{Sync: &appv1.SyncOperation{}}
+	projects := []*appv1.AppProject{
+		{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: test.FakeArgoCDNamespace}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "retained", Namespace: test.FakeArgoCDNamespace},
+			Spec:       appv1.AppProjectSpec{HistoryRetentionPolicy: &appv1.HistoryRetentionPolicy{MaxEntries: 2, MaxAge: metav1.Duration{Duration: 7 * 24 * time.Hour}}},
+		},
+	}
+
+	appIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	projIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	clientset := appclientset.NewSimpleClientset()
+	for _, app := range apps {
+		require.NoError(t, appIndexer.Add(app))
+		_, err := clientset.ArgoprojV1alpha1().Applications(app.Namespace).Create(context.Background(), app, metav1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	for _, proj := range projects {
+		require.NoError(t, projIndexer.Add(proj))
+	}
+	appFilter := func(obj interface{}) bool {
+		return obj.(*appv1.Application).Name != "filtered"
+	}
+	job := NewHistoryPruneJob(DefaultHistoryPruneInterval, applisters.NewApplicationLister(appIndexer), applisters.NewAppProjectLister(projIndexer), appFilter, clientset, nil, test.FakeArgoCDNamespace)
+	require.NoError(t, job.pruneAll(context.Background(), now))
+
+	expected := map[string][]int64{
+		"pruned":    {3, 4},
+		"syncing":   {0, 1, 2, 3, 4},
+		"unlimited": {0, 1, 2, 3, 4},
+		"filtered":  {0, 1, 2, 3, 4},
+	}
+	for name, ids := range expected {
+		app, err := clientset.ArgoprojV1alpha1().Applications(test.FakeArgoCDNamespace).Get(context.Background(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, ids, historyIDs(app.Status.History), name)
+	}
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/argoproj/gitops-engine/pkg/health"
 	"github.com/argoproj/gitops-engine/pkg/sync/common"
@@ -12,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/rest"
 
+	healthutil "github.com/argoproj/argo-cd/v2/util/health"
 	"github.com/argoproj/argo-cd/v2/util/lua"
 
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
@@ -23,17 +25,30 @@ var (
 		"argocd.argoproj.io/hook": postDeleteHook,
 		"helm.sh/hook":            "post-delete",
 	}
+	preDeleteHook  = "PreDelete"
+	preDeleteHooks = map[string]string{
+		"argocd.argoproj.io/hook": preDeleteHook,
+		"helm.sh/hook":            "pre-delete",
+	}
 )
 
 func isHook(obj *unstructured.Unstructured) bool {
-	return hook.IsHook(obj) || isPostDeleteHook(obj)
+	return hook.IsHook(obj) || isPostDeleteHook(obj) || isPreDeleteHook(obj)
 }
 
 func isPostDeleteHook(obj *unstructured.Unstructured) bool {
+	return hasHookAnnotation(obj, postDeleteHooks)
+}
+
+func isPreDeleteHook(obj *unstructured.Unstructured) bool {
+	return hasHookAnnotation(obj, preDeleteHooks)
+}
+
+func hasHookAnnotation(obj *unstructured.Unstructured, hooks map[string]string) bool {
 	if obj == nil || obj.GetAnnotations() == nil {
 		return false
 	}
-	for k, v := range postDeleteHooks {
+	for k, v := range hooks {
 		if val, ok := obj.GetAnnotations()[k]; ok && val == v {
 			return true
 		}
@@ -42,9 +57,31 @@ func isPostDeleteHook(obj *unstructured.Unstructured) bool {
 }
 
 func (ctrl *ApplicationController) executePostDeleteHooks(app *v1alpha1.Application, proj *v1alpha1.AppProject, liveObjs map[kube.ResourceKey]*unstructured.Unstructured, config *rest.Config, logCtx *log.Entry) (bool, error) {
+	done, _, err := ctrl.executeDeletionHooks(app, proj, liveObjs, config, logCtx, isPostDeleteHook, "post-delete")
+	return done, err
+}
+
+// executePreDeleteHooks creates the pre-delete hooks of the application and waits for them to complete, before any
+// resource of the application is deleted. A failed hook prevents the deletion of the application until it is deleted,
+// which creates it again.
+func (ctrl *ApplicationController) executePreDeleteHooks(app *v1alpha1.Application, proj *v1alpha1.AppProject, liveObjs map[kube.ResourceKey]*unstructured.Unstructured, config *rest.Config, logCtx *log.Entry) (bool, error) {
+	done, failedHooks, err := ctrl.executeDeletionHooks(app, proj, liveObjs, config, logCtx, isPreDeleteHook, "pre-delete")
+	if err != nil || !done {
+		return false, err
+	}
+	if len(failedHooks) > 0 {
+		obj := failedHooks[0]
+		return false, fmt.Errorf("pre-delete hook %s %s/%s failed", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+	}
+	return true, nil
+}
+
+// executeDeletionHooks creates the deletion hooks of the application which do not exist yet, and returns whether all of
+// them completed, along with the hooks which failed
+func (ctrl *ApplicationController) executeDeletionHooks(app *v1alpha1.Application, proj *v1alpha1.AppProject, liveObjs map[kube.ResourceKey]*unstructured.Unstructured, config *rest.Config, logCtx *log.Entry, isDeletionHook func(obj *unstructured.Unstructured) bool, hookName string) (bool, []*unstructured.Unstructured, error) {
 	appLabelKey, err := ctrl.settingsMgr.GetAppInstanceLabelKey()
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
 	var revisions []string
 	for _, src := range app.Spec.GetSources() {
@@ -53,11 +90,11 @@ func (ctrl *ApplicationController) executePostDeleteHooks(app *v1alpha1.Applicat
 
 	targets, _, err := ctrl.appStateManager.GetRepoObjs(app, app.Spec.GetSources(), appLabelKey, revisions, false, false, false, proj, false)
 	if err != nil {
-		return false, err
+		return false, nil, err
 	}
 	runningHooks := map[kube.ResourceKey]*unstructured.Unstructured{}
 	for key, obj := range liveObjs {
-		if isPostDeleteHook(obj) {
+		if isDeletionHook(obj) {
 			runningHooks[key] = obj
 		}
 	}
@@ -67,7 +104,7 @@ func (ctrl *ApplicationController) executePostDeleteHooks(app *v1alpha1.Applicat
 		if obj.GetNamespace() == "" {
 			obj.SetNamespace(app.Spec.Destination.Namespace)
 		}
-		if !isPostDeleteHook(obj) {
+		if !isDeletionHook(obj) {
 			continue
 		}
 		if runningHook := runningHooks[kube.GetResourceKey(obj)]; runningHook == nil {
@@ -78,25 +115,30 @@ func (ctrl *ApplicationController) executePostDeleteHooks(app *v1alpha1.Applicat
 	for _, obj := range expectedHook {
 		_, err = ctrl.kubectl.CreateResource(context.Background(), config, obj.GroupVersionKind(), obj.GetName(), obj.GetNamespace(), obj, v1.CreateOptions{})
 		if err != nil {
-			return false, err
+			return false, nil, err
 		}
 		createdCnt++
 	}
 	if createdCnt > 0 {
-		logCtx.Infof("Created %d post-delete hooks", createdCnt)
-		return false, nil
+		logCtx.Infof("Created %d %s hooks", createdCnt, hookName)
+		return false, nil, nil
 	}
 	resourceOverrides, err := ctrl.settingsMgr.GetResourceOverrides()
 	if err != nil {
-		return false, err
+		return false, nil, err
+	}
+	healthOverrideScripts, err := ctrl.settingsMgr.GetHealthOverrideScripts()
+	if err != nil {
+		return false, nil, err
 	}
-	healthOverrides := lua.ResourceHealthOverrides(resourceOverrides)
+	healthOverrides := healthutil.NewExtendedHealthOverride(lua.NewResourceHealthOverride(healthOverrideScripts, resourceOverrides))
 
 	progressingHooksCnt := 0
+	var failedHooks []*unstructured.Unstructured
 	for _, obj := range runningHooks {
 		hookHealth, err := health.GetResourceHealth(obj, healthOverrides)
 		if err != nil {
-			return false, err
+			return false, nil, err
 		}
 		if hookHealth == nil {
 			logCtx.WithFields(log.Fields{
@@ -110,16 +152,19 @@ func (ctrl *ApplicationController) executePostDeleteHooks(app *v1alpha1.Applicat
 				Status: health.HealthStatusHealthy,
 			}
 		}
-		if hookHealth.Status == health.HealthStatusProgressing {
+		switch hookHealth.Status {
+		case health.HealthStatusProgressing:
 			progressingHooksCnt++
+		case health.HealthStatusDegraded:
+			failedHooks = append(failedHooks, obj)
 		}
 	}
 	if progressingHooksCnt > 0 {
-		logCtx.Infof("Waiting for %d post-delete hooks to complete", progressingHooksCnt)
-		return false, nil
+		logCtx.Infof("Waiting for %d %s hooks to complete", progressingHooksCnt, hookName)
+		return false, nil, nil
 	}
 
-	return true, nil
+	return true, failedHooks, nil
 }
 
 func (ctrl *ApplicationController) cleanupPostDeleteHooks(liveObjs map[kube.ResourceKey]*unstructured.Unstructured, config *rest.Config, logCtx *log.Entry) (bool, error) {
@@ -127,7 +172,11 @@ func (ctrl *ApplicationController) cleanupPostDeleteHooks(liveObjs map[kube.Reso
 	if err != nil {
 		return false, err
 	}
-	healthOverrides := lua.ResourceHealthOverrides(resourceOverrides)
+	healthOverrideScripts, err := ctrl.settingsMgr.GetHealthOverrideScripts()
+	if err != nil {
+		return false, err
+	}
+	healthOverrides := healthutil.NewExtendedHealthOverride(lua.NewResourceHealthOverride(healthOverrideScripts, resourceOverrides))
 
 	pendingDeletionCount := 0
 	aggregatedHealth := health.HealthStatusHealthy
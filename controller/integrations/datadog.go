@@ -0,0 +1,273 @@
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+const (
+	// DatadogEventTypeDeployment is the type of the events sent for successful syncs
+	DatadogEventTypeDeployment = "Deployment"
+	// DatadogEventTypeDeploymentFailed is the type of the events sent for failed syncs
+	DatadogEventTypeDeploymentFailed = "DeploymentFailed"
+
+	// DefaultDatadogSite is the Datadog site events are sent to if the integration does not configure one
+	DefaultDatadogSite = "datadoghq.com"
+
+	// datadogEventsPath is the path of the endpoint of the Datadog Events API v2
+	datadogEventsPath = "/api/v2/events"
+	// datadogTimeout is the timeout of a single request to the Datadog API
+	datadogTimeout = 10 * time.Second
+	// DatadogWorkers is the number of events sent to Datadog concurrently
+	DatadogWorkers = 2
+	// datadogQueueSize is the maximum number of events waiting to be sent to Datadog
+	datadogQueueSize = 100
+)
+
+// DatadogMetrics records the events sent to Datadog
+type DatadogMetrics interface {
+	IncDatadogEventSent(app *appv1.Application, eventType string)
+	IncDatadogEventFailed(app *appv1.Application, eventType string)
+}
+
+// datadogRequest is a completed sync operation waiting to be sent to Datadog
+type datadogRequest struct {
+	integration appv1.DatadogIntegration
+	app         *appv1.Application
+	state       *appv1.OperationState
+}
+
+// DatadogEvent is the payload of a request to the Datadog Events API v2
+type DatadogEvent struct {
+	Data DatadogEventData `json:"data"`
+}
+
+// DatadogEventData is the event of a request to the Datadog Events API v2
+type DatadogEventData struct {
+	Type       string                 `json:"type"`
+	Attributes DatadogEventAttributes `json:"attributes"`
+}
+
+// DatadogEventAttributes are the attributes of an event of the Datadog Events API v2. Successful syncs are sent as
+// change events, failed syncs as alert events.
+type DatadogEventAttributes struct {
+	Title          string                 `json:"title"`
+	Message        string                 `json:"message,omitempty"`
+	Category       string                 `json:"category"`
+	AggregationKey string                 `json:"aggregation_key,omitempty"`
+	Timestamp      string                 `json:"timestamp,omitempty"`
+	Tags           []string               `json:"tags,omitempty"`
+	Attributes     map[string]interface{} `json:"attributes"`
+}
+
+// DatadogNotifier sends the results of completed syncs to Datadog as events, using the Datadog integration configured
+// in the project of the application. At most one event is sent per sync of an application.
+type DatadogNotifier struct {
+	client        *http.Client
+	kubeClientset kubernetes.Interface
+	// namespace is the namespace of the secrets holding the Datadog API keys
+	namespace string
+	metrics   DatadogMetrics
+	queue     chan datadogRequest
+	// apiURL overrides the URL of the Datadog API derived from the site of the integration
+	apiURL string
+
+	lock sync.Mutex
+	// lastSyncs are the start times of the last syncs an event was sent for, by qualified application name
+	lastSyncs map[string]time.Time
+}
+
+// NewDatadogNotifier returns a DatadogNotifier which reads the Datadog API keys from secrets in the given namespace
+func NewDatadogNotifier(kubeClientset kubernetes.Interface, namespace string, metrics DatadogMetrics) *DatadogNotifier {
+	return &DatadogNotifier{
+		client:        &http.Client{Timeout: datadogTimeout},
+		kubeClientset: kubeClientset,
+		namespace:     namespace,
+		metrics:       metrics,
+		queue:         make(chan datadogRequest, datadogQueueSize),
+		lastSyncs:     map[string]time.Time{},
+	}
+}
+
+// Queue queues the completed sync operation of the application to be sent to Datadog by RunWorker. The sync is
+// ignored if an event was already sent for it, and dropped if too many events are waiting to be sent.
+func (n *DatadogNotifier) Queue(integration *appv1.DatadogIntegration, app *appv1.Application, state *appv1.OperationState) {
+	if !state.Phase.Completed() || !n.markSent(app.QualifiedName(), state.StartedAt.Time) {
+		return
+	}
+	select {
+	case n.queue <- datadogRequest{integration: *integration, app: app.DeepCopy(), state: state.DeepCopy()}:
+	default:
+		log.WithField("application", app.QualifiedName()).Warn("Dropping Datadog event: too many events waiting to be sent")
+	}
+}
+
+// markSent records that an event is sent for the sync of the application started at the given time, and returns
+// false if one was already sent for it
+func (n *DatadogNotifier) markSent(appName string, startedAt time.Time) bool {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+	if last, ok := n.lastSyncs[appName]; ok && last.Equal(startedAt) {
+		return false
+	}
+	n.lastSyncs[appName] = startedAt
+	return true
+}
+
+// RunWorker sends the queued events until the context is done
+func (n *DatadogNotifier) RunWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case req := <-n.queue:
+			eventType := datadogEventType(req.state)
+			if err := n.Notify(ctx, &req.integration, req.app, req.state); err != nil {
+				log.WithField("application", req.app.QualifiedName()).Warnf("Failed to send Datadog event: %v", err)
+				n.metrics.IncDatadogEventFailed(req.app, eventType)
+				continue
+			}
+			n.metrics.IncDatadogEventSent(req.app, eventType)
+		}
+	}
+}
+
+// Notify sends the result of the completed sync operation of the application to Datadog
+func (n *DatadogNotifier) Notify(ctx context.Context, integration *appv1.DatadogIntegration, app *appv1.Application, state *appv1.OperationState) error {
+	apiKey, err := n.getAPIKey(ctx, integration.APIKeySecret)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(NewDatadogEvent(integration, app, state))
+	if err != nil {
+		return fmt.Errorf("error marshaling Datadog event: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, datadogTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.eventsURL(integration.Site), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating Datadog request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("DD-API-KEY", apiKey)
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error sending Datadog event: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("Datadog responded with status %s", resp.Status)
+	}
+	return nil
+}
+
+// getAPIKey returns the Datadog API key held by the referenced key of a secret
+func (n *DatadogNotifier) getAPIKey(ctx context.Context, ref appv1.SecretRef) (string, error) {
+	secret, err := n.kubeClientset.CoreV1().Secrets(n.namespace).Get(ctx, ref.SecretName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("error getting Datadog API key secret %s: %w", ref.SecretName, err)
+	}
+	apiKey := strings.TrimSpace(string(secret.Data[ref.Key]))
+	if apiKey == "" {
+		return "", fmt.Errorf("key %s not found in Datadog API key secret %s", ref.Key, ref.SecretName)
+	}
+	return apiKey, nil
+}
+
+// eventsURL returns the URL of the Events API of the given Datadog site
+func (n *DatadogNotifier) eventsURL(site string) string {
+	if n.apiURL != "" {
+		return n.apiURL + datadogEventsPath
+	}
+	if site == "" {
+		site = DefaultDatadogSite
+	}
+	return "https://api." + site + datadogEventsPath
+}
+
+// datadogEventType returns the type of the event sent for the completed sync operation
+func datadogEventType(state *appv1.OperationState) string {
+	if state.Phase.Successful() {
+		return DatadogEventTypeDeployment
+	}
+	return DatadogEventTypeDeploymentFailed
+}
+
+// NewDatadogEvent returns the event sent to Datadog for the completed sync operation of the application. The
+// environment of the event is the destination namespace of the application.
+func NewDatadogEvent(integration *appv1.DatadogIntegration, app *appv1.Application, state *appv1.OperationState) *DatadogEvent {
+	revision := ""
+	if state.SyncResult != nil {
+		revision = state.SyncResult.Revision
+	} else if state.Operation.Sync != nil {
+		revision = state.Operation.Sync.Revision
+	}
+	service := integration.ServiceTag
+	if service == "" {
+		service = app.Name
+	}
+	environment := app.Spec.Destination.Namespace
+	timestamp := time.Now()
+	if state.FinishedAt != nil {
+		timestamp = state.FinishedAt.Time
+	}
+	eventType := datadogEventType(state)
+
+	tags := []string{
+		"source:argocd",
+		"service:" + service,
+		"argocd_application:" + app.Name,
+		"argocd_project:" + app.Spec.GetProject(),
+		"event_type:" + eventType,
+	}
+	if environment != "" {
+		tags = append(tags, "env:"+environment)
+	}
+	if revision != "" {
+		tags = append(tags, "revision:"+revision)
+	}
+
+	attributes := DatadogEventAttributes{
+		Message:        state.Message,
+		AggregationKey: app.QualifiedName(),
+		Timestamp:      timestamp.UTC().Format(time.RFC3339),
+		Tags:           tags,
+	}
+	details := map[string]interface{}{
+		"appName":     app.Name,
+		"revision":    revision,
+		"environment": environment,
+		"tags":        tags,
+	}
+	if eventType == DatadogEventTypeDeployment {
+		attributes.Title = fmt.Sprintf("%s: %s synced to %s", eventType, app.Name, revision)
+		attributes.Category = "change"
+		attributes.Attributes = map[string]interface{}{
+			"changed_resource": map[string]string{"name": app.Name, "type": "configuration"},
+			"new_value":        details,
+		}
+	} else {
+		attributes.Title = fmt.Sprintf("%s: %s failed to sync to %s", eventType, app.Name, revision)
+		attributes.Category = "alert"
+		attributes.Attributes = map[string]interface{}{
+			"status":   "error",
+			"priority": "3",
+			"custom":   details,
+		}
+	}
+	return &DatadogEvent{Data: DatadogEventData{Type: "event", Attributes: attributes}}
+}
@@ -0,0 +1,237 @@
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+type fakeDatadogMetrics struct {
+	lock   sync.Mutex
+	sent   map[string]int
+	failed map[string]int
+}
+
+func newFakeDatadogMetrics() *fakeDatadogMetrics {
+	return &fakeDatadogMetrics{sent: map[string]int{}, failed: map[string]int{}}
+}
+
+func (m *fakeDatadogMetrics) IncDatadogEventSent(_ *appv1.Application, eventType string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.sent[eventType]++
+}
+
+func (m *fakeDatadogMetrics) IncDatadogEventFailed(_ *appv1.Application, eventType string) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	m.failed[eventType]++
+}
+
+func (m *fakeDatadogMetrics) counts() (map[string]int, map[string]int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	sent, failed := map[string]int{}, map[string]int{}
+	for k, v := range m.sent {
+		sent[k] = v
+	}
+	for k, v := range m.failed {
+		failed[k] = v
+	}
+	return sent, failed
+}
+
+var testIntegration = &appv1.DatadogIntegration{
+	APIKeySecret: appv1.SecretRef{SecretName: "datadog", Key: "apiKey"},
+	Site:         "datadoghq.eu",
+	ServiceTag:   "guestbook-svc",
+}
+
+func newDatadogTestState(phase synccommon.OperationPhase) (*appv1.Application, *appv1.OperationState) {
+	app := &appv1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd"},
+		Spec: appv1.ApplicationSpec{
+			Project:     "default",
+			Destination: appv1.ApplicationDestination{Namespace: "production"},
+		},
+	}
+	finishedAt := metav1.NewTime(time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC))
+	state := &appv1.OperationState{
+		Operation:  appv1.Operation{Sync: &appv1.SyncOperation{Revision: "HEAD"}},
+		Phase:      phase,
+		Message:    "one or more objects failed to apply",
+		SyncResult: &appv1.SyncOperationResult{Revision: "abc123"},
+		StartedAt:  metav1.NewTime(time.Date(2024, 5, 1, 10, 29, 0, 0, time.UTC)),
+		FinishedAt: &finishedAt,
+	}
+	return app, state
+}
+
+func newTestDatadogNotifier(apiURL string) (*DatadogNotifier, *fakeDatadogMetrics) {
+	kubeClientset := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "datadog", Namespace: "argocd"},
+		Data:       map[string][]byte{"apiKey": []byte("my-api-key\n")},
+	})
+	metrics := newFakeDatadogMetrics()
+	notifier := NewDatadogNotifier(kubeClientset, "argocd", metrics)
+	notifier.apiURL = apiURL
+	return notifier, metrics
+}
+
+type receivedDatadogEvent struct {
+	path   string
+	apiKey string
+	event  DatadogEvent
+}
+
+func newMockDatadogServer(t *testing.T, status int) (*httptest.Server, chan receivedDatadogEvent) {
+	t.Helper()
+	received := make(chan receivedDatadogEvent, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event DatadogEvent
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		received <- receivedDatadogEvent{path: r.URL.Path, apiKey: r.Header.Get("DD-API-KEY"), event: event}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func TestDatadogNotifier_Notify(t *testing.T) {
+	t.Run("successful sync", func(t *testing.T) {
+		server, received := newMockDatadogServer(t, http.StatusAccepted)
+		notifier, _ := newTestDatadogNotifier(server.URL)
+		app, state := newDatadogTestState(synccommon.OperationSucceeded)
+
+		require.NoError(t, notifier.Notify(context.Background(), testIntegration, app, state))
+
+		req := <-received
+		assert.Equal(t, "/api/v2/events", req.path)
+		assert.Equal(t, "my-api-key", req.apiKey)
+		assert.Equal(t, "event", req.event.Data.Type)
+		attrs := req.event.Data.Attributes
+		assert.Equal(t, "change", attrs.Category)
+		assert.Equal(t, "Deployment: guestbook synced to abc123", attrs.Title)
+		assert.Equal(t, "argocd/guestbook", attrs.AggregationKey)
+		assert.Equal(t, "2024-05-01T10:30:00Z", attrs.Timestamp)
+		assert.Equal(t, []string{
+			"source:argocd", "service:guestbook-svc", "argocd_application:guestbook", "argocd_project:default",
+			"event_type:Deployment", "env:production", "revision:abc123",
+		}, attrs.Tags)
+		assert.Equal(t, map[string]interface{}{"name": "guestbook", "type": "configuration"}, attrs.Attributes["changed_resource"])
+		newValue, ok := attrs.Attributes["new_value"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "guestbook", newValue["appName"])
+		assert.Equal(t, "abc123", newValue["revision"])
+		assert.Equal(t, "production", newValue["environment"])
+		assert.Len(t, newValue["tags"], 7)
+	})
+
+	t.Run("failed sync", func(t *testing.T) {
+		server, received := newMockDatadogServer(t, http.StatusAccepted)
+		notifier, _ := newTestDatadogNotifier(server.URL)
+		app, state := newDatadogTestState(synccommon.OperationFailed)
+
+		require.NoError(t, notifier.Notify(context.Background(), testIntegration, app, state))
+
+		attrs := (<-received).event.Data.Attributes
+		assert.Equal(t, "alert", attrs.Category)
+		assert.Equal(t, "DeploymentFailed: guestbook failed to sync to abc123", attrs.Title)
+		assert.Equal(t, "one or more objects failed to apply", attrs.Message)
+		assert.Contains(t, attrs.Tags, "event_type:DeploymentFailed")
+		assert.Equal(t, "error", attrs.Attributes["status"])
+		custom, ok := attrs.Attributes["custom"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, "guestbook", custom["appName"])
+	})
+
+	t.Run("error status", func(t *testing.T) {
+		server, _ := newMockDatadogServer(t, http.StatusForbidden)
+		notifier, _ := newTestDatadogNotifier(server.URL)
+		app, state := newDatadogTestState(synccommon.OperationSucceeded)
+
+		err := notifier.Notify(context.Background(), testIntegration, app, state)
+		assert.ErrorContains(t, err, "403")
+	})
+
+	t.Run("missing API key", func(t *testing.T) {
+		notifier, _ := newTestDatadogNotifier("http://127.0.0.1:0")
+		app, state := newDatadogTestState(synccommon.OperationSucceeded)
+
+		integration := testIntegration.DeepCopy()
+		integration.APIKeySecret.Key = "other"
+		err := notifier.Notify(context.Background(), integration, app, state)
+		assert.ErrorContains(t, err, "key other not found in Datadog API key secret datadog")
+	})
+}
+
+func TestDatadogNotifier_Queue(t *testing.T) {
+	server, received := newMockDatadogServer(t, http.StatusAccepted)
+	notifier, metrics := newTestDatadogNotifier(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go notifier.RunWorker(ctx)
+
+	app, state := newDatadogTestState(synccommon.OperationSucceeded)
+	// the completion of the same sync is only sent once
+	notifier.Queue(testIntegration, app, state)
+	notifier.Queue(testIntegration, app, state)
+	// running syncs are not sent
+	_, running := newDatadogTestState(synccommon.OperationRunning)
+	running.StartedAt = metav1.NewTime(state.StartedAt.Add(time.Minute))
+	notifier.Queue(testIntegration, app, running)
+	// the next sync is sent
+	_, next := newDatadogTestState(synccommon.OperationFailed)
+	next.StartedAt = metav1.NewTime(state.StartedAt.Add(2 * time.Minute))
+	notifier.Queue(testIntegration, app, next)
+
+	assert.Equal(t, "change", (<-received).event.Data.Attributes.Category)
+	assert.Equal(t, "alert", (<-received).event.Data.Attributes.Category)
+	assert.Eventually(t, func() bool {
+		sent, _ := metrics.counts()
+		return sent[DatadogEventTypeDeployment] == 1 && sent[DatadogEventTypeDeploymentFailed] == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	select {
+	case req := <-received:
+		t.Fatalf("unexpected event %s", req.event.Data.Attributes.Title)
+	case <-time.After(100 * time.Millisecond):
+	}
+	_, failed := metrics.counts()
+	assert.Empty(t, failed)
+}
+
+func TestDatadogNotifier_QueueFailure(t *testing.T) {
+	server, _ := newMockDatadogServer(t, http.StatusInternalServerError)
+	notifier, metrics := newTestDatadogNotifier(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go notifier.RunWorker(ctx)
+
+	app, state := newDatadogTestState(synccommon.OperationSucceeded)
+	notifier.Queue(testIntegration, app, state)
+
+	assert.Eventually(t, func() bool {
+		_, failed := metrics.counts()
+		return failed[DatadogEventTypeDeployment] == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestDatadogNotifier_EventsURL(t *testing.T) {
+	notifier := NewDatadogNotifier(fake.NewSimpleClientset(), "argocd", newFakeDatadogMetrics())
+	assert.Equal(t, "https://api.datadoghq.com/api/v2/events", notifier.eventsURL(""))
+	assert.Equal(t, "https://api.datadoghq.eu/api/v2/events", notifier.eventsURL("datadoghq.eu"))
+}
@@ -0,0 +1,174 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+const (
+	// GitOpsInventoryFlux reads the inventory of an application from the status of a Flux Kustomization
+	GitOpsInventoryFlux = "flux"
+	// defaultFluxNamespace is the namespace of the Flux Kustomizations unless the inventory reference sets one
+	defaultFluxNamespace = "flux-system"
+)
+
+var fluxKustomizationGVR = schema.GroupVersionResource{Group: "kustomize.toolkit.fluxcd.io", Version: "v1", Resource: "kustomizations"}
+
+// InventoryResource is a resource listed in the inventory of an application
+type InventoryResource struct {
+	kube.ResourceKey
+	// Version is the API version of the resource applied by the GitOps tool
+	Version string
+}
+
+// InventoryReader reads the resources managed by an application from the inventory kept by another GitOps tool
+type InventoryReader interface {
+	// ReadInventory returns the resources in the inventory of the given application
+	ReadInventory(ctx context.Context, app *v1alpha1.Application) ([]InventoryResource, error)
+}
+
+// fluxInventoryReader reads the inventory of an application from the status of a Flux Kustomization, which lists the
+// resources applied by its last successful reconciliation
+type fluxInventoryReader struct {
+	client dynamic.Interface
+}
+
+// NewFluxInventoryReader returns an InventoryReader reading the Flux Kustomizations with the given client of the
+// destination cluster
+func NewFluxInventoryReader(client dynamic.Interface) InventoryReader {
+	return &fluxInventoryReader{client: client}
+}
+
+func (r *fluxInventoryReader) ReadInventory(ctx context.Context, app *v1alpha1.Application) ([]InventoryResource, error) {
+	namespace, name, err := inventoryRef(app, defaultFluxNamespace)
+	if err != nil {
+		return nil, err
+	}
+	ks, err := r.client.Resource(fluxKustomizationGVR).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Flux Kustomization %s/%s: %w", namespace, name, err)
+	}
+	entries, found, err := unstructured.NestedSlice(ks.Object, "status", "inventory", "entries")
+	if err != nil {
+		return nil, fmt.Errorf("invalid inventory of Flux Kustomization %s/%s: %w", namespace, name, err)
+	}
+	if !found {
+		// the inventory is only set once the Kustomization was applied, an empty inventory has no entries
+		if _, hasInventory, _ := unstructured.NestedMap(ks.Object, "status", "inventory"); !hasInventory {
+			return nil, fmt.Errorf("Flux Kustomization %s/%s has no inventory yet", namespace, name)
+		}
+	}
+	resources := make([]InventoryResource, 0, len(entries))
+	for _, e := range entries {
+		entry, ok := e.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid inventory entry of Flux Kustomization %s/%s", namespace, name)
+		}
+		id, _ := entry["id"].(string)
+		key, err := parseFluxInventoryID(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid inventory entry of Flux Kustomization %s/%s: %w", namespace, name, err)
+		}
+		version, _ := entry["v"].(string)
+		if version == "" {
+			return nil, fmt.Errorf("inventory entry %s of Flux Kustomization %s/%s has no version", id, namespace, name)
+		}
+		resources = append(resources, InventoryResource{ResourceKey: key, Version: version})
+	}
+	return resources, nil
+}
+
+// parseFluxInventoryID parses the ID of a Flux inventory entry, formatted as <namespace>_<name>_<group>_<kind>. The
+// colons of the names of RBAC resources are encoded as double underscores.
+func parseFluxInventoryID(id string) (kube.ResourceKey, error) {
+	namespace, rest, ok := strings.Cut(id, "_")
+	if !ok {
+		return kube.ResourceKey{}, fmt.Errorf("ID %q is not formatted as <namespace>_<name>_<group>_<kind>", id)
+	}
+	i := strings.LastIndex(rest, "_")
+	if i < 0 {
+		return kube.ResourceKey{}, fmt.Errorf("ID %q is not formatted as <namespace>_<name>_<group>_<kind>", id)
+	}
+	kind := rest[i+1:]
+	rest = rest[:i]
+	i = strings.LastIndex(rest, "_")
+	if i < 0 {
+		return kube.ResourceKey{}, fmt.Errorf("ID %q is not formatted as <namespace>_<name>_<group>_<kind>", id)
+	}
+	group := rest[i+1:]
+	name := strings.ReplaceAll(rest[:i], "__", ":")
+	if name == "" || kind == "" {
+		return kube.ResourceKey{}, fmt.Errorf("ID %q is not formatted as <namespace>_<name>_<group>_<kind>", id)
+	}
+	return kube.NewResourceKey(group, kind, namespace, name), nil
+}
+
+// inventoryRef returns the namespace and name of the object holding the inventory of the given application
+func inventoryRef(app *v1alpha1.Application, defaultNamespace string) (string, string, error) {
+	ref, ok := app.Annotations[common.AnnotationKeyGitOpsInventoryRef]
+	if !ok {
+		return defaultNamespace, app.Name, nil
+	}
+	namespace, name, found := strings.Cut(ref, "/")
+	if !found {
+		namespace, name = defaultNamespace, ref
+	}
+	if namespace == "" || name == "" {
+		return "", "", fmt.Errorf("invalid %s annotation %q: must be formatted as <namespace>/<name>", common.AnnotationKeyGitOpsInventoryRef, ref)
+	}
+	return namespace, name, nil
+}
+
+// inventoryObjects returns the objects identifying the given inventory resources, used to get their live state
+func inventoryObjects(resources []InventoryResource) []*unstructured.Unstructured {
+	objs := make([]*unstructured.Unstructured, 0, len(resources))
+	for _, res := range resources {
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: res.Group, Version: res.Version, Kind: res.Kind})
+		obj.SetNamespace(res.Namespace)
+		obj.SetName(res.Name)
+		objs = append(objs, obj)
+	}
+	return objs
+}
+
+// getInventoryReader returns the reader of the inventory named by the gitops-inventory annotation of the given
+// application, or nil if the application does not have the annotation
+func (m *appStateManager) getInventoryReader(app *v1alpha1.Application) (InventoryReader, error) {
+	inventory, ok := app.Annotations[common.AnnotationKeyGitOpsInventory]
+	if !ok {
+		return nil, nil
+	}
+	switch inventory {
+	case GitOpsInventoryFlux:
+		client, err := m.getDestinationDynamicClient(app)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create client of cluster %q: %w", app.Spec.Destination.Server, err)
+		}
+		return NewFluxInventoryReader(client), nil
+	default:
+		return nil, fmt.Errorf("unsupported %s annotation %q", common.AnnotationKeyGitOpsInventory, inventory)
+	}
+}
+
+// getDestinationDynamicClient returns a dynamic client of the destination cluster of the given application
+func (m *appStateManager) getDestinationDynamicClient(app *v1alpha1.Application) (dynamic.Interface, error) {
+	if m.dynamicClientForDestination != nil {
+		return m.dynamicClientForDestination(app)
+	}
+	clst, err := m.db.GetCluster(context.Background(), app.Spec.Destination.Server)
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(clst.RESTConfig())
+}
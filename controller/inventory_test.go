@@ -0,0 +1,271 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+// newFluxKustomization returns a Flux Kustomization with the given status
+func newFluxKustomization(namespace, name string, status map[string]interface{}) *unstructured.Unstructured {
+	ks := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "kustomize.toolkit.fluxcd.io/v1",
+		"kind":       "Kustomization",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": namespace,
+		},
+		"spec": map[string]interface{}{
+			"path":     "./deploy",
+			"interval": "5m",
+			"prune":    true,
+		},
+	}}
+	if status != nil {
+		ks.Object["status"] = status
+	}
+	return ks
+}
+
+func fluxInventory(entries ...map[string]interface{}) map[string]interface{} {
+	items := make([]interface{}, 0, len(entries))
+	for _, e := range entries {
+		items = append(items, e)
+	}
+	return map[string]interface{}{"entries": items}
+}
+
+func newFakeDynamicClient(objs ...runtime.Object) dynamic.Interface {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		fluxKustomizationGVR: "KustomizationList",
+	}, objs...)
+}
+
+func newFluxInventoryApp() *v1alpha1.Application {
+	app := newFakeApp()
+	app.Annotations = map[string]string{common.AnnotationKeyGitOpsInventory: GitOpsInventoryFlux}
+	return app
+}
+
+func TestFluxInventoryReader_ReadInventory(t *testing.T) {
+	readyConditions := []interface{}{map[string]interface{}{"type": "Ready", "status": "True", "reason": "ReconciliationSucceeded"}}
+	failedConditions := []interface{}{map[string]interface{}{"type": "Ready", "status": "False", "reason": "BuildFailed"}}
+	entries := []map[string]interface{}{
+		{"id": "default_guestbook-ui_apps_Deployment", "v": "v1"},
+		{"id": "default_guestbook-ui__Service", "v": "v1"},
+		{"id": "_guestbook__view_rbac.authorization.k8s.io_ClusterRole", "v": "v1"},
+	}
+	expected := []InventoryResource{
+		{ResourceKey: kube.NewResourceKey("apps", "Deployment", "default", "guestbook-ui"), Version: "v1"},
+		{ResourceKey: kube.NewResourceKey("", "Service", "default", "guestbook-ui"), Version: "v1"},
+		{ResourceKey: kube.NewResourceKey("rbac.authorization.k8s.io", "ClusterRole", "", "guestbook:view"), Version: "v1"},
+	}
+
+	testCases := []struct {
+		name        string
+		ref         string
+		suspend     bool
+		status      map[string]interface{}
+		expected    []InventoryResource
+		expectedErr string
+	}{{
+		name:     "ready",
+		status:   map[string]interface{}{"conditions": readyConditions, "inventory": fluxInventory(entries...)},
+		expected: expected,
+	}, {
+		// the inventory of the last successful reconciliation is kept when a reconciliation fails
+		name:     "failed reconciliation",
+		status:   map[string]interface{}{"conditions": failedConditions, "inventory": fluxInventory(entries...)},
+		expected: expected,
+	}, {
+		name:     "suspended",
+		ref:      "apps/guestbook-suspended",
+		suspend:  true,
+		status:   map[string]interface{}{"inventory": fluxInventory(entries[0])},
+		expected: expected[:1],
+	}, {
+		name:     "empty inventory",
+		status:   map[string]interface{}{"conditions": readyConditions, "inventory": map[string]interface{}{}},
+		expected: []InventoryResource{},
+	}, {
+		name:        "not reconciled yet",
+		status:      map[string]interface{}{"conditions": failedConditions},
+		expectedErr: "Flux Kustomization flux-system/guestbook has no inventory yet",
+	}, {
+		name:        "no status",
+		expectedErr: "Flux Kustomization flux-system/guestbook has no inventory yet",
+	}, {
+		name:        "invalid ID",
+		status:      map[string]interface{}{"inventory": fluxInventory(map[string]interface{}{"id": "guestbook", "v": "v1"})},
+		expectedErr: `ID "guestbook" is not formatted as <namespace>_<name>_<group>_<kind>`,
+	}, {
+		name:        "missing version",
+		status:      map[string]interface{}{"inventory": fluxInventory(map[string]interface{}{"id": "default_guestbook-ui_apps_Deployment"})},
+		expectedErr: "inventory entry default_guestbook-ui_apps_Deployment of Flux Kustomization flux-system/guestbook has no version",
+	}}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newFluxInventoryApp()
+			app.Name = "guestbook"
+			if tc.ref != "" {
+				app.Annotations[common.AnnotationKeyGitOpsInventoryRef] = tc.ref
+			}
+			namespace, name, err := inventoryRef(app, defaultFluxNamespace)
+			require.NoError(t, err)
+			ks := newFluxKustomization(namespace, name, tc.status)
+			if tc.suspend {
+				require.NoError(t, unstructured.SetNestedField(ks.Object, true, "spec", "suspend"))
+			}
+
+			resources, err := NewFluxInventoryReader(newFakeDynamicClient(ks)).ReadInventory(context.Background(), app)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, resources)
+		})
+	}
+
+	t.Run("missing Kustomization", func(t *testing.T) {
+		_, err := NewFluxInventoryReader(newFakeDynamicClient()).ReadInventory(context.Background(), newFluxInventoryApp())
+		require.ErrorContains(t, err, "failed to get Flux Kustomization flux-system/my-app")
+	})
+}
+
+func TestParseFluxInventoryID(t *testing.T) {
+	testCases := []struct {
+		id       string
+		expected kube.ResourceKey
+	}{
+		{"default_guestbook-ui_apps_Deployment", kube.NewResourceKey("apps", "Deployment", "default", "guestbook-ui")},
+		{"default_guestbook-ui__ConfigMap", kube.NewResourceKey("", "ConfigMap", "default", "guestbook-ui")},
+		{"_guestbook_apiextensions.k8s.io_CustomResourceDefinition", kube.NewResourceKey("apiextensions.k8s.io", "CustomResourceDefinition", "", "guestbook")},
+		{"_system__controller__manager_rbac.authorization.k8s.io_ClusterRole", kube.NewResourceKey("rbac.authorization.k8s.io", "ClusterRole", "", "system:controller:manager")},
+		{"default_my_config__ConfigMap", kube.NewResourceKey("", "ConfigMap", "default", "my_config")},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.id, func(t *testing.T) {
+			key, err := parseFluxInventoryID(tc.id)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, key)
+		})
+	}
+
+	for _, id := range []string{"", "default", "default_guestbook", "default__apps_Deployment", "default_guestbook_apps_"} {
+		_, err := parseFluxInventoryID(id)
+		assert.Error(t, err, id)
+	}
+}
+
+func TestInventoryRef(t *testing.T) {
+	app := newFakeApp()
+	namespace, name, err := inventoryRef(app, defaultFluxNamespace)
+	require.NoError(t, err)
+	assert.Equal(t, "flux-system", namespace)
+	assert.Equal(t, "my-app", name)
+
+	app.Annotations = map[string]string{common.AnnotationKeyGitOpsInventoryRef: "apps/guestbook"}
+	namespace, name, err = inventoryRef(app, defaultFluxNamespace)
+	require.NoError(t, err)
+	assert.Equal(t, "apps", namespace)
+	assert.Equal(t, "guestbook", name)
+
+	app.Annotations[common.AnnotationKeyGitOpsInventoryRef] = "guestbook"
+	namespace, name, err = inventoryRef(app, defaultFluxNamespace)
+	require.NoError(t, err)
+	assert.Equal(t, "flux-system", namespace)
+	assert.Equal(t, "guestbook", name)
+
+	app.Annotations[common.AnnotationKeyGitOpsInventoryRef] = "apps/"
+	_, _, err = inventoryRef(app, defaultFluxNamespace)
+	assert.ErrorContains(t, err, "must be formatted as <namespace>/<name>")
+}
+
+func TestGetInventoryReader(t *testing.T) {
+	ctrl := newFakeController(&fakeData{}, nil)
+	manager := ctrl.appStateManager.(*appStateManager)
+	manager.dynamicClientForDestination = func(_ *v1alpha1.Application) (dynamic.Interface, error) {
+		return newFakeDynamicClient(), nil
+	}
+
+	reader, err := manager.getInventoryReader(newFakeApp())
+	require.NoError(t, err)
+	assert.Nil(t, reader)
+
+	reader, err = manager.getInventoryReader(newFluxInventoryApp())
+	require.NoError(t, err)
+	assert.IsType(t, &fluxInventoryReader{}, reader)
+
+	app := newFakeApp()
+	app.Annotations = map[string]string{common.AnnotationKeyGitOpsInventory: "config-sync"}
+	_, err = manager.getInventoryReader(app)
+	assert.ErrorContains(t, err, `unsupported argocd.argoproj.io/gitops-inventory annotation "config-sync"`)
+}
+
+func TestCompareAppStateFluxInventory(t *testing.T) {
+	deployment := test.YamlToUnstructured(`{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"guestbook-ui","namespace":"` + test.FakeDestNamespace + `","uid":"1"}}`)
+	service := test.YamlToUnstructured(`{"apiVersion":"v1","kind":"Service","metadata":{"name":"guestbook-ui","namespace":"` + test.FakeDestNamespace + `","uid":"2"}}`)
+	// a resource tracked by Argo CD that is not in the inventory
+	configMap := test.YamlToUnstructured(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"guestbook-config","namespace":"` + test.FakeDestNamespace + `","uid":"3"}}`)
+	newData := func() *fakeData {
+		return &fakeData{
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: []string{},
+				Namespace: test.FakeDestNamespace,
+				Server:    test.FakeClusterURL,
+				Revision:  "abc123",
+			},
+			managedLiveObjs: map[kube.ResourceKey]*unstructured.Unstructured{
+				kube.GetResourceKey(deployment): deployment,
+				kube.GetResourceKey(service):    service,
+				kube.GetResourceKey(configMap):  configMap,
+			},
+		}
+	}
+	compare := func(t *testing.T, ks *unstructured.Unstructured) (*v1alpha1.Application, *comparisonResult) {
+		t.Helper()
+		app := newFluxInventoryApp()
+		ctrl := newFakeController(newData(), nil)
+		ctrl.appStateManager.(*appStateManager).dynamicClientForDestination = func(_ *v1alpha1.Application) (dynamic.Interface, error) {
+			return newFakeDynamicClient(ks), nil
+		}
+		compRes, err := ctrl.appStateManager.CompareAppState(app, &defaultProj, []string{"abc123"}, app.Spec.GetSources(), false, false, nil, false, false)
+		require.NoError(t, err)
+		return app, compRes
+	}
+
+	t.Run("inventory read", func(t *testing.T) {
+		ks := newFluxKustomization(defaultFluxNamespace, "my-app", map[string]interface{}{"inventory": fluxInventory(
+			map[string]interface{}{"id": test.FakeDestNamespace + "_guestbook-ui_apps_Deployment", "v": "v1"},
+			map[string]interface{}{"id": test.FakeDestNamespace + "_guestbook-ui__Service", "v": "v1"},
+		)})
+		app, compRes := compare(t, ks)
+		assert.Empty(t, app.Status.Conditions)
+		var kinds []string
+		for _, res := range compRes.resources {
+			kinds = append(kinds, res.Kind)
+		}
+		assert.ElementsMatch(t, []string{"Deployment", "Service"}, kinds)
+	})
+
+	t.Run("inventory missing", func(t *testing.T) {
+		app, _ := compare(t, newFluxKustomization(defaultFluxNamespace, "my-app", nil))
+		require.Len(t, app.Status.Conditions, 1)
+		assert.Equal(t, v1alpha1.ApplicationConditionComparisonError, app.Status.Conditions[0].Type)
+		assert.Contains(t, app.Status.Conditions[0].Message, "Failed to read inventory: Flux Kustomization flux-system/my-app has no inventory yet")
+	})
+}
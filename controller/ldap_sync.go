@@ -0,0 +1,514 @@
+package controller
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	log "github.com/sirupsen/logrus"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned"
+	"github.com/argoproj/argo-cd/v2/util/rbac"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+// LDAPSyncPolicyKey is the key of the argocd-rbac-cm ConfigMap holding the policy generated by the LDAP group sync.
+// It is merged with the other policy[.overlay].csv keys by the RBAC enforcer and fully owned by the controller.
+const LDAPSyncPolicyKey = "policy.ldap-sync.csv"
+
+// ldapClient is the subset of the LDAP connection used by the group sync
+type ldapClient interface {
+	Search(req *ldap.SearchRequest) (*ldap.SearchResult, error)
+	Close() error
+}
+
+// ldapUserMatcher relates the attribute of a group entry listing its members to the attribute of the user entries
+type ldapUserMatcher struct {
+	UserAttr  string `json:"userAttr"`
+	GroupAttr string `json:"groupAttr"`
+}
+
+// ldapConfig is the subset of the Dex LDAP connector configuration used to query group members
+type ldapConfig struct {
+	Host               string `json:"host"`
+	InsecureNoSSL      bool   `json:"insecureNoSSL"`
+	InsecureSkipVerify bool   `json:"insecureSkipVerify"`
+	StartTLS           bool   `json:"startTLS"`
+	RootCAData         []byte `json:"rootCAData"`
+	BindDN             string `json:"bindDN"`
+	BindPW             string `json:"bindPW"`
+	UserSearch         struct {
+		BaseDN   string `json:"baseDN"`
+		Filter   string `json:"filter"`
+		Username string `json:"username"`
+	} `json:"userSearch"`
+	GroupSearch struct {
+		BaseDN       string            `json:"baseDN"`
+		Filter       string            `json:"filter"`
+		UserAttr     string            `json:"userAttr"`
+		GroupAttr    string            `json:"groupAttr"`
+		UserMatchers []ldapUserMatcher `json:"userMatchers"`
+		NameAttr     string            `json:"nameAttr"`
+	} `json:"groupSearch"`
+}
+
+func (c *ldapConfig) userMatchers() []ldapUserMatcher {
+	matchers := c.GroupSearch.UserMatchers
+	// userAttr and groupAttr are the deprecated form of a single user matcher
+	if c.GroupSearch.UserAttr != "" && c.GroupSearch.GroupAttr != "" {
+		matchers = append(matchers, ldapUserMatcher{UserAttr: c.GroupSearch.UserAttr, GroupAttr: c.GroupSearch.GroupAttr})
+	}
+	return matchers
+}
+
+// groupAttrs returns the attributes of group entries read to resolve their members
+func (c *ldapConfig) groupAttrs() []string {
+	attrs := []string{c.GroupSearch.NameAttr}
+	for _, m := range c.userMatchers() {
+		attrs = append(attrs, m.GroupAttr)
+	}
+	return attrs
+}
+
+// ldapConfigFromDex returns the configuration of the first LDAP connector of the Dex configuration or nil if there is none
+func ldapConfigFromDex(argoSettings *settings.ArgoCDSettings) (*ldapConfig, error) {
+	if argoSettings.DexConfig == "" {
+		return nil, nil
+	}
+	dexCfg, err := settings.UnmarshalDexConfig(argoSettings.DexConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal dex.config: %w", err)
+	}
+	dexCfg = settings.ReplaceMapSecrets(dexCfg, argoSettings.Secrets)
+	connectors, _ := dexCfg["connectors"].([]interface{})
+	for _, connectorIf := range connectors {
+		connector, ok := connectorIf.(map[string]interface{})
+		if !ok || connector["type"] != "ldap" {
+			continue
+		}
+		data, err := json.Marshal(connector["config"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal LDAP connector config: %w", err)
+		}
+		var cfg ldapConfig
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal LDAP connector config: %w", err)
+		}
+		if cfg.Host == "" || cfg.GroupSearch.BaseDN == "" || cfg.GroupSearch.NameAttr == "" || cfg.UserSearch.Username == "" {
+			return nil, fmt.Errorf("LDAP connector %v must configure host, userSearch.username, groupSearch.baseDN and groupSearch.nameAttr", connector["id"])
+		}
+		return &cfg, nil
+	}
+	return nil, nil
+}
+
+// dialLDAP connects and binds to the LDAP server the same way as the Dex LDAP connector
+func dialLDAP(cfg *ldapConfig) (ldapClient, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	if host, _, err := net.SplitHostPort(cfg.Host); err == nil {
+		tlsConfig.ServerName = host
+	} else {
+		tlsConfig.ServerName = cfg.Host
+	}
+	if len(cfg.RootCAData) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(cfg.RootCAData) {
+			return nil, fmt.Errorf("no certificates found in LDAP connector rootCAData")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	scheme, port := "ldaps", "636"
+	if cfg.InsecureNoSSL || cfg.StartTLS {
+		scheme, port = "ldap", "389"
+	}
+	addr := cfg.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, port)
+	}
+	conn, err := ldap.DialURL(fmt.Sprintf("%s://%s", scheme, addr), ldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to LDAP server %s: %w", addr, err)
+	}
+	if cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("failed to start TLS with LDAP server %s: %w", addr, err)
+		}
+	}
+	if cfg.BindDN != "" {
+		err = conn.Bind(cfg.BindDN, cfg.BindPW)
+	} else {
+		err = conn.UnauthenticatedBind("")
+	}
+	if err != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("failed to bind to LDAP server %s: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// LDAPGroupSync periodically synchronizes the members of the LDAP groups referenced in the RBAC policy and the
+// project roles with local accounts. The LDAP server is queried using the LDAP connector of the Dex configuration.
+// Every member gets a local account with the apiKey capability, and is assigned the roles of its groups in the
+// policy.ldap-sync.csv key of argocd-rbac-cm. Accounts created by the sync whose users are no longer members of any
+// group are disabled, while the other local accounts are never modified nor assigned roles.
+type LDAPGroupSync struct {
+	namespace            string
+	settingsMgr          *settings.SettingsManager
+	kubeClientset        kubernetes.Interface
+	applicationClientset appclientset.Interface
+	interval             time.Duration
+	// recursionDepth is the number of levels of nested groups whose members are synchronized
+	recursionDepth int
+	dial           func(cfg *ldapConfig) (ldapClient, error)
+}
+
+// NewLDAPGroupSync returns a new LDAP group sync running every interval
+func NewLDAPGroupSync(namespace string, settingsMgr *settings.SettingsManager, kubeClientset kubernetes.Interface, applicationClientset appclientset.Interface, interval time.Duration, recursionDepth int) *LDAPGroupSync {
+	return &LDAPGroupSync{
+		namespace:            namespace,
+		settingsMgr:          settingsMgr,
+		kubeClientset:        kubeClientset,
+		applicationClientset: applicationClientset,
+		interval:             interval,
+		recursionDepth:       recursionDepth,
+		dial:                 dialLDAP,
+	}
+}
+
+// Run synchronizes the LDAP groups every interval until the context is done
+func (s *LDAPGroupSync) Run(ctx context.Context) {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := s.Sync(ctx); err != nil {
+			log.Warnf("Failed to synchronize LDAP groups: %v", err)
+		}
+	}, s.interval)
+}
+
+// Sync synchronizes the members of the referenced LDAP groups with local accounts and the generated RBAC policy
+func (s *LDAPGroupSync) Sync(ctx context.Context) error {
+	argoSettings, err := s.settingsMgr.GetSettings()
+	if err != nil {
+		return fmt.Errorf("error getting settings: %w", err)
+	}
+	cfg, err := ldapConfigFromDex(argoSettings)
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		log.Debug("No LDAP connector is configured in dex.config, skipping LDAP group sync")
+		return nil
+	}
+
+	rbacCM, err := s.kubeClientset.CoreV1().ConfigMaps(s.namespace).Get(ctx, common.ArgoCDRBACConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("error getting %s: %w", common.ArgoCDRBACConfigMapName, err)
+	}
+	groupRoles, err := s.groupRoles(ctx, rbacCM.Data)
+	if err != nil {
+		return err
+	}
+
+	userRoles := make(map[string]map[string]bool)
+	if len(groupRoles) > 0 {
+		client, err := s.dial(cfg)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = client.Close() }()
+		for group, roles := range groupRoles {
+			users, err := s.groupMembers(client, cfg, group)
+			if err != nil {
+				return fmt.Errorf("error getting members of LDAP group %s: %w", group, err)
+			}
+			for _, user := range users {
+				if userRoles[user] == nil {
+					userRoles[user] = make(map[string]bool)
+				}
+				for _, role := range roles {
+					userRoles[user][role] = true
+				}
+			}
+		}
+	}
+
+	userRoles, err = s.syncAccounts(userRoles)
+	if err != nil {
+		return err
+	}
+	return s.savePolicy(ctx, syncPolicy(userRoles))
+}
+
+// groupRoles returns the roles assigned to every group referenced by the user defined policy or the project roles
+func (s *LDAPGroupSync) groupRoles(ctx context.Context, rbacData map[string]string) (map[string][]string, error) {
+	data := make(map[string]string, len(rbacData))
+	for k, v := range rbacData {
+		if k != LDAPSyncPolicyKey {
+			data[k] = v
+		}
+	}
+	groupRoles := make(map[string][]string)
+	for _, line := range strings.Split(rbac.PolicyCSV(data), "\n") {
+		tokens := parsePolicyLine(line)
+		if len(tokens) != 3 || tokens[0] != "g" {
+			continue
+		}
+		// role inheritance is not a group membership
+		if strings.HasPrefix(tokens[1], "role:") || strings.HasPrefix(tokens[1], "proj:") {
+			continue
+		}
+		groupRoles[tokens[1]] = append(groupRoles[tokens[1]], tokens[2])
+	}
+
+	projects, err := s.applicationClientset.ArgoprojV1alpha1().AppProjects(s.namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing projects: %w", err)
+	}
+	for _, proj := range projects.Items {
+		for _, role := range proj.Spec.Roles {
+			for _, group := range role.Groups {
+				groupRoles[group] = append(groupRoles[group], fmt.Sprintf("proj:%s:%s", proj.Name, role.Name))
+			}
+		}
+	}
+	return groupRoles, nil
+}
+
+// groupMembers returns the usernames of the members of the LDAP group with the given name
+func (s *LDAPGroupSync) groupMembers(client ldapClient, cfg *ldapConfig, group string) ([]string, error) {
+	filter := fmt.Sprintf("(%s=%s)", cfg.GroupSearch.NameAttr, ldap.EscapeFilter(group))
+	if cfg.GroupSearch.Filter != "" {
+		filter = fmt.Sprintf("(&%s%s)", cfg.GroupSearch.Filter, filter)
+	}
+	res, err := client.Search(ldap.NewSearchRequest(cfg.GroupSearch.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false, filter, cfg.groupAttrs(), nil))
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[string]bool)
+	visited := make(map[string]bool)
+	for _, entry := range res.Entries {
+		visited[entry.DN] = true
+	}
+	for _, entry := range res.Entries {
+		if err := s.collectMembers(client, cfg, entry, s.recursionDepth, users, visited); err != nil {
+			return nil, err
+		}
+	}
+	var result []string
+	for user := range users {
+		result = append(result, user)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// collectMembers adds the usernames of the members of the given group entry to users. Members which are groups
+// themselves are followed up to depth levels.
+func (s *LDAPGroupSync) collectMembers(client ldapClient, cfg *ldapConfig, group *ldap.Entry, depth int, users map[string]bool, visited map[string]bool) error {
+	userFilter := cfg.UserSearch.Filter
+	if userFilter == "" {
+		userFilter = "(objectClass=*)"
+	}
+	for _, m := range cfg.userMatchers() {
+		for _, value := range group.GetAttributeValues(m.GroupAttr) {
+			if m.UserAttr != "DN" {
+				res, err := client.Search(ldap.NewSearchRequest(cfg.UserSearch.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+					fmt.Sprintf("(&%s(%s=%s))", userFilter, m.UserAttr, ldap.EscapeFilter(value)), []string{cfg.UserSearch.Username}, nil))
+				if err != nil {
+					return err
+				}
+				for _, user := range res.Entries {
+					if username := user.GetAttributeValue(cfg.UserSearch.Username); username != "" {
+						users[username] = true
+					}
+				}
+				continue
+			}
+
+			user, err := lookupDN(client, value, userFilter, []string{cfg.UserSearch.Username})
+			if err != nil {
+				return err
+			}
+			if user != nil {
+				if username := user.GetAttributeValue(cfg.UserSearch.Username); username != "" {
+					users[username] = true
+				}
+				continue
+			}
+			if depth <= 0 || visited[value] {
+				continue
+			}
+			groupFilter := cfg.GroupSearch.Filter
+			if groupFilter == "" {
+				groupFilter = "(objectClass=*)"
+			}
+			nested, err := lookupDN(client, value, groupFilter, cfg.groupAttrs())
+			if err != nil {
+				return err
+			}
+			if nested == nil {
+				continue
+			}
+			visited[value] = true
+			if err := s.collectMembers(client, cfg, nested, depth-1, users, visited); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// lookupDN returns the entry with the given DN if it matches the filter, or nil
+func lookupDN(client ldapClient, dn string, filter string, attrs []string) (*ldap.Entry, error) {
+	res, err := client.Search(ldap.NewSearchRequest(dn, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false, filter, attrs, nil))
+	if err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultNoSuchObject) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(res.Entries) == 0 {
+		return nil, nil
+	}
+	return res.Entries[0], nil
+}
+
+// syncAccounts creates or enables the accounts of the synchronized users, and disables the accounts created by the
+// sync whose users are no longer members of any group. Users with an invalid name, or whose local account was not
+// created by the sync, are skipped. It returns the roles of the users which are not skipped.
+func (s *LDAPGroupSync) syncAccounts(userRoles map[string]map[string]bool) (map[string]map[string]bool, error) {
+	accounts, err := s.settingsMgr.GetAccounts()
+	if err != nil {
+		return nil, fmt.Errorf("error getting accounts: %w", err)
+	}
+	synced := make(map[string]map[string]bool)
+	for user, roles := range userRoles {
+		if err := validateAccountName(user); err != nil {
+			log.Warnf("Skipping LDAP user: %v", err)
+			continue
+		}
+		account, ok := accounts[user]
+		switch {
+		case !ok:
+			log.Infof("Creating account %s for LDAP user", user)
+			err := s.settingsMgr.AddAccount(user, settings.Account{
+				Enabled:      true,
+				Capabilities: []settings.AccountCapability{settings.AccountCapabilityApiKey},
+				ManagedBy:    settings.AccountManagedByLDAPSync,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error creating account %s: %w", user, err)
+			}
+		case account.ManagedBy != settings.AccountManagedByLDAPSync:
+			log.Warnf("Skipping LDAP user %s: local account %s was not created by the LDAP group sync", user, user)
+			continue
+		case !account.Enabled:
+			log.Infof("Enabling account %s of LDAP user", user)
+			if err := s.setAccountEnabled(user, true); err != nil {
+				return nil, err
+			}
+		}
+		synced[user] = roles
+	}
+	for name, account := range accounts {
+		if _, ok := synced[name]; ok || account.ManagedBy != settings.AccountManagedByLDAPSync || !account.Enabled {
+			continue
+		}
+		log.Infof("Disabling account %s of user which is no longer a member of any LDAP group", name)
+		if err := s.setAccountEnabled(name, false); err != nil {
+			return nil, err
+		}
+	}
+	return synced, nil
+}
+
+// validateAccountName returns an error if the given LDAP username cannot be used as the name of a local account and
+// as the subject of a policy line
+func validateAccountName(name string) error {
+	if name == "" || strings.ContainsAny(name, ".,:\"\n\r/ \t") || name == common.ArgoCDAdminUsername {
+		return fmt.Errorf("invalid account name %q", name)
+	}
+	return nil
+}
+
+func (s *LDAPGroupSync) setAccountEnabled(name string, enabled bool) error {
+	err := s.settingsMgr.UpdateAccount(name, func(account *settings.Account) error {
+		account.Enabled = enabled
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error updating account %s: %w", name, err)
+	}
+	return nil
+}
+
+// savePolicy stores the generated policy in argocd-rbac-cm if it changed
+func (s *LDAPGroupSync) savePolicy(ctx context.Context, policy string) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		cm, err := s.kubeClientset.CoreV1().ConfigMaps(s.namespace).Get(ctx, common.ArgoCDRBACConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if cm.Data[LDAPSyncPolicyKey] == policy {
+			return nil
+		}
+		if cm.Data == nil {
+			cm.Data = make(map[string]string)
+		}
+		if policy == "" {
+			delete(cm.Data, LDAPSyncPolicyKey)
+		} else {
+			cm.Data[LDAPSyncPolicyKey] = policy
+		}
+		_, err = s.kubeClientset.CoreV1().ConfigMaps(s.namespace).Update(ctx, cm, metav1.UpdateOptions{})
+		if apierrors.IsConflict(err) {
+			return err
+		}
+		if err != nil {
+			return fmt.Errorf("error updating %s: %w", common.ArgoCDRBACConfigMapName, err)
+		}
+		return nil
+	})
+}
+
+// syncPolicy returns the policy assigning the synchronized users to the roles of their groups
+func syncPolicy(userRoles map[string]map[string]bool) string {
+	var lines []string
+	for user, roles := range userRoles {
+		for role := range roles {
+			lines = append(lines, fmt.Sprintf("g, %s, %s", user, role))
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+func parsePolicyLine(line string) []string {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return nil
+	}
+	reader := csv.NewReader(strings.NewReader(line))
+	reader.TrimLeadingSpace = true
+	tokens, err := reader.Read()
+	if err != nil {
+		return nil
+	}
+	return tokens
+}
@@ -0,0 +1,282 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned/fake"
+	"github.com/argoproj/argo-cd/v2/test"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+const fakeLDAPDexConfig = `connectors:
+- type: ldap
+  id: ldap
+  name: LDAP
+  config:
+    host: ldap.example.com
+    bindDN: cn=admin,dc=example,dc=org
+    bindPW: $ldap.bindPW
+    userSearch:
+      baseDN: ou=people,dc=example,dc=org
+      filter: (objectClass=person)
+      username: uid
+    groupSearch:
+      baseDN: ou=groups,dc=example,dc=org
+      filter: (objectClass=groupOfNames)
+      userMatchers:
+      - userAttr: DN
+        groupAttr: member
+      nameAttr: cn
+`
+
+// fakeLDAPDirectory is an in-memory LDAP directory supporting the search filters used by the group sync
+type fakeLDAPDirectory struct {
+	entries []*ldap.Entry
+}
+
+func (d *fakeLDAPDirectory) add(dn string, attrs map[string][]string) {
+	d.entries = append(d.entries, ldap.NewEntry(dn, attrs))
+}
+
+func (d *fakeLDAPDirectory) Search(req *ldap.SearchRequest) (*ldap.SearchResult, error) {
+	res := &ldap.SearchResult{}
+	found := false
+	for _, entry := range d.entries {
+		inScope := strings.EqualFold(entry.DN, req.BaseDN)
+		if req.Scope == ldap.ScopeWholeSubtree {
+			inScope = strings.HasSuffix(strings.ToLower(entry.DN), strings.ToLower(req.BaseDN))
+		}
+		if !inScope {
+			continue
+		}
+		found = true
+		if matches, _ := matchesFilter(entry, req.Filter); matches {
+			res.Entries = append(res.Entries, entry)
+		}
+	}
+	if !found && req.Scope == ldap.ScopeBaseObject {
+		return nil, ldap.NewError(ldap.LDAPResultNoSuchObject, fmt.Errorf("no such object: %s", req.BaseDN))
+	}
+	return res, nil
+}
+
+func (d *fakeLDAPDirectory) Close() error {
+	return nil
+}
+
+// matchesFilter evaluates the conjunction, equality and presence filters at the start of the given filter and
+// returns the remaining part of the filter
+func matchesFilter(entry *ldap.Entry, filter string) (bool, string) {
+	filter = strings.TrimPrefix(filter, "(")
+	if strings.HasPrefix(filter, "&") {
+		filter = filter[1:]
+		matches := true
+		for strings.HasPrefix(filter, "(") {
+			var m bool
+			m, filter = matchesFilter(entry, filter)
+			matches = matches && m
+		}
+		return matches, strings.TrimPrefix(filter, ")")
+	}
+	end := strings.Index(filter, ")")
+	attr, value, _ := strings.Cut(filter[:end], "=")
+	rest := filter[end+1:]
+	values := entry.GetAttributeValues(attr)
+	if value == "*" {
+		return len(values) > 0, rest
+	}
+	for _, v := range values {
+		if strings.EqualFold(ldap.EscapeFilter(v), value) {
+			return true, rest
+		}
+	}
+	return false, rest
+}
+
+func newFakeLDAPDirectory() *fakeLDAPDirectory {
+	d := &fakeLDAPDirectory{}
+	for _, user := range []string{"alice", "bob", "carol"} {
+		d.add(fmt.Sprintf("uid=%s,ou=people,dc=example,dc=org", user), map[string][]string{"objectClass": {"person"}, "uid": {user}})
+	}
+	d.add("cn=admins,ou=groups,dc=example,dc=org", map[string][]string{
+		"objectClass": {"groupOfNames"},
+		"cn":          {"admins"},
+		"member":      {"uid=alice,ou=people,dc=example,dc=org", "cn=operators,ou=groups,dc=example,dc=org"},
+	})
+	d.add("cn=operators,ou=groups,dc=example,dc=org", map[string][]string{
+		"objectClass": {"groupOfNames"},
+		"cn":          {"operators"},
+		// the cycle must not be followed forever
+		"member": {"uid=carol,ou=people,dc=example,dc=org", "cn=admins,ou=groups,dc=example,dc=org"},
+	})
+	d.add("cn=developers,ou=groups,dc=example,dc=org", map[string][]string{
+		"objectClass": {"groupOfNames"},
+		"cn":          {"developers"},
+		"member":      {"uid=bob,ou=people,dc=example,dc=org", "uid=deleted,ou=people,dc=example,dc=org"},
+	})
+	return d
+}
+
+func newFakeLDAPGroupSync(recursionDepth int) (*LDAPGroupSync, *fake.Clientset, *[]*ldapConfig) {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ArgoCDConfigMapName,
+			Namespace: test.FakeArgoCDNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/part-of": "argocd"},
+		},
+		Data: map[string]string{
+			"dex.config":              fakeLDAPDexConfig,
+			"accounts.dave":           "apiKey",
+			"accounts.dave.managedBy": settings.AccountManagedByLDAPSync,
+			"accounts.erin":           "apiKey, login",
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ArgoCDSecretName,
+			Namespace: test.FakeArgoCDNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/part-of": "argocd"},
+		},
+		Data: map[string][]byte{
+			"admin.password":   []byte("test"),
+			"server.secretkey": []byte("test"),
+			"ldap.bindPW":      []byte("secret"),
+		},
+	}
+	rbacCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ArgoCDRBACConfigMapName, Namespace: test.FakeArgoCDNamespace},
+		Data: map[string]string{
+			"policy.csv": "g, admins, role:admin\ng, role:reader, role:readonly\ng, erin, role:readonly",
+			// dave was synchronized before and is no longer a member of any group
+			LDAPSyncPolicyKey: "g, dave, role:admin",
+		},
+	}
+	proj := &v1alpha1.AppProject{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: test.FakeArgoCDNamespace},
+		Spec: v1alpha1.AppProjectSpec{
+			Roles: []v1alpha1.ProjectRole{{Name: "deployer", Groups: []string{"developers"}}},
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(cm, secret, rbacCM)
+	settingsMgr := settings.NewSettingsManager(context.Background(), kubeClient, test.FakeArgoCDNamespace)
+	sync := NewLDAPGroupSync(test.FakeArgoCDNamespace, settingsMgr, kubeClient, appclientset.NewSimpleClientset(proj), time.Hour, recursionDepth)
+	var dialed []*ldapConfig
+	directory := newFakeLDAPDirectory()
+	sync.dial = func(cfg *ldapConfig) (ldapClient, error) {
+		dialed = append(dialed, cfg)
+		return directory, nil
+	}
+	return sync, kubeClient, &dialed
+}
+
+func getRBACPolicy(t *testing.T, kubeClient *fake.Clientset) string {
+	t.Helper()
+	cm, err := kubeClient.CoreV1().ConfigMaps(test.FakeArgoCDNamespace).Get(context.Background(), common.ArgoCDRBACConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	return cm.Data[LDAPSyncPolicyKey]
+}
+
+func TestLDAPGroupSync_Sync(t *testing.T) {
+	sync, kubeClient, dialed := newFakeLDAPGroupSync(0)
+	require.NoError(t, sync.Sync(context.Background()))
+
+	require.Len(t, *dialed, 1)
+	assert.Equal(t, "ldap.example.com", (*dialed)[0].Host)
+	assert.Equal(t, "secret", (*dialed)[0].BindPW)
+	// nested groups are not followed without recursion
+	assert.Equal(t, "g, alice, role:admin\ng, bob, proj:staging:deployer", getRBACPolicy(t, kubeClient))
+
+	accounts, err := sync.settingsMgr.GetAccounts()
+	require.NoError(t, err)
+	for _, user := range []string{"alice", "bob"} {
+		require.Contains(t, accounts, user)
+		assert.True(t, accounts[user].Enabled)
+		assert.Equal(t, []settings.AccountCapability{settings.AccountCapabilityApiKey}, accounts[user].Capabilities)
+	}
+	assert.NotContains(t, accounts, "carol")
+	assert.Equal(t, settings.AccountManagedByLDAPSync, accounts["alice"].ManagedBy)
+	assert.False(t, accounts["dave"].Enabled)
+	// accounts which were not synchronized are left untouched
+	assert.True(t, accounts["erin"].Enabled)
+
+	t.Run("Removed", func(t *testing.T) {
+		dir := newFakeLDAPDirectory()
+		// bob left and alice joined the developers
+		dir.entries[len(dir.entries)-1] = ldap.NewEntry("cn=developers,ou=groups,dc=example,dc=org", map[string][]string{
+			"objectClass": {"groupOfNames"},
+			"cn":          {"developers"},
+			"member":      {"uid=alice,ou=people,dc=example,dc=org"},
+		})
+		sync.dial = func(cfg *ldapConfig) (ldapClient, error) {
+			return dir, nil
+		}
+		require.NoError(t, sync.Sync(context.Background()))
+		assert.Equal(t, "g, alice, proj:staging:deployer\ng, alice, role:admin", getRBACPolicy(t, kubeClient))
+		account, err := sync.settingsMgr.GetAccount("bob")
+		require.NoError(t, err)
+		assert.False(t, account.Enabled)
+	})
+}
+
+func TestLDAPGroupSync_SkippedUsers(t *testing.T) {
+	sync, kubeClient, _ := newFakeLDAPGroupSync(0)
+	dir := newFakeLDAPDirectory()
+	dir.add("uid=erin,ou=people,dc=example,dc=org", map[string][]string{"objectClass": {"person"}, "uid": {"erin"}})
+	dir.add("uid=eve,ou=people,dc=example,dc=org", map[string][]string{"objectClass": {"person"}, "uid": {"eve, role:admin\ng, mallory"}})
+	dir.add("uid=root,ou=people,dc=example,dc=org", map[string][]string{"objectClass": {"person"}, "uid": {"admin"}})
+	dir.entries[3] = ldap.NewEntry("cn=admins,ou=groups,dc=example,dc=org", map[string][]string{
+		"objectClass": {"groupOfNames"},
+		"cn":          {"admins"},
+		"member": {
+			"uid=alice,ou=people,dc=example,dc=org",
+			"uid=erin,ou=people,dc=example,dc=org",
+			"uid=eve,ou=people,dc=example,dc=org",
+			"uid=root,ou=people,dc=example,dc=org",
+		},
+	})
+	sync.dial = func(cfg *ldapConfig) (ldapClient, error) {
+		return dir, nil
+	}
+	require.NoError(t, sync.Sync(context.Background()))
+
+	// invalid usernames and local accounts which were not created by the sync are not assigned roles
+	assert.Equal(t, "g, alice, role:admin\ng, bob, proj:staging:deployer", getRBACPolicy(t, kubeClient))
+	accounts, err := sync.settingsMgr.GetAccounts()
+	require.NoError(t, err)
+	assert.Len(t, accounts, 5)
+	assert.Empty(t, accounts["erin"].ManagedBy)
+	assert.Equal(t, []settings.AccountCapability{settings.AccountCapabilityApiKey, settings.AccountCapabilityLogin}, accounts["erin"].Capabilities)
+	assert.Equal(t, []settings.AccountCapability{settings.AccountCapabilityLogin}, accounts[common.ArgoCDAdminUsername].Capabilities)
+}
+
+func TestLDAPGroupSync_NestedGroups(t *testing.T) {
+	sync, kubeClient, _ := newFakeLDAPGroupSync(1)
+	require.NoError(t, sync.Sync(context.Background()))
+	assert.Equal(t, "g, alice, role:admin\ng, bob, proj:staging:deployer\ng, carol, role:admin", getRBACPolicy(t, kubeClient))
+}
+
+func TestLDAPGroupSync_NoLDAPConnector(t *testing.T) {
+	sync, kubeClient, dialed := newFakeLDAPGroupSync(0)
+	cm, err := kubeClient.CoreV1().ConfigMaps(test.FakeArgoCDNamespace).Get(context.Background(), common.ArgoCDConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	delete(cm.Data, "dex.config")
+	_, err = kubeClient.CoreV1().ConfigMaps(test.FakeArgoCDNamespace).Update(context.Background(), cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	require.NoError(t, sync.Sync(context.Background()))
+	assert.Empty(t, *dialed)
+	assert.Equal(t, "g, dave, role:admin", getRBACPolicy(t, kubeClient))
+}
@@ -0,0 +1,219 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	// LeaderElectionBackendKubernetes elects the leader with a coordination.k8s.io Lease
+	LeaderElectionBackendKubernetes = "k8s"
+	// LeaderElectionBackendEtcd elects the leader with an etcd lease, which does not depend on the latency of the API server
+	LeaderElectionBackendEtcd = "etcd"
+
+	// DefaultLeaderElectionTTL is the duration after which the leadership of a replica which stopped renewing it expires
+	DefaultLeaderElectionTTL = 15 * time.Second
+)
+
+// ErrLeadershipLost is returned by LeaderElection.Run once the leadership expired before the replica released it
+var ErrLeadershipLost = errors.New("leadership lost")
+
+// LeaderElection elects a single replica of the application controller which reconciles the applications, so that
+// the other replicas are standbys taking over once it stops
+type LeaderElection interface {
+	// Run blocks until the replica is elected and then calls onStartedLeading with a context which is canceled once
+	// the leadership is lost. Run releases the leadership and returns nil once the given context is done, or returns
+	// ErrLeadershipLost if the leadership expired before.
+	Run(ctx context.Context, onStartedLeading func(ctx context.Context)) error
+}
+
+// kubernetesLeaderElection elects the leader with a coordination.k8s.io Lease
+type kubernetesLeaderElection struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+	name       string
+	identity   string
+	ttl        time.Duration
+}
+
+// NewKubernetesLeaderElection returns a leader election based on the Lease with the given name in the namespace
+func NewKubernetesLeaderElection(kubeClient kubernetes.Interface, namespace, name, identity string, ttl time.Duration) LeaderElection {
+	return &kubernetesLeaderElection{kubeClient: kubeClient, namespace: namespace, name: name, identity: identity, ttl: ttl}
+}
+
+func (e *kubernetesLeaderElection) Run(ctx context.Context, onStartedLeading func(ctx context.Context)) error {
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta:  metav1.ObjectMeta{Namespace: e.namespace, Name: e.name},
+			Client:     e.kubeClient.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: e.identity},
+		},
+		LeaseDuration:   e.ttl,
+		RenewDeadline:   e.ttl * 2 / 3,
+		RetryPeriod:     e.ttl / 7,
+		ReleaseOnCancel: true,
+		Name:            e.name,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: onStartedLeading,
+			OnStoppedLeading: func() {
+				log.WithField("lease", e.name).Info("Stopped leading")
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating the leader election: %w", err)
+	}
+	// Run only returns before the context is done if the leadership could not be renewed
+	elector.Run(ctx)
+	if ctx.Err() == nil {
+		return ErrLeadershipLost
+	}
+	return nil
+}
+
+// etcdLeaderElection elects the leader with an etcd lease: the leader holds the key attached to its lease, which it
+// keeps alive in the background, and the other replicas wait for the key to be deleted to campaign again
+type etcdLeaderElection struct {
+	client   *clientv3.Client
+	key      string
+	identity string
+	ttl      time.Duration
+}
+
+// NewEtcdLeaderElection returns a leader election based on the given key of the etcd cluster
+func NewEtcdLeaderElection(client *clientv3.Client, key, identity string, ttl time.Duration) LeaderElection {
+	return &etcdLeaderElection{client: client, key: key, identity: identity, ttl: ttl}
+}
+
+func (e *etcdLeaderElection) Run(ctx context.Context, onStartedLeading func(ctx context.Context)) error {
+	logCtx := log.WithFields(log.Fields{"key": e.key, "identity": e.identity})
+	// etcd leases have a TTL in seconds, which is rounded up so that the lease does not expire before the TTL
+	lease, err := e.client.Grant(ctx, int64(math.Ceil(e.ttl.Seconds())))
+	if err != nil {
+		return fmt.Errorf("error granting the etcd lease: %w", err)
+	}
+	defer func() {
+		// revoking the lease deletes the key, so that a standby takes over without waiting for the lease to expire
+		revokeCtx, cancel := context.WithTimeout(context.Background(), e.ttl)
+		defer cancel()
+		if _, err := e.client.Revoke(revokeCtx, lease.ID); err != nil {
+			logCtx.Warnf("Failed to revoke the etcd lease: %v", err)
+		}
+	}()
+
+	renewCtx, cancelRenew := context.WithCancel(ctx)
+	defer cancelRenew()
+	lost := make(chan struct{})
+	go e.renew(renewCtx, lease.ID, lost)
+
+	for {
+		elected, revision, err := e.campaign(ctx, lease.ID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if elected {
+			break
+		}
+		logCtx.Info("Waiting for the leader to release the leadership")
+		if err := e.waitForDeletion(ctx, revision, lost); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+
+	logCtx.Info("Elected leader")
+	leaderCtx, cancelLeader := context.WithCancel(ctx)
+	defer cancelLeader()
+	go onStartedLeading(leaderCtx)
+	select {
+	case <-ctx.Done():
+		logCtx.Info("Releasing the leadership")
+		return nil
+	case <-lost:
+		return ErrLeadershipLost
+	}
+}
+
+// campaign puts the key attached to the lease unless another replica holds it, and returns the revision of the key
+// held by the other replica
+func (e *etcdLeaderElection) campaign(ctx context.Context, leaseID clientv3.LeaseID) (bool, int64, error) {
+	resp, err := e.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(e.key), "=", 0)).
+		Then(clientv3.OpPut(e.key, e.identity, clientv3.WithLease(leaseID))).
+		Else(clientv3.OpGet(e.key)).
+		Commit()
+	if err != nil {
+		return false, 0, fmt.Errorf("error campaigning for the leadership: %w", err)
+	}
+	return resp.Succeeded, resp.Header.Revision, nil
+}
+
+// waitForDeletion waits until the key held by another replica at the given revision is deleted
+func (e *etcdLeaderElection) waitForDeletion(ctx context.Context, revision int64, lost <-chan struct{}) error {
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	watchCh := e.client.Watch(watchCtx, e.key, clientv3.WithRev(revision+1), clientv3.WithFilterPut())
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-lost:
+			return ErrLeadershipLost
+		case resp, ok := <-watchCh:
+			if !ok {
+				return ctx.Err()
+			}
+			if err := resp.Err(); err != nil {
+				return fmt.Errorf("error watching the leader: %w", err)
+			}
+			if len(resp.Events) > 0 {
+				return nil
+			}
+		}
+	}
+}
+
+// renew keeps the lease alive until the context is done, and closes the lost channel once the lease expired
+func (e *etcdLeaderElection) renew(ctx context.Context, leaseID clientv3.LeaseID, lost chan<- struct{}) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+	renewedAt := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		_, err := e.client.KeepAliveOnce(ctx, leaseID)
+		switch {
+		case err == nil:
+			renewedAt = time.Now()
+			continue
+		case ctx.Err() != nil:
+			return
+		case errors.Is(err, rpctypes.ErrLeaseNotFound):
+		case time.Since(renewedAt) < e.ttl:
+			log.WithField("key", e.key).Warnf("Failed to renew the etcd lease: %v", err)
+			continue
+		}
+		log.WithField("key", e.key).Errorf("The etcd lease expired: %v", err)
+		close(lost)
+		return
+	}
+}
@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.etcd.io/etcd/server/v3/embed"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testLeaderElectionTTL = 2 * time.Second
+
+func freeLocalURL(t *testing.T) url.URL {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer l.Close()
+	return url.URL{Scheme: "http", Host: l.Addr().String()}
+}
+
+func startEmbeddedEtcd(t *testing.T) *clientv3.Client {
+	t.Helper()
+	cfg := embed.NewConfig()
+	cfg.Dir = t.TempDir()
+	cfg.LogLevel = "error"
+	clientURL, peerURL := freeLocalURL(t), freeLocalURL(t)
+	cfg.ListenClientUrls = []url.URL{clientURL}
+	cfg.AdvertiseClientUrls = []url.URL{clientURL}
+	cfg.ListenPeerUrls = []url.URL{peerURL}
+	cfg.AdvertisePeerUrls = []url.URL{peerURL}
+	cfg.InitialCluster = fmt.Sprintf("%s=%s", cfg.Name, peerURL.String())
+	server, err := embed.StartEtcd(cfg)
+	require.NoError(t, err)
+	t.Cleanup(server.Close)
+	select {
+	case <-server.Server.ReadyNotify():
+	case <-time.After(30 * time.Second):
+		t.Fatal("embedded etcd did not start")
+	}
+
+	client, err := clientv3.New(clientv3.Config{Endpoints: []string{clientURL.String()}, DialTimeout: 5 * time.Second})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+// runLeaderElection runs the leader election in the background and returns a channel receiving the context of the
+// leader once elected and a channel receiving the result of Run
+func runLeaderElection(ctx context.Context, election LeaderElection) (<-chan context.Context, <-chan error) {
+	leading := make(chan context.Context, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- election.Run(ctx, func(ctx context.Context) {
+			leading <- ctx
+		})
+	}()
+	return leading, done
+}
+
+func TestEtcdLeaderElection(t *testing.T) {
+	client := startEmbeddedEtcd(t)
+	key := "/argocd/application-controller/leader"
+
+	t.Run("standby takes over once the leader releases the leadership", func(t *testing.T) {
+		ctx1, cancel1 := context.WithCancel(context.Background())
+		defer cancel1()
+		leading1, done1 := runLeaderElection(ctx1, NewEtcdLeaderElection(client, key, "replica-1", testLeaderElectionTTL))
+		var leaderCtx context.Context
+		select {
+		case leaderCtx = <-leading1:
+		case <-time.After(10 * time.Second):
+			t.Fatal("first replica was not elected")
+		}
+		resp, err := client.Get(context.Background(), key)
+		require.NoError(t, err)
+		require.Len(t, resp.Kvs, 1)
+		assert.Equal(t, "replica-1", string(resp.Kvs[0].Value))
+
+		ctx2, cancel2 := context.WithCancel(context.Background())
+		defer cancel2()
+		leading2, done2 := runLeaderElection(ctx2, NewEtcdLeaderElection(client, key, "replica-2", testLeaderElectionTTL))
+		// the leadership is renewed, so the standby is not elected after the TTL
+		select {
+		case <-leading2:
+			t.Fatal("second replica was elected while the first one leads")
+		case <-time.After(2 * testLeaderElectionTTL):
+		}
+
+		cancel1()
+		require.NoError(t, <-done1)
+		assert.Error(t, leaderCtx.Err())
+		// the leadership was released, so the standby does not wait for the lease to expire
+		select {
+		case <-leading2:
+		case <-time.After(testLeaderElectionTTL / 2):
+			t.Fatal("second replica was not elected once the first one released the leadership")
+		}
+
+		cancel2()
+		require.NoError(t, <-done2)
+		resp, err = client.Get(context.Background(), key)
+		require.NoError(t, err)
+		assert.Empty(t, resp.Kvs)
+	})
+
+	t.Run("lease TTL rounded up to whole seconds", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		leading, done := runLeaderElection(ctx, NewEtcdLeaderElection(client, key, "replica-1", 1500*time.Millisecond))
+		select {
+		case <-leading:
+		case <-time.After(10 * time.Second):
+			t.Fatal("replica was not elected")
+		}
+		resp, err := client.Get(context.Background(), key)
+		require.NoError(t, err)
+		require.Len(t, resp.Kvs, 1)
+		ttl, err := client.TimeToLive(context.Background(), clientv3.LeaseID(resp.Kvs[0].Lease))
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), ttl.GrantedTTL)
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("leadership lost once the lease expired", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		leading, done := runLeaderElection(ctx, NewEtcdLeaderElection(client, key, "replica-1", testLeaderElectionTTL))
+		var leaderCtx context.Context
+		select {
+		case leaderCtx = <-leading:
+		case <-time.After(10 * time.Second):
+			t.Fatal("replica was not elected")
+		}
+
+		resp, err := client.Get(context.Background(), key)
+		require.NoError(t, err)
+		require.Len(t, resp.Kvs, 1)
+		_, err = client.Revoke(context.Background(), clientv3.LeaseID(resp.Kvs[0].Lease))
+		require.NoError(t, err)
+
+		select {
+		case err := <-done:
+			require.ErrorIs(t, err, ErrLeadershipLost)
+		case <-time.After(10 * time.Second):
+			t.Fatal("leadership was not lost")
+		}
+		assert.Error(t, leaderCtx.Err())
+	})
+}
+
+func TestKubernetesLeaderElection(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	leading, done := runLeaderElection(ctx, NewKubernetesLeaderElection(kubeClient, "argocd", "argocd-application-controller", "replica-1", testLeaderElectionTTL))
+	select {
+	case <-leading:
+	case <-time.After(10 * time.Second):
+		t.Fatal("replica was not elected")
+	}
+	lease, err := kubeClient.CoordinationV1().Leases("argocd").Get(context.Background(), "argocd-application-controller", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "replica-1", *lease.Spec.HolderIdentity)
+
+	cancel()
+	require.NoError(t, <-done)
+	lease, err = kubeClient.CoordinationV1().Leases("argocd").Get(context.Background(), "argocd-application-controller", metav1.GetOptions{})
+	require.NoError(t, err)
+	// the leadership is released on shutdown
+	assert.Empty(t, *lease.Spec.HolderIdentity)
+}
@@ -0,0 +1,394 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+	"k8s.io/apimachinery/pkg/labels"
+	"oras.land/oras-go/v2/registry/remote"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	applister "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/glob"
+)
+
+const (
+	// EnvVarImageUpdateCheckInterval is an environment variable which controls how often the registry of each image
+	// managed by Argo CD Image Updater is checked for new versions, 0 disables the check
+	EnvVarImageUpdateCheckInterval = "ARGOCD_IMAGE_UPDATE_CHECK_INTERVAL"
+	// DefaultImageUpdateCheckInterval is the default interval between two checks of the registry of an image
+	DefaultImageUpdateCheckInterval = 10 * time.Minute
+
+	imageUpdaterAnnotationPrefix    = "argocd-image-updater.argoproj.io/"
+	imageUpdaterImageListAnnotation = imageUpdaterAnnotationPrefix + "image-list"
+
+	imageUpdateStrategySemver       = "semver"
+	imageUpdateStrategyName         = "name"
+	imageUpdateStrategyAlphabetical = "alphabetical"
+
+	// registry requests are limited to imageUpdateRegistryQPS per second, across all registries
+	imageUpdateRegistryQPS   = 1
+	imageUpdateRegistryBurst = 5
+)
+
+var (
+	descImageUpdatePendingCount = prometheus.NewDesc(
+		"argocd_image_update_pending_count",
+		"Number of images managed by Argo CD Image Updater with a newer version available in the registry.",
+		descAppDefaultLabels,
+		nil,
+	)
+	descImageUpdateLastUpdateTimestamp = prometheus.NewDesc(
+		"argocd_image_update_last_update_timestamp",
+		"Unix timestamp of the last update of an image managed by Argo CD Image Updater.",
+		append(descAppDefaultLabels, "image"),
+		nil,
+	)
+)
+
+// ImageTagLister lists the tags of the repository of an image
+type ImageTagLister interface {
+	ListTags(ctx context.Context, image string) ([]string, error)
+}
+
+type registryTagLister struct {
+	// plainHTTP accesses registries over HTTP instead of HTTPS
+	plainHTTP bool
+}
+
+// NewRegistryTagLister returns an ImageTagLister which lists the tags of images anonymously, using the distribution API
+// of their registry
+func NewRegistryTagLister() ImageTagLister {
+	return &registryTagLister{}
+}
+
+func (l *registryTagLister) ListTags(ctx context.Context, image string) ([]string, error) {
+	reference := image
+	if strings.HasPrefix(reference, "docker.io/") {
+		// docker.io is not the host of the registry API of Docker Hub
+		reference = "registry-1." + reference
+	}
+	repo, err := remote.NewRepository(reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize repository %s: %w", image, err)
+	}
+	repo.PlainHTTP = l.plainHTTP
+	var tags []string
+	err = repo.Tags(ctx, "", func(result []string) error {
+		tags = append(tags, result...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags of %s: %w", image, err)
+	}
+	return tags, nil
+}
+
+// managedImage is an image of an application which is updated by Argo CD Image Updater
+type managedImage struct {
+	// name is the normalized name of the image, without tag
+	name       string
+	constraint string
+	strategy   string
+	allowTags  *regexp.Regexp
+	ignoreTags []string
+}
+
+// parseManagedImages returns the images listed in the Argo CD Image Updater annotations of the application. Images
+// with an update strategy which does not only depend on the tag names are ignored.
+func parseManagedImages(app *argoappv1.Application) []managedImage {
+	annotations := app.GetAnnotations()
+	var images []managedImage
+	for _, entry := range strings.Split(annotations[imageUpdaterImageListAnnotation], ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		alias, reference, found := strings.Cut(entry, "=")
+		if !found {
+			alias, reference = "", entry
+		}
+		name, constraint := splitImageTag(reference)
+		image := managedImage{name: normalizeImageName(name), constraint: constraint, strategy: imageUpdateStrategySemver}
+		// per image options can only be set on aliased images
+		if alias != "" {
+			option := func(name string) string {
+				return strings.TrimSpace(annotations[imageUpdaterAnnotationPrefix+alias+"."+name])
+			}
+			if strategy := option("update-strategy"); strategy != "" {
+				image.strategy = strategy
+			}
+			if allowTags := option("allow-tags"); strings.HasPrefix(allowTags, "regexp:") {
+				re, err := regexp.Compile(strings.TrimPrefix(allowTags, "regexp:"))
+				if err != nil {
+					log.WithField("application", app.QualifiedName()).Warnf("Invalid allow-tags of image %s: %v", image.name, err)
+					continue
+				}
+				image.allowTags = re
+			}
+			for _, pattern := range strings.Split(option("ignore-tags"), ",") {
+				if pattern = strings.TrimSpace(pattern); pattern != "" {
+					image.ignoreTags = append(image.ignoreTags, pattern)
+				}
+			}
+		}
+		switch image.strategy {
+		case imageUpdateStrategySemver, imageUpdateStrategyName, imageUpdateStrategyAlphabetical:
+			images = append(images, image)
+		default:
+			log.WithField("application", app.QualifiedName()).Debugf("Update strategy %s of image %s is not supported by the image update metrics", image.strategy, image.name)
+		}
+	}
+	return images
+}
+
+// splitImageTag splits an image reference into its name and tag, ignoring the digest
+func splitImageTag(reference string) (string, string) {
+	reference, _, _ = strings.Cut(reference, "@")
+	i := strings.LastIndex(reference, ":")
+	if i < 0 || strings.Contains(reference[i:], "/") {
+		return reference, ""
+	}
+	return reference[:i], reference[i+1:]
+}
+
+// normalizeImageName returns the fully qualified name of an image, e.g. docker.io/library/nginx for nginx
+func normalizeImageName(name string) string {
+	first, _, found := strings.Cut(name, "/")
+	if !found {
+		return "docker.io/library/" + name
+	}
+	if !strings.ContainsAny(first, ".:") && first != "localhost" {
+		return "docker.io/" + name
+	}
+	return name
+}
+
+// deployedTag returns the tag of the image deployed by the application, or false if the image is not deployed
+func deployedTag(app *argoappv1.Application, name string) (string, bool) {
+	for _, image := range app.Status.Summary.Images {
+		imageName, tag := splitImageTag(image)
+		if normalizeImageName(imageName) == name {
+			return tag, true
+		}
+	}
+	return "", false
+}
+
+// hasNewerTag returns true if one of the tags allowed for the image is a newer version than the current tag
+func (i managedImage) hasNewerTag(current string, tags []string) bool {
+	var candidates []string
+	for _, tag := range tags {
+		if i.allowTags != nil && !i.allowTags.MatchString(tag) {
+			continue
+		}
+		if glob.MatchStringInList(i.ignoreTags, tag, glob.GLOB) {
+			continue
+		}
+		candidates = append(candidates, tag)
+	}
+	if i.strategy != imageUpdateStrategySemver {
+		sort.Strings(candidates)
+		return len(candidates) > 0 && candidates[len(candidates)-1] > current
+	}
+
+	currentVersion, err := semver.NewVersion(current)
+	if err != nil {
+		return false
+	}
+	var constraint *semver.Constraints
+	if i.constraint != "" && i.constraint != "latest" {
+		if constraint, err = semver.NewConstraint(i.constraint); err != nil {
+			return false
+		}
+	}
+	for _, tag := range candidates {
+		version, err := semver.NewVersion(tag)
+		if err != nil || (constraint != nil && !constraint.Check(version)) {
+			continue
+		}
+		if version.GreaterThan(currentVersion) {
+			return true
+		}
+	}
+	return false
+}
+
+type imageTags struct {
+	tags      []string
+	checkedAt time.Time
+}
+
+type deployedImage struct {
+	tag       string
+	updatedAt time.Time
+}
+
+type imageUpdateInfo struct {
+	app          *argoappv1.Application
+	pendingCount int
+	// lastUpdates are the times of the last updates of the images of the application, by image name
+	lastUpdates map[string]time.Time
+}
+
+// imageUpdateCollector reports the pending updates of the images of applications managed by Argo CD Image Updater.
+// The registry of each image is checked at most once per check interval, and registry requests are rate limited.
+type imageUpdateCollector struct {
+	store         applister.ApplicationLister
+	appFilter     func(obj interface{}) bool
+	tagLister     ImageTagLister
+	checkInterval time.Duration
+	limiter       *rate.Limiter
+	now           func() time.Time
+
+	// tags are the tags of the image repositories, by image name
+	tags map[string]imageTags
+	// deployed are the deployed images of the applications, by application and image name
+	deployed map[string]deployedImage
+
+	lock sync.Mutex
+	info []imageUpdateInfo
+}
+
+func newImageUpdateCollector(appLister applister.ApplicationLister, appFilter func(obj interface{}) bool, tagLister ImageTagLister, checkInterval time.Duration) *imageUpdateCollector {
+	return &imageUpdateCollector{
+		store:         appLister,
+		appFilter:     appFilter,
+		tagLister:     tagLister,
+		checkInterval: checkInterval,
+		limiter:       rate.NewLimiter(imageUpdateRegistryQPS, imageUpdateRegistryBurst),
+		now:           time.Now,
+		tags:          map[string]imageTags{},
+		deployed:      map[string]deployedImage{},
+	}
+}
+
+func (c *imageUpdateCollector) Run(ctx context.Context) {
+	ticker := time.NewTicker(metricsCollectionInterval)
+	defer ticker.Stop()
+	for {
+		c.refresh(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// getTags returns the tags of the image repository, from the registry if they were not checked within the check interval
+func (c *imageUpdateCollector) getTags(ctx context.Context, name string) ([]string, error) {
+	cached, ok := c.tags[name]
+	if ok && c.now().Sub(cached.checkedAt) < c.checkInterval {
+		return cached.tags, nil
+	}
+	if err := c.limiter.Wait(ctx); err != nil {
+		return cached.tags, err
+	}
+	tags, err := c.tagLister.ListTags(ctx, name)
+	if err != nil {
+		// failed checks are not retried before the end of the check interval either
+		c.tags[name] = imageTags{tags: cached.tags, checkedAt: c.now()}
+		return cached.tags, err
+	}
+	c.tags[name] = imageTags{tags: tags, checkedAt: c.now()}
+	return tags, nil
+}
+
+// refresh computes the pending image updates of the applications
+func (c *imageUpdateCollector) refresh(ctx context.Context) {
+	apps, err := c.store.List(labels.NewSelector())
+	if err != nil {
+		log.Warnf("Failed to collect applications: %v", err)
+		return
+	}
+	var info []imageUpdateInfo
+	seenImages := map[string]bool{}
+	seenDeployed := map[string]bool{}
+	for _, app := range apps {
+		if !c.appFilter(app) {
+			continue
+		}
+		images := parseManagedImages(app)
+		if len(images) == 0 {
+			continue
+		}
+		appInfo := imageUpdateInfo{app: app, lastUpdates: map[string]time.Time{}}
+		for _, image := range images {
+			current, ok := deployedTag(app, image.name)
+			if !ok {
+				continue
+			}
+			seenImages[image.name] = true
+
+			key := app.QualifiedName() + "/" + image.name
+			seenDeployed[key] = true
+			deployed, ok := c.deployed[key]
+			switch {
+			case !ok:
+				// the time of the update which deployed the current tag is only known if it was the last sync
+				deployed = deployedImage{tag: current}
+				if app.Status.OperationState != nil && app.Status.OperationState.FinishedAt != nil {
+					deployed.updatedAt = app.Status.OperationState.FinishedAt.Time
+				}
+			case deployed.tag != current:
+				deployed = deployedImage{tag: current, updatedAt: c.now()}
+			}
+			c.deployed[key] = deployed
+			if !deployed.updatedAt.IsZero() {
+				appInfo.lastUpdates[image.name] = deployed.updatedAt
+			}
+
+			tags, err := c.getTags(ctx, image.name)
+			if err != nil {
+				log.WithField("application", app.QualifiedName()).Warnf("Failed to check image %s for updates: %v", image.name, err)
+			}
+			if image.hasNewerTag(current, tags) {
+				appInfo.pendingCount++
+			}
+		}
+		info = append(info, appInfo)
+	}
+	for name := range c.tags {
+		if !seenImages[name] {
+			delete(c.tags, name)
+		}
+	}
+	for key := range c.deployed {
+		if !seenDeployed[key] {
+			delete(c.deployed, key)
+		}
+	}
+
+	c.lock.Lock()
+	c.info = info
+	c.lock.Unlock()
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *imageUpdateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descImageUpdatePendingCount
+	ch <- descImageUpdateLastUpdateTimestamp
+}
+
+// Collect implements the prometheus.Collector interface
+func (c *imageUpdateCollector) Collect(ch chan<- prometheus.Metric) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, info := range c.info {
+		defaultValues := []string{info.app.Namespace, info.app.Name, info.app.Spec.GetProject()}
+		ch <- prometheus.MustNewConstMetric(descImageUpdatePendingCount, prometheus.GaugeValue, float64(info.pendingCount), defaultValues...)
+		for image, updatedAt := range info.lastUpdates {
+			ch <- prometheus.MustNewConstMetric(descImageUpdateLastUpdateTimestamp, prometheus.GaugeValue, float64(updatedAt.Unix()), append(defaultValues, image)...)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package metrics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// newMockRegistry returns a registry serving the tags of the given repositories, and counting the tag list requests
+func newMockRegistry(t *testing.T, tags map[string][]string) (*httptest.Server, *int32) {
+	t.Helper()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v2/" {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		repo, found := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/v2/"), "/tags/list")
+		if found {
+			atomic.AddInt32(&requests, 1)
+		}
+		if !found || tags[repo] == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": repo, "tags": tags[repo]})
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+const fakeImageUpdaterApp = `
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: guestbook
+  namespace: argocd
+  annotations:
+    argocd-image-updater.argoproj.io/image-list: app=%[1]s/org/guestbook:1.x, ui=%[1]s/org/ui, db=%[1]s/org/db
+    argocd-image-updater.argoproj.io/ui.update-strategy: name
+    argocd-image-updater.argoproj.io/ui.ignore-tags: nightly-*
+    argocd-image-updater.argoproj.io/db.update-strategy: digest
+spec:
+  destination:
+    namespace: dummy-namespace
+    server: https://localhost:6443
+  project: important-project
+  source:
+    path: some/path
+    repoURL: https://github.com/argoproj/argocd-example-apps.git
+status:
+  summary:
+    images:
+    - %[1]s/org/guestbook:1.0.0
+    - %[1]s/org/ui:2024-01-01
+    - %[1]s/org/db:1.0.0
+  operationState:
+    phase: Succeeded
+    startedAt: "2024-01-01T00:00:00Z"
+    finishedAt: "2024-01-01T00:01:00Z"
+`
+
+func TestParseManagedImages(t *testing.T) {
+	app := &argoappv1.Application{ObjectMeta: metav1.ObjectMeta{
+		Name: "guestbook",
+		Annotations: map[string]string{
+			imageUpdaterImageListAnnotation:                        "app=org/guestbook:~1.2, nginx, ui=ghcr.io/org/ui@sha256:abc, db=localhost:5000/db, cache=redis",
+			imageUpdaterAnnotationPrefix + "ui.allow-tags":         "regexp:^v[0-9]+$",
+			imageUpdaterAnnotationPrefix + "db.ignore-tags":        "latest, dev-*",
+			imageUpdaterAnnotationPrefix + "cache.update-strategy": "latest",
+		},
+	}}
+
+	images := parseManagedImages(app)
+	require.Len(t, images, 4)
+	assert.Equal(t, "docker.io/org/guestbook", images[0].name)
+	assert.Equal(t, "~1.2", images[0].constraint)
+	assert.Equal(t, imageUpdateStrategySemver, images[0].strategy)
+	assert.Equal(t, "docker.io/library/nginx", images[1].name)
+	assert.Equal(t, "ghcr.io/org/ui", images[2].name)
+	require.NotNil(t, images[2].allowTags)
+	assert.True(t, images[2].allowTags.MatchString("v2"))
+	assert.Equal(t, "localhost:5000/db", images[3].name)
+	assert.Empty(t, images[3].constraint)
+	assert.Equal(t, []string{"latest", "dev-*"}, images[3].ignoreTags)
+}
+
+func TestManagedImage_HasNewerTag(t *testing.T) {
+	tags := []string{"1.0.0", "1.1.0", "2.0.0", "latest"}
+	assert.True(t, managedImage{strategy: imageUpdateStrategySemver}.hasNewerTag("1.1.0", tags))
+	assert.True(t, managedImage{strategy: imageUpdateStrategySemver, constraint: "1.x"}.hasNewerTag("1.0.0", tags))
+	assert.False(t, managedImage{strategy: imageUpdateStrategySemver, constraint: "1.x"}.hasNewerTag("1.1.0", tags))
+	assert.False(t, managedImage{strategy: imageUpdateStrategySemver, ignoreTags: []string{"2.*"}}.hasNewerTag("1.1.0", tags))
+	assert.False(t, managedImage{strategy: imageUpdateStrategySemver}.hasNewerTag("latest", tags))
+	assert.True(t, managedImage{strategy: imageUpdateStrategyName}.hasNewerTag("2024-01-01", []string{"2024-01-01", "2024-02-01"}))
+	assert.False(t, managedImage{strategy: imageUpdateStrategyName}.hasNewerTag("2024-02-01", []string{"2024-01-01", "2024-02-01"}))
+}
+
+func TestImageUpdateMetrics(t *testing.T) {
+	registry, requests := newMockRegistry(t, map[string][]string{
+		"org/guestbook": {"1.0.0", "1.1.0", "2.0.0"},
+		"org/ui":        {"2024-01-01", "2024-02-01", "nightly-2024-03-01"},
+		"org/db":        {"1.0.0", "1.1.0"},
+	})
+	host := strings.TrimPrefix(registry.URL, "http://")
+
+	cancel, appLister := newFakeLister(fmt.Sprintf(fakeImageUpdaterApp, host))
+	defer cancel()
+	metricsServ, err := NewMetricsServer("localhost:8082", appLister, appFilter, noOpHealthCheck, []string{})
+	require.NoError(t, err)
+
+	collector := newImageUpdateCollector(appLister, appFilter, &registryTagLister{plainHTTP: true}, time.Hour)
+	metricsServ.registry.MustRegister(collector)
+	collector.refresh(context.Background())
+	// images with an unsupported update strategy are not checked
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	metricsServ.Handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assertMetricsPrinted(t, fmt.Sprintf(`
+# HELP argocd_image_update_pending_count Number of images managed by Argo CD Image Updater with a newer version available in the registry.
+# TYPE argocd_image_update_pending_count gauge
+argocd_image_update_pending_count{name="guestbook",namespace="argocd",project="important-project"} 2
+# HELP argocd_image_update_last_update_timestamp Unix timestamp of the last update of an image managed by Argo CD Image Updater.
+# TYPE argocd_image_update_last_update_timestamp gauge
+argocd_image_update_last_update_timestamp{image="%[1]s/org/guestbook",name="guestbook",namespace="argocd",project="important-project"} 1.70406726e+09
+`, host), rr.Body.String())
+
+	// the registry is not checked again within the check interval
+	collector.refresh(context.Background())
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+
+	collector.now = func() time.Time {
+		return time.Now().Add(2 * time.Hour)
+	}
+	collector.refresh(context.Background())
+	assert.Equal(t, int32(4), atomic.LoadInt32(requests))
+}
+
+func TestImageUpdateMetrics_RegistryError(t *testing.T) {
+	registry, requests := newMockRegistry(t, map[string][]string{})
+	host := strings.TrimPrefix(registry.URL, "http://")
+
+	cancel, appLister := newFakeLister(fmt.Sprintf(fakeImageUpdaterApp, host))
+	defer cancel()
+	collector := newImageUpdateCollector(appLister, appFilter, &registryTagLister{plainHTTP: true}, time.Hour)
+	collector.refresh(context.Background())
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+	require.Len(t, collector.info, 1)
+	assert.Equal(t, 0, collector.info[0].pendingCount)
+
+	// failed checks are not retried within the check interval either
+	collector.refresh(context.Background())
+	assert.Equal(t, int32(2), atomic.LoadInt32(requests))
+}
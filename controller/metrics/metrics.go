@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/rand"
 	"net/http"
 	"os"
 	"regexp"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/argoproj/gitops-engine/pkg/health"
@@ -29,24 +31,70 @@ import (
 
 type MetricsServer struct {
 	*http.Server
-	syncCounter             *prometheus.CounterVec
-	kubectlExecCounter      *prometheus.CounterVec
-	kubectlExecPendingGauge *prometheus.GaugeVec
-	k8sRequestCounter       *prometheus.CounterVec
-	clusterEventsCounter    *prometheus.CounterVec
-	redisRequestCounter     *prometheus.CounterVec
-	reconcileHistogram      *prometheus.HistogramVec
-	redisRequestHistogram   *prometheus.HistogramVec
-	registry                *prometheus.Registry
-	hostname                string
-	cron                    *cron.Cron
+	syncCounter                   *prometheus.CounterVec
+	syncErrorCounter              *prometheus.CounterVec
+	rollbackCounter               *prometheus.CounterVec
+	kubectlExecCounter            *prometheus.CounterVec
+	kubectlExecPendingGauge       *prometheus.GaugeVec
+	k8sRequestCounter             *prometheus.CounterVec
+	applyThrottledCounter         *prometheus.CounterVec
+	clusterEventsCounter          *prometheus.CounterVec
+	redisRequestCounter           *prometheus.CounterVec
+	reconcileHistogram            *prometheus.HistogramVec
+	redisRequestHistogram         *prometheus.HistogramVec
+	cacheOversizedCounter         *prometheus.CounterVec
+	eventsDedupCounter            *prometheus.CounterVec
+	eventsFilteredCounter         prometheus.Counter
+	datadogSentCounter            *prometheus.CounterVec
+	datadogFailedCounter          *prometheus.CounterVec
+	historyPrunedCounter          *prometheus.CounterVec
+	reconcilePanicCounter         *prometheus.CounterVec
+	workStolenCounter             *prometheus.CounterVec
+	clusterDisconnectGraceCounter *prometheus.CounterVec
+	queueHealthFailuresCounter    prometheus.Counter
+	syncPatternRetryCounter       *prometheus.CounterVec
+	orphanedResources             *orphanedResourcesCollector
+	resourceCounts                *resourceCountCollector
+	registry                      *prometheus.Registry
+	mux                           *http.ServeMux
+	appLister                     applister.ApplicationLister
+	appFilter                     func(obj interface{}) bool
+	hostname                      string
+	cron                          *cron.Cron
+	sampler                       *sampler
+}
+
+// sampler randomly selects the recordings of the metrics of the reconcile loop which are sampled
+type sampler struct {
+	ratio float64
+	lock  sync.Mutex
+	rand  *rand.Rand
+}
+
+func newSampler(ratio float64, source rand.Source) *sampler {
+	return &sampler{ratio: ratio, rand: rand.New(source)}
+}
+
+// sample returns true if the recording should be kept, together with the weight of the recording which compensates
+// for the dropped ones, so that the rates of the sampled counters are estimates of the actual rates
+func (s *sampler) sample() (bool, float64) {
+	if s == nil || s.ratio >= 1 {
+		return true, 1
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.rand.Float64() < s.ratio, 1 / s.ratio
 }
 
 const (
 	// MetricsPath is the endpoint to collect application metrics
 	MetricsPath = "/metrics"
+	// QueueHealthPath is the endpoint of the health check of the reconcile queue
+	QueueHealthPath = "/healthz/queue"
 	// EnvVarLegacyControllerMetrics is a env var to re-enable deprecated prometheus metrics
 	EnvVarLegacyControllerMetrics = "ARGOCD_LEGACY_CONTROLLER_METRICS"
+	// DefaultSamplingRatio is the default ratio of the recordings of the reconcile loop metrics which are kept
+	DefaultSamplingRatio = 1.0
 )
 
 // Follow Prometheus naming practices
@@ -62,6 +110,12 @@ var (
 		append(descAppDefaultLabels, "autosync_enabled", "repo", "dest_server", "dest_namespace", "sync_status", "health_status", "operation"),
 		nil,
 	)
+	descAppHelmChartVersionInfo = prometheus.NewDesc(
+		"argocd_app_helm_chart_version_info",
+		"Information about the Helm chart versions deployed by applications.",
+		[]string{"chart", "version", "app_name", "project", "dest_cluster"},
+		nil,
+	)
 	// Deprecated
 	descAppCreated = prometheus.NewDesc(
 		"argocd_app_created_time",
@@ -92,6 +146,22 @@ var (
 		append(descAppDefaultLabels, "dest_server", "phase"),
 	)
 
+	syncErrorCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_sync_error_total",
+			Help: "Number of failed application syncs, by error code.",
+		},
+		append(descAppDefaultLabels, "dest_server", "code"),
+	)
+
+	rollbackCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_app_rollback_total",
+			Help: "Number of automatic rollbacks of applications which became degraded after a sync.",
+		},
+		append(descAppDefaultLabels, "dest_server"),
+	)
+
 	k8sRequestCounter = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
 			Name: "argocd_app_k8s_request_total",
@@ -100,6 +170,14 @@ var (
 		append(descAppDefaultLabels, "server", "response_code", "verb", "resource_kind", "resource_namespace"),
 	)
 
+	applyThrottledCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_resource_apply_throttled_total",
+			Help: "Number of kubernetes requests modifying resources which were delayed by the apply rate limit during application syncs.",
+		},
+		append(descAppDefaultLabels, "server"),
+	)
+
 	kubectlExecCounter = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "argocd_kubectl_exec_total",
 		Help: "Number of kubectl executions",
@@ -133,6 +211,92 @@ var (
 		[]string{"hostname", "initiator", "failed"},
 	)
 
+	cacheOversizedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_cache_oversized_items_total",
+			Help: "Number of cache items not stored in the in-memory cache because they exceed its maximum item size.",
+		},
+		[]string{"hostname"},
+	)
+
+	eventsDedupCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_events_deduplicated_total",
+			Help: "Number of Kubernetes events not emitted because the same event was emitted within the deduplication window.",
+		},
+		[]string{"reason"},
+	)
+
+	eventsFilteredCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "argocd_events_filtered_total",
+			Help: "Number of resource events not handled because a later event of the same resource was received within the event filter interval.",
+		},
+	)
+
+	datadogSentCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_datadog_events_sent_total",
+			Help: "Number of sync results sent to Datadog as events.",
+		},
+		append(descAppDefaultLabels, "event_type"),
+	)
+
+	datadogFailedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_datadog_events_failed_total",
+			Help: "Number of sync results which could not be sent to Datadog as events.",
+		},
+		append(descAppDefaultLabels, "event_type"),
+	)
+
+	historyPrunedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_app_history_pruned_total",
+			Help: "Number of sync history entries removed by the history retention policy of the project.",
+		},
+		descAppDefaultLabels,
+	)
+
+	reconcilePanicCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_controller_reconcile_panic_total",
+			Help: "Number of application reconciliations which panicked.",
+		},
+		descAppDefaultLabels,
+	)
+
+	workStolenCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_work_stolen_total",
+			Help: "Number of application reconciliations stolen from the queues of other controller shards.",
+		},
+		[]string{"owner_shard"},
+	)
+
+	clusterDisconnectGraceCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_cluster_disconnect_grace_total",
+			Help: "Number of application reconciliations which kept the last known status because the cluster was unreachable for less than the grace period.",
+		},
+		[]string{"server"},
+	)
+
+	queueHealthFailuresCounter = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "argocd_queue_health_check_failures_total",
+			Help: "Number of failed health checks of the reconcile queue, because the queued applications were not processed within the queue health check window.",
+		},
+	)
+
+	syncPatternRetryCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_sync_pattern_retry_total",
+			Help: "Number of retries of failed syncs whose error matched a retry pattern of the application.",
+		},
+		[]string{"pattern"},
+	)
+
 	redisRequestHistogram = prometheus.NewHistogramVec(
 		prometheus.HistogramOpts{
 			Name:    "argocd_redis_request_duration",
@@ -173,29 +337,66 @@ func NewMetricsServer(addr string, appLister applister.ApplicationLister, appFil
 	healthz.ServeHealthCheck(mux, healthCheck)
 
 	registry.MustRegister(syncCounter)
+	registry.MustRegister(syncErrorCounter)
+	registry.MustRegister(rollbackCounter)
 	registry.MustRegister(k8sRequestCounter)
+	registry.MustRegister(applyThrottledCounter)
 	registry.MustRegister(kubectlExecCounter)
 	registry.MustRegister(kubectlExecPendingGauge)
 	registry.MustRegister(reconcileHistogram)
 	registry.MustRegister(clusterEventsCounter)
 	registry.MustRegister(redisRequestCounter)
 	registry.MustRegister(redisRequestHistogram)
+	registry.MustRegister(cacheOversizedCounter)
+	registry.MustRegister(eventsDedupCounter)
+	registry.MustRegister(eventsFilteredCounter)
+	registry.MustRegister(datadogSentCounter)
+	registry.MustRegister(datadogFailedCounter)
+	registry.MustRegister(historyPrunedCounter)
+	registry.MustRegister(reconcilePanicCounter)
+	registry.MustRegister(workStolenCounter)
+	registry.MustRegister(clusterDisconnectGraceCounter)
+	registry.MustRegister(queueHealthFailuresCounter)
+	registry.MustRegister(syncPatternRetryCounter)
+	orphanedResources := newOrphanedResourcesCollector(appLister, appFilter)
+	registry.MustRegister(orphanedResources)
+	resourceCounts := newResourceCountCollector(appLister, appFilter)
+	registry.MustRegister(resourceCounts)
 
 	return &MetricsServer{
 		registry: registry,
+		mux:      mux,
 		Server: &http.Server{
 			Addr:    addr,
 			Handler: mux,
 		},
-		syncCounter:             syncCounter,
-		k8sRequestCounter:       k8sRequestCounter,
-		kubectlExecCounter:      kubectlExecCounter,
-		kubectlExecPendingGauge: kubectlExecPendingGauge,
-		reconcileHistogram:      reconcileHistogram,
-		clusterEventsCounter:    clusterEventsCounter,
-		redisRequestCounter:     redisRequestCounter,
-		redisRequestHistogram:   redisRequestHistogram,
-		hostname:                hostname,
+		syncCounter:                   syncCounter,
+		syncErrorCounter:              syncErrorCounter,
+		rollbackCounter:               rollbackCounter,
+		k8sRequestCounter:             k8sRequestCounter,
+		applyThrottledCounter:         applyThrottledCounter,
+		kubectlExecCounter:            kubectlExecCounter,
+		kubectlExecPendingGauge:       kubectlExecPendingGauge,
+		reconcileHistogram:            reconcileHistogram,
+		clusterEventsCounter:          clusterEventsCounter,
+		redisRequestCounter:           redisRequestCounter,
+		redisRequestHistogram:         redisRequestHistogram,
+		cacheOversizedCounter:         cacheOversizedCounter,
+		eventsDedupCounter:            eventsDedupCounter,
+		eventsFilteredCounter:         eventsFilteredCounter,
+		datadogSentCounter:            datadogSentCounter,
+		datadogFailedCounter:          datadogFailedCounter,
+		historyPrunedCounter:          historyPrunedCounter,
+		reconcilePanicCounter:         reconcilePanicCounter,
+		workStolenCounter:             workStolenCounter,
+		clusterDisconnectGraceCounter: clusterDisconnectGraceCounter,
+		queueHealthFailuresCounter:    queueHealthFailuresCounter,
+		syncPatternRetryCounter:       syncPatternRetryCounter,
+		orphanedResources:             orphanedResources,
+		resourceCounts:                resourceCounts,
+		appLister:                     appLister,
+		appFilter:                     appFilter,
+		hostname:                      hostname,
 		// This cron is used to expire the metrics cache.
 		// Currently clearing the metrics cache is logging and deleting from the map
 		// so there is no possibility of panic, but we will add a chain to keep robfig/cron v1 behavior.
@@ -223,12 +424,106 @@ func (m *MetricsServer) RegisterClustersInfoSource(ctx context.Context, source H
 	m.registry.MustRegister(collector)
 }
 
-// IncSync increments the sync counter for an application
+// RegisterClusterAppHealth registers the collector of the number of applications per health status of each cluster
+func (m *MetricsServer) RegisterClusterAppHealth(collector prometheus.Collector) {
+	m.registry.MustRegister(collector)
+}
+
+// RegisterImageUpdateCollector registers the metrics of the pending image updates of applications managed by Argo CD
+// Image Updater. The registry of each image is checked at most once per checkInterval.
+func (m *MetricsServer) RegisterImageUpdateCollector(ctx context.Context, tagLister ImageTagLister, checkInterval time.Duration) {
+	collector := newImageUpdateCollector(m.appLister, m.appFilter, tagLister, checkInterval)
+	go collector.Run(ctx)
+	m.registry.MustRegister(collector)
+}
+
+// RegisterRetryQueueDepth registers the number of applications waiting in the operation queue after their last
+// operation failed or timed out, as returned by depth
+func (m *MetricsServer) RegisterRetryQueueDepth(depth func() int) {
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "argocd_controller_retry_queue_depth",
+		Help: "Number of applications waiting in the operation queue after their last operation failed or timed out.",
+	}, func() float64 {
+		return float64(depth())
+	}))
+}
+
+// RegisterQueueHealthCheck serves the health check of the reconcile queue on /healthz/queue, which fails if check
+// returns an error. The failed checks are counted by argocd_queue_health_check_failures_total.
+func (m *MetricsServer) RegisterQueueHealthCheck(check func() error) {
+	healthz.ServeHealthCheckPath(m.mux, QueueHealthPath, func(r *http.Request) error {
+		err := check()
+		if err != nil {
+			m.queueHealthFailuresCounter.Inc()
+		}
+		return err
+	})
+}
+
+// RegisterTwoLevelCacheStats registers the number of reads answered and not answered by the in-memory layer of the
+// two-level cache, as returned by stats, and the resulting hit ratio
+func (m *MetricsServer) RegisterTwoLevelCacheStats(stats func() (hits int64, misses int64)) {
+	m.registry.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Name: "argocd_two_level_cache_l1_hits_total",
+		Help: "Number of cache reads answered by the in-memory cache.",
+	}, func() float64 {
+		hits, _ := stats()
+		return float64(hits)
+	}))
+	m.registry.MustRegister(prometheus.NewCounterFunc(prometheus.CounterOpts{
+		Name: "argocd_two_level_cache_l1_misses_total",
+		Help: "Number of cache reads not answered by the in-memory cache, which fell back to Redis.",
+	}, func() float64 {
+		_, misses := stats()
+		return float64(misses)
+	}))
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "argocd_two_level_cache_l1_hit_ratio",
+		Help: "Ratio of the cache reads answered by the in-memory cache.",
+	}, func() float64 {
+		hits, misses := stats()
+		if hits+misses == 0 {
+			return 0
+		}
+		return float64(hits) / float64(hits+misses)
+	}))
+}
+
+// IncSync increments the sync counter for an application, and the sync error counter if the sync failed
 func (m *MetricsServer) IncSync(app *argoappv1.Application, state *argoappv1.OperationState) {
 	if !state.Phase.Completed() {
 		return
 	}
 	m.syncCounter.WithLabelValues(app.Namespace, app.Name, app.Spec.GetProject(), app.Spec.Destination.Server, string(state.Phase)).Inc()
+	if !state.Phase.Successful() && state.ErrorCode != "" {
+		m.syncErrorCounter.WithLabelValues(app.Namespace, app.Name, app.Spec.GetProject(), app.Spec.Destination.Server, string(state.ErrorCode)).Inc()
+	}
+}
+
+// IncRollback increments the automatic rollback counter for an application
+func (m *MetricsServer) IncRollback(app *argoappv1.Application) {
+	m.rollbackCounter.WithLabelValues(app.Namespace, app.Name, app.Spec.GetProject(), app.Spec.Destination.Server).Inc()
+}
+
+// IncReconcilePanic increments the counter of the reconciliations of an application which panicked
+func (m *MetricsServer) IncReconcilePanic(app *argoappv1.Application) {
+	m.reconcilePanicCounter.WithLabelValues(app.Namespace, app.Name, app.Spec.GetProject()).Inc()
+}
+
+// IncWorkStolen increments the counter of the application reconciliations stolen from the queue of the given shard
+func (m *MetricsServer) IncWorkStolen(ownerShard int) {
+	m.workStolenCounter.WithLabelValues(strconv.Itoa(ownerShard)).Inc()
+}
+
+// IncClusterDisconnectGrace increments the counter of the application reconciliations which kept the last known
+// status while the given cluster was briefly unreachable
+func (m *MetricsServer) IncClusterDisconnectGrace(server string) {
+	m.clusterDisconnectGraceCounter.WithLabelValues(server).Inc()
+}
+
+// IncSyncPatternRetry increments the counter of the retries of failed syncs whose error matched the given retry pattern
+func (m *MetricsServer) IncSyncPatternRetry(pattern string) {
+	m.syncPatternRetryCounter.WithLabelValues(pattern).Inc()
 }
 
 func (m *MetricsServer) IncKubectlExec(command string) {
@@ -256,26 +551,120 @@ func (m *MetricsServer) IncKubernetesRequest(app *argoappv1.Application, server,
 		name = app.Name
 		project = app.Spec.GetProject()
 	}
+	sampled, weight := m.sampler.sample()
+	if !sampled {
+		return
+	}
 	m.k8sRequestCounter.WithLabelValues(
 		namespace, name, project, server, statusCode,
 		verb, resourceKind, resourceNamespace,
-	).Inc()
+	).Add(weight)
+}
+
+// IncResourceApplyThrottled increments the counter of the requests of an application sync which were delayed by the
+// apply rate limit of the cluster
+func (m *MetricsServer) IncResourceApplyThrottled(app *argoappv1.Application, server string) {
+	m.applyThrottledCounter.WithLabelValues(app.Namespace, app.Name, app.Spec.GetProject(), server).Inc()
 }
 
 func (m *MetricsServer) IncRedisRequest(failed bool) {
 	m.redisRequestCounter.WithLabelValues(m.hostname, common.ApplicationController, strconv.FormatBool(failed)).Inc()
 }
 
+// IncCacheOversizedItems increments the number of cache items which bypassed the in-memory cache
+func (m *MetricsServer) IncCacheOversizedItems() {
+	m.cacheOversizedCounter.WithLabelValues(m.hostname).Inc()
+}
+
+// IncEventsDeduplicated increments the number of Kubernetes events which were not emitted since the same event was
+// emitted recently
+func (m *MetricsServer) IncEventsDeduplicated(reason string) {
+	m.eventsDedupCounter.WithLabelValues(reason).Inc()
+}
+
+// IncEventsFiltered increments the number of resource events which were not handled since a later event of the same
+// resource was received within the event filter interval
+func (m *MetricsServer) IncEventsFiltered() {
+	m.eventsFilteredCounter.Inc()
+}
+
+// IncDatadogEventSent increments the number of events of the given type sent to Datadog for an application
+func (m *MetricsServer) IncDatadogEventSent(app *argoappv1.Application, eventType string) {
+	m.datadogSentCounter.WithLabelValues(app.Namespace, app.Name, app.Spec.GetProject(), eventType).Inc()
+}
+
+// IncDatadogEventFailed increments the number of events of the given type which could not be sent to Datadog for an
+// application
+func (m *MetricsServer) IncDatadogEventFailed(app *argoappv1.Application, eventType string) {
+	m.datadogFailedCounter.WithLabelValues(app.Namespace, app.Name, app.Spec.GetProject(), eventType).Inc()
+}
+
+// AddHistoryPruned increments the number of sync history entries of an application removed by the history retention
+// policy
+func (m *MetricsServer) AddHistoryPruned(app *argoappv1.Application, count int) {
+	m.historyPrunedCounter.WithLabelValues(app.Namespace, app.Name, app.Spec.GetProject()).Add(float64(count))
+}
+
 // ObserveRedisRequestDuration observes redis request duration
 func (m *MetricsServer) ObserveRedisRequestDuration(duration time.Duration) {
 	m.redisRequestHistogram.WithLabelValues(m.hostname, common.ApplicationController).Observe(duration.Seconds())
 }
 
-// IncReconcile increments the reconcile counter for an application
+// SetOrphanedResourcesCount sets the number of orphaned resources of an application, counted while reconciling it
+func (m *MetricsServer) SetOrphanedResourcesCount(app *argoappv1.Application, count int) {
+	m.orphanedResources.set(app.QualifiedName(), count)
+}
+
+// DeleteOrphanedResourcesCount removes the number of orphaned resources of an application whose project does not
+// monitor orphaned resources
+func (m *MetricsServer) DeleteOrphanedResourcesCount(app *argoappv1.Application) {
+	m.orphanedResources.delete(app.QualifiedName())
+}
+
+// SetAppResourceCount sets the number of desired and live resources of an application, counted while reconciling it
+func (m *MetricsServer) SetAppResourceCount(app *argoappv1.Application, desired int, live int) {
+	m.resourceCounts.set(app.QualifiedName(), resourceCount{destServer: app.Spec.Destination.Server, desired: desired, live: live})
+}
+
+// IncReconcile increments the reconcile counter for an application. The observations are sampled when a sampling
+// ratio is set, so the count of the histogram must be divided by the sampling ratio to estimate the actual number of
+// reconciliations.
 func (m *MetricsServer) IncReconcile(app *argoappv1.Application, duration time.Duration) {
+	if sampled, _ := m.sampler.sample(); !sampled {
+		return
+	}
 	m.reconcileHistogram.WithLabelValues(app.Namespace, app.Spec.Destination.Server).Observe(duration.Seconds())
 }
 
+// SetSamplingRatio samples the recordings of the metrics of the reconcile loop, the reconciliation durations and the
+// Kubernetes requests, at the given ratio to reduce their cost with thousands of applications. The sampled counters
+// are incremented by the inverse of the ratio so that their rates remain estimates of the actual rates, while the other
+// metrics, such as the syncs, are always recorded. The ratio is exposed by the argocd_metrics_sample_ratio gauge when
+// sampling is active.
+func (m *MetricsServer) SetSamplingRatio(ratio float64) error {
+	return m.setSamplingRatio(ratio, rand.NewSource(time.Now().UnixNano()))
+}
+
+func (m *MetricsServer) setSamplingRatio(ratio float64, source rand.Source) error {
+	if ratio <= 0 || ratio > 1 {
+		return fmt.Errorf("invalid metrics sampling ratio %v: must be greater than 0 and at most 1", ratio)
+	}
+	if m.sampler != nil {
+		return errors.New("Sampling ratio is already set")
+	}
+	if ratio == 1 {
+		return nil
+	}
+	m.sampler = newSampler(ratio, source)
+	m.registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "argocd_metrics_sample_ratio",
+		Help: "Ratio of the recordings of the reconcile loop metrics which are kept.",
+	}, func() float64 {
+		return ratio
+	}))
+	return nil
+}
+
 // HasExpiration return true if expiration is set
 func (m *MetricsServer) HasExpiration() bool {
 	return len(m.cron.Entries()) > 0
@@ -290,10 +679,20 @@ func (m *MetricsServer) SetExpiration(cacheExpiration time.Duration) error {
 	_, err := m.cron.AddFunc(fmt.Sprintf("@every %s", cacheExpiration), func() {
 		log.Infof("Reset Prometheus metrics based on existing expiration '%v'", cacheExpiration)
 		m.syncCounter.Reset()
+		m.syncErrorCounter.Reset()
+		m.rollbackCounter.Reset()
 		m.kubectlExecCounter.Reset()
 		m.kubectlExecPendingGauge.Reset()
 		m.k8sRequestCounter.Reset()
+		m.applyThrottledCounter.Reset()
 		m.clusterEventsCounter.Reset()
+		m.datadogSentCounter.Reset()
+		m.datadogFailedCounter.Reset()
+		m.historyPrunedCounter.Reset()
+		m.reconcilePanicCounter.Reset()
+		m.workStolenCounter.Reset()
+		m.clusterDisconnectGraceCounter.Reset()
+		m.syncPatternRetryCounter.Reset()
 		m.redisRequestCounter.Reset()
 		m.reconcileHistogram.Reset()
 		m.redisRequestHistogram.Reset()
@@ -334,6 +733,7 @@ func (c *appCollector) Describe(ch chan<- *prometheus.Desc) {
 		ch <- descAppLabels
 	}
 	ch <- descAppInfo
+	ch <- descAppHelmChartVersionInfo
 	ch <- descAppSyncStatusCode
 	ch <- descAppHealthStatus
 }
@@ -359,6 +759,29 @@ func boolFloat64(b bool) float64 {
 	return 0
 }
 
+// collectHelmChartVersions emits one series per Helm chart and version deployed by the application. The series are
+// built from the current application specs on every scrape, so versions which are no longer deployed disappear and
+// the cardinality is bounded by the number of Helm sources rather than the number of versions ever deployed.
+func collectHelmChartVersions(ch chan<- prometheus.Metric, app *argoappv1.Application) {
+	destCluster := app.Spec.Destination.Server
+	if destCluster == "" {
+		destCluster = app.Spec.Destination.Name
+	}
+	seen := make(map[string]bool)
+	for _, source := range app.Spec.GetSources() {
+		if !source.IsHelm() {
+			continue
+		}
+		key := source.Chart + "@" + source.TargetRevision
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		ch <- prometheus.MustNewConstMetric(descAppHelmChartVersionInfo, prometheus.GaugeValue, 1,
+			source.Chart, source.TargetRevision, app.QualifiedName(), app.Spec.GetProject(), destCluster)
+	}
+}
+
 func (c *appCollector) collectApps(ch chan<- prometheus.Metric, app *argoappv1.Application) {
 	addConstMetric := func(desc *prometheus.Desc, t prometheus.ValueType, v float64, lv ...string) {
 		project := app.Spec.GetProject()
@@ -388,6 +811,8 @@ func (c *appCollector) collectApps(ch chan<- prometheus.Metric, app *argoappv1.A
 
 	addGauge(descAppInfo, 1, strconv.FormatBool(autoSyncEnabled), git.NormalizeGitURL(app.Spec.GetSource().RepoURL), app.Spec.Destination.Server, app.Spec.Destination.Namespace, string(syncStatus), string(healthStatus), operation)
 
+	collectHelmChartVersions(ch, app)
+
 	if len(c.appLabels) > 0 {
 		labelValues := []string{}
 		for _, desiredLabel := range c.appLabels {
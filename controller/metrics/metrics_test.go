@@ -2,7 +2,9 @@ package metrics
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"math/rand"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -11,6 +13,9 @@ import (
 
 	gitopsCache "github.com/argoproj/gitops-engine/pkg/cache"
 	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	promcm "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -332,6 +337,86 @@ argocd_app_sync_status{name="my-app",namespace="argocd",project="important-proje
 	testApp(t, []string{fakeApp}, expectedResponse)
 }
 
+const fakeHelmApp = `
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: my-helm-app
+  namespace: argocd
+spec:
+  destination:
+    namespace: dummy-namespace
+    name: in-cluster
+  project: important-project
+  sources:
+  - chart: redis
+    repoURL: https://charts.bitnami.com/bitnami
+    targetRevision: 17.3.2
+  - chart: redis
+    repoURL: https://charts.bitnami.com/bitnami
+    targetRevision: 17.3.2
+  - chart: postgresql
+    repoURL: https://charts.bitnami.com/bitnami
+    targetRevision: 12.1.0
+  - path: some/path
+    repoURL: https://github.com/argoproj/argocd-example-apps.git
+`
+
+func TestHelmChartVersionMetrics(t *testing.T) {
+	expectedResponse := `
+# HELP argocd_app_helm_chart_version_info Information about the Helm chart versions deployed by applications.
+# TYPE argocd_app_helm_chart_version_info gauge
+argocd_app_helm_chart_version_info{app_name="argocd/my-helm-app",chart="postgresql",dest_cluster="in-cluster",project="important-project",version="12.1.0"} 1
+argocd_app_helm_chart_version_info{app_name="argocd/my-helm-app",chart="redis",dest_cluster="in-cluster",project="important-project",version="17.3.2"} 1
+`
+	testApp(t, []string{fakeHelmApp, fakeApp}, expectedResponse)
+}
+
+func TestHelmChartVersionMetricsCardinality(t *testing.T) {
+	countSeries := func(appYAMLs []string) int {
+		cancel, appLister := newFakeLister(appYAMLs...)
+		defer cancel()
+		families, err := NewAppRegistry(appLister, appFilter, nil).Gather()
+		require.NoError(t, err)
+		for _, family := range families {
+			if family.GetName() == "argocd_app_helm_chart_version_info" {
+				return len(family.GetMetric())
+			}
+		}
+		return 0
+	}
+	newHelmApp := func(i int, version string) string {
+		return fmt.Sprintf(`
+apiVersion: argoproj.io/v1alpha1
+kind: Application
+metadata:
+  name: helm-app-%d
+  namespace: argocd
+spec:
+  destination:
+    server: https://localhost:6443
+  project: default
+  source:
+    chart: redis
+    repoURL: https://charts.bitnami.com/bitnami
+    targetRevision: %s
+`, i, version)
+	}
+
+	var apps []string
+	for i := 0; i < 100; i++ {
+		apps = append(apps, newHelmApp(i, fmt.Sprintf("17.%d.0", i)))
+	}
+	assert.Equal(t, 100, countSeries(apps))
+
+	// upgrading every application does not add series, as they are only built from the deployed versions
+	apps = nil
+	for i := 0; i < 100; i++ {
+		apps = append(apps, newHelmApp(i, fmt.Sprintf("18.%d.0", i)))
+	}
+	assert.Equal(t, 100, countSeries(apps))
+}
+
 func TestMetricsSyncCounter(t *testing.T) {
 	cancel, appLister := newFakeLister()
 	defer cancel()
@@ -344,12 +429,18 @@ func TestMetricsSyncCounter(t *testing.T) {
 argocd_app_sync_total{dest_server="https://localhost:6443",name="my-app",namespace="argocd",phase="Error",project="important-project"} 1
 argocd_app_sync_total{dest_server="https://localhost:6443",name="my-app",namespace="argocd",phase="Failed",project="important-project"} 1
 argocd_app_sync_total{dest_server="https://localhost:6443",name="my-app",namespace="argocd",phase="Succeeded",project="important-project"} 2
+`
+	syncErrorTotal := `
+# HELP argocd_sync_error_total Number of failed application syncs, by error code.
+# TYPE argocd_sync_error_total counter
+argocd_sync_error_total{code="ApplyFailed",dest_server="https://localhost:6443",name="my-app",namespace="argocd",project="important-project"} 1
+argocd_sync_error_total{code="InternalError",dest_server="https://localhost:6443",name="my-app",namespace="argocd",project="important-project"} 1
 `
 
 	fakeApp := newFakeApp(fakeApp)
 	metricsServ.IncSync(fakeApp, &argoappv1.OperationState{Phase: common.OperationRunning})
-	metricsServ.IncSync(fakeApp, &argoappv1.OperationState{Phase: common.OperationFailed})
-	metricsServ.IncSync(fakeApp, &argoappv1.OperationState{Phase: common.OperationError})
+	metricsServ.IncSync(fakeApp, &argoappv1.OperationState{Phase: common.OperationFailed, ErrorCode: argoappv1.SyncErrorCodeApplyFailed})
+	metricsServ.IncSync(fakeApp, &argoappv1.OperationState{Phase: common.OperationError, ErrorCode: argoappv1.SyncErrorCodeInternalError})
 	metricsServ.IncSync(fakeApp, &argoappv1.OperationState{Phase: common.OperationSucceeded})
 	metricsServ.IncSync(fakeApp, &argoappv1.OperationState{Phase: common.OperationSucceeded})
 
@@ -361,6 +452,141 @@ argocd_app_sync_total{dest_server="https://localhost:6443",name="my-app",namespa
 	body := rr.Body.String()
 	log.Println(body)
 	assertMetricsPrinted(t, appSyncTotal, body)
+	assertMetricsPrinted(t, syncErrorTotal, body)
+}
+
+func TestMetricsRollbackCounter(t *testing.T) {
+	cancel, appLister := newFakeLister()
+	defer cancel()
+	metricsServ, err := NewMetricsServer("localhost:8082", appLister, appFilter, noOpHealthCheck, []string{})
+	require.NoError(t, err)
+
+	appRollbackTotal := `
+# HELP argocd_app_rollback_total Number of automatic rollbacks of applications which became degraded after a sync.
+# TYPE argocd_app_rollback_total counter
+argocd_app_rollback_total{dest_server="https://localhost:6443",name="my-app",namespace="argocd",project="important-project"} 2
+`
+
+	fakeApp := newFakeApp(fakeApp)
+	metricsServ.IncRollback(fakeApp)
+	metricsServ.IncRollback(fakeApp)
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	metricsServ.Handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assertMetricsPrinted(t, appRollbackTotal, rr.Body.String())
+}
+
+func TestMetricsSyncPatternRetryCounter(t *testing.T) {
+	cancel, appLister := newFakeLister()
+	defer cancel()
+	metricsServ, err := NewMetricsServer("localhost:8082", appLister, appFilter, noOpHealthCheck, []string{})
+	require.NoError(t, err)
+
+	syncPatternRetryTotal := `
+# HELP argocd_sync_pattern_retry_total Number of retries of failed syncs whose error matched a retry pattern of the application.
+# TYPE argocd_sync_pattern_retry_total counter
+argocd_sync_pattern_retry_total{pattern="(?i)too many requests"} 2
+argocd_sync_pattern_retry_total{pattern="the object has been modified"} 1
+`
+
+	metricsServ.IncSyncPatternRetry("(?i)too many requests")
+	metricsServ.IncSyncPatternRetry("(?i)too many requests")
+	metricsServ.IncSyncPatternRetry("the object has been modified")
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	metricsServ.Handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assertMetricsPrinted(t, syncPatternRetryTotal, rr.Body.String())
+}
+
+func TestMetricsRetryQueueDepth(t *testing.T) {
+	cancel, appLister := newFakeLister()
+	defer cancel()
+	metricsServ, err := NewMetricsServer("localhost:8082", appLister, appFilter, noOpHealthCheck, []string{})
+	require.NoError(t, err)
+	metricsServ.RegisterRetryQueueDepth(func() int {
+		return 3
+	})
+
+	retryQueueDepth := `
+# HELP argocd_controller_retry_queue_depth Number of applications waiting in the operation queue after their last operation failed or timed out.
+# TYPE argocd_controller_retry_queue_depth gauge
+argocd_controller_retry_queue_depth 3
+`
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	metricsServ.Handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assertMetricsPrinted(t, retryQueueDepth, rr.Body.String())
+}
+
+func TestMetricsQueueHealthCheck(t *testing.T) {
+	cancel, appLister := newFakeLister()
+	defer cancel()
+	metricsServ, err := NewMetricsServer("localhost:8082", appLister, appFilter, noOpHealthCheck, []string{})
+	require.NoError(t, err)
+	var checkErr error
+	metricsServ.RegisterQueueHealthCheck(func() error {
+		return checkErr
+	})
+	check := func() int {
+		rr := httptest.NewRecorder()
+		metricsServ.Handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, QueueHealthPath, nil))
+		return rr.Code
+	}
+	failures := testutil.ToFloat64(metricsServ.queueHealthFailuresCounter)
+
+	assert.Equal(t, http.StatusOK, check())
+	assert.InDelta(t, failures, testutil.ToFloat64(metricsServ.queueHealthFailuresCounter), 0.01)
+
+	checkErr = fmt.Errorf("the reconcile queue is stuck")
+	assert.Equal(t, http.StatusServiceUnavailable, check())
+	assert.InDelta(t, failures+1, testutil.ToFloat64(metricsServ.queueHealthFailuresCounter), 0.01)
+}
+
+func TestMetricsTwoLevelCacheStats(t *testing.T) {
+	cancel, appLister := newFakeLister()
+	defer cancel()
+	metricsServ, err := NewMetricsServer("localhost:8082", appLister, appFilter, noOpHealthCheck, []string{})
+	require.NoError(t, err)
+	hits, misses := int64(0), int64(0)
+	metricsServ.RegisterTwoLevelCacheStats(func() (int64, int64) {
+		return hits, misses
+	})
+
+	scrape := func() string {
+		req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		metricsServ.Handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	// no division by zero before the first read
+	assertMetricsPrinted(t, `
+argocd_two_level_cache_l1_hit_ratio 0
+`, scrape())
+
+	hits, misses = 3, 1
+	assertMetricsPrinted(t, `
+# HELP argocd_two_level_cache_l1_hits_total Number of cache reads answered by the in-memory cache.
+# TYPE argocd_two_level_cache_l1_hits_total counter
+argocd_two_level_cache_l1_hits_total 3
+# HELP argocd_two_level_cache_l1_misses_total Number of cache reads not answered by the in-memory cache, which fell back to Redis.
+# TYPE argocd_two_level_cache_l1_misses_total counter
+argocd_two_level_cache_l1_misses_total 1
+# HELP argocd_two_level_cache_l1_hit_ratio Ratio of the cache reads answered by the in-memory cache.
+# TYPE argocd_two_level_cache_l1_hit_ratio gauge
+argocd_two_level_cache_l1_hit_ratio 0.75
+`, scrape())
 }
 
 // assertMetricsPrinted asserts every line in the expected lines appears in the body
@@ -520,3 +746,86 @@ go_threads
 	log.Println(body)
 	assertMetricsPrinted(t, expectedMetrics, body)
 }
+
+func TestMetricsEventsDeduplicated(t *testing.T) {
+	cancel, appLister := newFakeLister()
+	defer cancel()
+	metricsServ, err := NewMetricsServer("localhost:8082", appLister, appFilter, noOpHealthCheck, []string{})
+	require.NoError(t, err)
+	metricsServ.IncEventsDeduplicated("StatusRefreshed")
+	metricsServ.IncEventsDeduplicated("StatusRefreshed")
+
+	eventsDeduplicated := `
+# HELP argocd_events_deduplicated_total Number of Kubernetes events not emitted because the same event was emitted within the deduplication window.
+# TYPE argocd_events_deduplicated_total counter
+argocd_events_deduplicated_total{reason="StatusRefreshed"} 2
+`
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	metricsServ.Handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assertMetricsPrinted(t, eventsDeduplicated, rr.Body.String())
+}
+
+func TestMetricsSampling(t *testing.T) {
+	cancel, appLister := newFakeLister()
+	defer cancel()
+	metricsServ, err := NewMetricsServer("localhost:8082", appLister, appFilter, noOpHealthCheck, []string{})
+	require.NoError(t, err)
+
+	require.Error(t, metricsServ.SetSamplingRatio(0))
+	require.Error(t, metricsServ.SetSamplingRatio(1.5))
+	require.NoError(t, metricsServ.setSamplingRatio(0.25, rand.NewSource(1)))
+	require.Error(t, metricsServ.SetSamplingRatio(0.5))
+
+	app := newFakeApp(fakeApp)
+	app.Spec.Destination.Server = "https://sampled-cluster"
+	const recordings = 20000
+	for i := 0; i < recordings; i++ {
+		metricsServ.IncKubernetesRequest(app, app.Spec.Destination.Server, "200", "Get", "Pod", "default")
+		metricsServ.IncReconcile(app, time.Second)
+	}
+	metricsServ.IncSync(app, &argoappv1.OperationState{Phase: common.OperationFailed})
+
+	// the sampled counters are scaled so that their rates estimate the actual rates
+	requests := testutil.ToFloat64(metricsServ.k8sRequestCounter.WithLabelValues(app.Namespace, app.Name, app.Spec.GetProject(), app.Spec.Destination.Server, "200", "Get", "Pod", "default"))
+	assert.InEpsilon(t, recordings, requests, 0.05)
+	// the observations of the histograms are sampled
+	assert.InEpsilon(t, recordings*0.25, histogramSampleCount(t, metricsServ.reconcileHistogram.WithLabelValues(app.Namespace, app.Spec.Destination.Server)), 0.05)
+	// the syncs are always recorded
+	assert.Equal(t, 1.0, testutil.ToFloat64(metricsServ.syncCounter.WithLabelValues(app.Namespace, app.Name, app.Spec.GetProject(), app.Spec.Destination.Server, string(common.OperationFailed))))
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	metricsServ.Handler.ServeHTTP(rr, req)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	assertMetricsPrinted(t, `
+# HELP argocd_metrics_sample_ratio Ratio of the recordings of the reconcile loop metrics which are kept.
+# TYPE argocd_metrics_sample_ratio gauge
+argocd_metrics_sample_ratio 0.25
+`, rr.Body.String())
+}
+
+func TestMetricsSampling_Disabled(t *testing.T) {
+	cancel, appLister := newFakeLister()
+	defer cancel()
+	metricsServ, err := NewMetricsServer("localhost:8082", appLister, appFilter, noOpHealthCheck, []string{})
+	require.NoError(t, err)
+	require.NoError(t, metricsServ.SetSamplingRatio(DefaultSamplingRatio))
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	metricsServ.Handler.ServeHTTP(rr, req)
+	assert.NotContains(t, rr.Body.String(), "argocd_metrics_sample_ratio")
+}
+
+func histogramSampleCount(t *testing.T, observer prometheus.Observer) float64 {
+	t.Helper()
+	metric := &promcm.Metric{}
+	require.NoError(t, observer.(prometheus.Metric).Write(metric))
+	return float64(metric.GetHistogram().GetSampleCount())
+}
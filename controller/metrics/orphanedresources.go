@@ -0,0 +1,100 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	applister "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
+)
+
+var (
+	descAppOrphanedResourcesCount = prometheus.NewDesc(
+		"argocd_app_orphaned_resources_count",
+		"Number of orphaned resources in the destination namespace of the application.",
+		descAppDefaultLabels,
+		nil,
+	)
+	descProjectOrphanedResourcesCount = prometheus.NewDesc(
+		"argocd_project_orphaned_resources_count",
+		"Number of orphaned resources in the destination namespaces of the applications of the project.",
+		[]string{"name"},
+		nil,
+	)
+)
+
+// orphanedResourcesCollector collects the number of orphaned resources of the applications whose project monitors
+// orphaned resources, as counted during the last reconciliation of each application
+type orphanedResourcesCollector struct {
+	store     applister.ApplicationLister
+	appFilter func(obj interface{}) bool
+
+	lock sync.Mutex
+	// counts are the numbers of orphaned resources, by qualified application name
+	counts map[string]int
+}
+
+func newOrphanedResourcesCollector(appLister applister.ApplicationLister, appFilter func(obj interface{}) bool) *orphanedResourcesCollector {
+	return &orphanedResourcesCollector{
+		store:     appLister,
+		appFilter: appFilter,
+		counts:    map[string]int{},
+	}
+}
+
+// set sets the number of orphaned resources of an application
+func (c *orphanedResourcesCollector) set(qualifiedName string, count int) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.counts[qualifiedName] = count
+}
+
+// delete removes an application from the metrics
+func (c *orphanedResourcesCollector) delete(qualifiedName string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.counts, qualifiedName)
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *orphanedResourcesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descAppOrphanedResourcesCount
+	ch <- descProjectOrphanedResourcesCount
+}
+
+// Collect implements the prometheus.Collector interface
+func (c *orphanedResourcesCollector) Collect(ch chan<- prometheus.Metric) {
+	apps, err := c.store.List(labels.NewSelector())
+	if err != nil {
+		log.Warnf("Failed to collect applications: %v", err)
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	projectCounts := map[string]int{}
+	listed := map[string]bool{}
+	for _, app := range apps {
+		if !c.appFilter(app) {
+			continue
+		}
+		count, ok := c.counts[app.QualifiedName()]
+		if !ok {
+			continue
+		}
+		listed[app.QualifiedName()] = true
+		project := app.Spec.GetProject()
+		ch <- prometheus.MustNewConstMetric(descAppOrphanedResourcesCount, prometheus.GaugeValue, float64(count), app.Namespace, app.Name, project)
+		projectCounts[project] += count
+	}
+	for project, count := range projectCounts {
+		ch <- prometheus.MustNewConstMetric(descProjectOrphanedResourcesCount, prometheus.GaugeValue, float64(count), project)
+	}
+	// Forget the applications which were deleted or are no longer processed by this controller
+	for qualifiedName := range c.counts {
+		if !listed[qualifiedName] {
+			delete(c.counts, qualifiedName)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrphanedResourcesMetrics(t *testing.T) {
+	cancel, appLister := newFakeLister(fakeApp, fakeApp2)
+	defer cancel()
+	metricsServ, err := NewMetricsServer("localhost:8082", appLister, appFilter, noOpHealthCheck, []string{})
+	require.NoError(t, err)
+	getMetrics := func() string {
+		req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		metricsServ.Handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	// Applications whose project does not monitor orphaned resources have no series
+	assert.NotContains(t, getMetrics(), "argocd_app_orphaned_resources_count")
+
+	metricsServ.SetOrphanedResourcesCount(newFakeApp(fakeApp), 2)
+	metricsServ.SetOrphanedResourcesCount(newFakeApp(fakeApp2), 3)
+	// Applications which no longer exist are ignored
+	deleted := newFakeApp(fakeApp)
+	deleted.Name = "deleted-app"
+	metricsServ.SetOrphanedResourcesCount(deleted, 10)
+	body := getMetrics()
+	assertMetricsPrinted(t, `
+# HELP argocd_app_orphaned_resources_count Number of orphaned resources in the destination namespace of the application.
+# TYPE argocd_app_orphaned_resources_count gauge
+argocd_app_orphaned_resources_count{name="my-app",namespace="argocd",project="important-project"} 2
+argocd_app_orphaned_resources_count{name="my-app-2",namespace="argocd",project="important-project"} 3
+# HELP argocd_project_orphaned_resources_count Number of orphaned resources in the destination namespaces of the applications of the project.
+# TYPE argocd_project_orphaned_resources_count gauge
+argocd_project_orphaned_resources_count{name="important-project"} 5
+`, body)
+	assert.NotContains(t, body, "deleted-app")
+
+	metricsServ.DeleteOrphanedResourcesCount(newFakeApp(fakeApp2))
+	body = getMetrics()
+	assert.Contains(t, body, `argocd_project_orphaned_resources_count{name="important-project"} 2`)
+	assert.NotContains(t, body, `argocd_app_orphaned_resources_count{name="my-app-2"`)
+}
@@ -0,0 +1,94 @@
+package metrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	applister "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
+)
+
+const (
+	// ResourceCountTypeDesired is the type of the count of the resources rendered from the sources of an application
+	ResourceCountTypeDesired = "desired"
+	// ResourceCountTypeLive is the type of the count of the resources of an application found in its cluster
+	ResourceCountTypeLive = "live"
+)
+
+var descAppResourceCount = prometheus.NewDesc(
+	"argocd_app_resource_count",
+	"Number of desired and live resources of the application.",
+	append(descAppDefaultLabels, "dest_server", "type"),
+	nil,
+)
+
+// resourceCount is the number of desired and live resources of an application
+type resourceCount struct {
+	destServer string
+	desired    int
+	live       int
+}
+
+// resourceCountCollector collects the number of desired and live resources of the applications, as counted during
+// the last reconciliation of each application
+type resourceCountCollector struct {
+	store     applister.ApplicationLister
+	appFilter func(obj interface{}) bool
+
+	lock sync.Mutex
+	// counts are the numbers of resources, by qualified application name
+	counts map[string]resourceCount
+}
+
+func newResourceCountCollector(appLister applister.ApplicationLister, appFilter func(obj interface{}) bool) *resourceCountCollector {
+	return &resourceCountCollector{
+		store:     appLister,
+		appFilter: appFilter,
+		counts:    map[string]resourceCount{},
+	}
+}
+
+// set sets the number of desired and live resources of an application
+func (c *resourceCountCollector) set(qualifiedName string, count resourceCount) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.counts[qualifiedName] = count
+}
+
+// Describe implements the prometheus.Collector interface
+func (c *resourceCountCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descAppResourceCount
+}
+
+// Collect implements the prometheus.Collector interface
+func (c *resourceCountCollector) Collect(ch chan<- prometheus.Metric) {
+	apps, err := c.store.List(labels.NewSelector())
+	if err != nil {
+		log.Warnf("Failed to collect applications: %v", err)
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	listed := map[string]bool{}
+	for _, app := range apps {
+		if !c.appFilter(app) {
+			continue
+		}
+		count, ok := c.counts[app.QualifiedName()]
+		if !ok {
+			continue
+		}
+		listed[app.QualifiedName()] = true
+		project := app.Spec.GetProject()
+		ch <- prometheus.MustNewConstMetric(descAppResourceCount, prometheus.GaugeValue, float64(count.desired), app.Namespace, app.Name, project, count.destServer, ResourceCountTypeDesired)
+		ch <- prometheus.MustNewConstMetric(descAppResourceCount, prometheus.GaugeValue, float64(count.live), app.Namespace, app.Name, project, count.destServer, ResourceCountTypeLive)
+	}
+	// Forget the applications which were deleted or are no longer processed by this controller
+	for qualifiedName := range c.counts {
+		if !listed[qualifiedName] {
+			delete(c.counts, qualifiedName)
+		}
+	}
+}
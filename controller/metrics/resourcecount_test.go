@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppResourceCountMetrics(t *testing.T) {
+	cancel, appLister := newFakeLister(fakeApp, fakeApp2)
+	defer cancel()
+	metricsServ, err := NewMetricsServer("localhost:8082", appLister, appFilter, noOpHealthCheck, []string{})
+	require.NoError(t, err)
+	getMetrics := func() string {
+		req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		metricsServ.Handler.ServeHTTP(rr, req)
+		assert.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+
+	// Applications which were not reconciled yet have no series
+	assert.NotContains(t, getMetrics(), "argocd_app_resource_count")
+
+	// my-app misses resources while my-app-2 has extra resources
+	metricsServ.SetAppResourceCount(newFakeApp(fakeApp), 10, 3)
+	metricsServ.SetAppResourceCount(newFakeApp(fakeApp2), 2, 4)
+	// Applications which no longer exist are ignored
+	deleted := newFakeApp(fakeApp)
+	deleted.Name = "deleted-app"
+	metricsServ.SetAppResourceCount(deleted, 1, 1)
+	body := getMetrics()
+	assertMetricsPrinted(t, `
+# HELP argocd_app_resource_count Number of desired and live resources of the application.
+# TYPE argocd_app_resource_count gauge
+argocd_app_resource_count{dest_server="https://localhost:6443",name="my-app",namespace="argocd",project="important-project",type="desired"} 10
+argocd_app_resource_count{dest_server="https://localhost:6443",name="my-app",namespace="argocd",project="important-project",type="live"} 3
+argocd_app_resource_count{dest_server="https://localhost:6443",name="my-app-2",namespace="argocd",project="important-project",type="desired"} 2
+argocd_app_resource_count{dest_server="https://localhost:6443",name="my-app-2",namespace="argocd",project="important-project",type="live"} 4
+`, body)
+	assert.NotContains(t, body, "deleted-app")
+
+	// The counts are replaced on the next reconciliation
+	metricsServ.SetAppResourceCount(newFakeApp(fakeApp), 10, 10)
+	assert.Contains(t, getMetrics(), `argocd_app_resource_count{dest_server="https://localhost:6443",name="my-app",namespace="argocd",project="important-project",type="live"} 10`)
+}
@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	kubeutil "github.com/argoproj/argo-cd/v2/util/kube"
+)
+
+// checkClusterVersion returns a SyncWarning condition for each target resource of the application which uses an API
+// version deprecated in Kubernetes, compared to the preferred API versions of the destination cluster, so that they
+// can be migrated before the application is synced
+func (m *appStateManager) checkClusterVersion(app *v1alpha1.Application, targets []*unstructured.Unstructured, now metav1.Time) []v1alpha1.ApplicationCondition {
+	var preferredResources []*metav1.APIResourceList
+	kubeClient, err := m.getDestinationKubeClient(app)
+	if err == nil {
+		preferredResources, err = kubeutil.GetServerPreferredResources(kubeClient.Discovery())
+	}
+	if err != nil {
+		return []v1alpha1.ApplicationCondition{{Type: v1alpha1.ApplicationConditionSyncWarning, Message: fmt.Sprintf("Failed to check the API versions of the resources: %v", err), LastTransitionTime: &now}}
+	}
+	var conditions []v1alpha1.ApplicationCondition
+	for _, usage := range kubeutil.FindDeprecatedAPIs(targets, preferredResources) {
+		conditions = append(conditions, v1alpha1.ApplicationCondition{Type: v1alpha1.ApplicationConditionSyncWarning, Message: usage.String(), LastTransitionTime: &now})
+	}
+	return conditions
+}
@@ -0,0 +1,68 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+func TestCompareAppStateClusterVersionCheck(t *testing.T) {
+	deployment := test.YamlToUnstructured(`
+apiVersion: apps/v1beta1
+kind: Deployment
+metadata:
+  name: guestbook
+  namespace: ` + test.FakeDestNamespace + `
+`)
+	newController := func() *ApplicationController {
+		ctrl := newFakeController(&fakeData{
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: []string{toJSON(t, deployment)},
+				Namespace: test.FakeDestNamespace,
+				Server:    test.FakeClusterURL,
+				Revision:  "abc123",
+			},
+			managedLiveObjs: map[kube.ResourceKey]*unstructured.Unstructured{},
+		}, nil)
+		ctrl.appStateManager.(*appStateManager).kubeClientForDestination = func(_ *argoappv1.Application) (kubernetes.Interface, error) {
+			// the destination cluster does not serve apps/v1beta1 anymore
+			client := fake.NewSimpleClientset()
+			client.Resources = []*metav1.APIResourceList{
+				{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "pods", Kind: "Pod", Namespaced: true}}},
+				{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment", Namespaced: true}}},
+			}
+			return client, nil
+		}
+		return ctrl
+	}
+
+	t.Run("PreSync", func(t *testing.T) {
+		app := newFakeApp()
+		proj := defaultProj.DeepCopy()
+		proj.Spec.SyncPolicy = &argoappv1.ProjectSyncPolicy{ClusterVersionCheck: argoappv1.ClusterVersionCheckPreSync}
+		compRes, err := newController().appStateManager.CompareAppState(app, proj, []string{""}, []argoappv1.ApplicationSource{app.Spec.GetSource()}, false, false, nil, false, false)
+		require.NoError(t, err)
+
+		assert.Equal(t, argoappv1.SyncStatusCodeOutOfSync, compRes.syncStatus.Status)
+		require.Len(t, app.Status.Conditions, 1)
+		assert.Equal(t, argoappv1.ApplicationConditionSyncWarning, app.Status.Conditions[0].Type)
+		assert.Equal(t, "Deployment "+test.FakeDestNamespace+"/guestbook uses API version apps/v1beta1, which is removed in Kubernetes v1.16 and not served by the cluster, use apps/v1 instead", app.Status.Conditions[0].Message)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		app := newFakeApp()
+		_, err := newController().appStateManager.CompareAppState(app, &defaultProj, []string{""}, []argoappv1.ApplicationSource{app.Spec.GetSource()}, false, false, nil, false, false)
+		require.NoError(t, err)
+		assert.Empty(t, app.Status.Conditions)
+	})
+}
@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// InsufficientCapacityReason prefixes the message of the sync operations which failed because the destination cluster
+// did not have the capacity required by the pre-flight check of the application
+const InsufficientCapacityReason = "InsufficientCapacity"
+
+// clusterCapacity is the capacity available in a cluster
+type clusterCapacity struct {
+	// nodes is the number of ready and schedulable nodes
+	nodes  int64
+	cpu    resource.Quantity
+	memory resource.Quantity
+}
+
+// preFlightRequirements is the parsed capacity required by a pre-flight check
+type preFlightRequirements struct {
+	nodes  int64
+	cpu    *resource.Quantity
+	memory *resource.Quantity
+}
+
+// parsePreFlightCheck parses the capacity required by the given pre-flight check
+func parsePreFlightCheck(check *v1alpha1.PreFlightCheck) (*preFlightRequirements, error) {
+	if check.MinNodeCount < 0 {
+		return nil, fmt.Errorf("invalid pre-flight check: minimum node count %d must not be negative", check.MinNodeCount)
+	}
+	requirements := &preFlightRequirements{nodes: check.MinNodeCount}
+	if check.MinCPUAvailable != "" {
+		cpu, err := resource.ParseQuantity(check.MinCPUAvailable)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pre-flight check: minimum available CPU %q: %w", check.MinCPUAvailable, err)
+		}
+		requirements.cpu = &cpu
+	}
+	if check.MinMemoryAvailable != "" {
+		memory, err := resource.ParseQuantity(check.MinMemoryAvailable)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pre-flight check: minimum available memory %q: %w", check.MinMemoryAvailable, err)
+		}
+		requirements.memory = &memory
+	}
+	return requirements, nil
+}
+
+// isNodeSchedulable returns true if pods can be scheduled on the node
+func isNodeSchedulable(node *corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// podRequests returns the resources requested by the pod, which are the sum of the requests of its containers, or the
+// highest request of its init containers if higher, like the scheduler computes them
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	requests := corev1.ResourceList{}
+	for _, container := range pod.Spec.Containers {
+		for name, quantity := range container.Resources.Requests {
+			total := requests[name]
+			total.Add(quantity)
+			requests[name] = total
+		}
+	}
+	for _, container := range pod.Spec.InitContainers {
+		for name, quantity := range container.Resources.Requests {
+			if total, ok := requests[name]; !ok || quantity.Cmp(total) > 0 {
+				requests[name] = quantity.DeepCopy()
+			}
+		}
+	}
+	for name, quantity := range pod.Spec.Overhead {
+		total := requests[name]
+		total.Add(quantity)
+		requests[name] = total
+	}
+	return requests
+}
+
+// getClusterCapacity returns the capacity available in the cluster: the allocatable resources of the ready and
+// schedulable nodes, minus the resources requested by the pods which are not terminated on these nodes
+func getClusterCapacity(ctx context.Context, kubeClient kubernetes.Interface) (*clusterCapacity, error) {
+	nodes, err := kubeClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("error listing nodes: %w", err)
+	}
+	capacity := &clusterCapacity{}
+	schedulable := map[string]bool{}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if !isNodeSchedulable(node) {
+			continue
+		}
+		schedulable[node.Name] = true
+		capacity.nodes++
+		capacity.cpu.Add(*node.Status.Allocatable.Cpu())
+		capacity.memory.Add(*node.Status.Allocatable.Memory())
+	}
+	pods, err := kubeClient.CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("status.phase!=%s,status.phase!=%s", corev1.PodSucceeded, corev1.PodFailed),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing pods: %w", err)
+	}
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		if !schedulable[pod.Spec.NodeName] || pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		requests := podRequests(pod)
+		capacity.cpu.Sub(*requests.Cpu())
+		capacity.memory.Sub(*requests.Memory())
+	}
+	return capacity, nil
+}
+
+// checkCapacity returns an error listing the requirements which the capacity of the cluster does not meet
+func (r *preFlightRequirements) checkCapacity(capacity *clusterCapacity) error {
+	var insufficient []string
+	if capacity.nodes < r.nodes {
+		insufficient = append(insufficient, fmt.Sprintf("%d ready and schedulable nodes, %d required", capacity.nodes, r.nodes))
+	}
+	if r.cpu != nil && capacity.cpu.Cmp(*r.cpu) < 0 {
+		insufficient = append(insufficient, fmt.Sprintf("%s CPU available, %s required", capacity.cpu.String(), r.cpu.String()))
+	}
+	if r.memory != nil && capacity.memory.Cmp(*r.memory) < 0 {
+		insufficient = append(insufficient, fmt.Sprintf("%s memory available, %s required", capacity.memory.String(), r.memory.String()))
+	}
+	if len(insufficient) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: %s", InsufficientCapacityReason, strings.Join(insufficient, "; "))
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func newCapacityNode(name string, cpu string, memory string, ready bool) *corev1.Node {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)},
+			Conditions:  []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func newCapacityPod(name string, nodeName string, phase corev1.PodPhase, cpu string, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: corev1.PodSpec{
+			NodeName: nodeName,
+			Containers: []corev1.Container{{
+				Name: "main",
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu), corev1.ResourceMemory: resource.MustParse(memory)},
+				},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestParsePreFlightCheck(t *testing.T) {
+	requirements, err := parsePreFlightCheck(&v1alpha1.PreFlightCheck{MinNodeCount: 3, MinCPUAvailable: "500m", MinMemoryAvailable: "4Gi"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), requirements.nodes)
+	assert.Equal(t, "500m", requirements.cpu.String())
+	assert.Equal(t, "4Gi", requirements.memory.String())
+
+	requirements, err = parsePreFlightCheck(&v1alpha1.PreFlightCheck{MinNodeCount: 1})
+	require.NoError(t, err)
+	assert.Nil(t, requirements.cpu)
+	assert.Nil(t, requirements.memory)
+
+	_, err = parsePreFlightCheck(&v1alpha1.PreFlightCheck{MinNodeCount: -1})
+	require.ErrorContains(t, err, "must not be negative")
+	_, err = parsePreFlightCheck(&v1alpha1.PreFlightCheck{MinCPUAvailable: "two"})
+	require.ErrorContains(t, err, `minimum available CPU "two"`)
+	_, err = parsePreFlightCheck(&v1alpha1.PreFlightCheck{MinMemoryAvailable: "lots"})
+	require.ErrorContains(t, err, `minimum available memory "lots"`)
+}
+
+func TestGetClusterCapacity(t *testing.T) {
+	cordoned := newCapacityNode("cordoned", "8", "32Gi", true)
+	cordoned.Spec.Unschedulable = true
+	initPod := newCapacityPod("init", "node-2", corev1.PodPending, "100m", "128Mi")
+	initPod.Spec.InitContainers = []corev1.Container{{
+		Name: "init",
+		Resources: corev1.ResourceRequirements{
+			Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("64Mi")},
+		},
+	}}
+	kubeClient := fake.NewSimpleClientset([]runtime.Object{
+		newCapacityNode("node-1", "4", "16Gi", true),
+		newCapacityNode("node-2", "4", "16Gi", true),
+		newCapacityNode("not-ready", "8", "32Gi", false),
+		cordoned,
+		newCapacityPod("running", "node-1", corev1.PodRunning, "1500m", "4Gi"),
+		newCapacityPod("completed", "node-1", corev1.PodSucceeded, "2", "8Gi"),
+		newCapacityPod("on-cordoned-node", "cordoned", corev1.PodRunning, "2", "8Gi"),
+		newCapacityPod("unscheduled", "", corev1.PodPending, "2", "8Gi"),
+		initPod,
+	}...)
+
+	capacity, err := getClusterCapacity(context.Background(), kubeClient)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), capacity.nodes)
+	// the init container requests more CPU than the containers of its pod, but less memory
+	assert.Equal(t, "5500m", capacity.cpu.String())
+	assert.Equal(t, int64(28*1024*1024*1024-128*1024*1024), capacity.memory.Value())
+}
+
+func TestPreFlightRequirements_CheckCapacity(t *testing.T) {
+	capacity := &clusterCapacity{nodes: 3, cpu: resource.MustParse("2"), memory: resource.MustParse("8Gi")}
+
+	t.Run("Sufficient", func(t *testing.T) {
+		requirements, err := parsePreFlightCheck(&v1alpha1.PreFlightCheck{MinNodeCount: 3, MinCPUAvailable: "2", MinMemoryAvailable: "4Gi"})
+		require.NoError(t, err)
+		require.NoError(t, requirements.checkCapacity(capacity))
+	})
+
+	t.Run("NotEnoughNodes", func(t *testing.T) {
+		requirements, err := parsePreFlightCheck(&v1alpha1.PreFlightCheck{MinNodeCount: 5})
+		require.NoError(t, err)
+		assert.EqualError(t, requirements.checkCapacity(capacity), "InsufficientCapacity: 3 ready and schedulable nodes, 5 required")
+	})
+
+	t.Run("NotEnoughCPUAndMemory", func(t *testing.T) {
+		requirements, err := parsePreFlightCheck(&v1alpha1.PreFlightCheck{MinCPUAvailable: "2500m", MinMemoryAvailable: "16Gi"})
+		require.NoError(t, err)
+		assert.EqualError(t, requirements.checkCapacity(capacity), "InsufficientCapacity: 2 CPU available, 2500m required; 8Gi memory available, 16Gi required")
+	})
+
+	t.Run("OvercommittedCluster", func(t *testing.T) {
+		overcommitted := &clusterCapacity{nodes: 1, cpu: resource.MustParse("-1"), memory: resource.MustParse("1Gi")}
+		requirements, err := parsePreFlightCheck(&v1alpha1.PreFlightCheck{MinCPUAvailable: "0"})
+		require.NoError(t, err)
+		require.ErrorContains(t, requirements.checkCapacity(overcommitted), "-1 CPU available, 0 required")
+	})
+}
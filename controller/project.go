@@ -0,0 +1,136 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/argoproj/gitops-engine/pkg/sync/hook"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// appResourceUsage holds the number of resources of each group kind managed by a single application
+type appResourceUsage struct {
+	project string
+	counts  map[string]int64
+}
+
+// projectResourceUsage aggregates the resources managed by applications per project. It is fed by the
+// application informer and used to enforce the namespaceResourceQuota of projects before syncing.
+type projectResourceUsage struct {
+	lock sync.RWMutex
+	// apps maps the qualified application name to its resource usage
+	apps map[string]appResourceUsage
+}
+
+func newProjectResourceUsage() *projectResourceUsage {
+	return &projectResourceUsage{apps: make(map[string]appResourceUsage)}
+}
+
+func quotaKey(group, kind string) string {
+	return schema.GroupKind{Group: group, Kind: kind}.String()
+}
+
+// resourceCounts returns the number of resources of each group kind currently managed by the given application
+func resourceCounts(app *v1alpha1.Application) map[string]int64 {
+	counts := make(map[string]int64)
+	for _, res := range app.Status.Resources {
+		counts[quotaKey(res.Group, res.Kind)]++
+	}
+	return counts
+}
+
+// setApp records the resources currently managed by the given application
+func (u *projectResourceUsage) setApp(app *v1alpha1.Application) {
+	counts := resourceCounts(app)
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	u.apps[app.QualifiedName()] = appResourceUsage{project: app.Spec.GetProject(), counts: counts}
+}
+
+// deleteApp forgets the resources of the given application
+func (u *projectResourceUsage) deleteApp(app *v1alpha1.Application) {
+	u.lock.Lock()
+	defer u.lock.Unlock()
+	delete(u.apps, app.QualifiedName())
+}
+
+// usage returns the number of resources of each group kind managed by the applications of the given project,
+// not counting the application with the qualified name exceptApp
+func (u *projectResourceUsage) usage(project string, exceptApp string) map[string]int64 {
+	u.lock.RLock()
+	defer u.lock.RUnlock()
+	total := make(map[string]int64)
+	for name, app := range u.apps {
+		if app.project != project || name == exceptApp {
+			continue
+		}
+		for key, count := range app.counts {
+			total[key] += count
+		}
+	}
+	return total
+}
+
+// checkQuota returns an error if syncing the given target objects of the application would exceed
+// the resource quota of its project. Only the group kinds of which the application would manage more resources
+// than it currently does are checked, so that an application is neither blocked by a project already over its
+// quota because of other applications nor prevented from reducing its own usage.
+func (u *projectResourceUsage) checkQuota(proj *v1alpha1.AppProject, app *v1alpha1.Application, targets []*unstructured.Unstructured) error {
+	if u == nil || proj == nil || len(proj.Spec.NamespaceResourceQuota) == 0 {
+		return nil
+	}
+	target := make(map[string]int64)
+	for _, obj := range targets {
+		if obj == nil || hook.IsHook(obj) {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		target[quotaKey(gvk.Group, gvk.Kind)]++
+	}
+	live := resourceCounts(app)
+	others := u.usage(proj.Name, app.QualifiedName())
+	var exceeded []string
+	for key, limit := range proj.Spec.NamespaceResourceQuota {
+		if target[key] <= live[key] {
+			continue
+		}
+		if total := others[key] + target[key]; total > limit {
+			exceeded = append(exceeded, fmt.Sprintf("%s (%d/%d)", key, total, limit))
+		}
+	}
+	if len(exceeded) == 0 {
+		return nil
+	}
+	sort.Strings(exceeded)
+	return fmt.Errorf("project %s resource quota exceeded: %s", proj.Name, strings.Join(exceeded, ", "))
+}
+
+// eventHandler returns the handler which keeps the usage in sync with the application informer
+func (u *projectResourceUsage) eventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if app, ok := obj.(*v1alpha1.Application); ok {
+				u.setApp(app)
+			}
+		},
+		UpdateFunc: func(_, new interface{}) {
+			if app, ok := new.(*v1alpha1.Application); ok {
+				u.setApp(app)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if app, ok := obj.(*v1alpha1.Application); ok {
+				u.deleteApp(app)
+			}
+		},
+	}
+}
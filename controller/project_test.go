@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+func newQuotaApp(name string, resources ...v1alpha1.ResourceStatus) *v1alpha1.Application {
+	return &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: test.FakeArgoCDNamespace},
+		Spec:       v1alpha1.ApplicationSpec{Project: "staging"},
+		Status:     v1alpha1.ApplicationStatus{Resources: resources},
+	}
+}
+
+func newQuotaProject() *v1alpha1.AppProject {
+	return &v1alpha1.AppProject{
+		ObjectMeta: metav1.ObjectMeta{Name: "staging", Namespace: test.FakeArgoCDNamespace},
+		Spec: v1alpha1.AppProjectSpec{
+			NamespaceResourceQuota: map[string]int64{"Deployment.apps": 3, "ConfigMap": 2},
+		},
+	}
+}
+
+func newQuotaObject(apiVersion, kind string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": apiVersion,
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": "my-resource", "namespace": test.FakeDestNamespace},
+	}}
+}
+
+func TestProjectResourceUsage_CheckQuota(t *testing.T) {
+	usage := newProjectResourceUsage()
+	deployment := v1alpha1.ResourceStatus{Group: "apps", Kind: "Deployment"}
+	configMap := v1alpha1.ResourceStatus{Kind: "ConfigMap"}
+	app1 := newQuotaApp("app1", deployment, configMap)
+	app2 := newQuotaApp("app2", deployment)
+	app3 := newQuotaApp("app3")
+	usage.setApp(app1)
+	usage.setApp(app2)
+	usage.setApp(app3)
+	// applications of other projects are not counted
+	other := newQuotaApp("other", deployment, deployment)
+	other.Spec.Project = "default"
+	usage.setApp(other)
+
+	assert.Equal(t, map[string]int64{"Deployment.apps": 2, "ConfigMap": 1}, usage.usage("staging", ""))
+	proj := newQuotaProject()
+
+	t.Run("WithinQuota", func(t *testing.T) {
+		targets := []*unstructured.Unstructured{newQuotaObject("apps/v1", "Deployment"), newQuotaObject("v1", "ConfigMap")}
+		assert.NoError(t, usage.checkQuota(proj, app3, targets))
+	})
+
+	t.Run("ExceedsQuota", func(t *testing.T) {
+		targets := []*unstructured.Unstructured{newQuotaObject("apps/v1", "Deployment"), newQuotaObject("apps/v1", "Deployment"), newQuotaObject("v1", "ConfigMap")}
+		err := usage.checkQuota(proj, app3, targets)
+		require.Error(t, err)
+		assert.Equal(t, "project staging resource quota exceeded: Deployment.apps (4/3)", err.Error())
+	})
+
+	t.Run("OwnResourcesAreReplaced", func(t *testing.T) {
+		// app1 already manages a deployment, syncing the same set of resources does not increase the usage
+		targets := []*unstructured.Unstructured{newQuotaObject("apps/v1", "Deployment"), newQuotaObject("v1", "ConfigMap")}
+		assert.NoError(t, usage.checkQuota(proj, app1, targets))
+	})
+
+	t.Run("DeletedApp", func(t *testing.T) {
+		usage.deleteApp(app2)
+		defer usage.setApp(app2)
+		targets := []*unstructured.Unstructured{newQuotaObject("apps/v1", "Deployment"), newQuotaObject("apps/v1", "Deployment")}
+		assert.NoError(t, usage.checkQuota(proj, app3, targets))
+	})
+
+	t.Run("UnrelatedAppOfProjectOverQuota", func(t *testing.T) {
+		// the quota was lowered below the usage of app1 and app2, which does not block app3 from syncing the same
+		// number of deployments it already manages, nor resources of other kinds
+		lowered := newQuotaProject()
+		lowered.Spec.NamespaceResourceQuota["Deployment.apps"] = 1
+		targets := []*unstructured.Unstructured{newQuotaObject("v1", "ConfigMap")}
+		assert.NoError(t, usage.checkQuota(lowered, app3, targets))
+
+		targets = append(targets, newQuotaObject("apps/v1", "Deployment"))
+		err := usage.checkQuota(lowered, app3, targets)
+		require.Error(t, err)
+		assert.Equal(t, "project staging resource quota exceeded: Deployment.apps (3/1)", err.Error())
+	})
+
+	t.Run("SyncReducingUsage", func(t *testing.T) {
+		// app1 manages two config maps while the project is over its quota of config maps, removing one of them is allowed
+		app1 := newQuotaApp("app1", deployment, configMap, configMap)
+		usage.setApp(app1)
+		defer usage.setApp(newQuotaApp("app1", deployment, configMap))
+		usage.setApp(newQuotaApp("app4", configMap, configMap))
+		defer usage.deleteApp(newQuotaApp("app4"))
+		targets := []*unstructured.Unstructured{newQuotaObject("apps/v1", "Deployment"), newQuotaObject("v1", "ConfigMap")}
+		assert.NoError(t, usage.checkQuota(proj, app1, targets))
+	})
+
+	t.Run("NoQuota", func(t *testing.T) {
+		targets := []*unstructured.Unstructured{newQuotaObject("apps/v1", "Deployment"), newQuotaObject("apps/v1", "Deployment"), newQuotaObject("apps/v1", "Deployment")}
+		assert.NoError(t, usage.checkQuota(&v1alpha1.AppProject{ObjectMeta: metav1.ObjectMeta{Name: "staging"}}, app3, targets))
+	})
+}
+
+func TestProjectResourceUsage_EventHandler(t *testing.T) {
+	usage := newProjectResourceUsage()
+	handler := usage.eventHandler()
+	app := newQuotaApp("app1", v1alpha1.ResourceStatus{Kind: "ConfigMap"})
+
+	handler.OnAdd(app, false)
+	assert.Equal(t, map[string]int64{"ConfigMap": 1}, usage.usage("staging", ""))
+
+	updated := app.DeepCopy()
+	updated.Status.Resources = append(updated.Status.Resources, v1alpha1.ResourceStatus{Kind: "ConfigMap"})
+	handler.OnUpdate(app, updated)
+	assert.Equal(t, map[string]int64{"ConfigMap": 2}, usage.usage("staging", ""))
+
+	handler.OnDelete(cache.DeletedFinalStateUnknown{Key: "argocd/app1", Obj: updated})
+	assert.Empty(t, usage.usage("staging", ""))
+}
+
+func TestProjectResourceUsage_Nil(t *testing.T) {
+	var usage *projectResourceUsage
+	assert.NoError(t, usage.checkQuota(newQuotaProject(), newQuotaApp("app1"), []*unstructured.Unstructured{newQuotaObject("v1", "ConfigMap")}))
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// queueHealthSampleInterval is the interval at which the depth of the reconcile queue is sampled
+const queueHealthSampleInterval = 10 * time.Second
+
+// QueueHealthMonitor detects a reconcile queue which is stuck, e.g. because all the processors are deadlocked, by
+// periodically sampling its depth. The queue makes progress when it is empty or when its depth decreased since the
+// previous sample, and is unhealthy if it did not make progress within the window.
+type QueueHealthMonitor struct {
+	depth  func() int
+	window time.Duration
+	now    func() time.Time
+
+	lock         sync.RWMutex
+	lastDepth    int
+	lastProgress time.Time
+}
+
+// NewQueueHealthMonitor returns a monitor of the queue whose depth is returned by depth. A window of 0 disables the
+// health check.
+func NewQueueHealthMonitor(depth func() int, window time.Duration) *QueueHealthMonitor {
+	return &QueueHealthMonitor{
+		depth:  depth,
+		window: window,
+		now:    time.Now,
+	}
+}
+
+// Run samples the depth of the queue every interval until the context is done. The queue is considered to make
+// progress when Run starts, so that the time spent before processing the queue, e.g. to sync the caches, does not
+// count towards the window.
+func (m *QueueHealthMonitor) Run(ctx context.Context, interval time.Duration) {
+	if m.window <= 0 {
+		return
+	}
+	m.lock.Lock()
+	m.lastProgress = m.now()
+	m.lastDepth = m.depth()
+	m.lock.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Sample()
+		}
+	}
+}
+
+// Sample records the current depth of the queue
+func (m *QueueHealthMonitor) Sample() {
+	depth := m.depth()
+	now := m.now()
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if depth == 0 || depth < m.lastDepth || m.lastProgress.IsZero() {
+		m.lastProgress = now
+	}
+	m.lastDepth = depth
+}
+
+// Check returns an error if the queue holds applications and did not make progress within the window
+func (m *QueueHealthMonitor) Check() error {
+	if m.window <= 0 {
+		return nil
+	}
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	if m.lastDepth == 0 || m.lastProgress.IsZero() {
+		return nil
+	}
+	if stuckFor := m.now().Sub(m.lastProgress); stuckFor > m.window {
+		return fmt.Errorf("the %d applications of the reconcile queue were not processed for %s, exceeding the window of %s", m.lastDepth, stuckFor.Round(time.Second), m.window)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package controller
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// newTestQueueHealthMonitor returns a monitor of the given queue whose clock is advanced by the returned function
+func newTestQueueHealthMonitor(queue workqueue.Interface, window time.Duration) (*QueueHealthMonitor, func(time.Duration)) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	monitor := NewQueueHealthMonitor(queue.Len, window)
+	monitor.now = func() time.Time {
+		return now
+	}
+	return monitor, func(d time.Duration) {
+		now = now.Add(d)
+	}
+}
+
+func TestQueueHealthMonitor(t *testing.T) {
+	t.Run("EmptyQueue", func(t *testing.T) {
+		queue := workqueue.New()
+		defer queue.ShutDown()
+		monitor, advance := newTestQueueHealthMonitor(queue, 5*time.Minute)
+		monitor.Sample()
+		advance(time.Hour)
+		monitor.Sample()
+		assert.NoError(t, monitor.Check())
+	})
+
+	t.Run("StuckQueue", func(t *testing.T) {
+		queue := workqueue.New()
+		defer queue.ShutDown()
+		monitor, advance := newTestQueueHealthMonitor(queue, 5*time.Minute)
+		queue.Add("argocd/guestbook")
+		queue.Add("argocd/helm-guestbook")
+		monitor.Sample()
+
+		// no application is processed, e.g. because all the processors are deadlocked
+		for i := 0; i < 5; i++ {
+			advance(time.Minute)
+			monitor.Sample()
+			require.NoError(t, monitor.Check())
+		}
+		queue.Add("argocd/kustomize-guestbook")
+		advance(time.Minute)
+		monitor.Sample()
+		assert.ErrorContains(t, monitor.Check(), "the 3 applications of the reconcile queue were not processed for 6m0s")
+
+		// the queue recovers as soon as an application is processed
+		item, _ := queue.Get()
+		queue.Done(item)
+		monitor.Sample()
+		assert.NoError(t, monitor.Check())
+	})
+
+	t.Run("BusyQueue", func(t *testing.T) {
+		queue := workqueue.New()
+		defer queue.ShutDown()
+		monitor, advance := newTestQueueHealthMonitor(queue, 5*time.Minute)
+		for i := 0; i < 10; i++ {
+			queue.Add(i)
+		}
+		monitor.Sample()
+		// applications keep being added, but the depth decreases from time to time
+		for i := 0; i < 10; i++ {
+			advance(2 * time.Minute)
+			queue.Add(100 + i)
+			monitor.Sample()
+			item, _ := queue.Get()
+			queue.Done(item)
+			item, _ = queue.Get()
+			queue.Done(item)
+			advance(2 * time.Minute)
+			monitor.Sample()
+			require.NoError(t, monitor.Check())
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		queue := workqueue.New()
+		defer queue.ShutDown()
+		monitor, advance := newTestQueueHealthMonitor(queue, 0)
+		queue.Add("argocd/guestbook")
+		monitor.Sample()
+		advance(time.Hour)
+		monitor.Sample()
+		assert.NoError(t, monitor.Check())
+	})
+}
+
+func TestQueueHealthMonitor_Run(t *testing.T) {
+	var depth atomic.Int32
+	depth.Store(1)
+	monitor := NewQueueHealthMonitor(func() int {
+		return int(depth.Load())
+	}, 50*time.Millisecond)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go monitor.Run(ctx, 5*time.Millisecond)
+
+	assert.Eventually(t, func() bool {
+		return monitor.Check() != nil
+	}, 5*time.Second, 5*time.Millisecond)
+	depth.Store(0)
+	assert.Eventually(t, func() bool {
+		return monitor.Check() == nil
+	}, 5*time.Second, 5*time.Millisecond)
+}
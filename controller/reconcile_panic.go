@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"runtime/debug"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+const (
+	// reconcilePanicBaseDelay is the delay of the next reconciliation of an application after its first panic
+	reconcilePanicBaseDelay = 10 * time.Second
+	// reconcilePanicMaxDelay caps the delay of the next reconciliation of an application which keeps panicking
+	reconcilePanicMaxDelay = 30 * time.Minute
+)
+
+// reconcilePanicBackoff quarantines the applications whose reconciliation panicked. The reconciliation of a
+// quarantined application is skipped until its delay elapsed, which doubles with every consecutive panic, so that an
+// application which keeps panicking does not consume the reconciliation queue.
+type reconcilePanicBackoff struct {
+	lock        sync.Mutex
+	rateLimiter workqueue.RateLimiter
+	// quarantined maps the key of an application to the time its quarantine ends
+	quarantined map[string]time.Time
+	now         func() time.Time
+}
+
+func newReconcilePanicBackoff(baseDelay, maxDelay time.Duration) *reconcilePanicBackoff {
+	return &reconcilePanicBackoff{
+		rateLimiter: workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		quarantined: make(map[string]time.Time),
+		now:         time.Now,
+	}
+}
+
+// quarantine records a panic of the reconciliation of the application and returns the delay of its next reconciliation
+func (b *reconcilePanicBackoff) quarantine(key string) time.Duration {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delay := b.rateLimiter.When(key)
+	b.quarantined[key] = b.now().Add(delay)
+	return delay
+}
+
+// isQuarantined returns whether the reconciliation of the application is skipped after a recent panic
+func (b *reconcilePanicBackoff) isQuarantined(key string) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	until, ok := b.quarantined[key]
+	return ok && b.now().Before(until)
+}
+
+// forget resets the delay of the application once it reconciled without panicking or got deleted
+func (b *reconcilePanicBackoff) forget(key string) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if _, ok := b.quarantined[key]; ok {
+		delete(b.quarantined, key)
+		b.rateLimiter.Forget(key)
+	}
+}
+
+// handleReconcilePanic logs the recovered panic of the reconciliation of an application, counts it and re-queues the
+// application once its quarantine ends
+func (ctrl *ApplicationController) handleReconcilePanic(appKey string, r interface{}) {
+	delay := ctrl.reconcilePanicBackoff.quarantine(appKey)
+	log.WithField("application", appKey).Errorf("Recovered from panic while reconciling application, retrying in %v: %+v\n%s", delay, r, debug.Stack())
+
+	app := &appv1.Application{}
+	if obj, exists, err := ctrl.appInformer.GetIndexer().GetByKey(appKey); err == nil && exists {
+		if informerApp, ok := obj.(*appv1.Application); ok {
+			app = informerApp
+		}
+	} else if namespace, name, err := cache.SplitMetaNamespaceKey(appKey); err == nil {
+		app.ObjectMeta = metav1.ObjectMeta{Namespace: namespace, Name: name}
+	}
+	if ctrl.metricsServer != nil {
+		ctrl.metricsServer.IncReconcilePanic(app)
+	}
+	ctrl.appRefreshQueue.AddAfter(appKey, delay)
+}
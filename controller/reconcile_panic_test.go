@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+// panickingAppStateManager panics when comparing the state of an application, like a malformed resource would
+type panickingAppStateManager struct {
+	AppStateManager
+	comparisons int
+}
+
+func (m *panickingAppStateManager) CompareAppState(*v1alpha1.Application, *v1alpha1.AppProject, []string, []v1alpha1.ApplicationSource, bool, bool, []string, bool, bool) (*comparisonResult, error) {
+	m.comparisons++
+	panic("malformed resource")
+}
+
+func TestReconcilePanicBackoff(t *testing.T) {
+	now := time.Now()
+	backoff := newReconcilePanicBackoff(10*time.Second, 30*time.Second)
+	backoff.now = func() time.Time { return now }
+
+	assert.False(t, backoff.isQuarantined("argocd/guestbook"))
+	assert.Equal(t, 10*time.Second, backoff.quarantine("argocd/guestbook"))
+	assert.Equal(t, 20*time.Second, backoff.quarantine("argocd/guestbook"))
+	assert.Equal(t, 30*time.Second, backoff.quarantine("argocd/guestbook"))
+	assert.True(t, backoff.isQuarantined("argocd/guestbook"))
+	assert.False(t, backoff.isQuarantined("argocd/other"))
+
+	now = now.Add(31 * time.Second)
+	assert.False(t, backoff.isQuarantined("argocd/guestbook"))
+
+	backoff.forget("argocd/guestbook")
+	assert.Equal(t, 10*time.Second, backoff.quarantine("argocd/guestbook"))
+}
+
+func TestProcessAppRefreshQueueItem_Panic(t *testing.T) {
+	app := newFakeApp()
+	// the panic counter is shared by the controllers of all the tests, which also reconcile my-app
+	app.Name = "panicking-app"
+	ctrl := newFakeController(&fakeData{
+		apps: []runtime.Object{app, &defaultProj},
+		manifestResponse: &apiclient.ManifestResponse{
+			Manifests: []string{},
+			Namespace: test.FakeDestNamespace,
+			Server:    test.FakeClusterURL,
+			Revision:  "abc123",
+		},
+		managedLiveObjs: make(map[kube.ResourceKey]*unstructured.Unstructured),
+	}, nil)
+	now := time.Now()
+	ctrl.reconcilePanicBackoff.now = func() time.Time { return now }
+	stateManager := ctrl.appStateManager
+	panicking := &panickingAppStateManager{AppStateManager: stateManager}
+	ctrl.appStateManager = panicking
+	key, _ := cache.MetaNamespaceKeyFunc(app)
+
+	ctrl.requestAppRefresh(app.Name, CompareWithLatest.Pointer(), nil)
+	ctrl.appRefreshQueue.Add(key)
+	assert.True(t, ctrl.processAppRefreshQueueItem())
+	assert.Equal(t, 1, panicking.comparisons)
+	assert.True(t, ctrl.reconcilePanicBackoff.isQuarantined(key))
+
+	req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+	require.NoError(t, err)
+	rr := httptest.NewRecorder()
+	ctrl.metricsServer.Handler.ServeHTTP(rr, req)
+	assert.Contains(t, rr.Body.String(), `argocd_controller_reconcile_panic_total{name="panicking-app",namespace="`+test.FakeArgoCDNamespace+`",project="default"} 1`)
+
+	// the reconciliation of the quarantined application is skipped
+	ctrl.requestAppRefresh(app.Name, CompareWithLatest.Pointer(), nil)
+	ctrl.appRefreshQueue.Add(key)
+	assert.True(t, ctrl.processAppRefreshQueueItem())
+	assert.Equal(t, 1, panicking.comparisons)
+
+	// the application is reconciled again once the quarantine ends
+	now = now.Add(reconcilePanicBaseDelay + time.Second)
+	ctrl.appStateManager = stateManager
+	ctrl.appRefreshQueue.Add(key)
+	assert.True(t, ctrl.processAppRefreshQueueItem())
+	ctrl.reconcilePanicBackoff.lock.Lock()
+	assert.NotContains(t, ctrl.reconcilePanicBackoff.quarantined, key)
+	ctrl.reconcilePanicBackoff.lock.Unlock()
+}
@@ -0,0 +1,302 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned"
+	"github.com/argoproj/argo-cd/v2/pkg/client/informers/externalversions/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/db"
+	"github.com/argoproj/argo-cd/v2/util/git"
+)
+
+// repositoryConfigResyncPeriod is the period every RepositoryConfig is reconciled at, which picks up the changes of
+// the Secrets referenced by their sensitive values
+const repositoryConfigResyncPeriod = 3 * time.Minute
+
+// RepositoryConfigController reconciles the RepositoryConfig resources of the Argo CD namespace into repository
+// secrets, the credential store of the repositories read by the API server and the repo server. Every configuration
+// owns the secret named repoconfig-<name>, which is garbage collected with the configuration. The sensitive values of
+// the configurations can only reference the Secrets labeled as repository sources, so that a configuration cannot copy
+// the other secrets of the namespace, e.g. the cluster credentials, to a repository whose URL it controls.
+type RepositoryConfigController struct {
+	namespace            string
+	kubeClientset        kubernetes.Interface
+	applicationClientset appclientset.Interface
+	informer             cache.SharedIndexInformer
+	queue                workqueue.RateLimitingInterface
+}
+
+// NewRepositoryConfigController returns a new controller of the RepositoryConfig resources of the given namespace
+func NewRepositoryConfigController(namespace string, kubeClientset kubernetes.Interface, applicationClientset appclientset.Interface) *RepositoryConfigController {
+	c := &RepositoryConfigController{
+		namespace:            namespace,
+		kubeClientset:        kubeClientset,
+		applicationClientset: applicationClientset,
+		informer:             v1alpha1.NewRepositoryConfigInformer(applicationClientset, namespace, repositoryConfigResyncPeriod, cache.Indexers{}),
+		queue:                workqueue.NewRateLimitingQueueWithConfig(workqueue.DefaultControllerRateLimiter(), workqueue.RateLimitingQueueConfig{Name: "repository_config_queue"}),
+	}
+	_, err := c.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: c.enqueue,
+		UpdateFunc: func(old, new interface{}) {
+			oldConfig, oldOK := old.(*appv1.RepositoryConfig)
+			newConfig, newOK := new.(*appv1.RepositoryConfig)
+			// status updates do not change the generation, resyncs do not change the resource version
+			if oldOK && newOK && oldConfig.Generation == newConfig.Generation && oldConfig.ResourceVersion != newConfig.ResourceVersion {
+				return
+			}
+			c.enqueue(new)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj); err == nil {
+				c.queue.Add(key)
+			}
+		},
+	})
+	if err != nil {
+		log.Errorf("Failed to register the RepositoryConfig event handler: %v", err)
+	}
+	return c
+}
+
+func (c *RepositoryConfigController) enqueue(obj interface{}) {
+	if key, err := cache.MetaNamespaceKeyFunc(obj); err == nil {
+		c.queue.Add(key)
+	}
+}
+
+// Run reconciles the RepositoryConfig resources with the given number of workers until the context is done
+func (c *RepositoryConfigController) Run(ctx context.Context, workers int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	if _, err := c.applicationClientset.ArgoprojV1alpha1().RepositoryConfigs(c.namespace).List(ctx, metav1.ListOptions{Limit: 1}); apierr.IsNotFound(err) {
+		log.Warnf("The %s CRD is not installed, RepositoryConfig resources are not reconciled", application.RepositoryConfigFullName)
+		return
+	}
+
+	go c.informer.Run(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), c.informer.HasSynced) {
+		log.Error("Timed out waiting for the RepositoryConfig cache to sync")
+		return
+	}
+	for i := 0; i < workers; i++ {
+		go wait.UntilWithContext(ctx, func(ctx context.Context) {
+			for c.processNextItem(ctx) {
+			}
+		}, time.Second)
+	}
+	<-ctx.Done()
+}
+
+func (c *RepositoryConfigController) processNextItem(ctx context.Context) bool {
+	key, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer c.queue.Done(key)
+
+	if err := c.reconcile(ctx, key.(string)); err != nil {
+		log.WithField("repositoryconfig", key).Warnf("Failed to reconcile RepositoryConfig: %v", err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+// reconcile writes the repository secret of the RepositoryConfig of the given key, or deletes it if the configuration
+// no longer exists, and records the outcome in the status of the configuration
+func (c *RepositoryConfigController) reconcile(ctx context.Context, key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return err
+	}
+	obj, exists, err := c.informer.GetIndexer().GetByKey(key)
+	if err != nil {
+		return fmt.Errorf("error getting RepositoryConfig %s: %w", key, err)
+	}
+	if !exists {
+		return c.deleteSecrets(ctx, name)
+	}
+	repoConfig, ok := obj.(*appv1.RepositoryConfig)
+	if !ok {
+		return fmt.Errorf("unexpected object of type %T for RepositoryConfig %s", obj, key)
+	}
+
+	status := appv1.RepositoryConfigStatus{SecretName: repoConfig.Status.SecretName, ObservedGeneration: repoConfig.Generation}
+	secretName, reconcileErr := c.reconcileSecret(ctx, repoConfig)
+	if reconcileErr != nil {
+		status.Error = reconcileErr.Error()
+	} else {
+		status.SecretName = secretName
+	}
+	if status != repoConfig.Status {
+		updated := repoConfig.DeepCopy()
+		updated.Status = status
+		if _, err := c.applicationClientset.ArgoprojV1alpha1().RepositoryConfigs(repoConfig.Namespace).UpdateStatus(ctx, updated, metav1.UpdateOptions{}); err != nil {
+			return fmt.Errorf("error updating status of RepositoryConfig %s: %w", key, err)
+		}
+	}
+	return reconcileErr
+}
+
+// reconcileSecret creates or updates the repository secret of a RepositoryConfig and returns its name
+func (c *RepositoryConfigController) reconcileSecret(ctx context.Context, repoConfig *appv1.RepositoryConfig) (string, error) {
+	secretName := repositoryConfigSecretName(repoConfig.Name)
+	desired, err := c.repositorySecret(ctx, repoConfig, secretName)
+	if err != nil {
+		return "", err
+	}
+	if err := c.checkConflicts(ctx, repoConfig, secretName); err != nil {
+		return "", err
+	}
+
+	secrets := c.kubeClientset.CoreV1().Secrets(c.namespace)
+	existing, err := secrets.Get(ctx, secretName, metav1.GetOptions{})
+	if apierr.IsNotFound(err) {
+		if _, err := secrets.Create(ctx, desired, metav1.CreateOptions{}); err != nil {
+			return "", fmt.Errorf("error creating secret %s: %w", secretName, err)
+		}
+		log.WithField("repositoryconfig", repoConfig.Name).Infof("Created repository secret %s", secretName)
+		return secretName, nil
+	} else if err != nil {
+		return "", fmt.Errorf("error getting secret %s: %w", secretName, err)
+	}
+	if existing.Labels[common.LabelKeyRepositoryConfig] != repoConfig.Name {
+		return "", fmt.Errorf("secret %s already exists and is not managed by the RepositoryConfig", secretName)
+	}
+	if reflect.DeepEqual(existing.Data, desired.Data) && reflect.DeepEqual(existing.Labels, desired.Labels) &&
+		reflect.DeepEqual(existing.Annotations, desired.Annotations) && reflect.DeepEqual(existing.OwnerReferences, desired.OwnerReferences) {
+		return secretName, nil
+	}
+	existing.Data = desired.Data
+	existing.Labels = desired.Labels
+	existing.Annotations = desired.Annotations
+	existing.OwnerReferences = desired.OwnerReferences
+	if _, err := secrets.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return "", fmt.Errorf("error updating secret %s: %w", secretName, err)
+	}
+	log.WithField("repositoryconfig", repoConfig.Name).Infof("Updated repository secret %s", secretName)
+	return secretName, nil
+}
+
+// repositorySecret returns the repository secret of a RepositoryConfig, with the sensitive values resolved
+func (c *RepositoryConfigController) repositorySecret(ctx context.Context, repoConfig *appv1.RepositoryConfig, secretName string) (*v1.Secret, error) {
+	spec := repoConfig.Spec
+	repo := &appv1.Repository{
+		Repo:              spec.URL,
+		Type:              spec.Type,
+		Name:              spec.Name,
+		Project:           spec.Project,
+		Username:          spec.Username,
+		TLSClientCertData: spec.TLSClientCertData,
+		Insecure:          spec.Insecure,
+		EnableLFS:         spec.EnableLFS,
+		EnableOCI:         spec.EnableOCI,
+		Proxy:             spec.Proxy,
+	}
+	var err error
+	if repo.Password, err = c.secretValue(ctx, spec.Password); err != nil {
+		return nil, fmt.Errorf("error resolving password: %w", err)
+	}
+	if repo.SSHPrivateKey, err = c.secretValue(ctx, spec.SSHPrivateKey); err != nil {
+		return nil, fmt.Errorf("error resolving sshPrivateKey: %w", err)
+	}
+	if repo.TLSClientCertKey, err = c.secretValue(ctx, spec.TLSClientCertKey); err != nil {
+		return nil, fmt.Errorf("error resolving tlsClientCertKey: %w", err)
+	}
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      secretName,
+			Namespace: c.namespace,
+			Labels:    map[string]string{common.LabelKeyRepositoryConfig: repoConfig.Name},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(repoConfig, appv1.SchemeGroupVersion.WithKind(application.RepositoryConfigKind)),
+			},
+		},
+	}
+	db.RepositoryToSecret(repo, secret)
+	return secret, nil
+}
+
+// secretValue returns the value of the Secret key referenced by a sensitive value. The Secret must be labeled as a
+// repository source.
+func (c *RepositoryConfigController) secretValue(ctx context.Context, value *appv1.RepositoryConfigSecretValue) (string, error) {
+	if value == nil || value.ValueFrom.SecretKeyRef == nil {
+		return "", nil
+	}
+	ref := value.ValueFrom.SecretKeyRef
+	optional := ref.Optional != nil && *ref.Optional
+	secret, err := c.kubeClientset.CoreV1().Secrets(c.namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierr.IsNotFound(err) && optional {
+			return "", nil
+		}
+		return "", fmt.Errorf("error getting secret %s: %w", ref.Name, err)
+	}
+	if secret.Labels[common.LabelKeySecretType] != common.LabelValueSecretTypeRepositorySource {
+		return "", fmt.Errorf("secret %s cannot be referenced: it is not labeled with %s=%s", ref.Name, common.LabelKeySecretType, common.LabelValueSecretTypeRepositorySource)
+	}
+	data, ok := secret.Data[ref.Key]
+	if !ok && !optional {
+		return "", fmt.Errorf("secret %s has no key %s", ref.Name, ref.Key)
+	}
+	return string(data), nil
+}
+
+// checkConflicts fails if another repository secret configures the repository of a RepositoryConfig in the same
+// project, since Argo CD would pick either of them
+func (c *RepositoryConfigController) checkConflicts(ctx context.Context, repoConfig *appv1.RepositoryConfig, secretName string) error {
+	selector := labels.SelectorFromSet(labels.Set{common.LabelKeySecretType: common.LabelValueSecretTypeRepository})
+	secrets, err := c.kubeClientset.CoreV1().Secrets(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return fmt.Errorf("error listing repository secrets: %w", err)
+	}
+	for _, secret := range secrets.Items {
+		if secret.Name == secretName {
+			continue
+		}
+		if git.SameURL(string(secret.Data["url"]), repoConfig.Spec.URL) && string(secret.Data["project"]) == repoConfig.Spec.Project {
+			return fmt.Errorf("repository %s is already configured by secret %s", repoConfig.Spec.URL, secret.Name)
+		}
+	}
+	return nil
+}
+
+// deleteSecrets deletes the repository secrets of a deleted RepositoryConfig without waiting for their garbage collection
+func (c *RepositoryConfigController) deleteSecrets(ctx context.Context, name string) error {
+	selector := labels.SelectorFromSet(labels.Set{common.LabelKeyRepositoryConfig: name})
+	secrets, err := c.kubeClientset.CoreV1().Secrets(c.namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return fmt.Errorf("error listing secrets of RepositoryConfig %s: %w", name, err)
+	}
+	for _, secret := range secrets.Items {
+		if err := c.kubeClientset.CoreV1().Secrets(c.namespace).Delete(ctx, secret.Name, metav1.DeleteOptions{}); err != nil && !apierr.IsNotFound(err) {
+			return fmt.Errorf("error deleting secret %s: %w", secret.Name, err)
+		}
+		log.WithField("repositoryconfig", name).Infof("Deleted repository secret %s", secret.Name)
+	}
+	return nil
+}
+
+func repositoryConfigSecretName(name string) string {
+	return "repoconfig-" + name
+}
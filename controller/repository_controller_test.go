@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned/fake"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+func newFakeRepositoryConfig(name string, spec v1alpha1.RepositoryConfigSpec) *v1alpha1.RepositoryConfig {
+	return &v1alpha1.RepositoryConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: test.FakeArgoCDNamespace, UID: types.UID("uid-" + name), Generation: 1},
+		Spec:       spec,
+	}
+}
+
+func secretValue(name, key string) *v1alpha1.RepositoryConfigSecretValue {
+	return &v1alpha1.RepositoryConfigSecretValue{ValueFrom: v1alpha1.RepositoryConfigSecretValueSource{
+		SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: name}, Key: key},
+	}}
+}
+
+// newFakeRepositoryConfigController returns a controller whose informer cache holds the given configurations
+func newFakeRepositoryConfigController(t *testing.T, repoConfigs []*v1alpha1.RepositoryConfig, secrets ...runtime.Object) *RepositoryConfigController {
+	t.Helper()
+	var appObjs []runtime.Object
+	for _, repoConfig := range repoConfigs {
+		appObjs = append(appObjs, repoConfig)
+	}
+	c := NewRepositoryConfigController(test.FakeArgoCDNamespace, fake.NewSimpleClientset(secrets...), appclientset.NewSimpleClientset(appObjs...))
+	for _, repoConfig := range repoConfigs {
+		require.NoError(t, c.informer.GetIndexer().Add(repoConfig))
+	}
+	return c
+}
+
+func (c *RepositoryConfigController) getRepositoryConfig(t *testing.T, name string) *v1alpha1.RepositoryConfig {
+	t.Helper()
+	repoConfig, err := c.applicationClientset.ArgoprojV1alpha1().RepositoryConfigs(test.FakeArgoCDNamespace).Get(context.Background(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return repoConfig
+}
+
+func (c *RepositoryConfigController) getSecret(t *testing.T, name string) *corev1.Secret {
+	t.Helper()
+	secret, err := c.kubeClientset.CoreV1().Secrets(test.FakeArgoCDNamespace).Get(context.Background(), name, metav1.GetOptions{})
+	require.NoError(t, err)
+	return secret
+}
+
+func TestRepositoryConfigController_Create(t *testing.T) {
+	credentials := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "guestbook-credentials",
+			Namespace: test.FakeArgoCDNamespace,
+			Labels:    map[string]string{common.LabelKeySecretType: common.LabelValueSecretTypeRepositorySource},
+		},
+		Data: map[string][]byte{"token": []byte("s3cr3t"), "key": []byte("ssh-key")},
+	}
+	repoConfig := newFakeRepositoryConfig("guestbook", v1alpha1.RepositoryConfigSpec{
+		URL:           "https://github.com/argoproj/argocd-example-apps",
+		Project:       "default",
+		Username:      "admin",
+		Password:      secretValue("guestbook-credentials", "token"),
+		SSHPrivateKey: secretValue("guestbook-credentials", "key"),
+		EnableLFS:     true,
+	})
+	c := newFakeRepositoryConfigController(t, []*v1alpha1.RepositoryConfig{repoConfig}, credentials)
+
+	require.NoError(t, c.reconcile(context.Background(), test.FakeArgoCDNamespace+"/guestbook"))
+
+	secret := c.getSecret(t, "repoconfig-guestbook")
+	assert.Equal(t, map[string]string{
+		common.LabelKeySecretType:       common.LabelValueSecretTypeRepository,
+		common.LabelKeyRepositoryConfig: "guestbook",
+	}, secret.Labels)
+	assert.Equal(t, map[string][]byte{
+		"url":           []byte("https://github.com/argoproj/argocd-example-apps"),
+		"project":       []byte("default"),
+		"username":      []byte("admin"),
+		"password":      []byte("s3cr3t"),
+		"sshPrivateKey": []byte("ssh-key"),
+		"enableLfs":     []byte("true"),
+	}, secret.Data)
+	require.Len(t, secret.OwnerReferences, 1)
+	assert.Equal(t, "RepositoryConfig", secret.OwnerReferences[0].Kind)
+	assert.Equal(t, "guestbook", secret.OwnerReferences[0].Name)
+
+	status := c.getRepositoryConfig(t, "guestbook").Status
+	assert.Equal(t, v1alpha1.RepositoryConfigStatus{SecretName: "repoconfig-guestbook", ObservedGeneration: 1}, status)
+}
+
+func TestRepositoryConfigController_Update(t *testing.T) {
+	repoConfig := newFakeRepositoryConfig("guestbook", v1alpha1.RepositoryConfigSpec{URL: "https://github.com/argoproj/argocd-example-apps"})
+	c := newFakeRepositoryConfigController(t, []*v1alpha1.RepositoryConfig{repoConfig})
+	require.NoError(t, c.reconcile(context.Background(), test.FakeArgoCDNamespace+"/guestbook"))
+
+	updated := c.getRepositoryConfig(t, "guestbook")
+	updated.Spec.URL = "https://github.com/argoproj/argo-cd"
+	updated.Spec.Project = "platform"
+	updated.Generation = 2
+	require.NoError(t, c.informer.GetIndexer().Update(updated))
+	require.NoError(t, c.reconcile(context.Background(), test.FakeArgoCDNamespace+"/guestbook"))
+
+	secret := c.getSecret(t, "repoconfig-guestbook")
+	assert.Equal(t, "https://github.com/argoproj/argo-cd", string(secret.Data["url"]))
+	assert.Equal(t, "platform", string(secret.Data["project"]))
+	assert.Equal(t, int64(2), c.getRepositoryConfig(t, "guestbook").Status.ObservedGeneration)
+}
+
+func TestRepositoryConfigController_MissingSecret(t *testing.T) {
+	repoConfig := newFakeRepositoryConfig("guestbook", v1alpha1.RepositoryConfigSpec{
+		URL:      "https://github.com/argoproj/argocd-example-apps",
+		Password: secretValue("guestbook-credentials", "token"),
+	})
+
+	t.Run("Required", func(t *testing.T) {
+		c := newFakeRepositoryConfigController(t, []*v1alpha1.RepositoryConfig{repoConfig.DeepCopy()})
+		err := c.reconcile(context.Background(), test.FakeArgoCDNamespace+"/guestbook")
+		require.ErrorContains(t, err, "error resolving password")
+
+		_, err = c.kubeClientset.CoreV1().Secrets(test.FakeArgoCDNamespace).Get(context.Background(), "repoconfig-guestbook", metav1.GetOptions{})
+		assert.True(t, apierr.IsNotFound(err))
+		assert.Contains(t, c.getRepositoryConfig(t, "guestbook").Status.Error, "guestbook-credentials")
+	})
+
+	t.Run("Optional", func(t *testing.T) {
+		optional := repoConfig.DeepCopy()
+		optional.Spec.Password.ValueFrom.SecretKeyRef.Optional = ptr.To(true)
+		c := newFakeRepositoryConfigController(t, []*v1alpha1.RepositoryConfig{optional})
+		require.NoError(t, c.reconcile(context.Background(), test.FakeArgoCDNamespace+"/guestbook"))
+
+		secret := c.getSecret(t, "repoconfig-guestbook")
+		assert.NotContains(t, secret.Data, "password")
+	})
+}
+
+func TestRepositoryConfigController_UnlabeledSecret(t *testing.T) {
+	argocdSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ArgoCDSecretName, Namespace: test.FakeArgoCDNamespace},
+		Data:       map[string][]byte{"server.secretkey": []byte("s3cr3t")},
+	}
+	repoConfig := newFakeRepositoryConfig("guestbook", v1alpha1.RepositoryConfigSpec{
+		URL:      "https://github.com/argoproj/argocd-example-apps",
+		Password: secretValue(common.ArgoCDSecretName, "server.secretkey"),
+	})
+	c := newFakeRepositoryConfigController(t, []*v1alpha1.RepositoryConfig{repoConfig}, argocdSecret)
+
+	err := c.reconcile(context.Background(), test.FakeArgoCDNamespace+"/guestbook")
+	require.ErrorContains(t, err, "is not labeled with argocd.argoproj.io/secret-type=repository-source")
+	_, err = c.kubeClientset.CoreV1().Secrets(test.FakeArgoCDNamespace).Get(context.Background(), "repoconfig-guestbook", metav1.GetOptions{})
+	assert.True(t, apierr.IsNotFound(err))
+}
+
+func TestRepositoryConfigController_Conflict(t *testing.T) {
+	existing := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "repo-guestbook",
+			Namespace: test.FakeArgoCDNamespace,
+			Labels:    map[string]string{common.LabelKeySecretType: common.LabelValueSecretTypeRepository},
+		},
+		Data: map[string][]byte{"url": []byte("https://github.com/argoproj/argocd-example-apps.git")},
+	}
+
+	t.Run("SameProject", func(t *testing.T) {
+		repoConfig := newFakeRepositoryConfig("guestbook", v1alpha1.RepositoryConfigSpec{URL: "https://github.com/argoproj/argocd-example-apps"})
+		c := newFakeRepositoryConfigController(t, []*v1alpha1.RepositoryConfig{repoConfig}, existing.DeepCopy())
+		err := c.reconcile(context.Background(), test.FakeArgoCDNamespace+"/guestbook")
+		require.ErrorContains(t, err, "already configured by secret repo-guestbook")
+		assert.Equal(t, err.Error(), c.getRepositoryConfig(t, "guestbook").Status.Error)
+	})
+
+	t.Run("OtherProject", func(t *testing.T) {
+		repoConfig := newFakeRepositoryConfig("guestbook", v1alpha1.RepositoryConfigSpec{URL: "https://github.com/argoproj/argocd-example-apps", Project: "platform"})
+		c := newFakeRepositoryConfigController(t, []*v1alpha1.RepositoryConfig{repoConfig}, existing.DeepCopy())
+		require.NoError(t, c.reconcile(context.Background(), test.FakeArgoCDNamespace+"/guestbook"))
+	})
+
+	t.Run("UnmanagedSecret", func(t *testing.T) {
+		unmanaged := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "repoconfig-guestbook", Namespace: test.FakeArgoCDNamespace}}
+		repoConfig := newFakeRepositoryConfig("guestbook", v1alpha1.RepositoryConfigSpec{URL: "https://github.com/argoproj/argo-cd"})
+		c := newFakeRepositoryConfigController(t, []*v1alpha1.RepositoryConfig{repoConfig}, unmanaged)
+		require.ErrorContains(t, c.reconcile(context.Background(), test.FakeArgoCDNamespace+"/guestbook"), "not managed by the RepositoryConfig")
+	})
+}
+
+func TestRepositoryConfigController_Delete(t *testing.T) {
+	repoConfig := newFakeRepositoryConfig("guestbook", v1alpha1.RepositoryConfigSpec{URL: "https://github.com/argoproj/argocd-example-apps"})
+	c := newFakeRepositoryConfigController(t, []*v1alpha1.RepositoryConfig{repoConfig})
+	require.NoError(t, c.reconcile(context.Background(), test.FakeArgoCDNamespace+"/guestbook"))
+	c.getSecret(t, "repoconfig-guestbook")
+
+	require.NoError(t, c.informer.GetIndexer().Delete(repoConfig))
+	require.NoError(t, c.reconcile(context.Background(), test.FakeArgoCDNamespace+"/guestbook"))
+
+	_, err := c.kubeClientset.CoreV1().Secrets(test.FakeArgoCDNamespace).Get(context.Background(), "repoconfig-guestbook", metav1.GetOptions{})
+	assert.True(t, apierr.IsNotFound(err))
+}
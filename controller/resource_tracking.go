@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"strconv"
+
+	kubeutil "github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/argoproj/argo-cd/v2/common"
+)
+
+// hasTrueAnnotation returns true if the annotation of the object parses as true
+func hasTrueAnnotation(obj *unstructured.Unstructured, annotation string) bool {
+	if obj == nil {
+		return false
+	}
+	value, ok := obj.GetAnnotations()[annotation]
+	if !ok {
+		return false
+	}
+	enabled, err := strconv.ParseBool(value)
+	return err == nil && enabled
+}
+
+// isResourceExcludedByAnnotation returns true if the resource is annotated to be unmanaged
+func isResourceExcludedByAnnotation(obj *unstructured.Unstructured) bool {
+	return hasTrueAnnotation(obj, common.AnnotationExcludeResource)
+}
+
+// isResourceIncludedByAnnotation returns true if the resource is annotated to be managed regardless of the resource
+// exclusions of the settings. The exclusion annotation takes precedence.
+func isResourceIncludedByAnnotation(obj *unstructured.Unstructured) bool {
+	return hasTrueAnnotation(obj, common.AnnotationIncludeResource) && !isResourceExcludedByAnnotation(obj)
+}
+
+// filterExcludedResources removes the live resources annotated to be unmanaged from the live state, together with the
+// resources of the desired state they correspond to, and the desired resources annotated to be unmanaged. It returns
+// the remaining resources of the desired state, and the removed ones.
+func filterExcludedResources(targetObjs []*unstructured.Unstructured, liveObjByKey map[kubeutil.ResourceKey]*unstructured.Unstructured) ([]*unstructured.Unstructured, []*unstructured.Unstructured) {
+	excluded := make(map[kubeutil.ResourceKey]bool)
+	for key, liveObj := range liveObjByKey {
+		if isResourceExcludedByAnnotation(liveObj) {
+			delete(liveObjByKey, key)
+			excluded[key] = true
+		}
+	}
+	filtered := make([]*unstructured.Unstructured, 0, len(targetObjs))
+	var excludedTargetObjs []*unstructured.Unstructured
+	for _, targetObj := range targetObjs {
+		key := kubeutil.GetResourceKey(targetObj)
+		if excluded[key] || isResourceExcludedByAnnotation(targetObj) {
+			delete(liveObjByKey, key)
+			excludedTargetObjs = append(excludedTargetObjs, targetObj)
+			continue
+		}
+		filtered = append(filtered, targetObj)
+	}
+	return filtered, excludedTargetObjs
+}
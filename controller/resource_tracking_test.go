@@ -0,0 +1,57 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/argoproj/argo-cd/v2/common"
+)
+
+func newAnnotatedConfigMap(name string, annotations map[string]string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind("ConfigMap")
+	obj.SetNamespace("default")
+	obj.SetName(name)
+	obj.SetAnnotations(annotations)
+	return obj
+}
+
+func TestIsResourceIncludedByAnnotation(t *testing.T) {
+	assert.True(t, isResourceIncludedByAnnotation(newAnnotatedConfigMap("cm", map[string]string{common.AnnotationIncludeResource: "true"})))
+	assert.False(t, isResourceIncludedByAnnotation(newAnnotatedConfigMap("cm", map[string]string{common.AnnotationIncludeResource: "false"})))
+	assert.False(t, isResourceIncludedByAnnotation(newAnnotatedConfigMap("cm", map[string]string{common.AnnotationIncludeResource: "yes"})))
+	assert.False(t, isResourceIncludedByAnnotation(newAnnotatedConfigMap("cm", nil)))
+	// the exclusion annotation takes precedence
+	assert.False(t, isResourceIncludedByAnnotation(newAnnotatedConfigMap("cm", map[string]string{
+		common.AnnotationIncludeResource: "true",
+		common.AnnotationExcludeResource: "true",
+	})))
+}
+
+func TestFilterExcludedResources(t *testing.T) {
+	excludedLive := newAnnotatedConfigMap("excluded-live", map[string]string{common.AnnotationExcludeResource: "true"})
+	excludedTarget := newAnnotatedConfigMap("excluded-target", map[string]string{common.AnnotationExcludeResource: "true"})
+	managed := newAnnotatedConfigMap("managed", nil)
+	unmanagedLive := newAnnotatedConfigMap("unmanaged-live", map[string]string{common.AnnotationExcludeResource: "true"})
+
+	liveObjByKey := map[kube.ResourceKey]*unstructured.Unstructured{
+		kube.GetResourceKey(excludedLive):   excludedLive,
+		kube.GetResourceKey(excludedTarget): newAnnotatedConfigMap("excluded-target", nil),
+		kube.GetResourceKey(managed):        managed,
+		kube.GetResourceKey(unmanagedLive):  unmanagedLive,
+	}
+	targetObjs := []*unstructured.Unstructured{
+		newAnnotatedConfigMap("excluded-live", nil),
+		excludedTarget,
+		managed,
+	}
+
+	filtered, excluded := filterExcludedResources(targetObjs, liveObjByKey)
+	assert.Equal(t, []*unstructured.Unstructured{managed}, filtered)
+	assert.Equal(t, []*unstructured.Unstructured{targetObjs[0], excludedTarget}, excluded)
+	assert.Equal(t, map[kube.ResourceKey]*unstructured.Unstructured{kube.GetResourceKey(managed): managed}, liveObjByKey)
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// syncErrorMessages returns the messages of the resources whose sync failed, or the message of the operation if it
+// failed before syncing any resource, e.g. because the manifests could not be generated
+func syncErrorMessages(state *v1alpha1.OperationState) []string {
+	var messages []string
+	if state.SyncResult != nil {
+		for _, res := range state.SyncResult.Resources {
+			failed := res.Status == common.ResultCodeSyncFailed || res.HookPhase == common.OperationFailed || res.HookPhase == common.OperationError
+			if failed && res.Message != "" {
+				messages = append(messages, res.Message)
+			}
+		}
+	}
+	if len(messages) == 0 && state.Message != "" {
+		messages = append(messages, state.Message)
+	}
+	return messages
+}
+
+// matchRetryPattern returns the first retry pattern of the application matching an error of the failed sync, or nil
+// if none matches. Invalid patterns are ignored.
+func matchRetryPattern(app *v1alpha1.Application, state *v1alpha1.OperationState) *v1alpha1.SyncRetryPattern {
+	if app.Spec.SyncPolicy == nil || len(app.Spec.SyncPolicy.RetryOn) == 0 {
+		return nil
+	}
+	messages := syncErrorMessages(state)
+	for i := range app.Spec.SyncPolicy.RetryOn {
+		pattern := &app.Spec.SyncPolicy.RetryOn[i]
+		re, err := regexp.Compile(pattern.ErrorPattern)
+		if err != nil {
+			getAppLog(app).Warnf("Ignoring invalid sync retry pattern %q: %v", pattern.ErrorPattern, err)
+			continue
+		}
+		for _, message := range messages {
+			if re.MatchString(message) {
+				return pattern
+			}
+		}
+	}
+	return nil
+}
+
+// nextRetryAt returns when the failed sync of the application is retried. The backoff of the retry pattern matching
+// the error of the sync takes precedence over the backoff of the retry strategy of the operation.
+func nextRetryAt(app *v1alpha1.Application, state *v1alpha1.OperationState, lastAttempt time.Time) (time.Time, error) {
+	if pattern := matchRetryPattern(app, state); pattern != nil {
+		return lastAttempt.Add(pattern.GetBackoff()), nil
+	}
+	return state.Operation.Retry.NextRetryAt(lastAttempt, state.RetryCount)
+}
+
+// shouldRetrySync returns whether a failed sync is retried, along with the retry pattern matching its error, if any. A
+// sync whose error matches a retry pattern is retried up to the maximum retries of the pattern. Otherwise, the retry
+// strategy of the operation applies, unless the application only retries the errors matching its retry patterns.
+// Syncs which timed out are only retried if the application configures a retry strategy, since the default retry
+// strategy of automated syncs would likely time out again.
+func shouldRetrySync(app *v1alpha1.Application, state *v1alpha1.OperationState) (bool, *v1alpha1.SyncRetryPattern) {
+	if pattern := matchRetryPattern(app, state); pattern != nil {
+		return state.RetryCount < pattern.GetMaxRetries(), pattern
+	}
+	syncPolicy := app.Spec.SyncPolicy
+	if syncPolicy != nil && len(syncPolicy.RetryOn) > 0 && syncPolicy.Retry == nil {
+		return false, nil
+	}
+	if isSyncTimedOut(state) && (syncPolicy == nil || syncPolicy.Retry == nil) {
+		return false, nil
+	}
+	limit := state.Operation.Retry.Limit
+	return state.RetryCount < limit || limit < 0, nil
+}
@@ -0,0 +1,244 @@
+package controller
+
+import (
+	"container/heap"
+	goSync "sync"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+)
+
+const (
+	// retryPriorityPerFailedAttempt is the priority, in seconds of waiting, gained by an item for each failed attempt
+	retryPriorityPerFailedAttempt = 10
+	// maxRetryPriority caps the priority gained by failed attempts, so that items which never failed are not starved
+	// by items failing repeatedly
+	maxRetryPriority = 5 * 60
+)
+
+// retryInfo is the failed attempts of an item
+type retryInfo struct {
+	failedAttempts int
+	lastAttempt    time.Time
+}
+
+// retryQueueItem is an item waiting in the queue
+type retryQueueItem struct {
+	key            interface{}
+	failedAttempts int
+	// since is the time of the last attempt of items which failed, or the time the item was queued otherwise
+	since time.Time
+	// seq orders the items of the same priority by the time they were queued
+	seq   uint64
+	index int
+}
+
+// priority returns the priority of the item, which is (failedAttempts * 10) + (now - since) in seconds. The part of
+// the priority gained by failed attempts is capped, the rest grows at the same rate for every item.
+func (i *retryQueueItem) priority(now time.Time) float64 {
+	return float64(min(i.failedAttempts*retryPriorityPerFailedAttempt, maxRetryPriority)) + now.Sub(i.since).Seconds()
+}
+
+// retryQueueHeap is a max heap of items ordered by priority. Since the priorities of all items grow at the same rate,
+// their order does not change over time.
+type retryQueueHeap struct {
+	items []*retryQueueItem
+	now   time.Time
+}
+
+func (h *retryQueueHeap) Len() int {
+	return len(h.items)
+}
+
+func (h *retryQueueHeap) Less(i, j int) bool {
+	pi, pj := h.items[i].priority(h.now), h.items[j].priority(h.now)
+	if pi != pj {
+		return pi > pj
+	}
+	return h.items[i].seq < h.items[j].seq
+}
+
+func (h *retryQueueHeap) Swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.items[i].index = i
+	h.items[j].index = j
+}
+
+func (h *retryQueueHeap) Push(x interface{}) {
+	item := x.(*retryQueueItem)
+	item.index = len(h.items)
+	h.items = append(h.items, item)
+}
+
+func (h *retryQueueHeap) Pop() interface{} {
+	item := h.items[len(h.items)-1]
+	h.items[len(h.items)-1] = nil
+	h.items = h.items[:len(h.items)-1]
+	item.index = -1
+	return item
+}
+
+// RetryPriorityQueue is a work queue which hands out the items whose last attempts failed, such as applications whose
+// sync timed out, before the items which never failed, such as applications which are synced for the first time. It
+// has the same semantics as the default work queue: an item is never processed concurrently, and an item added while
+// it is processed is queued again once it is done.
+type RetryPriorityQueue struct {
+	cond  *goSync.Cond
+	clock clock.Clock
+
+	queue      retryQueueHeap
+	queued     map[interface{}]*retryQueueItem
+	dirty      map[interface{}]bool
+	processing map[interface{}]bool
+	retries    map[interface{}]retryInfo
+	seq        uint64
+
+	shuttingDown bool
+	drain        bool
+}
+
+var _ workqueue.Interface = &RetryPriorityQueue{}
+
+// NewRetryPriorityQueue returns an empty retry priority queue
+func NewRetryPriorityQueue() *RetryPriorityQueue {
+	return newRetryPriorityQueueWithClock(clock.RealClock{})
+}
+
+func newRetryPriorityQueueWithClock(clock clock.Clock) *RetryPriorityQueue {
+	return &RetryPriorityQueue{
+		cond:       goSync.NewCond(&goSync.Mutex{}),
+		clock:      clock,
+		queued:     map[interface{}]*retryQueueItem{},
+		dirty:      map[interface{}]bool{},
+		processing: map[interface{}]bool{},
+		retries:    map[interface{}]retryInfo{},
+	}
+}
+
+// push queues the item with the priority of its failed attempts
+func (q *RetryPriorityQueue) push(key interface{}) {
+	q.seq++
+	item := &retryQueueItem{key: key, since: q.clock.Now(), seq: q.seq}
+	if retry, ok := q.retries[key]; ok {
+		item.failedAttempts = retry.failedAttempts
+		item.since = retry.lastAttempt
+	}
+	q.queue.now = q.clock.Now()
+	heap.Push(&q.queue, item)
+	q.queued[key] = item
+	q.cond.Signal()
+}
+
+// Add queues the item, unless it is already queued
+func (q *RetryPriorityQueue) Add(key interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	if q.shuttingDown || q.dirty[key] {
+		return
+	}
+	q.dirty[key] = true
+	if q.processing[key] {
+		return
+	}
+	q.push(key)
+}
+
+// Len returns the number of queued items
+func (q *RetryPriorityQueue) Len() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.queue.Len()
+}
+
+// RetryLen returns the number of queued items whose last attempt failed
+func (q *RetryPriorityQueue) RetryLen() int {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	count := 0
+	for _, item := range q.queue.items {
+		if item.failedAttempts > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// Get blocks until an item can be processed and returns the item with the highest priority. The item must be marked
+// as done once it is processed.
+func (q *RetryPriorityQueue) Get() (interface{}, bool) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	for q.queue.Len() == 0 && !q.shuttingDown {
+		q.cond.Wait()
+	}
+	if q.queue.Len() == 0 {
+		return nil, true
+	}
+	q.queue.now = q.clock.Now()
+	item := heap.Pop(&q.queue).(*retryQueueItem)
+	delete(q.queued, item.key)
+	delete(q.dirty, item.key)
+	q.processing[item.key] = true
+	return item.key, false
+}
+
+// Done marks the item as processed, and queues it again if it was added while it was processed
+func (q *RetryPriorityQueue) Done(key interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	delete(q.processing, key)
+	if q.dirty[key] {
+		q.push(key)
+	} else if len(q.processing) == 0 {
+		q.cond.Broadcast()
+	}
+}
+
+// SetRetry records the failed attempts of the item, which is given a higher priority the next time it is queued
+func (q *RetryPriorityQueue) SetRetry(key interface{}, failedAttempts int, lastAttempt time.Time) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.retries[key] = retryInfo{failedAttempts: failedAttempts, lastAttempt: lastAttempt}
+	if item, ok := q.queued[key]; ok {
+		item.failedAttempts = failedAttempts
+		item.since = lastAttempt
+		q.queue.now = q.clock.Now()
+		heap.Fix(&q.queue, item.index)
+	}
+}
+
+// ClearRetry forgets the failed attempts of the item, once an attempt succeeded
+func (q *RetryPriorityQueue) ClearRetry(key interface{}) {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	delete(q.retries, key)
+}
+
+// ShutDown makes Get return immediately once the queue is empty and ignores the items added afterwards
+func (q *RetryPriorityQueue) ShutDown() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = false
+	q.shuttingDown = true
+	q.cond.Broadcast()
+}
+
+// ShutDownWithDrain shuts the queue down and waits until all the items being processed are done
+func (q *RetryPriorityQueue) ShutDownWithDrain() {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	q.drain = true
+	q.shuttingDown = true
+	q.cond.Broadcast()
+	for len(q.processing) != 0 && q.drain {
+		q.cond.Wait()
+	}
+}
+
+// ShuttingDown returns whether the queue is shutting down
+func (q *RetryPriorityQueue) ShuttingDown() bool {
+	q.cond.L.Lock()
+	defer q.cond.L.Unlock()
+	return q.shuttingDown
+}
@@ -0,0 +1,149 @@
+package controller
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/util/workqueue"
+	testingclock "k8s.io/utils/clock/testing"
+)
+
+func getAll(t *testing.T, q workqueue.Interface) []interface{} {
+	t.Helper()
+	var keys []interface{}
+	for q.Len() > 0 {
+		key, shutdown := q.Get()
+		require.False(t, shutdown)
+		keys = append(keys, key)
+		q.Done(key)
+	}
+	return keys
+}
+
+func TestRetryPriorityQueue_RetriesFirst(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	q := newRetryPriorityQueueWithClock(clock)
+
+	// many apps are queued for their first sync before the apps whose sync timed out are queued again
+	for i := 0; i < 100; i++ {
+		q.Add(fmt.Sprintf("argocd/new-%d", i))
+	}
+	clock.Step(5 * time.Second)
+	q.SetRetry("argocd/timed-out", 1, clock.Now())
+	q.SetRetry("argocd/failed-twice", 2, clock.Now())
+	q.Add("argocd/timed-out")
+	q.Add("argocd/failed-twice")
+	assert.Equal(t, 2, q.RetryLen())
+
+	keys := getAll(t, q)
+	require.Len(t, keys, 102)
+	assert.Equal(t, []interface{}{"argocd/failed-twice", "argocd/timed-out"}, keys[:2])
+	// the other apps are processed in the order they were queued
+	assert.Equal(t, "argocd/new-0", keys[2])
+	assert.Equal(t, "argocd/new-99", keys[101])
+	assert.Equal(t, 0, q.RetryLen())
+}
+
+func TestRetryPriorityQueue_NoStarvation(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	q := newRetryPriorityQueueWithClock(clock)
+
+	q.Add("argocd/new")
+	// the priority gained by failed attempts is capped, an app waiting longer than the cap is processed first
+	clock.Step(maxRetryPriority*time.Second + time.Minute)
+	q.SetRetry("argocd/failing", 1000, clock.Now())
+	q.Add("argocd/failing")
+
+	assert.Equal(t, []interface{}{"argocd/new", "argocd/failing"}, getAll(t, q))
+}
+
+func TestRetryPriorityQueue_SetRetryOfQueuedItem(t *testing.T) {
+	clock := testingclock.NewFakeClock(time.Now())
+	q := newRetryPriorityQueueWithClock(clock)
+
+	q.Add("argocd/a")
+	q.Add("argocd/b")
+	q.SetRetry("argocd/b", 1, clock.Now())
+	assert.Equal(t, []interface{}{"argocd/b", "argocd/a"}, getAll(t, q))
+
+	// the priority is kept until the failed attempts are cleared
+	q.Add("argocd/a")
+	q.Add("argocd/b")
+	assert.Equal(t, []interface{}{"argocd/b", "argocd/a"}, getAll(t, q))
+
+	q.ClearRetry("argocd/b")
+	q.Add("argocd/a")
+	clock.Step(time.Second)
+	q.Add("argocd/b")
+	assert.Equal(t, []interface{}{"argocd/a", "argocd/b"}, getAll(t, q))
+}
+
+func TestRetryPriorityQueue_Semantics(t *testing.T) {
+	q := NewRetryPriorityQueue()
+
+	q.Add("argocd/app")
+	q.Add("argocd/app")
+	assert.Equal(t, 1, q.Len())
+
+	key, shutdown := q.Get()
+	require.False(t, shutdown)
+	assert.Equal(t, "argocd/app", key)
+
+	// an item added while it is processed is queued again once it is done
+	q.Add("argocd/app")
+	assert.Equal(t, 0, q.Len())
+	q.Done("argocd/app")
+	assert.Equal(t, 1, q.Len())
+
+	key, _ = q.Get()
+	q.ShutDown()
+	assert.True(t, q.ShuttingDown())
+	q.Add("argocd/other")
+	assert.Equal(t, 0, q.Len())
+	q.Done(key)
+
+	_, shutdown = q.Get()
+	assert.True(t, shutdown)
+}
+
+func TestRetryPriorityQueue_ShutDownWithDrain(t *testing.T) {
+	q := NewRetryPriorityQueue()
+	q.Add("argocd/app")
+	key, _ := q.Get()
+
+	drained := make(chan struct{})
+	go func() {
+		q.ShutDownWithDrain()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		t.Fatal("the queue was drained while an item was processed")
+	case <-time.After(50 * time.Millisecond):
+	}
+	q.Done(key)
+	select {
+	case <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("the queue was not drained once the item was done")
+	}
+}
+
+func TestRetryPriorityQueue_RateLimitingQueue(t *testing.T) {
+	q := NewRetryPriorityQueue()
+	rateLimitingQueue := workqueue.NewRateLimitingQueueWithConfig(workqueue.DefaultControllerRateLimiter(), workqueue.RateLimitingQueueConfig{
+		DelayingQueue: workqueue.NewDelayingQueueWithConfig(workqueue.DelayingQueueConfig{Queue: q}),
+	})
+	defer rateLimitingQueue.ShutDown()
+
+	rateLimitingQueue.Add("argocd/new")
+	q.SetRetry("argocd/timed-out", 1, time.Now())
+	rateLimitingQueue.AddRateLimited("argocd/timed-out")
+	require.Eventually(t, func() bool {
+		return rateLimitingQueue.Len() == 2
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, []interface{}{"argocd/timed-out", "argocd/new"}, getAll(t, rateLimitingQueue))
+}
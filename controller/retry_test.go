@@ -0,0 +1,128 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+const (
+	conflictMessage   = `Operation cannot be fulfilled on configmaps "my-map": the object has been modified; please apply your changes to the latest version and try again`
+	throttledMessage  = "the server has received too many requests and has asked us to try again later (TooManyRequests)"
+	validationMessage = `error validating data: ValidationError(Deployment.spec): unknown field "replica" in io.k8s.api.apps.v1.DeploymentSpec`
+)
+
+func newRetryPatternApp(retry *v1alpha1.RetryStrategy, patterns ...v1alpha1.SyncRetryPattern) *v1alpha1.Application {
+	app := newFakeApp()
+	app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{Retry: retry, RetryOn: patterns}
+	return app
+}
+
+func newFailedSyncState(retryCount int64, message string, resourceMessages ...string) *v1alpha1.OperationState {
+	state := &v1alpha1.OperationState{
+		Phase:      synccommon.OperationFailed,
+		Message:    message,
+		RetryCount: retryCount,
+		Operation:  v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{}, Retry: v1alpha1.RetryStrategy{Limit: 5}},
+		SyncResult: &v1alpha1.SyncOperationResult{},
+	}
+	for _, msg := range resourceMessages {
+		state.SyncResult.Resources = append(state.SyncResult.Resources, &v1alpha1.ResourceResult{Kind: "ConfigMap", Name: "my-map", Status: synccommon.ResultCodeSyncFailed, Message: msg})
+	}
+	return state
+}
+
+var transientPatterns = []v1alpha1.SyncRetryPattern{
+	{ErrorPattern: "the object has been modified", MaxRetries: 3},
+	{ErrorPattern: "(?i)too many requests", MaxRetries: 2, Backoff: &metav1.Duration{Duration: 10 * time.Second}},
+}
+
+func TestSyncErrorMessages(t *testing.T) {
+	state := newFailedSyncState(0, "one or more objects failed to apply", conflictMessage)
+	state.SyncResult.Resources = append(state.SyncResult.Resources,
+		&v1alpha1.ResourceResult{Kind: "ConfigMap", Name: "synced", Status: synccommon.ResultCodeSynced, Message: "configmap/synced configured"},
+		&v1alpha1.ResourceResult{Kind: "Job", Name: "hook", HookType: synccommon.HookTypePreSync, HookPhase: synccommon.OperationFailed, Message: "Job has reached the specified backoff limit"})
+	assert.Equal(t, []string{conflictMessage, "Job has reached the specified backoff limit"}, syncErrorMessages(state))
+
+	// the message of the operation is matched if it failed before syncing any resource
+	assert.Equal(t, []string{throttledMessage}, syncErrorMessages(newFailedSyncState(0, throttledMessage)))
+	assert.Empty(t, syncErrorMessages(&v1alpha1.OperationState{}))
+}
+
+func TestMatchRetryPattern(t *testing.T) {
+	app := newRetryPatternApp(nil, transientPatterns...)
+
+	assert.Equal(t, &app.Spec.SyncPolicy.RetryOn[0], matchRetryPattern(app, newFailedSyncState(0, "one or more objects failed to apply", conflictMessage)))
+	assert.Equal(t, &app.Spec.SyncPolicy.RetryOn[1], matchRetryPattern(app, newFailedSyncState(0, throttledMessage)))
+	assert.Nil(t, matchRetryPattern(app, newFailedSyncState(0, "one or more objects failed to apply", validationMessage)))
+	assert.Nil(t, matchRetryPattern(newFakeApp(), newFailedSyncState(0, "one or more objects failed to apply", conflictMessage)))
+
+	// invalid patterns are ignored
+	app = newRetryPatternApp(nil, v1alpha1.SyncRetryPattern{ErrorPattern: "("}, v1alpha1.SyncRetryPattern{ErrorPattern: "modified"})
+	assert.Equal(t, "modified", matchRetryPattern(app, newFailedSyncState(0, "", conflictMessage)).ErrorPattern)
+}
+
+func TestShouldRetrySync(t *testing.T) {
+	t.Run("TransientErrorRetriedUpToMaxRetries", func(t *testing.T) {
+		app := newRetryPatternApp(nil, transientPatterns...)
+		retry, pattern := shouldRetrySync(app, newFailedSyncState(2, "one or more objects failed to apply", conflictMessage))
+		assert.True(t, retry)
+		assert.Equal(t, "the object has been modified", pattern.ErrorPattern)
+
+		retry, pattern = shouldRetrySync(app, newFailedSyncState(3, "one or more objects failed to apply", conflictMessage))
+		assert.False(t, retry)
+		assert.NotNil(t, pattern)
+	})
+
+	t.Run("PatternTakesPrecedenceOverRetryStrategy", func(t *testing.T) {
+		app := newRetryPatternApp(&v1alpha1.RetryStrategy{Limit: 5}, transientPatterns...)
+		retry, _ := shouldRetrySync(app, newFailedSyncState(2, throttledMessage))
+		assert.False(t, retry)
+	})
+
+	t.Run("PermanentErrorNotRetriedWithoutRetryStrategy", func(t *testing.T) {
+		app := newRetryPatternApp(nil, transientPatterns...)
+		retry, pattern := shouldRetrySync(app, newFailedSyncState(0, "one or more objects failed to apply", validationMessage))
+		assert.False(t, retry)
+		assert.Nil(t, pattern)
+	})
+
+	t.Run("PermanentErrorRetriedWithRetryStrategy", func(t *testing.T) {
+		app := newRetryPatternApp(&v1alpha1.RetryStrategy{Limit: 5}, transientPatterns...)
+		retry, pattern := shouldRetrySync(app, newFailedSyncState(0, "one or more objects failed to apply", validationMessage))
+		assert.True(t, retry)
+		assert.Nil(t, pattern)
+	})
+
+	t.Run("RetryStrategyWithoutPatterns", func(t *testing.T) {
+		retry, _ := shouldRetrySync(newFakeApp(), newFailedSyncState(4, "one or more objects failed to apply", validationMessage))
+		assert.True(t, retry)
+		retry, _ = shouldRetrySync(newFakeApp(), newFailedSyncState(5, "one or more objects failed to apply", validationMessage))
+		assert.False(t, retry)
+		retry, _ = shouldRetrySync(newFakeApp(), newFailedSyncState(0, syncTimedOutMessage(time.Minute)))
+		assert.False(t, retry)
+	})
+}
+
+func TestNextRetryAt(t *testing.T) {
+	lastAttempt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	app := newRetryPatternApp(nil, transientPatterns...)
+
+	// transient errors are retried after the backoff of their pattern, immediately by default
+	retryAt, err := nextRetryAt(app, newFailedSyncState(1, "one or more objects failed to apply", conflictMessage), lastAttempt)
+	assert.NoError(t, err)
+	assert.Equal(t, lastAttempt, retryAt)
+	retryAt, err = nextRetryAt(app, newFailedSyncState(1, throttledMessage), lastAttempt)
+	assert.NoError(t, err)
+	assert.Equal(t, lastAttempt.Add(10*time.Second), retryAt)
+
+	// the other errors are retried after the backoff of the retry strategy
+	retryAt, err = nextRetryAt(app, newFailedSyncState(1, "one or more objects failed to apply", validationMessage), lastAttempt)
+	assert.NoError(t, err)
+	assert.Equal(t, lastAttempt.Add(2*v1alpha1.DefaultSyncRetryDuration), retryAt)
+}
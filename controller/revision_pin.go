@@ -0,0 +1,51 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/argoproj/gitops-engine/pkg/diff"
+	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+)
+
+// pinAppRevisions pins the target revisions of the git sources of the application to the commits synced by the given
+// operation, if the operation is a successful sync with the PinRevision=true sync option. Once pinned, the target
+// revisions are commit SHAs so later syncs leave them untouched.
+func (ctrl *ApplicationController) pinAppRevisions(app *appv1.Application, state *appv1.OperationState) {
+	if state.Phase != synccommon.OperationSucceeded || state.Operation.Sync == nil || state.Operation.Sync.DryRun || state.SyncResult == nil {
+		return
+	}
+	if !state.Operation.Sync.SyncOptions.HasOption(argo.SyncOptionPinRevision) {
+		return
+	}
+
+	pinned := app.DeepCopy()
+	revisions := argo.PinSpecRevisions(&pinned.Spec, state.SyncResult.Revision, state.SyncResult.Revisions)
+	if len(revisions) == 0 {
+		return
+	}
+
+	logCtx := getAppLog(app)
+	patch, modified, err := diff.CreateTwoWayMergePatch(app, pinned, appv1.Application{})
+	if err != nil {
+		logCtx.Errorf("error constructing app spec patch to pin the target revision: %v", err)
+		return
+	}
+	if !modified {
+		return
+	}
+	if _, err := ctrl.PatchAppWithWriteBack(context.Background(), app.Name, app.Namespace, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		logCtx.Errorf("Unable to pin the target revision: %v", err)
+		return
+	}
+	message := fmt.Sprintf("Pinned target revision to %s", strings.Join(revisions, ", "))
+	logCtx.Info(message)
+	ctrl.logAppEvent(app, argo.EventInfo{Reason: argo.EventReasonResourceUpdated, Type: v1.EventTypeNormal}, message, context.TODO())
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+)
+
+// autoRollbackInfoName is the name of the operation info marking the syncs initiated by automatic rollbacks
+const autoRollbackInfoName = "Automatic rollback"
+
+// historyRevision returns the revision of a history entry, or the comma separated revisions of its sources
+func historyRevision(history appv1.RevisionHistory) string {
+	if len(history.Revisions) > 0 {
+		return strings.Join(history.Revisions, ",")
+	}
+	return history.Revision
+}
+
+// isAutoRollback returns true if the operation was initiated by an automatic rollback
+func isAutoRollback(op *appv1.Operation) bool {
+	for _, info := range op.Info {
+		if info != nil && info.Name == autoRollbackInfoName {
+			return true
+		}
+	}
+	return false
+}
+
+// getAutoRollbackTarget returns the history entry the application must be rolled back to because it became degraded
+// within the health window of its auto rollback policy after its last sync, or nil if it must not be rolled back
+func getAutoRollbackTarget(app *appv1.Application, healthStatus *appv1.HealthStatus, now time.Time) (*appv1.RevisionHistory, error) {
+	if app.Spec.SyncPolicy == nil || app.Spec.SyncPolicy.AutoRollback == nil || healthStatus.Status != health.HealthStatusDegraded {
+		return nil, nil
+	}
+	if app.Operation != nil || app.DeletionTimestamp != nil {
+		return nil, nil
+	}
+	window, err := time.ParseDuration(app.Spec.SyncPolicy.AutoRollback.HealthWindow)
+	if err != nil {
+		return nil, fmt.Errorf("invalid auto rollback health window %q: %w", app.Spec.SyncPolicy.AutoRollback.HealthWindow, err)
+	}
+	state := app.Status.OperationState
+	if state == nil || state.Operation.Sync == nil || state.Operation.Sync.DryRun || state.Phase != synccommon.OperationSucceeded || state.FinishedAt == nil {
+		return nil, nil
+	}
+	// a degraded rollback is not rolled back again, which would redeploy the degraded revision
+	if isAutoRollback(&state.Operation) || now.Sub(state.FinishedAt.Time) > window {
+		return nil, nil
+	}
+	history := app.Status.History
+	if len(history) < 2 {
+		return nil, nil
+	}
+	// syncs of a subset of the resources do not add an entry to the history
+	current := history[len(history)-1]
+	if current.DeployStartedAt == nil || !current.DeployStartedAt.Equal(&state.StartedAt) {
+		return nil, nil
+	}
+	return &history[len(history)-2], nil
+}
+
+// autoRollback initiates a sync to the revision of the previous history entry of an application which became degraded
+// within the health window of its auto rollback policy after its last sync, and records the rollback in the status
+func (ctrl *ApplicationController) autoRollback(app *appv1.Application, healthStatus *appv1.HealthStatus) (*appv1.ApplicationCondition, time.Duration) {
+	logCtx := getAppLog(app)
+	target, err := getAutoRollbackTarget(app, healthStatus, time.Now())
+	if err != nil {
+		return &appv1.ApplicationCondition{Type: appv1.ApplicationConditionSyncError, Message: err.Error()}, 0
+	}
+	if target == nil {
+		return nil, 0
+	}
+	current := app.Status.History.LastRevisionHistory()
+	fromRevision := historyRevision(current)
+	toRevision := historyRevision(*target)
+	maxAttempts := app.Spec.SyncPolicy.AutoRollback.GetMaxRollbackAttempts()
+	if attempts := app.Status.RollbackCount(fromRevision); attempts >= maxAttempts {
+		logCtx.Infof("Skipping auto-rollback: revision %s was already rolled back %d time(s)", fromRevision, attempts)
+		return nil, 0
+	}
+	if target.Source.IsZero() && target.Sources.IsZero() {
+		logCtx.Warnf("Skipping auto-rollback: history entry %d does not record the source of the application", target.ID)
+		return nil, 0
+	}
+
+	message := fmt.Sprintf("Application became %s within %s after the sync to %s", healthStatus.Status, app.Spec.SyncPolicy.AutoRollback.HealthWindow, fromRevision)
+	if healthStatus.Message != "" {
+		message = fmt.Sprintf("%s: %s", message, healthStatus.Message)
+	}
+	// the rollback is a sync to a previous revision, like the rollbacks initiated by users
+	op := appv1.Operation{
+		Sync: &appv1.SyncOperation{
+			Revision:     target.Revision,
+			Revisions:    target.Revisions,
+			SyncOptions:  app.Spec.SyncPolicy.SyncOptions,
+			SyncStrategy: &appv1.SyncStrategy{Apply: &appv1.SyncStrategyApply{}},
+			Source:       &target.Source,
+			Sources:      target.Sources,
+		},
+		InitiatedBy: appv1.OperationInitiator{Automated: true},
+		Info:        []*appv1.Info{{Name: autoRollbackInfoName, Value: message}},
+	}
+	appIf := ctrl.applicationClientset.ArgoprojV1alpha1().Applications(app.Namespace)
+	start := time.Now()
+	updatedApp, err := argo.SetAppOperation(appIf, app.Name, &op)
+	setOpTime := time.Since(start)
+	if err != nil {
+		if goerrors.Is(err, argo.ErrAnotherOperationInProgress) {
+			logCtx.Warnf("Failed to initiate auto-rollback to %s: %v", toRevision, err)
+			return nil, setOpTime
+		}
+		logCtx.Errorf("Failed to initiate auto-rollback to %s: %v", toRevision, err)
+		return &appv1.ApplicationCondition{Type: appv1.ApplicationConditionSyncError, Message: err.Error()}, setOpTime
+	}
+	ctrl.writeBackToInformer(updatedApp)
+	// the operation in progress prevents the automated sync of the degraded revision
+	app.Operation = updatedApp.Operation
+	app.Status.AddRollbackHistory(appv1.RollbackHistoryEntry{
+		FromRevision: fromRevision,
+		ToRevision:   toRevision,
+		ToHistoryID:  target.ID,
+		RolledBackAt: metav1.Now(),
+		Message:      message,
+	})
+	ctrl.metricsServer.IncRollback(app)
+
+	eventMessage := fmt.Sprintf("Initiated automated rollback to '%s': %s", toRevision, message)
+	ctrl.logAppEvent(app, argo.EventInfo{Reason: argo.EventReasonOperationStarted, Type: v1.EventTypeWarning}, eventMessage, context.TODO())
+	logCtx.Info(eventMessage)
+	return nil, setOpTime
+}
+
+// isRolledBackRevision returns true if the last operation of the application is an automatic rollback of the given
+// revision, which must not be synced again automatically
+func isRolledBackRevision(app *appv1.Application, revision string) bool {
+	if app.Status.OperationState == nil || !isAutoRollback(&app.Status.OperationState.Operation) || len(app.Status.RollbackHistory) == 0 {
+		return false
+	}
+	return app.Status.RollbackHistory[len(app.Status.RollbackHistory)-1].FromRevision == revision
+}
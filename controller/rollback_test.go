@@ -0,0 +1,188 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+var degradedHealth = &v1alpha1.HealthStatus{Status: health.HealthStatusDegraded, Message: "Deployment exceeded its progress deadline"}
+
+// newFakeDegradedApp returns an application with an auto rollback policy whose sync to revision "bad" finished at
+// syncedAt, after a sync to revision "good"
+func newFakeDegradedApp(syncedAt time.Time) *v1alpha1.Application {
+	app := newFakeApp()
+	app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{AutoRollback: &v1alpha1.AutoRollback{HealthWindow: "5m"}}
+	startedAt := metav1.NewTime(syncedAt.Add(-time.Minute))
+	finishedAt := metav1.NewTime(syncedAt)
+	app.Operation = nil
+	app.Status.OperationState = &v1alpha1.OperationState{
+		Operation:  v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{Revision: "bad"}},
+		Phase:      synccommon.OperationSucceeded,
+		StartedAt:  startedAt,
+		FinishedAt: &finishedAt,
+	}
+	goodStartedAt := metav1.NewTime(syncedAt.Add(-time.Hour))
+	app.Status.History = v1alpha1.RevisionHistories{
+		{ID: 1, Revision: "good", Source: app.Spec.GetSource(), DeployStartedAt: &goodStartedAt},
+		{ID: 2, Revision: "bad", Source: app.Spec.GetSource(), DeployStartedAt: &startedAt},
+	}
+	return app
+}
+
+func TestGetAutoRollbackTarget(t *testing.T) {
+	now := time.Now()
+
+	t.Run("DegradedWithinWindow", func(t *testing.T) {
+		target, err := getAutoRollbackTarget(newFakeDegradedApp(now.Add(-time.Minute)), degradedHealth, now)
+		require.NoError(t, err)
+		require.NotNil(t, target)
+		assert.Equal(t, int64(1), target.ID)
+		assert.Equal(t, "good", target.Revision)
+	})
+
+	t.Run("DegradedAfterWindow", func(t *testing.T) {
+		target, err := getAutoRollbackTarget(newFakeDegradedApp(now.Add(-10*time.Minute)), degradedHealth, now)
+		require.NoError(t, err)
+		assert.Nil(t, target)
+	})
+
+	t.Run("Healthy", func(t *testing.T) {
+		target, err := getAutoRollbackTarget(newFakeDegradedApp(now), &v1alpha1.HealthStatus{Status: health.HealthStatusHealthy}, now)
+		require.NoError(t, err)
+		assert.Nil(t, target)
+	})
+
+	t.Run("NoPolicy", func(t *testing.T) {
+		app := newFakeDegradedApp(now)
+		app.Spec.SyncPolicy.AutoRollback = nil
+		target, err := getAutoRollbackTarget(app, degradedHealth, now)
+		require.NoError(t, err)
+		assert.Nil(t, target)
+	})
+
+	t.Run("FailedSync", func(t *testing.T) {
+		app := newFakeDegradedApp(now)
+		app.Status.OperationState.Phase = synccommon.OperationFailed
+		target, err := getAutoRollbackTarget(app, degradedHealth, now)
+		require.NoError(t, err)
+		assert.Nil(t, target)
+	})
+
+	t.Run("DegradedRollback", func(t *testing.T) {
+		app := newFakeDegradedApp(now)
+		app.Status.OperationState.Operation.Info = []*v1alpha1.Info{{Name: autoRollbackInfoName, Value: "degraded"}}
+		target, err := getAutoRollbackTarget(app, degradedHealth, now)
+		require.NoError(t, err)
+		assert.Nil(t, target)
+	})
+
+	t.Run("PartialSync", func(t *testing.T) {
+		app := newFakeDegradedApp(now)
+		app.Status.OperationState.StartedAt = metav1.NewTime(now)
+		target, err := getAutoRollbackTarget(app, degradedHealth, now)
+		require.NoError(t, err)
+		assert.Nil(t, target)
+	})
+
+	t.Run("NoPreviousRevision", func(t *testing.T) {
+		app := newFakeDegradedApp(now)
+		app.Status.History = app.Status.History[1:]
+		target, err := getAutoRollbackTarget(app, degradedHealth, now)
+		require.NoError(t, err)
+		assert.Nil(t, target)
+	})
+
+	t.Run("InvalidWindow", func(t *testing.T) {
+		app := newFakeDegradedApp(now)
+		app.Spec.SyncPolicy.AutoRollback.HealthWindow = "five minutes"
+		_, err := getAutoRollbackTarget(app, degradedHealth, now)
+		require.ErrorContains(t, err, `invalid auto rollback health window "five minutes"`)
+	})
+}
+
+func TestAutoRollback(t *testing.T) {
+	t.Run("RollsBack", func(t *testing.T) {
+		app := newFakeDegradedApp(time.Now())
+		ctrl := newFakeController(&fakeData{apps: []runtime.Object{app}}, nil)
+		cond, _ := ctrl.autoRollback(app, degradedHealth)
+		assert.Nil(t, cond)
+		require.Len(t, app.Status.RollbackHistory, 1)
+		entry := app.Status.RollbackHistory[0]
+		assert.Equal(t, "bad", entry.FromRevision)
+		assert.Equal(t, "good", entry.ToRevision)
+		assert.Equal(t, int64(1), entry.ToHistoryID)
+		assert.Contains(t, entry.Message, "Application became Degraded within 5m after the sync to bad: Deployment exceeded its progress deadline")
+
+		updatedApp, err := ctrl.applicationClientset.ArgoprojV1alpha1().Applications(test.FakeArgoCDNamespace).Get(context.Background(), "my-app", metav1.GetOptions{})
+		require.NoError(t, err)
+		require.NotNil(t, updatedApp.Operation)
+		assert.Equal(t, "good", updatedApp.Operation.Sync.Revision)
+		assert.True(t, updatedApp.Operation.InitiatedBy.Automated)
+		assert.True(t, isAutoRollback(updatedApp.Operation))
+	})
+
+	t.Run("MaxRollbackAttemptsReached", func(t *testing.T) {
+		app := newFakeDegradedApp(time.Now())
+		app.Status.RollbackHistory = []v1alpha1.RollbackHistoryEntry{{FromRevision: "bad", ToRevision: "good", ToHistoryID: 1}}
+		ctrl := newFakeController(&fakeData{apps: []runtime.Object{app}}, nil)
+		cond, _ := ctrl.autoRollback(app, degradedHealth)
+		assert.Nil(t, cond)
+		assert.Len(t, app.Status.RollbackHistory, 1)
+
+		updatedApp, err := ctrl.applicationClientset.ArgoprojV1alpha1().Applications(test.FakeArgoCDNamespace).Get(context.Background(), "my-app", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Nil(t, updatedApp.Operation)
+	})
+
+	t.Run("InvalidWindow", func(t *testing.T) {
+		app := newFakeDegradedApp(time.Now())
+		app.Spec.SyncPolicy.AutoRollback.HealthWindow = "soon"
+		ctrl := newFakeController(&fakeData{apps: []runtime.Object{app}}, nil)
+		cond, _ := ctrl.autoRollback(app, degradedHealth)
+		require.NotNil(t, cond)
+		assert.Equal(t, v1alpha1.ApplicationConditionSyncError, cond.Type)
+	})
+}
+
+func TestAutoSync_SkipsRolledBackRevision(t *testing.T) {
+	app := newFakeApp()
+	app.Status.OperationState = &v1alpha1.OperationState{
+		Operation: v1alpha1.Operation{
+			Sync: &v1alpha1.SyncOperation{Revision: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+			Info: []*v1alpha1.Info{{Name: autoRollbackInfoName, Value: "degraded"}},
+		},
+		Phase:      synccommon.OperationSucceeded,
+		SyncResult: &v1alpha1.SyncOperationResult{Revision: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"},
+	}
+	app.Status.RollbackHistory = []v1alpha1.RollbackHistoryEntry{{FromRevision: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", ToRevision: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"}}
+	ctrl := newFakeController(&fakeData{apps: []runtime.Object{app}}, nil)
+	syncStatus := v1alpha1.SyncStatus{
+		Status:   v1alpha1.SyncStatusCodeOutOfSync,
+		Revision: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+	}
+	cond, _ := ctrl.autoSync(app, &syncStatus, []v1alpha1.ResourceStatus{{Name: "guestbook", Kind: "Deployment", Status: v1alpha1.SyncStatusCodeOutOfSync}})
+	assert.Nil(t, cond)
+	updatedApp, err := ctrl.applicationClientset.ArgoprojV1alpha1().Applications(test.FakeArgoCDNamespace).Get(context.Background(), "my-app", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, updatedApp.Operation)
+
+	// a new revision is synced
+	syncStatus.Revision = "cccccccccccccccccccccccccccccccccccccccc"
+	cond, _ = ctrl.autoSync(app, &syncStatus, []v1alpha1.ResourceStatus{{Name: "guestbook", Kind: "Deployment", Status: v1alpha1.SyncStatusCodeOutOfSync}})
+	assert.Nil(t, cond)
+	updatedApp, err = ctrl.applicationClientset.ArgoprojV1alpha1().Applications(test.FakeArgoCDNamespace).Get(context.Background(), "my-app", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, updatedApp.Operation)
+	assert.Equal(t, "cccccccccccccccccccccccccccccccccccccccc", updatedApp.Operation.Sync.Revision)
+}
@@ -20,6 +20,8 @@ type ClusterShardingCache interface {
 	IsManagedCluster(c *v1alpha1.Cluster) bool
 	GetDistribution() map[string]int
 	GetAppDistribution() map[string]int
+	GetShard() int
+	GetReplicas() int
 }
 
 type ClusterSharding struct {
@@ -52,6 +54,16 @@ func NewClusterSharding(_ db.ArgoDB, shard, replicas int, shardingAlgorithm stri
 	return clusterSharding
 }
 
+// GetShard returns the shard of the controller
+func (s *ClusterSharding) GetShard() int {
+	return s.Shard
+}
+
+// GetReplicas returns the number of shards of the controller
+func (s *ClusterSharding) GetReplicas() int {
+	return s.Replicas
+}
+
 // IsManagedCluster returns whether or not the cluster should be processed by a given shard.
 func (s *ClusterSharding) IsManagedCluster(c *v1alpha1.Cluster) bool {
 	s.lock.RLock()
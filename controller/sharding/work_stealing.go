@@ -0,0 +1,225 @@
+package sharding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultWorkStealAge is the duration after which the items waiting in the queue of a shard may be stolen by
+	// another shard
+	DefaultWorkStealAge = 30 * time.Second
+
+	workQueueKeyPrefix = "work-queue"
+	workLeaseKeyPrefix = "work-lease"
+)
+
+// releaseLeaseScript deletes the lease of an item only if it is held by the given shard
+var releaseLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// renewLeaseScript extends the lease of an item only if it is held by the given shard
+var renewLeaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// WorkStealingQueue shares the work items waiting in the queues of the controller shards in Redis, so that idle
+// shards can claim the items which overloaded shards did not make progress on. The items waiting in the queue of a
+// shard are stored in a sorted set whose key includes the shard, scored by the time they were queued. Items are
+// claimed with a lease held for a limited duration, so that an item is processed by a single shard at a time. The lease
+// is renewed while the item is processed, so that it only expires if the shard stops processing it, e.g. if it crashes.
+type WorkStealingQueue struct {
+	client   redis.UniversalClient
+	shard    int
+	replicas int
+	// stealAge is the duration after which the items waiting in the queue of a shard may be stolen
+	stealAge time.Duration
+	// leaseDuration is the duration an item stays claimed by a shard, unless the shard releases it
+	leaseDuration time.Duration
+	// renewInterval is the interval at which the leases of the items claimed by the shard are renewed
+	renewInterval time.Duration
+	now           func() time.Time
+	onStolen      func(key string, ownerShard int)
+	// leases holds the functions stopping the renewal of the leases of the items claimed by the shard
+	leases     map[string]*leaseRenewal
+	leasesLock sync.Mutex
+}
+
+// leaseRenewal renews the lease of an item claimed by a shard
+type leaseRenewal struct {
+	cancel context.CancelFunc
+}
+
+// NewWorkStealingQueue returns the queue of the given shard out of the given number of replicas. Items which waited
+// in the queue of a shard for longer than the steal age may be stolen by other shards.
+func NewWorkStealingQueue(client redis.UniversalClient, shard, replicas int, stealAge time.Duration) *WorkStealingQueue {
+	return &WorkStealingQueue{
+		client:        client,
+		shard:         shard,
+		replicas:      replicas,
+		stealAge:      stealAge,
+		leaseDuration: stealAge,
+		renewInterval: stealAge / 3,
+		now:           time.Now,
+		leases:        map[string]*leaseRenewal{},
+	}
+}
+
+// OnStolen registers a function called for each item stolen from the queue of another shard
+func (q *WorkStealingQueue) OnStolen(f func(key string, ownerShard int)) {
+	q.onStolen = f
+}
+
+func workQueueKey(shard int) string {
+	return fmt.Sprintf("%s|%d", workQueueKeyPrefix, shard)
+}
+
+func workLeaseKey(key string) string {
+	return fmt.Sprintf("%s|%s", workLeaseKeyPrefix, key)
+}
+
+// keepLease renews the lease of an item claimed by the shard until it is released
+func (q *WorkStealingQueue) keepLease(key string) {
+	q.leasesLock.Lock()
+	defer q.leasesLock.Unlock()
+	if _, ok := q.leases[key]; ok {
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	renewal := &leaseRenewal{cancel: cancel}
+	q.leases[key] = renewal
+	go func() {
+		defer q.removeLease(key, renewal)
+		ticker := time.NewTicker(q.renewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				renewed, err := renewLeaseScript.Run(ctx, q.client, []string{workLeaseKey(key)}, strconv.Itoa(q.shard), q.leaseDuration.Milliseconds()).Int()
+				if err != nil {
+					if ctx.Err() == nil {
+						log.Warnf("Failed to renew the lease of item %s: %v", key, err)
+					}
+					continue
+				}
+				if renewed == 0 {
+					// the lease expired and may be held by another shard
+					return
+				}
+			}
+		}
+	}()
+}
+
+// removeLease removes the renewal of the lease of an item, unless the item was claimed again in the meantime
+func (q *WorkStealingQueue) removeLease(key string, renewal *leaseRenewal) {
+	q.leasesLock.Lock()
+	defer q.leasesLock.Unlock()
+	if q.leases[key] == renewal {
+		delete(q.leases, key)
+	}
+}
+
+// stopLease stops renewing the lease of an item
+func (q *WorkStealingQueue) stopLease(key string) {
+	q.leasesLock.Lock()
+	defer q.leasesLock.Unlock()
+	if renewal, ok := q.leases[key]; ok {
+		renewal.cancel()
+		delete(q.leases, key)
+	}
+}
+
+// Add adds an item to the queue of the shard, to be available at the given time. An item already waiting in the
+// queue keeps the time it was first queued at, so that items which are queued again and again still become eligible
+// for stealing.
+func (q *WorkStealingQueue) Add(ctx context.Context, key string, at time.Time) error {
+	return q.client.ZAddNX(ctx, workQueueKey(q.shard), redis.Z{Score: float64(at.UnixMilli()), Member: key}).Err()
+}
+
+// Claim claims an item of the queue before it is processed. It returns false if the item is claimed by another
+// shard, which stole it. Otherwise the item is removed from the queue of the shard, so that it cannot be stolen while
+// it is processed.
+func (q *WorkStealingQueue) Claim(ctx context.Context, key string) (bool, error) {
+	leaseKey := workLeaseKey(key)
+	claimed, err := q.client.SetNX(ctx, leaseKey, strconv.Itoa(q.shard), q.leaseDuration).Result()
+	if err != nil {
+		return false, err
+	}
+	if !claimed {
+		owner, err := q.client.Get(ctx, leaseKey).Result()
+		if errors.Is(err, redis.Nil) {
+			// the lease expired in the meantime
+			return q.Claim(ctx, key)
+		} else if err != nil {
+			return false, err
+		}
+		if owner != strconv.Itoa(q.shard) {
+			return false, nil
+		}
+	}
+	q.keepLease(key)
+	return true, q.client.ZRem(ctx, workQueueKey(q.shard), key).Err()
+}
+
+// Done releases the lease of an item of the shard once it is processed. The lease of a stolen item is not released
+// but expires, see Forget.
+func (q *WorkStealingQueue) Done(ctx context.Context, key string) error {
+	q.stopLease(key)
+	return releaseLeaseScript.Run(ctx, q.client, []string{workLeaseKey(key)}, strconv.Itoa(q.shard)).Err()
+}
+
+// Forget stops renewing the lease of an item stolen from another shard once it is processed. The lease is left to
+// expire, so that the shard it was stolen from skips its own copy of the item in the meantime.
+func (q *WorkStealingQueue) Forget(key string) {
+	q.stopLease(key)
+}
+
+// Steal claims up to the given number of items which waited in the queues of the other shards for longer than the
+// steal age, oldest first within each shard, and returns their keys
+func (q *WorkStealingQueue) Steal(ctx context.Context, limit int) ([]string, error) {
+	var stolen []string
+	maxScore := strconv.FormatInt(q.now().Add(-q.stealAge).UnixMilli(), 10)
+	for i := 1; i < q.replicas && len(stolen) < limit; i++ {
+		// start with the next shard, so that the shards do not all steal from the first one
+		owner := (q.shard + i) % q.replicas
+		keys, err := q.client.ZRangeByScore(ctx, workQueueKey(owner), &redis.ZRangeBy{Min: "-inf", Max: maxScore, Count: int64(limit - len(stolen))}).Result()
+		if err != nil {
+			return stolen, fmt.Errorf("error getting the items of shard %d: %w", owner, err)
+		}
+		for _, key := range keys {
+			claimed, err := q.client.SetNX(ctx, workLeaseKey(key), strconv.Itoa(q.shard), q.leaseDuration).Result()
+			if err != nil {
+				return stolen, fmt.Errorf("error claiming item %s of shard %d: %w", key, owner, err)
+			}
+			if !claimed {
+				continue
+			}
+			if err := q.client.ZRem(ctx, workQueueKey(owner), key).Err(); err != nil {
+				return stolen, fmt.Errorf("error removing item %s from shard %d: %w", key, owner, err)
+			}
+			q.keepLease(key)
+			stolen = append(stolen, key)
+			if q.onStolen != nil {
+				q.onStolen(key, owner)
+			}
+		}
+	}
+	return stolen, nil
+}
@@ -0,0 +1,177 @@
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestWorkStealingQueues(t *testing.T, replicas int, now *time.Time) (*miniredis.Miniredis, []*WorkStealingQueue) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	queues := make([]*WorkStealingQueue, replicas)
+	for shard := range queues {
+		queues[shard] = NewWorkStealingQueue(client, shard, replicas, DefaultWorkStealAge)
+		queues[shard].now = func() time.Time { return *now }
+	}
+	return mr, queues
+}
+
+func TestWorkStealingQueue_Claim(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	mr, queues := newTestWorkStealingQueues(t, 2, &now)
+
+	require.NoError(t, queues[0].Add(ctx, "argocd/guestbook", now))
+	claimed, err := queues[0].Claim(ctx, "argocd/guestbook")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	// the claimed item cannot be stolen while it is processed, even if it waited for long
+	now = now.Add(time.Hour)
+	stolen, err := queues[1].Steal(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, stolen)
+
+	// the lease is held by shard 0 until it is released
+	claimed, err = queues[1].Claim(ctx, "argocd/guestbook")
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	require.NoError(t, queues[1].Done(ctx, "argocd/guestbook"))
+	assert.True(t, mr.Exists(workLeaseKey("argocd/guestbook")))
+	require.NoError(t, queues[0].Done(ctx, "argocd/guestbook"))
+	assert.False(t, mr.Exists(workLeaseKey("argocd/guestbook")))
+
+	// the lease expires if it is neither released nor renewed
+	claimed, err = queues[1].Claim(ctx, "argocd/guestbook")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	queues[1].Forget("argocd/guestbook")
+	mr.FastForward(DefaultWorkStealAge + time.Second)
+	claimed, err = queues[0].Claim(ctx, "argocd/guestbook")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func TestWorkStealingQueue_RenewLease(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	mr, queues := newTestWorkStealingQueues(t, 2, &now)
+	queues[0].renewInterval = 10 * time.Millisecond
+	leaseKey := workLeaseKey("argocd/guestbook")
+
+	claimed, err := queues[0].Claim(ctx, "argocd/guestbook")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	// the lease is renewed while the item is processed, for longer than the lease duration
+	for i := 0; i < 3; i++ {
+		mr.FastForward(DefaultWorkStealAge / 2)
+		assert.Eventually(t, func() bool {
+			return mr.TTL(leaseKey) == DefaultWorkStealAge
+		}, time.Second, 10*time.Millisecond)
+	}
+	claimed, err = queues[1].Claim(ctx, "argocd/guestbook")
+	require.NoError(t, err)
+	assert.False(t, claimed)
+
+	// the lease is not renewed once it is released, nor once it is claimed by another shard
+	require.NoError(t, queues[0].Done(ctx, "argocd/guestbook"))
+	assert.False(t, mr.Exists(leaseKey))
+	claimed, err = queues[1].Claim(ctx, "argocd/guestbook")
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	time.Sleep(50 * time.Millisecond)
+	mr.FastForward(DefaultWorkStealAge + time.Second)
+	assert.False(t, mr.Exists(leaseKey))
+}
+
+func TestWorkStealingQueue_Add(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	_, queues := newTestWorkStealingQueues(t, 2, &now)
+
+	require.NoError(t, queues[0].Add(ctx, "argocd/guestbook", now.Add(-time.Minute)))
+	// queuing the item again keeps the time it was first queued at
+	require.NoError(t, queues[0].Add(ctx, "argocd/guestbook", now))
+	// items delayed to the future are not stolen before they waited for the steal age
+	require.NoError(t, queues[0].Add(ctx, "argocd/delayed", now.Add(time.Minute)))
+
+	stolen, err := queues[1].Steal(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"argocd/guestbook"}, stolen)
+}
+
+// TestWorkStealingQueue_Overload simulates a shard which is overloaded while the other shards are idle
+func TestWorkStealingQueue_Overload(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	_, queues := newTestWorkStealingQueues(t, 3, &now)
+	stolenFrom := map[string]int{}
+	stolenBy := map[string]int{}
+	for _, queue := range queues {
+		queue := queue
+		queue.OnStolen(func(key string, ownerShard int) {
+			stolenFrom[key] = ownerShard
+			stolenBy[key] = queue.shard
+		})
+	}
+
+	const items = 25
+	for i := 0; i < items; i++ {
+		require.NoError(t, queues[0].Add(ctx, fmt.Sprintf("argocd/app-%02d", i), now))
+	}
+	// the overloaded shard only processes some of its items
+	for i := 0; i < 3; i++ {
+		key := fmt.Sprintf("argocd/app-%02d", i)
+		claimed, err := queues[0].Claim(ctx, key)
+		require.NoError(t, err)
+		require.True(t, claimed)
+		require.NoError(t, queues[0].Done(ctx, key))
+	}
+
+	// nothing is stolen before the items waited for the steal age
+	now = now.Add(DefaultWorkStealAge / 2)
+	stolen, err := queues[1].Steal(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, stolen)
+
+	// the idle shards steal the oldest items of the overloaded shard, each item once
+	now = now.Add(DefaultWorkStealAge)
+	stolen1, err := queues[1].Steal(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, stolen1, 10)
+	assert.Equal(t, "argocd/app-03", stolen1[0])
+	stolen2, err := queues[2].Steal(ctx, 20)
+	require.NoError(t, err)
+	assert.Len(t, stolen2, items-3-10)
+	assert.NotContains(t, stolen2, stolen1[0])
+	assert.Len(t, stolenFrom, items-3)
+	for key, owner := range stolenFrom {
+		assert.Equal(t, 0, owner, key)
+	}
+
+	// the overloaded shard skips the stolen items
+	for _, key := range append(stolen1, stolen2...) {
+		claimed, err := queues[0].Claim(ctx, key)
+		require.NoError(t, err)
+		assert.False(t, claimed, key)
+		// the thief processes its stolen items
+		claimed, err = queues[stolenBy[key]].Claim(ctx, key)
+		require.NoError(t, err)
+		assert.True(t, claimed, key)
+	}
+
+	// nothing is left to steal
+	stolen, err = queues[1].Steal(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, stolen)
+}
@@ -2,7 +2,6 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -24,7 +23,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 
 	"github.com/argoproj/argo-cd/v2/common"
@@ -37,6 +37,7 @@ import (
 	"github.com/argoproj/argo-cd/v2/util/argo"
 	argodiff "github.com/argoproj/argo-cd/v2/util/argo/diff"
 	"github.com/argoproj/argo-cd/v2/util/argo/normalizers"
+	"github.com/argoproj/argo-cd/v2/util/attestation"
 	appstatecache "github.com/argoproj/argo-cd/v2/util/cache/appstate"
 	"github.com/argoproj/argo-cd/v2/util/db"
 	"github.com/argoproj/argo-cd/v2/util/gpg"
@@ -84,10 +85,32 @@ type comparisonResult struct {
 	appSourceType        v1alpha1.ApplicationSourceType
 	// appSourceTypes stores the SourceType for each application source under sources field
 	appSourceTypes []v1alpha1.ApplicationSourceType
+	// autoValueFiles are the Helm value files appended to the value files of the sources by autoValuesFile
+	autoValueFiles []string
 	// timings maps phases of comparison to the duration it took to complete (for statistical purposes)
 	timings            map[string]time.Duration
 	diffResultList     *diff.DiffResultList
 	hasPostDeleteHooks bool
+	hasPreDeleteHooks  bool
+	// destinations are the comparisons of the additional destinations of the application
+	destinations []destinationComparison
+	// destinationStatuses are the sync status and the health of each destination of the application
+	destinationStatuses []v1alpha1.DestinationSyncStatus
+}
+
+// generatedTargets are the target objects generated once for all the destinations of an application
+type generatedTargets struct {
+	objs             []*unstructured.Unstructured
+	manifestInfos    []*apiclient.ManifestResponse
+	conditions       []v1alpha1.ApplicationCondition
+	failedToLoadObjs bool
+}
+
+// destinationComparison is the comparison of an additional destination of an application
+type destinationComparison struct {
+	// app is a copy of the application which targets the additional destination
+	app    *v1alpha1.Application
+	result *comparisonResult
 }
 
 func (res *comparisonResult) GetSyncStatus() *v1alpha1.SyncStatus {
@@ -117,6 +140,66 @@ type appStateManager struct {
 	repoErrorGracePeriod  time.Duration
 	serverSideDiff        bool
 	ignoreNormalizerOpts  normalizers.IgnoreNormalizerOpts
+	// defaultHealthForUnknownResources is the health assumed for resources without a health check
+	defaultHealthForUnknownResources health.HealthStatusCode
+	// disableHealthOverrides disables the health-override annotation of resources
+	disableHealthOverrides bool
+	// projectResourceUsage is used to enforce project resource quotas, nil disables the enforcement
+	projectResourceUsage *projectResourceUsage
+	// auditLogger emits the events of sync operations, nil disables the events
+	auditLogger *argo.AuditLogger
+	// attestationVerifier verifies the attestations of the container images of sources requiring them
+	attestationVerifier attestation.AttestationVerifier
+	// applyRateLimiters limit the requests modifying resources during syncs, nil disables the limits
+	applyRateLimiters *applyRateLimiters
+	// artifactStorer stores the manifests applied by successful syncs in the registry configured in the project, nil
+	// disables storing them
+	artifactStorer *ArtifactStorer
+	// globalSyncTimeout is the duration after which syncs fail if they did not complete, unless the application sets
+	// its own sync timeout, zero disables the timeout
+	globalSyncTimeout time.Duration
+	// syncAttempts remembers when the retries of sync operations started, to apply the sync timeout to each attempt
+	syncAttempts *syncAttempts
+	// syncProgress tracks the progress of sync operations, to estimate when they complete
+	syncProgress *syncProgress
+	// syncSnapshotRetention is the duration the live state of the resources captured before syncs is kept, zero disables
+	// capturing it
+	syncSnapshotRetention time.Duration
+	// defaultResourceApplyTimeout is the duration after which the apply of a resource fails, unless the application sets
+	// an apply timeout for the kind of the resource, zero disables the timeout
+	defaultResourceApplyTimeout time.Duration
+	// enableSyncCheckpoints records the resources applied by syncs, so that syncs resumed after a restart of the
+	// controller do not apply them again
+	enableSyncCheckpoints bool
+	// disableResourceAnnotationTemplates disables the injection of the annotations produced by the templates of the
+	// argocd-resource-annotations ConfigMap into the applied resources
+	disableResourceAnnotationTemplates bool
+	// kubeClientForDestination overrides the creation of the clients of destination clusters in tests
+	kubeClientForDestination func(app *v1alpha1.Application) (kubernetes.Interface, error)
+	// dynamicClientForDestination overrides the creation of the dynamic clients of destination clusters in tests
+	dynamicClientForDestination func(app *v1alpha1.Application) (dynamic.Interface, error)
+}
+
+// annotateTargets returns the target objects of the application with the annotations produced by the resource
+// annotation templates
+func (m *appStateManager) annotateTargets(app *v1alpha1.Application, templates resourceAnnotationTemplates, targets []*unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	clst, err := m.db.GetCluster(context.Background(), app.Spec.Destination.Server)
+	if err != nil {
+		return nil, fmt.Errorf("error getting cluster %q: %w", app.Spec.Destination.Server, err)
+	}
+	return templates.annotateAll(targets, resourceAnnotationContext(app, clst.Name))
+}
+
+// getDestinationKubeClient returns a client of the destination cluster of the given application
+func (m *appStateManager) getDestinationKubeClient(app *v1alpha1.Application) (kubernetes.Interface, error) {
+	if m.kubeClientForDestination != nil {
+		return m.kubeClientForDestination(app)
+	}
+	clst, err := m.db.GetCluster(context.Background(), app.Spec.Destination.Server)
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(clst.RESTConfig())
 }
 
 // GetRepoObjs will generate the manifests for the given application delegating the
@@ -165,6 +248,7 @@ func (m *appStateManager) GetRepoObjs(app *v1alpha1.Application, sources []v1alp
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to get cluster version for cluster %q: %w", app.Spec.Destination.Server, err)
 	}
+	clusterScopedResources, permittedClusterResources := argo.NamespaceIsolationResources(proj, apiResources)
 	conn, repoClient, err := m.repoClientset.NewRepoServerClient()
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to connect to repo server: %w", err)
@@ -182,11 +266,44 @@ func (m *appStateManager) GetRepoObjs(app *v1alpha1.Application, sources []v1alp
 		return nil, nil, fmt.Errorf("failed to get ref sources: %w", err)
 	}
 
+	// The client of the destination cluster is only created when some source reads Helm values from it, and the cluster
+	// is only read when some source selects Helm value files by its labels or name
+	var destKubeClient kubernetes.Interface
+	var destCluster *v1alpha1.Cluster
 	for i, source := range sources {
 		if len(revisions) < len(sources) || revisions[i] == "" {
 			revisions[i] = source.TargetRevision
 		}
+		var helmValuesFrom []string
+		if source.Helm != nil && source.Helm.ValuesFrom != nil {
+			if destKubeClient == nil {
+				destKubeClient, err = m.getDestinationKubeClient(app)
+				if err != nil {
+					return nil, nil, fmt.Errorf("failed to create client of cluster %q: %w", app.Spec.Destination.Server, err)
+				}
+			}
+			helmValuesFrom, err = argo.GetHelmValuesFrom(context.Background(), destKubeClient, source, app.Spec.Destination, proj, func(project string) ([]*v1alpha1.Cluster, error) {
+				return m.db.GetProjectClusters(context.Background(), project)
+			})
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get Helm values for source %d of %d: %w", i+1, len(sources), err)
+			}
+		}
+		if source.Helm != nil && (source.Helm.ValueFilesSuffix != "" || source.Helm.AutoValuesFile) && destCluster == nil {
+			destCluster, err = m.db.GetCluster(context.Background(), app.Spec.Destination.Server)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get cluster %q: %w", app.Spec.Destination.Server, err)
+			}
+		}
 		ts.AddCheckpoint("helm_ms")
+		httpSourceCABundle, err := argo.GetHTTPSourceCABundle(m.settingsMgr, source)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get CA bundle of HTTP source %d of %d: %w", i+1, len(sources), err)
+		}
+		inTotoLayout, inTotoLayoutKeys, err := argo.GetInTotoLayout(m.settingsMgr, source)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get in-toto layout of source %d of %d: %w", i+1, len(sources), err)
+		}
 		repo, err := m.db.GetRepository(context.Background(), source.RepoURL, proj.Name)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to get repo %q: %w", source.RepoURL, err)
@@ -231,27 +348,37 @@ func (m *appStateManager) GetRepoObjs(app *v1alpha1.Application, sources []v1alp
 		ts.AddCheckpoint("version_ms")
 		log.Debugf("Generating Manifest for source %s revision %s", source, revisions[i])
 		manifestInfo, err := repoClient.GenerateManifest(context.Background(), &apiclient.ManifestRequest{
-			Repo:               repo,
-			Repos:              permittedHelmRepos,
-			Revision:           revisions[i],
-			NoCache:            noCache,
-			NoRevisionCache:    noRevisionCache,
-			AppLabelKey:        appLabelKey,
-			AppName:            app.InstanceName(m.namespace),
-			Namespace:          app.Spec.Destination.Namespace,
-			ApplicationSource:  &source,
-			KustomizeOptions:   kustomizeOptions,
-			KubeVersion:        serverVersion,
-			ApiVersions:        argo.APIResourcesToStrings(apiResources, true),
-			VerifySignature:    verifySignature,
-			HelmRepoCreds:      permittedHelmCredentials,
-			TrackingMethod:     string(argo.GetTrackingMethod(m.settingsMgr)),
-			EnabledSourceTypes: enabledSourceTypes,
-			HelmOptions:        helmOptions,
-			HasMultipleSources: app.Spec.HasMultipleSources(),
-			RefSources:         refSources,
-			ProjectName:        proj.Name,
-			ProjectSourceRepos: proj.Spec.SourceRepos,
+			Repo:                      repo,
+			Repos:                     permittedHelmRepos,
+			Revision:                  revisions[i],
+			NoCache:                   noCache,
+			NoRevisionCache:           noRevisionCache,
+			AppLabelKey:               appLabelKey,
+			AppName:                   app.InstanceName(m.namespace),
+			Namespace:                 app.Spec.Destination.Namespace,
+			ApplicationSource:         &source,
+			KustomizeOptions:          kustomizeOptions,
+			KubeVersion:               serverVersion,
+			ApiVersions:               argo.APIResourcesToStrings(apiResources, true),
+			VerifySignature:           verifySignature,
+			HelmRepoCreds:             permittedHelmCredentials,
+			TrackingMethod:            string(argo.GetTrackingMethod(m.settingsMgr)),
+			EnabledSourceTypes:        enabledSourceTypes,
+			HelmOptions:               helmOptions,
+			HasMultipleSources:        app.Spec.HasMultipleSources(),
+			RefSources:                refSources,
+			ProjectName:               proj.Name,
+			ProjectSourceRepos:        proj.Spec.SourceRepos,
+			NamespaceIsolation:        proj.Spec.NamespaceIsolation,
+			ClusterScopedResources:    clusterScopedResources,
+			PermittedClusterResources: permittedClusterResources,
+			HelmValuesFrom:            helmValuesFrom,
+			HelmValueFilesSuffix:      argo.GetHelmValueFilesSuffix(source, destCluster),
+			DestinationClusterName:    argo.GetHelmDestinationClusterName(source, destCluster),
+			HttpSourceCABundle:        httpSourceCABundle,
+			InTotoLayout:              inTotoLayout,
+			InTotoLayoutKeys:          inTotoLayoutKeys,
+			DestinationServer:         app.Spec.Destination.Server,
 		})
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to generate manifest for source %d of %d: %w", i+1, len(sources), err)
@@ -392,6 +519,13 @@ func isManagedNamespace(ns *unstructured.Unstructured, app *v1alpha1.Application
 // revision and supplied source. If revision or overrides are empty, then compares against
 // revision and overrides in the app spec.
 func (m *appStateManager) CompareAppState(app *v1alpha1.Application, project *v1alpha1.AppProject, revisions []string, sources []v1alpha1.ApplicationSource, noCache bool, noRevisionCache bool, localManifests []string, hasMultipleSources bool, rollback bool) (*comparisonResult, error) {
+	return m.compareAppState(app, project, revisions, sources, noCache, noRevisionCache, localManifests, hasMultipleSources, rollback, nil)
+}
+
+// compareAppState compares the application to its destination. The target objects are generated from the sources
+// unless they were already generated for the application, which is the case when comparing the additional
+// destinations of the application.
+func (m *appStateManager) compareAppState(app *v1alpha1.Application, project *v1alpha1.AppProject, revisions []string, sources []v1alpha1.ApplicationSource, noCache bool, noRevisionCache bool, localManifests []string, hasMultipleSources bool, rollback bool, generated *generatedTargets) (*comparisonResult, error) {
 	ts := stats.NewTimingStats()
 	appLabelKey, resourceOverrides, resFilter, err := m.getComparisonSettings()
 
@@ -439,7 +573,15 @@ func (m *appStateManager) CompareAppState(app *v1alpha1.Application, project *v1
 	var manifestInfos []*apiclient.ManifestResponse
 	targetNsExists := false
 
-	if len(localManifests) == 0 {
+	if generated != nil {
+		targetObjs = make([]*unstructured.Unstructured, 0, len(generated.objs))
+		for _, obj := range generated.objs {
+			targetObjs = append(targetObjs, obj.DeepCopy())
+		}
+		manifestInfos = generated.manifestInfos
+		conditions = append(conditions, generated.conditions...)
+		failedToLoadObjs = generated.failedToLoadObjs
+	} else if len(localManifests) == 0 {
 		// If the length of revisions is not same as the length of sources,
 		// we take the revisions from the sources directly for all the sources.
 		if len(revisions) != len(sources) {
@@ -492,6 +634,20 @@ func (m *appStateManager) CompareAppState(app *v1alpha1.Application, project *v1
 	}
 	ts.AddCheckpoint("git_ms")
 
+	// the manifests are generated once and compared to each additional destination of the application
+	var fanOut *generatedTargets
+	if generated == nil && app.Spec.HasMultipleDestinations() {
+		fanOut = &generatedTargets{
+			objs:             make([]*unstructured.Unstructured, 0, len(targetObjs)),
+			manifestInfos:    manifestInfos,
+			conditions:       append([]v1alpha1.ApplicationCondition{}, conditions...),
+			failedToLoadObjs: failedToLoadObjs,
+		}
+		for _, obj := range targetObjs {
+			fanOut.objs = append(fanOut.objs, obj.DeepCopy())
+		}
+	}
+
 	var infoProvider kubeutil.ResourceInfoProvider
 	infoProvider, err = m.liveStateCache.GetClusterCache(app.Spec.Destination.Server)
 	if err != nil {
@@ -506,7 +662,7 @@ func (m *appStateManager) CompareAppState(app *v1alpha1.Application, project *v1
 	for i := len(targetObjs) - 1; i >= 0; i-- {
 		targetObj := targetObjs[i]
 		gvk := targetObj.GroupVersionKind()
-		if resFilter.IsExcludedResource(gvk.Group, gvk.Kind, app.Spec.Destination.Server) {
+		if resFilter.IsExcludedResource(gvk.Group, gvk.Kind, app.Spec.Destination.Server) && !isResourceIncludedByAnnotation(targetObj) {
 			targetObjs = append(targetObjs[:i], targetObjs[i+1:]...)
 			conditions = append(conditions, v1alpha1.ApplicationCondition{
 				Type:               v1alpha1.ApplicationConditionExcludedResourceWarning,
@@ -524,16 +680,54 @@ func (m *appStateManager) CompareAppState(app *v1alpha1.Application, project *v1
 	}
 	ts.AddCheckpoint("dedup_ms")
 
-	liveObjByKey, err := m.liveStateCache.GetManagedLiveObjs(app, targetObjs)
+	// The resources managed by applications reading the inventory of another GitOps tool are the resources in the
+	// inventory, instead of the resources tracked by Argo CD
+	inventoryReader, err := m.getInventoryReader(app)
+	var inventory []InventoryResource
+	if err == nil && inventoryReader != nil {
+		inventory, err = inventoryReader.ReadInventory(context.Background(), app)
+	}
+	if err != nil {
+		msg := fmt.Sprintf("Failed to read inventory: %s", err.Error())
+		conditions = append(conditions, v1alpha1.ApplicationCondition{Type: v1alpha1.ApplicationConditionComparisonError, Message: msg, LastTransitionTime: &now})
+		failedToLoadObjs = true
+	}
+
+	liveObjByKey, err := m.liveStateCache.GetManagedLiveObjs(app, append(inventoryObjects(inventory), targetObjs...))
 	if err != nil {
 		liveObjByKey = make(map[kubeutil.ResourceKey]*unstructured.Unstructured)
 		msg := fmt.Sprintf("Failed to load live state: %s", err.Error())
 		conditions = append(conditions, v1alpha1.ApplicationCondition{Type: v1alpha1.ApplicationConditionComparisonError, Message: msg, LastTransitionTime: &now})
 		failedToLoadObjs = true
 	}
+	if inventoryReader != nil {
+		managed := make(map[kubeutil.ResourceKey]bool)
+		for _, res := range inventory {
+			managed[res.ResourceKey] = true
+		}
+		for _, obj := range targetObjs {
+			managed[kubeutil.GetResourceKey(obj)] = true
+		}
+		for k := range liveObjByKey {
+			if !managed[k] {
+				delete(liveObjByKey, k)
+			}
+		}
+	}
 
 	logCtx.Debugf("Retrieved live manifests")
 
+	// resources annotated to be unmanaged are neither compared nor synced, and are reported as orphaned resources
+	targetObjs, excludedTargetObjs := filterExcludedResources(targetObjs, liveObjByKey)
+	for _, targetObj := range excludedTargetObjs {
+		gvk := targetObj.GroupVersionKind()
+		conditions = append(conditions, v1alpha1.ApplicationCondition{
+			Type:               v1alpha1.ApplicationConditionExcludedResourceWarning,
+			Message:            fmt.Sprintf("Resource %s/%s %s is excluded by the %s annotation", gvk.Group, gvk.Kind, targetObj.GetName(), common.AnnotationExcludeResource),
+			LastTransitionTime: &now,
+		})
+	}
+
 	// filter out all resources which are not permitted in the application project
 	for k, v := range liveObjByKey {
 		permitted, err := project.IsLiveResourcePermitted(v, app.Spec.Destination.Server, app.Spec.Destination.Name, func(project string) ([]*v1alpha1.Cluster, error) {
@@ -602,10 +796,14 @@ func (m *appStateManager) CompareAppState(app *v1alpha1.Application, project *v1
 		}
 	}
 	hasPostDeleteHooks := false
+	hasPreDeleteHooks := false
 	for _, obj := range targetObjs {
 		if isPostDeleteHook(obj) {
 			hasPostDeleteHooks = true
 		}
+		if isPreDeleteHook(obj) {
+			hasPreDeleteHooks = true
+		}
 	}
 
 	reconciliation := sync.Reconcile(targetObjs, liveObjByKey, app.Spec.Destination.Namespace, infoProvider)
@@ -616,26 +814,24 @@ func (m *appStateManager) CompareAppState(app *v1alpha1.Application, project *v1
 		log.Warnf("Could not get compare options from ConfigMap (assuming defaults): %v", err)
 		compareOptions = settings.GetDefaultDiffOptions()
 	}
+	diffPlugins, err := m.settingsMgr.GetDiffPlugins()
+	if err != nil {
+		log.Warnf("Could not get diff plugins from ConfigMap (using the default diff): %v", err)
+	}
 	manifestRevisions := make([]string, 0)
 
 	for _, manifestInfo := range manifestInfos {
 		manifestRevisions = append(manifestRevisions, manifestInfo.Revision)
 	}
 
-	serverSideDiff := m.serverSideDiff ||
-		resourceutil.HasAnnotationOption(app, common.AnnotationCompareOptions, "ServerSideDiff=true")
-
-	// This allows turning SSD off for a given app if it is enabled at the
-	// controller level
-	if resourceutil.HasAnnotationOption(app, common.AnnotationCompareOptions, "ServerSideDiff=false") {
-		serverSideDiff = false
-	}
+	serverSideDiff := useServerSideDiff(app, m.serverSideDiff)
 
 	useDiffCache := useDiffCache(noCache, manifestInfos, sources, app, manifestRevisions, m.statusRefreshTimeout, serverSideDiff, logCtx)
 
 	diffConfigBuilder := argodiff.NewDiffConfigBuilder().
 		WithDiffSettings(app.Spec.IgnoreDifferences, resourceOverrides, compareOptions.IgnoreAggregatedRoles, m.ignoreNormalizerOpts).
-		WithTracking(appLabelKey, string(trackingMethod))
+		WithTracking(appLabelKey, string(trackingMethod)).
+		WithDiffPlugins(diffPlugins)
 
 	if useDiffCache {
 		diffConfigBuilder.WithCache(m.cache, app.InstanceName(m.namespace))
@@ -675,7 +871,17 @@ func (m *appStateManager) CompareAppState(app *v1alpha1.Application, project *v1
 	// application conditions as argo.StateDiffs will validate this diffConfig again.
 	diffConfig, _ := diffConfigBuilder.Build()
 
-	diffResults, err := argodiff.StateDiffs(reconciliation.Live, reconciliation.Target, diffConfig)
+	// the annotations injected into the resources when they are applied are expected in the live state
+	diffTargets := reconciliation.Target
+	if templates := m.getResourceAnnotationTemplates(); len(templates) > 0 {
+		diffTargets, err = m.annotateTargets(app, templates, reconciliation.Target)
+		if err != nil {
+			diffTargets = reconciliation.Target
+			conditions = append(conditions, v1alpha1.ApplicationCondition{Type: v1alpha1.ApplicationConditionComparisonError, Message: err.Error(), LastTransitionTime: &now})
+		}
+	}
+
+	diffResults, err := argodiff.StateDiffs(reconciliation.Live, diffTargets, diffConfig)
 	if err != nil {
 		diffResults = &diff.DiffResultList{}
 		failedToLoadObjs = true
@@ -817,7 +1023,11 @@ func (m *appStateManager) CompareAppState(app *v1alpha1.Application, project *v1
 
 	ts.AddCheckpoint("sync_ms")
 
-	healthStatus, err := setApplicationHealth(managedResources, resourceSummaries, resourceOverrides, app, m.persistResourceHealth)
+	healthOverrideScripts, err := m.settingsMgr.GetHealthOverrideScripts()
+	if err != nil {
+		log.Warnf("Failed to get health overrides: %v", err)
+	}
+	healthStatus, err := setApplicationHealth(managedResources, resourceSummaries, resourceOverrides, healthOverrideScripts, app, m.persistResourceHealth, m.defaultHealthForUnknownResources, m.disableHealthOverrides)
 	if err != nil {
 		conditions = append(conditions, v1alpha1.ApplicationCondition{Type: v1alpha1.ApplicationConditionComparisonError, Message: fmt.Sprintf("error setting app health: %s", err.Error()), LastTransitionTime: &now})
 	}
@@ -831,6 +1041,23 @@ func (m *appStateManager) CompareAppState(app *v1alpha1.Application, project *v1
 		}
 	}
 
+	// Warnings reported while generating the manifests, e.g. Kustomize schema validation warnings, do not prevent the
+	// comparison but are surfaced as conditions.
+	for _, manifestInfo := range manifestInfos {
+		if manifestInfo == nil {
+			continue
+		}
+		for _, warning := range manifestInfo.Warnings {
+			conditions = append(conditions, v1alpha1.ApplicationCondition{Type: v1alpha1.ApplicationConditionSyncWarning, Message: warning, LastTransitionTime: &now})
+		}
+	}
+
+	// The API versions of the target resources are checked against the destination cluster before they are synced, if
+	// the project requires it
+	if project.IsClusterVersionCheckPreSync() && syncCode == v1alpha1.SyncStatusCodeOutOfSync {
+		conditions = append(conditions, m.checkClusterVersion(app, targetObjs, now)...)
+	}
+
 	compRes := comparisonResult{
 		syncStatus:           &syncStatus,
 		healthStatus:         healthStatus,
@@ -840,6 +1067,13 @@ func (m *appStateManager) CompareAppState(app *v1alpha1.Application, project *v1
 		diffConfig:           diffConfig,
 		diffResultList:       diffResults,
 		hasPostDeleteHooks:   hasPostDeleteHooks,
+		hasPreDeleteHooks:    hasPreDeleteHooks,
+	}
+
+	for _, manifestInfo := range manifestInfos {
+		if manifestInfo != nil {
+			compRes.autoValueFiles = append(compRes.autoValueFiles, manifestInfo.AutoValueFiles...)
+		}
 	}
 
 	if hasMultipleSources {
@@ -853,11 +1087,26 @@ func (m *appStateManager) CompareAppState(app *v1alpha1.Application, project *v1
 		}
 	}
 
+	// the quota is checked once for the application, against the target resources of its destination
+	if generated == nil {
+		if err := m.projectResourceUsage.checkQuota(project, app, reconciliation.Target); err != nil {
+			conditions = append(conditions, v1alpha1.ApplicationCondition{Type: v1alpha1.ApplicationConditionProjectQuotaExceeded, Message: err.Error(), LastTransitionTime: &now})
+		}
+	}
+
+	if fanOut != nil {
+		compRes.destinations = m.compareDestinations(app, project, revisions, sources, noRevisionCache, hasMultipleSources, rollback, fanOut)
+		compRes.destinationStatuses = compRes.aggregateDestinations(app)
+		conditions = append(conditions, destinationConditions(compRes.destinations, fanOut.conditions, now)...)
+	}
+
 	app.Status.SetConditions(conditions, map[v1alpha1.ApplicationConditionType]bool{
 		v1alpha1.ApplicationConditionComparisonError:         true,
 		v1alpha1.ApplicationConditionSharedResourceWarning:   true,
 		v1alpha1.ApplicationConditionRepeatedResourceWarning: true,
 		v1alpha1.ApplicationConditionExcludedResourceWarning: true,
+		v1alpha1.ApplicationConditionProjectQuotaExceeded:    true,
+		v1alpha1.ApplicationConditionSyncWarning:             true,
 	})
 	ts.AddCheckpoint("health_ms")
 	compRes.timings = ts.Timings()
@@ -917,6 +1166,7 @@ func (m *appStateManager) persistRevisionHistory(
 	hasMultipleSources bool,
 	startedAt metav1.Time,
 	initiatedBy v1alpha1.OperationInitiator,
+	retentionPolicy *v1alpha1.HistoryRetentionPolicy,
 ) error {
 	var nextID int64
 	if len(app.Status.History) > 0 {
@@ -944,17 +1194,16 @@ func (m *appStateManager) persistRevisionHistory(
 	}
 
 	app.Status.History = app.Status.History.Trunc(app.Spec.GetRevisionHistoryLimit())
+	var pruned int
+	app.Status.History, pruned = pruneRevisionHistory(app.Status.History, retentionPolicy, time.Now())
 
-	patch, err := json.Marshal(map[string]map[string][]v1alpha1.RevisionHistory{
-		"status": {
-			"history": app.Status.History,
-		},
-	})
-	if err != nil {
-		return fmt.Errorf("error marshaling revision history patch: %w", err)
+	if err := patchRevisionHistory(context.Background(), m.appclientset, app, app.Status.History); err != nil {
+		return err
 	}
-	_, err = m.appclientset.ArgoprojV1alpha1().Applications(app.Namespace).Patch(context.Background(), app.Name, types.MergePatchType, patch, metav1.PatchOptions{})
-	return err
+	if pruned > 0 && m.metricsServer != nil {
+		m.metricsServer.AddHistoryPruned(app, pruned)
+	}
+	return nil
 }
 
 // NewAppStateManager creates new instance of AppStateManager
@@ -975,24 +1224,49 @@ func NewAppStateManager(
 	repoErrorGracePeriod time.Duration,
 	serverSideDiff bool,
 	ignoreNormalizerOpts normalizers.IgnoreNormalizerOpts,
+	defaultHealthForUnknownResources health.HealthStatusCode,
+	disableHealthOverrides bool,
+	projectResourceUsage *projectResourceUsage,
+	auditLogger *argo.AuditLogger,
+	applyRateLimiters *applyRateLimiters,
+	artifactStorer *ArtifactStorer,
+	globalSyncTimeout time.Duration,
+	syncSnapshotRetention time.Duration,
+	defaultResourceApplyTimeout time.Duration,
+	enableSyncCheckpoints bool,
+	disableResourceAnnotationTemplates bool,
 ) AppStateManager {
 	return &appStateManager{
-		liveStateCache:        liveStateCache,
-		cache:                 cache,
-		db:                    db,
-		appclientset:          appclientset,
-		kubectl:               kubectl,
-		repoClientset:         repoClientset,
-		namespace:             namespace,
-		settingsMgr:           settingsMgr,
-		projInformer:          projInformer,
-		metricsServer:         metricsServer,
-		statusRefreshTimeout:  statusRefreshTimeout,
-		resourceTracking:      resourceTracking,
-		persistResourceHealth: persistResourceHealth,
-		repoErrorGracePeriod:  repoErrorGracePeriod,
-		serverSideDiff:        serverSideDiff,
-		ignoreNormalizerOpts:  ignoreNormalizerOpts,
+		liveStateCache:                     liveStateCache,
+		cache:                              cache,
+		db:                                 db,
+		appclientset:                       appclientset,
+		kubectl:                            kubectl,
+		repoClientset:                      repoClientset,
+		namespace:                          namespace,
+		settingsMgr:                        settingsMgr,
+		projInformer:                       projInformer,
+		metricsServer:                      metricsServer,
+		statusRefreshTimeout:               statusRefreshTimeout,
+		resourceTracking:                   resourceTracking,
+		persistResourceHealth:              persistResourceHealth,
+		repoErrorGracePeriod:               repoErrorGracePeriod,
+		serverSideDiff:                     serverSideDiff,
+		ignoreNormalizerOpts:               ignoreNormalizerOpts,
+		defaultHealthForUnknownResources:   defaultHealthForUnknownResources,
+		disableHealthOverrides:             disableHealthOverrides,
+		projectResourceUsage:               projectResourceUsage,
+		auditLogger:                        auditLogger,
+		attestationVerifier:                attestation.NewAttestationVerifier(),
+		applyRateLimiters:                  applyRateLimiters,
+		artifactStorer:                     artifactStorer,
+		globalSyncTimeout:                  globalSyncTimeout,
+		syncAttempts:                       newSyncAttempts(),
+		syncProgress:                       newSyncProgress(),
+		syncSnapshotRetention:              syncSnapshotRetention,
+		defaultResourceApplyTimeout:        defaultResourceApplyTimeout,
+		enableSyncCheckpoints:              enableSyncCheckpoints,
+		disableResourceAnnotationTemplates: disableResourceAnnotationTemplates,
 	}
 }
 
@@ -1052,3 +1326,22 @@ func isSelfReferencedObj(obj *unstructured.Unstructured, aiv argo.AppInstanceVal
 		obj.GetObjectKind().GroupVersionKind().Group == aiv.Group &&
 		obj.GetObjectKind().GroupVersionKind().Kind == aiv.Kind
 }
+
+// useServerSideDiff returns whether the diff of the application is calculated with server-side diff, which is enabled
+// or disabled by the compare options annotation or the sync options of the application, and defaults to the setting
+// of the controller
+func useServerSideDiff(app *v1alpha1.Application, enabled bool) bool {
+	syncOptions := v1alpha1.SyncOptions{}
+	if app.Spec.SyncPolicy != nil {
+		syncOptions = app.Spec.SyncPolicy.SyncOptions
+	}
+	// This allows turning SSD off for a given app if it is enabled at the
+	// controller level
+	if resourceutil.HasAnnotationOption(app, common.AnnotationCompareOptions, "ServerSideDiff=false") ||
+		syncOptions.HasOption("ServerSideDiff=false") {
+		return false
+	}
+	return enabled ||
+		resourceutil.HasAnnotationOption(app, common.AnnotationCompareOptions, "ServerSideDiff=true") ||
+		syncOptions.HasOption("ServerSideDiff=true")
+}
@@ -3,6 +3,8 @@ package controller
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 	"time"
@@ -22,6 +24,8 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/argoproj/argo-cd/v2/common"
 	"github.com/argoproj/argo-cd/v2/controller/testdata"
@@ -349,6 +353,84 @@ func TestCompareAppStateExtra(t *testing.T) {
 	assert.Empty(t, app.Status.Conditions)
 }
 
+// TestCompareAppStateResourceAnnotationTemplates checks that the annotations injected by resource annotation templates
+// are expected in the live state of the resources
+func TestCompareAppStateResourceAnnotationTemplates(t *testing.T) {
+	target := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-config","namespace":"` + test.FakeDestNamespace + `"},"data":{"key":"value"}}`
+	// the live state of the config map once synced with the injected annotation
+	applied := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"annotations":{"cost-center":"cc-default"},"name":"my-config","namespace":"` + test.FakeDestNamespace + `"},"data":{"key":"value"}}`
+	compare := func(templates map[string]string, disabled bool) *comparisonResult {
+		app := newFakeApp()
+		live := &unstructured.Unstructured{}
+		require.NoError(t, live.UnmarshalJSON([]byte(applied)))
+		live.SetAnnotations(map[string]string{"cost-center": "cc-default", corev1.LastAppliedConfigAnnotation: applied})
+		data := fakeData{
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: []string{target},
+				Namespace: test.FakeDestNamespace,
+				Server:    test.FakeClusterURL,
+				Revision:  "abc123",
+			},
+			managedLiveObjs:                    map[kube.ResourceKey]*unstructured.Unstructured{kube.GetResourceKey(live): live},
+			resourceAnnotationTemplates:        templates,
+			disableResourceAnnotationTemplates: disabled,
+		}
+		ctrl := newFakeController(&data, nil)
+		compRes, err := ctrl.appStateManager.CompareAppState(app, &defaultProj, []string{""}, []argoappv1.ApplicationSource{app.Spec.GetSource()}, false, false, nil, false, false)
+		require.NoError(t, err)
+		return compRes
+	}
+
+	costCenter := map[string]string{"cost-center": "gvk: v1/ConfigMap\ntemplate: 'cost-center: cc-{{ .project }}'\n"}
+	assert.Equal(t, argoappv1.SyncStatusCodeSynced, compare(costCenter, false).syncStatus.Status)
+	// the injected annotation would be removed by the next sync
+	assert.Equal(t, argoappv1.SyncStatusCodeOutOfSync, compare(costCenter, true).syncStatus.Status)
+	assert.Equal(t, argoappv1.SyncStatusCodeOutOfSync, compare(nil, false).syncStatus.Status)
+	// a new annotation is only injected by the next sync
+	owner := map[string]string{"owner": "gvk: '*'\ntemplate: 'owner: {{ .appName }}'\n"}
+	assert.Equal(t, argoappv1.SyncStatusCodeOutOfSync, compare(owner, false).syncStatus.Status)
+	assert.Equal(t, argoappv1.SyncStatusCodeSynced, compare(map[string]string{
+		"cost-center": costCenter["cost-center"],
+		"unknown":     "gvk: apps/v1/Deployment\ntemplate: 'a: b'\n",
+	}, false).syncStatus.Status)
+}
+
+// TestCompareAppStateExcludedByAnnotation checks that resources annotated to be unmanaged are neither compared nor synced
+func TestCompareAppStateExcludedByAnnotation(t *testing.T) {
+	pod := NewPod()
+	pod.SetNamespace(test.FakeDestNamespace)
+	podBytes, _ := json.Marshal(pod)
+	livePod := pod.DeepCopy()
+	livePod.SetAnnotations(map[string]string{common.AnnotationExcludeResource: "true"})
+	app := newFakeApp()
+	key := kube.ResourceKey{Group: "", Kind: "Pod", Namespace: test.FakeDestNamespace, Name: pod.GetName()}
+	data := fakeData{
+		manifestResponse: &apiclient.ManifestResponse{
+			Manifests: []string{string(podBytes)},
+			Namespace: test.FakeDestNamespace,
+			Server:    test.FakeClusterURL,
+			Revision:  "abc123",
+		},
+		managedLiveObjs: map[kube.ResourceKey]*unstructured.Unstructured{
+			key: livePod,
+		},
+	}
+	ctrl := newFakeController(&data, nil)
+	sources := make([]argoappv1.ApplicationSource, 0)
+	sources = append(sources, app.Spec.GetSource())
+	revisions := make([]string, 0)
+	revisions = append(revisions, "")
+	compRes, err := ctrl.appStateManager.CompareAppState(app, &defaultProj, revisions, sources, false, false, nil, false, false)
+	require.NoError(t, err)
+	assert.NotNil(t, compRes)
+	assert.Equal(t, argoappv1.SyncStatusCodeSynced, compRes.syncStatus.Status)
+	assert.Empty(t, compRes.resources)
+	assert.Empty(t, compRes.managedResources)
+	require.Len(t, app.Status.Conditions, 1)
+	assert.Equal(t, argoappv1.ApplicationConditionExcludedResourceWarning, app.Status.Conditions[0].Type)
+	assert.Contains(t, app.Status.Conditions[0].Message, common.AnnotationExcludeResource)
+}
+
 // TestCompareAppStateHook checks that hooks are detected during manifest generation, and not
 // considered as part of resources when assessing Synced status
 func TestCompareAppStateHook(t *testing.T) {
@@ -444,6 +526,80 @@ func TestCompareAppStateCompareOptionIgnoreExtraneous(t *testing.T) {
 	assert.Empty(t, app.Status.Conditions)
 }
 
+func TestSetAppResourceCount(t *testing.T) {
+	extraPod := func(name string) *unstructured.Unstructured {
+		pod := NewPod()
+		pod.SetName(name)
+		pod.SetNamespace(test.FakeDestNamespace)
+		return pod
+	}
+	hook := extraPod("hook")
+	hook.SetAnnotations(map[string]string{synccommon.AnnotationKeyHook: "PreSync"})
+
+	testCases := []struct {
+		name            string
+		manifests       []string
+		liveObjs        []*unstructured.Unstructured
+		expectedDesired int
+		expectedLive    int
+	}{
+		{name: "Missing", manifests: []string{PodManifest}, expectedDesired: 1, expectedLive: 0},
+		{name: "Extra", manifests: []string{}, liveObjs: []*unstructured.Unstructured{extraPod("extra")}, expectedDesired: 0, expectedLive: 1},
+		{name: "HooksAreNotCounted", manifests: []string{PodManifest}, liveObjs: []*unstructured.Unstructured{hook}, expectedDesired: 1, expectedLive: 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			app := newFakeApp()
+			liveObjs := map[kube.ResourceKey]*unstructured.Unstructured{}
+			for _, obj := range tc.liveObjs {
+				liveObjs[kube.GetResourceKey(obj)] = obj
+			}
+			ctrl := newFakeController(&fakeData{
+				apps: []runtime.Object{app, &defaultProj},
+				manifestResponse: &apiclient.ManifestResponse{
+					Manifests: tc.manifests,
+					Namespace: test.FakeDestNamespace,
+					Server:    test.FakeClusterURL,
+					Revision:  "abc123",
+				},
+				managedLiveObjs: liveObjs,
+			}, nil)
+			compRes, err := ctrl.appStateManager.CompareAppState(app, &defaultProj, []string{""}, []argoappv1.ApplicationSource{app.Spec.GetSource()}, false, false, nil, false, false)
+			require.NoError(t, err)
+
+			ctrl.setAppResourceCount(app, compRes)
+
+			req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+			require.NoError(t, err)
+			rr := httptest.NewRecorder()
+			ctrl.metricsServer.Handler.ServeHTTP(rr, req)
+			labels := `dest_server="` + app.Spec.Destination.Server + `",name="my-app",namespace="` + test.FakeArgoCDNamespace + `",project="default"`
+			assert.Contains(t, rr.Body.String(), fmt.Sprintf(`argocd_app_resource_count{%s,type="desired"} %d`, labels, tc.expectedDesired))
+			assert.Contains(t, rr.Body.String(), fmt.Sprintf(`argocd_app_resource_count{%s,type="live"} %d`, labels, tc.expectedLive))
+		})
+	}
+
+	t.Run("UnknownSyncStatusKeepsCounts", func(t *testing.T) {
+		app := newFakeApp()
+		ctrl := newFakeController(&fakeData{apps: []runtime.Object{app, &defaultProj}}, nil)
+		ctrl.setAppResourceCount(app, &comparisonResult{
+			syncStatus:       &argoappv1.SyncStatus{Status: argoappv1.SyncStatusCodeSynced},
+			managedResources: []managedResource{{Target: NewPod(), Live: NewPod()}},
+		})
+		// the live state could not be loaded, the resources are not all missing
+		ctrl.setAppResourceCount(app, &comparisonResult{
+			syncStatus:       &argoappv1.SyncStatus{Status: argoappv1.SyncStatusCodeUnknown},
+			managedResources: []managedResource{{Target: NewPod()}},
+		})
+
+		req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		ctrl.metricsServer.Handler.ServeHTTP(rr, req)
+		assert.Regexp(t, `argocd_app_resource_count\{[^}]*type="live"\} 1`, rr.Body.String())
+	})
+}
+
 // TestCompareAppStateExtraHook tests when there is an extra _hook_ object in live but not defined in git
 func TestCompareAppStateExtraHook(t *testing.T) {
 	pod := NewPod()
@@ -595,6 +751,33 @@ func TestCompareAppStateDuplicatedNamespacedResources(t *testing.T) {
 	assert.Len(t, compRes.resources, 4)
 }
 
+func TestCompareAppStateSyncWarnings(t *testing.T) {
+	obj := NewPod()
+	obj.SetNamespace(test.FakeDestNamespace)
+	app := newFakeApp()
+	data := fakeData{
+		manifestResponse: &apiclient.ManifestResponse{
+			Manifests: []string{toJSON(t, obj)},
+			Namespace: test.FakeDestNamespace,
+			Server:    test.FakeClusterURL,
+			Revision:  "abc123",
+			Warnings:  []string{"v1/Pod/my-pod: spec.containers[0].ports[0].containerPort: Invalid type. Expected: integer, given: string"},
+		},
+		managedLiveObjs: map[kube.ResourceKey]*unstructured.Unstructured{
+			kube.GetResourceKey(obj): obj,
+		},
+	}
+	ctrl := newFakeController(&data, nil)
+	sources := []argoappv1.ApplicationSource{app.Spec.GetSource()}
+	compRes, err := ctrl.appStateManager.CompareAppState(app, &defaultProj, []string{""}, sources, false, false, nil, false, false)
+	require.NoError(t, err)
+
+	assert.NotNil(t, compRes)
+	require.Len(t, app.Status.Conditions, 1)
+	assert.Equal(t, argoappv1.ApplicationConditionSyncWarning, app.Status.Conditions[0].Type)
+	assert.Equal(t, "v1/Pod/my-pod: spec.containers[0].ports[0].containerPort: Invalid type. Expected: integer, given: string", app.Status.Conditions[0].Message)
+}
+
 func TestCompareAppStateManagedNamespaceMetadataWithLiveNsDoesNotGetPruned(t *testing.T) {
 	app := newFakeApp()
 	app.Spec.SyncPolicy = &argoappv1.SyncPolicy{
@@ -683,6 +866,66 @@ func TestCompareAppStateWithManifestGeneratePath(t *testing.T) {
 	ctrl.repoClientset.(*mockrepoclient.Clientset).RepoServerServiceClient.(*mockrepoclient.RepoServerServiceClient).AssertNumberOfCalls(t, "UpdateRevisionForPaths", 1)
 }
 
+// TestGetRepoObjsHelmValuesFrom tests that the Helm values read from the destination cluster are passed to the repo-server
+func TestGetRepoObjsHelmValuesFrom(t *testing.T) {
+	app := newFakeApp()
+	app.Spec.Source.Helm = &argoappv1.ApplicationSourceHelm{ValuesFrom: &argoappv1.HelmValuesFrom{
+		ConfigMapKeyRef: &argoappv1.HelmValuesKeyRef{Name: "helm-values", Key: "values.yaml"},
+	}}
+	data := fakeData{
+		manifestResponse: &apiclient.ManifestResponse{
+			Manifests: []string{},
+			Namespace: test.FakeDestNamespace,
+			Server:    test.FakeClusterURL,
+			Revision:  "abc123",
+		},
+	}
+
+	t.Run("ConfigMap exists", func(t *testing.T) {
+		ctrl := newFakeController(&data, nil)
+		ctrl.appStateManager.(*appStateManager).kubeClientForDestination = func(_ *argoappv1.Application) (kubernetes.Interface, error) {
+			return fake.NewSimpleClientset(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "helm-values", Namespace: test.FakeDestNamespace},
+				Data:       map[string]string{"values.yaml": "replicaCount: 2\n"},
+			}), nil
+		}
+		_, _, err := ctrl.appStateManager.GetRepoObjs(app, app.Spec.GetSources(), "", []string{"abc123"}, false, false, false, &defaultProj, false)
+		require.NoError(t, err)
+		repoClient := ctrl.repoClientset.(*mockrepoclient.Clientset).RepoServerServiceClient.(*mockrepoclient.RepoServerServiceClient)
+		req := repoClient.Calls[0].Arguments.Get(1).(*apiclient.ManifestRequest)
+		assert.Equal(t, []string{"replicaCount: 2\n"}, req.HelmValuesFrom)
+	})
+
+	t.Run("ConfigMap missing", func(t *testing.T) {
+		ctrl := newFakeController(&data, nil)
+		ctrl.appStateManager.(*appStateManager).kubeClientForDestination = func(_ *argoappv1.Application) (kubernetes.Interface, error) {
+			return fake.NewSimpleClientset(), nil
+		}
+		_, _, err := ctrl.appStateManager.GetRepoObjs(app, app.Spec.GetSources(), "", []string{"abc123"}, false, false, false, &defaultProj, false)
+		require.ErrorContains(t, err, "failed to get Helm values for source 1 of 1")
+	})
+}
+
+func TestGetRepoObjsHelmValueFilesSuffix(t *testing.T) {
+	app := newFakeApp()
+	app.Spec.Source.Helm = &argoappv1.ApplicationSourceHelm{ValueFiles: []string{"values.yaml"}, ValueFilesSuffix: "region"}
+	data := fakeData{
+		manifestResponse: &apiclient.ManifestResponse{
+			Manifests: []string{},
+			Namespace: test.FakeDestNamespace,
+			Server:    test.FakeClusterURL,
+			Revision:  "abc123",
+		},
+		clusterLabels: map[string]string{"region": "us-east-1"},
+	}
+	ctrl := newFakeController(&data, nil)
+	_, _, err := ctrl.appStateManager.GetRepoObjs(app, app.Spec.GetSources(), "", []string{"abc123"}, false, false, false, &defaultProj, false)
+	require.NoError(t, err)
+	repoClient := ctrl.repoClientset.(*mockrepoclient.Clientset).RepoServerServiceClient.(*mockrepoclient.RepoServerServiceClient)
+	req := repoClient.Calls[0].Arguments.Get(1).(*apiclient.ManifestRequest)
+	assert.Equal(t, "us-east-1", req.HelmValueFilesSuffix)
+}
+
 func TestSetHealth(t *testing.T) {
 	app := newFakeApp()
 	deployment := kube.MustToUnstructured(&v1.Deployment{
@@ -872,7 +1115,7 @@ func Test_appStateManager_persistRevisionHistory(t *testing.T) {
 		app.Spec.RevisionHistoryLimit = &i
 	}
 	addHistory := func() {
-		err := manager.persistRevisionHistory(app, "my-revision", argoappv1.ApplicationSource{}, []string{}, []argoappv1.ApplicationSource{}, false, metav1.Time{}, v1alpha1.OperationInitiator{})
+		err := manager.persistRevisionHistory(app, "my-revision", argoappv1.ApplicationSource{}, []string{}, []argoappv1.ApplicationSource{}, false, metav1.Time{}, v1alpha1.OperationInitiator{}, nil)
 		require.NoError(t, err)
 	}
 	addHistory()
@@ -908,7 +1151,7 @@ func Test_appStateManager_persistRevisionHistory(t *testing.T) {
 	assert.Len(t, app.Status.History, 9)
 
 	metav1NowTime := metav1.NewTime(time.Now())
-	err := manager.persistRevisionHistory(app, "my-revision", argoappv1.ApplicationSource{}, []string{}, []argoappv1.ApplicationSource{}, false, metav1NowTime, v1alpha1.OperationInitiator{})
+	err := manager.persistRevisionHistory(app, "my-revision", argoappv1.ApplicationSource{}, []string{}, []argoappv1.ApplicationSource{}, false, metav1NowTime, v1alpha1.OperationInitiator{}, nil)
 	require.NoError(t, err)
 	assert.Equal(t, app.Status.History.LastRevisionHistory().DeployStartedAt, &metav1NowTime)
 }
@@ -1710,3 +1953,24 @@ func TestUseDiffCache(t *testing.T) {
 		})
 	}
 }
+
+func TestUseServerSideDiff(t *testing.T) {
+	newApp := func(annotation string, syncOptions ...string) *v1alpha1.Application {
+		app := newFakeApp()
+		if annotation != "" {
+			app.SetAnnotations(map[string]string{common.AnnotationCompareOptions: annotation})
+		}
+		if len(syncOptions) > 0 {
+			app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{SyncOptions: syncOptions}
+		}
+		return app
+	}
+
+	assert.False(t, useServerSideDiff(newApp(""), false))
+	assert.True(t, useServerSideDiff(newApp(""), true))
+	assert.True(t, useServerSideDiff(newApp("ServerSideDiff=true"), false))
+	assert.True(t, useServerSideDiff(newApp("", "ServerSideDiff=true"), false))
+	assert.False(t, useServerSideDiff(newApp("ServerSideDiff=false"), true))
+	assert.False(t, useServerSideDiff(newApp("", "ServerSideDiff=false"), true))
+	assert.False(t, useServerSideDiff(newApp("ServerSideDiff=false", "ServerSideDiff=true"), false))
+}
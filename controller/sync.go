@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"time"
 
@@ -13,23 +14,35 @@ import (
 
 	cdcommon "github.com/argoproj/argo-cd/v2/common"
 
+	clustercache "github.com/argoproj/gitops-engine/pkg/cache"
 	"github.com/argoproj/gitops-engine/pkg/sync"
 	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	resourceutil "github.com/argoproj/gitops-engine/pkg/sync/resource"
 	"github.com/argoproj/gitops-engine/pkg/utils/kube"
 	jsonpatch "github.com/evanphx/json-patch"
 	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/managedfields"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	"k8s.io/kubectl/pkg/util/openapi"
 
 	"github.com/argoproj/argo-cd/v2/controller/metrics"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	listersv1alpha1 "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
 	"github.com/argoproj/argo-cd/v2/util/argo"
 	"github.com/argoproj/argo-cd/v2/util/argo/diff"
+	"github.com/argoproj/argo-cd/v2/util/attestation"
+	appstatecache "github.com/argoproj/argo-cd/v2/util/cache/appstate"
+	healthutil "github.com/argoproj/argo-cd/v2/util/health"
+	kubeutil "github.com/argoproj/argo-cd/v2/util/kube"
 	logutils "github.com/argoproj/argo-cd/v2/util/log"
 	"github.com/argoproj/argo-cd/v2/util/lua"
 	"github.com/argoproj/argo-cd/v2/util/rand"
@@ -95,16 +108,39 @@ func (m *appStateManager) SyncAppState(app *v1alpha1.Application, state *v1alpha
 
 	if state.Operation.Sync == nil {
 		state.Phase = common.OperationFailed
+		state.ErrorCode = v1alpha1.SyncErrorCodeInvalidOperation
 		state.Message = "Invalid operation request: no operation specified"
 		return
 	}
 	syncOp = *state.Operation.Sync
+	// the error code of the previous attempt of the operation is replaced by the one of this attempt
+	state.ErrorCode = ""
+
+	syncTimeout, err := getSyncTimeout(app, m.globalSyncTimeout)
+	if err != nil {
+		state.Phase = common.OperationError
+		state.ErrorCode = v1alpha1.SyncErrorCodeInvalidOperation
+		state.Message = err.Error()
+		return
+	}
+
+	var preFlightRequirements *preFlightRequirements
+	if check := app.Spec.SyncPolicy.GetPreFlightCheck(); check != nil {
+		preFlightRequirements, err = parsePreFlightCheck(check)
+		if err != nil {
+			state.Phase = common.OperationError
+			state.ErrorCode = v1alpha1.SyncErrorCodeInvalidOperation
+			state.Message = err.Error()
+			return
+		}
+	}
 
 	// validates if it should fail the sync if it finds shared resources
 	hasSharedResource, sharedResourceMessage := hasSharedResourceCondition(app)
 	if syncOp.SyncOptions.HasOption("FailOnSharedResource=true") &&
 		hasSharedResource {
 		state.Phase = common.OperationFailed
+		state.ErrorCode = v1alpha1.SyncErrorCodeSharedResource
 		state.Message = fmt.Sprintf("Shared resource found: %s", sharedResourceMessage)
 		return
 	}
@@ -165,9 +201,10 @@ func (m *appStateManager) SyncAppState(app *v1alpha1.Application, state *v1alpha
 	proj, err := argo.GetAppProject(app, listersv1alpha1.NewAppProjectLister(m.projInformer.GetIndexer()), m.namespace, m.settingsMgr, m.db, context.TODO())
 	if err != nil {
 		state.Phase = common.OperationError
+		state.ErrorCode = v1alpha1.SyncErrorCodePermissionDenied
 		state.Message = fmt.Sprintf("Failed to load application project: %v", err)
 		return
-	} else if syncWindowPreventsSync(app, proj) {
+	} else if syncWindowPreventsSync(app, proj, m.getSyncWindowOverrides(app)) {
 		// If the operation is currently running, simply let the user know the sync is blocked by a current sync window
 		if state.Phase == common.OperationRunning {
 			state.Message = "Sync operation blocked by sync window"
@@ -184,6 +221,7 @@ func (m *appStateManager) SyncAppState(app *v1alpha1.Application, state *v1alpha
 	compareResult, err := m.CompareAppState(app, proj, revisions, sources, false, true, syncOp.Manifests, isMultiSourceRevision, rollback)
 	if err != nil && !goerrors.Is(err, CompareStateRepoError) {
 		state.Phase = common.OperationError
+		state.ErrorCode = v1alpha1.SyncErrorCodeComparisonFailed
 		state.Message = err.Error()
 		return
 	}
@@ -199,39 +237,126 @@ func (m *appStateManager) SyncAppState(app *v1alpha1.Application, state *v1alpha
 		v1alpha1.ApplicationConditionInvalidSpecError: true,
 	}); len(errConditions) > 0 {
 		state.Phase = common.OperationError
+		state.ErrorCode = v1alpha1.SyncErrorCodeInvalidOperation
 		state.Message = argo.FormatAppConditions(errConditions)
 		return
 	}
 
+	// Do not create resources beyond the resource quota of the project
+	if quotaConditions := app.Status.GetConditions(map[v1alpha1.ApplicationConditionType]bool{
+		v1alpha1.ApplicationConditionProjectQuotaExceeded: true,
+	}); len(quotaConditions) > 0 {
+		state.Phase = common.OperationFailed
+		state.ErrorCode = v1alpha1.SyncErrorCodeQuotaExceeded
+		state.Message = argo.FormatAppConditions(quotaConditions)
+		return
+	}
+
+	var reconciliationResult sync.ReconciliationResult
+	if len(compareResult.destinations) == 0 {
+		reconciliationResult = m.syncDestination(app, state, syncOp, proj, compareResult, sources, syncTimeout, preFlightRequirements, "", true)
+	} else {
+		reconciliationResult = m.syncDestinations(app, state, syncOp, proj, compareResult, sources, syncTimeout, preFlightRequirements)
+	}
+
+	if !syncOp.DryRun && len(syncOp.Resources) == 0 && state.Phase.Successful() {
+		err := m.persistRevisionHistory(app, compareResult.syncStatus.Revision, source, compareResult.syncStatus.Revisions, compareResult.syncStatus.ComparedTo.Sources, isMultiSourceRevision, state.StartedAt, state.Operation.InitiatedBy, proj.Spec.HistoryRetentionPolicy)
+		if err != nil {
+			state.Phase = common.OperationError
+			state.ErrorCode = v1alpha1.SyncErrorCodeInternalError
+			state.Message = fmt.Sprintf("failed to record sync to history: %v", err)
+		}
+	}
+
+	if !syncOp.DryRun && len(syncOp.Resources) == 0 && state.Phase.Successful() && m.artifactStorer != nil {
+		if storage := proj.Spec.ArtifactStorage.GetOCI(); storage != nil {
+			manifests := append(append([]*unstructured.Unstructured{}, reconciliationResult.Target...), reconciliationResult.Hooks...)
+			m.artifactStorer.Queue(app, storage, syncedRevision(compareResult.syncStatus, isMultiSourceRevision), state.StartedAt.Time, manifests)
+		}
+	}
+}
+
+// syncDestination syncs the application to its destination. The key identifies the destination in the attempts and the
+// checkpoint of the sync of an application with additional destinations, whose progress is not reported, and the live
+// state of the destination is captured before the sync if snapshot is true.
+func (m *appStateManager) syncDestination(app *v1alpha1.Application, state *v1alpha1.OperationState, syncOp v1alpha1.SyncOperation, proj *v1alpha1.AppProject, compareResult *comparisonResult, sources []v1alpha1.ApplicationSource, syncTimeout time.Duration, preFlightRequirements *preFlightRequirements, key string, snapshot bool) (reconciliationResult sync.ReconciliationResult) {
 	clst, err := m.db.GetCluster(context.Background(), app.Spec.Destination.Server)
 	if err != nil {
 		state.Phase = common.OperationError
+		state.ErrorCode = v1alpha1.SyncErrorCodeClusterError
 		state.Message = err.Error()
-		return
+		return reconciliationResult
 	}
 
 	rawConfig := clst.RawRestConfig()
 	restConfig := metrics.AddMetricsTransportWrapper(m.metricsServer, app, clst.RESTConfig())
 
+	applyRateLimiters, err := m.applyRateLimiters.get(clst.Server, app.Spec.SyncPolicy.GetApplyRateLimit())
+	if err != nil {
+		state.Phase = common.OperationError
+		state.ErrorCode = v1alpha1.SyncErrorCodeInvalidOperation
+		state.Message = err.Error()
+		return reconciliationResult
+	}
+	if len(applyRateLimiters) > 0 {
+		onThrottled := func() {
+			m.metricsServer.IncResourceApplyThrottled(app, clst.Server)
+		}
+		// resources are applied with the raw config, while the dry runs of server-side apply and the deletions use the
+		// rest config
+		rawConfig = withApplyRateLimit(rawConfig, applyRateLimiters, onThrottled)
+		restConfig = withApplyRateLimit(restConfig, applyRateLimiters, onThrottled)
+	}
+
+	// resources are synced as the service account of the project, which the application controller must be permitted to
+	// impersonate in the destination cluster
+	if saNamespace, saName := proj.GetDestinationServiceAccount(app.Spec.Destination.Namespace); saName != "" {
+		kubeClient, err := m.getDestinationKubeClient(app)
+		if err != nil {
+			state.Phase = common.OperationError
+			state.ErrorCode = v1alpha1.SyncErrorCodeClusterError
+			state.Message = fmt.Sprintf("Failed to create the client of the destination cluster: %v", err)
+			return reconciliationResult
+		}
+		if err := kubeutil.CanImpersonateServiceAccount(context.TODO(), kubeClient, saNamespace, saName); err != nil {
+			state.Phase = common.OperationFailed
+			state.ErrorCode = v1alpha1.SyncErrorCodePermissionDenied
+			state.Message = err.Error()
+			return reconciliationResult
+		}
+		username := kubeutil.ServiceAccountUsername(saNamespace, saName)
+		rawConfig = kubeutil.WithImpersonation(rawConfig, username)
+		restConfig = kubeutil.WithImpersonation(restConfig, username)
+	}
+
 	resourceOverrides, err := m.settingsMgr.GetResourceOverrides()
 	if err != nil {
 		state.Phase = common.OperationError
+		state.ErrorCode = v1alpha1.SyncErrorCodeInternalError
 		state.Message = fmt.Sprintf("Failed to load resource overrides: %v", err)
-		return
+		return reconciliationResult
+	}
+	healthOverrideScripts, err := m.settingsMgr.GetHealthOverrideScripts()
+	if err != nil {
+		state.Phase = common.OperationError
+		state.ErrorCode = v1alpha1.SyncErrorCodeInternalError
+		state.Message = fmt.Sprintf("Failed to load health overrides: %v", err)
+		return reconciliationResult
 	}
 
 	atomic.AddUint64(&syncIdPrefix, 1)
 	randSuffix, err := rand.String(5)
 	if err != nil {
 		state.Phase = common.OperationError
+		state.ErrorCode = v1alpha1.SyncErrorCodeInternalError
 		state.Message = fmt.Sprintf("Failed generate random sync ID: %v", err)
-		return
+		return reconciliationResult
 	}
 	syncId := fmt.Sprintf("%05d-%s", syncIdPrefix, randSuffix)
 
 	logEntry := log.WithFields(log.Fields{"application": app.QualifiedName(), "syncId": syncId})
 	initialResourcesRes := make([]common.ResourceSyncResult, 0)
-	for i, res := range syncRes.Resources {
+	for i, res := range state.SyncResult.Resources {
 		key := kube.ResourceKey{Group: res.Group, Kind: res.Kind, Namespace: res.Namespace, Name: res.Name}
 		initialResourcesRes = append(initialResourcesRes, common.ResourceSyncResult{
 			ResourceKey: key,
@@ -258,11 +383,12 @@ func (m *appStateManager) SyncAppState(app *v1alpha1.Application, state *v1alpha
 	openAPISchema, err := m.getOpenAPISchema(clst.Server)
 	if err != nil {
 		state.Phase = common.OperationError
+		state.ErrorCode = v1alpha1.SyncErrorCodeClusterError
 		state.Message = fmt.Sprintf("failed to load openAPISchema: %v", err)
-		return
+		return reconciliationResult
 	}
 
-	reconciliationResult := compareResult.reconciliationResult
+	reconciliationResult = compareResult.reconciliationResult
 
 	// if RespectIgnoreDifferences is enabled, it should normalize the target
 	// resources which in this case applies the live values in the configured
@@ -271,22 +397,94 @@ func (m *appStateManager) SyncAppState(app *v1alpha1.Application, state *v1alpha
 		patchedTargets, err := normalizeTargetResources(compareResult)
 		if err != nil {
 			state.Phase = common.OperationError
+			state.ErrorCode = v1alpha1.SyncErrorCodeComparisonFailed
 			state.Message = fmt.Sprintf("Failed to normalize target resources: %s", err)
-			return
+			return reconciliationResult
 		}
 		reconciliationResult.Target = patchedTargets
 	}
 
+	// conflicts are checked once, before the first resources are applied, since the sync forces them
+	if !syncOp.DryRun && state.Phase != common.OperationTerminating && len(state.SyncResult.Resources) == 0 {
+		if err := m.prepareServerSideApply(app, syncOp, clst.Server, restConfig, reconciliationResult, logEntry); err != nil {
+			state.Phase = common.OperationFailed
+			state.ErrorCode = v1alpha1.SyncErrorCodePreFlightFailed
+			state.Message = err.Error()
+			return reconciliationResult
+		}
+	}
+
+	// the merged resources are computed each time the sync is resumed, while the conflicts are reported once
+	if app.Spec.SyncPolicy.GetConflictResolution() == v1alpha1.ConflictResolutionMerge && state.Phase != common.OperationTerminating {
+		if err := m.mergeConflicts(app, syncOp, reconciliationResult, !syncOp.DryRun && len(state.SyncResult.Resources) == 0, logEntry); err != nil {
+			state.Phase = common.OperationError
+			state.ErrorCode = v1alpha1.SyncErrorCodeInternalError
+			state.Message = err.Error()
+			return reconciliationResult
+		}
+	}
+
+	// the capacity of the cluster is checked once, before the first resources are applied
+	if preFlightRequirements != nil && !syncOp.DryRun && state.Phase != common.OperationTerminating && len(state.SyncResult.Resources) == 0 {
+		kubeClient, err := kubernetes.NewForConfig(restConfig)
+		if err != nil {
+			state.Phase = common.OperationError
+			state.ErrorCode = v1alpha1.SyncErrorCodeClusterError
+			state.Message = fmt.Sprintf("Failed to create the client of the destination cluster: %v", err)
+			return reconciliationResult
+		}
+		capacity, err := getClusterCapacity(context.TODO(), kubeClient)
+		if err != nil {
+			state.Phase = common.OperationError
+			state.ErrorCode = v1alpha1.SyncErrorCodeClusterError
+			state.Message = fmt.Sprintf("Failed to get the capacity of the destination cluster: %v", err)
+			return reconciliationResult
+		}
+		if err := preFlightRequirements.checkCapacity(capacity); err != nil {
+			state.Phase = common.OperationFailed
+			state.ErrorCode = v1alpha1.SyncErrorCodePreFlightFailed
+			state.Message = err.Error()
+			return reconciliationResult
+		}
+	}
+
+	// attestations are verified once, before the first resources are applied
+	if state.Phase != common.OperationTerminating && len(state.SyncResult.Resources) == 0 && requiresSBOMAttestation(sources) {
+		if err := m.verifySBOMAttestations(proj, reconciliationResult.Target, logEntry); err != nil {
+			state.Phase = common.OperationFailed
+			state.ErrorCode = v1alpha1.SyncErrorCodePreFlightFailed
+			state.Message = err.Error()
+			return reconciliationResult
+		}
+	}
+
+	// the live state of the resources is captured once, before the first resources are applied, to allow rolling back to it
+	if snapshot && m.syncSnapshotRetention > 0 && !syncOp.DryRun && state.Phase != common.OperationTerminating && len(state.SyncResult.Resources) == 0 && state.SyncResult.SyncID == "" {
+		if err := m.storePreSyncSnapshot(app, syncId, reconciliationResult.Live, time.Now()); err != nil {
+			logEntry.Warnf("Failed to store the snapshot of the live state before the sync: %v", err)
+		} else {
+			state.SyncResult.SyncID = syncId
+		}
+	}
+
 	appLabelKey, err := m.settingsMgr.GetAppInstanceLabelKey()
 	if err != nil {
 		log.Errorf("Could not get appInstanceLabelKey: %v", err)
-		return
+		return reconciliationResult
 	}
 	trackingMethod := argo.GetTrackingMethod(m.settingsMgr)
 
+	resourcesFilter := func(key kube.ResourceKey, target *unstructured.Unstructured, live *unstructured.Unstructured) bool {
+		return (len(syncOp.Resources) == 0 ||
+			isPostDeleteHook(target) || isPreDeleteHook(target) ||
+			argo.ContainsSyncResource(key.Name, key.Namespace, schema.GroupVersionKind{Kind: key.Kind, Group: key.Group}, syncOp.Resources)) &&
+			m.isSelfReferencedObj(live, target, app.GetName(), appLabelKey, trackingMethod) &&
+			!isBootstrapAppSelfSync(app, key)
+	}
+
 	opts := []sync.SyncOpt{
 		sync.WithLogr(logutils.NewLogrusLogger(logEntry)),
-		sync.WithHealthOverride(lua.ResourceHealthOverrides(resourceOverrides)),
+		sync.WithHealthOverride(healthutil.NewExtendedHealthOverride(lua.NewResourceHealthOverride(healthOverrideScripts, resourceOverrides))),
 		sync.WithPermissionValidator(func(un *unstructured.Unstructured, res *v1.APIResource) error {
 			if !proj.IsGroupKindPermitted(un.GroupVersionKind().GroupKind(), res.Namespaced) {
 				return fmt.Errorf("resource %s:%s is not permitted in project %s", un.GroupVersionKind().Group, un.GroupVersionKind().Kind, proj.Name)
@@ -307,12 +505,7 @@ func (m *appStateManager) SyncAppState(app *v1alpha1.Application, state *v1alpha
 		}),
 		sync.WithOperationSettings(syncOp.DryRun, syncOp.Prune, syncOp.SyncStrategy.Force(), syncOp.IsApplyStrategy() || len(syncOp.Resources) > 0),
 		sync.WithInitialState(state.Phase, state.Message, initialResourcesRes, state.StartedAt),
-		sync.WithResourcesFilter(func(key kube.ResourceKey, target *unstructured.Unstructured, live *unstructured.Unstructured) bool {
-			return (len(syncOp.Resources) == 0 ||
-				isPostDeleteHook(target) ||
-				argo.ContainsSyncResource(key.Name, key.Namespace, schema.GroupVersionKind{Kind: key.Kind, Group: key.Group}, syncOp.Resources)) &&
-				m.isSelfReferencedObj(live, target, app.GetName(), appLabelKey, trackingMethod)
-		}),
+		sync.WithResourcesFilter(resourcesFilter),
 		sync.WithManifestValidation(!syncOp.SyncOptions.HasOption(common.SyncOptionsDisableValidation)),
 		sync.WithSyncWaveHook(delayBetweenSyncWaves),
 		sync.WithPruneLast(syncOp.SyncOptions.HasOption(common.SyncOptionPruneLast)),
@@ -327,33 +520,87 @@ func (m *appStateManager) SyncAppState(app *v1alpha1.Application, state *v1alpha
 		opts = append(opts, sync.WithNamespaceModifier(syncNamespace(app.Spec.SyncPolicy)))
 	}
 
+	// The progress of the sync is reported each time a resource is applied, at most once per report interval, and once
+	// the sync returns
+	resourcesTotal := countSyncedResources(reconciliationResult, resourcesFilter)
+	kubectl := m.kubectlForSync(app, syncOp, clst.Server, logEntry)
+	if templates := m.getResourceAnnotationTemplates(); len(templates) > 0 {
+		kubectl = &annotationTemplateKubectl{Kubectl: kubectl, templates: templates, data: resourceAnnotationContext(app, clst.Name)}
+	}
+	// the resources applied by the sync are recorded, so that they are not applied again if the controller restarts
+	// before the sync completes
+	var checkpoint *syncCheckpoint
+	if m.enableSyncCheckpoints && !syncOp.DryRun {
+		checkpoint = m.loadSyncCheckpoint(app.InstanceName(m.namespace)+key, syncCheckpointID(state), logEntry)
+		kubectl = &checkpointKubectl{Kubectl: kubectl, checkpoint: checkpoint}
+	}
+	if !syncOp.DryRun && key == "" {
+		m.syncProgress.begin(app.QualifiedName(), state, countAppliedResources(initialResourcesRes), resourcesTotal, time.Now())
+		kubectl = &progressKubectl{Kubectl: kubectl, onApplied: func() {
+			if progress, report := m.syncProgress.observeApply(app.QualifiedName(), time.Now()); report {
+				m.reportSyncProgress(app, progress, logEntry)
+			}
+		}}
+	}
+
 	syncCtx, cleanup, err := sync.NewSyncContext(
 		compareResult.syncStatus.Revision,
 		reconciliationResult,
 		restConfig,
 		rawConfig,
-		m.kubectl,
+		kubectl,
 		app.Spec.Destination.Namespace,
 		openAPISchema,
 		opts...,
 	)
 	if err != nil {
 		state.Phase = common.OperationError
+		state.ErrorCode = v1alpha1.SyncErrorCodeInternalError
 		state.Message = fmt.Sprintf("failed to initialize sync context: %v", err)
-		return
+		return reconciliationResult
 	}
 
 	defer cleanup()
 
 	start := time.Now()
 
-	if state.Phase == common.OperationTerminating {
+	// The sync of an application is resumed each time the application is reconciled, so the timeout applies from the
+	// start of the current attempt of the operation rather than to a single call
+	timedOut := false
+	if syncTimeout > 0 && state.Phase != common.OperationTerminating {
+		timedOut = !start.Before(m.syncAttempts.startedAt(app.QualifiedName()+key, state, start).Add(syncTimeout))
+	}
+
+	terminating := state.Phase == common.OperationTerminating
+	if terminating || timedOut {
 		syncCtx.Terminate()
 	} else {
 		syncCtx.Sync()
 	}
 	var resState []common.ResourceSyncResult
 	state.Phase, state.Message, resState = syncCtx.GetState()
+	state.ErrorCode = syncErrorCode(state.Phase, terminating, resState)
+	if timedOut {
+		logEntry.Infof("Sync did not complete within %v, terminated it", syncTimeout)
+		state.Phase = common.OperationFailed
+		state.ErrorCode = v1alpha1.SyncErrorCodeTimeout
+		state.Message = syncTimedOutMessage(syncTimeout)
+	}
+	if !syncOp.DryRun && key == "" {
+		applied := countAppliedResources(resState)
+		if state.Phase.Successful() {
+			// the resources in sync are not applied with the ApplyOutOfSyncOnly sync option
+			resourcesTotal = applied
+		}
+		state.Progress = m.syncProgress.estimate(app.QualifiedName(), state, applied, resourcesTotal, time.Now())
+	}
+	if state.Phase.Completed() {
+		m.syncAttempts.forget(app.QualifiedName() + key)
+		m.syncProgress.forget(app.QualifiedName())
+		if checkpoint != nil {
+			checkpoint.clear()
+		}
+	}
 	state.SyncResult.Resources = nil
 
 	if app.Spec.SyncPolicy != nil {
@@ -389,18 +636,251 @@ func (m *appStateManager) SyncAppState(app *v1alpha1.Application, state *v1alpha
 			HookPhase: res.HookPhase,
 			Status:    res.Status,
 			Message:   res.Message,
+			ErrorCode: resourceSyncErrorCode(res),
 		})
 	}
 
 	logEntry.WithField("duration", time.Since(start)).Info("sync/terminate complete")
+	return reconciliationResult
+}
 
-	if !syncOp.DryRun && len(syncOp.Resources) == 0 && state.Phase.Successful() {
-		err := m.persistRevisionHistory(app, compareResult.syncStatus.Revision, source, compareResult.syncStatus.Revisions, compareResult.syncStatus.ComparedTo.Sources, isMultiSourceRevision, state.StartedAt, state.Operation.InitiatedBy)
+// clientSideApplyManagers are the field managers owning the fields of resources applied with client-side apply, by
+// Argo CD and by kubectl
+var clientSideApplyManagers = []string{cdcommon.ArgoCDSSAManager, "kubectl-client-side-apply"}
+
+// kubectlForSync returns the Kubectl of the sync of the application, which applies the server-side applied resources
+// in bulk if the BulkApply=true sync option is set, and fails the apply of resources taking longer than the apply
+// timeout of their kind
+func (m *appStateManager) kubectlForSync(app *v1alpha1.Application, syncOp v1alpha1.SyncOperation, server string, logEntry *log.Entry) kube.Kubectl {
+	kubectl := m.kubectl
+	if syncOp.SyncOptions.HasOption("BulkApply=true") {
+		if clusterCache, err := m.liveStateCache.GetClusterCache(server); err != nil {
+			logEntry.Warnf("Failed to get cluster cache, applying resources one by one: %v", err)
+		} else {
+			kubectl = &bulkApplyKubectl{Kubectl: kubectl, resourceFor: apiResourceFor(clusterCache)}
+		}
+	}
+	if m.defaultResourceApplyTimeout <= 0 && (app.Spec.SyncPolicy == nil || len(app.Spec.SyncPolicy.ApplyTimeouts) == 0) {
+		return kubectl
+	}
+	return &applyTimeoutKubectl{
+		Kubectl: kubectl,
+		timeout: func(gk schema.GroupKind) time.Duration {
+			if timeout := app.Spec.SyncPolicy.GetApplyTimeout(gk); timeout > 0 {
+				return timeout
+			}
+			return m.defaultResourceApplyTimeout
+		},
+		onTimeout: func(obj *unstructured.Unstructured, timeout time.Duration) {
+			message := applyTimeoutMessage(obj, timeout)
+			logEntry.Warn(message)
+			if m.auditLogger != nil {
+				m.auditLogger.LogAppEvent(app, argo.EventInfo{Reason: argo.EventReasonApplyTimeout, Type: corev1.EventTypeWarning}, message, "", nil)
+			}
+		},
+	}
+}
+
+// apiResourceFor returns a function returning the API resource of a kind in the cluster of the given cache
+func apiResourceFor(clusterCache clustercache.ClusterCache) func(gvk schema.GroupVersionKind) (*v1.APIResource, error) {
+	return func(gvk schema.GroupVersionKind) (*v1.APIResource, error) {
+		for _, res := range clusterCache.GetAPIResources() {
+			if res.GroupKind == gvk.GroupKind() && res.GroupVersionResource.Version == gvk.Version {
+				return &res.Meta, nil
+			}
+		}
+		return nil, fmt.Errorf("the server could not find the requested resource")
+	}
+}
+
+// getResourceAnnotationTemplates returns the templates of the argocd-resource-annotations ConfigMap, nil if they are
+// disabled
+func (m *appStateManager) getResourceAnnotationTemplates() resourceAnnotationTemplates {
+	if m.disableResourceAnnotationTemplates {
+		return nil
+	}
+	entries, err := m.settingsMgr.GetResourceAnnotationTemplates()
+	if err != nil {
+		log.Warnf("Could not get resource annotation templates from ConfigMap (not injecting annotations): %v", err)
+		return nil
+	}
+	return newResourceAnnotationTemplates(entries)
+}
+
+// storePreSyncSnapshot stores the live state of the resources of the application before the sync with the given ID
+// applies them. Secrets are left out so that their data is not stored in the cache.
+func (m *appStateManager) storePreSyncSnapshot(app *v1alpha1.Application, syncID string, liveObjs []*unstructured.Unstructured, now time.Time) error {
+	snapshot := &appstatecache.ResourceSnapshot{CreatedAt: now.UTC()}
+	for _, live := range liveObjs {
+		if live == nil || (live.GroupVersionKind().Group == "" && live.GetKind() == kube.SecretKind) {
+			continue
+		}
+		manifest, err := live.MarshalJSON()
 		if err != nil {
-			state.Phase = common.OperationError
-			state.Message = fmt.Sprintf("failed to record sync to history: %v", err)
+			return fmt.Errorf("error marshaling resource %s/%s: %w", live.GetKind(), live.GetName(), err)
+		}
+		gvk := live.GroupVersionKind()
+		snapshot.Resources = append(snapshot.Resources, appstatecache.ResourceSnapshotEntry{
+			ResourceRef: v1alpha1.ResourceRef{
+				Group:     gvk.Group,
+				Version:   gvk.Version,
+				Kind:      gvk.Kind,
+				Namespace: live.GetNamespace(),
+				Name:      live.GetName(),
+				UID:       string(live.GetUID()),
+			},
+			LiveManifest:    string(manifest),
+			ResourceVersion: live.GetResourceVersion(),
+		})
+	}
+	return m.cache.SetPreSyncSnapshot(app.InstanceName(m.namespace), syncID, snapshot, m.syncSnapshotRetention)
+}
+
+// serverSideApplyTargets returns the indexes of the target resources of the sync which are server-side applied and
+// already exist in the cluster
+func serverSideApplyTargets(syncOp v1alpha1.SyncOperation, reconciliationResult sync.ReconciliationResult) []int {
+	var indexes []int
+	for i, target := range reconciliationResult.Target {
+		// resources which do not exist yet have neither managed fields nor conflicts
+		if target == nil || reconciliationResult.Live[i] == nil {
+			continue
+		}
+		if len(syncOp.Resources) > 0 && !argo.ContainsSyncResource(target.GetName(), target.GetNamespace(), target.GroupVersionKind(), syncOp.Resources) {
+			continue
+		}
+		serverSideApply := syncOp.SyncOptions.HasOption(common.SyncOptionServerSideApply) || resourceutil.HasAnnotationOption(target, common.AnnotationSyncOptions, common.SyncOptionServerSideApply)
+		replace := syncOp.SyncOptions.HasOption(common.SyncOptionReplace) || resourceutil.HasAnnotationOption(target, common.AnnotationSyncOptions, common.SyncOptionReplace)
+		if serverSideApply && !replace {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// isBootstrapAppSelfSync returns whether the resource is the Application resource of the bootstrap application itself,
+// which the bootstrap application does not sync so that it does not replace itself in a loop
+func isBootstrapAppSelfSync(app *v1alpha1.Application, key kube.ResourceKey) bool {
+	return app.Labels[cdcommon.LabelKeyBootstrapApp] == "true" &&
+		key.Group == application.Group &&
+		key.Kind == application.ApplicationKind &&
+		key.Namespace == app.Namespace &&
+		key.Name == app.Name
+}
+
+// prepareServerSideApply prepares the live resources which are about to be server-side applied. With the
+// CleanupManagedFields=true sync option, the fields owned by client-side apply managers are transferred to the
+// server-side apply manager of Argo CD. With the "strict" and "force" conflict resolutions, the resources are applied
+// in dry-run mode without forcing conflicts, since the sync itself forces them: conflicts fail the sync with the
+// "strict" resolution and are reported as warnings with the "force" resolution.
+func (m *appStateManager) prepareServerSideApply(app *v1alpha1.Application, syncOp v1alpha1.SyncOperation, server string, restConfig *rest.Config, reconciliationResult sync.ReconciliationResult, logEntry *log.Entry) error {
+	conflictResolution := app.Spec.SyncPolicy.GetConflictResolution()
+	switch conflictResolution {
+	case v1alpha1.ConflictResolutionStrict, v1alpha1.ConflictResolutionForce:
+	case "", v1alpha1.ConflictResolutionMerge:
+		// merged conflicts are resolved without dry-run
+		conflictResolution = ""
+	default:
+		return fmt.Errorf("unknown conflict resolution %q", conflictResolution)
+	}
+	cleanupManagedFields := syncOp.SyncOptions.HasOption("CleanupManagedFields=true")
+	if conflictResolution == "" && !cleanupManagedFields {
+		return nil
+	}
+
+	var targets []*unstructured.Unstructured
+	for _, i := range serverSideApplyTargets(syncOp, reconciliationResult) {
+		targets = append(targets, reconciliationResult.Target[i])
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	clusterCache, err := m.liveStateCache.GetClusterCache(server)
+	if err != nil {
+		return fmt.Errorf("error getting cluster cache: %w", err)
+	}
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("error creating dynamic client: %w", err)
+	}
+	applier := NewServerSideApplier(client, cdcommon.ArgoCDSSAManager, apiResourceFor(clusterCache))
+
+	var failures []string
+	for _, target := range targets {
+		obj := target.DeepCopy()
+		if obj.GetNamespace() == "" && kube.IsNamespacedOrUnknown(clusterCache, obj.GroupVersionKind().GroupKind()) {
+			obj.SetNamespace(app.Spec.Destination.Namespace)
+		}
+		resourceLog := logEntry.WithFields(log.Fields{"kind": obj.GetKind(), "namespace": obj.GetNamespace(), "name": obj.GetName()})
+		if cleanupManagedFields {
+			migrated, err := applier.MigrateManagedFields(context.TODO(), obj, clientSideApplyManagers...)
+			if err != nil {
+				return err
+			}
+			if migrated {
+				resourceLog.Info("Transferred client-side apply managed fields to server-side apply")
+			}
+		}
+		if conflictResolution == "" {
+			continue
+		}
+		conflicts, err := applier.Apply(context.TODO(), obj, true, false)
+		if len(conflicts) == 0 {
+			if err != nil {
+				// the error is reported by the sync
+				resourceLog.Warnf("Failed to check server-side apply conflicts: %v", err)
+			}
+			continue
+		}
+		for _, conflict := range conflicts {
+			message := fmt.Sprintf("%s %s/%s: %s", obj.GetKind(), obj.GetNamespace(), obj.GetName(), conflict.Message)
+			if conflictResolution == v1alpha1.ConflictResolutionForce {
+				resourceLog.Warnf("Forcing server-side apply conflict: %s", conflict.Message)
+				if m.auditLogger != nil {
+					m.auditLogger.LogAppEvent(app, argo.EventInfo{Reason: argo.EventReasonServerSideApplyConflictForced, Type: corev1.EventTypeWarning}, "forced server-side apply conflict of "+message, "", nil)
+				}
+			} else {
+				failures = append(failures, message)
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("server-side apply conflicts: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// requiresSBOMAttestation returns true if one of the sources requires SBOM attestations for its container images
+func requiresSBOMAttestation(sources []v1alpha1.ApplicationSource) bool {
+	for _, source := range sources {
+		if source.VerifyAttestation.RequiresSBOM() {
+			return true
+		}
+	}
+	return false
+}
+
+// verifySBOMAttestations verifies that an SBOM attestation is attached to each container image of the target
+// resources, and that the SBOMs only contain licenses permitted by the project. The images of all sources are verified,
+// since the target resources are not associated with their source.
+func (m *appStateManager) verifySBOMAttestations(proj *v1alpha1.AppProject, targets []*unstructured.Unstructured, logEntry *log.Entry) error {
+	var errs attestation.VerificationErrors
+	for _, image := range attestation.Images(targets) {
+		err := m.attestationVerifier.VerifySBOM(context.TODO(), image, proj.Spec.Allowlist.GetLicenseIDs())
+		if err == nil {
+			logEntry.Debugf("Verified SBOM attestation of image %s", image)
+			continue
+		}
+		var verificationErr *attestation.VerificationError
+		if !goerrors.As(err, &verificationErr) {
+			verificationErr = &attestation.VerificationError{Image: image, Reason: attestation.ReasonVerificationFailed, Err: err}
 		}
+		errs = append(errs, verificationErr)
 	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
 }
 
 // normalizeTargetResources modifies target resources to ensure ignored fields are not touched during synchronization:
@@ -528,11 +1008,52 @@ func delayBetweenSyncWaves(phase common.SyncPhase, wave int, finalWave bool) err
 	return nil
 }
 
-func syncWindowPreventsSync(app *v1alpha1.Application, proj *v1alpha1.AppProject) bool {
+// syncWindowPreventsSync returns whether the sync windows of the project prevent the sync operation of the application.
+// A manual sync overrides the windows blocking it if they allow it and the override is among the given overrides
+// recorded by the API server, which verifies that the user is authorized to override them.
+func syncWindowPreventsSync(app *v1alpha1.Application, proj *v1alpha1.AppProject, overrides []argo.SyncWindowOverrideRecord) bool {
 	window := proj.Spec.SyncWindows.Matches(app)
 	isManual := false
+	overrideReason := ""
 	if app.Status.OperationState != nil {
 		isManual = !app.Status.OperationState.Operation.InitiatedBy.Automated
+		if app.Status.OperationState.Operation.Sync != nil {
+			overrideReason = app.Status.OperationState.Operation.Sync.SyncWindowOverrideReason
+		}
+	}
+	if window.CanSync(isManual) {
+		return false
+	}
+	// Manual syncs may override the sync windows blocking them if the windows allow it
+	if isManual && overrideReason != "" && window.CanOverride() {
+		logCtx := log.WithFields(log.Fields{
+			"application": app.QualifiedName(),
+			"user":        app.Status.OperationState.Operation.InitiatedBy.Username,
+			"reason":      overrideReason,
+		})
+		if !argo.IsSyncWindowOverrideRecorded(overrides, app, app.Status.OperationState) {
+			logCtx.Warn("Ignoring sync window override which was not recorded by the API server")
+			return true
+		}
+		logCtx.Warn("Sync windows overridden")
+		return false
+	}
+	return true
+}
+
+// getSyncWindowOverrides returns the sync window overrides of the application recorded by the API server
+func (m *appStateManager) getSyncWindowOverrides(app *v1alpha1.Application) []argo.SyncWindowOverrideRecord {
+	cm, err := m.settingsMgr.GetConfigMapByName(cdcommon.ArgoCDSyncWindowOverridesConfigMapName)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			log.Warnf("Failed to get sync window overrides: %v", err)
+		}
+		return nil
+	}
+	records, err := argo.GetSyncWindowOverrideRecords(cm, app)
+	if err != nil {
+		log.Warnf("Failed to get sync window overrides: %v", err)
+		return nil
 	}
-	return !window.CanSync(isManual)
+	return records
 }
@@ -0,0 +1,162 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/openapi"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	appstatecache "github.com/argoproj/argo-cd/v2/util/cache/appstate"
+)
+
+// syncCheckpointExpiration is the duration the checkpoint of a sync is kept if the sync never completes
+const syncCheckpointExpiration = 24 * time.Hour
+
+// syncCheckpointEntry is a resource applied by a sync
+type syncCheckpointEntry struct {
+	ResourceRef v1alpha1.ResourceRef `json:"resourceRef"`
+	Applied     bool                 `json:"applied"`
+}
+
+// syncCheckpointData is the list of the resources applied by a sync, stored in the cache
+type syncCheckpointData struct {
+	Resources []syncCheckpointEntry `json:"resources"`
+}
+
+func syncCheckpointKey(appName string, syncID string) string {
+	return fmt.Sprintf("sync-checkpoint:%s:%s", appName, syncID)
+}
+
+// syncCheckpointID identifies the current attempt of the sync operation. It is derived from the persisted operation
+// state, so that it is the same once the sync resumes after a restart of the controller, and changes with each retry.
+func syncCheckpointID(state *v1alpha1.OperationState) string {
+	return fmt.Sprintf("%d-%d", state.StartedAt.Unix(), state.RetryCount)
+}
+
+// syncCheckpoint records the resources applied by a sync in the cache, so that the resources applied before the
+// controller restarted are not applied again when the sync resumes
+type syncCheckpoint struct {
+	cache    *appstatecache.Cache
+	key      string
+	logEntry *log.Entry
+
+	lock    sync.Mutex
+	data    syncCheckpointData
+	applied map[kube.ResourceKey]bool
+}
+
+// loadSyncCheckpoint returns the checkpoint of the sync with the given ID, holding the resources it already applied
+func (m *appStateManager) loadSyncCheckpoint(appName string, syncID string, logEntry *log.Entry) *syncCheckpoint {
+	checkpoint := &syncCheckpoint{
+		cache:    m.cache,
+		key:      syncCheckpointKey(appName, syncID),
+		logEntry: logEntry,
+		applied:  map[kube.ResourceKey]bool{},
+	}
+	err := m.cache.GetItem(checkpoint.key, &checkpoint.data)
+	if err != nil && !errors.Is(err, appstatecache.ErrCacheMiss) {
+		logEntry.Warnf("Failed to load sync checkpoint: %v", err)
+	}
+	for _, entry := range checkpoint.data.Resources {
+		if entry.Applied {
+			ref := entry.ResourceRef
+			checkpoint.applied[kube.NewResourceKey(ref.Group, ref.Kind, ref.Namespace, ref.Name)] = true
+		}
+	}
+	if len(checkpoint.applied) > 0 {
+		logEntry.Infof("Resuming sync from checkpoint with %d applied resources", len(checkpoint.applied))
+	}
+	return checkpoint
+}
+
+// isApplied returns whether the resource was applied by the sync before
+func (c *syncCheckpoint) isApplied(obj *unstructured.Unstructured) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.applied[kube.GetResourceKey(obj)]
+}
+
+// recordApplied adds the resource to the checkpoint and stores the checkpoint in the cache
+func (c *syncCheckpoint) recordApplied(obj *unstructured.Unstructured) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := kube.GetResourceKey(obj)
+	if c.applied[key] {
+		return
+	}
+	c.applied[key] = true
+	gvk := obj.GroupVersionKind()
+	c.data.Resources = append(c.data.Resources, syncCheckpointEntry{
+		ResourceRef: v1alpha1.ResourceRef{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind, Namespace: obj.GetNamespace(), Name: obj.GetName()},
+		Applied:     true,
+	})
+	if err := c.cache.SetItem(c.key, &c.data, syncCheckpointExpiration, false); err != nil {
+		c.logEntry.Warnf("Failed to store sync checkpoint: %v", err)
+	}
+}
+
+// clear deletes the checkpoint from the cache once the sync completed
+func (c *syncCheckpoint) clear() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if err := c.cache.SetItem(c.key, &syncCheckpointData{}, 0, true); err != nil && !errors.Is(err, appstatecache.ErrCacheMiss) {
+		c.logEntry.Warnf("Failed to delete sync checkpoint: %v", err)
+	}
+}
+
+// checkpointKubectl is a Kubectl whose resource operations skip the resources applied before the sync was resumed and
+// record the resources they apply in the checkpoint of the sync
+type checkpointKubectl struct {
+	kube.Kubectl
+	checkpoint *syncCheckpoint
+}
+
+func (k *checkpointKubectl) ManageResources(config *rest.Config, openAPISchema openapi.Resources) (kube.ResourceOperations, func(), error) {
+	resourceOps, cleanup, err := k.Kubectl.ManageResources(config, openAPISchema)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &checkpointResourceOperations{ResourceOperations: resourceOps, checkpoint: k.checkpoint}, cleanup, nil
+}
+
+// checkpointResourceOperations skips the apply and replace of the resources in the checkpoint of the sync, and records
+// the resources it applies or replaces. Created resources, such as hooks, are always created.
+type checkpointResourceOperations struct {
+	kube.ResourceOperations
+	checkpoint *syncCheckpoint
+}
+
+func (o *checkpointResourceOperations) ApplyResource(ctx context.Context, obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, force, validate, serverSideApply bool, manager string, serverSideDiff bool) (string, error) {
+	return o.withCheckpoint(obj, dryRunStrategy, func() (string, error) {
+		return o.ResourceOperations.ApplyResource(ctx, obj, dryRunStrategy, force, validate, serverSideApply, manager, serverSideDiff)
+	})
+}
+
+func (o *checkpointResourceOperations) ReplaceResource(ctx context.Context, obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, force bool) (string, error) {
+	return o.withCheckpoint(obj, dryRunStrategy, func() (string, error) {
+		return o.ResourceOperations.ReplaceResource(ctx, obj, dryRunStrategy, force)
+	})
+}
+
+func (o *checkpointResourceOperations) withCheckpoint(obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, apply func() (string, error)) (string, error) {
+	if dryRunStrategy != cmdutil.DryRunNone {
+		return apply()
+	}
+	if o.checkpoint.isApplied(obj) {
+		return fmt.Sprintf("%s/%s applied before the sync was resumed", obj.GetKind(), obj.GetName()), nil
+	}
+	message, err := apply()
+	if err == nil {
+		o.checkpoint.recordApplied(obj)
+	}
+	return message, err
+}
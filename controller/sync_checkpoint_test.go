@@ -0,0 +1,126 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/test"
+	appstatecache "github.com/argoproj/argo-cd/v2/util/cache/appstate"
+)
+
+// recordingApplyOps records the resources it applies, except for dry runs
+type recordingApplyOps struct {
+	kube.ResourceOperations
+	lock    sync.Mutex
+	applied []string
+}
+
+func (o *recordingApplyOps) ApplyResource(_ context.Context, obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, _, _, _ bool, _ string, _ bool) (string, error) {
+	if dryRunStrategy == cmdutil.DryRunNone {
+		o.lock.Lock()
+		o.applied = append(o.applied, obj.GetName())
+		o.lock.Unlock()
+	}
+	return fmt.Sprintf("%s/%s configured", obj.GetKind(), obj.GetName()), nil
+}
+
+func (o *recordingApplyOps) appliedResources() []string {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	return append([]string{}, o.applied...)
+}
+
+func TestSyncAppState_Checkpoint(t *testing.T) {
+	t.Setenv(EnvVarSyncWaveDelay, "0")
+	server := discoveryServer(nil)
+	defer server.Close()
+
+	var manifests []string
+	var objs []*unstructured.Unstructured
+	for i := 0; i < 2; i++ {
+		manifest := fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-config-%d","namespace":%q,"annotations":{"argocd.argoproj.io/sync-wave":"%d"}}}`, i, test.FakeDestNamespace, i)
+		manifests = append(manifests, manifest)
+		objs = append(objs, test.YamlToUnstructured(manifest))
+	}
+	app := newFakeApp()
+	app.Status.OperationState = nil
+	app.Status.History = nil
+	app.Spec.Destination.Server = server.URL
+	ops := &recordingApplyOps{}
+	var manifestResponses []*apiclient.ManifestResponse
+	for i := 0; i < 6; i++ {
+		manifestResponses = append(manifestResponses, &apiclient.ManifestResponse{
+			Manifests: manifests,
+			Namespace: test.FakeDestNamespace,
+			Server:    server.URL,
+			Revision:  "abc123",
+		})
+	}
+	data := fakeData{
+		apps:                  []runtime.Object{app, &defaultProj},
+		manifestResponses:     manifestResponses,
+		managedLiveObjs:       make(map[kube.ResourceKey]*unstructured.Unstructured),
+		resourceOps:           ops,
+		clusterServer:         server.URL,
+		enableSyncCheckpoints: true,
+	}
+	ctrl := newFakeController(&data, nil)
+	// the applied resources become live, so that the next wave can start
+	updateLiveObjs := func() {
+		for _, obj := range objs[:len(ops.appliedResources())] {
+			data.managedLiveObjs[kube.GetResourceKey(obj)] = obj
+		}
+	}
+	opState := &v1alpha1.OperationState{
+		Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{}},
+		Phase:     common.OperationRunning,
+		StartedAt: metav1.Now(),
+	}
+	key := syncCheckpointKey(app.InstanceName(test.FakeArgoCDNamespace), syncCheckpointID(opState))
+
+	// the first wave is applied and recorded in the checkpoint
+	ctrl.appStateManager.SyncAppState(app, opState)
+	updateLiveObjs()
+	require.Equal(t, common.OperationRunning, opState.Phase, opState.Message)
+	assert.Equal(t, []string{"my-config-0"}, ops.appliedResources())
+	var checkpoint syncCheckpointData
+	require.NoError(t, ctrl.cache.GetItem(key, &checkpoint))
+	require.Len(t, checkpoint.Resources, 1)
+	assert.Equal(t, "my-config-0", checkpoint.Resources[0].ResourceRef.Name)
+	assert.True(t, checkpoint.Resources[0].Applied)
+
+	// the controller restarts before the results of the first wave are persisted, the resumed sync does not apply the
+	// resources of the first wave again
+	opState.SyncResult.Resources = nil
+	for i := 0; i < 5 && opState.Phase == common.OperationRunning; i++ {
+		ctrl.appStateManager.SyncAppState(app, opState)
+		updateLiveObjs()
+	}
+	require.Equal(t, common.OperationSucceeded, opState.Phase, opState.Message)
+	assert.Equal(t, []string{"my-config-0", "my-config-1"}, ops.appliedResources())
+
+	// the checkpoint is cleared once the sync completed
+	err := ctrl.cache.GetItem(key, &checkpoint)
+	assert.ErrorIs(t, err, appstatecache.ErrCacheMiss)
+}
+
+func TestSyncCheckpointID(t *testing.T) {
+	state := &v1alpha1.OperationState{StartedAt: metav1.Unix(1714559400, 0)}
+	assert.Equal(t, "1714559400-0", syncCheckpointID(state))
+	// each retry of the sync has its own checkpoint
+	state.RetryCount = 2
+	assert.Equal(t, "1714559400-2", syncCheckpointID(state))
+}
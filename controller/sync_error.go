@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"strings"
+
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// resourceSyncErrorCode returns the error code of the sync of a resource, empty if the sync of the resource did not
+// fail
+func resourceSyncErrorCode(res common.ResourceSyncResult) v1alpha1.SyncErrorCode {
+	failed := res.Status == common.ResultCodeSyncFailed || res.HookPhase == common.OperationFailed || res.HookPhase == common.OperationError
+	switch {
+	case !failed:
+		return ""
+	case strings.Contains(res.Message, ApplyTimeoutReason+":"):
+		return v1alpha1.SyncErrorCodeTimeout
+	case res.HookType != "":
+		return v1alpha1.SyncErrorCodeHookFailed
+	case res.Status == common.ResultCodeSyncFailed:
+		return v1alpha1.SyncErrorCodeApplyFailed
+	default:
+		// the resource was applied, but its health degraded before the sync completed
+		return v1alpha1.SyncErrorCodeHealthCheckFailed
+	}
+}
+
+// syncErrorCode returns the error code of a sync operation which completed in the given phase, from the results of
+// its resources, or empty if the operation did not fail
+func syncErrorCode(phase common.OperationPhase, terminating bool, resources []common.ResourceSyncResult) v1alpha1.SyncErrorCode {
+	if !phase.Completed() || phase.Successful() {
+		return ""
+	}
+	if terminating {
+		return v1alpha1.SyncErrorCodeTerminated
+	}
+	for _, res := range resources {
+		if code := resourceSyncErrorCode(res); code != "" {
+			return code
+		}
+	}
+	return v1alpha1.SyncErrorCodeUnknown
+}
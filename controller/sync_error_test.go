@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func TestResourceSyncErrorCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		res      common.ResourceSyncResult
+		expected v1alpha1.SyncErrorCode
+	}{
+		{"Synced", common.ResourceSyncResult{Status: common.ResultCodeSynced}, ""},
+		{"Pruned", common.ResourceSyncResult{Status: common.ResultCodePruned}, ""},
+		{"SucceededHook", common.ResourceSyncResult{HookType: common.HookTypePreSync, HookPhase: common.OperationSucceeded}, ""},
+		{"ApplyFailed", common.ResourceSyncResult{Status: common.ResultCodeSyncFailed, Message: "the server rejected the request"}, v1alpha1.SyncErrorCodeApplyFailed},
+		{"FailedHook", common.ResourceSyncResult{HookType: common.HookTypePreSync, HookPhase: common.OperationFailed}, v1alpha1.SyncErrorCodeHookFailed},
+		{"ErroredHook", common.ResourceSyncResult{HookType: common.HookTypePostSync, HookPhase: common.OperationError}, v1alpha1.SyncErrorCodeHookFailed},
+		{"Degraded", common.ResourceSyncResult{Status: common.ResultCodeSynced, HookPhase: common.OperationFailed}, v1alpha1.SyncErrorCodeHealthCheckFailed},
+		{"Timeout", common.ResourceSyncResult{Status: common.ResultCodeSyncFailed, Message: ApplyTimeoutReason + ": the apply did not complete within 30s"}, v1alpha1.SyncErrorCodeTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, resourceSyncErrorCode(tt.res))
+		})
+	}
+}
+
+func TestSyncErrorCode(t *testing.T) {
+	failedHook := common.ResourceSyncResult{HookType: common.HookTypeSync, HookPhase: common.OperationFailed}
+	failedApply := common.ResourceSyncResult{Status: common.ResultCodeSyncFailed}
+	synced := common.ResourceSyncResult{Status: common.ResultCodeSynced}
+
+	assert.Empty(t, syncErrorCode(common.OperationRunning, false, []common.ResourceSyncResult{failedApply}))
+	assert.Empty(t, syncErrorCode(common.OperationSucceeded, false, []common.ResourceSyncResult{synced}))
+	assert.Equal(t, v1alpha1.SyncErrorCodeTerminated, syncErrorCode(common.OperationFailed, true, []common.ResourceSyncResult{failedApply}))
+	assert.Equal(t, v1alpha1.SyncErrorCodeHookFailed, syncErrorCode(common.OperationFailed, false, []common.ResourceSyncResult{synced, failedHook, failedApply}))
+	assert.Equal(t, v1alpha1.SyncErrorCodeApplyFailed, syncErrorCode(common.OperationError, false, []common.ResourceSyncResult{failedApply}))
+	assert.Equal(t, v1alpha1.SyncErrorCodeUnknown, syncErrorCode(common.OperationFailed, false, []common.ResourceSyncResult{synced}))
+}
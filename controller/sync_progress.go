@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	goSync "sync"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/sync"
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/openapi"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+const (
+	// syncProgressReportInterval is the minimum interval between the reports of the progress of a sync operation while
+	// it applies resources
+	syncProgressReportInterval = time.Second
+	// syncProgressSmoothing is the weight of the latest apply time in the moving average of the apply time per resource
+	syncProgressSmoothing = 0.2
+)
+
+// syncOperationProgress is the progress of the sync operation of an application
+type syncOperationProgress struct {
+	operationStartedAt time.Time
+	// averageApplyTime is the exponential moving average of the time between the applies of two resources, which
+	// accounts for the resources applied concurrently
+	averageApplyTime time.Duration
+	lastAppliedAt    time.Time
+	reportedAt       time.Time
+	// applied is the number of resources applied by the operation, and total the number of resources it applies
+	applied int64
+	total   int64
+}
+
+// syncProgress tracks the progress of the sync operations of the applications, to estimate when they complete. The
+// estimates start over when the controller restarts.
+type syncProgress struct {
+	lock       goSync.Mutex
+	operations map[string]*syncOperationProgress
+}
+
+func newSyncProgress() *syncProgress {
+	return &syncProgress{operations: map[string]*syncOperationProgress{}}
+}
+
+// begin is called before the sync operation of the given application applies resources, with the number of resources
+// it already applied and the number of resources it applies
+func (p *syncProgress) begin(appName string, state *v1alpha1.OperationState, applied, total int64, now time.Time) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	operation, ok := p.operations[appName]
+	if !ok || !operation.operationStartedAt.Equal(state.StartedAt.Time) {
+		operation = &syncOperationProgress{operationStartedAt: state.StartedAt.Time}
+		p.operations[appName] = operation
+	}
+	operation.lastAppliedAt = now
+	operation.applied = applied
+	operation.total = total
+}
+
+// observeApply records that the sync operation of the given application applied a resource. It returns the progress
+// of the operation if it is due to be reported.
+func (p *syncProgress) observeApply(appName string, now time.Time) (*v1alpha1.SyncOperationProgress, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	operation, ok := p.operations[appName]
+	if !ok {
+		return nil, false
+	}
+	sample := now.Sub(operation.lastAppliedAt)
+	if operation.averageApplyTime == 0 {
+		operation.averageApplyTime = sample
+	} else {
+		operation.averageApplyTime = time.Duration(syncProgressSmoothing*float64(sample) + (1-syncProgressSmoothing)*float64(operation.averageApplyTime))
+	}
+	operation.lastAppliedAt = now
+	operation.applied++
+	if !operation.reportedAt.IsZero() && now.Sub(operation.reportedAt) < syncProgressReportInterval {
+		return nil, false
+	}
+	operation.reportedAt = now
+	return operation.progress(now), true
+}
+
+// estimate returns the progress of the sync operation of the given application, once it applied the given number of
+// its resources
+func (p *syncProgress) estimate(appName string, state *v1alpha1.OperationState, applied, total int64, now time.Time) *v1alpha1.SyncOperationProgress {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	operation, ok := p.operations[appName]
+	if !ok || !operation.operationStartedAt.Equal(state.StartedAt.Time) {
+		operation = &syncOperationProgress{operationStartedAt: state.StartedAt.Time}
+		p.operations[appName] = operation
+	}
+	operation.applied = applied
+	operation.total = total
+	if operation.averageApplyTime == 0 && applied > 0 {
+		// the applies were not observed by this controller, e.g. because it restarted during the operation
+		operation.averageApplyTime = now.Sub(state.StartedAt.Time) / time.Duration(applied)
+	}
+	return operation.progress(now)
+}
+
+// forget removes the progress of the sync operation of the given application, once the operation completed
+func (p *syncProgress) forget(appName string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.operations, appName)
+}
+
+// progress returns the progress of the operation, whose completion is estimated from the moving average of the apply
+// time per resource. The estimate is unset once all the resources were applied.
+func (o *syncOperationProgress) progress(now time.Time) *v1alpha1.SyncOperationProgress {
+	progress := &v1alpha1.SyncOperationProgress{ResourcesApplied: o.applied, ResourcesTotal: max(o.total, o.applied)}
+	if remaining := progress.ResourcesTotal - progress.ResourcesApplied; remaining > 0 && o.averageApplyTime > 0 {
+		estimatedCompletionTime := metav1.NewTime(now.Add(o.averageApplyTime * time.Duration(remaining)))
+		progress.EstimatedCompletionTime = &estimatedCompletionTime
+	}
+	return progress
+}
+
+// countSyncedResources returns the number of resources and hooks of the reconciliation result applied by a sync, which
+// are the resources having a target state and the hooks selected by the filter
+func countSyncedResources(reconciliationResult sync.ReconciliationResult, filter func(key kube.ResourceKey, target *unstructured.Unstructured, live *unstructured.Unstructured) bool) int64 {
+	var count int64
+	for i, target := range reconciliationResult.Target {
+		if target == nil {
+			continue
+		}
+		if filter(kube.GetResourceKey(target), target, reconciliationResult.Live[i]) {
+			count++
+		}
+	}
+	for _, hook := range reconciliationResult.Hooks {
+		if filter(kube.GetResourceKey(hook), hook, nil) {
+			count++
+		}
+	}
+	return count
+}
+
+// countAppliedResources returns the number of resources and hooks the sync applied
+func countAppliedResources(results []common.ResourceSyncResult) int64 {
+	var count int64
+	for _, res := range results {
+		if res.Status == common.ResultCodeSynced {
+			count++
+		}
+	}
+	return count
+}
+
+// reportSyncProgress updates the progress of the running sync operation of the application
+func (m *appStateManager) reportSyncProgress(app *v1alpha1.Application, progress *v1alpha1.SyncOperationProgress, logEntry *log.Entry) {
+	patch, err := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"operationState": map[string]interface{}{
+				"progress": progress,
+			},
+		},
+	})
+	if err != nil {
+		logEntry.Warnf("Failed to marshal the progress of the sync: %v", err)
+		return
+	}
+	_, err = m.appclientset.ArgoprojV1alpha1().Applications(app.Namespace).Patch(context.Background(), app.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		logEntry.Warnf("Failed to report the progress of the sync: %v", err)
+	}
+}
+
+// progressKubectl is a Kubectl whose resource operations call onApplied each time a resource is applied, replaced or
+// created, except for dry runs
+type progressKubectl struct {
+	kube.Kubectl
+	onApplied func()
+}
+
+func (k *progressKubectl) ManageResources(config *rest.Config, openAPISchema openapi.Resources) (kube.ResourceOperations, func(), error) {
+	resourceOps, cleanup, err := k.Kubectl.ManageResources(config, openAPISchema)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &progressResourceOperations{ResourceOperations: resourceOps, onApplied: k.onApplied}, cleanup, nil
+}
+
+// progressResourceOperations calls onApplied each time a resource is applied, replaced or created, except for dry runs
+type progressResourceOperations struct {
+	kube.ResourceOperations
+	onApplied func()
+}
+
+func (o *progressResourceOperations) ApplyResource(ctx context.Context, obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, force, validate, serverSideApply bool, manager string, serverSideDiff bool) (string, error) {
+	message, err := o.ResourceOperations.ApplyResource(ctx, obj, dryRunStrategy, force, validate, serverSideApply, manager, serverSideDiff)
+	o.observe(dryRunStrategy, err)
+	return message, err
+}
+
+func (o *progressResourceOperations) ReplaceResource(ctx context.Context, obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, force bool) (string, error) {
+	message, err := o.ResourceOperations.ReplaceResource(ctx, obj, dryRunStrategy, force)
+	o.observe(dryRunStrategy, err)
+	return message, err
+}
+
+func (o *progressResourceOperations) CreateResource(ctx context.Context, obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, validate bool) (string, error) {
+	message, err := o.ResourceOperations.CreateResource(ctx, obj, dryRunStrategy, validate)
+	o.observe(dryRunStrategy, err)
+	return message, err
+}
+
+func (o *progressResourceOperations) observe(dryRunStrategy cmdutil.DryRunStrategy, err error) {
+	if err == nil && dryRunStrategy == cmdutil.DryRunNone {
+		o.onApplied()
+	}
+}
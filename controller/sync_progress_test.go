@@ -0,0 +1,150 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+func TestSyncProgress(t *testing.T) {
+	progress := newSyncProgress()
+	operationStartedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	state := &v1alpha1.OperationState{StartedAt: metav1.NewTime(operationStartedAt)}
+	now := operationStartedAt.Add(time.Minute)
+
+	progress.begin("argocd/my-app", state, 0, 10, now)
+
+	// the first apply is reported right away
+	reported, report := progress.observeApply("argocd/my-app", now.Add(2*time.Second))
+	require.True(t, report)
+	assert.Equal(t, int64(1), reported.ResourcesApplied)
+	assert.Equal(t, int64(10), reported.ResourcesTotal)
+	assert.Equal(t, now.Add(20*time.Second), reported.EstimatedCompletionTime.Time)
+
+	// the next applies are reported at most once per report interval
+	_, report = progress.observeApply("argocd/my-app", now.Add(2*time.Second+500*time.Millisecond))
+	assert.False(t, report)
+	reported, report = progress.observeApply("argocd/my-app", now.Add(7*time.Second))
+	require.True(t, report)
+	assert.Equal(t, int64(3), reported.ResourcesApplied)
+	// the moving average of the apply time is 0.2*4.5s + 0.8*(0.2*0.5s + 0.8*2s)
+	assert.Equal(t, now.Add(7*time.Second+7*2260*time.Millisecond), reported.EstimatedCompletionTime.Time)
+
+	estimated := progress.estimate("argocd/my-app", state, 10, 10, now.Add(30*time.Second))
+	assert.Equal(t, &v1alpha1.SyncOperationProgress{ResourcesApplied: 10, ResourcesTotal: 10}, estimated)
+
+	// the apply time is estimated from the start of the operation if the applies were not observed
+	progress.forget("argocd/my-app")
+	estimated = progress.estimate("argocd/my-app", state, 6, 10, now)
+	assert.Equal(t, int64(6), estimated.ResourcesApplied)
+	assert.Equal(t, now.Add(40*time.Second), estimated.EstimatedCompletionTime.Time)
+
+	// the applies of unknown operations are ignored
+	_, report = progress.observeApply("argocd/other-app", now)
+	assert.False(t, report)
+}
+
+func TestCountAppliedResources(t *testing.T) {
+	assert.Equal(t, int64(2), countAppliedResources([]common.ResourceSyncResult{
+		{Status: common.ResultCodeSynced},
+		{Status: common.ResultCodeSyncFailed},
+		{Status: common.ResultCodePruned},
+		{Status: common.ResultCodeSynced, HookType: common.HookTypePreSync},
+	}))
+}
+
+type failingApplyOps struct {
+	kube.ResourceOperations
+}
+
+func (o *failingApplyOps) ApplyResource(_ context.Context, _ *unstructured.Unstructured, _ cmdutil.DryRunStrategy, _, _, _ bool, _ string, _ bool) (string, error) {
+	return "", errors.New("apply failed")
+}
+
+func TestProgressResourceOperations(t *testing.T) {
+	configMap := &unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetName("my-config")
+
+	applied := 0
+	ops := &progressResourceOperations{ResourceOperations: &slowApplyOps{}, onApplied: func() {
+		applied++
+	}}
+	_, err := ops.ApplyResource(context.Background(), configMap, cmdutil.DryRunNone, false, false, false, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, applied)
+
+	_, err = ops.ApplyResource(context.Background(), configMap, cmdutil.DryRunClient, false, false, false, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, 1, applied)
+
+	ops.ResourceOperations = &failingApplyOps{}
+	_, err = ops.ApplyResource(context.Background(), configMap, cmdutil.DryRunNone, false, false, false, "", false)
+	require.Error(t, err)
+	assert.Equal(t, 1, applied)
+}
+
+func TestSyncAppState_Progress(t *testing.T) {
+	server := discoveryServer(nil)
+	defer server.Close()
+
+	var manifests []string
+	for i := 0; i < 3; i++ {
+		manifests = append(manifests, fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-config-%d","namespace":%q}}`, i, test.FakeDestNamespace))
+	}
+	syncApp := func(dryRun bool) (*ApplicationController, *v1alpha1.Application, *v1alpha1.OperationState) {
+		app := newFakeApp()
+		app.Status.OperationState = nil
+		app.Status.History = nil
+		app.Spec.Destination.Server = server.URL
+		data := fakeData{
+			apps: []runtime.Object{app, &defaultProj},
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: manifests,
+				Namespace: test.FakeDestNamespace,
+				Server:    server.URL,
+				Revision:  "abc123",
+			},
+			managedLiveObjs: make(map[kube.ResourceKey]*unstructured.Unstructured),
+			resourceOps:     &slowApplyOps{},
+			clusterServer:   server.URL,
+		}
+		ctrl := newFakeController(&data, nil)
+		opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{DryRun: dryRun}}, StartedAt: metav1.Now()}
+		ctrl.appStateManager.SyncAppState(app, opState)
+		return ctrl, app, opState
+	}
+
+	ctrl, app, opState := syncApp(false)
+	assert.Equal(t, common.OperationSucceeded, opState.Phase, opState.Message)
+	assert.Equal(t, &v1alpha1.SyncOperationProgress{ResourcesApplied: 3, ResourcesTotal: 3}, opState.Progress)
+
+	// the progress is reported while the resources are applied
+	updatedApp, err := ctrl.applicationClientset.ArgoprojV1alpha1().Applications(app.Namespace).Get(context.Background(), app.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	require.NotNil(t, updatedApp.Status.OperationState)
+	require.NotNil(t, updatedApp.Status.OperationState.Progress)
+	assert.Positive(t, updatedApp.Status.OperationState.Progress.ResourcesApplied)
+	assert.Equal(t, int64(3), updatedApp.Status.OperationState.Progress.ResourcesTotal)
+
+	// the progress of dry runs is not reported
+	_, _, opState = syncApp(true)
+	assert.Equal(t, common.OperationSucceeded, opState.Phase, opState.Message)
+	assert.Nil(t, opState.Progress)
+}
@@ -0,0 +1,782 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/gobwas/glob"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/csaupgrade"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+	"k8s.io/kubectl/pkg/util/openapi"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+// FieldManagerConflict is a field of a server-side applied resource which is owned by another field manager with a
+// different value
+type FieldManagerConflict struct {
+	// Field is the path of the conflicting field, e.g. .spec.replicas
+	Field string
+	// Message describes the conflict, including the manager owning the field
+	Message string
+}
+
+// ServerSideApplier applies resources with server-side apply using the dynamic client. Unlike kubectl apply
+// --server-side, which is what the sync uses, it does not force field manager conflicts unless asked to, so that
+// conflicts can be detected and reported.
+type ServerSideApplier struct {
+	client  dynamic.Interface
+	manager string
+	// resourceFor returns the API resource of the given kind
+	resourceFor func(gvk schema.GroupVersionKind) (*metav1.APIResource, error)
+}
+
+// NewServerSideApplier returns a ServerSideApplier applying resources as the given field manager
+func NewServerSideApplier(client dynamic.Interface, manager string, resourceFor func(gvk schema.GroupVersionKind) (*metav1.APIResource, error)) *ServerSideApplier {
+	return &ServerSideApplier{
+		client:      client,
+		manager:     manager,
+		resourceFor: resourceFor,
+	}
+}
+
+func (a *ServerSideApplier) resourceInterface(obj *unstructured.Unstructured) (dynamic.ResourceInterface, error) {
+	gvk := obj.GroupVersionKind()
+	apiResource, err := a.resourceFor(gvk)
+	if err != nil {
+		return nil, fmt.Errorf("error getting API resource of %s: %w", gvk, err)
+	}
+	resource := a.client.Resource(gvk.GroupVersion().WithResource(apiResource.Name))
+	if apiResource.Namespaced {
+		return resource.Namespace(obj.GetNamespace()), nil
+	}
+	return resource, nil
+}
+
+// Apply server-side applies obj and returns the field manager conflicts. Conflicts are forced when force is true, and
+// result in an error otherwise. With dryRun, the request is validated by the API server without being persisted.
+func (a *ServerSideApplier) Apply(ctx context.Context, obj *unstructured.Unstructured, dryRun, force bool) ([]FieldManagerConflict, error) {
+	client, err := a.resourceInterface(obj)
+	if err != nil {
+		return nil, err
+	}
+	obj = obj.DeepCopy()
+	// managed fields must not be set in apply requests
+	obj.SetManagedFields(nil)
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling %s %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	opts := metav1.PatchOptions{FieldManager: a.manager}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	_, err = client.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, opts)
+	if err == nil {
+		return nil, nil
+	}
+	if !apierrors.IsConflict(err) {
+		return nil, fmt.Errorf("error applying %s %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	conflicts := fieldManagerConflicts(err)
+	if !force {
+		return conflicts, fmt.Errorf("error applying %s %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	opts.Force = &force
+	if _, err = client.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, opts); err != nil {
+		return conflicts, fmt.Errorf("error force applying %s %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	return conflicts, nil
+}
+
+// MigrateManagedFields transfers the ownership of the fields of obj managed with client-side apply by the given
+// managers to the server-side apply manager. Without it, fields which were removed from the desired state after being
+// applied client-side are not removed by the first server-side apply, since they are still owned by the client-side
+// managers. It returns true if the managed fields of the resource were updated.
+func (a *ServerSideApplier) MigrateManagedFields(ctx context.Context, obj *unstructured.Unstructured, csaManagers ...string) (bool, error) {
+	client, err := a.resourceInterface(obj)
+	if err != nil {
+		return false, err
+	}
+	// the managed fields are read from the API server since cached live states may not include them
+	live, err := client.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error getting %s %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(live, sets.New(csaManagers...), a.manager)
+	if err != nil {
+		return false, fmt.Errorf("error computing managed fields of %s %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	if patch == nil {
+		return false, nil
+	}
+	if _, err := client.Patch(ctx, obj.GetName(), types.JSONPatchType, patch, metav1.PatchOptions{}); err != nil {
+		return false, fmt.Errorf("error updating managed fields of %s %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	return true, nil
+}
+
+// fieldManagerConflicts returns the field manager conflicts reported by an apply conflict error
+func fieldManagerConflicts(err error) []FieldManagerConflict {
+	var conflicts []FieldManagerConflict
+	if status, ok := err.(apierrors.APIStatus); ok && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			if cause.Type == metav1.CauseTypeFieldManagerConflict {
+				conflicts = append(conflicts, FieldManagerConflict{Field: cause.Field, Message: cause.Message})
+			}
+		}
+	}
+	if len(conflicts) == 0 {
+		conflicts = append(conflicts, FieldManagerConflict{Message: err.Error()})
+	}
+	return conflicts
+}
+
+// ResourceWithPatch is a resource applied by a bulk apply
+type ResourceWithPatch struct {
+	// Target is the desired state of the resource
+	Target *unstructured.Unstructured
+	// Patch is the patch between the live and the desired state of the resource. Resources with an empty patch ("{}")
+	// are in sync and are not applied, while resources with a nil patch are always applied.
+	Patch []byte
+}
+
+// BulkApplier applies many resources at once. The resources are grouped by GroupVersionKind, and the resources of a
+// group are server-side applied concurrently with a single request each, instead of going through kubectl apply one
+// by one. Kubernetes has no API applying several objects in a single request. The resources whose kind does not
+// support server-side apply are applied one by one with client-side apply.
+//
+// Unlike kubectl apply --server-side, the fields managed with client-side apply are not transferred to the server-side
+// apply manager, which the CleanupManagedFields=true sync option does before the sync.
+type BulkApplier struct {
+	applier     *ServerSideApplier
+	resourceOps kube.ResourceOperations
+	// parallelism is the maximum number of concurrent apply requests within a group
+	parallelism int
+	// force is passed to the client-side apply of the resources which cannot be server-side applied. Field manager
+	// conflicts are always forced, as kubectl apply --server-side does in syncs.
+	force bool
+	// validate rejects the resources with unknown or duplicate fields
+	validate bool
+}
+
+// NewBulkApplier returns a BulkApplier which server-side applies resources with applier, and client-side applies them
+// with resourceOps, with at most parallelism concurrent requests per GroupVersionKind
+func NewBulkApplier(applier *ServerSideApplier, resourceOps kube.ResourceOperations, parallelism int, force, validate bool) *BulkApplier {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	return &BulkApplier{
+		applier:     applier,
+		resourceOps: resourceOps,
+		parallelism: parallelism,
+		force:       force,
+		validate:    validate,
+	}
+}
+
+// BulkApply applies the given resources and returns one error per resource, in the same order as the resources. A nil
+// error means the resource was applied, or did not need to be.
+func (b *BulkApplier) BulkApply(ctx context.Context, resources []ResourceWithPatch) []error {
+	errs := make([]error, len(resources))
+	var groups []schema.GroupVersionKind
+	indexesByGVK := map[schema.GroupVersionKind][]int{}
+	for i, res := range resources {
+		if res.Target == nil {
+			errs[i] = fmt.Errorf("resource %d has no target state", i)
+			continue
+		}
+		if isEmptyPatch(res.Patch) {
+			continue
+		}
+		gvk := res.Target.GroupVersionKind()
+		if _, ok := indexesByGVK[gvk]; !ok {
+			groups = append(groups, gvk)
+		}
+		indexesByGVK[gvk] = append(indexesByGVK[gvk], i)
+	}
+
+	for _, gvk := range groups {
+		indexes := indexesByGVK[gvk]
+		if !b.supportsServerSideApply(gvk) {
+			for _, i := range indexes {
+				errs[i] = b.clientSideApply(ctx, resources[i].Target)
+			}
+			continue
+		}
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, b.parallelism)
+		for _, i := range indexes {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int) {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				errs[i] = b.serverSideApply(ctx, resources[i].Target)
+			}(i)
+		}
+		wg.Wait()
+	}
+	return errs
+}
+
+// supportsServerSideApply returns whether the resources of the given kind can be server-side applied, which requires
+// the patch verb. Unknown kinds are server-side applied, so that the API server reports the error.
+func (b *BulkApplier) supportsServerSideApply(gvk schema.GroupVersionKind) bool {
+	apiResource, err := b.applier.resourceFor(gvk)
+	if err != nil || len(apiResource.Verbs) == 0 {
+		return true
+	}
+	return sets.New(apiResource.Verbs...).Has("patch")
+}
+
+func (b *BulkApplier) serverSideApply(ctx context.Context, obj *unstructured.Unstructured) error {
+	client, err := b.applier.resourceInterface(obj)
+	if err != nil {
+		return err
+	}
+	// server-side apply does not maintain the last applied configuration, which is still needed by the client-side
+	// apply and the diff of the resources which are not server-side applied
+	applied, err := withLastAppliedConfiguration(obj)
+	if err != nil {
+		return fmt.Errorf("error setting last applied configuration of %s %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	// managed fields must not be set in apply requests
+	applied.SetManagedFields(nil)
+	data, err := json.Marshal(applied)
+	if err != nil {
+		return fmt.Errorf("error marshaling %s %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	force := true
+	opts := metav1.PatchOptions{FieldManager: b.applier.manager, Force: &force, FieldValidation: metav1.FieldValidationIgnore}
+	if b.validate {
+		opts.FieldValidation = metav1.FieldValidationStrict
+	}
+	_, err = client.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, opts)
+	if apierrors.IsUnsupportedMediaType(err) {
+		// the API of the resource, e.g. an aggregated API, does not support apply patches
+		return b.clientSideApply(ctx, obj)
+	}
+	if err != nil {
+		return fmt.Errorf("error applying %s %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	return nil
+}
+
+func (b *BulkApplier) clientSideApply(ctx context.Context, obj *unstructured.Unstructured) error {
+	if _, err := b.resourceOps.ApplyResource(ctx, obj, cmdutil.DryRunNone, b.force, b.validate, false, b.applier.manager, false); err != nil {
+		return fmt.Errorf("error applying %s %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	return nil
+}
+
+// withLastAppliedConfiguration returns a copy of obj annotated with its own configuration, the same way kubectl apply
+// records it
+func withLastAppliedConfiguration(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	obj = obj.DeepCopy()
+	annotations := obj.GetAnnotations()
+	delete(annotations, corev1.LastAppliedConfigAnnotation)
+	obj.SetAnnotations(annotations)
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return obj, err
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[corev1.LastAppliedConfigAnnotation] = string(data)
+	obj.SetAnnotations(annotations)
+	return obj, nil
+}
+
+func isEmptyPatch(patch []byte) bool {
+	return patch != nil && (len(patch) == 0 || string(patch) == "{}")
+}
+
+// bulkApplyWindow is the time the server-side applies of the resources of the same kind are collected for, before
+// they are applied in bulk. The sync applies the resources of the same kind of a sync wave concurrently, so that they
+// are all collected in a single bulk apply.
+const bulkApplyWindow = 10 * time.Millisecond
+
+// bulkApplyParallelism is the maximum number of concurrent apply requests of a bulk apply per GroupVersionKind
+const bulkApplyParallelism = 20
+
+// bulkApplyKubectl is a Kubectl whose resource operations collect the server-side applies of resources of the same
+// kind and apply them with a BulkApplier
+type bulkApplyKubectl struct {
+	kube.Kubectl
+	// resourceFor returns the API resource of the given kind in the destination cluster
+	resourceFor func(gvk schema.GroupVersionKind) (*metav1.APIResource, error)
+}
+
+func (k *bulkApplyKubectl) ManageResources(config *rest.Config, openAPISchema openapi.Resources) (kube.ResourceOperations, func(), error) {
+	resourceOps, cleanup, err := k.Kubectl.ManageResources(config, openAPISchema)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		cleanup()
+		return nil, nil, fmt.Errorf("error creating dynamic client: %w", err)
+	}
+	return newBulkApplyResourceOperations(resourceOps, client, k.resourceFor, bulkApplyWindow), cleanup, nil
+}
+
+// bulkApplyKey identifies the applies which can be applied in the same bulk apply
+type bulkApplyKey struct {
+	gvk      schema.GroupVersionKind
+	force    bool
+	validate bool
+	manager  string
+}
+
+// bulkApplyBatch collects the resources of a bulk apply
+type bulkApplyBatch struct {
+	resources []ResourceWithPatch
+	errs      []error
+	done      chan struct{}
+}
+
+// bulkApplyResourceOperations collects the server-side applies of the resources of the same kind for the bulk apply
+// window, and applies them with a BulkApplier. Dry runs, client-side applies, replaces and creations are left to the
+// wrapped resource operations.
+type bulkApplyResourceOperations struct {
+	kube.ResourceOperations
+	client      dynamic.Interface
+	resourceFor func(gvk schema.GroupVersionKind) (*metav1.APIResource, error)
+	window      time.Duration
+	lock        sync.Mutex
+	batches     map[bulkApplyKey]*bulkApplyBatch
+}
+
+func newBulkApplyResourceOperations(resourceOps kube.ResourceOperations, client dynamic.Interface, resourceFor func(gvk schema.GroupVersionKind) (*metav1.APIResource, error), window time.Duration) *bulkApplyResourceOperations {
+	return &bulkApplyResourceOperations{
+		ResourceOperations: resourceOps,
+		client:             client,
+		resourceFor:        resourceFor,
+		window:             window,
+		batches:            map[bulkApplyKey]*bulkApplyBatch{},
+	}
+}
+
+func (o *bulkApplyResourceOperations) ApplyResource(ctx context.Context, obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, force, validate, serverSideApply bool, manager string, serverSideDiff bool) (string, error) {
+	if !serverSideApply || serverSideDiff || dryRunStrategy != cmdutil.DryRunNone {
+		return o.ResourceOperations.ApplyResource(ctx, obj, dryRunStrategy, force, validate, serverSideApply, manager, serverSideDiff)
+	}
+	key := bulkApplyKey{gvk: obj.GroupVersionKind(), force: force, validate: validate, manager: manager}
+	o.lock.Lock()
+	batch, ok := o.batches[key]
+	if !ok {
+		batch = &bulkApplyBatch{done: make(chan struct{})}
+		o.batches[key] = batch
+		// the batch is shared by the applies of several resources, so it is not canceled with the context of the first
+		// one, while each caller stops waiting for it once its own context is done
+		batchCtx := context.WithoutCancel(ctx)
+		time.AfterFunc(o.window, func() {
+			o.flush(batchCtx, key, batch)
+		})
+	}
+	i := len(batch.resources)
+	batch.resources = append(batch.resources, ResourceWithPatch{Target: obj})
+	o.lock.Unlock()
+
+	select {
+	case <-batch.done:
+	case <-ctx.Done():
+		return "", fmt.Errorf("error applying %s %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), ctx.Err())
+	}
+	if err := batch.errs[i]; err != nil {
+		return "", err
+	}
+	return serverSideAppliedMessage(obj), nil
+}
+
+// flush applies the resources collected by a batch, which no longer collects resources
+func (o *bulkApplyResourceOperations) flush(ctx context.Context, key bulkApplyKey, batch *bulkApplyBatch) {
+	o.lock.Lock()
+	delete(o.batches, key)
+	o.lock.Unlock()
+	applier := NewBulkApplier(NewServerSideApplier(o.client, key.manager, o.resourceFor), o.ResourceOperations, bulkApplyParallelism, key.force, key.validate)
+	batch.errs = applier.BulkApply(ctx, batch.resources)
+	close(batch.done)
+}
+
+// serverSideAppliedMessage returns the message of a server-side applied resource, the same as kubectl apply
+// --server-side
+func serverSideAppliedMessage(obj *unstructured.Unstructured) string {
+	kind := strings.ToLower(obj.GetKind())
+	if group := obj.GroupVersionKind().Group; group != "" {
+		kind += "." + group
+	}
+	return fmt.Sprintf("%s/%s serverside-applied", kind, obj.GetName())
+}
+
+// applyRateLimiters holds the token buckets limiting the requests which modify resources of the destination clusters
+// during syncs. The default limit is a bucket shared by all applications syncing to the same cluster, so that the load
+// on the API server of a cluster does not grow with the number of applications syncing to it at once. The limit of an
+// application is a bucket of its own sync, which only delays the requests of that sync on top of the shared bucket.
+type applyRateLimiters struct {
+	// defaultRequestsPerSecond is the limit shared by the applications syncing to a cluster, 0 disables it
+	defaultRequestsPerSecond float64
+	lock                     sync.Mutex
+	// clusters are the shared token buckets of the destination clusters, keyed by server
+	clusters map[string]*rate.Limiter
+}
+
+func newApplyRateLimiters(defaultRequestsPerSecond float64) *applyRateLimiters {
+	return &applyRateLimiters{
+		defaultRequestsPerSecond: defaultRequestsPerSecond,
+		clusters:                 map[string]*rate.Limiter{},
+	}
+}
+
+// get returns the token buckets limiting the requests of a sync with the given apply rate limit to the given cluster,
+// or nil if they are not limited
+func (l *applyRateLimiters) get(server string, applyRateLimit *v1alpha1.ApplyRateLimit) ([]*rate.Limiter, error) {
+	if l == nil {
+		return nil, nil
+	}
+	var limiters []*rate.Limiter
+	if applyRateLimit != nil {
+		requestsPerSecond, burst, err := applyRateLimit.Limit()
+		if err != nil {
+			return nil, fmt.Errorf("invalid apply rate limit: %w", err)
+		}
+		limiters = append(limiters, rate.NewLimiter(rate.Limit(requestsPerSecond), burst))
+	}
+	if l.defaultRequestsPerSecond > 0 {
+		l.lock.Lock()
+		defer l.lock.Unlock()
+		cluster, ok := l.clusters[server]
+		if !ok {
+			cluster = rate.NewLimiter(rate.Limit(l.defaultRequestsPerSecond), int(math.Ceil(l.defaultRequestsPerSecond)))
+			l.clusters[server] = cluster
+		}
+		limiters = append(limiters, cluster)
+	}
+	return limiters, nil
+}
+
+// rateLimitedTransport delays the requests which modify resources until all token buckets permit them. Reads are
+// never delayed.
+type rateLimitedTransport struct {
+	delegate    http.RoundTripper
+	limiters    []*rate.Limiter
+	onThrottled func()
+}
+
+func (t *rateLimitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	switch req.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return t.delegate.RoundTrip(req)
+	}
+	var reservations []*rate.Reservation
+	cancel := func() {
+		for _, reservation := range reservations {
+			reservation.Cancel()
+		}
+	}
+	var delay time.Duration
+	for _, limiter := range t.limiters {
+		reservation := limiter.Reserve()
+		if !reservation.OK() {
+			cancel()
+			return nil, fmt.Errorf("apply rate limit of %v requests per second does not permit any request", limiter.Limit())
+		}
+		reservations = append(reservations, reservation)
+		delay = max(delay, reservation.Delay())
+	}
+	if delay > 0 {
+		if t.onThrottled != nil {
+			t.onThrottled()
+		}
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			cancel()
+			return nil, req.Context().Err()
+		}
+	}
+	return t.delegate.RoundTrip(req)
+}
+
+// withApplyRateLimit returns a copy of the config whose requests modifying resources are limited by the token buckets.
+// onThrottled is called for each request which is delayed.
+func withApplyRateLimit(config *rest.Config, limiters []*rate.Limiter, onThrottled func()) *rest.Config {
+	config = rest.CopyConfig(config)
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &rateLimitedTransport{delegate: rt, limiters: limiters, onThrottled: onThrottled}
+	})
+	return config
+}
+
+// ApplyTimeoutReason prefixes the messages of the resources whose apply did not complete within their apply timeout
+const ApplyTimeoutReason = "ApplyTimeout"
+
+// applyTimeoutKubectl is a Kubectl whose resource operations fail the apply of resources which does not complete within
+// the apply timeout of their kind
+type applyTimeoutKubectl struct {
+	kube.Kubectl
+	// timeout returns the apply timeout of the resources of the given kind, zero disables it
+	timeout func(gk schema.GroupKind) time.Duration
+	// onTimeout is called for each resource whose apply timed out
+	onTimeout func(obj *unstructured.Unstructured, timeout time.Duration)
+}
+
+func (k *applyTimeoutKubectl) ManageResources(config *rest.Config, openAPISchema openapi.Resources) (kube.ResourceOperations, func(), error) {
+	resourceOps, cleanup, err := k.Kubectl.ManageResources(config, openAPISchema)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &applyTimeoutResourceOperations{ResourceOperations: resourceOps, timeout: k.timeout, onTimeout: k.onTimeout}, cleanup, nil
+}
+
+// applyTimeoutResourceOperations fails the apply, replace and creation of resources which do not complete within the
+// apply timeout of their kind. The context of the timed out call is canceled, but since kubectl does not always honor it,
+// the call may still complete in the background.
+type applyTimeoutResourceOperations struct {
+	kube.ResourceOperations
+	timeout   func(gk schema.GroupKind) time.Duration
+	onTimeout func(obj *unstructured.Unstructured, timeout time.Duration)
+}
+
+func (o *applyTimeoutResourceOperations) ApplyResource(ctx context.Context, obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, force, validate, serverSideApply bool, manager string, serverSideDiff bool) (string, error) {
+	return o.withTimeout(ctx, obj, func(ctx context.Context) (string, error) {
+		return o.ResourceOperations.ApplyResource(ctx, obj, dryRunStrategy, force, validate, serverSideApply, manager, serverSideDiff)
+	})
+}
+
+func (o *applyTimeoutResourceOperations) ReplaceResource(ctx context.Context, obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, force bool) (string, error) {
+	return o.withTimeout(ctx, obj, func(ctx context.Context) (string, error) {
+		return o.ResourceOperations.ReplaceResource(ctx, obj, dryRunStrategy, force)
+	})
+}
+
+func (o *applyTimeoutResourceOperations) CreateResource(ctx context.Context, obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, validate bool) (string, error) {
+	return o.withTimeout(ctx, obj, func(ctx context.Context) (string, error) {
+		return o.ResourceOperations.CreateResource(ctx, obj, dryRunStrategy, validate)
+	})
+}
+
+func (o *applyTimeoutResourceOperations) withTimeout(ctx context.Context, obj *unstructured.Unstructured, apply func(ctx context.Context) (string, error)) (string, error) {
+	timeout := o.timeout(obj.GroupVersionKind().GroupKind())
+	if timeout <= 0 {
+		return apply(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	type result struct {
+		message string
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		message, err := apply(ctx)
+		done <- result{message: message, err: err}
+	}()
+	select {
+	case res := <-done:
+		return res.message, res.err
+	case <-ctx.Done():
+		if o.onTimeout != nil {
+			o.onTimeout(obj, timeout)
+		}
+		return "", errors.New(applyTimeoutMessage(obj, timeout))
+	}
+}
+
+// applyTimeoutMessage returns the message of a resource whose apply did not complete within the given timeout
+func applyTimeoutMessage(obj *unstructured.Unstructured, timeout time.Duration) string {
+	name := obj.GetName()
+	if obj.GetNamespace() != "" {
+		name = obj.GetNamespace() + "/" + name
+	}
+	return fmt.Sprintf("%s: apply of %s %s did not complete within %v", ApplyTimeoutReason, obj.GetKind(), name, timeout)
+}
+
+// resourceAnnotationTemplate is a compiled entry of the argocd-resource-annotations ConfigMap
+type resourceAnnotationTemplate struct {
+	name     string
+	gvk      glob.Glob
+	template *template.Template
+}
+
+// resourceAnnotationTemplates produce the annotations injected into the resources applied by syncs, in the order of
+// the keys of their entries
+type resourceAnnotationTemplates []resourceAnnotationTemplate
+
+// newResourceAnnotationTemplates compiles the entries of the argocd-resource-annotations ConfigMap. Entries whose GVK
+// pattern or template does not compile are logged and left out.
+func newResourceAnnotationTemplates(entries []settings.ResourceAnnotationTemplate) resourceAnnotationTemplates {
+	templates := make(resourceAnnotationTemplates, 0, len(entries))
+	for _, entry := range entries {
+		gvk, err := glob.Compile(entry.GVK)
+		if err != nil {
+			log.Warnf("Ignoring entry %s of %s with invalid GVK pattern %q: %v", entry.Name, common.ArgoCDResourceAnnotationsConfigMapName, entry.GVK, err)
+			continue
+		}
+		tmpl, err := template.New(entry.Name).Option("missingkey=error").Parse(entry.Template)
+		if err != nil {
+			log.Warnf("Ignoring entry %s of %s with invalid template: %v", entry.Name, common.ArgoCDResourceAnnotationsConfigMapName, err)
+			continue
+		}
+		templates = append(templates, resourceAnnotationTemplate{name: entry.Name, gvk: gvk, template: tmpl})
+	}
+	return templates
+}
+
+// resourceAnnotationContext returns the data the templates are executed with for the resources of the application
+func resourceAnnotationContext(app *v1alpha1.Application, cluster string) map[string]string {
+	return map[string]string{
+		"appName":   app.Name,
+		"project":   app.Spec.GetProject(),
+		"cluster":   cluster,
+		"namespace": app.Spec.Destination.Namespace,
+	}
+}
+
+// annotations returns the annotations produced by the templates matching the API version and kind of obj. The
+// annotations of later templates take precedence over those of earlier ones.
+func (t resourceAnnotationTemplates) annotations(obj *unstructured.Unstructured, data map[string]string) (map[string]string, error) {
+	gvk := obj.GetAPIVersion() + "/" + obj.GetKind()
+	var annotations map[string]string
+	for _, tmpl := range t {
+		if !tmpl.gvk.Match(gvk) {
+			continue
+		}
+		var out strings.Builder
+		if err := tmpl.template.Execute(&out, data); err != nil {
+			return nil, fmt.Errorf("error executing resource annotation template %s: %w", tmpl.name, err)
+		}
+		var produced map[string]string
+		if err := yaml.Unmarshal([]byte(out.String()), &produced); err != nil {
+			return nil, fmt.Errorf("resource annotation template %s did not produce a map of annotations: %w", tmpl.name, err)
+		}
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		for k, v := range produced {
+			annotations[k] = v
+		}
+	}
+	return annotations, nil
+}
+
+// annotate returns a copy of obj with the annotations produced by the templates, or obj itself if no template matches
+// it. The annotations of obj take precedence over the produced annotations.
+func (t resourceAnnotationTemplates) annotate(obj *unstructured.Unstructured, data map[string]string) (*unstructured.Unstructured, error) {
+	if obj == nil || len(t) == 0 {
+		return obj, nil
+	}
+	produced, err := t.annotations(obj, data)
+	if err != nil {
+		return nil, fmt.Errorf("error annotating %s %s/%s: %w", obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName(), err)
+	}
+	if len(produced) == 0 {
+		return obj, nil
+	}
+	obj = obj.DeepCopy()
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	for k, v := range produced {
+		if _, ok := annotations[k]; !ok {
+			annotations[k] = v
+		}
+	}
+	obj.SetAnnotations(annotations)
+	return obj, nil
+}
+
+// annotateAll returns the objects annotated by annotate. Nil objects, i.e. missing target states, are kept.
+func (t resourceAnnotationTemplates) annotateAll(objs []*unstructured.Unstructured, data map[string]string) ([]*unstructured.Unstructured, error) {
+	annotated := make([]*unstructured.Unstructured, len(objs))
+	for i, obj := range objs {
+		var err error
+		if annotated[i], err = t.annotate(obj, data); err != nil {
+			return nil, err
+		}
+	}
+	return annotated, nil
+}
+
+// annotationTemplateKubectl is a Kubectl whose resource operations inject the annotations produced by resource
+// annotation templates into the resources before they are applied
+type annotationTemplateKubectl struct {
+	kube.Kubectl
+	templates resourceAnnotationTemplates
+	// data is the data the templates are executed with
+	data map[string]string
+}
+
+func (k *annotationTemplateKubectl) ManageResources(config *rest.Config, openAPISchema openapi.Resources) (kube.ResourceOperations, func(), error) {
+	resourceOps, cleanup, err := k.Kubectl.ManageResources(config, openAPISchema)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &annotationTemplateResourceOperations{ResourceOperations: resourceOps, templates: k.templates, data: k.data}, cleanup, nil
+}
+
+// annotationTemplateResourceOperations injects the annotations produced by resource annotation templates into the
+// resources which are applied, replaced or created
+type annotationTemplateResourceOperations struct {
+	kube.ResourceOperations
+	templates resourceAnnotationTemplates
+	data      map[string]string
+}
+
+func (o *annotationTemplateResourceOperations) ApplyResource(ctx context.Context, obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, force, validate, serverSideApply bool, manager string, serverSideDiff bool) (string, error) {
+	obj, err := o.templates.annotate(obj, o.data)
+	if err != nil {
+		return "", err
+	}
+	return o.ResourceOperations.ApplyResource(ctx, obj, dryRunStrategy, force, validate, serverSideApply, manager, serverSideDiff)
+}
+
+func (o *annotationTemplateResourceOperations) ReplaceResource(ctx context.Context, obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, force bool) (string, error) {
+	obj, err := o.templates.annotate(obj, o.data)
+	if err != nil {
+		return "", err
+	}
+	return o.ResourceOperations.ReplaceResource(ctx, obj, dryRunStrategy, force)
+}
+
+func (o *annotationTemplateResourceOperations) CreateResource(ctx context.Context, obj *unstructured.Unstructured, dryRunStrategy cmdutil.DryRunStrategy, validate bool) (string, error) {
+	obj, err := o.templates.annotate(obj, o.data)
+	if err != nil {
+		return "", err
+	}
+	return o.ResourceOperations.CreateResource(ctx, obj, dryRunStrategy, validate)
+}
@@ -0,0 +1,664 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/time/rate"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	kubetesting "k8s.io/client-go/testing"
+	cmdutil "k8s.io/kubectl/pkg/cmd/util"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+func newSyncTaskResource(apiVersion, kind, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace("default")
+	return obj
+}
+
+func newTestServerSideApplier(objs ...runtime.Object) (*ServerSideApplier, *dynamicfake.FakeDynamicClient) {
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objs...)
+	return NewServerSideApplier(client, "argocd-controller", func(gvk schema.GroupVersionKind) (*metav1.APIResource, error) {
+		return &metav1.APIResource{Name: "configmaps", Namespaced: true}, nil
+	}), client
+}
+
+// conflictOnFirstApply makes the first apply request fail with a field manager conflict
+func conflictOnFirstApply(client *dynamicfake.FakeDynamicClient) *[]types.PatchType {
+	var patches []types.PatchType
+	client.PrependReactor("patch", "configmaps", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		patch := action.(kubetesting.PatchAction)
+		patches = append(patches, patch.GetPatchType())
+		if len(patches) == 1 {
+			return true, nil, apierrors.NewApplyConflict([]metav1.StatusCause{{
+				Type:    metav1.CauseTypeFieldManagerConflict,
+				Field:   ".data.key",
+				Message: `conflict with "kubectl-edit" using v1: .data.key`,
+			}}, "Apply failed with 1 conflict")
+		}
+		return true, &unstructured.Unstructured{Object: map[string]interface{}{}}, nil
+	})
+	return &patches
+}
+
+func TestServerSideApplier_Apply(t *testing.T) {
+	obj := newSyncTaskResource("v1", "ConfigMap", "cm")
+
+	t.Run("NoConflict", func(t *testing.T) {
+		applier, client := newTestServerSideApplier()
+		client.PrependReactor("patch", "configmaps", func(action kubetesting.Action) (bool, runtime.Object, error) {
+			assert.Equal(t, types.ApplyPatchType, action.(kubetesting.PatchAction).GetPatchType())
+			return true, &unstructured.Unstructured{Object: map[string]interface{}{}}, nil
+		})
+		conflicts, err := applier.Apply(context.Background(), obj, false, false)
+		require.NoError(t, err)
+		assert.Empty(t, conflicts)
+	})
+
+	t.Run("ConflictFails", func(t *testing.T) {
+		applier, client := newTestServerSideApplier()
+		patches := conflictOnFirstApply(client)
+		conflicts, err := applier.Apply(context.Background(), obj, true, false)
+		require.Error(t, err)
+		assert.True(t, apierrors.IsConflict(err))
+		assert.Equal(t, []FieldManagerConflict{{Field: ".data.key", Message: `conflict with "kubectl-edit" using v1: .data.key`}}, conflicts)
+		assert.Len(t, *patches, 1)
+	})
+
+	t.Run("ConflictForced", func(t *testing.T) {
+		applier, client := newTestServerSideApplier()
+		patches := conflictOnFirstApply(client)
+		conflicts, err := applier.Apply(context.Background(), obj, false, true)
+		require.NoError(t, err)
+		assert.Len(t, conflicts, 1)
+		assert.Equal(t, []types.PatchType{types.ApplyPatchType, types.ApplyPatchType}, *patches)
+	})
+}
+
+func TestServerSideApplier_MigrateManagedFields(t *testing.T) {
+	live := newSyncTaskResource("v1", "ConfigMap", "cm")
+	live.SetResourceVersion("1")
+	live.SetManagedFields([]metav1.ManagedFieldsEntry{{
+		Manager:    "kubectl-client-side-apply",
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		APIVersion: "v1",
+		FieldsType: "FieldsV1",
+		FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:data":{"f:key":{}}}`)},
+	}})
+	applier, client := newTestServerSideApplier(live)
+
+	migrated, err := applier.MigrateManagedFields(context.Background(), live, clientSideApplyManagers...)
+	require.NoError(t, err)
+	assert.True(t, migrated)
+
+	updated, err := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}).Namespace("default").Get(context.Background(), "cm", metav1.GetOptions{})
+	require.NoError(t, err)
+	managedFields := updated.GetManagedFields()
+	require.Len(t, managedFields, 1)
+	assert.Equal(t, "argocd-controller", managedFields[0].Manager)
+	assert.Equal(t, metav1.ManagedFieldsOperationApply, managedFields[0].Operation)
+
+	// the fields are already owned by the server-side apply manager
+	migrated, err = applier.MigrateManagedFields(context.Background(), updated, clientSideApplyManagers...)
+	require.NoError(t, err)
+	assert.False(t, migrated)
+
+	missing := newSyncTaskResource("v1", "ConfigMap", "missing")
+	migrated, err = applier.MigrateManagedFields(context.Background(), missing, clientSideApplyManagers...)
+	require.NoError(t, err)
+	assert.False(t, migrated)
+}
+
+// bulkApplyOps records the resources client-side applied by kubectl, by name
+type bulkApplyOps struct {
+	kube.ResourceOperations
+	lock     sync.Mutex
+	applied  []string
+	failures map[string]error
+}
+
+func (o *bulkApplyOps) ApplyResource(_ context.Context, obj *unstructured.Unstructured, _ cmdutil.DryRunStrategy, _, _, serverSideApply bool, _ string, _ bool) (string, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.applied = append(o.applied, fmt.Sprintf("%s:%v", obj.GetName(), serverSideApply))
+	if err, ok := o.failures[obj.GetName()]; ok {
+		return "", err
+	}
+	return fmt.Sprintf("%s configured", obj.GetName()), nil
+}
+
+var bulkApplyAPIResources = map[schema.GroupVersionKind]metav1.APIResource{
+	{Version: "v1", Kind: "ConfigMap"}:                          {Name: "configmaps", Namespaced: true, Verbs: metav1.Verbs{"get", "patch"}},
+	{Group: "apps", Version: "v1", Kind: "Deployment"}:          {Name: "deployments", Namespaced: true, Verbs: metav1.Verbs{"get", "patch"}},
+	{Group: "example.com", Version: "v1", Kind: "Legacy"}:       {Name: "legacies", Namespaced: true, Verbs: metav1.Verbs{"get", "update"}},
+	{Group: "metrics.example.com", Version: "v1", Kind: "Node"}: {Name: "nodes", Verbs: metav1.Verbs{"get", "patch"}},
+}
+
+func bulkApplyResourceFor(gvk schema.GroupVersionKind) (*metav1.APIResource, error) {
+	res, ok := bulkApplyAPIResources[gvk]
+	if !ok {
+		return nil, fmt.Errorf("the server could not find the requested resource")
+	}
+	return &res, nil
+}
+
+// recordApplyPatches records the server-side apply requests by name, and fails the requests of the given names
+func recordApplyPatches(client *dynamicfake.FakeDynamicClient, failures map[string]error) func() map[string]kubetesting.PatchAction {
+	var lock sync.Mutex
+	patches := map[string]kubetesting.PatchAction{}
+	client.PrependReactor("patch", "*", func(action kubetesting.Action) (bool, runtime.Object, error) {
+		patch := action.(kubetesting.PatchAction)
+		lock.Lock()
+		defer lock.Unlock()
+		patches[patch.GetName()] = patch
+		if err, ok := failures[patch.GetName()]; ok {
+			return true, nil, err
+		}
+		return true, &unstructured.Unstructured{Object: map[string]interface{}{}}, nil
+	})
+	return func() map[string]kubetesting.PatchAction {
+		lock.Lock()
+		defer lock.Unlock()
+		return patches
+	}
+}
+
+func newTestBulkApplier(ops kube.ResourceOperations, parallelism int, failures map[string]error) (*BulkApplier, func() map[string]kubetesting.PatchAction) {
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	patches := recordApplyPatches(client, failures)
+	return NewBulkApplier(NewServerSideApplier(client, "argocd-controller", bulkApplyResourceFor), ops, parallelism, false, true), patches
+}
+
+func TestBulkApplier_BulkApply(t *testing.T) {
+	ops := &bulkApplyOps{failures: map[string]error{"legacy-2": fmt.Errorf("invalid")}}
+	unsupported := &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   http.StatusUnsupportedMediaType,
+		Reason: metav1.StatusReasonUnsupportedMediaType,
+	}}
+	applier, patches := newTestBulkApplier(ops, 4, map[string]error{
+		"cm-2":   apierrors.NewBadRequest("invalid"),
+		"node-1": unsupported,
+	})
+
+	inSync := ResourceWithPatch{Target: newSyncTaskResource("v1", "ConfigMap", "in-sync"), Patch: []byte("{}")}
+	outOfSync := ResourceWithPatch{Target: newSyncTaskResource("v1", "ConfigMap", "out-of-sync"), Patch: []byte(`{"data":{"foo":"bar"}}`)}
+	resources := []ResourceWithPatch{
+		{Target: newSyncTaskResource("v1", "ConfigMap", "cm-1")},
+		{Target: newSyncTaskResource("apps/v1", "Deployment", "deploy-1")},
+		{Target: newSyncTaskResource("example.com/v1", "Legacy", "legacy-1")},
+		{Target: newSyncTaskResource("v1", "ConfigMap", "cm-2")},
+		{},
+		{Target: newSyncTaskResource("example.com/v1", "Legacy", "legacy-2")},
+		inSync,
+		outOfSync,
+		{Target: newSyncTaskResource("metrics.example.com/v1", "Node", "node-1")},
+	}
+	errs := applier.BulkApply(context.Background(), resources)
+
+	require.Len(t, errs, len(resources))
+	for i, err := range errs {
+		switch i {
+		case 3:
+			assert.ErrorContains(t, err, "error applying /v1, Kind=ConfigMap default/cm-2: invalid")
+		case 4:
+			assert.ErrorContains(t, err, "no target state")
+		case 5:
+			assert.ErrorContains(t, err, "error applying example.com/v1, Kind=Legacy default/legacy-2: invalid")
+		default:
+			assert.NoError(t, err)
+		}
+	}
+	// the resources which support server-side apply are applied with a single request each
+	applied := patches()
+	assert.ElementsMatch(t, []string{"cm-1", "cm-2", "deploy-1", "out-of-sync", "node-1"}, keys(applied))
+	// the other resources are client-side applied one by one, in order, as well as the resources whose API does not
+	// support apply requests
+	assert.Equal(t, []string{"legacy-1:false", "legacy-2:false", "node-1:false"}, ops.applied)
+
+	patch := applied["cm-1"]
+	assert.Equal(t, types.ApplyPatchType, patch.GetPatchType())
+	assert.JSONEq(t, `{
+		"apiVersion": "v1",
+		"kind": "ConfigMap",
+		"metadata": {
+			"name": "cm-1",
+			"namespace": "default",
+			"annotations": {
+				"kubectl.kubernetes.io/last-applied-configuration": "{\"apiVersion\":\"v1\",\"kind\":\"ConfigMap\",\"metadata\":{\"name\":\"cm-1\",\"namespace\":\"default\"}}"
+			}
+		}
+	}`, string(patch.GetPatch()))
+	// the target state of the caller is left untouched
+	assert.Empty(t, resources[0].Target.GetAnnotations())
+}
+
+func TestBulkApplier_Empty(t *testing.T) {
+	applier, patches := newTestBulkApplier(&bulkApplyOps{}, 0, nil)
+	assert.Empty(t, applier.BulkApply(context.Background(), nil))
+	assert.Empty(t, patches())
+}
+
+func TestBulkApplyResourceOperations(t *testing.T) {
+	ops := &bulkApplyOps{}
+	client := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	patches := recordApplyPatches(client, map[string]error{"cm-2": apierrors.NewBadRequest("invalid")})
+	resourceOps := newBulkApplyResourceOperations(ops, client, bulkApplyResourceFor, 50*time.Millisecond)
+
+	// the resources of a sync wave are applied concurrently
+	type result struct {
+		message string
+		err     error
+	}
+	names := []string{"cm-1", "cm-2", "cm-3"}
+	results := make([]result, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			message, err := resourceOps.ApplyResource(context.Background(), newSyncTaskResource("v1", "ConfigMap", name), cmdutil.DryRunNone, false, true, true, "argocd-controller", false)
+			results[i] = result{message: message, err: err}
+		}(i, name)
+	}
+	wg.Wait()
+
+	assert.Equal(t, result{message: "configmap/cm-1 serverside-applied"}, results[0])
+	assert.ErrorContains(t, results[1].err, "invalid")
+	assert.Equal(t, result{message: "configmap/cm-3 serverside-applied"}, results[2])
+	assert.ElementsMatch(t, names, keys(patches()))
+	assert.Empty(t, resourceOps.batches)
+
+	// client-side applies and dry runs are left to kubectl
+	message, err := resourceOps.ApplyResource(context.Background(), newSyncTaskResource("apps/v1", "Deployment", "deploy-1"), cmdutil.DryRunNone, false, true, false, "argocd-controller", false)
+	require.NoError(t, err)
+	assert.Equal(t, "deploy-1 configured", message)
+	_, err = resourceOps.ApplyResource(context.Background(), newSyncTaskResource("apps/v1", "Deployment", "deploy-2"), cmdutil.DryRunClient, false, true, true, "argocd-controller", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"deploy-1:false", "deploy-2:true"}, ops.applied)
+	assert.Len(t, patches(), len(names))
+}
+
+func keys[V any](m map[string]V) []string {
+	var res []string
+	for k := range m {
+		res = append(res, k)
+	}
+	return res
+}
+
+// BenchmarkBulkApply measures applying a synthetic application of 200 resources of 2 kinds to an API server taking a
+// millisecond to answer each request, one by one and in bulk
+func BenchmarkBulkApply(b *testing.B) {
+	server := httptest.NewServer(&slowAPIServer{latency: time.Millisecond})
+	defer server.Close()
+	client, err := dynamic.NewForConfig(&rest.Config{Host: server.URL, QPS: 1000, Burst: 1000})
+	require.NoError(b, err)
+
+	kinds := []struct{ apiVersion, kind string }{
+		{"v1", "ConfigMap"},
+		{"apps/v1", "Deployment"},
+	}
+	resources := make([]ResourceWithPatch, 200)
+	for i := range resources {
+		k := kinds[i%len(kinds)]
+		resources[i] = ResourceWithPatch{Target: newSyncTaskResource(k.apiVersion, k.kind, fmt.Sprintf("resource-%d", i))}
+	}
+	for _, bm := range []struct {
+		name        string
+		parallelism int
+	}{
+		{"Serial", 1},
+		{"Bulk", bulkApplyParallelism},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			applier := NewBulkApplier(NewServerSideApplier(client, "argocd-controller", bulkApplyResourceFor), &bulkApplyOps{}, bm.parallelism, false, true)
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				for _, err := range applier.BulkApply(context.Background(), resources) {
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestApplyRateLimiters(t *testing.T) {
+	limiters := newApplyRateLimiters(0)
+	appLimiters, err := limiters.get("https://cluster-1", nil)
+	require.NoError(t, err)
+	assert.Empty(t, appLimiters, "applies are not limited without limits")
+
+	limit := &v1alpha1.ApplyRateLimit{RequestsPerSecond: "2.5"}
+	appLimiters, err = limiters.get("https://cluster-1", limit)
+	require.NoError(t, err)
+	require.Len(t, appLimiters, 1)
+	assert.Equal(t, rate.Limit(2.5), appLimiters[0].Limit())
+	assert.Equal(t, 3, appLimiters[0].Burst())
+
+	other, err := limiters.get("https://cluster-1", limit)
+	require.NoError(t, err)
+	require.Len(t, other, 1)
+	assert.NotSame(t, appLimiters[0], other[0], "the limit of an application is not shared")
+
+	for _, invalid := range []*v1alpha1.ApplyRateLimit{{RequestsPerSecond: ""}, {RequestsPerSecond: "0"}, {RequestsPerSecond: "1e-9"}, {RequestsPerSecond: "NaN"}, {RequestsPerSecond: "fast"}, {RequestsPerSecond: "1", Burst: -1}} {
+		_, err = limiters.get("https://cluster-1", invalid)
+		require.ErrorContains(t, err, "invalid apply rate limit")
+	}
+
+	var nilLimiters *applyRateLimiters
+	appLimiters, err = nilLimiters.get("https://cluster-1", limit)
+	require.NoError(t, err)
+	assert.Empty(t, appLimiters)
+}
+
+func TestApplyRateLimiters_DefaultLimit(t *testing.T) {
+	limiters := newApplyRateLimiters(10)
+	app1, err := limiters.get("https://cluster-1", nil)
+	require.NoError(t, err)
+	require.Len(t, app1, 1)
+	assert.Equal(t, rate.Limit(10), app1[0].Limit())
+	assert.Equal(t, 10, app1[0].Burst())
+
+	// the default limit is shared by the applications syncing to the same cluster, in addition to their own limit
+	app2, err := limiters.get("https://cluster-1", &v1alpha1.ApplyRateLimit{RequestsPerSecond: "0.1"})
+	require.NoError(t, err)
+	require.Len(t, app2, 2)
+	assert.Equal(t, rate.Limit(0.1), app2[0].Limit())
+	assert.Same(t, app1[0], app2[1])
+
+	other, err := limiters.get("https://cluster-2", nil)
+	require.NoError(t, err)
+	require.Len(t, other, 1)
+	assert.NotSame(t, app1[0], other[0])
+
+	// the restrictive limit of an application does not delay the other applications syncing to the cluster
+	for i := 0; i < 10; i++ {
+		assert.True(t, app1[0].Allow())
+	}
+	assert.False(t, app1[0].Allow(), "the burst is shared by the applications")
+	assert.Equal(t, rate.Limit(10), app1[0].Limit())
+}
+
+// slowAPIServer is a Kubernetes API server answering each request after a delay, and recording the requests
+type slowAPIServer struct {
+	latency time.Duration
+	lock    sync.Mutex
+	// requests are the method and path of the received requests, in order
+	requests []string
+	// received are the times at which the requests were received
+	received []time.Time
+}
+
+func (s *slowAPIServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.lock.Lock()
+	s.requests = append(s.requests, req.Method+" "+req.URL.Path)
+	s.received = append(s.received, time.Now())
+	s.lock.Unlock()
+	time.Sleep(s.latency)
+	name := path.Base(req.URL.Path)
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = fmt.Fprintf(w, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":%q,"namespace":"default"}}`, name)
+}
+
+func TestApplyRateLimit_SlowAPIServer(t *testing.T) {
+	apiServer := &slowAPIServer{latency: 10 * time.Millisecond}
+	server := httptest.NewServer(apiServer)
+	defer server.Close()
+
+	limiters, err := newApplyRateLimiters(0).get(server.URL, &v1alpha1.ApplyRateLimit{RequestsPerSecond: "20", Burst: 2})
+	require.NoError(t, err)
+	throttled := 0
+	config := withApplyRateLimit(&rest.Config{Host: server.URL}, limiters, func() {
+		throttled++
+	})
+	client, err := dynamic.NewForConfig(config)
+	require.NoError(t, err)
+	applier := NewServerSideApplier(client, "argocd-controller", func(gvk schema.GroupVersionKind) (*metav1.APIResource, error) {
+		return &metav1.APIResource{Name: "configmaps", Namespaced: true, Kind: gvk.Kind, Version: gvk.Version}, nil
+	})
+
+	start := time.Now()
+	var expected []string
+	for i := 0; i < 6; i++ {
+		name := fmt.Sprintf("cm-%d", i)
+		_, err := applier.Apply(context.Background(), newSyncTaskResource("v1", "ConfigMap", name), false, false)
+		require.NoError(t, err)
+		expected = append(expected, "PATCH /api/v1/namespaces/default/configmaps/"+name)
+	}
+
+	assert.Equal(t, expected, apiServer.requests, "resources are applied in order")
+	// the burst of 2 requests is sent at once, then the bucket is refilled with a request every 50ms
+	assert.Equal(t, 4, throttled)
+	assert.Less(t, apiServer.received[1].Sub(start), 45*time.Millisecond)
+	for i := 2; i < len(apiServer.received); i++ {
+		assert.GreaterOrEqual(t, apiServer.received[i].Sub(start), time.Duration(i-1)*50*time.Millisecond-5*time.Millisecond)
+	}
+
+	// reads are not limited
+	start = time.Now()
+	for i := 0; i < 5; i++ {
+		_, err := client.Resource(schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}).Namespace("default").Get(context.Background(), "cm-0", metav1.GetOptions{})
+		require.NoError(t, err)
+	}
+	assert.Less(t, time.Since(start), 200*time.Millisecond)
+	assert.Equal(t, 4, throttled)
+
+	// delayed requests are canceled with their context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	limiters[0].SetLimit(rate.Every(time.Hour))
+	limiters[0].ReserveN(time.Now(), limiters[0].Burst())
+	_, err = applier.Apply(ctx, newSyncTaskResource("v1", "ConfigMap", "canceled"), false, false)
+	require.Error(t, err)
+	assert.NotContains(t, apiServer.requests, "PATCH /api/v1/namespaces/default/configmaps/canceled")
+}
+
+// slowApplyOps applies resources after a delay which depends on their kind, or until the context is done
+type slowApplyOps struct {
+	kube.ResourceOperations
+	delays map[string]time.Duration
+}
+
+func (o *slowApplyOps) ApplyResource(ctx context.Context, obj *unstructured.Unstructured, _ cmdutil.DryRunStrategy, _, _, _ bool, _ string, _ bool) (string, error) {
+	select {
+	case <-time.After(o.delays[obj.GetKind()]):
+		return fmt.Sprintf("%s/%s configured", obj.GetKind(), obj.GetName()), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func TestApplyTimeoutResourceOperations(t *testing.T) {
+	crd := &unstructured.Unstructured{}
+	crd.SetAPIVersion("apiextensions.k8s.io/v1")
+	crd.SetKind("CustomResourceDefinition")
+	crd.SetName("widgets.example.com")
+	configMap := &unstructured.Unstructured{}
+	configMap.SetAPIVersion("v1")
+	configMap.SetKind("ConfigMap")
+	configMap.SetNamespace("default")
+	configMap.SetName("my-config")
+
+	var timedOut []string
+	ops := &applyTimeoutResourceOperations{
+		ResourceOperations: &slowApplyOps{delays: map[string]time.Duration{"CustomResourceDefinition": time.Minute}},
+		timeout: func(gk schema.GroupKind) time.Duration {
+			if gk.Kind == "CustomResourceDefinition" {
+				return 10 * time.Millisecond
+			}
+			return time.Minute
+		},
+		onTimeout: func(obj *unstructured.Unstructured, timeout time.Duration) {
+			timedOut = append(timedOut, obj.GetName())
+		},
+	}
+
+	_, err := ops.ApplyResource(context.Background(), crd, cmdutil.DryRunNone, false, false, false, "", false)
+	require.EqualError(t, err, "ApplyTimeout: apply of CustomResourceDefinition widgets.example.com did not complete within 10ms")
+	assert.Equal(t, []string{"widgets.example.com"}, timedOut)
+
+	message, err := ops.ApplyResource(context.Background(), configMap, cmdutil.DryRunNone, false, false, false, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "ConfigMap/my-config configured", message)
+	assert.Len(t, timedOut, 1)
+
+	// resources without apply timeout are applied however long it takes
+	ops.timeout = func(schema.GroupKind) time.Duration {
+		return 0
+	}
+	ops.ResourceOperations = &slowApplyOps{delays: map[string]time.Duration{"CustomResourceDefinition": 20 * time.Millisecond}}
+	_, err = ops.ApplyResource(context.Background(), crd, cmdutil.DryRunNone, false, false, false, "", false)
+	require.NoError(t, err)
+}
+
+// annotationRecordingOps records the resources which are applied, replaced or created, including dry runs
+type annotationRecordingOps struct {
+	kube.ResourceOperations
+	lock    sync.Mutex
+	applied []*unstructured.Unstructured
+}
+
+func (o *annotationRecordingOps) record(obj *unstructured.Unstructured) (string, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.applied = append(o.applied, obj)
+	return fmt.Sprintf("%s/%s configured", obj.GetKind(), obj.GetName()), nil
+}
+
+func (o *annotationRecordingOps) ApplyResource(_ context.Context, obj *unstructured.Unstructured, _ cmdutil.DryRunStrategy, _, _, _ bool, _ string, _ bool) (string, error) {
+	return o.record(obj)
+}
+
+func (o *annotationRecordingOps) ReplaceResource(_ context.Context, obj *unstructured.Unstructured, _ cmdutil.DryRunStrategy, _ bool) (string, error) {
+	return o.record(obj)
+}
+
+func (o *annotationRecordingOps) CreateResource(_ context.Context, obj *unstructured.Unstructured, _ cmdutil.DryRunStrategy, _ bool) (string, error) {
+	return o.record(obj)
+}
+
+// appliedAnnotations returns the annotations of the applied resources of the given kind
+func (o *annotationRecordingOps) appliedAnnotations(kind string) []map[string]string {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	var annotations []map[string]string
+	for _, obj := range o.applied {
+		if obj.GetKind() == kind {
+			annotations = append(annotations, obj.GetAnnotations())
+		}
+	}
+	return annotations
+}
+
+func TestResourceAnnotationTemplates(t *testing.T) {
+	templates := newResourceAnnotationTemplates([]settings.ResourceAnnotationTemplate{
+		{Name: "cost-center", GVK: "*", Template: "cost-center: cc-{{ .project }}\nowner: {{ .appName }}"},
+		{Name: "deployments", GVK: "apps/*/Deployment", Template: "owner: {{ .appName }}-team\nplacement: {{ .cluster }}/{{ .namespace }}"},
+		{Name: "invalid-gvk", GVK: "[", Template: "a: b"},
+		{Name: "invalid-template", GVK: "*", Template: "a: {{ .appName"},
+	})
+	require.Len(t, templates, 2)
+	app := newFakeApp()
+	app.Name = "guestbook"
+	app.Spec.Project = "payments"
+	app.Spec.Destination.Namespace = "shop"
+	data := resourceAnnotationContext(app, "in-cluster")
+
+	t.Run("DynamicAnnotations", func(t *testing.T) {
+		deployment := newSyncTaskResource("apps/v1", "Deployment", "web")
+		annotated, err := templates.annotate(deployment, data)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"cost-center": "cc-payments",
+			"owner":       "guestbook-team",
+			"placement":   "in-cluster/shop",
+		}, annotated.GetAnnotations())
+		assert.Empty(t, deployment.GetAnnotations(), "the resource must not be modified")
+
+		configMap := newSyncTaskResource("v1", "ConfigMap", "config")
+		annotated, err = templates.annotate(configMap, data)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"cost-center": "cc-payments", "owner": "guestbook"}, annotated.GetAnnotations())
+	})
+
+	t.Run("ExistingAnnotationsTakePrecedence", func(t *testing.T) {
+		configMap := newSyncTaskResource("v1", "ConfigMap", "config")
+		configMap.SetAnnotations(map[string]string{"cost-center": "cc-shared", "note": "kept"})
+		annotated, err := templates.annotate(configMap, data)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"cost-center": "cc-shared", "note": "kept", "owner": "guestbook"}, annotated.GetAnnotations())
+	})
+
+	t.Run("NoMatchingTemplate", func(t *testing.T) {
+		configMap := newSyncTaskResource("v1", "ConfigMap", "config")
+		annotated, err := templates[1:].annotate(configMap, data)
+		require.NoError(t, err)
+		assert.Same(t, configMap, annotated)
+
+		annotated, err = templates.annotate(nil, data)
+		require.NoError(t, err)
+		assert.Nil(t, annotated)
+	})
+
+	t.Run("InvalidOutput", func(t *testing.T) {
+		configMap := newSyncTaskResource("v1", "ConfigMap", "config")
+		_, err := newResourceAnnotationTemplates([]settings.ResourceAnnotationTemplate{
+			{Name: "unknown-key", GVK: "*", Template: "region: {{ .region }}"},
+		}).annotate(configMap, data)
+		require.ErrorContains(t, err, "error executing resource annotation template unknown-key")
+
+		_, err = newResourceAnnotationTemplates([]settings.ResourceAnnotationTemplate{
+			{Name: "list", GVK: "*", Template: "- {{ .appName }}"},
+		}).annotate(configMap, data)
+		require.ErrorContains(t, err, "resource annotation template list did not produce a map of annotations")
+	})
+}
+
+func TestAnnotationTemplateResourceOperations(t *testing.T) {
+	recorder := &annotationRecordingOps{}
+	ops := &annotationTemplateResourceOperations{
+		ResourceOperations: recorder,
+		templates: newResourceAnnotationTemplates([]settings.ResourceAnnotationTemplate{
+			{Name: "cost-center", GVK: "v1/ConfigMap", Template: "cost-center: {{ .project }}"},
+		}),
+		data: map[string]string{"project": "payments"},
+	}
+
+	_, err := ops.ApplyResource(context.Background(), newSyncTaskResource("v1", "ConfigMap", "applied"), cmdutil.DryRunNone, false, false, false, "", false)
+	require.NoError(t, err)
+	_, err = ops.ReplaceResource(context.Background(), newSyncTaskResource("v1", "ConfigMap", "replaced"), cmdutil.DryRunNone, false)
+	require.NoError(t, err)
+	_, err = ops.CreateResource(context.Background(), newSyncTaskResource("v1", "ConfigMap", "created"), cmdutil.DryRunNone, false)
+	require.NoError(t, err)
+	_, err = ops.ApplyResource(context.Background(), newSyncTaskResource("v1", "Secret", "secret"), cmdutil.DryRunNone, false, false, false, "", false)
+	require.NoError(t, err)
+
+	assert.Equal(t, []map[string]string{{"cost-center": "payments"}, {"cost-center": "payments"}, {"cost-center": "payments"}}, recorder.appliedAnnotations("ConfigMap"))
+	assert.Equal(t, []map[string]string{nil}, recorder.appliedAnnotations("Secret"))
+}
@@ -2,23 +2,36 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	gosync "sync"
 	"testing"
+	"time"
 
 	"github.com/argoproj/gitops-engine/pkg/sync"
 	"github.com/argoproj/gitops-engine/pkg/sync/common"
 	"github.com/argoproj/gitops-engine/pkg/utils/kube"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
+	cdcommon "github.com/argoproj/argo-cd/v2/common"
 	"github.com/argoproj/argo-cd/v2/controller/testdata"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
 	"github.com/argoproj/argo-cd/v2/test"
+	"github.com/argoproj/argo-cd/v2/util/argo"
 	"github.com/argoproj/argo-cd/v2/util/argo/diff"
 	"github.com/argoproj/argo-cd/v2/util/argo/normalizers"
+	"github.com/argoproj/argo-cd/v2/util/attestation"
 )
 
 func TestPersistRevisionHistory(t *testing.T) {
@@ -255,6 +268,438 @@ func TestAppStateManager_SyncAppState(t *testing.T) {
 	})
 }
 
+func TestSyncAppState_SyncTimeout(t *testing.T) {
+	syncApp := func(app *v1alpha1.Application, globalSyncTimeout time.Duration, startedAt time.Time) *v1alpha1.OperationState {
+		data := fakeData{
+			apps: []runtime.Object{app, &defaultProj},
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: []string{},
+				Namespace: test.FakeDestNamespace,
+				Server:    test.FakeClusterURL,
+				Revision:  "abc123",
+			},
+			managedLiveObjs:   make(map[kube.ResourceKey]*unstructured.Unstructured),
+			globalSyncTimeout: globalSyncTimeout,
+		}
+		ctrl := newFakeController(&data, nil)
+		opState := &v1alpha1.OperationState{
+			Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{}},
+			Phase:     common.OperationRunning,
+			StartedAt: v1.NewTime(startedAt),
+		}
+		ctrl.appStateManager.SyncAppState(app, opState)
+		return opState
+	}
+
+	t.Run("ApplicationTimeout", func(t *testing.T) {
+		app := newFakeApp()
+		app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{SyncTimeout: "1m"}
+		opState := syncApp(app, time.Hour, time.Now().Add(-2*time.Minute))
+		assert.Equal(t, common.OperationFailed, opState.Phase)
+		assert.Equal(t, "SyncTimedOut: sync did not complete within 1m0s", opState.Message)
+	})
+
+	t.Run("GlobalTimeout", func(t *testing.T) {
+		opState := syncApp(newFakeApp(), time.Minute, time.Now().Add(-2*time.Minute))
+		assert.Equal(t, common.OperationFailed, opState.Phase)
+		assert.Equal(t, "SyncTimedOut: sync did not complete within 1m0s", opState.Message)
+	})
+
+	t.Run("NotExpired", func(t *testing.T) {
+		app := newFakeApp()
+		app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{SyncTimeout: "5m"}
+		opState := syncApp(app, time.Minute, time.Now().Add(-2*time.Minute))
+		assert.Equal(t, common.OperationSucceeded, opState.Phase)
+	})
+
+	t.Run("InvalidTimeout", func(t *testing.T) {
+		app := newFakeApp()
+		app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{SyncTimeout: "forever"}
+		opState := syncApp(app, 0, time.Now())
+		assert.Equal(t, common.OperationError, opState.Phase)
+		assert.Contains(t, opState.Message, "invalid sync timeout")
+	})
+}
+
+func TestSyncAppState_PreSyncSnapshot(t *testing.T) {
+	app := newFakeApp()
+	app.Status.OperationState = nil
+	app.Status.History = nil
+	live := test.NewDeployment()
+	live.SetNamespace(test.FakeDestNamespace)
+	live.SetResourceVersion("123")
+	manifestResponse := &apiclient.ManifestResponse{
+		Manifests: []string{test.DeploymentManifest},
+		Namespace: test.FakeDestNamespace,
+		Server:    test.FakeClusterURL,
+		Revision:  "abc123",
+	}
+	data := fakeData{
+		apps: []runtime.Object{app, &defaultProj},
+		// the application is synced three times
+		manifestResponses: []*apiclient.ManifestResponse{manifestResponse, manifestResponse, manifestResponse},
+		managedLiveObjs: map[kube.ResourceKey]*unstructured.Unstructured{
+			kube.GetResourceKey(live): live,
+		},
+	}
+	ctrl := newFakeController(&data, nil)
+
+	opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{}}}
+	ctrl.appStateManager.SyncAppState(app, opState)
+
+	require.NotNil(t, opState.SyncResult)
+	syncID := opState.SyncResult.SyncID
+	require.NotEmpty(t, syncID)
+	snapshot, err := ctrl.cache.GetPreSyncSnapshot(app.InstanceName(test.FakeArgoCDNamespace), syncID)
+	require.NoError(t, err)
+	require.Len(t, snapshot.Resources, 1)
+	entry := snapshot.Resources[0]
+	assert.Equal(t, v1alpha1.ResourceRef{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: test.FakeDestNamespace, Name: "nginx-deployment"}, entry.ResourceRef)
+	assert.Equal(t, "123", entry.ResourceVersion)
+	manifest, err := live.MarshalJSON()
+	require.NoError(t, err)
+	assert.JSONEq(t, string(manifest), entry.LiveManifest)
+
+	// the snapshot is taken once, when the sync is resumed it keeps its ID
+	ctrl.appStateManager.SyncAppState(app, opState)
+	assert.Equal(t, syncID, opState.SyncResult.SyncID)
+
+	t.Run("SecretsAreNotStored", func(t *testing.T) {
+		secret := &unstructured.Unstructured{}
+		secret.SetAPIVersion("v1")
+		secret.SetKind(kube.SecretKind)
+		secret.SetName("my-secret")
+		require.NoError(t, unstructured.SetNestedField(secret.Object, "c2VjcmV0", "data", "password"))
+		manager := ctrl.appStateManager.(*appStateManager)
+		require.NoError(t, manager.storePreSyncSnapshot(app, "00001-abcde", []*unstructured.Unstructured{secret, nil, live}, time.Now()))
+		snapshot, err := ctrl.cache.GetPreSyncSnapshot(app.InstanceName(test.FakeArgoCDNamespace), "00001-abcde")
+		require.NoError(t, err)
+		require.Len(t, snapshot.Resources, 1)
+		assert.Equal(t, "Deployment", snapshot.Resources[0].ResourceRef.Kind)
+	})
+
+	t.Run("DryRun", func(t *testing.T) {
+		opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{DryRun: true}}}
+		ctrl.appStateManager.SyncAppState(app, opState)
+		require.NotNil(t, opState.SyncResult)
+		assert.Empty(t, opState.SyncResult.SyncID)
+	})
+}
+
+func TestSyncAppState_BootstrapAppDoesNotSyncItself(t *testing.T) {
+	syncApp := func(bootstrap bool) []string {
+		app := newFakeApp()
+		app.Status.OperationState = nil
+		app.Status.History = nil
+		if bootstrap {
+			app.Labels = map[string]string{cdcommon.LabelKeyBootstrapApp: "true"}
+		}
+		self := fmt.Sprintf(`{"apiVersion":"argoproj.io/v1alpha1","kind":"Application","metadata":{"name":%q,"namespace":%q},"spec":{}}`, app.Name, app.Namespace)
+		data := fakeData{
+			apps: []runtime.Object{app, &defaultProj},
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: []string{self, `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"argocd-cm"}}`},
+				Namespace: test.FakeArgoCDNamespace,
+				Server:    test.FakeClusterURL,
+				Revision:  "abc123",
+			},
+			managedLiveObjs: make(map[kube.ResourceKey]*unstructured.Unstructured),
+		}
+		ctrl := newFakeController(&data, nil)
+		opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{}}}
+		ctrl.appStateManager.SyncAppState(app, opState)
+		require.NotNil(t, opState.SyncResult)
+		var kinds []string
+		for _, res := range opState.SyncResult.Resources {
+			kinds = append(kinds, res.Kind)
+		}
+		return kinds
+	}
+
+	assert.ElementsMatch(t, []string{"Application", "ConfigMap"}, syncApp(false))
+	assert.ElementsMatch(t, []string{"ConfigMap"}, syncApp(true))
+}
+
+// discoveryServer is a Kubernetes API server serving the discovery of config maps and custom resource definitions, and
+// custom resource definitions which are established
+func discoveryServer(onRequest func(req *http.Request)) *httptest.Server {
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if onRequest != nil {
+			onRequest(req)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/api":
+			_, _ = w.Write([]byte(`{"kind":"APIVersions","versions":["v1"]}`))
+		case "/apis":
+			_, _ = w.Write([]byte(`{"kind":"APIGroupList","groups":[{"name":"apiextensions.k8s.io","versions":[{"groupVersion":"apiextensions.k8s.io/v1","version":"v1"}],"preferredVersion":{"groupVersion":"apiextensions.k8s.io/v1","version":"v1"}}]}`))
+		case "/api/v1":
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","groupVersion":"v1","resources":[{"name":"configmaps","namespaced":true,"kind":"ConfigMap","verbs":["get","list","create","update","patch","delete"]}]}`))
+		case "/apis/apiextensions.k8s.io/v1":
+			_, _ = w.Write([]byte(`{"kind":"APIResourceList","groupVersion":"apiextensions.k8s.io/v1","resources":[{"name":"customresourcedefinitions","namespaced":false,"kind":"CustomResourceDefinition","verbs":["get","list","create","update","patch","delete"]}]}`))
+		default:
+			_, _ = fmt.Fprintf(w, `{"apiVersion":"apiextensions.k8s.io/v1","kind":"CustomResourceDefinition","metadata":{"name":%q},"status":{"conditions":[{"type":"Established","status":"True"}]}}`, path.Base(req.URL.Path))
+		}
+	}))
+}
+
+func TestSyncAppState_ApplyTimeout(t *testing.T) {
+	server := discoveryServer(nil)
+	defer server.Close()
+	proj := defaultProj.DeepCopy()
+	proj.Spec.ClusterResourceWhitelist = []v1.GroupKind{{Group: "*", Kind: "*"}}
+
+	syncApp := func(applyTimeouts map[string]v1.Duration, defaultResourceApplyTimeout, crdApplyDuration time.Duration) (*v1alpha1.OperationState, []string) {
+		app := newFakeApp()
+		app.Status.OperationState = nil
+		app.Status.History = nil
+		app.Spec.Destination.Server = server.URL
+		if applyTimeouts != nil {
+			app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{ApplyTimeouts: applyTimeouts}
+		}
+		crd := `{"apiVersion":"apiextensions.k8s.io/v1","kind":"CustomResourceDefinition","metadata":{"name":"widgets.example.com"}}`
+		configMap := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-config","namespace":"` + test.FakeDestNamespace + `"}}`
+		data := fakeData{
+			apps: []runtime.Object{app, proj},
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: []string{crd, configMap},
+				Namespace: test.FakeDestNamespace,
+				Server:    server.URL,
+				Revision:  "abc123",
+			},
+			managedLiveObjs:             make(map[kube.ResourceKey]*unstructured.Unstructured),
+			defaultResourceApplyTimeout: defaultResourceApplyTimeout,
+			resourceOps:                 &slowApplyOps{delays: map[string]time.Duration{"CustomResourceDefinition": crdApplyDuration}},
+			clusterServer:               server.URL,
+		}
+		ctrl := newFakeController(&data, nil)
+		opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{}}}
+		ctrl.appStateManager.SyncAppState(app, opState)
+		events, err := ctrl.kubeClientset.CoreV1().Events(app.Namespace).List(context.Background(), v1.ListOptions{})
+		require.NoError(t, err)
+		var timeoutEvents []string
+		for _, event := range events.Items {
+			if event.Reason == argo.EventReasonApplyTimeout {
+				timeoutEvents = append(timeoutEvents, event.Message)
+			}
+		}
+		return opState, timeoutEvents
+	}
+
+	resultsOf := func(opState *v1alpha1.OperationState) map[string]*v1alpha1.ResourceResult {
+		require.NotNil(t, opState.SyncResult)
+		results := map[string]*v1alpha1.ResourceResult{}
+		for _, res := range opState.SyncResult.Resources {
+			results[res.Kind] = res
+		}
+		return results
+	}
+
+	t.Run("ApplicationTimeout", func(t *testing.T) {
+		opState, events := syncApp(map[string]v1.Duration{
+			"apiextensions.k8s.io/CustomResourceDefinition": {Duration: 10 * time.Millisecond},
+		}, 0, time.Minute)
+		assert.Equal(t, common.OperationFailed, opState.Phase)
+		results := resultsOf(opState)
+		require.Contains(t, results, "CustomResourceDefinition")
+		assert.Equal(t, common.ResultCodeSyncFailed, results["CustomResourceDefinition"].Status)
+		message := results["CustomResourceDefinition"].Message
+		assert.Regexp(t, `^ApplyTimeout: apply of CustomResourceDefinition .*widgets\.example\.com did not complete within 10ms$`, message)
+		assert.Equal(t, []string{message}, events)
+	})
+
+	t.Run("DefaultTimeout", func(t *testing.T) {
+		opState, events := syncApp(nil, 10*time.Millisecond, time.Minute)
+		assert.Equal(t, common.OperationFailed, opState.Phase)
+		results := resultsOf(opState)
+		require.Contains(t, results, "CustomResourceDefinition")
+		assert.Regexp(t, `^ApplyTimeout: apply of CustomResourceDefinition .*widgets\.example\.com did not complete within 10ms$`, results["CustomResourceDefinition"].Message)
+		assert.Len(t, events, 1)
+	})
+
+	t.Run("ApplicationTimeoutOverridesDefault", func(t *testing.T) {
+		opState, events := syncApp(map[string]v1.Duration{
+			"apiextensions.k8s.io/CustomResourceDefinition": {Duration: time.Minute},
+		}, 10*time.Millisecond, 50*time.Millisecond)
+		assert.Equal(t, common.OperationSucceeded, opState.Phase, opState.Message)
+		results := resultsOf(opState)
+		require.Contains(t, results, "CustomResourceDefinition")
+		assert.Equal(t, common.ResultCodeSynced, results["CustomResourceDefinition"].Status)
+		assert.Empty(t, events)
+	})
+}
+
+func TestSyncAppState_DestinationServiceAccount(t *testing.T) {
+	var lock gosync.Mutex
+	impersonatedUsers := map[string]bool{}
+	server := discoveryServer(func(req *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		impersonatedUsers[req.Header.Get("Impersonate-User")] = true
+	})
+	defer server.Close()
+	proj := defaultProj.DeepCopy()
+	proj.Spec.DestinationServiceAccount = "deployer"
+
+	syncApp := func(allowed bool) (*v1alpha1.OperationState, *authorizationv1.SelfSubjectAccessReview) {
+		app := newFakeApp()
+		app.Status.OperationState = nil
+		app.Status.History = nil
+		app.Spec.Destination.Server = server.URL
+		configMap := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-config","namespace":"` + test.FakeDestNamespace + `"}}`
+		data := fakeData{
+			apps: []runtime.Object{app, proj},
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: []string{configMap},
+				Namespace: test.FakeDestNamespace,
+				Server:    server.URL,
+				Revision:  "abc123",
+			},
+			managedLiveObjs: make(map[kube.ResourceKey]*unstructured.Unstructured),
+			resourceOps:     &slowApplyOps{},
+			clusterServer:   server.URL,
+		}
+		ctrl := newFakeController(&data, nil)
+		var review *authorizationv1.SelfSubjectAccessReview
+		ctrl.appStateManager.(*appStateManager).kubeClientForDestination = func(_ *v1alpha1.Application) (kubernetes.Interface, error) {
+			kubeClient := fake.NewSimpleClientset()
+			kubeClient.PrependReactor("create", "selfsubjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+				review = action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview)
+				review.Status.Allowed = allowed
+				return true, review, nil
+			})
+			return kubeClient, nil
+		}
+		opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{}}}
+		ctrl.appStateManager.SyncAppState(app, opState)
+		return opState, review
+	}
+
+	t.Run("Denied", func(t *testing.T) {
+		opState, review := syncApp(false)
+		assert.Equal(t, common.OperationFailed, opState.Phase)
+		assert.Contains(t, opState.Message, "not permitted to impersonate service account fake-dest-ns/deployer")
+		require.NotNil(t, review)
+		assert.Equal(t, "impersonate", review.Spec.ResourceAttributes.Verb)
+		assert.Equal(t, "serviceaccounts", review.Spec.ResourceAttributes.Resource)
+		assert.Equal(t, "deployer", review.Spec.ResourceAttributes.Name)
+	})
+
+	t.Run("Allowed", func(t *testing.T) {
+		opState, _ := syncApp(true)
+		assert.Equal(t, common.OperationSucceeded, opState.Phase, opState.Message)
+		lock.Lock()
+		defer lock.Unlock()
+		assert.Equal(t, map[string]bool{"system:serviceaccount:fake-dest-ns:deployer": true}, impersonatedUsers)
+	})
+}
+
+func TestSyncAppState_ResourceAnnotationTemplates(t *testing.T) {
+	server := discoveryServer(nil)
+	defer server.Close()
+
+	syncApp := func(disabled bool) (*v1alpha1.OperationState, *annotationRecordingOps) {
+		app := newFakeApp()
+		app.Status.OperationState = nil
+		app.Status.History = nil
+		app.Spec.Destination.Server = server.URL
+		configMap := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"my-config","namespace":"` + test.FakeDestNamespace + `","annotations":{"owner":"platform"}}}`
+		ops := &annotationRecordingOps{}
+		data := fakeData{
+			apps: []runtime.Object{app, &defaultProj},
+			manifestResponse: &apiclient.ManifestResponse{
+				Manifests: []string{configMap},
+				Namespace: test.FakeDestNamespace,
+				Server:    server.URL,
+				Revision:  "abc123",
+			},
+			managedLiveObjs: make(map[kube.ResourceKey]*unstructured.Unstructured),
+			resourceOps:     ops,
+			clusterServer:   server.URL,
+			resourceAnnotationTemplates: map[string]string{
+				"cost-center": "gvk: v1/*\ntemplate: |\n  cost-center: cc-{{ .project }}\n  owner: {{ .appName }}\n  placement: {{ .cluster }}/{{ .namespace }}\n",
+			},
+			disableResourceAnnotationTemplates: disabled,
+		}
+		ctrl := newFakeController(&data, nil)
+		opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{Sync: &v1alpha1.SyncOperation{}}}
+		ctrl.appStateManager.SyncAppState(app, opState)
+		return opState, ops
+	}
+
+	t.Run("Enabled", func(t *testing.T) {
+		opState, ops := syncApp(false)
+		assert.Equal(t, common.OperationSucceeded, opState.Phase, opState.Message)
+		annotations := ops.appliedAnnotations("ConfigMap")
+		require.NotEmpty(t, annotations)
+		for _, applied := range annotations {
+			assert.Equal(t, map[string]string{
+				"cost-center": "cc-default",
+				"owner":       "platform",
+				"placement":   "minikube/" + test.FakeDestNamespace,
+			}, applied)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		opState, ops := syncApp(true)
+		assert.Equal(t, common.OperationSucceeded, opState.Phase, opState.Message)
+		annotations := ops.appliedAnnotations("ConfigMap")
+		require.NotEmpty(t, annotations)
+		for _, applied := range annotations {
+			assert.Equal(t, map[string]string{"owner": "platform"}, applied)
+		}
+	})
+}
+
+type fakeAttestationVerifier struct {
+	allowedLicenses map[string][]string
+}
+
+func (v *fakeAttestationVerifier) VerifySBOM(_ context.Context, image string, allowedLicenses []string) error {
+	v.allowedLicenses[image] = allowedLicenses
+	return &attestation.VerificationError{Image: image, Reason: attestation.ReasonSBOMMissing}
+}
+
+func TestSyncVerifiesSBOMAttestations(t *testing.T) {
+	app := newFakeApp()
+	app.Status.OperationState = nil
+	app.Status.History = nil
+	project := &v1alpha1.AppProject{
+		ObjectMeta: v1.ObjectMeta{
+			Namespace: test.FakeArgoCDNamespace,
+			Name:      "default",
+		},
+		Spec: v1alpha1.AppProjectSpec{
+			Allowlist: &v1alpha1.ProjectAllowlist{LicenseIDs: []string{"MIT"}},
+		},
+	}
+	data := fakeData{
+		apps: []runtime.Object{app, project},
+		manifestResponse: &apiclient.ManifestResponse{
+			Manifests: []string{test.DeploymentManifest},
+			Namespace: test.FakeDestNamespace,
+			Server:    test.FakeClusterURL,
+			Revision:  "abc123",
+		},
+		managedLiveObjs: make(map[kube.ResourceKey]*unstructured.Unstructured),
+	}
+	ctrl := newFakeController(&data, nil)
+	verifier := &fakeAttestationVerifier{allowedLicenses: map[string][]string{}}
+	ctrl.appStateManager.(*appStateManager).attestationVerifier = verifier
+
+	source := app.Spec.GetSource()
+	source.VerifyAttestation = &v1alpha1.ApplicationSourceVerifyAttestation{SBOM: true}
+	opState := &v1alpha1.OperationState{Operation: v1alpha1.Operation{
+		Sync: &v1alpha1.SyncOperation{Source: &source},
+	}}
+	ctrl.appStateManager.SyncAppState(app, opState)
+
+	assert.Equal(t, common.OperationFailed, opState.Phase)
+	assert.Equal(t, "SBOM attestation verification failed: image nginx:1.15.4 has no SBOM attestation", opState.Message)
+	assert.Equal(t, map[string][]string{"nginx:1.15.4": {"MIT"}}, verifier.allowedLicenses)
+}
+
 func TestSyncWindowDeniesSync(t *testing.T) {
 	type fixture struct {
 		project     *v1alpha1.AppProject
@@ -323,6 +768,35 @@ func TestSyncWindowDeniesSync(t *testing.T) {
 		assert.Equal(t, common.OperationRunning, opState.Phase)
 		assert.Contains(t, opState.Message, opMessage)
 	})
+
+	t.Run("will override the sync window if it allows it", func(t *testing.T) {
+		// given a project with an active deny sync window allowing overrides and an operation overriding it
+		t.Parallel()
+		f := setup()
+		startedAt := v1.Now()
+		f.application.Status.OperationState = &v1alpha1.OperationState{
+			Operation: v1alpha1.Operation{
+				Sync:        &v1alpha1.SyncOperation{SyncWindowOverrideReason: "security patch"},
+				InitiatedBy: v1alpha1.OperationInitiator{Username: "admin"},
+			},
+			StartedAt: startedAt,
+		}
+		overrides := []argo.SyncWindowOverrideRecord{{User: "admin", Reason: "security patch", Project: "default", Timestamp: startedAt}}
+
+		// then
+		assert.True(t, syncWindowPreventsSync(f.application, f.project, overrides))
+		f.project.Spec.SyncWindows[0].AllowOverride = true
+		assert.False(t, syncWindowPreventsSync(f.application, f.project, overrides))
+
+		// overrides which were not recorded by the API server are ignored
+		assert.True(t, syncWindowPreventsSync(f.application, f.project, nil))
+		f.application.Status.OperationState.Operation.InitiatedBy.Username = "other"
+		assert.True(t, syncWindowPreventsSync(f.application, f.project, overrides))
+
+		// automated syncs never override sync windows
+		f.application.Status.OperationState.Operation.InitiatedBy = v1alpha1.OperationInitiator{Automated: true}
+		assert.True(t, syncWindowPreventsSync(f.application, f.project, overrides))
+	})
 }
 
 func TestNormalizeTargetResources(t *testing.T) {
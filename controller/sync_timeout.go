@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"fmt"
+	"strings"
+	goSync "sync"
+	"time"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// SyncTimedOutReason prefixes the message of the sync operations which failed because they did not complete within
+// the sync timeout
+const SyncTimedOutReason = "SyncTimedOut"
+
+// getSyncTimeout returns the duration after which the syncs of the application fail if they did not complete, or zero
+// if they never time out. The sync timeout of the application takes precedence over the global sync timeout.
+func getSyncTimeout(app *v1alpha1.Application, globalSyncTimeout time.Duration) (time.Duration, error) {
+	timeout, err := app.Spec.SyncPolicy.GetSyncTimeout()
+	if err != nil {
+		return 0, err
+	}
+	if timeout == 0 {
+		return globalSyncTimeout, nil
+	}
+	return timeout, nil
+}
+
+// syncTimedOutMessage returns the message of a sync operation which did not complete within the given timeout
+func syncTimedOutMessage(timeout time.Duration) string {
+	return fmt.Sprintf("%s: sync did not complete within %v", SyncTimedOutReason, timeout)
+}
+
+// isSyncTimedOut returns whether the operation failed because the sync did not complete within the sync timeout
+func isSyncTimedOut(state *v1alpha1.OperationState) bool {
+	return strings.HasPrefix(state.Message, SyncTimedOutReason+":")
+}
+
+// syncAttempt is an attempt of a sync operation
+type syncAttempt struct {
+	operationStartedAt time.Time
+	retryCount         int64
+	startedAt          time.Time
+}
+
+// syncAttempts remembers when the retries of the sync operations started, since the operation state only records the
+// start of the first attempt. Retries in progress while the controller restarts are given the full sync timeout again.
+type syncAttempts struct {
+	lock     goSync.Mutex
+	attempts map[string]syncAttempt
+}
+
+func newSyncAttempts() *syncAttempts {
+	return &syncAttempts{attempts: map[string]syncAttempt{}}
+}
+
+// startedAt returns when the current attempt of the sync operation of the given application started
+func (a *syncAttempts) startedAt(appName string, state *v1alpha1.OperationState, now time.Time) time.Time {
+	if state.RetryCount == 0 {
+		return state.StartedAt.Time
+	}
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	attempt, ok := a.attempts[appName]
+	if !ok || !attempt.operationStartedAt.Equal(state.StartedAt.Time) || attempt.retryCount != state.RetryCount {
+		attempt = syncAttempt{operationStartedAt: state.StartedAt.Time, retryCount: state.RetryCount, startedAt: now}
+		a.attempts[appName] = attempt
+	}
+	return attempt.startedAt
+}
+
+// forget removes the attempt of the sync operation of the given application, once the operation completed
+func (a *syncAttempts) forget(appName string) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	delete(a.attempts, appName)
+}
@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func TestGetSyncTimeout(t *testing.T) {
+	app := newFakeApp()
+	timeout, err := getSyncTimeout(app, 0)
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), timeout)
+
+	timeout, err = getSyncTimeout(app, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, timeout)
+
+	app.Spec.SyncPolicy = &v1alpha1.SyncPolicy{SyncTimeout: "10m"}
+	timeout, err = getSyncTimeout(app, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 10*time.Minute, timeout)
+}
+
+func TestIsSyncTimedOut(t *testing.T) {
+	assert.True(t, isSyncTimedOut(&v1alpha1.OperationState{Message: syncTimedOutMessage(time.Minute)}))
+	assert.True(t, isSyncTimedOut(&v1alpha1.OperationState{Message: syncTimedOutMessage(time.Minute) + ". Retrying attempt #1 at 1:00PM."}))
+	assert.False(t, isSyncTimedOut(&v1alpha1.OperationState{Message: "one or more objects failed to apply"}))
+}
+
+func TestSyncAttempts_StartedAt(t *testing.T) {
+	attempts := newSyncAttempts()
+	operationStartedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := operationStartedAt.Add(time.Hour)
+	state := &v1alpha1.OperationState{StartedAt: metav1.NewTime(operationStartedAt)}
+
+	// the first attempt starts with the operation
+	assert.Equal(t, operationStartedAt, attempts.startedAt("argocd/my-app", state, now))
+
+	// retries start when they are first resumed
+	state.RetryCount = 1
+	assert.Equal(t, now, attempts.startedAt("argocd/my-app", state, now))
+	assert.Equal(t, now, attempts.startedAt("argocd/my-app", state, now.Add(time.Minute)))
+
+	state.RetryCount = 2
+	assert.Equal(t, now.Add(2*time.Minute), attempts.startedAt("argocd/my-app", state, now.Add(2*time.Minute)))
+
+	attempts.forget("argocd/my-app")
+	assert.Equal(t, now.Add(3*time.Minute), attempts.startedAt("argocd/my-app", state, now.Add(3*time.Minute)))
+}
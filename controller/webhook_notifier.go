@@ -0,0 +1,436 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/glob"
+)
+
+const (
+	// PostSyncWebhookStatusDelivered indicates that the post-sync webhook of the last sync operation was delivered
+	PostSyncWebhookStatusDelivered = "Delivered"
+	// PostSyncWebhookStatusFailed indicates that the post-sync webhook of the last sync operation could not be delivered
+	PostSyncWebhookStatusFailed = "Failed"
+
+	// postSyncWebhookTimeout is the timeout of a single post-sync webhook request
+	postSyncWebhookTimeout = 10 * time.Second
+	// postSyncWebhookWorkers is the number of post-sync webhooks delivered concurrently
+	postSyncWebhookWorkers = 5
+	// postSyncWebhookQueueSize is the maximum number of post-sync webhooks waiting to be delivered
+	postSyncWebhookQueueSize = 100
+
+	// webhookConfirmationWaitingSuffix is appended to the message of succeeded sync operations while they wait for the
+	// confirmation of the post-sync webhook
+	webhookConfirmationWaitingSuffix = " (waiting for the post-sync webhook to confirm the sync)"
+)
+
+// postSyncWebhookBackoff is the backoff between attempts to deliver a post-sync webhook
+var postSyncWebhookBackoff = wait.Backoff{
+	Steps:    5,
+	Duration: 1 * time.Second,
+	Factor:   2.0,
+	Jitter:   0.1,
+}
+
+// postSyncWebhookConfirmationBackoff is the backoff between attempts to get the confirmation of a sync operation from
+// a post-sync webhook. Attempts continue at the capped delay until the webhook confirmation timeout expires.
+var postSyncWebhookConfirmationBackoff = wait.Backoff{
+	Steps:    math.MaxInt32,
+	Duration: 1 * time.Second,
+	Factor:   2.0,
+	Jitter:   0.1,
+	Cap:      1 * time.Minute,
+}
+
+// postSyncWebhookRequest is a completed sync operation waiting to be sent to the post-sync webhook of the application
+type postSyncWebhookRequest struct {
+	app   *appv1.Application
+	state *appv1.OperationState
+}
+
+// WebhookNotifier notifies external systems about completed sync operations, using the post-sync webhook configured
+// in the project of the application
+type WebhookNotifier struct {
+	client  *http.Client
+	backoff wait.Backoff
+	// confirmationBackoff is the backoff between attempts to get the confirmation of a sync operation
+	confirmationBackoff wait.Backoff
+	// allowedURLs are the glob patterns of the URLs webhooks may be sent to
+	allowedURLs []string
+}
+
+// NewWebhookNotifier returns a WebhookNotifier which sends requests using the given client, to URLs matching one of
+// the allowedURLs glob patterns only. Redirects are not followed. Requests which fail with a server error or do not get
+// a response are retried using the given exponential backoff.
+func NewWebhookNotifier(client *http.Client, backoff wait.Backoff, allowedURLs []string) *WebhookNotifier {
+	noRedirectClient := *client
+	noRedirectClient.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &WebhookNotifier{client: &noRedirectClient, backoff: backoff, confirmationBackoff: postSyncWebhookConfirmationBackoff, allowedURLs: allowedURLs}
+}
+
+// resolvePostSyncWebhookHeaders returns the headers of the webhook, with the values which reference a key of
+// argocd-secret, such as $webhook.token, replaced by the value of the key
+func resolvePostSyncWebhookHeaders(webhook *appv1.PostSyncWebhook, secrets map[string]string) (map[string]string, error) {
+	headers := make(map[string]string, len(webhook.Headers))
+	for name, value := range webhook.Headers {
+		if strings.HasPrefix(value, "$") {
+			secretValue, ok := secrets[value[1:]]
+			if !ok {
+				return nil, fmt.Errorf("header %s references key %s which does not exist in argocd-secret", name, value[1:])
+			}
+			value = strings.TrimSpace(secretValue)
+		}
+		headers[name] = value
+	}
+	return headers, nil
+}
+
+// postSyncWebhookContext returns the values the body template of a post-sync webhook is executed with
+func postSyncWebhookContext(app *appv1.Application, state *appv1.OperationState) map[string]string {
+	revision := ""
+	if state.SyncResult != nil {
+		revision = state.SyncResult.Revision
+	} else if state.Operation.Sync != nil {
+		revision = state.Operation.Sync.Revision
+	}
+	timestamp := time.Now()
+	if state.FinishedAt != nil {
+		timestamp = state.FinishedAt.Time
+	}
+	return map[string]string{
+		"appName":   app.Name,
+		"status":    string(state.Phase),
+		"revision":  revision,
+		"message":   state.Message,
+		"timestamp": timestamp.UTC().Format(time.RFC3339),
+	}
+}
+
+// renderPostSyncWebhookBody executes the body template of the webhook. Without a body template, the values are
+// rendered as a JSON object.
+func renderPostSyncWebhookBody(webhook *appv1.PostSyncWebhook, values map[string]string) ([]byte, error) {
+	if webhook.BodyTemplate == "" {
+		body, err := json.Marshal(values)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling webhook body: %w", err)
+		}
+		return body, nil
+	}
+	tmpl, err := template.New("body").Option("missingkey=error").Parse(webhook.BodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing webhook body template: %w", err)
+	}
+	var body bytes.Buffer
+	if err := tmpl.Execute(&body, values); err != nil {
+		return nil, fmt.Errorf("error executing webhook body template: %w", err)
+	}
+	return body.Bytes(), nil
+}
+
+// Notify sends the result of the completed sync operation of the application to the webhook. Header values
+// referencing a key of argocd-secret are resolved using secrets.
+func (n *WebhookNotifier) Notify(ctx context.Context, webhook *appv1.PostSyncWebhook, app *appv1.Application, state *appv1.OperationState, secrets map[string]string) error {
+	return n.deliver(ctx, webhook, app, state, secrets, false)
+}
+
+// Confirm sends the result of the succeeded sync operation of the application to the webhook until it responds with
+// HTTP 200, which confirms the sync. Any other response is retried using the confirmation backoff until the context
+// is done.
+func (n *WebhookNotifier) Confirm(ctx context.Context, webhook *appv1.PostSyncWebhook, app *appv1.Application, state *appv1.OperationState, secrets map[string]string) error {
+	return n.deliver(ctx, webhook, app, state, secrets, true)
+}
+
+// deliver sends the webhook request, retrying failed attempts with exponential backoff. When confirm is true, only
+// HTTP 200 responses are successful.
+func (n *WebhookNotifier) deliver(ctx context.Context, webhook *appv1.PostSyncWebhook, app *appv1.Application, state *appv1.OperationState, secrets map[string]string, confirm bool) error {
+	if !glob.MatchStringInList(n.allowedURLs, webhook.URL, glob.GLOB) {
+		return fmt.Errorf("webhook URL %s is not allowed by --post-sync-webhook-allowed-urls", webhook.URL)
+	}
+	headers, err := resolvePostSyncWebhookHeaders(webhook, secrets)
+	if err != nil {
+		return err
+	}
+	body, err := renderPostSyncWebhookBody(webhook, postSyncWebhookContext(app, state))
+	if err != nil {
+		return err
+	}
+	method := webhook.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	attempts := 0
+	var lastErr error
+	attempt := func(ctx context.Context) (bool, error) {
+		attempts++
+		retryable, err := n.send(ctx, method, webhook, headers, body, confirm)
+		if err == nil {
+			return true, nil
+		}
+		if !retryable {
+			return false, err
+		}
+		log.WithField("application", app.QualifiedName()).Debugf("Post-sync webhook attempt %d failed: %v", attempts, err)
+		lastErr = err
+		return false, nil
+	}
+	if confirm {
+		// unlike wait.ExponentialBackoffWithContext, which gives up once the delay reaches the cap of the backoff,
+		// attempts continue at the capped delay until the context is done
+		err = n.confirmationBackoff.DelayFunc().Until(ctx, true, false, attempt)
+	} else {
+		err = wait.ExponentialBackoffWithContext(ctx, n.backoff, attempt)
+	}
+	if wait.Interrupted(err) && lastErr != nil {
+		return fmt.Errorf("webhook not delivered after %d attempts: %w", attempts, lastErr)
+	}
+	return err
+}
+
+// send sends a single webhook request and returns whether a failed request should be retried. When confirm is true,
+// responses other than HTTP 200 are failures which should be retried, since the webhook did not confirm the sync yet.
+func (n *WebhookNotifier) send(ctx context.Context, method string, webhook *appv1.PostSyncWebhook, headers map[string]string, body []byte, confirm bool) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, method, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("error creating webhook request: %w", err)
+	}
+	if webhook.BodyTemplate == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("error sending webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if confirm && resp.StatusCode != http.StatusOK {
+		return true, fmt.Errorf("webhook responded with status %s instead of 200 OK", resp.Status)
+	}
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return true, fmt.Errorf("webhook responded with status %s", resp.Status)
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return false, fmt.Errorf("webhook responded with status %s", resp.Status)
+	}
+	return false, nil
+}
+
+// queuePostSyncWebhook queues the completed sync operation of the application, to be sent to the post-sync webhook of
+// its project, if any, by runPostSyncWebhookWorker
+func (ctrl *ApplicationController) queuePostSyncWebhook(app *appv1.Application, state *appv1.OperationState) {
+	select {
+	case ctrl.postSyncWebhookQueue <- postSyncWebhookRequest{app: app.DeepCopy(), state: state.DeepCopy()}:
+	default:
+		getAppLog(app).Warn("Dropping post-sync webhook: too many webhooks waiting to be delivered")
+	}
+}
+
+// runPostSyncWebhookWorker delivers the queued post-sync webhooks until the context is done
+func (ctrl *ApplicationController) runPostSyncWebhookWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case req := <-ctrl.postSyncWebhookQueue:
+			ctrl.notifyPostSyncWebhook(ctx, req.app, req.state)
+		}
+	}
+}
+
+// notifyPostSyncWebhook sends the result of the completed sync operation to the post-sync webhook of the project of
+// the application, if any, and records the delivery status in the annotations of the application
+func (ctrl *ApplicationController) notifyPostSyncWebhook(ctx context.Context, app *appv1.Application, state *appv1.OperationState) {
+	logCtx := getAppLog(app)
+	proj, err := ctrl.getAppProj(app)
+	if err != nil {
+		logCtx.Warnf("Unable to get project for post-sync webhook: %v", err)
+		return
+	}
+	if proj.Spec.PostSyncWebhook == nil {
+		return
+	}
+
+	argoSettings, err := ctrl.settingsMgr.GetSettings()
+	if err == nil {
+		err = ctrl.webhookNotifier.Notify(ctx, proj.Spec.PostSyncWebhook, app, state, argoSettings.Secrets)
+	} else {
+		err = fmt.Errorf("error getting settings: %w", err)
+	}
+	ctrl.recordPostSyncWebhookStatus(ctx, app, err)
+}
+
+// recordPostSyncWebhookStatus records the delivery status of the post-sync webhook in the annotations of the
+// application, given the error of the delivery if it failed
+func (ctrl *ApplicationController) recordPostSyncWebhookStatus(ctx context.Context, app *appv1.Application, deliveryErr error) {
+	logCtx := getAppLog(app)
+	status := PostSyncWebhookStatusDelivered
+	var message interface{}
+	if deliveryErr != nil {
+		logCtx.Warnf("Failed to deliver post-sync webhook: %v", deliveryErr)
+		status = PostSyncWebhookStatusFailed
+		message = deliveryErr.Error()
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{
+				appv1.AnnotationKeyPostSyncWebhookStatus:  status,
+				appv1.AnnotationKeyPostSyncWebhookMessage: message,
+			},
+		},
+	})
+	if err != nil {
+		logCtx.Errorf("error marshaling json: %v", err)
+		return
+	}
+	_, err = ctrl.PatchAppWithWriteBack(ctx, app.Name, app.Namespace, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		logCtx.Warnf("Failed to record post-sync webhook status: %v", err)
+	}
+}
+
+// webhookConfirmation is the delivery of a succeeded sync operation to a post-sync webhook which must confirm it
+type webhookConfirmation struct {
+	// startedAt is the time the sync operation started waiting for the confirmation
+	startedAt time.Time
+	cancel    context.CancelFunc
+	// done is closed once the webhook confirmed the sync, or the confirmation failed with err
+	done chan struct{}
+	err  error
+}
+
+// getPostSyncWebhookRequiringConfirmation returns the post-sync webhook of the project of the application, if the
+// webhook must confirm the completed sync operation before it succeeds
+func (ctrl *ApplicationController) getPostSyncWebhookRequiringConfirmation(app *appv1.Application, state *appv1.OperationState) *appv1.PostSyncWebhook {
+	if state.Phase != synccommon.OperationSucceeded || state.Operation.Sync == nil || state.Operation.Sync.DryRun {
+		return nil
+	}
+	proj, err := ctrl.getAppProj(app)
+	if err != nil {
+		return nil
+	}
+	if proj.Spec.PostSyncWebhook == nil || !proj.Spec.PostSyncWebhook.RequireWebhookConfirmation {
+		return nil
+	}
+	return proj.Spec.PostSyncWebhook
+}
+
+// awaitPostSyncWebhookConfirmation keeps the succeeded sync operation running until the post-sync webhook confirms it
+func awaitPostSyncWebhookConfirmation(state *appv1.OperationState) {
+	now := metav1.Now()
+	state.Phase = synccommon.OperationRunning
+	state.Message += webhookConfirmationWaitingSuffix
+	state.WebhookConfirmationStartedAt = &now
+}
+
+// startPostSyncWebhookConfirmation starts delivering the succeeded sync operation of the application to the post-sync
+// webhook of its project until it confirms the sync or the webhook confirmation timeout expires, unless the delivery
+// is already in progress. The application operation is processed again once the delivery is done.
+func (ctrl *ApplicationController) startPostSyncWebhookConfirmation(appKey string, app *appv1.Application, state *appv1.OperationState) *webhookConfirmation {
+	// the start time is compared at the precision it is persisted with
+	startedAt := state.WebhookConfirmationStartedAt.Rfc3339Copy().Time
+	if existing, ok := ctrl.webhookConfirmations.Load(appKey); ok {
+		confirmation := existing.(*webhookConfirmation)
+		if confirmation.startedAt.Equal(startedAt) {
+			return confirmation
+		}
+		// the confirmation of a previous sync operation
+		confirmation.cancel()
+	}
+	ctx, cancel := context.WithDeadline(context.Background(), startedAt.Add(ctrl.webhookConfirmationTimeout))
+	confirmation := &webhookConfirmation{startedAt: startedAt, cancel: cancel, done: make(chan struct{})}
+	ctrl.webhookConfirmations.Store(appKey, confirmation)
+
+	// the webhook is sent the state of the sync operation once it succeeds
+	app = app.DeepCopy()
+	state = state.DeepCopy()
+	state.Phase = synccommon.OperationSucceeded
+	state.Message = strings.TrimSuffix(state.Message, webhookConfirmationWaitingSuffix)
+	go func() {
+		defer cancel()
+		proj, err := ctrl.getAppProj(app)
+		if err != nil {
+			confirmation.err = fmt.Errorf("error getting project: %w", err)
+		} else if proj.Spec.PostSyncWebhook == nil {
+			confirmation.err = fmt.Errorf("project %s has no post-sync webhook", proj.Name)
+		} else if argoSettings, err := ctrl.settingsMgr.GetSettings(); err != nil {
+			confirmation.err = fmt.Errorf("error getting settings: %w", err)
+		} else {
+			confirmation.err = ctrl.webhookNotifier.Confirm(ctx, proj.Spec.PostSyncWebhook, app, state, argoSettings.Secrets)
+		}
+		close(confirmation.done)
+		ctrl.appOperationQueue.Add(appKey)
+	}()
+	return confirmation
+}
+
+// processPostSyncWebhookConfirmation completes the sync operation of the application which waits for the confirmation
+// of the post-sync webhook once the webhook confirmed it, or the webhook confirmation timeout expired. The sync
+// operation succeeds in both cases, with a WebhookConfirmationTimeout warning condition if the webhook did not confirm
+// it. Terminating the operation stops waiting for the confirmation.
+func (ctrl *ApplicationController) processPostSyncWebhookConfirmation(app *appv1.Application, state *appv1.OperationState) {
+	logCtx := getAppLog(app)
+	appKey, err := cache.MetaNamespaceKeyFunc(app)
+	if err != nil {
+		logCtx.Warnf("Failed to get application key: %v", err)
+		return
+	}
+	if state.Phase == synccommon.OperationTerminating {
+		if existing, ok := ctrl.webhookConfirmations.LoadAndDelete(appKey); ok {
+			existing.(*webhookConfirmation).cancel()
+		}
+		state.Phase = synccommon.OperationFailed
+		state.ErrorCode = appv1.SyncErrorCodeTerminated
+		state.Message = "Operation terminated while waiting for the post-sync webhook to confirm the sync"
+		ctrl.setOperationState(app, state)
+		return
+	}
+
+	confirmation := ctrl.startPostSyncWebhookConfirmation(appKey, app, state)
+	select {
+	case <-confirmation.done:
+	default:
+		logCtx.Debug("Waiting for the post-sync webhook to confirm the sync")
+		return
+	}
+	ctrl.webhookConfirmations.Delete(appKey)
+
+	if confirmation.err != nil {
+		now := metav1.Now()
+		ctrl.setAppCondition(app, appv1.ApplicationCondition{
+			Type:               appv1.ApplicationConditionWebhookConfirmationTimeout,
+			Message:            fmt.Sprintf("Post-sync webhook did not confirm the sync within %s: %v", ctrl.webhookConfirmationTimeout, confirmation.err),
+			LastTransitionTime: &now,
+		})
+	} else {
+		ctrl.clearAppCondition(app, appv1.ApplicationConditionWebhookConfirmationTimeout)
+	}
+	ctrl.recordPostSyncWebhookStatus(context.Background(), app, confirmation.err)
+
+	state.Phase = synccommon.OperationSucceeded
+	state.Message = strings.TrimSuffix(state.Message, webhookConfirmationWaitingSuffix)
+	ctrl.setOperationState(app, state)
+	ctrl.appOperationRetryQueue.ClearRetry(appKey)
+	ctrl.requestAppRefresh(app.QualifiedName(), CompareWithLatestForceResolve.Pointer(), nil)
+}
@@ -0,0 +1,490 @@
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+)
+
+var testWebhookBackoff = wait.Backoff{
+	Steps:    3,
+	Duration: time.Millisecond,
+	Factor:   2.0,
+}
+
+var allowAllURLs = []string{"*"}
+
+func newWebhookTestState() (*appv1.Application, *appv1.OperationState) {
+	app := &appv1.Application{ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd"}}
+	finishedAt := metav1.NewTime(time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC))
+	state := &appv1.OperationState{
+		Operation:  appv1.Operation{Sync: &appv1.SyncOperation{Revision: "HEAD"}},
+		Phase:      synccommon.OperationSucceeded,
+		Message:    "successfully synced (all tasks run)",
+		SyncResult: &appv1.SyncOperationResult{Revision: "abc123"},
+		FinishedAt: &finishedAt,
+	}
+	return app, state
+}
+
+func TestWebhookNotifier_Payload(t *testing.T) {
+	app, state := newWebhookTestState()
+
+	t.Run("body template", func(t *testing.T) {
+		var method, body, header string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			header = r.Header.Get("X-Token")
+			data, _ := io.ReadAll(r.Body)
+			body = string(data)
+		}))
+		defer server.Close()
+
+		notifier := NewWebhookNotifier(server.Client(), testWebhookBackoff, allowAllURLs)
+		err := notifier.Notify(context.Background(), &appv1.PostSyncWebhook{
+			URL:          server.URL,
+			Method:       http.MethodPut,
+			Headers:      map[string]string{"X-Token": "$webhook.token"},
+			BodyTemplate: `{{.appName}} {{.status}} {{.revision}} {{.timestamp}} {{.message}}`,
+		}, app, state, map[string]string{"webhook.token": "secret\n"})
+		require.NoError(t, err)
+		assert.Equal(t, http.MethodPut, method)
+		assert.Equal(t, "secret", header)
+		assert.Equal(t, "guestbook Succeeded abc123 2024-05-01T10:30:00Z successfully synced (all tasks run)", body)
+	})
+
+	t.Run("default body", func(t *testing.T) {
+		var method, contentType string
+		var body map[string]string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			contentType = r.Header.Get("Content-Type")
+			_ = json.NewDecoder(r.Body).Decode(&body)
+		}))
+		defer server.Close()
+
+		notifier := NewWebhookNotifier(server.Client(), testWebhookBackoff, allowAllURLs)
+		require.NoError(t, notifier.Notify(context.Background(), &appv1.PostSyncWebhook{URL: server.URL}, app, state, nil))
+		assert.Equal(t, http.MethodPost, method)
+		assert.Equal(t, "application/json", contentType)
+		assert.Equal(t, map[string]string{
+			"appName":   "guestbook",
+			"status":    "Succeeded",
+			"revision":  "abc123",
+			"message":   "successfully synced (all tasks run)",
+			"timestamp": "2024-05-01T10:30:00Z",
+		}, body)
+	})
+
+	t.Run("invalid body template", func(t *testing.T) {
+		notifier := NewWebhookNotifier(http.DefaultClient, testWebhookBackoff, allowAllURLs)
+		err := notifier.Notify(context.Background(), &appv1.PostSyncWebhook{URL: "http://localhost", BodyTemplate: "{{.unknown}}"}, app, state, nil)
+		assert.ErrorContains(t, err, "error executing webhook body template")
+	})
+}
+
+func TestWebhookNotifier_Retry(t *testing.T) {
+	app, state := newWebhookTestState()
+
+	newServer := func(statuses ...int) (*httptest.Server, *int32) {
+		var requests int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			i := atomic.AddInt32(&requests, 1) - 1
+			if int(i) < len(statuses) {
+				w.WriteHeader(statuses[i])
+			}
+		}))
+		return server, &requests
+	}
+
+	t.Run("retries on server errors", func(t *testing.T) {
+		server, requests := newServer(http.StatusBadGateway, http.StatusServiceUnavailable)
+		defer server.Close()
+
+		notifier := NewWebhookNotifier(server.Client(), testWebhookBackoff, allowAllURLs)
+		require.NoError(t, notifier.Notify(context.Background(), &appv1.PostSyncWebhook{URL: server.URL}, app, state, nil))
+		assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		server, requests := newServer(http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError)
+		defer server.Close()
+
+		notifier := NewWebhookNotifier(server.Client(), testWebhookBackoff, allowAllURLs)
+		err := notifier.Notify(context.Background(), &appv1.PostSyncWebhook{URL: server.URL}, app, state, nil)
+		require.ErrorContains(t, err, "webhook not delivered after 3 attempts")
+		assert.ErrorContains(t, err, "500 Internal Server Error")
+		assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+	})
+
+	t.Run("does not retry on client errors", func(t *testing.T) {
+		server, requests := newServer(http.StatusUnauthorized)
+		defer server.Close()
+
+		notifier := NewWebhookNotifier(server.Client(), testWebhookBackoff, allowAllURLs)
+		err := notifier.Notify(context.Background(), &appv1.PostSyncWebhook{URL: server.URL}, app, state, nil)
+		require.ErrorContains(t, err, "401 Unauthorized")
+		assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+	})
+}
+
+func TestWebhookNotifier_Restrictions(t *testing.T) {
+	app, state := newWebhookTestState()
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/internal", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	t.Run("URL not allowed", func(t *testing.T) {
+		notifier := NewWebhookNotifier(server.Client(), testWebhookBackoff, []string{"https://hooks.example.com/*"})
+		err := notifier.Notify(context.Background(), &appv1.PostSyncWebhook{URL: server.URL}, app, state, nil)
+		require.ErrorContains(t, err, "is not allowed by --post-sync-webhook-allowed-urls")
+		assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("no URL allowed by default", func(t *testing.T) {
+		notifier := NewWebhookNotifier(server.Client(), testWebhookBackoff, nil)
+		err := notifier.Notify(context.Background(), &appv1.PostSyncWebhook{URL: server.URL}, app, state, nil)
+		require.ErrorContains(t, err, "is not allowed")
+		assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("missing secret key", func(t *testing.T) {
+		notifier := NewWebhookNotifier(server.Client(), testWebhookBackoff, allowAllURLs)
+		err := notifier.Notify(context.Background(), &appv1.PostSyncWebhook{
+			URL:     server.URL,
+			Headers: map[string]string{"Authorization": "$webhook.token"},
+		}, app, state, map[string]string{})
+		require.ErrorContains(t, err, "references key webhook.token which does not exist in argocd-secret")
+		assert.Equal(t, int32(0), atomic.LoadInt32(&requests))
+	})
+
+	t.Run("redirects are not followed", func(t *testing.T) {
+		atomic.StoreInt32(&requests, 0)
+		notifier := NewWebhookNotifier(server.Client(), testWebhookBackoff, []string{server.URL + "/*"})
+		err := notifier.Notify(context.Background(), &appv1.PostSyncWebhook{URL: server.URL + "/redirect"}, app, state, nil)
+		require.ErrorContains(t, err, "302 Found")
+		assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	})
+}
+
+func TestNotifyPostSyncWebhook(t *testing.T) {
+	_, state := newWebhookTestState()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/hook" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	newController := func(webhookURL string) (*ApplicationController, *appv1.Application) {
+		app := newFakeApp()
+		proj := defaultProj.DeepCopy()
+		proj.Spec.PostSyncWebhook = &appv1.PostSyncWebhook{URL: webhookURL}
+		ctrl := newFakeController(&fakeData{
+			apps:                       []runtime.Object{app, proj},
+			postSyncWebhookAllowedURLs: []string{server.URL + "/*"},
+		}, nil)
+		return ctrl, app
+	}
+	getApp := func(t *testing.T, ctrl *ApplicationController, app *appv1.Application) *appv1.Application {
+		t.Helper()
+		updated, err := ctrl.applicationClientset.ArgoprojV1alpha1().Applications(app.Namespace).Get(context.Background(), app.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return updated
+	}
+
+	t.Run("records delivery", func(t *testing.T) {
+		ctrl, app := newController(server.URL + "/hook")
+		ctrl.notifyPostSyncWebhook(context.Background(), app, state)
+
+		updated := getApp(t, ctrl, app)
+		assert.Equal(t, PostSyncWebhookStatusDelivered, updated.Annotations[appv1.AnnotationKeyPostSyncWebhookStatus])
+		assert.NotContains(t, updated.Annotations, appv1.AnnotationKeyPostSyncWebhookMessage)
+
+		// the patched application is written back to the informer
+		obj, exists, err := ctrl.appInformer.GetIndexer().GetByKey(app.Namespace + "/" + app.Name)
+		require.NoError(t, err)
+		require.True(t, exists)
+		assert.Equal(t, PostSyncWebhookStatusDelivered, obj.(*appv1.Application).Annotations[appv1.AnnotationKeyPostSyncWebhookStatus])
+	})
+
+	t.Run("records failure", func(t *testing.T) {
+		ctrl, app := newController(server.URL + "/missing")
+		ctrl.notifyPostSyncWebhook(context.Background(), app, state)
+
+		updated := getApp(t, ctrl, app)
+		assert.Equal(t, PostSyncWebhookStatusFailed, updated.Annotations[appv1.AnnotationKeyPostSyncWebhookStatus])
+		assert.Contains(t, updated.Annotations[appv1.AnnotationKeyPostSyncWebhookMessage], "404 Not Found")
+	})
+
+	t.Run("delivers queued webhooks", func(t *testing.T) {
+		ctrl, app := newController(server.URL + "/hook")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go ctrl.runPostSyncWebhookWorker(ctx)
+
+		ctrl.queuePostSyncWebhook(app, state)
+		assert.Eventually(t, func() bool {
+			return getApp(t, ctrl, app).Annotations[appv1.AnnotationKeyPostSyncWebhookStatus] == PostSyncWebhookStatusDelivered
+		}, 10*time.Second, 10*time.Millisecond)
+	})
+}
+
+var testWebhookConfirmationBackoff = wait.Backoff{
+	Steps:    math.MaxInt32,
+	Duration: 10 * time.Millisecond,
+	Factor:   2.0,
+	Cap:      50 * time.Millisecond,
+}
+
+// newDelayingWebhookServer returns a webhook server which responds with the given statuses in order, then with HTTP 200,
+// after delaying the first delayedRequests responses by delay
+func newDelayingWebhookServer(delay time.Duration, delayedRequests int32, statuses ...int) (*httptest.Server, *int32) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		i := atomic.AddInt32(&requests, 1) - 1
+		if i < delayedRequests {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if int(i) < len(statuses) {
+			w.WriteHeader(statuses[i])
+		}
+	}))
+	return server, &requests
+}
+
+func TestWebhookNotifier_Confirm(t *testing.T) {
+	app, state := newWebhookTestState()
+
+	newNotifier := func(server *httptest.Server) *WebhookNotifier {
+		client := server.Client()
+		client.Timeout = 50 * time.Millisecond
+		notifier := NewWebhookNotifier(client, testWebhookBackoff, allowAllURLs)
+		notifier.confirmationBackoff = testWebhookConfirmationBackoff
+		return notifier
+	}
+
+	t.Run("retries until the webhook responds in time", func(t *testing.T) {
+		server, requests := newDelayingWebhookServer(time.Second, 2)
+		defer server.Close()
+
+		err := newNotifier(server).Confirm(context.Background(), &appv1.PostSyncWebhook{URL: server.URL}, app, state, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+	})
+
+	t.Run("only HTTP 200 confirms the sync", func(t *testing.T) {
+		server, requests := newDelayingWebhookServer(0, 0, http.StatusAccepted, http.StatusNotFound, http.StatusServiceUnavailable)
+		defer server.Close()
+
+		err := newNotifier(server).Confirm(context.Background(), &appv1.PostSyncWebhook{URL: server.URL}, app, state, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int32(4), atomic.LoadInt32(requests))
+	})
+
+	t.Run("keeps retrying at the capped delay", func(t *testing.T) {
+		statuses := make([]int, 8)
+		for i := range statuses {
+			statuses[i] = http.StatusServiceUnavailable
+		}
+		server, requests := newDelayingWebhookServer(0, 0, statuses...)
+		defer server.Close()
+
+		err := newNotifier(server).Confirm(context.Background(), &appv1.PostSyncWebhook{URL: server.URL}, app, state, nil)
+		require.NoError(t, err)
+		assert.Equal(t, int32(9), atomic.LoadInt32(requests))
+	})
+
+	t.Run("gives up once the context is done", func(t *testing.T) {
+		server, requests := newDelayingWebhookServer(time.Second, math.MaxInt32)
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 300*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		err := newNotifier(server).Confirm(ctx, &appv1.PostSyncWebhook{URL: server.URL}, app, state, nil)
+		require.ErrorContains(t, err, "webhook not delivered after")
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Greater(t, atomic.LoadInt32(requests), int32(1))
+	})
+
+	t.Run("notify accepts any successful response", func(t *testing.T) {
+		server, requests := newDelayingWebhookServer(0, 0, http.StatusAccepted)
+		defer server.Close()
+
+		require.NoError(t, newNotifier(server).Notify(context.Background(), &appv1.PostSyncWebhook{URL: server.URL}, app, state, nil))
+		assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+	})
+}
+
+func TestProcessRequestedAppOperation_WebhookConfirmation(t *testing.T) {
+	newController := func(server *httptest.Server, timeout time.Duration) (*ApplicationController, *appv1.Application) {
+		app := newFakeApp()
+		app.Spec.Project = "default"
+		app.Operation = &appv1.Operation{Sync: &appv1.SyncOperation{}}
+		proj := defaultProj.DeepCopy()
+		proj.Spec.PostSyncWebhook = &appv1.PostSyncWebhook{URL: server.URL, RequireWebhookConfirmation: true}
+		ctrl := newFakeController(&fakeData{
+			apps:                       []runtime.Object{app, proj},
+			manifestResponses:          []*apiclient.ManifestResponse{{Manifests: []string{}}},
+			postSyncWebhookAllowedURLs: []string{server.URL},
+			webhookConfirmationTimeout: timeout,
+		}, nil)
+		client := server.Client()
+		client.Timeout = 50 * time.Millisecond
+		ctrl.webhookNotifier = NewWebhookNotifier(client, testWebhookBackoff, ctrl.webhookNotifier.allowedURLs)
+		ctrl.webhookNotifier.confirmationBackoff = testWebhookConfirmationBackoff
+		return ctrl, app
+	}
+	getApp := func(t *testing.T, ctrl *ApplicationController, app *appv1.Application) *appv1.Application {
+		t.Helper()
+		updated, err := ctrl.applicationClientset.ArgoprojV1alpha1().Applications(app.Namespace).Get(context.Background(), app.Name, metav1.GetOptions{})
+		require.NoError(t, err)
+		return updated
+	}
+	waitForConfirmation := func(t *testing.T, ctrl *ApplicationController, app *appv1.Application) {
+		t.Helper()
+		key := app.Namespace + "/" + app.Name
+		existing, ok := ctrl.webhookConfirmations.Load(key)
+		require.True(t, ok)
+		select {
+		case <-existing.(*webhookConfirmation).done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("webhook confirmation did not complete")
+		}
+	}
+
+	t.Run("sync succeeds once the webhook confirms it", func(t *testing.T) {
+		server, requests := newDelayingWebhookServer(time.Second, 2)
+		defer server.Close()
+		ctrl, app := newController(server, time.Minute)
+
+		ctrl.processRequestedAppOperation(app)
+		waiting := getApp(t, ctrl, app)
+		require.NotNil(t, waiting.Status.OperationState)
+		assert.Equal(t, synccommon.OperationRunning, waiting.Status.OperationState.Phase)
+		assert.NotNil(t, waiting.Status.OperationState.WebhookConfirmationStartedAt)
+		assert.Nil(t, waiting.Status.OperationState.FinishedAt)
+		assert.Equal(t, "successfully synced (no more tasks)"+webhookConfirmationWaitingSuffix, waiting.Status.OperationState.Message)
+		assert.NotNil(t, waiting.Operation)
+
+		waitForConfirmation(t, ctrl, app)
+		ctrl.processRequestedAppOperation(waiting)
+		confirmed := getApp(t, ctrl, app)
+		assert.Equal(t, synccommon.OperationSucceeded, confirmed.Status.OperationState.Phase)
+		assert.Equal(t, "successfully synced (no more tasks)", confirmed.Status.OperationState.Message)
+		assert.NotNil(t, confirmed.Status.OperationState.FinishedAt)
+		assert.Nil(t, confirmed.Operation)
+		assert.Empty(t, confirmed.Status.GetConditions(map[appv1.ApplicationConditionType]bool{appv1.ApplicationConditionWebhookConfirmationTimeout: true}))
+		assert.Equal(t, PostSyncWebhookStatusDelivered, confirmed.Annotations[appv1.AnnotationKeyPostSyncWebhookStatus])
+		assert.Equal(t, int32(3), atomic.LoadInt32(requests))
+		_, tracked := ctrl.webhookConfirmations.Load(app.Namespace + "/" + app.Name)
+		assert.False(t, tracked)
+	})
+
+	t.Run("sync succeeds with a warning once the timeout expires", func(t *testing.T) {
+		server, _ := newDelayingWebhookServer(time.Second, math.MaxInt32)
+		defer server.Close()
+		ctrl, app := newController(server, 300*time.Millisecond)
+
+		ctrl.processRequestedAppOperation(app)
+		waiting := getApp(t, ctrl, app)
+		assert.Equal(t, synccommon.OperationRunning, waiting.Status.OperationState.Phase)
+
+		waitForConfirmation(t, ctrl, app)
+		ctrl.processRequestedAppOperation(waiting)
+		timedOut := getApp(t, ctrl, app)
+		assert.Equal(t, synccommon.OperationSucceeded, timedOut.Status.OperationState.Phase)
+		conditions := timedOut.Status.GetConditions(map[appv1.ApplicationConditionType]bool{appv1.ApplicationConditionWebhookConfirmationTimeout: true})
+		require.Len(t, conditions, 1)
+		assert.Contains(t, conditions[0].Message, "Post-sync webhook did not confirm the sync within 300ms")
+		assert.Equal(t, PostSyncWebhookStatusFailed, timedOut.Annotations[appv1.AnnotationKeyPostSyncWebhookStatus])
+	})
+
+	t.Run("confirmation resumes after a restart of the controller", func(t *testing.T) {
+		server, _ := newDelayingWebhookServer(0, 0)
+		defer server.Close()
+		ctrl, app := newController(server, time.Minute)
+		startedAt := metav1.NewTime(time.Now().Add(-time.Second))
+		app.Status.OperationState = &appv1.OperationState{
+			Operation:                    *app.Operation,
+			Phase:                        synccommon.OperationRunning,
+			Message:                      "successfully synced (no more tasks)" + webhookConfirmationWaitingSuffix,
+			StartedAt:                    startedAt,
+			WebhookConfirmationStartedAt: &startedAt,
+		}
+
+		ctrl.processRequestedAppOperation(app)
+		waitForConfirmation(t, ctrl, app)
+		ctrl.processRequestedAppOperation(app)
+		confirmed := getApp(t, ctrl, app)
+		assert.Equal(t, synccommon.OperationSucceeded, confirmed.Status.OperationState.Phase)
+		assert.Equal(t, "successfully synced (no more tasks)", confirmed.Status.OperationState.Message)
+	})
+
+	t.Run("terminating stops waiting for the confirmation", func(t *testing.T) {
+		server, _ := newDelayingWebhookServer(time.Second, math.MaxInt32)
+		defer server.Close()
+		ctrl, app := newController(server, time.Minute)
+
+		ctrl.processRequestedAppOperation(app)
+		waiting := getApp(t, ctrl, app)
+		key := app.Namespace + "/" + app.Name
+		existing, ok := ctrl.webhookConfirmations.Load(key)
+		require.True(t, ok)
+
+		waiting.Status.OperationState.Phase = synccommon.OperationTerminating
+		ctrl.processRequestedAppOperation(waiting)
+		terminated := getApp(t, ctrl, app)
+		assert.Equal(t, synccommon.OperationFailed, terminated.Status.OperationState.Phase)
+		assert.Contains(t, terminated.Status.OperationState.Message, "terminated while waiting for the post-sync webhook")
+		select {
+		case <-existing.(*webhookConfirmation).done:
+		case <-time.After(10 * time.Second):
+			t.Fatal("webhook confirmation was not canceled")
+		}
+		_, tracked := ctrl.webhookConfirmations.Load(key)
+		assert.False(t, tracked)
+	})
+
+	t.Run("failed syncs do not wait for the confirmation", func(t *testing.T) {
+		server, requests := newDelayingWebhookServer(0, 0)
+		defer server.Close()
+		ctrl, app := newController(server, time.Minute)
+		app.Spec.Destination.Server = "https://unknown-cluster"
+
+		ctrl.processRequestedAppOperation(app)
+		failed := getApp(t, ctrl, app)
+		assert.True(t, failed.Status.OperationState.Phase.Completed())
+		assert.False(t, failed.Status.OperationState.Phase.Successful())
+		assert.Nil(t, failed.Status.OperationState.WebhookConfirmationStartedAt)
+		_, tracked := ctrl.webhookConfirmations.Load(app.Namespace + "/" + app.Name)
+		assert.False(t, tracked)
+		assert.Equal(t, int32(0), atomic.LoadInt32(requests))
+	})
+}
@@ -0,0 +1,190 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/client-go/util/workqueue"
+
+	"github.com/argoproj/argo-cd/v2/controller/sharding"
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+)
+
+const (
+	// workStealInterval is the interval at which an idle shard looks for work to steal from the other shards
+	workStealInterval = 5 * time.Second
+	// workStealBatchSize is the maximum number of applications an idle shard steals at once
+	workStealBatchSize = 10
+)
+
+// workStealingRefreshQueue is an app refresh queue which records its items in the work stealing queue of the shard,
+// so that idle shards can steal the items this shard does not make progress on
+type workStealingRefreshQueue struct {
+	workqueue.RateLimitingInterface
+	queue *sharding.WorkStealingQueue
+}
+
+func (q *workStealingRefreshQueue) record(item interface{}, delay time.Duration) {
+	key, ok := item.(string)
+	if !ok {
+		return
+	}
+	if err := q.queue.Add(context.Background(), key, time.Now().Add(delay)); err != nil {
+		log.Warnf("Failed to add application '%s' to the work stealing queue: %v", key, err)
+	}
+}
+
+func (q *workStealingRefreshQueue) Add(item interface{}) {
+	q.record(item, 0)
+	q.RateLimitingInterface.Add(item)
+}
+
+func (q *workStealingRefreshQueue) AddAfter(item interface{}, duration time.Duration) {
+	q.record(item, duration)
+	q.RateLimitingInterface.AddAfter(item, duration)
+}
+
+func (q *workStealingRefreshQueue) AddRateLimited(item interface{}) {
+	q.record(item, 0)
+	q.RateLimitingInterface.AddRateLimited(item)
+}
+
+// stolenApps are the applications stolen from the queues of other shards, with the servers of their destination
+// clusters, until their reconciliation is done
+type stolenApps struct {
+	lock    sync.RWMutex
+	servers map[string]string
+}
+
+func (s *stolenApps) add(key, server string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.servers[key] = server
+}
+
+// remove removes a stolen application, and returns the server of its destination cluster and whether no other stolen
+// application has the same destination cluster
+func (s *stolenApps) remove(key string) (string, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	server, ok := s.servers[key]
+	if !ok {
+		return "", false
+	}
+	delete(s.servers, key)
+	for _, stolenServer := range s.servers {
+		if stolenServer == server {
+			return server, false
+		}
+	}
+	return server, true
+}
+
+func (s *stolenApps) isStolen(key string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	_, ok := s.servers[key]
+	return ok
+}
+
+func (s *stolenApps) hasServer(server string) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	for _, stolenServer := range s.servers {
+		if stolenServer == server {
+			return true
+		}
+	}
+	return false
+}
+
+// borrowingClusterSharding manages the clusters of the shard, and the destination clusters of the applications stolen
+// from other shards while they are reconciled
+type borrowingClusterSharding struct {
+	sharding.ClusterShardingCache
+	stolenApps *stolenApps
+}
+
+func (s *borrowingClusterSharding) IsManagedCluster(c *appv1.Cluster) bool {
+	return s.ClusterShardingCache.IsManagedCluster(c) || (c != nil && s.stolenApps.hasServer(c.Server))
+}
+
+// claimAppRefresh claims the reconciliation of an application before it is processed. It returns false if the
+// application was stolen by another shard, and whether the application was stolen from another shard by this one.
+// The reconciliation is claimed if the work stealing queue is not available.
+func (ctrl *ApplicationController) claimAppRefresh(appKey string) (claimed bool, stolen bool) {
+	if ctrl.workStealingQueue == nil {
+		return true, false
+	}
+	if ctrl.stolenApps.isStolen(appKey) {
+		return true, true
+	}
+	claimed, err := ctrl.workStealingQueue.Claim(context.Background(), appKey)
+	if err != nil {
+		log.Warnf("Failed to claim application '%s' in the work stealing queue: %v", appKey, err)
+		return true, false
+	}
+	return claimed, false
+}
+
+// releaseAppRefresh releases the claim of the reconciliation of an application once it is processed. The cache of the
+// destination cluster of the last stolen application of a cluster managed by another shard is released as well, so
+// that the shard does not keep watching the clusters of the other shards.
+func (ctrl *ApplicationController) releaseAppRefresh(appKey string, stolen bool) {
+	if ctrl.workStealingQueue == nil {
+		return
+	}
+	if stolen {
+		ctrl.workStealingQueue.Forget(appKey)
+		if server, last := ctrl.stolenApps.remove(appKey); last && server != "" {
+			ctrl.stateCache.ReleaseCluster(server)
+		}
+		return
+	}
+	if err := ctrl.workStealingQueue.Done(context.Background(), appKey); err != nil {
+		log.Warnf("Failed to release application '%s' in the work stealing queue: %v", appKey, err)
+	}
+}
+
+// stealWork steals the applications which waited in the queues of other shards for longer than the steal age, if
+// the app refresh queue of this shard is empty, and queues them for reconciliation. The operations of stolen
+// applications are left to the shard they were stolen from.
+func (ctrl *ApplicationController) stealWork(ctx context.Context) {
+	if ctrl.appRefreshQueue.Len() > 0 {
+		return
+	}
+	keys, err := ctrl.workStealingQueue.Steal(ctx, workStealBatchSize)
+	if err != nil {
+		log.Warnf("Failed to steal work from other shards: %v", err)
+	}
+	for _, key := range keys {
+		obj, exists, err := ctrl.appInformer.GetIndexer().GetByKey(key)
+		if err != nil || !exists {
+			ctrl.workStealingQueue.Forget(key)
+			continue
+		}
+		app, ok := obj.(*appv1.Application)
+		if !ok {
+			ctrl.workStealingQueue.Forget(key)
+			continue
+		}
+		// the destination of applications which reference their cluster by name is resolved to the server of the
+		// cluster, otherwise the reconciliation reports the invalid destination as the owner shard would
+		destination := app.Spec.Destination
+		if err := argo.ValidateDestination(ctx, &destination, ctrl.db); err != nil {
+			log.Warnf("Failed to resolve the destination of stolen application '%s': %v", key, err)
+		}
+		ctrl.stolenApps.add(key, destination.Server)
+		// the stolen application is not recorded in the work stealing queue of this shard
+		ctrl.appRefreshQueue.(*workStealingRefreshQueue).RateLimitingInterface.Add(key)
+	}
+}
+
+// incWorkStolen counts an application stolen from the queue of another shard
+func (ctrl *ApplicationController) incWorkStolen(key string, ownerShard int) {
+	log.Infof("Stole application '%s' from shard %d", key, ownerShard)
+	ctrl.metricsServer.IncWorkStolen(ownerShard)
+}
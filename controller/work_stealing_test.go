@@ -0,0 +1,194 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	mockstatecache "github.com/argoproj/argo-cd/v2/controller/cache/mocks"
+	"github.com/argoproj/argo-cd/v2/controller/sharding"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/test"
+	dbmocks "github.com/argoproj/argo-cd/v2/util/db/mocks"
+)
+
+// countingAppStateManager counts the comparisons of the state of the applications
+type countingAppStateManager struct {
+	AppStateManager
+	comparisons int
+}
+
+func (m *countingAppStateManager) CompareAppState(app *v1alpha1.Application, project *v1alpha1.AppProject, revisions []string, sources []v1alpha1.ApplicationSource, noCache bool, noRevisionCache bool, localObjects []string, hasMultipleSources bool, rollback bool) (*comparisonResult, error) {
+	m.comparisons++
+	return m.AppStateManager.CompareAppState(app, project, revisions, sources, noCache, noRevisionCache, localObjects, hasMultipleSources, rollback)
+}
+
+const testWorkStealAge = 10 * time.Millisecond
+
+// newWorkStealingTestController returns a controller of shard 0 out of 2, and the work stealing queue of shard 1
+func newWorkStealingTestController(t *testing.T, app *v1alpha1.Application) (*ApplicationController, *countingAppStateManager, *sharding.WorkStealingQueue) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	t.Cleanup(mr.Close)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	ctrl := newFakeController(&fakeData{
+		apps: []runtime.Object{app, &defaultProj},
+		manifestResponse: &apiclient.ManifestResponse{
+			Manifests: []string{},
+			Namespace: test.FakeDestNamespace,
+			Server:    test.FakeClusterURL,
+			Revision:  "abc123",
+		},
+		managedLiveObjs:   make(map[kube.ResourceKey]*unstructured.Unstructured),
+		workStealingQueue: sharding.NewWorkStealingQueue(client, 0, 2, testWorkStealAge),
+	}, nil)
+	stateManager := &countingAppStateManager{AppStateManager: ctrl.appStateManager}
+	ctrl.appStateManager = stateManager
+	return ctrl, stateManager, sharding.NewWorkStealingQueue(client, 1, 2, testWorkStealAge)
+}
+
+// assertOperationQueued asserts whether the application was added to the rate limited operation queue
+func assertOperationQueued(t *testing.T, ctrl *ApplicationController, queued bool) {
+	t.Helper()
+	if queued {
+		assert.Eventually(t, func() bool { return ctrl.appOperationQueue.Len() == 1 }, time.Second, 10*time.Millisecond)
+	} else {
+		time.Sleep(100 * time.Millisecond)
+		assert.Equal(t, 0, ctrl.appOperationQueue.Len())
+	}
+}
+
+func TestProcessAppRefreshQueueItem_WorkStealing(t *testing.T) {
+	app := newFakeApp()
+	key, _ := cache.MetaNamespaceKeyFunc(app)
+
+	t.Run("NotStolen", func(t *testing.T) {
+		ctrl, stateManager, _ := newWorkStealingTestController(t, app)
+		ctrl.requestAppRefresh(app.Name, CompareWithLatest.Pointer(), nil)
+
+		assert.True(t, ctrl.processAppRefreshQueueItem())
+		assert.Equal(t, 1, stateManager.comparisons)
+		assertOperationQueued(t, ctrl, true)
+	})
+
+	t.Run("StolenByOtherShard", func(t *testing.T) {
+		ctrl, stateManager, otherShard := newWorkStealingTestController(t, app)
+		ctrl.requestAppRefresh(app.Name, CompareWithLatest.Pointer(), nil)
+		time.Sleep(2 * testWorkStealAge)
+		stolen, err := otherShard.Steal(context.Background(), 10)
+		require.NoError(t, err)
+		require.Equal(t, []string{key}, stolen)
+
+		// the application is reconciled by the other shard, but its operations are still handled by this one
+		assert.True(t, ctrl.processAppRefreshQueueItem())
+		assert.Equal(t, 0, stateManager.comparisons)
+		assertOperationQueued(t, ctrl, true)
+	})
+
+	t.Run("StolenFromOtherShard", func(t *testing.T) {
+		ctrl, stateManager, otherShard := newWorkStealingTestController(t, app)
+		require.NoError(t, otherShard.Add(context.Background(), key, time.Now().Add(-time.Minute)))
+		ctrl.refreshRequestedApps[key] = CompareWithLatest
+
+		ctrl.stealWork(context.Background())
+		require.Equal(t, 1, ctrl.appRefreshQueue.Len())
+		assert.True(t, ctrl.stolenApps.isStolen(key))
+
+		// the stolen application is reconciled, its operations are left to the other shard
+		assert.True(t, ctrl.processAppRefreshQueueItem())
+		assert.Equal(t, 1, stateManager.comparisons)
+		assertOperationQueued(t, ctrl, false)
+		assert.False(t, ctrl.stolenApps.isStolen(key))
+		// the other shard skips its own copy of the stolen application
+		claimed, err := otherShard.Claim(context.Background(), key)
+		require.NoError(t, err)
+		assert.False(t, claimed)
+
+		req, err := http.NewRequest(http.MethodGet, "/metrics", nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		ctrl.metricsServer.Handler.ServeHTTP(rr, req)
+		assert.Contains(t, rr.Body.String(), `argocd_work_stolen_total{owner_shard="1"} 1`)
+	})
+
+	t.Run("StolenWithDestinationName", func(t *testing.T) {
+		app := newFakeApp()
+		app.Spec.Destination.Server = ""
+		app.Spec.Destination.Name = "minikube"
+		// the server of the minikube cluster of the fake controller
+		server := "https://localhost:6443"
+		ctrl, stateManager, otherShard := newWorkStealingTestController(t, app)
+		require.NoError(t, otherShard.Add(context.Background(), key, time.Now().Add(-time.Minute)))
+
+		// the destination cluster is recorded by its server, so that the shard handles it
+		ctrl.stealWork(context.Background())
+		assert.True(t, ctrl.stolenApps.hasServer(server))
+
+		// the cache of the destination cluster is released with the last stolen application of the cluster
+		assert.True(t, ctrl.processAppRefreshQueueItem())
+		assert.Equal(t, 1, stateManager.comparisons)
+		assert.False(t, ctrl.stolenApps.hasServer(server))
+		ctrl.stateCache.(*mockstatecache.LiveStateCache).AssertCalled(t, "ReleaseCluster", server)
+	})
+
+	t.Run("NotIdle", func(t *testing.T) {
+		ctrl, _, otherShard := newWorkStealingTestController(t, app)
+		require.NoError(t, otherShard.Add(context.Background(), key, time.Now().Add(-time.Minute)))
+		ctrl.appRefreshQueue.Add(test.FakeArgoCDNamespace + "/other-app")
+
+		ctrl.stealWork(context.Background())
+		assert.Equal(t, 1, ctrl.appRefreshQueue.Len())
+		assert.False(t, ctrl.stolenApps.isStolen(key))
+	})
+}
+
+func TestBorrowingClusterSharding(t *testing.T) {
+	db := &dbmocks.ArgoDB{}
+	stolen := &stolenApps{servers: make(map[string]string)}
+	clusterSharding := &borrowingClusterSharding{ClusterShardingCache: sharding.NewClusterSharding(db, 1, 2, common.DefaultShardingAlgorithm), stolenApps: stolen}
+	cluster := &v1alpha1.Cluster{Server: test.FakeClusterURL}
+	assert.False(t, clusterSharding.IsManagedCluster(cluster))
+
+	// the destination cluster of a stolen application is managed until the application is reconciled
+	stolen.add("argocd/guestbook", test.FakeClusterURL)
+	assert.True(t, clusterSharding.IsManagedCluster(cluster))
+	assert.False(t, clusterSharding.IsManagedCluster(&v1alpha1.Cluster{Server: "https://other-cluster"}))
+	server, last := stolen.remove("argocd/guestbook")
+	assert.Equal(t, test.FakeClusterURL, server)
+	assert.True(t, last)
+	assert.False(t, clusterSharding.IsManagedCluster(cluster))
+}
+
+func TestStolenApps_Remove(t *testing.T) {
+	stolen := &stolenApps{servers: make(map[string]string)}
+	stolen.add("argocd/guestbook", test.FakeClusterURL)
+	stolen.add("argocd/helm-guestbook", test.FakeClusterURL)
+
+	server, last := stolen.remove("argocd/guestbook")
+	assert.Equal(t, test.FakeClusterURL, server)
+	assert.False(t, last, "another stolen application has the same destination cluster")
+	assert.True(t, stolen.hasServer(test.FakeClusterURL))
+
+	server, last = stolen.remove("argocd/helm-guestbook")
+	assert.Equal(t, test.FakeClusterURL, server)
+	assert.True(t, last)
+
+	_, last = stolen.remove("argocd/unknown")
+	assert.False(t, last)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
@@ -17,11 +18,15 @@ import (
 )
 
 var kindToCRDPath = map[string]string{
-	application.ApplicationFullName:    "manifests/crds/application-crd.yaml",
-	application.AppProjectFullName:     "manifests/crds/appproject-crd.yaml",
-	application.ApplicationSetFullName: "manifests/crds/applicationset-crd.yaml",
+	application.ApplicationFullName:      "manifests/crds/application-crd.yaml",
+	application.AppProjectFullName:       "manifests/crds/appproject-crd.yaml",
+	application.ApplicationSetFullName:   "manifests/crds/applicationset-crd.yaml",
+	application.RepositoryConfigFullName: "manifests/crds/repositoryconfig-crd.yaml",
 }
 
+// appSchemaPath is the path of the JSON Schema of the Application resource, used to validate application files locally
+const appSchemaPath = "config/crd/bases/application.schema.json"
+
 func getCustomResourceDefinitions() map[string]*extensionsobj.CustomResourceDefinition {
 	crdYamlBytes, err := exec.Command(
 		"controller-gen",
@@ -37,7 +42,7 @@ func getCustomResourceDefinitions() map[string]*extensionsobj.CustomResourceDefi
 	deleteFile("config/argoproj.io_applications.yaml")
 	deleteFile("config/argoproj.io_appprojects.yaml")
 	deleteFile("config/argoproj.io_applicationsets.yaml")
-	deleteFile("config")
+	deleteFile("config/argoproj.io_repositoryconfigs.yaml")
 
 	objs, err := kube.SplitYAML(crdYamlBytes)
 	checkErr(err)
@@ -125,6 +130,15 @@ func checkErr(err error) {
 
 func main() {
 	crdsapp := getCustomResourceDefinitions()
+	appCRD := crdsapp[application.ApplicationFullName]
+	if appCRD == nil {
+		panic(fmt.Sprintf("CRD of kind %s was not generated", application.ApplicationFullName))
+	}
+	writeSchemaIntoFile(appCRD, appSchemaPath)
+	// only generate the Application JSON Schema when invoked by `make generate-app-schema`
+	if len(os.Args) > 1 && os.Args[1] == "--app-schema-only" {
+		return
+	}
 	for kind, path := range kindToCRDPath {
 		crd := crdsapp[kind]
 		if crd == nil {
@@ -154,3 +168,42 @@ func writeCRDintoFile(crd *extensionsobj.CustomResourceDefinition, path string)
 	err = os.WriteFile(path, yamlBytes, 0o644)
 	checkErr(err)
 }
+
+// writeSchemaIntoFile writes the OpenAPI v3 schema of the first version of the given CRD as a JSON Schema
+func writeSchemaIntoFile(crd *extensionsobj.CustomResourceDefinition, path string) {
+	if len(crd.Spec.Versions) == 0 || crd.Spec.Versions[0].Schema == nil || crd.Spec.Versions[0].Schema.OpenAPIV3Schema == nil {
+		panic(fmt.Sprintf("CRD %s has no schema", crd.Name))
+	}
+	version := crd.Spec.Versions[0]
+	jsonBytes, err := json.Marshal(version.Schema.OpenAPIV3Schema)
+	checkErr(err)
+
+	// round trip through a map so that the keys are sorted
+	var schema map[string]interface{}
+	err = json.Unmarshal(jsonBytes, &schema)
+	checkErr(err)
+
+	// only accept resources of the kind described by the CRD
+	properties := schema["properties"].(map[string]interface{})
+	properties["apiVersion"].(map[string]interface{})["enum"] = []string{crd.Spec.Group + "/" + version.Name}
+	properties["kind"].(map[string]interface{})["enum"] = []string{crd.Spec.Names.Kind}
+
+	// the repository URL of the sources of the spec is required. The CRD does not enforce it, since the sources of the
+	// status and of the history share the same type and may have been written without one.
+	specProperties := properties["spec"].(map[string]interface{})["properties"].(map[string]interface{})
+	requireRepoURL(specProperties["source"].(map[string]interface{}))
+	requireRepoURL(specProperties["sources"].(map[string]interface{})["items"].(map[string]interface{}))
+
+	jsonBytes, err = json.MarshalIndent(schema, "", "  ")
+	checkErr(err)
+
+	checkErr(os.MkdirAll(filepath.Dir(path), 0o755))
+	err = os.WriteFile(path, append(jsonBytes, '\n'), 0o644)
+	checkErr(err)
+}
+
+// requireRepoURL rejects an empty repository URL in the given schema of an application source
+func requireRepoURL(source map[string]interface{}) {
+	repoURL := source["properties"].(map[string]interface{})["repoURL"].(map[string]interface{})
+	repoURL["minLength"] = 1
+}
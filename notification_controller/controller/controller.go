@@ -23,6 +23,7 @@ import (
 	"github.com/argoproj/notifications-engine/pkg/services"
 	"github.com/argoproj/notifications-engine/pkg/subscriptions"
 	httputil "github.com/argoproj/notifications-engine/pkg/util/http"
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -65,6 +66,7 @@ func NewController(
 	secretName string,
 	configMapName string,
 	selfServiceNotificationEnabled bool,
+	idempotencyKeyStore IdempotencyKeyStore,
 ) *notificationController {
 	var appClient dynamic.ResourceInterface
 
@@ -84,7 +86,18 @@ func NewController(
 	}
 	secretInformer := k8s.NewSecretInformer(k8sClient, notificationConfigNamespace, secretName)
 	configMapInformer := k8s.NewConfigMapInformer(k8sClient, notificationConfigNamespace, configMapName)
-	apiFactory := api.NewFactory(settings.GetFactorySettings(argocdService, secretName, configMapName, selfServiceNotificationEnabled), namespace, secretInformer, configMapInformer)
+	// webhook notifications are sent with an idempotency key, allowing external systems to ignore duplicate deliveries
+	var registerer prometheus.Registerer
+	if registry != nil {
+		registerer = registry
+	}
+	deliveries := newWebhookDeliveries(idempotencyKeyStore, registerer)
+	factorySettings := settings.GetFactorySettings(argocdService, secretName, configMapName, selfServiceNotificationEnabled)
+	factorySettings.InitGetVars = deliveries.wrapInitGetVars(factorySettings.InitGetVars)
+	apiFactory := &idempotentAPIFactory{
+		Factory:    api.NewFactory(factorySettings, namespace, secretInformer, configMapInformer),
+		deliveries: deliveries,
+	}
 
 	res := &notificationController{
 		secretInformer:    secretInformer,
@@ -125,6 +125,7 @@ func TestInit(t *testing.T) {
 			"my-secret",
 			"my-configmap",
 			selfServiceNotificationEnabled,
+			nil,
 		)
 
 		assert.NotNil(t, nc)
@@ -159,6 +160,7 @@ func TestInitTimeout(t *testing.T) {
 		"my-secret",
 		"my-configmap",
 		false,
+		nil,
 	)
 
 	assert.NotNil(t, nc)
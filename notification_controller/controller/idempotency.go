@@ -0,0 +1,331 @@
+package controller
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/argoproj/notifications-engine/pkg/api"
+	"github.com/argoproj/notifications-engine/pkg/services"
+	"github.com/argoproj/notifications-engine/pkg/templates"
+	"github.com/argoproj/notifications-engine/pkg/triggers"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	log "github.com/sirupsen/logrus"
+	yaml3 "gopkg.in/yaml.v3"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// IdempotencyKeyHeader is the header of webhook deliveries holding their idempotency key. External systems can
+	// use it to ignore duplicate deliveries of the same notification.
+	IdempotencyKeyHeader = "X-Argo-Idempotency-Key"
+	// IdempotencyKeySecretKey is the key of the notifications secret holding the HMAC key of the idempotency keys
+	IdempotencyKeySecretKey = "webhook.idempotencyKey.secret"
+
+	// idempotencyKeyWindow is the period during which the notifications of the same trigger for the same application
+	// get the same idempotency key
+	idempotencyKeyWindow = 5 * time.Minute
+	// idempotencyKeyTTL is how long the idempotency key of a delivered notification is remembered
+	idempotencyKeyTTL = 10 * time.Minute
+	// idempotencyKeyStorePrefix is the prefix of the idempotency keys recorded in Redis
+	idempotencyKeyStorePrefix = "notifications|webhook-idempotency-key"
+	// idempotencyKeySecretRef is the key of the copy of the notifications secret holding the idempotency key of a
+	// delivery, referenced by the idempotency key header of the webhook service configuration
+	idempotencyKeySecretRef = "argocd-notifications-idempotency-key"
+	// triggerTemplatePrefix is the prefix of the template name added to the condition results, carrying the name of
+	// the trigger to the delivery of the notifications
+	triggerTemplatePrefix = "argocd-notifications-trigger:"
+)
+
+// IdempotencyKeyStore records the idempotency keys of the delivered webhook notifications
+type IdempotencyKeyStore interface {
+	// Reserve records the key for the given duration and returns false if the key is already recorded
+	Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error)
+}
+
+type redisIdempotencyKeyStore struct {
+	client *redis.Client
+}
+
+// NewRedisIdempotencyKeyStore returns an IdempotencyKeyStore recording the keys in Redis, shared by all the replicas
+// of the notifications controller
+func NewRedisIdempotencyKeyStore(client *redis.Client) IdempotencyKeyStore {
+	return &redisIdempotencyKeyStore{client: client}
+}
+
+func (s *redisIdempotencyKeyStore) Reserve(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, idempotencyKeyStorePrefix+"|"+key, "1", ttl).Result()
+}
+
+// ValidateIdempotencyKeySecret returns an error if the notifications secret has no HMAC key for the idempotency keys
+func ValidateIdempotencyKeySecret(secret *v1.Secret) error {
+	if len(secret.Data[IdempotencyKeySecretKey]) == 0 {
+		return fmt.Errorf("key %s of secret %s is empty", IdempotencyKeySecretKey, secret.Name)
+	}
+	return nil
+}
+
+// newIdempotencyKey returns the idempotency key of the notifications of the trigger for the application. The key only
+// changes every idempotencyKeyWindow, so that retries of a delivery get the same key.
+func newIdempotencyKey(secret []byte, appName string, trigger string, now time.Time) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(appName + "/" + trigger + "/" + now.Round(idempotencyKeyWindow).UTC().Format(time.RFC3339)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookDeliveries sends webhook notifications with an idempotency key, and counts the deliveries whose key was
+// already delivered to the same destination
+type webhookDeliveries struct {
+	store            IdempotencyKeyStore
+	preventedCounter *prometheus.CounterVec
+	now              func() time.Time
+}
+
+func newWebhookDeliveries(store IdempotencyKeyStore, registry prometheus.Registerer) *webhookDeliveries {
+	preventedCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_notification_duplicate_delivery_prevented_total",
+			Help: "Number of webhook notifications delivered with an idempotency key which was already delivered, allowing the receiving system to ignore them.",
+		},
+		[]string{"trigger", "service"},
+	)
+	if registry != nil {
+		registry.MustRegister(preventedCounter)
+	}
+	return &webhookDeliveries{store: store, preventedCounter: preventedCounter, now: time.Now}
+}
+
+// deliver calls send and records the idempotency key of the delivery. Notifications whose key was already delivered
+// to the destination are still sent with the same key, and counted, since the receiving system is the one able to
+// tell a duplicate from a different notification of the same trigger.
+func (d *webhookDeliveries) deliver(key string, trigger string, dest services.Destination, send func() error) error {
+	if d.store != nil {
+		storeKey := fmt.Sprintf("%s|%s|%s", dest.Service, dest.Recipient, key)
+		reserved, err := d.store.Reserve(context.Background(), storeKey, idempotencyKeyTTL)
+		if err != nil {
+			log.Warnf("Failed to record idempotency key of notification %s to %s: %v", trigger, dest, err)
+		} else if !reserved {
+			log.Infof("Notification %s to %s reuses the already delivered idempotency key %s", trigger, dest, key)
+			d.preventedCounter.WithLabelValues(trigger, dest.Service).Inc()
+		}
+	}
+	return send()
+}
+
+// wrapInitGetVars replaces the webhook services of the configuration with services able to send notifications with
+// an idempotency key. The services are left unchanged if the notifications secret has no HMAC key, unless the
+// deduplication of the deliveries is enabled, which requires one.
+func (d *webhookDeliveries) wrapInitGetVars(initGetVars func(cfg *api.Config, configMap *v1.ConfigMap, secret *v1.Secret) (api.GetVars, error)) func(cfg *api.Config, configMap *v1.ConfigMap, secret *v1.Secret) (api.GetVars, error) {
+	return func(cfg *api.Config, configMap *v1.ConfigMap, secret *v1.Secret) (api.GetVars, error) {
+		getVars, err := initGetVars(cfg, configMap, secret)
+		if err != nil {
+			return nil, err
+		}
+		if err := ValidateIdempotencyKeySecret(secret); err != nil {
+			if d.store != nil {
+				return nil, fmt.Errorf("webhook deduplication requires an idempotency key secret: %w", err)
+			}
+			return getVars, nil
+		}
+		for k, v := range configMap.Data {
+			if parts := strings.Split(k, "."); len(parts) < 2 || parts[0] != "service" || parts[1] != "webhook" {
+				continue
+			}
+			serviceConfig, err := withIdempotencyKeyHeader(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to add idempotency key header to service configuration %s: %w", k, err)
+			}
+			webhookConfigMap := &v1.ConfigMap{ObjectMeta: configMap.ObjectMeta, Data: map[string]string{k: serviceConfig}}
+			// the engine names the service and validates its configuration
+			webhookCfg, err := api.ParseConfig(webhookConfigMap, withIdempotencyKey(secret, ""))
+			if err != nil {
+				return nil, err
+			}
+			for name := range webhookCfg.Services {
+				name, newService := name, cfg.Services[name]
+				cfg.Services[name] = func() (services.NotificationService, error) {
+					svc, err := newService()
+					if err != nil {
+						return nil, err
+					}
+					templatesService, err := templates.NewService(cfg.Templates)
+					if err != nil {
+						return nil, err
+					}
+					return &idempotentWebhookService{
+						NotificationService: svc,
+						name:                name,
+						configMap:           webhookConfigMap,
+						secret:              secret,
+						getVars:             getVars,
+						templates:           templatesService,
+						hmacKey:             secret.Data[IdempotencyKeySecretKey],
+					}, nil
+				}
+			}
+		}
+		return getVars, nil
+	}
+}
+
+// withIdempotencyKeyHeader adds to the webhook service configuration a header referencing the idempotency key of the
+// delivery in the notifications secret
+func withIdempotencyKeyHeader(serviceConfig string) (string, error) {
+	var doc yaml3.Node
+	if err := yaml3.Unmarshal([]byte(serviceConfig), &doc); err != nil {
+		return "", err
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml3.Node{Kind: yaml3.DocumentNode, Content: []*yaml3.Node{{Kind: yaml3.MappingNode}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml3.MappingNode {
+		return "", errors.New("service configuration is not an object")
+	}
+	str := func(value string) *yaml3.Node {
+		return &yaml3.Node{Kind: yaml3.ScalarNode, Tag: "!!str", Value: value}
+	}
+	header := &yaml3.Node{Kind: yaml3.MappingNode, Content: []*yaml3.Node{
+		str("name"), str(IdempotencyKeyHeader), str("value"), str("$" + idempotencyKeySecretRef),
+	}}
+	var headers *yaml3.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "headers" {
+			headers = root.Content[i+1]
+		}
+	}
+	if headers == nil {
+		headers = &yaml3.Node{}
+		root.Content = append(root.Content, str("headers"), headers)
+	}
+	if headers.Kind != yaml3.SequenceNode {
+		*headers = yaml3.Node{Kind: yaml3.SequenceNode}
+	}
+	headers.Content = append(headers.Content, header)
+	out, err := yaml3.Marshal(&doc)
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// withIdempotencyKey returns a copy of the notifications secret holding the idempotency key of a delivery
+func withIdempotencyKey(secret *v1.Secret, key string) *v1.Secret {
+	secret = secret.DeepCopy()
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[idempotencyKeySecretRef] = []byte(key)
+	return secret
+}
+
+// idempotentWebhookService is a webhook service which adds an idempotency key header to the notifications sent by the
+// notifications controller
+type idempotentWebhookService struct {
+	services.NotificationService
+	name string
+	// configMap holds the configuration of the service, with the idempotency key header
+	configMap *v1.ConfigMap
+	secret    *v1.Secret
+	getVars   api.GetVars
+	templates templates.Service
+	// hmacKey is the HMAC key of the idempotency keys
+	hmacKey []byte
+}
+
+// sendWithIdempotencyKey formats the notification of the object with the given templates, as the notifications engine
+// does, and sends it with the idempotency key header
+func (s *idempotentWebhookService) sendWithIdempotencyKey(obj map[string]interface{}, templateNames []string, dest services.Destination, key string) error {
+	in := make(map[string]interface{})
+	for k, v := range s.getVars(obj, dest) {
+		in[k] = v
+	}
+	in["serviceType"] = dest.Service
+	in["recipient"] = dest.Recipient
+	notification, err := s.templates.FormatNotification(in, templateNames...)
+	if err != nil {
+		return err
+	}
+	cfg, err := api.ParseConfig(s.configMap, withIdempotencyKey(s.secret, key))
+	if err != nil {
+		return err
+	}
+	svc, err := cfg.Services[s.name]()
+	if err != nil {
+		return err
+	}
+	return svc.Send(*notification, dest)
+}
+
+// idempotentAPIFactory returns APIs sending the webhook notifications with an idempotency key
+type idempotentAPIFactory struct {
+	api.Factory
+	deliveries *webhookDeliveries
+}
+
+func (f *idempotentAPIFactory) GetAPI() (api.API, error) {
+	a, err := f.Factory.GetAPI()
+	if err != nil {
+		return nil, err
+	}
+	return &idempotentAPI{API: a, deliveries: f.deliveries}, nil
+}
+
+func (f *idempotentAPIFactory) GetAPIsFromNamespace(namespace string) (map[string]api.API, error) {
+	apis, err := f.Factory.GetAPIsFromNamespace(namespace)
+	for k, a := range apis {
+		apis[k] = &idempotentAPI{API: a, deliveries: f.deliveries}
+	}
+	return apis, err
+}
+
+// idempotentAPI sends the webhook notifications with an idempotency key derived from the name of the application and
+// the trigger. The notifications controller passes the templates of a condition result as they are to Send, so the
+// name of the trigger is added to them as a template name with the triggerTemplatePrefix.
+type idempotentAPI struct {
+	api.API
+	deliveries *webhookDeliveries
+}
+
+func (a *idempotentAPI) RunTrigger(triggerName string, vars map[string]interface{}) ([]triggers.ConditionResult, error) {
+	res, err := a.API.RunTrigger(triggerName, vars)
+	for i := range res {
+		// the templates are shared with the trigger configuration
+		res[i].Templates = append(append([]string{}, res[i].Templates...), triggerTemplatePrefix+triggerName)
+	}
+	return res, err
+}
+
+// splitTriggerTemplate returns the name of the trigger added to the templates by RunTrigger, and the other templates
+func splitTriggerTemplate(templateNames []string) (string, []string) {
+	trigger := ""
+	var names []string
+	for _, name := range templateNames {
+		if strings.HasPrefix(name, triggerTemplatePrefix) {
+			trigger = strings.TrimPrefix(name, triggerTemplatePrefix)
+			continue
+		}
+		names = append(names, name)
+	}
+	return trigger, names
+}
+
+func (a *idempotentAPI) Send(obj map[string]interface{}, templateNames []string, dest services.Destination) error {
+	trigger, templateNames := splitTriggerTemplate(templateNames)
+	svc, ok := a.GetNotificationServices()[dest.Service].(*idempotentWebhookService)
+	if !ok || trigger == "" {
+		return a.API.Send(obj, templateNames, dest)
+	}
+	app := unstructured.Unstructured{Object: obj}
+	key := newIdempotencyKey(svc.hmacKey, app.GetNamespace()+"/"+app.GetName(), trigger, a.deliveries.now())
+	return a.deliveries.deliver(key, trigger, dest, func() error {
+		return svc.sendWithIdempotencyKey(obj, templateNames, dest, key)
+	})
+}
@@ -0,0 +1,245 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/argoproj/notifications-engine/pkg/api"
+	"github.com/argoproj/notifications-engine/pkg/services"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeIdempotencyKeyStore struct {
+	keys       map[string]bool
+	reserveErr error
+}
+
+func (s *fakeIdempotencyKeyStore) Reserve(_ context.Context, key string, _ time.Duration) (bool, error) {
+	if s.reserveErr != nil {
+		return false, s.reserveErr
+	}
+	if s.keys[key] {
+		return false, nil
+	}
+	s.keys[key] = true
+	return true, nil
+}
+
+func TestNewIdempotencyKey(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	key := newIdempotencyKey([]byte("secret"), "argocd/guestbook", "on-sync-succeeded", now)
+	assert.Len(t, key, 64)
+
+	// retries within the window get the same key
+	assert.Equal(t, key, newIdempotencyKey([]byte("secret"), "argocd/guestbook", "on-sync-succeeded", now.Add(2*time.Minute)))
+	assert.Equal(t, key, newIdempotencyKey([]byte("secret"), "argocd/guestbook", "on-sync-succeeded", now.Add(-2*time.Minute)))
+
+	assert.NotEqual(t, key, newIdempotencyKey([]byte("secret"), "argocd/guestbook", "on-sync-succeeded", now.Add(5*time.Minute)))
+	assert.NotEqual(t, key, newIdempotencyKey([]byte("secret"), "argocd/guestbook", "on-sync-failed", now))
+	assert.NotEqual(t, key, newIdempotencyKey([]byte("secret"), "argocd/guestbook-2", "on-sync-succeeded", now))
+	assert.NotEqual(t, key, newIdempotencyKey([]byte("other"), "argocd/guestbook", "on-sync-succeeded", now))
+}
+
+func TestWebhookDeliveries_Deliver(t *testing.T) {
+	dest := services.Destination{Service: "my-webhook"}
+
+	t.Run("duplicate delivery counted", func(t *testing.T) {
+		deliveries := newWebhookDeliveries(&fakeIdempotencyKeyStore{keys: map[string]bool{}}, prometheus.NewRegistry())
+		sent := 0
+		send := func() error {
+			sent++
+			return nil
+		}
+		require.NoError(t, deliveries.deliver("key", "on-sync-succeeded", dest, send))
+		require.NoError(t, deliveries.deliver("key", "on-sync-succeeded", dest, send))
+		assert.Equal(t, 2, sent)
+		assert.InDelta(t, 1, testutil.ToFloat64(deliveries.preventedCounter.WithLabelValues("on-sync-succeeded", "my-webhook")), 0)
+
+		// the same notification to another destination is not a duplicate
+		require.NoError(t, deliveries.deliver("key", "on-sync-succeeded", services.Destination{Service: "other-webhook"}, send))
+		assert.Equal(t, 3, sent)
+		assert.InDelta(t, 0, testutil.ToFloat64(deliveries.preventedCounter.WithLabelValues("on-sync-succeeded", "other-webhook")), 0)
+	})
+
+	t.Run("failed delivery", func(t *testing.T) {
+		deliveries := newWebhookDeliveries(&fakeIdempotencyKeyStore{keys: map[string]bool{}}, prometheus.NewRegistry())
+		err := deliveries.deliver("key", "on-sync-succeeded", dest, func() error {
+			return errors.New("connection refused")
+		})
+		require.EqualError(t, err, "connection refused")
+	})
+
+	t.Run("store unavailable", func(t *testing.T) {
+		deliveries := newWebhookDeliveries(&fakeIdempotencyKeyStore{reserveErr: errors.New("redis unavailable")}, prometheus.NewRegistry())
+		sent := 0
+		for i := 0; i < 2; i++ {
+			require.NoError(t, deliveries.deliver("key", "on-sync-succeeded", dest, func() error {
+				sent++
+				return nil
+			}))
+		}
+		assert.Equal(t, 2, sent)
+	})
+
+	t.Run("no store", func(t *testing.T) {
+		deliveries := newWebhookDeliveries(nil, nil)
+		sent := 0
+		for i := 0; i < 2; i++ {
+			require.NoError(t, deliveries.deliver("key", "on-sync-succeeded", dest, func() error {
+				sent++
+				return nil
+			}))
+		}
+		assert.Equal(t, 2, sent)
+	})
+}
+
+func TestRedisIdempotencyKeyStore(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	store := NewRedisIdempotencyKeyStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	ctx := context.Background()
+
+	reserved, err := store.Reserve(ctx, "key", idempotencyKeyTTL)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+	assert.Equal(t, idempotencyKeyTTL, mr.TTL(idempotencyKeyStorePrefix+"|key"))
+
+	reserved, err = store.Reserve(ctx, "key", idempotencyKeyTTL)
+	require.NoError(t, err)
+	assert.False(t, reserved)
+
+	mr.FastForward(idempotencyKeyTTL)
+	reserved, err = store.Reserve(ctx, "key", idempotencyKeyTTL)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+}
+
+func TestWithIdempotencyKeyHeader(t *testing.T) {
+	header := "    - name: " + IdempotencyKeyHeader + "\n      value: $" + idempotencyKeySecretRef + "\n"
+
+	serviceConfig, err := withIdempotencyKeyHeader("url: $webhook-url\nheaders:\n- name: Authorization\n  value: Bearer $token\n")
+	require.NoError(t, err)
+	assert.Equal(t, "url: $webhook-url\nheaders:\n    - name: Authorization\n      value: Bearer $token\n"+header, serviceConfig)
+
+	serviceConfig, err = withIdempotencyKeyHeader("url: https://example.com\n")
+	require.NoError(t, err)
+	assert.Equal(t, "url: https://example.com\nheaders:\n"+header, serviceConfig)
+
+	_, err = withIdempotencyKeyHeader("- url: https://example.com\n")
+	require.Error(t, err)
+}
+
+func TestSplitTriggerTemplate(t *testing.T) {
+	trigger, names := splitTriggerTemplate([]string{"app-synced", triggerTemplatePrefix + "on-synced"})
+	assert.Equal(t, "on-synced", trigger)
+	assert.Equal(t, []string{"app-synced"}, names)
+
+	trigger, names = splitTriggerTemplate([]string{"app-synced"})
+	assert.Empty(t, trigger)
+	assert.Equal(t, []string{"app-synced"}, names)
+}
+
+func TestIdempotentAPI_Send(t *testing.T) {
+	var lock sync.Mutex
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		defer lock.Unlock()
+		requests = append(requests, r)
+	}))
+	defer server.Close()
+
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "argocd"},
+		Data: map[string]string{
+			"service.webhook.my-webhook": "url: $webhook-url\nheaders:\n- name: Authorization\n  value: Bearer $token\n",
+			"service.slack":              "token: $token\n",
+			"template.app-synced": `webhook:
+  my-webhook:
+    method: POST
+    body: '{"app": "{{.app.metadata.name}}"}'`,
+			"trigger.on-synced": `- when: "true"
+  send: [app-synced]`,
+		},
+	}
+	secret := &v1.Secret{Data: map[string][]byte{
+		"webhook-url":           []byte(server.URL),
+		"token":                 []byte("abc"),
+		IdempotencyKeySecretKey: []byte("hmac-secret"),
+	}}
+	deliveries := newWebhookDeliveries(&fakeIdempotencyKeyStore{keys: map[string]bool{}}, prometheus.NewRegistry())
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	deliveries.now = func() time.Time { return now }
+	cfg, err := api.ParseConfig(cm, secret)
+	require.NoError(t, err)
+	initGetVars := func(_ *api.Config, _ *v1.ConfigMap, _ *v1.Secret) (api.GetVars, error) {
+		return func(obj map[string]interface{}, _ services.Destination) map[string]interface{} {
+			return map[string]interface{}{"app": obj}
+		}, nil
+	}
+	getVars, err := deliveries.wrapInitGetVars(initGetVars)(cfg, cm, secret)
+	require.NoError(t, err)
+	inner, err := api.NewAPI(*cfg, getVars)
+	require.NoError(t, err)
+	assert.IsType(t, &idempotentWebhookService{}, inner.GetNotificationServices()["my-webhook"])
+	_, ok := inner.GetNotificationServices()["slack"].(*idempotentWebhookService)
+	assert.False(t, ok)
+
+	a := &idempotentAPI{API: inner, deliveries: deliveries}
+	app := map[string]interface{}{"metadata": map[string]interface{}{"name": "guestbook", "namespace": "argocd"}}
+	dest := services.Destination{Service: "my-webhook"}
+	res, err := a.RunTrigger("on-synced", app)
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+
+	require.NoError(t, a.Send(app, res[0].Templates, dest))
+	// the retry of the same notification is delivered with the same key
+	require.NoError(t, a.Send(app, res[0].Templates, dest))
+	// the templates of the trigger configuration are left unchanged
+	assert.Equal(t, []string{"app-synced"}, cfg.Triggers["on-synced"][0].Send)
+
+	require.Len(t, requests, 2)
+	key := newIdempotencyKey([]byte("hmac-secret"), "argocd/guestbook", "on-synced", now)
+	for _, r := range requests {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "Bearer abc", r.Header.Get("Authorization"))
+		assert.Equal(t, key, r.Header.Get(IdempotencyKeyHeader))
+	}
+	assert.InDelta(t, 1, testutil.ToFloat64(deliveries.preventedCounter.WithLabelValues("on-synced", "my-webhook")), 0)
+}
+
+func TestWrapInitGetVars_NoIdempotencyKeySecret(t *testing.T) {
+	cm := &v1.ConfigMap{Data: map[string]string{"service.webhook.my-webhook": "url: https://example.com\n"}}
+	secret := &v1.Secret{}
+	initGetVars := func(_ *api.Config, _ *v1.ConfigMap, _ *v1.Secret) (api.GetVars, error) {
+		return func(obj map[string]interface{}, _ services.Destination) map[string]interface{} {
+			return obj
+		}, nil
+	}
+
+	cfg, err := api.ParseConfig(cm, secret)
+	require.NoError(t, err)
+	_, err = newWebhookDeliveries(nil, nil).wrapInitGetVars(initGetVars)(cfg, cm, secret)
+	require.NoError(t, err)
+	svc, err := cfg.Services["my-webhook"]()
+	require.NoError(t, err)
+	_, ok := svc.(*idempotentWebhookService)
+	assert.False(t, ok)
+
+	_, err = newWebhookDeliveries(&fakeIdempotencyKeyStore{keys: map[string]bool{}}, nil).wrapInitGetVars(initGetVars)(cfg, cm, secret)
+	require.ErrorContains(t, err, "webhook deduplication requires an idempotency key secret")
+}
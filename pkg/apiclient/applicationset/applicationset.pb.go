@@ -277,7 +277,9 @@ func (m *ApplicationSetCreateRequest) GetDryRun() bool {
 type ApplicationSetDeleteRequest struct {
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// The application set namespace. Default empty is argocd control plane namespace
-	AppsetNamespace      string   `protobuf:"bytes,2,opt,name=appsetNamespace,proto3" json:"appsetNamespace,omitempty"`
+	AppsetNamespace string `protobuf:"bytes,2,opt,name=appsetNamespace,proto3" json:"appsetNamespace,omitempty"`
+	// Remove the deletion protection annotation of the application set before deleting it
+	ForceDeleteProtected bool     `protobuf:"varint,3,opt,name=forceDeleteProtected,proto3" json:"forceDeleteProtected,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -330,6 +332,13 @@ func (m *ApplicationSetDeleteRequest) GetAppsetNamespace() string {
 	return ""
 }
 
+func (m *ApplicationSetDeleteRequest) GetForceDeleteProtected() bool {
+	if m != nil {
+		return m.ForceDeleteProtected
+	}
+	return false
+}
+
 type ApplicationSetTreeQuery struct {
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// The application set namespace. Default empty is argocd control plane namespace
@@ -400,44 +409,45 @@ func init() {
 }
 
 var fileDescriptor_eacb9df0ce5738fa = []byte{
-	// 586 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x95, 0xcd, 0x8a, 0x13, 0x41,
-	0x10, 0xc7, 0xe9, 0xcd, 0x12, 0xb3, 0xad, 0x28, 0x34, 0xb8, 0x1b, 0x47, 0x89, 0x61, 0x0e, 0x6b,
-	0x5c, 0xdd, 0x1e, 0x12, 0x3d, 0xe9, 0xc9, 0x0f, 0x58, 0x84, 0x20, 0x3a, 0x2b, 0x0a, 0x7a, 0x90,
-	0xde, 0x49, 0x31, 0x3b, 0xee, 0x64, 0xa6, 0xed, 0xee, 0x19, 0x58, 0x16, 0x2f, 0x82, 0x4f, 0xe0,
-	0x1b, 0xe8, 0xc5, 0x07, 0xf0, 0xee, 0xc1, 0x8b, 0x47, 0xc1, 0x17, 0x90, 0xe8, 0x83, 0x48, 0xf7,
-	0x4c, 0x92, 0x9d, 0x26, 0x9b, 0x08, 0xc6, 0xdb, 0x54, 0x77, 0x4f, 0xd5, 0xaf, 0xaa, 0xfe, 0xd5,
-	0x8d, 0xb7, 0x24, 0x88, 0x1c, 0x84, 0xc7, 0x38, 0x8f, 0xa3, 0x80, 0xa9, 0x28, 0x4d, 0x24, 0x28,
-	0xcb, 0xa4, 0x5c, 0xa4, 0x2a, 0x25, 0x67, 0xab, 0xab, 0xce, 0xa5, 0x30, 0x4d, 0xc3, 0x18, 0x3c,
-	0xc6, 0x23, 0x8f, 0x25, 0x49, 0xaa, 0x8a, 0x9d, 0xe2, 0xb4, 0xd3, 0x0f, 0x23, 0xb5, 0x9f, 0xed,
-	0xd1, 0x20, 0x1d, 0x7a, 0x4c, 0x84, 0x29, 0x17, 0xe9, 0x2b, 0xf3, 0xb1, 0x1d, 0x0c, 0xbc, 0xbc,
-	0xe7, 0xf1, 0x83, 0x50, 0xff, 0x29, 0x8f, 0xc7, 0xf2, 0xf2, 0x2e, 0x8b, 0xf9, 0x3e, 0xeb, 0x7a,
-	0x21, 0x24, 0x20, 0x98, 0x82, 0x41, 0xe1, 0xcd, 0x7d, 0x8a, 0xd7, 0xef, 0x4c, 0xcf, 0xed, 0x82,
-	0xda, 0x01, 0xf5, 0x38, 0x03, 0x71, 0x48, 0x08, 0x5e, 0x4d, 0xd8, 0x10, 0x9a, 0xa8, 0x8d, 0x3a,
-	0x6b, 0xbe, 0xf9, 0x26, 0x1d, 0x7c, 0x8e, 0x71, 0x2e, 0x41, 0x3d, 0x64, 0x43, 0x90, 0x9c, 0x05,
-	0xd0, 0x5c, 0x31, 0xdb, 0xf6, 0xb2, 0x7b, 0x84, 0x37, 0xaa, 0x7e, 0xfb, 0x91, 0x2c, 0x1d, 0x3b,
-	0xb8, 0xa1, 0x99, 0x21, 0x50, 0xb2, 0x89, 0xda, 0xb5, 0xce, 0x9a, 0x3f, 0xb1, 0xf5, 0x9e, 0x84,
-	0x18, 0x02, 0x95, 0x8a, 0xd2, 0xf3, 0xc4, 0x9e, 0x15, 0xbc, 0x36, 0x3b, 0xf8, 0x27, 0x64, 0x67,
-	0xe5, 0x83, 0xe4, 0xba, 0xb8, 0xa4, 0x89, 0x4f, 0x95, 0xc1, 0xca, 0xc4, 0xc6, 0x26, 0x51, 0xd8,
-	0xea, 0x83, 0x01, 0x38, 0xdd, 0xeb, 0xd3, 0x69, 0xc1, 0xe9, 0xb8, 0xe0, 0xe6, 0xe3, 0x65, 0x30,
-	0xa0, 0x79, 0x8f, 0xf2, 0x83, 0x90, 0xea, 0x82, 0xd3, 0x63, 0xbf, 0xd3, 0x71, 0xc1, 0xa9, 0xc5,
-	0x61, 0xc5, 0x70, 0xbf, 0x22, 0x7c, 0xb1, 0x7a, 0xe4, 0x9e, 0x00, 0xa6, 0xc0, 0x87, 0xd7, 0x19,
-	0xc8, 0x59, 0x54, 0xe8, 0xff, 0x53, 0x91, 0x75, 0x5c, 0xcf, 0xb8, 0x04, 0x51, 0xd4, 0xa0, 0xe1,
-	0x97, 0x96, 0x5e, 0x1f, 0x88, 0x43, 0x3f, 0x4b, 0x4c, 0xe5, 0x1b, 0x7e, 0x69, 0xb9, 0x2f, 0xec,
-	0x24, 0xee, 0x43, 0x0c, 0xd3, 0x24, 0xfe, 0x4d, 0x4a, 0xcf, 0x6c, 0x29, 0x3d, 0x11, 0x00, 0x4b,
-	0xd0, 0x68, 0xef, 0x57, 0x1d, 0x9f, 0xaf, 0x7a, 0xde, 0x05, 0x91, 0x47, 0x01, 0x90, 0x8f, 0x08,
-	0xd7, 0x76, 0x40, 0x91, 0x4d, 0x6a, 0x0d, 0xec, 0xec, 0x59, 0x71, 0x96, 0xda, 0x0d, 0x77, 0xf3,
-	0xed, 0x8f, 0xdf, 0xef, 0x57, 0xda, 0xa4, 0x65, 0x6e, 0x80, 0xbc, 0x6b, 0xdd, 0x1a, 0xd2, 0x3b,
-	0xd2, 0x89, 0xbe, 0x21, 0x1f, 0x10, 0x5e, 0xd5, 0x63, 0x45, 0xae, 0xcc, 0xc7, 0x9c, 0x8c, 0x9e,
-	0xf3, 0x68, 0x99, 0x9c, 0xda, 0xad, 0x7b, 0xd9, 0xb0, 0x5e, 0x20, 0x1b, 0x27, 0xb0, 0x92, 0xcf,
-	0x08, 0xd7, 0x0b, 0x49, 0x93, 0x6b, 0xf3, 0x31, 0x2b, 0xc2, 0x5f, 0x72, 0x49, 0x3d, 0x83, 0x79,
-	0xd5, 0x3d, 0x09, 0xf3, 0x96, 0x3d, 0x01, 0xef, 0x10, 0xae, 0x17, 0x22, 0x5e, 0x84, 0x5d, 0x91,
-	0xba, 0xb3, 0x40, 0x31, 0xe3, 0x7b, 0x68, 0xdc, 0xe3, 0xad, 0x45, 0x3d, 0xfe, 0x82, 0xf0, 0x19,
-	0x1f, 0x64, 0x9a, 0x89, 0x00, 0xb4, 0xee, 0x17, 0xf5, 0x7a, 0x32, 0x1b, 0xcb, 0xed, 0xb5, 0x76,
-	0xeb, 0xde, 0x34, 0xcc, 0x94, 0x5c, 0x9f, 0xcf, 0xec, 0x89, 0x92, 0x77, 0x5b, 0x09, 0x80, 0xbb,
-	0x0f, 0xbe, 0x8d, 0x5a, 0xe8, 0xfb, 0xa8, 0x85, 0x7e, 0x8e, 0x5a, 0xe8, 0xf9, 0xed, 0xbf, 0x7b,
-	0xbd, 0x82, 0x38, 0x82, 0xc4, 0x7e, 0x2e, 0xf7, 0xea, 0xe6, 0xcd, 0xba, 0xf1, 0x27, 0x00, 0x00,
-	0xff, 0xff, 0xd5, 0xe2, 0xa9, 0xbf, 0x5d, 0x07, 0x00, 0x00,
+	// 603 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x95, 0x4d, 0x6b, 0x13, 0x41,
+	0x18, 0xc7, 0x99, 0xb6, 0xc4, 0x76, 0x14, 0x85, 0x41, 0xdb, 0xb8, 0x4a, 0x0c, 0x7b, 0xa8, 0xb1,
+	0xda, 0x59, 0x12, 0x3d, 0xe9, 0xc9, 0x17, 0x28, 0x42, 0x90, 0xba, 0x15, 0x05, 0x2f, 0x32, 0x9d,
+	0x3c, 0x6e, 0xd7, 0x6e, 0x76, 0xc6, 0x99, 0xd9, 0x85, 0x52, 0xbc, 0x08, 0x9e, 0x3d, 0xf8, 0x0d,
+	0xf4, 0xe2, 0x07, 0xf0, 0xee, 0xc1, 0x8b, 0x47, 0xc1, 0x2f, 0x20, 0xd1, 0x0f, 0x22, 0xfb, 0x92,
+	0xa4, 0x3b, 0xa6, 0x89, 0x60, 0xbc, 0xed, 0x33, 0x33, 0xfb, 0x3c, 0xbf, 0xe7, 0xe5, 0x3f, 0x83,
+	0x37, 0x34, 0xa8, 0x14, 0x94, 0xc7, 0xa4, 0x8c, 0x42, 0xce, 0x4c, 0x28, 0x62, 0x0d, 0xc6, 0x32,
+	0xa9, 0x54, 0xc2, 0x08, 0x72, 0xba, 0xba, 0xea, 0x5c, 0x0c, 0x84, 0x08, 0x22, 0xf0, 0x98, 0x0c,
+	0x3d, 0x16, 0xc7, 0xc2, 0x14, 0x3b, 0xc5, 0x69, 0xa7, 0x1b, 0x84, 0x66, 0x2f, 0xd9, 0xa5, 0x5c,
+	0xf4, 0x3d, 0xa6, 0x02, 0x21, 0x95, 0x78, 0x91, 0x7f, 0x6c, 0xf2, 0x9e, 0x97, 0x76, 0x3c, 0xb9,
+	0x1f, 0x64, 0x7f, 0xea, 0xa3, 0xb1, 0xbc, 0xb4, 0xcd, 0x22, 0xb9, 0xc7, 0xda, 0x5e, 0x00, 0x31,
+	0x28, 0x66, 0xa0, 0x57, 0x78, 0x73, 0x1f, 0xe3, 0xd5, 0xdb, 0xe3, 0x73, 0x3b, 0x60, 0xb6, 0xc0,
+	0x3c, 0x4c, 0x40, 0x1d, 0x10, 0x82, 0x97, 0x62, 0xd6, 0x87, 0x3a, 0x6a, 0xa2, 0xd6, 0x8a, 0x9f,
+	0x7f, 0x93, 0x16, 0x3e, 0xc3, 0xa4, 0xd4, 0x60, 0x1e, 0xb0, 0x3e, 0x68, 0xc9, 0x38, 0xd4, 0x17,
+	0xf2, 0x6d, 0x7b, 0xd9, 0x3d, 0xc4, 0x6b, 0x55, 0xbf, 0xdd, 0x50, 0x97, 0x8e, 0x1d, 0xbc, 0x9c,
+	0x31, 0x03, 0x37, 0xba, 0x8e, 0x9a, 0x8b, 0xad, 0x15, 0x7f, 0x64, 0x67, 0x7b, 0x1a, 0x22, 0xe0,
+	0x46, 0xa8, 0xd2, 0xf3, 0xc8, 0x9e, 0x14, 0x7c, 0x71, 0x72, 0xf0, 0x8f, 0xc8, 0xce, 0xca, 0x07,
+	0x2d, 0xb3, 0xe2, 0x92, 0x3a, 0x3e, 0x51, 0x06, 0x2b, 0x13, 0x1b, 0x9a, 0xc4, 0x60, 0xab, 0x0f,
+	0x39, 0xc0, 0xc9, 0x4e, 0x97, 0x8e, 0x0b, 0x4e, 0x87, 0x05, 0xcf, 0x3f, 0x9e, 0xf1, 0x1e, 0x4d,
+	0x3b, 0x54, 0xee, 0x07, 0x34, 0x2b, 0x38, 0x3d, 0xf2, 0x3b, 0x1d, 0x16, 0x9c, 0x5a, 0x1c, 0x56,
+	0x0c, 0xf7, 0x0b, 0xc2, 0x17, 0xaa, 0x47, 0xee, 0x2a, 0x60, 0x06, 0x7c, 0x78, 0x99, 0x80, 0x9e,
+	0x44, 0x85, 0xfe, 0x3f, 0x15, 0x59, 0xc5, 0xb5, 0x44, 0x6a, 0x50, 0x45, 0x0d, 0x96, 0xfd, 0xd2,
+	0xca, 0xd6, 0x7b, 0xea, 0xc0, 0x4f, 0xe2, 0xbc, 0xf2, 0xcb, 0x7e, 0x69, 0xb9, 0x6f, 0xff, 0xc8,
+	0xe2, 0x1e, 0x44, 0x30, 0xce, 0xe2, 0x9f, 0x66, 0x89, 0x74, 0xf0, 0xd9, 0xe7, 0x42, 0x71, 0x28,
+	0x7c, 0x6e, 0x2b, 0x61, 0x80, 0x1b, 0xe8, 0x95, 0x0c, 0x13, 0xf7, 0xdc, 0x27, 0xf6, 0xfc, 0x3d,
+	0x52, 0x00, 0x73, 0x18, 0xec, 0xce, 0xcf, 0x1a, 0x3e, 0x57, 0xf5, 0xbc, 0x03, 0x2a, 0x0d, 0x39,
+	0x90, 0x0f, 0x08, 0x2f, 0x6e, 0x81, 0x21, 0xeb, 0xd4, 0x52, 0xf9, 0x64, 0x81, 0x39, 0x73, 0x6d,
+	0xa1, 0xbb, 0xfe, 0xfa, 0xfb, 0xaf, 0x77, 0x0b, 0x4d, 0xd2, 0xc8, 0xaf, 0x8d, 0xb4, 0x6d, 0x5d,
+	0x35, 0xda, 0x3b, 0xcc, 0x12, 0x7d, 0x45, 0xde, 0x23, 0xbc, 0x94, 0x69, 0x91, 0x5c, 0x9e, 0x8e,
+	0x39, 0xd2, 0xab, 0xb3, 0x3d, 0x4f, 0xce, 0xcc, 0xad, 0x7b, 0x29, 0x67, 0x3d, 0x4f, 0xd6, 0x8e,
+	0x61, 0x25, 0x9f, 0x10, 0xae, 0x15, 0x3a, 0x20, 0x57, 0xa7, 0x63, 0x56, 0xd4, 0x32, 0xe7, 0x92,
+	0x7a, 0x39, 0xe6, 0x15, 0xf7, 0x38, 0xcc, 0x9b, 0xb6, 0x6c, 0xde, 0x20, 0x5c, 0x2b, 0x06, 0x71,
+	0x16, 0x76, 0x45, 0x1e, 0xce, 0x8c, 0x89, 0x19, 0x5e, 0x5e, 0xc3, 0x1e, 0x6f, 0xcc, 0xea, 0xf1,
+	0x67, 0x84, 0x4f, 0xf9, 0xa0, 0x45, 0xa2, 0x38, 0x64, 0x73, 0x3f, 0xab, 0xd7, 0x23, 0x6d, 0xcc,
+	0xb7, 0xd7, 0x99, 0x5b, 0xf7, 0x46, 0xce, 0x4c, 0xc9, 0xb5, 0xe9, 0xcc, 0x9e, 0x2a, 0x79, 0x37,
+	0x8d, 0x02, 0xb8, 0x73, 0xff, 0xeb, 0xa0, 0x81, 0xbe, 0x0d, 0x1a, 0xe8, 0xc7, 0xa0, 0x81, 0x9e,
+	0xde, 0xfa, 0xbb, 0x27, 0x8f, 0x47, 0x21, 0xc4, 0xf6, 0x1b, 0xbb, 0x5b, 0xcb, 0x1f, 0xba, 0xeb,
+	0xbf, 0x07, 0x00, 0xe2, 0x03, 0x92, 0x3d, 0x92, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -894,6 +904,16 @@ func (m *ApplicationSetDeleteRequest) MarshalToSizedBuffer(dAtA []byte) (int, er
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.ForceDeleteProtected {
+		i--
+		if m.ForceDeleteProtected {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
 	if len(m.AppsetNamespace) > 0 {
 		i -= len(m.AppsetNamespace)
 		copy(dAtA[i:], m.AppsetNamespace)
@@ -1065,6 +1085,9 @@ func (m *ApplicationSetDeleteRequest) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovApplicationset(uint64(l))
 	}
+	if m.ForceDeleteProtected {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -1698,6 +1721,26 @@ func (m *ApplicationSetDeleteRequest) Unmarshal(dAtA []byte) error {
 			}
 			m.AppsetNamespace = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ForceDeleteProtected", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowApplicationset
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ForceDeleteProtected = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipApplicationset(dAtA[iNdEx:])
@@ -395,7 +395,9 @@ type RepoAccessQuery struct {
 	// Google Cloud Platform service account key
 	GcpServiceAccountKey string `protobuf:"bytes,18,opt,name=gcpServiceAccountKey,proto3" json:"gcpServiceAccountKey,omitempty"`
 	// Whether to force HTTP basic auth
-	ForceHttpBasicAuth   bool     `protobuf:"varint,19,opt,name=forceHttpBasicAuth,proto3" json:"forceHttpBasicAuth,omitempty"`
+	ForceHttpBasicAuth bool `protobuf:"varint,19,opt,name=forceHttpBasicAuth,proto3" json:"forceHttpBasicAuth,omitempty"`
+	// The type of the artifacts of an OCI repository, "helm" or "manifests"
+	OciType              string   `protobuf:"bytes,20,opt,name=ociType,proto3" json:"ociType,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -560,6 +562,13 @@ func (m *RepoAccessQuery) GetForceHttpBasicAuth() bool {
 	return false
 }
 
+func (m *RepoAccessQuery) GetOciType() string {
+	if m != nil {
+		return m.OciType
+	}
+	return ""
+}
+
 type RepoResponse struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -713,6 +722,236 @@ func (m *RepoUpdateRequest) GetRepo() *v1alpha1.Repository {
 	return nil
 }
 
+// RepoImportBundleRequest is a request for importing a git bundle into a repository
+type RepoImportBundleRequest struct {
+	// Repo URL the bundle is imported for
+	Repo string `protobuf:"bytes,1,opt,name=repo,proto3" json:"repo,omitempty"`
+	// Content of the git bundle file
+	Bundle []byte `protobuf:"bytes,2,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	// Whether to apply the bundle on top of the previously imported one instead of replacing it
+	Update bool `protobuf:"varint,3,opt,name=update,proto3" json:"update,omitempty"`
+	// App project of the repository
+	AppProject           string   `protobuf:"bytes,4,opt,name=appProject,proto3" json:"appProject,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RepoImportBundleRequest) Reset()         { *m = RepoImportBundleRequest{} }
+func (m *RepoImportBundleRequest) String() string { return proto.CompactTextString(m) }
+func (*RepoImportBundleRequest) ProtoMessage()    {}
+func (*RepoImportBundleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d38260443475705, []int{9}
+}
+func (m *RepoImportBundleRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RepoImportBundleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RepoImportBundleRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RepoImportBundleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepoImportBundleRequest.Merge(m, src)
+}
+func (m *RepoImportBundleRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *RepoImportBundleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepoImportBundleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepoImportBundleRequest proto.InternalMessageInfo
+
+func (m *RepoImportBundleRequest) GetRepo() string {
+	if m != nil {
+		return m.Repo
+	}
+	return ""
+}
+
+func (m *RepoImportBundleRequest) GetBundle() []byte {
+	if m != nil {
+		return m.Bundle
+	}
+	return nil
+}
+
+func (m *RepoImportBundleRequest) GetUpdate() bool {
+	if m != nil {
+		return m.Update
+	}
+	return false
+}
+
+func (m *RepoImportBundleRequest) GetAppProject() string {
+	if m != nil {
+		return m.AppProject
+	}
+	return ""
+}
+
+// RepoImportBundleResponse contains the references imported from a git bundle
+type RepoImportBundleResponse struct {
+	Refs                 []string `protobuf:"bytes,1,rep,name=refs,proto3" json:"refs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RepoImportBundleResponse) Reset()         { *m = RepoImportBundleResponse{} }
+func (m *RepoImportBundleResponse) String() string { return proto.CompactTextString(m) }
+func (*RepoImportBundleResponse) ProtoMessage()    {}
+func (*RepoImportBundleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d38260443475705, []int{10}
+}
+func (m *RepoImportBundleResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RepoImportBundleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RepoImportBundleResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RepoImportBundleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepoImportBundleResponse.Merge(m, src)
+}
+func (m *RepoImportBundleResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *RepoImportBundleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepoImportBundleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepoImportBundleResponse proto.InternalMessageInfo
+
+func (m *RepoImportBundleResponse) GetRefs() []string {
+	if m != nil {
+		return m.Refs
+	}
+	return nil
+}
+
+// RepoCachedKeysQuery is a query for a page of the keys stored by the repo server in the cache
+type RepoCachedKeysQuery struct {
+	// Cursor returned by the previous query, 0 to list the first page
+	Cursor uint64 `protobuf:"varint,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	// Number of keys to scan for the page
+	Count                int64    `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RepoCachedKeysQuery) Reset()         { *m = RepoCachedKeysQuery{} }
+func (m *RepoCachedKeysQuery) String() string { return proto.CompactTextString(m) }
+func (*RepoCachedKeysQuery) ProtoMessage()    {}
+func (*RepoCachedKeysQuery) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d38260443475705, []int{11}
+}
+func (m *RepoCachedKeysQuery) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RepoCachedKeysQuery) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RepoCachedKeysQuery.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RepoCachedKeysQuery) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepoCachedKeysQuery.Merge(m, src)
+}
+func (m *RepoCachedKeysQuery) XXX_Size() int {
+	return m.Size()
+}
+func (m *RepoCachedKeysQuery) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepoCachedKeysQuery.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepoCachedKeysQuery proto.InternalMessageInfo
+
+func (m *RepoCachedKeysQuery) GetCursor() uint64 {
+	if m != nil {
+		return m.Cursor
+	}
+	return 0
+}
+
+func (m *RepoCachedKeysQuery) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+// RepoBundleResponse contains a git bundle of a repository
+type RepoBundleResponse struct {
+	Bundle               []byte   `protobuf:"bytes,1,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RepoBundleResponse) Reset()         { *m = RepoBundleResponse{} }
+func (m *RepoBundleResponse) String() string { return proto.CompactTextString(m) }
+func (*RepoBundleResponse) ProtoMessage()    {}
+func (*RepoBundleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d38260443475705, []int{12}
+}
+func (m *RepoBundleResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *RepoBundleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_RepoBundleResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *RepoBundleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RepoBundleResponse.Merge(m, src)
+}
+func (m *RepoBundleResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *RepoBundleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RepoBundleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RepoBundleResponse proto.InternalMessageInfo
+
+func (m *RepoBundleResponse) GetBundle() []byte {
+	if m != nil {
+		return m.Bundle
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*RepoAppsQuery)(nil), "repository.RepoAppsQuery")
 	proto.RegisterType((*AppInfo)(nil), "repository.AppInfo")
@@ -723,6 +962,10 @@ func init() {
 	proto.RegisterType((*RepoResponse)(nil), "repository.RepoResponse")
 	proto.RegisterType((*RepoCreateRequest)(nil), "repository.RepoCreateRequest")
 	proto.RegisterType((*RepoUpdateRequest)(nil), "repository.RepoUpdateRequest")
+	proto.RegisterType((*RepoImportBundleRequest)(nil), "repository.RepoImportBundleRequest")
+	proto.RegisterType((*RepoImportBundleResponse)(nil), "repository.RepoImportBundleResponse")
+	proto.RegisterType((*RepoCachedKeysQuery)(nil), "repository.RepoCachedKeysQuery")
+	proto.RegisterType((*RepoBundleResponse)(nil), "repository.RepoBundleResponse")
 }
 
 func init() {
@@ -730,81 +973,94 @@ func init() {
 }
 
 var fileDescriptor_8d38260443475705 = []byte{
-	// 1178 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x57, 0x5d, 0x6f, 0x1b, 0x45,
-	0x17, 0xd6, 0x26, 0x8d, 0x93, 0x9c, 0x7c, 0xd4, 0x99, 0xe4, 0xed, 0xbb, 0xb8, 0x6e, 0x1a, 0x6d,
-	0x4b, 0x15, 0xa2, 0xb2, 0x6e, 0x8c, 0x10, 0xa8, 0x08, 0x24, 0xe7, 0x43, 0x4d, 0x44, 0x44, 0xca,
-	0x56, 0xe1, 0x02, 0x81, 0xd0, 0x64, 0x7d, 0x62, 0x6f, 0xbb, 0xde, 0x9d, 0xce, 0x8c, 0x4d, 0xad,
-	0xaa, 0x37, 0x5c, 0x21, 0xc1, 0x0d, 0x42, 0x48, 0xdc, 0x21, 0x24, 0x24, 0x2e, 0xf8, 0x23, 0x5c,
-	0x22, 0xf1, 0x07, 0x50, 0xc4, 0x8f, 0xe0, 0x0a, 0xa1, 0x99, 0x59, 0xef, 0xae, 0x13, 0xdb, 0x49,
-	0x45, 0xc8, 0xdd, 0xcc, 0x73, 0xce, 0x9e, 0xf3, 0xcc, 0xb3, 0xe7, 0x9c, 0x9d, 0x05, 0x47, 0x20,
-	0xef, 0x20, 0xaf, 0x70, 0x64, 0xb1, 0x08, 0x64, 0xcc, 0xbb, 0xb9, 0xa5, 0xcb, 0x78, 0x2c, 0x63,
-	0x02, 0x19, 0x52, 0x2a, 0x37, 0xe2, 0xb8, 0x11, 0x62, 0x85, 0xb2, 0xa0, 0x42, 0xa3, 0x28, 0x96,
-	0x54, 0x06, 0x71, 0x24, 0x8c, 0x67, 0x69, 0xaf, 0x11, 0xc8, 0x66, 0xfb, 0xd0, 0xf5, 0xe3, 0x56,
-	0x85, 0xf2, 0x46, 0xcc, 0x78, 0xfc, 0x58, 0x2f, 0x5e, 0xf7, 0xeb, 0x95, 0x4e, 0xb5, 0xc2, 0x9e,
-	0x34, 0xd4, 0x93, 0xa2, 0x42, 0x19, 0x0b, 0x03, 0x5f, 0x3f, 0x5b, 0xe9, 0xac, 0xd3, 0x90, 0x35,
-	0xe9, 0x7a, 0xa5, 0x81, 0x11, 0x72, 0x2a, 0xb1, 0x9e, 0x44, 0xdb, 0x3e, 0x23, 0x9a, 0xa6, 0x75,
-	0x26, 0x7d, 0xa7, 0x0b, 0x73, 0x1e, 0xb2, 0xb8, 0xc6, 0x98, 0xf8, 0xb0, 0x8d, 0xbc, 0x4b, 0x08,
-	0x5c, 0x51, 0x4e, 0xb6, 0xb5, 0x62, 0xad, 0x4e, 0x7b, 0x7a, 0x4d, 0x4a, 0x30, 0xc5, 0xb1, 0x13,
-	0x88, 0x20, 0x8e, 0xec, 0x31, 0x8d, 0xa7, 0x7b, 0x62, 0xc3, 0x24, 0x65, 0xec, 0x03, 0xda, 0x42,
-	0x7b, 0x5c, 0x9b, 0x7a, 0x5b, 0xb2, 0x0c, 0x40, 0x19, 0x7b, 0xc8, 0xe3, 0xc7, 0xe8, 0x4b, 0xfb,
-	0x8a, 0x36, 0xe6, 0x10, 0x67, 0x1d, 0x26, 0x6b, 0x8c, 0xed, 0x46, 0x47, 0xb1, 0x4a, 0x2a, 0xbb,
-	0x0c, 0x7b, 0x49, 0xd5, 0x5a, 0x61, 0x8c, 0xca, 0x66, 0x92, 0x50, 0xaf, 0x9d, 0xbf, 0x2c, 0x58,
-	0x4c, 0xe8, 0x6e, 0xa1, 0xa4, 0x41, 0x98, 0x90, 0x6e, 0x40, 0x41, 0xc4, 0x6d, 0xee, 0x9b, 0x08,
-	0x33, 0xd5, 0x7d, 0x37, 0x53, 0xc7, 0xed, 0xa9, 0xa3, 0x17, 0x9f, 0xf9, 0x75, 0xb7, 0x53, 0x75,
-	0xd9, 0x93, 0x86, 0xab, 0xb4, 0x76, 0x73, 0x5a, 0xbb, 0x3d, 0xad, 0xdd, 0x5a, 0x06, 0x3e, 0xd2,
-	0x61, 0xbd, 0x24, 0x7c, 0xfe, 0xb4, 0x63, 0xa3, 0x4e, 0x3b, 0x7e, 0xf2, 0xb4, 0x64, 0x05, 0x66,
-	0x4c, 0x8c, 0xdd, 0xa8, 0x8e, 0xcf, 0xb4, 0x1c, 0x13, 0x5e, 0x1e, 0x22, 0x65, 0x98, 0xee, 0x20,
-	0x57, 0xa2, 0xee, 0xd6, 0xed, 0x09, 0x6d, 0xcf, 0x00, 0xe7, 0x5d, 0x28, 0xf6, 0x5e, 0x94, 0x87,
-	0x82, 0xc5, 0x91, 0x40, 0xf2, 0x1a, 0x4c, 0x04, 0x12, 0x5b, 0xc2, 0xb6, 0x56, 0xc6, 0x57, 0x67,
-	0xaa, 0x8b, 0x6e, 0xee, 0xf5, 0x26, 0xd2, 0x7a, 0xc6, 0xc3, 0xf1, 0x61, 0x5a, 0x3d, 0x3e, 0xfc,
-	0x1d, 0x3b, 0x30, 0x7b, 0x14, 0xab, 0xa3, 0xe2, 0x11, 0x47, 0x61, 0x64, 0x9f, 0xf2, 0xfa, 0xb0,
-	0xb3, 0xce, 0xe8, 0xfc, 0x38, 0x01, 0x57, 0x35, 0x49, 0xdf, 0x47, 0x31, 0xba, 0x9e, 0xda, 0x02,
-	0x79, 0x94, 0xc9, 0x98, 0xee, 0x95, 0x8d, 0x51, 0x21, 0x3e, 0x8f, 0x79, 0x3d, 0xc9, 0x90, 0xee,
-	0xc9, 0x6d, 0x98, 0x13, 0xa2, 0xf9, 0x90, 0x07, 0x1d, 0x2a, 0xf1, 0x7d, 0xec, 0x26, 0x45, 0xd5,
-	0x0f, 0xaa, 0x08, 0x41, 0x24, 0xd0, 0x6f, 0x73, 0xd4, 0x32, 0x4e, 0x79, 0xe9, 0x9e, 0xdc, 0x85,
-	0x05, 0x19, 0x8a, 0xcd, 0x30, 0xc0, 0x48, 0x6e, 0x22, 0x97, 0x5b, 0x54, 0x52, 0xbb, 0xa0, 0xa3,
-	0x9c, 0x36, 0x90, 0x35, 0x28, 0xf6, 0x81, 0x2a, 0xe5, 0xa4, 0x76, 0x3e, 0x85, 0xa7, 0x25, 0x3c,
-	0xdd, 0x5f, 0xc2, 0xfa, 0x8c, 0x60, 0x30, 0x7d, 0xbe, 0x32, 0x4c, 0x63, 0x44, 0x0f, 0x43, 0xdc,
-	0xf7, 0x03, 0x7b, 0x46, 0xd3, 0xcb, 0x00, 0x72, 0x0f, 0x16, 0x4d, 0xe5, 0xd6, 0x94, 0xaa, 0xe9,
-	0x39, 0x67, 0x75, 0x80, 0x41, 0x26, 0x55, 0x57, 0x29, 0xbc, 0xbb, 0x65, 0xcf, 0xad, 0x58, 0xab,
-	0xe3, 0x5e, 0x1e, 0x22, 0x6f, 0xc3, 0xff, 0xb3, 0x6d, 0x24, 0x24, 0x0d, 0x43, 0x5d, 0xda, 0xbb,
-	0x5b, 0xf6, 0xbc, 0xf6, 0x1e, 0x66, 0x26, 0xef, 0x41, 0x29, 0x35, 0x6d, 0x47, 0x12, 0x39, 0xe3,
-	0x81, 0xc0, 0x0d, 0x2a, 0xf0, 0x80, 0x87, 0xf6, 0x55, 0x4d, 0x6a, 0x84, 0x07, 0x59, 0x82, 0x09,
-	0xc6, 0xe3, 0x67, 0x5d, 0xbb, 0xa8, 0x5d, 0xcd, 0x46, 0xf5, 0x10, 0x4b, 0x4a, 0x68, 0xc1, 0xf4,
-	0x50, 0xb2, 0x25, 0x55, 0x58, 0x6a, 0xf8, 0xec, 0x11, 0xf2, 0x4e, 0xe0, 0x63, 0xcd, 0xf7, 0xe3,
-	0x76, 0xa4, 0x35, 0x27, 0xda, 0x6d, 0xa0, 0x8d, 0xb8, 0x40, 0x74, 0x8d, 0xee, 0x48, 0xc9, 0x36,
-	0xa8, 0x08, 0xfc, 0x5a, 0x5b, 0x36, 0xed, 0x45, 0x2d, 0xec, 0x00, 0x8b, 0x33, 0x0f, 0xb3, 0xaa,
-	0x44, 0x7b, 0x3d, 0xe4, 0xfc, 0x6c, 0xc1, 0x82, 0x02, 0x36, 0x39, 0x52, 0x89, 0x1e, 0x3e, 0x6d,
-	0xa3, 0x90, 0xe4, 0x93, 0x5c, 0xd5, 0xce, 0x54, 0x77, 0xfe, 0xdd, 0x38, 0xf1, 0xd2, 0xae, 0x4c,
-	0xea, 0xff, 0x1a, 0x14, 0xda, 0x4c, 0x20, 0x97, 0x49, 0x97, 0x25, 0x3b, 0x55, 0x1b, 0x3e, 0xc7,
-	0xba, 0xd8, 0x8f, 0xc2, 0xae, 0x2e, 0xfe, 0x29, 0x2f, 0x03, 0x9c, 0xa7, 0x86, 0xe8, 0x01, 0xab,
-	0x5f, 0x16, 0xd1, 0xea, 0xdf, 0xf3, 0x26, 0xa7, 0x01, 0x13, 0xf1, 0xc9, 0xd7, 0x16, 0x5c, 0xd9,
-	0x0b, 0x84, 0x24, 0xff, 0xcb, 0x0f, 0x9c, 0x74, 0xbc, 0x94, 0xf6, 0x2e, 0x8a, 0x85, 0x4a, 0xe2,
-	0xdc, 0xfc, 0xe2, 0xf7, 0x3f, 0xbf, 0x1d, 0xbb, 0x46, 0x96, 0xf4, 0x67, 0xb5, 0xb3, 0x9e, 0x7d,
-	0xc3, 0x02, 0x14, 0x5f, 0x8e, 0x59, 0xe4, 0x2b, 0x0b, 0xc6, 0x1f, 0xe0, 0x50, 0x36, 0x17, 0xa6,
-	0x89, 0x73, 0x4b, 0x33, 0xb9, 0x41, 0xae, 0x0f, 0x62, 0x52, 0x79, 0xae, 0x76, 0x2f, 0xc8, 0x77,
-	0x16, 0x14, 0x15, 0x6f, 0x2f, 0x67, 0xbb, 0x1c, 0xa1, 0xca, 0xa3, 0x84, 0x22, 0x9f, 0xc2, 0x94,
-	0xa1, 0x75, 0x34, 0x94, 0x4e, 0xb1, 0x1f, 0x3e, 0x12, 0xce, 0xaa, 0x0e, 0xe9, 0x90, 0x95, 0x11,
-	0x27, 0xae, 0x70, 0x15, 0xb2, 0x65, 0xc2, 0xab, 0xcf, 0x13, 0x79, 0xe5, 0x64, 0xf8, 0xf4, 0x76,
-	0x51, 0x2a, 0x0f, 0x32, 0xa5, 0xbd, 0x78, 0xae, 0x74, 0x54, 0xa5, 0xf8, 0xc6, 0x82, 0xb9, 0x07,
-	0x28, 0xb3, 0x7b, 0x00, 0xb9, 0x39, 0x20, 0x72, 0xfe, 0x8e, 0x50, 0x72, 0x86, 0x3b, 0xa4, 0x04,
-	0xde, 0xd1, 0x04, 0xde, 0x74, 0xee, 0x0d, 0x26, 0x60, 0xbe, 0xd6, 0x3a, 0xce, 0x81, 0xb7, 0xa7,
-	0xa9, 0xd4, 0x4d, 0x84, 0xfb, 0xd6, 0x1a, 0xe9, 0x68, 0x4a, 0x3b, 0x18, 0xb6, 0x36, 0x9b, 0x94,
-	0xcb, 0xa1, 0x32, 0x2f, 0xe7, 0xe1, 0xcc, 0x3d, 0x25, 0xe1, 0x6a, 0x12, 0xab, 0xe4, 0xce, 0x28,
-	0x15, 0x9a, 0x18, 0xb6, 0x7c, 0x93, 0xe6, 0x7b, 0x0b, 0x0a, 0x66, 0x7a, 0x91, 0x1b, 0x27, 0x33,
-	0xf6, 0x4d, 0xb5, 0x0b, 0x6c, 0x85, 0x57, 0x35, 0xc7, 0xb2, 0x33, 0xb0, 0xd6, 0xee, 0xeb, 0xe1,
-	0xa1, 0x5a, 0xf3, 0x07, 0x0b, 0x8a, 0x3d, 0x0a, 0xbd, 0x67, 0x2f, 0x8f, 0xa4, 0x73, 0x36, 0x49,
-	0xf2, 0x93, 0x05, 0x05, 0x33, 0x51, 0x4f, 0xf3, 0xea, 0x9b, 0xb4, 0x17, 0xc8, 0x6b, 0xdd, 0xbc,
-	0xe0, 0xd2, 0x88, 0x32, 0xd7, 0x54, 0x5e, 0x64, 0x42, 0xfe, 0x62, 0x41, 0xb1, 0x47, 0x67, 0xb8,
-	0x90, 0xff, 0x15, 0x61, 0xf7, 0xe5, 0x08, 0x13, 0x0a, 0x85, 0x2d, 0x0c, 0x51, 0xe2, 0xb0, 0x16,
-	0xb0, 0x4f, 0xc2, 0x69, 0xf1, 0xdf, 0x31, 0x33, 0x76, 0x6d, 0xd4, 0x8c, 0x55, 0x82, 0x34, 0xa1,
-	0x68, 0x52, 0xe4, 0xf4, 0x78, 0xe9, 0x64, 0xb7, 0xce, 0x91, 0x8c, 0x3c, 0x87, 0xf9, 0x8f, 0x68,
-	0x18, 0x28, 0x65, 0xcd, 0xbd, 0x96, 0x5c, 0x3f, 0x35, 0x49, 0xb2, 0xfb, 0xee, 0x88, 0x6c, 0x55,
-	0x9d, 0xed, 0xae, 0x73, 0x7b, 0x54, 0x5f, 0x77, 0x92, 0x54, 0x46, 0xc9, 0x8d, 0xed, 0x5f, 0x8f,
-	0x97, 0xad, 0xdf, 0x8e, 0x97, 0xad, 0x3f, 0x8e, 0x97, 0xad, 0x8f, 0xdf, 0x3a, 0xdf, 0x1f, 0xa4,
-	0xaf, 0x2f, 0xa6, 0xb9, 0x7f, 0xbd, 0xc3, 0x82, 0xfe, 0xd9, 0x7b, 0xe3, 0x9f, 0x00, 0x00, 0x00,
-	0xff, 0xff, 0x56, 0xc6, 0x8e, 0x59, 0xd1, 0x0e, 0x00, 0x00,
+	// 1380 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xbc, 0x58, 0x4b, 0x6f, 0x1c, 0xc5,
+	0x16, 0x56, 0xfb, 0x31, 0xb1, 0xcb, 0x8f, 0x8c, 0xcb, 0xbe, 0x4e, 0xdf, 0x89, 0xe3, 0xf8, 0x76,
+	0x7c, 0x13, 0x5f, 0x2b, 0xe9, 0x89, 0xe7, 0x0a, 0x81, 0x82, 0x40, 0xf2, 0x4b, 0x89, 0x15, 0x8b,
+	0x84, 0x0e, 0x61, 0x81, 0x40, 0xa8, 0xdc, 0x73, 0x3c, 0xd3, 0x49, 0x4f, 0x77, 0xa5, 0xaa, 0x66,
+	0xc8, 0x28, 0xca, 0x86, 0x55, 0x24, 0xd8, 0x20, 0x84, 0xc4, 0x8e, 0x0d, 0x12, 0x0b, 0x7e, 0x02,
+	0x7f, 0x80, 0x25, 0x12, 0x7f, 0x00, 0x45, 0xfc, 0x03, 0x36, 0x2c, 0x51, 0x9d, 0xea, 0xe9, 0xee,
+	0x79, 0xda, 0x11, 0x26, 0xbb, 0x3e, 0x8f, 0x3e, 0xdf, 0x77, 0x4e, 0x9f, 0x73, 0xaa, 0x66, 0x88,
+	0x23, 0x41, 0xb4, 0x40, 0x94, 0x05, 0xf0, 0x58, 0x06, 0x2a, 0x16, 0xed, 0xdc, 0xa3, 0xcb, 0x45,
+	0xac, 0x62, 0x4a, 0x32, 0x4d, 0x69, 0xa5, 0x16, 0xc7, 0xb5, 0x10, 0xca, 0x8c, 0x07, 0x65, 0x16,
+	0x45, 0xb1, 0x62, 0x2a, 0x88, 0x23, 0x69, 0x3c, 0x4b, 0x87, 0xb5, 0x40, 0xd5, 0x9b, 0x47, 0xae,
+	0x1f, 0x37, 0xca, 0x4c, 0xd4, 0x62, 0x2e, 0xe2, 0x47, 0xf8, 0x70, 0xc3, 0xaf, 0x96, 0x5b, 0x95,
+	0x32, 0x7f, 0x5c, 0xd3, 0x6f, 0xca, 0x32, 0xe3, 0x3c, 0x0c, 0x7c, 0x7c, 0xb7, 0xdc, 0xda, 0x62,
+	0x21, 0xaf, 0xb3, 0xad, 0x72, 0x0d, 0x22, 0x10, 0x4c, 0x41, 0x35, 0x89, 0xb6, 0x7f, 0x42, 0x34,
+	0xa4, 0x75, 0x22, 0x7d, 0xa7, 0x4d, 0xe6, 0x3c, 0xe0, 0xf1, 0x36, 0xe7, 0xf2, 0xfd, 0x26, 0x88,
+	0x36, 0xa5, 0x64, 0x42, 0x3b, 0xd9, 0xd6, 0x9a, 0xb5, 0x31, 0xed, 0xe1, 0x33, 0x2d, 0x91, 0x29,
+	0x01, 0xad, 0x40, 0x06, 0x71, 0x64, 0x8f, 0xa1, 0x3e, 0x95, 0xa9, 0x4d, 0xce, 0x31, 0xce, 0xdf,
+	0x63, 0x0d, 0xb0, 0xc7, 0xd1, 0xd4, 0x11, 0xe9, 0x2a, 0x21, 0x8c, 0xf3, 0xfb, 0x22, 0x7e, 0x04,
+	0xbe, 0xb2, 0x27, 0xd0, 0x98, 0xd3, 0x38, 0x5b, 0xe4, 0xdc, 0x36, 0xe7, 0x07, 0xd1, 0x71, 0xac,
+	0x41, 0x55, 0x9b, 0x43, 0x07, 0x54, 0x3f, 0x6b, 0x1d, 0x67, 0xaa, 0x9e, 0x00, 0xe2, 0xb3, 0xf3,
+	0xa7, 0x45, 0x16, 0x13, 0xba, 0x7b, 0xa0, 0x58, 0x10, 0x26, 0xa4, 0x6b, 0xa4, 0x20, 0xe3, 0xa6,
+	0xf0, 0x4d, 0x84, 0x99, 0xca, 0x3d, 0x37, 0xab, 0x8e, 0xdb, 0xa9, 0x0e, 0x3e, 0x7c, 0xea, 0x57,
+	0xdd, 0x56, 0xc5, 0xe5, 0x8f, 0x6b, 0xae, 0xae, 0xb5, 0x9b, 0xab, 0xb5, 0xdb, 0xa9, 0xb5, 0xbb,
+	0x9d, 0x29, 0x1f, 0x60, 0x58, 0x2f, 0x09, 0x9f, 0xcf, 0x76, 0x6c, 0x54, 0xb6, 0xe3, 0xbd, 0xd9,
+	0xd2, 0x35, 0x32, 0x63, 0x62, 0x1c, 0x44, 0x55, 0x78, 0x8a, 0xe5, 0x98, 0xf4, 0xf2, 0x2a, 0xba,
+	0x42, 0xa6, 0x5b, 0x20, 0x74, 0x51, 0x0f, 0xaa, 0xf6, 0x24, 0xda, 0x33, 0x85, 0xf3, 0x0e, 0x29,
+	0x76, 0x3e, 0x94, 0x07, 0x92, 0xc7, 0x91, 0x04, 0xfa, 0x3f, 0x32, 0x19, 0x28, 0x68, 0x48, 0xdb,
+	0x5a, 0x1b, 0xdf, 0x98, 0xa9, 0x2c, 0xba, 0xb9, 0xcf, 0x9b, 0x94, 0xd6, 0x33, 0x1e, 0x8e, 0x4f,
+	0xa6, 0xf5, 0xeb, 0xc3, 0xbf, 0xb1, 0x43, 0x66, 0x8f, 0x63, 0x9d, 0x2a, 0x1c, 0x0b, 0x90, 0xa6,
+	0xec, 0x53, 0x5e, 0x97, 0xee, 0xa4, 0x1c, 0x9d, 0x9f, 0x26, 0xc9, 0x79, 0x24, 0xe9, 0xfb, 0x20,
+	0x47, 0xf7, 0x53, 0x53, 0x82, 0x88, 0xb2, 0x32, 0xa6, 0xb2, 0xb6, 0x71, 0x26, 0xe5, 0x67, 0xb1,
+	0xa8, 0x26, 0x08, 0xa9, 0x4c, 0xd7, 0xc9, 0x9c, 0x94, 0xf5, 0xfb, 0x22, 0x68, 0x31, 0x05, 0x77,
+	0xa1, 0x9d, 0x34, 0x55, 0xb7, 0x52, 0x47, 0x08, 0x22, 0x09, 0x7e, 0x53, 0x00, 0x96, 0x71, 0xca,
+	0x4b, 0x65, 0x7a, 0x9d, 0x2c, 0xa8, 0x50, 0xee, 0x86, 0x01, 0x44, 0x6a, 0x17, 0x84, 0xda, 0x63,
+	0x8a, 0xd9, 0x05, 0x8c, 0xd2, 0x6f, 0xa0, 0x9b, 0xa4, 0xd8, 0xa5, 0xd4, 0x90, 0xe7, 0xd0, 0xb9,
+	0x4f, 0x9f, 0xb6, 0xf0, 0x74, 0x77, 0x0b, 0x63, 0x8e, 0xc4, 0xe8, 0x30, 0xbf, 0x15, 0x32, 0x0d,
+	0x11, 0x3b, 0x0a, 0xe1, 0x9e, 0x1f, 0xd8, 0x33, 0x48, 0x2f, 0x53, 0xd0, 0x9b, 0x64, 0xd1, 0x74,
+	0xee, 0x36, 0xe7, 0x59, 0x4a, 0xf6, 0x2c, 0x06, 0x18, 0x64, 0xd2, 0x7d, 0x95, 0xaa, 0x0f, 0xf6,
+	0xec, 0xb9, 0x35, 0x6b, 0x63, 0xdc, 0xcb, 0xab, 0xe8, 0x5b, 0xe4, 0x42, 0x26, 0x46, 0x52, 0xb1,
+	0x30, 0xc4, 0xd6, 0x3e, 0xd8, 0xb3, 0xe7, 0xd1, 0x7b, 0x98, 0x99, 0xbe, 0x4b, 0x4a, 0xa9, 0x69,
+	0x3f, 0x52, 0x20, 0xb8, 0x08, 0x24, 0xec, 0x30, 0x09, 0x0f, 0x45, 0x68, 0x9f, 0x47, 0x52, 0x23,
+	0x3c, 0xe8, 0x12, 0x99, 0xe4, 0x22, 0x7e, 0xda, 0xb6, 0x8b, 0xe8, 0x6a, 0x04, 0x3d, 0x43, 0x3c,
+	0x69, 0xa1, 0x05, 0x33, 0x43, 0x89, 0x48, 0x2b, 0x64, 0xa9, 0xe6, 0xf3, 0x07, 0x20, 0x5a, 0x81,
+	0x0f, 0xdb, 0xbe, 0x1f, 0x37, 0x23, 0xac, 0x39, 0x45, 0xb7, 0x81, 0x36, 0xea, 0x12, 0x8a, 0x3d,
+	0x7a, 0x47, 0x29, 0xbe, 0xc3, 0x64, 0xe0, 0x6f, 0x37, 0x55, 0xdd, 0x5e, 0xc4, 0xc2, 0x0e, 0xb0,
+	0x68, 0xf4, 0xd8, 0x0f, 0x3e, 0xd0, 0x9f, 0x6a, 0xc9, 0xa0, 0x27, 0xa2, 0x33, 0x4f, 0x66, 0x75,
+	0xf3, 0x76, 0xa6, 0xcb, 0xf9, 0xc1, 0x22, 0x0b, 0x5a, 0xb1, 0x2b, 0x80, 0x29, 0xf0, 0xe0, 0x49,
+	0x13, 0xa4, 0xa2, 0x1f, 0xe7, 0xfa, 0x79, 0xa6, 0x72, 0xe7, 0xef, 0x2d, 0x1a, 0x2f, 0x9d, 0xd7,
+	0x64, 0x32, 0x96, 0x49, 0xa1, 0xc9, 0x25, 0x08, 0x95, 0xcc, 0x5f, 0x22, 0xe9, 0xae, 0xf1, 0x05,
+	0x54, 0xe5, 0xbd, 0x28, 0x6c, 0xe3, 0x58, 0x4c, 0x79, 0x99, 0xc2, 0x79, 0x62, 0x88, 0x3e, 0xe4,
+	0xd5, 0xd7, 0x45, 0xd4, 0x79, 0x4e, 0x2e, 0x68, 0xdd, 0x41, 0x83, 0xc7, 0x42, 0xed, 0x34, 0xa3,
+	0x6a, 0x98, 0x02, 0x0f, 0x9a, 0xf8, 0x65, 0x52, 0x38, 0x42, 0x27, 0xcc, 0x6b, 0xd6, 0x4b, 0x24,
+	0x93, 0xaf, 0x66, 0x9d, 0x24, 0x95, 0x48, 0x27, 0x9e, 0x1d, 0x2e, 0xb1, 0xfb, 0xe1, 0x93, 0xad,
+	0x88, 0xf8, 0xc7, 0x66, 0x29, 0x22, 0xfe, 0xb1, 0x74, 0x76, 0xcd, 0xb9, 0xb1, 0xcb, 0xfc, 0x3a,
+	0x54, 0xef, 0x42, 0x3b, 0x59, 0x4e, 0xcb, 0xa4, 0xe0, 0x37, 0x85, 0x8c, 0x05, 0x92, 0x9d, 0xf0,
+	0x12, 0x49, 0x37, 0x2e, 0x36, 0x18, 0xb2, 0x1d, 0xf7, 0x8c, 0xe0, 0x5c, 0x27, 0x54, 0x07, 0xe9,
+	0x81, 0xcb, 0x52, 0xb3, 0xf2, 0xa9, 0x55, 0xfe, 0x58, 0x20, 0x0b, 0x59, 0xd9, 0x92, 0xc6, 0xa5,
+	0x5f, 0x5a, 0x64, 0xe2, 0x30, 0x90, 0x8a, 0xfe, 0x2b, 0xbf, 0xac, 0xd3, 0xd5, 0x5c, 0x3a, 0x3c,
+	0xab, 0xef, 0xa4, 0x41, 0x9c, 0xcb, 0x9f, 0xff, 0xfa, 0xfb, 0xd7, 0x63, 0xcb, 0x74, 0x09, 0xaf,
+	0x24, 0xad, 0xad, 0xec, 0xfc, 0x0f, 0x40, 0xbe, 0x18, 0xb3, 0xe8, 0x17, 0x16, 0x19, 0xbf, 0x0d,
+	0x43, 0xd9, 0x9c, 0x59, 0xd7, 0x38, 0x57, 0x90, 0xc9, 0x25, 0x7a, 0x71, 0x10, 0x93, 0xf2, 0x33,
+	0x2d, 0x3d, 0xa7, 0xdf, 0x58, 0xa4, 0xa8, 0x79, 0x7b, 0x39, 0xdb, 0xeb, 0x29, 0xd4, 0xca, 0xa8,
+	0x42, 0xd1, 0x4f, 0xc8, 0x94, 0xa1, 0x75, 0x3c, 0x94, 0x4e, 0xb1, 0x5b, 0x7d, 0x2c, 0x9d, 0x0d,
+	0x0c, 0xe9, 0xd0, 0xb5, 0x11, 0x19, 0x97, 0x75, 0x73, 0xd2, 0x86, 0x09, 0xaf, 0x8f, 0x76, 0xfa,
+	0xef, 0xde, 0xf0, 0xe9, 0xcd, 0xac, 0xb4, 0x32, 0xc8, 0x94, 0x6e, 0xab, 0x53, 0xc1, 0x31, 0x0d,
+	0xf1, 0x95, 0x45, 0xe6, 0x6e, 0x83, 0xca, 0xee, 0x50, 0xf4, 0xf2, 0x80, 0xc8, 0xf9, 0xfb, 0x55,
+	0xc9, 0x19, 0xee, 0x90, 0x12, 0x78, 0x1b, 0x09, 0xbc, 0xe1, 0xdc, 0x1c, 0x4c, 0xc0, 0xdc, 0x74,
+	0x30, 0xce, 0x43, 0xef, 0x10, 0xa9, 0x54, 0x4d, 0x84, 0x5b, 0xd6, 0x26, 0x6d, 0x21, 0xa5, 0x3b,
+	0x10, 0x36, 0x76, 0xeb, 0x4c, 0xa8, 0xa1, 0x65, 0x5e, 0xcd, 0xab, 0x33, 0xf7, 0x94, 0x84, 0x8b,
+	0x24, 0x36, 0xe8, 0xd5, 0x51, 0x55, 0xa8, 0x43, 0xd8, 0xf0, 0x0d, 0xcc, 0xb7, 0x16, 0x29, 0x98,
+	0xfd, 0x4e, 0x2f, 0xf5, 0x22, 0x76, 0xed, 0xfd, 0x33, 0x1c, 0x85, 0xff, 0x22, 0xc7, 0x15, 0x67,
+	0x60, 0xaf, 0xdd, 0xc2, 0x7d, 0xa9, 0x47, 0xf3, 0x3b, 0x8b, 0x14, 0x3b, 0x14, 0x3a, 0xef, 0xbe,
+	0x3e, 0x92, 0xce, 0xc9, 0x24, 0xe9, 0xf7, 0x16, 0x29, 0x98, 0x33, 0xa7, 0x9f, 0x57, 0xd7, 0x59,
+	0x74, 0x86, 0xbc, 0xb6, 0xcc, 0x07, 0x2e, 0x8d, 0x68, 0x73, 0xa4, 0xf2, 0x3c, 0x2b, 0xe4, 0x8f,
+	0x16, 0x29, 0x76, 0xe8, 0x0c, 0x2f, 0xe4, 0x3f, 0x45, 0xd8, 0x7d, 0x35, 0xc2, 0x94, 0x91, 0xc2,
+	0x1e, 0x84, 0xa0, 0x60, 0xd8, 0x08, 0xd8, 0xbd, 0xea, 0xb4, 0xf9, 0xaf, 0x9a, 0x1d, 0xbb, 0x39,
+	0x6a, 0xc7, 0xea, 0x82, 0xd4, 0x49, 0xd1, 0x40, 0xe4, 0xea, 0xf1, 0xca, 0x60, 0x57, 0x4e, 0x01,
+	0x46, 0x9f, 0x91, 0xf9, 0x0f, 0x59, 0x18, 0xe8, 0xca, 0x9a, 0xdf, 0x04, 0xf4, 0x62, 0xdf, 0x26,
+	0xc9, 0x7e, 0x2b, 0x8c, 0x40, 0xab, 0x20, 0xda, 0x75, 0x67, 0x7d, 0xd4, 0x5c, 0xb7, 0x12, 0xa8,
+	0xa4, 0x92, 0x2f, 0x2c, 0x32, 0x9b, 0xbf, 0x20, 0xd0, 0x2b, 0xbd, 0xe1, 0x07, 0xdc, 0x5e, 0x4a,
+	0xeb, 0xa3, 0x9d, 0x12, 0x3e, 0x37, 0x90, 0xcf, 0x35, 0xc7, 0x19, 0xc5, 0xc7, 0x5c, 0x04, 0xf4,
+	0x7a, 0x7b, 0x42, 0x66, 0xf7, 0x9f, 0xe6, 0x98, 0x9c, 0x66, 0xbb, 0xf5, 0x5f, 0x35, 0x9c, 0x4d,
+	0x44, 0x5d, 0xa7, 0xa7, 0x40, 0xa5, 0x4d, 0x32, 0xaf, 0x0f, 0x95, 0xec, 0xc6, 0xd3, 0xbf, 0xe5,
+	0x7b, 0x6e, 0x43, 0xa5, 0xae, 0xb3, 0x27, 0x35, 0xe2, 0xf9, 0x78, 0x0d, 0x91, 0xff, 0x43, 0x2f,
+	0x0f, 0x44, 0xf6, 0xb5, 0x6f, 0xf9, 0x31, 0xb4, 0xe5, 0xce, 0xfe, 0xcf, 0x2f, 0x57, 0xad, 0x5f,
+	0x5e, 0xae, 0x5a, 0xbf, 0xbd, 0x5c, 0xb5, 0x3e, 0x7a, 0xf3, 0x74, 0x7f, 0x79, 0xf8, 0xf8, 0x4b,
+	0x2a, 0x8b, 0xd9, 0x3e, 0x2a, 0xe0, 0xbf, 0x13, 0xff, 0xff, 0x6b, 0x00, 0x26, 0x2f, 0x85, 0xcc,
+	0x82, 0x11, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -846,6 +1102,12 @@ type RepositoryServiceClient interface {
 	DeleteRepository(ctx context.Context, in *RepoQuery, opts ...grpc.CallOption) (*RepoResponse, error)
 	// ValidateAccess validates access to a repository with given parameters
 	ValidateAccess(ctx context.Context, in *RepoAccessQuery, opts ...grpc.CallOption) (*RepoResponse, error)
+	// ImportBundle imports a git bundle into the local copy of a repository, e.g. for repositories which cannot be reached in air-gapped environments
+	ImportBundle(ctx context.Context, in *RepoImportBundleRequest, opts ...grpc.CallOption) (*RepoImportBundleResponse, error)
+	// ExportBundle creates a git bundle containing all references of a repository
+	ExportBundle(ctx context.Context, in *RepoQuery, opts ...grpc.CallOption) (*RepoBundleResponse, error)
+	// ListCachedKeys returns a page of the keys stored by the repo server in the cache, along with their metadata
+	ListCachedKeys(ctx context.Context, in *RepoCachedKeysQuery, opts ...grpc.CallOption) (*apiclient.CachedKeyList, error)
 }
 
 type repositoryServiceClient struct {
@@ -986,6 +1248,33 @@ func (c *repositoryServiceClient) ValidateAccess(ctx context.Context, in *RepoAc
 	return out, nil
 }
 
+func (c *repositoryServiceClient) ImportBundle(ctx context.Context, in *RepoImportBundleRequest, opts ...grpc.CallOption) (*RepoImportBundleResponse, error) {
+	out := new(RepoImportBundleResponse)
+	err := c.cc.Invoke(ctx, "/repository.RepositoryService/ImportBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repositoryServiceClient) ExportBundle(ctx context.Context, in *RepoQuery, opts ...grpc.CallOption) (*RepoBundleResponse, error) {
+	out := new(RepoBundleResponse)
+	err := c.cc.Invoke(ctx, "/repository.RepositoryService/ExportBundle", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *repositoryServiceClient) ListCachedKeys(ctx context.Context, in *RepoCachedKeysQuery, opts ...grpc.CallOption) (*apiclient.CachedKeyList, error) {
+	out := new(apiclient.CachedKeyList)
+	err := c.cc.Invoke(ctx, "/repository.RepositoryService/ListCachedKeys", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RepositoryServiceServer is the server API for RepositoryService service.
 type RepositoryServiceServer interface {
 	// List returns list of repos or repository credentials
@@ -1015,6 +1304,12 @@ type RepositoryServiceServer interface {
 	DeleteRepository(context.Context, *RepoQuery) (*RepoResponse, error)
 	// ValidateAccess validates access to a repository with given parameters
 	ValidateAccess(context.Context, *RepoAccessQuery) (*RepoResponse, error)
+	// ImportBundle imports a git bundle into the local copy of a repository, e.g. for repositories which cannot be reached in air-gapped environments
+	ImportBundle(context.Context, *RepoImportBundleRequest) (*RepoImportBundleResponse, error)
+	// ExportBundle creates a git bundle containing all references of a repository
+	ExportBundle(context.Context, *RepoQuery) (*RepoBundleResponse, error)
+	// ListCachedKeys returns a page of the keys stored by the repo server in the cache, along with their metadata
+	ListCachedKeys(context.Context, *RepoCachedKeysQuery) (*apiclient.CachedKeyList, error)
 }
 
 // UnimplementedRepositoryServiceServer can be embedded to have forward compatible implementations.
@@ -1063,6 +1358,15 @@ func (*UnimplementedRepositoryServiceServer) DeleteRepository(ctx context.Contex
 func (*UnimplementedRepositoryServiceServer) ValidateAccess(ctx context.Context, req *RepoAccessQuery) (*RepoResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ValidateAccess not implemented")
 }
+func (*UnimplementedRepositoryServiceServer) ImportBundle(ctx context.Context, req *RepoImportBundleRequest) (*RepoImportBundleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportBundle not implemented")
+}
+func (*UnimplementedRepositoryServiceServer) ExportBundle(ctx context.Context, req *RepoQuery) (*RepoBundleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportBundle not implemented")
+}
+func (*UnimplementedRepositoryServiceServer) ListCachedKeys(ctx context.Context, req *RepoCachedKeysQuery) (*apiclient.CachedKeyList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCachedKeys not implemented")
+}
 
 func RegisterRepositoryServiceServer(s *grpc.Server, srv RepositoryServiceServer) {
 	s.RegisterService(&_RepositoryService_serviceDesc, srv)
@@ -1320,6 +1624,60 @@ func _RepositoryService_ValidateAccess_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _RepositoryService_ImportBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RepoImportBundleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryServiceServer).ImportBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/repository.RepositoryService/ImportBundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryServiceServer).ImportBundle(ctx, req.(*RepoImportBundleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepositoryService_ExportBundle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RepoQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryServiceServer).ExportBundle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/repository.RepositoryService/ExportBundle",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryServiceServer).ExportBundle(ctx, req.(*RepoQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RepositoryService_ListCachedKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RepoCachedKeysQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RepositoryServiceServer).ListCachedKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/repository.RepositoryService/ListCachedKeys",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RepositoryServiceServer).ListCachedKeys(ctx, req.(*RepoCachedKeysQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _RepositoryService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "repository.RepositoryService",
 	HandlerType: (*RepositoryServiceServer)(nil),
@@ -1380,6 +1738,18 @@ var _RepositoryService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "ValidateAccess",
 			Handler:    _RepositoryService_ValidateAccess_Handler,
 		},
+		{
+			MethodName: "ImportBundle",
+			Handler:    _RepositoryService_ImportBundle_Handler,
+		},
+		{
+			MethodName: "ExportBundle",
+			Handler:    _RepositoryService_ExportBundle_Handler,
+		},
+		{
+			MethodName: "ListCachedKeys",
+			Handler:    _RepositoryService_ListCachedKeys_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "server/repository/repository.proto",
@@ -1660,6 +2030,15 @@ func (m *RepoAccessQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.OciType) > 0 {
+		i -= len(m.OciType)
+		copy(dAtA[i:], m.OciType)
+		i = encodeVarintRepository(dAtA, i, uint64(len(m.OciType)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0xa2
+	}
 	if m.ForceHttpBasicAuth {
 		i--
 		if m.ForceHttpBasicAuth {
@@ -1927,34 +2306,199 @@ func (m *RepoUpdateRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintRepository(dAtA []byte, offset int, v uint64) int {
-	offset -= sovRepository(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *RepoImportBundleRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *RepoAppsQuery) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *RepoImportBundleRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RepoImportBundleRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	l = len(m.Repo)
-	if l > 0 {
-		n += 1 + l + sovRepository(uint64(l))
-	}
-	l = len(m.Revision)
-	if l > 0 {
-		n += 1 + l + sovRepository(uint64(l))
-	}
-	l = len(m.AppName)
-	if l > 0 {
-		n += 1 + l + sovRepository(uint64(l))
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.AppProject) > 0 {
+		i -= len(m.AppProject)
+		copy(dAtA[i:], m.AppProject)
+		i = encodeVarintRepository(dAtA, i, uint64(len(m.AppProject)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if m.Update {
+		i--
+		if m.Update {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Bundle) > 0 {
+		i -= len(m.Bundle)
+		copy(dAtA[i:], m.Bundle)
+		i = encodeVarintRepository(dAtA, i, uint64(len(m.Bundle)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Repo) > 0 {
+		i -= len(m.Repo)
+		copy(dAtA[i:], m.Repo)
+		i = encodeVarintRepository(dAtA, i, uint64(len(m.Repo)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RepoImportBundleResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RepoImportBundleResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RepoImportBundleResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Refs) > 0 {
+		for iNdEx := len(m.Refs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Refs[iNdEx])
+			copy(dAtA[i:], m.Refs[iNdEx])
+			i = encodeVarintRepository(dAtA, i, uint64(len(m.Refs[iNdEx])))
+			i--
+			dAtA[i] = 0xa
+		}
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RepoCachedKeysQuery) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RepoCachedKeysQuery) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RepoCachedKeysQuery) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Count != 0 {
+		i = encodeVarintRepository(dAtA, i, uint64(m.Count))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Cursor != 0 {
+		i = encodeVarintRepository(dAtA, i, uint64(m.Cursor))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *RepoBundleResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *RepoBundleResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *RepoBundleResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if len(m.Bundle) > 0 {
+		i -= len(m.Bundle)
+		copy(dAtA[i:], m.Bundle)
+		i = encodeVarintRepository(dAtA, i, uint64(len(m.Bundle)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintRepository(dAtA []byte, offset int, v uint64) int {
+	offset -= sovRepository(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *RepoAppsQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Repo)
+	if l > 0 {
+		n += 1 + l + sovRepository(uint64(l))
+	}
+	l = len(m.Revision)
+	if l > 0 {
+		n += 1 + l + sovRepository(uint64(l))
+	}
+	l = len(m.AppName)
+	if l > 0 {
+		n += 1 + l + sovRepository(uint64(l))
 	}
 	l = len(m.AppProject)
 	if l > 0 {
@@ -2130,6 +2674,10 @@ func (m *RepoAccessQuery) Size() (n int) {
 	if m.ForceHttpBasicAuth {
 		n += 3
 	}
+	l = len(m.OciType)
+	if l > 0 {
+		n += 2 + l + sovRepository(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -2186,6 +2734,85 @@ func (m *RepoUpdateRequest) Size() (n int) {
 	return n
 }
 
+func (m *RepoImportBundleRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Repo)
+	if l > 0 {
+		n += 1 + l + sovRepository(uint64(l))
+	}
+	l = len(m.Bundle)
+	if l > 0 {
+		n += 1 + l + sovRepository(uint64(l))
+	}
+	if m.Update {
+		n += 2
+	}
+	l = len(m.AppProject)
+	if l > 0 {
+		n += 1 + l + sovRepository(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *RepoImportBundleResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Refs) > 0 {
+		for _, s := range m.Refs {
+			l = len(s)
+			n += 1 + l + sovRepository(uint64(l))
+		}
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *RepoCachedKeysQuery) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Cursor != 0 {
+		n += 1 + sovRepository(uint64(m.Cursor))
+	}
+	if m.Count != 0 {
+		n += 1 + sovRepository(uint64(m.Count))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *RepoBundleResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Bundle)
+	if l > 0 {
+		n += 1 + l + sovRepository(uint64(l))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovRepository(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -3438,6 +4065,38 @@ func (m *RepoAccessQuery) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.ForceHttpBasicAuth = bool(v != 0)
+		case 20:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field OciType", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRepository
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRepository
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRepository
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.OciType = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipRepository(dAtA[iNdEx:])
@@ -3725,6 +4384,432 @@ func (m *RepoUpdateRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *RepoImportBundleRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRepository
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RepoImportBundleRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RepoImportBundleRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Repo", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRepository
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRepository
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRepository
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Repo = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bundle", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRepository
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRepository
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRepository
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Bundle = append(m.Bundle[:0], dAtA[iNdEx:postIndex]...)
+			if m.Bundle == nil {
+				m.Bundle = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Update", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRepository
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Update = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppProject", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRepository
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRepository
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRepository
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.AppProject = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRepository(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRepository
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RepoImportBundleResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRepository
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RepoImportBundleResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RepoImportBundleResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Refs", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRepository
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthRepository
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRepository
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Refs = append(m.Refs, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRepository(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRepository
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RepoCachedKeysQuery) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRepository
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RepoCachedKeysQuery: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RepoCachedKeysQuery: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cursor", wireType)
+			}
+			m.Cursor = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRepository
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Cursor |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			m.Count = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRepository
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Count |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRepository(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRepository
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *RepoBundleResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowRepository
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: RepoBundleResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: RepoBundleResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Bundle", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowRepository
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthRepository
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthRepository
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Bundle = append(m.Bundle[:0], dAtA[iNdEx:postIndex]...)
+			if m.Bundle == nil {
+				m.Bundle = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipRepository(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if (skippy < 0) || (iNdEx+skippy) < 0 {
+				return ErrInvalidLengthRepository
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipRepository(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -24,4 +24,11 @@ const (
 	ApplicationSetShortName string = "appset"
 	ApplicationSetPlural    string = "applicationsets"
 	ApplicationSetFullName  string = ApplicationSetPlural + "." + Group
+
+	// RepositoryConfig constants
+	RepositoryConfigKind      string = "RepositoryConfig"
+	RepositoryConfigSingular  string = "repositoryconfig"
+	RepositoryConfigShortName string = "repoconfig"
+	RepositoryConfigPlural    string = "repositoryconfigs"
+	RepositoryConfigFullName  string = RepositoryConfigPlural + "." + Group
 )
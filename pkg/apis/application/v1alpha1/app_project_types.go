@@ -2,6 +2,7 @@ package v1alpha1
 
 import (
 	"fmt"
+	"net/url"
 	"sort"
 	"strconv"
 	"strings"
@@ -15,6 +16,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 type ErrApplicationNotAllowedToUseProject struct {
@@ -251,7 +253,7 @@ func (p *AppProject) ValidateProject() error {
 			if window == nil {
 				continue
 			}
-			if _, ok := existingWindows[window.Kind+window.Schedule+window.Duration]; ok {
+			if _, ok := existingWindows[window.Kind+window.Schedule+window.Duration+window.ChronoSchedule]; ok {
 				return status.Errorf(codes.AlreadyExists, "window '%s':'%s':'%s' already exists, update or edit", window.Kind, window.Schedule, window.Duration)
 			}
 			err := window.Validate()
@@ -261,10 +263,65 @@ func (p *AppProject) ValidateProject() error {
 			if len(window.Applications) == 0 && len(window.Namespaces) == 0 && len(window.Clusters) == 0 {
 				return status.Errorf(codes.OutOfRange, "window '%s':'%s':'%s' requires one of application, cluster or namespace", window.Kind, window.Schedule, window.Duration)
 			}
-			existingWindows[window.Kind+window.Schedule+window.Duration] = true
+			existingWindows[window.Kind+window.Schedule+window.Duration+window.ChronoSchedule] = true
 		}
 	}
 
+	if p.Spec.PostSyncWebhook != nil {
+		if err := p.Spec.PostSyncWebhook.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if policy := p.Spec.HistoryRetentionPolicy; policy != nil && (policy.MaxEntries < 0 || policy.MaxAge.Duration < 0) {
+		return status.Errorf(codes.InvalidArgument, "history retention policy must not have a negative maxEntries or maxAge")
+	}
+
+	if policy := p.Spec.SyncPolicy; policy != nil && policy.ClusterVersionCheck != "" && policy.ClusterVersionCheck != ClusterVersionCheckPreSync {
+		return status.Errorf(codes.InvalidArgument, "cluster version check '%s' is not supported, only '%s' is", policy.ClusterVersionCheck, ClusterVersionCheckPreSync)
+	}
+
+	if p.Spec.DestinationServiceAccount != "" {
+		namespace, name := p.GetDestinationServiceAccount("default")
+		if len(validation.IsDNS1123Label(namespace)) > 0 || len(validation.IsDNS1123Subdomain(name)) > 0 {
+			return status.Errorf(codes.InvalidArgument, "destination service account '%s' must be formatted as '<namespace>:<name>' or '<name>'", p.Spec.DestinationServiceAccount)
+		}
+	}
+
+	return nil
+}
+
+// GetDestinationServiceAccount returns the namespace and the name of the service account impersonated to sync the
+// applications of the project to the given destination namespace, or empty strings if the application controller syncs
+// them with its own credentials
+func (p *AppProject) GetDestinationServiceAccount(destNamespace string) (string, string) {
+	if p.Spec.DestinationServiceAccount == "" {
+		return "", ""
+	}
+	if namespace, name, ok := strings.Cut(p.Spec.DestinationServiceAccount, ":"); ok {
+		return namespace, name
+	}
+	return destNamespace, p.Spec.DestinationServiceAccount
+}
+
+// postSyncWebhookCredentialHeaders are the headers whose values must reference a key of argocd-secret, so that
+// credentials are not readable by everyone allowed to read the project
+var postSyncWebhookCredentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// Validate checks that the URL of the webhook is an absolute HTTP(S) URL and that credential headers reference a
+// secret key instead of holding the credentials
+func (w *PostSyncWebhook) Validate() error {
+	webhookURL, err := url.Parse(w.URL)
+	if err != nil || (webhookURL.Scheme != "http" && webhookURL.Scheme != "https") || webhookURL.Host == "" {
+		return status.Errorf(codes.InvalidArgument, "post-sync webhook URL '%s' must be an absolute http or https URL", w.URL)
+	}
+	for name, value := range w.Headers {
+		for _, credentialHeader := range postSyncWebhookCredentialHeaders {
+			if strings.EqualFold(name, credentialHeader) && !strings.HasPrefix(value, "$") {
+				return status.Errorf(codes.InvalidArgument, "post-sync webhook header '%s' must reference a key of argocd-secret, such as '$webhook.token'", name)
+			}
+		}
+	}
 	return nil
 }
 
@@ -360,6 +417,31 @@ func (proj AppProject) IsGroupKindPermitted(gk schema.GroupKind, namespaced bool
 	return isWhiteListed && !isBlackListed
 }
 
+// IsClusterVersionCheckPreSync returns true if the API versions of the resources of the applications in the project are
+// checked against the destination cluster before they are synced
+func (proj AppProject) IsClusterVersionCheckPreSync() bool {
+	return proj.Spec.SyncPolicy != nil && proj.Spec.SyncPolicy.ClusterVersionCheck == ClusterVersionCheckPreSync
+}
+
+// IsNamespaceIsolationStrict returns true if the applications in the project may only create resources in their destination namespace
+func (proj AppProject) IsNamespaceIsolationStrict() bool {
+	return proj.Spec.NamespaceIsolation == NamespaceIsolationStrict
+}
+
+// IsClusterResourceExplicitlyPermitted validates if the given cluster-scoped resource group/kind is listed in the cluster
+// resource whitelist of the project without wildcards, and is not blacklisted
+func (proj AppProject) IsClusterResourceExplicitlyPermitted(gk schema.GroupKind) bool {
+	if !proj.IsGroupKindPermitted(gk, false) {
+		return false
+	}
+	for _, item := range proj.Spec.ClusterResourceWhitelist {
+		if item.Group == gk.Group && item.Kind == gk.Kind {
+			return true
+		}
+	}
+	return false
+}
+
 // IsLiveResourcePermitted returns whether a live resource found in the cluster is permitted by an AppProject
 func (proj AppProject) IsLiveResourcePermitted(un *unstructured.Unstructured, server string, name string, projectClusters func(project string) ([]*Cluster, error)) (bool, error) {
 	return proj.IsResourcePermitted(un.GroupVersionKind().GroupKind(), un.GetNamespace(), ApplicationDestination{Server: server, Name: name}, projectClusters)
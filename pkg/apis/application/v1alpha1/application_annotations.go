@@ -10,4 +10,12 @@ const (
 	// absolute path means an absolute path within the repository and the relative path is relative to the application
 	// source path within the repository.
 	AnnotationKeyManifestGeneratePaths = "argocd.argoproj.io/manifest-generate-paths"
+
+	// AnnotationKeyPostSyncWebhookStatus is the annotation key which contains the delivery status of the post-sync
+	// webhook for the last sync operation. Might take values 'Delivered'/'Failed'.
+	AnnotationKeyPostSyncWebhookStatus = "argocd.argoproj.io/post-sync-webhook-status"
+
+	// AnnotationKeyPostSyncWebhookMessage is the annotation key which contains the reason the post-sync webhook for
+	// the last sync operation could not be delivered
+	AnnotationKeyPostSyncWebhookMessage = "argocd.argoproj.io/post-sync-webhook-message"
 )
@@ -72,6 +72,10 @@ type ApplicationSetSpec struct {
 	ApplyNestedSelectors         bool                            `json:"applyNestedSelectors,omitempty" protobuf:"bytes,8,name=applyNestedSelectors"`
 	IgnoreApplicationDifferences ApplicationSetIgnoreDifferences `json:"ignoreApplicationDifferences,omitempty" protobuf:"bytes,9,name=ignoreApplicationDifferences"`
 	TemplatePatch                *string                         `json:"templatePatch,omitempty" protobuf:"bytes,10,name=templatePatch"`
+	// PropagateMetadata enables propagating the labels and annotations of the ApplicationSet to the generated Applications
+	PropagateMetadata bool `json:"propagateMetadata,omitempty" protobuf:"bytes,11,name=propagateMetadata"`
+	// PropagateLabels enables propagating the labels of the ApplicationSet to the generated Applications
+	PropagateLabels bool `json:"propagateLabels,omitempty" protobuf:"bytes,12,name=propagateLabels"`
 }
 
 type ApplicationPreservedFields struct {
@@ -281,13 +285,14 @@ type MatrixGenerator struct {
 
 // NestedMatrixGenerator is a MatrixGenerator nested under another combination-type generator (MatrixGenerator or
 // MergeGenerator). NestedMatrixGenerator does not have an override template, because template overriding has no meaning
-// within the constituent generators of combination-type generators.
+// within the constituent generators of combination-type generators. Its child generators may themselves be nested
+// matrix generators, up to the maximum depth configured on the ApplicationSet controller.
 //
 // NOTE: Nested matrix generator is not included directly in the CRD struct, instead it is included
 // as a generic 'apiextensionsv1.JSON' object, and then marshalled into a NestedMatrixGenerator
 // when processed.
 type NestedMatrixGenerator struct {
-	Generators ApplicationSetTerminalGenerators `json:"generators" protobuf:"bytes,1,name=generators"`
+	Generators ApplicationSetNestedGenerators `json:"generators" protobuf:"bytes,1,name=generators"`
 }
 
 // ToNestedMatrixGenerator converts a JSON struct (from the K8s resource) to corresponding
@@ -311,7 +316,7 @@ func ToNestedMatrixGenerator(j *apiextensionsv1.JSON) (*NestedMatrixGenerator, e
 // no override template).
 func (g NestedMatrixGenerator) ToMatrixGenerator() *MatrixGenerator {
 	return &MatrixGenerator{
-		Generators: g.Generators.toApplicationSetNestedGenerators(),
+		Generators: g.Generators,
 	}
 }
 
@@ -901,6 +906,12 @@ func (a *ApplicationSet) RefreshRequired() bool {
 	return found
 }
 
+// IsDeletionProtected returns whether the ApplicationSet is protected from deletion by the
+// argocd.argoproj.io/deletion-protected annotation
+func (a *ApplicationSet) IsDeletionProtected() bool {
+	return a.Annotations[common.AnnotationKeyDeletionProtected] == "true"
+}
+
 // SetConditions updates the applicationset status conditions for a subset of evaluated types.
 // If the applicationset has a pre-existing condition of a type that is not in the evaluated list,
 // it will be preserved. If the applicationset has a pre-existing condition of a type, status, reason that
@@ -7,6 +7,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-cd/v2/common"
 )
 
 func testAppSetCond(t ApplicationSetConditionType, msg string, lastTransitionTime *metav1.Time, status ApplicationSetConditionStatus, reason string) ApplicationSetCondition {
@@ -74,6 +76,17 @@ func TestApplicationSetRBACName(t *testing.T) {
 	})
 }
 
+func TestApplicationSetIsDeletionProtected(t *testing.T) {
+	a := newTestAppSet("test-appset", "argocd", "https://github.com/org/repo")
+	assert.False(t, a.IsDeletionProtected())
+
+	a.Annotations = map[string]string{common.AnnotationKeyDeletionProtected: "false"}
+	assert.False(t, a.IsDeletionProtected())
+
+	a.Annotations[common.AnnotationKeyDeletionProtected] = "true"
+	assert.True(t, a.IsDeletionProtected())
+}
+
 func TestApplicationSetSetConditions(t *testing.T) {
 	fiveMinsAgo := &metav1.Time{Time: time.Now().Add(-5 * time.Minute)}
 	tenMinsAgo := &metav1.Time{Time: time.Now().Add(-10 * time.Minute)}
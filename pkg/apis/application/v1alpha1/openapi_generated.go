@@ -43,17 +43,29 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSetTerminalGenerator":         schema_pkg_apis_application_v1alpha1_ApplicationSetTerminalGenerator(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSetTree":                      schema_pkg_apis_application_v1alpha1_ApplicationSetTree(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSource":                       schema_pkg_apis_application_v1alpha1_ApplicationSource(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceCue":                    schema_pkg_apis_application_v1alpha1_ApplicationSourceCue(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceDirectory":              schema_pkg_apis_application_v1alpha1_ApplicationSourceDirectory(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceHTTP":                   schema_pkg_apis_application_v1alpha1_ApplicationSourceHTTP(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceHelm":                   schema_pkg_apis_application_v1alpha1_ApplicationSourceHelm(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceInline":                 schema_pkg_apis_application_v1alpha1_ApplicationSourceInline(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceJsonnet":                schema_pkg_apis_application_v1alpha1_ApplicationSourceJsonnet(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceKustomize":              schema_pkg_apis_application_v1alpha1_ApplicationSourceKustomize(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceOCI":                    schema_pkg_apis_application_v1alpha1_ApplicationSourceOCI(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourcePlugin":                 schema_pkg_apis_application_v1alpha1_ApplicationSourcePlugin(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourcePluginParameter":        schema_pkg_apis_application_v1alpha1_ApplicationSourcePluginParameter(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourcePulumi":                 schema_pkg_apis_application_v1alpha1_ApplicationSourcePulumi(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceStarlark":               schema_pkg_apis_application_v1alpha1_ApplicationSourceStarlark(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceTimoni":                 schema_pkg_apis_application_v1alpha1_ApplicationSourceTimoni(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceVerifyAttestation":      schema_pkg_apis_application_v1alpha1_ApplicationSourceVerifyAttestation(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceYtt":                    schema_pkg_apis_application_v1alpha1_ApplicationSourceYtt(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSpec":                         schema_pkg_apis_application_v1alpha1_ApplicationSpec(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationStatus":                       schema_pkg_apis_application_v1alpha1_ApplicationStatus(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSummary":                      schema_pkg_apis_application_v1alpha1_ApplicationSummary(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationTree":                         schema_pkg_apis_application_v1alpha1_ApplicationTree(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationWatchEvent":                   schema_pkg_apis_application_v1alpha1_ApplicationWatchEvent(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplyRateLimit":                          schema_pkg_apis_application_v1alpha1_ApplyRateLimit(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ArtifactStorage":                         schema_pkg_apis_application_v1alpha1_ArtifactStorage(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.AutoRollback":                            schema_pkg_apis_application_v1alpha1_AutoRollback(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.Backoff":                                 schema_pkg_apis_application_v1alpha1_Backoff(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.BasicAuthBitbucketServer":                schema_pkg_apis_application_v1alpha1_BasicAuthBitbucketServer(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.BearerTokenBitbucket":                    schema_pkg_apis_application_v1alpha1_BearerTokenBitbucket(ref),
@@ -68,9 +80,13 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.Command":                                 schema_pkg_apis_application_v1alpha1_Command(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ComparedTo":                              schema_pkg_apis_application_v1alpha1_ComparedTo(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ComponentParameter":                      schema_pkg_apis_application_v1alpha1_ComponentParameter(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ConditionHistoryEntry":                   schema_pkg_apis_application_v1alpha1_ConditionHistoryEntry(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ConfigManagementPlugin":                  schema_pkg_apis_application_v1alpha1_ConfigManagementPlugin(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ConfigMapKeyRef":                         schema_pkg_apis_application_v1alpha1_ConfigMapKeyRef(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ConnectionState":                         schema_pkg_apis_application_v1alpha1_ConnectionState(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.DatadogIntegration":                      schema_pkg_apis_application_v1alpha1_DatadogIntegration(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.DestinationSyncResult":                   schema_pkg_apis_application_v1alpha1_DestinationSyncResult(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.DestinationSyncStatus":                   schema_pkg_apis_application_v1alpha1_DestinationSyncStatus(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.DuckTypeGenerator":                       schema_pkg_apis_application_v1alpha1_DuckTypeGenerator(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.EnvEntry":                                schema_pkg_apis_application_v1alpha1_EnvEntry(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ErrApplicationNotAllowedToUseProject":    schema_pkg_apis_application_v1alpha1_ErrApplicationNotAllowedToUseProject(ref),
@@ -84,8 +100,12 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmFileParameter":                       schema_pkg_apis_application_v1alpha1_HelmFileParameter(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmOptions":                             schema_pkg_apis_application_v1alpha1_HelmOptions(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmParameter":                           schema_pkg_apis_application_v1alpha1_HelmParameter(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmValuesFrom":                          schema_pkg_apis_application_v1alpha1_HelmValuesFrom(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmValuesKeyRef":                        schema_pkg_apis_application_v1alpha1_HelmValuesKeyRef(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HistoryRetentionPolicy":                  schema_pkg_apis_application_v1alpha1_HistoryRetentionPolicy(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HostInfo":                                schema_pkg_apis_application_v1alpha1_HostInfo(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HostResourceInfo":                        schema_pkg_apis_application_v1alpha1_HostResourceInfo(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.InTotoAttestation":                       schema_pkg_apis_application_v1alpha1_InTotoAttestation(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.Info":                                    schema_pkg_apis_application_v1alpha1_Info(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.InfoItem":                                schema_pkg_apis_application_v1alpha1_InfoItem(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.JWTToken":                                schema_pkg_apis_application_v1alpha1_JWTToken(ref),
@@ -104,6 +124,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.MergeGenerator":                          schema_pkg_apis_application_v1alpha1_MergeGenerator(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.NestedMatrixGenerator":                   schema_pkg_apis_application_v1alpha1_NestedMatrixGenerator(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.NestedMergeGenerator":                    schema_pkg_apis_application_v1alpha1_NestedMergeGenerator(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.OCIArtifactStorage":                      schema_pkg_apis_application_v1alpha1_OCIArtifactStorage(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.Operation":                               schema_pkg_apis_application_v1alpha1_Operation(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.OperationInitiator":                      schema_pkg_apis_application_v1alpha1_OperationInitiator(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.OperationState":                          schema_pkg_apis_application_v1alpha1_OperationState(ref),
@@ -115,7 +136,11 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.PluginConfigMapRef":                      schema_pkg_apis_application_v1alpha1_PluginConfigMapRef(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.PluginGenerator":                         schema_pkg_apis_application_v1alpha1_PluginGenerator(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.PluginInput":                             schema_pkg_apis_application_v1alpha1_PluginInput(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.PostSyncWebhook":                         schema_pkg_apis_application_v1alpha1_PostSyncWebhook(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.PreFlightCheck":                          schema_pkg_apis_application_v1alpha1_PreFlightCheck(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ProjectAllowlist":                        schema_pkg_apis_application_v1alpha1_ProjectAllowlist(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ProjectRole":                             schema_pkg_apis_application_v1alpha1_ProjectRole(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ProjectSyncPolicy":                       schema_pkg_apis_application_v1alpha1_ProjectSyncPolicy(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.PullRequestGenerator":                    schema_pkg_apis_application_v1alpha1_PullRequestGenerator(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.PullRequestGeneratorAzureDevOps":         schema_pkg_apis_application_v1alpha1_PullRequestGeneratorAzureDevOps(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.PullRequestGeneratorBitbucket":           schema_pkg_apis_application_v1alpha1_PullRequestGeneratorBitbucket(ref),
@@ -130,6 +155,12 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.Repository":                              schema_pkg_apis_application_v1alpha1_Repository(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryCertificate":                   schema_pkg_apis_application_v1alpha1_RepositoryCertificate(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryCertificateList":               schema_pkg_apis_application_v1alpha1_RepositoryCertificateList(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfig":                        schema_pkg_apis_application_v1alpha1_RepositoryConfig(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigList":                    schema_pkg_apis_application_v1alpha1_RepositoryConfigList(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigSecretValue":             schema_pkg_apis_application_v1alpha1_RepositoryConfigSecretValue(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigSecretValueSource":       schema_pkg_apis_application_v1alpha1_RepositoryConfigSecretValueSource(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigSpec":                    schema_pkg_apis_application_v1alpha1_RepositoryConfigSpec(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigStatus":                  schema_pkg_apis_application_v1alpha1_RepositoryConfigStatus(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryList":                          schema_pkg_apis_application_v1alpha1_RepositoryList(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ResourceAction":                          schema_pkg_apis_application_v1alpha1_ResourceAction(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ResourceActionDefinition":                schema_pkg_apis_application_v1alpha1_ResourceActionDefinition(ref),
@@ -146,6 +177,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RetryStrategy":                           schema_pkg_apis_application_v1alpha1_RetryStrategy(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RevisionHistory":                         schema_pkg_apis_application_v1alpha1_RevisionHistory(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RevisionMetadata":                        schema_pkg_apis_application_v1alpha1_RevisionMetadata(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RollbackHistoryEntry":                    schema_pkg_apis_application_v1alpha1_RollbackHistoryEntry(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SCMProviderGenerator":                    schema_pkg_apis_application_v1alpha1_SCMProviderGenerator(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SCMProviderGeneratorAWSCodeCommit":       schema_pkg_apis_application_v1alpha1_SCMProviderGeneratorAWSCodeCommit(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SCMProviderGeneratorAzureDevOps":         schema_pkg_apis_application_v1alpha1_SCMProviderGeneratorAzureDevOps(ref),
@@ -158,10 +190,12 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SecretRef":                               schema_pkg_apis_application_v1alpha1_SecretRef(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SignatureKey":                            schema_pkg_apis_application_v1alpha1_SignatureKey(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncOperation":                           schema_pkg_apis_application_v1alpha1_SyncOperation(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncOperationProgress":                   schema_pkg_apis_application_v1alpha1_SyncOperationProgress(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncOperationResource":                   schema_pkg_apis_application_v1alpha1_SyncOperationResource(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncOperationResult":                     schema_pkg_apis_application_v1alpha1_SyncOperationResult(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncPolicy":                              schema_pkg_apis_application_v1alpha1_SyncPolicy(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncPolicyAutomated":                     schema_pkg_apis_application_v1alpha1_SyncPolicyAutomated(ref),
+		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncRetryPattern":                        schema_pkg_apis_application_v1alpha1_SyncRetryPattern(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncStatus":                              schema_pkg_apis_application_v1alpha1_SyncStatus(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncStrategy":                            schema_pkg_apis_application_v1alpha1_SyncStrategy(ref),
 		"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncStrategyApply":                       schema_pkg_apis_application_v1alpha1_SyncStrategyApply(ref),
@@ -473,11 +507,77 @@ func schema_pkg_apis_application_v1alpha1_AppProjectSpec(ref common.ReferenceCal
 							Format:      "",
 						},
 					},
+					"postSyncWebhook": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PostSyncWebhook configures a webhook which is called after each sync operation of the applications in this project",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.PostSyncWebhook"),
+						},
+					},
+					"namespaceResourceQuota": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NamespaceResourceQuota limits the number of resources of each kind which the applications in this project may manage in total. Keys are group kinds formatted as `<kind>.<group>`, or `<kind>` for the core group, e.g. `Deployment.apps` or `ConfigMap`.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: 0,
+										Type:    []string{"integer"},
+										Format:  "int64",
+									},
+								},
+							},
+						},
+					},
+					"namespaceIsolation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "NamespaceIsolation controls whether the applications in this project may create resources outside their destination namespace. If set to `strict`, manifests containing namespaced resources of other namespaces, or cluster-scoped resources which are not explicitly listed in the cluster resource whitelist, are rejected during manifest generation.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"allowlist": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Allowlist restricts the contents of the container images deployed by the applications in this project",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ProjectAllowlist"),
+						},
+					},
+					"artifactStorage": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ArtifactStorage configures where the manifests applied by the syncs of the applications in this project are stored",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ArtifactStorage"),
+						},
+					},
+					"destinationServiceAccount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DestinationServiceAccount is the service account impersonated by the application controller to sync the applications in this project. It is formatted as `<namespace>:<name>`, or `<name>` for a service account of the destination namespace of the application, and must exist in the destination cluster.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"datadog": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Datadog sends the results of the syncs of the applications in this project to Datadog as deployment events",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.DatadogIntegration"),
+						},
+					},
+					"historyRetentionPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HistoryRetentionPolicy limits the sync history kept by the applications in this project",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HistoryRetentionPolicy"),
+						},
+					},
+					"syncPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SyncPolicy controls the checks performed before syncing the applications in this project",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ProjectSyncPolicy"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationDestination", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.OrphanedResourcesMonitorSettings", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ProjectRole", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SignatureKey", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncWindow", "k8s.io/apimachinery/pkg/apis/meta/v1.GroupKind"},
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationDestination", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ArtifactStorage", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.DatadogIntegration", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HistoryRetentionPolicy", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.OrphanedResourcesMonitorSettings", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.PostSyncWebhook", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ProjectAllowlist", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ProjectRole", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ProjectSyncPolicy", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SignatureKey", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncWindow", "k8s.io/apimachinery/pkg/apis/meta/v1.GroupKind"},
 	}
 }
 
@@ -1313,6 +1413,20 @@ func schema_pkg_apis_application_v1alpha1_ApplicationSetSpec(ref common.Referenc
 							Format: "",
 						},
 					},
+					"propagateMetadata": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PropagateMetadata enables propagating the labels and annotations of the ApplicationSet to the generated Applications",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"propagateLabels": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PropagateLabels enables propagating the labels of the ApplicationSet to the generated Applications",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"generators", "template"},
 			},
@@ -1680,12 +1794,102 @@ func schema_pkg_apis_application_v1alpha1_ApplicationSource(ref common.Reference
 							Format:      "",
 						},
 					},
+					"inline": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Inline holds manifests which are defined in the application itself instead of a repository",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceInline"),
+						},
+					},
+					"ytt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Ytt holds Carvel ytt specific options",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceYtt"),
+						},
+					},
+					"verifyAttestation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VerifyAttestation holds the attestations which must be attached to the container images of the source before it is synced",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceVerifyAttestation"),
+						},
+					},
+					"starlark": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Starlark holds options specific to applications rendered with a Starlark script",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceStarlark"),
+						},
+					},
+					"cue": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Cue holds options specific to applications rendered from a CUE configuration",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceCue"),
+						},
+					},
+					"http": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HTTP holds the URL of manifests which are fetched over HTTP or HTTPS instead of a repository",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceHTTP"),
+						},
+					},
+					"pulumi": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Pulumi holds options specific to applications rendered from the preview of a Pulumi program",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourcePulumi"),
+						},
+					},
+					"oci": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OCI holds the image of an OCI artifact whose layers hold the manifests, which is tracked instead of a Git repository",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceOCI"),
+						},
+					},
+					"timoni": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timoni holds options specific to applications rendered from a Timoni module",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceTimoni"),
+						},
+					},
 				},
 				Required: []string{"repoURL"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceDirectory", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceHelm", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceKustomize", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourcePlugin"},
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceCue", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceDirectory", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceHTTP", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceHelm", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceInline", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceKustomize", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceOCI", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourcePlugin", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourcePulumi", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceStarlark", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceTimoni", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceVerifyAttestation", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSourceYtt"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_ApplicationSourceCue(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ApplicationSourceCue holds options specific to applications rendered from a CUE configuration",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"entrypoint": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Entrypoint is the CUE file or package exported to manifests, relative to the application path. Defaults to the package of the application path",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tags": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tags are the values injected into the fields of the configuration marked with a @tag() attribute",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
 	}
 }
 
@@ -1732,6 +1936,43 @@ func schema_pkg_apis_application_v1alpha1_ApplicationSourceDirectory(ref common.
 	}
 }
 
+func schema_pkg_apis_application_v1alpha1_ApplicationSourceHTTP(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ApplicationSourceHTTP holds the URL of manifests which are fetched over HTTP or HTTPS instead of a repository",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL is the HTTP or HTTPS URL of the YAML manifests. Multiple documents are separated by `---`.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"sha256": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SHA256 is the expected hex encoded SHA256 digest of the manifests. The manifests are rejected if their digest differs. If empty, the manifests are fetched again once their cached copy expires, so that changes are detected.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"caBundleSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CABundleSecret references a key of a Secret in the Argo CD namespace holding the PEM encoded certificates of the certificate authorities trusted in addition to the system ones when fetching the manifests over HTTPS. The Secret must be labeled with app.kubernetes.io/part-of=argocd.",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SecretRef"),
+						},
+					},
+				},
+				Required: []string{"url"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SecretRef"},
+	}
+}
+
 func schema_pkg_apis_application_v1alpha1_ApplicationSourceHelm(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -1864,11 +2105,60 @@ func schema_pkg_apis_application_v1alpha1_ApplicationSourceHelm(ref common.Refer
 							},
 						},
 					},
+					"valuesFrom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ValuesFrom references Helm values stored in a ConfigMap or Secret of the destination cluster, which are read when generating manifests. They are merged after the value files and before the inline values.",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmValuesFrom"),
+						},
+					},
+					"valueFilesSuffix": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ValueFilesSuffix is the name of a label of the destination cluster. When set, the value of the label is appended to the name of each value file, e.g. values-us-east-1.yaml for values.yaml and the label region=us-east-1, and the suffixed value file is used instead of the value file if it exists.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"validateCRs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ValidateCRs validates the custom resources generated by Helm against the OpenAPI schemas of the CustomResourceDefinitions generated alongside them, and fails the manifest generation if they do not match",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"autoValuesFile": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AutoValuesFile appends the value files named after the destination namespace and the name of the destination cluster, values-<namespace>.yaml and values-<cluster name>.yaml, to the value files if they exist in the directory of the chart",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmFileParameter", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmParameter", "k8s.io/apimachinery/pkg/runtime.RawExtension"},
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmFileParameter", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmParameter", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmValuesFrom", "k8s.io/apimachinery/pkg/runtime.RawExtension"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_ApplicationSourceInline(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ApplicationSourceInline holds manifests which are defined in the application itself instead of a repository",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"manifests": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Manifests are the YAML manifests of the resources of the application. Multiple documents are separated by `---`.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"manifests"},
+			},
+		},
 	}
 }
 
@@ -2105,6 +2395,13 @@ func schema_pkg_apis_application_v1alpha1_ApplicationSourceKustomize(ref common.
 							},
 						},
 					},
+					"validate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Validate specifies whether to build the manifests with alpha plugins enabled, so that the schema validation warnings of the plugins are reported as SyncWarning conditions",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -2113,6 +2410,35 @@ func schema_pkg_apis_application_v1alpha1_ApplicationSourceKustomize(ref common.
 	}
 }
 
+func schema_pkg_apis_application_v1alpha1_ApplicationSourceOCI(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ApplicationSourceOCI holds the image of an OCI artifact whose layers are tarballs of manifests",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"image": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Image is the reference of the OCI repository of the artifact, without tag or digest, e.g. ghcr.io/org/manifests",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tag": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Tag is the tag of the artifact, a digest pinning it, or a semantic version constraint selecting the highest matching tag. The tags of the repository are polled for new versions of the constraint. Defaults to latest.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"image"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_application_v1alpha1_ApplicationSourcePlugin(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2185,64 +2511,237 @@ func schema_pkg_apis_application_v1alpha1_ApplicationSourcePluginParameter(ref c
 	}
 }
 
-func schema_pkg_apis_application_v1alpha1_ApplicationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_application_v1alpha1_ApplicationSourcePulumi(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "ApplicationSpec represents desired application state. Contains link to repository with application definition and additional parameters link definition revision.",
+				Description: "ApplicationSourcePulumi holds options specific to applications rendered from the preview of a Pulumi program",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"source": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Source is a reference to the location of the application's manifests or chart",
-							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSource"),
-						},
-					},
-					"destination": {
-						SchemaProps: spec.SchemaProps{
-							Description: "Destination is a reference to the target Kubernetes server and namespace",
-							Default:     map[string]interface{}{},
-							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationDestination"),
-						},
-					},
-					"project": {
+					"runtime": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Project is a reference to the project this application belongs to. The empty string means that application belongs to the 'default' project.",
+							Description: "Runtime is the language runtime of the program, one of nodejs, python or go",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"syncPolicy": {
+					"entrypoint": {
 						SchemaProps: spec.SchemaProps{
-							Description: "SyncPolicy controls when and how a sync will be performed",
-							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncPolicy"),
+							Description: "Entrypoint is the directory of the program, relative to the application path. Defaults to the application path",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"ignoreDifferences": {
+					"config": {
 						SchemaProps: spec.SchemaProps{
-							Description: "IgnoreDifferences is a list of resources and their fields which should be ignored during comparison",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
+							Description: "Config are the configuration values of the program, set on the ephemeral stack the program is previewed with",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ResourceIgnoreDifferences"),
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
 									},
 								},
 							},
 						},
 					},
-					"info": {
+				},
+				Required: []string{"runtime"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_ApplicationSourceStarlark(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ApplicationSourceStarlark holds options specific to applications rendered with a Starlark script",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"entrypoint": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Info contains a list of information (URLs, email addresses, and plain text) that relates to the application",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.Info"),
-									},
+							Description: "Entrypoint is the path of the Starlark script rendering the manifests, relative to the application path. The script must define a main function returning a list of Kubernetes manifests.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"values": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Values is a YAML or JSON object passed to the script as the values dict",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"entrypoint"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_ApplicationSourceTimoni(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ApplicationSourceTimoni holds options specific to applications rendered from a Timoni module",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"module": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Module is the directory of the module, relative to the application path, or the oci:// URL of a module published to a registry, optionally followed by the :version of the module. Defaults to the application path",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"values": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Values are the values of the instance of the module, as YAML",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_ApplicationSourceVerifyAttestation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ApplicationSourceVerifyAttestation holds the attestations required for an application source and its container images",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"sbom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SBOM requires a CycloneDX or SPDX software bill of materials to be attached to each container image as a cosign attestation",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"inToto": {
+						SchemaProps: spec.SchemaProps{
+							Description: "InToto requires the manifests of the source to be produced by the supply chain described by an in-toto layout",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.InTotoAttestation"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.InTotoAttestation"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_ApplicationSourceYtt(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ApplicationSourceYtt holds options specific to applications rendered with Carvel ytt",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"lib": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Lib is a list of additional template or library paths passed to ytt, relative to the application path",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"dataValues": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DataValues are data values passed to ytt with --data-value",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_ApplicationSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ApplicationSpec represents desired application state. Contains link to repository with application definition and additional parameters link definition revision.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"source": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Source is a reference to the location of the application's manifests or chart",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSource"),
+						},
+					},
+					"destination": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Destination is a reference to the target Kubernetes server and namespace",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationDestination"),
+						},
+					},
+					"project": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Project is a reference to the project this application belongs to. The empty string means that application belongs to the 'default' project.",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"syncPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SyncPolicy controls when and how a sync will be performed",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncPolicy"),
+						},
+					},
+					"ignoreDifferences": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IgnoreDifferences is a list of resources and their fields which should be ignored during comparison",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ResourceIgnoreDifferences"),
+									},
+								},
+							},
+						},
+					},
+					"info": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Info contains a list of information (URLs, email addresses, and plain text) that relates to the application",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.Info"),
+									},
 								},
 							},
 						},
@@ -2268,6 +2767,27 @@ func schema_pkg_apis_application_v1alpha1_ApplicationSpec(ref common.ReferenceCa
 							},
 						},
 					},
+					"ignoreMissingHealthChecks": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IgnoreMissingHealthChecks treats resources without a built-in or custom health check as healthy, regardless of the default health the controller assumes for such resources",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"destinations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Destinations are the additional destinations the manifests of the application are deployed to. The manifests are generated once, and compared and synced to the destination and each additional destination independently.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationDestination"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"destination", "project"},
 			},
@@ -2401,11 +2921,68 @@ func schema_pkg_apis_application_v1alpha1_ApplicationStatus(ref common.Reference
 							Format:      "",
 						},
 					},
+					"conditionHistory": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConditionHistory is the list of the last transitions of the health, sync status and conditions of the application, oldest first",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ConditionHistoryEntry"),
+									},
+								},
+							},
+						},
+					},
+					"rollbackHistory": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RollbackHistory is the list of the last automatic rollbacks of the application, oldest first",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RollbackHistoryEntry"),
+									},
+								},
+							},
+						},
+					},
+					"autoValueFiles": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AutoValueFiles are the Helm value files which were appended to the value files of the sources because they are named after the destination namespace or cluster, relative to the root of their repository",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"destinationStatuses": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DestinationStatuses are the sync and health statuses of each destination of an application with additional destinations, the destination first",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.DestinationSyncStatus"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationCondition", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSummary", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HealthStatus", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.OperationState", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ResourceStatus", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RevisionHistory", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationCondition", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSummary", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ConditionHistoryEntry", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.DestinationSyncStatus", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HealthStatus", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.OperationState", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ResourceStatus", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RevisionHistory", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RollbackHistoryEntry", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
@@ -2539,6 +3116,85 @@ func schema_pkg_apis_application_v1alpha1_ApplicationWatchEvent(ref common.Refer
 	}
 }
 
+func schema_pkg_apis_application_v1alpha1_ApplyRateLimit(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ApplyRateLimit is a token bucket limiting the requests which modify resources of the destination cluster during a sync of the application. It only delays the requests of the application, in addition to the default limit shared by all applications syncing to the same cluster.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"requestsPerSecond": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RequestsPerSecond is the number of requests per second, as a decimal number of at least 0.1, e.g. \"2.5\"",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"burst": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Burst is the number of requests which can be sent at once, defaults to RequestsPerSecond rounded up",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+				Required: []string{"requestsPerSecond"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_ArtifactStorage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ArtifactStorage configures where the manifests applied by successful syncs are stored",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"oci": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OCI stores the applied manifests as artifacts in an OCI registry",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.OCIArtifactStorage"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.OCIArtifactStorage"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_AutoRollback(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "AutoRollback rolls the application back to the revision of the previous entry of its history when it becomes degraded within the health window after a successful sync",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"healthWindow": {
+						SchemaProps: spec.SchemaProps{
+							Description: "HealthWindow is the duration after a successful sync during which a degraded application is rolled back, e.g. \"5m\"",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maxRollbackAttempts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxRollbackAttempts is the maximum number of automatic rollbacks of the same revision, defaults to 1",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+				},
+				Required: []string{"healthWindow"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_application_v1alpha1_Backoff(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3184,6 +3840,51 @@ func schema_pkg_apis_application_v1alpha1_ComponentParameter(ref common.Referenc
 	}
 }
 
+func schema_pkg_apis_application_v1alpha1_ConditionHistoryEntry(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ConditionHistoryEntry is a transition of the health status, the sync status or a condition of an application",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type is Health or Sync for health and sync status transitions, or the type of the application condition",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Status is the health or sync status after the transition, or True or False when a condition appeared or was resolved",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastTransitionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastTransitionTime is the time of the transition",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is the message of the health status or of the condition",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"type", "status"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_application_v1alpha1_ConfigManagementPlugin(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3267,26 +3968,152 @@ func schema_pkg_apis_application_v1alpha1_ConnectionState(ref common.ReferenceCa
 							Format:      "",
 						},
 					},
-					"message": {
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message contains human readable information about the connection status",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"attemptedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ModifiedAt contains the timestamp when this connection status has been determined",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+				},
+				Required: []string{"status", "message", "attemptedAt"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_DatadogIntegration(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DatadogIntegration sends the results of syncs to Datadog as events, using the Datadog Events API v2",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"apiKeySecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIKeySecret references the key of a secret in the namespace of the application controller holding the Datadog API key",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SecretRef"),
+						},
+					},
+					"site": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Site is the Datadog site the events are sent to, e.g. datadoghq.com or datadoghq.eu. Defaults to datadoghq.com.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"serviceTag": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServiceTag is the value of the service tag of the events. Defaults to the name of the application.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"apiKeySecret"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SecretRef"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_DestinationSyncResult(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DestinationSyncResult is the result of the sync of one of the destinations of an application with additional destinations",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"destination": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Destination is the synced destination",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationDestination"),
+						},
+					},
+					"phase": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Phase is the phase of the sync of the destination",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message holds any pertinent messages about the sync of the destination",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"resources": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Resources contains the sync result of each resource synced to the destination",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ResourceResult"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"destination", "phase"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationDestination", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ResourceResult"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_DestinationSyncStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "DestinationSyncStatus is the sync and health status of one of the destinations of an application with additional destinations",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"destination": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Destination is the compared destination",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationDestination"),
+						},
+					},
+					"status": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Message contains human readable information about the connection status",
+							Description: "Status is the sync state of the destination",
 							Default:     "",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
-					"attemptedAt": {
+					"health": {
 						SchemaProps: spec.SchemaProps{
-							Description: "ModifiedAt contains the timestamp when this connection status has been determined",
-							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+							Description: "Health is the health of the resources of the application in the destination",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HealthStatus"),
 						},
 					},
 				},
-				Required: []string{"status", "message", "attemptedAt"},
+				Required: []string{"destination", "status"},
 			},
 		},
 		Dependencies: []string{
-			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationDestination", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HealthStatus"},
 	}
 }
 
@@ -3826,6 +4653,98 @@ func schema_pkg_apis_application_v1alpha1_HelmParameter(ref common.ReferenceCall
 	}
 }
 
+func schema_pkg_apis_application_v1alpha1_HelmValuesFrom(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "HelmValuesFrom references Helm values stored in the destination cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"configMapKeyRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConfigMapKeyRef references a key of a ConfigMap holding Helm values in YAML format",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmValuesKeyRef"),
+						},
+					},
+					"secretKeyRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretKeyRef references a key of a Secret holding Helm values in YAML format. They are merged after the values of ConfigMapKeyRef.",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmValuesKeyRef"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.HelmValuesKeyRef"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_HelmValuesKeyRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "HelmValuesKeyRef references a key of a ConfigMap or Secret in the destination cluster",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"namespace": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Namespace is the namespace of the ConfigMap or Secret, which must be a permitted destination of the project. Defaults to the destination namespace of the application.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the ConfigMap or Secret",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"key": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Key is the key of the ConfigMap or Secret holding the values",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"name", "key"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_HistoryRetentionPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "HistoryRetentionPolicy limits the number and the age of the entries of the sync history of applications. Entries are removed if they exceed either constraint, so the more restrictive one applies. The latest entry is always kept.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"maxEntries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxEntries is the maximum number of history entries kept, zero does not limit the number of entries",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"maxAge": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxAge is the maximum age of the history entries kept, based on the time their sync completed. Zero does not limit the age of entries.",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
 func schema_pkg_apis_application_v1alpha1_HostInfo(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3902,6 +4821,28 @@ func schema_pkg_apis_application_v1alpha1_HostResourceInfo(ref common.ReferenceC
 	}
 }
 
+func schema_pkg_apis_application_v1alpha1_InTotoAttestation(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "InTotoAttestation references the in-toto layout verified against the link metadata files of a Git source",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"layout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Layout is the name of a ConfigMap of the Argo CD namespace, labeled with app.kubernetes.io/part-of=argocd, which holds the signed in-toto layout in its root.layout key and the PEM encoded public keys of the layout owners in keys with the .pub suffix",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"layout"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_application_v1alpha1_Info(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -4477,7 +5418,7 @@ func schema_pkg_apis_application_v1alpha1_NestedMatrixGenerator(ref common.Refer
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "NestedMatrixGenerator is a MatrixGenerator nested under another combination-type generator (MatrixGenerator or MergeGenerator). NestedMatrixGenerator does not have an override template, because template overriding has no meaning within the constituent generators of combination-type generators.\n\nNOTE: Nested matrix generator is not included directly in the CRD struct, instead it is included as a generic 'apiextensionsv1.JSON' object, and then marshalled into a NestedMatrixGenerator when processed.",
+				Description: "NestedMatrixGenerator is a MatrixGenerator nested under another combination-type generator (MatrixGenerator or MergeGenerator). NestedMatrixGenerator does not have an override template, because template overriding has no meaning within the constituent generators of combination-type generators. Its child generators may themselves be nested matrix generators, up to the maximum depth configured on the ApplicationSet controller.\n\nNOTE: Nested matrix generator is not included directly in the CRD struct, instead it is included as a generic 'apiextensionsv1.JSON' object, and then marshalled into a NestedMatrixGenerator when processed.",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
 					"generators": {
@@ -4487,7 +5428,7 @@ func schema_pkg_apis_application_v1alpha1_NestedMatrixGenerator(ref common.Refer
 								Schema: &spec.Schema{
 									SchemaProps: spec.SchemaProps{
 										Default: map[string]interface{}{},
-										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSetTerminalGenerator"),
+										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSetNestedGenerator"),
 									},
 								},
 							},
@@ -4498,7 +5439,7 @@ func schema_pkg_apis_application_v1alpha1_NestedMatrixGenerator(ref common.Refer
 			},
 		},
 		Dependencies: []string{
-			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSetTerminalGenerator"},
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSetNestedGenerator"},
 	}
 }
 
@@ -4545,6 +5486,43 @@ func schema_pkg_apis_application_v1alpha1_NestedMergeGenerator(ref common.Refere
 	}
 }
 
+func schema_pkg_apis_application_v1alpha1_OCIArtifactStorage(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "OCIArtifactStorage stores the manifests applied by successful syncs as artifacts in an OCI registry",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"registry": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Registry is the host name of the OCI registry, e.g. ghcr.io",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"repo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Repo is the repository in the registry the artifacts are pushed to, e.g. my-org/argocd-sync-results",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"credentialSecret": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CredentialSecret is the name of a secret in the namespace of the application controller, holding the username and password used to authenticate against the registry. Anonymous access is used if empty.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"registry", "repo"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_application_v1alpha1_Operation(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -4674,12 +5652,31 @@ func schema_pkg_apis_application_v1alpha1_OperationState(ref common.ReferenceCal
 							Format:      "int64",
 						},
 					},
+					"progress": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Progress reports the progress of a running sync operation",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncOperationProgress"),
+						},
+					},
+					"webhookConfirmationStartedAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "WebhookConfirmationStartedAt is the time the succeeded sync operation started waiting for the post-sync webhook of the project to confirm it",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"errorCode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ErrorCode is the machine readable reason of the failure of the operation, empty unless the operation failed",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"operation", "phase", "startedAt"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.Operation", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncOperationResult", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.Operation", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncOperationProgress", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncOperationResult", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
@@ -4970,6 +5967,127 @@ func schema_pkg_apis_application_v1alpha1_PluginInput(ref common.ReferenceCallba
 	}
 }
 
+func schema_pkg_apis_application_v1alpha1_PostSyncWebhook(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PostSyncWebhook is a webhook which is called when a sync operation of an application completes",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL is the URL the webhook request is sent to",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"method": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Method is the HTTP method of the webhook request. Defaults to POST.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"headers": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Headers are additional HTTP headers sent with the webhook request. Values starting with $ reference a key of the argocd-secret Secret, such as $webhook.token. Credential headers such as Authorization must reference a key.",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"bodyTemplate": {
+						SchemaProps: spec.SchemaProps{
+							Description: "BodyTemplate is a Go template for the body of the webhook request. It is executed with the appName, status, revision, message and timestamp of the sync operation. Defaults to a JSON object containing these values.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"requireWebhookConfirmation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RequireWebhookConfirmation keeps succeeded sync operations running until the webhook responds with HTTP 200. Once the webhook confirmation timeout of the application controller expires, the sync operation succeeds with a WebhookConfirmationTimeout warning condition.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"url"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_PreFlightCheck(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PreFlightCheck is the capacity the destination cluster must have available before a sync applies any resource. The available capacity is the allocatable resources of the ready and schedulable nodes, minus the resources requested by the pods running on them.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"minNodeCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MinNodeCount is the minimum number of ready and schedulable nodes",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"minCPUAvailable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MinCPUAvailable is the minimum available CPU, as a quantity, e.g. \"2\" or \"500m\"",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"minMemoryAvailable": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MinMemoryAvailable is the minimum available memory, as a quantity, e.g. \"4Gi\"",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_ProjectAllowlist(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ProjectAllowlist restricts the contents of the container images deployed by the applications in a project",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"licenseIds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LicenseIDs are the SPDX identifiers of the licenses permitted in the SBOMs of the container images, when SBOM attestations are verified. All licenses are permitted if empty.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_application_v1alpha1_ProjectRole(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -5037,11 +6155,31 @@ func schema_pkg_apis_application_v1alpha1_ProjectRole(ref common.ReferenceCallba
 						},
 					},
 				},
-				Required: []string{"name"},
+				Required: []string{"name"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.JWTToken"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_ProjectSyncPolicy(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ProjectSyncPolicy controls the checks performed before syncing the applications of a project",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"clusterVersionCheck": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ClusterVersionCheck controls whether the resources of the applications which use API versions deprecated or not served by the destination cluster are reported as SyncWarning conditions. If set to `pre-sync`, the API versions are checked against the preferred versions of the destination cluster while the applications are out of sync.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
 			},
 		},
-		Dependencies: []string{
-			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.JWTToken"},
 	}
 }
 
@@ -5872,6 +7010,13 @@ func schema_pkg_apis_application_v1alpha1_Repository(ref common.ReferenceCallbac
 							Format:      "",
 						},
 					},
+					"ociType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "OCIType specifies the type of the artifacts of an OCI repository, either \"helm\" for Helm charts or \"manifests\" for the tarballs of manifests of OCI sources. \"helm\" is assumed if empty or absent. Only used with OCI repos.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"repo"},
 			},
@@ -5904,69 +7049,350 @@ func schema_pkg_apis_application_v1alpha1_RepositoryCertificate(ref common.Refer
 							Format:      "",
 						},
 					},
-					"certSubType": {
+					"certSubType": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CertSubType specifies the sub type of the cert, i.e. \"ssh-rsa\"",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"certData": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CertData contains the actual certificate data, dependent on the certificate type",
+							Type:        []string{"string"},
+							Format:      "byte",
+						},
+					},
+					"certInfo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CertInfo will hold additional certificate info, depdendent on the certificate type (e.g. SSH fingerprint, X509 CommonName)",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"serverName", "certType", "certSubType", "certData", "certInfo"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_RepositoryCertificateList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RepositoryCertificateList is a collection of RepositoryCertificates",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Description: "List of certificates to be processed",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryCertificate"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryCertificate", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_RepositoryConfig(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RepositoryConfig is the declarative configuration of a repository of Argo CD. The application controller reconciles it into the repository secret Argo CD reads the repository and its credentials from.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"),
+						},
+					},
+					"spec": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigSpec"),
+						},
+					},
+					"status": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigStatus"),
+						},
+					},
+				},
+				Required: []string{"metadata", "spec"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigSpec", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigStatus", "k8s.io/apimachinery/pkg/apis/meta/v1.ObjectMeta"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_RepositoryConfigList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RepositoryConfigList is a list of RepositoryConfig resources",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind is a string value representing the REST resource this object represents. Servers may infer this from the endpoint the client submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"apiVersion": {
+						SchemaProps: spec.SchemaProps{
+							Description: "APIVersion defines the versioned schema of this representation of an object. Servers should convert recognized schemas to the latest internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#resources",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"metadata": {
+						SchemaProps: spec.SchemaProps{
+							Default: map[string]interface{}{},
+							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+						},
+					},
+					"items": {
+						SchemaProps: spec.SchemaProps{
+							Type: []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfig"),
+									},
+								},
+							},
+						},
+					},
+				},
+				Required: []string{"metadata", "items"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfig", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_RepositoryConfigSecretValue(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RepositoryConfigSecretValue is a sensitive value of a repository configuration",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"valueFrom": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ValueFrom is the source of the value",
+							Default:     map[string]interface{}{},
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigSecretValueSource"),
+						},
+					},
+				},
+				Required: []string{"valueFrom"},
+			},
+		},
+		Dependencies: []string{
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigSecretValueSource"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_RepositoryConfigSecretValueSource(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RepositoryConfigSecretValueSource is the source of a sensitive value of a repository configuration",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"secretKeyRef": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SecretKeyRef selects the key of a Secret of the Argo CD namespace holding the value",
+							Ref:         ref("k8s.io/api/core/v1.SecretKeySelector"),
+						},
+					},
+				},
+				Required: []string{"secretKeyRef"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/api/core/v1.SecretKeySelector"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_RepositoryConfigSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RepositoryConfigSpec is the configuration of a repository. The sensitive values are read from Secrets of the Argo CD namespace.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL is the URL of the repository",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"type": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Type is the type of the repository, git or helm. Defaults to git",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"name": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Name is the name of the repository, required for Helm repositories",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"project": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Project is the project the repository is scoped to, the repository is global if empty",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"username": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Username is the username used to access the repository",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"password": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Password is the password or token used to access the repository",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigSecretValue"),
+						},
+					},
+					"sshPrivateKey": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SSHPrivateKey is the SSH private key used to access the repository",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigSecretValue"),
+						},
+					},
+					"tlsClientCertData": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TLSClientCertData is the PEM encoded certificate presented to the repository",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tlsClientCertKey": {
+						SchemaProps: spec.SchemaProps{
+							Description: "TLSClientCertKey is the PEM encoded private key of the certificate presented to the repository",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigSecretValue"),
+						},
+					},
+					"insecure": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Insecure skips the verification of the TLS certificate and of the SSH host key of the repository",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+					"enableLfs": {
 						SchemaProps: spec.SchemaProps{
-							Description: "CertSubType specifies the sub type of the cert, i.e. \"ssh-rsa\"",
-							Default:     "",
-							Type:        []string{"string"},
+							Description: "EnableLFS enables Git LFS for the repository",
+							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
-					"certData": {
+					"enableOCI": {
 						SchemaProps: spec.SchemaProps{
-							Description: "CertData contains the actual certificate data, dependent on the certificate type",
-							Type:        []string{"string"},
-							Format:      "byte",
+							Description: "EnableOCI marks a Helm repository as an OCI registry",
+							Type:        []string{"boolean"},
+							Format:      "",
 						},
 					},
-					"certInfo": {
+					"proxy": {
 						SchemaProps: spec.SchemaProps{
-							Description: "CertInfo will hold additional certificate info, depdendent on the certificate type (e.g. SSH fingerprint, X509 CommonName)",
-							Default:     "",
+							Description: "Proxy is the HTTP/HTTPS proxy used to access the repository",
 							Type:        []string{"string"},
 							Format:      "",
 						},
 					},
 				},
-				Required: []string{"serverName", "certType", "certSubType", "certData", "certInfo"},
+				Required: []string{"url"},
 			},
 		},
+		Dependencies: []string{
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryConfigSecretValue"},
 	}
 }
 
-func schema_pkg_apis_application_v1alpha1_RepositoryCertificateList(ref common.ReferenceCallback) common.OpenAPIDefinition {
+func schema_pkg_apis_application_v1alpha1_RepositoryConfigStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
-				Description: "RepositoryCertificateList is a collection of RepositoryCertificates",
+				Description: "RepositoryConfigStatus is the state of the reconciliation of a repository configuration",
 				Type:        []string{"object"},
 				Properties: map[string]spec.Schema{
-					"metadata": {
+					"secretName": {
 						SchemaProps: spec.SchemaProps{
-							Default: map[string]interface{}{},
-							Ref:     ref("k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"),
+							Description: "SecretName is the name of the repository secret the configuration is reconciled into",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
-					"items": {
+					"observedGeneration": {
 						SchemaProps: spec.SchemaProps{
-							Description: "List of certificates to be processed",
-							Type:        []string{"array"},
-							Items: &spec.SchemaOrArray{
-								Schema: &spec.Schema{
-									SchemaProps: spec.SchemaProps{
-										Default: map[string]interface{}{},
-										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryCertificate"),
-									},
-								},
-							},
+							Description: "ObservedGeneration is the generation of the configuration last reconciled",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"error": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Error is the error which prevented the last reconciliation of the configuration, empty once it succeeded",
+							Type:        []string{"string"},
+							Format:      "",
 						},
 					},
 				},
-				Required: []string{"items"},
 			},
 		},
-		Dependencies: []string{
-			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RepositoryCertificate", "k8s.io/apimachinery/pkg/apis/meta/v1.ListMeta"},
 	}
 }
 
@@ -6714,6 +8140,13 @@ func schema_pkg_apis_application_v1alpha1_ResourceResult(ref common.ReferenceCal
 							Format:      "",
 						},
 					},
+					"errorCode": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ErrorCode is the machine readable reason of the failure of the sync of the resource, empty unless it failed",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 				Required: []string{"group", "version", "kind", "namespace", "name"},
 			},
@@ -6967,6 +8400,59 @@ func schema_pkg_apis_application_v1alpha1_RevisionMetadata(ref common.ReferenceC
 	}
 }
 
+func schema_pkg_apis_application_v1alpha1_RollbackHistoryEntry(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "RollbackHistoryEntry is an automatic rollback of an application which became degraded after a sync",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"fromRevision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "FromRevision is the revision whose sync degraded the application",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"toRevision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ToRevision is the revision the application was rolled back to",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"toHistoryID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ToHistoryID is the ID of the history entry the application was rolled back to",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"rolledBackAt": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RolledBackAt is the time the rollback was initiated",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message is the reason of the rollback",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"fromRevision", "toRevision", "toHistoryID", "rolledBackAt"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+	}
+}
+
 func schema_pkg_apis_application_v1alpha1_SCMProviderGenerator(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -7686,11 +9172,67 @@ func schema_pkg_apis_application_v1alpha1_SyncOperation(ref common.ReferenceCall
 							},
 						},
 					},
+					"syncWindowOverrideReason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SyncWindowOverrideReason is the reason given for overriding the sync windows blocking the sync. The sync windows are only overridden if they allow it and the sync has been triggered manually.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"destinations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Destinations selects the destinations of an application with additional destinations which are synced. All the destinations are synced if omitted.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationDestination"),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationDestination", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSource", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncOperationResource", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncStrategy"},
+	}
+}
+
+func schema_pkg_apis_application_v1alpha1_SyncOperationProgress(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SyncOperationProgress reports how many resources a sync operation applied and when it is expected to complete",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"resourcesApplied": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResourcesApplied is the number of resources applied so far",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"resourcesTotal": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ResourcesTotal is the number of resources the operation applies",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"estimatedCompletionTime": {
+						SchemaProps: spec.SchemaProps{
+							Description: "EstimatedCompletionTime is the time the operation is expected to have applied all its resources, estimated from the moving average of the time taken to apply a resource",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSource", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncOperationResource", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncStrategy"},
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
@@ -7804,12 +9346,33 @@ func schema_pkg_apis_application_v1alpha1_SyncOperationResult(ref common.Referen
 							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ManagedNamespaceMetadata"),
 						},
 					},
+					"syncId": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SyncID identifies the snapshot of the live state of the resources taken before the sync applied them",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"destinations": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Destinations holds the result of the sync of each synced destination of an application with additional destinations, in which case the results of the resources are recorded per destination rather than in resources",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.DestinationSyncResult"),
+									},
+								},
+							},
+						},
+					},
 				},
 				Required: []string{"revision"},
 			},
 		},
 		Dependencies: []string{
-			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSource", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ManagedNamespaceMetadata", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ResourceResult"},
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplicationSource", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.DestinationSyncResult", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ManagedNamespaceMetadata", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ResourceResult"},
 	}
 }
 
@@ -7853,11 +9416,93 @@ func schema_pkg_apis_application_v1alpha1_SyncPolicy(ref common.ReferenceCallbac
 							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ManagedNamespaceMetadata"),
 						},
 					},
+					"serverSideApplyConflictResolution": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServerSideApplyConflictResolution controls how field manager conflicts of server-side applied resources are resolved, \"fail\" fails the sync and \"force\" takes over the conflicting fields and reports a warning for each of them",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"applyRateLimit": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ApplyRateLimit limits the rate at which resources are applied to the destination cluster during a sync",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplyRateLimit"),
+						},
+					},
+					"conflictResolution": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ConflictResolution controls how field manager conflicts of server-side applied resources are resolved, \"strict\" fails the sync, \"force\" takes over the conflicting fields and \"merge\" only takes over the conflicting fields which are also owned by Argo CD. Takes precedence over ServerSideApplyConflictResolution",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"syncTimeout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SyncTimeout is the duration after which a sync which did not complete fails, e.g. \"30m\". Overrides the global sync timeout of the application controller",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"preFlightCheck": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PreFlightCheck is the capacity the destination cluster must have available before a sync applies any resource",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.PreFlightCheck"),
+						},
+					},
+					"autoRollback": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AutoRollback rolls the application back to its previous revision when it becomes degraded shortly after a sync",
+							Ref:         ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.AutoRollback"),
+						},
+					},
+					"applyTimeouts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ApplyTimeouts are the durations after which the apply of a resource fails, by group/kind of the resources, e.g. \"apiextensions.k8s.io/CustomResourceDefinition\": \"60s\". Resources of the core group are keyed by their kind. Overrides the default resource apply timeout of the application controller",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Allows: true,
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+									},
+								},
+							},
+						},
+					},
+					"deletionOrder": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeletionOrder is the order in which the resources of the application are deleted during a cascaded deletion, by group/kind of the resources, e.g. \"argoproj.io/Rollout\". Resources of the core group are listed by their kind. The resources of unlisted kinds are deleted last. Custom resource definitions are only deleted if listed",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+					"retryOn": {
+						SchemaProps: spec.SchemaProps{
+							Description: "RetryOn retries the failed syncs whose error matches a pattern, e.g. transient errors of the Kubernetes API, with the limit and backoff of the pattern instead of the retry strategy. If set without a retry strategy, the failed syncs whose error matches no pattern are not retried",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref("github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncRetryPattern"),
+									},
+								},
+							},
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ManagedNamespaceMetadata", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RetryStrategy", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncPolicyAutomated"},
+			"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ApplyRateLimit", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.AutoRollback", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.ManagedNamespaceMetadata", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.PreFlightCheck", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.RetryStrategy", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncPolicyAutomated", "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1.SyncRetryPattern", "k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
 	}
 }
 
@@ -7895,6 +9540,43 @@ func schema_pkg_apis_application_v1alpha1_SyncPolicyAutomated(ref common.Referen
 	}
 }
 
+func schema_pkg_apis_application_v1alpha1_SyncRetryPattern(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "SyncRetryPattern retries the failed syncs whose error matches a pattern",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"errorPattern": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ErrorPattern is the regular expression matched against the messages of the resources whose sync failed, or against the message of the operation if it failed before syncing any resource, e.g. \"(?i)too many requests|the object has been modified\"",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maxRetries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxRetries is the maximum number of retries of a sync failing with a matching error, defaults to 1",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"backoff": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Backoff is the duration to wait before each retry, e.g. \"5s\". The sync is retried immediately if not set",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Duration"),
+						},
+					},
+				},
+				Required: []string{"errorPattern"},
+			},
+		},
+		Dependencies: []string{
+			"k8s.io/apimachinery/pkg/apis/meta/v1.Duration"},
+	}
+}
+
 func schema_pkg_apis_application_v1alpha1_SyncStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -8102,6 +9784,20 @@ func schema_pkg_apis_application_v1alpha1_SyncWindow(ref common.ReferenceCallbac
 							Format:      "",
 						},
 					},
+					"chronoSchedule": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ChronoSchedule is an alternative to Schedule and Duration, specifying the window with an extended expression such as `every weekday from 09:00 to 17:00 in America/New_York except 12-25`. TimeZone is used if the expression has no time zone.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"allowOverride": {
+						SchemaProps: spec.SchemaProps{
+							Description: "AllowOverride allows users with the permission to override syncs to sync manually when the window would otherwise block the sync, e.g. to deploy an emergency fix",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -0,0 +1,84 @@
+package v1alpha1
+
+import (
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RepositoryConfig is the declarative configuration of a repository of Argo CD. The application controller reconciles
+// it into the repository secret Argo CD reads the repository and its credentials from.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:path=repositoryconfigs,shortName=repoconfig;repoconfigs
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="URL",type=string,JSONPath=`.spec.url`
+// +kubebuilder:printcolumn:name="Secret",type=string,JSONPath=`.status.secretName`
+// +kubebuilder:printcolumn:name="Error",type=string,JSONPath=`.status.error`
+type RepositoryConfig struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata" protobuf:"bytes,1,opt,name=metadata"`
+	Spec              RepositoryConfigSpec   `json:"spec" protobuf:"bytes,2,opt,name=spec"`
+	Status            RepositoryConfigStatus `json:"status,omitempty" protobuf:"bytes,3,opt,name=status"`
+}
+
+// RepositoryConfigList is a list of RepositoryConfig resources
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type RepositoryConfigList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata" protobuf:"bytes,1,opt,name=metadata"`
+	Items           []RepositoryConfig `json:"items" protobuf:"bytes,2,rep,name=items"`
+}
+
+// RepositoryConfigSpec is the configuration of a repository. The sensitive values are read from Secrets of the Argo CD
+// namespace.
+type RepositoryConfigSpec struct {
+	// URL is the URL of the repository
+	URL string `json:"url" protobuf:"bytes,1,opt,name=url"`
+	// Type is the type of the repository, git or helm. Defaults to git
+	// +kubebuilder:validation:Enum=git;helm
+	Type string `json:"type,omitempty" protobuf:"bytes,2,opt,name=type"`
+	// Name is the name of the repository, required for Helm repositories
+	Name string `json:"name,omitempty" protobuf:"bytes,3,opt,name=name"`
+	// Project is the project the repository is scoped to, the repository is global if empty
+	Project string `json:"project,omitempty" protobuf:"bytes,4,opt,name=project"`
+	// Username is the username used to access the repository
+	Username string `json:"username,omitempty" protobuf:"bytes,5,opt,name=username"`
+	// Password is the password or token used to access the repository
+	Password *RepositoryConfigSecretValue `json:"password,omitempty" protobuf:"bytes,6,opt,name=password"`
+	// SSHPrivateKey is the SSH private key used to access the repository
+	SSHPrivateKey *RepositoryConfigSecretValue `json:"sshPrivateKey,omitempty" protobuf:"bytes,7,opt,name=sshPrivateKey"`
+	// TLSClientCertData is the PEM encoded certificate presented to the repository
+	TLSClientCertData string `json:"tlsClientCertData,omitempty" protobuf:"bytes,8,opt,name=tlsClientCertData"`
+	// TLSClientCertKey is the PEM encoded private key of the certificate presented to the repository
+	TLSClientCertKey *RepositoryConfigSecretValue `json:"tlsClientCertKey,omitempty" protobuf:"bytes,9,opt,name=tlsClientCertKey"`
+	// Insecure skips the verification of the TLS certificate and of the SSH host key of the repository
+	Insecure bool `json:"insecure,omitempty" protobuf:"varint,10,opt,name=insecure"`
+	// EnableLFS enables Git LFS for the repository
+	EnableLFS bool `json:"enableLfs,omitempty" protobuf:"varint,11,opt,name=enableLfs"`
+	// EnableOCI marks a Helm repository as an OCI registry
+	EnableOCI bool `json:"enableOCI,omitempty" protobuf:"varint,12,opt,name=enableOCI"`
+	// Proxy is the HTTP/HTTPS proxy used to access the repository
+	Proxy string `json:"proxy,omitempty" protobuf:"bytes,13,opt,name=proxy"`
+}
+
+// RepositoryConfigSecretValue is a sensitive value of a repository configuration
+type RepositoryConfigSecretValue struct {
+	// ValueFrom is the source of the value
+	ValueFrom RepositoryConfigSecretValueSource `json:"valueFrom" protobuf:"bytes,1,opt,name=valueFrom"`
+}
+
+// RepositoryConfigSecretValueSource is the source of a sensitive value of a repository configuration
+type RepositoryConfigSecretValueSource struct {
+	// SecretKeyRef selects the key of a Secret of the Argo CD namespace holding the value
+	SecretKeyRef *v1.SecretKeySelector `json:"secretKeyRef" protobuf:"bytes,1,opt,name=secretKeyRef"`
+}
+
+// RepositoryConfigStatus is the state of the reconciliation of a repository configuration
+type RepositoryConfigStatus struct {
+	// SecretName is the name of the repository secret the configuration is reconciled into
+	SecretName string `json:"secretName,omitempty" protobuf:"bytes,1,opt,name=secretName"`
+	// ObservedGeneration is the generation of the configuration last reconciled
+	ObservedGeneration int64 `json:"observedGeneration,omitempty" protobuf:"varint,2,opt,name=observedGeneration"`
+	// Error is the error which prevented the last reconciliation of the configuration, empty once it succeeded
+	Error string `json:"error,omitempty" protobuf:"bytes,3,opt,name=error"`
+}
@@ -94,6 +94,21 @@ type Repository struct {
 	GCPServiceAccountKey string `json:"gcpServiceAccountKey,omitempty" protobuf:"bytes,21,opt,name=gcpServiceAccountKey"`
 	// ForceHttpBasicAuth specifies whether Argo CD should attempt to force basic auth for HTTP connections
 	ForceHttpBasicAuth bool `json:"forceHttpBasicAuth,omitempty" protobuf:"bytes,22,opt,name=forceHttpBasicAuth"`
+	// OCIType specifies the type of the artifacts of an OCI repository, either "helm" for Helm charts or "manifests" for
+	// the tarballs of manifests of OCI sources. "helm" is assumed if empty or absent. Only used with OCI repos.
+	OCIType string `json:"ociType,omitempty" protobuf:"bytes,23,opt,name=ociType"`
+}
+
+const (
+	// OCITypeHelm is the type of the OCI repositories holding Helm charts
+	OCITypeHelm = "helm"
+	// OCITypeManifests is the type of the OCI repositories holding the tarballs of manifests of OCI sources
+	OCITypeManifests = "manifests"
+)
+
+// IsOCIManifests returns true if the repository is an OCI repository holding the manifests of OCI sources
+func (repo *Repository) IsOCIManifests() bool {
+	return repo.EnableOCI && repo.OCIType == OCITypeManifests
 }
 
 // IsInsecure returns true if the repository has been configured to skip server verification
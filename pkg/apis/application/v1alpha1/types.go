@@ -36,6 +36,7 @@ import (
 	"sigs.k8s.io/yaml"
 
 	"github.com/argoproj/argo-cd/v2/common"
+	apputil "github.com/argoproj/argo-cd/v2/util/app"
 	"github.com/argoproj/argo-cd/v2/util/collections"
 	"github.com/argoproj/argo-cd/v2/util/env"
 	"github.com/argoproj/argo-cd/v2/util/helm"
@@ -84,6 +85,12 @@ type ApplicationSpec struct {
 
 	// Sources is a reference to the location of the application's manifests or chart
 	Sources ApplicationSources `json:"sources,omitempty" protobuf:"bytes,8,opt,name=sources"`
+	// IgnoreMissingHealthChecks treats resources without a built-in or custom health check as healthy, regardless of the
+	// default health the controller assumes for such resources
+	IgnoreMissingHealthChecks bool `json:"ignoreMissingHealthChecks,omitempty" protobuf:"bytes,9,opt,name=ignoreMissingHealthChecks"`
+	// Destinations are the additional destinations the manifests of the application are deployed to. The manifests are
+	// generated once, and compared and synced to the destination and each additional destination independently.
+	Destinations []ApplicationDestination `json:"destinations,omitempty" protobuf:"bytes,10,opt,name=destinations"`
 }
 
 type IgnoreDifferences []ResourceIgnoreDifferences
@@ -189,6 +196,24 @@ type ApplicationSource struct {
 	Chart string `json:"chart,omitempty" protobuf:"bytes,12,opt,name=chart"`
 	// Ref is reference to another source within sources field. This field will not be used if used with a `source` tag.
 	Ref string `json:"ref,omitempty" protobuf:"bytes,13,opt,name=ref"`
+	// Inline holds manifests which are defined in the application itself instead of a repository
+	Inline *ApplicationSourceInline `json:"inline,omitempty" protobuf:"bytes,14,opt,name=inline"`
+	// Ytt holds Carvel ytt specific options
+	Ytt *ApplicationSourceYtt `json:"ytt,omitempty" protobuf:"bytes,15,opt,name=ytt"`
+	// VerifyAttestation holds the attestations which must be attached to the container images of the source before it is synced
+	VerifyAttestation *ApplicationSourceVerifyAttestation `json:"verifyAttestation,omitempty" protobuf:"bytes,16,opt,name=verifyAttestation"`
+	// Starlark holds options specific to applications rendered with a Starlark script
+	Starlark *ApplicationSourceStarlark `json:"starlark,omitempty" protobuf:"bytes,17,opt,name=starlark"`
+	// Cue holds options specific to applications rendered from a CUE configuration
+	Cue *ApplicationSourceCue `json:"cue,omitempty" protobuf:"bytes,18,opt,name=cue"`
+	// HTTP holds the URL of manifests which are fetched over HTTP or HTTPS instead of a repository
+	HTTP *ApplicationSourceHTTP `json:"http,omitempty" protobuf:"bytes,19,opt,name=http"`
+	// Pulumi holds options specific to applications rendered from the preview of a Pulumi program
+	Pulumi *ApplicationSourcePulumi `json:"pulumi,omitempty" protobuf:"bytes,20,opt,name=pulumi"`
+	// OCI holds the image of an OCI artifact whose layers hold the manifests, which is tracked instead of a Git repository
+	OCI *ApplicationSourceOCI `json:"oci,omitempty" protobuf:"bytes,21,opt,name=oci"`
+	// Timoni holds options specific to applications rendered from a Timoni module
+	Timoni *ApplicationSourceTimoni `json:"timoni,omitempty" protobuf:"bytes,22,opt,name=timoni"`
 }
 
 // ApplicationSources contains list of required information about the sources of an application
@@ -236,6 +261,16 @@ func (a *ApplicationSpec) HasMultipleSources() bool {
 	return a.Sources != nil && len(a.Sources) > 0
 }
 
+// GetDestinations returns the destination of the application followed by its additional destinations
+func (a *ApplicationSpec) GetDestinations() []ApplicationDestination {
+	return append([]ApplicationDestination{a.Destination}, a.Destinations...)
+}
+
+// HasMultipleDestinations returns true if the manifests of the application are deployed to additional destinations
+func (a *ApplicationSpec) HasMultipleDestinations() bool {
+	return len(a.Destinations) > 0
+}
+
 func (a *ApplicationSpec) GetSourcePtrByPosition(sourcePosition int) *ApplicationSource {
 	// if Application has multiple sources, return the first source in sources
 	return a.GetSourcePtrByIndex(sourcePosition - 1)
@@ -272,6 +307,21 @@ func (a *ApplicationSource) IsHelm() bool {
 	return a.Chart != ""
 }
 
+// IsInline returns true when the manifests of the application source are defined inline
+func (a *ApplicationSource) IsInline() bool {
+	return a != nil && a.Inline != nil
+}
+
+// IsHTTP returns true when the manifests of the application source are fetched from a URL
+func (a *ApplicationSource) IsHTTP() bool {
+	return a != nil && a.HTTP != nil
+}
+
+// IsOCI returns true when the manifests of the application source are fetched from an OCI artifact
+func (a *ApplicationSource) IsOCI() bool {
+	return a != nil && a.OCI != nil
+}
+
 // IsHelmOci returns true when the application source is of type Helm OCI
 func (a *ApplicationSource) IsHelmOci() bool {
 	if a.Chart == "" {
@@ -289,7 +339,15 @@ func (a *ApplicationSource) IsZero() bool {
 			a.Helm.IsZero() &&
 			a.Kustomize.IsZero() &&
 			a.Directory.IsZero() &&
-			a.Plugin.IsZero()
+			a.Plugin.IsZero() &&
+			a.Inline.IsZero() &&
+			a.Ytt.IsZero() &&
+			a.Starlark.IsZero() &&
+			a.Cue.IsZero() &&
+			a.HTTP.IsZero() &&
+			a.Pulumi.IsZero() &&
+			a.OCI.IsZero() &&
+			a.Timoni.IsZero()
 }
 
 // GetNamespaceOrDefault gets the static namespace configured in the source. If none is configured, returns the given
@@ -345,6 +403,14 @@ const (
 	ApplicationSourceTypeKustomize ApplicationSourceType = "Kustomize"
 	ApplicationSourceTypeDirectory ApplicationSourceType = "Directory"
 	ApplicationSourceTypePlugin    ApplicationSourceType = "Plugin"
+	ApplicationSourceTypeInline    ApplicationSourceType = "Inline"
+	ApplicationSourceTypeYtt       ApplicationSourceType = "Ytt"
+	ApplicationSourceTypeStarlark  ApplicationSourceType = "Starlark"
+	ApplicationSourceTypeCue       ApplicationSourceType = "Cue"
+	ApplicationSourceTypeHTTP      ApplicationSourceType = "HTTP"
+	ApplicationSourceTypePulumi    ApplicationSourceType = "Pulumi"
+	ApplicationSourceTypeOCI       ApplicationSourceType = "OCI"
+	ApplicationSourceTypeTimoni    ApplicationSourceType = "Timoni"
 )
 
 // RefreshType specifies how to refresh the sources of a given application
@@ -395,6 +461,39 @@ type ApplicationSourceHelm struct {
 	// APIVersions specifies the Kubernetes resource API versions to pass to Helm when templating manifests. By default,
 	// Argo CD uses the API versions of the target cluster. The format is [group/]version/kind.
 	APIVersions []string `json:"apiVersions,omitempty" protobuf:"bytes,13,opt,name=apiVersions"`
+	// ValuesFrom references Helm values stored in a ConfigMap or Secret of the destination cluster, which are read when
+	// generating manifests. They are merged after the value files and before the inline values.
+	ValuesFrom *HelmValuesFrom `json:"valuesFrom,omitempty" protobuf:"bytes,14,opt,name=valuesFrom"`
+	// ValueFilesSuffix is the name of a label of the destination cluster. When set, the value of the label is appended to
+	// the name of each value file, e.g. values-us-east-1.yaml for values.yaml and the label region=us-east-1, and the
+	// suffixed value file is used instead of the value file if it exists.
+	ValueFilesSuffix string `json:"valueFilesSuffix,omitempty" protobuf:"bytes,15,opt,name=valueFilesSuffix"`
+	// ValidateCRs validates the custom resources generated by Helm against the OpenAPI schemas of the
+	// CustomResourceDefinitions generated alongside them, and fails the manifest generation if they do not match
+	ValidateCRs bool `json:"validateCRs,omitempty" protobuf:"bytes,16,opt,name=validateCRs"`
+	// AutoValuesFile appends the value files named after the destination namespace and the name of the destination cluster,
+	// values-<namespace>.yaml and values-<cluster name>.yaml, to the value files if they exist in the directory of the chart
+	AutoValuesFile bool `json:"autoValuesFile,omitempty" protobuf:"bytes,17,opt,name=autoValuesFile"`
+}
+
+// HelmValuesFrom references Helm values stored in the destination cluster
+type HelmValuesFrom struct {
+	// ConfigMapKeyRef references a key of a ConfigMap holding Helm values in YAML format
+	ConfigMapKeyRef *HelmValuesKeyRef `json:"configMapKeyRef,omitempty" protobuf:"bytes,1,opt,name=configMapKeyRef"`
+	// SecretKeyRef references a key of a Secret holding Helm values in YAML format. They are merged after the values of
+	// ConfigMapKeyRef.
+	SecretKeyRef *HelmValuesKeyRef `json:"secretKeyRef,omitempty" protobuf:"bytes,2,opt,name=secretKeyRef"`
+}
+
+// HelmValuesKeyRef references a key of a ConfigMap or Secret in the destination cluster
+type HelmValuesKeyRef struct {
+	// Namespace is the namespace of the ConfigMap or Secret, which must be a permitted destination of the project.
+	// Defaults to the destination namespace of the application.
+	Namespace string `json:"namespace,omitempty" protobuf:"bytes,1,opt,name=namespace"`
+	// Name is the name of the ConfigMap or Secret
+	Name string `json:"name" protobuf:"bytes,2,opt,name=name"`
+	// Key is the key of the ConfigMap or Secret holding the values
+	Key string `json:"key" protobuf:"bytes,3,opt,name=key"`
 }
 
 // HelmParameter is a parameter that's passed to helm template during manifest generation
@@ -476,7 +575,7 @@ func (in *ApplicationSourceHelm) AddFileParameter(p HelmFileParameter) {
 
 // IsZero Returns true if the Helm options in an application source are considered zero
 func (h *ApplicationSourceHelm) IsZero() bool {
-	return h == nil || (h.Version == "") && (h.ReleaseName == "") && len(h.ValueFiles) == 0 && len(h.Parameters) == 0 && len(h.FileParameters) == 0 && h.ValuesIsEmpty() && !h.PassCredentials && !h.IgnoreMissingValueFiles && !h.SkipCrds && h.KubeVersion == "" && len(h.APIVersions) == 0 && h.Namespace == ""
+	return h == nil || (h.Version == "") && (h.ReleaseName == "") && len(h.ValueFiles) == 0 && len(h.Parameters) == 0 && len(h.FileParameters) == 0 && h.ValuesIsEmpty() && !h.PassCredentials && !h.IgnoreMissingValueFiles && !h.SkipCrds && h.KubeVersion == "" && len(h.APIVersions) == 0 && h.Namespace == "" && h.ValuesFrom == nil && !h.ValidateCRs
 }
 
 // KustomizeImage represents a Kustomize image definition in the format [old_image_name=]<image_name>:<image_tag>
@@ -549,6 +648,9 @@ type ApplicationSourceKustomize struct {
 	// APIVersions specifies the Kubernetes resource API versions to pass to Helm when templating manifests. By default,
 	// Argo CD uses the API versions of the target cluster. The format is [group/]version/kind.
 	APIVersions []string `json:"apiVersions,omitempty" protobuf:"bytes,16,opt,name=apiVersions"`
+	// Validate specifies whether to build the manifests with alpha plugins enabled, so that the schema validation
+	// warnings of the plugins are reported as SyncWarning conditions
+	Validate bool `json:"validate,omitempty" protobuf:"bytes,17,opt,name=validate"`
 }
 
 type KustomizeReplica struct {
@@ -656,7 +758,8 @@ func (k *ApplicationSourceKustomize) IsZero() bool {
 			len(k.Patches) == 0 &&
 			len(k.Components) == 0 &&
 			k.KubeVersion == "" &&
-			len(k.APIVersions) == 0
+			len(k.APIVersions) == 0 &&
+			!k.Validate
 }
 
 // MergeImage merges a new Kustomize image identifier in to a list of images
@@ -926,6 +1029,153 @@ func escaped(paramName string) string {
 	return invalidParamCharRegex.ReplaceAllString(newParamName, "_")
 }
 
+// InlineSourceRepoURL is the repository URL used by the CLI for inline sources. The repository URL of an inline source
+// is never contacted, but is still matched against the source repositories of the project.
+const InlineSourceRepoURL = "inline"
+
+// ApplicationSourceInline holds manifests which are defined in the application itself instead of a repository
+type ApplicationSourceInline struct {
+	// Manifests are the YAML manifests of the resources of the application. Multiple documents are separated by `---`.
+	Manifests string `json:"manifests" protobuf:"bytes,1,opt,name=manifests"`
+}
+
+// IsZero returns true if the inline source is considered empty
+func (i *ApplicationSourceInline) IsZero() bool {
+	return i == nil || i.Manifests == ""
+}
+
+// ApplicationSourceHTTP holds the URL of manifests which are fetched over HTTP or HTTPS instead of a repository
+type ApplicationSourceHTTP struct {
+	// URL is the HTTP or HTTPS URL of the YAML manifests. Multiple documents are separated by `---`.
+	URL string `json:"url" protobuf:"bytes,1,opt,name=url"`
+	// SHA256 is the expected hex encoded SHA256 digest of the manifests. The manifests are rejected if their digest
+	// differs. If empty, the manifests are fetched again once their cached copy expires, so that changes are detected.
+	SHA256 string `json:"sha256,omitempty" protobuf:"bytes,2,opt,name=sha256"`
+	// CABundleSecret references a key of a Secret in the Argo CD namespace holding the PEM encoded certificates of the
+	// certificate authorities trusted in addition to the system ones when fetching the manifests over HTTPS. The Secret
+	// must be labeled with app.kubernetes.io/part-of=argocd.
+	CABundleSecret *SecretRef `json:"caBundleSecret,omitempty" protobuf:"bytes,3,opt,name=caBundleSecret"`
+}
+
+// IsZero returns true if the HTTP source is considered empty
+func (h *ApplicationSourceHTTP) IsZero() bool {
+	return h == nil || h.URL == "" && h.SHA256 == "" && h.CABundleSecret == nil
+}
+
+// ApplicationSourceOCI holds the image of an OCI artifact whose layers are tarballs of manifests
+type ApplicationSourceOCI struct {
+	// Image is the reference of the OCI repository of the artifact, without tag or digest, e.g. ghcr.io/org/manifests
+	Image string `json:"image" protobuf:"bytes,1,opt,name=image"`
+	// Tag is the tag of the artifact, a digest pinning it, or a semantic version constraint selecting the highest
+	// matching tag. The tags of the repository are polled for new versions of the constraint. Defaults to latest.
+	Tag string `json:"tag,omitempty" protobuf:"bytes,2,opt,name=tag"`
+}
+
+// IsZero returns true if the OCI source is considered empty
+func (o *ApplicationSourceOCI) IsZero() bool {
+	return o == nil || o.Image == "" && o.Tag == ""
+}
+
+// ApplicationSourceTimoni holds options specific to applications rendered from a Timoni module
+type ApplicationSourceTimoni struct {
+	// Module is the directory of the module, relative to the application path, or the oci:// URL of a module published
+	// to a registry, optionally followed by the :version of the module. Defaults to the application path
+	Module string `json:"module,omitempty" protobuf:"bytes,1,opt,name=module"`
+	// Values are the values of the instance of the module, as YAML
+	Values string `json:"values,omitempty" protobuf:"bytes,2,opt,name=values"`
+}
+
+// IsZero returns true if the Timoni options are considered empty
+func (t *ApplicationSourceTimoni) IsZero() bool {
+	return t == nil || t.Module == "" && t.Values == ""
+}
+
+// ApplicationSourcePulumi holds options specific to applications rendered from the preview of a Pulumi program
+type ApplicationSourcePulumi struct {
+	// Runtime is the language runtime of the program, one of nodejs, python or go
+	// +kubebuilder:validation:Enum=nodejs;python;go
+	Runtime string `json:"runtime" protobuf:"bytes,1,opt,name=runtime"`
+	// Entrypoint is the directory of the program, relative to the application path. Defaults to the application path
+	Entrypoint string `json:"entrypoint,omitempty" protobuf:"bytes,2,opt,name=entrypoint"`
+	// Config are the configuration values of the program, set on the ephemeral stack the program is previewed with
+	Config map[string]string `json:"config,omitempty" protobuf:"bytes,3,rep,name=config"`
+}
+
+// IsZero returns true if the Pulumi options are considered empty
+func (p *ApplicationSourcePulumi) IsZero() bool {
+	return p == nil || p.Runtime == "" && p.Entrypoint == "" && len(p.Config) == 0
+}
+
+// ApplicationSourceYtt holds options specific to applications rendered with Carvel ytt
+type ApplicationSourceYtt struct {
+	// Lib is a list of additional template or library paths passed to ytt, relative to the application path
+	Lib []string `json:"lib,omitempty" protobuf:"bytes,1,rep,name=lib"`
+	// DataValues are data values passed to ytt with --data-value
+	DataValues map[string]string `json:"dataValues,omitempty" protobuf:"bytes,2,rep,name=dataValues"`
+}
+
+// IsZero returns true if the ytt options are considered empty
+func (y *ApplicationSourceYtt) IsZero() bool {
+	return y == nil || len(y.Lib) == 0 && len(y.DataValues) == 0
+}
+
+// ApplicationSourceVerifyAttestation holds the attestations required for an application source and its container images
+type ApplicationSourceVerifyAttestation struct {
+	// SBOM requires a CycloneDX or SPDX software bill of materials to be attached to each container image as a cosign attestation
+	SBOM bool `json:"sbom,omitempty" protobuf:"varint,1,opt,name=sbom"`
+	// InToto requires the manifests of the source to be produced by the supply chain described by an in-toto layout
+	InToto *InTotoAttestation `json:"inToto,omitempty" protobuf:"bytes,2,opt,name=inToto"`
+}
+
+// RequiresSBOM returns true if the container images of the source must have an SBOM attestation
+func (v *ApplicationSourceVerifyAttestation) RequiresSBOM() bool {
+	return v != nil && v.SBOM
+}
+
+// InTotoLayout returns the name of the ConfigMap holding the in-toto layout of the source, or an empty string if the
+// source does not require an in-toto attestation
+func (v *ApplicationSourceVerifyAttestation) InTotoLayout() string {
+	if v == nil || v.InToto == nil {
+		return ""
+	}
+	return v.InToto.Layout
+}
+
+// InTotoAttestation references the in-toto layout verified against the link metadata files of a Git source
+type InTotoAttestation struct {
+	// Layout is the name of a ConfigMap of the Argo CD namespace, labeled with app.kubernetes.io/part-of=argocd, which
+	// holds the signed in-toto layout in its root.layout key and the PEM encoded public keys of the layout owners in
+	// keys with the .pub suffix
+	Layout string `json:"layout" protobuf:"bytes,1,opt,name=layout"`
+}
+
+// ApplicationSourceStarlark holds options specific to applications rendered with a Starlark script
+type ApplicationSourceStarlark struct {
+	// Entrypoint is the path of the Starlark script rendering the manifests, relative to the application path. The
+	// script must define a main function returning a list of Kubernetes manifests.
+	Entrypoint string `json:"entrypoint" protobuf:"bytes,1,opt,name=entrypoint"`
+	// Values is a YAML or JSON object passed to the script as the values dict
+	Values string `json:"values,omitempty" protobuf:"bytes,2,opt,name=values"`
+}
+
+// IsZero returns true if the Starlark options are considered empty
+func (s *ApplicationSourceStarlark) IsZero() bool {
+	return s == nil || s.Entrypoint == "" && s.Values == ""
+}
+
+// ApplicationSourceCue holds options specific to applications rendered from a CUE configuration
+type ApplicationSourceCue struct {
+	// Entrypoint is the CUE file or package exported to manifests, relative to the application path. Defaults to the package of the application path
+	Entrypoint string `json:"entrypoint,omitempty" protobuf:"bytes,1,opt,name=entrypoint"`
+	// Tags are the values injected into the fields of the configuration marked with a @tag() attribute
+	Tags map[string]string `json:"tags,omitempty" protobuf:"bytes,2,rep,name=tags"`
+}
+
+// IsZero returns true if the CUE options are considered empty
+func (c *ApplicationSourceCue) IsZero() bool {
+	return c == nil || c.Entrypoint == "" && len(c.Tags) == 0
+}
+
 // ApplicationSourcePlugin holds options specific to config management plugins
 type ApplicationSourcePlugin struct {
 	Name       string `json:"name,omitempty" protobuf:"bytes,1,opt,name=name"`
@@ -1035,6 +1285,90 @@ type ApplicationStatus struct {
 	SourceTypes []ApplicationSourceType `json:"sourceTypes,omitempty" protobuf:"bytes,12,opt,name=sourceTypes"`
 	// ControllerNamespace indicates the namespace in which the application controller is located
 	ControllerNamespace string `json:"controllerNamespace,omitempty" protobuf:"bytes,13,opt,name=controllerNamespace"`
+	// ConditionHistory is the list of the last transitions of the health, sync status and conditions of the application, oldest first
+	ConditionHistory []ConditionHistoryEntry `json:"conditionHistory,omitempty" protobuf:"bytes,14,opt,name=conditionHistory"`
+	// RollbackHistory is the list of the last automatic rollbacks of the application, oldest first
+	RollbackHistory []RollbackHistoryEntry `json:"rollbackHistory,omitempty" protobuf:"bytes,15,opt,name=rollbackHistory"`
+	// AutoValueFiles are the Helm value files which were appended to the value files of the sources because they are named
+	// after the destination namespace or cluster, relative to the root of their repository
+	AutoValueFiles []string `json:"autoValueFiles,omitempty" protobuf:"bytes,16,opt,name=autoValueFiles"`
+	// DestinationStatuses are the sync and health statuses of each destination of an application with additional
+	// destinations, the destination first
+	DestinationStatuses []DestinationSyncStatus `json:"destinationStatuses,omitempty" protobuf:"bytes,17,opt,name=destinationStatuses"`
+}
+
+// RollbackHistoryLimit is the maximum number of automatic rollbacks kept in the rollback history of an application
+const RollbackHistoryLimit = 10
+
+// RollbackHistoryEntry is an automatic rollback of an application which became degraded after a sync
+type RollbackHistoryEntry struct {
+	// FromRevision is the revision whose sync degraded the application
+	FromRevision string `json:"fromRevision" protobuf:"bytes,1,opt,name=fromRevision"`
+	// ToRevision is the revision the application was rolled back to
+	ToRevision string `json:"toRevision" protobuf:"bytes,2,opt,name=toRevision"`
+	// ToHistoryID is the ID of the history entry the application was rolled back to
+	ToHistoryID int64 `json:"toHistoryID" protobuf:"bytes,3,opt,name=toHistoryID"`
+	// RolledBackAt is the time the rollback was initiated
+	RolledBackAt metav1.Time `json:"rolledBackAt" protobuf:"bytes,4,opt,name=rolledBackAt"`
+	// Message is the reason of the rollback
+	Message string `json:"message,omitempty" protobuf:"bytes,5,opt,name=message"`
+}
+
+// AddRollbackHistory appends the given rollback to the rollback history, keeping only the last RollbackHistoryLimit
+// rollbacks
+func (a *ApplicationStatus) AddRollbackHistory(entry RollbackHistoryEntry) {
+	a.RollbackHistory = append(a.RollbackHistory, entry)
+	if len(a.RollbackHistory) > RollbackHistoryLimit {
+		a.RollbackHistory = a.RollbackHistory[len(a.RollbackHistory)-RollbackHistoryLimit:]
+	}
+}
+
+// RollbackCount returns the number of automatic rollbacks of the given revision recorded in the rollback history
+func (a *ApplicationStatus) RollbackCount(revision string) int64 {
+	var count int64
+	for _, entry := range a.RollbackHistory {
+		if entry.FromRevision == revision {
+			count++
+		}
+	}
+	return count
+}
+
+const (
+	// ConditionHistoryLimit is the maximum number of transitions kept in the condition history of an application
+	ConditionHistoryLimit = 50
+	// ConditionHistoryTypeHealth is the type of the condition history entries recording health status transitions
+	ConditionHistoryTypeHealth = "Health"
+	// ConditionHistoryTypeSync is the type of the condition history entries recording sync status transitions
+	ConditionHistoryTypeSync = "Sync"
+	// ConditionHistoryStatusTrue is the status of the condition history entries recording the appearance of a condition
+	ConditionHistoryStatusTrue = "True"
+	// ConditionHistoryStatusFalse is the status of the condition history entries recording the resolution of a condition
+	ConditionHistoryStatusFalse = "False"
+)
+
+// ConditionHistoryEntry is a transition of the health status, the sync status or a condition of an application
+type ConditionHistoryEntry struct {
+	// Type is Health or Sync for health and sync status transitions, or the type of the application condition
+	Type string `json:"type" protobuf:"bytes,1,opt,name=type"`
+	// Status is the health or sync status after the transition, or True or False when a condition appeared or was resolved
+	Status string `json:"status" protobuf:"bytes,2,opt,name=status"`
+	// LastTransitionTime is the time of the transition
+	LastTransitionTime *metav1.Time `json:"lastTransitionTime,omitempty" protobuf:"bytes,3,opt,name=lastTransitionTime"`
+	// Message is the message of the health status or of the condition
+	Message string `json:"message,omitempty" protobuf:"bytes,4,opt,name=message"`
+}
+
+// AddConditionHistory appends the given transitions to the condition history, keeping only the last
+// ConditionHistoryLimit transitions
+func (a *ApplicationStatus) AddConditionHistory(entries ...ConditionHistoryEntry) {
+	if len(entries) == 0 {
+		return
+	}
+	a.ConditionHistory = append(a.ConditionHistory, entries...)
+	if len(a.ConditionHistory) > ConditionHistoryLimit {
+		a.ConditionHistory = a.ConditionHistory[len(a.ConditionHistory)-ConditionHistoryLimit:]
+	}
 }
 
 // GetRevisions will return the current revision associated with the Application.
@@ -1171,6 +1505,12 @@ type SyncOperation struct {
 	// Revisions is the list of revision (Git) or chart version (Helm) which to sync each source in sources field for the application to
 	// If omitted, will use the revision specified in app spec.
 	Revisions []string `json:"revisions,omitempty" protobuf:"bytes,11,opt,name=revisions"`
+	// SyncWindowOverrideReason is the reason given for overriding the sync windows blocking the sync. The sync windows
+	// are only overridden if they allow it and the sync has been triggered manually.
+	SyncWindowOverrideReason string `json:"syncWindowOverrideReason,omitempty" protobuf:"bytes,12,opt,name=syncWindowOverrideReason"`
+	// Destinations selects the destinations of an application with additional destinations which are synced. All the
+	// destinations are synced if omitted.
+	Destinations []ApplicationDestination `json:"destinations,omitempty" protobuf:"bytes,13,opt,name=destinations"`
 }
 
 // IsApplyStrategy returns true if the sync strategy is "apply"
@@ -1178,6 +1518,22 @@ func (o *SyncOperation) IsApplyStrategy() bool {
 	return o.SyncStrategy != nil && o.SyncStrategy.Apply != nil
 }
 
+// SyncsDestination returns true if the destination is synced by the operation, i.e. if no destination is selected or
+// if the server, name and namespace set in one of the selected destinations match the ones of the destination
+func (o *SyncOperation) SyncsDestination(dest ApplicationDestination) bool {
+	if len(o.Destinations) == 0 {
+		return true
+	}
+	for _, selected := range o.Destinations {
+		if (selected.Server == "" || selected.Server == dest.Server) &&
+			(selected.Name == "" || selected.Name == dest.Name) &&
+			(selected.Namespace == "" || selected.Namespace == dest.Namespace) {
+			return true
+		}
+	}
+	return false
+}
+
 // OperationState contains information about state of a running operation
 type OperationState struct {
 	// Operation is the original requested operation
@@ -1194,6 +1550,60 @@ type OperationState struct {
 	FinishedAt *metav1.Time `json:"finishedAt,omitempty" protobuf:"bytes,7,opt,name=finishedAt"`
 	// RetryCount contains time of operation retries
 	RetryCount int64 `json:"retryCount,omitempty" protobuf:"bytes,8,opt,name=retryCount"`
+	// Progress reports the progress of a running sync operation
+	Progress *SyncOperationProgress `json:"progress,omitempty" protobuf:"bytes,9,opt,name=progress"`
+	// WebhookConfirmationStartedAt is the time the succeeded sync operation started waiting for the post-sync webhook
+	// of the project to confirm it
+	WebhookConfirmationStartedAt *metav1.Time `json:"webhookConfirmationStartedAt,omitempty" protobuf:"bytes,10,opt,name=webhookConfirmationStartedAt"`
+	// ErrorCode is the machine readable reason of the failure of the operation, empty unless the operation failed
+	ErrorCode SyncErrorCode `json:"errorCode,omitempty" protobuf:"bytes,11,opt,name=errorCode,casttype=SyncErrorCode"`
+}
+
+// SyncErrorCode is the machine readable reason of the failure of a sync operation or of the sync of a resource
+type SyncErrorCode string
+
+const (
+	// SyncErrorCodeInvalidOperation is set if the requested operation or the spec of the application is invalid
+	SyncErrorCodeInvalidOperation SyncErrorCode = "InvalidOperation"
+	// SyncErrorCodeComparisonFailed is set if the desired state of the application could not be generated or compared
+	// to the live state
+	SyncErrorCodeComparisonFailed SyncErrorCode = "ComparisonFailed"
+	// SyncErrorCodePermissionDenied is set if the project of the application does not permit the operation
+	SyncErrorCodePermissionDenied SyncErrorCode = "PermissionDenied"
+	// SyncErrorCodeQuotaExceeded is set if the resources of the application exceed the resource quota of the project
+	SyncErrorCodeQuotaExceeded SyncErrorCode = "QuotaExceeded"
+	// SyncErrorCodeSharedResource is set if a resource of the application is managed by another application
+	SyncErrorCodeSharedResource SyncErrorCode = "SharedResource"
+	// SyncErrorCodeClusterError is set if the destination cluster could not be accessed
+	SyncErrorCodeClusterError SyncErrorCode = "ClusterError"
+	// SyncErrorCodePreFlightFailed is set if a check made before the first resource is applied failed, e.g. the
+	// capacity of the cluster, the attestations of the images or the server-side apply conflicts
+	SyncErrorCodePreFlightFailed SyncErrorCode = "PreFlightFailed"
+	// SyncErrorCodeApplyFailed is set if a resource could not be applied or pruned
+	SyncErrorCodeApplyFailed SyncErrorCode = "ApplyFailed"
+	// SyncErrorCodeHookFailed is set if a hook failed
+	SyncErrorCodeHookFailed SyncErrorCode = "HookFailed"
+	// SyncErrorCodeHealthCheckFailed is set if a resource was applied but became degraded during the sync
+	SyncErrorCodeHealthCheckFailed SyncErrorCode = "HealthCheckFailed"
+	// SyncErrorCodeTimeout is set if the sync or the apply of a resource did not complete within its timeout
+	SyncErrorCodeTimeout SyncErrorCode = "Timeout"
+	// SyncErrorCodeTerminated is set if the operation was terminated before it completed
+	SyncErrorCodeTerminated SyncErrorCode = "Terminated"
+	// SyncErrorCodeInternalError is set if the operation failed because of an internal error of Argo CD
+	SyncErrorCodeInternalError SyncErrorCode = "InternalError"
+	// SyncErrorCodeUnknown is set if the operation failed for a reason which has no dedicated code
+	SyncErrorCodeUnknown SyncErrorCode = "Unknown"
+)
+
+// SyncOperationProgress reports how many resources a sync operation applied and when it is expected to complete
+type SyncOperationProgress struct {
+	// ResourcesApplied is the number of resources applied so far
+	ResourcesApplied int64 `json:"resourcesApplied,omitempty" protobuf:"bytes,1,opt,name=resourcesApplied"`
+	// ResourcesTotal is the number of resources the operation applies
+	ResourcesTotal int64 `json:"resourcesTotal,omitempty" protobuf:"bytes,2,opt,name=resourcesTotal"`
+	// EstimatedCompletionTime is the time the operation is expected to have applied all its resources, estimated from
+	// the moving average of the time taken to apply a resource
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty" protobuf:"bytes,3,opt,name=estimatedCompletionTime"`
 }
 
 type Info struct {
@@ -1250,12 +1660,186 @@ type SyncPolicy struct {
 	Retry *RetryStrategy `json:"retry,omitempty" protobuf:"bytes,3,opt,name=retry"`
 	// ManagedNamespaceMetadata controls metadata in the given namespace (if CreateNamespace=true)
 	ManagedNamespaceMetadata *ManagedNamespaceMetadata `json:"managedNamespaceMetadata,omitempty" protobuf:"bytes,4,opt,name=managedNamespaceMetadata"`
+	// ServerSideApplyConflictResolution controls how field manager conflicts of server-side applied resources are resolved, "fail" fails the sync and "force" takes over the conflicting fields and reports a warning for each of them
+	ServerSideApplyConflictResolution string `json:"serverSideApplyConflictResolution,omitempty" protobuf:"bytes,5,opt,name=serverSideApplyConflictResolution"`
+	// ApplyRateLimit limits the rate at which resources are applied to the destination cluster during a sync
+	ApplyRateLimit *ApplyRateLimit `json:"applyRateLimit,omitempty" protobuf:"bytes,6,opt,name=applyRateLimit"`
+	// ConflictResolution controls how field manager conflicts of server-side applied resources are resolved, "strict" fails the sync, "force" takes over the conflicting fields and "merge" only takes over the conflicting fields which are also owned by Argo CD. Takes precedence over ServerSideApplyConflictResolution
+	ConflictResolution string `json:"conflictResolution,omitempty" protobuf:"bytes,7,opt,name=conflictResolution"`
+	// SyncTimeout is the duration after which a sync which did not complete fails, e.g. "30m". Overrides the global sync timeout of the application controller
+	SyncTimeout string `json:"syncTimeout,omitempty" protobuf:"bytes,8,opt,name=syncTimeout"`
+	// PreFlightCheck is the capacity the destination cluster must have available before a sync applies any resource
+	PreFlightCheck *PreFlightCheck `json:"preFlightCheck,omitempty" protobuf:"bytes,9,opt,name=preFlightCheck"`
+	// AutoRollback rolls the application back to its previous revision when it becomes degraded shortly after a sync
+	AutoRollback *AutoRollback `json:"autoRollback,omitempty" protobuf:"bytes,10,opt,name=autoRollback"`
+	// ApplyTimeouts are the durations after which the apply of a resource fails, by group/kind of the resources, e.g. "apiextensions.k8s.io/CustomResourceDefinition": "60s". Resources of the core group are keyed by their kind. Overrides the default resource apply timeout of the application controller
+	ApplyTimeouts map[string]metav1.Duration `json:"applyTimeouts,omitempty" protobuf:"bytes,11,rep,name=applyTimeouts"`
+	// DeletionOrder is the order in which the resources of the application are deleted during a cascaded deletion, by group/kind of the resources, e.g. "argoproj.io/Rollout". Resources of the core group are listed by their kind. The resources of unlisted kinds are deleted last. Custom resource definitions are only deleted if listed
+	DeletionOrder []string `json:"deletionOrder,omitempty" protobuf:"bytes,12,rep,name=deletionOrder"`
+	// RetryOn retries the failed syncs whose error matches a pattern, e.g. transient errors of the Kubernetes API, with the limit and backoff of the pattern instead of the retry strategy. If set without a retry strategy, the failed syncs whose error matches no pattern are not retried
+	RetryOn []SyncRetryPattern `json:"retryOn,omitempty" protobuf:"bytes,13,rep,name=retryOn"`
 	// If you add a field here, be sure to update IsZero.
 }
 
+// AutoRollback rolls the application back to the revision of the previous entry of its history when it becomes
+// degraded within the health window after a successful sync
+type AutoRollback struct {
+	// HealthWindow is the duration after a successful sync during which a degraded application is rolled back, e.g. "5m"
+	HealthWindow string `json:"healthWindow" protobuf:"bytes,1,opt,name=healthWindow"`
+	// MaxRollbackAttempts is the maximum number of automatic rollbacks of the same revision, defaults to 1
+	MaxRollbackAttempts int64 `json:"maxRollbackAttempts,omitempty" protobuf:"bytes,2,opt,name=maxRollbackAttempts"`
+}
+
+// GetMaxRollbackAttempts returns the maximum number of automatic rollbacks of the same revision
+func (r *AutoRollback) GetMaxRollbackAttempts() int64 {
+	if r.MaxRollbackAttempts <= 0 {
+		return 1
+	}
+	return r.MaxRollbackAttempts
+}
+
+// PreFlightCheck is the capacity the destination cluster must have available before a sync applies any resource. The
+// available capacity is the allocatable resources of the ready and schedulable nodes, minus the resources requested by
+// the pods running on them.
+type PreFlightCheck struct {
+	// MinNodeCount is the minimum number of ready and schedulable nodes
+	MinNodeCount int64 `json:"minNodeCount,omitempty" protobuf:"bytes,1,opt,name=minNodeCount"`
+	// MinCPUAvailable is the minimum available CPU, as a quantity, e.g. "2" or "500m"
+	MinCPUAvailable string `json:"minCPUAvailable,omitempty" protobuf:"bytes,2,opt,name=minCPUAvailable"`
+	// MinMemoryAvailable is the minimum available memory, as a quantity, e.g. "4Gi"
+	MinMemoryAvailable string `json:"minMemoryAvailable,omitempty" protobuf:"bytes,3,opt,name=minMemoryAvailable"`
+}
+
+// MinApplyRequestsPerSecond is the lowest number of requests per second of an apply rate limit
+const MinApplyRequestsPerSecond = 0.1
+
+// ApplyRateLimit is a token bucket limiting the requests which modify resources of the destination cluster during a
+// sync of the application. It only delays the requests of the application, in addition to the default limit shared by
+// all applications syncing to the same cluster.
+type ApplyRateLimit struct {
+	// RequestsPerSecond is the number of requests per second, as a decimal number of at least 0.1, e.g. "2.5"
+	RequestsPerSecond string `json:"requestsPerSecond" protobuf:"bytes,1,opt,name=requestsPerSecond"`
+	// Burst is the number of requests which can be sent at once, defaults to RequestsPerSecond rounded up
+	Burst int64 `json:"burst,omitempty" protobuf:"bytes,2,opt,name=burst"`
+}
+
+// Limit returns the number of requests per second and the burst of the rate limit
+func (l *ApplyRateLimit) Limit() (float64, int, error) {
+	requestsPerSecond, err := strconv.ParseFloat(l.RequestsPerSecond, 64)
+	if err != nil || requestsPerSecond < MinApplyRequestsPerSecond || math.IsInf(requestsPerSecond, 0) || math.IsNaN(requestsPerSecond) {
+		return 0, 0, fmt.Errorf("invalid requests per second %q: must be a number of at least %v", l.RequestsPerSecond, MinApplyRequestsPerSecond)
+	}
+	if l.Burst < 0 {
+		return 0, 0, fmt.Errorf("invalid burst %d: must not be negative", l.Burst)
+	}
+	burst := int(l.Burst)
+	if burst == 0 {
+		burst = int(math.Ceil(requestsPerSecond))
+	}
+	return requestsPerSecond, burst, nil
+}
+
+const (
+	// ServerSideApplyConflictResolutionFail fails the sync when server-side applied resources have field manager conflicts
+	ServerSideApplyConflictResolutionFail = "fail"
+	// ServerSideApplyConflictResolutionForce forces field manager conflicts of server-side applied resources
+	ServerSideApplyConflictResolutionForce = "force"
+)
+
+const (
+	// ConflictResolutionStrict fails the sync when server-side applied resources have field manager conflicts
+	ConflictResolutionStrict = "strict"
+	// ConflictResolutionForce forces the field manager conflicts of server-side applied resources
+	ConflictResolutionForce = "force"
+	// ConflictResolutionMerge forces the conflicting fields of server-side applied resources which are also owned by
+	// Argo CD, and leaves the other conflicting fields to their field manager
+	ConflictResolutionMerge = "merge"
+)
+
 // IsZero returns true if the sync policy is empty
 func (p *SyncPolicy) IsZero() bool {
-	return p == nil || (p.Automated == nil && len(p.SyncOptions) == 0 && p.Retry == nil && p.ManagedNamespaceMetadata == nil)
+	return p == nil || (p.Automated == nil && len(p.SyncOptions) == 0 && p.Retry == nil && p.ManagedNamespaceMetadata == nil && p.ServerSideApplyConflictResolution == "" && p.ApplyRateLimit == nil && p.ConflictResolution == "" && p.SyncTimeout == "" && p.PreFlightCheck == nil && p.AutoRollback == nil && len(p.ApplyTimeouts) == 0 && len(p.DeletionOrder) == 0 && len(p.RetryOn) == 0)
+}
+
+// GetConflictResolution returns the resolution of field manager conflicts of server-side applied resources, or an empty
+// string if conflicts are forced without being reported. ServerSideApplyConflictResolution is used if
+// ConflictResolution is not set.
+func (p *SyncPolicy) GetConflictResolution() string {
+	if p == nil {
+		return ""
+	}
+	if p.ConflictResolution != "" {
+		return p.ConflictResolution
+	}
+	switch p.ServerSideApplyConflictResolution {
+	case ServerSideApplyConflictResolutionFail:
+		return ConflictResolutionStrict
+	case ServerSideApplyConflictResolutionForce:
+		return ConflictResolutionForce
+	}
+	return ""
+}
+
+// GetApplyRateLimit returns the rate limit of the resources applied by syncs, or nil if the default rate limit applies
+func (p *SyncPolicy) GetApplyRateLimit() *ApplyRateLimit {
+	if p == nil {
+		return nil
+	}
+	return p.ApplyRateLimit
+}
+
+// GetPreFlightCheck returns the capacity the destination cluster must have available before syncing, or nil if the
+// capacity is not checked
+func (p *SyncPolicy) GetPreFlightCheck() *PreFlightCheck {
+	if p == nil {
+		return nil
+	}
+	return p.PreFlightCheck
+}
+
+// GetSyncTimeout returns the duration after which syncs fail if they did not complete, or zero if the global sync timeout
+// applies
+func (p *SyncPolicy) GetSyncTimeout() (time.Duration, error) {
+	if p == nil || p.SyncTimeout == "" {
+		return 0, nil
+	}
+	timeout, err := parseStringToDuration(p.SyncTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid sync timeout: %w", err)
+	}
+	if timeout < 0 {
+		return 0, fmt.Errorf("invalid sync timeout %q: must not be negative", p.SyncTimeout)
+	}
+	return timeout, nil
+}
+
+// GetApplyTimeout returns the duration after which the apply of resources of the given kind fails, or zero if the
+// default resource apply timeout applies
+func (p *SyncPolicy) GetApplyTimeout(gk schema.GroupKind) time.Duration {
+	if p == nil {
+		return 0
+	}
+	if timeout, ok := p.ApplyTimeouts[gk.Group+"/"+gk.Kind]; ok {
+		return timeout.Duration
+	}
+	if gk.Group == "" {
+		return p.ApplyTimeouts[gk.Kind].Duration
+	}
+	return 0
+}
+
+// GetDeletionOrderIndex returns the position of the given kind in the deletion order of the application, or the length
+// of the deletion order if the kind is not listed, since the resources of unlisted kinds are deleted last
+func (p *SyncPolicy) GetDeletionOrderIndex(gk schema.GroupKind) int {
+	if p == nil {
+		return 0
+	}
+	for i, entry := range p.DeletionOrder {
+		if entry == gk.Group+"/"+gk.Kind || gk.Group == "" && entry == gk.Kind {
+			return i
+		}
+	}
+	return len(p.DeletionOrder)
 }
 
 // RetryStrategy contains information about the strategy to apply when a sync failed
@@ -1315,11 +1899,38 @@ type Backoff struct {
 	// Duration is the amount to back off. Default unit is seconds, but could also be a duration (e.g. "2m", "1h")
 	Duration string `json:"duration,omitempty" protobuf:"bytes,1,opt,name=duration"`
 	// Factor is a factor to multiply the base duration after each failed retry
+	// +kubebuilder:validation:Minimum=1
 	Factor *int64 `json:"factor,omitempty" protobuf:"bytes,2,name=factor"`
 	// MaxDuration is the maximum amount of time allowed for the backoff strategy
 	MaxDuration string `json:"maxDuration,omitempty" protobuf:"bytes,3,opt,name=maxDuration"`
 }
 
+// SyncRetryPattern retries the failed syncs whose error matches a pattern
+type SyncRetryPattern struct {
+	// ErrorPattern is the regular expression matched against the messages of the resources whose sync failed, or against the message of the operation if it failed before syncing any resource, e.g. "(?i)too many requests|the object has been modified"
+	ErrorPattern string `json:"errorPattern" protobuf:"bytes,1,opt,name=errorPattern"`
+	// MaxRetries is the maximum number of retries of a sync failing with a matching error, defaults to 1
+	MaxRetries int64 `json:"maxRetries,omitempty" protobuf:"bytes,2,opt,name=maxRetries"`
+	// Backoff is the duration to wait before each retry, e.g. "5s". The sync is retried immediately if not set
+	Backoff *metav1.Duration `json:"backoff,omitempty" protobuf:"bytes,3,opt,name=backoff"`
+}
+
+// GetMaxRetries returns the maximum number of retries of a sync failing with a matching error
+func (p *SyncRetryPattern) GetMaxRetries() int64 {
+	if p.MaxRetries <= 0 {
+		return 1
+	}
+	return p.MaxRetries
+}
+
+// GetBackoff returns the duration to wait before each retry
+func (p *SyncRetryPattern) GetBackoff() time.Duration {
+	if p.Backoff == nil {
+		return 0
+	}
+	return p.Backoff.Duration
+}
+
 // SyncPolicyAutomated controls the behavior of an automated sync
 type SyncPolicyAutomated struct {
 	// Prune specifies whether to delete resources from the cluster that are not found in the sources anymore as part of automated sync (default: false)
@@ -1407,6 +2018,24 @@ type SyncOperationResult struct {
 	Revisions []string `json:"revisions,omitempty" protobuf:"bytes,5,opt,name=revisions"`
 	// ManagedNamespaceMetadata contains the current sync state of managed namespace metadata
 	ManagedNamespaceMetadata *ManagedNamespaceMetadata `json:"managedNamespaceMetadata,omitempty" protobuf:"bytes,6,opt,name=managedNamespaceMetadata"`
+	// SyncID identifies the snapshot of the live state of the resources taken before the sync applied them
+	SyncID string `json:"syncId,omitempty" protobuf:"bytes,7,opt,name=syncId"`
+	// Destinations holds the result of the sync of each synced destination of an application with additional
+	// destinations, in which case the results of the resources are recorded per destination rather than in resources
+	Destinations []DestinationSyncResult `json:"destinations,omitempty" protobuf:"bytes,8,opt,name=destinations"`
+}
+
+// DestinationSyncResult is the result of the sync of one of the destinations of an application with additional
+// destinations
+type DestinationSyncResult struct {
+	// Destination is the synced destination
+	Destination ApplicationDestination `json:"destination" protobuf:"bytes,1,opt,name=destination"`
+	// Phase is the phase of the sync of the destination
+	Phase synccommon.OperationPhase `json:"phase" protobuf:"bytes,2,opt,name=phase"`
+	// Message holds any pertinent messages about the sync of the destination
+	Message string `json:"message,omitempty" protobuf:"bytes,3,opt,name=message"`
+	// Resources contains the sync result of each resource synced to the destination
+	Resources ResourceResults `json:"resources,omitempty" protobuf:"bytes,4,opt,name=resources"`
 }
 
 // ResourceResult holds the operation result details of a specific resource
@@ -1432,6 +2061,8 @@ type ResourceResult struct {
 	HookPhase synccommon.OperationPhase `json:"hookPhase,omitempty" protobuf:"bytes,9,opt,name=hookPhase"`
 	// SyncPhase indicates the particular phase of the sync that this result was acquired in
 	SyncPhase synccommon.SyncPhase `json:"syncPhase,omitempty" protobuf:"bytes,10,opt,name=syncPhase"`
+	// ErrorCode is the machine readable reason of the failure of the sync of the resource, empty unless it failed
+	ErrorCode SyncErrorCode `json:"errorCode,omitempty" protobuf:"bytes,11,opt,name=errorCode,casttype=SyncErrorCode"`
 }
 
 // GroupVersionKind returns the GVK schema information for a given resource within a sync result
@@ -1551,6 +2182,14 @@ const (
 	ApplicationConditionExcludedResourceWarning = "ExcludedResourceWarning"
 	// ApplicationConditionOrphanedResourceWarning indicates that application has orphaned resources
 	ApplicationConditionOrphanedResourceWarning = "OrphanedResourceWarning"
+	// ApplicationConditionProjectQuotaExceeded indicates that syncing the application would exceed the resource quota of its project
+	ApplicationConditionProjectQuotaExceeded = "ProjectQuotaExceeded"
+	// ApplicationConditionSkippedReconcile indicates that the reconciliation of the application is skipped based on its annotations
+	ApplicationConditionSkippedReconcile = "SkippedReconcile"
+	// ApplicationConditionSyncWarning indicates that a warning was reported while generating the manifests of the application, e.g. a schema validation warning
+	ApplicationConditionSyncWarning = "SyncWarning"
+	// ApplicationConditionWebhookConfirmationTimeout indicates that the post-sync webhook did not confirm the last sync operation before the confirmation timeout
+	ApplicationConditionWebhookConfirmationTimeout = "WebhookConfirmationTimeout"
 )
 
 // ApplicationCondition contains details about an application condition, which is usually an error or warning
@@ -1587,6 +2226,17 @@ type SyncStatus struct {
 	Revisions []string `json:"revisions,omitempty" protobuf:"bytes,4,opt,name=revisions"`
 }
 
+// DestinationSyncStatus is the sync and health status of one of the destinations of an application with additional
+// destinations
+type DestinationSyncStatus struct {
+	// Destination is the compared destination
+	Destination ApplicationDestination `json:"destination" protobuf:"bytes,1,opt,name=destination"`
+	// Status is the sync state of the destination
+	Status SyncStatusCode `json:"status" protobuf:"bytes,2,opt,name=status,casttype=SyncStatusCode"`
+	// Health is the health of the resources of the application in the destination
+	Health HealthStatus `json:"health,omitempty" protobuf:"bytes,3,opt,name=health"`
+}
+
 // HealthStatus contains information about the currently observed health state of an application or resource
 type HealthStatus struct {
 	// Status holds the status code of the application or resource
@@ -2291,6 +2941,138 @@ type AppProjectSpec struct {
 	SourceNamespaces []string `json:"sourceNamespaces,omitempty" protobuf:"bytes,12,opt,name=sourceNamespaces"`
 	// PermitOnlyProjectScopedClusters determines whether destinations can only reference clusters which are project-scoped
 	PermitOnlyProjectScopedClusters bool `json:"permitOnlyProjectScopedClusters,omitempty" protobuf:"bytes,13,opt,name=permitOnlyProjectScopedClusters"`
+	// PostSyncWebhook configures a webhook which is called after each sync operation of the applications in this project
+	PostSyncWebhook *PostSyncWebhook `json:"postSyncWebhook,omitempty" protobuf:"bytes,14,opt,name=postSyncWebhook"`
+	// NamespaceResourceQuota limits the number of resources of each kind which the applications in this project may manage in total.
+	// Keys are group kinds formatted as `<kind>.<group>`, or `<kind>` for the core group, e.g. `Deployment.apps` or `ConfigMap`.
+	NamespaceResourceQuota map[string]int64 `json:"namespaceResourceQuota,omitempty" protobuf:"bytes,15,opt,name=namespaceResourceQuota"`
+	// NamespaceIsolation controls whether the applications in this project may create resources outside their destination namespace.
+	// If set to `strict`, manifests containing namespaced resources of other namespaces, or cluster-scoped resources which are not
+	// explicitly listed in the cluster resource whitelist, are rejected during manifest generation.
+	// +kubebuilder:validation:Enum=strict
+	NamespaceIsolation string `json:"namespaceIsolation,omitempty" protobuf:"bytes,16,opt,name=namespaceIsolation"`
+	// Allowlist restricts the contents of the container images deployed by the applications in this project
+	Allowlist *ProjectAllowlist `json:"allowlist,omitempty" protobuf:"bytes,17,opt,name=allowlist"`
+	// ArtifactStorage configures where the manifests applied by the syncs of the applications in this project are stored
+	ArtifactStorage *ArtifactStorage `json:"artifactStorage,omitempty" protobuf:"bytes,18,opt,name=artifactStorage"`
+	// DestinationServiceAccount is the service account impersonated by the application controller to sync the applications in this project.
+	// It is formatted as `<namespace>:<name>`, or `<name>` for a service account of the destination namespace of the application, and must
+	// exist in the destination cluster.
+	DestinationServiceAccount string `json:"destinationServiceAccount,omitempty" protobuf:"bytes,19,opt,name=destinationServiceAccount"`
+	// Datadog sends the results of the syncs of the applications in this project to Datadog as deployment events
+	Datadog *DatadogIntegration `json:"datadog,omitempty" protobuf:"bytes,20,opt,name=datadog"`
+	// HistoryRetentionPolicy limits the sync history kept by the applications in this project
+	HistoryRetentionPolicy *HistoryRetentionPolicy `json:"historyRetentionPolicy,omitempty" protobuf:"bytes,21,opt,name=historyRetentionPolicy"`
+	// SyncPolicy controls the checks performed before syncing the applications in this project
+	SyncPolicy *ProjectSyncPolicy `json:"syncPolicy,omitempty" protobuf:"bytes,22,opt,name=syncPolicy"`
+}
+
+// ProjectSyncPolicy controls the checks performed before syncing the applications of a project
+type ProjectSyncPolicy struct {
+	// ClusterVersionCheck controls whether the resources of the applications which use API versions deprecated or not
+	// served by the destination cluster are reported as SyncWarning conditions. If set to `pre-sync`, the API versions
+	// are checked against the preferred versions of the destination cluster while the applications are out of sync.
+	// +kubebuilder:validation:Enum=pre-sync
+	ClusterVersionCheck string `json:"clusterVersionCheck,omitempty" protobuf:"bytes,1,opt,name=clusterVersionCheck"`
+}
+
+// HistoryRetentionPolicy limits the number and the age of the entries of the sync history of applications. Entries are
+// removed if they exceed either constraint, so the more restrictive one applies. The latest entry is always kept.
+type HistoryRetentionPolicy struct {
+	// MaxEntries is the maximum number of history entries kept, zero does not limit the number of entries
+	MaxEntries int64 `json:"maxEntries,omitempty" protobuf:"varint,1,opt,name=maxEntries"`
+	// MaxAge is the maximum age of the history entries kept, based on the time their sync completed. Zero does not
+	// limit the age of entries.
+	MaxAge metav1.Duration `json:"maxAge,omitempty" protobuf:"bytes,2,opt,name=maxAge"`
+}
+
+// IsZero returns true if the policy does not limit the history
+func (p *HistoryRetentionPolicy) IsZero() bool {
+	return p == nil || (p.MaxEntries <= 0 && p.MaxAge.Duration <= 0)
+}
+
+// DatadogIntegration sends the results of syncs to Datadog as events, using the Datadog Events API v2
+type DatadogIntegration struct {
+	// APIKeySecret references the key of a secret in the namespace of the application controller holding the Datadog
+	// API key
+	APIKeySecret SecretRef `json:"apiKeySecret" protobuf:"bytes,1,opt,name=apiKeySecret"`
+	// Site is the Datadog site the events are sent to, e.g. datadoghq.com or datadoghq.eu. Defaults to datadoghq.com.
+	Site string `json:"site,omitempty" protobuf:"bytes,2,opt,name=site"`
+	// ServiceTag is the value of the service tag of the events. Defaults to the name of the application.
+	ServiceTag string `json:"serviceTag,omitempty" protobuf:"bytes,3,opt,name=serviceTag"`
+}
+
+// GetDatadog returns the Datadog integration of the project, or nil if sync results are not sent to Datadog
+func (s *AppProjectSpec) GetDatadog() *DatadogIntegration {
+	if s.Datadog == nil || s.Datadog.APIKeySecret.SecretName == "" || s.Datadog.APIKeySecret.Key == "" {
+		return nil
+	}
+	return s.Datadog
+}
+
+// ProjectAllowlist restricts the contents of the container images deployed by the applications in a project
+type ProjectAllowlist struct {
+	// LicenseIDs are the SPDX identifiers of the licenses permitted in the SBOMs of the container images, when SBOM
+	// attestations are verified. All licenses are permitted if empty.
+	LicenseIDs []string `json:"licenseIds,omitempty" protobuf:"bytes,1,rep,name=licenseIds"`
+}
+
+// GetLicenseIDs returns the licenses permitted in the SBOMs of the container images, or nil if all are permitted
+func (a *ProjectAllowlist) GetLicenseIDs() []string {
+	if a == nil {
+		return nil
+	}
+	return a.LicenseIDs
+}
+
+// ArtifactStorage configures where the manifests applied by successful syncs are stored
+type ArtifactStorage struct {
+	// OCI stores the applied manifests as artifacts in an OCI registry
+	OCI *OCIArtifactStorage `json:"oci,omitempty" protobuf:"bytes,1,opt,name=oci"`
+}
+
+// OCIArtifactStorage stores the manifests applied by successful syncs as artifacts in an OCI registry
+type OCIArtifactStorage struct {
+	// Registry is the host name of the OCI registry, e.g. ghcr.io
+	Registry string `json:"registry" protobuf:"bytes,1,opt,name=registry"`
+	// Repo is the repository in the registry the artifacts are pushed to, e.g. my-org/argocd-sync-results
+	Repo string `json:"repo" protobuf:"bytes,2,opt,name=repo"`
+	// CredentialSecret is the name of a secret in the namespace of the application controller, holding the username
+	// and password used to authenticate against the registry. Anonymous access is used if empty.
+	CredentialSecret string `json:"credentialSecret,omitempty" protobuf:"bytes,3,opt,name=credentialSecret"`
+}
+
+// GetOCI returns the OCI artifact storage, or nil if the manifests are not stored in an OCI registry
+func (s *ArtifactStorage) GetOCI() *OCIArtifactStorage {
+	if s == nil || s.OCI == nil || s.OCI.Registry == "" || s.OCI.Repo == "" {
+		return nil
+	}
+	return s.OCI
+}
+
+// NamespaceIsolationStrict restricts the resources of the applications in a project to their destination namespace
+const NamespaceIsolationStrict = "strict"
+
+// ClusterVersionCheckPreSync checks the API versions of the resources of the applications in a project against the
+// destination cluster before they are synced
+const ClusterVersionCheckPreSync = "pre-sync"
+
+// PostSyncWebhook is a webhook which is called when a sync operation of an application completes
+type PostSyncWebhook struct {
+	// URL is the URL the webhook request is sent to
+	URL string `json:"url" protobuf:"bytes,1,opt,name=url"`
+	// Method is the HTTP method of the webhook request. Defaults to POST.
+	Method string `json:"method,omitempty" protobuf:"bytes,2,opt,name=method"`
+	// Headers are additional HTTP headers sent with the webhook request. Values starting with $ reference a key of
+	// the argocd-secret Secret, such as $webhook.token. Credential headers such as Authorization must reference a key.
+	Headers map[string]string `json:"headers,omitempty" protobuf:"bytes,3,opt,name=headers"`
+	// BodyTemplate is a Go template for the body of the webhook request. It is executed with the appName, status,
+	// revision, message and timestamp of the sync operation. Defaults to a JSON object containing these values.
+	BodyTemplate string `json:"bodyTemplate,omitempty" protobuf:"bytes,4,opt,name=bodyTemplate"`
+	// RequireWebhookConfirmation keeps succeeded sync operations running until the webhook responds with HTTP 200.
+	// Once the webhook confirmation timeout of the application controller expires, the sync operation succeeds with
+	// a WebhookConfirmationTimeout warning condition.
+	RequireWebhookConfirmation bool `json:"requireWebhookConfirmation,omitempty" protobuf:"varint,5,opt,name=requireWebhookConfirmation"`
 }
 
 // SyncWindows is a collection of sync windows in this project
@@ -2314,6 +3096,13 @@ type SyncWindow struct {
 	ManualSync bool `json:"manualSync,omitempty" protobuf:"bytes,7,opt,name=manualSync"`
 	// TimeZone of the sync that will be applied to the schedule
 	TimeZone string `json:"timeZone,omitempty" protobuf:"bytes,8,opt,name=timeZone"`
+	// ChronoSchedule is an alternative to Schedule and Duration, specifying the window with an extended expression such
+	// as `every weekday from 09:00 to 17:00 in America/New_York except 12-25`. TimeZone is used if the expression has
+	// no time zone.
+	ChronoSchedule string `json:"chronoSchedule,omitempty" protobuf:"bytes,9,opt,name=chronoSchedule"`
+	// AllowOverride allows users with the permission to override syncs to sync manually when the window would
+	// otherwise block the sync, e.g. to deploy an emergency fix
+	AllowOverride bool `json:"allowOverride,omitempty" protobuf:"bytes,10,opt,name=allowOverride"`
 }
 
 // HasWindows returns true if SyncWindows has one or more SyncWindow
@@ -2335,6 +3124,12 @@ func (s *SyncWindows) active(currentTime time.Time) *SyncWindows {
 		var active SyncWindows
 		specParser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
 		for _, w := range *s {
+			if w.ChronoSchedule != "" {
+				if chronoActive, _ := w.chronoActive(currentTime); chronoActive {
+					active = append(active, w)
+				}
+				continue
+			}
 			schedule, _ := specParser.Parse(w.Schedule)
 			duration, _ := time.ParseDuration(w.Duration)
 
@@ -2368,7 +3163,11 @@ func (s *SyncWindows) inactiveAllows(currentTime time.Time) *SyncWindows {
 		var inactive SyncWindows
 		specParser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
 		for _, w := range *s {
-			if w.Kind == "allow" {
+			if w.Kind == "allow" && w.ChronoSchedule != "" {
+				if chronoActive, err := w.chronoActive(currentTime); err == nil && !chronoActive {
+					inactive = append(inactive, w)
+				}
+			} else if w.Kind == "allow" {
 				schedule, sErr := specParser.Parse(w.Schedule)
 				duration, dErr := time.ParseDuration(w.Duration)
 				// Offset the nextWindow time to consider the timeZone of the sync window
@@ -2387,6 +3186,15 @@ func (s *SyncWindows) inactiveAllows(currentTime time.Time) *SyncWindows {
 	return nil
 }
 
+// chronoActive returns whether the window specified by the chrono schedule is open at the given time
+func (w *SyncWindow) chronoActive(currentTime time.Time) (bool, error) {
+	schedule, err := apputil.ParseChronoSchedule(w.ChronoSchedule, w.TimeZone)
+	if err != nil {
+		return false, err
+	}
+	return schedule.Active(currentTime), nil
+}
+
 func (w *SyncWindow) scheduleOffsetByTimeZone() time.Duration {
 	loc, err := time.LoadLocation(w.TimeZone)
 	if err != nil {
@@ -2520,6 +3328,34 @@ func (w *SyncWindows) CanSync(isManual bool) bool {
 	return true
 }
 
+// CanOverride returns true if the sync windows currently blocking a manual sync allow to override them. A sync can
+// only be overridden if all of the windows blocking it allow it.
+func (w *SyncWindows) CanOverride() bool {
+	if w.CanSync(true) {
+		return true
+	}
+
+	active := w.Active()
+	if hasActiveDeny, _ := active.hasDeny(); hasActiveDeny {
+		return active.overrideAllowed("deny")
+	}
+
+	return w.InactiveAllows().overrideAllowed("allow")
+}
+
+// overrideAllowed returns true if all of the SyncWindows of the given kind have AllowOverride set to true
+func (w *SyncWindows) overrideAllowed(kind string) bool {
+	if !w.HasWindows() {
+		return false
+	}
+	for _, s := range *w {
+		if s.Kind == kind && !s.AllowOverride {
+			return false
+		}
+	}
+	return true
+}
+
 // hasDeny will iterate over the SyncWindows and return if a deny window is found and if
 // manual sync is enabled. It returns true in the first return boolean value if it finds
 // any deny window. Will return true in the second return boolean value if all deny windows
@@ -2581,6 +3417,11 @@ func (w SyncWindow) active(currentTime time.Time) bool {
 	// first converted to UTC before search
 	currentTime = currentTime.UTC()
 
+	if w.ChronoSchedule != "" {
+		active, _ := w.chronoActive(currentTime)
+		return active
+	}
+
 	specParser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
 	schedule, _ := specParser.Parse(w.Schedule)
 	duration, _ := time.ParseDuration(w.Duration)
@@ -2636,6 +3477,15 @@ func (w *SyncWindow) Validate() error {
 	if w.Kind != "allow" && w.Kind != "deny" {
 		return fmt.Errorf("kind '%s' mismatch: can only be allow or deny", w.Kind)
 	}
+	if w.ChronoSchedule != "" {
+		if w.Schedule != "" || w.Duration != "" {
+			return fmt.Errorf("chronoSchedule cannot be combined with schedule and duration")
+		}
+		if _, err := apputil.ParseChronoSchedule(w.ChronoSchedule, w.TimeZone); err != nil {
+			return fmt.Errorf("cannot parse chronoSchedule '%s': %w", w.ChronoSchedule, err)
+		}
+		return nil
+	}
 	specParser := cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
 	_, err := specParser.Parse(w.Schedule)
 	if err != nil {
@@ -2747,6 +3597,18 @@ func (app *Application) UnSetPostDeleteFinalizer(stage ...string) {
 	setFinalizer(&app.ObjectMeta, strings.Join(append([]string{PostDeleteFinalizerName}, stage...), "/"), false)
 }
 
+func (app *Application) HasPreDeleteFinalizer() bool {
+	return getFinalizerIndex(app.ObjectMeta, PreDeleteFinalizerName) > -1
+}
+
+func (app *Application) SetPreDeleteFinalizer() {
+	setFinalizer(&app.ObjectMeta, PreDeleteFinalizerName, true)
+}
+
+func (app *Application) UnSetPreDeleteFinalizer() {
+	setFinalizer(&app.ObjectMeta, PreDeleteFinalizerName, false)
+}
+
 // SetCascadedDeletion will enable cascaded deletion by setting the propagation policy finalizer
 func (app *Application) SetCascadedDeletion(finalizer string) {
 	setFinalizer(&app.ObjectMeta, finalizer, true)
@@ -2901,6 +3763,30 @@ func (source *ApplicationSource) ExplicitType() (*ApplicationSourceType, error)
 	if source.Plugin != nil {
 		appTypes = append(appTypes, ApplicationSourceTypePlugin)
 	}
+	if source.Inline != nil {
+		appTypes = append(appTypes, ApplicationSourceTypeInline)
+	}
+	if source.Ytt != nil {
+		appTypes = append(appTypes, ApplicationSourceTypeYtt)
+	}
+	if source.Starlark != nil {
+		appTypes = append(appTypes, ApplicationSourceTypeStarlark)
+	}
+	if source.Cue != nil {
+		appTypes = append(appTypes, ApplicationSourceTypeCue)
+	}
+	if source.HTTP != nil {
+		appTypes = append(appTypes, ApplicationSourceTypeHTTP)
+	}
+	if source.Pulumi != nil {
+		appTypes = append(appTypes, ApplicationSourceTypePulumi)
+	}
+	if source.OCI != nil {
+		appTypes = append(appTypes, ApplicationSourceTypeOCI)
+	}
+	if source.Timoni != nil {
+		appTypes = append(appTypes, ApplicationSourceTypeTimoni)
+	}
 	if len(appTypes) == 0 {
 		return nil, nil
 	}
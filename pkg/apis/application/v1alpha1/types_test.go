@@ -585,6 +585,77 @@ func TestAppProject_ValidateSources(t *testing.T) {
 	require.Error(t, err)
 }
 
+// TestAppProject_ValidatePostSyncWebhook tests for an invalid post-sync webhook
+func TestAppProject_ValidatePostSyncWebhook(t *testing.T) {
+	p := newTestProject()
+	p.Spec.PostSyncWebhook = &PostSyncWebhook{
+		URL:     "https://hooks.example.com/argocd",
+		Headers: map[string]string{"Authorization": "$webhook.token", "X-Source": "argocd"},
+	}
+	require.NoError(t, p.ValidateProject())
+
+	for _, badURL := range []string{"", "hooks.example.com/argocd", "file:///etc/passwd", "https://"} {
+		p.Spec.PostSyncWebhook.URL = badURL
+		require.ErrorContains(t, p.ValidateProject(), "must be an absolute http or https URL")
+	}
+
+	p.Spec.PostSyncWebhook.URL = "https://hooks.example.com/argocd"
+	p.Spec.PostSyncWebhook.Headers = map[string]string{"authorization": "Bearer token"}
+	require.ErrorContains(t, p.ValidateProject(), "must reference a key of argocd-secret")
+}
+
+func TestAppProject_DestinationServiceAccount(t *testing.T) {
+	p := newTestProject()
+	namespace, name := p.GetDestinationServiceAccount("guestbook")
+	assert.Empty(t, namespace)
+	assert.Empty(t, name)
+
+	p.Spec.DestinationServiceAccount = "deployer"
+	require.NoError(t, p.ValidateProject())
+	namespace, name = p.GetDestinationServiceAccount("guestbook")
+	assert.Equal(t, "guestbook", namespace)
+	assert.Equal(t, "deployer", name)
+
+	p.Spec.DestinationServiceAccount = "argocd-deployers:deployer"
+	require.NoError(t, p.ValidateProject())
+	namespace, name = p.GetDestinationServiceAccount("guestbook")
+	assert.Equal(t, "argocd-deployers", namespace)
+	assert.Equal(t, "deployer", name)
+
+	for _, invalid := range []string{"Deployer", "ns:", ":deployer", "a:b:c", "system:serviceaccount:ns:deployer"} {
+		p.Spec.DestinationServiceAccount = invalid
+		require.ErrorContains(t, p.ValidateProject(), "must be formatted as", invalid)
+	}
+}
+
+func TestAppProject_ValidateHistoryRetentionPolicy(t *testing.T) {
+	p := newTestProject()
+	assert.True(t, p.Spec.HistoryRetentionPolicy.IsZero())
+	p.Spec.HistoryRetentionPolicy = &HistoryRetentionPolicy{MaxEntries: 5, MaxAge: metav1.Duration{Duration: 24 * time.Hour}}
+	require.NoError(t, p.ValidateProject())
+	assert.False(t, p.Spec.HistoryRetentionPolicy.IsZero())
+
+	p.Spec.HistoryRetentionPolicy.MaxEntries = -1
+	require.ErrorContains(t, p.ValidateProject(), "must not have a negative maxEntries or maxAge")
+	p.Spec.HistoryRetentionPolicy = &HistoryRetentionPolicy{MaxAge: metav1.Duration{Duration: -time.Hour}}
+	require.ErrorContains(t, p.ValidateProject(), "must not have a negative maxEntries or maxAge")
+}
+
+func TestAppProject_ValidateSyncPolicy(t *testing.T) {
+	p := newTestProject()
+	assert.False(t, p.IsClusterVersionCheckPreSync())
+	p.Spec.SyncPolicy = &ProjectSyncPolicy{}
+	require.NoError(t, p.ValidateProject())
+	assert.False(t, p.IsClusterVersionCheckPreSync())
+
+	p.Spec.SyncPolicy.ClusterVersionCheck = ClusterVersionCheckPreSync
+	require.NoError(t, p.ValidateProject())
+	assert.True(t, p.IsClusterVersionCheckPreSync())
+
+	p.Spec.SyncPolicy.ClusterVersionCheck = "post-sync"
+	require.ErrorContains(t, p.ValidateProject(), "cluster version check 'post-sync' is not supported")
+}
+
 // TestAppProject_ValidateDestinations tests for an invalid destination
 func TestAppProject_ValidateDestinations(t *testing.T) {
 	p := newTestProject()
@@ -871,6 +942,88 @@ func TestExplicitTypeWithDirectory(t *testing.T) {
 	require.Error(t, err, "cannot add directory with any other types")
 }
 
+func TestExplicitTypeWithYtt(t *testing.T) {
+	src := ApplicationSource{
+		Ytt: &ApplicationSourceYtt{},
+	}
+	explicitType, err := src.ExplicitType()
+	require.NoError(t, err)
+	assert.Equal(t, ApplicationSourceTypeYtt, *explicitType)
+
+	src.Kustomize = &ApplicationSourceKustomize{}
+	_, err = src.ExplicitType()
+	require.Error(t, err, "cannot add ytt with any other types")
+}
+
+func TestExplicitTypeWithStarlark(t *testing.T) {
+	src := ApplicationSource{
+		Starlark: &ApplicationSourceStarlark{Entrypoint: "main.star"},
+	}
+	explicitType, err := src.ExplicitType()
+	require.NoError(t, err)
+	assert.Equal(t, ApplicationSourceTypeStarlark, *explicitType)
+
+	src.Ytt = &ApplicationSourceYtt{}
+	_, err = src.ExplicitType()
+	require.Error(t, err, "cannot add starlark with any other types")
+}
+
+func TestExplicitTypeWithCue(t *testing.T) {
+	src := ApplicationSource{
+		Cue: &ApplicationSourceCue{Entrypoint: "main.cue"},
+	}
+	explicitType, err := src.ExplicitType()
+	require.NoError(t, err)
+	assert.Equal(t, ApplicationSourceTypeCue, *explicitType)
+
+	src.Starlark = &ApplicationSourceStarlark{}
+	_, err = src.ExplicitType()
+	require.Error(t, err, "cannot add cue with any other types")
+}
+
+func TestExplicitTypeWithPulumi(t *testing.T) {
+	src := ApplicationSource{
+		Pulumi: &ApplicationSourcePulumi{Runtime: "go"},
+	}
+	explicitType, err := src.ExplicitType()
+	require.NoError(t, err)
+	assert.Equal(t, ApplicationSourceTypePulumi, *explicitType)
+	assert.False(t, src.IsZero())
+
+	src.Cue = &ApplicationSourceCue{}
+	_, err = src.ExplicitType()
+	require.Error(t, err, "cannot add pulumi with any other types")
+}
+
+func TestExplicitTypeWithOCI(t *testing.T) {
+	src := ApplicationSource{
+		OCI: &ApplicationSourceOCI{Image: "ghcr.io/argoproj/manifests"},
+	}
+	explicitType, err := src.ExplicitType()
+	require.NoError(t, err)
+	assert.Equal(t, ApplicationSourceTypeOCI, *explicitType)
+	assert.True(t, src.IsOCI())
+	assert.False(t, src.IsZero())
+
+	src.Kustomize = &ApplicationSourceKustomize{}
+	_, err = src.ExplicitType()
+	require.Error(t, err, "cannot add oci with any other types")
+}
+
+func TestExplicitTypeWithTimoni(t *testing.T) {
+	src := ApplicationSource{
+		Timoni: &ApplicationSourceTimoni{Module: "oci://ghcr.io/stefanprodan/modules/podinfo"},
+	}
+	explicitType, err := src.ExplicitType()
+	require.NoError(t, err)
+	assert.Equal(t, ApplicationSourceTypeTimoni, *explicitType)
+	assert.False(t, src.IsZero())
+
+	src.Cue = &ApplicationSourceCue{}
+	_, err = src.ExplicitType()
+	require.Error(t, err, "cannot add timoni with any other types")
+}
+
 func TestAppSourceEquality(t *testing.T) {
 	left := &ApplicationSource{
 		Directory: &ApplicationSourceDirectory{
@@ -1353,6 +1506,29 @@ func TestSyncOperation_IsApplyStrategy(t *testing.T) {
 	}
 }
 
+func TestSyncOperation_SyncsDestination(t *testing.T) {
+	dest := ApplicationDestination{Server: "https://cluster-1", Name: "cluster-1", Namespace: "default"}
+	tests := []struct {
+		name         string
+		destinations []ApplicationDestination
+		want         bool
+	}{
+		{"TestNoneSelected", nil, true},
+		{"TestServer", []ApplicationDestination{{Server: "https://cluster-1"}}, true},
+		{"TestName", []ApplicationDestination{{Name: "cluster-1"}}, true},
+		{"TestServerAndNamespace", []ApplicationDestination{{Server: "https://cluster-1", Namespace: "default"}}, true},
+		{"TestOtherNamespace", []ApplicationDestination{{Server: "https://cluster-1", Namespace: "other"}}, false},
+		{"TestOtherServer", []ApplicationDestination{{Server: "https://cluster-2"}}, false},
+		{"TestAnySelected", []ApplicationDestination{{Server: "https://cluster-2"}, {Name: "cluster-1"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &SyncOperation{Destinations: tt.destinations}
+			assert.Equal(t, tt.want, o.SyncsDestination(dest))
+		})
+	}
+}
+
 func TestResourceResults_Find(t *testing.T) {
 	type args struct {
 		group     string
@@ -2518,6 +2694,49 @@ func TestSyncWindows_CanSync(t *testing.T) {
 	})
 }
 
+func TestSyncWindows_CanOverride(t *testing.T) {
+	t.Run("will allow override if no window blocks the sync", func(t *testing.T) {
+		t.Parallel()
+		proj := newProjectBuilder().withActiveDenyWindow(true).build()
+		assert.True(t, proj.Spec.SyncWindows.CanOverride())
+	})
+	t.Run("will allow override if the active-deny-window allows it", func(t *testing.T) {
+		t.Parallel()
+		proj := newProjectBuilder().withActiveDenyWindow(false).withAllowOverride().build()
+		assert.True(t, proj.Spec.SyncWindows.CanOverride())
+	})
+	t.Run("will deny override if the active-deny-window does not allow it", func(t *testing.T) {
+		t.Parallel()
+		proj := newProjectBuilder().withActiveDenyWindow(false).build()
+		assert.False(t, proj.Spec.SyncWindows.CanOverride())
+	})
+	t.Run("will deny override if one of the active-deny-windows does not allow it", func(t *testing.T) {
+		t.Parallel()
+		proj := newProjectBuilder().
+			withActiveDenyWindow(false).withAllowOverride().
+			withActiveDenyWindow(false).
+			withActiveAllowWindow(false).
+			build()
+		assert.False(t, proj.Spec.SyncWindows.CanOverride())
+	})
+	t.Run("will allow override if the inactive-allow-windows allow it", func(t *testing.T) {
+		t.Parallel()
+		proj := newProjectBuilder().
+			withInactiveAllowWindow(false).withAllowOverride().
+			withInactiveAllowWindow(false).withAllowOverride().
+			build()
+		assert.True(t, proj.Spec.SyncWindows.CanOverride())
+	})
+	t.Run("will deny override if one of the inactive-allow-windows does not allow it", func(t *testing.T) {
+		t.Parallel()
+		proj := newProjectBuilder().
+			withInactiveAllowWindow(false).withAllowOverride().
+			withInactiveAllowWindow(false).
+			build()
+		assert.False(t, proj.Spec.SyncWindows.CanOverride())
+	})
+}
+
 func TestSyncWindows_hasDeny(t *testing.T) {
 	t.Run("True", func(t *testing.T) {
 		proj := newTestProjectWithSyncWindows()
@@ -2701,6 +2920,36 @@ func TestSyncWindow_Validate(t *testing.T) {
 		window.Duration = "1000days"
 		require.Error(t, window.Validate())
 	})
+	t.Run("ChronoSchedule", func(t *testing.T) {
+		window := &SyncWindow{Kind: "allow", ChronoSchedule: "every weekday from 09:00 to 17:00 in America/New_York"}
+		require.NoError(t, window.Validate())
+	})
+	t.Run("IncorrectChronoSchedule", func(t *testing.T) {
+		window := &SyncWindow{Kind: "allow", ChronoSchedule: "every holiday"}
+		require.ErrorContains(t, window.Validate(), "cannot parse chronoSchedule 'every holiday'")
+	})
+	t.Run("ChronoScheduleWithSchedule", func(t *testing.T) {
+		window := &SyncWindow{Kind: "allow", ChronoSchedule: "every day", Schedule: "* * * * *", Duration: "1h"}
+		require.ErrorContains(t, window.Validate(), "chronoSchedule cannot be combined with schedule and duration")
+	})
+}
+
+func TestSyncWindow_ChronoSchedule(t *testing.T) {
+	window := SyncWindow{Kind: "allow", ChronoSchedule: "every weekday from 09:00 to 17:00 except 12-25", TimeZone: "America/New_York"}
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// Monday
+	assert.True(t, window.active(time.Date(2024, 3, 11, 9, 0, 0, 0, newYork)))
+	assert.False(t, window.active(time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)))
+	// Christmas
+	assert.False(t, window.active(time.Date(2024, 12, 25, 12, 0, 0, 0, newYork)))
+
+	windows := &SyncWindows{&window}
+	assert.Equal(t, windows, windows.active(time.Date(2024, 3, 11, 12, 0, 0, 0, newYork)))
+	assert.Nil(t, windows.inactiveAllows(time.Date(2024, 3, 11, 12, 0, 0, 0, newYork)))
+	assert.Nil(t, windows.active(time.Date(2024, 12, 25, 12, 0, 0, 0, newYork)))
+	assert.Equal(t, windows, windows.inactiveAllows(time.Date(2024, 12, 25, 12, 0, 0, 0, newYork)))
 }
 
 func TestApplicationStatus_GetConditions(t *testing.T) {
@@ -2754,6 +3003,11 @@ func (b *projectBuilder) withInactiveDenyWindow(allowManual bool) *projectBuilde
 	return b
 }
 
+func (b *projectBuilder) withAllowOverride() *projectBuilder {
+	b.proj.Spec.SyncWindows[len(b.proj.Spec.SyncWindows)-1].AllowOverride = true
+	return b
+}
+
 func inactiveCronSchedule() string {
 	hourPlus10, _, _ := time.Now().Add(10 * time.Hour).Clock()
 	return fmt.Sprintf("0 %d * * *", hourPlus10)
@@ -2983,6 +3237,64 @@ func TestSyncPolicy_IsZero(t *testing.T) {
 	assert.False(t, (&SyncPolicy{Automated: &SyncPolicyAutomated{}}).IsZero())
 	assert.False(t, (&SyncPolicy{SyncOptions: SyncOptions{""}}).IsZero())
 	assert.False(t, (&SyncPolicy{Retry: &RetryStrategy{}}).IsZero())
+	assert.False(t, (&SyncPolicy{SyncTimeout: "30m"}).IsZero())
+	assert.False(t, (&SyncPolicy{ApplyTimeouts: map[string]metav1.Duration{"/ConfigMap": {Duration: time.Minute}}}).IsZero())
+	assert.False(t, (&SyncPolicy{DeletionOrder: []string{"ConfigMap"}}).IsZero())
+}
+
+func TestSyncPolicy_GetDeletionOrderIndex(t *testing.T) {
+	crd := schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+	var nilPolicy *SyncPolicy
+	assert.Equal(t, 0, nilPolicy.GetDeletionOrderIndex(crd))
+
+	policy := &SyncPolicy{DeletionOrder: []string{"argoproj.io/Rollout", "apiextensions.k8s.io/CustomResourceDefinition", "ConfigMap", "/Secret"}}
+	assert.Equal(t, 0, policy.GetDeletionOrderIndex(schema.GroupKind{Group: "argoproj.io", Kind: "Rollout"}))
+	assert.Equal(t, 1, policy.GetDeletionOrderIndex(crd))
+	assert.Equal(t, 2, policy.GetDeletionOrderIndex(schema.GroupKind{Kind: "ConfigMap"}))
+	assert.Equal(t, 3, policy.GetDeletionOrderIndex(schema.GroupKind{Kind: "Secret"}))
+	// resources of unlisted kinds are deleted last
+	assert.Equal(t, 4, policy.GetDeletionOrderIndex(schema.GroupKind{Group: "apps", Kind: "Deployment"}))
+	// resources of other groups are not matched by their kind only
+	assert.Equal(t, 4, policy.GetDeletionOrderIndex(schema.GroupKind{Group: "example.com", Kind: "ConfigMap"}))
+}
+
+func TestSyncPolicy_GetApplyTimeout(t *testing.T) {
+	crd := schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"}
+	var nilPolicy *SyncPolicy
+	assert.Equal(t, time.Duration(0), nilPolicy.GetApplyTimeout(crd))
+
+	policy := &SyncPolicy{ApplyTimeouts: map[string]metav1.Duration{
+		"apiextensions.k8s.io/CustomResourceDefinition": {Duration: time.Minute},
+		"ConfigMap": {Duration: 10 * time.Second},
+		"/Secret":   {Duration: 20 * time.Second},
+	}}
+	assert.Equal(t, time.Minute, policy.GetApplyTimeout(crd))
+	assert.Equal(t, 10*time.Second, policy.GetApplyTimeout(schema.GroupKind{Kind: "ConfigMap"}))
+	assert.Equal(t, 20*time.Second, policy.GetApplyTimeout(schema.GroupKind{Kind: "Secret"}))
+	assert.Equal(t, time.Duration(0), policy.GetApplyTimeout(schema.GroupKind{Group: "apps", Kind: "Deployment"}))
+	// resources of other groups are not matched by their kind only
+	assert.Equal(t, time.Duration(0), policy.GetApplyTimeout(schema.GroupKind{Group: "example.com", Kind: "ConfigMap"}))
+}
+
+func TestSyncPolicy_GetSyncTimeout(t *testing.T) {
+	var nilPolicy *SyncPolicy
+	timeout, err := nilPolicy.GetSyncTimeout()
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), timeout)
+
+	timeout, err = (&SyncPolicy{SyncTimeout: "30m"}).GetSyncTimeout()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, timeout)
+
+	// durations without unit are in seconds
+	timeout, err = (&SyncPolicy{SyncTimeout: "90"}).GetSyncTimeout()
+	require.NoError(t, err)
+	assert.Equal(t, 90*time.Second, timeout)
+
+	_, err = (&SyncPolicy{SyncTimeout: "forever"}).GetSyncTimeout()
+	require.ErrorContains(t, err, "invalid sync timeout")
+	_, err = (&SyncPolicy{SyncTimeout: "-1m"}).GetSyncTimeout()
+	require.ErrorContains(t, err, "must not be negative")
 }
 
 func TestSyncOptions_HasOption(t *testing.T) {
@@ -3842,6 +4154,16 @@ func TestOptionalMapEquality(t *testing.T) {
 	}
 }
 
+func TestApplicationSpec_GetDestinations(t *testing.T) {
+	spec := ApplicationSpec{Destination: ApplicationDestination{Server: "https://cluster-1", Namespace: "default"}}
+	assert.False(t, spec.HasMultipleDestinations())
+	assert.Equal(t, []ApplicationDestination{spec.Destination}, spec.GetDestinations())
+
+	spec.Destinations = []ApplicationDestination{{Server: "https://cluster-2", Namespace: "default"}}
+	assert.True(t, spec.HasMultipleDestinations())
+	assert.Equal(t, []ApplicationDestination{spec.Destination, spec.Destinations[0]}, spec.GetDestinations())
+}
+
 func TestApplicationSpec_GetSourcePtrByIndex(t *testing.T) {
 	testCases := []struct {
 		name        string
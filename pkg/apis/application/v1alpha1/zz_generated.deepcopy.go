@@ -156,6 +156,43 @@ func (in *AppProjectSpec) DeepCopyInto(out *AppProjectSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PostSyncWebhook != nil {
+		in, out := &in.PostSyncWebhook, &out.PostSyncWebhook
+		*out = new(PostSyncWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.NamespaceResourceQuota != nil {
+		in, out := &in.NamespaceResourceQuota, &out.NamespaceResourceQuota
+		*out = make(map[string]int64, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Allowlist != nil {
+		in, out := &in.Allowlist, &out.Allowlist
+		*out = new(ProjectAllowlist)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ArtifactStorage != nil {
+		in, out := &in.ArtifactStorage, &out.ArtifactStorage
+		*out = new(ArtifactStorage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Datadog != nil {
+		in, out := &in.Datadog, &out.Datadog
+		*out = new(DatadogIntegration)
+		**out = **in
+	}
+	if in.HistoryRetentionPolicy != nil {
+		in, out := &in.HistoryRetentionPolicy, &out.HistoryRetentionPolicy
+		*out = new(HistoryRetentionPolicy)
+		**out = **in
+	}
+	if in.SyncPolicy != nil {
+		in, out := &in.SyncPolicy, &out.SyncPolicy
+		*out = new(ProjectSyncPolicy)
+		**out = **in
+	}
 	return
 }
 
@@ -1012,6 +1049,51 @@ func (in *ApplicationSource) DeepCopyInto(out *ApplicationSource) {
 		*out = new(ApplicationSourcePlugin)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Inline != nil {
+		in, out := &in.Inline, &out.Inline
+		*out = new(ApplicationSourceInline)
+		**out = **in
+	}
+	if in.Ytt != nil {
+		in, out := &in.Ytt, &out.Ytt
+		*out = new(ApplicationSourceYtt)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VerifyAttestation != nil {
+		in, out := &in.VerifyAttestation, &out.VerifyAttestation
+		*out = new(ApplicationSourceVerifyAttestation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Starlark != nil {
+		in, out := &in.Starlark, &out.Starlark
+		*out = new(ApplicationSourceStarlark)
+		**out = **in
+	}
+	if in.Cue != nil {
+		in, out := &in.Cue, &out.Cue
+		*out = new(ApplicationSourceCue)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(ApplicationSourceHTTP)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pulumi != nil {
+		in, out := &in.Pulumi, &out.Pulumi
+		*out = new(ApplicationSourcePulumi)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(ApplicationSourceOCI)
+		**out = **in
+	}
+	if in.Timoni != nil {
+		in, out := &in.Timoni, &out.Timoni
+		*out = new(ApplicationSourceTimoni)
+		**out = **in
+	}
 	return
 }
 
@@ -1025,6 +1107,29 @@ func (in *ApplicationSource) DeepCopy() *ApplicationSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSourceCue) DeepCopyInto(out *ApplicationSourceCue) {
+	*out = *in
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSourceCue.
+func (in *ApplicationSourceCue) DeepCopy() *ApplicationSourceCue {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSourceCue)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationSourceDirectory) DeepCopyInto(out *ApplicationSourceDirectory) {
 	*out = *in
@@ -1042,6 +1147,27 @@ func (in *ApplicationSourceDirectory) DeepCopy() *ApplicationSourceDirectory {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSourceHTTP) DeepCopyInto(out *ApplicationSourceHTTP) {
+	*out = *in
+	if in.CABundleSecret != nil {
+		in, out := &in.CABundleSecret, &out.CABundleSecret
+		*out = new(SecretRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSourceHTTP.
+func (in *ApplicationSourceHTTP) DeepCopy() *ApplicationSourceHTTP {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSourceHTTP)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationSourceHelm) DeepCopyInto(out *ApplicationSourceHelm) {
 	*out = *in
@@ -1065,6 +1191,16 @@ func (in *ApplicationSourceHelm) DeepCopyInto(out *ApplicationSourceHelm) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.APIVersions != nil {
+		in, out := &in.APIVersions, &out.APIVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ValuesFrom != nil {
+		in, out := &in.ValuesFrom, &out.ValuesFrom
+		*out = new(HelmValuesFrom)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1078,6 +1214,22 @@ func (in *ApplicationSourceHelm) DeepCopy() *ApplicationSourceHelm {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSourceInline) DeepCopyInto(out *ApplicationSourceInline) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSourceInline.
+func (in *ApplicationSourceInline) DeepCopy() *ApplicationSourceInline {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSourceInline)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationSourceJsonnet) DeepCopyInto(out *ApplicationSourceJsonnet) {
 	*out = *in
@@ -1148,6 +1300,11 @@ func (in *ApplicationSourceKustomize) DeepCopyInto(out *ApplicationSourceKustomi
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.APIVersions != nil {
+		in, out := &in.APIVersions, &out.APIVersions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1161,6 +1318,22 @@ func (in *ApplicationSourceKustomize) DeepCopy() *ApplicationSourceKustomize {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSourceOCI) DeepCopyInto(out *ApplicationSourceOCI) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSourceOCI.
+func (in *ApplicationSourceOCI) DeepCopy() *ApplicationSourceOCI {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSourceOCI)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ApplicationSourcePlugin) DeepCopyInto(out *ApplicationSourcePlugin) {
 	*out = *in
@@ -1248,6 +1421,110 @@ func (in ApplicationSourcePluginParameters) DeepCopy() ApplicationSourcePluginPa
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSourcePulumi) DeepCopyInto(out *ApplicationSourcePulumi) {
+	*out = *in
+	if in.Config != nil {
+		in, out := &in.Config, &out.Config
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSourcePulumi.
+func (in *ApplicationSourcePulumi) DeepCopy() *ApplicationSourcePulumi {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSourcePulumi)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSourceStarlark) DeepCopyInto(out *ApplicationSourceStarlark) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSourceStarlark.
+func (in *ApplicationSourceStarlark) DeepCopy() *ApplicationSourceStarlark {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSourceStarlark)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSourceTimoni) DeepCopyInto(out *ApplicationSourceTimoni) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSourceTimoni.
+func (in *ApplicationSourceTimoni) DeepCopy() *ApplicationSourceTimoni {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSourceTimoni)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSourceVerifyAttestation) DeepCopyInto(out *ApplicationSourceVerifyAttestation) {
+	*out = *in
+	if in.InToto != nil {
+		in, out := &in.InToto, &out.InToto
+		*out = new(InTotoAttestation)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSourceVerifyAttestation.
+func (in *ApplicationSourceVerifyAttestation) DeepCopy() *ApplicationSourceVerifyAttestation {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSourceVerifyAttestation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplicationSourceYtt) DeepCopyInto(out *ApplicationSourceYtt) {
+	*out = *in
+	if in.Lib != nil {
+		in, out := &in.Lib, &out.Lib
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DataValues != nil {
+		in, out := &in.DataValues, &out.DataValues
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplicationSourceYtt.
+func (in *ApplicationSourceYtt) DeepCopy() *ApplicationSourceYtt {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplicationSourceYtt)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in ApplicationSources) DeepCopyInto(out *ApplicationSources) {
 	{
@@ -1308,6 +1585,11 @@ func (in *ApplicationSpec) DeepCopyInto(out *ApplicationSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]ApplicationDestination, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1366,6 +1648,30 @@ func (in *ApplicationStatus) DeepCopyInto(out *ApplicationStatus) {
 		*out = make([]ApplicationSourceType, len(*in))
 		copy(*out, *in)
 	}
+	if in.ConditionHistory != nil {
+		in, out := &in.ConditionHistory, &out.ConditionHistory
+		*out = make([]ConditionHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RollbackHistory != nil {
+		in, out := &in.RollbackHistory, &out.RollbackHistory
+		*out = make([]RollbackHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AutoValueFiles != nil {
+		in, out := &in.AutoValueFiles, &out.AutoValueFiles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DestinationStatuses != nil {
+		in, out := &in.DestinationStatuses, &out.DestinationStatuses
+		*out = make([]DestinationSyncStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1459,6 +1765,59 @@ func (in *ApplicationWatchEvent) DeepCopy() *ApplicationWatchEvent {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApplyRateLimit) DeepCopyInto(out *ApplyRateLimit) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApplyRateLimit.
+func (in *ApplyRateLimit) DeepCopy() *ApplyRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(ApplyRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ArtifactStorage) DeepCopyInto(out *ArtifactStorage) {
+	*out = *in
+	if in.OCI != nil {
+		in, out := &in.OCI, &out.OCI
+		*out = new(OCIArtifactStorage)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ArtifactStorage.
+func (in *ArtifactStorage) DeepCopy() *ArtifactStorage {
+	if in == nil {
+		return nil
+	}
+	out := new(ArtifactStorage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoRollback) DeepCopyInto(out *AutoRollback) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoRollback.
+func (in *AutoRollback) DeepCopy() *AutoRollback {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoRollback)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Backoff) DeepCopyInto(out *Backoff) {
 	*out = *in
@@ -1501,6 +1860,27 @@ func (in *BasicAuthBitbucketServer) DeepCopy() *BasicAuthBitbucketServer {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BearerTokenBitbucket) DeepCopyInto(out *BearerTokenBitbucket) {
+	*out = *in
+	if in.TokenRef != nil {
+		in, out := &in.TokenRef, &out.TokenRef
+		*out = new(SecretRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BearerTokenBitbucket.
+func (in *BearerTokenBitbucket) DeepCopy() *BearerTokenBitbucket {
+	if in == nil {
+		return nil
+	}
+	out := new(BearerTokenBitbucket)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BearerTokenBitbucketCloud) DeepCopyInto(out *BearerTokenBitbucketCloud) {
 	*out = *in
@@ -1783,6 +2163,26 @@ func (in *ComponentParameter) DeepCopy() *ComponentParameter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConditionHistoryEntry) DeepCopyInto(out *ConditionHistoryEntry) {
+	*out = *in
+	if in.LastTransitionTime != nil {
+		in, out := &in.LastTransitionTime, &out.LastTransitionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConditionHistoryEntry.
+func (in *ConditionHistoryEntry) DeepCopy() *ConditionHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ConditionHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConfigManagementPlugin) DeepCopyInto(out *ConfigManagementPlugin) {
 	*out = *in
@@ -1805,6 +2205,22 @@ func (in *ConfigManagementPlugin) DeepCopy() *ConfigManagementPlugin {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConfigMapKeyRef) DeepCopyInto(out *ConfigMapKeyRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConfigMapKeyRef.
+func (in *ConfigMapKeyRef) DeepCopy() *ConfigMapKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(ConfigMapKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ConnectionState) DeepCopyInto(out *ConnectionState) {
 	*out = *in
@@ -1825,6 +2241,69 @@ func (in *ConnectionState) DeepCopy() *ConnectionState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatadogIntegration) DeepCopyInto(out *DatadogIntegration) {
+	*out = *in
+	out.APIKeySecret = in.APIKeySecret
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatadogIntegration.
+func (in *DatadogIntegration) DeepCopy() *DatadogIntegration {
+	if in == nil {
+		return nil
+	}
+	out := new(DatadogIntegration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DestinationSyncResult) DeepCopyInto(out *DestinationSyncResult) {
+	*out = *in
+	out.Destination = in.Destination
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make(ResourceResults, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = new(ResourceResult)
+				**out = **in
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DestinationSyncResult.
+func (in *DestinationSyncResult) DeepCopy() *DestinationSyncResult {
+	if in == nil {
+		return nil
+	}
+	out := new(DestinationSyncResult)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DestinationSyncStatus) DeepCopyInto(out *DestinationSyncStatus) {
+	*out = *in
+	out.Destination = in.Destination
+	out.Health = in.Health
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DestinationSyncStatus.
+func (in *DestinationSyncStatus) DeepCopy() *DestinationSyncStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DestinationSyncStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DuckTypeGenerator) DeepCopyInto(out *DuckTypeGenerator) {
 	*out = *in
@@ -2119,6 +2598,65 @@ func (in *HelmParameter) DeepCopy() *HelmParameter {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmValuesFrom) DeepCopyInto(out *HelmValuesFrom) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(HelmValuesKeyRef)
+		**out = **in
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(HelmValuesKeyRef)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmValuesFrom.
+func (in *HelmValuesFrom) DeepCopy() *HelmValuesFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmValuesFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmValuesKeyRef) DeepCopyInto(out *HelmValuesKeyRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmValuesKeyRef.
+func (in *HelmValuesKeyRef) DeepCopy() *HelmValuesKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmValuesKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HistoryRetentionPolicy) DeepCopyInto(out *HistoryRetentionPolicy) {
+	*out = *in
+	out.MaxAge = in.MaxAge
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HistoryRetentionPolicy.
+func (in *HistoryRetentionPolicy) DeepCopy() *HistoryRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(HistoryRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostInfo) DeepCopyInto(out *HostInfo) {
 	*out = *in
@@ -2179,6 +2717,22 @@ func (in IgnoreDifferences) DeepCopy() IgnoreDifferences {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InTotoAttestation) DeepCopyInto(out *InTotoAttestation) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InTotoAttestation.
+func (in *InTotoAttestation) DeepCopy() *InTotoAttestation {
+	if in == nil {
+		return nil
+	}
+	out := new(InTotoAttestation)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Info) DeepCopyInto(out *Info) {
 	*out = *in
@@ -2565,7 +3119,7 @@ func (in *NestedMatrixGenerator) DeepCopyInto(out *NestedMatrixGenerator) {
 	*out = *in
 	if in.Generators != nil {
 		in, out := &in.Generators, &out.Generators
-		*out = make(ApplicationSetTerminalGenerators, len(*in))
+		*out = make(ApplicationSetNestedGenerators, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -2601,12 +3155,28 @@ func (in *NestedMergeGenerator) DeepCopyInto(out *NestedMergeGenerator) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NestedMergeGenerator.
-func (in *NestedMergeGenerator) DeepCopy() *NestedMergeGenerator {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NestedMergeGenerator.
+func (in *NestedMergeGenerator) DeepCopy() *NestedMergeGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(NestedMergeGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OCIArtifactStorage) DeepCopyInto(out *OCIArtifactStorage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OCIArtifactStorage.
+func (in *OCIArtifactStorage) DeepCopy() *OCIArtifactStorage {
 	if in == nil {
 		return nil
 	}
-	out := new(NestedMergeGenerator)
+	out := new(OCIArtifactStorage)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -2675,6 +3245,15 @@ func (in *OperationState) DeepCopyInto(out *OperationState) {
 		in, out := &in.FinishedAt, &out.FinishedAt
 		*out = (*in).DeepCopy()
 	}
+	if in.Progress != nil {
+		in, out := &in.Progress, &out.Progress
+		*out = new(SyncOperationProgress)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WebhookConfirmationStartedAt != nil {
+		in, out := &in.WebhookConfirmationStartedAt, &out.WebhookConfirmationStartedAt
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -2897,6 +3476,66 @@ func (in PluginParameters) DeepCopy() PluginParameters {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostSyncWebhook) DeepCopyInto(out *PostSyncWebhook) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostSyncWebhook.
+func (in *PostSyncWebhook) DeepCopy() *PostSyncWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(PostSyncWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PreFlightCheck) DeepCopyInto(out *PreFlightCheck) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PreFlightCheck.
+func (in *PreFlightCheck) DeepCopy() *PreFlightCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(PreFlightCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectAllowlist) DeepCopyInto(out *ProjectAllowlist) {
+	*out = *in
+	if in.LicenseIDs != nil {
+		in, out := &in.LicenseIDs, &out.LicenseIDs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectAllowlist.
+func (in *ProjectAllowlist) DeepCopy() *ProjectAllowlist {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectAllowlist)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ProjectRole) DeepCopyInto(out *ProjectRole) {
 	*out = *in
@@ -2928,6 +3567,22 @@ func (in *ProjectRole) DeepCopy() *ProjectRole {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProjectSyncPolicy) DeepCopyInto(out *ProjectSyncPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProjectSyncPolicy.
+func (in *ProjectSyncPolicy) DeepCopy() *ProjectSyncPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ProjectSyncPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PullRequestGenerator) DeepCopyInto(out *PullRequestGenerator) {
 	*out = *in
@@ -3047,6 +3702,16 @@ func (in *PullRequestGeneratorBitbucketServer) DeepCopyInto(out *PullRequestGene
 		*out = new(BasicAuthBitbucketServer)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BearerToken != nil {
+		in, out := &in.BearerToken, &out.BearerToken
+		*out = new(BearerTokenBitbucket)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CARef != nil {
+		in, out := &in.CARef, &out.CARef
+		*out = new(ConfigMapKeyRef)
+		**out = **in
+	}
 	return
 }
 
@@ -3099,6 +3764,11 @@ func (in *PullRequestGeneratorGitLab) DeepCopyInto(out *PullRequestGeneratorGitL
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.CARef != nil {
+		in, out := &in.CARef, &out.CARef
+		*out = new(ConfigMapKeyRef)
+		**out = **in
+	}
 	return
 }
 
@@ -3332,6 +4002,152 @@ func (in *RepositoryCertificateList) DeepCopy() *RepositoryCertificateList {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryConfig) DeepCopyInto(out *RepositoryConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryConfig.
+func (in *RepositoryConfig) DeepCopy() *RepositoryConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepositoryConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryConfigList) DeepCopyInto(out *RepositoryConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RepositoryConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryConfigList.
+func (in *RepositoryConfigList) DeepCopy() *RepositoryConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RepositoryConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryConfigSecretValue) DeepCopyInto(out *RepositoryConfigSecretValue) {
+	*out = *in
+	in.ValueFrom.DeepCopyInto(&out.ValueFrom)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryConfigSecretValue.
+func (in *RepositoryConfigSecretValue) DeepCopy() *RepositoryConfigSecretValue {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryConfigSecretValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryConfigSecretValueSource) DeepCopyInto(out *RepositoryConfigSecretValueSource) {
+	*out = *in
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryConfigSecretValueSource.
+func (in *RepositoryConfigSecretValueSource) DeepCopy() *RepositoryConfigSecretValueSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryConfigSecretValueSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryConfigSpec) DeepCopyInto(out *RepositoryConfigSpec) {
+	*out = *in
+	if in.Password != nil {
+		in, out := &in.Password, &out.Password
+		*out = new(RepositoryConfigSecretValue)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SSHPrivateKey != nil {
+		in, out := &in.SSHPrivateKey, &out.SSHPrivateKey
+		*out = new(RepositoryConfigSecretValue)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLSClientCertKey != nil {
+		in, out := &in.TLSClientCertKey, &out.TLSClientCertKey
+		*out = new(RepositoryConfigSecretValue)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryConfigSpec.
+func (in *RepositoryConfigSpec) DeepCopy() *RepositoryConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RepositoryConfigStatus) DeepCopyInto(out *RepositoryConfigStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RepositoryConfigStatus.
+func (in *RepositoryConfigStatus) DeepCopy() *RepositoryConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RepositoryConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RepositoryList) DeepCopyInto(out *RepositoryList) {
 	*out = *in
@@ -3776,6 +4592,23 @@ func (in *RevisionMetadata) DeepCopy() *RevisionMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackHistoryEntry) DeepCopyInto(out *RollbackHistoryEntry) {
+	*out = *in
+	in.RolledBackAt.DeepCopyInto(&out.RolledBackAt)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackHistoryEntry.
+func (in *RollbackHistoryEntry) DeepCopy() *RollbackHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SCMProviderGenerator) DeepCopyInto(out *SCMProviderGenerator) {
 	*out = *in
@@ -3924,6 +4757,16 @@ func (in *SCMProviderGeneratorBitbucketServer) DeepCopyInto(out *SCMProviderGene
 		*out = new(BasicAuthBitbucketServer)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.BearerToken != nil {
+		in, out := &in.BearerToken, &out.BearerToken
+		*out = new(BearerTokenBitbucket)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CARef != nil {
+		in, out := &in.CARef, &out.CARef
+		*out = new(ConfigMapKeyRef)
+		**out = **in
+	}
 	return
 }
 
@@ -4033,6 +4876,11 @@ func (in *SCMProviderGeneratorGitlab) DeepCopyInto(out *SCMProviderGeneratorGitl
 		*out = new(bool)
 		**out = **in
 	}
+	if in.CARef != nil {
+		in, out := &in.CARef, &out.CARef
+		*out = new(ConfigMapKeyRef)
+		**out = **in
+	}
 	return
 }
 
@@ -4118,6 +4966,11 @@ func (in *SyncOperation) DeepCopyInto(out *SyncOperation) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]ApplicationDestination, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -4131,6 +4984,26 @@ func (in *SyncOperation) DeepCopy() *SyncOperation {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncOperationProgress) DeepCopyInto(out *SyncOperationProgress) {
+	*out = *in
+	if in.EstimatedCompletionTime != nil {
+		in, out := &in.EstimatedCompletionTime, &out.EstimatedCompletionTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncOperationProgress.
+func (in *SyncOperationProgress) DeepCopy() *SyncOperationProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncOperationProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncOperationResource) DeepCopyInto(out *SyncOperationResource) {
 	*out = *in
@@ -4179,6 +5052,13 @@ func (in *SyncOperationResult) DeepCopyInto(out *SyncOperationResult) {
 		*out = new(ManagedNamespaceMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]DestinationSyncResult, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -4235,6 +5115,40 @@ func (in *SyncPolicy) DeepCopyInto(out *SyncPolicy) {
 		*out = new(ManagedNamespaceMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ApplyRateLimit != nil {
+		in, out := &in.ApplyRateLimit, &out.ApplyRateLimit
+		*out = new(ApplyRateLimit)
+		**out = **in
+	}
+	if in.PreFlightCheck != nil {
+		in, out := &in.PreFlightCheck, &out.PreFlightCheck
+		*out = new(PreFlightCheck)
+		**out = **in
+	}
+	if in.AutoRollback != nil {
+		in, out := &in.AutoRollback, &out.AutoRollback
+		*out = new(AutoRollback)
+		**out = **in
+	}
+	if in.ApplyTimeouts != nil {
+		in, out := &in.ApplyTimeouts, &out.ApplyTimeouts
+		*out = make(map[string]v1.Duration, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DeletionOrder != nil {
+		in, out := &in.DeletionOrder, &out.DeletionOrder
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RetryOn != nil {
+		in, out := &in.RetryOn, &out.RetryOn
+		*out = make([]SyncRetryPattern, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -4264,6 +5178,27 @@ func (in *SyncPolicyAutomated) DeepCopy() *SyncPolicyAutomated {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncRetryPattern) DeepCopyInto(out *SyncRetryPattern) {
+	*out = *in
+	if in.Backoff != nil {
+		in, out := &in.Backoff, &out.Backoff
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncRetryPattern.
+func (in *SyncRetryPattern) DeepCopy() *SyncRetryPattern {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncRetryPattern)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncStatus) DeepCopyInto(out *SyncStatus) {
 	*out = *in
@@ -24,6 +24,10 @@ func (c *FakeArgoprojV1alpha1) ApplicationSets(namespace string) v1alpha1.Applic
 	return &FakeApplicationSets{c, namespace}
 }
 
+func (c *FakeArgoprojV1alpha1) RepositoryConfigs(namespace string) v1alpha1.RepositoryConfigInterface {
+	return &FakeRepositoryConfigs{c, namespace}
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeArgoprojV1alpha1) RESTClient() rest.Interface {
@@ -0,0 +1,126 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeRepositoryConfigs implements RepositoryConfigInterface
+type FakeRepositoryConfigs struct {
+	Fake *FakeArgoprojV1alpha1
+	ns   string
+}
+
+var repositoryconfigsResource = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "repositoryconfigs"}
+
+var repositoryconfigsKind = schema.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "RepositoryConfig"}
+
+// Get takes name of the repositoryConfig, and returns the corresponding repositoryConfig object, and an error if there is any.
+func (c *FakeRepositoryConfigs) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.RepositoryConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(repositoryconfigsResource, c.ns, name), &v1alpha1.RepositoryConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RepositoryConfig), err
+}
+
+// List takes label and field selectors, and returns the list of RepositoryConfigs that match those selectors.
+func (c *FakeRepositoryConfigs) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.RepositoryConfigList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(repositoryconfigsResource, repositoryconfigsKind, c.ns, opts), &v1alpha1.RepositoryConfigList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.RepositoryConfigList{ListMeta: obj.(*v1alpha1.RepositoryConfigList).ListMeta}
+	for _, item := range obj.(*v1alpha1.RepositoryConfigList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested repositoryConfigs.
+func (c *FakeRepositoryConfigs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(repositoryconfigsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a repositoryConfig and creates it.  Returns the server's representation of the repositoryConfig, and an error, if there is any.
+func (c *FakeRepositoryConfigs) Create(ctx context.Context, repositoryConfig *v1alpha1.RepositoryConfig, opts v1.CreateOptions) (result *v1alpha1.RepositoryConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(repositoryconfigsResource, c.ns, repositoryConfig), &v1alpha1.RepositoryConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RepositoryConfig), err
+}
+
+// Update takes the representation of a repositoryConfig and updates it. Returns the server's representation of the repositoryConfig, and an error, if there is any.
+func (c *FakeRepositoryConfigs) Update(ctx context.Context, repositoryConfig *v1alpha1.RepositoryConfig, opts v1.UpdateOptions) (result *v1alpha1.RepositoryConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(repositoryconfigsResource, c.ns, repositoryConfig), &v1alpha1.RepositoryConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RepositoryConfig), err
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *FakeRepositoryConfigs) UpdateStatus(ctx context.Context, repositoryConfig *v1alpha1.RepositoryConfig, opts v1.UpdateOptions) (*v1alpha1.RepositoryConfig, error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateSubresourceAction(repositoryconfigsResource, "status", c.ns, repositoryConfig), &v1alpha1.RepositoryConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RepositoryConfig), err
+}
+
+// Delete takes name of the repositoryConfig and deletes it. Returns an error if one occurs.
+func (c *FakeRepositoryConfigs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(repositoryconfigsResource, c.ns, name, opts), &v1alpha1.RepositoryConfig{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeRepositoryConfigs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(repositoryconfigsResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.RepositoryConfigList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched repositoryConfig.
+func (c *FakeRepositoryConfigs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RepositoryConfig, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(repositoryconfigsResource, c.ns, name, pt, data, subresources...), &v1alpha1.RepositoryConfig{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.RepositoryConfig), err
+}
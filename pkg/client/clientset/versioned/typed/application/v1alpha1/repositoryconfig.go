@@ -0,0 +1,179 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	scheme "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// RepositoryConfigsGetter has a method to return a RepositoryConfigInterface.
+// A group's client should implement this interface.
+type RepositoryConfigsGetter interface {
+	RepositoryConfigs(namespace string) RepositoryConfigInterface
+}
+
+// RepositoryConfigInterface has methods to work with RepositoryConfig resources.
+type RepositoryConfigInterface interface {
+	Create(ctx context.Context, repositoryConfig *v1alpha1.RepositoryConfig, opts v1.CreateOptions) (*v1alpha1.RepositoryConfig, error)
+	Update(ctx context.Context, repositoryConfig *v1alpha1.RepositoryConfig, opts v1.UpdateOptions) (*v1alpha1.RepositoryConfig, error)
+	UpdateStatus(ctx context.Context, repositoryConfig *v1alpha1.RepositoryConfig, opts v1.UpdateOptions) (*v1alpha1.RepositoryConfig, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.RepositoryConfig, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.RepositoryConfigList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RepositoryConfig, err error)
+	RepositoryConfigExpansion
+}
+
+// repositoryConfigs implements RepositoryConfigInterface
+type repositoryConfigs struct {
+	client rest.Interface
+	ns     string
+}
+
+// newRepositoryConfigs returns a RepositoryConfigs
+func newRepositoryConfigs(c *ArgoprojV1alpha1Client, namespace string) *repositoryConfigs {
+	return &repositoryConfigs{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the repositoryConfig, and returns the corresponding repositoryConfig object, and an error if there is any.
+func (c *repositoryConfigs) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.RepositoryConfig, err error) {
+	result = &v1alpha1.RepositoryConfig{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("repositoryconfigs").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of RepositoryConfigs that match those selectors.
+func (c *repositoryConfigs) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.RepositoryConfigList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.RepositoryConfigList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("repositoryconfigs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested repositoryConfigs.
+func (c *repositoryConfigs) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("repositoryconfigs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a repositoryConfig and creates it.  Returns the server's representation of the repositoryConfig, and an error, if there is any.
+func (c *repositoryConfigs) Create(ctx context.Context, repositoryConfig *v1alpha1.RepositoryConfig, opts v1.CreateOptions) (result *v1alpha1.RepositoryConfig, err error) {
+	result = &v1alpha1.RepositoryConfig{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("repositoryconfigs").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(repositoryConfig).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a repositoryConfig and updates it. Returns the server's representation of the repositoryConfig, and an error, if there is any.
+func (c *repositoryConfigs) Update(ctx context.Context, repositoryConfig *v1alpha1.RepositoryConfig, opts v1.UpdateOptions) (result *v1alpha1.RepositoryConfig, err error) {
+	result = &v1alpha1.RepositoryConfig{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("repositoryconfigs").
+		Name(repositoryConfig.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(repositoryConfig).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (c *repositoryConfigs) UpdateStatus(ctx context.Context, repositoryConfig *v1alpha1.RepositoryConfig, opts v1.UpdateOptions) (result *v1alpha1.RepositoryConfig, err error) {
+	result = &v1alpha1.RepositoryConfig{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("repositoryconfigs").
+		Name(repositoryConfig.Name).
+		SubResource("status").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(repositoryConfig).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the repositoryConfig and deletes it. Returns an error if one occurs.
+func (c *repositoryConfigs) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("repositoryconfigs").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *repositoryConfigs) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("repositoryconfigs").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched repositoryConfig.
+func (c *repositoryConfigs) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.RepositoryConfig, err error) {
+	result = &v1alpha1.RepositoryConfig{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("repositoryconfigs").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
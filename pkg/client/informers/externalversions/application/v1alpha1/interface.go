@@ -14,6 +14,8 @@ type Interface interface {
 	Applications() ApplicationInformer
 	// ApplicationSets returns a ApplicationSetInformer.
 	ApplicationSets() ApplicationSetInformer
+	// RepositoryConfigs returns a RepositoryConfigInformer.
+	RepositoryConfigs() RepositoryConfigInformer
 }
 
 type version struct {
@@ -41,3 +43,8 @@ func (v *version) Applications() ApplicationInformer {
 func (v *version) ApplicationSets() ApplicationSetInformer {
 	return &applicationSetInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// RepositoryConfigs returns a RepositoryConfigInformer.
+func (v *version) RepositoryConfigs() RepositoryConfigInformer {
+	return &repositoryConfigInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
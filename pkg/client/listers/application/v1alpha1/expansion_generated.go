@@ -25,3 +25,11 @@ type ApplicationSetListerExpansion interface{}
 // ApplicationSetNamespaceListerExpansion allows custom methods to be added to
 // ApplicationSetNamespaceLister.
 type ApplicationSetNamespaceListerExpansion interface{}
+
+// RepositoryConfigListerExpansion allows custom methods to be added to
+// RepositoryConfigLister.
+type RepositoryConfigListerExpansion interface{}
+
+// RepositoryConfigNamespaceListerExpansion allows custom methods to be added to
+// RepositoryConfigNamespaceLister.
+type RepositoryConfigNamespaceListerExpansion interface{}
@@ -0,0 +1,83 @@
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// RepositoryConfigLister helps list RepositoryConfigs.
+// All objects returned here must be treated as read-only.
+type RepositoryConfigLister interface {
+	// List lists all RepositoryConfigs in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.RepositoryConfig, err error)
+	// RepositoryConfigs returns an object that can list and get RepositoryConfigs.
+	RepositoryConfigs(namespace string) RepositoryConfigNamespaceLister
+	RepositoryConfigListerExpansion
+}
+
+// repositoryConfigLister implements the RepositoryConfigLister interface.
+type repositoryConfigLister struct {
+	indexer cache.Indexer
+}
+
+// NewRepositoryConfigLister returns a new RepositoryConfigLister.
+func NewRepositoryConfigLister(indexer cache.Indexer) RepositoryConfigLister {
+	return &repositoryConfigLister{indexer: indexer}
+}
+
+// List lists all RepositoryConfigs in the indexer.
+func (s *repositoryConfigLister) List(selector labels.Selector) (ret []*v1alpha1.RepositoryConfig, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.RepositoryConfig))
+	})
+	return ret, err
+}
+
+// RepositoryConfigs returns an object that can list and get RepositoryConfigs.
+func (s *repositoryConfigLister) RepositoryConfigs(namespace string) RepositoryConfigNamespaceLister {
+	return repositoryConfigNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// RepositoryConfigNamespaceLister helps list and get RepositoryConfigs.
+// All objects returned here must be treated as read-only.
+type RepositoryConfigNamespaceLister interface {
+	// List lists all RepositoryConfigs in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.RepositoryConfig, err error)
+	// Get retrieves the RepositoryConfig from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.RepositoryConfig, error)
+	RepositoryConfigNamespaceListerExpansion
+}
+
+// repositoryConfigNamespaceLister implements the RepositoryConfigNamespaceLister
+// interface.
+type repositoryConfigNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all RepositoryConfigs in the indexer for a given namespace.
+func (s repositoryConfigNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.RepositoryConfig, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.RepositoryConfig))
+	})
+	return ret, err
+}
+
+// Get retrieves the RepositoryConfig from the indexer for a given namespace and name.
+func (s repositoryConfigNamespaceLister) Get(name string) (*v1alpha1.RepositoryConfig, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("repositoryconfig"), name)
+	}
+	return obj.(*v1alpha1.RepositoryConfig), nil
+}
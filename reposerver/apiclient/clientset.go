@@ -68,6 +68,8 @@ func NewConnection(address string, timeoutSeconds int, tlsConfig *TLSConfigurati
 		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(MaxGRPCMessageSize), grpc.MaxCallSendMsgSize(MaxGRPCMessageSize)),
 		grpc.WithUnaryInterceptor(argogrpc.OTELUnaryClientInterceptor()),
 		grpc.WithStreamInterceptor(argogrpc.OTELStreamClientInterceptor()),
+		grpc.WithChainUnaryInterceptor(argogrpc.CorrelationIDUnaryClientInterceptor()),
+		grpc.WithChainStreamInterceptor(argogrpc.CorrelationIDStreamClientInterceptor()),
 	}
 
 	tlsC := &tls.Config{}
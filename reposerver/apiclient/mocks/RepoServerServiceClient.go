@@ -21,6 +21,43 @@ type RepoServerServiceClient struct {
 	mock.Mock
 }
 
+// ExportBundle provides a mock function with given fields: ctx, in, opts
+func (_m *RepoServerServiceClient) ExportBundle(ctx context.Context, in *apiclient.ExportBundleRequest, opts ...grpc.CallOption) (*apiclient.ExportBundleResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportBundle")
+	}
+
+	var r0 *apiclient.ExportBundleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *apiclient.ExportBundleRequest, ...grpc.CallOption) (*apiclient.ExportBundleResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *apiclient.ExportBundleRequest, ...grpc.CallOption) *apiclient.ExportBundleResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*apiclient.ExportBundleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *apiclient.ExportBundleRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GenerateManifest provides a mock function with given fields: ctx, in, opts
 func (_m *RepoServerServiceClient) GenerateManifest(ctx context.Context, in *apiclient.ManifestRequest, opts ...grpc.CallOption) (*apiclient.ManifestResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -243,6 +280,43 @@ func (_m *RepoServerServiceClient) GetHelmCharts(ctx context.Context, in *apicli
 	return r0, r1
 }
 
+// GetManifestBlame provides a mock function with given fields: ctx, in, opts
+func (_m *RepoServerServiceClient) GetManifestBlame(ctx context.Context, in *apiclient.ManifestBlameRequest, opts ...grpc.CallOption) (*apiclient.ManifestBlame, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetManifestBlame")
+	}
+
+	var r0 *apiclient.ManifestBlame
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *apiclient.ManifestBlameRequest, ...grpc.CallOption) (*apiclient.ManifestBlame, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *apiclient.ManifestBlameRequest, ...grpc.CallOption) *apiclient.ManifestBlame); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*apiclient.ManifestBlame)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *apiclient.ManifestBlameRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRevisionChartDetails provides a mock function with given fields: ctx, in, opts
 func (_m *RepoServerServiceClient) GetRevisionChartDetails(ctx context.Context, in *apiclient.RepoServerRevisionChartDetailsRequest, opts ...grpc.CallOption) (*v1alpha1.ChartDetails, error) {
 	_va := make([]interface{}, len(opts))
@@ -317,6 +391,43 @@ func (_m *RepoServerServiceClient) GetRevisionMetadata(ctx context.Context, in *
 	return r0, r1
 }
 
+// ImportBundle provides a mock function with given fields: ctx, in, opts
+func (_m *RepoServerServiceClient) ImportBundle(ctx context.Context, in *apiclient.ImportBundleRequest, opts ...grpc.CallOption) (*apiclient.ImportBundleResponse, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportBundle")
+	}
+
+	var r0 *apiclient.ImportBundleResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *apiclient.ImportBundleRequest, ...grpc.CallOption) (*apiclient.ImportBundleResponse, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *apiclient.ImportBundleRequest, ...grpc.CallOption) *apiclient.ImportBundleResponse); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*apiclient.ImportBundleResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *apiclient.ImportBundleRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListApps provides a mock function with given fields: ctx, in, opts
 func (_m *RepoServerServiceClient) ListApps(ctx context.Context, in *apiclient.ListAppsRequest, opts ...grpc.CallOption) (*apiclient.AppList, error) {
 	_va := make([]interface{}, len(opts))
@@ -354,6 +465,43 @@ func (_m *RepoServerServiceClient) ListApps(ctx context.Context, in *apiclient.L
 	return r0, r1
 }
 
+// ListCachedKeys provides a mock function with given fields: ctx, in, opts
+func (_m *RepoServerServiceClient) ListCachedKeys(ctx context.Context, in *apiclient.ListCachedKeysRequest, opts ...grpc.CallOption) (*apiclient.CachedKeyList, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCachedKeys")
+	}
+
+	var r0 *apiclient.CachedKeyList
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *apiclient.ListCachedKeysRequest, ...grpc.CallOption) (*apiclient.CachedKeyList, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *apiclient.ListCachedKeysRequest, ...grpc.CallOption) *apiclient.CachedKeyList); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*apiclient.CachedKeyList)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *apiclient.ListCachedKeysRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListPlugins provides a mock function with given fields: ctx, in, opts
 func (_m *RepoServerServiceClient) ListPlugins(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*apiclient.PluginList, error) {
 	_va := make([]interface{}, len(opts))
@@ -428,6 +576,43 @@ func (_m *RepoServerServiceClient) ListRefs(ctx context.Context, in *apiclient.L
 	return r0, r1
 }
 
+// ProfileManifestGeneration provides a mock function with given fields: ctx, in, opts
+func (_m *RepoServerServiceClient) ProfileManifestGeneration(ctx context.Context, in *apiclient.ProfileRequest, opts ...grpc.CallOption) (*apiclient.ProfileResult, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ProfileManifestGeneration")
+	}
+
+	var r0 *apiclient.ProfileResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *apiclient.ProfileRequest, ...grpc.CallOption) (*apiclient.ProfileResult, error)); ok {
+		return rf(ctx, in, opts...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *apiclient.ProfileRequest, ...grpc.CallOption) *apiclient.ProfileResult); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*apiclient.ProfileResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *apiclient.ProfileRequest, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ResolveRevision provides a mock function with given fields: ctx, in, opts
 func (_m *RepoServerServiceClient) ResolveRevision(ctx context.Context, in *apiclient.ResolveRevisionRequest, opts ...grpc.CallOption) (*apiclient.ResolveRevisionResponse, error) {
 	_va := make([]interface{}, len(opts))
@@ -1,7 +1,10 @@
 package cache
 
 import (
+	"context"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -44,6 +47,35 @@ type ClusterRuntimeInfo interface {
 	GetKubeVersion() string
 }
 
+// helmValuesFromInfo is optionally implemented by a ClusterRuntimeInfo holding Helm values read from the cluster
+type helmValuesFromInfo interface {
+	// GetHelmValuesFrom returns the Helm values read from the ConfigMaps and Secrets of the cluster
+	GetHelmValuesFrom() []string
+}
+
+// helmValueFilesSuffixInfo is optionally implemented by a ClusterRuntimeInfo holding the Helm value files suffix of the
+// cluster
+type helmValueFilesSuffixInfo interface {
+	// GetHelmValueFilesSuffix returns the suffix of the Helm value files selected by the labels of the cluster
+	GetHelmValueFilesSuffix() string
+}
+
+// destinationClusterNameInfo is optionally implemented by a ClusterRuntimeInfo holding the name of the cluster
+type destinationClusterNameInfo interface {
+	// GetDestinationClusterName returns the name of the cluster, which selects the Helm value files of sources with
+	// autoValuesFile
+	GetDestinationClusterName() string
+}
+
+// inTotoLayoutInfo is optionally implemented by a ClusterRuntimeInfo holding the in-toto layout verified before
+// generating the manifests
+type inTotoLayoutInfo interface {
+	// GetInTotoLayout returns the signed in-toto layout of the source
+	GetInTotoLayout() string
+	// GetInTotoLayoutKeys returns the public keys of the owners of the in-toto layout
+	GetInTotoLayoutKeys() []string
+}
+
 func NewCache(cache *cacheutil.Cache, repoCacheExpiration time.Duration, revisionCacheExpiration time.Duration, revisionCacheLockTimeout time.Duration) *Cache {
 	return &Cache{cache, repoCacheExpiration, revisionCacheExpiration, revisionCacheLockTimeout}
 }
@@ -138,7 +170,32 @@ func clusterRuntimeInfoKeyUnhashed(info ClusterRuntimeInfo) string {
 	sort.Slice(apiVersions, func(i, j int) bool {
 		return apiVersions[i] < apiVersions[j]
 	})
-	return info.GetKubeVersion() + "|" + strings.Join(apiVersions, ",")
+	key := info.GetKubeVersion() + "|" + strings.Join(apiVersions, ",")
+	// The Helm values read from the cluster may hold secrets, so only their hash is part of the key
+	if valuesInfo, ok := info.(helmValuesFromInfo); ok && len(valuesInfo.GetHelmValuesFrom()) > 0 {
+		h := sha256.New()
+		for _, values := range valuesInfo.GetHelmValuesFrom() {
+			_, _ = h.Write([]byte(values))
+			_, _ = h.Write([]byte{0})
+		}
+		key += "|" + hex.EncodeToString(h.Sum(nil))
+	}
+	if suffixInfo, ok := info.(helmValueFilesSuffixInfo); ok && suffixInfo.GetHelmValueFilesSuffix() != "" {
+		key += "|suffix=" + suffixInfo.GetHelmValueFilesSuffix()
+	}
+	if nameInfo, ok := info.(destinationClusterNameInfo); ok && nameInfo.GetDestinationClusterName() != "" {
+		key += "|cluster=" + nameInfo.GetDestinationClusterName()
+	}
+	if layoutInfo, ok := info.(inTotoLayoutInfo); ok && layoutInfo.GetInTotoLayout() != "" {
+		h := sha256.New()
+		_, _ = h.Write([]byte(layoutInfo.GetInTotoLayout()))
+		for _, key := range layoutInfo.GetInTotoLayoutKeys() {
+			_, _ = h.Write([]byte{0})
+			_, _ = h.Write([]byte(key))
+		}
+		key += "|in-toto=" + hex.EncodeToString(h.Sum(nil))
+	}
+	return key
 }
 
 func listApps(repoURL, revision string) string {
@@ -182,6 +239,36 @@ func (c *Cache) GetHelmIndex(repo string, indexData *[]byte) error {
 	return c.cache.GetItem(helmIndexRefsKey(repo), indexData)
 }
 
+func helmIndexETagKey(repo string) string {
+	return fmt.Sprintf("helm-index-etag|%s", repo)
+}
+
+func helmIndexByETagKey(repo string, etag string) string {
+	return fmt.Sprintf("helm-index|%s|%s", repo, etag)
+}
+
+// SetHelmIndexETag stores the helm repository index.yaml content served with the given ETag, and remembers the ETag
+// as the latest one of the repository
+func (c *Cache) SetHelmIndexETag(repo string, etag string, indexData []byte) error {
+	if indexData == nil {
+		// Logged as warning upstream
+		return fmt.Errorf("helm index data is nil, skipping cache")
+	}
+	err := c.cache.SetItem(helmIndexByETagKey(repo, etag), indexData, &cacheutil.CacheActionOpts{Expiration: c.repoCacheExpiration})
+	if err != nil {
+		return err
+	}
+	return c.cache.SetItem(helmIndexETagKey(repo), etag, &cacheutil.CacheActionOpts{Expiration: c.repoCacheExpiration})
+}
+
+// GetHelmIndexETag retrieves the latest ETag of the helm repository index.yaml and the content served with it
+func (c *Cache) GetHelmIndexETag(repo string, etag *string, indexData *[]byte) error {
+	if err := c.cache.GetItem(helmIndexETagKey(repo), etag); err != nil {
+		return err
+	}
+	return c.cache.GetItem(helmIndexByETagKey(repo, *etag), indexData)
+}
+
 func gitRefsKey(repo string) string {
 	return fmt.Sprintf("git-refs|%s", repo)
 }
@@ -478,6 +565,311 @@ func (c *Cache) GetGitDirectories(repoURL, revision string) ([]string, error) {
 	return item, c.cache.GetItem(gitDirectoriesKey(repoURL, revision), &item)
 }
 
+func crdSchemaKey(group, version, kind, hash string) string {
+	return fmt.Sprintf("crd-schema:%s/%s/%s:%s", group, version, kind, hash)
+}
+
+// SetCRDSchema stores the OpenAPI schema of a version of a CustomResourceDefinition, in JSON format, under the hash of
+// the schema
+func (c *Cache) SetCRDSchema(group, version, kind, hash string, schema []byte) error {
+	return c.cache.SetItem(
+		crdSchemaKey(group, version, kind, hash),
+		&schema,
+		&cacheutil.CacheActionOpts{Expiration: c.repoCacheExpiration})
+}
+
+// GetCRDSchema returns the OpenAPI schema of a version of a CustomResourceDefinition stored under the hash of the schema
+func (c *Cache) GetCRDSchema(group, version, kind, hash string) ([]byte, error) {
+	var item []byte
+	return item, c.cache.GetItem(crdSchemaKey(group, version, kind, hash), &item)
+}
+
+func kustomizeBaseKey(repoURL, revision, basePath string) string {
+	return fmt.Sprintf("kustomize-base:%s:%s:%s", repoURL, revision, basePath)
+}
+
+// SetKustomizeBase stores the manifests rendered by `kustomize build` of a base, at a path of a revision of a
+// repository, so that the overlays sharing the base do not render it again
+func (c *Cache) SetKustomizeBase(repoURL, revision, basePath string, manifests []byte) error {
+	return c.cache.SetItem(
+		kustomizeBaseKey(repoURL, revision, basePath),
+		&manifests,
+		&cacheutil.CacheActionOpts{Expiration: c.repoCacheExpiration})
+}
+
+// GetKustomizeBase returns the rendered manifests of a kustomize base at a path of a revision of a repository
+func (c *Cache) GetKustomizeBase(repoURL, revision, basePath string) ([]byte, error) {
+	var item []byte
+	return item, c.cache.GetItem(kustomizeBaseKey(repoURL, revision, basePath), &item)
+}
+
+func helmDependenciesKey(chartHash string) string {
+	return fmt.Sprintf("helm-deps:%s", chartHash)
+}
+
+// SetHelmDependencies stores the files of the charts/ directory populated by `helm dependency update`, by their path
+// relative to the directory, under the hash of the chart declaring the dependencies
+func (c *Cache) SetHelmDependencies(chartHash string, files map[string][]byte) error {
+	return c.cache.SetItem(
+		helmDependenciesKey(chartHash),
+		&files,
+		&cacheutil.CacheActionOpts{Expiration: c.repoCacheExpiration})
+}
+
+// GetHelmDependencies returns the files of the charts/ directory of the chart with the given hash
+func (c *Cache) GetHelmDependencies(chartHash string) (map[string][]byte, error) {
+	var item map[string][]byte
+	return item, c.cache.GetItem(helmDependenciesKey(chartHash), &item)
+}
+
+func httpSourceKey(url, sha256 string) string {
+	return fmt.Sprintf("httpsource|%s:%s", url, sha256)
+}
+
+// SetHTTPSource stores the manifests fetched from the URL of an HTTP source under the expected SHA256 digest of the
+// source, which is empty if the source does not pin its manifests
+func (c *Cache) SetHTTPSource(url, sha256 string, manifests []byte, expiration time.Duration) error {
+	return c.cache.SetItem(
+		httpSourceKey(url, sha256),
+		&manifests,
+		&cacheutil.CacheActionOpts{Expiration: expiration})
+}
+
+// GetHTTPSource returns the manifests fetched from the URL of an HTTP source with the given expected SHA256 digest
+func (c *Cache) GetHTTPSource(url, sha256 string) ([]byte, error) {
+	var item []byte
+	return item, c.cache.GetItem(httpSourceKey(url, sha256), &item)
+}
+
+// ClusterAPIVersionsExpiration is the time the API versions of a destination cluster are cached
+const ClusterAPIVersionsExpiration = time.Hour
+
+func clusterAPIVersionsKey(server string) string {
+	return fmt.Sprintf("apiversions|%s", server)
+}
+
+// SetClusterAPIVersions stores the API versions served by the destination cluster with the given URL
+func (c *Cache) SetClusterAPIVersions(server string, apiVersions []string) error {
+	return c.cache.SetItem(
+		clusterAPIVersionsKey(server),
+		&apiVersions,
+		&cacheutil.CacheActionOpts{Expiration: ClusterAPIVersionsExpiration})
+}
+
+// GetClusterAPIVersions returns the API versions served by the destination cluster with the given URL
+func (c *Cache) GetClusterAPIVersions(server string) ([]string, error) {
+	var item []string
+	return item, c.cache.GetItem(clusterAPIVersionsKey(server), &item)
+}
+
+func pulumiPreviewKey(hash string) string {
+	return fmt.Sprintf("pulumi|%s", hash)
+}
+
+// SetPulumiPreview stores the manifests previewed from a Pulumi program under the hash of the program and of the
+// options of its source
+func (c *Cache) SetPulumiPreview(hash string, manifests []byte) error {
+	return c.cache.SetItem(
+		pulumiPreviewKey(hash),
+		&manifests,
+		&cacheutil.CacheActionOpts{Expiration: c.repoCacheExpiration})
+}
+
+// GetPulumiPreview returns the manifests previewed from the Pulumi program with the given hash
+func (c *Cache) GetPulumiPreview(hash string) ([]byte, error) {
+	var item []byte
+	return item, c.cache.GetItem(pulumiPreviewKey(hash), &item)
+}
+
+func timoniBuildKey(hash string) string {
+	return fmt.Sprintf("timoni|%s", hash)
+}
+
+// SetTimoniBuild stores the manifests built from a Timoni module under the hash of the module and of the values of its
+// instance
+func (c *Cache) SetTimoniBuild(hash string, manifests []byte) error {
+	return c.cache.SetItem(
+		timoniBuildKey(hash),
+		&manifests,
+		&cacheutil.CacheActionOpts{Expiration: c.repoCacheExpiration})
+}
+
+// GetTimoniBuild returns the manifests built from the Timoni module with the given hash
+func (c *Cache) GetTimoniBuild(hash string) ([]byte, error) {
+	var item []byte
+	return item, c.cache.GetItem(timoniBuildKey(hash), &item)
+}
+
+func ociSourceDigestKey(image, tag string) string {
+	return fmt.Sprintf("ocitag|%s:%s", image, tag)
+}
+
+// SetOCISourceDigest stores the digest of the artifact the tag of an OCI source resolved to, the tag is resolved again
+// once it expires so that new versions of the artifact are detected
+func (c *Cache) SetOCISourceDigest(image, tag, digest string, expiration time.Duration) error {
+	return c.cache.SetItem(
+		ociSourceDigestKey(image, tag),
+		&digest,
+		&cacheutil.CacheActionOpts{Expiration: expiration})
+}
+
+// GetOCISourceDigest returns the digest of the artifact the tag of an OCI source resolved to
+func (c *Cache) GetOCISourceDigest(image, tag string) (string, error) {
+	var item string
+	return item, c.cache.GetItem(ociSourceDigestKey(image, tag), &item)
+}
+
+func ociSourceManifestsKey(image, digest string) string {
+	return fmt.Sprintf("ocisource|%s@%s", image, digest)
+}
+
+// SetOCISourceManifests stores the manifests extracted from the artifact of an OCI source with the given digest
+func (c *Cache) SetOCISourceManifests(image, digest string, manifests []byte) error {
+	return c.cache.SetItem(
+		ociSourceManifestsKey(image, digest),
+		&manifests,
+		&cacheutil.CacheActionOpts{Expiration: c.repoCacheExpiration})
+}
+
+// GetOCISourceManifests returns the manifests extracted from the artifact of an OCI source with the given digest
+func (c *Cache) GetOCISourceManifests(image, digest string) ([]byte, error) {
+	var item []byte
+	return item, c.cache.GetItem(ociSourceManifestsKey(image, digest), &item)
+}
+
+func kustomizeOCIBaseKey(image, digest string) string {
+	return fmt.Sprintf("ocikustomize|%s@%s", image, digest)
+}
+
+// SetKustomizeOCIBase stores the files extracted from the artifact of a Kustomize OCI base with the given digest, by
+// their path in the artifact
+func (c *Cache) SetKustomizeOCIBase(image, digest string, files map[string][]byte) error {
+	return c.cache.SetItem(
+		kustomizeOCIBaseKey(image, digest),
+		&files,
+		&cacheutil.CacheActionOpts{Expiration: c.repoCacheExpiration})
+}
+
+// GetKustomizeOCIBase returns the files extracted from the artifact of a Kustomize OCI base with the given digest
+func (c *Cache) GetKustomizeOCIBase(image, digest string) (map[string][]byte, error) {
+	var item map[string][]byte
+	return item, c.cache.GetItem(kustomizeOCIBaseKey(image, digest), &item)
+}
+
+// manifestGenerationProfileExpiration is the time the profiles of manifest generations are kept in the cache
+const manifestGenerationProfileExpiration = time.Hour
+
+func manifestGenerationProfileKey(appName string, profiledAt time.Time) string {
+	return fmt.Sprintf("mfstprofile|%s|%d", appName, profiledAt.Unix())
+}
+
+// SetManifestGenerationProfile stores the profile of a manifest generation of the application for an hour, and
+// returns the key it is stored under
+func (c *Cache) SetManifestGenerationProfile(appName string, profiledAt time.Time, profile *apiclient.ProfileResult) (string, error) {
+	key := manifestGenerationProfileKey(appName, profiledAt)
+	return key, c.cache.SetItem(
+		key,
+		profile,
+		&cacheutil.CacheActionOpts{Expiration: manifestGenerationProfileExpiration})
+}
+
+// GetManifestGenerationProfile returns the profile of a manifest generation of the application
+func (c *Cache) GetManifestGenerationProfile(appName string, profiledAt time.Time) (*apiclient.ProfileResult, error) {
+	var item apiclient.ProfileResult
+	return &item, c.cache.GetItem(manifestGenerationProfileKey(appName, profiledAt), &item)
+}
+
+func manifestBlameKey(repoURL, revision, path string) string {
+	return fmt.Sprintf("mfstblame|%s|%s|%s", repoURL, revision, path)
+}
+
+// SetManifestBlame stores the commits which last modified the manifest files of an application source at a revision
+func (c *Cache) SetManifestBlame(repoURL, revision, path string, blame *apiclient.ManifestBlame) error {
+	return c.cache.SetItem(
+		manifestBlameKey(repoURL, revision, path),
+		blame,
+		&cacheutil.CacheActionOpts{Expiration: c.repoCacheExpiration})
+}
+
+// GetManifestBlame returns the commits which last modified the manifest files of an application source at a revision
+func (c *Cache) GetManifestBlame(repoURL, revision, path string) (*apiclient.ManifestBlame, error) {
+	var item apiclient.ManifestBlame
+	return &item, c.cache.GetItem(manifestBlameKey(repoURL, revision, path), &item)
+}
+
+const (
+	CachedKeyTypeManifest   = "manifest"
+	CachedKeyTypeAppDetails = "app-details"
+	CachedKeyTypeAppList    = "app-list"
+	CachedKeyTypeRevision   = "revision"
+	CachedKeyTypeIndex      = "index"
+	CachedKeyTypeGitFiles   = "git-files"
+	CachedKeyTypeHTTPSource = "http-source"
+	CachedKeyTypePulumi     = "pulumi-preview"
+	CachedKeyTypeTimoni     = "timoni-build"
+	CachedKeyTypeAPIVersion = "api-versions"
+	CachedKeyTypeOCISource  = "oci-source"
+	CachedKeyTypeProfile    = "profile"
+	CachedKeyTypeBlame      = "manifest-blame"
+)
+
+// cachedKeyTypes maps the prefixes of the keys stored by the repo server to the type of the cached data
+var cachedKeyTypes = map[string]string{
+	"mfst":             CachedKeyTypeManifest,
+	"appdetails":       CachedKeyTypeAppDetails,
+	"ldir":             CachedKeyTypeAppList,
+	"git-refs":         CachedKeyTypeRevision,
+	"revisionmetadata": CachedKeyTypeRevision,
+	"chartdetails":     CachedKeyTypeRevision,
+	"helm-index":       CachedKeyTypeIndex,
+	"helm-index-etag":  CachedKeyTypeIndex,
+	"gitfiles":         CachedKeyTypeGitFiles,
+	"gitdirs":          CachedKeyTypeGitFiles,
+	"httpsource":       CachedKeyTypeHTTPSource,
+	"pulumi":           CachedKeyTypePulumi,
+	"timoni":           CachedKeyTypeTimoni,
+	"apiversions":      CachedKeyTypeAPIVersion,
+	"ocitag":           CachedKeyTypeOCISource,
+	"ocisource":        CachedKeyTypeOCISource,
+	"ocikustomize":     CachedKeyTypeOCISource,
+	"mfstprofile":      CachedKeyTypeProfile,
+	"mfstblame":        CachedKeyTypeBlame,
+}
+
+// CachedKey describes a key stored by the repo server in the cache
+type CachedKey struct {
+	cacheutil.KeyInfo
+	// Type is the type of the cached data, parsed from the key
+	Type string
+}
+
+// cachedKeyType returns the type of the data cached under the given key, or an empty string if the key is not one of
+// the repo server
+func cachedKeyType(key string) string {
+	prefix, _, found := strings.Cut(key, "|")
+	if !found {
+		return ""
+	}
+	return cachedKeyTypes[prefix]
+}
+
+// ListCachedKeys returns a page of the keys stored by the repo server, and the cursor to pass to retrieve the next
+// page. Keys stored by the other components sharing the cache are skipped, so a page may contain fewer keys than
+// requested, or none at all, while the returned cursor is not 0.
+func (c *Cache) ListCachedKeys(ctx context.Context, cursor uint64, count int64) ([]CachedKey, uint64, error) {
+	infos, next, err := c.cache.ListKeys(ctx, cursor, count)
+	if err != nil {
+		return nil, 0, err
+	}
+	var keys []CachedKey
+	for _, info := range infos {
+		if keyType := cachedKeyType(info.Key); keyType != "" {
+			keys = append(keys, CachedKey{KeyInfo: info, Type: keyType})
+		}
+	}
+	return keys, next, nil
+}
+
 func (cmr *CachedManifestResponse) shallowCopy() *CachedManifestResponse {
 	if cmr == nil {
 		return nil
@@ -1,9 +1,11 @@
 package cache
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -13,6 +15,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/argoproj/argo-cd/v2/common"
 	. "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
@@ -607,6 +610,23 @@ func TestSetHelmIndex(t *testing.T) {
 	})
 }
 
+func TestHelmIndexETag(t *testing.T) {
+	fixtures := newFixtures()
+	t.Cleanup(fixtures.mockCache.StopRedisCallback)
+	var etag string
+	var indexData []byte
+	err := fixtures.cache.GetHelmIndexETag("test-repo", &etag, &indexData)
+	require.ErrorIs(t, err, ErrCacheMiss)
+
+	require.NoError(t, fixtures.cache.SetHelmIndexETag("test-repo", `"v1"`, []byte("v1-data")))
+	require.NoError(t, fixtures.cache.SetHelmIndexETag("test-repo", `"v2"`, []byte("v2-data")))
+	require.NoError(t, fixtures.cache.GetHelmIndexETag("test-repo", &etag, &indexData))
+	assert.Equal(t, `"v2"`, etag)
+	assert.Equal(t, []byte("v2-data"), indexData)
+
+	require.Error(t, fixtures.cache.SetHelmIndexETag("test-repo", `"v3"`, nil), "nil data should not be cached")
+}
+
 func TestRevisionChartDetails(t *testing.T) {
 	t.Run("GetRevisionChartDetails cache miss", func(t *testing.T) {
 		fixtures := newFixtures()
@@ -764,3 +784,208 @@ func TestGetGitFiles(t *testing.T) {
 		fixtures.mockCache.AssertCacheCalledTimes(t, &mocks.CacheCallCounts{ExternalGets: 1, ExternalSets: 1})
 	})
 }
+
+func TestCache_GetHTTPSource(t *testing.T) {
+	fixtures := newFixtures()
+	t.Cleanup(fixtures.mockCache.StopRedisCallback)
+	cache := fixtures.cache
+	// cache miss
+	_, err := cache.GetHTTPSource("https://example.com/install.yaml", "")
+	assert.Equal(t, ErrCacheMiss, err)
+	err = cache.SetHTTPSource("https://example.com/install.yaml", "", []byte("kind: ConfigMap"), time.Minute)
+	require.NoError(t, err)
+	// cache miss
+	_, err = cache.GetHTTPSource("https://example.com/install.yaml", "abc")
+	assert.Equal(t, ErrCacheMiss, err)
+	// cache hit
+	manifests, err := cache.GetHTTPSource("https://example.com/install.yaml", "")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("kind: ConfigMap"), manifests)
+}
+
+func TestCache_GetOCISource(t *testing.T) {
+	fixtures := newFixtures()
+	t.Cleanup(fixtures.mockCache.StopRedisCallback)
+	cache := fixtures.cache
+	// cache miss
+	_, err := cache.GetOCISourceDigest("ghcr.io/argoproj/manifests", "latest")
+	assert.Equal(t, ErrCacheMiss, err)
+	err = cache.SetOCISourceDigest("ghcr.io/argoproj/manifests", "latest", "sha256:abc", time.Minute)
+	require.NoError(t, err)
+	// cache hit
+	digest, err := cache.GetOCISourceDigest("ghcr.io/argoproj/manifests", "latest")
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:abc", digest)
+
+	// cache miss
+	_, err = cache.GetOCISourceManifests("ghcr.io/argoproj/manifests", "sha256:abc")
+	assert.Equal(t, ErrCacheMiss, err)
+	err = cache.SetOCISourceManifests("ghcr.io/argoproj/manifests", "sha256:abc", []byte("kind: ConfigMap"))
+	require.NoError(t, err)
+	// cache miss
+	_, err = cache.GetOCISourceManifests("ghcr.io/argoproj/other", "sha256:abc")
+	assert.Equal(t, ErrCacheMiss, err)
+	// cache hit
+	manifests, err := cache.GetOCISourceManifests("ghcr.io/argoproj/manifests", "sha256:abc")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("kind: ConfigMap"), manifests)
+}
+
+func TestCache_GetManifestGenerationProfile(t *testing.T) {
+	fixtures := newFixtures()
+	t.Cleanup(fixtures.mockCache.StopRedisCallback)
+	cache := fixtures.cache
+	profiledAt := time.Unix(1700000000, 0)
+	// cache miss
+	_, err := cache.GetManifestGenerationProfile("guestbook", profiledAt)
+	assert.Equal(t, ErrCacheMiss, err)
+	key, err := cache.SetManifestGenerationProfile("guestbook", profiledAt, &apiclient.ProfileResult{DurationMs: 1500, PeakMemoryBytes: 1024, CpuProfile: []byte("profile")})
+	require.NoError(t, err)
+	assert.Equal(t, "mfstprofile|guestbook|1700000000", key)
+	assert.Equal(t, CachedKeyTypeProfile, cachedKeyType(key))
+	// cache miss
+	_, err = cache.GetManifestGenerationProfile("guestbook", profiledAt.Add(time.Second))
+	assert.Equal(t, ErrCacheMiss, err)
+	// cache hit
+	profile, err := cache.GetManifestGenerationProfile("guestbook", profiledAt)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1500), profile.DurationMs)
+	assert.Equal(t, []byte("profile"), profile.CpuProfile)
+}
+
+func TestCache_GetManifestBlame(t *testing.T) {
+	fixtures := newFixtures()
+	t.Cleanup(fixtures.mockCache.StopRedisCallback)
+	cache := fixtures.cache
+	// cache miss
+	_, err := cache.GetManifestBlame("my-repo-url", "my-revision", "guestbook")
+	assert.Equal(t, ErrCacheMiss, err)
+	err = cache.SetManifestBlame("my-repo-url", "my-revision", "guestbook", &apiclient.ManifestBlame{
+		Revision: "my-revision",
+		Files:    []*apiclient.ManifestFileBlame{{FilePath: "cm.yaml", LastCommitAuthor: "Alice <alice@example.com>", LastCommitTime: 1700000000}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, CachedKeyTypeBlame, cachedKeyType(manifestBlameKey("my-repo-url", "my-revision", "guestbook")))
+	// cache miss
+	_, err = cache.GetManifestBlame("my-repo-url", "other-revision", "guestbook")
+	assert.Equal(t, ErrCacheMiss, err)
+	// cache hit
+	blame, err := cache.GetManifestBlame("my-repo-url", "my-revision", "guestbook")
+	require.NoError(t, err)
+	require.Len(t, blame.Files, 1)
+	assert.Equal(t, "cm.yaml", blame.Files[0].FilePath)
+	assert.Equal(t, int64(1700000000), blame.Files[0].LastCommitTime)
+}
+
+func TestListCachedKeys(t *testing.T) {
+	redisClient, stopRedis := mocks.NewInMemoryRedis()
+	t.Cleanup(stopRedis)
+	baseCache := cacheutil.NewCache(cacheutil.NewRedisCache(redisClient, time.Hour, cacheutil.RedisCompressionNone))
+	cache := NewCache(baseCache, time.Minute, 2*time.Minute, 10*time.Second)
+
+	require.NoError(t, cache.SetApps("repo", "rev", map[string]string{"app": "Kustomize"}))
+	require.NoError(t, cache.SetHelmIndex("repo", []byte("index")))
+	require.NoError(t, cache.SetRevisionMetadata("repo", "rev", &RevisionMetadata{Message: "message"}))
+	require.NoError(t, cache.SetGitReferences("repo", []*plumbing.Reference{plumbing.NewHashReference("refs/heads/main", plumbing.NewHash("abc"))}))
+	require.NoError(t, cache.SetGitFiles("repo", "rev", "*.yaml", map[string][]byte{"a.yaml": []byte("a")}))
+	require.NoError(t, cache.SetManifests("rev", &ApplicationSource{}, nil, &ClusterInfo{}, "ns", "", "label", "app", &CachedManifestResponse{ManifestResponse: &apiclient.ManifestResponse{}}, nil))
+	// keys of other components and of other cache versions are not listed
+	require.NoError(t, baseCache.SetItem("app|resources-tree|app", "tree", nil))
+	require.NoError(t, redisClient.Set(context.Background(), "ldir|repo|rev|0.0.1", "old", 0).Err())
+
+	keys := map[string]CachedKey{}
+	var cursor uint64
+	pages := 0
+	for {
+		page, next, err := cache.ListCachedKeys(context.Background(), cursor, 2)
+		require.NoError(t, err)
+		for _, key := range page {
+			keys[key.Key] = key
+		}
+		pages++
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+	assert.Greater(t, pages, 1)
+
+	types := map[string]string{}
+	for name, key := range keys {
+		types[strings.SplitN(name, "|", 2)[0]] = key.Type
+		assert.Positive(t, key.SizeBytes, name)
+	}
+	assert.Equal(t, map[string]string{
+		"ldir":             CachedKeyTypeAppList,
+		"helm-index":       CachedKeyTypeIndex,
+		"revisionmetadata": CachedKeyTypeRevision,
+		"git-refs":         CachedKeyTypeRevision,
+		"gitfiles":         CachedKeyTypeGitFiles,
+		"mfst":             CachedKeyTypeManifest,
+	}, types)
+
+	appsKey := keys["ldir|repo|rev|"+common.CacheVersion]
+	assert.Greater(t, appsKey.TTL, time.Duration(0))
+	assert.LessOrEqual(t, appsKey.TTL, time.Minute)
+	refsKey := keys["git-refs|repo|"+common.CacheVersion]
+	assert.Greater(t, refsKey.TTL, time.Minute)
+}
+
+func TestListCachedKeys_UnsupportedClient(t *testing.T) {
+	cache := NewCache(cacheutil.NewCache(cacheutil.NewInMemoryCache(time.Minute)), time.Minute, time.Minute, 10*time.Second)
+	_, _, err := cache.ListCachedKeys(context.Background(), 0, 10)
+	require.ErrorContains(t, err, "does not support listing keys")
+}
+
+func TestClusterRuntimeInfoKeyUnhashed_HelmValuesFrom(t *testing.T) {
+	q := &apiclient.ManifestRequest{KubeVersion: "1.28", ApiVersions: []string{"v1"}}
+	assert.Equal(t, "1.28|v1", clusterRuntimeInfoKeyUnhashed(q))
+
+	q.HelmValuesFrom = []string{"password: secret"}
+	key := clusterRuntimeInfoKeyUnhashed(q)
+	assert.NotContains(t, key, "secret")
+	assert.NotEqual(t, clusterRuntimeInfoKey(&apiclient.ManifestRequest{KubeVersion: "1.28", ApiVersions: []string{"v1"}}), clusterRuntimeInfoKey(q))
+
+	q.HelmValuesFrom = []string{"password: other"}
+	assert.NotEqual(t, key, clusterRuntimeInfoKeyUnhashed(q))
+}
+
+func TestClusterRuntimeInfoKeyUnhashed_HelmValueFilesSuffix(t *testing.T) {
+	q := &apiclient.ManifestRequest{KubeVersion: "1.28", ApiVersions: []string{"v1"}, HelmValueFilesSuffix: "us-east-1"}
+	assert.Equal(t, "1.28|v1|suffix=us-east-1", clusterRuntimeInfoKeyUnhashed(q))
+	assert.NotEqual(t, clusterRuntimeInfoKey(&apiclient.ManifestRequest{KubeVersion: "1.28", ApiVersions: []string{"v1"}}), clusterRuntimeInfoKey(q))
+}
+
+func TestClusterRuntimeInfoKeyUnhashed_DestinationClusterName(t *testing.T) {
+	q := &apiclient.ManifestRequest{KubeVersion: "1.28", ApiVersions: []string{"v1"}, DestinationClusterName: "prod"}
+	assert.Equal(t, "1.28|v1|cluster=prod", clusterRuntimeInfoKeyUnhashed(q))
+	assert.NotEqual(t, clusterRuntimeInfoKey(&apiclient.ManifestRequest{KubeVersion: "1.28", ApiVersions: []string{"v1"}}), clusterRuntimeInfoKey(q))
+}
+
+func TestClusterRuntimeInfoKeyUnhashed_InTotoLayout(t *testing.T) {
+	q := &apiclient.ManifestRequest{KubeVersion: "1.28", ApiVersions: []string{"v1"}, InTotoLayout: "layout", InTotoLayoutKeys: []string{"key"}}
+	key := clusterRuntimeInfoKeyUnhashed(q)
+	assert.Contains(t, key, "|in-toto=")
+	assert.NotEqual(t, clusterRuntimeInfoKey(&apiclient.ManifestRequest{KubeVersion: "1.28", ApiVersions: []string{"v1"}}), clusterRuntimeInfoKey(q))
+
+	q.InTotoLayoutKeys = []string{"other"}
+	assert.NotEqual(t, key, clusterRuntimeInfoKeyUnhashed(q))
+}
+
+func TestCache_GetClusterAPIVersions(t *testing.T) {
+	fixtures := newFixtures()
+	t.Cleanup(fixtures.mockCache.StopRedisCallback)
+	cache := fixtures.cache
+	// cache miss
+	_, err := cache.GetClusterAPIVersions("https://kubernetes.default.svc")
+	assert.Equal(t, ErrCacheMiss, err)
+	err = cache.SetClusterAPIVersions("https://kubernetes.default.svc", []string{"apps/v1beta2", "apps/v1beta2/Deployment"})
+	require.NoError(t, err)
+	// cache miss
+	_, err = cache.GetClusterAPIVersions("https://other")
+	assert.Equal(t, ErrCacheMiss, err)
+	// cache hit
+	apiVersions, err := cache.GetClusterAPIVersions("https://kubernetes.default.svc")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"apps/v1beta2", "apps/v1beta2/Deployment"}, apiVersions)
+}
@@ -47,5 +47,8 @@ func NewGitClientEventHandlers(metricsServer *MetricsServer) git.EventHandlers {
 				metricsServer.ObserveGitRequestDuration(repo, GitRequestTypeLsRemote, time.Since(startTime))
 			}
 		},
+		OnShallowDeepen: func(repo string) {
+			metricsServer.IncGitShallowDeepen(repo)
+		},
 	}
 }
@@ -11,14 +11,19 @@ import (
 )
 
 type MetricsServer struct {
-	handler                  http.Handler
-	gitFetchFailCounter      *prometheus.CounterVec
-	gitLsRemoteFailCounter   *prometheus.CounterVec
-	gitRequestCounter        *prometheus.CounterVec
-	gitRequestHistogram      *prometheus.HistogramVec
-	repoPendingRequestsGauge *prometheus.GaugeVec
-	redisRequestCounter      *prometheus.CounterVec
-	redisRequestHistogram    *prometheus.HistogramVec
+	handler                    http.Handler
+	gitFetchFailCounter        *prometheus.CounterVec
+	gitLsRemoteFailCounter     *prometheus.CounterVec
+	gitRequestCounter          *prometheus.CounterVec
+	gitRequestHistogram        *prometheus.HistogramVec
+	gitShallowDeepenCounter    *prometheus.CounterVec
+	repoPendingRequestsGauge   *prometheus.GaugeVec
+	redisRequestCounter        *prometheus.CounterVec
+	redisRequestHistogram      *prometheus.HistogramVec
+	kustomizeBaseCacheCounter  *prometheus.CounterVec
+	apiVersionRewriteCounter   *prometheus.CounterVec
+	generationTimeoutCounter   *prometheus.CounterVec
+	generationCoalescedCounter prometheus.Counter
 }
 
 type GitRequestType string
@@ -71,6 +76,15 @@ func NewMetricsServer() *MetricsServer {
 	)
 	registry.MustRegister(gitRequestHistogram)
 
+	gitShallowDeepenCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_git_shallow_deepen_total",
+			Help: "Number of times shallow clones were deepened to find a revision by repo server",
+		},
+		[]string{"repo"},
+	)
+	registry.MustRegister(gitShallowDeepenCounter)
+
 	repoPendingRequestsGauge := prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
 			Name: "argocd_repo_pending_request_total",
@@ -99,15 +113,55 @@ func NewMetricsServer() *MetricsServer {
 	)
 	registry.MustRegister(redisRequestHistogram)
 
+	kustomizeBaseCacheCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_kustomize_base_cache_requests_total",
+			Help: "Number of lookups of rendered Kustomize bases in the cache by repo server",
+		},
+		[]string{"result"},
+	)
+	registry.MustRegister(kustomizeBaseCacheCounter)
+
+	apiVersionRewriteCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_resource_api_version_rewritten_total",
+			Help: "Number of generated resources whose API version was rewritten to a version served by the destination cluster",
+		},
+		[]string{"group", "kind", "from", "to"},
+	)
+	registry.MustRegister(apiVersionRewriteCounter)
+
+	generationTimeoutCounter := prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "argocd_manifest_generation_timeout_total",
+			Help: "Number of manifest generations which did not complete within the timeout of their generator",
+		},
+		[]string{"generator"},
+	)
+	registry.MustRegister(generationTimeoutCounter)
+
+	generationCoalescedCounter := prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "argocd_manifest_generation_coalesced_total",
+			Help: "Number of manifest requests which received the result of a concurrent generation of an identical request",
+		},
+	)
+	registry.MustRegister(generationCoalescedCounter)
+
 	return &MetricsServer{
-		handler:                  promhttp.HandlerFor(registry, promhttp.HandlerOpts{}),
-		gitFetchFailCounter:      gitFetchFailCounter,
-		gitLsRemoteFailCounter:   gitLsRemoteFailCounter,
-		gitRequestCounter:        gitRequestCounter,
-		gitRequestHistogram:      gitRequestHistogram,
-		repoPendingRequestsGauge: repoPendingRequestsGauge,
-		redisRequestCounter:      redisRequestCounter,
-		redisRequestHistogram:    redisRequestHistogram,
+		handler:                    promhttp.HandlerFor(registry, promhttp.HandlerOpts{}),
+		gitFetchFailCounter:        gitFetchFailCounter,
+		gitLsRemoteFailCounter:     gitLsRemoteFailCounter,
+		gitRequestCounter:          gitRequestCounter,
+		gitRequestHistogram:        gitRequestHistogram,
+		gitShallowDeepenCounter:    gitShallowDeepenCounter,
+		repoPendingRequestsGauge:   repoPendingRequestsGauge,
+		redisRequestCounter:        redisRequestCounter,
+		redisRequestHistogram:      redisRequestHistogram,
+		kustomizeBaseCacheCounter:  kustomizeBaseCacheCounter,
+		apiVersionRewriteCounter:   apiVersionRewriteCounter,
+		generationTimeoutCounter:   generationTimeoutCounter,
+		generationCoalescedCounter: generationCoalescedCounter,
 	}
 }
 
@@ -128,6 +182,11 @@ func (m *MetricsServer) IncGitRequest(repo string, requestType GitRequestType) {
 	m.gitRequestCounter.WithLabelValues(repo, string(requestType)).Inc()
 }
 
+// IncGitShallowDeepen increments the shallow clone deepen counter
+func (m *MetricsServer) IncGitShallowDeepen(repo string) {
+	m.gitShallowDeepenCounter.WithLabelValues(repo).Inc()
+}
+
 func (m *MetricsServer) IncPendingRepoRequest(repo string) {
 	m.repoPendingRequestsGauge.WithLabelValues(repo).Inc()
 }
@@ -147,3 +206,30 @@ func (m *MetricsServer) IncRedisRequest(failed bool) {
 func (m *MetricsServer) ObserveRedisRequestDuration(duration time.Duration) {
 	m.redisRequestHistogram.WithLabelValues("argocd-repo-server").Observe(duration.Seconds())
 }
+
+// IncKustomizeBaseCacheRequest counts a lookup of a rendered Kustomize base in the cache, by whether it was a hit
+func (m *MetricsServer) IncKustomizeBaseCacheRequest(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.kustomizeBaseCacheCounter.WithLabelValues(result).Inc()
+}
+
+// IncResourceAPIVersionRewritten counts a generated resource of the given group and kind whose API version was
+// rewritten from a version which is not served by the destination cluster to one which is
+func (m *MetricsServer) IncResourceAPIVersionRewritten(group, kind, from, to string) {
+	m.apiVersionRewriteCounter.WithLabelValues(group, kind, from, to).Inc()
+}
+
+// IncManifestGenerationTimeout counts a manifest generation by the given generator which did not complete within its
+// timeout
+func (m *MetricsServer) IncManifestGenerationTimeout(generator string) {
+	m.generationTimeoutCounter.WithLabelValues(generator).Inc()
+}
+
+// IncManifestGenerationCoalesced counts a manifest request which received the result of a concurrent generation of an
+// identical request instead of generating the manifests itself
+func (m *MetricsServer) IncManifestGenerationCoalesced() {
+	m.generationCoalescedCounter.Inc()
+}
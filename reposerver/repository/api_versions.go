@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/reposerver/cache"
+	"github.com/argoproj/argo-cd/v2/util/grpc"
+)
+
+var (
+	builtinKindVersionsOnce sync.Once
+	// builtinKindVersions are the versions of the built-in Kubernetes kinds, highest first
+	builtinKindVersions map[schema.GroupKind][]string
+)
+
+// getBuiltinKindVersions returns the versions each built-in Kubernetes kind is served in, highest first, as registered
+// in the scheme of client-go
+func getBuiltinKindVersions() map[schema.GroupKind][]string {
+	builtinKindVersionsOnce.Do(func() {
+		builtinKindVersions = map[schema.GroupKind][]string{}
+		for gvk := range scheme.Scheme.AllKnownTypes() {
+			// lists and options are not resources
+			if gvk.Version == "__internal" || strings.HasSuffix(gvk.Kind, "List") || strings.HasSuffix(gvk.Kind, "Options") {
+				continue
+			}
+			builtinKindVersions[gvk.GroupKind()] = append(builtinKindVersions[gvk.GroupKind()], gvk.Version)
+		}
+		for _, versions := range builtinKindVersions {
+			sort.Slice(versions, func(i, j int) bool {
+				return version.CompareKubeAwareVersionStrings(versions[i], versions[j]) > 0
+			})
+		}
+	})
+	return builtinKindVersions
+}
+
+// apiVersionRewrite is the rewrite of the API version of a resource to a version served by the destination cluster
+type apiVersionRewrite struct {
+	GroupKind schema.GroupKind
+	From      string
+	To        string
+}
+
+// clusterAPIVersions are the API versions served by a cluster, formatted as `<group>/<version>` and
+// `<group>/<version>/<kind>` like the apiVersions of a manifest request
+type clusterAPIVersions struct {
+	served map[string]bool
+	// hasKinds is true if the served kinds are known, otherwise a kind is assumed to be served in all the served
+	// versions of its group
+	hasKinds bool
+}
+
+func newClusterAPIVersions(apiVersions []string) *clusterAPIVersions {
+	versions := &clusterAPIVersions{served: map[string]bool{}}
+	for _, apiVersion := range apiVersions {
+		versions.served[apiVersion] = true
+		parts := strings.Split(apiVersion, "/")
+		// kinds start with an upper case letter, unlike groups and versions
+		if last := parts[len(parts)-1]; last != "" && last[0] >= 'A' && last[0] <= 'Z' {
+			versions.hasKinds = true
+		}
+	}
+	return versions
+}
+
+func (v *clusterAPIVersions) serves(gvk schema.GroupVersionKind) bool {
+	if v.hasKinds {
+		return v.served[gvk.GroupVersion().String()+"/"+gvk.Kind]
+	}
+	return v.served[gvk.GroupVersion().String()]
+}
+
+// negotiateAPIVersion rewrites the API version of a built-in resource which is not served by the cluster to the
+// highest version of its kind served by the cluster. Custom resources, and resources whose kind is not served in any
+// version, are left unchanged so that the sync reports them.
+func negotiateAPIVersion(obj *unstructured.Unstructured, versions *clusterAPIVersions) (*apiVersionRewrite, bool) {
+	gvk := obj.GroupVersionKind()
+	if gvk.Version == "" || gvk.Kind == "" || versions.serves(gvk) || !scheme.Scheme.Recognizes(gvk) {
+		return nil, false
+	}
+	for _, candidate := range getBuiltinKindVersions()[gvk.GroupKind()] {
+		target := gvk.GroupKind().WithVersion(candidate)
+		if candidate == gvk.Version || !versions.serves(target) {
+			continue
+		}
+		obj.SetAPIVersion(target.GroupVersion().String())
+		return &apiVersionRewrite{GroupKind: gvk.GroupKind(), From: gvk.Version, To: candidate}, true
+	}
+	return nil, false
+}
+
+// negotiateAPIVersions rewrites the API versions of the manifests which are not served by the destination cluster of
+// the request, and returns the rewritten manifests together with the rewrites
+func negotiateAPIVersions(manifests []string, apiVersions []string) ([]string, []apiVersionRewrite, error) {
+	if len(apiVersions) == 0 {
+		return manifests, nil, nil
+	}
+	versions := newClusterAPIVersions(apiVersions)
+	var rewrites []apiVersionRewrite
+	for i, manifest := range manifests {
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal([]byte(manifest), obj); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal manifest: %w", err)
+		}
+		rewrite, ok := negotiateAPIVersion(obj, versions)
+		if !ok {
+			continue
+		}
+		data, err := json.Marshal(obj.Object)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal manifest: %w", err)
+		}
+		manifests[i] = string(data)
+		rewrites = append(rewrites, *rewrite)
+	}
+	return manifests, rewrites, nil
+}
+
+// resolveClusterAPIVersions caches the API versions of the destination cluster of the request, and sets them on
+// requests which do not provide them from the cache
+func (s *Service) resolveClusterAPIVersions(ctx context.Context, q *apiclient.ManifestRequest) {
+	if q.DestinationServer == "" {
+		return
+	}
+	logCtx := grpc.LoggerFromContext(ctx).WithField("server", q.DestinationServer)
+	cached, err := s.cache.GetClusterAPIVersions(q.DestinationServer)
+	if err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+		logCtx.Warnf("Failed to get API versions of cluster from cache: %v", err)
+	}
+	if len(q.ApiVersions) == 0 {
+		q.ApiVersions = cached
+		return
+	}
+	if strings.Join(cached, ",") == strings.Join(q.ApiVersions, ",") {
+		return
+	}
+	if err := s.cache.SetClusterAPIVersions(q.DestinationServer, q.ApiVersions); err != nil {
+		logCtx.Warnf("Failed to cache API versions of cluster: %v", err)
+	}
+}
+
+// negotiateManifestAPIVersions rewrites the API versions of the generated manifests which are not served by the
+// destination cluster of the request
+func (s *Service) negotiateManifestAPIVersions(ctx context.Context, q *apiclient.ManifestRequest, res *apiclient.ManifestResponse) error {
+	manifests, rewrites, err := negotiateAPIVersions(res.Manifests, q.ApiVersions)
+	if err != nil {
+		return err
+	}
+	res.Manifests = manifests
+	for _, rewrite := range rewrites {
+		grpc.LoggerFromContext(ctx).WithField("application", q.AppName).Debugf("Rewrote API version of %s from %s to %s", rewrite.GroupKind, rewrite.From, rewrite.To)
+		if s.metricsServer != nil {
+			s.metricsServer.IncResourceAPIVersionRewritten(rewrite.GroupKind.Group, rewrite.GroupKind.Kind, rewrite.From, rewrite.To)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/reposerver/cache"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+)
+
+// olderClusterAPIVersions are the API versions advertised by a cluster which does not serve apps/v1 yet
+var olderClusterAPIVersions = []string{
+	"v1",
+	"v1/ConfigMap",
+	"apps/v1beta1",
+	"apps/v1beta1/Deployment",
+	"apps/v1beta2",
+	"apps/v1beta2/Deployment",
+	"apps/v1beta2/DaemonSet",
+}
+
+func Test_negotiateAPIVersions(t *testing.T) {
+	deployment := `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"guestbook"}}`
+	daemonSet := `{"apiVersion":"apps/v1","kind":"DaemonSet","metadata":{"name":"guestbook"}}`
+	configMap := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"guestbook"}}`
+	statefulSet := `{"apiVersion":"apps/v1","kind":"StatefulSet","metadata":{"name":"guestbook"}}`
+	rollout := `{"apiVersion":"argoproj.io/v1alpha1","kind":"Rollout","metadata":{"name":"guestbook"}}`
+
+	t.Run("Resources are rewritten to the highest version served by the cluster", func(t *testing.T) {
+		manifests, rewrites, err := negotiateAPIVersions([]string{deployment, daemonSet, configMap}, olderClusterAPIVersions)
+		require.NoError(t, err)
+		assert.Contains(t, manifests[0], `"apiVersion":"apps/v1beta2"`)
+		assert.Contains(t, manifests[1], `"apiVersion":"apps/v1beta2"`)
+		assert.Equal(t, configMap, manifests[2])
+		assert.Equal(t, []apiVersionRewrite{
+			{GroupKind: schema.GroupKind{Group: "apps", Kind: "Deployment"}, From: "v1", To: "v1beta2"},
+			{GroupKind: schema.GroupKind{Group: "apps", Kind: "DaemonSet"}, From: "v1", To: "v1beta2"},
+		}, rewrites)
+	})
+	t.Run("Kinds not served in any version are left unchanged", func(t *testing.T) {
+		manifests, rewrites, err := negotiateAPIVersions([]string{statefulSet, rollout}, olderClusterAPIVersions)
+		require.NoError(t, err)
+		assert.Equal(t, []string{statefulSet, rollout}, manifests)
+		assert.Empty(t, rewrites)
+	})
+	t.Run("Kinds are assumed to be served in all the versions of their group", func(t *testing.T) {
+		manifests, rewrites, err := negotiateAPIVersions([]string{statefulSet}, []string{"v1", "apps/v1beta1", "apps/v1beta2"})
+		require.NoError(t, err)
+		assert.Contains(t, manifests[0], `"apiVersion":"apps/v1beta2"`)
+		assert.Len(t, rewrites, 1)
+	})
+	t.Run("Resources served by the cluster are left unchanged", func(t *testing.T) {
+		manifests, rewrites, err := negotiateAPIVersions([]string{deployment}, []string{"apps/v1", "apps/v1/Deployment", "apps/v1beta2/Deployment"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{deployment}, manifests)
+		assert.Empty(t, rewrites)
+	})
+	t.Run("Manifests are left unchanged without the API versions of the cluster", func(t *testing.T) {
+		manifests, rewrites, err := negotiateAPIVersions([]string{deployment}, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{deployment}, manifests)
+		assert.Empty(t, rewrites)
+	})
+	t.Run("Invalid manifests fail", func(t *testing.T) {
+		_, _, err := negotiateAPIVersions([]string{"{"}, olderClusterAPIVersions)
+		require.ErrorContains(t, err, "failed to unmarshal manifest")
+	})
+}
+
+func TestGenerateManifest_NegotiateAPIVersions(t *testing.T) {
+	service, _, cacheMocks := newServiceWithMocks(t, ".", false)
+	source := &argoappv1.ApplicationSource{
+		RepoURL: argoappv1.InlineSourceRepoURL,
+		Inline: &argoappv1.ApplicationSourceInline{Manifests: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: guestbook
+`},
+	}
+	newRequest := func(apiVersions []string) *apiclient.ManifestRequest {
+		return &apiclient.ManifestRequest{
+			Repo:              &argoappv1.Repository{Repo: argoappv1.InlineSourceRepoURL},
+			ApplicationSource: source,
+			AppLabelKey:       common.LabelKeyAppInstance,
+			AppName:           "guestbook",
+			TrackingMethod:    string(argo.TrackingMethodLabel),
+			ApiVersions:       apiVersions,
+			DestinationServer: "https://old-cluster",
+		}
+	}
+
+	res, err := service.GenerateManifest(context.Background(), newRequest(olderClusterAPIVersions))
+	require.NoError(t, err)
+	require.Len(t, res.Manifests, 1)
+	assert.Contains(t, res.Manifests[0], `"apiVersion":"apps/v1beta2"`)
+
+	cached, err := cacheMocks.cache.GetClusterAPIVersions("https://old-cluster")
+	require.NoError(t, err)
+	assert.Equal(t, olderClusterAPIVersions, cached)
+
+	// requests without API versions use the cached API versions of the cluster
+	res, err = service.GenerateManifest(context.Background(), newRequest(nil))
+	require.NoError(t, err)
+	assert.Contains(t, res.Manifests[0], `"apiVersion":"apps/v1beta2"`)
+
+	_, err = cacheMocks.cache.GetClusterAPIVersions("https://other-cluster")
+	assert.Equal(t, cache.ErrCacheMiss, err)
+}
@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+
+	"github.com/argoproj/argo-cd/v2/reposerver/cache"
+)
+
+// FieldViolation is a field of a custom resource which does not match the schema of its CustomResourceDefinition
+type FieldViolation struct {
+	// Path is the path of the field in the custom resource, e.g. spec.replicas
+	Path string
+	// Message describes how the field does not match the schema
+	Message string
+}
+
+func (v FieldViolation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return fmt.Sprintf("%s %s", v.Path, v.Message)
+}
+
+// ResourceSchemaViolation lists the fields of a custom resource which do not match the schema of its
+// CustomResourceDefinition
+type ResourceSchemaViolation struct {
+	GroupVersionKind schema.GroupVersionKind
+	Namespace        string
+	Name             string
+	Fields           []FieldViolation
+}
+
+// SchemaViolation is the error returned when custom resources do not match the schemas of the
+// CustomResourceDefinitions generated alongside them
+type SchemaViolation struct {
+	Resources []ResourceSchemaViolation
+}
+
+func (e *SchemaViolation) Error() string {
+	resources := make([]string, 0, len(e.Resources))
+	for _, resource := range e.Resources {
+		fields := make([]string, 0, len(resource.Fields))
+		for _, field := range resource.Fields {
+			fields = append(fields, field.String())
+		}
+		name := resource.Name
+		if resource.Namespace != "" {
+			name = resource.Namespace + "/" + name
+		}
+		resources = append(resources, fmt.Sprintf("%s %s: %s", resource.GroupVersionKind.GroupKind(), name, strings.Join(fields, ", ")))
+	}
+	return fmt.Sprintf("SchemaViolation: custom resources do not match the schema of their CustomResourceDefinition: %s", strings.Join(resources, "; "))
+}
+
+// isCRD returns true if the object is a CustomResourceDefinition
+func isCRD(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	return gvk.Group == "apiextensions.k8s.io" && gvk.Kind == "CustomResourceDefinition"
+}
+
+// extractCRDSchemas returns the OpenAPI schemas of the versions of the CustomResourceDefinitions among the objects, and
+// stores them in the cache if it is not nil
+func extractCRDSchemas(objs []*unstructured.Unstructured, schemaCache *cache.Cache) (map[schema.GroupVersionKind]*spec.Schema, error) {
+	schemas := map[schema.GroupVersionKind]*spec.Schema{}
+	for _, obj := range objs {
+		if obj == nil || !isCRD(obj) {
+			continue
+		}
+		group, _, _ := unstructured.NestedString(obj.Object, "spec", "group")
+		kind, _, _ := unstructured.NestedString(obj.Object, "spec", "names", "kind")
+		versions, _, err := unstructured.NestedSlice(obj.Object, "spec", "versions")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the versions of CustomResourceDefinition %s: %w", obj.GetName(), err)
+		}
+		for _, v := range versions {
+			version, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(version, "name")
+			openAPISchema, ok, err := unstructured.NestedMap(version, "schema", "openAPIV3Schema")
+			if err != nil || !ok {
+				continue
+			}
+			data, err := json.Marshal(openAPISchema)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal the schema of CustomResourceDefinition %s version %s: %w", obj.GetName(), name, err)
+			}
+			s := &spec.Schema{}
+			if err := json.Unmarshal(data, s); err != nil {
+				return nil, fmt.Errorf("invalid schema of CustomResourceDefinition %s version %s: %w", obj.GetName(), name, err)
+			}
+			schemas[schema.GroupVersionKind{Group: group, Version: name, Kind: kind}] = s
+			if schemaCache != nil {
+				hash := sha256.Sum256(data)
+				if err := schemaCache.SetCRDSchema(group, name, kind, hex.EncodeToString(hash[:]), data); err != nil {
+					log.Warnf("Failed to cache the schema of CustomResourceDefinition %s version %s: %v", obj.GetName(), name, err)
+				}
+			}
+		}
+	}
+	return schemas, nil
+}
+
+// fieldViolations flattens the errors returned by the schema validator into field violations
+func fieldViolations(errs []error) []FieldViolation {
+	var violations []FieldViolation
+	for _, err := range errs {
+		switch e := err.(type) {
+		case *errors.CompositeError:
+			violations = append(violations, fieldViolations(e.Errors)...)
+		case *errors.Validation:
+			violations = append(violations, FieldViolation{Path: e.Name, Message: strings.TrimPrefix(e.Error(), e.Name+" in body ")})
+		default:
+			violations = append(violations, FieldViolation{Message: err.Error()})
+		}
+	}
+	return violations
+}
+
+// validateCustomResources validates the custom resources among the objects against the OpenAPI schemas of the
+// CustomResourceDefinitions among the objects, and returns a SchemaViolation error listing the fields which do not
+// match. The custom resources of other CustomResourceDefinitions are not validated.
+func validateCustomResources(objs []*unstructured.Unstructured, schemaCache *cache.Cache) error {
+	schemas, err := extractCRDSchemas(objs, schemaCache)
+	if err != nil {
+		return err
+	}
+	if len(schemas) == 0 {
+		return nil
+	}
+	var violations []ResourceSchemaViolation
+	for _, obj := range objs {
+		if obj == nil {
+			continue
+		}
+		s, ok := schemas[obj.GroupVersionKind()]
+		if !ok {
+			continue
+		}
+		result := validate.NewSchemaValidator(s, nil, "", strfmt.Default).Validate(obj.Object)
+		if result.IsValid() {
+			continue
+		}
+		violations = append(violations, ResourceSchemaViolation{
+			GroupVersionKind: obj.GroupVersionKind(),
+			Namespace:        obj.GetNamespace(),
+			Name:             obj.GetName(),
+			Fields:           fieldViolations(result.Errors),
+		})
+	}
+	if len(violations) > 0 {
+		return &SchemaViolation{Resources: violations}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/reposerver/cache"
+	cacheutil "github.com/argoproj/argo-cd/v2/util/cache"
+)
+
+const widgetCRD = `
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: widgets.example.com
+spec:
+  group: example.com
+  names:
+    kind: Widget
+  versions:
+    - name: v1
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              type: object
+              required:
+                - replicas
+              properties:
+                replicas:
+                  type: integer
+                  minimum: 1
+`
+
+func unstructuredFromYAML(t *testing.T, manifest string) *unstructured.Unstructured {
+	t.Helper()
+	obj := &unstructured.Unstructured{}
+	require.NoError(t, yaml.Unmarshal([]byte(manifest), &obj.Object))
+	return obj
+}
+
+func TestValidateCustomResources(t *testing.T) {
+	crd := unstructuredFromYAML(t, widgetCRD)
+	valid := unstructuredFromYAML(t, `{"apiVersion": "example.com/v1", "kind": "Widget", "metadata": {"name": "valid"}, "spec": {"replicas": 2}}`)
+	missing := unstructuredFromYAML(t, `{"apiVersion": "example.com/v1", "kind": "Widget", "metadata": {"name": "missing", "namespace": "default"}, "spec": {}}`)
+	other := unstructuredFromYAML(t, `{"apiVersion": "example.com/v2", "kind": "Widget", "metadata": {"name": "other"}, "spec": {}}`)
+
+	require.NoError(t, validateCustomResources([]*unstructured.Unstructured{crd, valid, other}, nil))
+
+	err := validateCustomResources([]*unstructured.Unstructured{crd, valid, missing}, nil)
+	var violation *SchemaViolation
+	require.True(t, errors.As(err, &violation))
+	require.Len(t, violation.Resources, 1)
+	assert.Equal(t, "missing", violation.Resources[0].Name)
+	assert.Equal(t, "default", violation.Resources[0].Namespace)
+	require.Len(t, violation.Resources[0].Fields, 1)
+	assert.Equal(t, "spec.replicas", violation.Resources[0].Fields[0].Path)
+	assert.Contains(t, err.Error(), "Widget.example.com default/missing: spec.replicas is required")
+
+	// custom resources are not validated without the definition
+	require.NoError(t, validateCustomResources([]*unstructured.Unstructured{missing}, nil))
+}
+
+func TestValidateCustomResources_CachesSchemas(t *testing.T) {
+	schemaCache := cache.NewCache(cacheutil.NewCache(cacheutil.NewInMemoryCache(time.Minute)), time.Minute, time.Minute, 10*time.Second)
+	crd := unstructuredFromYAML(t, widgetCRD)
+	require.NoError(t, validateCustomResources([]*unstructured.Unstructured{crd}, schemaCache))
+
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	require.NoError(t, err)
+	data, err := json.Marshal(versions[0].(map[string]interface{})["schema"].(map[string]interface{})["openAPIV3Schema"])
+	require.NoError(t, err)
+	hash := sha256.Sum256(data)
+
+	cached, err := schemaCache.GetCRDSchema("example.com", "v1", "Widget", hex.EncodeToString(hash[:]))
+	require.NoError(t, err)
+	assert.JSONEq(t, string(data), string(cached))
+}
+
+func TestGenerateManifest_HelmValidateCRs(t *testing.T) {
+	service := newService(t, "./testdata")
+	q := apiclient.ManifestRequest{
+		Repo:    &argoappv1.Repository{},
+		AppName: "test",
+		ApplicationSource: &argoappv1.ApplicationSource{
+			Path: "./helm-crd-validation",
+			Helm: &argoappv1.ApplicationSourceHelm{ValidateCRs: true},
+		},
+		ProjectName:        "something",
+		ProjectSourceRepos: []string{"*"},
+	}
+	_, err := service.GenerateManifest(context.Background(), &q)
+	require.ErrorContains(t, err, "SchemaViolation")
+	assert.ErrorContains(t, err, "spec.replicas is required")
+
+	q.ApplicationSource.Helm.Parameters = []argoappv1.HelmParameter{{Name: "replicas", Value: "2"}}
+	_, err = service.GenerateManifest(context.Background(), &q)
+	require.NoError(t, err)
+
+	q.ApplicationSource.Helm = &argoappv1.ApplicationSourceHelm{}
+	_, err = service.GenerateManifest(context.Background(), &q)
+	require.NoError(t, err)
+}
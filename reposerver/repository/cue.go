@@ -0,0 +1,169 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	executil "github.com/argoproj/argo-cd/v2/util/exec"
+	pathutil "github.com/argoproj/argo-cd/v2/util/io/path"
+)
+
+const defaultCueBinary = "cue"
+
+// cueVersionRegex matches the output of `cue version`, e.g. "cue version v0.9.2"
+var cueVersionRegex = regexp.MustCompile(`cue version (v?[0-9]+\.[0-9]+\.[0-9]+\S*)`)
+
+// CueGenerator renders the manifests of applications with the CUE command line tool
+type CueGenerator struct {
+	// binaryPath is the path of the cue binary, the binary is looked up in the PATH when empty
+	binaryPath string
+}
+
+// NewCueGenerator returns a generator running the cue binary at the given path, or the one found in the PATH if the
+// path is empty
+func NewCueGenerator(binaryPath string) *CueGenerator {
+	return &CueGenerator{binaryPath: binaryPath}
+}
+
+func (g *CueGenerator) getBinaryPath() string {
+	if g.binaryPath != "" {
+		return g.binaryPath
+	}
+	return defaultCueBinary
+}
+
+// command returns the cue command evaluating the entrypoint of the source with its tags, and the command to log
+func (g *CueGenerator) command(appPath, repoRoot string, source *v1alpha1.ApplicationSourceCue, subcommand string, args ...string) (*exec.Cmd, string, error) {
+	entrypoint := "."
+	if source != nil && source.Entrypoint != "" {
+		entrypoint = source.Entrypoint
+	}
+	resolved, err := pathutil.ResolveFileOrDirectoryPath(appPath, repoRoot, entrypoint)
+	if err != nil {
+		return nil, "", fmt.Errorf("error resolving cue entrypoint %q: %w", entrypoint, err)
+	}
+	if _, err := os.Stat(string(resolved)); err != nil {
+		if os.IsNotExist(err) {
+			return nil, "", fmt.Errorf("cue entrypoint %q does not exist", entrypoint)
+		}
+		return nil, "", fmt.Errorf("error reading cue entrypoint %q: %w", entrypoint, err)
+	}
+	args = append([]string{subcommand, string(resolved)}, args...)
+	if source != nil {
+		keys := make([]string, 0, len(source.Tags))
+		for k := range source.Tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			args = append(args, "--inject", fmt.Sprintf("%s=%s", k, source.Tags[k]))
+		}
+	}
+	cmd := exec.Command(g.getBinaryPath(), args...)
+	cmd.Dir = appPath
+	// modules are only loaded from the repository, never from a registry
+	cmd.Env = append(os.Environ(), "CUE_REGISTRY=none")
+	return cmd, strings.ReplaceAll(executil.GetCommandArgsToLog(cmd), repoRoot, "."), nil
+}
+
+// Validate checks that the configuration of the source evaluates without errors
+func (g *CueGenerator) Validate(appPath, repoRoot string, source *v1alpha1.ApplicationSourceCue) error {
+	cmd, _, err := g.command(appPath, repoRoot, source, "vet")
+	if err != nil {
+		return err
+	}
+	if _, err := executil.Run(cmd); err != nil {
+		return fmt.Errorf("invalid cue configuration: %w", err)
+	}
+	return nil
+}
+
+// Generate exports the configuration of the source as JSON and returns the manifests it contains, together with the
+// executed command
+func (g *CueGenerator) Generate(appPath, repoRoot string, source *v1alpha1.ApplicationSourceCue) ([]*unstructured.Unstructured, string, error) {
+	cmd, command, err := g.command(appPath, repoRoot, source, "export", "--out", "json")
+	if err != nil {
+		return nil, "", err
+	}
+	out, err := executil.Run(cmd)
+	if err != nil {
+		return nil, "", err
+	}
+	var value interface{}
+	if err := json.Unmarshal([]byte(out), &value); err != nil {
+		return nil, "", fmt.Errorf("failed to parse cue output: %w", err)
+	}
+	objs, err := collectCueManifests(value, "")
+	if err != nil {
+		return nil, "", err
+	}
+	return objs, command, nil
+}
+
+// collectCueManifests returns the manifests found in an exported CUE value. Objects with an apiVersion and a kind are
+// manifests, other objects and lists are searched for manifests in the order of their keys or elements
+func collectCueManifests(value interface{}, path string) ([]*unstructured.Unstructured, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		_, hasAPIVersion := v["apiVersion"]
+		_, hasKind := v["kind"]
+		if hasAPIVersion && hasKind {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal cue manifest at %q: %w", path, err)
+			}
+			obj := &unstructured.Unstructured{}
+			if err := obj.UnmarshalJSON(data); err != nil {
+				return nil, fmt.Errorf("invalid cue manifest at %q: %w", path, err)
+			}
+			return []*unstructured.Unstructured{obj}, nil
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var objs []*unstructured.Unstructured
+		for _, k := range keys {
+			child, err := collectCueManifests(v[k], strings.TrimPrefix(path+"."+k, "."))
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, child...)
+		}
+		return objs, nil
+	case []interface{}:
+		var objs []*unstructured.Unstructured
+		for i := range v {
+			child, err := collectCueManifests(v[i], fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return nil, err
+			}
+			objs = append(objs, child...)
+		}
+		return objs, nil
+	}
+	return nil, fmt.Errorf("cue value at %q is not a Kubernetes manifest", path)
+}
+
+// Version returns the version of the cue binary
+func (g *CueGenerator) Version() (*semver.Version, error) {
+	out, err := executil.Run(exec.Command(g.getBinaryPath(), "version"))
+	if err != nil {
+		return nil, fmt.Errorf("could not get cue version: %w", err)
+	}
+	matches := cueVersionRegex.FindStringSubmatch(out)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("expected string that includes cue version but got: '%s'", strings.TrimSpace(out))
+	}
+	return semver.NewVersion(matches[1])
+}
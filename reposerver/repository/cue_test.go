@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/util/git"
+)
+
+func fakeCueGenerator(t *testing.T) *CueGenerator {
+	t.Helper()
+	binaryPath, err := filepath.Abs("./testdata/cue/fake-cue")
+	require.NoError(t, err)
+	return NewCueGenerator(binaryPath)
+}
+
+func TestCueGenerator_Generate(t *testing.T) {
+	repoRoot, err := filepath.Abs("./testdata/cue")
+	require.NoError(t, err)
+	appPath := filepath.Join(repoRoot, "app")
+
+	objs, command, err := fakeCueGenerator(t).Generate(appPath, repoRoot, &argoappv1.ApplicationSourceCue{
+		Entrypoint: "main.cue",
+		Tags:       map[string]string{"replicas": "3", "environment": "prod"},
+	})
+	require.NoError(t, err)
+	require.Len(t, objs, 3)
+	assert.Equal(t, "ConfigMap", objs[0].GetKind())
+	assert.Equal(t, "Deployment", objs[1].GetKind())
+	assert.Equal(t, "Service", objs[2].GetKind())
+
+	data, _, err := unstructured.NestedStringMap(objs[0].Object, "data")
+	require.NoError(t, err)
+	assert.Equal(t, "export "+filepath.Join(appPath, "main.cue")+" --out json --inject environment=prod --inject replicas=3", data["args"])
+	assert.Equal(t, appPath, data["dir"])
+	assert.Equal(t, "none", data["registry"])
+	assert.Equal(t, "./fake-cue export ./app/main.cue --out json --inject environment=prod --inject replicas=3", command)
+}
+
+func TestCueGenerator_Generate_OutOfBoundsEntrypoint(t *testing.T) {
+	repoRoot, err := filepath.Abs("./testdata/cue")
+	require.NoError(t, err)
+
+	_, _, err = fakeCueGenerator(t).Generate(filepath.Join(repoRoot, "app"), repoRoot, &argoappv1.ApplicationSourceCue{
+		Entrypoint: "../../out-of-bounds.cue",
+	})
+	require.ErrorContains(t, err, "error resolving cue entrypoint")
+
+	_, _, err = fakeCueGenerator(t).Generate(filepath.Join(repoRoot, "app"), repoRoot, &argoappv1.ApplicationSourceCue{
+		Entrypoint: "missing.cue",
+	})
+	require.ErrorContains(t, err, `cue entrypoint "missing.cue" does not exist`)
+}
+
+func TestCueGenerator_Validate(t *testing.T) {
+	repoRoot, err := filepath.Abs("./testdata/cue")
+	require.NoError(t, err)
+
+	require.NoError(t, fakeCueGenerator(t).Validate(filepath.Join(repoRoot, "app"), repoRoot, nil))
+	err = fakeCueGenerator(t).Validate(filepath.Join(repoRoot, "invalid"), repoRoot, &argoappv1.ApplicationSourceCue{Entrypoint: "invalid.cue"})
+	require.ErrorContains(t, err, "invalid cue configuration")
+}
+
+func TestCollectCueManifests(t *testing.T) {
+	var value interface{}
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"b": [{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "svc"}}],
+		"a": {"deploy": {"apiVersion": "apps/v1", "kind": "Deployment", "metadata": {"name": "deploy"}}}
+	}`), &value))
+	objs, err := collectCueManifests(value, "")
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	assert.Equal(t, "deploy", objs[0].GetName())
+	assert.Equal(t, "svc", objs[1].GetName())
+
+	require.NoError(t, json.Unmarshal([]byte(`{"a": {"replicas": 3}}`), &value))
+	_, err = collectCueManifests(value, "")
+	require.ErrorContains(t, err, `cue value at "a.replicas" is not a Kubernetes manifest`)
+}
+
+func TestCueGenerator_Version(t *testing.T) {
+	version, err := fakeCueGenerator(t).Version()
+	require.NoError(t, err)
+	assert.Equal(t, "0.9.2", version.String())
+
+	_, err = NewCueGenerator("./testdata/cue/does-not-exist").Version()
+	require.Error(t, err)
+}
+
+func TestCueGenerator_Configuration(t *testing.T) {
+	if _, err := exec.LookPath(defaultCueBinary); err != nil {
+		t.Skip("cue is not installed")
+	}
+	repoRoot, err := filepath.Abs("./testdata/cue")
+	require.NoError(t, err)
+
+	objs, _, err := NewCueGenerator("").Generate(filepath.Join(repoRoot, "app"), repoRoot, &argoappv1.ApplicationSourceCue{
+		Tags: map[string]string{"environment": "prod", "replicas": "3"},
+	})
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	assert.Equal(t, "Deployment", objs[0].GetKind())
+	assert.Equal(t, "guestbook-prod", objs[0].GetName())
+	replicas, _, err := unstructured.NestedInt64(objs[0].Object, "spec", "replicas")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), replicas)
+	assert.Equal(t, "Service", objs[1].GetKind())
+	assert.Equal(t, "guestbook-prod", objs[1].GetName())
+
+	err = NewCueGenerator("").Validate(filepath.Join(repoRoot, "invalid"), repoRoot, &argoappv1.ApplicationSourceCue{Entrypoint: "invalid.cue"})
+	require.ErrorContains(t, err, "invalid cue configuration")
+}
+
+func TestGenerateManifests_Cue(t *testing.T) {
+	q := apiclient.ManifestRequest{
+		Repo: &argoappv1.Repository{},
+		ApplicationSource: &argoappv1.ApplicationSource{
+			Cue: &argoappv1.ApplicationSourceCue{Tags: map[string]string{"environment": "prod"}},
+		},
+		ProjectName:        "something",
+		ProjectSourceRepos: []string{"*"},
+	}
+
+	res, err := GenerateManifests(context.Background(), "./testdata/cue/app", "./testdata/cue", "", &q, false, &git.NoopCredsStore{}, resource.MustParse("0"), nil, WithCueBinaryPath(fakeCueGenerator(t).binaryPath))
+	require.NoError(t, err)
+	assert.Equal(t, string(argoappv1.ApplicationSourceTypeCue), res.SourceType)
+	require.Len(t, res.Manifests, 3)
+	var obj unstructured.Unstructured
+	require.NoError(t, json.Unmarshal([]byte(res.Manifests[1]), &obj.Object))
+	assert.Equal(t, "Deployment", obj.GetKind())
+	assert.Len(t, res.Commands, 1)
+}
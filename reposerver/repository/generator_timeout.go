@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/metrics"
+)
+
+// GeneratorTimeouts are the maximum durations of the manifest generations by type of generator, zero disables the
+// timeout of a generator
+type GeneratorTimeouts struct {
+	Helm      time.Duration
+	Kustomize time.Duration
+	// Jsonnet limits the evaluation of the programs run by the repo server itself, the Jsonnet files of directory
+	// sources and the Starlark scripts
+	Jsonnet time.Duration
+	Plugin  time.Duration
+}
+
+// forSourceType returns the name of the generator of the source type, and its timeout
+func (t GeneratorTimeouts) forSourceType(sourceType v1alpha1.ApplicationSourceType) (string, time.Duration) {
+	switch sourceType {
+	case v1alpha1.ApplicationSourceTypeHelm:
+		return "helm", t.Helm
+	case v1alpha1.ApplicationSourceTypeKustomize:
+		return "kustomize", t.Kustomize
+	case v1alpha1.ApplicationSourceTypeDirectory:
+		return "jsonnet", t.Jsonnet
+	case v1alpha1.ApplicationSourceTypeStarlark:
+		return "starlark", t.Jsonnet
+	case v1alpha1.ApplicationSourceTypePlugin:
+		return "plugin", t.Plugin
+	}
+	return "", 0
+}
+
+// GenerationTimedOutError is returned when a manifest generator does not complete within its timeout
+type GenerationTimedOutError struct {
+	Generator string
+	Timeout   time.Duration
+}
+
+func (e *GenerationTimedOutError) Error() string {
+	return fmt.Sprintf("GenerationTimedOut: %s manifest generation did not complete within %v", e.Generator, e.Timeout)
+}
+
+// GRPCStatus returns the error as a DeadlineExceeded gRPC status
+func (e *GenerationTimedOutError) GRPCStatus() *status.Status {
+	return status.New(codes.DeadlineExceeded, e.Error())
+}
+
+// runGenerator runs the generation with a context canceled once the timeout of the generator is exceeded, and returns a
+// GenerationTimedOutError if it does not complete by then. Generators running subprocesses kill them once the timeout
+// is exceeded, while the evaluations which cannot be interrupted are abandoned.
+func runGenerator(ctx context.Context, generator string, timeout time.Duration, metricsServer *metrics.MetricsServer, generate func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return generate(ctx)
+	}
+	generateCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	// the timeout of the generator is exceeded, rather than a deadline of the request
+	timedOut := func() bool {
+		return generateCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- generate(generateCtx)
+	}()
+	select {
+	case err := <-done:
+		if err == nil || !timedOut() {
+			return err
+		}
+	case <-generateCtx.Done():
+		if !timedOut() {
+			return generateCtx.Err()
+		}
+	}
+	if metricsServer != nil {
+		metricsServer.IncManifestGenerationTimeout(generator)
+	}
+	return &GenerationTimedOutError{Generator: generator, Timeout: timeout}
+}
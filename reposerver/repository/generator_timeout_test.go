@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/reposerver/metrics"
+	"github.com/argoproj/argo-cd/v2/util/git"
+)
+
+func Test_runGenerator(t *testing.T) {
+	t.Run("Generation completing within the timeout", func(t *testing.T) {
+		err := runGenerator(context.Background(), "helm", time.Minute, metrics.NewMetricsServer(), func(ctx context.Context) error {
+			return nil
+		})
+		require.NoError(t, err)
+	})
+	t.Run("Errors of the generation are returned", func(t *testing.T) {
+		err := runGenerator(context.Background(), "helm", time.Minute, nil, func(ctx context.Context) error {
+			return errors.New("invalid chart")
+		})
+		require.EqualError(t, err, "invalid chart")
+	})
+	t.Run("Generation stopped once the timeout is exceeded", func(t *testing.T) {
+		err := runGenerator(context.Background(), "plugin", 10*time.Millisecond, metrics.NewMetricsServer(), func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		var timedOut *GenerationTimedOutError
+		require.ErrorAs(t, err, &timedOut)
+		assert.Equal(t, "plugin", timedOut.Generator)
+		assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+		assert.Equal(t, "GenerationTimedOut: plugin manifest generation did not complete within 10ms", err.Error())
+	})
+	t.Run("Generation which cannot be interrupted is abandoned", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+		err := runGenerator(context.Background(), "jsonnet", 10*time.Millisecond, nil, func(ctx context.Context) error {
+			<-release
+			return nil
+		})
+		var timedOut *GenerationTimedOutError
+		require.ErrorAs(t, err, &timedOut)
+	})
+	t.Run("Canceled requests do not time out", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err := runGenerator(ctx, "helm", time.Minute, nil, func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		})
+		require.ErrorIs(t, err, context.Canceled)
+	})
+	t.Run("Generation without timeout", func(t *testing.T) {
+		called := false
+		err := runGenerator(context.Background(), "", 0, nil, func(ctx context.Context) error {
+			called = true
+			_, hasDeadline := ctx.Deadline()
+			assert.False(t, hasDeadline)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.True(t, called)
+	})
+}
+
+func TestGenerateManifests_GeneratorTimeouts(t *testing.T) {
+	t.Run("Starlark script looping forever", func(t *testing.T) {
+		appPath, repoRoot := starlarkTestPaths(t)
+		q := apiclient.ManifestRequest{
+			Repo: &argoappv1.Repository{},
+			ApplicationSource: &argoappv1.ApplicationSource{
+				Starlark: &argoappv1.ApplicationSourceStarlark{Entrypoint: "loop.star"},
+			},
+		}
+		start := time.Now()
+		_, err := GenerateManifests(context.Background(), appPath, repoRoot, "", &q, false, &git.NoopCredsStore{}, resource.MustParse("0"), nil,
+			WithGeneratorTimeouts(GeneratorTimeouts{Jsonnet: 100 * time.Millisecond}, metrics.NewMetricsServer()))
+		var timedOut *GenerationTimedOutError
+		require.ErrorAs(t, err, &timedOut)
+		assert.Equal(t, "starlark", timedOut.Generator)
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+	t.Run("Helm hanging forever is killed", func(t *testing.T) {
+		binDir := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(binDir, "helm"), []byte("#!/bin/sh\nsleep 60\n"), 0o755))
+		t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+		appPath, err := filepath.Abs("./testdata/my-chart")
+		require.NoError(t, err)
+		q := apiclient.ManifestRequest{
+			Repo: &argoappv1.Repository{},
+			ApplicationSource: &argoappv1.ApplicationSource{
+				Helm: &argoappv1.ApplicationSourceHelm{},
+			},
+		}
+		start := time.Now()
+		_, err = GenerateManifests(context.Background(), appPath, appPath, "", &q, false, &git.NoopCredsStore{}, resource.MustParse("0"), nil,
+			WithGeneratorTimeouts(GeneratorTimeouts{Helm: 100 * time.Millisecond}, metrics.NewMetricsServer()), WithParseWorkerPool(NewParseWorkerPool(1)))
+		var timedOut *GenerationTimedOutError
+		require.ErrorAs(t, err, &timedOut)
+		assert.Equal(t, "helm", timedOut.Generator)
+		assert.Less(t, time.Since(start), 10*time.Second)
+	})
+}
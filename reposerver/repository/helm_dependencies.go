@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-cd/v2/reposerver/cache"
+	"github.com/argoproj/argo-cd/v2/util/helm"
+)
+
+const (
+	// helmChartsDir is the directory of a chart holding its dependencies
+	helmChartsDir = "charts"
+	// helmLocalRepositoryPrefix prefixes the repository of the dependencies stored in a local directory
+	helmLocalRepositoryPrefix = "file://"
+)
+
+// helmDependencyUpdater fetches the dependencies of the Helm charts which were not committed, before their manifests
+// are generated, and caches them by the hash of the charts so that they are only downloaded once
+type helmDependencyUpdater struct {
+	// cache stores the dependencies by the hash of the charts, the dependencies are always downloaded when nil
+	cache *cache.Cache
+	// timeout is the maximum duration of `helm dependency update`
+	timeout time.Duration
+}
+
+// readHelmDependencies returns the dependencies declared by the Chart.yaml of the chart
+func readHelmDependencies(appPath string) ([]repositories, error) {
+	data, err := os.ReadFile(filepath.Join(appPath, "Chart.yaml"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading helm chart from %s: %w", filepath.Join(appPath, "Chart.yaml"), err)
+	}
+	d := &dependencies{}
+	if err = yaml.Unmarshal(data, d); err != nil {
+		return nil, fmt.Errorf("error unmarshalling the helm chart while getting helm dependencies: %w", err)
+	}
+	return d.Dependencies, nil
+}
+
+// isHelmChartsDirEmpty returns whether the charts/ directory of the chart is missing or empty
+func isHelmChartsDirEmpty(appPath string) (bool, error) {
+	entries, err := os.ReadDir(filepath.Join(appPath, helmChartsDir))
+	if errors.Is(err, fs.ErrNotExist) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error reading helm charts directory: %w", err)
+	}
+	return len(entries) == 0, nil
+}
+
+// walkHelmDir calls fn with the path, relative to the directory, and the content of each file of a directory
+func walkHelmDir(dir string, fn func(relPath string, data []byte)) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		fn(relPath, data)
+		return nil
+	})
+}
+
+// helmChartHash returns the hash of the files which determine the dependencies of the chart: its Chart.yaml and
+// Chart.lock, and the files of its local dependencies
+func helmChartHash(appPath string, deps []repositories) (string, error) {
+	h := sha256.New()
+	hash := func(data ...[]byte) {
+		for _, d := range data {
+			_, _ = h.Write([]byte(fmt.Sprintf("%d:", len(d))))
+			_, _ = h.Write(d)
+		}
+	}
+	for _, name := range []string{"Chart.yaml", "Chart.lock"} {
+		data, err := os.ReadFile(filepath.Join(appPath, name))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("error reading %s: %w", name, err)
+		}
+		hash([]byte(name), data)
+	}
+	for _, dep := range deps {
+		if !strings.HasPrefix(dep.Repository, helmLocalRepositoryPrefix) {
+			continue
+		}
+		localPath := strings.TrimPrefix(dep.Repository, helmLocalRepositoryPrefix)
+		hash([]byte(dep.Repository))
+		err := walkHelmDir(filepath.Join(appPath, localPath), func(relPath string, data []byte) {
+			hash([]byte(relPath), data)
+		})
+		if err != nil {
+			return "", fmt.Errorf("error hashing local dependency %s: %w", dep.Repository, err)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// readHelmChartsDir returns the files of the charts/ directory of the chart, by their path relative to the directory
+func readHelmChartsDir(appPath string) (map[string][]byte, error) {
+	chartsDir := filepath.Join(appPath, helmChartsDir)
+	files := map[string][]byte{}
+	err := walkHelmDir(chartsDir, func(relPath string, data []byte) {
+		files[relPath] = data
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading helm charts directory: %w", err)
+	}
+	return files, nil
+}
+
+// writeHelmChartsDir writes the files of the charts/ directory of the chart
+func writeHelmChartsDir(appPath string, files map[string][]byte) error {
+	chartsDir := filepath.Join(appPath, helmChartsDir)
+	for relPath, data := range files {
+		path := filepath.Join(chartsDir, relPath)
+		if !strings.HasPrefix(path, chartsDir+string(filepath.Separator)) {
+			return fmt.Errorf("cached helm dependency %s is out of the charts directory", relPath)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("error creating helm charts directory: %w", err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return fmt.Errorf("error writing cached helm dependency %s: %w", relPath, err)
+		}
+	}
+	return nil
+}
+
+// ensureDependencies fetches the dependencies of the chart if its Chart.yaml declares dependencies while its charts/
+// directory is empty. The dependencies are restored from the cache if the same chart was updated before, otherwise
+// they are downloaded by `helm dependency update` and cached. The chart is locked while its dependencies are fetched
+// if lockChart is set, when manifests of the chart may be generated concurrently.
+func (u *helmDependencyUpdater) ensureDependencies(appPath string, h helm.Helm, lockChart bool) error {
+	if lockChart {
+		manifestGenerateLock.Lock(appPath)
+		defer manifestGenerateLock.Unlock(appPath)
+	}
+	deps, err := readHelmDependencies(appPath)
+	if err != nil {
+		return err
+	}
+	if len(deps) == 0 {
+		return nil
+	}
+	empty, err := isHelmChartsDirEmpty(appPath)
+	if err != nil || !empty {
+		return err
+	}
+
+	chartHash, err := helmChartHash(appPath, deps)
+	if err != nil {
+		return err
+	}
+	if u.cache != nil {
+		files, err := u.cache.GetHelmDependencies(chartHash)
+		if err == nil && len(files) > 0 {
+			return writeHelmChartsDir(appPath, files)
+		}
+		if err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+			log.Warnf("Failed to get the cached dependencies of helm chart %s: %v", appPath, err)
+		}
+	}
+
+	if err := h.DependencyUpdate(u.timeout); err != nil {
+		return fmt.Errorf("error updating helm chart dependencies: %w", err)
+	}
+	if u.cache != nil {
+		files, err := readHelmChartsDir(appPath)
+		if err != nil {
+			return err
+		}
+		if err := u.cache.SetHelmDependencies(chartHash, files); err != nil {
+			log.Warnf("Failed to cache the dependencies of helm chart %s: %v", appPath, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/argoproj/argo-cd/v2/util/helm"
+)
+
+// fakeDependencyHelm writes a dependency into the charts/ directory of the chart when its dependencies are updated
+type fakeDependencyHelm struct {
+	helm.Helm
+	appPath string
+	updates int
+	timeout time.Duration
+}
+
+func (h *fakeDependencyHelm) DependencyUpdate(timeout time.Duration) error {
+	h.updates++
+	h.timeout = timeout
+	if err := os.MkdirAll(filepath.Join(h.appPath, "charts"), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(h.appPath, "charts", "redis-17.0.0.tgz"), []byte("redis"), 0o644)
+}
+
+const helmChartWithDependencies = `apiVersion: v2
+name: guestbook
+version: 1.0.0
+dependencies:
+  - name: redis
+    repository: https://charts.bitnami.com/bitnami
+    version: 17.0.0
+  - name: common
+    repository: file://../common
+    version: 1.0.0
+`
+
+// newHelmChartWithDependencies creates a chart declaring a remote and a local dependency, and returns its path
+func newHelmChartWithDependencies(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	appPath := filepath.Join(root, "guestbook")
+	require.NoError(t, os.MkdirAll(appPath, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(appPath, "Chart.yaml"), []byte(helmChartWithDependencies), 0o644))
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "common", "templates"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "common", "Chart.yaml"), []byte("apiVersion: v2\nname: common\nversion: 1.0.0\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "common", "templates", "_helpers.tpl"), []byte(`{{- define "common.name" -}}common{{- end -}}`), 0o644))
+	return appPath
+}
+
+func TestHelmChartHash(t *testing.T) {
+	appPath := newHelmChartWithDependencies(t)
+	deps, err := readHelmDependencies(appPath)
+	require.NoError(t, err)
+	require.Len(t, deps, 2)
+
+	hash, err := helmChartHash(appPath, deps)
+	require.NoError(t, err)
+	again, err := helmChartHash(appPath, deps)
+	require.NoError(t, err)
+	assert.Equal(t, hash, again)
+
+	// the hash changes with the files of the local dependencies
+	require.NoError(t, os.WriteFile(filepath.Join(appPath, "..", "common", "templates", "_helpers.tpl"), []byte(`{{- define "common.name" -}}shared{{- end -}}`), 0o644))
+	changed, err := helmChartHash(appPath, deps)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changed)
+
+	// and with the lock file
+	require.NoError(t, os.WriteFile(filepath.Join(appPath, "Chart.lock"), []byte("generated: 2024-01-01T00:00:00Z\n"), 0o644))
+	locked, err := helmChartHash(appPath, deps)
+	require.NoError(t, err)
+	assert.NotEqual(t, changed, locked)
+}
+
+func TestWriteHelmChartsDir(t *testing.T) {
+	appPath := t.TempDir()
+	empty, err := isHelmChartsDirEmpty(appPath)
+	require.NoError(t, err)
+	assert.True(t, empty)
+
+	files := map[string][]byte{"redis-17.0.0.tgz": []byte("redis"), filepath.Join("common", "Chart.yaml"): []byte("name: common")}
+	require.NoError(t, writeHelmChartsDir(appPath, files))
+	empty, err = isHelmChartsDirEmpty(appPath)
+	require.NoError(t, err)
+	assert.False(t, empty)
+	read, err := readHelmChartsDir(appPath)
+	require.NoError(t, err)
+	assert.Equal(t, files, read)
+
+	err = writeHelmChartsDir(appPath, map[string][]byte{filepath.Join("..", "Chart.yaml"): []byte("name: evil")})
+	assert.ErrorContains(t, err, "is out of the charts directory")
+}
+
+func TestEnsureDependencies(t *testing.T) {
+	cacheMocks := newCacheMocks()
+	t.Cleanup(cacheMocks.mockCache.StopRedisCallback)
+	updater := &helmDependencyUpdater{cache: cacheMocks.cache, timeout: 30 * time.Second}
+
+	t.Run("Missing", func(t *testing.T) {
+		appPath := newHelmChartWithDependencies(t)
+		h := &fakeDependencyHelm{appPath: appPath}
+		require.NoError(t, updater.ensureDependencies(appPath, h, true))
+		assert.Equal(t, 1, h.updates)
+		assert.Equal(t, 30*time.Second, h.timeout)
+		assert.FileExists(t, filepath.Join(appPath, "charts", "redis-17.0.0.tgz"))
+
+		// the same chart checked out again is restored from the cache
+		appPath = newHelmChartWithDependencies(t)
+		h = &fakeDependencyHelm{appPath: appPath}
+		require.NoError(t, updater.ensureDependencies(appPath, h, false))
+		assert.Zero(t, h.updates)
+		data, err := os.ReadFile(filepath.Join(appPath, "charts", "redis-17.0.0.tgz"))
+		require.NoError(t, err)
+		assert.Equal(t, "redis", string(data))
+	})
+
+	t.Run("Committed", func(t *testing.T) {
+		appPath := newHelmChartWithDependencies(t)
+		require.NoError(t, os.MkdirAll(filepath.Join(appPath, "charts"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(appPath, "charts", "redis-16.0.0.tgz"), []byte("committed"), 0o644))
+		h := &fakeDependencyHelm{appPath: appPath}
+		require.NoError(t, updater.ensureDependencies(appPath, h, true))
+		assert.Zero(t, h.updates)
+		assert.NoFileExists(t, filepath.Join(appPath, "charts", "redis-17.0.0.tgz"))
+	})
+
+	t.Run("NoDependencies", func(t *testing.T) {
+		appPath := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(appPath, "Chart.yaml"), []byte("apiVersion: v2\nname: guestbook\nversion: 1.0.0\n"), 0o644))
+		h := &fakeDependencyHelm{appPath: appPath}
+		require.NoError(t, updater.ensureDependencies(appPath, h, true))
+		assert.Zero(t, h.updates)
+		assert.NoDirExists(t, filepath.Join(appPath, "charts"))
+	})
+
+	t.Run("NoCache", func(t *testing.T) {
+		appPath := newHelmChartWithDependencies(t)
+		h := &fakeDependencyHelm{appPath: appPath}
+		require.NoError(t, (&helmDependencyUpdater{timeout: time.Second}).ensureDependencies(appPath, h, true))
+		assert.Equal(t, 1, h.updates)
+	})
+}
+
+func TestEnsureDependencies_LocalDependency(t *testing.T) {
+	if _, err := exec.LookPath("helm"); err != nil {
+		t.Skip("helm is not installed")
+	}
+	root := t.TempDir()
+	for _, chart := range []string{"helm-with-local-dependency", "simple-chart"} {
+		require.NoError(t, exec.Command("cp", "-r", filepath.Join("testdata", chart), root).Run())
+	}
+	appPath := filepath.Join(root, "helm-with-local-dependency")
+	h, err := helm.NewHelmApp(appPath, nil, false, "", "", false)
+	require.NoError(t, err)
+	defer h.Dispose()
+
+	updater := &helmDependencyUpdater{timeout: time.Minute}
+	require.NoError(t, updater.ensureDependencies(appPath, h, true))
+	assert.FileExists(t, filepath.Join(appPath, "charts", "simple-chart-v1.1.0.tgz"))
+
+	out, _, err := h.Template(&helm.TemplateOpts{Name: "test", Namespace: "default"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, out)
+}
@@ -0,0 +1,229 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/reposerver/cache"
+)
+
+const (
+	// httpSourceTimeout bounds the time to fetch the manifests of an HTTP source
+	httpSourceTimeout = 60 * time.Second
+	// httpSourceMaxRedirects is the maximum number of redirects followed when fetching the manifests of an HTTP source
+	httpSourceMaxRedirects = 10
+)
+
+// generateHTTPManifests returns the manifests fetched from the URL of the HTTP source of the request, without any
+// repository operation
+func (s *Service) generateHTTPManifests(ctx context.Context, q *apiclient.ManifestRequest) (*apiclient.ManifestResponse, error) {
+	source := q.ApplicationSource.HTTP
+	data, err := s.getHTTPSourceManifests(ctx, source, q.HttpSourceCABundle, q.NoCache)
+	if err != nil {
+		return nil, err
+	}
+	targetObjs, err := s.parsePool.Parse(data)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse manifests of %s: %v", source.URL, err)
+	}
+	manifests, err := marshalManifests(targetObjs, q)
+	if err != nil {
+		return nil, err
+	}
+	return &apiclient.ManifestResponse{
+		Manifests:  manifests,
+		SourceType: string(v1alpha1.ApplicationSourceTypeHTTP),
+	}, nil
+}
+
+// getHTTPSourceManifests returns the manifests of the HTTP source from the cache, or fetches them and verifies their
+// SHA256 digest. Sources without a digest are cached too, so that their URL is polled for changes once the cached
+// manifests expire.
+func (s *Service) getHTTPSourceManifests(ctx context.Context, source *v1alpha1.ApplicationSourceHTTP, caBundle string, noCache bool) ([]byte, error) {
+	expectedDigest := strings.ToLower(source.SHA256)
+	cacheTTL := s.initConstants.HTTPSourceCacheTTL
+	if !noCache && cacheTTL > 0 {
+		data, err := s.cache.GetHTTPSource(source.URL, expectedDigest)
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, cache.ErrCacheMiss) {
+			log.Warnf("manifests cache error %s: %v", source.URL, err)
+		}
+	}
+
+	data, err := fetchHTTPSource(ctx, source.URL, caBundle, s.initConstants.MaxCombinedDirectoryManifestsSize.Value())
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(data)
+	if actualDigest := hex.EncodeToString(digest[:]); expectedDigest != "" && actualDigest != expectedDigest {
+		return nil, status.Errorf(codes.FailedPrecondition, "SHA256 digest %s of the manifests of %s does not match the expected digest %s", actualDigest, source.URL, expectedDigest)
+	}
+
+	if cacheTTL > 0 {
+		if err := s.cache.SetHTTPSource(source.URL, expectedDigest, data, cacheTTL); err != nil {
+			log.Warnf("manifests cache set error %s: %v", source.URL, err)
+		}
+	}
+	return data, nil
+}
+
+// fetchHTTPSource fetches the manifests at the given HTTP or HTTPS URL. The certificates of the given PEM encoded CA
+// bundle are trusted in addition to the system ones. The size of the manifests is limited to maxSize bytes, zero
+// means no limit.
+func fetchHTTPSource(ctx context.Context, rawURL string, caBundle string, maxSize int64) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid URL %q of HTTP source: must be an absolute http or https URL", rawURL)
+	}
+
+	guard := &httpSourceDialGuard{}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// every request of the redirect chain dials a new connection, so that a redirect is never served by a
+	// connection to a non-public address dialed for the URL of the source
+	transport.DisableKeepAlives = true
+	transport.DialContext = guard.dialContext
+	transport.Proxy = guard.proxy(transport.Proxy)
+	if caBundle != "" {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM([]byte(caBundle)) {
+			return nil, status.Errorf(codes.InvalidArgument, "CA bundle of HTTP source %s does not contain any PEM encoded certificate", rawURL)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+	}
+	client := &http.Client{Transport: transport, Timeout: httpSourceTimeout, CheckRedirect: guard.checkRedirect}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request to %s: %w", rawURL, err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "error fetching manifests from %s: %v", rawURL, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, status.Errorf(codes.Unavailable, "error fetching manifests from %s: unexpected status %s", rawURL, resp.Status)
+	}
+
+	body := io.Reader(resp.Body)
+	if maxSize > 0 {
+		body = io.LimitReader(resp.Body, maxSize+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "error reading manifests from %s: %v", rawURL, err)
+	}
+	if maxSize > 0 && int64(len(data)) > maxSize {
+		return nil, status.Errorf(codes.ResourceExhausted, "manifests of %s exceed the maximum size of %d bytes", rawURL, maxSize)
+	}
+	return data, nil
+}
+
+// httpSourceDialGuard refuses the redirects of an HTTP source to non-public addresses. Only the URL of the source is
+// matched against the source repositories of the project, so that a permitted URL must not be able to redirect the
+// repo-server to internal services. Once redirected, the address of every dialed connection is checked rather than
+// the result of a separate lookup, which a DNS rebinding host could answer differently.
+type httpSourceDialGuard struct {
+	redirected atomic.Bool
+	// proxyAddr is the address of the proxy of the current request, if any
+	proxyAddr atomic.Value
+}
+
+func (g *httpSourceDialGuard) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= httpSourceMaxRedirects {
+		return fmt.Errorf("stopped after %d redirects", httpSourceMaxRedirects)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("redirect to %s is not allowed: must be an http or https URL", req.URL.Redacted())
+	}
+	g.redirected.Store(true)
+	return nil
+}
+
+// proxy wraps the proxy function of the transport. The target of a redirect sent through a proxy is resolved by the
+// proxy, so that it can only be checked by looking up its addresses before the request.
+func (g *httpSourceDialGuard) proxy(proxy func(*http.Request) (*url.URL, error)) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		g.proxyAddr.Store("")
+		if proxy == nil {
+			return nil, nil
+		}
+		proxyURL, err := proxy(req)
+		if err != nil || proxyURL == nil {
+			return proxyURL, err
+		}
+		if g.redirected.Load() {
+			if err := checkPublicHost(req.Context(), req.URL.Hostname()); err != nil {
+				return nil, fmt.Errorf("redirect to %s is not allowed: %w", req.URL.Redacted(), err)
+			}
+		}
+		port := proxyURL.Port()
+		if port == "" {
+			port = map[string]string{"http": "80", "https": "443", "socks5": "1080"}[proxyURL.Scheme]
+		}
+		g.proxyAddr.Store(net.JoinHostPort(proxyURL.Hostname(), port))
+		return proxyURL, nil
+	}
+}
+
+func (g *httpSourceDialGuard) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if g.redirected.Load() && address != g.proxyAddr.Load() {
+		dialer.Control = func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return fmt.Errorf("redirect is not allowed: %s is not a public address", host)
+			}
+			return nil
+		}
+	}
+	return dialer.DialContext(ctx, network, address)
+}
+
+// checkPublicHost returns an error if any of the addresses of the given host is not public
+func checkPublicHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return fmt.Errorf("error resolving %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !isPublicIP(addr.IP) {
+			return fmt.Errorf("%s is not a public address", addr.IP)
+		}
+	}
+	return nil
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsUnspecified() && !ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() && !ip.IsInterfaceLocalMulticast() && !ip.IsMulticast()
+}
@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+)
+
+const httpSourceManifests = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+`
+
+// newHTTPSourceServer returns a TLS server serving the given manifests, the PEM encoded certificate of the server and
+// the number of requests it served
+func newHTTPSourceServer(t *testing.T, manifests *atomic.Value) (*httptest.Server, string, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/install.yaml", http.StatusFound)
+			return
+		}
+		if r.URL.Path != "/install.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(manifests.Load().(string)))
+	}))
+	t.Cleanup(server.Close)
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	return server, string(caBundle), &requests
+}
+
+func httpSourceManifestRequest(source *argoappv1.ApplicationSourceHTTP, caBundle string) *apiclient.ManifestRequest {
+	return &apiclient.ManifestRequest{
+		Repo:               &argoappv1.Repository{Repo: source.URL},
+		ApplicationSource:  &argoappv1.ApplicationSource{RepoURL: source.URL, HTTP: source},
+		AppLabelKey:        common.LabelKeyAppInstance,
+		AppName:            "http",
+		TrackingMethod:     string(argo.TrackingMethodLabel),
+		HttpSourceCABundle: caBundle,
+	}
+}
+
+func sha256Hex(data string) string {
+	digest := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(digest[:])
+}
+
+func TestGenerateManifest_HTTP(t *testing.T) {
+	var manifests atomic.Value
+	manifests.Store(httpSourceManifests)
+	server, caBundle, _ := newHTTPSourceServer(t, &manifests)
+	service := newService(t, ".")
+
+	t.Run("Verified SHA256", func(t *testing.T) {
+		res, err := service.GenerateManifest(context.Background(), httpSourceManifestRequest(&argoappv1.ApplicationSourceHTTP{
+			URL:    server.URL + "/install.yaml",
+			SHA256: sha256Hex(httpSourceManifests),
+		}, caBundle))
+		require.NoError(t, err)
+		assert.Equal(t, string(argoappv1.ApplicationSourceTypeHTTP), res.SourceType)
+		assert.Empty(t, res.Revision)
+		require.Len(t, res.Manifests, 2)
+		assert.Contains(t, res.Manifests[0], `"name":"first"`)
+		assert.Contains(t, res.Manifests[1], `"app.kubernetes.io/instance":"http"`)
+	})
+
+	t.Run("SHA256 mismatch", func(t *testing.T) {
+		_, err := service.GenerateManifest(context.Background(), httpSourceManifestRequest(&argoappv1.ApplicationSourceHTTP{
+			URL:    server.URL + "/install.yaml",
+			SHA256: sha256Hex("other manifests"),
+		}, caBundle))
+		require.ErrorContains(t, err, "does not match the expected digest "+sha256Hex("other manifests"))
+	})
+
+	t.Run("Untrusted certificate", func(t *testing.T) {
+		_, err := service.GenerateManifest(context.Background(), httpSourceManifestRequest(&argoappv1.ApplicationSourceHTTP{
+			URL: server.URL + "/install.yaml",
+		}, ""))
+		require.ErrorContains(t, err, "certificate")
+	})
+
+	t.Run("Invalid CA bundle", func(t *testing.T) {
+		_, err := service.GenerateManifest(context.Background(), httpSourceManifestRequest(&argoappv1.ApplicationSourceHTTP{
+			URL: server.URL + "/install.yaml",
+		}, "not a certificate"))
+		require.ErrorContains(t, err, "does not contain any PEM encoded certificate")
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		_, err := service.GenerateManifest(context.Background(), httpSourceManifestRequest(&argoappv1.ApplicationSourceHTTP{
+			URL: server.URL + "/missing.yaml",
+		}, caBundle))
+		require.ErrorContains(t, err, "unexpected status 404 Not Found")
+	})
+
+	t.Run("Redirect to non-public address", func(t *testing.T) {
+		_, err := service.GenerateManifest(context.Background(), httpSourceManifestRequest(&argoappv1.ApplicationSourceHTTP{
+			URL: server.URL + "/redirect",
+		}, caBundle))
+		require.ErrorContains(t, err, "127.0.0.1 is not a public address")
+	})
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		_, err := service.GenerateManifest(context.Background(), httpSourceManifestRequest(&argoappv1.ApplicationSourceHTTP{
+			URL: "file:///etc/passwd",
+		}, ""))
+		require.ErrorContains(t, err, "must be an absolute http or https URL")
+	})
+
+	t.Run("Invalid manifests", func(t *testing.T) {
+		manifests.Store("kind: ConfigMap\nmetadata: [name")
+		t.Cleanup(func() {
+			manifests.Store(httpSourceManifests)
+		})
+		_, err := service.GenerateManifest(context.Background(), httpSourceManifestRequest(&argoappv1.ApplicationSourceHTTP{
+			URL: server.URL + "/install.yaml",
+		}, caBundle))
+		require.ErrorContains(t, err, "failed to parse manifests")
+	})
+}
+
+func TestIsPublicIP(t *testing.T) {
+	for _, ip := range []string{"127.0.0.1", "10.0.0.1", "172.16.0.1", "192.168.1.1", "169.254.169.254", "0.0.0.0", "::1", "fe80::1", "fd00::1"} {
+		assert.False(t, isPublicIP(net.ParseIP(ip)), ip)
+	}
+	for _, ip := range []string{"140.82.121.4", "2606:50c0:8000::154"} {
+		assert.True(t, isPublicIP(net.ParseIP(ip)), ip)
+	}
+}
+
+func TestHTTPSourceDialGuard(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = listener.Close()
+	})
+	guard := &httpSourceDialGuard{}
+
+	// the URL of the source itself may be a non-public address
+	conn, err := guard.dialContext(context.Background(), "tcp", listener.Addr().String())
+	require.NoError(t, err)
+	_ = conn.Close()
+
+	// the address actually dialed is checked once redirected, whatever a previous lookup of the host returned
+	guard.redirected.Store(true)
+	_, err = guard.dialContext(context.Background(), "tcp", listener.Addr().String())
+	require.ErrorContains(t, err, "127.0.0.1 is not a public address")
+
+	// the proxy of the request resolves the target itself
+	guard.proxyAddr.Store(listener.Addr().String())
+	conn, err = guard.dialContext(context.Background(), "tcp", listener.Addr().String())
+	require.NoError(t, err)
+	_ = conn.Close()
+}
+
+func TestGenerateManifest_HTTPMaxSize(t *testing.T) {
+	var manifests atomic.Value
+	manifests.Store(httpSourceManifests)
+	server, caBundle, _ := newHTTPSourceServer(t, &manifests)
+	service := newService(t, ".")
+	service.initConstants.MaxCombinedDirectoryManifestsSize = resource.MustParse("10")
+
+	_, err := service.GenerateManifest(context.Background(), httpSourceManifestRequest(&argoappv1.ApplicationSourceHTTP{
+		URL: server.URL + "/install.yaml",
+	}, caBundle))
+	require.ErrorContains(t, err, "exceed the maximum size of 10 bytes")
+}
+
+func TestGenerateManifest_HTTPCache(t *testing.T) {
+	var manifests atomic.Value
+	manifests.Store(httpSourceManifests)
+	server, caBundle, requests := newHTTPSourceServer(t, &manifests)
+	service := newService(t, ".")
+	service.initConstants.HTTPSourceCacheTTL = time.Minute
+	source := &argoappv1.ApplicationSourceHTTP{URL: server.URL + "/install.yaml"}
+
+	res, err := service.GenerateManifest(context.Background(), httpSourceManifestRequest(source, caBundle))
+	require.NoError(t, err)
+	require.Len(t, res.Manifests, 2)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// the cached manifests are used until they expire, even if they changed
+	manifests.Store("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: changed\n")
+	res, err = service.GenerateManifest(context.Background(), httpSourceManifestRequest(source, caBundle))
+	require.NoError(t, err)
+	require.Len(t, res.Manifests, 2)
+	assert.Equal(t, int32(1), requests.Load())
+
+	// a hard refresh fetches the manifests again
+	req := httpSourceManifestRequest(source, caBundle)
+	req.NoCache = true
+	res, err = service.GenerateManifest(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, res.Manifests, 1)
+	assert.Contains(t, res.Manifests[0], `"name":"changed"`)
+	assert.Equal(t, int32(2), requests.Load())
+
+	// the manifests of a source pinned to a digest are cached separately
+	source.SHA256 = sha256Hex(httpSourceManifests)
+	_, err = service.GenerateManifest(context.Background(), httpSourceManifestRequest(source, caBundle))
+	require.ErrorContains(t, err, "does not match the expected digest")
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestGetAppDetails_HTTP(t *testing.T) {
+	service := newService(t, ".")
+	res, err := service.GetAppDetails(context.Background(), &apiclient.RepoServerAppDetailsQuery{
+		Repo:   &argoappv1.Repository{Repo: "https://example.com/install.yaml"},
+		Source: &argoappv1.ApplicationSource{RepoURL: "https://example.com/install.yaml", HTTP: &argoappv1.ApplicationSourceHTTP{URL: "https://example.com/install.yaml"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, string(argoappv1.ApplicationSourceTypeHTTP), res.Type)
+}
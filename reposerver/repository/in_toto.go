@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/in-toto/in-toto-golang/in_toto"
+)
+
+// inTotoLinkExtension is the extension of the in-toto link metadata files, which are not products of the supply chain
+const inTotoLinkExtension = ".link"
+
+// verifyInTotoAttestation verifies that the link metadata files of the application path satisfy the given signed in-toto
+// layout, and that each file of the application path is a product of the last step of the supply chain with the same
+// SHA256 digest. Inspections are not supported, since they would run the commands of the layout in the repo-server.
+func verifyInTotoAttestation(appPath string, layout string, layoutKeys []string) error {
+	if layout == "" || len(layoutKeys) == 0 {
+		return fmt.Errorf("in-toto layout of the source is missing")
+	}
+	layoutEnv, err := loadInTotoLayout(layout)
+	if err != nil {
+		return fmt.Errorf("failed to load in-toto layout: %w", err)
+	}
+	if l, ok := layoutEnv.GetPayload().(in_toto.Layout); !ok {
+		return fmt.Errorf("in-toto layout metadata does not hold a layout")
+	} else if len(l.Inspect) > 0 {
+		return fmt.Errorf("inspections of in-toto layouts are not supported")
+	}
+	keys := make(map[string]in_toto.Key, len(layoutKeys))
+	for i, pemKey := range layoutKeys {
+		var key in_toto.Key
+		if err := key.LoadKeyReaderDefaults(strings.NewReader(pemKey)); err != nil {
+			return fmt.Errorf("failed to load public key %d of the in-toto layout: %w", i+1, err)
+		}
+		keys[key.KeyID] = key
+	}
+
+	summary, err := in_toto.InTotoVerify(layoutEnv, keys, appPath, "", nil, nil, false)
+	if err != nil {
+		return fmt.Errorf("in-toto verification failed: %w", err)
+	}
+	summaryLink, ok := summary.GetPayload().(in_toto.Link)
+	if !ok {
+		return fmt.Errorf("in-toto verification did not return a summary link")
+	}
+	return verifyInTotoProducts(appPath, summaryLink.Products)
+}
+
+// loadInTotoLayout parses the given signed in-toto layout. The in-toto library only loads metadata from files, so the
+// layout is written to a temporary file first.
+func loadInTotoLayout(layout string) (in_toto.Metadata, error) {
+	f, err := os.CreateTemp("", "in-toto-layout")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	_, err = f.WriteString(layout)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return in_toto.LoadMetadata(f.Name())
+}
+
+// verifyInTotoProducts verifies that each file of the application path, except the link metadata files, is one of the
+// given products with the same SHA256 digest
+func verifyInTotoProducts(appPath string, products map[string]interface{}) error {
+	return filepath.WalkDir(appPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if strings.HasSuffix(d.Name(), inTotoLinkExtension) {
+			return nil
+		}
+		relPath, err := filepath.Rel(appPath, path)
+		if err != nil {
+			return err
+		}
+		relPath = filepath.ToSlash(relPath)
+		product, ok := products[relPath].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("file %s is not a product of the in-toto supply chain", relPath)
+		}
+		expected, ok := product["sha256"].(string)
+		if !ok {
+			return fmt.Errorf("product %s of the in-toto supply chain has no sha256 digest", relPath)
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		digest := sha256.Sum256(data)
+		if hex.EncodeToString(digest[:]) != strings.ToLower(expected) {
+			return fmt.Errorf("sha256 digest of file %s does not match the product of the in-toto supply chain", relPath)
+		}
+		return nil
+	})
+}
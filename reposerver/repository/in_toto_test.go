@@ -0,0 +1,252 @@
+package repository
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/in-toto/in-toto-golang/in_toto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+)
+
+const inTotoTestManifest = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: guestbook
+data:
+  color: blue
+`
+
+// inTotoTestKey is an ed25519 key pair of the in-toto test chain
+type inTotoTestKey struct {
+	private   in_toto.Key
+	public    in_toto.Key
+	publicPEM string
+}
+
+func newInTotoTestKey(t *testing.T) inTotoTestKey {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	privDER, err := x509.MarshalPKCS8PrivateKey(priv)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	publicPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}))
+
+	var key inTotoTestKey
+	require.NoError(t, key.private.LoadKeyReaderDefaults(strings.NewReader(string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER})))))
+	require.NoError(t, key.public.LoadKeyReaderDefaults(strings.NewReader(publicPEM)))
+	key.publicPEM = publicPEM
+	return key
+}
+
+// inTotoTestChain is a minimal in-toto supply chain, where the manifests built by the build step are checked by the
+// test step
+type inTotoTestChain struct {
+	owner     inTotoTestKey
+	builder   inTotoTestKey
+	tester    inTotoTestKey
+	inspect   bool
+	artifacts map[string]string
+}
+
+func newInTotoTestChain(t *testing.T) *inTotoTestChain {
+	t.Helper()
+	return &inTotoTestChain{
+		owner:     newInTotoTestKey(t),
+		builder:   newInTotoTestKey(t),
+		tester:    newInTotoTestKey(t),
+		artifacts: map[string]string{"guestbook.yaml": inTotoTestManifest},
+	}
+}
+
+// layout returns the layout of the chain, signed by its owner
+func (c *inTotoTestChain) layout(t *testing.T) string {
+	t.Helper()
+	layout := in_toto.Layout{
+		Type:    "layout",
+		Expires: time.Now().Add(time.Hour).UTC().Format(in_toto.ISO8601DateSchema),
+		Keys: map[string]in_toto.Key{
+			c.builder.public.KeyID: c.builder.public,
+			c.tester.public.KeyID:  c.tester.public,
+		},
+		Steps: []in_toto.Step{{
+			Type:            "step",
+			PubKeys:         []string{c.builder.public.KeyID},
+			Threshold:       1,
+			ExpectedCommand: []string{},
+			SupplyChainItem: in_toto.SupplyChainItem{
+				Name:              "build",
+				ExpectedMaterials: [][]string{},
+				ExpectedProducts:  [][]string{{"CREATE", "*"}, {"DISALLOW", "*"}},
+			},
+		}, {
+			Type:            "step",
+			PubKeys:         []string{c.tester.public.KeyID},
+			Threshold:       1,
+			ExpectedCommand: []string{},
+			SupplyChainItem: in_toto.SupplyChainItem{
+				Name:              "test",
+				ExpectedMaterials: [][]string{{"MATCH", "*", "WITH", "PRODUCTS", "FROM", "build"}, {"DISALLOW", "*"}},
+				ExpectedProducts:  [][]string{{"MATCH", "*", "WITH", "MATERIALS", "FROM", "test"}, {"DISALLOW", "*"}},
+			},
+		}},
+		Inspect: []in_toto.Inspection{},
+	}
+	if c.inspect {
+		layout.Inspect = append(layout.Inspect, in_toto.Inspection{
+			Type:            "inspection",
+			Run:             []string{"true"},
+			SupplyChainItem: in_toto.SupplyChainItem{Name: "inspect", ExpectedMaterials: [][]string{}, ExpectedProducts: [][]string{}},
+		})
+	}
+	mb := in_toto.Metablock{Signed: layout}
+	require.NoError(t, mb.Sign(c.owner.private))
+	data, err := json.Marshal(mb)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// write writes the artifacts of the chain, and the link metadata files of the given steps, to the given directory
+func (c *inTotoTestChain) write(t *testing.T, dir string, steps ...string) {
+	t.Helper()
+	artifacts := map[string]interface{}{}
+	for name, content := range c.artifacts {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+		digest := sha256.Sum256([]byte(content))
+		artifacts[name] = map[string]interface{}{"sha256": hex.EncodeToString(digest[:])}
+	}
+	for _, step := range steps {
+		link := in_toto.Link{
+			Type:        "link",
+			Name:        step,
+			Materials:   artifacts,
+			Products:    artifacts,
+			ByProducts:  map[string]interface{}{},
+			Command:     []string{},
+			Environment: map[string]interface{}{},
+		}
+		key := c.tester.private
+		if step == "build" {
+			link.Materials = map[string]interface{}{}
+			key = c.builder.private
+		}
+		mb := in_toto.Metablock{Signed: link}
+		require.NoError(t, mb.Sign(key))
+		require.NoError(t, mb.Dump(filepath.Join(dir, fmt.Sprintf(in_toto.LinkNameFormat, step, key.KeyID))))
+	}
+}
+
+func TestVerifyInTotoAttestation(t *testing.T) {
+	t.Run("valid chain", func(t *testing.T) {
+		chain := newInTotoTestChain(t)
+		dir := t.TempDir()
+		chain.write(t, dir, "build", "test")
+		require.NoError(t, verifyInTotoAttestation(dir, chain.layout(t), []string{chain.owner.publicPEM}))
+	})
+
+	t.Run("missing link of a step", func(t *testing.T) {
+		chain := newInTotoTestChain(t)
+		dir := t.TempDir()
+		chain.write(t, dir, "build")
+		err := verifyInTotoAttestation(dir, chain.layout(t), []string{chain.owner.publicPEM})
+		assert.ErrorContains(t, err, "step 'test' requires '1' link metadata file(s), found '0'")
+	})
+
+	t.Run("link signed by another key", func(t *testing.T) {
+		chain := newInTotoTestChain(t)
+		dir := t.TempDir()
+		chain.write(t, dir, "build")
+		tester := chain.tester
+		chain.tester = newInTotoTestKey(t)
+		chain.write(t, dir, "test")
+		chain.tester = tester
+		err := verifyInTotoAttestation(dir, chain.layout(t), []string{chain.owner.publicPEM})
+		assert.ErrorContains(t, err, "in-toto verification failed")
+	})
+
+	t.Run("layout signed by another key", func(t *testing.T) {
+		chain := newInTotoTestChain(t)
+		dir := t.TempDir()
+		chain.write(t, dir, "build", "test")
+		err := verifyInTotoAttestation(dir, chain.layout(t), []string{newInTotoTestKey(t).publicPEM})
+		assert.ErrorContains(t, err, "in-toto verification failed")
+	})
+
+	t.Run("modified manifest", func(t *testing.T) {
+		chain := newInTotoTestChain(t)
+		dir := t.TempDir()
+		chain.write(t, dir, "build", "test")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "guestbook.yaml"), []byte(strings.ReplaceAll(inTotoTestManifest, "blue", "red")), 0o644))
+		err := verifyInTotoAttestation(dir, chain.layout(t), []string{chain.owner.publicPEM})
+		assert.ErrorContains(t, err, "sha256 digest of file guestbook.yaml does not match")
+	})
+
+	t.Run("manifest outside of the chain", func(t *testing.T) {
+		chain := newInTotoTestChain(t)
+		dir := t.TempDir()
+		chain.write(t, dir, "build", "test")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "extra.yaml"), []byte(inTotoTestManifest), 0o644))
+		err := verifyInTotoAttestation(dir, chain.layout(t), []string{chain.owner.publicPEM})
+		assert.ErrorContains(t, err, "file extra.yaml is not a product of the in-toto supply chain")
+	})
+
+	t.Run("inspections", func(t *testing.T) {
+		chain := newInTotoTestChain(t)
+		chain.inspect = true
+		dir := t.TempDir()
+		chain.write(t, dir, "build", "test")
+		err := verifyInTotoAttestation(dir, chain.layout(t), []string{chain.owner.publicPEM})
+		assert.ErrorContains(t, err, "inspections of in-toto layouts are not supported")
+	})
+
+	t.Run("missing layout", func(t *testing.T) {
+		err := verifyInTotoAttestation(t.TempDir(), "", nil)
+		assert.ErrorContains(t, err, "in-toto layout of the source is missing")
+	})
+}
+
+func TestGenerateManifest_InTotoAttestation(t *testing.T) {
+	chain := newInTotoTestChain(t)
+	root := t.TempDir()
+	chain.write(t, root, "build", "test")
+	newRequest := func() *apiclient.ManifestRequest {
+		return &apiclient.ManifestRequest{
+			Repo: &argoappv1.Repository{},
+			ApplicationSource: &argoappv1.ApplicationSource{
+				Path:              ".",
+				VerifyAttestation: &argoappv1.ApplicationSourceVerifyAttestation{InToto: &argoappv1.InTotoAttestation{Layout: "guestbook-layout"}},
+			},
+			NoCache:            true,
+			ProjectName:        "default",
+			ProjectSourceRepos: []string{"*"},
+			InTotoLayout:       chain.layout(t),
+			InTotoLayoutKeys:   []string{chain.owner.publicPEM},
+		}
+	}
+
+	res, err := newService(t, root).GenerateManifest(context.Background(), newRequest())
+	require.NoError(t, err)
+	require.Len(t, res.Manifests, 1)
+	assert.Contains(t, res.Manifests[0], `"name":"guestbook"`)
+
+	require.NoError(t, os.Remove(filepath.Join(root, fmt.Sprintf(in_toto.LinkNameFormat, "test", chain.tester.public.KeyID))))
+	_, err = newService(t, root).GenerateManifest(context.Background(), newRequest())
+	require.ErrorContains(t, err, "step 'test' requires '1' link metadata file(s)")
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	log "github.com/sirupsen/logrus"
+	"oras.land/oras-go/v2/registry/remote"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/cache"
+	"github.com/argoproj/argo-cd/v2/util/helm"
+	"github.com/argoproj/argo-cd/v2/util/kustomize"
+)
+
+// kustomizeOCIBaseFetcher pulls the Kustomize bases stored as OCI artifacts with the credentials of the Helm OCI
+// repositories matching them, and caches the files of the artifacts by their digest. Tags are resolved to digests on
+// every fetch, so that bases are updated as soon as their tag moves.
+type kustomizeOCIBaseFetcher struct {
+	ctx              context.Context
+	cache            *cache.Cache
+	newOCIRepository func(reference string, creds helm.Creds, proxy string) (*remote.Repository, error)
+	repos            []*v1alpha1.Repository
+	repoCreds        []*v1alpha1.RepoCreds
+	// maxSize is the maximum total size of the files of an artifact in bytes, zero means no limit
+	maxSize int64
+	noCache bool
+}
+
+var _ kustomize.OCIBaseFetcher = &kustomizeOCIBaseFetcher{}
+
+func (f *kustomizeOCIBaseFetcher) FetchBase(reference string, dir string) error {
+	repo, err := f.newOCIRepository(reference, helmOCICreds(ociPrefix+reference, f.repos, f.repoCreds), "")
+	if err != nil {
+		return fmt.Errorf("invalid reference %q: %w", reference, err)
+	}
+	image := repo.Reference.Registry + "/" + repo.Reference.Repository
+	dgst := repo.Reference.Reference
+	if dgst == "" {
+		dgst = ociSourceDefaultTag
+	}
+	if _, err := digest.Parse(dgst); err != nil {
+		desc, err := repo.Resolve(f.ctx, dgst)
+		if err != nil {
+			return fmt.Errorf("error resolving tag %s of %s: %w", dgst, image, err)
+		}
+		dgst = desc.Digest.String()
+	}
+
+	var files map[string][]byte
+	if !f.noCache {
+		files, err = f.cache.GetKustomizeOCIBase(image, dgst)
+		if err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+			log.Warnf("Kustomize OCI base cache error %s@%s: %v", image, dgst, err)
+		}
+	}
+	if files == nil {
+		files, err = fetchOCIArtifactFiles(f.ctx, repo, dgst, f.maxSize, func(string) bool { return true })
+		if err != nil {
+			return err
+		}
+		if err := f.cache.SetKustomizeOCIBase(image, dgst, files); err != nil {
+			log.Warnf("Kustomize OCI base cache set error %s@%s: %v", image, dgst, err)
+		}
+	}
+	return writeKustomizeOCIBase(dir, files)
+}
+
+// writeKustomizeOCIBase writes the files of the artifact of an OCI base, by their path in the artifact, into dir
+func writeKustomizeOCIBase(dir string, files map[string][]byte) error {
+	for name, data := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return fmt.Errorf("file %s is out of the artifact", name)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("error creating directory of %s: %w", name, err)
+		}
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return fmt.Errorf("error writing %s: %w", name, err)
+		}
+	}
+	return nil
+}
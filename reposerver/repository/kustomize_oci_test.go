@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/util/git"
+)
+
+func newKustomizeOCIBaseFetcher(service *Service, repos []*argoappv1.Repository) *kustomizeOCIBaseFetcher {
+	return &kustomizeOCIBaseFetcher{
+		ctx:              context.Background(),
+		cache:            service.cache,
+		newOCIRepository: service.newOCIRepository,
+		repos:            repos,
+	}
+}
+
+func TestKustomizeOCIBaseFetcher(t *testing.T) {
+	service, registry, image := newOCISourceService(t)
+	files := map[string]string{
+		"kustomization.yaml":   "resources:\n- deployment.yaml\nconfigMapGenerator:\n- name: config\n  envs:\n  - config/app.env\n",
+		"deployment.yaml":      "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: guestbook-ui\n",
+		"config/app.env":       "LOG_LEVEL=debug\n",
+		"config/.hidden.patch": "- op: remove\n",
+	}
+	v1 := registry.push(t, "v1", files)
+
+	readDir := func(t *testing.T, dir string) map[string]string {
+		t.Helper()
+		read := map[string]string{}
+		require.NoError(t, filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			data, err := os.ReadFile(path)
+			rel, _ := filepath.Rel(dir, path)
+			read[filepath.ToSlash(rel)] = string(data)
+			return err
+		}))
+		return read
+	}
+
+	t.Run("Tag", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, newKustomizeOCIBaseFetcher(service, nil).FetchBase(image+":v1", dir))
+		// every file of the artifact is extracted, not only its manifests
+		assert.Equal(t, files, readDir(t, dir))
+
+		// the files are cached by the digest of the artifact, the tag is resolved again
+		requests := registry.requestCount()
+		dir = t.TempDir()
+		require.NoError(t, newKustomizeOCIBaseFetcher(service, nil).FetchBase(image+":v1", dir))
+		assert.Equal(t, files, readDir(t, dir))
+		assert.Equal(t, requests+1, registry.requestCount())
+	})
+
+	t.Run("Digest", func(t *testing.T) {
+		requests := registry.requestCount()
+		dir := t.TempDir()
+		require.NoError(t, newKustomizeOCIBaseFetcher(service, nil).FetchBase(image+"@"+v1, dir))
+		assert.Equal(t, files, readDir(t, dir))
+		assert.Equal(t, requests, registry.requestCount())
+	})
+
+	t.Run("TagMoved", func(t *testing.T) {
+		registry.push(t, "v1", map[string]string{"kustomization.yaml": "resources: []\n"})
+		dir := t.TempDir()
+		require.NoError(t, newKustomizeOCIBaseFetcher(service, nil).FetchBase(image+":v1", dir))
+		assert.Equal(t, map[string]string{"kustomization.yaml": "resources: []\n"}, readDir(t, dir))
+	})
+
+	t.Run("MissingTag", func(t *testing.T) {
+		err := newKustomizeOCIBaseFetcher(service, nil).FetchBase(image+":v2", t.TempDir())
+		assert.ErrorContains(t, err, "error resolving tag v2 of "+image)
+	})
+}
+
+func TestKustomizeOCIBaseFetcher_Credentials(t *testing.T) {
+	service, registry, image := newOCISourceService(t)
+	registry.username, registry.password = "admin", "s3cr3t"
+	registry.push(t, "latest", map[string]string{"kustomization.yaml": "resources: []\n"})
+
+	// the credentials of the Helm OCI repository matching the base are used
+	registryURL, _, _ := strings.Cut(image, "/")
+	repos := []*argoappv1.Repository{
+		{Repo: "oci://ghcr.io/argoproj", Type: "helm", EnableOCI: true, Username: "other", Password: "other"},
+		{Repo: registryURL + "/argoproj", Type: "helm", EnableOCI: true, Username: "admin", Password: "s3cr3t"},
+	}
+	dir := t.TempDir()
+	require.NoError(t, newKustomizeOCIBaseFetcher(service, repos).FetchBase(image, dir))
+	assert.FileExists(t, filepath.Join(dir, "kustomization.yaml"))
+
+	err := newKustomizeOCIBaseFetcher(service, repos[:1]).FetchBase(image, t.TempDir())
+	assert.ErrorContains(t, err, "basic credential not found")
+}
+
+func TestWriteKustomizeOCIBase(t *testing.T) {
+	dir := t.TempDir()
+	err := writeKustomizeOCIBase(dir, map[string][]byte{"../kustomization.yaml": []byte("resources: []")})
+	require.EqualError(t, err, "file ../kustomization.yaml is out of the artifact")
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(dir), "kustomization.yaml"))
+}
+
+func TestGenerateManifests_KustomizeOCIBase(t *testing.T) {
+	if _, err := exec.LookPath("kustomize"); err != nil {
+		t.Skip("kustomize is not installed")
+	}
+	service, registry, image := newOCISourceService(t)
+	registry.push(t, "v1", map[string]string{
+		"kustomization.yaml": "resources:\n- deployment.yaml\n",
+		"deployment.yaml":    "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: guestbook-ui\n",
+	})
+	repoDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "kustomization.yaml"), []byte("resources:\n- oci://"+image+":v1\nnamePrefix: prod-\n"), 0o644))
+
+	q := &apiclient.ManifestRequest{
+		Repo:               &argoappv1.Repository{},
+		ApplicationSource:  &argoappv1.ApplicationSource{Kustomize: &argoappv1.ApplicationSourceKustomize{}},
+		ProjectName:        "default",
+		ProjectSourceRepos: []string{"*"},
+	}
+	res, err := GenerateManifests(context.Background(), repoDir, repoDir, "", q, false, &git.NoopCredsStore{}, resource.MustParse("0"), nil, WithKustomizeOCIBases(service.cache, service.newOCIRepository))
+	require.NoError(t, err)
+	require.Len(t, res.Manifests, 1)
+	assert.Contains(t, res.Manifests[0], `"name":"prod-guestbook-ui"`)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	goio "io"
+	"io/fs"
+	"path/filepath"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	argopath "github.com/argoproj/argo-cd/v2/util/app/path"
+	"github.com/argoproj/argo-cd/v2/util/git"
+	"github.com/argoproj/argo-cd/v2/util/grpc"
+	"github.com/argoproj/argo-cd/v2/util/io"
+)
+
+// manifestFileExtensions are the extensions of the files which are blamed as manifest files
+var manifestFileExtensions = map[string]bool{
+	".yaml":      true,
+	".yml":       true,
+	".json":      true,
+	".jsonnet":   true,
+	".libsonnet": true,
+}
+
+// GetManifestBlame returns the commits which last modified the manifest files under the path of an application
+// source, according to git blame. The result is cached along with the manifests of the revision.
+func (s *Service) GetManifestBlame(ctx context.Context, q *apiclient.ManifestBlameRequest) (*apiclient.ManifestBlame, error) {
+	repo := q.GetRepo()
+	if repo == nil {
+		return nil, status.Error(codes.InvalidArgument, "must pass a valid repo")
+	}
+	appPath := q.GetPath()
+	if appPath == "" {
+		appPath = "."
+	}
+
+	gitClient, revision, err := s.newClientResolveRevision(repo, q.GetRevision(), git.WithCache(s.cache, !q.GetNoRevisionCache()))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to resolve git revision %s: %v", q.GetRevision(), err)
+	}
+
+	if blame, err := s.cache.GetManifestBlame(repo.Repo, revision, appPath); err == nil {
+		grpc.LoggerFromContext(ctx).Debugf("manifest blame cache hit: %s/%s", repo.Repo, appPath)
+		return blame, nil
+	}
+
+	s.metricsServer.IncPendingRepoRequest(repo.Repo)
+	defer s.metricsServer.DecPendingRepoRequest(repo.Repo)
+
+	closer, err := s.repoLock.Lock(gitClient.Root(), revision, true, func() (goio.Closer, error) {
+		return s.checkoutRevision(gitClient, revision, q.GetSubmoduleEnabled())
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to checkout git repo %s with revision %s: %v", repo.Repo, revision, err)
+	}
+	defer io.Close(closer)
+
+	appDir, err := argopath.Path(gitClient.Root(), appPath)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	files, err := blameManifestFiles(ctx, gitClient, appDir)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to blame the manifests of repo %s with revision %s path %s: %v", repo.Repo, revision, appPath, err)
+	}
+
+	blame := &apiclient.ManifestBlame{Revision: revision, Files: files}
+	if err := s.cache.SetManifestBlame(repo.Repo, revision, appPath, blame); err != nil {
+		grpc.LoggerFromContext(ctx).Warnf("manifest blame cache set error %s/%s: %v", repo.Repo, appPath, err)
+	}
+	return blame, nil
+}
+
+// blameManifestFiles returns the commits which last modified the manifest files under the given directory of the
+// checked out repository. The files which are not committed, e.g. the files of submodules, are skipped.
+func blameManifestFiles(ctx context.Context, gitClient git.Client, appDir string) ([]*apiclient.ManifestFileBlame, error) {
+	var files []*apiclient.ManifestFileBlame
+	err := filepath.WalkDir(appDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || !manifestFileExtensions[strings.ToLower(filepath.Ext(path))] {
+			return nil
+		}
+		repoPath, err := filepath.Rel(gitClient.Root(), path)
+		if err != nil {
+			return err
+		}
+		lines, err := gitClient.Blame(repoPath)
+		if err != nil {
+			grpc.LoggerFromContext(ctx).Debugf("skipping the blame of %s: %v", repoPath, err)
+			return nil
+		}
+		commit := git.LastBlameCommit(lines)
+		if commit == nil {
+			return nil
+		}
+		filePath, err := filepath.Rel(appDir, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, &apiclient.ManifestFileBlame{
+			FilePath:          filepath.ToSlash(filePath),
+			LastCommitAuthor:  fmt.Sprintf("%s <%s>", commit.Author, commit.AuthorEmail),
+			LastCommitTime:    commit.AuthorTime.Unix(),
+			LastCommitMessage: commit.Summary,
+			LastCommitSHA:     commit.SHA,
+		})
+		return nil
+	})
+	return files, err
+}
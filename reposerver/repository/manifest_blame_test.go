@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/util/git"
+)
+
+func blameLines(author string, authorTime int64, summary string) []git.BlameLine {
+	commit := &git.BlameCommit{SHA: "632039659e542ed7de0c170a4fcc1c571b288fc0", Author: author, AuthorEmail: "alice@example.com", AuthorTime: time.Unix(authorTime, 0), Summary: summary}
+	return []git.BlameLine{{Commit: commit, Line: 1, OriginalLine: 1, Content: "kind: ConfigMap"}}
+}
+
+func TestGetManifestBlame(t *testing.T) {
+	root := t.TempDir()
+	for _, file := range []string{"guestbook/cm.yaml", "guestbook/base/deployment.yml", "guestbook/README.md", "guestbook/generated.yaml", "other/svc.yaml"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, filepath.Dir(file)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(root, file), []byte("kind: ConfigMap\n"), 0o644))
+	}
+	service, gitClient, _ := newServiceWithMocks(t, root, false)
+	gitClient.On("Blame", "guestbook/cm.yaml").Return(blameLines("Alice", 1700000000, "Add config map"), nil).Once()
+	gitClient.On("Blame", "guestbook/base/deployment.yml").Return(blameLines("Alice", 1700003600, "Add deployment"), nil).Once()
+	gitClient.On("Blame", "guestbook/generated.yaml").Return(nil, errors.New("no such path guestbook/generated.yaml in HEAD")).Once()
+	request := &apiclient.ManifestBlameRequest{Repo: &argoappv1.Repository{Repo: "https://github.com/argoproj/argocd-example-apps"}, Revision: "HEAD", Path: "guestbook"}
+
+	blame, err := service.GetManifestBlame(context.Background(), request)
+	require.NoError(t, err)
+	// the files which are not manifests, not committed or outside of the path are not blamed
+	require.Len(t, blame.Files, 2)
+	assert.Equal(t, "base/deployment.yml", blame.Files[0].FilePath)
+	assert.Equal(t, "Add deployment", blame.Files[0].LastCommitMessage)
+	assert.Equal(t, int64(1700003600), blame.Files[0].LastCommitTime)
+	assert.Equal(t, "cm.yaml", blame.Files[1].FilePath)
+	assert.Equal(t, "Alice <alice@example.com>", blame.Files[1].LastCommitAuthor)
+	assert.Equal(t, "632039659e542ed7de0c170a4fcc1c571b288fc0", blame.Files[1].LastCommitSHA)
+
+	// the blame is returned from the cache afterwards
+	cached, err := service.GetManifestBlame(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, blame.Files, cached.Files)
+	gitClient.AssertNumberOfCalls(t, "Blame", 3)
+
+	t.Run("OutOfBoundsPath", func(t *testing.T) {
+		_, err := service.GetManifestBlame(context.Background(), &apiclient.ManifestBlameRequest{Repo: request.Repo, Revision: "HEAD", Path: "../.."})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("MissingRepo", func(t *testing.T) {
+		_, err := service.GetManifestBlame(context.Background(), &apiclient.ManifestBlameRequest{Path: "guestbook"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
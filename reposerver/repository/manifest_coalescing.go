@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gogo/protobuf/proto"
+	"golang.org/x/sync/singleflight"
+
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/reposerver/metrics"
+)
+
+// manifestGenerationGroup coalesces concurrent identical manifest requests, like the ones of many applications of the
+// same repository, revision and path refreshed at once, into a single generation whose result is returned to every
+// caller
+type manifestGenerationGroup struct {
+	group         singleflight.Group
+	metricsServer *metrics.MetricsServer
+}
+
+// generateFunc generates the manifests of a request
+type generateFunc func(ctx context.Context, q *apiclient.ManifestRequest) (*apiclient.ManifestResponse, error)
+
+// manifestGenerationKey returns the key of the generations which are coalesced: the repository URL, revision and path of
+// the source, followed by a hash of the whole request, since any other field, like the parameters of the source or the
+// application name passed to the build environment, may change the generated manifests
+func manifestGenerationKey(q *apiclient.ManifestRequest) (string, error) {
+	data, err := json.Marshal(q)
+	if err != nil {
+		return "", fmt.Errorf("error marshaling manifest request: %w", err)
+	}
+	source := q.ApplicationSource
+	return fmt.Sprintf("%s:%s:%s:%x", source.RepoURL, q.Revision, source.Path, sha256.Sum256(data)), nil
+}
+
+// generate returns the result of the generation of the manifests of the request, which is shared with the concurrent
+// identical requests. The generation is not canceled with the context of any caller, since other callers may wait for
+// it, but callers whose context is done stop waiting for it.
+func (g *manifestGenerationGroup) generate(ctx context.Context, q *apiclient.ManifestRequest, generate generateFunc) (*apiclient.ManifestResponse, error) {
+	key, err := manifestGenerationKey(q)
+	if err != nil {
+		return nil, err
+	}
+	leader := false
+	resCh := g.group.DoChan(key, func() (interface{}, error) {
+		leader = true
+		return generate(context.WithoutCancel(ctx), q)
+	})
+	select {
+	case res := <-resCh:
+		if !leader {
+			g.metricsServer.IncManifestGenerationCoalesced()
+		}
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		resp := res.Val.(*apiclient.ManifestResponse)
+		if res.Shared && resp != nil {
+			// every caller gets its own copy, the response may be modified once returned
+			resp = proto.Clone(resp).(*apiclient.ManifestResponse)
+		}
+		return resp, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
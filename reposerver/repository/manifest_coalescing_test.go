@@ -0,0 +1,187 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/reposerver/metrics"
+)
+
+func newCoalescingManifestRequest(path string) *apiclient.ManifestRequest {
+	return &apiclient.ManifestRequest{
+		Repo:              &argoappv1.Repository{Repo: "https://github.com/argoproj/argocd-example-apps"},
+		Revision:          "main",
+		ApplicationSource: &argoappv1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: path},
+	}
+}
+
+// blockingGenerator generates manifests once released, and counts its generations
+type blockingGenerator struct {
+	release     chan struct{}
+	generations atomic.Int32
+}
+
+func (g *blockingGenerator) generate(ctx context.Context, q *apiclient.ManifestRequest) (*apiclient.ManifestResponse, error) {
+	g.generations.Add(1)
+	select {
+	case <-g.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return &apiclient.ManifestResponse{Manifests: []string{`{"kind":"ConfigMap","metadata":{"name":"` + q.ApplicationSource.Path + `"}}`}, Revision: "abc123"}, nil
+}
+
+func TestManifestGenerationKey(t *testing.T) {
+	key, err := manifestGenerationKey(newCoalescingManifestRequest("guestbook"))
+	require.NoError(t, err)
+	assert.Regexp(t, `^https://github.com/argoproj/argocd-example-apps:main:guestbook:[0-9a-f]{64}$`, key)
+
+	same, err := manifestGenerationKey(newCoalescingManifestRequest("guestbook"))
+	require.NoError(t, err)
+	assert.Equal(t, key, same)
+
+	withParameters := newCoalescingManifestRequest("guestbook")
+	withParameters.ApplicationSource.Helm = &argoappv1.ApplicationSourceHelm{Parameters: []argoappv1.HelmParameter{{Name: "replicas", Value: "2"}}}
+	other, err := manifestGenerationKey(withParameters)
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+}
+
+func TestManifestGenerationGroup_Concurrent(t *testing.T) {
+	metricsServer := metrics.NewMetricsServer()
+	group := &manifestGenerationGroup{metricsServer: metricsServer}
+	generator := &blockingGenerator{release: make(chan struct{})}
+
+	const callers = 10
+	var started, done sync.WaitGroup
+	responses := make([]*apiclient.ManifestResponse, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+			started.Done()
+			responses[i], errs[i] = group.generate(context.Background(), newCoalescingManifestRequest("guestbook"), generator.generate)
+		}(i)
+	}
+	started.Wait()
+	// the callers join the generation started by the first one while it is blocked
+	require.Eventually(t, func() bool { return generator.generations.Load() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	close(generator.release)
+	done.Wait()
+
+	assert.Equal(t, int32(1), generator.generations.Load())
+	for i := 0; i < callers; i++ {
+		require.NoError(t, errs[i])
+		assert.Equal(t, responses[0], responses[i])
+	}
+	// every caller gets its own copy of the response
+	responses[0].Manifests[0] = "modified"
+	assert.NotEqual(t, "modified", responses[1].Manifests[0])
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rr := httptest.NewRecorder()
+	metricsServer.GetHandler().ServeHTTP(rr, req)
+	assert.Contains(t, rr.Body.String(), "argocd_manifest_generation_coalesced_total 9")
+
+	// a later request generates the manifests again
+	generator.release = make(chan struct{})
+	close(generator.release)
+	_, err := group.generate(context.Background(), newCoalescingManifestRequest("guestbook"), generator.generate)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), generator.generations.Load())
+}
+
+func TestManifestGenerationGroup_DifferentRequests(t *testing.T) {
+	group := &manifestGenerationGroup{metricsServer: metrics.NewMetricsServer()}
+	generator := &blockingGenerator{release: make(chan struct{})}
+	close(generator.release)
+
+	var wg sync.WaitGroup
+	for _, path := range []string{"guestbook", "helm-guestbook"} {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			res, err := group.generate(context.Background(), newCoalescingManifestRequest(path), generator.generate)
+			assert.NoError(t, err)
+			assert.Contains(t, res.Manifests[0], path)
+		}(path)
+	}
+	wg.Wait()
+	assert.Equal(t, int32(2), generator.generations.Load())
+}
+
+func TestManifestGenerationGroup_CallerCanceled(t *testing.T) {
+	group := &manifestGenerationGroup{metricsServer: metrics.NewMetricsServer()}
+	generator := &blockingGenerator{release: make(chan struct{})}
+
+	// the caller starting the generation is canceled while another one waits for it
+	ctx, cancel := context.WithCancel(context.Background())
+	canceledErr := make(chan error, 1)
+	go func() {
+		_, err := group.generate(ctx, newCoalescingManifestRequest("guestbook"), generator.generate)
+		canceledErr <- err
+	}()
+	require.Eventually(t, func() bool { return generator.generations.Load() == 1 }, time.Second, time.Millisecond)
+
+	type result struct {
+		res *apiclient.ManifestResponse
+		err error
+	}
+	waiting := make(chan result, 1)
+	go func() {
+		res, err := group.generate(context.Background(), newCoalescingManifestRequest("guestbook"), generator.generate)
+		waiting <- result{res, err}
+	}()
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-canceledErr, context.Canceled)
+
+	// the generation is not canceled with its first caller, and completes for the one still waiting
+	close(generator.release)
+	res := <-waiting
+	require.NoError(t, res.err)
+	assert.Equal(t, "abc123", res.res.Revision)
+	assert.Equal(t, int32(1), generator.generations.Load())
+}
+
+func TestGenerateManifest_Coalesced(t *testing.T) {
+	service := newService(t, ".")
+
+	const callers = 10
+	var wg sync.WaitGroup
+	responses := make([]*apiclient.ManifestResponse, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// every gRPC request is decoded into its own request, which the generation may modify
+			res, err := service.GenerateManifest(context.Background(), &apiclient.ManifestRequest{
+				Repo:               &argoappv1.Repository{},
+				ApplicationSource:  &argoappv1.ApplicationSource{Path: "./testdata/concatenated"},
+				ProjectName:        "something",
+				ProjectSourceRepos: []string{"*"},
+			})
+			assert.NoError(t, err)
+			responses[i] = res
+		}(i)
+	}
+	wg.Wait()
+	for i := 0; i < callers; i++ {
+		require.NotNil(t, responses[i])
+		assert.Len(t, responses[i].Manifests, 3)
+		assert.Equal(t, responses[0].Manifests, responses[i].Manifests)
+	}
+}
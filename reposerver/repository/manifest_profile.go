@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"runtime/metrics"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+)
+
+const (
+	// profileMemorySampleInterval is the interval at which the heap memory is sampled while profiling a manifest
+	// generation
+	profileMemorySampleInterval = 10 * time.Millisecond
+	// heapObjectsMetric is the runtime metric of the memory occupied by the live and unswept objects of the heap
+	heapObjectsMetric = "/memory/classes/heap/objects:bytes"
+)
+
+// profileLock ensures that a single manifest generation is profiled at a time, since a process has a single CPU
+// profile
+var profileLock sync.Mutex
+
+// ProfileManifestGeneration generates the manifests of an application source without cache, while profiling the CPU
+// and memory usage of the repo server. The CPU profile covers the whole process, so it includes the other requests
+// served concurrently. The result is stored in the cache for an hour.
+func (s *Service) ProfileManifestGeneration(ctx context.Context, q *apiclient.ProfileRequest) (*apiclient.ProfileResult, error) {
+	request := q.GetManifestRequest()
+	if request == nil || request.ApplicationSource == nil {
+		return nil, status.Error(codes.InvalidArgument, "manifest request is missing")
+	}
+	if !profileLock.TryLock() {
+		return nil, status.Error(codes.Unavailable, "another manifest generation is being profiled")
+	}
+	defer profileLock.Unlock()
+
+	// the manifests are generated again, rather than returned from the cache
+	request.NoCache = true
+	request.NoRevisionCache = true
+
+	var cpuProfile bytes.Buffer
+	if err := pprof.StartCPUProfile(&cpuProfile); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to start CPU profile: %v", err)
+	}
+	sampler := startHeapSampler(profileMemorySampleInterval)
+	start := time.Now()
+	res, err := s.GenerateManifest(ctx, request)
+	duration := time.Since(start)
+	peakMemory := sampler.stop()
+	pprof.StopCPUProfile()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &apiclient.ProfileResult{
+		DurationMs:      duration.Milliseconds(),
+		PeakMemoryBytes: int64(peakMemory),
+		CpuProfile:      cpuProfile.Bytes(),
+		Revision:        res.Revision,
+		Manifests:       int64(len(res.Manifests)),
+	}
+	key, err := s.cache.SetManifestGenerationProfile(request.AppName, s.now(), result)
+	if err != nil {
+		log.Warnf("manifest generation profile cache set error %s: %v", request.AppName, err)
+	} else {
+		result.CacheKey = key
+	}
+	return result, nil
+}
+
+// heapSampler samples the heap memory of the process periodically, and records its peak
+type heapSampler struct {
+	done chan struct{}
+	wg   sync.WaitGroup
+	peak uint64
+}
+
+// startHeapSampler starts sampling the heap memory at the given interval until the sampler is stopped
+func startHeapSampler(interval time.Duration) *heapSampler {
+	sampler := &heapSampler{done: make(chan struct{})}
+	sampler.sample()
+	sampler.wg.Add(1)
+	go func() {
+		defer sampler.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				sampler.sample()
+			case <-sampler.done:
+				return
+			}
+		}
+	}()
+	return sampler
+}
+
+// sample reads the heap memory through the runtime metrics, which unlike runtime.ReadMemStats do not stop the world
+func (h *heapSampler) sample() {
+	samples := []metrics.Sample{{Name: heapObjectsMetric}}
+	metrics.Read(samples)
+	if samples[0].Value.Kind() == metrics.KindUint64 {
+		if value := samples[0].Value.Uint64(); value > h.peak {
+			h.peak = value
+		}
+	}
+}
+
+// stop stops sampling, and returns the peak heap memory sampled in bytes
+func (h *heapSampler) stop() uint64 {
+	close(h.done)
+	h.wg.Wait()
+	h.sample()
+	return h.peak
+}
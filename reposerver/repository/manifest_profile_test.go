@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+)
+
+// installSlowHelm installs a helm binary which renders a ConfigMap after sleeping for the given number of seconds
+func installSlowHelm(t *testing.T, seconds string) {
+	t.Helper()
+	binDir := t.TempDir()
+	script := "#!/bin/sh\nsleep " + seconds + "\nprintf 'apiVersion: v1\\nkind: ConfigMap\\nmetadata:\\n  name: my-map\\n'\n"
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "helm"), []byte(script), 0o755))
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func profileRequest() *apiclient.ProfileRequest {
+	return &apiclient.ProfileRequest{ManifestRequest: &apiclient.ManifestRequest{
+		Repo:              &argoappv1.Repository{},
+		AppName:           "guestbook",
+		ApplicationSource: &argoappv1.ApplicationSource{Path: ".", Helm: &argoappv1.ApplicationSourceHelm{}},
+	}}
+}
+
+func TestProfileManifestGeneration(t *testing.T) {
+	t.Run("Duration proportional to the generation time", func(t *testing.T) {
+		durations := map[string]int64{}
+		for _, seconds := range []string{"0.2", "0.8"} {
+			installSlowHelm(t, seconds)
+			service := newService(t, "./testdata/my-chart")
+
+			start := time.Now()
+			res, err := service.ProfileManifestGeneration(context.Background(), profileRequest())
+			elapsed := time.Since(start)
+			require.NoError(t, err)
+
+			assert.Equal(t, int64(1), res.Manifests)
+			assert.Positive(t, res.PeakMemoryBytes)
+			assert.LessOrEqual(t, res.DurationMs, elapsed.Milliseconds())
+			assert.GreaterOrEqual(t, res.DurationMs, elapsed.Milliseconds()*8/10)
+			durations[seconds] = res.DurationMs
+		}
+		// the fixed costs of the generation are small compared to the time helm sleeps
+		ratio := float64(durations["0.8"]) / float64(durations["0.2"])
+		assert.InDelta(t, 4, ratio, 1.5)
+	})
+
+	t.Run("CPU profile in the pprof format", func(t *testing.T) {
+		installSlowHelm(t, "0")
+		service := newService(t, "./testdata/my-chart")
+		res, err := service.ProfileManifestGeneration(context.Background(), profileRequest())
+		require.NoError(t, err)
+		// pprof profiles are gzip compressed protocol buffers
+		reader, err := gzip.NewReader(bytes.NewReader(res.CpuProfile))
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+	})
+
+	t.Run("Profile stored in the cache", func(t *testing.T) {
+		installSlowHelm(t, "0")
+		service := newService(t, "./testdata/my-chart")
+		now := time.Unix(1700000000, 0)
+		service.now = func() time.Time { return now }
+		res, err := service.ProfileManifestGeneration(context.Background(), profileRequest())
+		require.NoError(t, err)
+		assert.Equal(t, "mfstprofile|guestbook|1700000000", res.CacheKey)
+		cached, err := service.cache.GetManifestGenerationProfile("guestbook", now)
+		require.NoError(t, err)
+		assert.Equal(t, res.DurationMs, cached.DurationMs)
+		assert.Equal(t, res.CpuProfile, cached.CpuProfile)
+	})
+
+	t.Run("Cache is bypassed", func(t *testing.T) {
+		installSlowHelm(t, "0")
+		service := newService(t, "./testdata/my-chart")
+		request := profileRequest()
+		_, err := service.GenerateManifest(context.Background(), request.ManifestRequest)
+		require.NoError(t, err)
+		installSlowHelm(t, "0.3")
+		res, err := service.ProfileManifestGeneration(context.Background(), profileRequest())
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, res.DurationMs, int64(300))
+	})
+
+	t.Run("Generation errors are returned", func(t *testing.T) {
+		service := newService(t, "./testdata/my-chart")
+		request := profileRequest()
+		request.ManifestRequest.ApplicationSource.Path = "missing"
+		_, err := service.ProfileManifestGeneration(context.Background(), request)
+		require.Error(t, err)
+		// the profile lock is released
+		assert.True(t, profileLock.TryLock())
+		profileLock.Unlock()
+	})
+
+	t.Run("Single profile at a time", func(t *testing.T) {
+		service := newService(t, "./testdata/my-chart")
+		profileLock.Lock()
+		defer profileLock.Unlock()
+		_, err := service.ProfileManifestGeneration(context.Background(), profileRequest())
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("Missing request", func(t *testing.T) {
+		service := newService(t, "./testdata/my-chart")
+		_, err := service.ProfileManifestGeneration(context.Background(), &apiclient.ProfileRequest{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
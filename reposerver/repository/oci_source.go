@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/registry/remote"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/reposerver/cache"
+	"github.com/argoproj/argo-cd/v2/util/helm"
+)
+
+// ociSourceDefaultTag is the tag of the artifact of the OCI sources which do not set one
+const ociSourceDefaultTag = "latest"
+
+// generateOCIManifests returns the manifests extracted from the artifact of the OCI source of the request, without
+// any Git repository operation. The revision of the response is the digest of the artifact.
+func (s *Service) generateOCIManifests(ctx context.Context, q *apiclient.ManifestRequest) (*apiclient.ManifestResponse, error) {
+	source := q.ApplicationSource.OCI
+	repo, err := s.newOCISourceRepository(source.Image, q.Repo)
+	if err != nil {
+		return nil, err
+	}
+	dgst, err := s.resolveOCISourceDigest(ctx, repo, source, q.NoCache || q.NoRevisionCache)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.getOCISourceManifests(ctx, repo, source.Image, dgst, q.NoCache)
+	if err != nil {
+		return nil, err
+	}
+	targetObjs, err := s.parsePool.Parse(data)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse manifests of %s@%s: %v", source.Image, dgst, err)
+	}
+	manifests, err := marshalManifests(targetObjs, q)
+	if err != nil {
+		return nil, err
+	}
+	return &apiclient.ManifestResponse{
+		Manifests:  manifests,
+		Revision:   dgst,
+		SourceType: string(v1alpha1.ApplicationSourceTypeOCI),
+	}, nil
+}
+
+// newOCISourceRepository returns a client of the OCI repository of the image, which authenticates with the
+// credentials of the repository of the source like Helm OCI repositories
+func (s *Service) newOCISourceRepository(image string, repo *v1alpha1.Repository) (*remote.Repository, error) {
+	if repo == nil {
+		repo = &v1alpha1.Repository{Repo: image}
+	}
+	ociRepo, err := s.newOCIRepository(image, repo.GetHelmCreds(), repo.Proxy)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid image %q of OCI source: %v", image, err)
+	}
+	return ociRepo, nil
+}
+
+// resolveOCISourceDigest returns the digest of the artifact the tag of the OCI source resolves to. Resolved tags are
+// cached for the poll interval of OCI sources, so that the registry is polled for new artifacts once it expires.
+func (s *Service) resolveOCISourceDigest(ctx context.Context, repo *remote.Repository, source *v1alpha1.ApplicationSourceOCI, noCache bool) (string, error) {
+	tag := source.Tag
+	if tag == "" {
+		tag = ociSourceDefaultTag
+	}
+	if _, err := digest.Parse(tag); err == nil {
+		return tag, nil
+	}
+
+	pollInterval := s.initConstants.OCISourcePollInterval
+	if !noCache && pollInterval > 0 {
+		dgst, err := s.cache.GetOCISourceDigest(source.Image, tag)
+		if err == nil {
+			return dgst, nil
+		}
+		if !errors.Is(err, cache.ErrCacheMiss) {
+			log.Warnf("OCI source digest cache error %s:%s: %v", source.Image, tag, err)
+		}
+	}
+
+	reference, err := resolveOCISourceTag(ctx, repo, tag)
+	if err != nil {
+		return "", err
+	}
+	desc, err := repo.Resolve(ctx, reference)
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "error resolving tag %s of %s: %v", reference, source.Image, err)
+	}
+	dgst := desc.Digest.String()
+
+	if pollInterval > 0 {
+		if err := s.cache.SetOCISourceDigest(source.Image, tag, dgst, pollInterval); err != nil {
+			log.Warnf("OCI source digest cache set error %s:%s: %v", source.Image, tag, err)
+		}
+	}
+	return dgst, nil
+}
+
+// resolveOCISourceTag returns the given tag, or the highest tag of the repository matching it if it is a semantic
+// version constraint rather than a tag. Like Helm charts, the tags use an underscore instead of the plus sign of the
+// build metadata of semantic versions.
+func resolveOCISourceTag(ctx context.Context, repo *remote.Repository, tag string) (string, error) {
+	if _, err := semver.NewVersion(tag); err == nil {
+		return tag, nil
+	}
+	constraints, err := semver.NewConstraint(tag)
+	if err != nil {
+		return tag, nil
+	}
+
+	versionTags := map[string]string{}
+	tags := helm.TagsList{}
+	err = repo.Tags(ctx, "", func(page []string) error {
+		for _, t := range page {
+			version := strings.ReplaceAll(t, "_", "+")
+			versionTags[version] = t
+			tags.Tags = append(tags.Tags, version)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", status.Errorf(codes.Unavailable, "error listing tags of %s: %v", repo.Reference.String(), err)
+	}
+	version, err := tags.MaxVersion(constraints)
+	if err != nil {
+		return "", status.Errorf(codes.NotFound, "no tag of %s matches %s: %v", repo.Reference.String(), tag, err)
+	}
+	return versionTags[version.Original()], nil
+}
+
+// getOCISourceManifests returns the manifests of the artifact with the given digest from the cache, or fetches them
+// from the registry. Artifacts are immutable, so their manifests are cached like the manifests of a Git revision.
+func (s *Service) getOCISourceManifests(ctx context.Context, repo *remote.Repository, image string, dgst string, noCache bool) ([]byte, error) {
+	if !noCache {
+		data, err := s.cache.GetOCISourceManifests(image, dgst)
+		if err == nil {
+			return data, nil
+		}
+		if !errors.Is(err, cache.ErrCacheMiss) {
+			log.Warnf("manifests cache error %s@%s: %v", image, dgst, err)
+		}
+	}
+
+	data, err := fetchOCISourceManifests(ctx, repo, dgst, s.initConstants.MaxCombinedDirectoryManifestsSize.Value())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.cache.SetOCISourceManifests(image, dgst, data); err != nil {
+		log.Warnf("manifests cache set error %s@%s: %v", image, dgst, err)
+	}
+	return data, nil
+}
+
+// fetchOCISourceManifests returns the YAML and JSON files of the tarball layers of the artifact with the given digest,
+// as a multi-document YAML file. The files are sorted by path, and their total size is limited to maxSize bytes, zero
+// means no limit.
+func fetchOCISourceManifests(ctx context.Context, repo *remote.Repository, dgst string, maxSize int64) ([]byte, error) {
+	files, err := fetchOCIArtifactFiles(ctx, repo, dgst, maxSize, isOCISourceManifestFile)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var manifests bytes.Buffer
+	for _, name := range names {
+		manifests.WriteString("---\n")
+		manifests.Write(files[name])
+		manifests.WriteString("\n")
+	}
+	return manifests.Bytes(), nil
+}
+
+// fetchOCIArtifactFiles returns the files of the tarball layers of the artifact with the given digest which are
+// included by the given function, by their path. Their total size is limited to maxSize bytes, zero means no limit.
+func fetchOCIArtifactFiles(ctx context.Context, repo *remote.Repository, dgst string, maxSize int64, include func(name string) bool) (map[string][]byte, error) {
+	_, data, err := oras.FetchBytes(ctx, repo, dgst, oras.DefaultFetchBytesOptions)
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "error fetching artifact %s@%s: %v", repo.Reference.String(), dgst, err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid manifest of artifact %s@%s: %v", repo.Reference.String(), dgst, err)
+	}
+
+	files := map[string][]byte{}
+	var size int64
+	tarballs := 0
+	for _, layer := range manifest.Layers {
+		if !strings.Contains(layer.MediaType, "tar") {
+			continue
+		}
+		tarballs++
+		if maxSize > 0 && size+layer.Size > maxSize {
+			return nil, status.Errorf(codes.ResourceExhausted, "manifests of artifact %s@%s exceed the maximum size of %d bytes", repo.Reference.String(), dgst, maxSize)
+		}
+		layerData, err := content.FetchAll(ctx, repo, layer)
+		if err != nil {
+			return nil, status.Errorf(codes.Unavailable, "error fetching layer %s of artifact %s@%s: %v", layer.Digest, repo.Reference.String(), dgst, err)
+		}
+		if err := extractOCISourceLayer(layerData, files, &size, maxSize, include); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "error extracting layer %s of artifact %s@%s: %v", layer.Digest, repo.Reference.String(), dgst, err)
+		}
+	}
+	if tarballs == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "artifact %s@%s has no tarball layer", repo.Reference.String(), dgst)
+	}
+	return files, nil
+}
+
+// extractOCISourceLayer adds the files of the tarball, which may be gzip compressed, included by the given function to
+// the given files. The size of the extracted files is added to size, and must not exceed maxSize bytes unless it is
+// zero.
+func extractOCISourceLayer(data []byte, files map[string][]byte, size *int64, maxSize int64, include func(name string) bool) error {
+	var reader io.Reader = bytes.NewReader(data)
+	if len(data) > 1 && data[0] == 0x1f && data[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return fmt.Errorf("error reading gzip: %w", err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error reading tar: %w", err)
+		}
+		name := path.Clean(strings.TrimPrefix(header.Name, "/"))
+		if header.Typeflag != tar.TypeReg || !include(name) {
+			continue
+		}
+		remaining := int64(-1)
+		if maxSize > 0 {
+			remaining = maxSize - *size
+		}
+		fileReader := io.Reader(tarReader)
+		if remaining >= 0 {
+			fileReader = io.LimitReader(tarReader, remaining+1)
+		}
+		fileData, err := io.ReadAll(fileReader)
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", name, err)
+		}
+		*size += int64(len(fileData))
+		if maxSize > 0 && *size > maxSize {
+			return fmt.Errorf("manifests exceed the maximum size of %d bytes", maxSize)
+		}
+		files[name] = fileData
+	}
+}
+
+// isOCISourceManifestFile returns true if the file at the given path of a tarball holds manifests. Hidden files and
+// directories are skipped.
+func isOCISourceManifestFile(name string) bool {
+	for _, segment := range strings.Split(name, "/") {
+		if strings.HasPrefix(segment, ".") {
+			return false
+		}
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml", ".json":
+		return true
+	}
+	return false
+}
+
+// testOCIManifestsRepository verifies the access to an OCI repository holding the artifacts of OCI sources by listing
+// its tags
+func (s *Service) testOCIManifestsRepository(ctx context.Context, repo *v1alpha1.Repository) error {
+	ociRepo, err := s.newOCIRepository(repo.Repo, repo.GetHelmCreds(), repo.Proxy)
+	if err != nil {
+		return err
+	}
+	return ociRepo.Tags(ctx, "", func([]string) error {
+		return nil
+	})
+}
@@ -0,0 +1,309 @@
+package repository
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"oras.land/oras-go/v2/registry/remote"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+	"github.com/argoproj/argo-cd/v2/util/helm"
+)
+
+// mockOCIRegistry is a read-only OCI registry serving the artifacts of a single repository
+type mockOCIRegistry struct {
+	lock      sync.Mutex
+	manifests map[string][]byte
+	blobs     map[digest.Digest][]byte
+	requests  int
+	// username and password are the credentials required by the registry, if not empty
+	username string
+	password string
+}
+
+func (r *mockOCIRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.requests++
+	if r.username != "" {
+		if username, password, ok := req.BasicAuth(); !ok || username != r.username || password != r.password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="mock"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+	path := strings.TrimPrefix(req.URL.Path, "/v2/argoproj/manifests/")
+	var data []byte
+	mediaType := "application/octet-stream"
+	switch {
+	case path == "tags/list":
+		tags := []string{}
+		for tag := range r.manifests {
+			tags = append(tags, tag)
+		}
+		sort.Strings(tags)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": "argoproj/manifests", "tags": tags})
+		return
+	case strings.HasPrefix(path, "manifests/"):
+		ref := strings.TrimPrefix(path, "manifests/")
+		data = r.manifests[ref]
+		if data == nil {
+			data = r.blobs[digest.Digest(ref)]
+		}
+		mediaType = ocispec.MediaTypeImageManifest
+	case strings.HasPrefix(path, "blobs/"):
+		data = r.blobs[digest.Digest(strings.TrimPrefix(path, "blobs/"))]
+	}
+	if data == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Docker-Content-Digest", digest.FromBytes(data).String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if req.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+}
+
+// push stores an artifact with a layer holding the given files as a gzip compressed tarball, and returns its digest
+func (r *mockOCIRegistry) push(t *testing.T, tag string, files map[string]string) string {
+	t.Helper()
+	var layer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&layer)
+	tarWriter := tar.NewWriter(gzipWriter)
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name]))}))
+		_, err := tarWriter.Write([]byte(files[name]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, tarWriter.Close())
+	require.NoError(t, gzipWriter.Close())
+
+	config := []byte("{}")
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.Descriptor{MediaType: ocispec.MediaTypeEmptyJSON, Digest: digest.FromBytes(config), Size: int64(len(config))},
+		Layers: []ocispec.Descriptor{
+			{MediaType: ocispec.MediaTypeImageLayerGzip, Digest: digest.FromBytes(layer.Bytes()), Size: int64(layer.Len())},
+		},
+	})
+	require.NoError(t, err)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.blobs[digest.FromBytes(config)] = config
+	r.blobs[digest.FromBytes(layer.Bytes())] = layer.Bytes()
+	r.blobs[digest.FromBytes(manifest)] = manifest
+	r.manifests[tag] = manifest
+	return digest.FromBytes(manifest).String()
+}
+
+func (r *mockOCIRegistry) requestCount() int {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.requests
+}
+
+// newOCISourceService returns a service accessing the mock registry over plain HTTP, and the image of the repository
+// of the registry
+func newOCISourceService(t *testing.T) (*Service, *mockOCIRegistry, string) {
+	t.Helper()
+	registry := &mockOCIRegistry{manifests: map[string][]byte{}, blobs: map[digest.Digest][]byte{}}
+	server := httptest.NewServer(registry)
+	t.Cleanup(server.Close)
+
+	service := newService(t, ".")
+	service.newOCIRepository = func(reference string, creds helm.Creds, proxy string) (*remote.Repository, error) {
+		repo, err := helm.NewOCIRepository(reference, creds, proxy)
+		if err != nil {
+			return nil, err
+		}
+		repo.PlainHTTP = true
+		return repo, nil
+	}
+	return service, registry, strings.TrimPrefix(server.URL, "http://") + "/argoproj/manifests"
+}
+
+func ociSourceManifestRequest(image, tag string) *apiclient.ManifestRequest {
+	return &apiclient.ManifestRequest{
+		Repo:              &argoappv1.Repository{Repo: image, Type: "helm", EnableOCI: true, OCIType: argoappv1.OCITypeManifests},
+		ApplicationSource: &argoappv1.ApplicationSource{RepoURL: image, OCI: &argoappv1.ApplicationSourceOCI{Image: image, Tag: tag}},
+		AppLabelKey:       common.LabelKeyAppInstance,
+		AppName:           "oci",
+		TrackingMethod:    string(argo.TrackingMethodLabel),
+	}
+}
+
+const ociSourceConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+`
+
+func configMapManifest(name string) string {
+	return strings.Replace(ociSourceConfigMap, "%s", name, 1)
+}
+
+func TestGenerateManifest_OCI(t *testing.T) {
+	service, registry, image := newOCISourceService(t)
+	latest := registry.push(t, "latest", map[string]string{
+		"manifests/b.yaml":   configMapManifest("second"),
+		"manifests/a.yml":    configMapManifest("first"),
+		"service.json":       `{"apiVersion":"v1","kind":"Service","metadata":{"name":"third"}}`,
+		"README.md":          "# Manifests",
+		".hidden/skip.yaml":  configMapManifest("hidden"),
+		"manifests/.ci.yaml": configMapManifest("hidden"),
+	})
+	registry.push(t, "1.0.0", map[string]string{"cm.yaml": configMapManifest("v1.0.0")})
+	v110 := registry.push(t, "1.1.0", map[string]string{"cm.yaml": configMapManifest("v1.1.0")})
+	registry.push(t, "2.0.0", map[string]string{"cm.yaml": configMapManifest("v2.0.0")})
+	registry.push(t, "1.2.0-rc.1", map[string]string{"cm.yaml": configMapManifest("v1.2.0-rc.1")})
+
+	t.Run("Default tag", func(t *testing.T) {
+		res, err := service.GenerateManifest(context.Background(), ociSourceManifestRequest(image, ""))
+		require.NoError(t, err)
+		assert.Equal(t, string(argoappv1.ApplicationSourceTypeOCI), res.SourceType)
+		assert.Equal(t, latest, res.Revision)
+		require.Len(t, res.Manifests, 3)
+		assert.Contains(t, res.Manifests[0], `"name":"first"`)
+		assert.Contains(t, res.Manifests[1], `"name":"second"`)
+		assert.Contains(t, res.Manifests[2], `"name":"third"`)
+		assert.Contains(t, res.Manifests[2], `"app.kubernetes.io/instance":"oci"`)
+	})
+
+	t.Run("Tag", func(t *testing.T) {
+		res, err := service.GenerateManifest(context.Background(), ociSourceManifestRequest(image, "1.0.0"))
+		require.NoError(t, err)
+		require.Len(t, res.Manifests, 1)
+		assert.Contains(t, res.Manifests[0], `"name":"v1.0.0"`)
+	})
+
+	t.Run("Semantic version constraint", func(t *testing.T) {
+		res, err := service.GenerateManifest(context.Background(), ociSourceManifestRequest(image, "~1"))
+		require.NoError(t, err)
+		assert.Equal(t, v110, res.Revision)
+		require.Len(t, res.Manifests, 1)
+		assert.Contains(t, res.Manifests[0], `"name":"v1.1.0"`)
+	})
+
+	t.Run("Digest", func(t *testing.T) {
+		res, err := service.GenerateManifest(context.Background(), ociSourceManifestRequest(image, v110))
+		require.NoError(t, err)
+		assert.Equal(t, v110, res.Revision)
+		assert.Contains(t, res.Manifests[0], `"name":"v1.1.0"`)
+	})
+
+	t.Run("No matching tag", func(t *testing.T) {
+		_, err := service.GenerateManifest(context.Background(), ociSourceManifestRequest(image, ">=3.0.0"))
+		require.ErrorContains(t, err, "no tag of")
+	})
+
+	t.Run("Missing tag", func(t *testing.T) {
+		_, err := service.GenerateManifest(context.Background(), ociSourceManifestRequest(image, "missing"))
+		require.ErrorContains(t, err, "error resolving tag missing")
+	})
+}
+
+func TestGenerateManifest_OCIPollInterval(t *testing.T) {
+	service, registry, image := newOCISourceService(t)
+	service.initConstants.OCISourcePollInterval = time.Minute
+	first := registry.push(t, "1.0.0", map[string]string{"cm.yaml": configMapManifest("first")})
+
+	res, err := service.GenerateManifest(context.Background(), ociSourceManifestRequest(image, "1.x"))
+	require.NoError(t, err)
+	assert.Equal(t, first, res.Revision)
+
+	// the resolved tag is cached until the registry is polled again, and the manifests of the digest are cached
+	second := registry.push(t, "1.1.0", map[string]string{"cm.yaml": configMapManifest("second")})
+	requests := registry.requestCount()
+	res, err = service.GenerateManifest(context.Background(), ociSourceManifestRequest(image, "1.x"))
+	require.NoError(t, err)
+	assert.Equal(t, first, res.Revision)
+	assert.Contains(t, res.Manifests[0], `"name":"first"`)
+	assert.Equal(t, requests, registry.requestCount())
+
+	// a hard refresh resolves the tag again
+	req := ociSourceManifestRequest(image, "1.x")
+	req.NoCache = true
+	res, err = service.GenerateManifest(context.Background(), req)
+	require.NoError(t, err)
+	assert.Equal(t, second, res.Revision)
+	assert.Contains(t, res.Manifests[0], `"name":"second"`)
+}
+
+func TestGenerateManifest_OCICredentials(t *testing.T) {
+	service, registry, image := newOCISourceService(t)
+	registry.push(t, "latest", map[string]string{"cm.yaml": configMapManifest("first")})
+	registry.username = "admin"
+	registry.password = "secret"
+
+	_, err := service.GenerateManifest(context.Background(), ociSourceManifestRequest(image, ""))
+	require.ErrorContains(t, err, "credential not found")
+
+	req := ociSourceManifestRequest(image, "")
+	req.Repo.Username = "admin"
+	req.Repo.Password = "secret"
+	res, err := service.GenerateManifest(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, res.Manifests, 1)
+}
+
+func TestGenerateManifest_OCIMaxSize(t *testing.T) {
+	service, registry, image := newOCISourceService(t)
+	service.initConstants.MaxCombinedDirectoryManifestsSize = resource.MustParse("100")
+	registry.push(t, "latest", map[string]string{"a.yaml": configMapManifest("first"), "b.yaml": configMapManifest("second")})
+
+	_, err := service.GenerateManifest(context.Background(), ociSourceManifestRequest(image, ""))
+	require.ErrorContains(t, err, "exceed the maximum size of 100 bytes")
+}
+
+func TestGetAppDetails_OCI(t *testing.T) {
+	service := newService(t, ".")
+	res, err := service.GetAppDetails(context.Background(), &apiclient.RepoServerAppDetailsQuery{
+		Repo:   &argoappv1.Repository{Repo: "ghcr.io/argoproj/manifests"},
+		Source: &argoappv1.ApplicationSource{RepoURL: "ghcr.io/argoproj/manifests", OCI: &argoappv1.ApplicationSourceOCI{Image: "ghcr.io/argoproj/manifests"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, string(argoappv1.ApplicationSourceTypeOCI), res.Type)
+}
+
+func TestTestRepository_OCIManifests(t *testing.T) {
+	service, registry, image := newOCISourceService(t)
+	registry.username = "admin"
+	registry.password = "secret"
+	repo := &argoappv1.Repository{Repo: image, Type: "helm", EnableOCI: true, OCIType: argoappv1.OCITypeManifests, Username: "admin", Password: "secret"}
+
+	_, err := service.TestRepository(context.Background(), &apiclient.TestRepositoryRequest{Repo: repo})
+	require.NoError(t, err)
+
+	repo.Password = "wrong"
+	_, err = service.TestRepository(context.Background(), &apiclient.TestRepositoryRequest{Repo: repo})
+	require.ErrorContains(t, err, "401")
+}
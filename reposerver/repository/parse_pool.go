@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	goio "io"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultManifestParseWorkers is the default number of YAML documents parsed concurrently
+const DefaultManifestParseWorkers = 4
+
+// parseJob is a YAML document waiting to be parsed by a worker of a ParseWorkerPool
+type parseJob struct {
+	index   int
+	doc     []byte
+	results chan<- parseResult
+}
+
+// parseResult is the object parsed from the YAML document at the given index, nil if the document is empty
+type parseResult struct {
+	index int
+	obj   *unstructured.Unstructured
+	err   error
+}
+
+// ParseWorkerPool parses the documents of multi-document YAML manifests concurrently. The workers of a pool are shared
+// by all the manifests parsed with it, which bounds the number of documents parsed at the same time by the repo-server.
+type ParseWorkerPool struct {
+	jobs chan parseJob
+}
+
+// NewParseWorkerPool starts a pool of the given number of workers, which run until the pool is closed. A nil pool,
+// which parses documents sequentially, is returned if workers is lower than 2.
+func NewParseWorkerPool(workers int) *ParseWorkerPool {
+	if workers < 2 {
+		return nil
+	}
+	p := &ParseWorkerPool{jobs: make(chan parseJob)}
+	for i := 0; i < workers; i++ {
+		go p.runWorker()
+	}
+	return p
+}
+
+// Close stops the workers of the pool once the queued documents are parsed
+func (p *ParseWorkerPool) Close() {
+	if p != nil {
+		close(p.jobs)
+	}
+}
+
+// runWorker parses documents until the pool is closed
+func (p *ParseWorkerPool) runWorker() {
+	for job := range p.jobs {
+		obj, err := parseYAMLDocument(job.doc)
+		job.results <- parseResult{index: job.index, obj: obj, err: err}
+	}
+}
+
+// Parse splits the given YAML manifests into documents, parses the documents concurrently and returns the resulting
+// objects in the order of the documents. The errors of all the documents which could not be parsed are returned
+// together with the objects of the other documents. JSON manifests, and all manifests if the pool is nil, are parsed
+// sequentially.
+func (p *ParseWorkerPool) Parse(data []byte) ([]*unstructured.Unstructured, error) {
+	trimmed := bytes.TrimSpace(data)
+	if p == nil || len(trimmed) == 0 || trimmed[0] == '{' || trimmed[0] == '[' {
+		return kube.SplitYAML(data)
+	}
+	docs, err := splitYAMLDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan parseResult, len(docs))
+	for i, doc := range docs {
+		p.jobs <- parseJob{index: i, doc: doc, results: results}
+	}
+	parsed := make([]parseResult, len(docs))
+	for range docs {
+		result := <-results
+		parsed[result.index] = result
+	}
+
+	objs := make([]*unstructured.Unstructured, 0, len(docs))
+	var errs []error
+	for i, result := range parsed {
+		if result.err != nil {
+			errs = append(errs, fmt.Errorf("failed to unmarshal manifest of document %d: %w", i+1, result.err))
+			continue
+		}
+		if result.obj != nil {
+			objs = append(objs, result.obj)
+		}
+	}
+	return objs, errors.Join(errs...)
+}
+
+// splitYAMLDocuments splits the given YAML manifests into their documents, without parsing them
+func splitYAMLDocuments(data []byte) ([][]byte, error) {
+	reader := kubeyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(data)))
+	var docs [][]byte
+	for {
+		doc, err := reader.Read()
+		if errors.Is(err, goio.EOF) {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to split manifests: %w", err)
+		}
+		docs = append(docs, doc)
+	}
+}
+
+// parseYAMLDocument parses a single YAML document. A nil object is returned if the document is empty or null.
+func parseYAMLDocument(doc []byte) (*unstructured.Unstructured, error) {
+	jsonData, err := yaml.YAMLToJSON(doc)
+	if err != nil {
+		return nil, err
+	}
+	jsonData = bytes.TrimSpace(jsonData)
+	if len(jsonData) == 0 || bytes.Equal(jsonData, []byte("null")) {
+		return nil, nil
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(jsonData); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generateYAMLDocuments returns multi-document YAML manifests of the given number of ConfigMaps
+func generateYAMLDocuments(count int) []byte {
+	var sb strings.Builder
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&sb, `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config-%d
+  labels:
+    app: guestbook
+    index: "%d"
+data:
+  key: value-%d
+  nested: |
+    line one
+    line two
+`, i, i, i)
+	}
+	return []byte(sb.String())
+}
+
+func TestParseWorkerPool_Parse(t *testing.T) {
+	pool := NewParseWorkerPool(4)
+	defer pool.Close()
+
+	t.Run("documents in order", func(t *testing.T) {
+		objs, err := pool.Parse(generateYAMLDocuments(50))
+		require.NoError(t, err)
+		require.Len(t, objs, 50)
+		for i, obj := range objs {
+			assert.Equal(t, fmt.Sprintf("config-%d", i), obj.GetName())
+		}
+	})
+
+	t.Run("same objects as sequential parsing", func(t *testing.T) {
+		data := []byte("# comment only\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: svc\n---\n---\nnull\n---\napiVersion: v1\nkind: List\nitems: []\n")
+		expected, err := kube.SplitYAML(data)
+		require.NoError(t, err)
+		objs, err := pool.Parse(data)
+		require.NoError(t, err)
+		assert.Equal(t, expected, objs)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		objs, err := pool.Parse([]byte(`{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "a"}} {"apiVersion": "v1", "kind": "Service", "metadata": {"name": "b"}}`))
+		require.NoError(t, err)
+		require.Len(t, objs, 2)
+		assert.Equal(t, "b", objs[1].GetName())
+	})
+
+	t.Run("errors of all documents", func(t *testing.T) {
+		data := []byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: a\n---\nkind: [\n---\napiVersion: v1\nkind: Service\nmetadata:\n  name: b\n---\nfoo: bar: baz\n")
+		objs, err := pool.Parse(data)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to unmarshal manifest of document 2")
+		assert.Contains(t, err.Error(), "failed to unmarshal manifest of document 4")
+		require.Len(t, objs, 2)
+		assert.Equal(t, "a", objs[0].GetName())
+		assert.Equal(t, "b", objs[1].GetName())
+	})
+
+	t.Run("concurrent calls", func(t *testing.T) {
+		errs := make(chan error, 10)
+		for i := 0; i < 10; i++ {
+			go func(count int) {
+				objs, err := pool.Parse(generateYAMLDocuments(count))
+				if err == nil && len(objs) != count {
+					err = fmt.Errorf("expected %d objects, got %d", count, len(objs))
+				}
+				errs <- err
+			}(10 + i)
+		}
+		for i := 0; i < 10; i++ {
+			require.NoError(t, <-errs)
+		}
+	})
+}
+
+func TestParseWorkerPool_Sequential(t *testing.T) {
+	pool := NewParseWorkerPool(1)
+	assert.Nil(t, pool)
+	objs, err := pool.Parse(generateYAMLDocuments(3))
+	require.NoError(t, err)
+	assert.Len(t, objs, 3)
+}
+
+func BenchmarkParseWorkerPool(b *testing.B) {
+	data := generateYAMLDocuments(200)
+	b.Run("sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := kube.SplitYAML(data); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	for _, workers := range []int{2, DefaultManifestParseWorkers, 8} {
+		b.Run(fmt.Sprintf("%d workers", workers), func(b *testing.B) {
+			pool := NewParseWorkerPool(workers)
+			defer pool.Close()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := pool.Parse(data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
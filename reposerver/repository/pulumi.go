@@ -0,0 +1,372 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/cache"
+	executil "github.com/argoproj/argo-cd/v2/util/exec"
+	pathutil "github.com/argoproj/argo-cd/v2/util/io/path"
+)
+
+const (
+	defaultPulumiBinary = "pulumi"
+	// pulumiStackName is the name of the ephemeral stack the programs are previewed with
+	pulumiStackName = "argocd"
+	// pulumiUnknownValue is the value the preview holds for the outputs which are only known once the resources are
+	// deployed
+	pulumiUnknownValue = "04da6b54-80e4-46f7-96ec-b56ff0331ba9"
+)
+
+// ErrPulumiDisabled is returned for the applications of type Pulumi unless they are enabled on the repo server
+var ErrPulumiDisabled = errors.New("applications of type Pulumi are disabled: the repo-server must be started with --enable-pulumi to run the code of Pulumi programs")
+
+var (
+	// pulumiVersionRegex matches the output of `pulumi version`, e.g. "v3.130.0"
+	pulumiVersionRegex = regexp.MustCompile(`(v?[0-9]+\.[0-9]+\.[0-9]+\S*)`)
+	// pulumiRuntimes are the runtimes of the programs which can be previewed
+	pulumiRuntimes = map[string]bool{"nodejs": true, "python": true, "go": true}
+	// pulumiEnvPassthrough are the variables of the environment of the repo server which are passed to pulumi, so that
+	// preinstalled plugins and language toolchains are found. Credentials of the repo server are never passed.
+	pulumiEnvPassthrough = []string{"PATH", "PULUMI_HOME", "GOPATH", "GOMODCACHE", "GOCACHE", "GOPROXY", "NODE_PATH"}
+)
+
+// PulumiGenerator renders the manifests of applications from the preview of a Pulumi program with the pulumi command
+// line tool
+type PulumiGenerator struct {
+	// binaryPath is the path of the pulumi binary, the binary is looked up in the PATH when empty
+	binaryPath string
+	// cache stores the manifests previewed from a program by its hash, the programs are always previewed when nil
+	cache *cache.Cache
+}
+
+// NewPulumiGenerator returns a generator running the pulumi binary at the given path, or the one found in the PATH if
+// the path is empty. The previewed manifests are cached in the given cache if it is not nil.
+func NewPulumiGenerator(binaryPath string, cache *cache.Cache) *PulumiGenerator {
+	return &PulumiGenerator{binaryPath: binaryPath, cache: cache}
+}
+
+func (g *PulumiGenerator) getBinaryPath() string {
+	if g.binaryPath != "" {
+		return g.binaryPath
+	}
+	return defaultPulumiBinary
+}
+
+// resolveProgram returns the directory of the program of the source and checks that it is a Pulumi project of the
+// runtime of the source
+func (g *PulumiGenerator) resolveProgram(appPath, repoRoot string, source *v1alpha1.ApplicationSourcePulumi) (string, error) {
+	if source == nil || !pulumiRuntimes[source.Runtime] {
+		return "", errors.New("pulumi runtime must be one of nodejs, python or go")
+	}
+	entrypoint := "."
+	if source.Entrypoint != "" {
+		entrypoint = source.Entrypoint
+	}
+	resolved, err := pathutil.ResolveFileOrDirectoryPath(appPath, repoRoot, entrypoint)
+	if err != nil {
+		return "", fmt.Errorf("error resolving pulumi entrypoint %q: %w", entrypoint, err)
+	}
+	data, err := os.ReadFile(filepath.Join(string(resolved), "Pulumi.yaml"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("pulumi entrypoint %q has no Pulumi.yaml project file", entrypoint)
+		}
+		return "", fmt.Errorf("error reading pulumi project of entrypoint %q: %w", entrypoint, err)
+	}
+	var project struct {
+		Runtime interface{} `json:"runtime"`
+	}
+	if err := yaml.Unmarshal(data, &project); err != nil {
+		return "", fmt.Errorf("failed to parse pulumi project of entrypoint %q: %w", entrypoint, err)
+	}
+	// the runtime is either a name or an object with a name and options
+	runtime, _ := project.Runtime.(string)
+	if r, ok := project.Runtime.(map[string]interface{}); ok {
+		runtime, _ = r["name"].(string)
+	}
+	if runtime != source.Runtime {
+		return "", fmt.Errorf("pulumi project of entrypoint %q has runtime %q instead of %q", entrypoint, runtime, source.Runtime)
+	}
+	return string(resolved), nil
+}
+
+// Validate checks that the source references a Pulumi project of its runtime
+func (g *PulumiGenerator) Validate(appPath, repoRoot string, source *v1alpha1.ApplicationSourcePulumi) error {
+	_, err := g.resolveProgram(appPath, repoRoot, source)
+	return err
+}
+
+// Generate previews the program of the source and returns the Kubernetes resources it would create, together with the
+// executed command. The manifests are returned from the cache if the program and the options of the source did not
+// change, unless noCache is set.
+func (g *PulumiGenerator) Generate(appPath, repoRoot string, source *v1alpha1.ApplicationSourcePulumi, noCache bool) ([]*unstructured.Unstructured, string, error) {
+	programDir, err := g.resolveProgram(appPath, repoRoot, source)
+	if err != nil {
+		return nil, "", err
+	}
+	command := fmt.Sprintf("%s preview --json --diff --stack %s", g.getBinaryPath(), pulumiStackName)
+	hash, err := hashPulumiProgram(programDir, source)
+	if err != nil {
+		return nil, "", err
+	}
+	if g.cache != nil && !noCache {
+		data, err := g.cache.GetPulumiPreview(hash)
+		if err == nil {
+			objs, err := unmarshalPulumiManifests(data)
+			if err == nil {
+				return objs, command, nil
+			}
+			log.Warnf("Failed to parse cached pulumi preview of %s: %v", hash, err)
+		} else if !errors.Is(err, cache.ErrCacheMiss) {
+			log.Warnf("Failed to get pulumi preview of %s from cache: %v", hash, err)
+		}
+	}
+
+	out, err := g.preview(programDir, source)
+	if err != nil {
+		return nil, "", err
+	}
+	objs, err := parsePulumiPreview([]byte(out))
+	if err != nil {
+		return nil, "", err
+	}
+	if g.cache != nil {
+		data, err := json.Marshal(objs)
+		if err == nil {
+			err = g.cache.SetPulumiPreview(hash, data)
+		}
+		if err != nil {
+			log.Warnf("Failed to cache pulumi preview of %s: %v", hash, err)
+		}
+	}
+	return objs, command, nil
+}
+
+// preview previews a copy of the program: pulumi runs in a temporary directory holding the copy, its home directory and
+// the state of an ephemeral stack, with a minimal environment which does not pass the variables of the repo server.
+// The program still runs as the repo server process, with access to its file system and network, which is why Pulumi
+// applications must be enabled explicitly. It returns the JSON output of the preview.
+func (g *PulumiGenerator) preview(programDir string, source *v1alpha1.ApplicationSourcePulumi) (string, error) {
+	tempDir, err := os.MkdirTemp("", "pulumi")
+	if err != nil {
+		return "", fmt.Errorf("error creating pulumi sandbox: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tempDir); err != nil {
+			log.Warnf("Failed to remove pulumi sandbox %s: %v", tempDir, err)
+		}
+	}()
+	workDir := filepath.Join(tempDir, "program")
+	homeDir := filepath.Join(tempDir, "home")
+	stateDir := filepath.Join(tempDir, "state")
+	for _, dir := range []string{homeDir, stateDir} {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return "", fmt.Errorf("error creating pulumi sandbox: %w", err)
+		}
+	}
+	// stacks and their configuration are written next to the program, so the repository is never modified
+	err = walkSourceFiles(programDir, func(relPath string, info fs.FileInfo, data []byte) error {
+		if err := os.MkdirAll(filepath.Join(workDir, filepath.Dir(relPath)), 0o700); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(workDir, relPath), data, info.Mode().Perm())
+	})
+	if err != nil {
+		return "", fmt.Errorf("error copying pulumi program: %w", err)
+	}
+
+	env := []string{
+		"HOME=" + homeDir,
+		"PULUMI_HOME=" + filepath.Join(homeDir, ".pulumi"),
+		"PULUMI_BACKEND_URL=file://" + stateDir,
+		// the ephemeral stack only holds plain configuration, its secrets are never used
+		"PULUMI_CONFIG_PASSPHRASE=argocd",
+		"PULUMI_SKIP_UPDATE_CHECK=true",
+		"PULUMI_SKIP_CONFIRMATIONS=true",
+	}
+	// the PULUMI_HOME of the repo server, holding the preinstalled plugins, replaces the one of the sandbox if it is set
+	for _, name := range pulumiEnvPassthrough {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command(g.getBinaryPath(), append(args, "--non-interactive")...)
+		cmd.Dir = workDir
+		cmd.Env = env
+		return executil.Run(cmd)
+	}
+
+	if _, err := run("stack", "init", pulumiStackName); err != nil {
+		return "", fmt.Errorf("error creating pulumi stack: %w", err)
+	}
+	keys := make([]string, 0, len(source.Config))
+	for k := range source.Config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if _, err := run("config", "set", "--stack", pulumiStackName, "--", k, source.Config[k]); err != nil {
+			return "", fmt.Errorf("error setting pulumi config %q: %w", k, err)
+		}
+	}
+	out, err := run("preview", "--json", "--diff", "--stack", pulumiStackName)
+	if err != nil {
+		return "", fmt.Errorf("error previewing pulumi program: %w", err)
+	}
+	return out, nil
+}
+
+// walkSourceFiles calls fn with the path relative to the given directory, the info and the content of each regular
+// file of the directory. Git metadata and symbolic links, which could point outside the repository, are skipped.
+func walkSourceFiles(dir string, fn func(relPath string, info fs.FileInfo, data []byte) error) error {
+	return filepath.Walk(dir, func(path string, info fs.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && info.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		return fn(relPath, info, data)
+	})
+}
+
+// hashPulumiProgram returns the SHA256 digest of the files of the program and of the options of the source, which
+// identifies the manifests previewed from the program
+func hashPulumiProgram(programDir string, source *v1alpha1.ApplicationSourcePulumi) (string, error) {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "runtime:%s\nentrypoint:%s\n", source.Runtime, source.Entrypoint)
+	keys := make([]string, 0, len(source.Config))
+	for k := range source.Config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, _ = fmt.Fprintf(h, "config:%q=%q\n", k, source.Config[k])
+	}
+	// files are walked in lexical order
+	err := walkSourceFiles(programDir, func(relPath string, info fs.FileInfo, data []byte) error {
+		fileHash := sha256.Sum256(data)
+		_, _ = fmt.Fprintf(h, "file:%q:%o:%s\n", filepath.ToSlash(relPath), info.Mode().Perm(), hex.EncodeToString(fileHash[:]))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("error hashing pulumi program: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// pulumiPreview is the part of the JSON output of `pulumi preview --json` describing the steps of the update
+type pulumiPreview struct {
+	Steps []struct {
+		Op       string `json:"op"`
+		URN      string `json:"urn"`
+		NewState *struct {
+			Type   string                 `json:"type"`
+			Custom bool                   `json:"custom"`
+			Inputs map[string]interface{} `json:"inputs"`
+		} `json:"newState"`
+	} `json:"steps"`
+}
+
+// parsePulumiPreview returns the Kubernetes resources created by the steps of a preview. Resources of the Kubernetes
+// provider are the custom resources of a kubernetes: type whose inputs have an apiVersion and a kind, the components
+// grouping them and the other providers are skipped.
+func parsePulumiPreview(data []byte) ([]*unstructured.Unstructured, error) {
+	var preview pulumiPreview
+	if err := json.Unmarshal(data, &preview); err != nil {
+		return nil, fmt.Errorf("failed to parse pulumi preview: %w", err)
+	}
+	var objs []*unstructured.Unstructured
+	for _, step := range preview.Steps {
+		state := step.NewState
+		if state == nil || !state.Custom || !strings.HasPrefix(state.Type, "kubernetes:") {
+			continue
+		}
+		_, hasAPIVersion := state.Inputs["apiVersion"]
+		_, hasKind := state.Inputs["kind"]
+		if !hasAPIVersion || !hasKind {
+			continue
+		}
+		if hasPulumiUnknownValue(state.Inputs) {
+			return nil, fmt.Errorf("pulumi resource %s has values which are only known once it is deployed", step.URN)
+		}
+		objs = append(objs, &unstructured.Unstructured{Object: state.Inputs})
+	}
+	return objs, nil
+}
+
+// hasPulumiUnknownValue returns true if the value holds an output which is only known once the resources are deployed
+func hasPulumiUnknownValue(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == pulumiUnknownValue
+	case map[string]interface{}:
+		for _, child := range v {
+			if hasPulumiUnknownValue(child) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, child := range v {
+			if hasPulumiUnknownValue(child) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// unmarshalPulumiManifests parses the JSON list of manifests cached for a program
+func unmarshalPulumiManifests(data []byte) ([]*unstructured.Unstructured, error) {
+	var manifests []map[string]interface{}
+	if err := json.Unmarshal(data, &manifests); err != nil {
+		return nil, err
+	}
+	objs := make([]*unstructured.Unstructured, 0, len(manifests))
+	for _, manifest := range manifests {
+		objs = append(objs, &unstructured.Unstructured{Object: manifest})
+	}
+	return objs, nil
+}
+
+// Version returns the version of the pulumi binary
+func (g *PulumiGenerator) Version() (*semver.Version, error) {
+	out, err := executil.Run(exec.Command(g.getBinaryPath(), "version"))
+	if err != nil {
+		return nil, fmt.Errorf("could not get pulumi version: %w", err)
+	}
+	matches := pulumiVersionRegex.FindStringSubmatch(out)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("expected string that includes pulumi version but got: '%s'", strings.TrimSpace(out))
+	}
+	return semver.NewVersion(matches[1])
+}
@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/reposerver/cache"
+	cacheutil "github.com/argoproj/argo-cd/v2/util/cache"
+	"github.com/argoproj/argo-cd/v2/util/git"
+)
+
+func fakePulumiGenerator(t *testing.T, previewCache *cache.Cache) *PulumiGenerator {
+	t.Helper()
+	binaryPath, err := filepath.Abs("./testdata/pulumi/fake-pulumi")
+	require.NoError(t, err)
+	return NewPulumiGenerator(binaryPath, previewCache)
+}
+
+func TestPulumiGenerator_Generate(t *testing.T) {
+	t.Setenv("FAKE_PULUMI_SECRET", "repo-server-credentials")
+	repoRoot, err := filepath.Abs("./testdata/pulumi")
+	require.NoError(t, err)
+	appPath := filepath.Join(repoRoot, "app")
+
+	objs, command, err := fakePulumiGenerator(t, nil).Generate(appPath, repoRoot, &argoappv1.ApplicationSourcePulumi{
+		Runtime: "go",
+		Config:  map[string]string{"replicas": "3", "environment": "prod"},
+	}, false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repoRoot, "fake-pulumi")+" preview --json --diff --stack argocd", command)
+	// the stack, the provider and the component are not manifests
+	require.Len(t, objs, 2)
+	assert.Equal(t, "Deployment", objs[0].GetKind())
+	assert.Equal(t, "guestbook", objs[0].GetName())
+	assert.Equal(t, "ConfigMap", objs[1].GetKind())
+
+	data, _, err := unstructured.NestedStringMap(objs[1].Object, "data")
+	require.NoError(t, err)
+	assert.Equal(t, "preview --json --diff --stack argocd --non-interactive", data["args"])
+	assert.Equal(t, "environment=prod,replicas=3,", data["config"])
+	// the program is previewed in a sandbox without the environment of the repo server
+	assert.NotEqual(t, appPath, data["dir"])
+	assert.True(t, strings.HasPrefix(data["home"], filepath.Dir(data["dir"])), data["home"])
+	assert.Equal(t, "file://"+filepath.Join(filepath.Dir(data["dir"]), "state"), data["backend"])
+	assert.Empty(t, data["secret"])
+	// the sandbox is removed and the repository is not modified
+	assert.NoDirExists(t, data["dir"])
+	assert.NoFileExists(t, filepath.Join(appPath, "Pulumi.argocd.yaml"))
+	assert.NoFileExists(t, filepath.Join(appPath, "config.log"))
+}
+
+func TestGenerateManifests_PulumiEnabled(t *testing.T) {
+	repoRoot, err := filepath.Abs("./testdata/pulumi")
+	require.NoError(t, err)
+	binaryPath, err := filepath.Abs("./testdata/pulumi/fake-pulumi")
+	require.NoError(t, err)
+	q := &apiclient.ManifestRequest{
+		Repo:               &argoappv1.Repository{},
+		ApplicationSource:  &argoappv1.ApplicationSource{Pulumi: &argoappv1.ApplicationSourcePulumi{Runtime: "go", Config: map[string]string{"replicas": "3"}}},
+		ProjectName:        "something",
+		ProjectSourceRepos: []string{"*"},
+	}
+
+	// the programs are not run unless Pulumi applications are enabled
+	_, err = GenerateManifests(context.Background(), filepath.Join(repoRoot, "app"), repoRoot, "", q, false, &git.NoopCredsStore{}, resource.MustParse("0"), nil, WithPulumiBinaryPath(binaryPath))
+	require.ErrorIs(t, err, ErrPulumiDisabled)
+
+	res, err := GenerateManifests(context.Background(), filepath.Join(repoRoot, "app"), repoRoot, "", q, false, &git.NoopCredsStore{}, resource.MustParse("0"), nil, WithPulumiEnabled(true), WithPulumiBinaryPath(binaryPath))
+	require.NoError(t, err)
+	assert.NotEmpty(t, res.Manifests)
+}
+
+func TestPulumiGenerator_Generate_Cache(t *testing.T) {
+	repoRoot, err := filepath.Abs("./testdata/pulumi")
+	require.NoError(t, err)
+	previewCache := cache.NewCache(cacheutil.NewCache(cacheutil.NewInMemoryCache(time.Minute)), time.Minute, time.Minute, 10*time.Second)
+	source := &argoappv1.ApplicationSourcePulumi{Runtime: "go", Config: map[string]string{"replicas": "3"}}
+
+	objs, _, err := fakePulumiGenerator(t, previewCache).Generate(filepath.Join(repoRoot, "app"), repoRoot, source, false)
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+
+	// cached manifests are returned without running pulumi
+	broken := NewPulumiGenerator(filepath.Join(repoRoot, "missing-pulumi"), previewCache)
+	cached, _, err := broken.Generate(filepath.Join(repoRoot, "app"), repoRoot, source, false)
+	require.NoError(t, err)
+	assert.Equal(t, objs, cached)
+
+	// the program is previewed again if its options change, or if the cache is bypassed
+	_, _, err = broken.Generate(filepath.Join(repoRoot, "app"), repoRoot, &argoappv1.ApplicationSourcePulumi{Runtime: "go", Config: map[string]string{"replicas": "4"}}, false)
+	require.ErrorContains(t, err, "error creating pulumi stack")
+	_, _, err = broken.Generate(filepath.Join(repoRoot, "app"), repoRoot, source, true)
+	require.ErrorContains(t, err, "error creating pulumi stack")
+}
+
+func TestPulumiGenerator_Generate_UnknownValues(t *testing.T) {
+	repoRoot, err := filepath.Abs("./testdata/pulumi")
+	require.NoError(t, err)
+
+	_, _, err = fakePulumiGenerator(t, nil).Generate(filepath.Join(repoRoot, "app"), repoRoot, &argoappv1.ApplicationSourcePulumi{
+		Runtime: "go",
+		Config:  map[string]string{"unknown": "true"},
+	}, false)
+	require.ErrorContains(t, err, "pulumi resource urn:pulumi:argocd::guestbook::kubernetes:apps/v1:Deployment::guestbook has values which are only known once it is deployed")
+}
+
+func TestPulumiGenerator_Validate(t *testing.T) {
+	repoRoot, err := filepath.Abs("./testdata/pulumi")
+	require.NoError(t, err)
+	g := fakePulumiGenerator(t, nil)
+
+	require.NoError(t, g.Validate(filepath.Join(repoRoot, "app"), repoRoot, &argoappv1.ApplicationSourcePulumi{Runtime: "go"}))
+	require.NoError(t, g.Validate(repoRoot, repoRoot, &argoappv1.ApplicationSourcePulumi{Runtime: "nodejs", Entrypoint: "nodejs-app"}))
+
+	err = g.Validate(filepath.Join(repoRoot, "app"), repoRoot, &argoappv1.ApplicationSourcePulumi{Runtime: "python"})
+	require.ErrorContains(t, err, `pulumi project of entrypoint "." has runtime "go" instead of "python"`)
+	err = g.Validate(filepath.Join(repoRoot, "app"), repoRoot, &argoappv1.ApplicationSourcePulumi{Runtime: "java"})
+	require.ErrorContains(t, err, "pulumi runtime must be one of nodejs, python or go")
+	err = g.Validate(repoRoot, repoRoot, &argoappv1.ApplicationSourcePulumi{Runtime: "go"})
+	require.ErrorContains(t, err, `pulumi entrypoint "." has no Pulumi.yaml project file`)
+	err = g.Validate(filepath.Join(repoRoot, "app"), repoRoot, &argoappv1.ApplicationSourcePulumi{Runtime: "go", Entrypoint: "../../out-of-bounds"})
+	require.ErrorContains(t, err, "error resolving pulumi entrypoint")
+}
+
+func TestHashPulumiProgram(t *testing.T) {
+	programDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(programDir, "main.go"), []byte("package main"), 0o600))
+	source := &argoappv1.ApplicationSourcePulumi{Runtime: "go", Config: map[string]string{"a": "1"}}
+
+	hash, err := hashPulumiProgram(programDir, source)
+	require.NoError(t, err)
+	same, err := hashPulumiProgram(programDir, &argoappv1.ApplicationSourcePulumi{Runtime: "go", Config: map[string]string{"a": "1"}})
+	require.NoError(t, err)
+	assert.Equal(t, hash, same)
+
+	otherConfig, err := hashPulumiProgram(programDir, &argoappv1.ApplicationSourcePulumi{Runtime: "go", Config: map[string]string{"a": "2"}})
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, otherConfig)
+
+	require.NoError(t, os.WriteFile(filepath.Join(programDir, "main.go"), []byte("package main\n"), 0o600))
+	otherProgram, err := hashPulumiProgram(programDir, source)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, otherProgram)
+}
+
+// TestPulumiGenerator_Generate_Integration previews the Go program of testdata/pulumi/app with the pulumi binary of the
+// PATH. It requires the Go toolchain and the Kubernetes plugin of pulumi.
+func TestPulumiGenerator_Generate_Integration(t *testing.T) {
+	if _, err := exec.LookPath("pulumi"); err != nil {
+		t.Skip("pulumi is not installed")
+	}
+	repoRoot, err := filepath.Abs("./testdata/pulumi")
+	require.NoError(t, err)
+
+	objs, _, err := NewPulumiGenerator("", nil).Generate(filepath.Join(repoRoot, "app"), repoRoot, &argoappv1.ApplicationSourcePulumi{
+		Runtime: "go",
+		Config:  map[string]string{"replicas": "2"},
+	}, false)
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Equal(t, "apps/v1", objs[0].GetAPIVersion())
+	assert.Equal(t, "Deployment", objs[0].GetKind())
+	assert.Equal(t, "guestbook", objs[0].GetName())
+	replicas, _, err := unstructured.NestedFieldNoCopy(objs[0].Object, "spec", "replicas")
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, replicas)
+}
@@ -13,6 +13,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
 	"time"
 
@@ -36,6 +37,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	kubeyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"oras.land/oras-go/v2/registry/remote"
 	"sigs.k8s.io/yaml"
 
 	pluginclient "github.com/argoproj/argo-cd/v2/cmpserver/apiclient"
@@ -70,6 +72,8 @@ const (
 	repoSourceFile                 = ".argocd-source.yaml"
 	appSourceFile                  = ".argocd-source-%s.yaml"
 	ociPrefix                      = "oci://"
+	// bundlesDirName is the directory in the root path holding the repositories imported from git bundles
+	bundlesDirName = "_bundles"
 )
 
 var (
@@ -86,6 +90,7 @@ type Service struct {
 	gitRepoPaths              io.TempPaths
 	chartPaths                io.TempPaths
 	gitRepoInitializer        func(rootPath string) goio.Closer
+	bundles                   *git.BundleStore
 	repoLock                  *repositoryLock
 	cache                     *cache.Cache
 	parallelismLimitSemaphore *semaphore.Weighted
@@ -93,7 +98,12 @@ type Service struct {
 	resourceTracking          argo.ResourceTracking
 	newGitClient              func(rawRepoURL string, root string, creds git.Creds, insecure bool, enableLfs bool, proxy string, opts ...git.ClientOpts) (git.Client, error)
 	newHelmClient             func(repoURL string, creds helm.Creds, enableOci bool, proxy string, opts ...helm.ClientOpts) helm.Client
+	newOCIRepository          func(reference string, creds helm.Creds, proxy string) (*remote.Repository, error)
 	initConstants             RepoServerInitConstants
+	// parsePool parses the YAML documents of generated manifests concurrently, shared by all manifest generations
+	parsePool *ParseWorkerPool
+	// manifestGenerations coalesces the concurrent generations of identical manifest requests
+	manifestGenerations *manifestGenerationGroup
 	// now is usually just time.Now, but may be replaced by unit tests for testing purposes
 	now func() time.Time
 }
@@ -113,6 +123,41 @@ type RepoServerInitConstants struct {
 	HelmRegistryMaxIndexSize                     int64
 	DisableHelmManifestMaxExtractedSize          bool
 	IncludeHiddenDirectories                     bool
+	// KustomizeVersions are the Kustomize binaries which applications may select with spec.source.kustomize.version
+	KustomizeVersions kustomize.Versions
+	// GitShallowCloneDepth is the number of commits fetched from git repositories, zero fetches the full history
+	GitShallowCloneDepth int
+	// MaxShallowDeepenDepth is the depth shallow clones are deepened up to when a revision is not found, zero means the full history
+	MaxShallowDeepenDepth int
+	// YttBinaryPath is the path of the ytt binary rendering ytt applications, the binary is looked up in the PATH when empty
+	YttBinaryPath string
+	// CueBinaryPath is the path of the cue binary rendering CUE applications, the binary is looked up in the PATH when empty
+	CueBinaryPath string
+	// PulumiBinaryPath is the path of the pulumi binary previewing Pulumi applications, the binary is looked up in the PATH when empty
+	PulumiBinaryPath string
+	// EnablePulumi permits Pulumi applications, whose programs are run by the repo server
+	EnablePulumi bool
+	// TimoniBinaryPath is the path of the timoni binary building Timoni applications, the binary is looked up in the PATH when empty
+	TimoniBinaryPath string
+	// KustomizePluginHome is the directory Kustomize looks up alpha plugins in when validating manifests, the default
+	// directory of Kustomize is used when empty
+	KustomizePluginHome string
+	// KustomizeBaseCache enables caching the rendered local bases of Kustomize overlays, so that the applications
+	// sharing a base render it once per revision
+	KustomizeBaseCache bool
+	// HTTPSourceCacheTTL is the time the manifests fetched for HTTP sources are cached, zero disables caching
+	HTTPSourceCacheTTL time.Duration
+	// OCISourcePollInterval is the time the digests the tags of OCI sources resolve to are cached, so that the
+	// registries are polled for new artifacts at this interval. Zero resolves the tags on every manifest generation.
+	OCISourcePollInterval time.Duration
+	// ManifestParseWorkers is the number of YAML documents of generated manifests parsed concurrently, the documents
+	// are parsed sequentially when lower than 2
+	ManifestParseWorkers int
+	// GeneratorTimeouts are the maximum durations of the manifest generations by type of generator
+	GeneratorTimeouts GeneratorTimeouts
+	// HelmDependencyUpdateTimeout is the maximum duration of `helm dependency update`, run before generating the
+	// manifests of the charts whose dependencies were not committed. Zero disables the update.
+	HelmDependencyUpdateTimeout time.Duration
 }
 
 // NewService returns a new instance of the Manifest service
@@ -134,13 +179,17 @@ func NewService(metricsServer *metrics.MetricsServer, cache *cache.Cache, initCo
 		newHelmClient: func(repoURL string, creds helm.Creds, enableOci bool, proxy string, opts ...helm.ClientOpts) helm.Client {
 			return helm.NewClientWithLock(repoURL, creds, sync.NewKeyLock(), enableOci, proxy, opts...)
 		},
-		initConstants:      initConstants,
-		now:                time.Now,
-		gitCredsStore:      gitCredsStore,
-		gitRepoPaths:       gitRandomizedPaths,
-		chartPaths:         helmRandomizedPaths,
-		gitRepoInitializer: directoryPermissionInitializer,
-		rootDir:            rootDir,
+		newOCIRepository:    helm.NewOCIRepository,
+		initConstants:       initConstants,
+		parsePool:           NewParseWorkerPool(initConstants.ManifestParseWorkers),
+		manifestGenerations: &manifestGenerationGroup{metricsServer: metricsServer},
+		now:                 time.Now,
+		gitCredsStore:       gitCredsStore,
+		gitRepoPaths:        gitRandomizedPaths,
+		chartPaths:          helmRandomizedPaths,
+		gitRepoInitializer:  directoryPermissionInitializer,
+		rootDir:             rootDir,
+		bundles:             git.NewBundleStore(filepath.Join(rootDir, bundlesDirName)),
 	}
 }
 
@@ -163,7 +212,7 @@ func (s *Service) Init() error {
 	}
 
 	for _, file := range dirEntries {
-		if !file.IsDir() {
+		if !file.IsDir() || file.Name() == bundlesDirName {
 			continue
 		}
 		fullPath := filepath.Join(s.rootDir, file.Name())
@@ -209,7 +258,7 @@ func (s *Service) ListApps(ctx context.Context, q *apiclient.ListAppsRequest) (*
 		return nil, fmt.Errorf("error setting up git client and resolving given revision: %w", err)
 	}
 	if apps, err := s.cache.ListApps(q.Repo.Repo, commitSHA); err == nil {
-		log.Infof("cache hit: %s/%s", q.Repo.Repo, q.Revision)
+		grpc.LoggerFromContext(ctx).Infof("cache hit: %s/%s", q.Repo.Repo, q.Revision)
 		return &apiclient.AppList{Apps: apps}, nil
 	}
 
@@ -230,7 +279,7 @@ func (s *Service) ListApps(ctx context.Context, q *apiclient.ListAppsRequest) (*
 	}
 	err = s.cache.SetApps(q.Repo.Repo, commitSHA, apps)
 	if err != nil {
-		log.Warnf("cache set error %s/%s: %v", q.Repo.Repo, commitSHA, err)
+		grpc.LoggerFromContext(ctx).Warnf("cache set error %s/%s: %v", q.Repo.Repo, commitSHA, err)
 	}
 	res := apiclient.AppList{Apps: apps}
 	return &res, nil
@@ -512,13 +561,65 @@ func resolveReferencedSources(hasMultipleSources bool, source *v1alpha1.Applicat
 	return repoRefs, nil
 }
 
+// GenerateManifest generates the manifests of the request. Concurrent identical requests share a single generation.
 func (s *Service) GenerateManifest(ctx context.Context, q *apiclient.ManifestRequest) (*apiclient.ManifestResponse, error) {
+	return s.manifestGenerations.generate(ctx, q, s.generateManifest)
+}
+
+func (s *Service) generateManifest(ctx context.Context, q *apiclient.ManifestRequest) (*apiclient.ManifestResponse, error) {
 	var res *apiclient.ManifestResponse
 	var err error
 
+	s.resolveClusterAPIVersions(ctx, q)
+
+	// Inline manifests are defined in the application itself and do not require any repository operation
+	if q.ApplicationSource.IsInline() {
+		res, err = GenerateInlineManifests(q)
+		if err != nil {
+			return nil, err
+		}
+		if err = s.negotiateManifestAPIVersions(ctx, q, res); err != nil {
+			return nil, err
+		}
+		if err = checkNamespaceIsolation(q, res.Manifests); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	// The manifests of HTTP sources are fetched from their URL and do not require any repository operation
+	if q.ApplicationSource.IsHTTP() {
+		res, err = s.generateHTTPManifests(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		if err = s.negotiateManifestAPIVersions(ctx, q, res); err != nil {
+			return nil, err
+		}
+		if err = checkNamespaceIsolation(q, res.Manifests); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
+	// The manifests of OCI sources are extracted from an artifact and do not require any repository operation
+	if q.ApplicationSource.IsOCI() {
+		res, err = s.generateOCIManifests(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		if err = s.negotiateManifestAPIVersions(ctx, q, res); err != nil {
+			return nil, err
+		}
+		if err = checkNamespaceIsolation(q, res.Manifests); err != nil {
+			return nil, err
+		}
+		return res, nil
+	}
+
 	// Skip this path for ref only sources
 	if q.HasMultipleSources && q.ApplicationSource.Path == "" && !q.ApplicationSource.IsHelm() && q.ApplicationSource.IsRef() {
-		log.Debugf("Skipping manifest generation for ref only source for application: %s and ref %s", q.AppName, q.ApplicationSource.Ref)
+		grpc.LoggerFromContext(ctx).Debugf("Skipping manifest generation for ref only source for application: %s and ref %s", q.AppName, q.ApplicationSource.Ref)
 		_, revision, err := s.newClientResolveRevision(q.Repo, q.Revision, git.WithCache(s.cache, !q.NoRevisionCache && !q.NoCache))
 		res = &apiclient.ManifestResponse{
 			Revision: revision,
@@ -538,7 +639,7 @@ func (s *Service) GenerateManifest(ctx context.Context, q *apiclient.ManifestReq
 	operation := func(repoRoot, commitSHA, cacheKey string, ctxSrc operationContextSrc) error {
 		// do not generate manifests if Path and Chart fields are not set for a source in Multiple Sources
 		if q.HasMultipleSources && q.ApplicationSource.Path == "" && q.ApplicationSource.Chart == "" {
-			log.WithFields(map[string]interface{}{
+			grpc.LoggerFromContext(ctx).WithFields(map[string]interface{}{
 				"source": q.ApplicationSource,
 			}).Debugf("not generating manifests as path and chart fields are empty")
 			res = &apiclient.ManifestResponse{
@@ -579,9 +680,53 @@ func (s *Service) GenerateManifest(ctx context.Context, q *apiclient.ManifestReq
 			return nil, err
 		}
 	}
+	if err == nil && res != nil {
+		// the API versions of cached manifests are negotiated as well, since the cluster may serve other versions
+		if err = s.negotiateManifestAPIVersions(ctx, q, res); err != nil {
+			return nil, err
+		}
+		// the check is applied to cached manifests as well, since the project settings may have changed
+		if err = checkNamespaceIsolation(q, res.Manifests); err != nil {
+			return nil, err
+		}
+	}
 	return res, err
 }
 
+// checkNamespaceIsolation returns an error if the project of the application enforces strict namespace isolation, and
+// the manifests contain namespaced resources outside the destination namespace of the application, or cluster-scoped
+// resources which are not explicitly permitted by the cluster resource whitelist of the project.
+func checkNamespaceIsolation(q *apiclient.ManifestRequest, manifests []string) error {
+	if q.NamespaceIsolation != v1alpha1.NamespaceIsolationStrict {
+		return nil
+	}
+	clusterScoped := make(map[string]bool)
+	for _, gk := range q.ClusterScopedResources {
+		clusterScoped[gk] = true
+	}
+	permitted := make(map[string]bool)
+	for _, gk := range q.PermittedClusterResources {
+		permitted[gk] = true
+	}
+	for _, manifest := range manifests {
+		obj := &unstructured.Unstructured{}
+		if err := json.Unmarshal([]byte(manifest), obj); err != nil {
+			return fmt.Errorf("failed to unmarshal manifest: %w", err)
+		}
+		gk := obj.GroupVersionKind().GroupKind()
+		if clusterScoped[gk.String()] {
+			if !permitted[gk.String()] {
+				return status.Errorf(codes.PermissionDenied, "cluster-scoped resource %s %s is not explicitly permitted by the cluster resource whitelist of project %s, which enforces strict namespace isolation", gk.String(), obj.GetName(), q.ProjectName)
+			}
+			continue
+		}
+		if ns := obj.GetNamespace(); ns != "" && ns != q.Namespace {
+			return status.Errorf(codes.PermissionDenied, "resource %s %s/%s is outside the destination namespace %q of the application: project %s enforces strict namespace isolation", gk.String(), ns, obj.GetName(), q.Namespace, q.ProjectName)
+		}
+	}
+	return nil
+}
+
 func (s *Service) GenerateManifestWithFiles(stream apiclient.RepoServerService_GenerateManifestWithFilesServer) error {
 	workDir, err := files.CreateTempDir("")
 	if err != nil {
@@ -590,7 +735,7 @@ func (s *Service) GenerateManifestWithFiles(stream apiclient.RepoServerService_G
 	defer func() {
 		if err := os.RemoveAll(workDir); err != nil {
 			// we panic here as the workDir may contain sensitive information
-			log.WithField(common.SecurityField, common.SecurityCritical).Errorf("error removing generate manifest workdir: %v", err)
+			grpc.LoggerFromContext(stream.Context()).WithField(common.SecurityField, common.SecurityCritical).Errorf("error removing generate manifest workdir: %v", err)
 			panic(fmt.Sprintf("error removing generate manifest workdir: %s", err))
 		}
 	}()
@@ -605,7 +750,7 @@ func (s *Service) GenerateManifestWithFiles(stream apiclient.RepoServerService_G
 		if err != nil {
 			oobError := &argopath.OutOfBoundsSymlinkError{}
 			if errors.As(err, &oobError) {
-				log.WithFields(log.Fields{
+				grpc.LoggerFromContext(stream.Context()).WithFields(log.Fields{
 					common.SecurityField: common.SecurityHigh,
 					"file":               oobError.File,
 				}).Warn("streamed files contains out-of-bounds symlink")
@@ -753,7 +898,7 @@ func (s *Service) runManifestGenAsync(ctx context.Context, repoRoot, commitSHA,
 						} else {
 							gitClient, referencedCommitSHA, err := s.newClientResolveRevision(&refSourceMapping.Repo, refSourceMapping.TargetRevision, git.WithCache(s.cache, !q.NoRevisionCache && !q.NoCache))
 							if err != nil {
-								log.Errorf("Failed to get git client for repo %s: %v", refSourceMapping.Repo.Repo, err)
+								grpc.LoggerFromContext(ctx).Errorf("Failed to get git client for repo %s: %v", refSourceMapping.Repo.Repo, err)
 								ch.errCh <- fmt.Errorf("failed to get git client for repo %s", refSourceMapping.Repo.Repo)
 								return
 							}
@@ -766,14 +911,14 @@ func (s *Service) runManifestGenAsync(ctx context.Context, repoRoot, commitSHA,
 								return s.checkoutRevision(gitClient, referencedCommitSHA, s.initConstants.SubmoduleEnabled)
 							})
 							if err != nil {
-								log.Errorf("failed to acquire lock for referenced source %s", normalizedRepoURL)
+								grpc.LoggerFromContext(ctx).Errorf("failed to acquire lock for referenced source %s", normalizedRepoURL)
 								ch.errCh <- err
 								return
 							}
 							defer func(closer goio.Closer) {
 								err := closer.Close()
 								if err != nil {
-									log.Errorf("Failed to release repo lock: %v", err)
+									grpc.LoggerFromContext(ctx).Errorf("Failed to release repo lock: %v", err)
 								}
 							}(closer)
 
@@ -783,7 +928,7 @@ func (s *Service) runManifestGenAsync(ctx context.Context, repoRoot, commitSHA,
 								if err != nil {
 									oobError := &argopath.OutOfBoundsSymlinkError{}
 									if errors.As(err, &oobError) {
-										log.WithFields(log.Fields{
+										grpc.LoggerFromContext(ctx).WithFields(log.Fields{
 											common.SecurityField: common.SecurityHigh,
 											"repo":               refSourceMapping.Repo,
 											"revision":           refSourceMapping.TargetRevision,
@@ -805,7 +950,20 @@ func (s *Service) runManifestGenAsync(ctx context.Context, repoRoot, commitSHA,
 			}
 		}
 
-		manifestGenResult, err = GenerateManifests(ctx, opContext.appPath, repoRoot, commitSHA, q, false, s.gitCredsStore, s.initConstants.MaxCombinedDirectoryManifestsSize, s.gitRepoPaths, WithCMPTarDoneChannel(ch.tarDoneCh), WithCMPTarExcludedGlobs(s.initConstants.CMPTarExcludedGlobs))
+		genOpts := []GenerateManifestOpt{WithCMPTarDoneChannel(ch.tarDoneCh), WithCMPTarExcludedGlobs(s.initConstants.CMPTarExcludedGlobs), WithKustomizeVersions(s.initConstants.KustomizeVersions), WithYttBinaryPath(s.initConstants.YttBinaryPath), WithCueBinaryPath(s.initConstants.CueBinaryPath), WithPulumiEnabled(s.initConstants.EnablePulumi), WithPulumiBinaryPath(s.initConstants.PulumiBinaryPath), WithPulumiCache(s.cache), WithTimoniBinaryPath(s.initConstants.TimoniBinaryPath), WithTimoniCache(s.cache), WithCRDSchemaCache(s.cache), WithKustomizePluginHome(s.initConstants.KustomizePluginHome), WithParseWorkerPool(s.parsePool), WithGeneratorTimeouts(s.initConstants.GeneratorTimeouts, s.metricsServer), WithKustomizeOCIBases(s.cache, s.newOCIRepository)}
+		if s.initConstants.KustomizeBaseCache {
+			genOpts = append(genOpts, WithKustomizeBaseCache(s.cache, s.metricsServer))
+		}
+		if s.initConstants.HelmDependencyUpdateTimeout > 0 {
+			genOpts = append(genOpts, WithHelmDependencyUpdate(s.cache, s.initConstants.HelmDependencyUpdateTimeout))
+		}
+		// the manifests are only generated once the in-toto supply chain which produced them is verified
+		if q.ApplicationSource.VerifyAttestation.InTotoLayout() != "" {
+			err = verifyInTotoAttestation(opContext.appPath, q.InTotoLayout, q.InTotoLayoutKeys)
+		}
+		if err == nil {
+			manifestGenResult, err = GenerateManifests(ctx, opContext.appPath, repoRoot, commitSHA, q, false, s.gitCredsStore, s.initConstants.MaxCombinedDirectoryManifestsSize, s.gitRepoPaths, genOpts...)
+		}
 	}
 	refSourceCommitSHAs := make(map[string]string)
 	if len(repoRefs) > 0 {
@@ -814,7 +972,7 @@ func (s *Service) runManifestGenAsync(ctx context.Context, repoRoot, commitSHA,
 		}
 	}
 	if err != nil {
-		logCtx := log.WithFields(log.Fields{
+		logCtx := grpc.LoggerFromContext(ctx).WithFields(log.Fields{
 			"application":  q.AppName,
 			"appNamespace": q.Namespace,
 		})
@@ -870,7 +1028,7 @@ func (s *Service) runManifestGenAsync(ctx context.Context, repoRoot, commitSHA,
 	manifestGenResult.VerifyResult = opContext.verificationResult
 	err = s.cache.SetManifests(cacheKey, appSourceCopy, q.RefSources, q, q.Namespace, q.TrackingMethod, q.AppLabelKey, q.AppName, &manifestGenCacheEntry, refSourceCommitSHAs)
 	if err != nil {
-		log.Warnf("manifest cache set error %s/%s: %v", appSourceCopy.String(), cacheKey, err)
+		grpc.LoggerFromContext(ctx).Warnf("manifest cache set error %s/%s: %v", appSourceCopy.String(), cacheKey, err)
 	}
 	ch.responseCh <- manifestGenCacheEntry.ManifestResponse
 }
@@ -1102,7 +1260,7 @@ func isSourcePermitted(url string, repos []string) bool {
 	return p.IsSourcePermitted(v1alpha1.ApplicationSource{RepoURL: url})
 }
 
-func helmTemplate(appPath string, repoRoot string, env *v1alpha1.Env, q *apiclient.ManifestRequest, isLocal bool, gitRepoPaths io.TempPaths) ([]*unstructured.Unstructured, string, error) {
+func helmTemplate(appPath string, repoRoot string, env *v1alpha1.Env, q *apiclient.ManifestRequest, isLocal bool, gitRepoPaths io.TempPaths, parsePool *ParseWorkerPool, timeout time.Duration, depUpdater *helmDependencyUpdater) ([]*unstructured.Unstructured, string, []string, error) {
 	concurrencyAllowed := helmConcurrencyDefault || isConcurrencyAllowed(appPath)
 	if !concurrencyAllowed {
 		manifestGenerateLock.Lock(appPath)
@@ -1123,10 +1281,13 @@ func helmTemplate(appPath string, repoRoot string, env *v1alpha1.Env, q *apiclie
 		Set:         map[string]string{},
 		SetString:   map[string]string{},
 		SetFile:     map[string]pathutil.ResolvedFilePath{},
+		Timeout:     timeout,
 	}
 
 	appHelm := q.ApplicationSource.Helm
 	var version string
+	var valuesFromPaths []string
+	var autoValueFiles []string
 	var passCredentials bool
 	if appHelm != nil {
 		if appHelm.Version != "" {
@@ -1136,17 +1297,48 @@ func helmTemplate(appPath string, repoRoot string, env *v1alpha1.Env, q *apiclie
 			templateOpts.Name = appHelm.ReleaseName
 		}
 
-		resolvedValueFiles, err := getResolvedValueFiles(appPath, repoRoot, env, q.GetValuesFileSchemes(), appHelm.ValueFiles, q.RefSources, gitRepoPaths, appHelm.IgnoreMissingValueFiles)
+		resolvedValueFiles, err := getResolvedValueFiles(appPath, repoRoot, env, q.GetValuesFileSchemes(), appHelm.ValueFiles, q.RefSources, gitRepoPaths, appHelm.IgnoreMissingValueFiles, q.HelmValueFilesSuffix)
 		if err != nil {
-			return nil, "", fmt.Errorf("error resolving helm value files: %w", err)
+			return nil, "", nil, fmt.Errorf("error resolving helm value files: %w", err)
 		}
 
 		templateOpts.Values = resolvedValueFiles
 
+		// The value files named after the destination are appended to the value files, so that they take precedence
+		if appHelm.AutoValuesFile {
+			for _, valueFile := range getAutoValueFiles(appPath, q.Namespace, q.DestinationClusterName, resolvedValueFiles) {
+				templateOpts.Values = append(templateOpts.Values, valueFile)
+				relPath, err := filepath.Rel(repoRoot, string(valueFile))
+				if err != nil {
+					return nil, "", nil, fmt.Errorf("error resolving auto value file %s: %w", valueFile, err)
+				}
+				autoValueFiles = append(autoValueFiles, relPath)
+			}
+		}
+
+		// The values read from the ConfigMaps and Secrets of the destination cluster are merged after the value files
+		// and before the inline values
+		for _, values := range q.HelmValuesFrom {
+			rand, err := uuid.NewRandom()
+			if err != nil {
+				return nil, "", nil, fmt.Errorf("error generating random filename for Helm values file: %w", err)
+			}
+			p := path.Join(os.TempDir(), rand.String())
+			defer func() {
+				_ = os.RemoveAll(p)
+			}()
+			err = os.WriteFile(p, []byte(values), 0o600)
+			if err != nil {
+				return nil, "", nil, fmt.Errorf("error writing helm values file: %w", err)
+			}
+			templateOpts.Values = append(templateOpts.Values, pathutil.ResolvedFilePath(p))
+			valuesFromPaths = append(valuesFromPaths, p)
+		}
+
 		if !appHelm.ValuesIsEmpty() {
 			rand, err := uuid.NewRandom()
 			if err != nil {
-				return nil, "", fmt.Errorf("error generating random filename for Helm values file: %w", err)
+				return nil, "", nil, fmt.Errorf("error generating random filename for Helm values file: %w", err)
 			}
 			p := path.Join(os.TempDir(), rand.String())
 			defer func() {
@@ -1157,7 +1349,7 @@ func helmTemplate(appPath string, repoRoot string, env *v1alpha1.Env, q *apiclie
 			}()
 			err = os.WriteFile(p, appHelm.ValuesYAML(), 0o644)
 			if err != nil {
-				return nil, "", fmt.Errorf("error writing helm values file: %w", err)
+				return nil, "", nil, fmt.Errorf("error writing helm values file: %w", err)
 			}
 			templateOpts.ExtraValues = pathutil.ResolvedFilePath(p)
 		}
@@ -1176,12 +1368,12 @@ func helmTemplate(appPath string, repoRoot string, env *v1alpha1.Env, q *apiclie
 				// If the $-prefixed path appears to reference another source, do env substitution _after_ resolving the source
 				resolvedPath, err = getResolvedRefValueFile(p.Path, env, q.GetValuesFileSchemes(), referencedSource.Repo.Repo, gitRepoPaths, referencedSource.Repo.Project)
 				if err != nil {
-					return nil, "", fmt.Errorf("error resolving set-file path: %w", err)
+					return nil, "", nil, fmt.Errorf("error resolving set-file path: %w", err)
 				}
 			} else {
 				resolvedPath, _, err = pathutil.ResolveValueFilePathOrUrl(appPath, repoRoot, env.Envsubst(p.Path), q.GetValuesFileSchemes())
 				if err != nil {
-					return nil, "", fmt.Errorf("error resolving helm value file path: %w", err)
+					return nil, "", nil, fmt.Errorf("error resolving helm value file path: %w", err)
 				}
 			}
 			templateOpts.SetFile[p.Name] = resolvedPath
@@ -1206,20 +1398,26 @@ func helmTemplate(appPath string, repoRoot string, env *v1alpha1.Env, q *apiclie
 
 	helmRepos, err := getHelmRepos(appPath, q.Repos, q.HelmRepoCreds)
 	if err != nil {
-		return nil, "", fmt.Errorf("error getting helm repos: %w", err)
+		return nil, "", nil, fmt.Errorf("error getting helm repos: %w", err)
 	}
 
 	h, err := helm.NewHelmApp(appPath, helmRepos, isLocal, version, proxy, passCredentials)
 	if err != nil {
-		return nil, "", fmt.Errorf("error initializing helm app object: %w", err)
+		return nil, "", nil, fmt.Errorf("error initializing helm app object: %w", err)
 	}
 
 	defer h.Dispose()
 
+	if depUpdater != nil {
+		if err := depUpdater.ensureDependencies(appPath, h, concurrencyAllowed); err != nil {
+			return nil, "", nil, err
+		}
+	}
+
 	out, command, err := h.Template(templateOpts)
 	if err != nil {
 		if !helm.IsMissingDependencyErr(err) {
-			return nil, "", err
+			return nil, "", nil, err
 		}
 
 		if concurrencyAllowed {
@@ -1244,22 +1442,47 @@ func helmTemplate(appPath string, repoRoot string, env *v1alpha1.Env, q *apiclie
 			}
 
 			if len(reposNotPermitted) > 0 {
-				return nil, "", status.Errorf(codes.PermissionDenied, "helm repos %s are not permitted in project '%s'", strings.Join(reposNotPermitted, ", "), q.ProjectName)
+				return nil, "", nil, status.Errorf(codes.PermissionDenied, "helm repos %s are not permitted in project '%s'", strings.Join(reposNotPermitted, ", "), q.ProjectName)
 			}
 
-			return nil, "", err
+			return nil, "", nil, err
 		}
 
 		out, command, err = h.Template(templateOpts)
 		if err != nil {
-			return nil, "", err
+			return nil, "", nil, err
 		}
 	}
-	objs, err := kube.SplitYAML([]byte(out))
+	objs, err := parsePool.Parse([]byte(out))
 
 	redactedCommand := redactPaths(command, gitRepoPaths, templateOpts.ExtraValues)
+	for _, p := range valuesFromPaths {
+		redactedCommand = strings.ReplaceAll(redactedCommand, p, "<temp file with values from source.helm.valuesFrom>")
+	}
+
+	return objs, redactedCommand, autoValueFiles, err
+}
 
-	return objs, redactedCommand, err
+// getAutoValueFiles returns the value files named after the given destination namespace and cluster,
+// values-<namespace>.yaml and values-<cluster name>.yaml, which exist in the directory of the chart and are not part of
+// the given value files
+func getAutoValueFiles(appPath string, namespace string, clusterName string, valueFiles []pathutil.ResolvedFilePath) []pathutil.ResolvedFilePath {
+	var autoValueFiles []pathutil.ResolvedFilePath
+	for _, name := range []string{namespace, clusterName} {
+		// names which are not a single path element could escape the directory of the chart
+		if name == "" || name != filepath.Base(name) || name == ".." {
+			continue
+		}
+		valueFile := pathutil.ResolvedFilePath(filepath.Join(appPath, "values-"+name+".yaml"))
+		if slices.Contains(valueFiles, valueFile) || slices.Contains(autoValueFiles, valueFile) {
+			continue
+		}
+		if info, err := os.Stat(string(valueFile)); err != nil || info.IsDir() {
+			continue
+		}
+		autoValueFiles = append(autoValueFiles, valueFile)
+	}
+	return autoValueFiles
 }
 
 // redactPaths removes temp repo paths, since those paths are randomized (and therefore not helpful for the user) and
@@ -1288,6 +1511,7 @@ func getResolvedValueFiles(
 	refSources map[string]*v1alpha1.RefTarget,
 	gitRepoPaths io.TempPaths,
 	ignoreMissingValueFiles bool,
+	valueFilesSuffix string,
 ) ([]pathutil.ResolvedFilePath, error) {
 	var resolvedValueFiles []pathutil.ResolvedFilePath
 	for _, rawValueFile := range rawValueFiles {
@@ -1311,6 +1535,13 @@ func getResolvedValueFiles(
 		}
 
 		if !isRemote {
+			// The value file with the suffix of the destination cluster takes precedence over the value file
+			if valueFilesSuffix != "" {
+				suffixedPath := suffixedValueFilePath(resolvedPath, valueFilesSuffix)
+				if _, err := os.Stat(string(suffixedPath)); err == nil {
+					resolvedPath = suffixedPath
+				}
+			}
 			_, err = os.Stat(string(resolvedPath))
 			if os.IsNotExist(err) {
 				if ignoreMissingValueFiles {
@@ -1325,6 +1556,13 @@ func getResolvedValueFiles(
 	return resolvedValueFiles, nil
 }
 
+// suffixedValueFilePath returns the path of the value file with the given suffix appended to its name, before the file
+// extension
+func suffixedValueFilePath(valueFile pathutil.ResolvedFilePath, suffix string) pathutil.ResolvedFilePath {
+	ext := filepath.Ext(string(valueFile))
+	return pathutil.ResolvedFilePath(strings.TrimSuffix(string(valueFile), ext) + "-" + suffix + ext)
+}
+
 func getResolvedRefValueFile(
 	rawValueFile string,
 	env *v1alpha1.Env,
@@ -1373,11 +1611,47 @@ func getRepoCredential(repoCredentials []*v1alpha1.RepoCreds, repoURL string) *v
 	return nil
 }
 
+// helmOCICreds returns the credentials of the Helm OCI repository, or else of the Helm repository credential template,
+// matching the reference of an OCI artifact, e.g. oci://ghcr.io/org/module, so that artifacts are pulled with the
+// credentials configured for Helm OCI charts
+func helmOCICreds(reference string, repos []*v1alpha1.Repository, repoCreds []*v1alpha1.RepoCreds) helm.Creds {
+	ref := strings.TrimPrefix(reference, ociPrefix)
+	for _, repo := range repos {
+		url := strings.TrimPrefix(repo.Repo, ociPrefix)
+		if repo.EnableOCI && url != "" && strings.HasPrefix(ref, url) {
+			return repo.GetHelmCreds()
+		}
+	}
+	if cred := getRepoCredential(repoCreds, reference); cred != nil {
+		return helm.Creds{Username: cred.Username, Password: cred.Password}
+	}
+	return helm.Creds{}
+}
+
 type (
 	GenerateManifestOpt func(*generateManifestOpt)
 	generateManifestOpt struct {
 		cmpTarDoneCh        chan<- bool
 		cmpTarExcludedGlobs []string
+		kustomizeVersions   kustomize.Versions
+		yttBinaryPath       string
+		cueBinaryPath       string
+		pulumiEnabled       bool
+		pulumiBinaryPath    string
+		pulumiCache         *cache.Cache
+		timoniBinaryPath    string
+		timoniCache         *cache.Cache
+		kustomizePluginHome string
+		crdSchemaCache      *cache.Cache
+		kustomizeBaseCache  *cache.Cache
+		// kustomizeOCIBaseCache caches the files of the OCI bases of kustomizations, which fail to build when nil
+		kustomizeOCIBaseCache *cache.Cache
+		newOCIRepository      func(reference string, creds helm.Creds, proxy string) (*remote.Repository, error)
+		// helmDependencyUpdater fetches the missing dependencies of charts, nil if disabled
+		helmDependencyUpdater *helmDependencyUpdater
+		metricsServer         *metrics.MetricsServer
+		parsePool             *ParseWorkerPool
+		generatorTimeouts     GeneratorTimeouts
 	}
 )
 
@@ -1406,13 +1680,151 @@ func WithCMPTarExcludedGlobs(excludedGlobs []string) GenerateManifestOpt {
 	}
 }
 
+// WithKustomizeVersions defines the Kustomize binaries which may be selected by the version of the source.
+func WithKustomizeVersions(versions kustomize.Versions) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.kustomizeVersions = versions
+	}
+}
+
+// WithYttBinaryPath defines the path of the ytt binary used to render ytt applications.
+func WithYttBinaryPath(binaryPath string) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.yttBinaryPath = binaryPath
+	}
+}
+
+// WithCueBinaryPath defines the path of the cue binary used to render CUE applications.
+func WithCueBinaryPath(binaryPath string) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.cueBinaryPath = binaryPath
+	}
+}
+
+// WithPulumiEnabled defines whether Pulumi applications are previewed, they are refused otherwise.
+func WithPulumiEnabled(enabled bool) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.pulumiEnabled = enabled
+	}
+}
+
+// WithPulumiBinaryPath defines the path of the pulumi binary used to preview Pulumi applications.
+func WithPulumiBinaryPath(binaryPath string) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.pulumiBinaryPath = binaryPath
+	}
+}
+
+// WithPulumiCache defines the cache storing the manifests previewed from Pulumi programs by the hash of the programs.
+func WithPulumiCache(pulumiCache *cache.Cache) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.pulumiCache = pulumiCache
+	}
+}
+
+// WithTimoniBinaryPath defines the path of the timoni binary used to build Timoni applications.
+func WithTimoniBinaryPath(binaryPath string) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.timoniBinaryPath = binaryPath
+	}
+}
+
+// WithTimoniCache defines the cache storing the manifests built from Timoni modules by the hash of the modules and of
+// their values.
+func WithTimoniCache(timoniCache *cache.Cache) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.timoniCache = timoniCache
+	}
+}
+
+// WithCRDSchemaCache defines the cache storing the schemas of the CustomResourceDefinitions custom resources are
+// validated against.
+func WithCRDSchemaCache(schemaCache *cache.Cache) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.crdSchemaCache = schemaCache
+	}
+}
+
+// WithKustomizePluginHome defines the directory Kustomize looks up alpha plugins in when validating manifests.
+func WithKustomizePluginHome(pluginHome string) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.kustomizePluginHome = pluginHome
+	}
+}
+
+// WithKustomizeBaseCache defines the cache storing the rendered local bases of Kustomize overlays, and the metrics
+// server counting the lookups of the bases.
+func WithKustomizeBaseCache(baseCache *cache.Cache, metricsServer *metrics.MetricsServer) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.kustomizeBaseCache = baseCache
+		o.metricsServer = metricsServer
+	}
+}
+
+// WithKustomizeOCIBases makes the OCI bases of kustomizations be pulled with the clients of their repositories created
+// by newOCIRepository, and defines the cache storing their files.
+func WithKustomizeOCIBases(baseCache *cache.Cache, newOCIRepository func(reference string, creds helm.Creds, proxy string) (*remote.Repository, error)) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.kustomizeOCIBaseCache = baseCache
+		o.newOCIRepository = newOCIRepository
+	}
+}
+
+// WithHelmDependencyUpdate makes the manifest generation of a chart declaring dependencies which were not committed run
+// `helm dependency update` with the given timeout first, and cache the dependencies.
+func WithHelmDependencyUpdate(depCache *cache.Cache, timeout time.Duration) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.helmDependencyUpdater = &helmDependencyUpdater{cache: depCache, timeout: timeout}
+	}
+}
+
+// kustomizeBaseCache caches the rendered Kustomize bases of a revision of a repository in the repo server cache
+type kustomizeBaseCache struct {
+	cache         *cache.Cache
+	metricsServer *metrics.MetricsServer
+	repoURL       string
+	revision      string
+}
+
+func (c *kustomizeBaseCache) GetBase(basePath string) ([]byte, bool) {
+	manifests, err := c.cache.GetKustomizeBase(c.repoURL, c.revision, basePath)
+	if err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+		log.Warnf("Failed to get rendered Kustomize base %s of %s at %s from cache: %v", basePath, c.repoURL, c.revision, err)
+	}
+	hit := err == nil
+	if c.metricsServer != nil {
+		c.metricsServer.IncKustomizeBaseCacheRequest(hit)
+	}
+	return manifests, hit
+}
+
+func (c *kustomizeBaseCache) SetBase(basePath string, manifests []byte) {
+	if err := c.cache.SetKustomizeBase(c.repoURL, c.revision, basePath, manifests); err != nil {
+		log.Warnf("Failed to cache rendered Kustomize base %s of %s at %s: %v", basePath, c.repoURL, c.revision, err)
+	}
+}
+
+// WithParseWorkerPool sets the pool parsing the YAML documents of the manifests rendered by Helm concurrently
+func WithParseWorkerPool(pool *ParseWorkerPool) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.parsePool = pool
+	}
+}
+
+// WithGeneratorTimeouts defines the timeouts of the manifest generators, and the metrics server counting the
+// generations which time out.
+func WithGeneratorTimeouts(timeouts GeneratorTimeouts, metricsServer *metrics.MetricsServer) GenerateManifestOpt {
+	return func(o *generateManifestOpt) {
+		o.generatorTimeouts = timeouts
+		o.metricsServer = metricsServer
+	}
+}
+
 // GenerateManifests generates manifests from a path. Overrides are applied as a side effect on the given ApplicationSource.
 func GenerateManifests(ctx context.Context, appPath, repoRoot, revision string, q *apiclient.ManifestRequest, isLocal bool, gitCredsStore git.CredsStore, maxCombinedManifestQuantity resource.Quantity, gitRepoPaths io.TempPaths, opts ...GenerateManifestOpt) (*apiclient.ManifestResponse, error) {
 	opt := newGenerateManifestOpt(opts...)
 	var targetObjs []*unstructured.Unstructured
 
-	resourceTracking := argo.NewResourceTracking()
-
 	env := newEnv(q, revision)
 
 	appSourceType, err := GetAppSourceType(ctx, q.ApplicationSource, appPath, repoRoot, q.AppName, q.EnabledSourceTypes, opt.cmpTarExcludedGlobs, env.Environ())
@@ -1425,44 +1837,119 @@ func GenerateManifests(ctx context.Context, appPath, repoRoot, revision string,
 	}
 
 	var commands []string
+	var warnings []string
+	var autoValueFiles []string
 
-	switch appSourceType {
-	case v1alpha1.ApplicationSourceTypeHelm:
-		var command string
-		targetObjs, command, err = helmTemplate(appPath, repoRoot, env, q, isLocal, gitRepoPaths)
-		commands = append(commands, command)
-	case v1alpha1.ApplicationSourceTypeKustomize:
-		kustomizeBinary := ""
-		if q.KustomizeOptions != nil {
-			kustomizeBinary = q.KustomizeOptions.BinaryPath
-		}
-		k := kustomize.NewKustomizeApp(repoRoot, appPath, q.Repo.GetGitCreds(gitCredsStore), repoURL, kustomizeBinary)
-		targetObjs, _, commands, err = k.Build(q.ApplicationSource.Kustomize, q.KustomizeOptions, env, &kustomize.BuildOpts{
-			KubeVersion: text.SemVer(q.ApplicationSource.GetKubeVersionOrDefault(q.KubeVersion)),
-			APIVersions: q.ApplicationSource.GetAPIVersionsOrDefault(q.ApiVersions),
-		})
-	case v1alpha1.ApplicationSourceTypePlugin:
-		pluginName := ""
-		if q.ApplicationSource.Plugin != nil {
-			pluginName = q.ApplicationSource.Plugin.Name
-		}
-		// if pluginName is provided it has to be `<metadata.name>-<spec.version>` or just `<metadata.name>` if plugin version is empty
-		targetObjs, err = runConfigManagementPluginSidecars(ctx, appPath, repoRoot, pluginName, env, q, opt.cmpTarDoneCh, opt.cmpTarExcludedGlobs)
-		if err != nil {
-			err = fmt.Errorf("plugin sidecar failed. %s", err.Error())
-		}
-	case v1alpha1.ApplicationSourceTypeDirectory:
-		var directory *v1alpha1.ApplicationSourceDirectory
-		if directory = q.ApplicationSource.Directory; directory == nil {
-			directory = &v1alpha1.ApplicationSourceDirectory{}
+	generator, timeout := opt.generatorTimeouts.forSourceType(appSourceType)
+	err = runGenerator(ctx, generator, timeout, opt.metricsServer, func(ctx context.Context) error {
+		var err error
+		switch appSourceType {
+		case v1alpha1.ApplicationSourceTypeHelm:
+			var command string
+			targetObjs, command, autoValueFiles, err = helmTemplate(appPath, repoRoot, env, q, isLocal, gitRepoPaths, opt.parsePool, timeout, opt.helmDependencyUpdater)
+			commands = append(commands, command)
+			if err == nil && q.ApplicationSource.Helm != nil && q.ApplicationSource.Helm.ValidateCRs {
+				err = validateCustomResources(targetObjs, opt.crdSchemaCache)
+			}
+		case v1alpha1.ApplicationSourceTypeKustomize:
+			var kustomizeBinary string
+			kustomizeBinary, err = opt.kustomizeVersions.BinaryPath(q.ApplicationSource.Kustomize, q.KustomizeOptions)
+			if err != nil {
+				return err
+			}
+			k := kustomize.NewKustomizeApp(repoRoot, appPath, q.Repo.GetGitCreds(gitCredsStore), repoURL, kustomizeBinary)
+			buildOpts := &kustomize.BuildOpts{
+				KubeVersion: text.SemVer(q.ApplicationSource.GetKubeVersionOrDefault(q.KubeVersion)),
+				APIVersions: q.ApplicationSource.GetAPIVersionsOrDefault(q.ApiVersions),
+				PluginHome:  opt.kustomizePluginHome,
+				Timeout:     timeout,
+			}
+			// bases are cached regardless of the Kustomize version, so they are only cached when rendered by the default binary
+			if opt.kustomizeBaseCache != nil && kustomizeBinary == "" && repoURL != "" && !isLocal {
+				buildOpts.BaseCache = &kustomizeBaseCache{cache: opt.kustomizeBaseCache, metricsServer: opt.metricsServer, repoURL: repoURL, revision: revision}
+			}
+			if opt.kustomizeOCIBaseCache != nil {
+				buildOpts.OCIBases = &kustomizeOCIBaseFetcher{
+					ctx:              ctx,
+					cache:            opt.kustomizeOCIBaseCache,
+					newOCIRepository: opt.newOCIRepository,
+					repos:            q.Repos,
+					repoCreds:        q.HelmRepoCreds,
+					maxSize:          maxCombinedManifestQuantity.Value(),
+					noCache:          q.NoCache,
+				}
+			}
+			var kustomizeWarnings []kustomize.Warning
+			targetObjs, _, commands, kustomizeWarnings, err = k.Build(q.ApplicationSource.Kustomize, q.KustomizeOptions, env, buildOpts)
+			for _, warning := range kustomizeWarnings {
+				warnings = append(warnings, warning.String())
+			}
+		case v1alpha1.ApplicationSourceTypeYtt:
+			var command string
+			targetObjs, command, err = NewYttGenerator(opt.yttBinaryPath).Generate(appPath, repoRoot, q.ApplicationSource.Ytt)
+			commands = append(commands, command)
+		case v1alpha1.ApplicationSourceTypeCue:
+			var command string
+			targetObjs, command, err = NewCueGenerator(opt.cueBinaryPath).Generate(appPath, repoRoot, q.ApplicationSource.Cue)
+			commands = append(commands, command)
+		case v1alpha1.ApplicationSourceTypePulumi:
+			if !opt.pulumiEnabled {
+				err = ErrPulumiDisabled
+				break
+			}
+			var command string
+			targetObjs, command, err = NewPulumiGenerator(opt.pulumiBinaryPath, opt.pulumiCache).Generate(appPath, repoRoot, q.ApplicationSource.Pulumi, q.NoCache)
+			commands = append(commands, command)
+		case v1alpha1.ApplicationSourceTypeTimoni:
+			var command string
+			instance := TimoniInstance{Name: q.AppName, Namespace: q.Namespace}
+			creds := timoniRegistryCreds(q.ApplicationSource.Timoni, q.Repos, q.HelmRepoCreds)
+			targetObjs, command, err = NewTimoniGenerator(opt.timoniBinaryPath, opt.timoniCache).Generate(appPath, repoRoot, instance, q.ApplicationSource.Timoni, creds, q.NoCache)
+			commands = append(commands, command)
+		case v1alpha1.ApplicationSourceTypeStarlark:
+			targetObjs, err = NewStarlarkGenerator().Generate(ctx, appPath, repoRoot, revision, q.ApplicationSource.Starlark)
+		case v1alpha1.ApplicationSourceTypePlugin:
+			pluginName := ""
+			if q.ApplicationSource.Plugin != nil {
+				pluginName = q.ApplicationSource.Plugin.Name
+			}
+			// if pluginName is provided it has to be `<metadata.name>-<spec.version>` or just `<metadata.name>` if plugin version is empty
+			targetObjs, err = runConfigManagementPluginSidecars(ctx, appPath, repoRoot, pluginName, env, q, opt.cmpTarDoneCh, opt.cmpTarExcludedGlobs)
+			if err != nil {
+				err = fmt.Errorf("plugin sidecar failed. %s", err.Error())
+			}
+		case v1alpha1.ApplicationSourceTypeDirectory:
+			var directory *v1alpha1.ApplicationSourceDirectory
+			if directory = q.ApplicationSource.Directory; directory == nil {
+				directory = &v1alpha1.ApplicationSourceDirectory{}
+			}
+			logCtx := grpc.LoggerFromContext(ctx).WithField("application", q.AppName)
+			targetObjs, err = findManifests(logCtx, appPath, repoRoot, env, *directory, q.EnabledSourceTypes, maxCombinedManifestQuantity)
 		}
-		logCtx := log.WithField("application", q.AppName)
-		targetObjs, err = findManifests(logCtx, appPath, repoRoot, env, *directory, q.EnabledSourceTypes, maxCombinedManifestQuantity)
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
+
+	manifests, err := marshalManifests(targetObjs, q)
 	if err != nil {
 		return nil, err
 	}
 
+	return &apiclient.ManifestResponse{
+		Manifests:      manifests,
+		SourceType:     string(appSourceType),
+		Commands:       commands,
+		Warnings:       warnings,
+		AutoValueFiles: autoValueFiles,
+	}, nil
+}
+
+// marshalManifests sets the tracking information of the application on the given objects, flattens lists and
+// returns the resulting manifests as JSON
+func marshalManifests(targetObjs []*unstructured.Unstructured, q *apiclient.ManifestRequest) ([]string, error) {
+	resourceTracking := argo.NewResourceTracking()
 	manifests := make([]string, 0)
 	for _, obj := range targetObjs {
 		if obj == nil {
@@ -1471,7 +1958,7 @@ func GenerateManifests(ctx context.Context, appPath, repoRoot, revision string,
 
 		var targets []*unstructured.Unstructured
 		if obj.IsList() {
-			err = obj.EachListItem(func(object runtime.Object) error {
+			err := obj.EachListItem(func(object runtime.Object) error {
 				unstructuredObj, ok := object.(*unstructured.Unstructured)
 				if ok {
 					targets = append(targets, unstructuredObj)
@@ -1490,7 +1977,7 @@ func GenerateManifests(ctx context.Context, appPath, repoRoot, revision string,
 
 		for _, target := range targets {
 			if q.AppLabelKey != "" && q.AppName != "" && !kube.IsCRD(target) {
-				err = resourceTracking.SetAppInstance(target, q.AppLabelKey, q.AppName, q.Namespace, v1alpha1.TrackingMethod(q.TrackingMethod))
+				err := resourceTracking.SetAppInstance(target, q.AppLabelKey, q.AppName, q.Namespace, v1alpha1.TrackingMethod(q.TrackingMethod))
 				if err != nil {
 					return nil, fmt.Errorf("failed to set app instance tracking info on manifest: %w", err)
 				}
@@ -1502,11 +1989,22 @@ func GenerateManifests(ctx context.Context, appPath, repoRoot, revision string,
 			manifests = append(manifests, string(manifestStr))
 		}
 	}
+	return manifests, nil
+}
 
+// GenerateInlineManifests returns the manifests defined inline in the application source, without any repository operation
+func GenerateInlineManifests(q *apiclient.ManifestRequest) (*apiclient.ManifestResponse, error) {
+	targetObjs, err := kube.SplitYAML([]byte(q.ApplicationSource.Inline.Manifests))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to parse inline manifests: %v", err)
+	}
+	manifests, err := marshalManifests(targetObjs, q)
+	if err != nil {
+		return nil, err
+	}
 	return &apiclient.ManifestResponse{
 		Manifests:  manifests,
-		SourceType: string(appSourceType),
-		Commands:   commands,
+		SourceType: string(v1alpha1.ApplicationSourceTypeInline),
 	}, nil
 }
 
@@ -1607,7 +2105,7 @@ func GetAppSourceType(ctx context.Context, source *v1alpha1.ApplicationSource, a
 	}
 	if appSourceType != nil {
 		if !discovery.IsManifestGenerationEnabled(*appSourceType, enableGenerateManifests) {
-			log.Debugf("Manifest generation is disabled for '%s'. Assuming plain YAML manifest.", *appSourceType)
+			grpc.LoggerFromContext(ctx).Debugf("Manifest generation is disabled for '%s'. Assuming plain YAML manifest.", *appSourceType)
 			return v1alpha1.ApplicationSourceTypeDirectory, nil
 		}
 		return *appSourceType, nil
@@ -1985,7 +2483,7 @@ func runConfigManagementPluginSidecars(ctx context.Context, appPath, repoPath, p
 			if len(manifestString) > 1000 {
 				sanitizedManifestString = sanitizedManifestString[:1000]
 			}
-			log.Debugf("Failed to convert generated manifests. Beginning of generated manifests: %q", sanitizedManifestString)
+			grpc.LoggerFromContext(ctx).Debugf("Failed to convert generated manifests. Beginning of generated manifests: %q", sanitizedManifestString)
 			return nil, fmt.Errorf("failed to convert CMP manifests to unstructured objects: %s", err.Error())
 		}
 		manifests = append(manifests, manifestObjs...)
@@ -2015,6 +2513,18 @@ func generateManifestsCMP(ctx context.Context, appPath, repoPath string, env []s
 
 func (s *Service) GetAppDetails(ctx context.Context, q *apiclient.RepoServerAppDetailsQuery) (*apiclient.RepoAppDetailsResponse, error) {
 	res := &apiclient.RepoAppDetailsResponse{}
+	if q.Source.IsInline() {
+		res.Type = string(v1alpha1.ApplicationSourceTypeInline)
+		return res, nil
+	}
+	if q.Source.IsHTTP() {
+		res.Type = string(v1alpha1.ApplicationSourceTypeHTTP)
+		return res, nil
+	}
+	if q.Source.IsOCI() {
+		res.Type = string(v1alpha1.ApplicationSourceTypeOCI)
+		return res, nil
+	}
 
 	cacheFn := s.createGetAppDetailsCacheHandler(res, q)
 	operation := func(repoRoot, commitSHA, revision string, ctxSrc operationContextSrc) error {
@@ -2038,13 +2548,40 @@ func (s *Service) GetAppDetails(ctx context.Context, q *apiclient.RepoServerAppD
 				return err
 			}
 		case v1alpha1.ApplicationSourceTypeKustomize:
-			if err := populateKustomizeAppDetails(res, q, repoRoot, opContext.appPath, commitSHA, s.gitCredsStore); err != nil {
+			ociBases := &kustomizeOCIBaseFetcher{
+				ctx:              ctx,
+				cache:            s.cache,
+				newOCIRepository: s.newOCIRepository,
+				repos:            q.Repos,
+				maxSize:          s.initConstants.MaxCombinedDirectoryManifestsSize.Value(),
+				noCache:          q.NoCache,
+			}
+			if err := populateKustomizeAppDetails(res, q, repoRoot, opContext.appPath, commitSHA, s.gitCredsStore, s.initConstants.KustomizeVersions, ociBases); err != nil {
 				return err
 			}
 		case v1alpha1.ApplicationSourceTypePlugin:
 			if err := populatePluginAppDetails(ctx, res, opContext.appPath, repoRoot, q, s.initConstants.CMPTarExcludedGlobs); err != nil {
 				return fmt.Errorf("failed to populate plugin app details: %w", err)
 			}
+		case v1alpha1.ApplicationSourceTypeStarlark:
+			if _, err := NewStarlarkGenerator().ResolveEntrypoint(opContext.appPath, repoRoot, q.Source.Starlark); err != nil {
+				return err
+			}
+		case v1alpha1.ApplicationSourceTypeCue:
+			if err := NewCueGenerator(s.initConstants.CueBinaryPath).Validate(opContext.appPath, repoRoot, q.Source.Cue); err != nil {
+				return err
+			}
+		case v1alpha1.ApplicationSourceTypePulumi:
+			if !s.initConstants.EnablePulumi {
+				return ErrPulumiDisabled
+			}
+			if err := NewPulumiGenerator(s.initConstants.PulumiBinaryPath, nil).Validate(opContext.appPath, repoRoot, q.Source.Pulumi); err != nil {
+				return err
+			}
+		case v1alpha1.ApplicationSourceTypeTimoni:
+			if err := NewTimoniGenerator(s.initConstants.TimoniBinaryPath, nil).Validate(opContext.appPath, repoRoot, q.Source.Timoni); err != nil {
+				return err
+			}
 		}
 		_ = s.cache.SetAppDetails(revision, q.Source, q.RefSources, res, v1alpha1.TrackingMethod(q.TrackingMethod), nil)
 		return nil
@@ -2112,11 +2649,18 @@ func populateHelmAppDetails(res *apiclient.RepoAppDetailsResponse, appPath strin
 	} else {
 		log.Warnf("Values file %s is not allowed: %v", filepath.Join(appPath, "values.yaml"), err)
 	}
+	if resolvedSchemaPath, _, err := pathutil.ResolveValueFilePathOrUrl(appPath, repoRoot, "values.schema.json", []string{}); err == nil {
+		if err := loadFileIntoIfExists(resolvedSchemaPath, &res.Helm.ValuesSchema); err != nil {
+			return err
+		}
+	} else {
+		log.Warnf("Values schema file %s is not allowed: %v", filepath.Join(appPath, "values.schema.json"), err)
+	}
 	ignoreMissingValueFiles := false
 	if q.Source.Helm != nil {
 		ignoreMissingValueFiles = q.Source.Helm.IgnoreMissingValueFiles
 	}
-	resolvedSelectedValueFiles, err := getResolvedValueFiles(appPath, repoRoot, &v1alpha1.Env{}, q.GetValuesFileSchemes(), selectedValueFiles, q.RefSources, gitRepoPaths, ignoreMissingValueFiles)
+	resolvedSelectedValueFiles, err := getResolvedValueFiles(appPath, repoRoot, &v1alpha1.Env{}, q.GetValuesFileSchemes(), selectedValueFiles, q.RefSources, gitRepoPaths, ignoreMissingValueFiles, "")
 	if err != nil {
 		return fmt.Errorf("failed to resolve value files: %w", err)
 	}
@@ -2174,11 +2718,11 @@ func walkHelmValueFilesInPath(root string, valueFiles *[]string) filepath.WalkFu
 	}
 }
 
-func populateKustomizeAppDetails(res *apiclient.RepoAppDetailsResponse, q *apiclient.RepoServerAppDetailsQuery, repoRoot string, appPath string, reversion string, credsStore git.CredsStore) error {
+func populateKustomizeAppDetails(res *apiclient.RepoAppDetailsResponse, q *apiclient.RepoServerAppDetailsQuery, repoRoot string, appPath string, reversion string, credsStore git.CredsStore, kustomizeVersions kustomize.Versions, ociBases kustomize.OCIBaseFetcher) error {
 	res.Kustomize = &apiclient.KustomizeAppSpec{}
-	kustomizeBinary := ""
-	if q.KustomizeOptions != nil {
-		kustomizeBinary = q.KustomizeOptions.BinaryPath
+	kustomizeBinary, err := kustomizeVersions.BinaryPath(q.Source.Kustomize, q.KustomizeOptions)
+	if err != nil {
+		return err
 	}
 	k := kustomize.NewKustomizeApp(repoRoot, appPath, q.Repo.GetGitCreds(credsStore), q.Repo.Repo, kustomizeBinary)
 	fakeManifestRequest := apiclient.ManifestRequest{
@@ -2188,7 +2732,7 @@ func populateKustomizeAppDetails(res *apiclient.RepoAppDetailsResponse, q *apicl
 		ApplicationSource: q.Source,
 	}
 	env := newEnv(&fakeManifestRequest, reversion)
-	_, images, _, err := k.Build(q.Source.Kustomize, q.KustomizeOptions, env, nil)
+	_, images, _, _, err := k.Build(q.Source.Kustomize, q.KustomizeOptions, env, &kustomize.BuildOpts{OCIBases: ociBases})
 	if err != nil {
 		return err
 	}
@@ -2255,9 +2799,9 @@ func (s *Service) GetRevisionMetadata(ctx context.Context, q *apiclient.RepoServ
 		// in the metadata, but none was requested, we remove it from the data
 		// that we return.
 		if q.CheckSignature && metadata.SignatureInfo == "" {
-			log.Infof("revision metadata cache hit, but need to regenerate due to missing signature info: %s/%s", q.Repo.Repo, q.Revision)
+			grpc.LoggerFromContext(ctx).Infof("revision metadata cache hit, but need to regenerate due to missing signature info: %s/%s", q.Repo.Repo, q.Revision)
 		} else {
-			log.Infof("revision metadata cache hit: %s/%s", q.Repo.Repo, q.Revision)
+			grpc.LoggerFromContext(ctx).Infof("revision metadata cache hit: %s/%s", q.Repo.Repo, q.Revision)
 			if !q.CheckSignature {
 				metadata.SignatureInfo = ""
 			}
@@ -2265,9 +2809,9 @@ func (s *Service) GetRevisionMetadata(ctx context.Context, q *apiclient.RepoServ
 		}
 	} else {
 		if !errors.Is(err, cache.ErrCacheMiss) {
-			log.Warnf("revision metadata cache error %s/%s: %v", q.Repo.Repo, q.Revision, err)
+			grpc.LoggerFromContext(ctx).Warnf("revision metadata cache error %s/%s: %v", q.Repo.Repo, q.Revision, err)
 		} else {
-			log.Infof("revision metadata cache miss: %s/%s", q.Repo.Repo, q.Revision)
+			grpc.LoggerFromContext(ctx).Infof("revision metadata cache miss: %s/%s", q.Repo.Repo, q.Revision)
 		}
 	}
 
@@ -2298,7 +2842,7 @@ func (s *Service) GetRevisionMetadata(ctx context.Context, q *apiclient.RepoServ
 	if gpg.IsGPGEnabled() && q.CheckSignature {
 		cs, err := gitClient.VerifyCommitSignature(q.Revision)
 		if err != nil {
-			log.Errorf("error verifying signature of commit '%s' in repo '%s': %v", q.Revision, q.Repo.Repo, err)
+			grpc.LoggerFromContext(ctx).Errorf("error verifying signature of commit '%s' in repo '%s': %v", q.Revision, q.Repo.Repo, err)
 			return nil, err
 		}
 
@@ -2323,13 +2867,13 @@ func (s *Service) GetRevisionMetadata(ctx context.Context, q *apiclient.RepoServ
 func (s *Service) GetRevisionChartDetails(ctx context.Context, q *apiclient.RepoServerRevisionChartDetailsRequest) (*v1alpha1.ChartDetails, error) {
 	details, err := s.cache.GetRevisionChartDetails(q.Repo.Repo, q.Name, q.Revision)
 	if err == nil {
-		log.Infof("revision chart details cache hit: %s/%s/%s", q.Repo.Repo, q.Name, q.Revision)
+		grpc.LoggerFromContext(ctx).Infof("revision chart details cache hit: %s/%s/%s", q.Repo.Repo, q.Name, q.Revision)
 		return details, nil
 	} else {
 		if errors.Is(err, cache.ErrCacheMiss) {
-			log.Infof("revision metadata cache miss: %s/%s/%s", q.Repo.Repo, q.Name, q.Revision)
+			grpc.LoggerFromContext(ctx).Infof("revision metadata cache miss: %s/%s/%s", q.Repo.Repo, q.Name, q.Revision)
 		} else {
-			log.Warnf("revision metadata cache error %s/%s/%s: %v", q.Repo.Repo, q.Name, q.Revision, err)
+			grpc.LoggerFromContext(ctx).Warnf("revision metadata cache error %s/%s/%s: %v", q.Repo.Repo, q.Name, q.Revision, err)
 		}
 	}
 	helmClient, revision, err := s.newHelmClientResolveRevision(q.Repo, q.Revision, q.Name, true)
@@ -2366,6 +2910,7 @@ func fileParameters(q *apiclient.RepoServerAppDetailsQuery) []v1alpha1.HelmFileP
 }
 
 func (s *Service) newClient(repo *v1alpha1.Repository, opts ...git.ClientOpts) (git.Client, error) {
+	repo = s.bundleRepo(repo)
 	keyData, err := json.Marshal(map[string]string{"url": git.NormalizeGitURL(repo.Repo), "project": repo.Project})
 	if err != nil {
 		return nil, err
@@ -2375,9 +2920,24 @@ func (s *Service) newClient(repo *v1alpha1.Repository, opts ...git.ClientOpts) (
 		return nil, err
 	}
 	opts = append(opts, git.WithEventHandlers(metrics.NewGitClientEventHandlers(s.metricsServer)))
+	if s.initConstants.GitShallowCloneDepth > 0 {
+		opts = append(opts, git.WithShallowClone(s.initConstants.GitShallowCloneDepth, s.initConstants.MaxShallowDeepenDepth))
+	}
 	return s.newGitClient(repo.Repo, repoPath, repo.GetGitCreds(s.gitCredsStore), repo.IsInsecure(), repo.EnableLFS, repo.Proxy, opts...)
 }
 
+// bundleRepo returns the local copy of the given repository if a git bundle was imported for it, or the repository itself otherwise
+func (s *Service) bundleRepo(repo *v1alpha1.Repository) *v1alpha1.Repository {
+	if s.bundles == nil || repo == nil {
+		return repo
+	}
+	path, ok := s.bundles.Path(repo.Repo)
+	if !ok {
+		return repo
+	}
+	return &v1alpha1.Repository{Repo: "file://" + path, Type: repo.Type, Project: repo.Project}
+}
+
 // newClientResolveRevision is a helper to perform the common task of instantiating a git client
 // and resolving a revision to a commit SHA
 func (s *Service) newClientResolveRevision(repo *v1alpha1.Repository, revision string, opts ...git.ClientOpts) (git.Client, string, error) {
@@ -2533,9 +3093,13 @@ func (s *Service) TestRepository(ctx context.Context, q *apiclient.TestRepositor
 	}
 	checks := map[string]func() error{
 		"git": func() error {
+			repo := s.bundleRepo(repo)
 			return git.TestRepo(repo.Repo, repo.GetGitCreds(s.gitCredsStore), repo.IsInsecure(), repo.IsLFSEnabled(), repo.Proxy)
 		},
 		"helm": func() error {
+			if repo.IsOCIManifests() {
+				return s.testOCIManifestsRepository(ctx, repo)
+			}
 			if repo.EnableOCI {
 				if !helm.IsHelmOciRepo(repo.Repo) {
 					return errors.New("OCI Helm repository URL should include hostname and port only")
@@ -2574,6 +3138,7 @@ func (s *Service) ResolveRevision(ctx context.Context, q *apiclient.ResolveRevis
 			AmbiguousRevision: fmt.Sprintf("%v (%v)", ambiguousRevision, revision),
 		}, nil
 	} else {
+		repo := s.bundleRepo(repo)
 		gitClient, err := git.NewClient(repo.Repo, repo.GetGitCreds(s.gitCredsStore), repo.IsInsecure(), repo.IsLFSEnabled(), repo.Proxy)
 		if err != nil {
 			return &apiclient.ResolveRevisionResponse{Revision: "", AmbiguousRevision: ""}, err
@@ -2590,7 +3155,7 @@ func (s *Service) ResolveRevision(ctx context.Context, q *apiclient.ResolveRevis
 	}
 }
 
-func (s *Service) GetGitFiles(_ context.Context, request *apiclient.GitFilesRequest) (*apiclient.GitFilesResponse, error) {
+func (s *Service) GetGitFiles(ctx context.Context, request *apiclient.GitFilesRequest) (*apiclient.GitFilesResponse, error) {
 	repo := request.GetRepo()
 	revision := request.GetRevision()
 	gitPath := request.GetPath()
@@ -2615,7 +3180,7 @@ func (s *Service) GetGitFiles(_ context.Context, request *apiclient.GitFilesRequ
 
 	// check the cache and return the results if present
 	if cachedFiles, err := s.cache.GetGitFiles(repo.Repo, revision, gitPath); err == nil {
-		log.Debugf("cache hit for repo: %s revision: %s pattern: %s", repo.Repo, revision, gitPath)
+		grpc.LoggerFromContext(ctx).Debugf("cache hit for repo: %s revision: %s pattern: %s", repo.Repo, revision, gitPath)
 		return &apiclient.GitFilesResponse{
 			Map: cachedFiles,
 		}, nil
@@ -2637,7 +3202,7 @@ func (s *Service) GetGitFiles(_ context.Context, request *apiclient.GitFilesRequ
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "unable to list files. repo %s with revision %s pattern %s: %v", repo.Repo, revision, gitPath, err)
 	}
-	log.Debugf("listed %d git files from %s under %s", len(gitFiles), repo.Repo, gitPath)
+	grpc.LoggerFromContext(ctx).Debugf("listed %d git files from %s under %s", len(gitFiles), repo.Repo, gitPath)
 
 	res := make(map[string][]byte)
 	for _, filePath := range gitFiles {
@@ -2650,7 +3215,7 @@ func (s *Service) GetGitFiles(_ context.Context, request *apiclient.GitFilesRequ
 
 	err = s.cache.SetGitFiles(repo.Repo, revision, gitPath, res)
 	if err != nil {
-		log.Warnf("error caching git files for repo %s with revision %s pattern %s: %v", repo.Repo, revision, gitPath, err)
+		grpc.LoggerFromContext(ctx).Warnf("error caching git files for repo %s with revision %s pattern %s: %v", repo.Repo, revision, gitPath, err)
 	}
 
 	return &apiclient.GitFilesResponse{
@@ -2680,7 +3245,7 @@ func verifyCommitSignature(verifyCommit bool, gitClient git.Client, revision str
 	return nil
 }
 
-func (s *Service) GetGitDirectories(_ context.Context, request *apiclient.GitDirectoriesRequest) (*apiclient.GitDirectoriesResponse, error) {
+func (s *Service) GetGitDirectories(ctx context.Context, request *apiclient.GitDirectoriesRequest) (*apiclient.GitDirectoriesResponse, error) {
 	repo := request.GetRepo()
 	revision := request.GetRevision()
 	noRevisionCache := request.GetNoRevisionCache()
@@ -2699,7 +3264,7 @@ func (s *Service) GetGitDirectories(_ context.Context, request *apiclient.GitDir
 
 	// check the cache and return the results if present
 	if cachedPaths, err := s.cache.GetGitDirectories(repo.Repo, revision); err == nil {
-		log.Debugf("cache hit for repo: %s revision: %s", repo.Repo, revision)
+		grpc.LoggerFromContext(ctx).Debugf("cache hit for repo: %s revision: %s", repo.Repo, revision)
 		return &apiclient.GitDirectoriesResponse{
 			Paths: cachedPaths,
 		}, nil
@@ -2747,10 +3312,10 @@ func (s *Service) GetGitDirectories(_ context.Context, request *apiclient.GitDir
 		return nil, err
 	}
 
-	log.Debugf("found %d git paths from %s", len(paths), repo.Repo)
+	grpc.LoggerFromContext(ctx).Debugf("found %d git paths from %s", len(paths), repo.Repo)
 	err = s.cache.SetGitDirectories(repo.Repo, revision, paths)
 	if err != nil {
-		log.Warnf("error caching git directories for repo %s with revision %s: %v", repo.Repo, revision, err)
+		grpc.LoggerFromContext(ctx).Warnf("error caching git directories for repo %s with revision %s: %v", repo.Repo, revision, err)
 	}
 
 	return &apiclient.GitDirectoriesResponse{
@@ -2762,8 +3327,8 @@ func (s *Service) GetGitDirectories(_ context.Context, request *apiclient.GitDir
 // If no files were changed, it will store the already cached manifest to the key corresponding to the old revision, avoiding an unnecessary generation.
 // Example: cache has key "a1a1a1" with manifest "x", and the files for that manifest have not changed,
 // "x" will be stored again with the new revision "b2b2b2".
-func (s *Service) UpdateRevisionForPaths(_ context.Context, request *apiclient.UpdateRevisionForPathsRequest) (*apiclient.UpdateRevisionForPathsResponse, error) {
-	logCtx := log.WithFields(log.Fields{"application": request.AppName, "appNamespace": request.Namespace})
+func (s *Service) UpdateRevisionForPaths(ctx context.Context, request *apiclient.UpdateRevisionForPathsRequest) (*apiclient.UpdateRevisionForPathsResponse, error) {
+	logCtx := grpc.LoggerFromContext(ctx).WithFields(log.Fields{"application": request.AppName, "appNamespace": request.Namespace})
 
 	repo := request.GetRepo()
 	revision := request.GetRevision()
@@ -2861,3 +3426,103 @@ func (s *Service) updateCachedRevision(logCtx *log.Entry, oldRev string, newRev
 	logCtx.Debugf("manifest cache updated for application %s in repo %s from revision %s to revision %s", request.AppName, request.GetRepo().Repo, oldRev, newRev)
 	return nil
 }
+
+// ImportBundle unpacks a git bundle into the local copy of a repository, which is used instead of the remote repository afterwards
+func (s *Service) ImportBundle(_ context.Context, q *apiclient.ImportBundleRequest) (*apiclient.ImportBundleResponse, error) {
+	if q.Repo == nil {
+		return nil, status.Error(codes.InvalidArgument, "must pass a valid repo")
+	}
+	if len(q.Bundle) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "must pass a git bundle")
+	}
+	bundleFile, err := os.CreateTemp("", "repo-*.bundle")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp file: %w", err)
+	}
+	defer func() { _ = os.Remove(bundleFile.Name()) }()
+	_, err = bundleFile.Write(q.Bundle)
+	if closeErr := bundleFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error writing bundle to temp file: %w", err)
+	}
+
+	refs, err := s.bundles.Import(q.Repo.Repo, bundleFile.Name(), q.Update)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to import bundle for repository %s: %v", q.Repo.Repo, err)
+	}
+	// refresh the cached references since they changed with the import
+	gitClient, err := s.newClient(q.Repo, git.WithCache(s.cache, false))
+	if err != nil {
+		return nil, fmt.Errorf("error creating git client: %w", err)
+	}
+	if _, err := gitClient.LsRefs(); err != nil {
+		return nil, fmt.Errorf("error listing refs of imported bundle: %w", err)
+	}
+	return &apiclient.ImportBundleResponse{Refs: refs}, nil
+}
+
+// ExportBundle creates a git bundle containing all references of a repository
+func (s *Service) ExportBundle(_ context.Context, q *apiclient.ExportBundleRequest) (*apiclient.ExportBundleResponse, error) {
+	if q.Repo == nil {
+		return nil, status.Error(codes.InvalidArgument, "must pass a valid repo")
+	}
+	gitClient, err := s.newClient(q.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("error creating git client: %w", err)
+	}
+	closer, err := s.repoLock.Lock(gitClient.Root(), "HEAD", false, func() (goio.Closer, error) {
+		closer := s.gitRepoInitializer(gitClient.Root())
+		if err := gitClient.Init(); err != nil {
+			return closer, status.Errorf(codes.Internal, "Failed to initialize git repo: %v", err)
+		}
+		if err := gitClient.Fetch(""); err != nil {
+			return closer, status.Errorf(codes.Internal, "Failed to fetch git repo: %v", err)
+		}
+		return closer, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer io.Close(closer)
+
+	tempDir, err := files.CreateTempDir("")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tempDir) }()
+	bundleFile := filepath.Join(tempDir, "repo.bundle")
+	if err := git.CreateBundle(gitClient.Root(), bundleFile); err != nil {
+		return nil, err
+	}
+	bundle, err := os.ReadFile(bundleFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading bundle: %w", err)
+	}
+	return &apiclient.ExportBundleResponse{Bundle: bundle}, nil
+}
+
+// defaultCachedKeysCount is the number of keys scanned per page when listing cached keys, unless requested otherwise
+const defaultCachedKeysCount = 100
+
+// ListCachedKeys returns a page of the keys stored by the repo server in the cache, along with their metadata
+func (s *Service) ListCachedKeys(ctx context.Context, q *apiclient.ListCachedKeysRequest) (*apiclient.CachedKeyList, error) {
+	count := q.Count
+	if count <= 0 {
+		count = defaultCachedKeysCount
+	}
+	keys, cursor, err := s.cache.ListCachedKeys(ctx, q.Cursor, count)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list cached keys: %v", err)
+	}
+	res := &apiclient.CachedKeyList{Cursor: cursor, Items: make([]*apiclient.CachedKey, len(keys))}
+	for i, key := range keys {
+		ttlSeconds := int64(-1)
+		if key.TTL >= 0 {
+			ttlSeconds = int64(key.TTL.Seconds())
+		}
+		res.Items[i] = &apiclient.CachedKey{Key: key.Key, Type: key.Type, TtlSeconds: ttlSeconds, SizeBytes: key.SizeBytes}
+	}
+	return res, nil
+}
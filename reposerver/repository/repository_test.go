@@ -8,6 +8,8 @@ import (
 	"fmt"
 	goio "io"
 	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path"
@@ -49,6 +51,7 @@ import (
 	helmmocks "github.com/argoproj/argo-cd/v2/util/helm/mocks"
 	"github.com/argoproj/argo-cd/v2/util/io"
 	iomocks "github.com/argoproj/argo-cd/v2/util/io/mocks"
+	pathutil "github.com/argoproj/argo-cd/v2/util/io/path"
 )
 
 const testSignature = `gpg: Signature made Wed Feb 26 23:22:34 2020 CET
@@ -223,6 +226,95 @@ func TestGenerateYamlManifestInDir(t *testing.T) {
 	assert.Len(t, res2.Manifests, 3)
 }
 
+func TestGenerateManifest_NamespaceIsolation(t *testing.T) {
+	service := newService(t, "./testdata/namespace-isolation")
+	newRequest := func() *apiclient.ManifestRequest {
+		return &apiclient.ManifestRequest{
+			Repo:               &argoappv1.Repository{},
+			ApplicationSource:  &argoappv1.ApplicationSource{Path: "."},
+			Namespace:          "tenant-a",
+			ProjectName:        "tenant-a",
+			ProjectSourceRepos: []string{"*"},
+			NoCache:            true,
+		}
+	}
+
+	t.Run("Disabled", func(t *testing.T) {
+		res, err := service.GenerateManifest(context.Background(), newRequest())
+		require.NoError(t, err)
+		assert.Len(t, res.Manifests, 4)
+	})
+
+	t.Run("CrossNamespace", func(t *testing.T) {
+		q := newRequest()
+		q.NamespaceIsolation = argoappv1.NamespaceIsolationStrict
+		q.ClusterScopedResources = []string{"ClusterRole.rbac.authorization.k8s.io"}
+		q.PermittedClusterResources = []string{"ClusterRole.rbac.authorization.k8s.io"}
+		_, err := service.GenerateManifest(context.Background(), q)
+		require.ErrorContains(t, err, "resource Secret kube-system/credentials is outside the destination namespace \"tenant-a\"")
+	})
+}
+
+func TestGenerateManifest_Inline(t *testing.T) {
+	service := newService(t, ".")
+	source := &argoappv1.ApplicationSource{
+		RepoURL: argoappv1.InlineSourceRepoURL,
+		Inline: &argoappv1.ApplicationSourceInline{Manifests: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: second
+`},
+	}
+
+	res, err := service.GenerateManifest(context.Background(), &apiclient.ManifestRequest{
+		Repo:              &argoappv1.Repository{Repo: argoappv1.InlineSourceRepoURL},
+		ApplicationSource: source,
+		AppLabelKey:       common.LabelKeyAppInstance,
+		AppName:           "inline",
+		TrackingMethod:    string(argo.TrackingMethodLabel),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, string(argoappv1.ApplicationSourceTypeInline), res.SourceType)
+	assert.Empty(t, res.Revision)
+	require.Len(t, res.Manifests, 2)
+	assert.Contains(t, res.Manifests[0], `"name":"first"`)
+	assert.Contains(t, res.Manifests[1], `"app.kubernetes.io/instance":"inline"`)
+
+	source.Inline.Manifests = "kind: ConfigMap\nmetadata: [name"
+	_, err = service.GenerateManifest(context.Background(), &apiclient.ManifestRequest{
+		Repo:              &argoappv1.Repository{Repo: argoappv1.InlineSourceRepoURL},
+		ApplicationSource: source,
+	})
+	require.ErrorContains(t, err, "failed to parse inline manifests")
+}
+
+func Test_checkNamespaceIsolation(t *testing.T) {
+	configMap := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config"}}`
+	tenantConfigMap := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"tenant-a"}}`
+	otherConfigMap := `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"tenant-b"}}`
+	clusterRole := `{"apiVersion":"rbac.authorization.k8s.io/v1","kind":"ClusterRole","metadata":{"name":"tenant-a"}}`
+	namespace := `{"apiVersion":"v1","kind":"Namespace","metadata":{"name":"tenant-b"}}`
+
+	q := &apiclient.ManifestRequest{
+		Namespace:                 "tenant-a",
+		ProjectName:               "tenant-a",
+		NamespaceIsolation:        argoappv1.NamespaceIsolationStrict,
+		ClusterScopedResources:    []string{"ClusterRole.rbac.authorization.k8s.io", "Namespace"},
+		PermittedClusterResources: []string{"ClusterRole.rbac.authorization.k8s.io"},
+	}
+	require.NoError(t, checkNamespaceIsolation(q, []string{configMap, tenantConfigMap, clusterRole}))
+	require.ErrorContains(t, checkNamespaceIsolation(q, []string{configMap, otherConfigMap}), "resource ConfigMap tenant-b/config is outside the destination namespace")
+	require.ErrorContains(t, checkNamespaceIsolation(q, []string{namespace}), "cluster-scoped resource Namespace tenant-b is not explicitly permitted")
+
+	q.NamespaceIsolation = ""
+	require.NoError(t, checkNamespaceIsolation(q, []string{otherConfigMap, namespace}))
+}
+
 func Test_GenerateManifests_NoOutOfBoundsAccess(t *testing.T) {
 	testCases := []struct {
 		name                    string
@@ -1122,6 +1214,56 @@ func TestGenerateHelmWithValues(t *testing.T) {
 	assert.True(t, replicasVerified)
 }
 
+func TestGenerateHelmWithValuesFrom(t *testing.T) {
+	service := newService(t, "../../util/helm/testdata/redis")
+
+	getSlaveReplicas := func(t *testing.T, res *apiclient.ManifestResponse) int32 {
+		t.Helper()
+		for _, src := range res.Manifests {
+			obj := unstructured.Unstructured{}
+			err := json.Unmarshal([]byte(src), &obj)
+			require.NoError(t, err)
+
+			if obj.GetKind() == "Deployment" && obj.GetName() == "test-redis-slave" {
+				var dep v1.Deployment
+				err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, &dep)
+				require.NoError(t, err)
+				return *dep.Spec.Replicas
+			}
+		}
+		require.Fail(t, "deployment test-redis-slave not found")
+		return 0
+	}
+	req := &apiclient.ManifestRequest{
+		Repo:    &argoappv1.Repository{},
+		AppName: "test",
+		ApplicationSource: &argoappv1.ApplicationSource{
+			Path: ".",
+			Helm: &argoappv1.ApplicationSourceHelm{
+				ValueFiles: []string{"values-production.yaml"},
+				ValuesFrom: &argoappv1.HelmValuesFrom{ConfigMapKeyRef: &argoappv1.HelmValuesKeyRef{Name: "helm-values", Key: "values.yaml"}},
+			},
+		},
+		HelmValuesFrom:     []string{`cluster: {slaveCount: 4}`, `cluster: {slaveCount: 5}`},
+		ProjectName:        "something",
+		ProjectSourceRepos: []string{"*"},
+	}
+
+	t.Run("Values from the cluster override the value files", func(t *testing.T) {
+		res, err := service.GenerateManifest(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, int32(5), getSlaveReplicas(t, res))
+		assert.Contains(t, res.Commands[0], "<temp file with values from source.helm.valuesFrom>")
+	})
+
+	t.Run("Inline values override the values from the cluster", func(t *testing.T) {
+		req.ApplicationSource.Helm.ValuesObject = &runtime.RawExtension{Raw: []byte(`cluster: {slaveCount: 2}`)}
+		res, err := service.GenerateManifest(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), getSlaveReplicas(t, res))
+	})
+}
+
 func TestHelmWithMissingValueFiles(t *testing.T) {
 	service := newService(t, "../../util/helm/testdata/redis")
 	missingValuesFile := "values-prod-overrides.yaml"
@@ -1585,9 +1727,12 @@ func TestListApps(t *testing.T) {
 		"oci-dependencies":                  "Helm",
 		"out-of-bounds-values-file-link":    "Helm",
 		"values-files":                      "Helm",
+		"values-schema":                     "Helm",
 		"helm-with-dependencies":            "Helm",
 		"helm-with-dependencies-alias":      "Helm",
 		"helm-with-local-dependency":        "Helm",
+		"helm-crd-validation":               "Helm",
+		"helm-auto-values-files":            "Helm",
 		"simple-chart":                      "Helm",
 	}
 	assert.Equal(t, expectedApps, res.Apps)
@@ -1645,6 +1790,17 @@ func TestGetAppDetailsHelm_WithNoValuesFile(t *testing.T) {
 	assert.Equal(t, "", res.Helm.Values)
 }
 
+func Test_populateHelmAppDetails_valuesSchema(t *testing.T) {
+	emptyTempPaths := io.NewRandomizedTempPaths(t.TempDir())
+	res := apiclient.RepoAppDetailsResponse{}
+	q := apiclient.RepoServerAppDetailsQuery{Repo: &argoappv1.Repository{}, Source: &argoappv1.ApplicationSource{}}
+	appPath, err := filepath.Abs("./testdata/values-schema/")
+	require.NoError(t, err)
+	err = populateHelmAppDetails(&res, appPath, appPath, &q, emptyTempPaths)
+	require.NoError(t, err)
+	assert.Contains(t, res.Helm.ValuesSchema, `"required": ["replicas"]`)
+}
+
 func TestGetAppDetailsKustomize(t *testing.T) {
 	service := newService(t, "../../util/kustomize/testdata/kustomization_yaml")
 
@@ -3352,7 +3508,7 @@ func Test_getResolvedValueFiles(t *testing.T) {
 		tcc := tc
 		t.Run(tcc.name, func(t *testing.T) {
 			t.Parallel()
-			resolvedPaths, err := getResolvedValueFiles(path.Join(tempDir, "main-repo"), path.Join(tempDir, "main-repo"), tcc.env, []string{}, []string{tcc.rawPath}, tcc.refSources, paths, false)
+			resolvedPaths, err := getResolvedValueFiles(path.Join(tempDir, "main-repo"), path.Join(tempDir, "main-repo"), tcc.env, []string{}, []string{tcc.rawPath}, tcc.refSources, paths, false, "")
 			if !tcc.expectedErr {
 				require.NoError(t, err)
 				require.Len(t, resolvedPaths, 1)
@@ -3365,6 +3521,96 @@ func Test_getResolvedValueFiles(t *testing.T) {
 	}
 }
 
+func TestGetResolvedValueFiles_ValueFilesSuffix(t *testing.T) {
+	repoRoot := t.TempDir()
+	for _, name := range []string{"values.yaml", "values-us-east-1.yaml", "region-us-east-1.yaml", "env/prod.yaml"} {
+		require.NoError(t, os.MkdirAll(path.Dir(path.Join(repoRoot, name)), 0o755))
+		require.NoError(t, os.WriteFile(path.Join(repoRoot, name), []byte("{}"), 0o644))
+	}
+	valueFiles := []string{"values.yaml", "env/prod.yaml"}
+
+	t.Run("Suffixed value file exists", func(t *testing.T) {
+		resolvedPaths, err := getResolvedValueFiles(repoRoot, repoRoot, &argoappv1.Env{}, []string{}, valueFiles, nil, io.NewRandomizedTempPaths(t.TempDir()), false, "us-east-1")
+		require.NoError(t, err)
+		assert.Equal(t, []pathutil.ResolvedFilePath{
+			pathutil.ResolvedFilePath(path.Join(repoRoot, "values-us-east-1.yaml")),
+			pathutil.ResolvedFilePath(path.Join(repoRoot, "env/prod.yaml")),
+		}, resolvedPaths)
+	})
+
+	t.Run("Falls back to the value file", func(t *testing.T) {
+		resolvedPaths, err := getResolvedValueFiles(repoRoot, repoRoot, &argoappv1.Env{}, []string{}, valueFiles, nil, io.NewRandomizedTempPaths(t.TempDir()), false, "eu-west-1")
+		require.NoError(t, err)
+		assert.Equal(t, []pathutil.ResolvedFilePath{
+			pathutil.ResolvedFilePath(path.Join(repoRoot, "values.yaml")),
+			pathutil.ResolvedFilePath(path.Join(repoRoot, "env/prod.yaml")),
+		}, resolvedPaths)
+	})
+
+	t.Run("Only the suffixed value file exists", func(t *testing.T) {
+		resolvedPaths, err := getResolvedValueFiles(repoRoot, repoRoot, &argoappv1.Env{}, []string{}, []string{"region.yaml", "missing.yaml"}, nil, io.NewRandomizedTempPaths(t.TempDir()), true, "us-east-1")
+		require.NoError(t, err)
+		assert.Equal(t, []pathutil.ResolvedFilePath{pathutil.ResolvedFilePath(path.Join(repoRoot, "region-us-east-1.yaml"))}, resolvedPaths)
+	})
+}
+
+func TestGenerateHelmWithAutoValuesFile(t *testing.T) {
+	service := newService(t, "./testdata/helm-auto-values-files")
+	generate := func(autoValuesFile bool, namespace string, clusterName string) (*apiclient.ManifestResponse, map[string]interface{}) {
+		res, err := service.GenerateManifest(context.Background(), &apiclient.ManifestRequest{
+			Repo:                   &argoappv1.Repository{},
+			AppName:                "test",
+			Namespace:              namespace,
+			DestinationClusterName: clusterName,
+			ApplicationSource: &argoappv1.ApplicationSource{
+				Path: ".",
+				Helm: &argoappv1.ApplicationSourceHelm{ValueFiles: []string{"values.yaml"}, AutoValuesFile: autoValuesFile},
+			},
+			ProjectName:        "something",
+			ProjectSourceRepos: []string{"*"},
+			NoCache:            true,
+		})
+		require.NoError(t, err)
+		require.Len(t, res.Manifests, 1)
+		obj := unstructured.Unstructured{}
+		require.NoError(t, json.Unmarshal([]byte(res.Manifests[0]), &obj))
+		data, _, err := unstructured.NestedMap(obj.Object, "data")
+		require.NoError(t, err)
+		return res, data
+	}
+
+	t.Run("Namespace and cluster value files", func(t *testing.T) {
+		res, data := generate(true, "dev", "prod-cluster")
+		assert.Equal(t, []string{"values-dev.yaml", "values-prod-cluster.yaml"}, res.AutoValueFiles)
+		assert.Equal(t, map[string]interface{}{"env": "dev", "replicas": "3"}, data)
+	})
+
+	t.Run("Missing value files are ignored", func(t *testing.T) {
+		res, data := generate(true, "staging", "other-cluster")
+		assert.Empty(t, res.AutoValueFiles)
+		assert.Equal(t, map[string]interface{}{"env": "default", "replicas": "1"}, data)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		res, data := generate(false, "dev", "prod-cluster")
+		assert.Empty(t, res.AutoValueFiles)
+		assert.Equal(t, map[string]interface{}{"env": "default", "replicas": "1"}, data)
+	})
+}
+
+func TestGetAutoValueFiles(t *testing.T) {
+	appPath := "./testdata/helm-auto-values-files"
+	valueFile := func(name string) pathutil.ResolvedFilePath {
+		return pathutil.ResolvedFilePath(filepath.Join(appPath, name))
+	}
+
+	assert.Equal(t, []pathutil.ResolvedFilePath{valueFile("values-dev.yaml")}, getAutoValueFiles(appPath, "dev", "", nil))
+	// value files which are already selected are not appended again
+	assert.Empty(t, getAutoValueFiles(appPath, "dev", "dev", []pathutil.ResolvedFilePath{valueFile("values-dev.yaml")}))
+	// names which are not a single path element are ignored
+	assert.Empty(t, getAutoValueFiles(appPath, "../helm-auto-values-files/values-dev", "templates/../values-dev", nil))
+}
+
 func TestErrorGetGitDirectories(t *testing.T) {
 	// test not using the cache
 	root := "./testdata/git-files-dirs"
@@ -4300,3 +4546,59 @@ images:
 		}, res.Commands)
 	})
 }
+
+func TestListCachedKeys(t *testing.T) {
+	cacheMocks := newCacheMocks()
+	t.Cleanup(cacheMocks.mockCache.StopRedisCallback)
+	service := NewService(metrics.NewMetricsServer(), cacheMocks.cache, RepoServerInitConstants{ParallelismLimit: 1}, argo.NewResourceTracking(), &git.NoopCredsStore{}, t.TempDir())
+
+	require.NoError(t, cacheMocks.cache.SetApps("https://github.com/org/repo", "main", map[string]string{"app": "Kustomize"}))
+	require.NoError(t, cacheMocks.cache.SetHelmIndex("https://charts.example.com", []byte("index")))
+
+	var items []*apiclient.CachedKey
+	req := &apiclient.ListCachedKeysRequest{Count: 1}
+	for {
+		res, err := service.ListCachedKeys(context.Background(), req)
+		require.NoError(t, err)
+		items = append(items, res.Items...)
+		if res.Cursor == 0 {
+			break
+		}
+		req.Cursor = res.Cursor
+	}
+
+	require.Len(t, items, 2)
+	types := map[string]string{}
+	for _, item := range items {
+		types[item.Key] = item.Type
+		assert.Positive(t, item.TtlSeconds)
+		assert.Positive(t, item.SizeBytes)
+	}
+	assert.Equal(t, map[string]string{
+		"ldir|https://github.com/org/repo|main|" + common.CacheVersion: cache.CachedKeyTypeAppList,
+		"helm-index|https://charts.example.com|" + common.CacheVersion: cache.CachedKeyTypeIndex,
+	}, types)
+}
+
+func TestKustomizeBaseCache(t *testing.T) {
+	cacheMocks := newCacheMocks()
+	t.Cleanup(cacheMocks.mockCache.StopRedisCallback)
+	metricsServer := metrics.NewMetricsServer()
+	baseCache := &kustomizeBaseCache{cache: cacheMocks.cache, metricsServer: metricsServer, repoURL: "https://github.com/argoproj/argocd-example-apps", revision: "abc123"}
+
+	_, ok := baseCache.GetBase("guestbook/base")
+	assert.False(t, ok)
+	baseCache.SetBase("guestbook/base", []byte("kind: Deployment"))
+	manifests, ok := baseCache.GetBase("guestbook/base")
+	require.True(t, ok)
+	assert.Equal(t, "kind: Deployment", string(manifests))
+
+	// bases are cached per revision
+	_, ok = (&kustomizeBaseCache{cache: cacheMocks.cache, metricsServer: metricsServer, repoURL: baseCache.repoURL, revision: "def456"}).GetBase("guestbook/base")
+	assert.False(t, ok)
+
+	rr := httptest.NewRecorder()
+	metricsServer.GetHandler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rr.Body.String(), `argocd_kustomize_base_cache_requests_total{result="hit"} 1`)
+	assert.Contains(t, rr.Body.String(), `argocd_kustomize_base_cache_requests_total{result="miss"} 2`)
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"go.starlark.net/starlark"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	pathutil "github.com/argoproj/argo-cd/v2/util/io/path"
+)
+
+const (
+	// starlarkMainFunction is the function of the entrypoint script returning the manifests
+	starlarkMainFunction = "main"
+	// starlarkMaxExecutionSteps bounds the computation of a script, so that a runaway script cannot block the repo-server
+	starlarkMaxExecutionSteps = 100000000
+)
+
+// StarlarkGenerator renders the manifests of applications with a Starlark script. The script runs in a sandbox: it can
+// neither load other modules nor access the file system or the network, its only inputs are the `values` and
+// `revision` built-ins.
+type StarlarkGenerator struct{}
+
+// NewStarlarkGenerator returns a generator running Starlark scripts
+func NewStarlarkGenerator() *StarlarkGenerator {
+	return &StarlarkGenerator{}
+}
+
+// ResolveEntrypoint returns the path of the entrypoint script of the source, which must be a file within the repository
+func (g *StarlarkGenerator) ResolveEntrypoint(appPath, repoRoot string, source *v1alpha1.ApplicationSourceStarlark) (string, error) {
+	if source == nil || source.Entrypoint == "" {
+		return "", errors.New("starlark entrypoint is not set")
+	}
+	resolved, err := pathutil.ResolveFileOrDirectoryPath(appPath, repoRoot, source.Entrypoint)
+	if err != nil {
+		return "", fmt.Errorf("error resolving starlark entrypoint %q: %w", source.Entrypoint, err)
+	}
+	info, err := os.Stat(string(resolved))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("starlark entrypoint %q does not exist", source.Entrypoint)
+		}
+		return "", fmt.Errorf("error reading starlark entrypoint %q: %w", source.Entrypoint, err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("starlark entrypoint %q is a directory", source.Entrypoint)
+	}
+	return string(resolved), nil
+}
+
+// Generate runs the main function of the entrypoint script of the source and returns the manifests it returns
+func (g *StarlarkGenerator) Generate(ctx context.Context, appPath, repoRoot, revision string, source *v1alpha1.ApplicationSourceStarlark) ([]*unstructured.Unstructured, error) {
+	entrypoint, err := g.ResolveEntrypoint(appPath, repoRoot, source)
+	if err != nil {
+		return nil, err
+	}
+	src, err := os.ReadFile(entrypoint)
+	if err != nil {
+		return nil, fmt.Errorf("error reading starlark entrypoint %q: %w", source.Entrypoint, err)
+	}
+	values, err := starlarkValues(source.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	thread := &starlark.Thread{
+		Name: source.Entrypoint,
+		Print: func(_ *starlark.Thread, msg string) {
+			log.WithField("entrypoint", source.Entrypoint).Debug(msg)
+		},
+		Load: func(_ *starlark.Thread, module string) (starlark.StringDict, error) {
+			return nil, fmt.Errorf("cannot load %q: loading modules is not allowed", module)
+		},
+	}
+	thread.SetMaxExecutionSteps(starlarkMaxExecutionSteps)
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			thread.Cancel(ctx.Err().Error())
+		case <-done:
+		}
+	}()
+
+	predeclared := starlark.StringDict{
+		"values":   values,
+		"revision": starlark.String(revision),
+	}
+	globals, err := starlark.ExecFile(thread, source.Entrypoint, src, predeclared)
+	if err != nil {
+		return nil, fmt.Errorf("error executing starlark entrypoint %q: %w", source.Entrypoint, starlarkError(err))
+	}
+	main, ok := globals[starlarkMainFunction].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("starlark entrypoint %q does not define a %s function", source.Entrypoint, starlarkMainFunction)
+	}
+	result, err := starlark.Call(thread, main, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error executing starlark entrypoint %q: %w", source.Entrypoint, starlarkError(err))
+	}
+	return starlarkManifests(result)
+}
+
+// starlarkError adds the backtrace of Starlark evaluation errors to their message
+func starlarkError(err error) error {
+	var evalErr *starlark.EvalError
+	if errors.As(err, &evalErr) {
+		return errors.New(evalErr.Backtrace())
+	}
+	return err
+}
+
+// starlarkValues converts the YAML or JSON values of a source to the frozen dict passed to the script
+func starlarkValues(values string) (starlark.Value, error) {
+	data := map[string]interface{}{}
+	if values != "" {
+		if err := yaml.Unmarshal([]byte(values), &data); err != nil {
+			return nil, fmt.Errorf("error parsing starlark values: %w", err)
+		}
+	}
+	value, err := toStarlark(data)
+	if err != nil {
+		return nil, fmt.Errorf("error converting starlark values: %w", err)
+	}
+	value.Freeze()
+	return value, nil
+}
+
+// starlarkManifests converts the list of dicts returned by a script to objects
+func starlarkManifests(result starlark.Value) ([]*unstructured.Unstructured, error) {
+	iterable, ok := result.(starlark.Iterable)
+	if !ok || result.Type() == "string" || result.Type() == "dict" {
+		return nil, fmt.Errorf("starlark %s function must return a list of manifests, got %s", starlarkMainFunction, result.Type())
+	}
+	var objs []*unstructured.Unstructured
+	iter := iterable.Iterate()
+	defer iter.Done()
+	var item starlark.Value
+	for i := 0; iter.Next(&item); i++ {
+		if item == starlark.None {
+			continue
+		}
+		if _, ok := item.(*starlark.Dict); !ok {
+			return nil, fmt.Errorf("starlark manifest %d must be a dict, got %s", i, item.Type())
+		}
+		obj, err := fromStarlark(item)
+		if err != nil {
+			return nil, fmt.Errorf("error converting starlark manifest %d: %w", i, err)
+		}
+		objs = append(objs, &unstructured.Unstructured{Object: obj.(map[string]interface{})})
+	}
+	return objs, nil
+}
+
+// toStarlark converts a value decoded from YAML or JSON to a Starlark value
+func toStarlark(value interface{}) (starlark.Value, error) {
+	switch v := value.(type) {
+	case nil:
+		return starlark.None, nil
+	case bool:
+		return starlark.Bool(v), nil
+	case string:
+		return starlark.String(v), nil
+	case int64:
+		return starlark.MakeInt64(v), nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return starlark.MakeInt64(int64(v)), nil
+		}
+		return starlark.Float(v), nil
+	case []interface{}:
+		items := make([]starlark.Value, len(v))
+		for i, item := range v {
+			converted, err := toStarlark(item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = converted
+		}
+		return starlark.NewList(items), nil
+	case map[string]interface{}:
+		dict := starlark.NewDict(len(v))
+		for key, item := range v {
+			converted, err := toStarlark(item)
+			if err != nil {
+				return nil, err
+			}
+			if err := dict.SetKey(starlark.String(key), converted); err != nil {
+				return nil, err
+			}
+		}
+		return dict, nil
+	}
+	return nil, fmt.Errorf("unsupported value of type %T", value)
+}
+
+// fromStarlark converts a Starlark value to a value which can be marshaled to JSON
+func fromStarlark(value starlark.Value) (interface{}, error) {
+	switch v := value.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.String:
+		return string(v), nil
+	case starlark.Int:
+		i, ok := v.Int64()
+		if !ok {
+			return nil, fmt.Errorf("integer %s is out of range", v.String())
+		}
+		return i, nil
+	case starlark.Float:
+		return float64(v), nil
+	case *starlark.List, starlark.Tuple:
+		var items []interface{}
+		iter := v.(starlark.Iterable).Iterate()
+		defer iter.Done()
+		var item starlark.Value
+		for iter.Next(&item) {
+			converted, err := fromStarlark(item)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, converted)
+		}
+		if items == nil {
+			items = []interface{}{}
+		}
+		return items, nil
+	case *starlark.Dict:
+		obj := make(map[string]interface{}, v.Len())
+		for _, entry := range v.Items() {
+			key, ok := entry[0].(starlark.String)
+			if !ok {
+				return nil, fmt.Errorf("dict keys must be strings, got %s", entry[0].Type())
+			}
+			converted, err := fromStarlark(entry[1])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", string(key), err)
+			}
+			obj[string(key)] = converted
+		}
+		return obj, nil
+	}
+	return nil, fmt.Errorf("unsupported value of type %s", value.Type())
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func starlarkTestPaths(t *testing.T) (string, string) {
+	t.Helper()
+	repoRoot, err := filepath.Abs("./testdata/starlark")
+	require.NoError(t, err)
+	return filepath.Join(repoRoot, "app"), repoRoot
+}
+
+func TestStarlarkGenerator_Generate(t *testing.T) {
+	appPath, repoRoot := starlarkTestPaths(t)
+
+	objs, err := NewStarlarkGenerator().Generate(context.Background(), appPath, repoRoot, "abc123", &argoappv1.ApplicationSourceStarlark{
+		Entrypoint: "main.star",
+		Values:     "name: my-config\nreplicas: 3\n",
+	})
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Equal(t, "ConfigMap", objs[0].GetKind())
+	assert.Equal(t, "my-config", objs[0].GetName())
+	data, _, err := unstructured.NestedStringMap(objs[0].Object, "data")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"revision": "abc123", "replicas": "3"}, data)
+}
+
+func TestStarlarkGenerator_Generate_JSONValues(t *testing.T) {
+	appPath, repoRoot := starlarkTestPaths(t)
+
+	objs, err := NewStarlarkGenerator().Generate(context.Background(), appPath, repoRoot, "abc123", &argoappv1.ApplicationSourceStarlark{
+		Entrypoint: "main.star",
+		Values:     `{"name": "json-config", "replicas": 1}`,
+	})
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Equal(t, "json-config", objs[0].GetName())
+}
+
+func TestStarlarkGenerator_Generate_Errors(t *testing.T) {
+	appPath, repoRoot := starlarkTestPaths(t)
+
+	testCases := []struct {
+		name        string
+		source      *argoappv1.ApplicationSourceStarlark
+		expectedErr string
+	}{
+		{name: "no source", source: nil, expectedErr: "starlark entrypoint is not set"},
+		{name: "missing entrypoint", source: &argoappv1.ApplicationSourceStarlark{Entrypoint: "missing.star"}, expectedErr: `starlark entrypoint "missing.star" does not exist`},
+		{name: "out of bounds entrypoint", source: &argoappv1.ApplicationSourceStarlark{Entrypoint: "../../../out-of-bounds.star"}, expectedErr: "error resolving starlark entrypoint"},
+		{name: "directory entrypoint", source: &argoappv1.ApplicationSourceStarlark{Entrypoint: "."}, expectedErr: "is a directory"},
+		{name: "invalid values", source: &argoappv1.ApplicationSourceStarlark{Entrypoint: "main.star", Values: "- not a dict"}, expectedErr: "error parsing starlark values"},
+		{name: "missing values", source: &argoappv1.ApplicationSourceStarlark{Entrypoint: "main.star"}, expectedErr: `key "name" not in dict`},
+		{name: "no main function", source: &argoappv1.ApplicationSourceStarlark{Entrypoint: "no-main.star"}, expectedErr: "does not define a main function"},
+		{name: "not a list", source: &argoappv1.ApplicationSourceStarlark{Entrypoint: "not-a-list.star"}, expectedErr: "must return a list of manifests, got dict"},
+		{name: "load is not allowed", source: &argoappv1.ApplicationSourceStarlark{Entrypoint: "load.star"}, expectedErr: "loading modules is not allowed"},
+		{name: "fail", source: &argoappv1.ApplicationSourceStarlark{Entrypoint: "fail.star"}, expectedErr: "replicas must be positive"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewStarlarkGenerator().Generate(context.Background(), appPath, repoRoot, "abc123", tc.source)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.expectedErr)
+		})
+	}
+}
+
+func TestStarlarkGenerator_Generate_Sandbox(t *testing.T) {
+	appPath, repoRoot := starlarkTestPaths(t)
+
+	// scripts have no access to the file system
+	_, err := NewStarlarkGenerator().Generate(context.Background(), appPath, repoRoot, "abc123", &argoappv1.ApplicationSourceStarlark{Entrypoint: "open.star"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "undefined: open")
+
+	// a runaway script is stopped when the request is canceled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = NewStarlarkGenerator().Generate(ctx, appPath, repoRoot, "abc123", &argoappv1.ApplicationSourceStarlark{Entrypoint: "loop.star"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "context canceled")
+}
+
+func TestStarlarkManifests(t *testing.T) {
+	values, err := starlarkValues("nested:\n  list: [1, 2.5, true, null]\n")
+	require.NoError(t, err)
+	converted, err := fromStarlark(values)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"nested": map[string]interface{}{"list": []interface{}{int64(1), 2.5, true, nil}},
+	}, converted)
+}
+
+func TestStarlarkGenerator_Examples(t *testing.T) {
+	repoRoot, err := filepath.Abs("../../examples/starlark")
+	require.NoError(t, err)
+
+	objs, err := NewStarlarkGenerator().Generate(context.Background(), repoRoot, repoRoot, "abc123", &argoappv1.ApplicationSourceStarlark{
+		Entrypoint: "main.star",
+		Values:     "name: guestbook\nimage: gcr.io/heptio-images/ks-guestbook-demo:0.2\nreplicas: 2\n",
+	})
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	assert.Equal(t, "Deployment", objs[0].GetKind())
+	replicas, _, err := unstructured.NestedInt64(objs[0].Object, "spec", "replicas")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), replicas)
+	assert.Equal(t, "abc123", objs[0].GetAnnotations()["example.argoproj.io/revision"])
+	assert.Equal(t, "Service", objs[1].GetKind())
+
+	objs, err = NewStarlarkGenerator().Generate(context.Background(), repoRoot, repoRoot, "abc123", &argoappv1.ApplicationSourceStarlark{
+		Entrypoint: "environments.star",
+		Values:     "environments:\n- name: staging\n  logLevel: debug\n- name: prod\n  enabled: false\n",
+	})
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Equal(t, "settings-staging", objs[0].GetName())
+}
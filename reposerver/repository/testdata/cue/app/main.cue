@@ -0,0 +1,43 @@
+package guestbook
+
+environment: string | *"dev" @tag(environment)
+replicas:    int | *1        @tag(replicas,type=int)
+
+_labels: {
+	app:                            "guestbook"
+	"app.kubernetes.io/managed-by": "cue"
+}
+
+deployments: guestbook: {
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	metadata: {
+		name:   "guestbook-\(environment)"
+		labels: _labels
+	}
+	spec: {
+		"replicas": replicas
+		selector: matchLabels: _labels
+		template: {
+			metadata: labels: _labels
+			spec: containers: [{
+				name:  "guestbook"
+				image: "quay.io/argoprojlabs/argocd-e2e-container:0.2"
+				ports: [{containerPort: 80}]
+			}]
+		}
+	}
+}
+
+services: guestbook: {
+	apiVersion: "v1"
+	kind:       "Service"
+	metadata: {
+		name:   "guestbook-\(environment)"
+		labels: _labels
+	}
+	spec: {
+		selector: _labels
+		ports: [{port: 80, targetPort: 80}]
+	}
+}
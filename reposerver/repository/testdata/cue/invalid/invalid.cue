@@ -0,0 +1,4 @@
+package invalid
+
+replicas: int
+replicas: "one"
@@ -0,0 +1,40 @@
+package main
+
+import (
+	appsv1 "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/apps/v1"
+	corev1 "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/core/v1"
+	metav1 "github.com/pulumi/pulumi-kubernetes/sdk/v4/go/kubernetes/meta/v1"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+func main() {
+	pulumi.Run(func(ctx *pulumi.Context) error {
+		replicas := config.New(ctx, "").GetInt("replicas")
+		if replicas == 0 {
+			replicas = 1
+		}
+		labels := pulumi.StringMap{"app": pulumi.String("guestbook")}
+		_, err := appsv1.NewDeployment(ctx, "guestbook", &appsv1.DeploymentArgs{
+			Metadata: &metav1.ObjectMetaArgs{
+				Name: pulumi.String("guestbook"),
+			},
+			Spec: &appsv1.DeploymentSpecArgs{
+				Replicas: pulumi.Int(replicas),
+				Selector: &metav1.LabelSelectorArgs{MatchLabels: labels},
+				Template: &corev1.PodTemplateSpecArgs{
+					Metadata: &metav1.ObjectMetaArgs{Labels: labels},
+					Spec: &corev1.PodSpecArgs{
+						Containers: corev1.ContainerArray{
+							&corev1.ContainerArgs{
+								Name:  pulumi.String("guestbook"),
+								Image: pulumi.String("gcr.io/heptio-images/ks-guestbook-demo:0.2"),
+							},
+						},
+					},
+				},
+			},
+		})
+		return err
+	})
+}
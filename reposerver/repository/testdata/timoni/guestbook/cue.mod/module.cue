@@ -0,0 +1,2 @@
+module: "timoni.sh/guestbook"
+language: version: "v0.9.0"
@@ -0,0 +1,22 @@
+package templates
+
+#Config: {
+	kubeVersion!:   string
+	moduleVersion!: string
+	metadata: {
+		name:      string
+		namespace: string
+		labels: "app.kubernetes.io/name": name
+	}
+	image!:   string
+	replicas: *1 | int & >=0
+	port:     *80 | int & >0 & <=65535
+}
+
+#Instance: {
+	config: #Config
+	objects: {
+		deploy: #Deployment & {#config: config}
+		svc:    #Service & {#config: config}
+	}
+}
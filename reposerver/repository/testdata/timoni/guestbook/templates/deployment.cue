@@ -0,0 +1,24 @@
+package templates
+
+#Deployment: {
+	#config:    #Config
+	apiVersion: "apps/v1"
+	kind:       "Deployment"
+	metadata: {
+		name:      #config.metadata.name
+		namespace: #config.metadata.namespace
+		labels:    #config.metadata.labels
+	}
+	spec: {
+		replicas: #config.replicas
+		selector: matchLabels: #config.metadata.labels
+		template: {
+			metadata: labels: #config.metadata.labels
+			spec: containers: [{
+				name:  "guestbook"
+				image: #config.image
+				ports: [{name: "http", containerPort: 80}]
+			}]
+		}
+	}
+}
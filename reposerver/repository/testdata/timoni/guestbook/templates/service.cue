@@ -0,0 +1,16 @@
+package templates
+
+#Service: {
+	#config:    #Config
+	apiVersion: "v1"
+	kind:       "Service"
+	metadata: {
+		name:      #config.metadata.name
+		namespace: #config.metadata.namespace
+		labels:    #config.metadata.labels
+	}
+	spec: {
+		selector: #config.metadata.labels
+		ports: [{name: "http", port: #config.port, targetPort: "http", protocol: "TCP"}]
+	}
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	templates "timoni.sh/guestbook/templates"
+)
+
+// values are validated against the schema of the configuration of the instance
+values: templates.#Config
+
+timoni: {
+	apiVersion: "v1alpha1"
+
+	instance: templates.#Instance & {
+		config: values
+		// injected by timoni when the instance is built
+		config: {
+			metadata: {
+				name:      string @tag(name)
+				namespace: string @tag(namespace)
+			}
+			moduleVersion: string @tag(mv, var=moduleVersion)
+			kubeVersion:   string @tag(kv, var=kubeVersion)
+		}
+	}
+
+	apply: app: [for obj in instance.objects {obj}]
+}
@@ -0,0 +1,5 @@
+package main
+
+values: {
+	image: "quay.io/argoprojlabs/argocd-e2e-container:0.2"
+}
@@ -0,0 +1,334 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/cache"
+	executil "github.com/argoproj/argo-cd/v2/util/exec"
+	"github.com/argoproj/argo-cd/v2/util/helm"
+	pathutil "github.com/argoproj/argo-cd/v2/util/io/path"
+)
+
+const (
+	defaultTimoniBinary = "timoni"
+	// timoniDefaultInstanceName is the name of the instance of the module when the application has no name
+	timoniDefaultInstanceName = "argocd"
+	// timoniModuleFile is the file every Timoni module holds at its root
+	timoniModuleFile = "timoni.cue"
+)
+
+var (
+	// timoniVersionRegex matches the client version in the output of `timoni version`, e.g. "client: 0.22.0"
+	timoniVersionRegex = regexp.MustCompile(`client:\s*(v?[0-9]+\.[0-9]+\.[0-9]+\S*)`)
+	// timoniEnvPassthrough are the variables of the environment of the repo server which are passed to timoni.
+	// Credentials of the repo server are never passed.
+	timoniEnvPassthrough = []string{"PATH", "SSL_CERT_FILE", "SSL_CERT_DIR", "HTTPS_PROXY", "HTTP_PROXY", "NO_PROXY"}
+)
+
+// TimoniGenerator renders the manifests of applications from an instance of a Timoni module with the timoni command
+// line tool
+type TimoniGenerator struct {
+	// binaryPath is the path of the timoni binary, the binary is looked up in the PATH when empty
+	binaryPath string
+	// cache stores the manifests built from a module by its hash, the modules are always built when nil
+	cache *cache.Cache
+}
+
+// TimoniInstance identifies the instance of a module built for an application
+type TimoniInstance struct {
+	// Name is the name of the instance, the name of the application
+	Name string
+	// Namespace is the namespace of the instance, the destination namespace of the application
+	Namespace string
+}
+
+// timoniModule is the module of a source, either a directory of the repository or a module published to a registry
+type timoniModule struct {
+	// dir is the directory of a module of the repository
+	dir string
+	// url is the oci:// URL of a module published to a registry, without its version
+	url string
+	// version is the version of a module published to a registry, the latest version is used when empty
+	version string
+}
+
+func (m *timoniModule) isRemote() bool {
+	return m.url != ""
+}
+
+// registry returns the host of the registry of a module published to a registry
+func (m *timoniModule) registry() string {
+	return strings.SplitN(strings.TrimPrefix(m.url, ociPrefix), "/", 2)[0]
+}
+
+// NewTimoniGenerator returns a generator running the timoni binary at the given path, or the one found in the PATH if
+// the path is empty. The built manifests are cached in the given cache if it is not nil.
+func NewTimoniGenerator(binaryPath string, cache *cache.Cache) *TimoniGenerator {
+	return &TimoniGenerator{binaryPath: binaryPath, cache: cache}
+}
+
+func (g *TimoniGenerator) getBinaryPath() string {
+	if g.binaryPath != "" {
+		return g.binaryPath
+	}
+	return defaultTimoniBinary
+}
+
+// resolveModule returns the module of the source and checks that its values are valid. Modules of the repository must
+// hold a timoni.cue file, modules published to a registry are referenced by an oci:// URL, optionally followed by the
+// :version of the module.
+func (g *TimoniGenerator) resolveModule(appPath, repoRoot string, source *v1alpha1.ApplicationSourceTimoni) (*timoniModule, error) {
+	module := "."
+	if source != nil && source.Module != "" {
+		module = source.Module
+	}
+	if source != nil && source.Values != "" {
+		var values map[string]interface{}
+		if err := yaml.Unmarshal([]byte(source.Values), &values); err != nil {
+			return nil, fmt.Errorf("failed to parse timoni values: %w", err)
+		}
+	}
+
+	if strings.HasPrefix(module, ociPrefix) {
+		ref := strings.TrimPrefix(module, ociPrefix)
+		i := strings.LastIndex(ref, "/")
+		if i <= 0 || i == len(ref)-1 {
+			return nil, fmt.Errorf("invalid timoni module %q: expected oci://<registry>/<repository>[:<version>]", module)
+		}
+		// the port of the registry is the only other colon of the reference, and is never in its last segment
+		version := ""
+		if j := strings.Index(ref[i:], ":"); j >= 0 {
+			version = ref[i+j+1:]
+			ref = ref[:i+j]
+			if version == "" {
+				return nil, fmt.Errorf("invalid timoni module %q: the version is empty", module)
+			}
+		}
+		return &timoniModule{url: ociPrefix + ref, version: version}, nil
+	}
+
+	resolved, err := pathutil.ResolveFileOrDirectoryPath(appPath, repoRoot, module)
+	if err != nil {
+		return nil, fmt.Errorf("error resolving timoni module %q: %w", module, err)
+	}
+	if _, err := os.Stat(filepath.Join(string(resolved), timoniModuleFile)); err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("timoni module %q has no %s file", module, timoniModuleFile)
+		}
+		return nil, fmt.Errorf("error reading timoni module %q: %w", module, err)
+	}
+	return &timoniModule{dir: string(resolved)}, nil
+}
+
+// Validate checks that the source references a Timoni module and that its values are valid. The modules published to
+// a registry are not pulled.
+func (g *TimoniGenerator) Validate(appPath, repoRoot string, source *v1alpha1.ApplicationSourceTimoni) error {
+	_, err := g.resolveModule(appPath, repoRoot, source)
+	return err
+}
+
+// Generate builds the instance of the module of the source and returns the manifests of its resources, together with
+// the executed command. Modules published to a registry are pulled with the given credentials of the registry. The
+// manifests are returned from the cache if the module and the values of the source did not change, unless noCache is
+// set.
+func (g *TimoniGenerator) Generate(appPath, repoRoot string, instance TimoniInstance, source *v1alpha1.ApplicationSourceTimoni, creds helm.Creds, noCache bool) ([]*unstructured.Unstructured, string, error) {
+	module, err := g.resolveModule(appPath, repoRoot, source)
+	if err != nil {
+		return nil, "", err
+	}
+	if instance.Name == "" {
+		instance.Name = timoniDefaultInstanceName
+	}
+	if instance.Namespace == "" {
+		instance.Namespace = "default"
+	}
+	args := timoniBuildArgs(module, instance, source.Values != "")
+	// the directory of the module is logged relative to the repository
+	logArgs := append([]string{g.getBinaryPath()}, args...)
+	for i := range logArgs {
+		if !module.isRemote() && logArgs[i] == module.dir {
+			logArgs[i] = strings.Replace(module.dir, repoRoot, ".", 1)
+		}
+	}
+	command := strings.Join(logArgs, " ")
+	hash, err := hashTimoniModule(module, instance, source.Values)
+	if err != nil {
+		return nil, "", err
+	}
+	if g.cache != nil && !noCache {
+		data, err := g.cache.GetTimoniBuild(hash)
+		if err == nil {
+			objs, err := kube.SplitYAML(data)
+			if err == nil {
+				return objs, command, nil
+			}
+			log.Warnf("Failed to parse cached timoni build of %s: %v", hash, err)
+		} else if !errors.Is(err, cache.ErrCacheMiss) {
+			log.Warnf("Failed to get timoni build of %s from cache: %v", hash, err)
+		}
+	}
+
+	out, err := g.build(module, args, source.Values, creds)
+	if err != nil {
+		return nil, "", err
+	}
+	objs, err := kube.SplitYAML([]byte(out))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse timoni build: %w", err)
+	}
+	if g.cache != nil {
+		if err := g.cache.SetTimoniBuild(hash, []byte(out)); err != nil {
+			log.Warnf("Failed to cache timoni build of %s: %v", hash, err)
+		}
+	}
+	return objs, command, nil
+}
+
+// timoniBuildArgs returns the arguments of the timoni command building the instance of the module. The values are read
+// from a values.yaml file of the working directory.
+func timoniBuildArgs(module *timoniModule, instance TimoniInstance, hasValues bool) []string {
+	args := []string{"build", instance.Name}
+	if module.isRemote() {
+		args = append(args, module.url)
+		if module.version != "" {
+			args = append(args, "--version", module.version)
+		}
+	} else {
+		args = append(args, module.dir)
+	}
+	args = append(args, "--namespace", instance.Namespace)
+	if hasValues {
+		args = append(args, "--values", "values.yaml")
+	}
+	return args
+}
+
+// build runs timoni in a temporary directory holding the values of the instance, a home directory and the Docker
+// configuration with the credentials of the registry of the module. It runs with a minimal environment which does not
+// give access to the credentials of the repo server or to the cluster it runs in, and returns the built manifests.
+func (g *TimoniGenerator) build(module *timoniModule, args []string, values string, creds helm.Creds) (string, error) {
+	tempDir, err := os.MkdirTemp("", "timoni")
+	if err != nil {
+		return "", fmt.Errorf("error creating timoni sandbox: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tempDir); err != nil {
+			log.Warnf("Failed to remove timoni sandbox %s: %v", tempDir, err)
+		}
+	}()
+	homeDir := filepath.Join(tempDir, "home")
+	dockerConfigDir := filepath.Join(tempDir, "docker")
+	for _, dir := range []string{homeDir, dockerConfigDir} {
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return "", fmt.Errorf("error creating timoni sandbox: %w", err)
+		}
+	}
+	if values != "" {
+		if err := os.WriteFile(filepath.Join(tempDir, "values.yaml"), []byte(values), 0o600); err != nil {
+			return "", fmt.Errorf("error writing timoni values: %w", err)
+		}
+	}
+	if module.isRemote() && (creds.Username != "" || creds.Password != "") {
+		if err := writeTimoniDockerConfig(dockerConfigDir, module.registry(), creds); err != nil {
+			return "", fmt.Errorf("error writing timoni registry credentials: %w", err)
+		}
+	}
+
+	cmd := exec.Command(g.getBinaryPath(), args...)
+	cmd.Dir = tempDir
+	cmd.Env = []string{
+		"HOME=" + homeDir,
+		"DOCKER_CONFIG=" + dockerConfigDir,
+		// the cache of the modules pulled from registries is removed with the sandbox
+		"XDG_CACHE_HOME=" + filepath.Join(homeDir, ".cache"),
+	}
+	for _, name := range timoniEnvPassthrough {
+		if value, ok := os.LookupEnv(name); ok {
+			cmd.Env = append(cmd.Env, name+"="+value)
+		}
+	}
+	out, err := executil.Run(cmd)
+	if err != nil {
+		return "", fmt.Errorf("error building timoni module: %w", err)
+	}
+	return out, nil
+}
+
+// writeTimoniDockerConfig writes the Docker configuration timoni authenticates to the registry with
+func writeTimoniDockerConfig(dir, registry string, creds helm.Creds) error {
+	config := map[string]interface{}{
+		"auths": map[string]interface{}{
+			registry: map[string]string{
+				"auth": base64.StdEncoding.EncodeToString([]byte(creds.Username + ":" + creds.Password)),
+			},
+		},
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "config.json"), data, 0o600)
+}
+
+// hashTimoniModule returns the SHA256 digest of the module, of the instance and of its values, which identifies the
+// manifests built from the module. Modules of the repository are identified by their files, modules published to a
+// registry by their URL and version.
+func hashTimoniModule(module *timoniModule, instance TimoniInstance, values string) (string, error) {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "name:%s\nnamespace:%s\nvalues:%q\n", instance.Name, instance.Namespace, values)
+	if module.isRemote() {
+		_, _ = fmt.Fprintf(h, "url:%s\nversion:%s\n", module.url, module.version)
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+	// files are walked in lexical order
+	err := walkSourceFiles(module.dir, func(relPath string, info fs.FileInfo, data []byte) error {
+		fileHash := sha256.Sum256(data)
+		_, _ = fmt.Fprintf(h, "file:%q:%s\n", filepath.ToSlash(relPath), hex.EncodeToString(fileHash[:]))
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("error hashing timoni module: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// timoniRegistryCreds returns the credentials of the registry of a module published to a registry: the credentials of
+// the first OCI Helm repository whose URL is a prefix of the URL of the module, or else of the matching repository
+// credential template
+func timoniRegistryCreds(source *v1alpha1.ApplicationSourceTimoni, repos []*v1alpha1.Repository, repoCreds []*v1alpha1.RepoCreds) helm.Creds {
+	if source == nil || !strings.HasPrefix(source.Module, ociPrefix) {
+		return helm.Creds{}
+	}
+	return helmOCICreds(source.Module, repos, repoCreds)
+}
+
+// Version returns the version of the timoni binary
+func (g *TimoniGenerator) Version() (*semver.Version, error) {
+	out, err := executil.Run(exec.Command(g.getBinaryPath(), "version"))
+	if err != nil {
+		return nil, fmt.Errorf("could not get timoni version: %w", err)
+	}
+	matches := timoniVersionRegex.FindStringSubmatch(out)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("expected string that includes timoni version but got: '%s'", strings.TrimSpace(out))
+	}
+	return semver.NewVersion(matches[1])
+}
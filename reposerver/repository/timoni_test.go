@@ -0,0 +1,222 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/cache"
+	cacheutil "github.com/argoproj/argo-cd/v2/util/cache"
+	"github.com/argoproj/argo-cd/v2/util/helm"
+)
+
+func fakeTimoniGenerator(t *testing.T, buildCache *cache.Cache) *TimoniGenerator {
+	t.Helper()
+	binaryPath, err := filepath.Abs("./testdata/timoni/fake-timoni")
+	require.NoError(t, err)
+	return NewTimoniGenerator(binaryPath, buildCache)
+}
+
+func TestTimoniGenerator_Generate(t *testing.T) {
+	t.Setenv("FAKE_TIMONI_SECRET", "repo-server-credentials")
+	repoRoot, err := filepath.Abs("./testdata/timoni")
+	require.NoError(t, err)
+	appPath := filepath.Join(repoRoot, "guestbook")
+
+	objs, command, err := fakeTimoniGenerator(t, nil).Generate(appPath, repoRoot, TimoniInstance{Name: "guestbook", Namespace: "apps"}, &argoappv1.ApplicationSourceTimoni{
+		Values: "replicas: 3\n",
+	}, helm.Creds{}, false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repoRoot, "fake-timoni")+" build guestbook ./guestbook --namespace apps --values values.yaml", command)
+	require.Len(t, objs, 2)
+	assert.Equal(t, "Deployment", objs[0].GetKind())
+	assert.Equal(t, "guestbook", objs[0].GetName())
+	assert.Equal(t, "apps", objs[0].GetNamespace())
+	replicas, _, err := unstructured.NestedInt64(objs[0].Object, "spec", "replicas")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), replicas)
+	assert.Equal(t, "Service", objs[1].GetKind())
+	assert.Equal(t, "guestbook", objs[1].GetName())
+
+	annotations := objs[0].GetAnnotations()
+	assert.Equal(t, "build guestbook "+appPath+" --namespace apps --values values.yaml", annotations["args"])
+	// timoni runs in a sandbox without the environment of the repo server
+	assert.Equal(t, filepath.Join(annotations["dir"], "home"), annotations["home"])
+	assert.Empty(t, annotations["secret"])
+	assert.Empty(t, annotations["auth"])
+	// the sandbox is removed
+	assert.NoDirExists(t, annotations["dir"])
+}
+
+func TestTimoniGenerator_Generate_Defaults(t *testing.T) {
+	repoRoot, err := filepath.Abs("./testdata/timoni")
+	require.NoError(t, err)
+
+	objs, command, err := fakeTimoniGenerator(t, nil).Generate(filepath.Join(repoRoot, "guestbook"), repoRoot, TimoniInstance{}, &argoappv1.ApplicationSourceTimoni{}, helm.Creds{}, false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repoRoot, "fake-timoni")+" build argocd ./guestbook --namespace default", command)
+	require.Len(t, objs, 2)
+	assert.Equal(t, "argocd", objs[0].GetName())
+	assert.Equal(t, "default", objs[0].GetNamespace())
+}
+
+func TestTimoniGenerator_Generate_Registry(t *testing.T) {
+	repoRoot, err := filepath.Abs("./testdata/timoni")
+	require.NoError(t, err)
+	source := &argoappv1.ApplicationSourceTimoni{Module: "oci://registry.example.com:5000/modules/guestbook:1.2.0"}
+
+	objs, command, err := fakeTimoniGenerator(t, nil).Generate(repoRoot, repoRoot, TimoniInstance{Name: "guestbook", Namespace: "apps"}, source, helm.Creds{Username: "user", Password: "pass"}, false)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(repoRoot, "fake-timoni")+" build guestbook oci://registry.example.com:5000/modules/guestbook --version 1.2.0 --namespace apps", command)
+	require.Len(t, objs, 2)
+
+	// the credentials of the registry are passed in a Docker configuration rather than as arguments
+	var config struct {
+		Auths map[string]struct {
+			Auth string `json:"auth"`
+		} `json:"auths"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(objs[0].GetAnnotations()["auth"]), &config))
+	require.Contains(t, config.Auths, "registry.example.com:5000")
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("user:pass")), config.Auths["registry.example.com:5000"].Auth)
+	assert.NotContains(t, objs[0].GetAnnotations()["args"], "pass")
+}
+
+func TestTimoniGenerator_Generate_Cache(t *testing.T) {
+	repoRoot, err := filepath.Abs("./testdata/timoni")
+	require.NoError(t, err)
+	buildCache := cache.NewCache(cacheutil.NewCache(cacheutil.NewInMemoryCache(time.Minute)), time.Minute, time.Minute, 10*time.Second)
+	instance := TimoniInstance{Name: "guestbook", Namespace: "apps"}
+	source := &argoappv1.ApplicationSourceTimoni{Values: "replicas: 3\n"}
+
+	objs, _, err := fakeTimoniGenerator(t, buildCache).Generate(filepath.Join(repoRoot, "guestbook"), repoRoot, instance, source, helm.Creds{}, false)
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+
+	// cached manifests are returned without running timoni
+	broken := NewTimoniGenerator(filepath.Join(repoRoot, "missing-timoni"), buildCache)
+	cached, _, err := broken.Generate(filepath.Join(repoRoot, "guestbook"), repoRoot, instance, source, helm.Creds{}, false)
+	require.NoError(t, err)
+	assert.Equal(t, objs, cached)
+
+	// the module is built again if its values change, or if the cache is bypassed
+	_, _, err = broken.Generate(filepath.Join(repoRoot, "guestbook"), repoRoot, instance, &argoappv1.ApplicationSourceTimoni{Values: "replicas: 4\n"}, helm.Creds{}, false)
+	require.ErrorContains(t, err, "error building timoni module")
+	_, _, err = broken.Generate(filepath.Join(repoRoot, "guestbook"), repoRoot, instance, source, helm.Creds{}, true)
+	require.ErrorContains(t, err, "error building timoni module")
+}
+
+func TestTimoniGenerator_Validate(t *testing.T) {
+	repoRoot, err := filepath.Abs("./testdata/timoni")
+	require.NoError(t, err)
+	g := fakeTimoniGenerator(t, nil)
+
+	require.NoError(t, g.Validate(filepath.Join(repoRoot, "guestbook"), repoRoot, &argoappv1.ApplicationSourceTimoni{}))
+	require.NoError(t, g.Validate(repoRoot, repoRoot, &argoappv1.ApplicationSourceTimoni{Module: "guestbook", Values: "replicas: 2"}))
+	require.NoError(t, g.Validate(repoRoot, repoRoot, &argoappv1.ApplicationSourceTimoni{Module: "oci://ghcr.io/stefanprodan/modules/podinfo"}))
+
+	err = g.Validate(filepath.Join(repoRoot, "not-a-module"), repoRoot, &argoappv1.ApplicationSourceTimoni{})
+	require.ErrorContains(t, err, `timoni module "." has no timoni.cue file`)
+	err = g.Validate(repoRoot, repoRoot, &argoappv1.ApplicationSourceTimoni{Module: "../../out-of-bounds"})
+	require.ErrorContains(t, err, "error resolving timoni module")
+	err = g.Validate(repoRoot, repoRoot, &argoappv1.ApplicationSourceTimoni{Module: "guestbook", Values: "- replicas"})
+	require.ErrorContains(t, err, "failed to parse timoni values")
+	err = g.Validate(repoRoot, repoRoot, &argoappv1.ApplicationSourceTimoni{Module: "oci://ghcr.io"})
+	require.ErrorContains(t, err, "invalid timoni module")
+	err = g.Validate(repoRoot, repoRoot, &argoappv1.ApplicationSourceTimoni{Module: "oci://ghcr.io/modules/podinfo:"})
+	require.ErrorContains(t, err, "the version is empty")
+}
+
+func TestHashTimoniModule(t *testing.T) {
+	moduleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "timoni.cue"), []byte("package main"), 0o600))
+	module := &timoniModule{dir: moduleDir}
+	instance := TimoniInstance{Name: "guestbook", Namespace: "apps"}
+
+	hash, err := hashTimoniModule(module, instance, "replicas: 1")
+	require.NoError(t, err)
+	same, err := hashTimoniModule(module, instance, "replicas: 1")
+	require.NoError(t, err)
+	assert.Equal(t, hash, same)
+
+	otherValues, err := hashTimoniModule(module, instance, "replicas: 2")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, otherValues)
+	otherNamespace, err := hashTimoniModule(module, TimoniInstance{Name: "guestbook", Namespace: "default"}, "replicas: 1")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, otherNamespace)
+
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "timoni.cue"), []byte("package main\n"), 0o600))
+	otherModule, err := hashTimoniModule(module, instance, "replicas: 1")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, otherModule)
+
+	remote, err := hashTimoniModule(&timoniModule{url: "oci://ghcr.io/modules/guestbook", version: "1.0.0"}, instance, "replicas: 1")
+	require.NoError(t, err)
+	otherVersion, err := hashTimoniModule(&timoniModule{url: "oci://ghcr.io/modules/guestbook", version: "1.0.1"}, instance, "replicas: 1")
+	require.NoError(t, err)
+	assert.NotEqual(t, remote, otherVersion)
+}
+
+func TestTimoniRegistryCreds(t *testing.T) {
+	repos := []*argoappv1.Repository{
+		{Repo: "ghcr.io/other", Username: "other", Password: "other", EnableOCI: true},
+		{Repo: "ghcr.io/modules", Username: "repo", Password: "repo-pass"},
+		{Repo: "ghcr.io/modules", Username: "oci", Password: "oci-pass", EnableOCI: true},
+	}
+	repoCreds := []*argoappv1.RepoCreds{{URL: "registry.example.com", Username: "template", Password: "template-pass"}}
+
+	creds := timoniRegistryCreds(&argoappv1.ApplicationSourceTimoni{Module: "oci://ghcr.io/modules/guestbook"}, repos, repoCreds)
+	assert.Equal(t, "oci", creds.Username)
+	assert.Equal(t, "oci-pass", creds.Password)
+	creds = timoniRegistryCreds(&argoappv1.ApplicationSourceTimoni{Module: "oci://registry.example.com/guestbook:1.0.0"}, repos, repoCreds)
+	assert.Equal(t, helm.Creds{Username: "template", Password: "template-pass"}, creds)
+	assert.Empty(t, timoniRegistryCreds(&argoappv1.ApplicationSourceTimoni{Module: "oci://docker.io/guestbook"}, repos, repoCreds))
+	assert.Empty(t, timoniRegistryCreds(&argoappv1.ApplicationSourceTimoni{Module: "ghcr.io/modules/guestbook"}, repos, repoCreds))
+}
+
+func TestTimoniGenerator_Version(t *testing.T) {
+	version, err := fakeTimoniGenerator(t, nil).Version()
+	require.NoError(t, err)
+	assert.Equal(t, "0.22.0", version.String())
+}
+
+// TestTimoniGenerator_Generate_Integration builds the module of testdata/timoni/guestbook with the timoni binary of
+// the PATH
+func TestTimoniGenerator_Generate_Integration(t *testing.T) {
+	if _, err := exec.LookPath("timoni"); err != nil {
+		t.Skip("timoni is not installed")
+	}
+	repoRoot, err := filepath.Abs("./testdata/timoni")
+	require.NoError(t, err)
+
+	objs, _, err := NewTimoniGenerator("", nil).Generate(filepath.Join(repoRoot, "guestbook"), repoRoot, TimoniInstance{Name: "guestbook", Namespace: "apps"}, &argoappv1.ApplicationSourceTimoni{
+		Values: "replicas: 2\nport: 8080\n",
+	}, helm.Creds{}, false)
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	kinds := map[string]*unstructured.Unstructured{}
+	for _, obj := range objs {
+		assert.Equal(t, "guestbook", obj.GetName())
+		assert.Equal(t, "apps", obj.GetNamespace())
+		kinds[obj.GetKind()] = obj
+	}
+	require.Contains(t, kinds, "Deployment")
+	require.Contains(t, kinds, "Service")
+	replicas, _, err := unstructured.NestedInt64(kinds["Deployment"].Object, "spec", "replicas")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), replicas)
+	ports, _, err := unstructured.NestedSlice(kinds["Service"].Object, "spec", "ports")
+	require.NoError(t, err)
+	require.Len(t, ports, 1)
+	assert.EqualValues(t, 8080, ports[0].(map[string]interface{})["port"])
+}
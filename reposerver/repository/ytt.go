@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	executil "github.com/argoproj/argo-cd/v2/util/exec"
+	pathutil "github.com/argoproj/argo-cd/v2/util/io/path"
+)
+
+const defaultYttBinary = "ytt"
+
+// yttVersionRegex matches the output of `ytt version`, e.g. "ytt version 0.49.0"
+var yttVersionRegex = regexp.MustCompile(`ytt version (v?[0-9]+\.[0-9]+\.[0-9]+\S*)`)
+
+// YttGenerator renders the manifests of applications with the Carvel ytt templating engine
+type YttGenerator struct {
+	// binaryPath is the path of the ytt binary, the binary is looked up in the PATH when empty
+	binaryPath string
+}
+
+// NewYttGenerator returns a generator running the ytt binary at the given path, or the one found in the PATH if the
+// path is empty
+func NewYttGenerator(binaryPath string) *YttGenerator {
+	return &YttGenerator{binaryPath: binaryPath}
+}
+
+func (g *YttGenerator) getBinaryPath() string {
+	if g.binaryPath != "" {
+		return g.binaryPath
+	}
+	return defaultYttBinary
+}
+
+// Generate renders the templates in appPath, together with the library paths and data values of the source, and
+// returns the resulting objects and the executed command
+func (g *YttGenerator) Generate(appPath, repoRoot string, source *v1alpha1.ApplicationSourceYtt) ([]*unstructured.Unstructured, string, error) {
+	args := []string{"--file", appPath}
+	if source != nil {
+		for _, lib := range source.Lib {
+			resolved, err := pathutil.ResolveFileOrDirectoryPath(appPath, repoRoot, lib)
+			if err != nil {
+				return nil, "", fmt.Errorf("error resolving ytt library path %q: %w", lib, err)
+			}
+			args = append(args, "--file", string(resolved))
+		}
+		keys := make([]string, 0, len(source.DataValues))
+		for k := range source.DataValues {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			args = append(args, "--data-value", fmt.Sprintf("%s=%s", k, source.DataValues[k]))
+		}
+	}
+
+	cmd := exec.Command(g.getBinaryPath(), args...)
+	command := strings.ReplaceAll(executil.GetCommandArgsToLog(cmd), repoRoot, ".")
+	out, err := executil.Run(cmd)
+	if err != nil {
+		return nil, "", err
+	}
+	objs, err := kube.SplitYAML([]byte(out))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse ytt output: %w", err)
+	}
+	return objs, command, nil
+}
+
+// Version returns the version of the ytt binary
+func (g *YttGenerator) Version() (*semver.Version, error) {
+	out, err := executil.Run(exec.Command(g.getBinaryPath(), "version"))
+	if err != nil {
+		return nil, fmt.Errorf("could not get ytt version: %w", err)
+	}
+	matches := yttVersionRegex.FindStringSubmatch(out)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("expected string that includes ytt version but got: '%s'", strings.TrimSpace(out))
+	}
+	return semver.NewVersion(matches[1])
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/util/git"
+)
+
+func fakeYttGenerator(t *testing.T) *YttGenerator {
+	t.Helper()
+	binaryPath, err := filepath.Abs("./testdata/ytt/fake-ytt")
+	require.NoError(t, err)
+	return NewYttGenerator(binaryPath)
+}
+
+func TestYttGenerator_Generate(t *testing.T) {
+	repoRoot, err := filepath.Abs("./testdata/ytt")
+	require.NoError(t, err)
+	appPath := filepath.Join(repoRoot, "app")
+
+	objs, command, err := fakeYttGenerator(t).Generate(appPath, repoRoot, &argoappv1.ApplicationSourceYtt{
+		Lib:        []string{"../lib"},
+		DataValues: map[string]string{"name": "my-config", "environment": "prod"},
+	})
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+
+	args, _, err := unstructured.NestedString(objs[0].Object, "data", "args")
+	require.NoError(t, err)
+	assert.Equal(t, "--file "+appPath+" --file "+filepath.Join(repoRoot, "lib")+" --data-value environment=prod --data-value name=my-config", args)
+	assert.Equal(t, "./fake-ytt --file ./app --file ./lib --data-value environment=prod --data-value name=my-config", command)
+}
+
+func TestYttGenerator_Generate_OutOfBoundsLib(t *testing.T) {
+	repoRoot, err := filepath.Abs("./testdata/ytt")
+	require.NoError(t, err)
+
+	_, _, err = fakeYttGenerator(t).Generate(filepath.Join(repoRoot, "app"), repoRoot, &argoappv1.ApplicationSourceYtt{
+		Lib: []string{"../../out-of-bounds.yaml"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error resolving ytt library path")
+}
+
+func TestYttGenerator_Version(t *testing.T) {
+	version, err := fakeYttGenerator(t).Version()
+	require.NoError(t, err)
+	assert.Equal(t, "0.49.0", version.String())
+
+	_, err = NewYttGenerator("./testdata/ytt/does-not-exist").Version()
+	require.Error(t, err)
+}
+
+func TestYttGenerator_Templates(t *testing.T) {
+	if _, err := exec.LookPath(defaultYttBinary); err != nil {
+		t.Skip("ytt is not installed")
+	}
+	repoRoot, err := filepath.Abs("./testdata/ytt")
+	require.NoError(t, err)
+
+	objs, _, err := NewYttGenerator("").Generate(filepath.Join(repoRoot, "app"), repoRoot, &argoappv1.ApplicationSourceYtt{
+		Lib:        []string{"../lib"},
+		DataValues: map[string]string{"environment": "prod"},
+	})
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Equal(t, "ytt-config", objs[0].GetName())
+	assert.Equal(t, map[string]string{"app.kubernetes.io/managed-by": "ytt"}, objs[0].GetLabels())
+	environment, _, err := unstructured.NestedString(objs[0].Object, "data", "environment")
+	require.NoError(t, err)
+	assert.Equal(t, "prod", environment)
+}
+
+func TestGenerateManifests_Ytt(t *testing.T) {
+	q := apiclient.ManifestRequest{
+		Repo: &argoappv1.Repository{},
+		ApplicationSource: &argoappv1.ApplicationSource{
+			Ytt: &argoappv1.ApplicationSourceYtt{DataValues: map[string]string{"environment": "prod"}},
+		},
+		ProjectName:        "something",
+		ProjectSourceRepos: []string{"*"},
+	}
+
+	res, err := GenerateManifests(context.Background(), "./testdata/ytt/app", "./testdata/ytt", "", &q, false, &git.NoopCredsStore{}, resource.MustParse("0"), nil, WithYttBinaryPath(fakeYttGenerator(t).binaryPath))
+	require.NoError(t, err)
+	assert.Equal(t, string(argoappv1.ApplicationSourceTypeYtt), res.SourceType)
+	require.Len(t, res.Manifests, 1)
+	var obj unstructured.Unstructured
+	require.NoError(t, json.Unmarshal([]byte(res.Manifests[0]), &obj.Object))
+	assert.Equal(t, "ytt-args", obj.GetName())
+	assert.Len(t, res.Commands, 1)
+}
@@ -1,35 +1,39 @@
 local hs = {}
-local healthy = false
-local degraded = false
-local suspended = false
-if obj.status ~= nil then
-  if obj.status.conditions ~= nil then
-    for i, condition in ipairs(obj.status.conditions) do
-      if condition.status == "False" and condition.type == "Ready" then
-        hs.message = condition.message
-        degraded = true
-      end
-      if condition.status == "True" and condition.type == "Ready" then
-        hs.message = condition.message
-        healthy = true
-      end
-      if condition.status == "True" and condition.type == "Paused" then
-        hs.message = condition.message
-        suspended = true
-      end
-    end
+local conditions = {}
+if obj.status ~= nil and obj.status.conditions ~= nil then
+  for i, condition in ipairs(obj.status.conditions) do
+    conditions[condition.type] = condition
   end
 end
-if degraded == true then
+local ready = conditions["Ready"]
+if ready ~= nil and ready.status == "False" then
   hs.status = "Degraded"
+  hs.message = ready.message
   return hs
-elseif healthy == true and suspended == false then
-  hs.status = "Healthy"
-  return hs 
-elseif healthy == true and suspended == true then
+end
+local fallback = conditions["Fallback"]
+if fallback ~= nil and fallback.status == "True" then
+  hs.status = "Degraded"
+  hs.message = fallback.message
+  return hs
+end
+if ready == nil or ready.status ~= "True" then
+  hs.status = "Progressing"
+  hs.message = "Waiting for the ScaledObject to be ready"
+  return hs
+end
+local paused = conditions["Paused"]
+if paused ~= nil and paused.status == "True" then
   hs.status = "Suspended"
+  hs.message = paused.message
+  return hs
+end
+local active = conditions["Active"]
+if active ~= nil and active.status == "True" then
+  hs.status = "Healthy"
+  hs.message = active.message
   return hs
 end
-hs.status = "Progressing"
-hs.message = "Creating HorizontalPodAutoscaler Object"
-return hs
\ No newline at end of file
+hs.status = "Healthy"
+hs.message = ready.message
+return hs
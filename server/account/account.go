@@ -9,7 +9,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"k8s.io/kubectl/pkg/util/slice"
@@ -17,6 +16,7 @@ import (
 	"github.com/argoproj/argo-cd/v2/common"
 	"github.com/argoproj/argo-cd/v2/pkg/apiclient/account"
 	"github.com/argoproj/argo-cd/v2/server/rbacpolicy"
+	grpc_util "github.com/argoproj/argo-cd/v2/util/grpc"
 	"github.com/argoproj/argo-cd/v2/util/password"
 	"github.com/argoproj/argo-cd/v2/util/rbac"
 	"github.com/argoproj/argo-cd/v2/util/session"
@@ -109,9 +109,9 @@ func (s *Server) UpdatePassword(ctx context.Context, q *account.UpdatePasswordRe
 	}
 
 	if updatedUsername == username {
-		log.Infof("user '%s' updated password", username)
+		grpc_util.LoggerFromContext(ctx).Infof("user '%s' updated password", username)
 	} else {
-		log.Infof("user '%s' updated password of user '%s'", username, updatedUsername)
+		grpc_util.LoggerFromContext(ctx).Infof("user '%s' updated password of user '%s'", username, updatedUsername)
 	}
 	return &account.UpdatePasswordResponse{}, nil
 }
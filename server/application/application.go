@@ -14,11 +14,13 @@ import (
 
 	kubecache "github.com/argoproj/gitops-engine/pkg/cache"
 	"github.com/argoproj/gitops-engine/pkg/diff"
+	"github.com/argoproj/gitops-engine/pkg/health"
 	"github.com/argoproj/gitops-engine/pkg/sync/common"
 	"github.com/argoproj/gitops-engine/pkg/utils/kube"
 	"github.com/argoproj/gitops-engine/pkg/utils/text"
 	"github.com/argoproj/pkg/sync"
 	jsonpatch "github.com/evanphx/json-patch"
+	gocache "github.com/patrickmn/go-cache"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -48,10 +50,13 @@ import (
 	"github.com/argoproj/argo-cd/v2/server/rbacpolicy"
 	"github.com/argoproj/argo-cd/v2/util/argo"
 	argoutil "github.com/argoproj/argo-cd/v2/util/argo"
+	argodiff "github.com/argoproj/argo-cd/v2/util/argo/diff"
+	"github.com/argoproj/argo-cd/v2/util/argo/normalizers"
 	"github.com/argoproj/argo-cd/v2/util/collections"
 	"github.com/argoproj/argo-cd/v2/util/db"
 	"github.com/argoproj/argo-cd/v2/util/env"
 	"github.com/argoproj/argo-cd/v2/util/git"
+	grpc_util "github.com/argoproj/argo-cd/v2/util/grpc"
 	ioutil "github.com/argoproj/argo-cd/v2/util/io"
 	"github.com/argoproj/argo-cd/v2/util/lua"
 	"github.com/argoproj/argo-cd/v2/util/manifeststream"
@@ -93,6 +98,8 @@ type Server struct {
 	cache             *servercache.Cache
 	projInformer      cache.SharedIndexInformer
 	enabledNamespaces []string
+	fleetStatusCache  *gocache.Cache
+	resourceWatcher   *resourceWatcher
 }
 
 // NewServer returns a new instance of the Application service
@@ -137,6 +144,8 @@ func NewServer(
 		settingsMgr:       settingsMgr,
 		projInformer:      projInformer,
 		enabledNamespaces: enabledNamespaces,
+		fleetStatusCache:  gocache.New(fleetStatusCacheTTL, fleetStatusCacheTTL),
+		resourceWatcher:   newResourceWatcher(),
 	}
 	return s, s.getAppResources
 }
@@ -156,7 +165,7 @@ func (s *Server) getAppEnforceRBAC(ctx context.Context, action, project, namespa
 	if user == "" {
 		user = "Unknown user"
 	}
-	logCtx := log.WithFields(map[string]interface{}{
+	logCtx := grpc_util.LoggerFromContext(ctx).WithFields(map[string]interface{}{
 		"user":        user,
 		"application": name,
 		"namespace":   namespace,
@@ -328,7 +337,7 @@ func (s *Server) Create(ctx context.Context, q *application.ApplicationCreateReq
 		validate = *q.Validate
 	}
 
-	proj, err := s.getAppProject(ctx, a, log.WithField("application", a.Name))
+	proj, err := s.getAppProject(ctx, a, grpc_util.LoggerFromContext(ctx).WithField("application", a.Name))
 	if err != nil {
 		return nil, err
 	}
@@ -346,7 +355,7 @@ func (s *Server) Create(ctx context.Context, q *application.ApplicationCreateReq
 
 	// Don't let the app creator set the operation explicitly. Those requests should always go through the Sync API.
 	if a.Operation != nil {
-		log.WithFields(log.Fields{
+		grpc_util.LoggerFromContext(ctx).WithFields(log.Fields{
 			"application":            a.Name,
 			argocommon.SecurityField: argocommon.SecurityLow,
 		}).Warn("User attempted to set operation on application creation. This could have allowed them to bypass branch protection rules by setting manifests directly. Ignoring the set operation.")
@@ -431,105 +440,170 @@ func (s *Server) queryRepoServer(ctx context.Context, proj *appv1.AppProject, ac
 	return action(client, permittedHelmRepos, permittedHelmCredentials, helmOptions, enabledSourceTypes)
 }
 
-// GetManifests returns application manifests
-func (s *Server) GetManifests(ctx context.Context, q *application.ApplicationManifestQuery) (*apiclient.ManifestResponse, error) {
-	if q.Name == nil || *q.Name == "" {
-		return nil, fmt.Errorf("invalid request: application name is missing")
+// getQuerySources returns the sources of the application, whose revisions are overridden by the query
+func getQuerySources(a *appv1.Application, q *application.ApplicationManifestQuery) ([]appv1.ApplicationSource, error) {
+	if a.Spec.HasMultipleSources() {
+		appSpec := a.Spec.DeepCopy()
+		numOfSources := int64(len(a.Spec.GetSources()))
+		for i, pos := range q.SourcePositions {
+			if pos <= 0 || pos > numOfSources {
+				return nil, fmt.Errorf("source position is out of range")
+			}
+			appSpec.Sources[pos-1].TargetRevision = q.Revisions[i]
+		}
+		return appSpec.GetSources(), nil
 	}
-	a, proj, err := s.getApplicationEnforceRBACInformer(ctx, rbacpolicy.ActionGet, q.GetProject(), q.GetAppNamespace(), q.GetName())
+	source := a.Spec.GetSource()
+	if q.GetRevision() != "" {
+		source.TargetRevision = q.GetRevision()
+	}
+	return []appv1.ApplicationSource{source}, nil
+}
+
+// getManifestRequests returns the requests generating the manifests of each source of the application, whose
+// revisions are overridden by the query
+func (s *Server) getManifestRequests(ctx context.Context, a *appv1.Application, proj *appv1.AppProject, q *application.ApplicationManifestQuery,
+	helmRepos []*appv1.Repository, helmCreds []*appv1.RepoCreds, helmOptions *appv1.HelmOptions, enableGenerateManifests map[string]bool,
+) ([]*apiclient.ManifestRequest, error) {
+	appInstanceLabelKey, err := s.settingsMgr.GetAppInstanceLabelKey()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("error getting app instance label key from settings: %w", err)
 	}
 
-	if !s.isNamespaceEnabled(a.Namespace) {
-		return nil, security.NamespaceNotPermittedError(a.Namespace)
+	config, err := s.getApplicationClusterConfig(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("error getting application cluster config: %w", err)
 	}
 
-	manifestInfos := make([]*apiclient.ManifestResponse, 0)
-	err = s.queryRepoServer(ctx, proj, func(
-		client apiclient.RepoServerServiceClient, helmRepos []*appv1.Repository, helmCreds []*appv1.RepoCreds, helmOptions *appv1.HelmOptions, enableGenerateManifests map[string]bool,
-	) error {
-		appInstanceLabelKey, err := s.settingsMgr.GetAppInstanceLabelKey()
-		if err != nil {
-			return fmt.Errorf("error getting app instance label key from settings: %w", err)
-		}
+	serverVersion, err := s.kubectl.GetServerVersion(config)
+	if err != nil {
+		return nil, fmt.Errorf("error getting server version: %w", err)
+	}
 
-		config, err := s.getApplicationClusterConfig(ctx, a)
-		if err != nil {
-			return fmt.Errorf("error getting application cluster config: %w", err)
-		}
+	apiResources, err := s.kubectl.GetAPIResources(config, false, kubecache.NewNoopSettings())
+	if err != nil {
+		return nil, fmt.Errorf("error getting API resources: %w", err)
+	}
+	clusterScopedResources, permittedClusterResources := argo.NamespaceIsolationResources(proj, apiResources)
 
-		serverVersion, err := s.kubectl.GetServerVersion(config)
-		if err != nil {
-			return fmt.Errorf("error getting server version: %w", err)
-		}
+	sources, err := getQuerySources(a, q)
+	if err != nil {
+		return nil, err
+	}
 
-		apiResources, err := s.kubectl.GetAPIResources(config, false, kubecache.NewNoopSettings())
+	// Store the map of all sources having ref field into a map for applications with sources field
+	refSources, err := argo.GetRefSources(context.Background(), sources, a.Spec.Project, s.db.GetRepository, []string{}, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ref sources: %w", err)
+	}
+
+	requests := make([]*apiclient.ManifestRequest, 0, len(sources))
+	var destKubeClient kubernetes.Interface
+	var destCluster *appv1.Cluster
+	for _, source := range sources {
+		repo, err := s.db.GetRepository(ctx, source.RepoURL, proj.Name)
 		if err != nil {
-			return fmt.Errorf("error getting API resources: %w", err)
+			return nil, fmt.Errorf("error getting repository: %w", err)
 		}
 
-		sources := make([]appv1.ApplicationSource, 0)
-		appSpec := a.Spec.DeepCopy()
-		if a.Spec.HasMultipleSources() {
-			numOfSources := int64(len(a.Spec.GetSources()))
-			for i, pos := range q.SourcePositions {
-				if pos <= 0 || pos > numOfSources {
-					return fmt.Errorf("source position is out of range")
+		var helmValuesFrom []string
+		if source.Helm != nil && source.Helm.ValuesFrom != nil {
+			if destKubeClient == nil {
+				destKubeClient, err = kubernetes.NewForConfig(config)
+				if err != nil {
+					return nil, fmt.Errorf("error creating kube client: %w", err)
 				}
-				appSpec.Sources[pos-1].TargetRevision = q.Revisions[i]
 			}
-			sources = appSpec.GetSources()
-		} else {
-			source := a.Spec.GetSource()
-			if q.GetRevision() != "" {
-				source.TargetRevision = q.GetRevision()
+			helmValuesFrom, err = argo.GetHelmValuesFrom(ctx, destKubeClient, source, a.Spec.Destination, proj, func(project string) ([]*appv1.Cluster, error) {
+				return s.db.GetProjectClusters(ctx, project)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error getting Helm values: %w", err)
+			}
+		}
+		if source.Helm != nil && (source.Helm.ValueFilesSuffix != "" || source.Helm.AutoValuesFile) && destCluster == nil {
+			destCluster, err = s.db.GetCluster(ctx, a.Spec.Destination.Server)
+			if err != nil {
+				return nil, fmt.Errorf("error getting cluster: %w", err)
 			}
-			sources = append(sources, source)
+		}
+		httpSourceCABundle, err := argo.GetHTTPSourceCABundle(s.settingsMgr, source)
+		if err != nil {
+			return nil, fmt.Errorf("error getting CA bundle of HTTP source: %w", err)
+		}
+		inTotoLayout, inTotoLayoutKeys, err := argo.GetInTotoLayout(s.settingsMgr, source)
+		if err != nil {
+			return nil, fmt.Errorf("error getting in-toto layout: %w", err)
 		}
 
-		// Store the map of all sources having ref field into a map for applications with sources field
-		refSources, err := argo.GetRefSources(context.Background(), sources, appSpec.Project, s.db.GetRepository, []string{}, false)
+		kustomizeSettings, err := s.settingsMgr.GetKustomizeSettings()
 		if err != nil {
-			return fmt.Errorf("failed to get ref sources: %w", err)
+			return nil, fmt.Errorf("error getting kustomize settings: %w", err)
 		}
 
-		for _, source := range sources {
-			repo, err := s.db.GetRepository(ctx, source.RepoURL, proj.Name)
-			if err != nil {
-				return fmt.Errorf("error getting repository: %w", err)
-			}
+		kustomizeOptions, err := kustomizeSettings.GetOptions(source)
+		if err != nil {
+			return nil, fmt.Errorf("error getting kustomize settings options: %w", err)
+		}
+
+		requests = append(requests, &apiclient.ManifestRequest{
+			Repo:                      repo,
+			Revision:                  source.TargetRevision,
+			AppLabelKey:               appInstanceLabelKey,
+			AppName:                   a.InstanceName(s.ns),
+			Namespace:                 a.Spec.Destination.Namespace,
+			ApplicationSource:         source.DeepCopy(),
+			Repos:                     helmRepos,
+			KustomizeOptions:          kustomizeOptions,
+			KubeVersion:               serverVersion,
+			ApiVersions:               argo.APIResourcesToStrings(apiResources, true),
+			HelmRepoCreds:             helmCreds,
+			HelmOptions:               helmOptions,
+			TrackingMethod:            string(argoutil.GetTrackingMethod(s.settingsMgr)),
+			EnabledSourceTypes:        enableGenerateManifests,
+			ProjectName:               proj.Name,
+			ProjectSourceRepos:        proj.Spec.SourceRepos,
+			HasMultipleSources:        a.Spec.HasMultipleSources(),
+			RefSources:                refSources,
+			NamespaceIsolation:        proj.Spec.NamespaceIsolation,
+			ClusterScopedResources:    clusterScopedResources,
+			PermittedClusterResources: permittedClusterResources,
+			HelmValuesFrom:            helmValuesFrom,
+			HelmValueFilesSuffix:      argo.GetHelmValueFilesSuffix(source, destCluster),
+			DestinationClusterName:    argo.GetHelmDestinationClusterName(source, destCluster),
+			HttpSourceCABundle:        httpSourceCABundle,
+			InTotoLayout:              inTotoLayout,
+			InTotoLayoutKeys:          inTotoLayoutKeys,
+			DestinationServer:         a.Spec.Destination.Server,
+		})
+	}
+	return requests, nil
+}
 
-			kustomizeSettings, err := s.settingsMgr.GetKustomizeSettings()
-			if err != nil {
-				return fmt.Errorf("error getting kustomize settings: %w", err)
-			}
+// GetManifests returns application manifests
+func (s *Server) GetManifests(ctx context.Context, q *application.ApplicationManifestQuery) (*apiclient.ManifestResponse, error) {
+	if q.Name == nil || *q.Name == "" {
+		return nil, fmt.Errorf("invalid request: application name is missing")
+	}
+	a, proj, err := s.getApplicationEnforceRBACInformer(ctx, rbacpolicy.ActionGet, q.GetProject(), q.GetAppNamespace(), q.GetName())
+	if err != nil {
+		return nil, err
+	}
 
-			kustomizeOptions, err := kustomizeSettings.GetOptions(source)
-			if err != nil {
-				return fmt.Errorf("error getting kustomize settings options: %w", err)
-			}
+	if !s.isNamespaceEnabled(a.Namespace) {
+		return nil, security.NamespaceNotPermittedError(a.Namespace)
+	}
 
-			manifestInfo, err := client.GenerateManifest(ctx, &apiclient.ManifestRequest{
-				Repo:               repo,
-				Revision:           source.TargetRevision,
-				AppLabelKey:        appInstanceLabelKey,
-				AppName:            a.InstanceName(s.ns),
-				Namespace:          a.Spec.Destination.Namespace,
-				ApplicationSource:  &source,
-				Repos:              helmRepos,
-				KustomizeOptions:   kustomizeOptions,
-				KubeVersion:        serverVersion,
-				ApiVersions:        argo.APIResourcesToStrings(apiResources, true),
-				HelmRepoCreds:      helmCreds,
-				HelmOptions:        helmOptions,
-				TrackingMethod:     string(argoutil.GetTrackingMethod(s.settingsMgr)),
-				EnabledSourceTypes: enableGenerateManifests,
-				ProjectName:        proj.Name,
-				ProjectSourceRepos: proj.Spec.SourceRepos,
-				HasMultipleSources: a.Spec.HasMultipleSources(),
-				RefSources:         refSources,
-			})
+	manifestInfos := make([]*apiclient.ManifestResponse, 0)
+	err = s.queryRepoServer(ctx, proj, func(
+		client apiclient.RepoServerServiceClient, helmRepos []*appv1.Repository, helmCreds []*appv1.RepoCreds, helmOptions *appv1.HelmOptions, enableGenerateManifests map[string]bool,
+	) error {
+		requests, err := s.getManifestRequests(ctx, a, proj, q, helmRepos, helmCreds, helmOptions, enableGenerateManifests)
+		if err != nil {
+			return err
+		}
+		for _, request := range requests {
+			manifestInfo, err := client.GenerateManifest(ctx, request)
 			if err != nil {
 				return fmt.Errorf("error generating manifests: %w", err)
 			}
@@ -542,7 +616,7 @@ func (s *Server) GetManifests(ctx context.Context, q *application.ApplicationMan
 	}
 
 	manifests := &apiclient.ManifestResponse{}
-	for _, manifestInfo := range manifestInfos {
+	for pos, manifestInfo := range manifestInfos {
 		for i, manifest := range manifestInfo.Manifests {
 			obj := &unstructured.Unstructured{}
 			err = json.Unmarshal([]byte(manifest), obj)
@@ -562,11 +636,106 @@ func (s *Server) GetManifests(ctx context.Context, q *application.ApplicationMan
 			}
 		}
 		manifests.Manifests = append(manifests.Manifests, manifestInfo.Manifests...)
+		if a.Spec.HasMultipleSources() {
+			for range manifestInfo.Manifests {
+				manifests.SourcePositions = append(manifests.SourcePositions, int64(pos+1))
+			}
+		}
 	}
 
 	return manifests, nil
 }
 
+// ProfileManifests generates the manifests of each source of the application without cache, and returns the
+// duration, peak memory and CPU profile of the generations in the repo server
+func (s *Server) ProfileManifests(ctx context.Context, q *application.ApplicationManifestQuery) (*application.ApplicationManifestProfileResponse, error) {
+	if q.Name == nil || *q.Name == "" {
+		return nil, fmt.Errorf("invalid request: application name is missing")
+	}
+	// profiling bypasses the cache of the repo server, so it requires more than the permission to get the application
+	a, proj, err := s.getApplicationEnforceRBACInformer(ctx, rbacpolicy.ActionUpdate, q.GetProject(), q.GetAppNamespace(), q.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isNamespaceEnabled(a.Namespace) {
+		return nil, security.NamespaceNotPermittedError(a.Namespace)
+	}
+
+	profiles := &application.ApplicationManifestProfileResponse{}
+	err = s.queryRepoServer(ctx, proj, func(
+		client apiclient.RepoServerServiceClient, helmRepos []*appv1.Repository, helmCreds []*appv1.RepoCreds, helmOptions *appv1.HelmOptions, enableGenerateManifests map[string]bool,
+	) error {
+		requests, err := s.getManifestRequests(ctx, a, proj, q, helmRepos, helmCreds, helmOptions, enableGenerateManifests)
+		if err != nil {
+			return err
+		}
+		for _, request := range requests {
+			profile, err := client.ProfileManifestGeneration(ctx, &apiclient.ProfileRequest{ManifestRequest: request})
+			if err != nil {
+				return fmt.Errorf("error profiling manifest generation: %w", err)
+			}
+			profiles.Items = append(profiles.Items, profile)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
+
+// GetManifestBlame returns the commits which last modified the manifest files of the sources of an application
+func (s *Server) GetManifestBlame(ctx context.Context, q *application.ApplicationManifestQuery) (*application.ApplicationManifestBlameResponse, error) {
+	if q.Name == nil || *q.Name == "" {
+		return nil, fmt.Errorf("invalid request: application name is missing")
+	}
+	a, proj, err := s.getApplicationEnforceRBACInformer(ctx, rbacpolicy.ActionGet, q.GetProject(), q.GetAppNamespace(), q.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.isNamespaceEnabled(a.Namespace) {
+		return nil, security.NamespaceNotPermittedError(a.Namespace)
+	}
+
+	sources, err := getQuerySources(a, q)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &application.ApplicationManifestBlameResponse{}
+	err = s.queryRepoServer(ctx, proj, func(
+		client apiclient.RepoServerServiceClient, _ []*appv1.Repository, _ []*appv1.RepoCreds, _ *appv1.HelmOptions, _ map[string]bool,
+	) error {
+		for _, source := range sources {
+			// only the manifests stored in git repositories have a history to blame
+			if source.IsHelm() || source.IsInline() || source.IsHTTP() || source.IsOCI() {
+				res.Items = append(res.Items, &apiclient.ManifestBlame{})
+				continue
+			}
+			repo, err := s.db.GetRepository(ctx, source.RepoURL, proj.Name)
+			if err != nil {
+				return fmt.Errorf("error getting repository: %w", err)
+			}
+			blame, err := client.GetManifestBlame(ctx, &apiclient.ManifestBlameRequest{
+				Repo:     repo,
+				Revision: source.TargetRevision,
+				Path:     source.Path,
+			})
+			if err != nil {
+				return fmt.Errorf("error getting manifest blame: %w", err)
+			}
+			res.Items = append(res.Items, blame)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
 func (s *Server) GetManifestsWithFiles(stream application.ApplicationService_GetManifestsWithFilesServer) error {
 	ctx := stream.Context()
 	query, err := manifeststream.ReceiveApplicationManifestQueryWithFiles(stream)
@@ -772,7 +941,7 @@ func (s *Server) Get(ctx context.Context, q *application.ApplicationQuery) (*app
 			})
 			return err
 		}); err != nil {
-			log.Warnf("Failed to force refresh application details: %v", err)
+			grpc_util.LoggerFromContext(ctx).Warnf("Failed to force refresh application details: %v", err)
 		}
 	}
 
@@ -854,7 +1023,7 @@ func (s *Server) ListResourceEvents(ctx context.Context, q *application.Applicat
 			"involvedObject.namespace": namespace,
 		}).String()
 	}
-	log.Infof("Querying for resource events with field selector: %s", fieldSelector)
+	grpc_util.LoggerFromContext(ctx).Infof("Querying for resource events with field selector: %s", fieldSelector)
 	opts := metav1.ListOptions{FieldSelector: fieldSelector}
 	list, err := kubeClientset.CoreV1().Events(namespace).List(ctx, opts)
 	if err != nil {
@@ -1207,9 +1376,24 @@ func (s *Server) Watch(q *application.ApplicationQuery, ws application.Applicati
 	}
 	unsubscribe := s.appBroadcaster.Subscribe(events)
 	defer unsubscribe()
+	// the watch of a single application is notified of the status updates of the application controller, so that
+	// they are delivered without waiting for the informer of the API server
+	var lastVersion int
+	if appName != "" {
+		go s.watchAppStatus(ws.Context(), appName, appNs, events, logCtx)
+	}
 	for {
 		select {
 		case event := <-events:
+			if appName != "" && event.Type == watch.Modified {
+				// the same version of the application is received from both the informer and the notifications
+				if version, err := strconv.Atoi(event.Application.ResourceVersion); err == nil {
+					if version <= lastVersion {
+						continue
+					}
+					lastVersion = version
+				}
+			}
 			sendIfPermitted(event.Application, event.Type)
 		case <-ws.Context().Done():
 			return nil
@@ -1217,6 +1401,26 @@ func (s *Server) Watch(q *application.ApplicationQuery, ws application.Applicati
 	}
 }
 
+// watchAppStatus queues a modification event of the application each time the application controller notifies that
+// it updated the status of the application, until the context is done
+func (s *Server) watchAppStatus(ctx context.Context, appName, appNs string, events chan<- *appv1.ApplicationWatchEvent, logCtx *log.Entry) {
+	err := s.cache.OnAppStatusChanged(ctx, argo.AppInstanceName(appName, appNs, s.ns), func() error {
+		a, err := s.appclientset.ArgoprojV1alpha1().Applications(appNs).Get(ctx, appName, metav1.GetOptions{})
+		if err != nil {
+			logCtx.Warnf("Unable to get application after its status changed: %v", err)
+			return nil
+		}
+		select {
+		case events <- &appv1.ApplicationWatchEvent{Type: watch.Modified, Application: *a}:
+		case <-ctx.Done():
+		}
+		return nil
+	})
+	if err != nil {
+		logCtx.Warnf("Unable to watch application status changes: %v", err)
+	}
+}
+
 func (s *Server) validateAndNormalizeApp(ctx context.Context, app *appv1.Application, proj *appv1.AppProject, validate bool) error {
 	if app.GetName() == "" {
 		return fmt.Errorf("resource name may not be empty")
@@ -1373,6 +1577,31 @@ func (s *Server) GetResource(ctx context.Context, q *application.ApplicationReso
 	return &application.ApplicationResourceResponse{Manifest: &manifest}, nil
 }
 
+// WatchResource streams the live state changes of a resource of an application, and heartbeats while the resource does
+// not change
+func (s *Server) WatchResource(q *application.WatchResourceRequest, ws application.ApplicationService_WatchResourceServer) error {
+	res, config, _, err := s.getAppLiveResource(ws.Context(), rbacpolicy.ActionGet, &application.ApplicationResourceRequest{
+		Name:         q.Name,
+		AppNamespace: q.AppNamespace,
+		Namespace:    q.Namespace,
+		ResourceName: q.ResourceName,
+		Kind:         q.Kind,
+		Version:      q.Version,
+		Group:        q.Group,
+		Project:      q.Project,
+	})
+	if err != nil {
+		return err
+	}
+
+	// make sure to use specified resource version if provided
+	if q.GetVersion() != "" {
+		res.Version = q.GetVersion()
+	}
+	key := resourceWatchKey{server: config.Host, gvk: res.GroupKindVersion(), namespace: res.Namespace, name: res.Name}
+	return s.resourceWatcher.Watch(ws.Context(), config, key, ws.Send)
+}
+
 func replaceSecretValues(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 	if obj.GetKind() == kube.SecretKind && obj.GroupVersionKind().Group == "" {
 		_, obj, err := diff.HideSecretData(nil, obj)
@@ -1386,6 +1615,12 @@ func replaceSecretValues(obj *unstructured.Unstructured) (*unstructured.Unstruct
 
 // PatchResource patches a resource
 func (s *Server) PatchResource(ctx context.Context, q *application.ApplicationResourcePatchRequest) (*application.ApplicationResourceResponse, error) {
+	dryRun := q.GetDryRun()
+	switch dryRun {
+	case "", patchDryRunNone, patchDryRunClient, patchDryRunServer:
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown dry run mode %q, must be one of %s, %s or %s", dryRun, patchDryRunNone, patchDryRunClient, patchDryRunServer)
+	}
 	resourceRequest := &application.ApplicationResourceRequest{
 		Name:         q.Name,
 		AppNamespace: q.AppNamespace,
@@ -1401,7 +1636,19 @@ func (s *Server) PatchResource(ctx context.Context, q *application.ApplicationRe
 		return nil, err
 	}
 
-	manifest, err := s.kubectl.PatchResource(ctx, config, res.GroupKindVersion(), res.Name, res.Namespace, types.PatchType(q.GetPatchType()), []byte(q.GetPatch()))
+	var manifest *unstructured.Unstructured
+	switch dryRun {
+	case patchDryRunServer:
+		manifest, err = patchResourceServerDryRun(ctx, config, res.GroupKindVersion(), res.Name, res.Namespace, types.PatchType(q.GetPatchType()), []byte(q.GetPatch()))
+	case patchDryRunClient:
+		var live *unstructured.Unstructured
+		live, err = s.kubectl.GetResource(ctx, config, res.GroupKindVersion(), res.Name, res.Namespace)
+		if err == nil {
+			manifest, err = patchResourceClientDryRun(live, types.PatchType(q.GetPatchType()), []byte(q.GetPatch()))
+		}
+	default:
+		manifest, err = s.kubectl.PatchResource(ctx, config, res.GroupKindVersion(), res.Name, res.Namespace, types.PatchType(q.GetPatchType()), []byte(q.GetPatch()))
+	}
 	if err != nil {
 		// don't expose real error for secrets since it might contain secret data
 		if res.Kind == kube.SecretKind && res.Group == "" {
@@ -1420,7 +1667,9 @@ func (s *Server) PatchResource(ctx context.Context, q *application.ApplicationRe
 	if err != nil {
 		return nil, fmt.Errorf("erro marshaling manifest object: %w", err)
 	}
-	s.logAppEvent(a, ctx, argo.EventReasonResourceUpdated, fmt.Sprintf("patched resource %s/%s '%s'", q.GetGroup(), q.GetKind(), q.GetResourceName()))
+	if dryRun == "" || dryRun == patchDryRunNone {
+		s.logAppEvent(a, ctx, argo.EventReasonResourceUpdated, fmt.Sprintf("patched resource %s/%s '%s'", q.GetGroup(), q.GetKind(), q.GetResourceName()))
+	}
 	m := string(data)
 	return &application.ApplicationResourceResponse{
 		Manifest: &m,
@@ -1489,6 +1738,95 @@ func (s *Server) WatchResourceTree(q *application.ResourcesQuery, ws application
 	})
 }
 
+// StreamApplicationResourceTree sends the resource tree of an application level by level, so that clients can render
+// the resources managed by the application while their children are loading
+func (s *Server) StreamApplicationResourceTree(q *application.ResourceTreeStreamQuery, ws application.ApplicationService_StreamApplicationResourceTreeServer) error {
+	if q.GetNodeLimit() < 0 {
+		return status.Errorf(codes.InvalidArgument, "node limit must not be negative")
+	}
+	if q.MaxDepth != nil && q.GetMaxDepth() < 0 {
+		return status.Errorf(codes.InvalidArgument, "max depth must not be negative")
+	}
+	a, _, err := s.getApplicationEnforceRBACInformer(ws.Context(), rbacpolicy.ActionGet, q.GetProject(), q.GetAppNamespace(), q.GetApplicationName())
+	if err != nil {
+		return err
+	}
+
+	tree, err := s.getAppResources(ws.Context(), a)
+	if err != nil {
+		return err
+	}
+	for _, level := range resourceTreeLevels(tree, q.MaxDepth, q.GetNodeLimit()) {
+		if err := ws.Send(level); err != nil {
+			return fmt.Errorf("error sending resource tree level: %w", err)
+		}
+	}
+	return nil
+}
+
+// resourceTreeLevels splits the nodes of the resource tree in levels: the nodes without a parent in the tree first, then
+// their children, and so on. A node with several parents belongs to the level following its first parent. The levels
+// stop at the given maximum depth, if not nil, and after the given number of nodes, if not 0.
+func resourceTreeLevels(tree *appv1.ApplicationTree, maxDepth *int64, nodeLimit int64) []*application.ResourceTreeLevel {
+	nodeKey := func(ref appv1.ResourceRef) string {
+		return fmt.Sprintf("%s/%s/%s/%s", ref.Group, ref.Kind, ref.Namespace, ref.Name)
+	}
+	inTree := map[string]bool{}
+	for _, node := range tree.Nodes {
+		inTree[nodeKey(node.ResourceRef)] = true
+	}
+	children := map[string][]*appv1.ResourceNode{}
+	var current []*appv1.ResourceNode
+	for i := range tree.Nodes {
+		node := &tree.Nodes[i]
+		hasParent := false
+		for _, parent := range node.ParentRefs {
+			if key := nodeKey(parent); inTree[key] && key != nodeKey(node.ResourceRef) {
+				children[key] = append(children[key], node)
+				hasParent = true
+			}
+		}
+		if !hasParent {
+			current = append(current, node)
+		}
+	}
+
+	var levels []*application.ResourceTreeLevel
+	sent := map[string]bool{}
+	var sentCount int64
+	for depth := int64(0); ; depth++ {
+		// keep the nodes which were not sent at a previous level, nor reached twice at this level
+		var pending []*appv1.ResourceNode
+		for _, node := range current {
+			if key := nodeKey(node.ResourceRef); !sent[key] {
+				sent[key] = true
+				pending = append(pending, node)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+		if (maxDepth != nil && depth > *maxDepth) || (nodeLimit > 0 && sentCount == nodeLimit) {
+			levels[len(levels)-1].Truncated = ptr.To(true)
+			break
+		}
+		sort.Slice(pending, func(i, j int) bool {
+			return nodeKey(pending[i].ResourceRef) < nodeKey(pending[j].ResourceRef)
+		})
+		if nodeLimit > 0 && sentCount+int64(len(pending)) > nodeLimit {
+			levels = append(levels, &application.ResourceTreeLevel{Depth: ptr.To(depth), Nodes: pending[:nodeLimit-sentCount], Truncated: ptr.To(true)})
+			break
+		}
+		levels = append(levels, &application.ResourceTreeLevel{Depth: ptr.To(depth), Nodes: pending})
+		sentCount += int64(len(pending))
+		current = nil
+		for _, node := range pending {
+			current = append(current, children[nodeKey(node.ResourceRef)]...)
+		}
+	}
+	return levels
+}
+
 func (s *Server) RevisionMetadata(ctx context.Context, q *application.RevisionMetadataQuery) (*appv1.RevisionMetadata, error) {
 	a, proj, err := s.getApplicationEnforceRBACInformer(ctx, rbacpolicy.ActionGet, q.GetProject(), q.GetAppNamespace(), q.GetName())
 	if err != nil {
@@ -1818,7 +2156,7 @@ func (s *Server) PodLogs(q *application.ApplicationPodLogsQuery, ws application.
 	case err := <-done:
 		return err
 	case <-ws.Context().Done():
-		log.WithField("application", q.Name).Debug("k8s pod logs reader completed due to closed grpc context")
+		grpc_util.LoggerFromContext(ws.Context()).WithField("application", q.Name).Debug("k8s pod logs reader completed due to closed grpc context")
 		return nil
 	}
 }
@@ -1878,6 +2216,34 @@ func isTheSelectedOne(currentNode *appv1.ResourceNode, q *application.Applicatio
 }
 
 // Sync syncs an application to its target state
+// getSyncDestinations returns the destinations selected by a sync request, each of which must select at least one of the
+// destinations of the application
+func (s *Server) getSyncDestinations(ctx context.Context, a *appv1.Application, selected []*appv1.ApplicationDestination) ([]appv1.ApplicationDestination, error) {
+	var destinations []appv1.ApplicationDestination
+	for _, selector := range selected {
+		if selector == nil {
+			continue
+		}
+		matched := false
+		for _, dest := range a.Spec.GetDestinations() {
+			// destinations which reference their cluster by name are selected by the server of the cluster as well
+			if err := argo.ValidateDestination(ctx, &dest, s.db); err != nil {
+				return nil, status.Errorf(codes.InvalidArgument, "application destination spec for %s is invalid: %s", a.Name, err.Error())
+			}
+			op := appv1.SyncOperation{Destinations: []appv1.ApplicationDestination{*selector}}
+			if op.SyncsDestination(dest) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return nil, status.Errorf(codes.InvalidArgument, "destination server '%s', name '%s' and namespace '%s' does not match any destination of application %s", selector.Server, selector.Name, selector.Namespace, a.Name)
+		}
+		destinations = append(destinations, *selector)
+	}
+	return destinations, nil
+}
+
 func (s *Server) Sync(ctx context.Context, syncReq *application.ApplicationSyncRequest) (*appv1.Application, error) {
 	a, proj, err := s.getApplicationEnforceRBACClient(ctx, rbacpolicy.ActionGet, syncReq.GetProject(), syncReq.GetAppNamespace(), syncReq.GetName(), "")
 	if err != nil {
@@ -1886,8 +2252,22 @@ func (s *Server) Sync(ctx context.Context, syncReq *application.ApplicationSyncR
 
 	s.inferResourcesStatusHealth(a)
 
-	if !proj.Spec.SyncWindows.Matches(a).CanSync(true) {
-		return a, status.Errorf(codes.PermissionDenied, "cannot sync: blocked by sync window")
+	// Syncs blocked by sync windows may override them, which is only needed while they block the sync
+	overrideSyncWindow := false
+	if syncWindows := proj.Spec.SyncWindows.Matches(a); !syncWindows.CanSync(true) {
+		if !syncReq.GetOverrideSyncWindow() {
+			return a, status.Errorf(codes.PermissionDenied, "cannot sync: blocked by sync window")
+		}
+		if syncReq.GetOverrideReason() == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "cannot override sync windows without a reason")
+		}
+		if err := s.enf.EnforceErr(ctx.Value("claims"), rbacpolicy.ResourceApplications, rbacpolicy.ActionOverride, a.RBACName(s.ns)); err != nil {
+			return nil, err
+		}
+		if !syncWindows.CanOverride() {
+			return a, status.Errorf(codes.PermissionDenied, "cannot sync: blocked by sync window which does not allow overrides")
+		}
+		overrideSyncWindow = true
 	}
 
 	if err := s.enf.EnforceErr(ctx.Value("claims"), rbacpolicy.ResourceApplications, rbacpolicy.ActionSync, a.RBACName(s.ns)); err != nil {
@@ -1937,6 +2317,10 @@ func (s *Server) Sync(ctx context.Context, syncReq *application.ApplicationSyncR
 			}
 		}
 	}
+	destinations, err := s.getSyncDestinations(ctx, a, syncReq.GetDestinations())
+	if err != nil {
+		return nil, err
+	}
 	op := appv1.Operation{
 		Sync: &appv1.SyncOperation{
 			Revision:     revision,
@@ -1948,6 +2332,7 @@ func (s *Server) Sync(ctx context.Context, syncReq *application.ApplicationSyncR
 			Manifests:    syncReq.Manifests,
 			Sources:      a.Spec.Sources,
 			Revisions:    sourceRevisions,
+			Destinations: destinations,
 		},
 		InitiatedBy: appv1.OperationInitiator{Username: session.Username(ctx)},
 		Info:        syncReq.Infos,
@@ -1956,6 +2341,18 @@ func (s *Server) Sync(ctx context.Context, syncReq *application.ApplicationSyncR
 		op.Retry = *retry
 	}
 
+	if overrideSyncWindow {
+		if err := s.recordSyncWindowOverride(ctx, a, session.Username(ctx), syncReq.GetOverrideReason(), time.Now()); err != nil {
+			return nil, err
+		}
+		log.WithFields(log.Fields{
+			"application": a.QualifiedName(),
+			"user":        session.Username(ctx),
+			"reason":      syncReq.GetOverrideReason(),
+		}).Warn("Sync windows overridden")
+		op.Sync.SyncWindowOverrideReason = syncReq.GetOverrideReason()
+	}
+
 	appName := syncReq.GetName()
 	appNs := s.appNamespaceOrDefault(syncReq.GetAppNamespace())
 	appIf := s.appclientset.ArgoprojV1alpha1().Applications(appNs)
@@ -1976,6 +2373,9 @@ func (s *Server) Sync(ctx context.Context, syncReq *application.ApplicationSyncR
 	if syncReq.Manifests != nil {
 		reason = fmt.Sprintf("initiated %ssync locally", partial)
 	}
+	if overrideSyncWindow {
+		reason = fmt.Sprintf("%s overriding sync windows: %s", reason, syncReq.GetOverrideReason())
+	}
 	s.logAppEvent(a, ctx, argo.EventReasonOperationStarted, reason)
 	return a, nil
 }
@@ -2109,7 +2509,7 @@ func (s *Server) ListLinks(ctx context.Context, req *application.ListAppLinksReq
 
 	finalList, errorList := deeplinks.EvaluateDeepLinksResponse(deepLinksObject, obj.GetName(), deepLinks)
 	if len(errorList) > 0 {
-		log.Errorf("errorList while evaluating application deep links, %v", strings.Join(errorList, ", "))
+		grpc_util.LoggerFromContext(ctx).Errorf("errorList while evaluating application deep links, %v", strings.Join(errorList, ", "))
 	}
 
 	return finalList, nil
@@ -2129,7 +2529,7 @@ func (s *Server) getObjectsForDeepLinks(ctx context.Context, app *appv1.Applicat
 	}
 
 	if err := argo.ValidateDestination(ctx, &app.Spec.Destination, s.db); err != nil {
-		log.WithFields(map[string]interface{}{
+		grpc_util.LoggerFromContext(ctx).WithFields(map[string]interface{}{
 			"application": app.GetName(),
 			"ns":          app.GetNamespace(),
 			"destination": app.Spec.Destination,
@@ -2146,7 +2546,7 @@ func (s *Server) getObjectsForDeepLinks(ctx context.Context, app *appv1.Applicat
 	}
 	clst, err := s.db.GetCluster(ctx, app.Spec.Destination.Server)
 	if err != nil {
-		log.WithFields(map[string]interface{}{
+		grpc_util.LoggerFromContext(ctx).WithFields(map[string]interface{}{
 			"application": app.GetName(),
 			"ns":          app.GetNamespace(),
 			"destination": app.Spec.Destination,
@@ -2178,7 +2578,7 @@ func (s *Server) ListResourceLinks(ctx context.Context, req *application.Applica
 		return nil, err
 	}
 
-	proj, err := s.getAppProject(ctx, app, log.WithField("application", app.GetName()))
+	proj, err := s.getAppProject(ctx, app, grpc_util.LoggerFromContext(ctx).WithField("application", app.GetName()))
 	if err != nil {
 		return nil, err
 	}
@@ -2191,12 +2591,402 @@ func (s *Server) ListResourceLinks(ctx context.Context, req *application.Applica
 	deepLinksObject := deeplinks.CreateDeepLinksObject(obj, appObj, clstObj, projObj)
 	finalList, errorList := deeplinks.EvaluateDeepLinksResponse(deepLinksObject, obj.GetName(), deepLinks)
 	if len(errorList) > 0 {
-		log.Errorf("errors while evaluating resource deep links, %v", strings.Join(errorList, ", "))
+		grpc_util.LoggerFromContext(ctx).Errorf("errors while evaluating resource deep links, %v", strings.Join(errorList, ", "))
 	}
 
 	return finalList, nil
 }
 
+// GetResourceHealthTimeline returns the health changes of an application resource, most recent first
+func (s *Server) GetResourceHealthTimeline(ctx context.Context, q *application.ResourceHealthTimelineRequest) (*application.ResourceHealthTimeline, error) {
+	a, _, err := s.getApplicationEnforceRBACInformer(ctx, rbacpolicy.ActionGet, q.GetProject(), q.GetAppNamespace(), q.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if q.GetLimit() < 0 || q.GetOffset() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit and offset must not be negative")
+	}
+
+	key := kube.NewResourceKey(q.GetGroup(), q.GetKind(), q.GetNamespace(), q.GetResourceName())
+	events, err := s.cache.GetResourceHealthEvents(a.InstanceName(s.ns), key)
+	if err != nil && !errors.Is(err, servercache.ErrCacheMiss) {
+		return nil, fmt.Errorf("error getting resource health events: %w", err)
+	}
+	ref := appv1.ResourceRef{Group: q.GetGroup(), Version: q.GetVersion(), Kind: q.GetKind(), Namespace: q.GetNamespace(), Name: q.GetResourceName()}
+	timeline := &application.ResourceHealthTimeline{Total: ptr.To(int64(len(events)))}
+	// events are stored oldest first
+	for i := len(events) - 1 - int(q.GetOffset()); i >= 0; i-- {
+		if q.GetLimit() > 0 && int64(len(timeline.Events)) >= q.GetLimit() {
+			break
+		}
+		timeline.Events = append(timeline.Events, &application.ResourceHealthEvent{
+			ResourceRef: &ref,
+			Timestamp:   &metav1.Time{Time: events[i].Timestamp},
+			Status:      ptr.To(string(events[i].Status)),
+			Message:     ptr.To(events[i].Message),
+		})
+	}
+	return timeline, nil
+}
+
+// GetPreSyncSnapshot returns the live state of the resources of an application captured before a sync
+func (s *Server) GetPreSyncSnapshot(ctx context.Context, q *application.PreSyncSnapshotRequest) (*application.ResourceSnapshot, error) {
+	a, _, err := s.getApplicationEnforceRBACInformer(ctx, rbacpolicy.ActionGet, q.GetProject(), q.GetAppNamespace(), q.GetName())
+	if err != nil {
+		return nil, err
+	}
+	if q.GetSyncId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "sync ID must not be empty")
+	}
+	snapshot, err := s.cache.GetPreSyncSnapshot(a.InstanceName(s.ns), q.GetSyncId())
+	if errors.Is(err, servercache.ErrCacheMiss) {
+		return nil, status.Errorf(codes.NotFound, "no snapshot found for sync %s of application %s, it may have expired", q.GetSyncId(), a.QualifiedName())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting pre-sync snapshot: %w", err)
+	}
+	res := &application.ResourceSnapshot{SyncId: ptr.To(q.GetSyncId()), CreatedAt: &metav1.Time{Time: snapshot.CreatedAt}}
+	for i := range snapshot.Resources {
+		entry := snapshot.Resources[i]
+		res.Resources = append(res.Resources, &application.ResourceSnapshotEntry{
+			ResourceRef:     &entry.ResourceRef,
+			LiveManifest:    ptr.To(entry.LiveManifest),
+			ResourceVersion: ptr.To(entry.ResourceVersion),
+		})
+	}
+	return res, nil
+}
+
+// StoreDryRunResult stores the result of the last dry run sync of an application for later comparison
+func (s *Server) StoreDryRunResult(ctx context.Context, q *application.DryRunResultStoreRequest) (*application.DryRunResultStoreResponse, error) {
+	a, _, err := s.getApplicationEnforceRBACInformer(ctx, rbacpolicy.ActionSync, q.GetProject(), q.GetAppNamespace(), q.GetName())
+	if err != nil {
+		return nil, err
+	}
+	result, err := lastSyncResult(a, true)
+	if err != nil {
+		return nil, err
+	}
+	id, err := s.cache.StoreDryRunResult(a.Name, &application.DryRunSyncResult{
+		AppNamespace: ptr.To(a.Namespace),
+		Project:      ptr.To(a.Spec.GetProject()),
+		Result:       result,
+		StoredAt:     &metav1.Time{Time: time.Now()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &application.DryRunResultStoreResponse{Id: ptr.To(id)}, nil
+}
+
+// GetDryRunResult returns a stored dry run sync result
+func (s *Server) GetDryRunResult(ctx context.Context, q *application.DryRunResultQuery) (*application.DryRunSyncResult, error) {
+	return s.getDryRunResult(ctx, q.GetId())
+}
+
+// CompareDryRunToActual compares a stored dry run sync result to the last sync of an application
+func (s *Server) CompareDryRunToActual(ctx context.Context, q *application.DryRunComparisonRequest) (*application.DryRunComparisonResult, error) {
+	a, _, err := s.getApplicationEnforceRBACInformer(ctx, rbacpolicy.ActionGet, q.GetProject(), q.GetAppNamespace(), q.GetName())
+	if err != nil {
+		return nil, err
+	}
+	dryRun, err := s.getDryRunResult(ctx, q.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if dryRun.GetAppName() != a.Name || dryRun.GetAppNamespace() != a.Namespace {
+		return nil, status.Errorf(codes.InvalidArgument, "dry run result %s does not belong to application %s", q.GetId(), a.QualifiedName())
+	}
+	actual, err := lastSyncResult(a, false)
+	if err != nil {
+		return nil, err
+	}
+	return compareDryRunToActual(dryRun.Result, actual), nil
+}
+
+// DryRunSyncFromSnapshot compares the manifests of an application to a snapshot of the state of its destination
+// cluster, without contacting the cluster
+func (s *Server) DryRunSyncFromSnapshot(ctx context.Context, q *application.DryRunSnapshotRequest) (*application.DryRunSnapshotResult, error) {
+	a, _, err := s.getApplicationEnforceRBACInformer(ctx, rbacpolicy.ActionGet, q.GetProject(), q.GetAppNamespace(), q.GetName())
+	if err != nil {
+		return nil, err
+	}
+	snapshot, err := parseClusterSnapshot([]byte(q.GetSnapshot()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	manifests := q.GetManifests()
+	if len(manifests) == 0 {
+		manifestInfo, err := s.GetManifests(ctx, &application.ApplicationManifestQuery{
+			Name:         q.Name,
+			AppNamespace: q.AppNamespace,
+			Project:      q.Project,
+			Revision:     q.Revision,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("error getting manifests: %w", err)
+		}
+		manifests = manifestInfo.Manifests
+	}
+	targets := make([]*unstructured.Unstructured, 0, len(manifests))
+	for _, manifest := range manifests {
+		obj, err := appv1.UnmarshalToUnstructured(manifest)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "error unmarshaling manifest: %v", err)
+		}
+		targets = append(targets, obj)
+	}
+
+	appLabelKey, err := s.settingsMgr.GetAppInstanceLabelKey()
+	if err != nil {
+		return nil, fmt.Errorf("error getting app instance label key from settings: %w", err)
+	}
+	trackingMethod := argoutil.GetTrackingMethod(s.settingsMgr)
+	resourceOverrides, err := s.settingsMgr.GetResourceOverrides()
+	if err != nil {
+		return nil, fmt.Errorf("error getting resource overrides: %w", err)
+	}
+	compareOptions, err := s.settingsMgr.GetResourceCompareOptions()
+	if err != nil {
+		return nil, fmt.Errorf("error getting resource compare options: %w", err)
+	}
+	diffConfig, err := argodiff.NewDiffConfigBuilder().
+		WithDiffSettings(a.Spec.IgnoreDifferences, resourceOverrides, compareOptions.IgnoreAggregatedRoles, normalizers.IgnoreNormalizerOpts{}).
+		WithTracking(appLabelKey, string(trackingMethod)).
+		WithNoCache().
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("error building diff config: %w", err)
+	}
+
+	items, err := snapshotDiffs(a, a.InstanceName(s.ns), targets, snapshot, appLabelKey, trackingMethod, diffConfig)
+	if err != nil {
+		return nil, err
+	}
+	modified := false
+	for _, item := range items {
+		if item.Modified {
+			modified = true
+			break
+		}
+	}
+	return &application.DryRunSnapshotResult{Items: items, Modified: ptr.To(modified)}, nil
+}
+
+// ServerSideDiff compares the live resources of an application to the result of a server-side apply of their target
+// state in dry-run mode. Unlike the diff of the application, the mutations of the admission webhooks are kept, which
+// reveals whether a difference is caused by the manifests or by the destination cluster.
+func (s *Server) ServerSideDiff(ctx context.Context, q *application.ApplicationServerSideDiffQuery) (*application.ApplicationServerSideDiffResponse, error) {
+	a, _, err := s.getApplicationEnforceRBACInformer(ctx, rbacpolicy.ActionGet, q.GetProject(), q.GetAppNamespace(), q.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*appv1.ResourceDiff, 0)
+	err = s.getCachedAppState(ctx, a, func() error {
+		return s.cache.GetAppManagedResources(a.InstanceName(s.ns), &items)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting cached app managed resources: %w", err)
+	}
+	managed := make([]*appv1.ResourceDiff, 0, len(items))
+	for _, item := range items {
+		if !item.Hook {
+			managed = append(managed, item)
+		}
+	}
+
+	config, err := s.getApplicationClusterConfig(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("error getting application cluster config: %w", err)
+	}
+	openAPISchema, gvkParser, err := s.kubectl.LoadOpenAPISchema(config)
+	if err != nil {
+		return nil, fmt.Errorf("error loading OpenAPI schema: %w", err)
+	}
+	resourceOps, cleanup, err := s.kubectl.ManageResources(config, openAPISchema)
+	if err != nil {
+		return nil, fmt.Errorf("error creating resource operations: %w", err)
+	}
+	defer cleanup()
+
+	appLabelKey, err := s.settingsMgr.GetAppInstanceLabelKey()
+	if err != nil {
+		return nil, fmt.Errorf("error getting app instance label key from settings: %w", err)
+	}
+	trackingMethod := argoutil.GetTrackingMethod(s.settingsMgr)
+	resourceOverrides, err := s.settingsMgr.GetResourceOverrides()
+	if err != nil {
+		return nil, fmt.Errorf("error getting resource overrides: %w", err)
+	}
+	compareOptions, err := s.settingsMgr.GetResourceCompareOptions()
+	if err != nil {
+		return nil, fmt.Errorf("error getting resource compare options: %w", err)
+	}
+	diffConfig, err := argodiff.NewDiffConfigBuilder().
+		WithDiffSettings(a.Spec.IgnoreDifferences, resourceOverrides, compareOptions.IgnoreAggregatedRoles, normalizers.IgnoreNormalizerOpts{}).
+		WithTracking(appLabelKey, string(trackingMethod)).
+		WithNoCache().
+		WithGVKParser(gvkParser).
+		WithManager(argocommon.ArgoCDSSAManager).
+		WithServerSideDiff(true).
+		WithServerSideDryRunner(diff.NewK8sServerSideDryRunner(resourceOps)).
+		WithIgnoreMutationWebhook(false).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("error building diff config: %w", err)
+	}
+
+	diffs, err := serverSideDiffs(managed, diffConfig)
+	if err != nil {
+		return nil, err
+	}
+	modified := false
+	for _, item := range diffs {
+		if item.Modified {
+			modified = true
+			break
+		}
+	}
+	return &application.ApplicationServerSideDiffResponse{Items: diffs, Modified: ptr.To(modified)}, nil
+}
+
+// getDryRunResult returns the stored dry run sync result with the given ID if the user may get its application
+func (s *Server) getDryRunResult(ctx context.Context, id string) (*application.DryRunSyncResult, error) {
+	result := &application.DryRunSyncResult{}
+	if err := s.cache.GetDryRunResult(id, result); err != nil {
+		if errors.Is(err, servercache.ErrCacheMiss) {
+			return nil, status.Errorf(codes.NotFound, "dry run result %s not found", id)
+		}
+		return nil, fmt.Errorf("error getting dry run result: %w", err)
+	}
+	rbacName := security.RBACName(s.ns, result.GetProject(), result.GetAppNamespace(), result.GetAppName())
+	if err := s.enf.EnforceErr(ctx.Value("claims"), rbacpolicy.ResourceApplications, rbacpolicy.ActionGet, rbacName); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// lastSyncResult returns the result of the last sync operation of the given application if it completed and was a
+// dry run (dryRun is true) or an actual sync (dryRun is false)
+func lastSyncResult(a *appv1.Application, dryRun bool) (*appv1.SyncOperationResult, error) {
+	state := a.Status.OperationState
+	if state == nil || state.Operation.Sync == nil || state.Operation.Sync.DryRun != dryRun || !state.Phase.Completed() || state.SyncResult == nil {
+		if dryRun {
+			return nil, status.Errorf(codes.FailedPrecondition, "the last operation of application %s is not a completed dry run sync", a.QualifiedName())
+		}
+		return nil, status.Errorf(codes.FailedPrecondition, "the last operation of application %s is not a completed sync", a.QualifiedName())
+	}
+	return state.SyncResult, nil
+}
+
+// compareDryRunToActual compares the resource results of a dry run sync to the ones of an actual sync. Hooks are
+// skipped, since they are not run by dry runs.
+func compareDryRunToActual(dryRun *appv1.SyncOperationResult, actual *appv1.SyncOperationResult) *application.DryRunComparisonResult {
+	var items []*application.DryRunResourceComparison
+	byKey := map[kube.ResourceKey]*application.DryRunResourceComparison{}
+	item := func(res *appv1.ResourceResult) *application.DryRunResourceComparison {
+		key := kube.NewResourceKey(res.Group, res.Kind, res.Namespace, res.Name)
+		if c, ok := byKey[key]; ok {
+			return c
+		}
+		c := &application.DryRunResourceComparison{Group: ptr.To(res.Group), Kind: ptr.To(res.Kind), Namespace: ptr.To(res.Namespace), Name: ptr.To(res.Name)}
+		byKey[key] = c
+		items = append(items, c)
+		return c
+	}
+	for _, res := range dryRun.Resources {
+		if res.HookType == "" {
+			c := item(res)
+			c.DryRunStatus = ptr.To(string(res.Status))
+			c.DryRunMessage = ptr.To(res.Message)
+		}
+	}
+	for _, res := range actual.Resources {
+		if res.HookType == "" {
+			c := item(res)
+			c.ActualStatus = ptr.To(string(res.Status))
+			c.ActualMessage = ptr.To(res.Message)
+		}
+	}
+
+	result := &application.DryRunComparisonResult{
+		DryRunRevision: ptr.To(dryRun.Revision),
+		ActualRevision: ptr.To(actual.Revision),
+		Items:          items,
+		Matched:        ptr.To(true),
+	}
+	for _, c := range items {
+		c.Matched = ptr.To(c.GetDryRunStatus() == c.GetActualStatus())
+		if !c.GetMatched() {
+			result.Matched = ptr.To(false)
+		}
+	}
+	return result
+}
+
+// fleetStatusCacheTTL is how long the fleet status computed for a user is cached
+const fleetStatusCacheTTL = 5 * time.Second
+
+// GetFleetStatus returns the number of applications per destination cluster, project, sync and health status. The
+// counts are computed from the application informer cache, so the destination clusters are never contacted.
+func (s *Server) GetFleetStatus(ctx context.Context, q *application.FleetStatusRequest) (*application.FleetStatus, error) {
+	projects := append([]string{}, q.GetProjects()...)
+	sort.Strings(projects)
+	// the counts only include the applications the user may get, so they are cached per user
+	cacheKey := strings.Join([]string{session.Iss(ctx), session.Sub(ctx), strings.Join(projects, ",")}, "|")
+	if fleetStatus, ok := s.fleetStatusCache.Get(cacheKey); ok {
+		return fleetStatus.(*application.FleetStatus), nil
+	}
+
+	apps, err := s.appLister.List(labels.Everything())
+	if err != nil {
+		return nil, fmt.Errorf("error listing apps: %w", err)
+	}
+	rollups := map[string]*application.ClusterRollup{}
+	for _, a := range argoutil.FilterByProjectsP(apps, projects) {
+		if !s.isNamespaceEnabled(a.Namespace) {
+			continue
+		}
+		if !s.enf.Enforce(ctx.Value("claims"), rbacpolicy.ResourceApplications, rbacpolicy.ActionGet, a.RBACName(s.ns)) {
+			continue
+		}
+		server := a.Spec.Destination.Server
+		if server == "" {
+			server = a.Spec.Destination.Name
+		}
+		rollup, ok := rollups[server]
+		if !ok {
+			rollup = &application.ClusterRollup{Server: ptr.To(server), AppCounts: map[string]int32{}}
+			rollups[server] = rollup
+		}
+		rollup.AppCounts[fleetStatusCountKey(a)]++
+	}
+
+	fleetStatus := &application.FleetStatus{Clusters: make([]*application.ClusterRollup, 0, len(rollups))}
+	for _, rollup := range rollups {
+		fleetStatus.Clusters = append(fleetStatus.Clusters, rollup)
+	}
+	sort.Slice(fleetStatus.Clusters, func(i, j int) bool {
+		return fleetStatus.Clusters[i].GetServer() < fleetStatus.Clusters[j].GetServer()
+	})
+	s.fleetStatusCache.SetDefault(cacheKey, fleetStatus)
+	return fleetStatus, nil
+}
+
+// fleetStatusCountKey returns the key under which the given application is counted in a ClusterRollup
+func fleetStatusCountKey(a *appv1.Application) string {
+	syncStatus := string(a.Status.Sync.Status)
+	if syncStatus == "" {
+		syncStatus = string(appv1.SyncStatusCodeUnknown)
+	}
+	healthStatus := string(a.Status.Health.Status)
+	if healthStatus == "" {
+		healthStatus = string(health.HealthStatusUnknown)
+	}
+	return fmt.Sprintf("%s/%s/%s", a.Spec.GetProject(), syncStatus, healthStatus)
+}
+
 func getAmbiguousRevision(app *appv1.Application, syncReq *application.ApplicationSyncRequest, sourceIndex int) string {
 	ambiguousRevision := ""
 	if app.Spec.HasMultipleSources() {
@@ -2223,15 +3013,18 @@ func (s *Server) resolveRevision(ctx context.Context, app *appv1.Application, sy
 	if syncReq.Manifests != nil {
 		return "", "", nil
 	}
-
-	ambiguousRevision := getAmbiguousRevision(app, syncReq, sourceIndex)
-
-	repoUrl := app.Spec.GetSource().RepoURL
+	// inline manifests and the manifests of HTTP and OCI sources are not versioned in a Git or Helm repository
+	source := app.Spec.GetSource()
 	if app.Spec.HasMultipleSources() {
-		repoUrl = app.Spec.Sources[sourceIndex].RepoURL
+		source = app.Spec.Sources[sourceIndex]
 	}
+	if source.IsInline() || source.IsHTTP() || source.IsOCI() {
+		return "", "", nil
+	}
+
+	ambiguousRevision := getAmbiguousRevision(app, syncReq, sourceIndex)
 
-	repo, err := s.db.GetRepository(ctx, repoUrl, app.Spec.Project)
+	repo, err := s.db.GetRepository(ctx, source.RepoURL, app.Spec.Project)
 	if err != nil {
 		return "", "", fmt.Errorf("error getting repository by URL: %w", err)
 	}
@@ -2241,7 +3034,6 @@ func (s *Server) resolveRevision(ctx context.Context, app *appv1.Application, sy
 	}
 	defer ioutil.Close(conn)
 
-	source := app.Spec.GetSourcePtrByIndex(sourceIndex)
 	if !source.IsHelm() {
 		if git.IsCommitSHA(ambiguousRevision) {
 			// If it's already a commit SHA, then no need to look it up
@@ -2283,7 +3075,7 @@ func (s *Server) TerminateOperation(ctx context.Context, termOpReq *application.
 		if !apierr.IsConflict(err) {
 			return nil, fmt.Errorf("error updating application: %w", err)
 		}
-		log.Warnf("failed to set operation for app %q due to update conflict. retrying again...", *termOpReq.Name)
+		grpc_util.LoggerFromContext(ctx).Warnf("failed to set operation for app %q due to update conflict. retrying again...", *termOpReq.Name)
 		time.Sleep(100 * time.Millisecond)
 		a, err = s.appclientset.ArgoprojV1alpha1().Applications(appNs).Get(ctx, appName, metav1.GetOptions{})
 		if err != nil {
@@ -2432,7 +3224,7 @@ func (s *Server) RunResourceAction(ctx context.Context, q *application.ResourceA
 		}
 	}
 
-	proj, err := s.getAppProject(ctx, a, log.WithField("application", a.Name))
+	proj, err := s.getAppProject(ctx, a, grpc_util.LoggerFromContext(ctx).WithField("application", a.Name))
 	if err != nil {
 		return nil, err
 	}
@@ -2,10 +2,12 @@ package application
 
 import (
 	"context"
+	"encoding/json"
 	coreerrors "errors"
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -14,12 +16,14 @@ import (
 
 	"k8s.io/apimachinery/pkg/labels"
 
+	"github.com/alicebob/miniredis/v2"
 	"github.com/argoproj/gitops-engine/pkg/health"
 	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
 	"github.com/argoproj/gitops-engine/pkg/utils/kube"
 	"github.com/argoproj/gitops-engine/pkg/utils/kube/kubetest"
 	"github.com/argoproj/pkg/sync"
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
@@ -30,6 +34,7 @@ import (
 	k8sbatchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -63,6 +68,7 @@ import (
 	"github.com/argoproj/argo-cd/v2/util/errors"
 	"github.com/argoproj/argo-cd/v2/util/grpc"
 	"github.com/argoproj/argo-cd/v2/util/rbac"
+	"github.com/argoproj/argo-cd/v2/util/session"
 	"github.com/argoproj/argo-cd/v2/util/settings"
 )
 
@@ -119,6 +125,11 @@ func fakeRepoServerClient(isHelm bool) *mocks.RepoServerServiceClient {
 	mockWithFilesClient.On("CloseAndRecv").Return(&apiclient.ManifestResponse{}, nil)
 	mockRepoServiceClient.On("GenerateManifestWithFiles", mock.Anything, mock.Anything).Return(mockWithFilesClient, nil)
 	mockRepoServiceClient.On("GetRevisionChartDetails", mock.Anything, mock.Anything).Return(&appsv1.ChartDetails{}, nil)
+	mockRepoServiceClient.On("ProfileManifestGeneration", mock.Anything, mock.Anything).Return(&apiclient.ProfileResult{DurationMs: 100, PeakMemoryBytes: 1024}, nil)
+	mockRepoServiceClient.On("GetManifestBlame", mock.Anything, mock.Anything).Return(&apiclient.ManifestBlame{
+		Revision: "abc123",
+		Files:    []*apiclient.ManifestFileBlame{{FilePath: "cm.yaml", LastCommitAuthor: "Alice <alice@example.com>", LastCommitTime: 1700000000, LastCommitMessage: "Add config map"}},
+	}, nil)
 
 	if isHelm {
 		mockRepoServiceClient.On("ResolveRevision", mock.Anything, mock.Anything).Return(fakeResolveRevisionResponseHelm(), nil)
@@ -653,6 +664,38 @@ func (t *TestResourceTreeServer) RecvMsg(m interface{}) error {
 	return nil
 }
 
+type TestResourceTreeLevelServer struct {
+	ctx    context.Context
+	levels []*application.ResourceTreeLevel
+}
+
+func (t *TestResourceTreeLevelServer) Send(level *application.ResourceTreeLevel) error {
+	t.levels = append(t.levels, level)
+	return nil
+}
+
+func (t *TestResourceTreeLevelServer) SetHeader(metadata.MD) error {
+	return nil
+}
+
+func (t *TestResourceTreeLevelServer) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func (t *TestResourceTreeLevelServer) SetTrailer(metadata.MD) {}
+
+func (t *TestResourceTreeLevelServer) Context() context.Context {
+	return t.ctx
+}
+
+func (t *TestResourceTreeLevelServer) SendMsg(m interface{}) error {
+	return nil
+}
+
+func (t *TestResourceTreeLevelServer) RecvMsg(m interface{}) error {
+	return nil
+}
+
 type TestPodLogsServer struct {
 	ctx context.Context
 }
@@ -822,6 +865,17 @@ func TestNoAppEnumeration(t *testing.T) {
 		assert.Equal(t, "rpc error: code = NotFound desc = applications.argoproj.io \"doest-not-exist\" not found", err.Error(), "when the request specifies a project, we can return the standard k8s error message")
 	})
 
+	t.Run("ProfileManifests", func(t *testing.T) {
+		_, err := appServer.ProfileManifests(adminCtx, &application.ApplicationManifestQuery{Name: ptr.To("test")})
+		require.NoError(t, err)
+		_, err = appServer.ProfileManifests(noRoleCtx, &application.ApplicationManifestQuery{Name: ptr.To("test")})
+		assert.Equal(t, permissionDeniedErr.Error(), err.Error(), "error message must be _only_ the permission error, to avoid leaking information about app existence")
+		_, err = appServer.ProfileManifests(adminCtx, &application.ApplicationManifestQuery{Name: ptr.To("doest-not-exist")})
+		assert.Equal(t, permissionDeniedErr.Error(), err.Error(), "error message must be _only_ the permission error, to avoid leaking information about app existence")
+		_, err = appServer.ProfileManifests(adminCtx, &application.ApplicationManifestQuery{Name: ptr.To("doest-not-exist"), Project: ptr.To("test")})
+		assert.Equal(t, "rpc error: code = NotFound desc = applications.argoproj.io \"doest-not-exist\" not found", err.Error(), "when the request specifies a project, we can return the standard k8s error message")
+	})
+
 	t.Run("ListResourceEvents", func(t *testing.T) {
 		_, err := appServer.ListResourceEvents(adminCtx, &application.ApplicationResourceEventsQuery{Name: ptr.To("test")})
 		require.NoError(t, err)
@@ -1046,6 +1100,17 @@ func TestNoAppEnumeration(t *testing.T) {
 		assert.Equal(t, "rpc error: code = NotFound desc = applications.argoproj.io \"does-not-exist\" not found", err.Error(), "when the request specifies a project, we can return the standard k8s error message")
 	})
 
+	t.Run("StreamApplicationResourceTree", func(t *testing.T) {
+		err := appServer.StreamApplicationResourceTree(&application.ResourceTreeStreamQuery{ApplicationName: ptr.To("test")}, &TestResourceTreeLevelServer{ctx: adminCtx})
+		require.NoError(t, err)
+		err = appServer.StreamApplicationResourceTree(&application.ResourceTreeStreamQuery{ApplicationName: ptr.To("test")}, &TestResourceTreeLevelServer{ctx: noRoleCtx})
+		assert.Equal(t, permissionDeniedErr.Error(), err.Error(), "error message must be _only_ the permission error, to avoid leaking information about app existence")
+		err = appServer.StreamApplicationResourceTree(&application.ResourceTreeStreamQuery{ApplicationName: ptr.To("does-not-exist")}, &TestResourceTreeLevelServer{ctx: adminCtx})
+		assert.Equal(t, permissionDeniedErr.Error(), err.Error(), "error message must be _only_ the permission error, to avoid leaking information about app existence")
+		err = appServer.StreamApplicationResourceTree(&application.ResourceTreeStreamQuery{ApplicationName: ptr.To("does-not-exist"), Project: ptr.To("test")}, &TestResourceTreeLevelServer{ctx: adminCtx})
+		assert.Equal(t, "rpc error: code = NotFound desc = applications.argoproj.io \"does-not-exist\" not found", err.Error(), "when the request specifies a project, we can return the standard k8s error message")
+	})
+
 	t.Run("PodLogs", func(t *testing.T) {
 		err := appServer.PodLogs(&application.ApplicationPodLogsQuery{Name: ptr.To("test")}, &TestPodLogsServer{ctx: adminCtx})
 		require.NoError(t, err)
@@ -1802,6 +1867,115 @@ func TestSyncAndTerminate(t *testing.T) {
 	assert.Equal(t, synccommon.OperationTerminating, app.Status.OperationState.Phase)
 }
 
+func TestSyncDestinations(t *testing.T) {
+	ctx := context.Background()
+	appServer := newTestAppServer(t)
+	testApp := newTestApp()
+	testApp.Spec.Source.RepoURL = "https://github.com/argoproj/argo-cd.git"
+	app, err := appServer.Create(ctx, &application.ApplicationCreateRequest{Application: testApp})
+	require.NoError(t, err)
+
+	_, err = appServer.Sync(ctx, &application.ApplicationSyncRequest{Name: &app.Name, Destinations: []*appsv1.ApplicationDestination{{Server: "https://other-cluster.example.com"}}})
+	require.Error(t, err)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	app, err = appServer.Sync(ctx, &application.ApplicationSyncRequest{Name: &app.Name, Destinations: []*appsv1.ApplicationDestination{{Server: testApp.Spec.Destination.Server, Namespace: test.FakeDestNamespace}}})
+	require.NoError(t, err)
+	assert.Equal(t, []appsv1.ApplicationDestination{{Server: testApp.Spec.Destination.Server, Namespace: test.FakeDestNamespace}}, app.Operation.Sync.Destinations)
+}
+
+func TestSyncOverrideSyncWindow(t *testing.T) {
+	denyWindow := &appsv1.SyncWindow{
+		Kind:          "deny",
+		Schedule:      "* * * * *",
+		Duration:      "1h",
+		Applications:  []string{"*"},
+		AllowOverride: true,
+	}
+	proj := &appsv1.AppProject{
+		ObjectMeta: metav1.ObjectMeta{Name: "proj-frozen", Namespace: testNamespace},
+		Spec: appsv1.AppProjectSpec{
+			SourceRepos:  []string{"*"},
+			Destinations: []appsv1.ApplicationDestination{{Server: "*", Namespace: "*"}},
+			SyncWindows:  appsv1.SyncWindows{denyWindow},
+		},
+	}
+	testApp := newTestApp(func(app *appsv1.Application) {
+		app.Spec.Project = "proj-frozen"
+	})
+	// nolint:staticcheck
+	ctx := context.WithValue(context.Background(), "claims", &jwt.RegisteredClaims{Subject: "admin", Issuer: session.SessionManagerClaimsIssuer})
+	syncReq := func(override bool, reason string) *application.ApplicationSyncRequest {
+		return &application.ApplicationSyncRequest{Name: &testApp.Name, OverrideSyncWindow: &override, OverrideReason: &reason}
+	}
+
+	t.Run("blocked without override", func(t *testing.T) {
+		appServer := newTestAppServer(t, testApp, proj)
+		_, err := appServer.Sync(ctx, syncReq(false, ""))
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("override requires a reason", func(t *testing.T) {
+		appServer := newTestAppServer(t, testApp, proj)
+		_, err := appServer.Sync(ctx, syncReq(true, ""))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("non-admin users cannot override", func(t *testing.T) {
+		f := func(enf *rbac.Enforcer) {
+			_ = enf.SetBuiltinPolicy(assets.BuiltinPolicyCSV)
+			_ = enf.SetUserPolicy("p, role:test, applications, get, */*, allow\np, role:test, applications, sync, */*, allow")
+			enf.SetDefaultRole("role:test")
+		}
+		appServer := newTestAppServerWithEnforcerConfigure(f, t, map[string]string{}, testApp, proj)
+		// nolint:staticcheck
+		ctx := context.WithValue(context.Background(), "claims", &jwt.RegisteredClaims{Subject: "test-user", Issuer: session.SessionManagerClaimsIssuer})
+		_, err := appServer.Sync(ctx, syncReq(true, "security patch"))
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = appServer.kubeclientset.CoreV1().ConfigMaps(testNamespace).Get(ctx, "argocd-sync-window-overrides", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("windows which do not allow overrides cannot be overridden", func(t *testing.T) {
+		proj := proj.DeepCopy()
+		proj.Spec.SyncWindows[0].AllowOverride = false
+		appServer := newTestAppServer(t, testApp, proj)
+		_, err := appServer.Sync(ctx, syncReq(true, "security patch"))
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("admins can override and overrides are recorded", func(t *testing.T) {
+		appServer := newTestAppServer(t, testApp, proj)
+		app, err := appServer.Sync(ctx, syncReq(true, "security patch"))
+		require.NoError(t, err)
+		require.NotNil(t, app.Operation)
+		assert.Equal(t, "security patch", app.Operation.Sync.SyncWindowOverrideReason)
+
+		cm, err := appServer.kubeclientset.CoreV1().ConfigMaps(testNamespace).Get(ctx, "argocd-sync-window-overrides", metav1.GetOptions{})
+		require.NoError(t, err)
+		var records []argo.SyncWindowOverrideRecord
+		require.NoError(t, json.Unmarshal([]byte(cm.Data[testNamespace+"_test-app"]), &records))
+		require.Len(t, records, 1)
+		assert.Equal(t, "admin", records[0].User)
+		assert.Equal(t, "security patch", records[0].Reason)
+		assert.Equal(t, "proj-frozen", records[0].Project)
+
+		// overrides are rejected during the cooldown
+		_, err = appServer.Sync(ctx, syncReq(true, "another patch"))
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		assert.ErrorContains(t, err, "overridden by admin")
+	})
+
+	t.Run("overrides are possible again after the cooldown", func(t *testing.T) {
+		appServer := newTestAppServer(t, testApp, proj)
+		now := time.Now()
+		require.NoError(t, appServer.recordSyncWindowOverride(ctx, testApp, "admin", "security patch", now.Add(-2*time.Hour)))
+		err := appServer.recordSyncWindowOverride(ctx, testApp, "admin", "another patch", now.Add(-90*time.Minute))
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		require.NoError(t, appServer.recordSyncWindowOverride(ctx, testApp, "admin", "another patch", now))
+	})
+}
+
 func TestSyncHelm(t *testing.T) {
 	ctx := context.Background()
 	appServer := newTestAppServer(t)
@@ -2010,6 +2184,97 @@ func TestServer_GetApplicationSyncWindowsState(t *testing.T) {
 	})
 }
 
+type TestWatchServer struct {
+	ctx    context.Context
+	events chan *appsv1.ApplicationWatchEvent
+}
+
+func (t *TestWatchServer) Send(event *appsv1.ApplicationWatchEvent) error {
+	t.events <- event
+	return nil
+}
+
+func (t *TestWatchServer) SetHeader(metadata.MD) error {
+	return nil
+}
+
+func (t *TestWatchServer) SendHeader(metadata.MD) error {
+	return nil
+}
+
+func (t *TestWatchServer) SetTrailer(metadata.MD) {}
+
+func (t *TestWatchServer) Context() context.Context {
+	return t.ctx
+}
+
+func (t *TestWatchServer) SendMsg(m interface{}) error {
+	return nil
+}
+
+func (t *TestWatchServer) RecvMsg(m interface{}) error {
+	return nil
+}
+
+func TestWatch_AppStatusChanged(t *testing.T) {
+	testApp := newTestApp()
+	testApp.ResourceVersion = "1"
+	appServer := newTestAppServer(t, testApp)
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+	defer mr.Close()
+	redisClient := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer redisClient.Close()
+	appStateCache := appstate.NewCache(cacheutil.NewCache(cacheutil.NewRedisCache(redisClient, time.Hour, cacheutil.RedisCompressionNone)), time.Hour)
+	appServer.cache = servercache.NewCache(appStateCache, time.Hour, time.Hour, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ws := &TestWatchServer{ctx: ctx, events: make(chan *appsv1.ApplicationWatchEvent, 10)}
+	done := make(chan error, 1)
+	go func() {
+		done <- appServer.Watch(&application.ApplicationQuery{Name: ptr.To("test-app")}, ws)
+	}()
+	receive := func() *appsv1.ApplicationWatchEvent {
+		select {
+		case event := <-ws.events:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("no event received")
+			return nil
+		}
+	}
+	// the application is listed, then added by the broadcaster
+	assert.Equal(t, watch.Added, receive().Type)
+	assert.Equal(t, watch.Added, receive().Type)
+
+	// the status update of the application controller is received without waiting for the informer
+	updated := testApp.DeepCopy()
+	updated.ResourceVersion = "3"
+	updated.Status.Health.Status = health.HealthStatusDegraded
+	_, err = appServer.appclientset.ArgoprojV1alpha1().Applications(testNamespace).Update(context.Background(), updated, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	channel := fmt.Sprintf("argocd:app-status:test-app|%s", common.CacheVersion)
+	require.Eventually(t, func() bool {
+		return mr.PubSubNumSub(channel)[channel] == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, appStateCache.NotifyAppStatusChanged("test-app"))
+	event := receive()
+	assert.Equal(t, watch.Modified, event.Type)
+	assert.Equal(t, health.HealthStatusDegraded, event.Application.Status.Health.Status)
+
+	// the same version is not sent again
+	require.NoError(t, appStateCache.NotifyAppStatusChanged("test-app"))
+	select {
+	case event := <-ws.events:
+		t.Fatalf("unexpected event %s of version %s", event.Type, event.Application.ResourceVersion)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
 func TestGetCachedAppState(t *testing.T) {
 	testApp := newTestApp()
 	testApp.ObjectMeta.ResourceVersion = "1"
@@ -3289,3 +3554,531 @@ func Test_RevisionMetadata(t *testing.T) {
 		})
 	}
 }
+
+func TestGetResourceHealthTimeline(t *testing.T) {
+	testApp := newTestApp()
+	appServer := newTestAppServer(t, testApp)
+	appStateCache := appstate.NewCache(appServer.cache.GetCache(), time.Hour)
+	key := kube.NewResourceKey("apps", "Deployment", testNamespace, "guestbook")
+	now := time.Now().UTC().Truncate(time.Second)
+	for i, status := range []health.HealthStatusCode{health.HealthStatusProgressing, health.HealthStatusHealthy, health.HealthStatusDegraded} {
+		event := appstate.ResourceHealthEvent{Timestamp: now.Add(time.Duration(i) * time.Minute), Status: status}
+		require.NoError(t, appStateCache.AddResourceHealthEvent(testApp.InstanceName(testNamespace), key, event, time.Hour))
+	}
+	request := func(limit, offset int64) *application.ResourceHealthTimelineRequest {
+		return &application.ResourceHealthTimelineRequest{
+			Name:         ptr.To(testApp.Name),
+			Namespace:    ptr.To(testNamespace),
+			Group:        ptr.To("apps"),
+			Kind:         ptr.To("Deployment"),
+			ResourceName: ptr.To("guestbook"),
+			Limit:        ptr.To(limit),
+			Offset:       ptr.To(offset),
+		}
+	}
+	statuses := func(timeline *application.ResourceHealthTimeline) []string {
+		var res []string
+		for _, event := range timeline.Events {
+			res = append(res, event.GetStatus())
+		}
+		return res
+	}
+
+	t.Run("AllEvents", func(t *testing.T) {
+		timeline, err := appServer.GetResourceHealthTimeline(context.Background(), request(0, 0))
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), timeline.GetTotal())
+		assert.Equal(t, []string{"Degraded", "Healthy", "Progressing"}, statuses(timeline))
+		assert.Equal(t, now.Add(2*time.Minute), timeline.Events[0].Timestamp.Time.UTC())
+		assert.Equal(t, "guestbook", timeline.Events[0].ResourceRef.Name)
+	})
+
+	t.Run("Paginated", func(t *testing.T) {
+		timeline, err := appServer.GetResourceHealthTimeline(context.Background(), request(1, 1))
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), timeline.GetTotal())
+		assert.Equal(t, []string{"Healthy"}, statuses(timeline))
+	})
+
+	t.Run("NoEvents", func(t *testing.T) {
+		req := request(0, 0)
+		req.ResourceName = ptr.To("other")
+		timeline, err := appServer.GetResourceHealthTimeline(context.Background(), req)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), timeline.GetTotal())
+		assert.Empty(t, timeline.Events)
+	})
+
+	t.Run("NegativeLimit", func(t *testing.T) {
+		_, err := appServer.GetResourceHealthTimeline(context.Background(), request(-1, 0))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestGetPreSyncSnapshot(t *testing.T) {
+	testApp := newTestApp()
+	appServer := newTestAppServer(t, testApp)
+	appStateCache := appstate.NewCache(appServer.cache.GetCache(), time.Hour)
+	now := time.Now().UTC().Truncate(time.Second)
+	ref := appv1.ResourceRef{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: testNamespace, Name: "guestbook"}
+	snapshot := &appstate.ResourceSnapshot{
+		CreatedAt: now,
+		Resources: []appstate.ResourceSnapshotEntry{{ResourceRef: ref, LiveManifest: `{"kind":"Deployment"}`, ResourceVersion: "123"}},
+	}
+	require.NoError(t, appStateCache.SetPreSyncSnapshot(testApp.InstanceName(testNamespace), "00001-abcde", snapshot, time.Hour))
+
+	t.Run("Found", func(t *testing.T) {
+		res, err := appServer.GetPreSyncSnapshot(context.Background(), &application.PreSyncSnapshotRequest{Name: ptr.To(testApp.Name), SyncId: ptr.To("00001-abcde")})
+		require.NoError(t, err)
+		assert.Equal(t, "00001-abcde", res.GetSyncId())
+		assert.Equal(t, now, res.CreatedAt.Time.UTC())
+		require.Len(t, res.Resources, 1)
+		assert.Equal(t, ref, *res.Resources[0].ResourceRef)
+		assert.Equal(t, `{"kind":"Deployment"}`, res.Resources[0].GetLiveManifest())
+		assert.Equal(t, "123", res.Resources[0].GetResourceVersion())
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := appServer.GetPreSyncSnapshot(context.Background(), &application.PreSyncSnapshotRequest{Name: ptr.To(testApp.Name), SyncId: ptr.To("00002-fghij")})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("EmptySyncID", func(t *testing.T) {
+		_, err := appServer.GetPreSyncSnapshot(context.Background(), &application.PreSyncSnapshotRequest{Name: ptr.To(testApp.Name)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestGetFleetStatus(t *testing.T) {
+	servers := []string{"https://cluster-1", "https://cluster-2", "https://cluster-3", "https://cluster-4"}
+	projects := []string{"proj-a", "proj-b", "proj-c"}
+	syncStatuses := []appsv1.SyncStatusCode{appsv1.SyncStatusCodeSynced, appsv1.SyncStatusCodeOutOfSync, ""}
+	healthStatuses := []health.HealthStatusCode{health.HealthStatusHealthy, health.HealthStatusDegraded, health.HealthStatusProgressing, ""}
+
+	var objects []runtime.Object
+	expected := map[string]map[string]int32{}
+	for i := 0; i < 200; i++ {
+		server := servers[i%len(servers)]
+		project := projects[i%len(projects)]
+		syncStatus := syncStatuses[i%len(syncStatuses)]
+		healthStatus := healthStatuses[i%len(healthStatuses)]
+		objects = append(objects, newTestApp(func(app *appsv1.Application) {
+			app.Name = fmt.Sprintf("app-%d", i)
+			app.Spec.Project = project
+			app.Spec.Destination.Server = server
+			app.Status.Sync.Status = syncStatus
+			app.Status.Health.Status = healthStatus
+		}))
+		if syncStatus == "" {
+			syncStatus = appsv1.SyncStatusCodeUnknown
+		}
+		if healthStatus == "" {
+			healthStatus = health.HealthStatusUnknown
+		}
+		if expected[server] == nil {
+			expected[server] = map[string]int32{}
+		}
+		expected[server][fmt.Sprintf("%s/%s/%s", project, syncStatus, healthStatus)]++
+	}
+
+	t.Run("AllApps", func(t *testing.T) {
+		appServer := newTestAppServer(t, objects...)
+		fleetStatus, err := appServer.GetFleetStatus(context.Background(), &application.FleetStatusRequest{})
+		require.NoError(t, err)
+
+		require.Len(t, fleetStatus.Clusters, len(servers))
+		total := int32(0)
+		for i, rollup := range fleetStatus.Clusters {
+			assert.Equal(t, servers[i], rollup.GetServer())
+			assert.Equal(t, expected[rollup.GetServer()], rollup.AppCounts)
+			for _, count := range rollup.AppCounts {
+				total += count
+			}
+		}
+		assert.Equal(t, int32(200), total)
+	})
+
+	t.Run("FilteredByProject", func(t *testing.T) {
+		appServer := newTestAppServer(t, objects...)
+		fleetStatus, err := appServer.GetFleetStatus(context.Background(), &application.FleetStatusRequest{Projects: []string{"proj-b"}})
+		require.NoError(t, err)
+
+		total := int32(0)
+		for _, rollup := range fleetStatus.Clusters {
+			for key, count := range rollup.AppCounts {
+				assert.True(t, strings.HasPrefix(key, "proj-b/"), key)
+				total += count
+			}
+		}
+		assert.Equal(t, int32(67), total)
+	})
+
+	t.Run("FilteredByRBAC", func(t *testing.T) {
+		f := func(enf *rbac.Enforcer) {
+			_ = enf.SetUserPolicy("p, role:test, applications, get, proj-c/*, allow")
+			enf.SetDefaultRole("role:test")
+		}
+		appServer := newTestAppServerWithEnforcerConfigure(f, t, map[string]string{}, objects...)
+		fleetStatus, err := appServer.GetFleetStatus(context.Background(), &application.FleetStatusRequest{})
+		require.NoError(t, err)
+
+		total := int32(0)
+		for _, rollup := range fleetStatus.Clusters {
+			for key, count := range rollup.AppCounts {
+				assert.True(t, strings.HasPrefix(key, "proj-c/"), key)
+				total += count
+			}
+		}
+		assert.Equal(t, int32(66), total)
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		appServer := newTestAppServer(t, objects...)
+		first, err := appServer.GetFleetStatus(context.Background(), &application.FleetStatusRequest{Projects: []string{"proj-b", "proj-a"}})
+		require.NoError(t, err)
+		second, err := appServer.GetFleetStatus(context.Background(), &application.FleetStatusRequest{Projects: []string{"proj-a", "proj-b"}})
+		require.NoError(t, err)
+		assert.Same(t, first, second)
+		assert.Equal(t, 1, appServer.fleetStatusCache.ItemCount())
+	})
+}
+
+func TestDryRunResult(t *testing.T) {
+	syncState := func(dryRun bool, resources ...*appsv1.ResourceResult) *appsv1.OperationState {
+		return &appsv1.OperationState{
+			Operation:  appsv1.Operation{Sync: &appsv1.SyncOperation{DryRun: dryRun}},
+			Phase:      synccommon.OperationSucceeded,
+			SyncResult: &appsv1.SyncOperationResult{Revision: "abc", Resources: resources},
+		}
+	}
+	dryRunApp := newTestApp(func(app *appsv1.Application) {
+		app.Status.OperationState = syncState(true, &appsv1.ResourceResult{Kind: "ConfigMap", Namespace: testNamespace, Name: "my-map", Status: synccommon.ResultCodeSynced})
+	})
+	syncedApp := newTestApp(func(app *appsv1.Application) {
+		app.Name = "synced"
+		app.Status.OperationState = syncState(false)
+	})
+	appServer := newTestAppServer(t, dryRunApp, syncedApp)
+
+	stored, err := appServer.StoreDryRunResult(context.Background(), &application.DryRunResultStoreRequest{Name: ptr.To(dryRunApp.Name)})
+	require.NoError(t, err)
+	require.NotEmpty(t, stored.GetId())
+
+	t.Run("Get", func(t *testing.T) {
+		result, err := appServer.GetDryRunResult(context.Background(), &application.DryRunResultQuery{Id: stored.Id})
+		require.NoError(t, err)
+		assert.Equal(t, dryRunApp.Name, result.GetAppName())
+		assert.Equal(t, dryRunApp.Spec.GetProject(), result.GetProject())
+		assert.Equal(t, dryRunApp.Status.OperationState.SyncResult, result.Result)
+	})
+
+	t.Run("GetUnknown", func(t *testing.T) {
+		_, err := appServer.GetDryRunResult(context.Background(), &application.DryRunResultQuery{Id: ptr.To("unknown")})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("StoreWithoutDryRun", func(t *testing.T) {
+		_, err := appServer.StoreDryRunResult(context.Background(), &application.DryRunResultStoreRequest{Name: ptr.To(syncedApp.Name)})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("CompareWithoutSync", func(t *testing.T) {
+		_, err := appServer.CompareDryRunToActual(context.Background(), &application.DryRunComparisonRequest{Id: stored.Id, Name: ptr.To(dryRunApp.Name)})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("CompareOtherApplication", func(t *testing.T) {
+		_, err := appServer.CompareDryRunToActual(context.Background(), &application.DryRunComparisonRequest{Id: stored.Id, Name: ptr.To(syncedApp.Name)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestCompareDryRunToActual(t *testing.T) {
+	dryRun := &appsv1.SyncOperationResult{
+		Revision: "abc",
+		Resources: appsv1.ResourceResults{
+			{Kind: "ConfigMap", Namespace: "default", Name: "same", Status: synccommon.ResultCodeSynced, Message: "configmap/same configured (dry run)"},
+			{Kind: "ConfigMap", Namespace: "default", Name: "failed", Status: synccommon.ResultCodeSynced, Message: "configmap/failed configured (dry run)"},
+			{Kind: "ConfigMap", Namespace: "default", Name: "dry-run-only", Status: synccommon.ResultCodePruned, Message: "pruned (dry run)"},
+		},
+	}
+	actual := &appsv1.SyncOperationResult{
+		Revision: "def",
+		Resources: appsv1.ResourceResults{
+			{Kind: "ConfigMap", Namespace: "default", Name: "same", Status: synccommon.ResultCodeSynced, Message: "configmap/same configured"},
+			{Kind: "ConfigMap", Namespace: "default", Name: "failed", Status: synccommon.ResultCodeSyncFailed, Message: "admission webhook denied the request"},
+			{Kind: "Job", Namespace: "default", Name: "hook", Status: synccommon.ResultCodeSynced, HookType: synccommon.HookTypePreSync},
+			{Group: "apps", Kind: "Deployment", Namespace: "default", Name: "actual-only", Status: synccommon.ResultCodeSynced},
+		},
+	}
+
+	result := compareDryRunToActual(dryRun, actual)
+
+	assert.False(t, result.GetMatched())
+	assert.Equal(t, "abc", result.GetDryRunRevision())
+	assert.Equal(t, "def", result.GetActualRevision())
+	matched := map[string]bool{}
+	for _, item := range result.Items {
+		matched[item.GetName()] = item.GetMatched()
+	}
+	assert.Equal(t, map[string]bool{"same": true, "failed": false, "dry-run-only": false, "actual-only": false}, matched)
+	assert.Equal(t, "admission webhook denied the request", result.Items[1].GetActualMessage())
+	assert.Equal(t, "configmap/failed configured (dry run)", result.Items[1].GetDryRunMessage())
+
+	result = compareDryRunToActual(dryRun, &appsv1.SyncOperationResult{Resources: dryRun.Resources[:1]})
+	assert.False(t, result.GetMatched())
+	result = compareDryRunToActual(&appsv1.SyncOperationResult{Resources: dryRun.Resources[:1]}, &appsv1.SyncOperationResult{Resources: actual.Resources[:1]})
+	assert.True(t, result.GetMatched())
+}
+
+func TestDryRunSyncFromSnapshot(t *testing.T) {
+	configMap := func(name string, tracked bool, value string) string {
+		labels := ""
+		if tracked {
+			labels = `,"labels":{"app.kubernetes.io/instance":"test-app"}`
+		}
+		return fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"%s","namespace":"%s"%s},"data":{"key":"%s"}}`, name, test.FakeDestNamespace, labels, value)
+	}
+	snapshot := `{"apiVersion":"v1","kind":"List","items":[` + strings.Join([]string{
+		configMap("same", true, "a"),
+		configMap("changed", true, "a"),
+		configMap("pruned", true, "a"),
+		configMap("unrelated", false, "a"),
+	}, ",") + `]}`
+	appServer := newTestAppServer(t, newTestApp())
+
+	t.Run("Modified", func(t *testing.T) {
+		result, err := appServer.DryRunSyncFromSnapshot(context.Background(), &application.DryRunSnapshotRequest{
+			Name:      ptr.To("test-app"),
+			Snapshot:  ptr.To(snapshot),
+			Manifests: []string{configMap("same", true, "a"), configMap("changed", true, "b"), configMap("created", true, "a")},
+		})
+		require.NoError(t, err)
+		assert.True(t, result.GetModified())
+		modified := map[string]bool{}
+		for _, item := range result.Items {
+			assert.Equal(t, test.FakeDestNamespace, item.Namespace)
+			modified[item.Name] = item.Modified
+		}
+		assert.Equal(t, map[string]bool{"same": false, "changed": true, "created": true, "pruned": true}, modified)
+	})
+
+	t.Run("NotModified", func(t *testing.T) {
+		result, err := appServer.DryRunSyncFromSnapshot(context.Background(), &application.DryRunSnapshotRequest{
+			Name:      ptr.To("test-app"),
+			Snapshot:  ptr.To("[" + configMap("same", true, "a") + "]"),
+			Manifests: []string{configMap("same", true, "a")},
+		})
+		require.NoError(t, err)
+		assert.False(t, result.GetModified())
+		require.Len(t, result.Items, 1)
+		assert.Contains(t, result.Items[0].LiveState, `"key":"a"`)
+	})
+
+	t.Run("InvalidSnapshot", func(t *testing.T) {
+		_, err := appServer.DryRunSyncFromSnapshot(context.Background(), &application.DryRunSnapshotRequest{
+			Name:      ptr.To("test-app"),
+			Snapshot:  ptr.To(`[{"apiVersion":"v1"}]`),
+			Manifests: []string{configMap("same", true, "a")},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServerSideDiff(t *testing.T) {
+	appServer := newTestAppServer(t, newTestApp())
+
+	t.Run("NoResources", func(t *testing.T) {
+		result, err := appServer.ServerSideDiff(context.Background(), &application.ApplicationServerSideDiffQuery{Name: ptr.To("test-app")})
+		require.NoError(t, err)
+		assert.False(t, result.GetModified())
+		assert.Empty(t, result.Items)
+	})
+
+	t.Run("UnknownApplication", func(t *testing.T) {
+		_, err := appServer.ServerSideDiff(context.Background(), &application.ApplicationServerSideDiffQuery{Name: ptr.To("unknown-app")})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func newTreeNode(kind string, name string, parents ...appsv1.ResourceRef) appsv1.ResourceNode {
+	return appsv1.ResourceNode{
+		ResourceRef: appsv1.ResourceRef{Kind: kind, Namespace: "default", Name: name},
+		ParentRefs:  parents,
+	}
+}
+
+func levelNodeNames(level *application.ResourceTreeLevel) []string {
+	var names []string
+	for _, node := range level.Nodes {
+		names = append(names, node.Kind+"/"+node.Name)
+	}
+	return names
+}
+
+func TestResourceTreeLevels(t *testing.T) {
+	deploy := newTreeNode("Deployment", "guestbook")
+	svc := newTreeNode("Service", "guestbook")
+	rs := newTreeNode("ReplicaSet", "guestbook-1", deploy.ResourceRef)
+	pod1 := newTreeNode("Pod", "guestbook-1-a", rs.ResourceRef)
+	pod2 := newTreeNode("Pod", "guestbook-1-b", rs.ResourceRef)
+	endpoints := newTreeNode("Endpoints", "guestbook", svc.ResourceRef)
+	// the parent of a node may be a node of a deeper level, and may not be in the tree
+	slice := newTreeNode("EndpointSlice", "guestbook-x", svc.ResourceRef, pod1.ResourceRef)
+	orphan := newTreeNode("ConfigMap", "leftover", appsv1.ResourceRef{Kind: "Deployment", Namespace: "default", Name: "deleted"})
+	// the children are listed before their parents in the tree
+	tree := &appsv1.ApplicationTree{Nodes: []appsv1.ResourceNode{pod2, pod1, slice, endpoints, rs, orphan, svc, deploy}}
+
+	t.Run("All", func(t *testing.T) {
+		levels := resourceTreeLevels(tree, nil, 0)
+		require.Len(t, levels, 3)
+		for i, level := range levels {
+			assert.Equal(t, int64(i), level.GetDepth())
+			assert.False(t, level.GetTruncated())
+		}
+		assert.Equal(t, []string{"ConfigMap/leftover", "Deployment/guestbook", "Service/guestbook"}, levelNodeNames(levels[0]))
+		assert.Equal(t, []string{"EndpointSlice/guestbook-x", "Endpoints/guestbook", "ReplicaSet/guestbook-1"}, levelNodeNames(levels[1]))
+		assert.Equal(t, []string{"Pod/guestbook-1-a", "Pod/guestbook-1-b"}, levelNodeNames(levels[2]))
+
+		// every node is sent once, at the level following its first parent in the tree
+		sentAt := map[string]int{}
+		for i, level := range levels {
+			for _, name := range levelNodeNames(level) {
+				_, duplicate := sentAt[name]
+				require.False(t, duplicate, name)
+				sentAt[name] = i
+			}
+		}
+		assert.Len(t, sentAt, len(tree.Nodes))
+		for _, node := range tree.Nodes {
+			expectedLevel := 0
+			for _, parent := range node.ParentRefs {
+				if parentLevel, ok := sentAt[parent.Kind+"/"+parent.Name]; ok && (expectedLevel == 0 || parentLevel+1 < expectedLevel) {
+					expectedLevel = parentLevel + 1
+				}
+			}
+			assert.Equal(t, expectedLevel, sentAt[node.Kind+"/"+node.Name], node.Name)
+		}
+	})
+
+	t.Run("MaxDepth", func(t *testing.T) {
+		levels := resourceTreeLevels(tree, ptr.To(int64(0)), 0)
+		require.Len(t, levels, 1)
+		assert.True(t, levels[0].GetTruncated())
+
+		levels = resourceTreeLevels(tree, ptr.To(int64(2)), 0)
+		require.Len(t, levels, 3)
+		assert.False(t, levels[2].GetTruncated())
+	})
+
+	t.Run("NodeLimit", func(t *testing.T) {
+		levels := resourceTreeLevels(tree, nil, 4)
+		require.Len(t, levels, 2)
+		assert.False(t, levels[0].GetTruncated())
+		assert.Equal(t, []string{"EndpointSlice/guestbook-x"}, levelNodeNames(levels[1]))
+		assert.True(t, levels[1].GetTruncated())
+
+		levels = resourceTreeLevels(tree, nil, 3)
+		require.Len(t, levels, 1)
+		assert.True(t, levels[0].GetTruncated())
+
+		levels = resourceTreeLevels(tree, nil, int64(len(tree.Nodes)))
+		require.Len(t, levels, 3)
+		assert.False(t, levels[2].GetTruncated())
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		assert.Empty(t, resourceTreeLevels(&appsv1.ApplicationTree{}, nil, 1))
+	})
+}
+
+func TestStreamApplicationResourceTree(t *testing.T) {
+	testApp := newTestApp()
+	appServer := newTestAppServer(t, testApp)
+	appStateCache := appstate.NewCache(cache.NewCache(cache.NewInMemoryCache(time.Hour)), time.Hour)
+	appServer.cache = servercache.NewCache(appStateCache, time.Minute, time.Minute, time.Minute)
+	deploy := newTreeNode("Deployment", "guestbook")
+	rs := newTreeNode("ReplicaSet", "guestbook-1", deploy.ResourceRef)
+	pod := newTreeNode("Pod", "guestbook-1-a", rs.ResourceRef)
+	require.NoError(t, appStateCache.SetAppResourcesTree(testApp.Name, &appsv1.ApplicationTree{Nodes: []appsv1.ResourceNode{pod, rs, deploy}}))
+
+	ws := &TestResourceTreeLevelServer{ctx: context.Background()}
+	err := appServer.StreamApplicationResourceTree(&application.ResourceTreeStreamQuery{ApplicationName: ptr.To(testApp.Name), MaxDepth: ptr.To(int64(1))}, ws)
+	require.NoError(t, err)
+	require.Len(t, ws.levels, 2)
+	assert.Equal(t, []string{"Deployment/guestbook"}, levelNodeNames(ws.levels[0]))
+	assert.Equal(t, []string{"ReplicaSet/guestbook-1"}, levelNodeNames(ws.levels[1]))
+	assert.True(t, ws.levels[1].GetTruncated())
+
+	err = appServer.StreamApplicationResourceTree(&application.ResourceTreeStreamQuery{ApplicationName: ptr.To(testApp.Name), NodeLimit: ptr.To(int64(-1))}, ws)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetManifestBlame(t *testing.T) {
+	// nolint:staticcheck
+	ctx := context.WithValue(context.Background(), "claims", &jwt.RegisteredClaims{Subject: "admin", Issuer: session.SessionManagerClaimsIssuer})
+
+	t.Run("blame is returned for each source", func(t *testing.T) {
+		testApp := newTestApp()
+		appServer := newTestAppServer(t, testApp)
+		res, err := appServer.GetManifestBlame(ctx, &application.ApplicationManifestQuery{Name: ptr.To(testApp.Name)})
+		require.NoError(t, err)
+		require.Len(t, res.Items, 1)
+		require.Len(t, res.Items[0].Files, 1)
+		assert.Equal(t, "cm.yaml", res.Items[0].Files[0].FilePath)
+		assert.Equal(t, "Alice <alice@example.com>", res.Items[0].Files[0].LastCommitAuthor)
+	})
+
+	t.Run("sources which are not git repositories are not blamed", func(t *testing.T) {
+		testApp := newTestApp(func(app *appsv1.Application) {
+			app.Spec.Source.Chart = "my-chart"
+		})
+		appServer := newTestAppServer(t, testApp)
+		res, err := appServer.GetManifestBlame(ctx, &application.ApplicationManifestQuery{Name: ptr.To(testApp.Name)})
+		require.NoError(t, err)
+		require.Len(t, res.Items, 1)
+		assert.Empty(t, res.Items[0].Files)
+	})
+
+	t.Run("application name is required", func(t *testing.T) {
+		appServer := newTestAppServer(t, newTestApp())
+		_, err := appServer.GetManifestBlame(ctx, &application.ApplicationManifestQuery{})
+		require.Error(t, err)
+	})
+}
+
+func TestProfileManifests(t *testing.T) {
+	testApp := newTestApp()
+	// nolint:staticcheck
+	ctx := context.WithValue(context.Background(), "claims", &jwt.RegisteredClaims{Subject: "admin", Issuer: session.SessionManagerClaimsIssuer})
+
+	t.Run("profiles are returned for each source", func(t *testing.T) {
+		appServer := newTestAppServer(t, testApp)
+		res, err := appServer.ProfileManifests(ctx, &application.ApplicationManifestQuery{Name: ptr.To(testApp.Name)})
+		require.NoError(t, err)
+		require.Len(t, res.Items, 1)
+		assert.Equal(t, int64(100), res.Items[0].DurationMs)
+		assert.Equal(t, int64(1024), res.Items[0].PeakMemoryBytes)
+	})
+
+	t.Run("users who can only get the application cannot profile it", func(t *testing.T) {
+		f := func(enf *rbac.Enforcer) {
+			_ = enf.SetBuiltinPolicy(assets.BuiltinPolicyCSV)
+			_ = enf.SetUserPolicy("p, role:test, applications, get, */*, allow")
+			enf.SetDefaultRole("role:test")
+		}
+		appServer := newTestAppServerWithEnforcerConfigure(f, t, map[string]string{}, testApp)
+		// nolint:staticcheck
+		ctx := context.WithValue(context.Background(), "claims", &jwt.RegisteredClaims{Subject: "test-user", Issuer: session.SessionManagerClaimsIssuer})
+		_, err := appServer.GetManifests(ctx, &application.ApplicationManifestQuery{Name: ptr.To(testApp.Name)})
+		require.NoError(t, err)
+		_, err = appServer.ProfileManifests(ctx, &application.ApplicationManifestQuery{Name: ptr.To(testApp.Name)})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("application name is required", func(t *testing.T) {
+		appServer := newTestAppServer(t, testApp)
+		_, err := appServer.ProfileManifests(ctx, &application.ApplicationManifestQuery{})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,77 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/server/rbacpolicy"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+	kubeutil "github.com/argoproj/argo-cd/v2/util/kube"
+)
+
+// CheckDeprecations returns the target resources of an application which use API versions deprecated in Kubernetes,
+// compared to the preferred API versions of the destination cluster
+func (s *Server) CheckDeprecations(ctx context.Context, q *application.DeprecatedAPIsQuery) (*application.DeprecatedAPIsResponse, error) {
+	a, _, err := s.getApplicationEnforceRBACInformer(ctx, rbacpolicy.ActionGet, q.GetProject(), q.GetAppNamespace(), q.GetName())
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*appv1.ResourceDiff, 0)
+	err = s.getCachedAppState(ctx, a, func() error {
+		return s.cache.GetAppManagedResources(a.InstanceName(s.ns), &items)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting cached app managed resources: %w", err)
+	}
+	var targets []*unstructured.Unstructured
+	for _, item := range items {
+		if item.TargetState == "" || item.TargetState == "null" {
+			continue
+		}
+		target := &unstructured.Unstructured{}
+		if err := json.Unmarshal([]byte(item.TargetState), target); err != nil {
+			return nil, fmt.Errorf("error unmarshaling target state of %s %s/%s: %w", item.Kind, item.Namespace, item.Name, err)
+		}
+		targets = append(targets, target)
+	}
+
+	if err := argo.ValidateDestination(ctx, &a.Spec.Destination, s.db); err != nil {
+		return nil, fmt.Errorf("error validating destination: %w", err)
+	}
+	clst, err := s.db.GetCluster(ctx, a.Spec.Destination.Server)
+	if err != nil {
+		return nil, fmt.Errorf("error getting cluster: %w", err)
+	}
+	kubeClientset, err := kubernetes.NewForConfig(clst.RESTConfig())
+	if err != nil {
+		return nil, fmt.Errorf("error creating kube client: %w", err)
+	}
+	preferredResources, err := kubeutil.GetServerPreferredResources(kubeClientset.Discovery())
+	if err != nil {
+		return nil, err
+	}
+
+	res := &application.DeprecatedAPIsResponse{}
+	for _, usage := range kubeutil.FindDeprecatedAPIs(targets, preferredResources) {
+		res.Items = append(res.Items, &application.DeprecatedAPIUsage{
+			Group:                 ptr.To(usage.GroupVersionKind.Group),
+			Version:               ptr.To(usage.GroupVersionKind.Version),
+			Kind:                  ptr.To(usage.GroupVersionKind.Kind),
+			Namespace:             ptr.To(usage.Namespace),
+			Name:                  ptr.To(usage.Name),
+			ReplacementAPIVersion: ptr.To(usage.ReplacementAPIVersion),
+			RemovedInVersion:      ptr.To(usage.RemovedInVersion),
+			Served:                ptr.To(usage.Served),
+			Message:               ptr.To(usage.String()),
+		})
+	}
+	return res, nil
+}
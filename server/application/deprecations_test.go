@@ -0,0 +1,79 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	appsv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	appstate "github.com/argoproj/argo-cd/v2/util/cache/appstate"
+)
+
+// newMockDiscoveryEndpoint returns a server serving the discovery API of a cluster which does not serve apps/v1beta1
+func newMockDiscoveryEndpoint(t *testing.T) *httptest.Server {
+	t.Helper()
+	responses := map[string]interface{}{
+		"/api": &metav1.APIVersions{Versions: []string{"v1"}},
+		"/apis": &metav1.APIGroupList{Groups: []metav1.APIGroup{{
+			Name:             "apps",
+			Versions:         []metav1.GroupVersionForDiscovery{{GroupVersion: "apps/v1", Version: "v1"}},
+			PreferredVersion: metav1.GroupVersionForDiscovery{GroupVersion: "apps/v1", Version: "v1"},
+		}}},
+		"/api/v1": &metav1.APIResourceList{GroupVersion: "v1", APIResources: []metav1.APIResource{
+			{Name: "configmaps", Kind: "ConfigMap", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+		}},
+		"/apis/apps/v1": &metav1.APIResourceList{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{
+			{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: metav1.Verbs{"get", "list"}},
+		}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res, ok := responses[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(res)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCheckDeprecations(t *testing.T) {
+	endpoint := newMockDiscoveryEndpoint(t)
+	testApp := newTestApp(func(app *appsv1.Application) {
+		app.Spec.Destination.Server = endpoint.URL
+	})
+	appServer := newTestAppServer(t, testApp)
+	_, err := appServer.db.CreateCluster(context.Background(), &appsv1.Cluster{Server: endpoint.URL, Name: "deprecations-cluster"})
+	require.NoError(t, err)
+
+	appStateCache := appstate.NewCache(appServer.cache.GetCache(), time.Hour)
+	require.NoError(t, appStateCache.SetAppManagedResources(testApp.InstanceName(testNamespace), []*appsv1.ResourceDiff{
+		{Group: "apps", Kind: "Deployment", Namespace: testNamespace, Name: "guestbook", TargetState: `{"apiVersion":"apps/v1beta1","kind":"Deployment","metadata":{"name":"guestbook","namespace":"` + testNamespace + `"}}`},
+		{Kind: "ConfigMap", Namespace: testNamespace, Name: "config", TargetState: `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"config","namespace":"` + testNamespace + `"}}`},
+		{Group: "apps", Kind: "Deployment", Namespace: testNamespace, Name: "pruned", TargetState: "null"},
+	}))
+
+	res, err := appServer.CheckDeprecations(context.Background(), &application.DeprecatedAPIsQuery{Name: ptr.To(testApp.Name)})
+	require.NoError(t, err)
+	require.Len(t, res.Items, 1)
+	item := res.Items[0]
+	assert.Equal(t, "apps", item.GetGroup())
+	assert.Equal(t, "v1beta1", item.GetVersion())
+	assert.Equal(t, "Deployment", item.GetKind())
+	assert.Equal(t, "guestbook", item.GetName())
+	assert.Equal(t, "apps/v1", item.GetReplacementAPIVersion())
+	assert.Equal(t, "v1.16", item.GetRemovedInVersion())
+	assert.False(t, item.GetServed())
+	assert.Equal(t, "Deployment "+testNamespace+"/guestbook uses API version apps/v1beta1, which is removed in Kubernetes v1.16 and not served by the cluster, use apps/v1 instead", item.GetMessage())
+}
@@ -0,0 +1,154 @@
+package application
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/argoproj/gitops-engine/pkg/diff"
+	"github.com/argoproj/gitops-engine/pkg/sync"
+	"github.com/argoproj/gitops-engine/pkg/sync/hook"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/argoproj/gitops-engine/pkg/utils/text"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	argoutil "github.com/argoproj/argo-cd/v2/util/argo"
+	argodiff "github.com/argoproj/argo-cd/v2/util/argo/diff"
+)
+
+// parseClusterSnapshot parses a cluster state snapshot, which is either a JSON encoded list such as the output of
+// `kubectl get -o json`, or a JSON array of resources
+func parseClusterSnapshot(data []byte) ([]*unstructured.Unstructured, error) {
+	var items []map[string]interface{}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &items); err != nil {
+			return nil, fmt.Errorf("error unmarshaling cluster snapshot: %w", err)
+		}
+	} else {
+		list := struct {
+			Items []map[string]interface{} `json:"items"`
+		}{}
+		if err := json.Unmarshal(trimmed, &list); err != nil {
+			return nil, fmt.Errorf("error unmarshaling cluster snapshot: %w", err)
+		}
+		items = list.Items
+	}
+	objs := make([]*unstructured.Unstructured, 0, len(items))
+	for i, item := range items {
+		obj := &unstructured.Unstructured{Object: item}
+		if obj.GetKind() == "" || obj.GetName() == "" {
+			return nil, fmt.Errorf("resource %d of the cluster snapshot has no kind or name", i)
+		}
+		// Live resources are deduplicated by UID during reconciliation, so hand written snapshots without UIDs get a
+		// unique one derived from the resource key
+		if obj.GetUID() == "" {
+			key := kube.GetResourceKey(obj)
+			obj.SetUID(types.UID(key.String()))
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+// snapshotResourceInfo provides the scope of the resource kinds found in a cluster snapshot
+type snapshotResourceInfo map[schema.GroupKind]bool
+
+func newSnapshotResourceInfo(snapshot []*unstructured.Unstructured) snapshotResourceInfo {
+	info := snapshotResourceInfo{}
+	for _, obj := range snapshot {
+		info[obj.GroupVersionKind().GroupKind()] = obj.GetNamespace() != ""
+	}
+	return info
+}
+
+// IsNamespaced returns whether the resources of the given kind are namespaced, or an error if the snapshot has no
+// resource of that kind
+func (i snapshotResourceInfo) IsNamespaced(gk schema.GroupKind) (bool, error) {
+	namespaced, ok := i[gk]
+	if !ok {
+		return false, fmt.Errorf("scope of %s is unknown", gk.String())
+	}
+	return namespaced, nil
+}
+
+// snapshotDiffs compares the target manifests of an application to the live state of the given cluster snapshot. The
+// live state consists of the resources of the snapshot tracked as part of the application, and of the resources of the
+// target manifests. Hooks are ignored.
+func snapshotDiffs(app *appv1.Application, appInstanceName string, targets, snapshot []*unstructured.Unstructured, appLabelKey string, trackingMethod appv1.TrackingMethod, diffConfig argodiff.DiffConfig) ([]*appv1.ResourceDiff, error) {
+	resourceTracking := argoutil.NewResourceTracking()
+	namespace := app.Spec.Destination.Namespace
+	snapshotByKey := make(map[kube.ResourceKey]*unstructured.Unstructured)
+	liveObjByKey := make(map[kube.ResourceKey]*unstructured.Unstructured)
+	for _, obj := range snapshot {
+		key := kube.GetResourceKey(obj)
+		snapshotByKey[key] = obj
+		if resourceTracking.GetAppName(obj, appLabelKey, trackingMethod) == appInstanceName {
+			liveObjByKey[key] = obj
+		}
+	}
+	for _, obj := range targets {
+		gvk := obj.GroupVersionKind()
+		for _, ns := range []string{text.FirstNonEmpty(obj.GetNamespace(), namespace), ""} {
+			key := kube.NewResourceKey(gvk.Group, gvk.Kind, ns, obj.GetName())
+			if live, ok := snapshotByKey[key]; ok {
+				liveObjByKey[key] = live
+			}
+		}
+	}
+
+	resInfo := newSnapshotResourceInfo(snapshot)
+	reconciliation := sync.Reconcile(targets, liveObjByKey, namespace, resInfo)
+	items := make([]*appv1.ResourceDiff, 0, len(reconciliation.Target))
+	for i := range reconciliation.Target {
+		target := reconciliation.Target[i]
+		live := reconciliation.Live[i]
+		if target != nil && hook.IsHook(target) || live != nil && hook.IsHook(live) {
+			continue
+		}
+		obj := target
+		if obj == nil {
+			obj = live
+		}
+		gvk := obj.GroupVersionKind()
+		item := &appv1.ResourceDiff{
+			Group: gvk.Group,
+			Kind:  gvk.Kind,
+			Name:  obj.GetName(),
+		}
+		if live != nil {
+			item.Namespace = live.GetNamespace()
+		} else if namespaced, err := resInfo.IsNamespaced(gvk.GroupKind()); err != nil || namespaced {
+			item.Namespace = text.FirstNonEmpty(target.GetNamespace(), namespace)
+		}
+		if item.Kind == kube.SecretKind && item.Group == "" {
+			var err error
+			target, live, err = diff.HideSecretData(target, live)
+			if err != nil {
+				return nil, fmt.Errorf("error hiding secret data: %w", err)
+			}
+		}
+		diffRes, err := argodiff.StateDiff(live, target, diffConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error diffing %s: %w", item.Name, err)
+		}
+		liveState, err := json.Marshal(live)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling live state: %w", err)
+		}
+		targetState, err := json.Marshal(target)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling target state: %w", err)
+		}
+		item.LiveState = string(liveState)
+		item.TargetState = string(targetState)
+		item.NormalizedLiveState = string(diffRes.NormalizedLive)
+		item.PredictedLiveState = string(diffRes.PredictedLive)
+		// Resources missing from the snapshot would be created, and resources missing from the manifests pruned
+		item.Modified = diffRes.Modified || target == nil || live == nil
+		items = append(items, item)
+	}
+	return items, nil
+}
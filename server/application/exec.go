@@ -0,0 +1,231 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/server/rbacpolicy"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+	sessionmgr "github.com/argoproj/argo-cd/v2/util/session"
+)
+
+// ExecPod executes a command in a container of a pod of an application through the exec subresource of the pod. The
+// first message of the client starts the command, the next ones are proxied to its standard input and terminal, until
+// the client closes its side of the stream, which closes the standard input of the command.
+func (s *Server) ExecPod(stream application.ApplicationService_ExecPodServer) error {
+	ctx := stream.Context()
+	req, err := stream.Recv()
+	if err != nil {
+		return fmt.Errorf("error receiving exec request: %w", err)
+	}
+	start := req.GetStart()
+	if start == nil {
+		return status.Error(codes.InvalidArgument, "the first message must start the command")
+	}
+	if err := validateExecPodStart(start); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	argocdSettings, err := s.settingsMgr.GetSettings()
+	if err != nil {
+		return fmt.Errorf("error getting settings: %w", err)
+	}
+	if !argocdSettings.ExecEnabled {
+		return status.Error(codes.FailedPrecondition, "exec is not enabled")
+	}
+
+	a, _, err := s.getApplicationEnforceRBACInformer(ctx, rbacpolicy.ActionGet, start.GetProject(), start.GetAppNamespace(), start.GetName())
+	if err != nil {
+		return err
+	}
+	if err := s.enf.EnforceErr(ctx.Value("claims"), rbacpolicy.ResourceExec, rbacpolicy.ActionCreate, a.RBACName(s.ns)); err != nil {
+		return err
+	}
+
+	tree, err := s.getAppResources(ctx, a)
+	if err != nil {
+		return err
+	}
+	if !podExists(tree.Nodes, start.GetPodName(), start.GetNamespace()) {
+		return status.Errorf(codes.InvalidArgument, "pod %s/%s doesn't belong to application %s", start.GetNamespace(), start.GetPodName(), a.QualifiedName())
+	}
+
+	if err := argo.ValidateDestination(ctx, &a.Spec.Destination, s.db); err != nil {
+		return fmt.Errorf("error validating destination: %w", err)
+	}
+	clst, err := s.db.GetCluster(ctx, a.Spec.Destination.Server)
+	if err != nil {
+		return fmt.Errorf("error getting cluster: %w", err)
+	}
+	config := clst.RawRestConfig()
+	kubeClientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("error creating kube client: %w", err)
+	}
+
+	tty := start.GetTty()
+	execReq := kubeClientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(start.GetPodName()).
+		Namespace(start.GetNamespace()).
+		SubResource("exec")
+	execReq.VersionedParams(&v1.PodExecOptions{
+		Container: start.GetContainer(),
+		Command:   start.GetCommand(),
+		Stdin:     true,
+		Stdout:    true,
+		Stderr:    !tty,
+		TTY:       tty,
+	}, scheme.ParameterCodec)
+	executor, err := remotecommand.NewSPDYExecutor(config, "POST", execReq.URL())
+	if err != nil {
+		return fmt.Errorf("error creating executor: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"application": a.QualifiedName(), "userName": sessionmgr.Username(ctx), "namespace": start.GetNamespace(),
+		"podName": start.GetPodName(), "container": start.GetContainer(),
+	}).Info("exec session starting")
+
+	streamer := newExecPodStreamer(stream)
+	defer streamer.stdin.Close()
+	go streamer.receive()
+	streamOpts := remotecommand.StreamOptions{
+		Stdin:  streamer.stdin,
+		Stdout: streamer.writer(false),
+		Tty:    tty,
+	}
+	if tty {
+		streamOpts.TerminalSizeQueue = streamer.sizes
+	} else {
+		streamOpts.Stderr = streamer.writer(true)
+	}
+	err = executor.StreamWithContext(ctx, streamOpts)
+	var exitErr utilexec.ExitError
+	if err != nil && !errors.As(err, &exitErr) {
+		return fmt.Errorf("error executing command: %w", err)
+	}
+	exitCode := int32(0)
+	if exitErr != nil {
+		exitCode = int32(exitErr.ExitStatus())
+	}
+	return streamer.send(&application.ExecPodResponse{Exited: ptr.To(true), ExitCode: &exitCode})
+}
+
+func validateExecPodStart(start *application.ExecPodStart) error {
+	if !argo.IsValidPodName(start.GetPodName()) {
+		return errors.New("pod name is not valid")
+	}
+	if start.GetContainer() != "" && !argo.IsValidContainerName(start.GetContainer()) {
+		return errors.New("container name is not valid")
+	}
+	if !argo.IsValidNamespaceName(start.GetNamespace()) {
+		return errors.New("namespace name is not valid")
+	}
+	if len(start.GetCommand()) == 0 {
+		return errors.New("command is missing")
+	}
+	return nil
+}
+
+// execPodStreamer proxies the standard input and terminal resizes received from the client of ExecPod to the command,
+// and the output of the command to the client
+type execPodStreamer struct {
+	stream application.ApplicationService_ExecPodServer
+	stdin  *io.PipeReader
+	// stdinWriter is closed once the client closed its side of the stream
+	stdinWriter *io.PipeWriter
+	sizes       terminalSizeQueue
+	// sendLock serializes the messages sent to the client, since stdout and stderr are written concurrently
+	sendLock sync.Mutex
+}
+
+func newExecPodStreamer(stream application.ApplicationService_ExecPodServer) *execPodStreamer {
+	stdin, stdinWriter := io.Pipe()
+	return &execPodStreamer{
+		stream:      stream,
+		stdin:       stdin,
+		stdinWriter: stdinWriter,
+		sizes:       make(terminalSizeQueue, 1),
+	}
+}
+
+// receive proxies the messages of the client until it closes its side of the stream
+func (s *execPodStreamer) receive() {
+	defer close(s.sizes)
+	for {
+		req, err := s.stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				_ = s.stdinWriter.Close()
+			} else {
+				_ = s.stdinWriter.CloseWithError(err)
+			}
+			return
+		}
+		switch part := req.GetPart().(type) {
+		case *application.ExecPodRequest_Stdin:
+			if _, err := s.stdinWriter.Write(part.Stdin); err != nil {
+				return
+			}
+		case *application.ExecPodRequest_Resize:
+			size := remotecommand.TerminalSize{Width: uint16(part.Resize.GetWidth()), Height: uint16(part.Resize.GetHeight())}
+			// only the latest size matters if the command did not read the previous one yet
+			select {
+			case <-s.sizes:
+			default:
+			}
+			s.sizes <- size
+		}
+	}
+}
+
+func (s *execPodStreamer) send(res *application.ExecPodResponse) error {
+	s.sendLock.Lock()
+	defer s.sendLock.Unlock()
+	return s.stream.Send(res)
+}
+
+// writer returns a writer sending the output of the command to the client, as stderr or stdout
+func (s *execPodStreamer) writer(stderr bool) io.Writer {
+	return execPodOutputWriter(func(p []byte) error {
+		data := append([]byte(nil), p...)
+		if stderr {
+			return s.send(&application.ExecPodResponse{Stderr: data})
+		}
+		return s.send(&application.ExecPodResponse{Stdout: data})
+	})
+}
+
+type execPodOutputWriter func(p []byte) error
+
+func (w execPodOutputWriter) Write(p []byte) (int, error) {
+	if err := w(p); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// terminalSizeQueue holds the latest terminal size requested by the client
+type terminalSizeQueue chan remotecommand.TerminalSize
+
+func (q terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q
+	if !ok {
+		return nil
+	}
+	return &size
+}
@@ -0,0 +1,267 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/apimachinery/pkg/util/httpstream/spdy"
+	remotecommandconsts "k8s.io/apimachinery/pkg/util/remotecommand"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	appsv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/assets"
+	"github.com/argoproj/argo-cd/v2/util/rbac"
+)
+
+// newMockExecEndpoint returns a server serving the exec subresource of pods like the Kubernetes API server. handle
+// runs the command of each request with the streams opened by the client, by type, and returns its exit code.
+func newMockExecEndpoint(t *testing.T, handle func(r *http.Request, streams map[string]httpstream.Stream) int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := httpstream.Handshake(r, w, []string{remotecommandconsts.StreamProtocolV4Name}); err != nil {
+			return
+		}
+		query := r.URL.Query()
+		expected := map[string]bool{
+			v1.StreamTypeError:  true,
+			v1.StreamTypeStdin:  query.Get("stdin") == "true",
+			v1.StreamTypeStdout: query.Get("stdout") == "true",
+			v1.StreamTypeStderr: query.Get("stderr") == "true",
+			v1.StreamTypeResize: query.Get("tty") == "true",
+		}
+		streamCh := make(chan httpstream.Stream, len(expected))
+		conn := spdy.NewResponseUpgrader().UpgradeResponse(w, r, func(stream httpstream.Stream, _ <-chan struct{}) error {
+			streamCh <- stream
+			return nil
+		})
+		if conn == nil {
+			return
+		}
+		defer conn.Close()
+
+		streams := map[string]httpstream.Stream{}
+		for streamType, ok := range expected {
+			if !ok {
+				continue
+			}
+			select {
+			case stream := <-streamCh:
+				streams[stream.Headers().Get(v1.StreamType)] = stream
+			case <-time.After(10 * time.Second):
+				t.Errorf("timed out waiting for the %s stream", streamType)
+				return
+			}
+		}
+
+		if exitCode := handle(r, streams); exitCode != 0 {
+			_ = json.NewEncoder(streams[v1.StreamTypeError]).Encode(metav1.Status{
+				Status: metav1.StatusFailure,
+				Reason: remotecommandconsts.NonZeroExitCodeReason,
+				Details: &metav1.StatusDetails{Causes: []metav1.StatusCause{
+					{Type: remotecommandconsts.ExitCodeCauseType, Message: strconv.Itoa(exitCode)},
+				}},
+			})
+		}
+		for _, stream := range streams {
+			_ = stream.Close()
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// fakeExecPodStream is the server side of an ExecPod stream, receiving the given requests and recording the responses
+type fakeExecPodStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	requests  chan *application.ExecPodRequest
+	lock      sync.Mutex
+	responses []*application.ExecPodResponse
+}
+
+func newFakeExecPodStream(requests ...*application.ExecPodRequest) *fakeExecPodStream {
+	stream := &fakeExecPodStream{ctx: context.Background(), requests: make(chan *application.ExecPodRequest, len(requests))}
+	for _, req := range requests {
+		stream.requests <- req
+	}
+	close(stream.requests)
+	return stream
+}
+
+func (s *fakeExecPodStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeExecPodStream) Recv() (*application.ExecPodRequest, error) {
+	req, ok := <-s.requests
+	if !ok {
+		return nil, io.EOF
+	}
+	return req, nil
+}
+
+func (s *fakeExecPodStream) Send(res *application.ExecPodResponse) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.responses = append(s.responses, res)
+	return nil
+}
+
+// output returns the stdout and stderr sent to the client, and the last response
+func (s *fakeExecPodStream) output() (string, string, *application.ExecPodResponse) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	var stdout, stderr strings.Builder
+	for _, res := range s.responses {
+		stdout.Write(res.Stdout)
+		stderr.Write(res.Stderr)
+	}
+	if len(s.responses) == 0 {
+		return stdout.String(), stderr.String(), nil
+	}
+	return stdout.String(), stderr.String(), s.responses[len(s.responses)-1]
+}
+
+func newExecTestAppServer(t *testing.T, endpoint *httptest.Server, execEnabled bool, policy string) *Server {
+	t.Helper()
+	app := newTestApp(func(app *appsv1.Application) {
+		app.Spec.Destination.Server = endpoint.URL
+		app.Status.Resources = []appsv1.ResourceStatus{{Version: "v1", Kind: "Pod", Namespace: "default", Name: "guestbook-pod"}}
+	})
+	appServer := newTestAppServerWithEnforcerConfigure(func(enf *rbac.Enforcer) {
+		_ = enf.SetBuiltinPolicy(policy)
+		enf.SetDefaultRole("role:test")
+	}, t, map[string]string{"exec.enabled": strconv.FormatBool(execEnabled)}, app)
+	_, err := appServer.db.CreateCluster(context.Background(), &appsv1.Cluster{Server: endpoint.URL, Name: "exec-cluster"})
+	require.NoError(t, err)
+	return appServer
+}
+
+const execTestPolicy = `p, role:test, applications, get, default/test-app, allow
+p, role:test, exec, create, default/test-app, allow`
+
+func execPodStart(podName string, tty bool, command ...string) *application.ExecPodRequest {
+	return &application.ExecPodRequest{Part: &application.ExecPodRequest_Start{Start: &application.ExecPodStart{
+		Name:      ptr.To("test-app"),
+		Namespace: ptr.To("default"),
+		PodName:   ptr.To(podName),
+		Container: ptr.To("guestbook"),
+		Command:   command,
+		Tty:       ptr.To(tty),
+	}}}
+}
+
+func execPodStdin(data string) *application.ExecPodRequest {
+	return &application.ExecPodRequest{Part: &application.ExecPodRequest_Stdin{Stdin: []byte(data)}}
+}
+
+func TestExecPod(t *testing.T) {
+	t.Run("Stream", func(t *testing.T) {
+		var query map[string][]string
+		var path string
+		endpoint := newMockExecEndpoint(t, func(r *http.Request, streams map[string]httpstream.Stream) int {
+			path, query = r.URL.Path, r.URL.Query()
+			stdin, err := io.ReadAll(streams[v1.StreamTypeStdin])
+			assert.NoError(t, err)
+			_, _ = streams[v1.StreamTypeStdout].Write([]byte(strings.ToUpper(string(stdin))))
+			_, _ = streams[v1.StreamTypeStderr].Write([]byte("warning\n"))
+			return 3
+		})
+		appServer := newExecTestAppServer(t, endpoint, true, execTestPolicy)
+
+		stream := newFakeExecPodStream(execPodStart("guestbook-pod", false, "tr", "a-z", "A-Z"), execPodStdin("hello "), execPodStdin("world\n"))
+		require.NoError(t, appServer.ExecPod(stream))
+
+		assert.Equal(t, "/api/v1/namespaces/default/pods/guestbook-pod/exec", path)
+		assert.Equal(t, []string{"tr", "a-z", "A-Z"}, query["command"])
+		assert.Equal(t, []string{"guestbook"}, query["container"])
+		stdout, stderr, last := stream.output()
+		// the standard input of the command is closed once the client closes its side of the stream
+		assert.Equal(t, "HELLO WORLD\n", stdout)
+		assert.Equal(t, "warning\n", stderr)
+		assert.True(t, last.GetExited())
+		assert.Equal(t, int32(3), last.GetExitCode())
+	})
+
+	t.Run("TTY", func(t *testing.T) {
+		endpoint := newMockExecEndpoint(t, func(r *http.Request, streams map[string]httpstream.Stream) int {
+			var size remotecommand.TerminalSize
+			assert.NoError(t, json.NewDecoder(streams[v1.StreamTypeResize]).Decode(&size))
+			_, _ = streams[v1.StreamTypeStdout].Write([]byte(strconv.Itoa(int(size.Width)) + "x" + strconv.Itoa(int(size.Height)) + "\n"))
+			_, err := io.ReadAll(streams[v1.StreamTypeStdin])
+			assert.NoError(t, err)
+			return 0
+		})
+		appServer := newExecTestAppServer(t, endpoint, true, execTestPolicy)
+
+		stream := newFakeExecPodStream(
+			execPodStart("guestbook-pod", true, "sh"),
+			&application.ExecPodRequest{Part: &application.ExecPodRequest_Resize{Resize: &application.TerminalSize{Width: ptr.To(uint32(120)), Height: ptr.To(uint32(40))}}},
+			execPodStdin("exit\n"),
+		)
+		require.NoError(t, appServer.ExecPod(stream))
+
+		stdout, stderr, last := stream.output()
+		assert.Equal(t, "120x40\n", stdout)
+		assert.Empty(t, stderr)
+		assert.True(t, last.GetExited())
+		assert.Equal(t, int32(0), last.GetExitCode())
+	})
+
+	t.Run("PodNotInApplication", func(t *testing.T) {
+		endpoint := newMockExecEndpoint(t, func(*http.Request, map[string]httpstream.Stream) int {
+			t.Error("the command must not be executed")
+			return 0
+		})
+		appServer := newExecTestAppServer(t, endpoint, true, execTestPolicy)
+		err := appServer.ExecPod(newFakeExecPodStream(execPodStart("other-pod", false, "sh")))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.ErrorContains(t, err, "pod default/other-pod doesn't belong to application")
+	})
+
+	t.Run("PermissionDenied", func(t *testing.T) {
+		endpoint := newMockExecEndpoint(t, func(*http.Request, map[string]httpstream.Stream) int {
+			t.Error("the command must not be executed")
+			return 0
+		})
+		appServer := newExecTestAppServer(t, endpoint, true, "p, role:test, applications, get, default/test-app, allow")
+		err := appServer.ExecPod(newFakeExecPodStream(execPodStart("guestbook-pod", false, "sh")))
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		endpoint := newMockExecEndpoint(t, func(*http.Request, map[string]httpstream.Stream) int {
+			t.Error("the command must not be executed")
+			return 0
+		})
+		appServer := newExecTestAppServer(t, endpoint, false, assets.BuiltinPolicyCSV)
+		err := appServer.ExecPod(newFakeExecPodStream(execPodStart("guestbook-pod", false, "sh")))
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("InvalidRequest", func(t *testing.T) {
+		appServer := newTestAppServer(t)
+		err := appServer.ExecPod(newFakeExecPodStream(execPodStdin("ls\n")))
+		assert.EqualError(t, err, "rpc error: code = InvalidArgument desc = the first message must start the command")
+		err = appServer.ExecPod(newFakeExecPodStream(execPodStart("guestbook-pod", false)))
+		assert.EqualError(t, err, "rpc error: code = InvalidArgument desc = command is missing")
+	})
+}
@@ -0,0 +1,92 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	jsonpatch "github.com/evanphx/json-patch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+)
+
+// dry run modes of resource patches, named after the --dry-run modes of kubectl
+const (
+	patchDryRunNone   = "none"
+	patchDryRunClient = "client"
+	patchDryRunServer = "server"
+)
+
+// patchResourceServerDryRun submits the patch of a resource to the Kubernetes API server in dry-run mode, and returns
+// the patched resource as the API server would persist it, including the changes of admission webhooks and defaults
+func patchResourceServerDryRun(ctx context.Context, config *rest.Config, gvk schema.GroupVersionKind, name string, namespace string, patchType types.PatchType, patch []byte) (*unstructured.Unstructured, error) {
+	disco, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating discovery client: %w", err)
+	}
+	apiResource, err := kube.ServerResourceForGroupVersionKind(disco, gvk, "patch")
+	if err != nil {
+		return nil, fmt.Errorf("error getting API resource of %s: %w", gvk, err)
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating dynamic client: %w", err)
+	}
+	namespaceableClient := client.Resource(gvk.GroupVersion().WithResource(apiResource.Name))
+	var resourceClient dynamic.ResourceInterface = namespaceableClient
+	if apiResource.Namespaced {
+		resourceClient = namespaceableClient.Namespace(namespace)
+	}
+	return resourceClient.Patch(ctx, name, patchType, patch, metav1.PatchOptions{DryRun: []string{metav1.DryRunAll}})
+}
+
+// patchResourceClientDryRun applies a patch to the live state of a resource without submitting it to the Kubernetes
+// API server. Strategic merge patches are only supported for the built-in kinds, whose merge strategies are known.
+func patchResourceClientDryRun(live *unstructured.Unstructured, patchType types.PatchType, patch []byte) (*unstructured.Unstructured, error) {
+	original, err := json.Marshal(live.Object)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling live resource: %w", err)
+	}
+	var patched []byte
+	switch patchType {
+	case types.JSONPatchType:
+		jsonPatch, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding JSON patch: %w", err)
+		}
+		patched, err = jsonPatch.Apply(original)
+		if err != nil {
+			return nil, fmt.Errorf("error applying JSON patch: %w", err)
+		}
+	case types.MergePatchType:
+		patched, err = jsonpatch.MergePatch(original, patch)
+		if err != nil {
+			return nil, fmt.Errorf("error applying merge patch: %w", err)
+		}
+	case types.StrategicMergePatchType:
+		gvk := live.GroupVersionKind()
+		dataStruct, err := scheme.Scheme.New(gvk)
+		if err != nil {
+			return nil, fmt.Errorf("strategic merge patches of %s cannot be applied by a client dry run, use a server dry run instead", gvk)
+		}
+		patched, err = strategicpatch.StrategicMergePatch(original, patch, dataStruct)
+		if err != nil {
+			return nil, fmt.Errorf("error applying strategic merge patch: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported patch type %q", patchType)
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(patched); err != nil {
+		return nil, fmt.Errorf("error unmarshaling patched resource: %w", err)
+	}
+	return obj, nil
+}
@@ -0,0 +1,137 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+const fakeDeploymentManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: guestbook
+  namespace: test
+  labels:
+    app: guestbook
+spec:
+  replicas: 1
+  template:
+    spec:
+      containers:
+      - name: guestbook
+        image: guestbook:v1
+      - name: sidecar
+        image: sidecar:v1
+`
+
+func TestPatchResourceServerDryRun(t *testing.T) {
+	var patchRequest *http.Request
+	var patchBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/apis/apps/v1":
+			_ = json.NewEncoder(w).Encode(metav1.APIResourceList{
+				TypeMeta:     metav1.TypeMeta{Kind: "APIResourceList", APIVersion: "v1"},
+				GroupVersion: "apps/v1",
+				APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment", Namespaced: true, Verbs: []string{"get", "patch"}}},
+			})
+		case r.Method == http.MethodPatch && r.URL.Path == "/apis/apps/v1/namespaces/test/deployments/guestbook":
+			patchRequest = r
+			patchBody, _ = io.ReadAll(r.Body)
+			patched, err := patchResourceClientDryRun(test.YamlToUnstructured(fakeDeploymentManifest), types.PatchType(r.Header.Get("Content-Type")), patchBody)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(patched.Object)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	patch := []byte(`{"metadata": {"labels": {"team": "a"}}}`)
+	patched, err := patchResourceServerDryRun(context.Background(), &rest.Config{Host: server.URL}, gvk, "guestbook", "test", types.MergePatchType, patch)
+	require.NoError(t, err)
+
+	require.NotNil(t, patchRequest)
+	assert.Equal(t, []string{metav1.DryRunAll}, patchRequest.URL.Query()["dryRun"])
+	assert.Equal(t, string(types.MergePatchType), patchRequest.Header.Get("Content-Type"))
+	assert.JSONEq(t, string(patch), string(patchBody))
+	assert.Equal(t, map[string]string{"app": "guestbook", "team": "a"}, patched.GetLabels())
+}
+
+func TestPatchResourceClientDryRun(t *testing.T) {
+	t.Run("JSONPatch", func(t *testing.T) {
+		live := test.YamlToUnstructured(fakeDeploymentManifest)
+		patched, err := patchResourceClientDryRun(live, types.JSONPatchType, []byte(`[{"op": "replace", "path": "/spec/replicas", "value": 3}]`))
+		require.NoError(t, err)
+		replicas, _, _ := unstructured.NestedFieldNoCopy(patched.Object, "spec", "replicas")
+		assert.EqualValues(t, 3, replicas)
+		// the live state is not modified
+		replicas, _, _ = unstructured.NestedFieldNoCopy(live.Object, "spec", "replicas")
+		assert.EqualValues(t, 1, replicas)
+	})
+
+	t.Run("MergePatch", func(t *testing.T) {
+		patched, err := patchResourceClientDryRun(test.YamlToUnstructured(fakeDeploymentManifest), types.MergePatchType, []byte(`{"metadata": {"annotations": {"owner": "team-a"}, "labels": {"app": null}}}`))
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"owner": "team-a"}, patched.GetAnnotations())
+		assert.Empty(t, patched.GetLabels())
+	})
+
+	t.Run("StrategicMergePatch", func(t *testing.T) {
+		patched, err := patchResourceClientDryRun(test.YamlToUnstructured(fakeDeploymentManifest), types.StrategicMergePatchType, []byte(`{"spec": {"template": {"spec": {"containers": [{"name": "guestbook", "image": "guestbook:v2"}]}}}}`))
+		require.NoError(t, err)
+		containers, _, _ := unstructured.NestedSlice(patched.Object, "spec", "template", "spec", "containers")
+		// containers are merged by name
+		require.Len(t, containers, 2)
+		assert.Equal(t, "guestbook:v2", containers[0].(map[string]interface{})["image"])
+		assert.Equal(t, "sidecar:v1", containers[1].(map[string]interface{})["image"])
+	})
+
+	t.Run("StrategicMergePatchOfCustomResource", func(t *testing.T) {
+		live := test.YamlToUnstructured(`
+apiVersion: example.com/v1
+kind: Widget
+metadata:
+  name: widget
+`)
+		_, err := patchResourceClientDryRun(live, types.StrategicMergePatchType, []byte(`{"spec": {"size": 2}}`))
+		assert.ErrorContains(t, err, "use a server dry run instead")
+	})
+
+	t.Run("InvalidPatch", func(t *testing.T) {
+		_, err := patchResourceClientDryRun(test.YamlToUnstructured(fakeDeploymentManifest), types.JSONPatchType, []byte(`{"spec": {}}`))
+		assert.ErrorContains(t, err, "error decoding JSON patch")
+	})
+}
+
+func TestPatchResource_InvalidDryRun(t *testing.T) {
+	appServer := newTestAppServer(t)
+	_, err := appServer.PatchResource(context.Background(), &application.ApplicationResourcePatchRequest{
+		Name: ptr.To("test"), ResourceName: ptr.To("test"), Group: ptr.To("apps"), Kind: ptr.To("Deployment"), Namespace: ptr.To("test"),
+		Patch: ptr.To(`{"spec": {"replicas": 3}}`), PatchType: ptr.To(string(types.MergePatchType)), DryRun: ptr.To("all"),
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,208 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+)
+
+const (
+	// resourceWatchHeartbeatInterval is the interval of the heartbeats sent to the watchers of a resource which does
+	// not change
+	resourceWatchHeartbeatInterval = 30 * time.Second
+	// resourceWatchEventBufferSize is the number of events buffered for a watcher of a resource, the events are dropped
+	// once the buffer is full
+	resourceWatchEventBufferSize = 100
+	// resourceWatchHeartbeat is the type of the heartbeat events
+	resourceWatchHeartbeat = "HEARTBEAT"
+)
+
+// resourceWatchKey identifies a watched resource
+type resourceWatchKey struct {
+	server    string
+	gvk       schema.GroupVersionKind
+	namespace string
+	name      string
+}
+
+// resourceSubscription is the informer watching a resource, shared by the subscribers of the resource
+type resourceSubscription struct {
+	informer    cache.SharedIndexInformer
+	cancel      context.CancelFunc
+	subscribers int
+}
+
+// resourceWatcher is a registry of the subscriptions to the live state changes of resources. The subscribers of a
+// resource share an informer watching it, which is stopped once the last subscriber unsubscribes.
+type resourceWatcher struct {
+	lock              sync.Mutex
+	subscriptions     map[resourceWatchKey]*resourceSubscription
+	heartbeatInterval time.Duration
+	newListerWatcher  func(config *rest.Config, key resourceWatchKey) (cache.ListerWatcher, error)
+}
+
+func newResourceWatcher() *resourceWatcher {
+	return &resourceWatcher{
+		subscriptions:     map[resourceWatchKey]*resourceSubscription{},
+		heartbeatInterval: resourceWatchHeartbeatInterval,
+		newListerWatcher:  newResourceListerWatcher,
+	}
+}
+
+// newResourceListerWatcher returns a lister watcher of the resource, which only lists and watches the resource itself
+func newResourceListerWatcher(config *rest.Config, key resourceWatchKey) (cache.ListerWatcher, error) {
+	disco, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating discovery client: %w", err)
+	}
+	apiResource, err := kube.ServerResourceForGroupVersionKind(disco, key.gvk, "watch")
+	if err != nil {
+		return nil, fmt.Errorf("error getting API resource of %s: %w", key.gvk, err)
+	}
+	client, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("error creating dynamic client: %w", err)
+	}
+	namespaceableClient := client.Resource(key.gvk.GroupVersion().WithResource(apiResource.Name))
+	var resourceClient dynamic.ResourceInterface = namespaceableClient
+	if apiResource.Namespaced {
+		resourceClient = namespaceableClient.Namespace(key.namespace)
+	}
+	fieldSelector := fields.OneTermEqualSelector("metadata.name", key.name).String()
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = fieldSelector
+			return resourceClient.List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = fieldSelector
+			return resourceClient.Watch(context.Background(), options)
+		},
+	}, nil
+}
+
+// Subscribe sends the live state changes of the resource to the channel until the returned function is called. An
+// ADDED event is sent first if the resource exists. The events are dropped if the channel is full.
+func (w *resourceWatcher) Subscribe(config *rest.Config, key resourceWatchKey, ch chan<- watch.Event) (func(), error) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	sub, ok := w.subscriptions[key]
+	if !ok {
+		listerWatcher, err := w.newListerWatcher(config, key)
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		sub = &resourceSubscription{
+			informer: cache.NewSharedIndexInformer(listerWatcher, &unstructured.Unstructured{}, 0, cache.Indexers{}),
+			cancel:   cancel,
+		}
+		go sub.informer.Run(ctx.Done())
+		w.subscriptions[key] = sub
+	}
+
+	send := func(eventType watch.EventType, obj interface{}) {
+		if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+			obj = tombstone.Obj
+		}
+		un, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return
+		}
+		select {
+		case ch <- watch.Event{Type: eventType, Object: un}:
+		default:
+			log.WithFields(log.Fields{"kind": key.gvk.Kind, "namespace": key.namespace, "name": key.name}).Warn("unable to send resource watch event")
+		}
+	}
+	registration, err := sub.informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			send(watch.Added, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			// the informer notifies the objects it lists again after its watch expires even if they did not change
+			if oldObj.(*unstructured.Unstructured).GetResourceVersion() != newObj.(*unstructured.Unstructured).GetResourceVersion() {
+				send(watch.Modified, newObj)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			send(watch.Deleted, obj)
+		},
+	})
+	if err != nil {
+		if sub.subscribers == 0 {
+			sub.cancel()
+			delete(w.subscriptions, key)
+		}
+		return nil, fmt.Errorf("error subscribing to resource: %w", err)
+	}
+	sub.subscribers++
+
+	return func() {
+		w.lock.Lock()
+		defer w.lock.Unlock()
+		if err := sub.informer.RemoveEventHandler(registration); err != nil {
+			log.Warnf("Failed to remove resource watch event handler: %v", err)
+		}
+		sub.subscribers--
+		if sub.subscribers == 0 {
+			sub.cancel()
+			delete(w.subscriptions, key)
+		}
+	}, nil
+}
+
+// Watch sends the live state changes of the resource until the context is done, and heartbeats while the resource
+// does not change. The data of secrets is hidden.
+func (w *resourceWatcher) Watch(ctx context.Context, config *rest.Config, key resourceWatchKey, send func(event *application.ResourceWatchEvent) error) error {
+	events := make(chan watch.Event, resourceWatchEventBufferSize)
+	unsubscribe, err := w.Subscribe(config, key, events)
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(w.heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case event := <-events:
+			obj, err := replaceSecretValues(event.Object.(*unstructured.Unstructured))
+			if err != nil {
+				return fmt.Errorf("error replacing secret values: %w", err)
+			}
+			data, err := json.Marshal(obj.Object)
+			if err != nil {
+				return fmt.Errorf("error marshaling object: %w", err)
+			}
+			if err := send(&application.ResourceWatchEvent{Type: ptr.To(string(event.Type)), Manifest: ptr.To(string(data))}); err != nil {
+				return err
+			}
+			heartbeat.Reset(w.heartbeatInterval)
+		case <-heartbeat.C:
+			if err := send(&application.ResourceWatchEvent{Type: ptr.To(resourceWatchHeartbeat)}); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+)
+
+// resourceWatchEventTimeout is the maximum delay between a change of a resource and the delivery of its event
+const resourceWatchEventTimeout = 500 * time.Millisecond
+
+var (
+	configMapGVR = schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	secretGVR    = schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+)
+
+func newTestResource(kind, name string, data map[string]interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": name, "namespace": testNamespace, "resourceVersion": "1"},
+		"data":       data,
+	}}
+}
+
+func newTestResourceWatcher(client *dynamicfake.FakeDynamicClient) *resourceWatcher {
+	w := newResourceWatcher()
+	w.newListerWatcher = func(_ *rest.Config, key resourceWatchKey) (cache.ListerWatcher, error) {
+		resourceClient := client.Resource(key.gvk.GroupVersion().WithResource(map[string]string{"ConfigMap": "configmaps", "Secret": "secrets"}[key.gvk.Kind])).Namespace(key.namespace)
+		return &cache.ListWatch{
+			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+				return resourceClient.List(context.Background(), options)
+			},
+			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+				return resourceClient.Watch(context.Background(), options)
+			},
+		}, nil
+	}
+	return w
+}
+
+func newFakeDynamicClient(objs ...runtime.Object) *dynamicfake.FakeDynamicClient {
+	return dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+		configMapGVR: "ConfigMapList",
+		secretGVR:    "SecretList",
+	}, objs...)
+}
+
+// watchResource watches the resource in the background and returns the channel its events are sent to
+func watchResource(t *testing.T, ctx context.Context, w *resourceWatcher, key resourceWatchKey) <-chan *application.ResourceWatchEvent {
+	t.Helper()
+	events := make(chan *application.ResourceWatchEvent, 10)
+	go func() {
+		assert.NoError(t, w.Watch(ctx, &rest.Config{}, key, func(event *application.ResourceWatchEvent) error {
+			events <- event
+			return nil
+		}))
+	}()
+	return events
+}
+
+func receiveResourceWatchEvent(t *testing.T, events <-chan *application.ResourceWatchEvent) *application.ResourceWatchEvent {
+	t.Helper()
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(resourceWatchEventTimeout):
+		require.FailNow(t, "no event received")
+		return nil
+	}
+}
+
+func TestResourceWatcher_Watch(t *testing.T) {
+	client := newFakeDynamicClient(newTestResource("ConfigMap", "my-config", map[string]interface{}{"key": "value"}))
+	w := newTestResourceWatcher(client)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	key := resourceWatchKey{gvk: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, namespace: testNamespace, name: "my-config"}
+	events := watchResource(t, ctx, w, key)
+
+	event := receiveResourceWatchEvent(t, events)
+	assert.Equal(t, string(watch.Added), event.GetType())
+	assert.Contains(t, event.GetManifest(), `"key":"value"`)
+
+	updated := newTestResource("ConfigMap", "my-config", map[string]interface{}{"key": "updated"})
+	updated.SetResourceVersion("2")
+	_, err := client.Resource(configMapGVR).Namespace(testNamespace).Update(ctx, updated, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	event = receiveResourceWatchEvent(t, events)
+	assert.Equal(t, string(watch.Modified), event.GetType())
+	assert.Contains(t, event.GetManifest(), `"key":"updated"`)
+
+	require.NoError(t, client.Resource(configMapGVR).Namespace(testNamespace).Delete(ctx, "my-config", metav1.DeleteOptions{}))
+	event = receiveResourceWatchEvent(t, events)
+	assert.Equal(t, string(watch.Deleted), event.GetType())
+
+	_, err = client.Resource(configMapGVR).Namespace(testNamespace).Create(ctx, newTestResource("ConfigMap", "my-config", nil), metav1.CreateOptions{})
+	require.NoError(t, err)
+	event = receiveResourceWatchEvent(t, events)
+	assert.Equal(t, string(watch.Added), event.GetType())
+}
+
+func TestResourceWatcher_Heartbeat(t *testing.T) {
+	w := newTestResourceWatcher(newFakeDynamicClient())
+	w.heartbeatInterval = 50 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := watchResource(t, ctx, w, resourceWatchKey{gvk: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, namespace: testNamespace, name: "missing"})
+
+	for i := 0; i < 2; i++ {
+		event := receiveResourceWatchEvent(t, events)
+		assert.Equal(t, resourceWatchHeartbeat, event.GetType())
+		assert.Nil(t, event.Manifest)
+	}
+}
+
+func TestResourceWatcher_HidesSecretData(t *testing.T) {
+	client := newFakeDynamicClient(newTestResource("Secret", "my-secret", map[string]interface{}{"password": "c2VjcmV0"}))
+	w := newTestResourceWatcher(client)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	events := watchResource(t, ctx, w, resourceWatchKey{gvk: schema.GroupVersionKind{Version: "v1", Kind: "Secret"}, namespace: testNamespace, name: "my-secret"})
+
+	event := receiveResourceWatchEvent(t, events)
+	var obj map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(event.GetManifest()), &obj))
+	assert.NotEqual(t, "c2VjcmV0", obj["data"].(map[string]interface{})["password"])
+}
+
+func TestResourceWatcher_SharedSubscription(t *testing.T) {
+	client := newFakeDynamicClient(newTestResource("ConfigMap", "my-config", nil))
+	w := newTestResourceWatcher(client)
+	key := resourceWatchKey{gvk: schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, namespace: testNamespace, name: "my-config"}
+
+	first := make(chan watch.Event, 10)
+	unsubscribeFirst, err := w.Subscribe(&rest.Config{}, key, first)
+	require.NoError(t, err)
+	second := make(chan watch.Event, 10)
+	unsubscribeSecond, err := w.Subscribe(&rest.Config{}, key, second)
+	require.NoError(t, err)
+	require.Len(t, w.subscriptions, 1)
+	assert.Equal(t, 2, w.subscriptions[key].subscribers)
+
+	// both subscribers receive the current state of the resource
+	for _, ch := range []chan watch.Event{first, second} {
+		select {
+		case event := <-ch:
+			assert.Equal(t, watch.Added, event.Type)
+		case <-time.After(resourceWatchEventTimeout):
+			require.FailNow(t, "no event received")
+		}
+	}
+
+	unsubscribeFirst()
+	assert.Equal(t, 1, w.subscriptions[key].subscribers)
+	unsubscribeSecond()
+	assert.Empty(t, w.subscriptions)
+}
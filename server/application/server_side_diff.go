@@ -0,0 +1,56 @@
+package application
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	argodiff "github.com/argoproj/argo-cd/v2/util/argo/diff"
+)
+
+// unmarshalResourceState unmarshals the live or target state of a managed resource, which is nil if the resource does
+// not exist in the cluster or in the manifests
+func unmarshalResourceState(state string) (*unstructured.Unstructured, error) {
+	if state == "" || state == "null" {
+		return nil, nil
+	}
+	obj := &unstructured.Unstructured{}
+	if err := json.Unmarshal([]byte(state), obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// serverSideDiffs recalculates the diffs of the managed resources of an application with the given server-side diff
+// config. Secrets are returned as is, since their target state is masked and cannot be applied.
+func serverSideDiffs(items []*appv1.ResourceDiff, diffConfig argodiff.DiffConfig) ([]*appv1.ResourceDiff, error) {
+	diffs := make([]*appv1.ResourceDiff, 0, len(items))
+	for _, item := range items {
+		if item.Kind == kube.SecretKind && item.Group == "" {
+			diffs = append(diffs, item)
+			continue
+		}
+		live, err := unmarshalResourceState(item.LiveState)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshaling live state of %s: %w", item.Name, err)
+		}
+		target, err := unmarshalResourceState(item.TargetState)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshaling target state of %s: %w", item.Name, err)
+		}
+		diffRes, err := argodiff.StateDiff(live, target, diffConfig)
+		if err != nil {
+			return nil, fmt.Errorf("error diffing %s: %w", item.Name, err)
+		}
+		diff := item.DeepCopy()
+		diff.NormalizedLiveState = string(diffRes.NormalizedLive)
+		diff.PredictedLiveState = string(diffRes.PredictedLive)
+		// Resources missing from the cluster would be created, and resources missing from the manifests pruned
+		diff.Modified = diffRes.Modified || target == nil || live == nil
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
@@ -0,0 +1,72 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	argodiff "github.com/argoproj/argo-cd/v2/util/argo/diff"
+	"github.com/argoproj/argo-cd/v2/util/argo/normalizers"
+)
+
+// fakeMutatingDryRunner returns the applied resource with the annotation injected by a mutating admission webhook
+type fakeMutatingDryRunner struct {
+	applied []string
+}
+
+func (r *fakeMutatingDryRunner) Run(_ context.Context, obj *unstructured.Unstructured, _ string) (string, error) {
+	r.applied = append(r.applied, obj.GetName())
+	mutated := obj.DeepCopy()
+	annotations := mutated.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations["webhook.example.com/injected"] = "true"
+	mutated.SetAnnotations(annotations)
+	data, err := json.Marshal(mutated)
+	return string(data), err
+}
+
+func TestServerSideDiffs(t *testing.T) {
+	newItem := func(kind, name, live, target string) *appv1.ResourceDiff {
+		return &appv1.ResourceDiff{Kind: kind, Namespace: "default", Name: name, LiveState: live, TargetState: target}
+	}
+	cm := func(name string) string {
+		return `{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"` + name + `","namespace":"default"},"data":{"key":"value"}}`
+	}
+	dryRunner := &fakeMutatingDryRunner{}
+	diffConfig, err := argodiff.NewDiffConfigBuilder().
+		WithDiffSettings([]appv1.ResourceIgnoreDifferences{}, map[string]appv1.ResourceOverride{}, false, normalizers.IgnoreNormalizerOpts{}).
+		WithNoCache().
+		WithServerSideDiff(true).
+		WithServerSideDryRunner(dryRunner).
+		WithIgnoreMutationWebhook(false).
+		Build()
+	require.NoError(t, err)
+
+	secret := newItem("Secret", "secret", `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"secret"}}`, `{"apiVersion":"v1","kind":"Secret","metadata":{"name":"secret"}}`)
+	items := []*appv1.ResourceDiff{
+		newItem("ConfigMap", "mutated", cm("mutated"), cm("mutated")),
+		newItem("ConfigMap", "created", "null", cm("created")),
+		newItem("ConfigMap", "pruned", cm("pruned"), ""),
+		secret,
+	}
+	diffs, err := serverSideDiffs(items, diffConfig)
+	require.NoError(t, err)
+	require.Len(t, diffs, 4)
+
+	// only the resources which exist in the cluster and in the manifests are applied in dry-run mode
+	assert.Equal(t, []string{"mutated"}, dryRunner.applied)
+	assert.True(t, diffs[0].Modified)
+	assert.Contains(t, diffs[0].PredictedLiveState, `"webhook.example.com/injected":"true"`)
+	assert.NotContains(t, diffs[0].NormalizedLiveState, "webhook.example.com/injected")
+	assert.False(t, items[0].Modified, "the cached diff must not be modified")
+	assert.True(t, diffs[1].Modified)
+	assert.True(t, diffs[2].Modified)
+	assert.Same(t, secret, diffs[3])
+}
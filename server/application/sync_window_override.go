@@ -0,0 +1,85 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	v1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	argocommon "github.com/argoproj/argo-cd/v2/common"
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+)
+
+// maxSyncWindowOverrideRecords is the number of sync window overrides recorded for each application
+const maxSyncWindowOverrideRecords = 10
+
+// recordSyncWindowOverride records a sync overriding the sync windows of an application in the
+// argocd-sync-window-overrides ConfigMap. It fails if the sync windows of the application have been overridden less
+// than the configured cooldown ago.
+func (s *Server) recordSyncWindowOverride(ctx context.Context, a *appv1.Application, user string, reason string, now time.Time) error {
+	cooldown, err := s.settingsMgr.GetSyncWindowOverrideCooldown()
+	if err != nil {
+		return fmt.Errorf("error getting sync window override cooldown: %w", err)
+	}
+	key := argo.SyncWindowOverrideKey(a)
+	cmIf := s.kubeclientset.CoreV1().ConfigMaps(s.ns)
+	return retry.OnError(retry.DefaultRetry, func(err error) bool {
+		return apierr.IsConflict(err) || apierr.IsAlreadyExists(err)
+	}, func() error {
+		cm, err := cmIf.Get(ctx, argocommon.ArgoCDSyncWindowOverridesConfigMapName, metav1.GetOptions{})
+		create := apierr.IsNotFound(err)
+		if create {
+			cm = &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   argocommon.ArgoCDSyncWindowOverridesConfigMapName,
+					Labels: map[string]string{"app.kubernetes.io/part-of": "argocd"},
+				},
+			}
+		} else if err != nil {
+			return fmt.Errorf("error getting sync window overrides: %w", err)
+		}
+
+		records, err := argo.GetSyncWindowOverrideRecords(cm, a)
+		if err != nil {
+			return err
+		}
+		if len(records) > 0 {
+			last := records[len(records)-1]
+			if next := last.Timestamp.Add(cooldown); now.Before(next) {
+				return status.Errorf(codes.FailedPrecondition, "cannot override sync windows: they were overridden by %s at %s, next override is possible at %s", last.User, last.Timestamp.UTC().Format(time.RFC3339), next.UTC().Format(time.RFC3339))
+			}
+		}
+		records = append(records, argo.SyncWindowOverrideRecord{
+			User:      user,
+			Reason:    reason,
+			Project:   a.Spec.GetProject(),
+			Timestamp: metav1.NewTime(now),
+		})
+		if len(records) > maxSyncWindowOverrideRecords {
+			records = records[len(records)-maxSyncWindowOverrideRecords:]
+		}
+		data, err := json.Marshal(records)
+		if err != nil {
+			return fmt.Errorf("error marshaling sync window overrides of application %s: %w", a.QualifiedName(), err)
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[key] = string(data)
+
+		if create {
+			_, err = cmIf.Create(ctx, cm, metav1.CreateOptions{})
+		} else {
+			_, err = cmIf.Update(ctx, cm, metav1.UpdateOptions{})
+		}
+		return err
+	})
+}
@@ -2,6 +2,7 @@ package applicationset
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sort"
@@ -17,6 +18,7 @@ import (
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -27,6 +29,7 @@ import (
 	"github.com/argoproj/argo-cd/v2/applicationset/services"
 	appsetstatus "github.com/argoproj/argo-cd/v2/applicationset/status"
 	appsetutils "github.com/argoproj/argo-cd/v2/applicationset/utils"
+	"github.com/argoproj/argo-cd/v2/common"
 	"github.com/argoproj/argo-cd/v2/pkg/apiclient/applicationset"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	appclientset "github.com/argoproj/argo-cd/v2/pkg/client/clientset/versioned"
@@ -37,6 +40,7 @@ import (
 	"github.com/argoproj/argo-cd/v2/util/collections"
 	"github.com/argoproj/argo-cd/v2/util/db"
 	"github.com/argoproj/argo-cd/v2/util/github_app"
+	grpc_util "github.com/argoproj/argo-cd/v2/util/grpc"
 	"github.com/argoproj/argo-cd/v2/util/rbac"
 	"github.com/argoproj/argo-cd/v2/util/security"
 	"github.com/argoproj/argo-cd/v2/util/session"
@@ -64,6 +68,7 @@ type Server struct {
 	ScmRootCAPath            string
 	AllowedScmProviders      []string
 	EnableScmProviders       bool
+	MatrixConfig             generators.MatrixConfig
 }
 
 // NewServer returns a new instance of the ApplicationSet service
@@ -87,6 +92,8 @@ func NewServer(
 	scmRootCAPath string,
 	allowedScmProviders []string,
 	enableScmProviders bool,
+	matrixMaxDepth int,
+	matrixMaxParams int,
 ) applicationset.ApplicationSetServiceServer {
 	s := &Server{
 		ns:                       namespace,
@@ -109,6 +116,7 @@ func NewServer(
 		ScmRootCAPath:            scmRootCAPath,
 		AllowedScmProviders:      allowedScmProviders,
 		EnableScmProviders:       enableScmProviders,
+		MatrixConfig:             generators.NewMatrixConfig(matrixMaxDepth, matrixMaxParams),
 	}
 	return s
 }
@@ -261,7 +269,7 @@ func (s *Server) Create(ctx context.Context, q *applicationset.ApplicationSetCre
 }
 
 func (s *Server) generateApplicationSetApps(ctx context.Context, appset v1alpha1.ApplicationSet, namespace string) ([]v1alpha1.Application, error) {
-	logCtx := log.WithField("applicationset", appset.Name)
+	logCtx := grpc_util.LoggerFromContext(ctx).WithField("applicationset", appset.Name)
 
 	argoCDDB := s.db
 
@@ -275,7 +283,7 @@ func (s *Server) generateApplicationSetApps(ctx context.Context, appset v1alpha1
 		return nil, fmt.Errorf("error creating ArgoCDService: %w", err)
 	}
 
-	appSetGenerators := generators.GetGenerators(ctx, s.client, s.k8sClient, namespace, argoCDService, s.dynamicClient, scmConfig)
+	appSetGenerators := generators.GetGenerators(ctx, s.client, s.k8sClient, namespace, argoCDService, s.dynamicClient, scmConfig, s.MatrixConfig)
 
 	apps, _, err := appsettemplate.GenerateApplications(logCtx, appset, appSetGenerators, &appsetutils.Render{}, s.client)
 	if err != nil {
@@ -337,6 +345,15 @@ func (s *Server) Delete(ctx context.Context, q *applicationset.ApplicationSetDel
 		return nil, err
 	}
 
+	if appset.IsDeletionProtected() {
+		if !q.ForceDeleteProtected {
+			return nil, status.Errorf(codes.InvalidArgument, "ApplicationSet %s is protected from deletion by the %s annotation, remove the annotation or force the deletion to delete it", appset.QualifiedName(), common.AnnotationKeyDeletionProtected)
+		}
+		if err := s.removeDeletionProtection(ctx, appset); err != nil {
+			return nil, err
+		}
+	}
+
 	s.projectLock.RLock(appset.Spec.Template.Spec.Project)
 	defer s.projectLock.RUnlock(appset.Spec.Template.Spec.Project)
 
@@ -348,6 +365,28 @@ func (s *Server) Delete(ctx context.Context, q *applicationset.ApplicationSetDel
 	return &applicationset.ApplicationSetResponse{}, nil
 }
 
+// removeDeletionProtection removes the deletion protection annotation of the ApplicationSet, which requires the update
+// permission on it
+func (s *Server) removeDeletionProtection(ctx context.Context, appset *v1alpha1.ApplicationSet) error {
+	if err := s.enf.EnforceErr(ctx.Value("claims"), rbacpolicy.ResourceApplicationSets, rbacpolicy.ActionUpdate, appset.RBACName(s.ns)); err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]interface{}{common.AnnotationKeyDeletionProtected: nil},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error marshaling ApplicationSet patch: %w", err)
+	}
+	_, err = s.appclientset.ArgoprojV1alpha1().ApplicationSets(appset.Namespace).Patch(ctx, appset.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("error removing the deletion protection of ApplicationSet: %w", err)
+	}
+	s.logAppSetEvent(appset, ctx, argo.EventReasonResourceUpdated, "removed deletion protection of ApplicationSet")
+	return nil
+}
+
 func (s *Server) ResourceTree(ctx context.Context, q *applicationset.ApplicationSetTreeQuery) (*v1alpha1.ApplicationSetTree, error) {
 	namespace := s.appsetNamespaceOrDefault(q.AppsetNamespace)
 
@@ -9,13 +9,18 @@ import (
 	"github.com/argoproj/pkg/sync"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	v1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes/fake"
+	kubetesting "k8s.io/client-go/testing"
 	k8scache "k8s.io/client-go/tools/cache"
 
+	"github.com/argoproj/argo-cd/v2/applicationset/generators"
 	"github.com/argoproj/argo-cd/v2/common"
 	"github.com/argoproj/argo-cd/v2/pkg/apiclient/applicationset"
 	appsv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
@@ -162,6 +167,8 @@ func newTestAppSetServerWithEnforcerConfigure(f func(*rbac.Enforcer), namespace
 		"",
 		[]string{},
 		true,
+		generators.DefaultMatrixMaxDepth,
+		generators.DefaultMatrixMaxParams,
 	)
 	return server.(*Server)
 }
@@ -497,6 +504,55 @@ func TestDeleteAppSet(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, &applicationset.ApplicationSetResponse{}, res)
 	})
+
+	protectedAppSet := newTestAppSet(func(appset *appsv1.ApplicationSet) {
+		appset.Name = "Protected"
+		appset.Annotations = map[string]string{common.AnnotationKeyDeletionProtected: "true"}
+	})
+
+	t.Run("Delete protected is rejected", func(t *testing.T) {
+		appSetServer := newTestAppSetServer(protectedAppSet)
+
+		_, err := appSetServer.Delete(context.Background(), &applicationset.ApplicationSetDeleteRequest{Name: "Protected"})
+		require.Error(t, err)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Contains(t, err.Error(), common.AnnotationKeyDeletionProtected)
+
+		_, err = appSetServer.appclientset.ArgoprojV1alpha1().ApplicationSets(testNamespace).Get(context.Background(), "Protected", metav1.GetOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("Force delete protected", func(t *testing.T) {
+		appSetServer := newTestAppSetServer(protectedAppSet)
+		clientset := appSetServer.appclientset.(*apps.Clientset)
+		var annotations map[string]string
+		clientset.PrependReactor("delete", "applicationsets", func(action kubetesting.Action) (bool, runtime.Object, error) {
+			appset, err := clientset.Tracker().Get(action.GetResource(), action.GetNamespace(), "Protected")
+			require.NoError(t, err)
+			annotations = appset.(*appsv1.ApplicationSet).Annotations
+			return false, nil, nil
+		})
+
+		res, err := appSetServer.Delete(context.Background(), &applicationset.ApplicationSetDeleteRequest{Name: "Protected", ForceDeleteProtected: true})
+		require.NoError(t, err)
+		assert.Equal(t, &applicationset.ApplicationSetResponse{}, res)
+		assert.NotContains(t, annotations, common.AnnotationKeyDeletionProtected)
+
+		_, err = appSetServer.appclientset.ArgoprojV1alpha1().ApplicationSets(testNamespace).Get(context.Background(), "Protected", metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("Force delete protected requires update permission", func(t *testing.T) {
+		appSetServer := newTestAppSetServerWithEnforcerConfigure(func(enf *rbac.Enforcer) {
+			_ = enf.SetBuiltinPolicy(assets.BuiltinPolicyCSV)
+			_ = enf.SetUserPolicy("p, role:deleter, applicationsets, delete, */*, allow")
+			enf.SetDefaultRole("role:deleter")
+		}, "", protectedAppSet)
+
+		_, err := appSetServer.Delete(context.Background(), &applicationset.ApplicationSetDeleteRequest{Name: "Protected", ForceDeleteProtected: true})
+		require.Error(t, err)
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
 }
 
 func TestUpdateAppSet(t *testing.T) {
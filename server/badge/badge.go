@@ -3,12 +3,17 @@ package badge
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
+	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	healthutil "github.com/argoproj/gitops-engine/pkg/health"
+	gocache "github.com/patrickmn/go-cache"
+	"golang.org/x/time/rate"
 	"k8s.io/apimachinery/pkg/api/errors"
 	validation "k8s.io/apimachinery/pkg/api/validation"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -23,7 +28,14 @@ import (
 
 // NewHandler creates handler serving to do api/badge endpoint
 func NewHandler(appClientset versioned.Interface, settingsMrg *settings.SettingsManager, namespace string, enabledNamespaces []string) http.Handler {
-	return &Handler{appClientset: appClientset, namespace: namespace, settingsMgr: settingsMrg, enabledNamespaces: enabledNamespaces}
+	return &Handler{
+		appClientset:      appClientset,
+		namespace:         namespace,
+		settingsMgr:       settingsMrg,
+		enabledNamespaces: enabledNamespaces,
+		badges:            gocache.New(badgeCacheExpiration, badgeCacheExpiration),
+		limiters:          gocache.New(limiterExpiration, limiterExpiration),
+	}
 }
 
 // Handler used to get application in order to access health/sync
@@ -32,6 +44,10 @@ type Handler struct {
 	appClientset      versioned.Interface
 	settingsMgr       *settings.SettingsManager
 	enabledNamespaces []string
+	// badges caches the rendered badges by query
+	badges *gocache.Cache
+	// limiters holds the rate limiter of each client address
+	limiters *gocache.Cache
 }
 
 var (
@@ -69,6 +85,14 @@ const (
 	leftRectWidth             = 77
 	widthPerChar              = 6
 	textPositionWidthPerChar  = 62
+
+	// badgeCacheExpiration is the duration a rendered badge is served from the cache
+	badgeCacheExpiration = 30 * time.Second
+	// requestsPerSecond and requestsBurst limit the rate of the unauthenticated badge requests of each client
+	requestsPerSecond = 5
+	requestsBurst     = 20
+	// limiterExpiration is the duration the rate limiter of an idle client is kept
+	limiterExpiration = 5 * time.Minute
 )
 
 func replaceFirstGroupSubMatch(re *regexp.Regexp, str string, repl string) string {
@@ -88,9 +112,85 @@ func replaceFirstGroupSubMatch(re *regexp.Regexp, str string, repl string) strin
 	return result + str[lastIndex:]
 }
 
+// badgeQuery returns the query of a badge request. The /api/badge/applications/{name} and
+// /api/badge/projects/{name} paths are equivalent to the name and project query parameters.
+func badgeQuery(u *url.URL) (url.Values, bool) {
+	query := u.Query()
+	path := strings.Trim(strings.TrimPrefix(u.Path, "/api/badge"), "/")
+	if path == "" {
+		return query, true
+	}
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, false
+	}
+	switch parts[0] {
+	case "applications":
+		query.Set("name", parts[1])
+	case "projects":
+		query.Set("project", parts[1])
+	default:
+		return nil, false
+	}
+	return query, true
+}
+
+// allowRequest returns false if the client of the request exceeded its rate limit
+func (h *Handler) allowRequest(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	var limiter *rate.Limiter
+	if cached, ok := h.limiters.Get(host); ok {
+		limiter = cached.(*rate.Limiter)
+	} else {
+		limiter = rate.NewLimiter(requestsPerSecond, requestsBurst)
+	}
+	// refresh the expiration of the limiter of active clients
+	h.limiters.SetDefault(host, limiter)
+	return limiter.Allow()
+}
+
 // ServeHTTP returns badge with health and sync status for application
 // (or an error badge if wrong query or application name is given)
 func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.allowRequest(r) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	query, ok := badgeQuery(r.URL)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	key := query.Encode()
+	var badge string
+	if cached, ok := h.badges.Get(key); ok {
+		badge = cached.(string)
+	} else {
+		var status int
+		if badge, status = h.renderBadge(query); status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		h.badges.SetDefault(key, badge)
+	}
+
+	w.Header().Set("Content-Type", "image/svg+xml")
+
+	// Ask cache's to not cache the contents in order prevent the badge from becoming stale
+	w.Header().Set("Cache-Control", "private, no-store")
+
+	// Allow badges to be fetched via XHR from frontend applications without running into CORS issues
+	w.Header().Set("Access-Control-Allow-Origin", "*")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(badge))
+}
+
+// renderBadge returns the SVG badge of the query and the HTTP status of the response
+func (h *Handler) renderBadge(query url.Values) (string, int) {
 	health := healthutil.HealthStatusUnknown
 	status := appv1.SyncStatusCodeUnknown
 	revision := ""
@@ -107,7 +207,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	reqNs := ""
-	if ns, ok := r.URL.Query()["namespace"]; ok && enabled {
+	if ns, ok := query["namespace"]; ok && enabled {
 		if argo.IsValidNamespaceName(ns[0]) {
 			if security.IsNamespaceEnabled(ns[0], h.namespace, h.enabledNamespaces) {
 				reqNs = ns[0]
@@ -115,15 +215,14 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				notFound = true
 			}
 		} else {
-			w.WriteHeader(http.StatusBadRequest)
-			return
+			return "", http.StatusBadRequest
 		}
 	} else {
 		reqNs = h.namespace
 	}
 
 	// Sample url: http://localhost:8080/api/badge?name=123
-	if name, ok := r.URL.Query()["name"]; ok && enabled && !notFound {
+	if name, ok := query["name"]; ok && enabled && !notFound {
 		if argo.IsValidAppName(name[0]) {
 			if app, err := h.appClientset.ArgoprojV1alpha1().Applications(reqNs).Get(context.Background(), name[0], v1.GetOptions{}); err == nil {
 				health = app.Status.Health.Status
@@ -136,16 +235,14 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				notFound = true
 			}
 		} else {
-			w.WriteHeader(http.StatusBadRequest)
-			return
+			return "", http.StatusBadRequest
 		}
 	}
 	// Sample url: http://localhost:8080/api/badge?project=default
-	if projects, ok := r.URL.Query()["project"]; ok && enabled && !notFound {
+	if projects, ok := query["project"]; ok && enabled && !notFound {
 		for _, p := range projects {
 			if errs := validation.NameIsDNSLabel(strings.ToLower(p), false); len(p) > 0 && len(errs) != 0 {
-				w.WriteHeader(http.StatusBadRequest)
-				return
+				return "", http.StatusBadRequest
 			}
 		}
 		if apps, err := h.appClientset.ArgoprojV1alpha1().Applications(reqNs).List(context.Background(), v1.ListOptions{}); err == nil {
@@ -154,11 +251,17 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				if a.Status.Sync.Status != appv1.SyncStatusCodeSynced {
 					status = appv1.SyncStatusCodeOutOfSync
 				}
-				if a.Status.Health.Status != healthutil.HealthStatusHealthy {
+				switch a.Status.Health.Status {
+				case healthutil.HealthStatusHealthy:
+				case healthutil.HealthStatusProgressing:
+					if health != healthutil.HealthStatusDegraded {
+						health = healthutil.HealthStatusProgressing
+					}
+				default:
 					health = healthutil.HealthStatusDegraded
 				}
 			}
-			if health != healthutil.HealthStatusDegraded && len(applicationSet) > 0 {
+			if health != healthutil.HealthStatusDegraded && health != healthutil.HealthStatusProgressing && len(applicationSet) > 0 {
 				health = healthutil.HealthStatusHealthy
 			}
 			if status != appv1.SyncStatusCodeOutOfSync && len(applicationSet) > 0 {
@@ -167,7 +270,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 	// Sample url: http://localhost:8080/api/badge?name=123&revision=true
-	if revisionParam, ok := r.URL.Query()["revision"]; ok && enabled && strings.EqualFold(revisionParam[0], "true") {
+	if revisionParam, ok := query["revision"]; ok && enabled && strings.EqualFold(revisionParam[0], "true") {
 		revisionEnabled = true
 	}
 
@@ -206,7 +309,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 		adjustWidth = true
 		displayedRevision = revision
-		if keepFullRevisionParam, ok := r.URL.Query()["keepFullRevision"]; !(ok && strings.EqualFold(keepFullRevisionParam[0], "true")) && len(revision) > 7 {
+		if keepFullRevisionParam, ok := query["keepFullRevision"]; !(ok && strings.EqualFold(keepFullRevisionParam[0], "true")) && len(revision) > 7 {
 			displayedRevision = revision[:7]
 			svgWidth = svgWidthWithRevision
 		} else {
@@ -216,7 +319,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		badge = replaceFirstGroupSubMatch(revisionTextPattern, badge, fmt.Sprintf("(%s)", displayedRevision))
 	}
 
-	if widthParam, ok := r.URL.Query()["width"]; ok && enabled {
+	if widthParam, ok := query["width"]; ok && enabled {
 		width, err := strconv.Atoi(widthParam[0])
 		if err == nil {
 			svgWidth = width
@@ -236,7 +339,7 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if showAppNameParam, ok := r.URL.Query()["showAppName"]; ok && enabled && strings.EqualFold(showAppNameParam[0], "true") {
+	if showAppNameParam, ok := query["showAppName"]; ok && enabled && strings.EqualFold(showAppNameParam[0], "true") {
 		displayAppName = true
 	}
 
@@ -258,13 +361,5 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		badge = svgWidthPattern.ReplaceAllString(badge, fmt.Sprintf(`<svg width="%d" $2`, longerWidth))
 	}
 
-	w.Header().Set("Content-Type", "image/svg+xml")
-
-	// Ask cache's to not cache the contents in order prevent the badge from becoming stale
-	w.Header().Set("Cache-Control", "private, no-store")
-
-	// Allow badges to be fetched via XHR from frontend applications without running into CORS issues
-	w.Header().Set("Access-Control-Allow-Origin", "*")
-	w.WriteHeader(http.StatusOK)
-	_, _ = w.Write([]byte(badge))
+	return badge, http.StatusOK
 }
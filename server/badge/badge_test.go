@@ -137,7 +137,7 @@ func TestHandlerFeatureProjectIsEnabled(t *testing.T) {
 		},
 		{
 			createApplications([]string{"Healthy:Synced", "Healthy:OutOfSync"}, []string{"test-project", "test-project"}, "default"),
-			http.StatusOK, "/api/badge?project=test-project", "default", "Healthy", "OutOfSync", Green, Orange,
+			http.StatusOK, "/api/badge?project=test-project", "default", "Healthy", "OutOfSync", Green, Red,
 		},
 		{
 			createApplications([]string{"Healthy:Synced", "Degraded:Synced"}, []string{"default", "default"}, "test"),
@@ -145,7 +145,7 @@ func TestHandlerFeatureProjectIsEnabled(t *testing.T) {
 		},
 		{
 			createApplications([]string{"Healthy:Synced", "Degraded:OutOfSync"}, []string{"test-project", "test-project"}, "default"),
-			http.StatusOK, "/api/badge?project=test-project", "default", "Degraded", "OutOfSync", Red, Orange,
+			http.StatusOK, "/api/badge?project=test-project", "default", "Degraded", "OutOfSync", Red, Red,
 		},
 		{
 			createApplications([]string{"Healthy:Synced", "Healthy:Synced"}, []string{"test-project", "default"}, "test"),
@@ -153,7 +153,7 @@ func TestHandlerFeatureProjectIsEnabled(t *testing.T) {
 		},
 		{
 			createApplications([]string{"Healthy:OutOfSync", "Healthy:Synced"}, []string{"test-project", "default"}, "default"),
-			http.StatusOK, "/api/badge?project=default&project=test-project", "default", "Healthy", "OutOfSync", Green, Orange,
+			http.StatusOK, "/api/badge?project=default&project=test-project", "default", "Healthy", "OutOfSync", Green, Red,
 		},
 		{
 			createApplications([]string{"Degraded:Synced", "Healthy:Synced"}, []string{"test-project", "default"}, "test"),
@@ -161,7 +161,15 @@ func TestHandlerFeatureProjectIsEnabled(t *testing.T) {
 		},
 		{
 			createApplications([]string{"Degraded:OutOfSync", "Healthy:OutOfSync"}, []string{"test-project", "default"}, "default"),
-			http.StatusOK, "/api/badge?project=default&project=test-project", "default", "Degraded", "OutOfSync", Red, Orange,
+			http.StatusOK, "/api/badge?project=default&project=test-project", "default", "Degraded", "OutOfSync", Red, Red,
+		},
+		{
+			createApplications([]string{"Healthy:Synced", "Progressing:Synced"}, []string{"default", "default"}, "test"),
+			http.StatusOK, "/api/badge?project=default", "test", "Progressing", "Synced", Orange, Green,
+		},
+		{
+			createApplications([]string{"Progressing:Synced", "Degraded:Synced"}, []string{"default", "default"}, "test"),
+			http.StatusOK, "/api/badge/projects/default", "test", "Degraded", "Synced", Red, Green,
 		},
 		{
 			createApplications([]string{"Unknown:Unknown", "Unknown:Unknown"}, []string{"test-project", "default"}, "default"),
@@ -367,6 +375,8 @@ func createApplicationsWithName(appCombo, projectName []string, namespace string
 			return health.HealthStatusHealthy
 		case "Degraded":
 			return health.HealthStatusDegraded
+		case "Progressing":
+			return health.HealthStatusProgressing
 		default:
 			return health.HealthStatusUnknown
 		}
@@ -527,3 +537,105 @@ func TestHandlerApplicationNameInBadgeIsDisabled(t *testing.T) {
 	assert.Equal(t, "\"2\"", logoYCoodPattern.FindStringSubmatch(response)[2])
 	assert.NotContains(t, response, "test-app")
 }
+
+func TestHandlerStatusColors(t *testing.T) {
+	tests := []struct {
+		health      health.HealthStatusCode
+		status      v1alpha1.SyncStatusCode
+		healthColor color.RGBA
+		statusColor color.RGBA
+	}{
+		{health.HealthStatusHealthy, v1alpha1.SyncStatusCodeSynced, Green, Green},
+		{health.HealthStatusHealthy, v1alpha1.SyncStatusCodeOutOfSync, Green, Red},
+		{health.HealthStatusProgressing, v1alpha1.SyncStatusCodeSynced, Orange, Green},
+		{health.HealthStatusProgressing, v1alpha1.SyncStatusCodeOutOfSync, Orange, Red},
+		{health.HealthStatusDegraded, v1alpha1.SyncStatusCodeSynced, Red, Green},
+		{health.HealthStatusDegraded, v1alpha1.SyncStatusCodeOutOfSync, Red, Red},
+		{health.HealthStatusSuspended, v1alpha1.SyncStatusCodeSynced, Grey, Green},
+		{health.HealthStatusMissing, v1alpha1.SyncStatusCodeOutOfSync, Purple, Red},
+		{health.HealthStatusUnknown, v1alpha1.SyncStatusCodeUnknown, Purple, Purple},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s:%s", tt.health, tt.status), func(t *testing.T) {
+			app := testApp()
+			app.Status.Health.Status = tt.health
+			app.Status.Sync.Status = tt.status
+			settingsMgr := settings.NewSettingsManager(context.Background(), fake.NewSimpleClientset(argoCDCm(), argoCDSecret()), "default")
+			handler := NewHandler(appclientset.NewSimpleClientset(app), settingsMgr, "default", []string{})
+			req, err := http.NewRequest(http.MethodGet, "/api/badge/applications/test-app", nil)
+			require.NoError(t, err)
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			require.Equal(t, http.StatusOK, rr.Code)
+			assert.Equal(t, "image/svg+xml", rr.Header().Get("Content-Type"))
+			response := rr.Body.String()
+			assert.Equal(t, toRGBString(tt.healthColor), leftRectColorPattern.FindStringSubmatch(response)[1])
+			assert.Equal(t, toRGBString(tt.statusColor), rightRectColorPattern.FindStringSubmatch(response)[1])
+			assert.Equal(t, string(tt.health), leftTextPattern.FindStringSubmatch(response)[1])
+			assert.Equal(t, string(tt.status), rightTextPattern.FindStringSubmatch(response)[1])
+		})
+	}
+}
+
+func TestHandlerPathNotFound(t *testing.T) {
+	settingsMgr := settings.NewSettingsManager(context.Background(), fake.NewSimpleClientset(argoCDCm(), argoCDSecret()), "default")
+	handler := NewHandler(appclientset.NewSimpleClientset(testApp()), settingsMgr, "default", []string{})
+	for _, path := range []string{"/api/badge/applications", "/api/badge/applications/", "/api/badge/clusters/in-cluster", "/api/badge/applications/test-app/revision"} {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		assert.Equal(t, http.StatusNotFound, rr.Code, path)
+	}
+}
+
+func TestHandlerCachesBadge(t *testing.T) {
+	settingsMgr := settings.NewSettingsManager(context.Background(), fake.NewSimpleClientset(argoCDCm(), argoCDSecret()), "default")
+	appClientset := appclientset.NewSimpleClientset(testApp())
+	handler := NewHandler(appClientset, settingsMgr, "default", []string{})
+	getBadge := func(path string) string {
+		req, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		require.Equal(t, http.StatusOK, rr.Code)
+		return rr.Body.String()
+	}
+	assert.Equal(t, "Synced", rightTextPattern.FindStringSubmatch(getBadge("/api/badge/applications/test-app"))[1])
+
+	app := testApp()
+	app.Status.Sync.Status = v1alpha1.SyncStatusCodeOutOfSync
+	_, err := appClientset.ArgoprojV1alpha1().Applications("default").Update(context.Background(), app, v1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// the badge is served from the cache, which is shared with the equivalent query parameter
+	assert.Equal(t, "Synced", rightTextPattern.FindStringSubmatch(getBadge("/api/badge/applications/test-app"))[1])
+	assert.Equal(t, "Synced", rightTextPattern.FindStringSubmatch(getBadge("/api/badge?name=test-app"))[1])
+	// other queries are rendered again
+	assert.Equal(t, "OutOfSync", rightTextPattern.FindStringSubmatch(getBadge("/api/badge/applications/test-app?showAppName=true"))[1])
+}
+
+func TestHandlerRateLimit(t *testing.T) {
+	settingsMgr := settings.NewSettingsManager(context.Background(), fake.NewSimpleClientset(argoCDCm(), argoCDSecret()), "default")
+	handler := NewHandler(appclientset.NewSimpleClientset(testApp()), settingsMgr, "default", []string{})
+	getBadge := func(remoteAddr string) int {
+		req, err := http.NewRequest(http.MethodGet, "/api/badge/applications/test-app", nil)
+		require.NoError(t, err)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		return rr.Code
+	}
+	// render the badge before, so that the burst is served from the cache
+	require.Equal(t, http.StatusOK, getBadge("192.0.2.3:1234"))
+	for i := 0; i < requestsBurst; i++ {
+		require.Equal(t, http.StatusOK, getBadge("192.0.2.1:1234"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, getBadge("192.0.2.1:5678"))
+	// other clients are not limited
+	assert.Equal(t, http.StatusOK, getBadge("192.0.2.2:1234"))
+}
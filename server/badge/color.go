@@ -21,14 +21,14 @@ var (
 		health.HealthStatusDegraded:    Red,
 		health.HealthStatusHealthy:     Green,
 		health.HealthStatusMissing:     Purple,
-		health.HealthStatusProgressing: Blue,
+		health.HealthStatusProgressing: Orange,
 		health.HealthStatusSuspended:   Grey,
 		health.HealthStatusUnknown:     Purple,
 	}
 
 	SyncStatusColors = map[appv1.SyncStatusCode]color.RGBA{
 		appv1.SyncStatusCodeSynced:    Green,
-		appv1.SyncStatusCodeOutOfSync: Orange,
+		appv1.SyncStatusCodeOutOfSync: Red,
 		appv1.SyncStatusCodeUnknown:   Purple,
 	}
 )
@@ -6,8 +6,11 @@ import (
 	"math"
 	"time"
 
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
 	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	cacheutil "github.com/argoproj/argo-cd/v2/util/cache"
 	appstatecache "github.com/argoproj/argo-cd/v2/util/cache/appstate"
@@ -16,6 +19,9 @@ import (
 
 var ErrCacheMiss = appstatecache.ErrCacheMiss
 
+// dryRunResultExpiration is how long stored dry run sync results are kept
+const dryRunResultExpiration = 24 * time.Hour
+
 type Cache struct {
 	cache                           *appstatecache.Cache
 	connectionStatusCacheExpiration time.Duration
@@ -61,10 +67,22 @@ func (c *Cache) OnAppResourcesTreeChanged(ctx context.Context, appName string, c
 	return c.cache.OnAppResourcesTreeChanged(ctx, appName, callback)
 }
 
+func (c *Cache) OnAppStatusChanged(ctx context.Context, appName string, callback func() error) error {
+	return c.cache.OnAppStatusChanged(ctx, appName, callback)
+}
+
 func (c *Cache) GetAppManagedResources(appName string, res *[]*appv1.ResourceDiff) error {
 	return c.cache.GetAppManagedResources(appName, res)
 }
 
+func (c *Cache) GetResourceHealthEvents(appName string, key kube.ResourceKey) ([]appstatecache.ResourceHealthEvent, error) {
+	return c.cache.GetResourceHealthEvents(appName, key)
+}
+
+func (c *Cache) GetPreSyncSnapshot(appName string, syncID string) (*appstatecache.ResourceSnapshot, error) {
+	return c.cache.GetPreSyncSnapshot(appName, syncID)
+}
+
 func (c *Cache) SetRepoConnectionState(repo string, project string, state *appv1.ConnectionState) error {
 	return c.cache.SetItem(repoConnectionStateKey(repo, project), &state, c.connectionStatusCacheExpiration, state == nil)
 }
@@ -79,6 +97,28 @@ func (c *Cache) GetRepoConnectionState(repo string, project string) (appv1.Conne
 	return res, err
 }
 
+// StoreDryRunResult stores the dry run sync result of the given application under a generated ID, which is returned
+func (c *Cache) StoreDryRunResult(appName string, result *application.DryRunSyncResult) (string, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate dry run result ID: %w", err)
+	}
+	result.AppName = &appName
+	if err := c.cache.SetItem(dryRunResultKey(id.String()), result, dryRunResultExpiration, false); err != nil {
+		return "", fmt.Errorf("failed to store dry run result: %w", err)
+	}
+	return id.String(), nil
+}
+
+// GetDryRunResult returns the dry run sync result stored under the given ID
+func (c *Cache) GetDryRunResult(id string, result *application.DryRunSyncResult) error {
+	return c.cache.GetItem(dryRunResultKey(id), result)
+}
+
+func dryRunResultKey(id string) string {
+	return fmt.Sprintf("dry-run-result|%s", id)
+}
+
 func (c *Cache) GetClusterInfo(server string, res *appv1.ClusterInfo) error {
 	return c.cache.GetClusterInfo(server, res)
 }
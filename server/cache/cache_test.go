@@ -7,7 +7,9 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
 
+	"github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
 	. "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	cacheutil "github.com/argoproj/argo-cd/v2/util/cache"
 	appstatecache "github.com/argoproj/argo-cd/v2/util/cache/appstate"
@@ -56,6 +58,33 @@ func TestCache_GetRepoConnectionState(t *testing.T) {
 	assert.Equal(t, ConnectionState{Status: "my-project-state"}, value)
 }
 
+func TestCache_DryRunResult(t *testing.T) {
+	cache := newFixtures().Cache
+	result := &application.DryRunSyncResult{
+		Project: ptr.To("default"),
+		Result: &SyncOperationResult{
+			Revision:  "abc",
+			Resources: ResourceResults{{Kind: "ConfigMap", Name: "my-map", Status: "Synced", Message: "configmap/my-map created (dry run)"}},
+		},
+	}
+	// cache miss
+	err := cache.GetDryRunResult("unknown", &application.DryRunSyncResult{})
+	assert.Equal(t, ErrCacheMiss, err)
+	// populate cache
+	id, err := cache.StoreDryRunResult("my-app", result)
+	require.NoError(t, err)
+	otherID, err := cache.StoreDryRunResult("my-app", result)
+	require.NoError(t, err)
+	assert.NotEqual(t, id, otherID)
+	// cache hit
+	value := &application.DryRunSyncResult{}
+	err = cache.GetDryRunResult(id, value)
+	require.NoError(t, err)
+	assert.Equal(t, "my-app", value.GetAppName())
+	assert.Equal(t, "default", value.GetProject())
+	assert.Equal(t, result.Result, value.Result)
+}
+
 func TestAddCacheFlagsToCmd(t *testing.T) {
 	cache, err := AddCacheFlagsToCmd(&cobra.Command{})()
 	require.NoError(t, err)
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/argoproj/gitops-engine/pkg/utils/kube"
-	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -21,6 +20,7 @@ import (
 	"github.com/argoproj/argo-cd/v2/util/argo"
 	"github.com/argoproj/argo-cd/v2/util/clusterauth"
 	"github.com/argoproj/argo-cd/v2/util/db"
+	grpc_util "github.com/argoproj/argo-cd/v2/util/grpc"
 	"github.com/argoproj/argo-cd/v2/util/rbac"
 )
 
@@ -211,7 +211,7 @@ func (s *Server) getClusterAndVerifyAccess(ctx context.Context, q *cluster.Clust
 
 	// verify that user can do the specified action inside project where cluster is located
 	if !s.enf.Enforce(ctx.Value("claims"), rbacpolicy.ResourceClusters, action, CreateClusterRBACObject(c.Project, c.Server)) {
-		log.WithField("cluster", q.Server).Warnf("encountered permissions issue while processing request: %v", err)
+		grpc_util.LoggerFromContext(ctx).WithField("cluster", q.Server).Warnf("encountered permissions issue while processing request: %v", err)
 		return nil, common.PermissionDeniedAPIError
 	}
 
@@ -347,7 +347,7 @@ func (s *Server) Delete(ctx context.Context, q *cluster.ClusterQuery) (*cluster.
 	if q.Name != "" {
 		servers, err := s.db.GetClusterServersByName(ctx, q.Name)
 		if err != nil {
-			log.WithField("cluster", q.Name).Warnf("failed to get cluster servers by name: %v", err)
+			grpc_util.LoggerFromContext(ctx).WithField("cluster", q.Name).Warnf("failed to get cluster servers by name: %v", err)
 			return nil, common.PermissionDeniedAPIError
 		}
 		for _, server := range servers {
@@ -366,7 +366,7 @@ func (s *Server) Delete(ctx context.Context, q *cluster.ClusterQuery) (*cluster.
 
 func enforceAndDelete(s *Server, ctx context.Context, server, project string) error {
 	if err := s.enf.EnforceErr(ctx.Value("claims"), rbacpolicy.ResourceClusters, rbacpolicy.ActionDelete, CreateClusterRBACObject(project, server)); err != nil {
-		log.WithField("cluster", server).Warnf("encountered permissions issue while processing request: %v", err)
+		grpc_util.LoggerFromContext(ctx).WithField("cluster", server).Warnf("encountered permissions issue while processing request: %v", err)
 		return common.PermissionDeniedAPIError
 	}
 	if err := s.db.DeleteCluster(ctx, server); err != nil {
@@ -386,25 +386,25 @@ func (s *Server) RotateAuth(ctx context.Context, q *cluster.ClusterQuery) (*clus
 	if q.Name != "" {
 		servers, err = s.db.GetClusterServersByName(ctx, q.Name)
 		if err != nil {
-			log.WithField("cluster", q.Name).Warnf("failed to get cluster servers by name: %v", err)
+			grpc_util.LoggerFromContext(ctx).WithField("cluster", q.Name).Warnf("failed to get cluster servers by name: %v", err)
 			return nil, common.PermissionDeniedAPIError
 		}
 		for _, server := range servers {
 			if err := s.enf.EnforceErr(ctx.Value("claims"), rbacpolicy.ResourceClusters, rbacpolicy.ActionUpdate, CreateClusterRBACObject(clust.Project, server)); err != nil {
-				log.WithField("cluster", server).Warnf("encountered permissions issue while processing request: %v", err)
+				grpc_util.LoggerFromContext(ctx).WithField("cluster", server).Warnf("encountered permissions issue while processing request: %v", err)
 				return nil, common.PermissionDeniedAPIError
 			}
 		}
 	} else {
 		if err := s.enf.EnforceErr(ctx.Value("claims"), rbacpolicy.ResourceClusters, rbacpolicy.ActionUpdate, CreateClusterRBACObject(clust.Project, q.Server)); err != nil {
-			log.WithField("cluster", q.Server).Warnf("encountered permissions issue while processing request: %v", err)
+			grpc_util.LoggerFromContext(ctx).WithField("cluster", q.Server).Warnf("encountered permissions issue while processing request: %v", err)
 			return nil, common.PermissionDeniedAPIError
 		}
 		servers = append(servers, q.Server)
 	}
 
 	for _, server := range servers {
-		logCtx := log.WithField("cluster", server)
+		logCtx := grpc_util.LoggerFromContext(ctx).WithField("cluster", server)
 		logCtx.Info("Rotating auth")
 		restCfg := clust.RESTConfig()
 		if restCfg.BearerToken == "" {
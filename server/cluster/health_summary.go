@@ -0,0 +1,69 @@
+package cluster
+
+import (
+	"encoding/json"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"github.com/argoproj/argo-cd/v2/controller"
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	applisters "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/server/rbacpolicy"
+	"github.com/argoproj/argo-cd/v2/util/rbac"
+	"github.com/argoproj/argo-cd/v2/util/security"
+)
+
+// HealthSummaryPath is the path of the endpoint returning the number of applications per health status of each cluster
+const HealthSummaryPath = "/api/v1/clusters/health-summary"
+
+// HealthSummaryHandler serves the number of applications per health status of each cluster, as exported by the
+// argocd_cluster_app_health_count metric of the application controller. Only the applications the user is allowed to
+// get are counted.
+type HealthSummaryHandler struct {
+	appLister         applisters.ApplicationLister
+	enf               *rbac.Enforcer
+	namespace         string
+	enabledNamespaces []string
+}
+
+// NewHealthSummaryHandler returns a new handler of the cluster health summary endpoint
+func NewHealthSummaryHandler(appLister applisters.ApplicationLister, enf *rbac.Enforcer, namespace string, enabledNamespaces []string) *HealthSummaryHandler {
+	return &HealthSummaryHandler{
+		appLister:         appLister,
+		enf:               enf,
+		namespace:         namespace,
+		enabledNamespaces: enabledNamespaces,
+	}
+}
+
+// ServeHTTP returns the cluster health summary as a JSON object keyed by the destination server of the applications
+func (h *HealthSummaryHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	apps, err := h.appLister.List(labels.Everything())
+	if err != nil {
+		log.Errorf("Failed to list applications: %v", err)
+		http.Error(w, "Failed to list applications", http.StatusInternalServerError)
+		return
+	}
+	claims := r.Context().Value("claims")
+	allowed := make([]*appv1.Application, 0, len(apps))
+	for _, app := range apps {
+		if !security.IsNamespaceEnabled(app.Namespace, h.namespace, h.enabledNamespaces) {
+			continue
+		}
+		if h.enf.Enforce(claims, rbacpolicy.ResourceApplications, rbacpolicy.ActionGet, app.RBACName(h.namespace)) {
+			allowed = append(allowed, app)
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(controller.SummarizeClusterHealth(allowed)); err != nil {
+		log.Errorf("Failed to write the cluster health summary: %v", err)
+	}
+}
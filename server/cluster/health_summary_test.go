@@ -0,0 +1,108 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	applisters "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+func newHealthSummaryTestApp(name, namespace, project, server string, status health.HealthStatusCode) *appv1.Application {
+	return &appv1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: appv1.ApplicationSpec{
+			Project:     project,
+			Destination: appv1.ApplicationDestination{Server: server, Namespace: "default"},
+		},
+		Status: appv1.ApplicationStatus{Health: appv1.HealthStatus{Status: status}},
+	}
+}
+
+func newHealthSummaryTestHandler(t *testing.T, enforce func(object string) bool) *HealthSummaryHandler {
+	t.Helper()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, app := range []*appv1.Application{
+		newHealthSummaryTestApp("a1", test.FakeArgoCDNamespace, "default", "https://cluster-a", health.HealthStatusHealthy),
+		newHealthSummaryTestApp("a2", test.FakeArgoCDNamespace, "default", "https://cluster-a", health.HealthStatusDegraded),
+		newHealthSummaryTestApp("a3", test.FakeArgoCDNamespace, "restricted", "https://cluster-a", health.HealthStatusDegraded),
+		newHealthSummaryTestApp("b1", test.FakeArgoCDNamespace, "default", "https://cluster-b", health.HealthStatusProgressing),
+		newHealthSummaryTestApp("b2", test.FakeArgoCDNamespace, "default", "https://cluster-b", health.HealthStatusHealthy),
+		newHealthSummaryTestApp("c1", test.FakeArgoCDNamespace, "default", "https://cluster-c", health.HealthStatusMissing),
+		newHealthSummaryTestApp("c2", test.FakeArgoCDNamespace, "restricted", "https://cluster-c", health.HealthStatusSuspended),
+		newHealthSummaryTestApp("c3", "apps", "default", "https://cluster-c", health.HealthStatusHealthy),
+		newHealthSummaryTestApp("c4", "disabled", "default", "https://cluster-c", health.HealthStatusHealthy),
+	} {
+		require.NoError(t, indexer.Add(app))
+	}
+	enf := newEnforcer()
+	enf.SetClaimsEnforcerFunc(func(_ jwt.Claims, rvals ...interface{}) bool {
+		return enforce(rvals[3].(string))
+	})
+	return NewHealthSummaryHandler(applisters.NewApplicationLister(indexer), enf, test.FakeArgoCDNamespace, []string{"apps"})
+}
+
+func getHealthSummary(t *testing.T, handler http.Handler, method string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, HealthSummaryPath, nil)
+	req = req.WithContext(context.WithValue(req.Context(), "claims", &jwt.RegisteredClaims{Subject: "admin"}))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	return w
+}
+
+func TestHealthSummaryHandler(t *testing.T) {
+	t.Run("summarizes the health of the applications of each cluster", func(t *testing.T) {
+		handler := newHealthSummaryTestHandler(t, func(string) bool { return true })
+
+		w := getHealthSummary(t, handler, http.MethodGet)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+		var summary map[string]map[string]int
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+		assert.Equal(t, map[string]map[string]int{
+			"https://cluster-a": {"Healthy": 1, "Degraded": 2},
+			"https://cluster-b": {"Progressing": 1, "Healthy": 1},
+			"https://cluster-c": {"Missing": 1, "Suspended": 1, "Healthy": 1},
+		}, summary)
+	})
+
+	t.Run("only counts the applications the user can get", func(t *testing.T) {
+		handler := newHealthSummaryTestHandler(t, func(object string) bool {
+			return !strings.HasPrefix(object, "restricted/")
+		})
+
+		w := getHealthSummary(t, handler, http.MethodGet)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var summary map[string]map[string]int
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &summary))
+		assert.Equal(t, map[string]map[string]int{
+			"https://cluster-a": {"Healthy": 1, "Degraded": 1},
+			"https://cluster-b": {"Progressing": 1, "Healthy": 1},
+			"https://cluster-c": {"Missing": 1, "Healthy": 1},
+		}, summary)
+	})
+
+	t.Run("rejects other methods", func(t *testing.T) {
+		handler := newHealthSummaryTestHandler(t, func(string) bool { return true })
+
+		w := getHealthSummary(t, handler, http.MethodPost)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, http.MethodGet, w.Header().Get("Allow"))
+	})
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/argoproj/argo-cd/v2/server/rbacpolicy"
 	"github.com/argoproj/argo-cd/v2/util/argo"
 	"github.com/argoproj/argo-cd/v2/util/db"
+	grpc_util "github.com/argoproj/argo-cd/v2/util/grpc"
 	jwtutil "github.com/argoproj/argo-cd/v2/util/jwt"
 	"github.com/argoproj/argo-cd/v2/util/rbac"
 	"github.com/argoproj/argo-cd/v2/util/session"
@@ -165,7 +166,7 @@ func (s *Server) ListLinks(ctx context.Context, q *project.ListProjectLinksReque
 	projName := q.GetName()
 
 	if err := s.enf.EnforceErr(ctx.Value("claims"), rbacpolicy.ResourceProjects, rbacpolicy.ActionGet, projName); err != nil {
-		log.WithFields(map[string]interface{}{
+		grpc_util.LoggerFromContext(ctx).WithFields(map[string]interface{}{
 			"project": projName,
 		}).Warnf("unauthorized access to project, error=%v", err.Error())
 		return nil, fmt.Errorf("unauthorized access to project %v", projName)
@@ -192,7 +193,7 @@ func (s *Server) ListLinks(ctx context.Context, q *project.ListProjectLinksReque
 	deeplinksObj := deeplinks.CreateDeepLinksObject(nil, nil, nil, obj)
 	finalList, errorList := deeplinks.EvaluateDeepLinksResponse(deeplinksObj, obj.GetName(), deepLinks)
 	if len(errorList) > 0 {
-		log.Errorf("errorList while evaluating project deep links, %v", strings.Join(errorList, ", "))
+		grpc_util.LoggerFromContext(ctx).Errorf("errorList while evaluating project deep links, %v", strings.Join(errorList, ", "))
 	}
 
 	return finalList, nil
@@ -9,7 +9,6 @@ import (
 
 	"github.com/argoproj/gitops-engine/pkg/utils/kube"
 	"github.com/argoproj/gitops-engine/pkg/utils/text"
-	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
@@ -28,6 +27,7 @@ import (
 	"github.com/argoproj/argo-cd/v2/util/db"
 	"github.com/argoproj/argo-cd/v2/util/errors"
 	"github.com/argoproj/argo-cd/v2/util/git"
+	grpc_util "github.com/argoproj/argo-cd/v2/util/grpc"
 	"github.com/argoproj/argo-cd/v2/util/io"
 	"github.com/argoproj/argo-cd/v2/util/rbac"
 	"github.com/argoproj/argo-cd/v2/util/settings"
@@ -108,14 +108,14 @@ func (s *Server) getConnectionState(ctx context.Context, url string, project str
 		connectionState.Status = appsv1.ConnectionStatusFailed
 		if errors.IsCredentialsConfigurationError(err) {
 			connectionState.Message = "Configuration error - please check the server logs"
-			log.Warnf("could not retrieve repo: %s", err.Error())
+			grpc_util.LoggerFromContext(ctx).Warnf("could not retrieve repo: %s", err.Error())
 		} else {
 			connectionState.Message = fmt.Sprintf("Unable to connect to repository: %v", err)
 		}
 	}
 	err = s.cache.SetRepoConnectionState(url, project, &connectionState)
 	if err != nil {
-		log.Warnf("getConnectionState cache set error %s: %v", url, err)
+		grpc_util.LoggerFromContext(ctx).Warnf("getConnectionState cache set error %s: %v", url, err)
 	}
 	return connectionState
 }
@@ -195,6 +195,7 @@ func (s *Server) ListRepositories(ctx context.Context, q *repositorypkg.RepoQuer
 				Insecure:           repo.IsInsecure(),
 				EnableLFS:          repo.EnableLFS,
 				EnableOCI:          repo.EnableOCI,
+				OCIType:            repo.OCIType,
 				Proxy:              repo.Proxy,
 				Project:            repo.Project,
 				ForceHttpBasicAuth: repo.ForceHttpBasicAuth,
@@ -499,7 +500,7 @@ func (s *Server) DeleteRepository(ctx context.Context, q *repositorypkg.RepoQuer
 
 	// invalidate cache
 	if err := s.cache.SetRepoConnectionState(repo.Repo, repo.Project, nil); err != nil {
-		log.Errorf("error invalidating cache: %v", err)
+		grpc_util.LoggerFromContext(ctx).Errorf("error invalidating cache: %v", err)
 	}
 
 	err = s.db.DeleteRepository(ctx, repo.Repo, repo.Project)
@@ -563,6 +564,7 @@ func (s *Server) ValidateAccess(ctx context.Context, q *repositorypkg.RepoAccess
 		TLSClientCertData:          q.TlsClientCertData,
 		TLSClientCertKey:           q.TlsClientCertKey,
 		EnableOCI:                  q.EnableOci,
+		OCIType:                    q.OciType,
 		GithubAppPrivateKey:        q.GithubAppPrivateKey,
 		GithubAppId:                q.GithubAppID,
 		GithubAppInstallationId:    q.GithubAppInstallationID,
@@ -589,6 +591,74 @@ func (s *Server) ValidateAccess(ctx context.Context, q *repositorypkg.RepoAccess
 	return &repositorypkg.RepoResponse{}, nil
 }
 
+// ImportBundle imports a git bundle into the local copy of a repository kept by the repo server
+func (s *Server) ImportBundle(ctx context.Context, q *repositorypkg.RepoImportBundleRequest) (*repositorypkg.RepoImportBundleResponse, error) {
+	repo, err := s.getRepo(ctx, q.Repo, q.GetAppProject())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.enf.EnforceErr(ctx.Value("claims"), rbacpolicy.ResourceRepositories, rbacpolicy.ActionUpdate, createRBACObject(repo.Project, repo.Repo)); err != nil {
+		return nil, err
+	}
+	if repo.Type == "helm" {
+		return nil, status.Errorf(codes.InvalidArgument, "bundles can only be imported for git repositories")
+	}
+
+	conn, repoClient, err := s.repoClientset.NewRepoServerClient()
+	if err != nil {
+		return nil, err
+	}
+	defer io.Close(conn)
+
+	res, err := repoClient.ImportBundle(ctx, &apiclient.ImportBundleRequest{Repo: repo, Bundle: q.Bundle, Update: q.Update})
+	if err != nil {
+		return nil, err
+	}
+	return &repositorypkg.RepoImportBundleResponse{Refs: res.Refs}, nil
+}
+
+// ExportBundle creates a git bundle containing all references of a repository
+func (s *Server) ExportBundle(ctx context.Context, q *repositorypkg.RepoQuery) (*repositorypkg.RepoBundleResponse, error) {
+	repo, err := s.getRepo(ctx, q.Repo, q.GetAppProject())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.enf.EnforceErr(ctx.Value("claims"), rbacpolicy.ResourceRepositories, rbacpolicy.ActionGet, createRBACObject(repo.Project, repo.Repo)); err != nil {
+		return nil, err
+	}
+	if repo.Type == "helm" {
+		return nil, status.Errorf(codes.InvalidArgument, "bundles can only be exported for git repositories")
+	}
+
+	conn, repoClient, err := s.repoClientset.NewRepoServerClient()
+	if err != nil {
+		return nil, err
+	}
+	defer io.Close(conn)
+
+	res, err := repoClient.ExportBundle(ctx, &apiclient.ExportBundleRequest{Repo: repo})
+	if err != nil {
+		return nil, err
+	}
+	return &repositorypkg.RepoBundleResponse{Bundle: res.Bundle}, nil
+}
+
+// ListCachedKeys returns a page of the keys stored by the repo server in the cache. Since the keys reveal the
+// repositories and applications of all projects, it requires the permission to update all repositories.
+func (s *Server) ListCachedKeys(ctx context.Context, q *repositorypkg.RepoCachedKeysQuery) (*apiclient.CachedKeyList, error) {
+	if err := s.enf.EnforceErr(ctx.Value("claims"), rbacpolicy.ResourceRepositories, rbacpolicy.ActionUpdate, "*"); err != nil {
+		return nil, err
+	}
+
+	conn, repoClient, err := s.repoClientset.NewRepoServerClient()
+	if err != nil {
+		return nil, err
+	}
+	defer io.Close(conn)
+
+	return repoClient.ListCachedKeys(ctx, &apiclient.ListCachedKeysRequest{Cursor: q.Cursor, Count: q.Count})
+}
+
 func (s *Server) testRepo(ctx context.Context, repo *appsv1.Repository) error {
 	conn, repoClient, err := s.repoClientset.NewRepoServerClient()
 	if err != nil {
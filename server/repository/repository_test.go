@@ -435,6 +435,69 @@ func TestRepositoryServer(t *testing.T) {
 		require.NoError(t, err)
 		assert.Len(t, resp.Items, 2)
 	})
+
+	t.Run("Test_ImportBundle", func(t *testing.T) {
+		url := "https://test"
+		repoServerClient := mocks.RepoServerServiceClient{}
+		repoServerClient.On("ImportBundle", mock.Anything, &apiclient.ImportBundleRequest{Repo: &appsv1.Repository{Repo: url}, Bundle: []byte("bundle"), Update: true}).
+			Return(&apiclient.ImportBundleResponse{Refs: []string{"refs/heads/main"}}, nil)
+		repoServerClientset := mocks.Clientset{RepoServerServiceClient: &repoServerClient}
+		db := &dbmocks.ArgoDB{}
+		db.On("GetRepository", context.TODO(), url, "").Return(&appsv1.Repository{Repo: url}, nil)
+
+		s := NewServer(&repoServerClientset, db, enforcer, newFixtures().Cache, appLister, projInformer, testNamespace, settingsMgr)
+		resp, err := s.ImportBundle(context.TODO(), &repository.RepoImportBundleRequest{Repo: url, Bundle: []byte("bundle"), Update: true})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"refs/heads/main"}, resp.Refs)
+	})
+
+	t.Run("Test_ImportBundleHelmRepository", func(t *testing.T) {
+		url := "https://test"
+		repoServerClientset := mocks.Clientset{RepoServerServiceClient: &mocks.RepoServerServiceClient{}}
+		db := &dbmocks.ArgoDB{}
+		db.On("GetRepository", context.TODO(), url, "").Return(&appsv1.Repository{Repo: url, Type: "helm"}, nil)
+
+		s := NewServer(&repoServerClientset, db, enforcer, newFixtures().Cache, appLister, projInformer, testNamespace, settingsMgr)
+		_, err := s.ImportBundle(context.TODO(), &repository.RepoImportBundleRequest{Repo: url, Bundle: []byte("bundle")})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("Test_ExportBundle", func(t *testing.T) {
+		url := "https://test"
+		repoServerClient := mocks.RepoServerServiceClient{}
+		repoServerClient.On("ExportBundle", mock.Anything, &apiclient.ExportBundleRequest{Repo: &appsv1.Repository{Repo: url}}).
+			Return(&apiclient.ExportBundleResponse{Bundle: []byte("bundle")}, nil)
+		repoServerClientset := mocks.Clientset{RepoServerServiceClient: &repoServerClient}
+		db := &dbmocks.ArgoDB{}
+		db.On("GetRepository", context.TODO(), url, "").Return(&appsv1.Repository{Repo: url}, nil)
+
+		s := NewServer(&repoServerClientset, db, enforcer, newFixtures().Cache, appLister, projInformer, testNamespace, settingsMgr)
+		resp, err := s.ExportBundle(context.TODO(), &repository.RepoQuery{Repo: url})
+		require.NoError(t, err)
+		assert.Equal(t, []byte("bundle"), resp.Bundle)
+	})
+
+	t.Run("Test_ListCachedKeys", func(t *testing.T) {
+		keys := &apiclient.CachedKeyList{Items: []*apiclient.CachedKey{{Key: "ldir|https://test|HEAD|1.8.3", Type: "app-list", TtlSeconds: 60, SizeBytes: 10}}, Cursor: 42}
+		repoServerClient := mocks.RepoServerServiceClient{}
+		repoServerClient.On("ListCachedKeys", mock.Anything, &apiclient.ListCachedKeysRequest{Cursor: 1, Count: 10}).Return(keys, nil)
+		repoServerClientset := mocks.Clientset{RepoServerServiceClient: &repoServerClient}
+
+		s := NewServer(&repoServerClientset, argoDB, enforcer, newFixtures().Cache, appLister, projInformer, testNamespace, settingsMgr)
+		resp, err := s.ListCachedKeys(context.TODO(), &repository.RepoCachedKeysQuery{Cursor: 1, Count: 10})
+		require.NoError(t, err)
+		assert.Equal(t, keys, resp)
+	})
+
+	t.Run("Test_ListCachedKeysWithoutAdminPrivileges", func(t *testing.T) {
+		repoServerClientset := mocks.Clientset{RepoServerServiceClient: &mocks.RepoServerServiceClient{}}
+		enforcer := newEnforcer(kubeclientset)
+		enforcer.SetDefaultRole("role:readonly")
+
+		s := NewServer(&repoServerClientset, argoDB, enforcer, newFixtures().Cache, appLister, projInformer, testNamespace, settingsMgr)
+		_, err := s.ListCachedKeys(context.TODO(), &repository.RepoCachedKeysQuery{})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
 }
 
 func TestRepositoryServerListApps(t *testing.T) {
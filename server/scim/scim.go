@@ -0,0 +1,863 @@
+package scim
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/util/password"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+const (
+	// URLPrefix is the path prefix of the SCIM endpoints
+	URLPrefix = "/scim/v2"
+	// SettingTokenKey is the key of argocd-secret holding the bearer token of SCIM clients. SCIM is disabled when empty.
+	SettingTokenKey = "scim.token"
+	// RBACPolicyKey is the key of argocd-rbac-cm holding the group memberships of SCIM users
+	RBACPolicyKey = "policy.scim.csv"
+	// RBACGroupsKey is the key of argocd-rbac-cm holding the names of the SCIM groups, one per line
+	RBACGroupsKey = "scim.groups"
+
+	userSchema         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	groupSchema        = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	listResponseSchema = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	patchOpSchema      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	errorSchema        = "urn:ietf:params:scim:api:messages:2.0:Error"
+	schemaSchema       = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+	contentType        = "application/scim+json"
+)
+
+// filterPattern matches the only filter supported by the list endpoints, e.g. `userName eq "alice"`
+var filterPattern = regexp.MustCompile(`^\s*(\w+)\s+eq\s+"([^"]*)"\s*$`)
+
+// memberPathPattern matches the path of a patch operation removing a single group member
+var memberPathPattern = regexp.MustCompile(`^members\[value eq "([^"]*)"\]$`)
+
+// Handler serves the SCIM 2.0 Users and Groups endpoints. Users are local accounts in argocd-cm, groups are RBAC
+// groups whose memberships are kept in argocd-rbac-cm.
+type Handler struct {
+	namespace     string
+	kubeClientset kubernetes.Interface
+	settingsMgr   *settings.SettingsManager
+}
+
+// NewHandler creates a handler serving the SCIM endpoints under URLPrefix
+func NewHandler(namespace string, kubeClientset kubernetes.Interface, settingsMgr *settings.SettingsManager) *Handler {
+	return &Handler{namespace: namespace, kubeClientset: kubeClientset, settingsMgr: settingsMgr}
+}
+
+// User is a SCIM user resource
+type User struct {
+	Schemas  []string `json:"schemas"`
+	ID       string   `json:"id,omitempty"`
+	UserName string   `json:"userName"`
+	Active   *bool    `json:"active,omitempty"`
+	Password string   `json:"password,omitempty"`
+	Groups   []Member `json:"groups,omitempty"`
+	Meta     *Meta    `json:"meta,omitempty"`
+}
+
+// Group is a SCIM group resource
+type Group struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id,omitempty"`
+	DisplayName string   `json:"displayName"`
+	Members     []Member `json:"members,omitempty"`
+	Meta        *Meta    `json:"meta,omitempty"`
+}
+
+// Member references a user from a group, or a group from a user
+type Member struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+}
+
+// Meta holds the metadata of a SCIM resource
+type Meta struct {
+	ResourceType string `json:"resourceType"`
+	Location     string `json:"location"`
+}
+
+type listResponse struct {
+	Schemas      []string      `json:"schemas"`
+	TotalResults int           `json:"totalResults"`
+	StartIndex   int           `json:"startIndex"`
+	ItemsPerPage int           `json:"itemsPerPage"`
+	Resources    []interface{} `json:"Resources"`
+}
+
+type patchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []patchOperation `json:"Operations"`
+}
+
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+type scimError struct {
+	Schemas []string `json:"schemas"`
+	Status  string   `json:"status"`
+	Detail  string   `json:"detail"`
+}
+
+// httpError is an error with the HTTP status returned to the SCIM client
+type httpError struct {
+	status int
+	detail string
+}
+
+func (e *httpError) Error() string {
+	return e.detail
+}
+
+func newHTTPError(status int, format string, args ...interface{}) error {
+	return &httpError{status: status, detail: fmt.Sprintf(format, args...)}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := h.authenticate(r); err != nil {
+		writeError(w, err)
+		return
+	}
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, URLPrefix), "/")
+	resource, id, _ := strings.Cut(path, "/")
+	var err error
+	switch {
+	case resource == "Schemas" && id == "" && r.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, schemas())
+	case resource == "Users" && id == "" && r.Method == http.MethodGet:
+		err = h.listUsers(w, r)
+	case resource == "Users" && id == "" && r.Method == http.MethodPost:
+		err = h.createUser(w, r)
+	case resource == "Users" && id != "" && r.Method == http.MethodGet:
+		err = h.getUser(w, id)
+	case resource == "Users" && id != "" && r.Method == http.MethodPut:
+		err = h.replaceUser(w, r, id)
+	case resource == "Users" && id != "" && r.Method == http.MethodPatch:
+		err = h.patchUser(w, r, id)
+	case resource == "Users" && id != "" && r.Method == http.MethodDelete:
+		err = h.deleteUser(w, id)
+	case resource == "Groups" && id == "" && r.Method == http.MethodGet:
+		err = h.listGroups(w, r)
+	case resource == "Groups" && id == "" && r.Method == http.MethodPost:
+		err = h.createGroup(w, r)
+	case resource == "Groups" && id != "" && r.Method == http.MethodGet:
+		err = h.getGroup(w, id)
+	case resource == "Groups" && id != "" && r.Method == http.MethodPut:
+		err = h.replaceGroup(w, r, id)
+	case resource == "Groups" && id != "" && r.Method == http.MethodPatch:
+		err = h.patchGroup(w, r, id)
+	case resource == "Groups" && id != "" && r.Method == http.MethodDelete:
+		err = h.deleteGroup(w, id)
+	default:
+		err = newHTTPError(http.StatusNotFound, "%s %s is not supported", r.Method, r.URL.Path)
+	}
+	if err != nil {
+		writeError(w, err)
+	}
+}
+
+// authenticate verifies the bearer token of the request against the token configured in argocd-secret
+func (h *Handler) authenticate(r *http.Request) error {
+	argoSettings, err := h.settingsMgr.GetSettings()
+	if err != nil {
+		return fmt.Errorf("error getting settings: %w", err)
+	}
+	expected := argoSettings.Secrets[SettingTokenKey]
+	if expected == "" {
+		return newHTTPError(http.StatusNotFound, "SCIM provisioning is not enabled")
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		return newHTTPError(http.StatusUnauthorized, "invalid bearer token")
+	}
+	return nil
+}
+
+func (h *Handler) listUsers(w http.ResponseWriter, r *http.Request) error {
+	attribute, value, err := parseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		return err
+	}
+	if attribute != "" && attribute != "userName" && attribute != "id" {
+		return newHTTPError(http.StatusBadRequest, "filtering users by %s is not supported", attribute)
+	}
+	accounts, err := h.settingsMgr.GetAccounts()
+	if err != nil {
+		return fmt.Errorf("error getting accounts: %w", err)
+	}
+	memberships, _, err := h.getMemberships()
+	if err != nil {
+		return err
+	}
+	var users []interface{}
+	for _, name := range sortedAccountNames(accounts) {
+		if (value != "" && name != value) || accounts[name].ManagedBy != settings.AccountManagedBySCIM {
+			continue
+		}
+		users = append(users, toUser(name, accounts[name], memberships))
+	}
+	writeJSON(w, http.StatusOK, newListResponse(users))
+	return nil
+}
+
+func (h *Handler) getUser(w http.ResponseWriter, name string) error {
+	account, err := h.getAccount(name)
+	if err != nil {
+		return err
+	}
+	memberships, _, err := h.getMemberships()
+	if err != nil {
+		return err
+	}
+	writeJSON(w, http.StatusOK, toUser(name, *account, memberships))
+	return nil
+}
+
+func (h *Handler) createUser(w http.ResponseWriter, r *http.Request) error {
+	var user User
+	if err := decode(r, &user); err != nil {
+		return err
+	}
+	if err := validateName(user.UserName); err != nil {
+		return err
+	}
+	account := settings.Account{
+		Enabled:      true,
+		Capabilities: []settings.AccountCapability{settings.AccountCapabilityLogin},
+		ManagedBy:    settings.AccountManagedBySCIM,
+	}
+	if err := applyUser(&account, user); err != nil {
+		return err
+	}
+	if err := h.settingsMgr.AddAccount(user.UserName, account); err != nil {
+		return convertError(err)
+	}
+	log.WithField("user", user.UserName).Info("SCIM user created")
+	writeJSON(w, http.StatusCreated, toUser(user.UserName, account, nil))
+	return nil
+}
+
+func (h *Handler) replaceUser(w http.ResponseWriter, r *http.Request, name string) error {
+	var user User
+	if err := decode(r, &user); err != nil {
+		return err
+	}
+	if user.UserName != "" && user.UserName != name {
+		return newHTTPError(http.StatusBadRequest, "userName of user %s cannot be changed", name)
+	}
+	if user.Active == nil {
+		active := true
+		user.Active = &active
+	}
+	return h.updateUser(w, name, func(account *settings.Account) error {
+		return applyUser(account, user)
+	})
+}
+
+func (h *Handler) patchUser(w http.ResponseWriter, r *http.Request, name string) error {
+	var patch patchRequest
+	if err := decode(r, &patch); err != nil {
+		return err
+	}
+	return h.updateUser(w, name, func(account *settings.Account) error {
+		for _, op := range patch.Operations {
+			if !strings.EqualFold(op.Op, "replace") && !strings.EqualFold(op.Op, "add") {
+				return newHTTPError(http.StatusBadRequest, "patch operation %s is not supported for users", op.Op)
+			}
+			var user User
+			switch op.Path {
+			case "":
+				if err := json.Unmarshal(op.Value, &user); err != nil {
+					return newHTTPError(http.StatusBadRequest, "invalid patch value: %v", err)
+				}
+			case "active":
+				if err := json.Unmarshal(op.Value, &user.Active); err != nil {
+					return newHTTPError(http.StatusBadRequest, "invalid patch value: %v", err)
+				}
+			case "password":
+				if err := json.Unmarshal(op.Value, &user.Password); err != nil {
+					return newHTTPError(http.StatusBadRequest, "invalid patch value: %v", err)
+				}
+			default:
+				return newHTTPError(http.StatusBadRequest, "patching user attribute %s is not supported", op.Path)
+			}
+			if err := applyUser(account, user); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (h *Handler) updateUser(w http.ResponseWriter, name string, update func(account *settings.Account) error) error {
+	if _, err := h.getAccount(name); err != nil {
+		return err
+	}
+	if err := h.settingsMgr.UpdateAccount(name, update); err != nil {
+		return convertError(err)
+	}
+	return h.getUser(w, name)
+}
+
+func (h *Handler) deleteUser(w http.ResponseWriter, name string) error {
+	if _, err := h.getAccount(name); err != nil {
+		return err
+	}
+	if err := h.updateMemberships(func(memberships map[string][]string, groups []string) ([]string, error) {
+		for group, members := range memberships {
+			memberships[group] = removeMember(members, name)
+		}
+		return groups, nil
+	}); err != nil {
+		return err
+	}
+	if err := h.settingsMgr.RemoveAccount(name); err != nil {
+		return convertError(err)
+	}
+	log.WithField("user", name).Info("SCIM user deleted")
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// getAccount returns the local account with the given name if it was created by SCIM. The other local accounts, such
+// as the admin account, are not managed by SCIM and not found.
+func (h *Handler) getAccount(name string) (*settings.Account, error) {
+	account, err := h.settingsMgr.GetAccount(name)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	if account.ManagedBy != settings.AccountManagedBySCIM {
+		return nil, newHTTPError(http.StatusNotFound, "user %s not found", name)
+	}
+	return account, nil
+}
+
+func (h *Handler) listGroups(w http.ResponseWriter, r *http.Request) error {
+	attribute, value, err := parseFilter(r.URL.Query().Get("filter"))
+	if err != nil {
+		return err
+	}
+	if attribute != "" && attribute != "displayName" && attribute != "id" {
+		return newHTTPError(http.StatusBadRequest, "filtering groups by %s is not supported", attribute)
+	}
+	memberships, groups, err := h.getMemberships()
+	if err != nil {
+		return err
+	}
+	var resources []interface{}
+	for _, name := range groups {
+		if value != "" && name != value {
+			continue
+		}
+		resources = append(resources, toGroup(name, memberships[name]))
+	}
+	writeJSON(w, http.StatusOK, newListResponse(resources))
+	return nil
+}
+
+func (h *Handler) getGroup(w http.ResponseWriter, name string) error {
+	memberships, groups, err := h.getMemberships()
+	if err != nil {
+		return err
+	}
+	if !containsString(groups, name) {
+		return newHTTPError(http.StatusNotFound, "group %s not found", name)
+	}
+	writeJSON(w, http.StatusOK, toGroup(name, memberships[name]))
+	return nil
+}
+
+func (h *Handler) createGroup(w http.ResponseWriter, r *http.Request) error {
+	var group Group
+	if err := decode(r, &group); err != nil {
+		return err
+	}
+	if err := validateName(group.DisplayName); err != nil {
+		return err
+	}
+	members, err := h.memberNames(group.Members)
+	if err != nil {
+		return err
+	}
+	err = h.updateMemberships(func(memberships map[string][]string, groups []string) ([]string, error) {
+		if containsString(groups, group.DisplayName) {
+			return nil, newHTTPError(http.StatusConflict, "group %s already exists", group.DisplayName)
+		}
+		memberships[group.DisplayName] = members
+		return append(groups, group.DisplayName), nil
+	})
+	if err != nil {
+		return err
+	}
+	log.WithField("group", group.DisplayName).Info("SCIM group created")
+	writeJSON(w, http.StatusCreated, toGroup(group.DisplayName, members))
+	return nil
+}
+
+func (h *Handler) replaceGroup(w http.ResponseWriter, r *http.Request, name string) error {
+	var group Group
+	if err := decode(r, &group); err != nil {
+		return err
+	}
+	if group.DisplayName != "" && group.DisplayName != name {
+		return newHTTPError(http.StatusBadRequest, "displayName of group %s cannot be changed", name)
+	}
+	members, err := h.memberNames(group.Members)
+	if err != nil {
+		return err
+	}
+	return h.updateGroup(w, name, func(current []string) ([]string, error) {
+		return members, nil
+	})
+}
+
+func (h *Handler) patchGroup(w http.ResponseWriter, r *http.Request, name string) error {
+	var patch patchRequest
+	if err := decode(r, &patch); err != nil {
+		return err
+	}
+	type memberChange struct {
+		op      string
+		members []string
+	}
+	var changes []memberChange
+	for _, op := range patch.Operations {
+		change := memberChange{op: strings.ToLower(op.Op)}
+		switch {
+		case op.Path == "members":
+			if len(op.Value) > 0 {
+				var members []Member
+				if err := json.Unmarshal(op.Value, &members); err != nil {
+					return newHTTPError(http.StatusBadRequest, "invalid patch value: %v", err)
+				}
+				if change.op == "remove" {
+					change.members = []string{}
+					for _, member := range members {
+						change.members = append(change.members, member.Value)
+					}
+				} else {
+					names, err := h.memberNames(members)
+					if err != nil {
+						return err
+					}
+					change.members = names
+				}
+			}
+		case strings.HasPrefix(op.Path, "members[") && change.op == "remove":
+			match := memberPathPattern.FindStringSubmatch(op.Path)
+			if match == nil {
+				return newHTTPError(http.StatusBadRequest, "invalid patch path %s", op.Path)
+			}
+			change.members = []string{match[1]}
+		default:
+			return newHTTPError(http.StatusBadRequest, "patching group attribute %s is not supported", op.Path)
+		}
+		if change.op != "add" && change.op != "remove" && change.op != "replace" {
+			return newHTTPError(http.StatusBadRequest, "patch operation %s is not supported", op.Op)
+		}
+		changes = append(changes, change)
+	}
+	return h.updateGroup(w, name, func(members []string) ([]string, error) {
+		for _, change := range changes {
+			switch change.op {
+			case "add":
+				for _, member := range change.members {
+					if !containsString(members, member) {
+						members = append(members, member)
+					}
+				}
+			case "remove":
+				if change.members == nil {
+					members = nil
+				}
+				for _, member := range change.members {
+					members = removeMember(members, member)
+				}
+			case "replace":
+				members = change.members
+			}
+		}
+		return members, nil
+	})
+}
+
+func (h *Handler) updateGroup(w http.ResponseWriter, name string, update func(members []string) ([]string, error)) error {
+	var members []string
+	err := h.updateMemberships(func(memberships map[string][]string, groups []string) ([]string, error) {
+		if !containsString(groups, name) {
+			return nil, newHTTPError(http.StatusNotFound, "group %s not found", name)
+		}
+		var err error
+		members, err = update(memberships[name])
+		if err != nil {
+			return nil, err
+		}
+		memberships[name] = members
+		return groups, nil
+	})
+	if err != nil {
+		return err
+	}
+	log.WithField("group", name).Info("SCIM group updated")
+	writeJSON(w, http.StatusOK, toGroup(name, members))
+	return nil
+}
+
+func (h *Handler) deleteGroup(w http.ResponseWriter, name string) error {
+	err := h.updateMemberships(func(memberships map[string][]string, groups []string) ([]string, error) {
+		if !containsString(groups, name) {
+			return nil, newHTTPError(http.StatusNotFound, "group %s not found", name)
+		}
+		delete(memberships, name)
+		return removeMember(groups, name), nil
+	})
+	if err != nil {
+		return err
+	}
+	log.WithField("group", name).Info("SCIM group deleted")
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// memberNames returns the names of the referenced users, which must be local accounts created by SCIM
+func (h *Handler) memberNames(members []Member) ([]string, error) {
+	if len(members) == 0 {
+		return nil, nil
+	}
+	accounts, err := h.settingsMgr.GetAccounts()
+	if err != nil {
+		return nil, fmt.Errorf("error getting accounts: %w", err)
+	}
+	var names []string
+	for _, member := range members {
+		if account, ok := accounts[member.Value]; !ok || account.ManagedBy != settings.AccountManagedBySCIM {
+			return nil, newHTTPError(http.StatusNotFound, "user %s not found", member.Value)
+		}
+		if !containsString(names, member.Value) {
+			names = append(names, member.Value)
+		}
+	}
+	return names, nil
+}
+
+// getMemberships returns the members of each SCIM group and the sorted names of the SCIM groups
+func (h *Handler) getMemberships() (map[string][]string, []string, error) {
+	cm, err := h.kubeClientset.CoreV1().ConfigMaps(h.namespace).Get(context.Background(), common.ArgoCDRBACConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("error getting %s: %w", common.ArgoCDRBACConfigMapName, err)
+	}
+	return parseMemberships(cm.Data)
+}
+
+// updateMemberships updates the group memberships and the SCIM groups in argocd-rbac-cm. The update returns the new
+// list of groups.
+func (h *Handler) updateMemberships(update func(memberships map[string][]string, groups []string) ([]string, error)) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		cm, err := h.kubeClientset.CoreV1().ConfigMaps(h.namespace).Get(context.Background(), common.ArgoCDRBACConfigMapName, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("error getting %s: %w", common.ArgoCDRBACConfigMapName, err)
+		}
+		memberships, groups, err := parseMemberships(cm.Data)
+		if err != nil {
+			return err
+		}
+		groups, err = update(memberships, groups)
+		if err != nil {
+			return err
+		}
+		policy, err := formatMemberships(memberships, groups)
+		if err != nil {
+			return err
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[RBACPolicyKey] = policy
+		sort.Strings(groups)
+		cm.Data[RBACGroupsKey] = strings.Join(groups, "\n")
+		_, err = h.kubeClientset.CoreV1().ConfigMaps(h.namespace).Update(context.Background(), cm, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// parseMemberships parses the `g, <user>, <group>` lines of the SCIM policy and the list of SCIM groups
+func parseMemberships(data map[string]string) (map[string][]string, []string, error) {
+	memberships := map[string][]string{}
+	reader := csv.NewReader(strings.NewReader(data[RBACPolicyKey]))
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = 3
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing %s: %w", RBACPolicyKey, err)
+	}
+	for _, record := range records {
+		if record[0] != "g" {
+			return nil, nil, fmt.Errorf("error parsing %s: unexpected policy type %s", RBACPolicyKey, record[0])
+		}
+		memberships[record[2]] = append(memberships[record[2]], record[1])
+	}
+	var groups []string
+	for _, group := range strings.Split(data[RBACGroupsKey], "\n") {
+		if group != "" {
+			groups = append(groups, group)
+		}
+	}
+	sort.Strings(groups)
+	return memberships, groups, nil
+}
+
+func formatMemberships(memberships map[string][]string, groups []string) (string, error) {
+	var sb strings.Builder
+	writer := csv.NewWriter(&sb)
+	sorted := append([]string{}, groups...)
+	sort.Strings(sorted)
+	for _, group := range sorted {
+		members := append([]string{}, memberships[group]...)
+		sort.Strings(members)
+		for _, member := range members {
+			if err := writer.Write([]string{"g", member, group}); err != nil {
+				return "", fmt.Errorf("error formatting %s: %w", RBACPolicyKey, err)
+			}
+		}
+	}
+	writer.Flush()
+	return strings.ReplaceAll(sb.String(), ",", ", "), writer.Error()
+}
+
+func applyUser(account *settings.Account, user User) error {
+	if user.Active != nil {
+		account.Enabled = *user.Active
+	}
+	if user.Password != "" {
+		hash, err := password.HashPassword(user.Password)
+		if err != nil {
+			return fmt.Errorf("error hashing password: %w", err)
+		}
+		account.PasswordHash = hash
+	}
+	return nil
+}
+
+func toUser(name string, account settings.Account, memberships map[string][]string) User {
+	active := account.Enabled
+	user := User{
+		Schemas:  []string{userSchema},
+		ID:       name,
+		UserName: name,
+		Active:   &active,
+		Meta:     &Meta{ResourceType: "User", Location: URLPrefix + "/Users/" + name},
+	}
+	var groups []string
+	for group, members := range memberships {
+		if containsString(members, name) {
+			groups = append(groups, group)
+		}
+	}
+	sort.Strings(groups)
+	for _, group := range groups {
+		user.Groups = append(user.Groups, Member{Value: group, Display: group})
+	}
+	return user
+}
+
+func toGroup(name string, members []string) Group {
+	group := Group{
+		Schemas:     []string{groupSchema},
+		ID:          name,
+		DisplayName: name,
+		Meta:        &Meta{ResourceType: "Group", Location: URLPrefix + "/Groups/" + name},
+	}
+	for _, member := range members {
+		group.Members = append(group.Members, Member{Value: member, Display: member})
+	}
+	return group
+}
+
+func sortedAccountNames(accounts map[string]settings.Account) []string {
+	var names []string
+	for name := range accounts {
+		if name != common.ArgoCDAdminUsername {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newListResponse(resources []interface{}) listResponse {
+	if resources == nil {
+		resources = []interface{}{}
+	}
+	return listResponse{
+		Schemas:      []string{listResponseSchema},
+		TotalResults: len(resources),
+		StartIndex:   1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// parseFilter parses a filter of the form `<attribute> eq "<value>"`
+func parseFilter(filter string) (string, string, error) {
+	if filter == "" {
+		return "", "", nil
+	}
+	match := filterPattern.FindStringSubmatch(filter)
+	if match == nil {
+		return "", "", newHTTPError(http.StatusBadRequest, "unsupported filter %q: only the eq operator is supported", filter)
+	}
+	return match[1], match[2], nil
+}
+
+// validateName verifies that the name can be used as an account name and as an RBAC subject. Names containing ':' are
+// rejected, so that a SCIM group cannot be named after an RBAC role, e.g. role:admin.
+func validateName(name string) error {
+	if name == "" {
+		return newHTTPError(http.StatusBadRequest, "name must not be empty")
+	}
+	if strings.ContainsAny(name, ".,:\"\n/") || name == common.ArgoCDAdminUsername {
+		return newHTTPError(http.StatusBadRequest, "invalid name %q", name)
+	}
+	return nil
+}
+
+func decode(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return newHTTPError(http.StatusBadRequest, "invalid request body: %v", err)
+	}
+	return nil
+}
+
+// convertError converts errors of the settings manager to HTTP errors
+func convertError(err error) error {
+	switch status.Code(err) {
+	case codes.NotFound:
+		return newHTTPError(http.StatusNotFound, "%s", status.Convert(err).Message())
+	case codes.AlreadyExists:
+		return newHTTPError(http.StatusConflict, "%s", status.Convert(err).Message())
+	case codes.InvalidArgument:
+		return newHTTPError(http.StatusBadRequest, "%s", status.Convert(err).Message())
+	}
+	if apierrors.IsConflict(err) {
+		return newHTTPError(http.StatusConflict, "%s", err.Error())
+	}
+	return err
+}
+
+func removeMember(members []string, name string) []string {
+	var result []string
+	for _, member := range members {
+		if member != name {
+			result = append(result, member)
+		}
+	}
+	return result
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Warnf("Failed to write SCIM response: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var httpErr *httpError
+	if !errors.As(err, &httpErr) {
+		log.Errorf("SCIM request failed: %v", err)
+		httpErr = &httpError{status: http.StatusInternalServerError, detail: "internal error"}
+	}
+	writeJSON(w, httpErr.status, scimError{
+		Schemas: []string{errorSchema},
+		Status:  fmt.Sprintf("%d", httpErr.status),
+		Detail:  httpErr.detail,
+	})
+}
+
+// schemas returns the definitions of the supported resource schemas
+func schemas() listResponse {
+	attribute := func(name, typ string, required bool, mutability string) map[string]interface{} {
+		return map[string]interface{}{
+			"name":        name,
+			"type":        typ,
+			"multiValued": false,
+			"required":    required,
+			"caseExact":   true,
+			"mutability":  mutability,
+			"returned":    "default",
+			"uniqueness":  "none",
+		}
+	}
+	multiValued := func(name string, mutability string) map[string]interface{} {
+		a := attribute(name, "complex", false, mutability)
+		a["multiValued"] = true
+		a["subAttributes"] = []map[string]interface{}{
+			attribute("value", "string", false, "immutable"),
+			attribute("display", "string", false, "readOnly"),
+		}
+		return a
+	}
+	userName := attribute("userName", "string", true, "immutable")
+	userName["uniqueness"] = "server"
+	passwordAttribute := attribute("password", "string", false, "writeOnly")
+	passwordAttribute["returned"] = "never"
+	displayName := attribute("displayName", "string", true, "immutable")
+	displayName["uniqueness"] = "server"
+	return newListResponse([]interface{}{
+		map[string]interface{}{
+			"schemas":     []string{schemaSchema},
+			"id":          userSchema,
+			"name":        "User",
+			"description": "Argo CD local account",
+			"attributes": []map[string]interface{}{
+				userName,
+				attribute("active", "boolean", false, "readWrite"),
+				passwordAttribute,
+				multiValued("groups", "readOnly"),
+			},
+			"meta": Meta{ResourceType: "Schema", Location: URLPrefix + "/Schemas/" + userSchema},
+		},
+		map[string]interface{}{
+			"schemas":     []string{schemaSchema},
+			"id":          groupSchema,
+			"name":        "Group",
+			"description": "Argo CD RBAC group",
+			"attributes": []map[string]interface{}{
+				displayName,
+				multiValued("members", "readWrite"),
+			},
+			"meta": Meta{ResourceType: "Schema", Location: URLPrefix + "/Schemas/" + groupSchema},
+		},
+	})
+}
@@ -0,0 +1,204 @@
+package scim
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+const testNamespace = "default"
+
+func newTestHandler(t *testing.T, token string) (*Handler, *fake.Clientset) {
+	t.Helper()
+	secret := &corev1.Secret{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "argocd-secret",
+			Namespace: testNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/part-of": "argocd"},
+		},
+		Data: map[string][]byte{
+			"admin.password":   []byte("test"),
+			"server.secretkey": []byte("test"),
+		},
+	}
+	if token != "" {
+		secret.Data[SettingTokenKey] = []byte(token)
+	}
+	cm := &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "argocd-cm",
+			Namespace: testNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/part-of": "argocd"},
+		},
+		Data: map[string]string{
+			"accounts.alice":           "login",
+			"accounts.alice.managedBy": settings.AccountManagedBySCIM,
+			// erin is a local account which was not created by SCIM
+			"accounts.erin": "login",
+		},
+	}
+	rbacCM := &corev1.ConfigMap{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "argocd-rbac-cm",
+			Namespace: testNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/part-of": "argocd"},
+		},
+	}
+	kubeClientset := fake.NewSimpleClientset(secret, cm, rbacCM)
+	settingsMgr := settings.NewSettingsManager(context.Background(), kubeClientset, testNamespace)
+	return NewHandler(testNamespace, kubeClientset, settingsMgr), kubeClientset
+}
+
+func doRequest(t *testing.T, handler http.Handler, method, path, body string, out interface{}) int {
+	t.Helper()
+	req, err := http.NewRequest(method, URLPrefix+path, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret-token")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if out != nil && rr.Body.Len() > 0 {
+		require.NoError(t, json.Unmarshal(rr.Body.Bytes(), out))
+	}
+	return rr.Code
+}
+
+func rbacData(t *testing.T, kubeClientset *fake.Clientset) map[string]string {
+	t.Helper()
+	cm, err := kubeClientset.CoreV1().ConfigMaps(testNamespace).Get(context.Background(), "argocd-rbac-cm", v1.GetOptions{})
+	require.NoError(t, err)
+	return cm.Data
+}
+
+func TestAuthentication(t *testing.T) {
+	t.Run("Disabled", func(t *testing.T) {
+		handler, _ := newTestHandler(t, "")
+		assert.Equal(t, http.StatusNotFound, doRequest(t, handler, http.MethodGet, "/Users", "", nil))
+	})
+
+	t.Run("InvalidToken", func(t *testing.T) {
+		handler, _ := newTestHandler(t, "other-token")
+		var scimErr scimError
+		assert.Equal(t, http.StatusUnauthorized, doRequest(t, handler, http.MethodGet, "/Users", "", &scimErr))
+		assert.Equal(t, []string{errorSchema}, scimErr.Schemas)
+		assert.Equal(t, "401", scimErr.Status)
+	})
+
+	t.Run("ValidToken", func(t *testing.T) {
+		handler, _ := newTestHandler(t, "secret-token")
+		assert.Equal(t, http.StatusOK, doRequest(t, handler, http.MethodGet, "/Users", "", nil))
+	})
+}
+
+func TestUsers(t *testing.T) {
+	handler, _ := newTestHandler(t, "secret-token")
+
+	var list listResponse
+	require.Equal(t, http.StatusOK, doRequest(t, handler, http.MethodGet, "/Users", "", &list))
+	assert.Equal(t, 1, list.TotalResults)
+
+	var user User
+	require.Equal(t, http.StatusCreated, doRequest(t, handler, http.MethodPost, "/Users", `{"schemas":["`+userSchema+`"],"userName":"bob","password":"password123"}`, &user))
+	assert.Equal(t, "bob", user.ID)
+	assert.True(t, *user.Active)
+	assert.Empty(t, user.Password)
+
+	account, err := handler.settingsMgr.GetAccount("bob")
+	require.NoError(t, err)
+	assert.True(t, account.Enabled)
+	assert.NotEmpty(t, account.PasswordHash)
+	assert.Equal(t, []settings.AccountCapability{settings.AccountCapabilityLogin}, account.Capabilities)
+	assert.Equal(t, settings.AccountManagedBySCIM, account.ManagedBy)
+
+	assert.Equal(t, http.StatusConflict, doRequest(t, handler, http.MethodPost, "/Users", `{"userName":"bob"}`, nil))
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, handler, http.MethodPost, "/Users", `{"userName":"admin"}`, nil))
+
+	require.Equal(t, http.StatusOK, doRequest(t, handler, http.MethodGet, "/Users?filter="+`userName%20eq%20%22bob%22`, "", &list))
+	assert.Equal(t, 1, list.TotalResults)
+
+	require.Equal(t, http.StatusOK, doRequest(t, handler, http.MethodPatch, "/Users/bob", `{"schemas":["`+patchOpSchema+`"],"Operations":[{"op":"replace","path":"active","value":false}]}`, &user))
+	assert.False(t, *user.Active)
+	account, err = handler.settingsMgr.GetAccount("bob")
+	require.NoError(t, err)
+	assert.False(t, account.Enabled)
+
+	require.Equal(t, http.StatusOK, doRequest(t, handler, http.MethodPut, "/Users/bob", `{"userName":"bob","active":true}`, &user))
+	assert.True(t, *user.Active)
+	assert.Equal(t, http.StatusBadRequest, doRequest(t, handler, http.MethodPut, "/Users/bob", `{"userName":"carol"}`, nil))
+
+	assert.Equal(t, http.StatusNotFound, doRequest(t, handler, http.MethodGet, "/Users/admin", "", nil))
+	// local accounts which were not created by SCIM cannot be read nor modified
+	assert.Equal(t, http.StatusNotFound, doRequest(t, handler, http.MethodGet, "/Users/erin", "", nil))
+	assert.Equal(t, http.StatusNotFound, doRequest(t, handler, http.MethodPatch, "/Users/erin", `{"Operations":[{"op":"replace","path":"password","value":"password123"}]}`, nil))
+	assert.Equal(t, http.StatusNotFound, doRequest(t, handler, http.MethodPut, "/Users/erin", `{"userName":"erin","active":false}`, nil))
+	assert.Equal(t, http.StatusNotFound, doRequest(t, handler, http.MethodDelete, "/Users/erin", "", nil))
+	account, err = handler.settingsMgr.GetAccount("erin")
+	require.NoError(t, err)
+	assert.True(t, account.Enabled)
+	assert.Empty(t, account.PasswordHash)
+	assert.Equal(t, http.StatusNoContent, doRequest(t, handler, http.MethodDelete, "/Users/bob", "", nil))
+	assert.Equal(t, http.StatusNotFound, doRequest(t, handler, http.MethodGet, "/Users/bob", "", nil))
+}
+
+func TestGroups(t *testing.T) {
+	handler, kubeClientset := newTestHandler(t, "secret-token")
+	require.Equal(t, http.StatusCreated, doRequest(t, handler, http.MethodPost, "/Users", `{"userName":"bob"}`, nil))
+
+	var group Group
+	require.Equal(t, http.StatusCreated, doRequest(t, handler, http.MethodPost, "/Groups", `{"displayName":"developers","members":[{"value":"alice"}]}`, &group))
+	assert.Equal(t, "developers", group.ID)
+	assert.Equal(t, "g, alice, developers\n", rbacData(t, kubeClientset)[RBACPolicyKey])
+	assert.Equal(t, "developers", rbacData(t, kubeClientset)[RBACGroupsKey])
+
+	assert.Equal(t, http.StatusConflict, doRequest(t, handler, http.MethodPost, "/Groups", `{"displayName":"developers"}`, nil))
+	assert.Equal(t, http.StatusNotFound, doRequest(t, handler, http.MethodPost, "/Groups", `{"displayName":"ops","members":[{"value":"unknown"}]}`, nil))
+	assert.Equal(t, http.StatusNotFound, doRequest(t, handler, http.MethodPost, "/Groups", `{"displayName":"ops","members":[{"value":"erin"}]}`, nil))
+	assert.Equal(t, http.StatusNotFound, doRequest(t, handler, http.MethodPost, "/Groups", `{"displayName":"ops","members":[{"value":"admin"}]}`, nil))
+	// group names must not be RBAC roles or break the policy CSV
+	for _, name := range []string{"role:admin", "ops:admin", "ops,admin"} {
+		assert.Equal(t, http.StatusBadRequest, doRequest(t, handler, http.MethodPost, "/Groups", `{"displayName":"`+name+`"}`, nil), name)
+	}
+
+	require.Equal(t, http.StatusOK, doRequest(t, handler, http.MethodPatch, "/Groups/developers", `{"Operations":[{"op":"add","path":"members","value":[{"value":"bob"}]}]}`, &group))
+	assert.Len(t, group.Members, 2)
+	assert.Equal(t, "g, alice, developers\ng, bob, developers\n", rbacData(t, kubeClientset)[RBACPolicyKey])
+
+	var user User
+	require.Equal(t, http.StatusOK, doRequest(t, handler, http.MethodGet, "/Users/bob", "", &user))
+	assert.Equal(t, []Member{{Value: "developers", Display: "developers"}}, user.Groups)
+
+	require.Equal(t, http.StatusOK, doRequest(t, handler, http.MethodPatch, "/Groups/developers", `{"Operations":[{"op":"remove","path":"members[value eq \"alice\"]"}]}`, &group))
+	assert.Equal(t, []Member{{Value: "bob", Display: "bob"}}, group.Members)
+
+	// deleting a user removes its group memberships
+	require.Equal(t, http.StatusNoContent, doRequest(t, handler, http.MethodDelete, "/Users/bob", "", nil))
+	assert.Equal(t, "", rbacData(t, kubeClientset)[RBACPolicyKey])
+
+	require.Equal(t, http.StatusOK, doRequest(t, handler, http.MethodPut, "/Groups/developers", `{"displayName":"developers","members":[{"value":"alice"}]}`, &group))
+	assert.Equal(t, "g, alice, developers\n", rbacData(t, kubeClientset)[RBACPolicyKey])
+
+	var list listResponse
+	require.Equal(t, http.StatusOK, doRequest(t, handler, http.MethodGet, "/Groups?filter="+`displayName%20eq%20%22developers%22`, "", &list))
+	assert.Equal(t, 1, list.TotalResults)
+
+	require.Equal(t, http.StatusNoContent, doRequest(t, handler, http.MethodDelete, "/Groups/developers", "", nil))
+	assert.Equal(t, http.StatusNotFound, doRequest(t, handler, http.MethodGet, "/Groups/developers", "", nil))
+	assert.Equal(t, "", rbacData(t, kubeClientset)[RBACPolicyKey])
+}
+
+func TestSchemas(t *testing.T) {
+	handler, _ := newTestHandler(t, "secret-token")
+	var list listResponse
+	require.Equal(t, http.StatusOK, doRequest(t, handler, http.MethodGet, "/Schemas", "", &list))
+	assert.Equal(t, 2, list.TotalResults)
+}
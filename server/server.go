@@ -96,10 +96,13 @@ import (
 	"github.com/argoproj/argo-cd/v2/server/rbacpolicy"
 	"github.com/argoproj/argo-cd/v2/server/repocreds"
 	"github.com/argoproj/argo-cd/v2/server/repository"
+	"github.com/argoproj/argo-cd/v2/server/scim"
 	"github.com/argoproj/argo-cd/v2/server/session"
 	"github.com/argoproj/argo-cd/v2/server/settings"
 	"github.com/argoproj/argo-cd/v2/server/version"
+	appwebhook "github.com/argoproj/argo-cd/v2/server/webhook"
 	"github.com/argoproj/argo-cd/v2/ui"
+	"github.com/argoproj/argo-cd/v2/util/argo"
 	"github.com/argoproj/argo-cd/v2/util/assets"
 	cacheutil "github.com/argoproj/argo-cd/v2/util/cache"
 	"github.com/argoproj/argo-cd/v2/util/db"
@@ -229,6 +232,16 @@ type ArgoCDServerOpts struct {
 	ApplicationNamespaces   []string
 	EnableProxyExtension    bool
 	WebhookParallelism      int
+	EnableSchemaValidation  bool
+	SchemaValidationTimeout time.Duration
+	// EnableDestinationValidation serves a validating admission webhook enforcing the destinations of projects
+	EnableDestinationValidation bool
+	// DestinationValidationPort is the port of the admission webhook server enforcing the destinations of projects
+	DestinationValidationPort int
+	// BootstrapAppRepo is the repository of the configuration of Argo CD, the bootstrap application is not created if empty
+	BootstrapAppRepo string
+	// BootstrapAppPath is the path of the configuration of Argo CD in BootstrapAppRepo
+	BootstrapAppPath string
 }
 
 type ApplicationSetOpts struct {
@@ -237,6 +250,8 @@ type ApplicationSetOpts struct {
 	ScmRootCAPath            string
 	AllowedScmProviders      []string
 	EnableScmProviders       bool
+	MatrixMaxDepth           int
+	MatrixMaxParams          int
 }
 
 // HTTPMetricsRegistry exposes operations to update http metrics in the Argo CD
@@ -272,6 +287,51 @@ func initializeDefaultProject(opts ArgoCDServerOpts) error {
 	return err
 }
 
+// bootstrapAppName is the name of the application managing the configuration of Argo CD
+const bootstrapAppName = "argocd-bootstrap"
+
+// initializeBootstrapApp creates the application managing the configuration of Argo CD from the bootstrap repository
+// if it does not already exist. An existing application is left untouched, so that it can be changed after the first
+// startup.
+func initializeBootstrapApp(opts ArgoCDServerOpts) error {
+	path := opts.BootstrapAppPath
+	if path == "" {
+		path = "."
+	}
+	bootstrapApp := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      bootstrapAppName,
+			Namespace: opts.Namespace,
+			Labels:    map[string]string{common.LabelKeyBootstrapApp: "true"},
+		},
+		Spec: v1alpha1.ApplicationSpec{
+			Project: v1alpha1.DefaultAppProjectName,
+			Source: &v1alpha1.ApplicationSource{
+				RepoURL:        opts.BootstrapAppRepo,
+				Path:           path,
+				TargetRevision: "HEAD",
+			},
+			Destination: v1alpha1.ApplicationDestination{
+				Server:    v1alpha1.KubernetesInternalAPIServerAddr,
+				Namespace: opts.Namespace,
+			},
+			SyncPolicy: &v1alpha1.SyncPolicy{Automated: &v1alpha1.SyncPolicyAutomated{}},
+		},
+	}
+
+	_, err := opts.AppClientset.ArgoprojV1alpha1().Applications(opts.Namespace).Get(context.Background(), bootstrapApp.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = opts.AppClientset.ArgoprojV1alpha1().Applications(opts.Namespace).Create(context.Background(), bootstrapApp, metav1.CreateOptions{})
+		if apierrors.IsAlreadyExists(err) {
+			return nil
+		}
+		if err == nil {
+			log.Infof("Created bootstrap application %s from %s", bootstrapApp.Name, opts.BootstrapAppRepo)
+		}
+	}
+	return err
+}
+
 // NewServer returns a new instance of the Argo CD API server
 func NewServer(ctx context.Context, opts ArgoCDServerOpts, appsetOpts ApplicationSetOpts) *ArgoCDServer {
 	settingsMgr := settings_util.NewSettingsManager(ctx, opts.KubeClientset, opts.Namespace)
@@ -279,6 +339,10 @@ func NewServer(ctx context.Context, opts ArgoCDServerOpts, appsetOpts Applicatio
 	errorsutil.CheckError(err)
 	err = initializeDefaultProject(opts)
 	errorsutil.CheckError(err)
+	if opts.BootstrapAppRepo != "" {
+		err = initializeBootstrapApp(opts)
+		errorsutil.CheckError(err)
+	}
 
 	appInformerNs := opts.Namespace
 	if len(opts.ApplicationNamespaces) > 0 {
@@ -380,6 +444,7 @@ func (a *ArgoCDServer) healthCheck(r *http.Request) error {
 type Listeners struct {
 	Main        net.Listener
 	Metrics     net.Listener
+	Webhook     net.Listener
 	GatewayConn *grpc.ClientConn
 }
 
@@ -396,6 +461,12 @@ func (l *Listeners) Close() error {
 		}
 		l.Metrics = nil
 	}
+	if l.Webhook != nil {
+		if err := l.Webhook.Close(); err != nil {
+			return err
+		}
+		l.Webhook = nil
+	}
 	if l.GatewayConn != nil {
 		if err := l.GatewayConn.Close(); err != nil {
 			return err
@@ -469,6 +540,15 @@ func (a *ArgoCDServer) Listen() (*Listeners, error) {
 		io.Close(mainLn)
 		return nil, err
 	}
+	var webhookLn net.Listener
+	if a.EnableDestinationValidation {
+		webhookLn, err = startListener(a.ListenHost, a.DestinationValidationPort)
+		if err != nil {
+			io.Close(mainLn)
+			io.Close(metricsLn)
+			return nil, err
+		}
+	}
 	var dOpts []grpc.DialOption
 	dOpts = append(dOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(apiclient.MaxGRPCMessageSize)))
 	dOpts = append(dOpts, grpc.WithUserAgent(fmt.Sprintf("%s/%s", common.ArgoCDUserAgentName, common.GetVersion().Version)))
@@ -493,9 +573,12 @@ func (a *ArgoCDServer) Listen() (*Listeners, error) {
 	if err != nil {
 		io.Close(mainLn)
 		io.Close(metricsLn)
+		if webhookLn != nil {
+			io.Close(webhookLn)
+		}
 		return nil, err
 	}
-	return &Listeners{Main: mainLn, Metrics: metricsLn, GatewayConn: conn}, nil
+	return &Listeners{Main: mainLn, Metrics: metricsLn, Webhook: webhookLn, GatewayConn: conn}, nil
 }
 
 // Init starts informers used by the API server
@@ -588,6 +671,10 @@ func (a *ArgoCDServer) Run(ctx context.Context, listeners *Listeners) {
 	go a.rbacPolicyLoader(ctx)
 	go func() { a.checkServeErr("tcpm", tcpm.Serve()) }()
 	go func() { a.checkServeErr("metrics", metricsServ.Serve(listeners.Metrics)) }()
+	if listeners.Webhook != nil {
+		webhookS := a.newDestinationValidationServer(ctx)
+		go func() { a.checkServeErr("webhook", webhookS.ServeTLS(listeners.Webhook, "", "")) }()
+	}
 	if !cache.WaitForCacheSync(ctx.Done(), a.projInformer.HasSynced, a.appInformer.HasSynced) {
 		log.Fatal("Timed out waiting for project cache to sync")
 	}
@@ -596,6 +683,39 @@ func (a *ArgoCDServer) Run(ctx context.Context, listeners *Listeners) {
 	<-a.stopCh
 }
 
+// newDestinationValidationServer returns the server of the validating admission webhooks enforcing the destinations of
+// projects, protecting the destinations of existing applications and protecting ApplicationSets from deletion, and of
+// the mutating admission webhooks injecting defaults into new applications and ApplicationSets. Its self-signed
+// certificate is rotated in the background.
+func (a *ArgoCDServer) newDestinationValidationServer(ctx context.Context) *http.Server {
+	hosts := []string{
+		fmt.Sprintf("%s.%s.svc", common.DefaultServerName, a.Namespace),
+		fmt.Sprintf("%s.%s.svc.cluster.local", common.DefaultServerName, a.Namespace),
+	}
+	rotator := appwebhook.NewCertRotator(a.KubeClientset, a.Namespace, appwebhook.DefaultCertSecretName, []string{appwebhook.DefaultWebhookConfigurationName, appwebhook.ApplicationSetDeletionProtectionWebhookConfigurationName}, appwebhook.DefaultMutatingWebhookConfigurationName, hosts)
+	if err := rotator.Rotate(ctx); err != nil {
+		log.Fatalf("failed to initialize webhook serving certificate: %v", err)
+	}
+	go rotator.Run(ctx, time.Hour)
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/validate/application-destinations", appwebhook.NewDestinationValidator(db.NewDB(a.Namespace, a.settingsMgr, a.KubeClientset), a.projLister))
+	mux.Handle("/api/validate/application-destination-changes", appwebhook.NewDestinationChangeValidator(a.enf, argo.NewAuditLogger(a.Namespace, a.KubeClientset, "argocd-server")))
+	mux.Handle("/api/mutate/applications", appwebhook.NewApplicationMutator(a.KubeClientset, a.Namespace))
+	mux.Handle("/api/validate/applicationset-deletions", appwebhook.NewApplicationSetDeletionValidator())
+	mux.Handle("/api/mutate/applicationsets", appwebhook.NewApplicationSetMutator())
+	mux.Handle("/api/validate/health-overrides", appwebhook.NewHealthOverridesValidator(a.enf, a.Namespace))
+	tlsConfig := &tls.Config{GetCertificate: rotator.GetCertificate}
+	if a.TLSConfigCustomizer != nil {
+		a.TLSConfigCustomizer(tlsConfig)
+	}
+	return &http.Server{
+		Addr:      fmt.Sprintf("%s:%d", a.ListenHost, a.DestinationValidationPort),
+		Handler:   mux,
+		TLSConfig: tlsConfig,
+	}
+}
+
 func (a *ArgoCDServer) Initialized() bool {
 	return a.projInformer.HasSynced() && a.appInformer.HasSynced()
 }
@@ -788,12 +908,15 @@ func (a *ArgoCDServer) newGRPCServer() (*grpc.Server, application.AppResourceTre
 		"/application.ApplicationService/PatchResource":           true,
 		// Remove from logs both because the contents are sensitive and because they may be very large.
 		"/application.ApplicationService/GetManifestsWithFiles": true,
+		// The standard input and output of commands executed in pods may contain secrets.
+		"/application.ApplicationService/ExecPod": true,
 	}
 	// NOTE: notice we do not configure the gRPC server here with TLS (e.g. grpc.Creds(creds))
 	// This is because TLS handshaking occurs in cmux handling
 	sOpts = append(sOpts, grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
 		otelgrpc.StreamServerInterceptor(), //nolint:staticcheck // TODO: ignore SA1019 for depreciation: see https://github.com/argoproj/argo-cd/issues/18258
 		grpc_logrus.StreamServerInterceptor(a.log),
+		grpc_util.CorrelationIDStreamServerInterceptor(),
 		grpc_prometheus.StreamServerInterceptor,
 		grpc_auth.StreamServerInterceptor(a.Authenticate),
 		grpc_util.UserAgentStreamServerInterceptor(common.ArgoCDUserAgentName, clientConstraint),
@@ -808,6 +931,7 @@ func (a *ArgoCDServer) newGRPCServer() (*grpc.Server, application.AppResourceTre
 		bug21955WorkaroundInterceptor,
 		otelgrpc.UnaryServerInterceptor(), //nolint:staticcheck // TODO: ignore SA1019 for depreciation: see https://github.com/argoproj/argo-cd/issues/18258
 		grpc_logrus.UnaryServerInterceptor(a.log),
+		grpc_util.CorrelationIDUnaryServerInterceptor(),
 		grpc_prometheus.UnaryServerInterceptor,
 		grpc_auth.UnaryServerInterceptor(a.Authenticate),
 		grpc_util.UserAgentUnaryServerInterceptor(common.ArgoCDUserAgentName, clientConstraint),
@@ -905,6 +1029,8 @@ func newArgoCDServiceSet(a *ArgoCDServer) *ArgoCDServiceSet {
 		a.ScmRootCAPath,
 		a.AllowedScmProviders,
 		a.EnableScmProviders,
+		a.MatrixMaxDepth,
+		a.MatrixMaxParams,
 	)
 
 	projectService := project.NewServer(a.Namespace, a.KubeClientset, a.AppClientset, a.enf, projectLock, a.sessionMgr, a.policyEnforcer, a.projInformer, a.settingsMgr, a.db)
@@ -1006,12 +1132,13 @@ func compressHandler(handler http.Handler) http.Handler {
 func (a *ArgoCDServer) newHTTPServer(ctx context.Context, port int, grpcWebHandler http.Handler, appResourceTreeFn application.AppResourceTreeFn, conn *grpc.ClientConn, metricsReg HTTPMetricsRegistry) *http.Server {
 	endpoint := fmt.Sprintf("localhost:%d", port)
 	mux := http.NewServeMux()
+	badgeHandler := badge.NewHandler(a.AppClientset, a.settingsMgr, a.Namespace, a.ApplicationNamespaces)
 	httpS := http.Server{
 		Addr: endpoint,
 		Handler: &handlerSwitcher{
 			handler: mux,
 			urlToHandler: map[string]http.Handler{
-				"/api/badge":          badge.NewHandler(a.AppClientset, a.settingsMgr, a.Namespace, a.ApplicationNamespaces),
+				"/api/badge":          badgeHandler,
 				common.LogoutEndpoint: logout.NewHandler(a.AppClientset, a.settingsMgr, a.sessionMgr, a.ArgoCDServerOpts.RootPath, a.ArgoCDServerOpts.BaseHRef, a.Namespace),
 			},
 			contentTypeToHandler: map[string]http.Handler{
@@ -1028,7 +1155,20 @@ func (a *ArgoCDServer) newHTTPServer(ctx context.Context, port int, grpcWebHandl
 	// we use our own Marshaler
 	gwMuxOpts := runtime.WithMarshalerOption(runtime.MIMEWildcard, new(grpc_util.JSONMarshaler))
 	gwCookieOpts := runtime.WithForwardResponseOption(a.translateGrpcCookieHeader)
-	gwmux := runtime.NewServeMux(gwMuxOpts, gwCookieOpts)
+	// propagate the correlation ID between the X-Correlation-ID HTTP header and the gRPC metadata
+	gwIncomingHeaderOpts := runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+		if strings.EqualFold(key, grpc_util.CorrelationIDHeader) {
+			return grpc_util.CorrelationIDMetadataKey, true
+		}
+		return runtime.DefaultHeaderMatcher(key)
+	})
+	gwOutgoingHeaderOpts := runtime.WithOutgoingHeaderMatcher(func(key string) (string, bool) {
+		if key == grpc_util.CorrelationIDMetadataKey {
+			return grpc_util.CorrelationIDHeader, true
+		}
+		return fmt.Sprintf("%s%s", runtime.MetadataHeaderPrefix, key), true
+	})
+	gwmux := runtime.NewServeMux(gwMuxOpts, gwCookieOpts, gwIncomingHeaderOpts, gwOutgoingHeaderOpts)
 
 	var handler http.Handler = gwmux
 	if a.EnableGZip {
@@ -1040,6 +1180,30 @@ func (a *ArgoCDServer) newHTTPServer(ctx context.Context, port int, grpcWebHandl
 		log.WithField(common.SecurityField, common.SecurityHigh).Warnf("Content-Type enforcement is disabled, which may make your API vulnerable to CSRF attacks")
 	}
 	mux.Handle("/api/", handler)
+	mux.Handle("/api/badge/", badgeHandler)
+
+	// Validation of applications against the Rego policies of argocd-app-policies before they are created. The other
+	// methods are served by the gateway, so that an application named "validate" can still be retrieved.
+	appPolicyValidator := appwebhook.NewApplicationPolicyValidator(a.KubeClientset, a.Namespace)
+	var appPolicyHandler http.Handler = a.withTokenAuth(http.HandlerFunc(appPolicyValidator.ServeValidation))
+	if len(a.ContentTypes) > 0 {
+		appPolicyHandler = enforceContentTypes(appPolicyHandler, a.ContentTypes)
+	}
+	mux.Handle("/api/v1/applications/validate", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			handler.ServeHTTP(w, r)
+			return
+		}
+		appPolicyHandler.ServeHTTP(w, r)
+	}))
+
+	// The cluster health summary is served outside of the gateway, since its path would otherwise match the Get method
+	// of the cluster service
+	var healthSummaryHandler http.Handler = a.withTokenAuth(cluster.NewHealthSummaryHandler(a.appLister, a.enf, a.Namespace, a.ApplicationNamespaces))
+	if a.EnableGZip {
+		healthSummaryHandler = compressHandler(healthSummaryHandler)
+	}
+	mux.Handle(cluster.HealthSummaryPath, healthSummaryHandler)
 
 	terminalOpts := application.TerminalOptions{DisableAuth: a.ArgoCDServerOpts.DisableAuth, Enf: a.enf}
 
@@ -1084,6 +1248,17 @@ func (a *ArgoCDServer) newHTTPServer(ctx context.Context, port int, grpcWebHandl
 
 	mux.HandleFunc("/api/webhook", acdWebhookHandler.Handler)
 
+	// Validating admission webhook for application parameters
+	if a.ArgoCDServerOpts.EnableSchemaValidation {
+		mux.Handle("/api/validate/applications", appwebhook.NewApplicationValidator(argoDB, a.RepoClientset, a.settingsMgr, a.ArgoCDServerOpts.SchemaValidationTimeout))
+	}
+
+	// Validating admission webhook for the Rego policies of applications
+	mux.Handle("/api/validate/application-policies", appPolicyValidator)
+
+	// SCIM 2.0 user and group provisioning, authenticated with the token in argocd-secret
+	mux.Handle(scim.URLPrefix+"/", scim.NewHandler(a.Namespace, a.KubeClientset, a.settingsMgr))
+
 	// Serve cli binaries directly from API server
 	registerDownloadHandlers(mux, "/download")
 
@@ -1414,6 +1589,32 @@ func (a *ArgoCDServer) getClaims(ctx context.Context) (jwt.Claims, string, error
 	return groupClaims, newToken, nil
 }
 
+// withTokenAuth authenticates the requests of the HTTP handler with the bearer token of their Authorization header, as
+// sent by the CLI, or with the auth cookie, as sent by the UI
+func (a *ArgoCDServer) withTokenAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.DisableAuth {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok {
+				token, _ = httputil.JoinCookies(common.AuthCookieName, r.Cookies())
+			}
+			if token == "" {
+				http.Error(w, "No auth token", http.StatusUnauthorized)
+				return
+			}
+			claims, _, err := a.sessionMgr.VerifyToken(token)
+			if err != nil {
+				http.Error(w, "Invalid token", http.StatusUnauthorized)
+				return
+			}
+			// Add claims to the context to inspect for RBAC
+			// nolint:staticcheck
+			r = r.WithContext(context.WithValue(r.Context(), "claims", claims))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // getToken extracts the token from gRPC metadata or cookie headers
 func getToken(md metadata.MD) string {
 	// check the "token" metadata
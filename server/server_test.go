@@ -1164,6 +1164,61 @@ func TestInitializeDefaultProject_ProjectAlreadyInitialized(t *testing.T) {
 	assert.Equal(t, proj.Spec, existingDefaultProject.Spec)
 }
 
+func TestInitializeBootstrapApp(t *testing.T) {
+	newOpts := func(objects ...runtime.Object) ArgoCDServerOpts {
+		return ArgoCDServerOpts{
+			Namespace:        test.FakeArgoCDNamespace,
+			KubeClientset:    fake.NewSimpleClientset(test.NewFakeConfigMap(), test.NewFakeSecret()),
+			AppClientset:     apps.NewSimpleClientset(objects...),
+			RepoClientset:    &mocks.Clientset{RepoServerServiceClient: &mocks.RepoServerServiceClient{}},
+			BootstrapAppRepo: "https://github.com/example/argocd-config",
+			BootstrapAppPath: "config",
+		}
+	}
+
+	t.Run("AppDoesNotExist", func(t *testing.T) {
+		argoCDOpts := newOpts()
+		require.NoError(t, initializeBootstrapApp(argoCDOpts))
+
+		app, err := argoCDOpts.AppClientset.ArgoprojV1alpha1().Applications(test.FakeArgoCDNamespace).Get(context.Background(), bootstrapAppName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "true", app.Labels[common.LabelKeyBootstrapApp])
+		assert.Equal(t, v1alpha1.DefaultAppProjectName, app.Spec.Project)
+		assert.Equal(t, &v1alpha1.ApplicationSource{RepoURL: "https://github.com/example/argocd-config", Path: "config", TargetRevision: "HEAD"}, app.Spec.Source)
+		assert.Equal(t, v1alpha1.ApplicationDestination{Server: v1alpha1.KubernetesInternalAPIServerAddr, Namespace: test.FakeArgoCDNamespace}, app.Spec.Destination)
+		require.NotNil(t, app.Spec.SyncPolicy)
+		assert.NotNil(t, app.Spec.SyncPolicy.Automated)
+
+		// restarting the server keeps the application
+		require.NoError(t, initializeBootstrapApp(argoCDOpts))
+	})
+
+	t.Run("DefaultPath", func(t *testing.T) {
+		argoCDOpts := newOpts()
+		argoCDOpts.BootstrapAppPath = ""
+		require.NoError(t, initializeBootstrapApp(argoCDOpts))
+
+		app, err := argoCDOpts.AppClientset.ArgoprojV1alpha1().Applications(test.FakeArgoCDNamespace).Get(context.Background(), bootstrapAppName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, ".", app.Spec.Source.Path)
+	})
+
+	t.Run("AppAlreadyExists", func(t *testing.T) {
+		existing := &v1alpha1.Application{
+			ObjectMeta: metav1.ObjectMeta{Name: bootstrapAppName, Namespace: test.FakeArgoCDNamespace},
+			Spec: v1alpha1.ApplicationSpec{
+				Source: &v1alpha1.ApplicationSource{RepoURL: "https://github.com/example/other", Path: "other"},
+			},
+		}
+		argoCDOpts := newOpts(existing)
+		require.NoError(t, initializeBootstrapApp(argoCDOpts))
+
+		app, err := argoCDOpts.AppClientset.ArgoprojV1alpha1().Applications(test.FakeArgoCDNamespace).Get(context.Background(), bootstrapAppName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, existing.Spec, app.Spec)
+	})
+}
+
 func TestOIDCConfigChangeDetection_SecretsChanged(t *testing.T) {
 	// Given
 	rawOIDCConfig, err := yaml.Marshal(&settings_util.OIDCConfig{
@@ -0,0 +1,251 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	log "github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
+)
+
+// MutationRule is a JSON patch applied to the applications matching a label selector when they are created
+type MutationRule struct {
+	// Name is the key of the rule in the argocd-app-mutations ConfigMap
+	Name string `json:"-"`
+	// Selector selects the applications the rule applies to by their labels. All applications are selected if it is
+	// not set.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Patch is the JSON patch applied to the applications. Its add operations do not overwrite existing values.
+	Patch jsonpatch.Patch `json:"patch"`
+}
+
+// MutationRulesFromConfigMap parses the mutation rules of the argocd-app-mutations ConfigMap, which holds one rule per
+// key, and returns them sorted by name
+func MutationRulesFromConfigMap(cm *corev1.ConfigMap) ([]MutationRule, error) {
+	rules := make([]MutationRule, 0, len(cm.Data))
+	for name, data := range cm.Data {
+		rule := MutationRule{Name: name}
+		if err := yaml.Unmarshal([]byte(data), &rule); err != nil {
+			return nil, fmt.Errorf("error parsing mutation rule %s: %w", name, err)
+		}
+		if _, err := metav1.LabelSelectorAsSelector(rule.Selector); err != nil {
+			return nil, fmt.Errorf("error parsing selector of mutation rule %s: %w", name, err)
+		}
+		rules = append(rules, rule)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name < rules[j].Name
+	})
+	return rules, nil
+}
+
+// ApplicationMutator is a mutating admission webhook which injects default labels, annotations or fields into
+// applications when they are created, according to the rules of the argocd-app-mutations ConfigMap. Mutating webhooks
+// are called before validating webhooks, so the mutated applications are the ones which get validated.
+type ApplicationMutator struct {
+	clientset kubernetes.Interface
+	namespace string
+}
+
+// NewApplicationMutator creates a mutating admission webhook reading its rules from the namespace of Argo CD
+func NewApplicationMutator(clientset kubernetes.Interface, namespace string) *ApplicationMutator {
+	return &ApplicationMutator{
+		clientset: clientset,
+		namespace: namespace,
+	}
+}
+
+func (m *ApplicationMutator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(w, r, m.Mutate)
+}
+
+// Mutate applies the mutation rules to the application of the admission request. Only created applications are
+// mutated. Rules which cannot be applied are skipped with a warning rather than rejecting the application.
+func (m *ApplicationMutator) Mutate(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	allowed := &admissionv1.AdmissionResponse{Allowed: true}
+	if req.Operation != admissionv1.Create || req.Kind.Group != application.Group || req.Kind.Kind != application.ApplicationKind {
+		return allowed
+	}
+	rules, err := m.getRules(ctx)
+	if err != nil {
+		log.Warnf("Failed to get mutation rules: %v", err)
+		allowed.Warnings = append(allowed.Warnings, fmt.Sprintf("application was not mutated: %v", err))
+		return allowed
+	}
+	if len(rules) == 0 {
+		return allowed
+	}
+	_, patch, warnings, err := MutateApplication(req.Object.Raw, rules)
+	if err != nil {
+		return deniedResponse(http.StatusBadRequest, metav1.StatusReasonBadRequest, err.Error())
+	}
+	allowed.Warnings = append(allowed.Warnings, warnings...)
+	if len(patch) > 0 {
+		data, err := json.Marshal(patch)
+		if err != nil {
+			return deniedResponse(http.StatusInternalServerError, metav1.StatusReasonInternalError, fmt.Sprintf("failed to marshal patch: %v", err))
+		}
+		patchType := admissionv1.PatchTypeJSONPatch
+		allowed.PatchType = &patchType
+		allowed.Patch = data
+	}
+	return allowed
+}
+
+// getRules returns the rules of the argocd-app-mutations ConfigMap, or no rules if it does not exist
+func (m *ApplicationMutator) getRules(ctx context.Context) ([]MutationRule, error) {
+	cm, err := m.clientset.CoreV1().ConfigMaps(m.namespace).Get(ctx, common.ArgoCDAppMutationsConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting ConfigMap %s: %w", common.ArgoCDAppMutationsConfigMapName, err)
+	}
+	return MutationRulesFromConfigMap(cm)
+}
+
+// MutateApplication applies the rules selecting the application, given as JSON, in order. It returns the mutated
+// application, the JSON patch turning the application into the mutated one, and a warning for each rule which could
+// not be applied. Add operations whose path already exists are skipped, so that values set by the user are not
+// overwritten, and the missing objects or arrays on their path are created, e.g. /metadata/labels of an application
+// without labels.
+func MutateApplication(app []byte, rules []MutationRule) ([]byte, jsonpatch.Patch, []string, error) {
+	var obj struct {
+		Metadata metav1.ObjectMeta `json:"metadata"`
+	}
+	if err := json.Unmarshal(app, &obj); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to decode application: %w", err)
+	}
+	var patch jsonpatch.Patch
+	var warnings []string
+	for _, rule := range rules {
+		selector := labels.Everything()
+		if rule.Selector != nil {
+			var err error
+			if selector, err = metav1.LabelSelectorAsSelector(rule.Selector); err != nil {
+				warnings = append(warnings, fmt.Sprintf("mutation rule %s was not applied: %v", rule.Name, err))
+				continue
+			}
+		}
+		if !selector.Matches(labels.Set(obj.Metadata.Labels)) {
+			continue
+		}
+		mutated, rulePatch, err := applyRule(app, rule.Patch)
+		if err != nil {
+			log.WithField("application", obj.Metadata.Name).Warnf("Failed to apply mutation rule %s: %v", rule.Name, err)
+			warnings = append(warnings, fmt.Sprintf("mutation rule %s was not applied: %v", rule.Name, err))
+			continue
+		}
+		app = mutated
+		patch = append(patch, rulePatch...)
+	}
+	return app, patch, warnings, nil
+}
+
+// applyRule applies the operations of the patch of a rule to the document, and returns the mutated document along with
+// the operations which were actually applied
+func applyRule(doc []byte, patch jsonpatch.Patch) ([]byte, jsonpatch.Patch, error) {
+	var applied jsonpatch.Patch
+	for _, op := range patch {
+		ops := jsonpatch.Patch{op}
+		if op.Kind() == "add" {
+			path, err := op.Path()
+			if err != nil {
+				return nil, nil, err
+			}
+			var value interface{}
+			if err := json.Unmarshal(doc, &value); err != nil {
+				return nil, nil, fmt.Errorf("failed to decode document: %w", err)
+			}
+			tokens := pointerTokens(path)
+			if len(tokens) > 0 {
+				// adding to an array inserts a value, while adding to an object replaces the existing member
+				parent, _ := lookupPointer(value, tokens[:len(tokens)-1])
+				if _, isObject := parent.(map[string]interface{}); isObject {
+					if _, exists := lookupPointer(value, tokens); exists {
+						continue
+					}
+				}
+			}
+			ops = nil
+			for i := 1; i < len(tokens); i++ {
+				if _, exists := lookupPointer(value, tokens[:i]); !exists {
+					ops = append(ops, addContainerOperation(tokens[:i], tokens[i]))
+				}
+			}
+			ops = append(ops, op)
+		}
+		mutated, err := ops.Apply(doc)
+		if err != nil {
+			return nil, nil, err
+		}
+		doc = mutated
+		applied = append(applied, ops...)
+	}
+	return doc, applied, nil
+}
+
+// pointerTokens returns the unescaped reference tokens of a JSON pointer
+func pointerTokens(path string) []string {
+	if path == "" {
+		return nil
+	}
+	tokens := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(tokens[i], "~1", "/"), "~0", "~")
+	}
+	return tokens
+}
+
+// lookupPointer returns the value at the reference tokens of a JSON pointer in the decoded document
+func lookupPointer(value interface{}, tokens []string) (interface{}, bool) {
+	for _, token := range tokens {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var ok bool
+			if value, ok = v[token]; !ok {
+				return nil, false
+			}
+		case []interface{}:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// addContainerOperation returns the operation adding an empty container at the reference tokens of a JSON pointer: an
+// array if the next token is an array index, an object otherwise
+func addContainerOperation(tokens []string, next string) jsonpatch.Operation {
+	escaped := make([]string, len(tokens))
+	for i, token := range tokens {
+		escaped[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+	}
+	value := json.RawMessage("{}")
+	if _, err := strconv.Atoi(next); err == nil || next == "-" {
+		value = json.RawMessage("[]")
+	}
+	op, _ := json.Marshal("add")
+	path, _ := json.Marshal("/" + strings.Join(escaped, "/"))
+	return jsonpatch.Operation{"op": (*json.RawMessage)(&op), "path": (*json.RawMessage)(&path), "value": &value}
+}
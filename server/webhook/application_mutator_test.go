@@ -0,0 +1,150 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+const testMutationRules = `
+cost-allocation: |
+  patch:
+  - op: add
+    path: /metadata/labels/cost-center
+    value: shared
+  - op: add
+    path: /metadata/annotations/example.com~1owner
+    value: platform
+payments: |
+  selector:
+    matchLabels:
+      team: payments
+  patch:
+  - op: add
+    path: /metadata/labels/cost-center
+    value: payments
+  - op: add
+    path: /spec/syncPolicy/syncOptions/-
+    value: CreateNamespace=true
+`
+
+func newTestApplicationMutator(t *testing.T) *ApplicationMutator {
+	t.Helper()
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: common.ArgoCDAppMutationsConfigMapName, Namespace: testNamespace}}
+	require.NoError(t, yaml.Unmarshal([]byte(testMutationRules), &cm.Data))
+	return NewApplicationMutator(fake.NewSimpleClientset(cm), testNamespace)
+}
+
+func newMutationAdmissionRequest(t *testing.T, operation admissionv1.Operation, labels map[string]string) *admissionv1.AdmissionRequest {
+	t.Helper()
+	raw, err := json.Marshal(v1alpha1.Application{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "argoproj.io/v1alpha1", Kind: "Application"},
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: testNamespace, Labels: labels},
+		Spec: v1alpha1.ApplicationSpec{
+			Destination: v1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "guestbook"},
+		},
+	})
+	require.NoError(t, err)
+	return &admissionv1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"},
+		Operation: operation,
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+}
+
+// mutate applies the patch of the admission response to the application of the request
+func mutate(t *testing.T, req *admissionv1.AdmissionRequest, res *admissionv1.AdmissionResponse) *v1alpha1.Application {
+	t.Helper()
+	require.True(t, res.Allowed)
+	data := req.Object.Raw
+	if res.Patch != nil {
+		require.Equal(t, admissionv1.PatchTypeJSONPatch, *res.PatchType)
+		patch, err := jsonpatch.DecodePatch(res.Patch)
+		require.NoError(t, err)
+		data, err = patch.Apply(data)
+		require.NoError(t, err)
+	}
+	var app v1alpha1.Application
+	require.NoError(t, json.Unmarshal(data, &app))
+	return &app
+}
+
+func TestApplicationMutator_Mutate(t *testing.T) {
+	mutator := newTestApplicationMutator(t)
+
+	t.Run("InjectsLabels", func(t *testing.T) {
+		req := newMutationAdmissionRequest(t, admissionv1.Create, nil)
+		app := mutate(t, req, mutator.Mutate(context.Background(), req))
+		assert.Equal(t, map[string]string{"cost-center": "shared"}, app.Labels)
+		assert.Equal(t, map[string]string{"example.com/owner": "platform"}, app.Annotations)
+		assert.Nil(t, app.Spec.SyncPolicy)
+	})
+
+	t.Run("DoesNotOverwriteExistingLabels", func(t *testing.T) {
+		req := newMutationAdmissionRequest(t, admissionv1.Create, map[string]string{"cost-center": "marketing", "team": "web"})
+		app := mutate(t, req, mutator.Mutate(context.Background(), req))
+		assert.Equal(t, map[string]string{"cost-center": "marketing", "team": "web"}, app.Labels)
+		assert.Equal(t, map[string]string{"example.com/owner": "platform"}, app.Annotations)
+	})
+
+	t.Run("RulesAreAppliedInOrder", func(t *testing.T) {
+		req := newMutationAdmissionRequest(t, admissionv1.Create, map[string]string{"team": "payments"})
+		app := mutate(t, req, mutator.Mutate(context.Background(), req))
+		// the cost-allocation rule sets the label first, so the payments rule does not overwrite it
+		assert.Equal(t, map[string]string{"cost-center": "shared", "team": "payments"}, app.Labels)
+		require.NotNil(t, app.Spec.SyncPolicy)
+		assert.Equal(t, v1alpha1.SyncOptions{"CreateNamespace=true"}, app.Spec.SyncPolicy.SyncOptions)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		req := newMutationAdmissionRequest(t, admissionv1.Update, nil)
+		res := mutator.Mutate(context.Background(), req)
+		assert.True(t, res.Allowed)
+		assert.Nil(t, res.Patch)
+	})
+
+	t.Run("NoRules", func(t *testing.T) {
+		req := newMutationAdmissionRequest(t, admissionv1.Create, nil)
+		res := NewApplicationMutator(fake.NewSimpleClientset(), testNamespace).Mutate(context.Background(), req)
+		assert.True(t, res.Allowed)
+		assert.Nil(t, res.Patch)
+	})
+}
+
+func TestMutateApplication_InvalidRule(t *testing.T) {
+	rules, err := MutationRulesFromConfigMap(&corev1.ConfigMap{Data: map[string]string{
+		"a-invalid": `{"patch": [{"op": "remove", "path": "/spec/missing"}]}`,
+		"b-labels":  `{"patch": [{"op": "add", "path": "/metadata/labels/team", "value": "web"}]}`,
+	}})
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+
+	mutated, patch, warnings, err := MutateApplication([]byte(`{"metadata":{"name":"guestbook"},"spec":{}}`), rules)
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "mutation rule a-invalid was not applied")
+	assert.JSONEq(t, `{"metadata":{"name":"guestbook","labels":{"team":"web"}},"spec":{}}`, string(mutated))
+	data, err := json.Marshal(patch)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"op":"add","path":"/metadata/labels","value":{}},{"op":"add","path":"/metadata/labels/team","value":"web"}]`, string(data))
+}
+
+func TestMutationRulesFromConfigMap_InvalidSelector(t *testing.T) {
+	_, err := MutationRulesFromConfigMap(&corev1.ConfigMap{Data: map[string]string{
+		"invalid": `{"selector": {"matchExpressions": [{"key": "team", "operator": "Unknown"}]}, "patch": []}`,
+	}})
+	assert.ErrorContains(t, err, "error parsing selector of mutation rule invalid")
+}
@@ -0,0 +1,205 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/open-policy-agent/opa/rego"
+	log "github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
+)
+
+const (
+	// ApplicationPolicyDenyQuery is the query of the Rego policies returning the reasons an application is denied
+	ApplicationPolicyDenyQuery = "data.argocd.applications.deny"
+	// applicationPolicySuffix is the suffix of the keys of the argocd-app-policies ConfigMap holding Rego policies
+	applicationPolicySuffix = ".rego"
+)
+
+// ApplicationPolicies are compiled Rego policies which deny applications with the messages of the
+// data.argocd.applications.deny set
+type ApplicationPolicies struct {
+	query rego.PreparedEvalQuery
+}
+
+// PoliciesFromConfigMap returns the Rego policies of the argocd-app-policies ConfigMap, which holds one policy per key
+// ending with .rego
+func PoliciesFromConfigMap(cm *corev1.ConfigMap) map[string]string {
+	policies := map[string]string{}
+	for name, data := range cm.Data {
+		if strings.HasSuffix(name, applicationPolicySuffix) {
+			policies[name] = data
+		}
+	}
+	return policies
+}
+
+// CompileApplicationPolicies compiles the Rego policies, given by file name
+func CompileApplicationPolicies(ctx context.Context, policies map[string]string) (*ApplicationPolicies, error) {
+	options := []func(*rego.Rego){rego.Query(ApplicationPolicyDenyQuery)}
+	for name, policy := range policies {
+		options = append(options, rego.Module(name, policy))
+	}
+	query, err := rego.New(options...).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error compiling application policies: %w", err)
+	}
+	return &ApplicationPolicies{query: query}, nil
+}
+
+// Evaluate evaluates the policies with the application, given as JSON or YAML, as input and returns the sorted
+// messages denying it. The application is permitted if there are none.
+func (p *ApplicationPolicies) Evaluate(ctx context.Context, app []byte) ([]string, error) {
+	var input map[string]interface{}
+	if err := yaml.Unmarshal(app, &input); err != nil {
+		return nil, fmt.Errorf("failed to decode application: %w", err)
+	}
+	results, err := p.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating application policies: %w", err)
+	}
+	var denials []string
+	for _, result := range results {
+		for _, expression := range result.Expressions {
+			messages, ok := expression.Value.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%s must be a set of messages, got %T", ApplicationPolicyDenyQuery, expression.Value)
+			}
+			for _, message := range messages {
+				if s, ok := message.(string); ok {
+					denials = append(denials, s)
+				} else {
+					denials = append(denials, fmt.Sprintf("%v", message))
+				}
+			}
+		}
+	}
+	sort.Strings(denials)
+	return denials, nil
+}
+
+// ApplicationPolicyValidator is a validating admission webhook which rejects the applications denied by the Rego
+// policies of the argocd-app-policies ConfigMap
+type ApplicationPolicyValidator struct {
+	clientset kubernetes.Interface
+	namespace string
+
+	lock sync.Mutex
+	// policies are the policies compiled from the ConfigMap with the resource version policiesVersion
+	policies        *ApplicationPolicies
+	policiesVersion string
+}
+
+// NewApplicationPolicyValidator creates a validating admission webhook reading its policies from the namespace of
+// Argo CD
+func NewApplicationPolicyValidator(clientset kubernetes.Interface, namespace string) *ApplicationPolicyValidator {
+	return &ApplicationPolicyValidator{
+		clientset: clientset,
+		namespace: namespace,
+	}
+}
+
+func (v *ApplicationPolicyValidator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(w, r, v.Validate)
+}
+
+// Validate rejects the application of the admission request if the policies deny it. Each message of the policies is
+// returned as a cause of the denial. Applications are admitted with a warning if the policies cannot be evaluated.
+func (v *ApplicationPolicyValidator) Validate(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	allowed := &admissionv1.AdmissionResponse{Allowed: true}
+	if req.Operation == admissionv1.Delete || req.Kind.Group != application.Group || req.Kind.Kind != application.ApplicationKind {
+		return allowed
+	}
+	denials, err := v.evaluate(ctx, req.Object.Raw)
+	if err != nil {
+		log.Warnf("Failed to evaluate application policies: %v", err)
+		allowed.Warnings = append(allowed.Warnings, fmt.Sprintf("application policies were not evaluated: %v", err))
+		return allowed
+	}
+	if len(denials) == 0 {
+		return allowed
+	}
+	causes := make([]metav1.StatusCause, 0, len(denials))
+	for _, denial := range denials {
+		causes = append(causes, metav1.StatusCause{Type: metav1.CauseTypeForbidden, Message: denial})
+	}
+	response := deniedResponse(http.StatusForbidden, metav1.StatusReasonForbidden, fmt.Sprintf("application denied by policy: %s", strings.Join(denials, "; ")))
+	response.Result.Details = &metav1.StatusDetails{Causes: causes}
+	return response
+}
+
+// ApplicationPolicyValidationResult is the response of the validation of an application against the policies
+type ApplicationPolicyValidationResult struct {
+	Allowed bool     `json:"allowed"`
+	Denials []string `json:"denials,omitempty"`
+}
+
+// ServeValidation evaluates the policies with the application in the body of the request, so that it can be checked
+// before it is created
+func (v *ApplicationPolicyValidator) ServeValidation(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var app json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdmissionReviewSize)).Decode(&app); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to decode application: %v", err), http.StatusBadRequest)
+		return
+	}
+	denials, err := v.evaluate(r.Context(), app)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(ApplicationPolicyValidationResult{Allowed: len(denials) == 0, Denials: denials}); err != nil {
+		log.Warnf("Failed to write application policy validation response: %v", err)
+	}
+}
+
+// evaluate evaluates the policies of the argocd-app-policies ConfigMap with the application. Applications are
+// permitted if the ConfigMap does not exist.
+func (v *ApplicationPolicyValidator) evaluate(ctx context.Context, app []byte) ([]string, error) {
+	policies, err := v.getPolicies(ctx)
+	if err != nil || policies == nil {
+		return nil, err
+	}
+	return policies.Evaluate(ctx, app)
+}
+
+// getPolicies returns the compiled policies of the argocd-app-policies ConfigMap, which are only compiled again once
+// the ConfigMap changed, or nil if it does not exist
+func (v *ApplicationPolicyValidator) getPolicies(ctx context.Context) (*ApplicationPolicies, error) {
+	cm, err := v.clientset.CoreV1().ConfigMaps(v.namespace).Get(ctx, common.ArgoCDAppPoliciesConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error getting ConfigMap %s: %w", common.ArgoCDAppPoliciesConfigMapName, err)
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if v.policies != nil && cm.ResourceVersion != "" && v.policiesVersion == cm.ResourceVersion {
+		return v.policies, nil
+	}
+	policies, err := CompileApplicationPolicies(ctx, PoliciesFromConfigMap(cm))
+	if err != nil {
+		return nil, err
+	}
+	v.policies, v.policiesVersion = policies, cm.ResourceVersion
+	return policies, nil
+}
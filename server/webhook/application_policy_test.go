@@ -0,0 +1,189 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+const testProductionPolicy = `package argocd.applications
+
+deny[msg] {
+	input.metadata.labels.env == "production"
+	not input.spec.syncPolicy.automated.selfHeal
+	msg := sprintf("production application %s must enable selfHeal", [input.metadata.name])
+}
+`
+
+const testOwnerPolicy = `package argocd.applications
+
+deny[msg] {
+	not input.metadata.labels.owner
+	msg := "application must have an owner label"
+}
+`
+
+func newPolicyTestApplication(t *testing.T, labels map[string]string, selfHeal bool) []byte {
+	t.Helper()
+	app := v1alpha1.Application{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "argoproj.io/v1alpha1", Kind: "Application"},
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: testNamespace, Labels: labels},
+		Spec: v1alpha1.ApplicationSpec{
+			Destination: v1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "guestbook"},
+			SyncPolicy:  &v1alpha1.SyncPolicy{Automated: &v1alpha1.SyncPolicyAutomated{SelfHeal: selfHeal}},
+		},
+	}
+	raw, err := json.Marshal(app)
+	require.NoError(t, err)
+	return raw
+}
+
+func newPolicyAdmissionRequest(operation admissionv1.Operation, raw []byte) *admissionv1.AdmissionRequest {
+	return &admissionv1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"},
+		Operation: operation,
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+}
+
+func newTestApplicationPolicyValidator(data map[string]string) *ApplicationPolicyValidator {
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: common.ArgoCDAppPoliciesConfigMapName, Namespace: testNamespace, ResourceVersion: "1"},
+		Data:       data,
+	}
+	return NewApplicationPolicyValidator(fake.NewSimpleClientset(cm), testNamespace)
+}
+
+func TestApplicationPolicies_Evaluate(t *testing.T) {
+	policies, err := CompileApplicationPolicies(context.Background(), map[string]string{
+		"production.rego": testProductionPolicy,
+		"owner.rego":      testOwnerPolicy,
+	})
+	require.NoError(t, err)
+
+	denials, err := policies.Evaluate(context.Background(), newPolicyTestApplication(t, map[string]string{"env": "production"}, false))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"application must have an owner label", "production application guestbook must enable selfHeal"}, denials)
+
+	denials, err = policies.Evaluate(context.Background(), newPolicyTestApplication(t, map[string]string{"env": "production", "owner": "team-a"}, true))
+	require.NoError(t, err)
+	assert.Empty(t, denials)
+
+	// applications can be given as YAML
+	denials, err = policies.Evaluate(context.Background(), []byte("metadata:\n  name: guestbook\n  labels:\n    owner: team-a\n"))
+	require.NoError(t, err)
+	assert.Empty(t, denials)
+
+	_, err = CompileApplicationPolicies(context.Background(), map[string]string{"broken.rego": "package argocd.applications\ndeny[msg] {"})
+	require.ErrorContains(t, err, "error compiling application policies")
+}
+
+func TestPoliciesFromConfigMap(t *testing.T) {
+	policies := PoliciesFromConfigMap(&corev1.ConfigMap{Data: map[string]string{
+		"production.rego": testProductionPolicy,
+		"README":          "not a policy",
+	}})
+	assert.Equal(t, map[string]string{"production.rego": testProductionPolicy}, policies)
+}
+
+func TestApplicationPolicyValidator_Validate(t *testing.T) {
+	validator := newTestApplicationPolicyValidator(map[string]string{"production.rego": testProductionPolicy, "owner.rego": testOwnerPolicy})
+
+	t.Run("denied", func(t *testing.T) {
+		res := validator.Validate(context.Background(), newPolicyAdmissionRequest(admissionv1.Create, newPolicyTestApplication(t, map[string]string{"env": "production"}, false)))
+		assert.False(t, res.Allowed)
+		assert.Equal(t, int32(http.StatusForbidden), res.Result.Code)
+		assert.Equal(t, "application denied by policy: application must have an owner label; production application guestbook must enable selfHeal", res.Result.Message)
+		require.Len(t, res.Result.Details.Causes, 2)
+		assert.Equal(t, "application must have an owner label", res.Result.Details.Causes[0].Message)
+		assert.Equal(t, "production application guestbook must enable selfHeal", res.Result.Details.Causes[1].Message)
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		res := validator.Validate(context.Background(), newPolicyAdmissionRequest(admissionv1.Update, newPolicyTestApplication(t, map[string]string{"env": "production", "owner": "team-a"}, true)))
+		assert.True(t, res.Allowed)
+	})
+
+	t.Run("deletions are not validated", func(t *testing.T) {
+		res := validator.Validate(context.Background(), newPolicyAdmissionRequest(admissionv1.Delete, newPolicyTestApplication(t, nil, false)))
+		assert.True(t, res.Allowed)
+	})
+
+	t.Run("no policies", func(t *testing.T) {
+		validator := NewApplicationPolicyValidator(fake.NewSimpleClientset(), testNamespace)
+		res := validator.Validate(context.Background(), newPolicyAdmissionRequest(admissionv1.Create, newPolicyTestApplication(t, nil, false)))
+		assert.True(t, res.Allowed)
+		assert.Empty(t, res.Warnings)
+	})
+
+	t.Run("invalid policies", func(t *testing.T) {
+		validator := newTestApplicationPolicyValidator(map[string]string{"broken.rego": "package argocd.applications\ndeny[msg] {"})
+		res := validator.Validate(context.Background(), newPolicyAdmissionRequest(admissionv1.Create, newPolicyTestApplication(t, nil, false)))
+		assert.True(t, res.Allowed)
+		require.Len(t, res.Warnings, 1)
+		assert.Contains(t, res.Warnings[0], "application policies were not evaluated")
+	})
+}
+
+func TestApplicationPolicyValidator_CompilesOnChange(t *testing.T) {
+	validator := newTestApplicationPolicyValidator(map[string]string{"owner.rego": testOwnerPolicy})
+	policies, err := validator.getPolicies(context.Background())
+	require.NoError(t, err)
+	cached, err := validator.getPolicies(context.Background())
+	require.NoError(t, err)
+	assert.Same(t, policies, cached)
+
+	cm, err := validator.clientset.CoreV1().ConfigMaps(testNamespace).Get(context.Background(), common.ArgoCDAppPoliciesConfigMapName, metav1.GetOptions{})
+	require.NoError(t, err)
+	cm.Data = map[string]string{}
+	cm.ResourceVersion = "2"
+	_, err = validator.clientset.CoreV1().ConfigMaps(testNamespace).Update(context.Background(), cm, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	updated, err := validator.getPolicies(context.Background())
+	require.NoError(t, err)
+	assert.NotSame(t, policies, updated)
+	denials, err := updated.Evaluate(context.Background(), newPolicyTestApplication(t, nil, false))
+	require.NoError(t, err)
+	assert.Empty(t, denials)
+}
+
+func TestApplicationPolicyValidator_ServeValidation(t *testing.T) {
+	validator := newTestApplicationPolicyValidator(map[string]string{"production.rego": testProductionPolicy})
+
+	validate := func(body string) (*httptest.ResponseRecorder, ApplicationPolicyValidationResult) {
+		w := httptest.NewRecorder()
+		validator.ServeValidation(w, httptest.NewRequest(http.MethodPost, "/api/v1/applications/validate", strings.NewReader(body)))
+		var result ApplicationPolicyValidationResult
+		if w.Code == http.StatusOK {
+			require.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+		}
+		return w, result
+	}
+
+	w, result := validate(string(newPolicyTestApplication(t, map[string]string{"env": "production"}, false)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, []string{"production application guestbook must enable selfHeal"}, result.Denials)
+
+	w, result = validate(string(newPolicyTestApplication(t, map[string]string{"env": "production"}, true)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, result.Allowed)
+	assert.Empty(t, result.Denials)
+
+	w, _ = validate("{")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,171 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	applisters "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+	"github.com/argoproj/argo-cd/v2/util/db"
+	"github.com/argoproj/argo-cd/v2/util/rbac"
+)
+
+// adminRole is the role required to change the destination of applications and the health overrides
+const adminRole = "role:admin"
+
+// DestinationValidator is a validating admission webhook which rejects applications whose destination is not permitted
+// by their project, before they are persisted.
+type DestinationValidator struct {
+	db         db.ArgoDB
+	projLister applisters.AppProjectNamespaceLister
+}
+
+// NewDestinationValidator creates a validating admission webhook enforcing the destination restrictions of projects
+func NewDestinationValidator(db db.ArgoDB, projLister applisters.AppProjectNamespaceLister) *DestinationValidator {
+	return &DestinationValidator{
+		db:         db,
+		projLister: projLister,
+	}
+}
+
+func (v *DestinationValidator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(w, r, v.Validate)
+}
+
+// Validate validates the destination of the application of the admission request against its project. Updates which
+// change neither the project nor the destination are admitted, so that the status of applications which are no longer
+// permitted can still be updated.
+func (v *DestinationValidator) Validate(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	allowed := &admissionv1.AdmissionResponse{Allowed: true}
+	if req.Operation == admissionv1.Delete || req.Kind.Group != application.Group || req.Kind.Kind != application.ApplicationKind {
+		return allowed
+	}
+	var app v1alpha1.Application
+	if err := json.Unmarshal(req.Object.Raw, &app); err != nil {
+		return deniedResponse(http.StatusBadRequest, metav1.StatusReasonBadRequest, fmt.Sprintf("failed to decode application: %v", err))
+	}
+	if req.Operation == admissionv1.Update && len(req.OldObject.Raw) > 0 {
+		var oldApp v1alpha1.Application
+		if err := json.Unmarshal(req.OldObject.Raw, &oldApp); err == nil &&
+			oldApp.Spec.GetProject() == app.Spec.GetProject() && oldApp.Spec.Destination.Equals(app.Spec.Destination) {
+			return allowed
+		}
+	}
+
+	logCtx := log.WithField("application", app.QualifiedName())
+	proj, err := v.projLister.Get(app.Spec.GetProject())
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return deniedResponse(http.StatusForbidden, metav1.StatusReasonForbidden, fmt.Sprintf("application references project %s which does not exist", app.Spec.GetProject()))
+		}
+		logCtx.Warnf("Failed to get project: %v", err)
+		allowed.Warnings = append(allowed.Warnings, fmt.Sprintf("destination was not validated: %v", err))
+		return allowed
+	}
+
+	dest := app.Spec.Destination
+	if err := argo.ValidateDestination(ctx, &dest, v.db); err != nil {
+		return deniedResponse(http.StatusUnprocessableEntity, metav1.StatusReasonInvalid, err.Error())
+	}
+	permitted, err := proj.IsDestinationPermitted(dest, func(project string) ([]*v1alpha1.Cluster, error) {
+		return v.db.GetProjectClusters(ctx, project)
+	})
+	if err != nil {
+		logCtx.Warnf("Failed to validate destination: %v", err)
+		allowed.Warnings = append(allowed.Warnings, fmt.Sprintf("destination was not validated: %v", err))
+		return allowed
+	}
+	if !permitted {
+		return deniedResponse(http.StatusForbidden, metav1.StatusReasonForbidden, fmt.Sprintf("application destination server '%s' and namespace '%s' do not match any of the allowed destinations in project '%s'", dest.Server, dest.Namespace, proj.Name))
+	}
+	return allowed
+}
+
+// DestinationChangeValidator is a validating admission webhook which rejects changes of the destination or project of
+// existing applications, which can leave their managed resources behind without cleanup, unless they are made by a
+// user with the admin role or the application is annotated to allow them.
+type DestinationChangeValidator struct {
+	enf         *rbac.Enforcer
+	auditLogger *argo.AuditLogger
+}
+
+// NewDestinationChangeValidator creates a validating admission webhook protecting the destinations of applications
+func NewDestinationChangeValidator(enf *rbac.Enforcer, auditLogger *argo.AuditLogger) *DestinationChangeValidator {
+	return &DestinationChangeValidator{
+		enf:         enf,
+		auditLogger: auditLogger,
+	}
+}
+
+func (v *DestinationChangeValidator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(w, r, v.Validate)
+}
+
+// Validate rejects updates of the application of the admission request which change its destination or project,
+// unless the Kubernetes user or one of its groups is assigned the admin role in the RBAC policy, or the updated
+// application is annotated with argocd.argoproj.io/allow-destination-change=true. Permitted changes are recorded as
+// events of the application.
+func (v *DestinationChangeValidator) Validate(_ context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	allowed := &admissionv1.AdmissionResponse{Allowed: true}
+	if req.Operation != admissionv1.Update || req.Kind.Group != application.Group || req.Kind.Kind != application.ApplicationKind {
+		return allowed
+	}
+	var app, oldApp v1alpha1.Application
+	if err := json.Unmarshal(req.Object.Raw, &app); err != nil {
+		return deniedResponse(http.StatusBadRequest, metav1.StatusReasonBadRequest, fmt.Sprintf("failed to decode application: %v", err))
+	}
+	if err := json.Unmarshal(req.OldObject.Raw, &oldApp); err != nil {
+		return deniedResponse(http.StatusBadRequest, metav1.StatusReasonBadRequest, fmt.Sprintf("failed to decode previous application: %v", err))
+	}
+	if oldApp.Spec.GetProject() == app.Spec.GetProject() && oldApp.Spec.Destination.Equals(app.Spec.Destination) {
+		return allowed
+	}
+
+	user := req.UserInfo.Username
+	var permittedBy string
+	switch {
+	case app.Annotations[common.AnnotationKeyAllowDestinationChange] == "true":
+		permittedBy = fmt.Sprintf("annotation %s", common.AnnotationKeyAllowDestinationChange)
+	case v.enf.HasRole(adminRole, append([]string{user}, req.UserInfo.Groups...)...):
+		permittedBy = adminRole
+	default:
+		return deniedResponse(http.StatusForbidden, metav1.StatusReasonForbidden, fmt.Sprintf(
+			"changing the destination or project of application %s requires the %s role, or the %s=true annotation", app.QualifiedName(), adminRole, common.AnnotationKeyAllowDestinationChange))
+	}
+
+	message := fmt.Sprintf("Destination changed from %s in project %s to %s in project %s, permitted by %s",
+		formatDestination(oldApp.Spec.Destination), oldApp.Spec.GetProject(), formatDestination(app.Spec.Destination), app.Spec.GetProject(), permittedBy)
+	log.WithFields(log.Fields{"application": app.QualifiedName(), "user": user}).Info(message)
+	v.auditLogger.LogAppEvent(&app, argo.EventInfo{Reason: argo.EventReasonDestinationChanged, Type: corev1.EventTypeNormal}, message, user, nil)
+	return allowed
+}
+
+// formatDestination returns the cluster and namespace of a destination
+func formatDestination(dest v1alpha1.ApplicationDestination) string {
+	cluster := dest.Server
+	if cluster == "" {
+		cluster = dest.Name
+	}
+	return fmt.Sprintf("%s/%s", cluster, dest.Namespace)
+}
+
+// deniedResponse returns an admission response rejecting the request
+func deniedResponse(code int32, reason metav1.StatusReason, message string) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{Result: &metav1.Status{
+		Status:  metav1.StatusFailure,
+		Code:    code,
+		Reason:  reason,
+		Message: message,
+	}}
+}
@@ -0,0 +1,289 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	applisters "github.com/argoproj/argo-cd/v2/pkg/client/listers/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+	"github.com/argoproj/argo-cd/v2/util/assets"
+	dbmocks "github.com/argoproj/argo-cd/v2/util/db/mocks"
+	"github.com/argoproj/argo-cd/v2/util/rbac"
+)
+
+func newTestDestinationValidator(t *testing.T) *DestinationValidator {
+	t.Helper()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, proj := range []*v1alpha1.AppProject{{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: testNamespace},
+		Spec: v1alpha1.AppProjectSpec{
+			Destinations: []v1alpha1.ApplicationDestination{{Server: "*", Namespace: "*"}},
+		},
+	}, {
+		ObjectMeta: metav1.ObjectMeta{Name: "team-a", Namespace: testNamespace},
+		Spec: v1alpha1.AppProjectSpec{
+			Destinations: []v1alpha1.ApplicationDestination{{Server: "https://team-a.example.com", Namespace: "team-a-*"}},
+		},
+	}} {
+		require.NoError(t, indexer.Add(proj))
+	}
+
+	db := &dbmocks.ArgoDB{}
+	db.On("GetClusterServersByName", mock.Anything, "team-a").Return([]string{"https://team-a.example.com"}, nil)
+	db.On("GetClusterServersByName", mock.Anything, "team-b").Return([]string{"https://team-b.example.com"}, nil)
+	db.On("GetClusterServersByName", mock.Anything, mock.Anything).Return(nil, nil)
+	db.On("GetProjectClusters", mock.Anything, mock.Anything).Return(nil, nil)
+	return NewDestinationValidator(db, applisters.NewAppProjectLister(indexer).AppProjects(testNamespace))
+}
+
+func newDestinationAdmissionRequest(t *testing.T, operation admissionv1.Operation, project string, dest v1alpha1.ApplicationDestination, oldDest *v1alpha1.ApplicationDestination) *admissionv1.AdmissionRequest {
+	t.Helper()
+	newApp := func(dest v1alpha1.ApplicationDestination) []byte {
+		raw, err := json.Marshal(v1alpha1.Application{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "argoproj.io/v1alpha1", Kind: "Application"},
+			ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: testNamespace},
+			Spec: v1alpha1.ApplicationSpec{
+				Project:     project,
+				Destination: dest,
+				Source:      &v1alpha1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook"},
+			},
+		})
+		require.NoError(t, err)
+		return raw
+	}
+	req := &admissionv1.AdmissionRequest{
+		UID:       types.UID("test-uid"),
+		Kind:      metav1.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"},
+		Operation: operation,
+		Object:    runtime.RawExtension{Raw: newApp(dest)},
+	}
+	if oldDest != nil {
+		req.OldObject = runtime.RawExtension{Raw: newApp(*oldDest)}
+	}
+	return req
+}
+
+func TestDestinationValidator_Validate(t *testing.T) {
+	validator := newTestDestinationValidator(t)
+
+	t.Run("PermittedDestination", func(t *testing.T) {
+		res := validator.Validate(context.Background(), newDestinationAdmissionRequest(t, admissionv1.Create, "team-a",
+			v1alpha1.ApplicationDestination{Server: "https://team-a.example.com", Namespace: "team-a-guestbook"}, nil))
+		assert.True(t, res.Allowed)
+	})
+
+	t.Run("PermittedDestinationByName", func(t *testing.T) {
+		res := validator.Validate(context.Background(), newDestinationAdmissionRequest(t, admissionv1.Create, "team-a",
+			v1alpha1.ApplicationDestination{Name: "team-a", Namespace: "team-a-guestbook"}, nil))
+		assert.True(t, res.Allowed)
+	})
+
+	t.Run("ClusterOfAnotherTeam", func(t *testing.T) {
+		res := validator.Validate(context.Background(), newDestinationAdmissionRequest(t, admissionv1.Create, "team-a",
+			v1alpha1.ApplicationDestination{Name: "team-b", Namespace: "team-a-guestbook"}, nil))
+		assert.False(t, res.Allowed)
+		require.NotNil(t, res.Result)
+		assert.Equal(t, int32(http.StatusForbidden), res.Result.Code)
+		assert.Contains(t, res.Result.Message, "https://team-b.example.com")
+	})
+
+	t.Run("NamespaceNotPermitted", func(t *testing.T) {
+		res := validator.Validate(context.Background(), newDestinationAdmissionRequest(t, admissionv1.Create, "team-a",
+			v1alpha1.ApplicationDestination{Server: "https://team-a.example.com", Namespace: "kube-system"}, nil))
+		assert.False(t, res.Allowed)
+		assert.Equal(t, int32(http.StatusForbidden), res.Result.Code)
+	})
+
+	t.Run("UnknownCluster", func(t *testing.T) {
+		res := validator.Validate(context.Background(), newDestinationAdmissionRequest(t, admissionv1.Create, "default",
+			v1alpha1.ApplicationDestination{Name: "unknown", Namespace: "guestbook"}, nil))
+		assert.False(t, res.Allowed)
+		assert.Equal(t, int32(http.StatusUnprocessableEntity), res.Result.Code)
+	})
+
+	t.Run("MissingProject", func(t *testing.T) {
+		res := validator.Validate(context.Background(), newDestinationAdmissionRequest(t, admissionv1.Create, "does-not-exist",
+			v1alpha1.ApplicationDestination{Server: "https://team-a.example.com", Namespace: "team-a-guestbook"}, nil))
+		assert.False(t, res.Allowed)
+		assert.Contains(t, res.Result.Message, "project does-not-exist which does not exist")
+	})
+
+	t.Run("UpdateWithUnchangedDestination", func(t *testing.T) {
+		dest := v1alpha1.ApplicationDestination{Server: "https://team-b.example.com", Namespace: "team-b"}
+		res := validator.Validate(context.Background(), newDestinationAdmissionRequest(t, admissionv1.Update, "team-a", dest, &dest))
+		assert.True(t, res.Allowed)
+	})
+
+	t.Run("UpdateWithChangedDestination", func(t *testing.T) {
+		res := validator.Validate(context.Background(), newDestinationAdmissionRequest(t, admissionv1.Update, "team-a",
+			v1alpha1.ApplicationDestination{Server: "https://team-b.example.com", Namespace: "team-a-guestbook"},
+			&v1alpha1.ApplicationDestination{Server: "https://team-a.example.com", Namespace: "team-a-guestbook"}))
+		assert.False(t, res.Allowed)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		res := validator.Validate(context.Background(), newDestinationAdmissionRequest(t, admissionv1.Delete, "team-a",
+			v1alpha1.ApplicationDestination{Server: "https://team-b.example.com", Namespace: "team-b"}, nil))
+		assert.True(t, res.Allowed)
+	})
+}
+
+func TestDestinationValidator_ServeHTTP(t *testing.T) {
+	review := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request: newDestinationAdmissionRequest(t, admissionv1.Create, "team-a",
+			v1alpha1.ApplicationDestination{Server: "https://team-b.example.com", Namespace: "team-b"}, nil),
+	}
+	body, err := json.Marshal(review)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/validate/application-destinations", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	newTestDestinationValidator(t).ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var res admissionv1.AdmissionReview
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+	require.NotNil(t, res.Response)
+	assert.Equal(t, types.UID("test-uid"), res.Response.UID)
+	assert.False(t, res.Response.Allowed)
+}
+
+func newTestDestinationChangeValidator(t *testing.T) (*DestinationChangeValidator, *fake.Clientset) {
+	t.Helper()
+	kubeclientset := fake.NewSimpleClientset()
+	enf := rbac.NewEnforcer(kubeclientset, testNamespace, common.ArgoCDRBACConfigMapName, nil)
+	require.NoError(t, enf.SetBuiltinPolicy(assets.BuiltinPolicyCSV))
+	require.NoError(t, enf.SetUserPolicy("g, platform-team, role:admin"))
+	return NewDestinationChangeValidator(enf, argo.NewAuditLogger(testNamespace, kubeclientset, "argocd-server")), kubeclientset
+}
+
+func newDestinationChangeAdmissionRequest(t *testing.T, oldProject, project string, oldDest, dest v1alpha1.ApplicationDestination, annotations map[string]string, user authenticationv1.UserInfo) *admissionv1.AdmissionRequest {
+	t.Helper()
+	newApp := func(project string, dest v1alpha1.ApplicationDestination) []byte {
+		raw, err := json.Marshal(v1alpha1.Application{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "argoproj.io/v1alpha1", Kind: "Application"},
+			ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: testNamespace, Annotations: annotations},
+			Spec: v1alpha1.ApplicationSpec{
+				Project:     project,
+				Destination: dest,
+				Source:      &v1alpha1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook"},
+			},
+		})
+		require.NoError(t, err)
+		return raw
+	}
+	return &admissionv1.AdmissionRequest{
+		UID:       types.UID("test-uid"),
+		Kind:      metav1.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"},
+		Operation: admissionv1.Update,
+		UserInfo:  user,
+		Object:    runtime.RawExtension{Raw: newApp(project, dest)},
+		OldObject: runtime.RawExtension{Raw: newApp(oldProject, oldDest)},
+	}
+}
+
+func TestDestinationChangeValidator_Validate(t *testing.T) {
+	oldDest := v1alpha1.ApplicationDestination{Server: "https://team-a.example.com", Namespace: "guestbook"}
+	newDest := v1alpha1.ApplicationDestination{Server: "https://team-b.example.com", Namespace: "guestbook"}
+	developer := authenticationv1.UserInfo{Username: "alice", Groups: []string{"developers"}}
+
+	t.Run("UnchangedDestination", func(t *testing.T) {
+		validator, _ := newTestDestinationChangeValidator(t)
+		res := validator.Validate(context.Background(), newDestinationChangeAdmissionRequest(t, "default", "default", oldDest, oldDest, nil, developer))
+		assert.True(t, res.Allowed)
+	})
+
+	t.Run("ChangedDestination", func(t *testing.T) {
+		validator, _ := newTestDestinationChangeValidator(t)
+		res := validator.Validate(context.Background(), newDestinationChangeAdmissionRequest(t, "default", "default", oldDest, newDest, nil, developer))
+		assert.False(t, res.Allowed)
+		require.NotNil(t, res.Result)
+		assert.Equal(t, int32(http.StatusForbidden), res.Result.Code)
+		assert.Contains(t, res.Result.Message, "requires the role:admin role")
+	})
+
+	t.Run("ChangedProject", func(t *testing.T) {
+		validator, _ := newTestDestinationChangeValidator(t)
+		res := validator.Validate(context.Background(), newDestinationChangeAdmissionRequest(t, "default", "team-b", oldDest, oldDest, nil, developer))
+		assert.False(t, res.Allowed)
+	})
+
+	t.Run("ChangedByAdmin", func(t *testing.T) {
+		validator, kubeclientset := newTestDestinationChangeValidator(t)
+		res := validator.Validate(context.Background(), newDestinationChangeAdmissionRequest(t, "default", "team-b", oldDest, newDest, nil,
+			authenticationv1.UserInfo{Username: "bob", Groups: []string{"platform-team"}}))
+		assert.True(t, res.Allowed)
+
+		events, err := kubeclientset.CoreV1().Events(testNamespace).List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		require.Len(t, events.Items, 1)
+		assert.Equal(t, argo.EventReasonDestinationChanged, events.Items[0].Reason)
+		assert.Equal(t, "bob", events.Items[0].Annotations["user"])
+		assert.Contains(t, events.Items[0].Message, "https://team-a.example.com/guestbook in project default to https://team-b.example.com/guestbook in project team-b")
+	})
+
+	t.Run("ChangeAllowedByAnnotation", func(t *testing.T) {
+		validator, kubeclientset := newTestDestinationChangeValidator(t)
+		res := validator.Validate(context.Background(), newDestinationChangeAdmissionRequest(t, "default", "default", oldDest, newDest,
+			map[string]string{common.AnnotationKeyAllowDestinationChange: "true"}, developer))
+		assert.True(t, res.Allowed)
+
+		events, err := kubeclientset.CoreV1().Events(testNamespace).List(context.Background(), metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Len(t, events.Items, 1)
+	})
+
+	t.Run("Create", func(t *testing.T) {
+		validator, _ := newTestDestinationChangeValidator(t)
+		req := newDestinationChangeAdmissionRequest(t, "default", "default", oldDest, newDest, nil, developer)
+		req.Operation = admissionv1.Create
+		req.OldObject = runtime.RawExtension{}
+		assert.True(t, validator.Validate(context.Background(), req).Allowed)
+	})
+}
+
+func TestDestinationChangeValidator_ServeHTTP(t *testing.T) {
+	validator, _ := newTestDestinationChangeValidator(t)
+	server := httptest.NewServer(validator)
+	defer server.Close()
+
+	review := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request: newDestinationChangeAdmissionRequest(t, "default", "default",
+			v1alpha1.ApplicationDestination{Server: "https://team-a.example.com", Namespace: "guestbook"},
+			v1alpha1.ApplicationDestination{Server: "https://team-b.example.com", Namespace: "guestbook"},
+			nil, authenticationv1.UserInfo{Username: "alice"}),
+	}
+	body, err := json.Marshal(review)
+	require.NoError(t, err)
+
+	resp, err := http.Post(server.URL+"/api/validate/application-destination-changes", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var res admissionv1.AdmissionReview
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+	require.NotNil(t, res.Response)
+	assert.Equal(t, types.UID("test-uid"), res.Response.UID)
+	assert.False(t, res.Response.Allowed)
+	assert.Equal(t, int32(http.StatusForbidden), res.Response.Result.Code)
+}
@@ -0,0 +1,100 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// ApplicationSetDeletionValidator is a validating admission webhook which rejects the deletion of ApplicationSets
+// annotated with argocd.argoproj.io/deletion-protected=true, which would delete all the Applications they generated.
+type ApplicationSetDeletionValidator struct{}
+
+// NewApplicationSetDeletionValidator creates a validating admission webhook protecting ApplicationSets from deletion
+func NewApplicationSetDeletionValidator() *ApplicationSetDeletionValidator {
+	return &ApplicationSetDeletionValidator{}
+}
+
+func (v *ApplicationSetDeletionValidator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(w, r, v.Validate)
+}
+
+// Validate rejects the deletion of the ApplicationSet of the admission request if it is deletion protected
+func (v *ApplicationSetDeletionValidator) Validate(_ context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	allowed := &admissionv1.AdmissionResponse{Allowed: true}
+	if req.Operation != admissionv1.Delete || req.Kind.Group != application.Group || req.Kind.Kind != application.ApplicationSetKind {
+		return allowed
+	}
+	var appset v1alpha1.ApplicationSet
+	if err := json.Unmarshal(req.OldObject.Raw, &appset); err != nil {
+		return deniedResponse(http.StatusBadRequest, metav1.StatusReasonBadRequest, fmt.Sprintf("failed to decode ApplicationSet: %v", err))
+	}
+	if !appset.IsDeletionProtected() {
+		return allowed
+	}
+	return deniedResponse(http.StatusUnprocessableEntity, metav1.StatusReasonInvalid, fmt.Sprintf(
+		"ApplicationSet %s is protected from deletion by the %s annotation, remove the annotation to delete it", appset.QualifiedName(), common.AnnotationKeyDeletionProtected))
+}
+
+// ApplicationSetMutator is a mutating admission webhook which protects ApplicationSets preserving the resources of
+// their Applications on deletion from being deleted, by annotating them with argocd.argoproj.io/deletion-protected=true
+// when they are created.
+type ApplicationSetMutator struct{}
+
+// NewApplicationSetMutator creates a mutating admission webhook setting the default deletion protection of
+// ApplicationSets
+func NewApplicationSetMutator() *ApplicationSetMutator {
+	return &ApplicationSetMutator{}
+}
+
+func (m *ApplicationSetMutator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(w, r, m.Mutate)
+}
+
+// Mutate annotates the ApplicationSet of the admission request as deletion protected if it is created with
+// spec.syncPolicy.preserveResourcesOnDeletion set to true. The annotation is not added if it is already set, whatever
+// its value, so that the protection can be disabled explicitly. Updates are not mutated, so that the protection is not
+// added back once removed.
+func (m *ApplicationSetMutator) Mutate(_ context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	allowed := &admissionv1.AdmissionResponse{Allowed: true}
+	if req.Operation != admissionv1.Create || req.Kind.Group != application.Group || req.Kind.Kind != application.ApplicationSetKind {
+		return allowed
+	}
+	var appset v1alpha1.ApplicationSet
+	if err := json.Unmarshal(req.Object.Raw, &appset); err != nil {
+		return deniedResponse(http.StatusBadRequest, metav1.StatusReasonBadRequest, fmt.Sprintf("failed to decode ApplicationSet: %v", err))
+	}
+	if appset.Spec.SyncPolicy == nil || !appset.Spec.SyncPolicy.PreserveResourcesOnDeletion {
+		return allowed
+	}
+	if _, ok := appset.Annotations[common.AnnotationKeyDeletionProtected]; ok {
+		return allowed
+	}
+
+	op := map[string]interface{}{
+		"op":    "add",
+		"path":  "/metadata/annotations",
+		"value": map[string]string{common.AnnotationKeyDeletionProtected: "true"},
+	}
+	if appset.Annotations != nil {
+		op["path"] = "/metadata/annotations/" + strings.ReplaceAll(common.AnnotationKeyDeletionProtected, "/", "~1")
+		op["value"] = "true"
+	}
+	data, err := json.Marshal([]interface{}{op})
+	if err != nil {
+		return deniedResponse(http.StatusInternalServerError, metav1.StatusReasonInternalError, fmt.Sprintf("failed to marshal patch: %v", err))
+	}
+	patchType := admissionv1.PatchTypeJSONPatch
+	allowed.PatchType = &patchType
+	allowed.Patch = data
+	return allowed
+}
@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func newApplicationSetAdmissionRequest(t *testing.T, operation admissionv1.Operation, annotations map[string]string, preserveResources bool) *admissionv1.AdmissionRequest {
+	t.Helper()
+	appset := v1alpha1.ApplicationSet{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "argoproj.io/v1alpha1", Kind: "ApplicationSet"},
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: testNamespace, Annotations: annotations},
+	}
+	if preserveResources {
+		appset.Spec.SyncPolicy = &v1alpha1.ApplicationSetSyncPolicy{PreserveResourcesOnDeletion: true}
+	}
+	raw, err := json.Marshal(appset)
+	require.NoError(t, err)
+	req := &admissionv1.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "ApplicationSet"},
+		Operation: operation,
+	}
+	// the object of deletion requests is the deleted object
+	if operation == admissionv1.Delete {
+		req.OldObject = runtime.RawExtension{Raw: raw}
+	} else {
+		req.Object = runtime.RawExtension{Raw: raw}
+	}
+	return req
+}
+
+// mutateApplicationSet applies the patch of the admission response to the ApplicationSet of the request
+func mutateApplicationSet(t *testing.T, req *admissionv1.AdmissionRequest, res *admissionv1.AdmissionResponse) *v1alpha1.ApplicationSet {
+	t.Helper()
+	require.True(t, res.Allowed)
+	data := req.Object.Raw
+	if res.Patch != nil {
+		require.Equal(t, admissionv1.PatchTypeJSONPatch, *res.PatchType)
+		patch, err := jsonpatch.DecodePatch(res.Patch)
+		require.NoError(t, err)
+		data, err = patch.Apply(data)
+		require.NoError(t, err)
+	}
+	var appset v1alpha1.ApplicationSet
+	require.NoError(t, json.Unmarshal(data, &appset))
+	return &appset
+}
+
+func TestApplicationSetDeletionValidator_Validate(t *testing.T) {
+	validator := NewApplicationSetDeletionValidator()
+
+	t.Run("RejectsProtected", func(t *testing.T) {
+		req := newApplicationSetAdmissionRequest(t, admissionv1.Delete, map[string]string{common.AnnotationKeyDeletionProtected: "true"}, false)
+		res := validator.Validate(context.Background(), req)
+		assert.False(t, res.Allowed)
+		assert.Equal(t, int32(http.StatusUnprocessableEntity), res.Result.Code)
+		assert.Equal(t, metav1.StatusReasonInvalid, res.Result.Reason)
+		assert.Contains(t, res.Result.Message, common.AnnotationKeyDeletionProtected)
+	})
+
+	t.Run("AdmitsUnprotected", func(t *testing.T) {
+		req := newApplicationSetAdmissionRequest(t, admissionv1.Delete, map[string]string{common.AnnotationKeyDeletionProtected: "false"}, false)
+		assert.True(t, validator.Validate(context.Background(), req).Allowed)
+
+		req = newApplicationSetAdmissionRequest(t, admissionv1.Delete, nil, true)
+		assert.True(t, validator.Validate(context.Background(), req).Allowed)
+	})
+
+	t.Run("AdmitsUpdates", func(t *testing.T) {
+		req := newApplicationSetAdmissionRequest(t, admissionv1.Update, map[string]string{common.AnnotationKeyDeletionProtected: "true"}, false)
+		assert.True(t, validator.Validate(context.Background(), req).Allowed)
+	})
+}
+
+func TestApplicationSetMutator_Mutate(t *testing.T) {
+	mutator := NewApplicationSetMutator()
+
+	t.Run("ProtectsPreservingResources", func(t *testing.T) {
+		req := newApplicationSetAdmissionRequest(t, admissionv1.Create, nil, true)
+		appset := mutateApplicationSet(t, req, mutator.Mutate(context.Background(), req))
+		assert.Equal(t, map[string]string{common.AnnotationKeyDeletionProtected: "true"}, appset.Annotations)
+	})
+
+	t.Run("KeepsExistingAnnotations", func(t *testing.T) {
+		req := newApplicationSetAdmissionRequest(t, admissionv1.Create, map[string]string{"example.com/owner": "platform"}, true)
+		appset := mutateApplicationSet(t, req, mutator.Mutate(context.Background(), req))
+		assert.Equal(t, map[string]string{"example.com/owner": "platform", common.AnnotationKeyDeletionProtected: "true"}, appset.Annotations)
+	})
+
+	t.Run("DoesNotOverwriteExplicitValue", func(t *testing.T) {
+		req := newApplicationSetAdmissionRequest(t, admissionv1.Create, map[string]string{common.AnnotationKeyDeletionProtected: "false"}, true)
+		res := mutator.Mutate(context.Background(), req)
+		assert.True(t, res.Allowed)
+		assert.Nil(t, res.Patch)
+	})
+
+	t.Run("NotPreservingResources", func(t *testing.T) {
+		req := newApplicationSetAdmissionRequest(t, admissionv1.Create, nil, false)
+		res := mutator.Mutate(context.Background(), req)
+		assert.True(t, res.Allowed)
+		assert.Nil(t, res.Patch)
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		req := newApplicationSetAdmissionRequest(t, admissionv1.Update, nil, true)
+		res := mutator.Mutate(context.Background(), req)
+		assert.True(t, res.Allowed)
+		assert.Nil(t, res.Patch)
+	})
+}
@@ -0,0 +1,252 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	tlsutil "github.com/argoproj/argo-cd/v2/util/tls"
+)
+
+const (
+	// DefaultCertSecretName is the name of the secret holding the serving certificate of the admission webhook server
+	DefaultCertSecretName = "argocd-application-validator-tls"
+	// DefaultWebhookConfigurationName is the name of the ValidatingWebhookConfiguration whose CA bundle is kept in
+	// sync with the serving certificate
+	DefaultWebhookConfigurationName = "argocd-application-destination-validation"
+	// DefaultMutatingWebhookConfigurationName is the name of the MutatingWebhookConfiguration whose CA bundle is kept in
+	// sync with the serving certificate
+	DefaultMutatingWebhookConfigurationName = "argocd-application-mutation"
+	// ApplicationSetDeletionProtectionWebhookConfigurationName is the name of the ValidatingWebhookConfiguration of the
+	// deletion protection of ApplicationSets, whose CA bundle is kept in sync with the serving certificate
+	ApplicationSetDeletionProtectionWebhookConfigurationName = "argocd-applicationset-deletion-protection"
+
+	defaultCertValidity    = 365 * 24 * time.Hour
+	defaultCertRenewBefore = 30 * 24 * time.Hour
+)
+
+// CertRotator maintains a self-signed serving certificate for the admission webhook server. The certificate is stored
+// in a secret shared by all replicas, renewed before it expires, and injected as CA bundle into the webhooks of the
+// ValidatingWebhookConfigurations and of the MutatingWebhookConfiguration.
+type CertRotator struct {
+	clientset  kubernetes.Interface
+	namespace  string
+	secretName string
+	// webhookConfigNames are the names of the ValidatingWebhookConfigurations
+	webhookConfigNames []string
+	// mutatingWebhookConfigName is the name of the MutatingWebhookConfiguration
+	mutatingWebhookConfigName string
+	hosts                     []string
+	validity                  time.Duration
+	renewBefore               time.Duration
+	now                       func() time.Time
+
+	mu   sync.RWMutex
+	cert *tls.Certificate
+}
+
+// NewCertRotator creates a rotator of the serving certificate for the given hosts, which are the DNS names the
+// Kubernetes API server uses to reach the webhook server
+func NewCertRotator(clientset kubernetes.Interface, namespace, secretName string, webhookConfigNames []string, mutatingWebhookConfigName string, hosts []string) *CertRotator {
+	return &CertRotator{
+		clientset:                 clientset,
+		namespace:                 namespace,
+		secretName:                secretName,
+		webhookConfigNames:        webhookConfigNames,
+		mutatingWebhookConfigName: mutatingWebhookConfigName,
+		hosts:                     hosts,
+		validity:                  defaultCertValidity,
+		renewBefore:               defaultCertRenewBefore,
+		now:                       time.Now,
+	}
+}
+
+// GetCertificate returns the current serving certificate, it is meant to be used in a tls.Config
+func (r *CertRotator) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if r.cert == nil {
+		return nil, fmt.Errorf("webhook serving certificate is not available")
+	}
+	return r.cert, nil
+}
+
+// Run rotates the certificate periodically until the context is done
+func (r *CertRotator) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Rotate(ctx); err != nil {
+				log.Warnf("Failed to rotate webhook serving certificate: %v", err)
+			}
+		}
+	}
+}
+
+// Rotate loads the certificate from the secret, replaces it when it is missing, invalid or about to expire, and
+// updates the CA bundle of the webhook configurations
+func (r *CertRotator) Rotate(ctx context.Context) error {
+	certPEM, keyPEM, err := r.ensureSecret(ctx)
+	if err != nil {
+		return err
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return fmt.Errorf("error parsing webhook serving certificate: %w", err)
+	}
+	r.mu.Lock()
+	r.cert = &cert
+	r.mu.Unlock()
+	if err := r.injectCABundle(ctx, certPEM); err != nil {
+		return err
+	}
+	return r.injectMutatingCABundle(ctx, certPEM)
+}
+
+// ensureSecret returns the certificate and key of the secret, after generating new ones if needed
+func (r *CertRotator) ensureSecret(ctx context.Context) ([]byte, []byte, error) {
+	secret, err := r.clientset.CoreV1().Secrets(r.namespace).Get(ctx, r.secretName, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, nil, fmt.Errorf("error getting secret %s: %w", r.secretName, err)
+	}
+	if err == nil && !r.needsRenewal(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey]) {
+		return secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey], nil
+	}
+
+	cert, genErr := tlsutil.GenerateX509KeyPair(tlsutil.CertOptions{
+		Hosts:        r.hosts,
+		Organization: "Argo CD",
+		ValidFrom:    r.now(),
+		ValidFor:     r.validity,
+		IsCA:         true,
+	})
+	if genErr != nil {
+		return nil, nil, fmt.Errorf("error generating webhook serving certificate: %w", genErr)
+	}
+	certPEM, keyPEM := tlsutil.EncodeX509KeyPair(*cert)
+	data := map[string][]byte{corev1.TLSCertKey: certPEM, corev1.TLSPrivateKeyKey: keyPEM}
+
+	if apierrors.IsNotFound(err) {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   r.secretName,
+				Labels: map[string]string{common.LabelKeyAppName: r.secretName, "app.kubernetes.io/part-of": "argocd"},
+			},
+			Type: corev1.SecretTypeTLS,
+			Data: data,
+		}
+		_, err = r.clientset.CoreV1().Secrets(r.namespace).Create(ctx, secret, metav1.CreateOptions{})
+	} else {
+		secret.Data = data
+		_, err = r.clientset.CoreV1().Secrets(r.namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	}
+	if apierrors.IsAlreadyExists(err) || apierrors.IsConflict(err) {
+		// another replica rotated the certificate first
+		secret, err = r.clientset.CoreV1().Secrets(r.namespace).Get(ctx, r.secretName, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("error getting secret %s: %w", r.secretName, err)
+		}
+		return secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey], nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("error saving secret %s: %w", r.secretName, err)
+	}
+	log.Infof("Generated webhook serving certificate valid until %s", r.now().Add(r.validity).Format(time.RFC3339))
+	return certPEM, keyPEM, nil
+}
+
+// needsRenewal returns true if the key pair is invalid, does not cover the hosts or expires within the renewal period
+func (r *CertRotator) needsRenewal(certPEM, keyPEM []byte) bool {
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil || len(pair.Certificate) == 0 {
+		return true
+	}
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return true
+	}
+	for _, host := range r.hosts {
+		if cert.VerifyHostname(host) != nil {
+			return true
+		}
+	}
+	return r.now().Add(r.renewBefore).After(cert.NotAfter)
+}
+
+// injectCABundle sets the CA bundle of all webhooks of the validating webhook configurations to the certificate. A
+// missing configuration is not an error, since the webhook may not be registered yet.
+func (r *CertRotator) injectCABundle(ctx context.Context, caBundle []byte) error {
+	for _, name := range r.webhookConfigNames {
+		if err := r.injectValidatingCABundle(ctx, name, caBundle); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *CertRotator) injectValidatingCABundle(ctx context.Context, name string, caBundle []byte) error {
+	config, err := r.clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		log.Debugf("ValidatingWebhookConfiguration %s not found, CA bundle is not injected", name)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error getting ValidatingWebhookConfiguration %s: %w", name, err)
+	}
+	updated := false
+	for i := range config.Webhooks {
+		if !bytes.Equal(config.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			config.Webhooks[i].ClientConfig.CABundle = caBundle
+			updated = true
+		}
+	}
+	if !updated {
+		return nil
+	}
+	if _, err := r.clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().Update(ctx, config, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating CA bundle of ValidatingWebhookConfiguration %s: %w", name, err)
+	}
+	return nil
+}
+
+// injectMutatingCABundle sets the CA bundle of all webhooks of the mutating webhook configuration to the certificate. A
+// missing configuration is not an error, since the mutating webhook is optional.
+func (r *CertRotator) injectMutatingCABundle(ctx context.Context, caBundle []byte) error {
+	config, err := r.clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(ctx, r.mutatingWebhookConfigName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		log.Debugf("MutatingWebhookConfiguration %s not found, CA bundle is not injected", r.mutatingWebhookConfigName)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("error getting MutatingWebhookConfiguration %s: %w", r.mutatingWebhookConfigName, err)
+	}
+	updated := false
+	for i := range config.Webhooks {
+		if !bytes.Equal(config.Webhooks[i].ClientConfig.CABundle, caBundle) {
+			config.Webhooks[i].ClientConfig.CABundle = caBundle
+			updated = true
+		}
+	}
+	if !updated {
+		return nil
+	}
+	if _, err := r.clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Update(ctx, config, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error updating CA bundle of MutatingWebhookConfiguration %s: %w", r.mutatingWebhookConfigName, err)
+	}
+	return nil
+}
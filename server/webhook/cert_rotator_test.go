@@ -0,0 +1,127 @@
+package webhook
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var testWebhookHosts = []string{"argocd-server.argocd.svc"}
+
+func newTestWebhookConfiguration() *admissionregistrationv1.ValidatingWebhookConfiguration {
+	return &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultWebhookConfigurationName},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+			Name: "destinations.applications.argoproj.io",
+		}},
+	}
+}
+
+func getTestSecret(t *testing.T, clientset *fake.Clientset) *corev1.Secret {
+	t.Helper()
+	secret, err := clientset.CoreV1().Secrets(testNamespace).Get(context.Background(), DefaultCertSecretName, metav1.GetOptions{})
+	require.NoError(t, err)
+	return secret
+}
+
+func TestCertRotator_Rotate(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newTestWebhookConfiguration(), &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultMutatingWebhookConfigurationName},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{{
+			Name: "applications.mutation.argoproj.io",
+		}},
+	})
+	rotator := NewCertRotator(clientset, testNamespace, DefaultCertSecretName, []string{DefaultWebhookConfigurationName}, DefaultMutatingWebhookConfigurationName, testWebhookHosts)
+
+	_, err := rotator.GetCertificate(nil)
+	require.Error(t, err)
+
+	require.NoError(t, rotator.Rotate(context.Background()))
+	secret := getTestSecret(t, clientset)
+	assert.Equal(t, corev1.SecretTypeTLS, secret.Type)
+	assert.NotEmpty(t, secret.Data[corev1.TLSPrivateKeyKey])
+
+	config, err := clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.Background(), DefaultWebhookConfigurationName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, secret.Data[corev1.TLSCertKey], config.Webhooks[0].ClientConfig.CABundle)
+	mutatingConfig, err := clientset.AdmissionregistrationV1().MutatingWebhookConfigurations().Get(context.Background(), DefaultMutatingWebhookConfigurationName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, secret.Data[corev1.TLSCertKey], mutatingConfig.Webhooks[0].ClientConfig.CABundle)
+
+	cert, err := rotator.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.NotNil(t, cert)
+}
+
+func TestCertRotator_Rotate_MultipleWebhookConfigurations(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newTestWebhookConfiguration(), &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: ApplicationSetDeletionProtectionWebhookConfigurationName},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{{
+			Name: "applicationsets.deletion-protection.argoproj.io",
+		}},
+	})
+	rotator := NewCertRotator(clientset, testNamespace, DefaultCertSecretName, []string{DefaultWebhookConfigurationName, ApplicationSetDeletionProtectionWebhookConfigurationName}, DefaultMutatingWebhookConfigurationName, testWebhookHosts)
+
+	require.NoError(t, rotator.Rotate(context.Background()))
+	certPEM := getTestSecret(t, clientset).Data[corev1.TLSCertKey]
+	for _, name := range []string{DefaultWebhookConfigurationName, ApplicationSetDeletionProtectionWebhookConfigurationName} {
+		config, err := clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.Background(), name, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, certPEM, config.Webhooks[0].ClientConfig.CABundle)
+	}
+}
+
+func TestCertRotator_KeepsValidCertificate(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newTestWebhookConfiguration())
+	rotator := NewCertRotator(clientset, testNamespace, DefaultCertSecretName, []string{DefaultWebhookConfigurationName}, DefaultMutatingWebhookConfigurationName, testWebhookHosts)
+
+	require.NoError(t, rotator.Rotate(context.Background()))
+	certPEM := getTestSecret(t, clientset).Data[corev1.TLSCertKey]
+
+	require.NoError(t, rotator.Rotate(context.Background()))
+	assert.Equal(t, certPEM, getTestSecret(t, clientset).Data[corev1.TLSCertKey])
+}
+
+func TestCertRotator_RenewsExpiringCertificate(t *testing.T) {
+	clientset := fake.NewSimpleClientset(newTestWebhookConfiguration())
+	rotator := NewCertRotator(clientset, testNamespace, DefaultCertSecretName, []string{DefaultWebhookConfigurationName}, DefaultMutatingWebhookConfigurationName, testWebhookHosts)
+
+	require.NoError(t, rotator.Rotate(context.Background()))
+	certPEM := getTestSecret(t, clientset).Data[corev1.TLSCertKey]
+
+	rotator.now = func() time.Time {
+		return time.Now().Add(defaultCertValidity - defaultCertRenewBefore + time.Hour)
+	}
+	require.NoError(t, rotator.Rotate(context.Background()))
+	renewedPEM := getTestSecret(t, clientset).Data[corev1.TLSCertKey]
+	assert.NotEqual(t, certPEM, renewedPEM)
+
+	config, err := clientset.AdmissionregistrationV1().ValidatingWebhookConfigurations().Get(context.Background(), DefaultWebhookConfigurationName, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, renewedPEM, config.Webhooks[0].ClientConfig.CABundle)
+}
+
+func TestCertRotator_RenewsCertificateForOtherHosts(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	require.NoError(t, NewCertRotator(clientset, testNamespace, DefaultCertSecretName, []string{DefaultWebhookConfigurationName}, DefaultMutatingWebhookConfigurationName, testWebhookHosts).Rotate(context.Background()))
+	certPEM := getTestSecret(t, clientset).Data[corev1.TLSCertKey]
+
+	rotator := NewCertRotator(clientset, testNamespace, DefaultCertSecretName, []string{DefaultWebhookConfigurationName}, DefaultMutatingWebhookConfigurationName, []string{"argocd-server.other.svc"})
+	require.NoError(t, rotator.Rotate(context.Background()))
+	assert.NotEqual(t, certPEM, getTestSecret(t, clientset).Data[corev1.TLSCertKey])
+}
+
+func TestCertRotator_MissingWebhookConfiguration(t *testing.T) {
+	clientset := fake.NewSimpleClientset()
+	rotator := NewCertRotator(clientset, testNamespace, DefaultCertSecretName, []string{DefaultWebhookConfigurationName}, DefaultMutatingWebhookConfigurationName, testWebhookHosts)
+
+	require.NoError(t, rotator.Rotate(context.Background()))
+	getTestSecret(t, clientset)
+}
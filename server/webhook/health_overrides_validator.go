@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/util/lua"
+	"github.com/argoproj/argo-cd/v2/util/rbac"
+)
+
+// HealthOverridesValidator is a validating admission webhook which protects the argocd-health-overrides ConfigMap,
+// whose scripts override the health assessment of resources: only users with the admin role may change it, and its
+// keys and scripts must be valid.
+type HealthOverridesValidator struct {
+	enf       *rbac.Enforcer
+	namespace string
+}
+
+// NewHealthOverridesValidator creates a validating admission webhook protecting the argocd-health-overrides ConfigMap
+// of the given namespace
+func NewHealthOverridesValidator(enf *rbac.Enforcer, namespace string) *HealthOverridesValidator {
+	return &HealthOverridesValidator{
+		enf:       enf,
+		namespace: namespace,
+	}
+}
+
+func (v *HealthOverridesValidator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(w, r, v.Validate)
+}
+
+// Validate rejects changes of the argocd-health-overrides ConfigMap unless the Kubernetes user or one of its groups is
+// assigned the admin role in the RBAC policy. Created or updated ConfigMaps are rejected if a key is not a
+// {group}_{version}_{kind} key or a script does not define the function health_status(obj).
+func (v *HealthOverridesValidator) Validate(_ context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	allowed := &admissionv1.AdmissionResponse{Allowed: true}
+	if req.Kind.Group != "" || req.Kind.Kind != "ConfigMap" || req.Name != common.ArgoCDHealthOverridesConfigMapName || req.Namespace != v.namespace {
+		return allowed
+	}
+	if !v.enf.HasRole(adminRole, append([]string{req.UserInfo.Username}, req.UserInfo.Groups...)...) {
+		return deniedResponse(http.StatusForbidden, metav1.StatusReasonForbidden, fmt.Sprintf(
+			"changing the %s ConfigMap requires the %s role", common.ArgoCDHealthOverridesConfigMapName, adminRole))
+	}
+	if req.Operation != admissionv1.Create && req.Operation != admissionv1.Update {
+		return allowed
+	}
+
+	var cm corev1.ConfigMap
+	if err := json.Unmarshal(req.Object.Raw, &cm); err != nil {
+		return deniedResponse(http.StatusBadRequest, metav1.StatusReasonBadRequest, fmt.Sprintf("failed to decode ConfigMap: %v", err))
+	}
+	keys := make([]string, 0, len(cm.Data))
+	for key := range cm.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var errs []error
+	for _, key := range keys {
+		if _, err := lua.ParseHealthOverrideKey(key); err != nil {
+			errs = append(errs, err)
+		} else if err := lua.ValidateHealthOverrideScript(cm.Data[key]); err != nil {
+			errs = append(errs, fmt.Errorf("invalid health override %s: %w", key, err))
+		}
+	}
+	if len(errs) > 0 {
+		return deniedResponse(http.StatusUnprocessableEntity, metav1.StatusReasonInvalid, errors.Join(errs...).Error())
+	}
+	return allowed
+}
@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/util/assets"
+	"github.com/argoproj/argo-cd/v2/util/rbac"
+)
+
+const healthyDeploymentScript = `
+function health_status(obj)
+  return {status = "Healthy"}
+end
+`
+
+func newTestHealthOverridesValidator(t *testing.T) *HealthOverridesValidator {
+	t.Helper()
+	enf := rbac.NewEnforcer(fake.NewSimpleClientset(), testNamespace, common.ArgoCDRBACConfigMapName, nil)
+	require.NoError(t, enf.SetBuiltinPolicy(assets.BuiltinPolicyCSV))
+	require.NoError(t, enf.SetUserPolicy("g, platform-team, role:admin"))
+	return NewHealthOverridesValidator(enf, testNamespace)
+}
+
+func newHealthOverridesAdmissionRequest(t *testing.T, operation admissionv1.Operation, name string, data map[string]string, user authenticationv1.UserInfo) *admissionv1.AdmissionRequest {
+	t.Helper()
+	raw, err := json.Marshal(corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMap"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Data:       data,
+	})
+	require.NoError(t, err)
+	req := &admissionv1.AdmissionRequest{
+		UID:       types.UID("test-uid"),
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+		Name:      name,
+		Namespace: testNamespace,
+		Operation: operation,
+		UserInfo:  user,
+	}
+	if operation == admissionv1.Delete {
+		req.OldObject = runtime.RawExtension{Raw: raw}
+	} else {
+		req.Object = runtime.RawExtension{Raw: raw}
+	}
+	return req
+}
+
+func TestHealthOverridesValidator_Validate(t *testing.T) {
+	admin := authenticationv1.UserInfo{Username: "bob", Groups: []string{"platform-team"}}
+	developer := authenticationv1.UserInfo{Username: "alice", Groups: []string{"developers"}}
+	validData := map[string]string{"apps_v1_Deployment": healthyDeploymentScript}
+
+	t.Run("ChangedByAdmin", func(t *testing.T) {
+		validator := newTestHealthOverridesValidator(t)
+		for _, operation := range []admissionv1.Operation{admissionv1.Create, admissionv1.Update, admissionv1.Delete} {
+			res := validator.Validate(context.Background(), newHealthOverridesAdmissionRequest(t, operation, common.ArgoCDHealthOverridesConfigMapName, validData, admin))
+			assert.True(t, res.Allowed, operation)
+		}
+	})
+
+	t.Run("ChangedByOtherUser", func(t *testing.T) {
+		validator := newTestHealthOverridesValidator(t)
+		for _, operation := range []admissionv1.Operation{admissionv1.Create, admissionv1.Update, admissionv1.Delete} {
+			res := validator.Validate(context.Background(), newHealthOverridesAdmissionRequest(t, operation, common.ArgoCDHealthOverridesConfigMapName, validData, developer))
+			assert.False(t, res.Allowed, operation)
+			require.NotNil(t, res.Result)
+			assert.Equal(t, int32(http.StatusForbidden), res.Result.Code)
+			assert.Contains(t, res.Result.Message, "requires the role:admin role")
+		}
+	})
+
+	t.Run("InvalidScripts", func(t *testing.T) {
+		validator := newTestHealthOverridesValidator(t)
+		res := validator.Validate(context.Background(), newHealthOverridesAdmissionRequest(t, admissionv1.Update, common.ArgoCDHealthOverridesConfigMapName, map[string]string{
+			"apps_v1_Deployment":  healthyDeploymentScript,
+			"apps/v1/StatefulSet": healthyDeploymentScript,
+			"v1_Pod":              `return {status = "Healthy"}`,
+		}, admin))
+		assert.False(t, res.Allowed)
+		require.NotNil(t, res.Result)
+		assert.Equal(t, int32(http.StatusUnprocessableEntity), res.Result.Code)
+		assert.Contains(t, res.Result.Message, `invalid health override key "apps/v1/StatefulSet"`)
+		assert.Contains(t, res.Result.Message, "invalid health override v1_Pod: the health override script must define the function health_status(obj)")
+	})
+
+	t.Run("OtherConfigMap", func(t *testing.T) {
+		validator := newTestHealthOverridesValidator(t)
+		res := validator.Validate(context.Background(), newHealthOverridesAdmissionRequest(t, admissionv1.Update, common.ArgoCDConfigMapName, map[string]string{"url": "https://argocd.example.com"}, developer))
+		assert.True(t, res.Allowed)
+	})
+
+	t.Run("OtherNamespace", func(t *testing.T) {
+		validator := newTestHealthOverridesValidator(t)
+		req := newHealthOverridesAdmissionRequest(t, admissionv1.Update, common.ArgoCDHealthOverridesConfigMapName, validData, developer)
+		req.Namespace = "team-a"
+		assert.True(t, validator.Validate(context.Background(), req).Allowed)
+	})
+}
@@ -0,0 +1,374 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	openapierrors "k8s.io/kube-openapi/pkg/validation/errors"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+	"k8s.io/kube-openapi/pkg/validation/strfmt"
+	"k8s.io/kube-openapi/pkg/validation/validate"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	"github.com/argoproj/argo-cd/v2/util/db"
+	"github.com/argoproj/argo-cd/v2/util/io"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+// maxAdmissionReviewSize is the maximum size of admission review requests
+const maxAdmissionReviewSize = 3 * 1024 * 1024
+
+// ApplicationValidator is a validating admission webhook which rejects applications whose source parameters do not
+// conform to the values.schema.json of their Helm chart, or to a schema configured in argocd-cm.
+type ApplicationValidator struct {
+	db            db.ArgoDB
+	repoClientset apiclient.Clientset
+	settingsMgr   *settings.SettingsManager
+	timeout       time.Duration
+}
+
+// NewApplicationValidator creates a validating admission webhook for applications. The timeout bounds the time spent
+// retrieving chart schemas from the repo server.
+func NewApplicationValidator(db db.ArgoDB, repoClientset apiclient.Clientset, settingsMgr *settings.SettingsManager, timeout time.Duration) *ApplicationValidator {
+	return &ApplicationValidator{
+		db:            db,
+		repoClientset: repoClientset,
+		settingsMgr:   settingsMgr,
+		timeout:       timeout,
+	}
+}
+
+func (v *ApplicationValidator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(w, r, v.Validate)
+}
+
+// serveAdmissionReview decodes the admission review of the request, and responds with the result of the validation
+func serveAdmissionReview(w http.ResponseWriter, r *http.Request, validate func(context.Context, *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var review admissionv1.AdmissionReview
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxAdmissionReviewSize)).Decode(&review); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to decode admission review: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(w, "Admission review has no request", http.StatusBadRequest)
+		return
+	}
+	review.Response = validate(r.Context(), review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		log.Warnf("Failed to write admission review response: %v", err)
+	}
+}
+
+// Validate validates the parameters of all sources of the application of the admission request
+func (v *ApplicationValidator) Validate(ctx context.Context, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	allowed := &admissionv1.AdmissionResponse{Allowed: true}
+	if req.Operation == admissionv1.Delete || req.Kind.Group != application.Group || req.Kind.Kind != application.ApplicationKind {
+		return allowed
+	}
+	var app v1alpha1.Application
+	if err := json.Unmarshal(req.Object.Raw, &app); err != nil {
+		return &admissionv1.AdmissionResponse{Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusBadRequest,
+			Reason:  metav1.StatusReasonBadRequest,
+			Message: fmt.Sprintf("failed to decode application: %v", err),
+		}}
+	}
+	schemas, err := v.settingsMgr.GetParametersSchemas()
+	if err != nil {
+		log.Warnf("Failed to get parameters schemas: %v", err)
+		allowed.Warnings = append(allowed.Warnings, fmt.Sprintf("parameters were not validated: %v", err))
+		return allowed
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+	var causes []metav1.StatusCause
+	for i, source := range app.Spec.GetSources() {
+		fieldPath := "spec.source"
+		if app.Spec.HasMultipleSources() {
+			fieldPath = fmt.Sprintf("spec.sources[%d]", i)
+		}
+		sourceCauses, err := v.validateSource(ctx, &app, source, fieldPath, schemas)
+		if err != nil {
+			log.WithField("application", app.QualifiedName()).Warnf("Failed to validate parameters of %s: %v", fieldPath, err)
+			allowed.Warnings = append(allowed.Warnings, fmt.Sprintf("parameters of %s were not validated: %v", fieldPath, err))
+			continue
+		}
+		causes = append(causes, sourceCauses...)
+	}
+	if len(causes) == 0 {
+		return allowed
+	}
+	return &admissionv1.AdmissionResponse{
+		Warnings: allowed.Warnings,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusUnprocessableEntity,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: fmt.Sprintf("application %s has %d invalid parameter(s)", app.Name, len(causes)),
+			Details: &metav1.StatusDetails{
+				Name:   app.Name,
+				Group:  application.Group,
+				Kind:   application.ApplicationKind,
+				Causes: causes,
+			},
+		},
+	}
+}
+
+// validateSource validates the Helm values or the plugin parameters of the source against its schema. Sources
+// without a schema are not validated.
+func (v *ApplicationValidator) validateSource(ctx context.Context, app *v1alpha1.Application, source v1alpha1.ApplicationSource, fieldPath string, schemas []settings.ParametersSchema) ([]metav1.StatusCause, error) {
+	customSchema := findParametersSchema(schemas, source)
+	switch {
+	case source.Plugin != nil:
+		if customSchema == "" {
+			return nil, nil
+		}
+		return validateAgainstSchema(customSchema, pluginParameters(source.Plugin), fieldPath+".plugin.parameters")
+	case source.Helm != nil || source.Chart != "":
+		helm := source.Helm
+		if helm == nil {
+			helm = &v1alpha1.ApplicationSourceHelm{}
+		}
+		if len(helm.ValueFiles) > 0 {
+			// values files are only resolved when generating manifests, so the values would be incomplete
+			return nil, fmt.Errorf("sources with value files are not supported")
+		}
+		details, err := v.getHelmAppDetails(ctx, app, source)
+		if err != nil {
+			return nil, err
+		}
+		schema := customSchema
+		if schema == "" {
+			schema = details.ValuesSchema
+		}
+		if schema == "" {
+			return nil, nil
+		}
+		values, err := helmValues(details.Values, helm)
+		if err != nil {
+			return []metav1.StatusCause{{
+				Type:    metav1.CauseTypeFieldValueInvalid,
+				Field:   fieldPath + ".helm.values",
+				Message: err.Error(),
+			}}, nil
+		}
+		return validateAgainstSchema(schema, values, fieldPath+".helm.values")
+	}
+	return nil, nil
+}
+
+// getHelmAppDetails returns the default values and the values schema of the chart of the source
+func (v *ApplicationValidator) getHelmAppDetails(ctx context.Context, app *v1alpha1.Application, source v1alpha1.ApplicationSource) (*apiclient.HelmAppSpec, error) {
+	repo, err := v.db.GetRepository(ctx, source.RepoURL, app.Spec.Project)
+	if err != nil {
+		return nil, fmt.Errorf("error getting repository: %w", err)
+	}
+	helmRepos, err := v.db.ListHelmRepositories(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error listing helm repositories: %w", err)
+	}
+	helmOptions, err := v.settingsMgr.GetHelmSettings()
+	if err != nil {
+		return nil, fmt.Errorf("error getting helm settings: %w", err)
+	}
+	conn, repoClient, err := v.repoClientset.NewRepoServerClient()
+	if err != nil {
+		return nil, fmt.Errorf("error creating repo server client: %w", err)
+	}
+	defer io.Close(conn)
+	res, err := repoClient.GetAppDetails(ctx, &apiclient.RepoServerAppDetailsQuery{
+		Repo:        repo,
+		Source:      &source,
+		Repos:       helmRepos,
+		AppName:     app.InstanceName(v.settingsMgr.GetNamespace()),
+		HelmOptions: helmOptions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error getting chart details: %w", err)
+	}
+	if res.Helm == nil {
+		return &apiclient.HelmAppSpec{}, nil
+	}
+	return res.Helm, nil
+}
+
+// findParametersSchema returns the first schema configured in argocd-cm which matches the source
+func findParametersSchema(schemas []settings.ParametersSchema, source v1alpha1.ApplicationSource) string {
+	for _, schema := range schemas {
+		if schema.RepoURL != source.RepoURL {
+			continue
+		}
+		if source.IsHelm() && schema.Chart == source.Chart || !source.IsHelm() && schema.Path == source.Path {
+			return schema.Schema
+		}
+	}
+	return ""
+}
+
+// validateAgainstSchema validates the value against the JSON schema, and returns a cause for each violation
+func validateAgainstSchema(schemaJSON string, value interface{}, fieldPath string) ([]metav1.StatusCause, error) {
+	var schema spec.Schema
+	if err := json.Unmarshal([]byte(schemaJSON), &schema); err != nil {
+		return nil, fmt.Errorf("error parsing schema: %w", err)
+	}
+	// round trip the value through JSON so numbers are represented the way the validator expects
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("error marshaling parameters: %w", err)
+	}
+	var obj interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("error unmarshaling parameters: %w", err)
+	}
+	result := validate.NewSchemaValidator(&schema, nil, "", strfmt.Default).Validate(obj)
+	var causes []metav1.StatusCause
+	for _, err := range result.Errors {
+		cause := metav1.StatusCause{Type: metav1.CauseTypeFieldValueInvalid, Field: fieldPath, Message: err.Error()}
+		if validationErr, ok := err.(*openapierrors.Validation); ok {
+			// properties required at the top level are named with a leading dot
+			if name := strings.TrimPrefix(validationErr.Name, "."); name != "" {
+				cause.Field = fieldPath + "." + name
+			}
+			if validationErr.Code() == openapierrors.RequiredFailCode {
+				cause.Type = metav1.CauseTypeFieldValueRequired
+			}
+		}
+		causes = append(causes, cause)
+	}
+	sort.Slice(causes, func(i, j int) bool {
+		return causes[i].Field < causes[j].Field
+	})
+	return causes, nil
+}
+
+// helmValues returns the values the chart would be rendered with: the default values of the chart, overridden by the
+// values and then by the parameters of the source
+func helmValues(defaultValues string, helm *v1alpha1.ApplicationSourceHelm) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(defaultValues), &values); err != nil {
+		return nil, fmt.Errorf("error parsing chart values: %w", err)
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	if !helm.ValuesIsEmpty() {
+		overrides := map[string]interface{}{}
+		if err := yaml.Unmarshal(helm.ValuesYAML(), &overrides); err != nil {
+			return nil, fmt.Errorf("error parsing values: %w", err)
+		}
+		mergeValues(values, overrides)
+	}
+	for _, p := range helm.Parameters {
+		if strings.ContainsAny(p.Name, "[]") {
+			// list indexes are not supported, so the parameter is not validated
+			continue
+		}
+		setValue(values, p.Name, parameterValue(p))
+	}
+	return values, nil
+}
+
+// mergeValues recursively merges the overrides into the values like Helm merges values files
+func mergeValues(values, overrides map[string]interface{}) {
+	for k, v := range overrides {
+		if override, ok := v.(map[string]interface{}); ok {
+			if current, ok := values[k].(map[string]interface{}); ok {
+				mergeValues(current, override)
+				continue
+			}
+		}
+		values[k] = v
+	}
+}
+
+// parameterValue converts the value of a parameter the way `helm --set` does: booleans, integers and null are typed,
+// everything else is a string
+func parameterValue(p v1alpha1.HelmParameter) interface{} {
+	if p.ForceString {
+		return p.Value
+	}
+	switch strings.ToLower(p.Value) {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if p.Value == "0" {
+		return int64(0)
+	}
+	if !strings.HasPrefix(p.Value, "0") {
+		if i, err := strconv.ParseInt(p.Value, 10, 64); err == nil {
+			return i
+		}
+	}
+	return p.Value
+}
+
+// setValue sets the value at the dot separated path of a parameter name. Dots may be escaped with a backslash.
+func setValue(values map[string]interface{}, name string, value interface{}) {
+	var keys []string
+	var key strings.Builder
+	for i := 0; i < len(name); i++ {
+		switch {
+		case name[i] == '\\' && i+1 < len(name) && name[i+1] == '.':
+			key.WriteByte('.')
+			i++
+		case name[i] == '.':
+			keys = append(keys, key.String())
+			key.Reset()
+		default:
+			key.WriteByte(name[i])
+		}
+	}
+	keys = append(keys, key.String())
+	current := values
+	for _, k := range keys[:len(keys)-1] {
+		next, ok := current[k].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			current[k] = next
+		}
+		current = next
+	}
+	current[keys[len(keys)-1]] = value
+}
+
+// pluginParameters returns the parameters of a plugin source as an object keyed by parameter name
+func pluginParameters(plugin *v1alpha1.ApplicationSourcePlugin) map[string]interface{} {
+	params := map[string]interface{}{}
+	for _, p := range plugin.Parameters {
+		switch {
+		case p.String_ != nil:
+			params[p.Name] = *p.String_
+		case p.OptionalArray != nil:
+			params[p.Name] = p.OptionalArray.Array
+		case p.OptionalMap != nil:
+			params[p.Name] = p.OptionalMap.Map
+		}
+	}
+	return params
+}
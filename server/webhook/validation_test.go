@@ -0,0 +1,210 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	repomocks "github.com/argoproj/argo-cd/v2/reposerver/apiclient/mocks"
+	dbmocks "github.com/argoproj/argo-cd/v2/util/db/mocks"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+const testNamespace = "argocd"
+
+const testValuesSchema = `{
+  "type": "object",
+  "required": ["image", "replicas"],
+  "properties": {
+    "image": {
+      "type": "object",
+      "required": ["repository"],
+      "properties": {
+        "repository": {"type": "string"},
+        "tag": {"type": "string"}
+      }
+    },
+    "replicas": {"type": "integer", "minimum": 1}
+  }
+}`
+
+func newTestValidator(t *testing.T, cmData map[string]string, chartValues string) *ApplicationValidator {
+	t.Helper()
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ArgoCDConfigMapName,
+			Namespace: testNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/part-of": "argocd"},
+		},
+		Data: cmData,
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ArgoCDSecretName,
+			Namespace: testNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/part-of": "argocd"},
+		},
+		Data: map[string][]byte{"server.secretkey": []byte("test")},
+	}
+	settingsMgr := settings.NewSettingsManager(context.Background(), fake.NewSimpleClientset(cm, secret), testNamespace)
+
+	db := &dbmocks.ArgoDB{}
+	db.On("GetRepository", mock.Anything, "https://charts.example.com", "default").Return(&v1alpha1.Repository{Repo: "https://charts.example.com"}, nil)
+	db.On("ListHelmRepositories", mock.Anything).Return(nil, nil)
+	repoClient := &repomocks.RepoServerServiceClient{}
+	repoClient.On("GetAppDetails", mock.Anything, mock.Anything).Return(&apiclient.RepoAppDetailsResponse{
+		Type: "Helm",
+		Helm: &apiclient.HelmAppSpec{Values: chartValues, ValuesSchema: testValuesSchema},
+	}, nil)
+	return NewApplicationValidator(db, &repomocks.Clientset{RepoServerServiceClient: repoClient}, settingsMgr, 10*time.Second)
+}
+
+func newAdmissionRequest(t *testing.T, helm *v1alpha1.ApplicationSourceHelm) *admissionv1.AdmissionRequest {
+	t.Helper()
+	app := v1alpha1.Application{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "argoproj.io/v1alpha1", Kind: "Application"},
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: testNamespace},
+		Spec: v1alpha1.ApplicationSpec{
+			Project: "default",
+			Source: &v1alpha1.ApplicationSource{
+				RepoURL:        "https://charts.example.com",
+				Chart:          "guestbook",
+				TargetRevision: "1.0.0",
+				Helm:           helm,
+			},
+		},
+	}
+	raw, err := json.Marshal(app)
+	require.NoError(t, err)
+	return &admissionv1.AdmissionRequest{
+		UID:       types.UID("test-uid"),
+		Kind:      metav1.GroupVersionKind{Group: "argoproj.io", Version: "v1alpha1", Kind: "Application"},
+		Operation: admissionv1.Create,
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+}
+
+func TestValidate_WellFormedValues(t *testing.T) {
+	validator := newTestValidator(t, nil, "image:\n  tag: latest\n")
+	helm := &v1alpha1.ApplicationSourceHelm{
+		Parameters: []v1alpha1.HelmParameter{{Name: "replicas", Value: "2"}},
+	}
+	require.NoError(t, helm.SetValuesString("image:\n  repository: nginx\n"))
+
+	res := validator.Validate(context.Background(), newAdmissionRequest(t, helm))
+	assert.True(t, res.Allowed)
+	assert.Empty(t, res.Warnings)
+}
+
+func TestValidate_MalformedValues(t *testing.T) {
+	validator := newTestValidator(t, nil, "image:\n  tag: latest\n")
+	helm := &v1alpha1.ApplicationSourceHelm{
+		Parameters: []v1alpha1.HelmParameter{{Name: "replicas", Value: "two"}},
+	}
+
+	res := validator.Validate(context.Background(), newAdmissionRequest(t, helm))
+	assert.False(t, res.Allowed)
+	require.NotNil(t, res.Result)
+	assert.Equal(t, int32(http.StatusUnprocessableEntity), res.Result.Code)
+	assert.Equal(t, metav1.StatusReasonInvalid, res.Result.Reason)
+	require.Len(t, res.Result.Details.Causes, 2)
+	assert.Equal(t, "spec.source.helm.values.image.repository", res.Result.Details.Causes[0].Field)
+	assert.Equal(t, metav1.CauseTypeFieldValueRequired, res.Result.Details.Causes[0].Type)
+	assert.Equal(t, "spec.source.helm.values.replicas", res.Result.Details.Causes[1].Field)
+	assert.Equal(t, metav1.CauseTypeFieldValueInvalid, res.Result.Details.Causes[1].Type)
+}
+
+func TestValidate_ForceStringParameter(t *testing.T) {
+	validator := newTestValidator(t, nil, "image:\n  repository: nginx\n")
+	helm := &v1alpha1.ApplicationSourceHelm{
+		Parameters: []v1alpha1.HelmParameter{{Name: "replicas", Value: "2", ForceString: true}},
+	}
+
+	res := validator.Validate(context.Background(), newAdmissionRequest(t, helm))
+	assert.False(t, res.Allowed)
+	require.Len(t, res.Result.Details.Causes, 1)
+	assert.Equal(t, "spec.source.helm.values.replicas", res.Result.Details.Causes[0].Field)
+}
+
+func TestValidate_CustomSchema(t *testing.T) {
+	validator := newTestValidator(t, map[string]string{
+		"application.parametersSchemas": `
+- repoURL: https://charts.example.com
+  chart: guestbook
+  schema: |
+    {"type": "object", "required": ["ingress"]}
+`,
+	}, "")
+
+	res := validator.Validate(context.Background(), newAdmissionRequest(t, nil))
+	assert.False(t, res.Allowed)
+	require.Len(t, res.Result.Details.Causes, 1)
+	assert.Equal(t, "spec.source.helm.values.ingress", res.Result.Details.Causes[0].Field)
+}
+
+func TestValidate_ValueFilesAreNotValidated(t *testing.T) {
+	validator := newTestValidator(t, nil, "")
+	helm := &v1alpha1.ApplicationSourceHelm{ValueFiles: []string{"values-production.yaml"}}
+
+	res := validator.Validate(context.Background(), newAdmissionRequest(t, helm))
+	assert.True(t, res.Allowed)
+	assert.Len(t, res.Warnings, 1)
+}
+
+func TestServeHTTP(t *testing.T) {
+	validator := newTestValidator(t, nil, "")
+	review := admissionv1.AdmissionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+		Request:  newAdmissionRequest(t, nil),
+	}
+	body, err := json.Marshal(review)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/validate/applications", bytes.NewReader(body))
+	rr := httptest.NewRecorder()
+	validator.ServeHTTP(rr, req)
+	require.Equal(t, http.StatusOK, rr.Code)
+
+	var res admissionv1.AdmissionReview
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &res))
+	require.NotNil(t, res.Response)
+	assert.Equal(t, types.UID("test-uid"), res.Response.UID)
+	assert.False(t, res.Response.Allowed)
+	assert.Len(t, res.Response.Result.Details.Causes, 2)
+}
+
+func TestParameterValue(t *testing.T) {
+	assert.Equal(t, true, parameterValue(v1alpha1.HelmParameter{Value: "true"}))
+	assert.Equal(t, int64(10), parameterValue(v1alpha1.HelmParameter{Value: "10"}))
+	assert.Equal(t, "010", parameterValue(v1alpha1.HelmParameter{Value: "010"}))
+	assert.Equal(t, "1.5", parameterValue(v1alpha1.HelmParameter{Value: "1.5"}))
+	assert.Nil(t, parameterValue(v1alpha1.HelmParameter{Value: "null"}))
+	assert.Equal(t, "10", parameterValue(v1alpha1.HelmParameter{Value: "10", ForceString: true}))
+}
+
+func TestSetValue(t *testing.T) {
+	values := map[string]interface{}{"image": "nginx"}
+	setValue(values, "image.tag", "latest")
+	setValue(values, `annotations.example\.com/owner`, "me")
+	assert.Equal(t, map[string]interface{}{
+		"image":       map[string]interface{}{"tag": "latest"},
+		"annotations": map[string]interface{}{"example.com/owner": "me"},
+	}, values)
+}
@@ -0,0 +1,89 @@
+package e2e
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	. "github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/argoproj/argo-cd/v2/test/e2e/fixture/app"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+)
+
+// degradeDeployment makes the deployment exceed its progress deadline because of an invalid image
+const degradeDeployment = `[
+    {
+        "op": "replace",
+        "path": "/spec/progressDeadlineSeconds",
+        "value": 1
+    },
+    {
+        "op": "replace",
+        "path": "/spec/template/spec/containers/0/image",
+        "value": "alpine:ops!"
+    }
+]`
+
+func TestAutoRollbackOnDegradedSync(t *testing.T) {
+	var goodRevision string
+	Given(t).
+		Path("one-deployment").
+		When().
+		CreateFromFile(func(app *Application) {
+			app.Spec.SyncPolicy = &SyncPolicy{AutoRollback: &AutoRollback{HealthWindow: "5m"}}
+		}).
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		Expect(HealthIs(health.HealthStatusHealthy)).
+		And(func(app *Application) {
+			goodRevision = app.Status.Sync.Revision
+		}).
+		When().
+		PatchFile("deployment.yaml", degradeDeployment).
+		Sync().
+		Then().
+		// the application becomes degraded and is rolled back to the first revision
+		Expect(RollbackHistoryLenIs(1)).
+		Expect(Event(argo.EventReasonOperationStarted, "Initiated automated rollback")).
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		Expect(HealthIs(health.HealthStatusHealthy)).
+		Expect(SyncStatusIs(SyncStatusCodeOutOfSync)).
+		And(func(app *Application) {
+			entry := app.Status.RollbackHistory[0]
+			assert.Equal(t, goodRevision, entry.ToRevision)
+			assert.NotEqual(t, goodRevision, entry.FromRevision)
+			require.NotNil(t, app.Status.OperationState)
+			assert.Equal(t, goodRevision, app.Status.OperationState.SyncResult.Revision)
+		}).
+		When().
+		// the degraded revision is not rolled back again once the maximum number of attempts is reached
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		Expect(HealthIs(health.HealthStatusDegraded)).
+		Expect(RollbackHistoryLenIs(1))
+}
+
+func TestAutoRollbackAfterHealthWindow(t *testing.T) {
+	Given(t).
+		Path("one-deployment").
+		When().
+		CreateFromFile(func(app *Application) {
+			app.Spec.SyncPolicy = &SyncPolicy{AutoRollback: &AutoRollback{HealthWindow: "1ms"}}
+		}).
+		Sync().
+		Then().
+		Expect(HealthIs(health.HealthStatusHealthy)).
+		When().
+		PatchFile("deployment.yaml", degradeDeployment).
+		Sync().
+		Then().
+		// the application becomes degraded after the health window and is not rolled back
+		Expect(HealthIs(health.HealthStatusDegraded)).
+		Expect(SyncStatusIs(SyncStatusCodeSynced)).
+		Expect(RollbackHistoryLenIs(0))
+}
@@ -0,0 +1,82 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	. "github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/argoproj/argo-cd/v2/test/e2e/fixture"
+	. "github.com/argoproj/argo-cd/v2/test/e2e/fixture/app"
+)
+
+// This test verifies that Argo CD deploys an application to its destination and to each of its additional
+// destinations, using two Argo CD cluster secrets which can only deploy to their own namespace as destination clusters.
+func TestMultipleDestinations(t *testing.T) {
+	EnsureCleanState(t)
+
+	users := []string{E2ETestPrefix + "user1", E2ETestPrefix + "user2"}
+	for _, username := range users {
+		createNamespaceScopedUser(t, username, false)
+	}
+
+	additionalDestination := E2ETestPrefix + "cluster-" + users[1]
+
+	GivenWithSameState(t).
+		Path("deployment").
+		DestName(E2ETestPrefix+"cluster-"+users[0]).
+		When().
+		CreateWithNoNameSpace("--dest-namespace", users[0]).
+		PatchApp(fmt.Sprintf(`[{"op": "add", "path": "/spec/destinations", "value": [{"name": %q, "namespace": %q}]}]`, additionalDestination, users[1])).
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		Expect(SyncStatusIs(SyncStatusCodeSynced)).
+		Expect(HealthIs(health.HealthStatusHealthy)).
+		And(func(app *Application) {
+			require.Len(t, app.Status.DestinationStatuses, 2)
+			for i, username := range users {
+				status := app.Status.DestinationStatuses[i]
+				assert.Equal(t, E2ETestPrefix+"cluster-"+username, status.Destination.Name)
+				assert.Equal(t, username, status.Destination.Namespace)
+				assert.Equal(t, SyncStatusCodeSynced, status.Status)
+				assert.Equal(t, health.HealthStatusHealthy, status.Health.Status)
+
+				_, err := KubeClientset.AppsV1().Deployments(username).Get(context.Background(), "nginx-deployment", metav1.GetOptions{})
+				require.NoError(t, err)
+			}
+			require.Len(t, app.Status.OperationState.SyncResult.Destinations, 2)
+		}).
+		When().
+		And(func() {
+			require.NoError(t, KubeClientset.AppsV1().Deployments(users[1]).Delete(context.Background(), "nginx-deployment", metav1.DeleteOptions{}))
+		}).
+		Refresh(RefreshTypeNormal).
+		Then().
+		Expect(SyncStatusIs(SyncStatusCodeOutOfSync)).
+		And(func(app *Application) {
+			require.Len(t, app.Status.DestinationStatuses, 2)
+			assert.Equal(t, SyncStatusCodeSynced, app.Status.DestinationStatuses[0].Status)
+			assert.Equal(t, SyncStatusCodeOutOfSync, app.Status.DestinationStatuses[1].Status)
+		}).
+		When().
+		Sync("--destination", "name="+additionalDestination).
+		Then().
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		Expect(SyncStatusIs(SyncStatusCodeSynced)).
+		And(func(app *Application) {
+			destinations := app.Status.OperationState.SyncResult.Destinations
+			require.Len(t, destinations, 1)
+			assert.Equal(t, additionalDestination, destinations[0].Destination.Name)
+			assert.Equal(t, OperationSucceeded, destinations[0].Phase)
+
+			_, err := KubeClientset.AppsV1().Deployments(users[1]).Get(context.Background(), "nginx-deployment", metav1.GetOptions{})
+			require.NoError(t, err)
+		})
+}
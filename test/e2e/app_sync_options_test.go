@@ -1,16 +1,23 @@
 package e2e
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/argoproj/gitops-engine/pkg/health"
 	. "github.com/argoproj/gitops-engine/pkg/sync/common"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	. "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	. "github.com/argoproj/argo-cd/v2/test/e2e/fixture"
 	. "github.com/argoproj/argo-cd/v2/test/e2e/fixture/app"
+	"github.com/argoproj/argo-cd/v2/util/argo"
+	. "github.com/argoproj/argo-cd/v2/util/errors"
 )
 
 // Given application is set with --sync-option CreateNamespace=true and --sync-option ServerSideApply=true
@@ -59,3 +66,168 @@ func TestNamespaceCreationWithSSA(t *testing.T) {
 		Expect(ResourceHealthWithNamespaceIs("Service", "guestbook-ui", namespace, health.HealthStatusHealthy)).
 		Expect(ResourceSyncStatusWithNamespaceIs("Service", "guestbook-ui", namespace, SyncStatusCodeSynced))
 }
+
+func getGuestbookDeployment(t *testing.T) *appsv1.Deployment {
+	t.Helper()
+	deployment, err := KubeClientset.AppsV1().Deployments(DeploymentNamespace()).Get(context.Background(), "guestbook-ui", metav1.GetOptions{})
+	require.NoError(t, err)
+	return deployment
+}
+
+// Given application is synced with client-side apply
+//
+//	When application is synced again with --sync-option ServerSideApply=true and --sync-option CleanupManagedFields=true
+//
+//	Then, the resources have the same state as with client-side apply
+//		  the fields are owned by the server-side apply manager of Argo CD
+func TestSyncWithServerSideApplyMatchesClientSideApply(t *testing.T) {
+	var clientSideSpec appsv1.DeploymentSpec
+	Given(t).
+		Path("guestbook").
+		When().
+		CreateApp().
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		Expect(SyncStatusIs(SyncStatusCodeSynced)).
+		And(func(app *Application) {
+			clientSideSpec = getGuestbookDeployment(t).Spec
+		}).
+		When().
+		PatchApp(`[{
+			"op": "add",
+			"path": "/spec/syncPolicy",
+			"value": { "syncOptions": ["ServerSideApply=true", "CleanupManagedFields=true"] }
+			}]`).
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		Expect(SyncStatusIs(SyncStatusCodeSynced)).
+		Expect(HealthIs(health.HealthStatusHealthy)).
+		And(func(app *Application) {
+			deployment := getGuestbookDeployment(t)
+			assert.Equal(t, clientSideSpec, deployment.Spec)
+			var applyManagers, updateManagers []string
+			for _, entry := range deployment.ManagedFields {
+				if entry.Operation == metav1.ManagedFieldsOperationApply {
+					applyManagers = append(applyManagers, entry.Manager)
+				} else {
+					updateManagers = append(updateManagers, entry.Manager)
+				}
+			}
+			assert.Contains(t, applyManagers, "argocd-controller")
+			assert.NotContains(t, updateManagers, "argocd-controller")
+		})
+}
+
+// Given application is set with --sync-option ServerSideApply=true
+//
+//	When a field of a resource is changed by another field manager
+//
+//	Then, the sync fails with the "fail" conflict resolution
+//		  the sync forces the conflict and emits a warning event with the "force" conflict resolution
+func TestSyncWithServerSideApplyConflictResolution(t *testing.T) {
+	Given(t).
+		Path("guestbook").
+		When().
+		CreateFromFile(func(app *Application) {
+			app.Spec.SyncPolicy = &SyncPolicy{
+				SyncOptions:                       SyncOptions{"ServerSideApply=true"},
+				ServerSideApplyConflictResolution: ServerSideApplyConflictResolutionFail,
+			}
+		}).
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		When().
+		And(func() {
+			FailOnErr(Run("", "kubectl", "-n", DeploymentNamespace(), "scale", "deployment", "guestbook-ui", "--replicas=2"))
+		}).
+		IgnoreErrors().
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationFailed)).
+		Expect(OperationMessageContains("server-side apply conflicts")).
+		Expect(OperationMessageContains(".spec.replicas")).
+		And(func(app *Application) {
+			assert.Equal(t, int32(2), *getGuestbookDeployment(t).Spec.Replicas)
+		}).
+		When().
+		DoNotIgnoreErrors().
+		PatchApp(`[{
+			"op": "replace",
+			"path": "/spec/syncPolicy/serverSideApplyConflictResolution",
+			"value": "force"
+			}]`).
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		Expect(SyncStatusIs(SyncStatusCodeSynced)).
+		Expect(Event(argo.EventReasonServerSideApplyConflictForced, ".spec.replicas")).
+		And(func(app *Application) {
+			// the replicas of the guestbook manifest
+			assert.Equal(t, int32(0), *getGuestbookDeployment(t).Spec.Replicas)
+		})
+}
+
+const guestbookHPA = `apiVersion: autoscaling/v2
+kind: HorizontalPodAutoscaler
+metadata:
+  name: guestbook-ui
+spec:
+  scaleTargetRef:
+    apiVersion: apps/v1
+    kind: Deployment
+    name: guestbook-ui
+  minReplicas: 2
+  maxReplicas: 3
+`
+
+// Given application is set with --sync-option ServerSideApply=true
+//
+//	When the replicas of a deployment are managed by a HorizontalPodAutoscaler
+//
+//	Then, the sync fails with the "strict" conflict resolution
+//		  the sync leaves the replicas to the HorizontalPodAutoscaler with the "merge" conflict resolution
+func TestSyncWithMergeConflictResolution(t *testing.T) {
+	Given(t).
+		Path("guestbook").
+		When().
+		CreateFromFile(func(app *Application) {
+			app.Spec.SyncPolicy = &SyncPolicy{
+				SyncOptions:        SyncOptions{"ServerSideApply=true"},
+				ConflictResolution: ConflictResolutionStrict,
+			}
+		}).
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		When().
+		And(func() {
+			// a deployment scaled to zero is not autoscaled
+			FailOnErr(Run("", "kubectl", "-n", DeploymentNamespace(), "scale", "deployment", "guestbook-ui", "--replicas=1"))
+			FailOnErr(RunWithStdin(guestbookHPA, "", "kubectl", "-n", DeploymentNamespace(), "apply", "-f", "-"))
+			assert.Eventually(t, func() bool {
+				return *getGuestbookDeployment(t).Spec.Replicas == 2
+			}, time.Minute, time.Second)
+		}).
+		IgnoreErrors().
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationFailed)).
+		Expect(OperationMessageContains(".spec.replicas")).
+		When().
+		DoNotIgnoreErrors().
+		PatchApp(`[{
+			"op": "replace",
+			"path": "/spec/syncPolicy/conflictResolution",
+			"value": "merge"
+			}]`).
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		And(func(app *Application) {
+			// the replicas of the HorizontalPodAutoscaler
+			assert.Equal(t, int32(2), *getGuestbookDeployment(t).Spec.Replicas)
+		})
+}
@@ -50,6 +50,21 @@ func OperationMessageContains(text string) Expectation {
 	}
 }
 
+// OperationProgressIsReported expects the running operation to report that it applied some of the given total number
+// of resources, and when it is expected to complete
+func OperationProgressIsReported(resourcesTotal int64) Expectation {
+	return func(c *Consequences) (state, string) {
+		operationState := c.app().Status.OperationState
+		if operationState == nil || operationState.Progress == nil {
+			return pending, "operation progress should be reported"
+		}
+		progress := operationState.Progress
+		return simple(progress.ResourcesApplied > 0 && progress.ResourcesTotal == resourcesTotal && progress.EstimatedCompletionTime != nil,
+			fmt.Sprintf("operation progress should report applied resources out of %d and an estimated completion time, is %d/%d applied, completion at %v",
+				resourcesTotal, progress.ResourcesApplied, progress.ResourcesTotal, progress.EstimatedCompletionTime))
+	}
+}
+
 func simple(success bool, message string) (state, string) {
 	if success {
 		return succeeded, message
@@ -127,6 +142,13 @@ func HealthIs(expected health.HealthStatusCode) Expectation {
 	}
 }
 
+func RollbackHistoryLenIs(expected int) Expectation {
+	return func(c *Consequences) (state, string) {
+		actual := len(c.app().Status.RollbackHistory)
+		return simple(actual == expected, fmt.Sprintf("rollback history length should be %d, is %d", expected, actual))
+	}
+}
+
 func ResourceSyncStatusIs(kind, resource string, expected SyncStatusCode) Expectation {
 	return func(c *Consequences) (state, string) {
 		actual := c.resource(kind, resource, "").Status
@@ -348,6 +348,38 @@ func TestHelmSetStringEnv(t *testing.T) {
 }
 
 // make sure kube-version gets passed down to resources
+func TestHelmValuesFrom(t *testing.T) {
+	Given(t).
+		Path("helm-values").
+		When().
+		And(func() {
+			FailOnErr(KubeClientset.CoreV1().ConfigMaps(DeploymentNamespace()).Create(context.Background(), &v1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "helm-values"},
+				Data:       map[string]string{"values.yaml": "foo: from-configmap"},
+			}, metav1.CreateOptions{}))
+		}).
+		CreateApp().
+		PatchApp(`[{"op": "add", "path": "/spec/source/helm", "value": {"valuesFrom": {"configMapKeyRef": {"name": "helm-values", "key": "values.yaml"}}}}]`).
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		Expect(SyncStatusIs(SyncStatusCodeSynced)).
+		And(func(app *Application) {
+			assert.Equal(t, "from-configmap", FailOnErr(Run(".", "kubectl", "-n", DeploymentNamespace(), "get", "cm", "my-map", "-o", "jsonpath={.data.foo}")).(string))
+		})
+}
+
+func TestHelmValuesFromMissingConfigMap(t *testing.T) {
+	Given(t).
+		Path("helm-values").
+		When().
+		CreateApp().
+		PatchApp(`[{"op": "add", "path": "/spec/source/helm", "value": {"valuesFrom": {"configMapKeyRef": {"name": "helm-values", "key": "values.yaml"}}}}]`).
+		Refresh(RefreshTypeNormal).
+		Then().
+		Expect(Condition(ApplicationConditionComparisonError, "error getting Helm values ConfigMap"))
+}
+
 func TestKubeVersion(t *testing.T) {
 	SkipOnEnv(t, "HELM")
 	Given(t).
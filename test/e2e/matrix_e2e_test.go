@@ -336,7 +336,7 @@ func TestMatrixTerminalMatrixGeneratorSelector(t *testing.T) {
 							Generators: []v1alpha1.ApplicationSetNestedGenerator{
 								{
 									Matrix: toAPIExtensionsJSON(t, &v1alpha1.NestedMatrixGenerator{
-										Generators: []v1alpha1.ApplicationSetTerminalGenerator{
+										Generators: []v1alpha1.ApplicationSetNestedGenerator{
 											{
 												List: &v1alpha1.ListGenerator{
 													Elements: []apiextensionsv1.JSON{
@@ -381,7 +381,7 @@ func TestMatrixTerminalMatrixGeneratorSelector(t *testing.T) {
 		When().
 		Update(func(appset *v1alpha1.ApplicationSet) {
 			appset.Spec.Generators[0].Matrix.Generators[0].Matrix = toAPIExtensionsJSON(t, &v1alpha1.NestedMatrixGenerator{
-				Generators: []v1alpha1.ApplicationSetTerminalGenerator{
+				Generators: []v1alpha1.ApplicationSetNestedGenerator{
 					{
 						List: &v1alpha1.ListGenerator{
 							Elements: []apiextensionsv1.JSON{
@@ -209,7 +209,7 @@ func TestClusterMergeGenerator(t *testing.T) {
 							Generators: []v1alpha1.ApplicationSetNestedGenerator{
 								{
 									Matrix: toAPIExtensionsJSON(t, &v1alpha1.NestedMatrixGenerator{
-										Generators: []v1alpha1.ApplicationSetTerminalGenerator{
+										Generators: []v1alpha1.ApplicationSetNestedGenerator{
 											{
 												Clusters: &v1alpha1.ClusterGenerator{
 													Selector: metav1.LabelSelector{
@@ -0,0 +1,147 @@
+package e2e
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	. "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/test/e2e/fixture"
+	. "github.com/argoproj/argo-cd/v2/test/e2e/fixture/app"
+	"github.com/argoproj/argo-cd/v2/test/fixture/test"
+)
+
+// injectedAnnotation is the annotation injected into the config maps of the deployment namespace by the mutating
+// webhook of the tests
+const injectedAnnotation = "e2e.argoproj.io/injected"
+
+// mutatingWebhookHandler answers the admission reviews with a JSON patch injecting injectedAnnotation
+func mutatingWebhookHandler(t *testing.T) http.HandlerFunc {
+	t.Helper()
+	return func(w http.ResponseWriter, r *http.Request) {
+		review := admissionv1.AdmissionReview{}
+		if !assert.NoError(t, json.NewDecoder(r.Body).Decode(&review)) {
+			http.Error(w, "invalid admission review", http.StatusBadRequest)
+			return
+		}
+		var obj metav1.PartialObjectMetadata
+		require.NoError(t, json.Unmarshal(review.Request.Object.Raw, &obj))
+		var patch []map[string]any
+		if obj.Annotations == nil {
+			patch = []map[string]any{{"op": "add", "path": "/metadata/annotations", "value": map[string]string{injectedAnnotation: "true"}}}
+		} else {
+			patch = []map[string]any{{"op": "add", "path": "/metadata/annotations/e2e.argoproj.io~1injected", "value": "true"}}
+		}
+		patchBytes, err := json.Marshal(patch)
+		require.NoError(t, err)
+		review.Response = &admissionv1.AdmissionResponse{
+			UID:       review.Request.UID,
+			Allowed:   true,
+			PatchType: ptr.To(admissionv1.PatchTypeJSONPatch),
+			Patch:     patchBytes,
+		}
+		review.Request = nil
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(review))
+	}
+}
+
+// registerMutatingWebhook registers a mutating webhook injecting an annotation into the config maps created or updated
+// in the deployment namespace. The webhook is served by the test, so the Kubernetes API server must run locally.
+func registerMutatingWebhook(t *testing.T) {
+	t.Helper()
+	server := httptest.NewTLSServer(mutatingWebhookHandler(t))
+	t.Cleanup(server.Close)
+
+	webhooks := fixture.KubeClientset.AdmissionregistrationV1().MutatingWebhookConfigurations()
+	config := &admissionregistrationv1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{Name: "argocd-e2e-" + fixture.DeploymentNamespace()},
+		Webhooks: []admissionregistrationv1.MutatingWebhook{{
+			Name: "inject-annotation.e2e.argoproj.io",
+			ClientConfig: admissionregistrationv1.WebhookClientConfig{
+				URL:      ptr.To(server.URL + "/mutate"),
+				CABundle: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}),
+			},
+			Rules: []admissionregistrationv1.RuleWithOperations{{
+				Operations: []admissionregistrationv1.OperationType{admissionregistrationv1.Create, admissionregistrationv1.Update},
+				Rule: admissionregistrationv1.Rule{
+					APIGroups:   []string{""},
+					APIVersions: []string{"v1"},
+					Resources:   []string{"configmaps"},
+				},
+			}},
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"kubernetes.io/metadata.name": fixture.DeploymentNamespace()},
+			},
+			SideEffects:             ptr.To(admissionregistrationv1.SideEffectClassNone),
+			AdmissionReviewVersions: []string{"v1"},
+			FailurePolicy:           ptr.To(admissionregistrationv1.Fail),
+			TimeoutSeconds:          ptr.To(int32(5)),
+		}},
+	}
+	_, err := webhooks.Create(context.Background(), config, metav1.CreateOptions{})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = webhooks.Delete(context.Background(), config.Name, metav1.DeleteOptions{})
+	})
+}
+
+// The mutations of the webhooks of the cluster are only shown by the server-side diff
+func TestServerSideDiffShowsWebhookMutations(t *testing.T) {
+	test.LocalOnly(t)
+	Given(t).
+		Path("config-map").
+		When().
+		CreateApp().
+		Sync().
+		Then().
+		Expect(SyncStatusIs(SyncStatusCodeSynced)).
+		And(func(app *Application) {
+			registerMutatingWebhook(t)
+
+			output, err := fixture.RunCli("app", "diff", app.QualifiedName())
+			require.NoError(t, err, output)
+			assert.NotContains(t, output, injectedAnnotation)
+
+			output, err = fixture.RunCli("app", "diff", app.QualifiedName(), "--server-side-diff")
+			require.Error(t, err, "a diff is expected")
+			assert.Contains(t, output, "===== /ConfigMap "+fixture.DeploymentNamespace()+"/my-map ======")
+			assert.Contains(t, output, injectedAnnotation)
+		})
+}
+
+// Applications with the ServerSideDiff=true sync option are compared with server-side diff by the controller, which
+// ignores the mutations of the webhooks, while the CLI shows them
+func TestServerSideDiffSyncOption(t *testing.T) {
+	test.LocalOnly(t)
+	Given(t).
+		Path("config-map").
+		When().
+		CreateApp().
+		Sync().
+		Then().
+		Expect(SyncStatusIs(SyncStatusCodeSynced)).
+		And(func(_ *Application) {
+			registerMutatingWebhook(t)
+		}).
+		When().
+		PatchApp(`[{"op": "add", "path": "/spec/syncPolicy", "value": {"syncOptions": ["ServerSideDiff=true"]}}]`).
+		Refresh(RefreshTypeNormal).
+		Then().
+		Expect(SyncStatusIs(SyncStatusCodeSynced)).
+		And(func(app *Application) {
+			output, err := fixture.RunCli("app", "diff", app.QualifiedName())
+			require.Error(t, err, "a diff is expected")
+			assert.Contains(t, output, injectedAnnotation)
+		})
+}
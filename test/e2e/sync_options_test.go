@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	. "github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
@@ -146,3 +147,57 @@ func TestSyncWithForceReplace(t *testing.T) {
 		Then().
 		Expect(SyncStatusIs(SyncStatusCodeSynced))
 }
+
+// TestSyncTimeout verifies that a sync waiting for a resource which never becomes healthy fails once the sync timeout
+// of the application expired, and is not retried without a retry strategy
+func TestSyncTimeout(t *testing.T) {
+	Given(t).
+		Timeout(60).
+		And(func() {
+			fixture.SetResourceOverrides(map[string]ResourceOverride{
+				"ConfigMap": {
+					HealthLua: `return { status = obj.annotations and obj.annotations['health'] or 'Progressing' }`,
+				},
+			})
+		}).
+		Async(true).
+		Path("hook-custom-health").
+		When().
+		CreateFromFile(func(app *Application) {
+			app.Spec.SyncPolicy = &SyncPolicy{SyncTimeout: "10s"}
+		}).
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationFailed)).
+		Expect(OperationMessageContains("SyncTimedOut: sync did not complete within 10s")).
+		Expect(SyncStatusIs(SyncStatusCodeOutOfSync)).
+		And(func(app *Application) {
+			assert.Equal(t, int64(0), app.Status.OperationState.RetryCount)
+		})
+}
+
+// TestSyncTimeoutWithRetry verifies that a sync which timed out is retried if the application sets a retry strategy
+func TestSyncTimeoutWithRetry(t *testing.T) {
+	Given(t).
+		Timeout(90).
+		And(func() {
+			fixture.SetResourceOverrides(map[string]ResourceOverride{
+				"ConfigMap": {
+					HealthLua: `return { status = obj.annotations and obj.annotations['health'] or 'Progressing' }`,
+				},
+			})
+		}).
+		Async(true).
+		Path("hook-custom-health").
+		When().
+		CreateFromFile(func(app *Application) {
+			app.Spec.SyncPolicy = &SyncPolicy{
+				SyncTimeout: "10s",
+				Retry:       &RetryStrategy{Limit: 1, Backoff: &Backoff{Duration: "1s"}},
+			}
+		}).
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationFailed)).
+		Expect(OperationMessageContains("SyncTimedOut: sync did not complete within 10s (retried 1 times)."))
+}
@@ -0,0 +1,33 @@
+package e2e
+
+import (
+	"testing"
+
+	. "github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	. "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/argoproj/argo-cd/v2/test/e2e/fixture/app"
+)
+
+// TestSyncProgress syncs 50 resources applied in turn and checks that the progress of the sync is reported once the
+// first resource is applied
+func TestSyncProgress(t *testing.T) {
+	Given(t).
+		Path("many-configmaps").
+		Async(true).
+		Timeout(180).
+		When().
+		CreateApp().
+		Sync().
+		Then().
+		Expect(OperationProgressIsReported(50)).
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		Expect(SyncStatusIs(SyncStatusCodeSynced)).
+		And(func(app *Application) {
+			require.NotNil(t, app.Status.OperationState.Progress)
+			assert.Equal(t, int64(50), app.Status.OperationState.Progress.ResourcesApplied)
+			assert.Equal(t, int64(50), app.Status.OperationState.Progress.ResourcesTotal)
+		})
+}
@@ -0,0 +1,80 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/argoproj/gitops-engine/pkg/sync/common"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	. "github.com/argoproj/argo-cd/v2/test/e2e/fixture"
+	. "github.com/argoproj/argo-cd/v2/test/e2e/fixture/app"
+)
+
+// TestTimoniModule builds the module of testdata/timoni, which requires timoni in the PATH of the repo server
+func TestTimoniModule(t *testing.T) {
+	SkipOnEnv(t, "TIMONI")
+	Given(t).
+		Path("timoni").
+		When().
+		CreateFromFile(func(app *Application) {
+			app.Spec.Source.Timoni = &ApplicationSourceTimoni{}
+		}).
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		Expect(SyncStatusIs(SyncStatusCodeSynced)).
+		Expect(ResourceResultNumbering(2)).
+		And(func(app *Application) {
+			assert.Equal(t, string(ApplicationSourceTypeTimoni), app.Status.SourceType)
+
+			manifests, err := RunCli("app", "manifests", app.Name, "--source", "git")
+			require.NoError(t, err)
+			resources, err := kube.SplitYAML([]byte(manifests))
+			require.NoError(t, err)
+			require.Len(t, resources, 2)
+			kinds := map[string]bool{}
+			for _, resource := range resources {
+				// the instance is named after the application and deployed to its destination namespace
+				assert.Equal(t, app.Name, resource.GetName())
+				assert.Equal(t, DeploymentNamespace(), resource.GetNamespace())
+				kinds[resource.GetKind()] = true
+			}
+			assert.Equal(t, map[string]bool{kube.DeploymentKind: true, kube.ServiceKind: true}, kinds)
+
+			deployment, err := KubeClientset.AppsV1().Deployments(DeploymentNamespace()).Get(context.Background(), app.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, int32(1), *deployment.Spec.Replicas)
+			assert.Equal(t, "quay.io/argoprojlabs/argocd-e2e-container:0.2", deployment.Spec.Template.Spec.Containers[0].Image)
+		})
+}
+
+func TestTimoniModuleValues(t *testing.T) {
+	SkipOnEnv(t, "TIMONI")
+	Given(t).
+		Path("timoni").
+		When().
+		CreateFromFile(func(app *Application) {
+			app.Spec.Source.Timoni = &ApplicationSourceTimoni{Values: "replicas: 2\nport: 8080\n"}
+		}).
+		Sync().
+		Then().
+		Expect(OperationPhaseIs(OperationSucceeded)).
+		Expect(SyncStatusIs(SyncStatusCodeSynced)).
+		Expect(ResourceResultNumbering(2)).
+		And(func(app *Application) {
+			deployment, err := KubeClientset.AppsV1().Deployments(DeploymentNamespace()).Get(context.Background(), app.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, int32(2), *deployment.Spec.Replicas)
+
+			service, err := KubeClientset.CoreV1().Services(DeploymentNamespace()).Get(context.Background(), app.Name, metav1.GetOptions{})
+			require.NoError(t, err)
+			require.Len(t, service.Spec.Ports, 1)
+			assert.Equal(t, int32(8080), service.Spec.Ports[0].Port)
+			assert.Equal(t, "http", service.Spec.Ports[0].TargetPort.String())
+		})
+}
@@ -103,6 +103,59 @@ export const ApplicationOperationState: React.StatelessComponent<Props> = ({appl
             )
         });
     }
+    const [dryRunResultId, setDryRunResultId] = React.useState('');
+    const [dryRunComparison, setDryRunComparison] = React.useState<models.DryRunComparisonResult>(null);
+    const syncCompleted = operationState.operation.sync && operationState.syncResult && operationState.finishedAt && operationState.phase !== 'Running';
+    if (syncCompleted && operationState.operation.sync.dryRun) {
+        operationAttributes.push({
+            title: 'DRY RUN RESULT',
+            value: (
+                <button
+                    className='argo-button argo-button--base'
+                    onClick={async () => {
+                        try {
+                            const id = await services.applications.storeDryRunResult(application.metadata.name, application.metadata.namespace);
+                            ctx.apis.notifications.show({content: `Dry run result stored with ID ${id}`, type: NotificationType.Success});
+                        } catch (e) {
+                            ctx.apis.notifications.show({
+                                content: <ErrorNotification title='Unable to store dry run result' e={e} />,
+                                type: NotificationType.Error
+                            });
+                        }
+                    }}>
+                    Store
+                </button>
+            )
+        });
+    } else if (syncCompleted) {
+        operationAttributes.push({
+            title: 'COMPARE WITH DRY RUN',
+            value: (
+                <div style={{display: 'flex'}}>
+                    <input className='argo-field' placeholder='Dry run result ID' value={dryRunResultId} onChange={e => setDryRunResultId(e.target.value)} />
+                    <button
+                        className='argo-button argo-button--base'
+                        style={{marginLeft: '5px'}}
+                        disabled={!dryRunResultId}
+                        onClick={async () => {
+                            try {
+                                setDryRunComparison(
+                                    await services.applications.compareDryRunToActual(dryRunResultId.trim(), application.metadata.name, application.metadata.namespace)
+                                );
+                            } catch (e) {
+                                setDryRunComparison(null);
+                                ctx.apis.notifications.show({
+                                    content: <ErrorNotification title='Unable to compare dry run result' e={e} />,
+                                    type: NotificationType.Error
+                                });
+                            }
+                        }}>
+                        Compare
+                    </button>
+                </div>
+            )
+        });
+    }
     let initiator = '';
     if (operationState.operation.initiatedBy) {
         if (operationState.operation.initiatedBy.automated) {
@@ -150,6 +203,39 @@ export const ApplicationOperationState: React.StatelessComponent<Props> = ({appl
                     ))}
                 </div>
             </div>
+            {dryRunComparison && (
+                <React.Fragment>
+                    <label style={{display: 'block', marginBottom: '1em'}}>
+                        DRY RUN COMPARISON: {dryRunComparison.matched ? 'the sync matched the dry run' : 'the sync did not match the dry run'}
+                    </label>
+                    <div className='argo-table-list'>
+                        <div className='argo-table-list__head'>
+                            <div className='row'>
+                                <div className='columns small-3'>RESOURCE</div>
+                                <div className='columns small-4'>DRY RUN</div>
+                                <div className='columns small-4'>ACTUAL</div>
+                                <div className='columns small-1'>MATCHED</div>
+                            </div>
+                        </div>
+                        {(dryRunComparison.items || []).map((item, i) => (
+                            <div className='argo-table-list__row' key={i}>
+                                <div className='row'>
+                                    <div className='columns small-3' title={`${item.namespace}/${item.kind}:${item.name}`}>
+                                        {item.namespace}/{item.kind}:{item.name}
+                                    </div>
+                                    <div className='columns small-4' title={item.dryRunMessage}>
+                                        {item.dryRunStatus || '-'} <div className='application-operation-state__message'>{item.dryRunMessage}</div>
+                                    </div>
+                                    <div className='columns small-4' title={item.actualMessage}>
+                                        {item.actualStatus || '-'} <div className='application-operation-state__message'>{item.actualMessage}</div>
+                                    </div>
+                                    <div className='columns small-1'>{item.matched ? <i className='fa fa-check' /> : <i className='fa fa-times' />}</div>
+                                </div>
+                            </div>
+                        ))}
+                    </div>
+                </React.Fragment>
+            )}
             {syncResult && syncResult.resources && syncResult.resources.length > 0 && (
                 <React.Fragment>
                     <div style={{display: 'flex'}}>
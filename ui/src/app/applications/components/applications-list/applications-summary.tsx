@@ -1,9 +1,10 @@
 import * as React from 'react';
 const PieChart = require('react-svg-piechart').default;
 
-import {COLORS} from '../../../shared/components';
+import {COLORS, DataLoader} from '../../../shared/components';
 import * as models from '../../../shared/models';
 import {HealthStatusCode, SyncStatusCode} from '../../../shared/models';
+import {services} from '../../../shared/services';
 import {ComparisonStatusIcon, HealthStatusIcon} from '../utils';
 
 const healthColors = new Map<models.HealthStatusCode, string>();
@@ -19,7 +20,61 @@ syncColors.set('Unknown', COLORS.sync.unknown);
 syncColors.set('Synced', COLORS.sync.synced);
 syncColors.set('OutOfSync', COLORS.sync.out_of_sync);
 
-export const ApplicationsSummary = ({applications}: {applications: models.Application[]}) => {
+const clusterCounts = (rollup: models.ClusterRollup) => {
+    const counts = {total: 0, synced: 0, healthy: 0};
+    Object.entries(rollup.appCounts || {}).forEach(([key, count]) => {
+        const [, syncStatus, healthStatus] = key.split('/');
+        counts.total += count;
+        counts.synced += syncStatus === 'Synced' ? count : 0;
+        counts.healthy += healthStatus === 'Healthy' ? count : 0;
+    });
+    return counts;
+};
+
+const FleetStatusSummary = ({projects}: {projects: string[]}) => (
+    <DataLoader input={(projects || []).join(',')} load={() => services.applications.getFleetStatus(projects || [])}>
+        {(fleetStatus: models.FleetStatus) =>
+            (fleetStatus.clusters || []).length > 0 && (
+                <div className='white-box applications-list__summary'>
+                    <div className='white-box__details'>
+                        <p className='row'>CLUSTERS</p>
+                        <div className='row white-box__details-row'>
+                            <div className='columns small-6'>SERVER</div>
+                            <div style={{textAlign: 'right'}} className='columns small-2'>
+                                APPLICATIONS
+                            </div>
+                            <div style={{textAlign: 'right'}} className='columns small-2'>
+                                SYNCED
+                            </div>
+                            <div style={{textAlign: 'right'}} className='columns small-2'>
+                                HEALTHY
+                            </div>
+                        </div>
+                        {fleetStatus.clusters.map(rollup => {
+                            const counts = clusterCounts(rollup);
+                            return (
+                                <div className='row white-box__details-row' key={rollup.server}>
+                                    <div className='columns small-6'>{rollup.server}</div>
+                                    <div style={{textAlign: 'right'}} className='columns small-2'>
+                                        {counts.total}
+                                    </div>
+                                    <div style={{textAlign: 'right'}} className='columns small-2'>
+                                        {counts.synced}
+                                    </div>
+                                    <div style={{textAlign: 'right'}} className='columns small-2'>
+                                        {counts.healthy}
+                                    </div>
+                                </div>
+                            );
+                        })}
+                    </div>
+                </div>
+            )
+        }
+    </DataLoader>
+);
+
+export const ApplicationsSummary = ({applications, projects}: {applications: models.Application[]; projects?: string[]}) => {
     const sync = new Map<string, number>();
     applications.forEach(app => sync.set(app.status.sync.status, (sync.get(app.status.sync.status) || 0) + 1));
     const health = new Map<string, number>();
@@ -61,55 +116,58 @@ export const ApplicationsSummary = ({applications}: {applications: models.Applic
         }
     ];
     return (
-        <div className='white-box applications-list__summary'>
-            <div className='row'>
-                <div className='columns large-3 small-12'>
-                    <div className='white-box__details'>
-                        <p className='row'>SUMMARY</p>
-                        {attributes.map(attr => (
-                            <div className='row white-box__details-row' key={attr.title}>
-                                <div className='columns small-8'>{attr.title}</div>
-                                <div style={{textAlign: 'right'}} className='columns small-4'>
-                                    {attr.value}
+        <React.Fragment>
+            <div className='white-box applications-list__summary'>
+                <div className='row'>
+                    <div className='columns large-3 small-12'>
+                        <div className='white-box__details'>
+                            <p className='row'>SUMMARY</p>
+                            {attributes.map(attr => (
+                                <div className='row white-box__details-row' key={attr.title}>
+                                    <div className='columns small-8'>{attr.title}</div>
+                                    <div style={{textAlign: 'right'}} className='columns small-4'>
+                                        {attr.value}
+                                    </div>
                                 </div>
-                            </div>
-                        ))}
+                            ))}
+                        </div>
                     </div>
-                </div>
-                <div className='columns large-9 small-12'>
-                    <div className='row chart-group'>
-                        {charts.map(chart => {
-                            const getLegendValue = (key: string) => {
-                                const index = chart.data.findIndex((data: {title: string}) => data.title === key);
-                                return index > -1 ? chart.data[index].value : 0;
-                            };
-                            return (
-                                <React.Fragment key={chart.title}>
-                                    <div className='columns large-6 small-12'>
-                                        <div className='row chart'>
-                                            <div className='large-8 small-6'>
-                                                <h4 style={{textAlign: 'center'}}>{chart.title}</h4>
-                                                <PieChart data={chart.data} />
-                                            </div>
-                                            <div className='large-3 small-1'>
-                                                <ul>
-                                                    {Array.from(chart.legend.keys()).map(key => (
-                                                        <li style={{listStyle: 'none', whiteSpace: 'nowrap'}} key={key}>
-                                                            {chart.title === 'Health' && <HealthStatusIcon state={{status: key as HealthStatusCode, message: ''}} noSpin={true} />}
-                                                            {chart.title === 'Sync' && <ComparisonStatusIcon status={key as SyncStatusCode} noSpin={true} />}
-                                                            {` ${key} (${getLegendValue(key)})`}
-                                                        </li>
-                                                    ))}
-                                                </ul>
+                    <div className='columns large-9 small-12'>
+                        <div className='row chart-group'>
+                            {charts.map(chart => {
+                                const getLegendValue = (key: string) => {
+                                    const index = chart.data.findIndex((data: {title: string}) => data.title === key);
+                                    return index > -1 ? chart.data[index].value : 0;
+                                };
+                                return (
+                                    <React.Fragment key={chart.title}>
+                                        <div className='columns large-6 small-12'>
+                                            <div className='row chart'>
+                                                <div className='large-8 small-6'>
+                                                    <h4 style={{textAlign: 'center'}}>{chart.title}</h4>
+                                                    <PieChart data={chart.data} />
+                                                </div>
+                                                <div className='large-3 small-1'>
+                                                    <ul>
+                                                        {Array.from(chart.legend.keys()).map(key => (
+                                                            <li style={{listStyle: 'none', whiteSpace: 'nowrap'}} key={key}>
+                                                                {chart.title === 'Health' && <HealthStatusIcon state={{status: key as HealthStatusCode, message: ''}} noSpin={true} />}
+                                                                {chart.title === 'Sync' && <ComparisonStatusIcon status={key as SyncStatusCode} noSpin={true} />}
+                                                                {` ${key} (${getLegendValue(key)})`}
+                                                            </li>
+                                                        ))}
+                                                    </ul>
+                                                </div>
                                             </div>
                                         </div>
-                                    </div>
-                                </React.Fragment>
-                            );
-                        })}
+                                    </React.Fragment>
+                                );
+                            })}
+                        </div>
                     </div>
                 </div>
             </div>
-        </div>
+            <FleetStatusSummary projects={projects} />
+        </React.Fragment>
     );
 };
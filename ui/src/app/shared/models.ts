@@ -965,6 +965,35 @@ export interface LinksResponse {
     items: LinkInfo[];
 }
 
+export interface DryRunResourceComparison {
+    group: string;
+    kind: string;
+    namespace: string;
+    name: string;
+    dryRunStatus?: string;
+    dryRunMessage?: string;
+    actualStatus?: string;
+    actualMessage?: string;
+    matched: boolean;
+}
+
+export interface DryRunComparisonResult {
+    dryRunRevision: string;
+    actualRevision: string;
+    items: DryRunResourceComparison[];
+    matched: boolean;
+}
+
+export interface ClusterRollup {
+    server: string;
+    // number of applications keyed by "<project>/<syncStatus>/<healthStatus>"
+    appCounts: {[key: string]: number};
+}
+
+export interface FleetStatus {
+    clusters: ClusterRollup[];
+}
+
 export interface UserMessages {
     appName: string;
     msgKey: string;
@@ -21,6 +21,13 @@ function optionsToSearch(options?: QueryOptions) {
 }
 
 export class ApplicationsService {
+    public getFleetStatus(projects: string[]): Promise<models.FleetStatus> {
+        return requests
+            .get('/fleet/status')
+            .query({projects})
+            .then(res => res.body as models.FleetStatus);
+    }
+
     public list(projects: string[], options?: QueryOptions): Promise<models.ApplicationList> {
         return requests
             .get('/applications')
@@ -413,6 +420,20 @@ export class ApplicationsService {
             .then(() => true);
     }
 
+    public storeDryRunResult(applicationName: string, appNamespace: string): Promise<string> {
+        return requests
+            .post(`/applications/${applicationName}/dry-run-results`)
+            .send({appNamespace})
+            .then(res => res.body.id as string);
+    }
+
+    public compareDryRunToActual(id: string, applicationName: string, appNamespace: string): Promise<models.DryRunComparisonResult> {
+        return requests
+            .get(`/applications/${applicationName}/dry-run-results/${id}/comparison`)
+            .query({appNamespace})
+            .then(res => res.body as models.DryRunComparisonResult);
+    }
+
     public getLinks(applicationName: string, namespace: string): Promise<models.LinksResponse> {
         return requests
             .get(`/applications/${applicationName}/links`)
@@ -0,0 +1,206 @@
+package app
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const minutesPerDay = 24 * 60
+
+var chronoWeekdays = map[string][]time.Weekday{
+	"day":       {time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday},
+	"weekday":   {time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	"weekend":   {time.Saturday, time.Sunday},
+	"sunday":    {time.Sunday},
+	"sun":       {time.Sunday},
+	"monday":    {time.Monday},
+	"mon":       {time.Monday},
+	"tuesday":   {time.Tuesday},
+	"tue":       {time.Tuesday},
+	"wednesday": {time.Wednesday},
+	"wed":       {time.Wednesday},
+	"thursday":  {time.Thursday},
+	"thu":       {time.Thursday},
+	"friday":    {time.Friday},
+	"fri":       {time.Friday},
+	"saturday":  {time.Saturday},
+	"sat":       {time.Saturday},
+}
+
+// chronoDate is a date excluded from a chrono schedule, a zero year matches the date of every year
+type chronoDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+func (d chronoDate) matches(t time.Time) bool {
+	return (d.year == 0 || d.year == t.Year()) && d.month == t.Month() && d.day == t.Day()
+}
+
+// ChronoSchedule is a recurring sync window schedule, parsed from an extended expression such as
+// `every weekday from 09:00 to 17:00 in America/New_York except 12-25, 2024-11-28`
+type ChronoSchedule struct {
+	days [7]bool
+	// start and end are the minutes since midnight the window opens and closes at, an end before or equal to the start
+	// closes the window on the following day
+	start    int
+	end      int
+	location *time.Location
+	except   []chronoDate
+}
+
+// ParseChronoSchedule parses an extended sync window expression of the form
+//
+//	every <days> [from <HH:MM> to <HH:MM>] [in <time zone>] [except <dates>]
+//
+// The days are a list of weekdays, or one of `day`, `weekday` and `weekend`. The window lasts the whole day if no time
+// range is given. The time zone defaults to the given default time zone, and to UTC if that is empty. The dates are a
+// list of `YYYY-MM-DD` dates, or `MM-DD` dates recurring every year, on which the window does not open.
+func ParseChronoSchedule(expr string, defaultTimeZone string) (*ChronoSchedule, error) {
+	tokens := strings.Fields(strings.ReplaceAll(expr, ",", " , "))
+	if len(tokens) == 0 || !strings.EqualFold(tokens[0], "every") {
+		return nil, fmt.Errorf("schedule must start with 'every'")
+	}
+	s := &ChronoSchedule{start: 0, end: minutesPerDay}
+	timeZone := defaultTimeZone
+
+	isKeyword := func(token string) bool {
+		switch strings.ToLower(token) {
+		case "from", "in", "except":
+			return true
+		}
+		return false
+	}
+	// clause returns the tokens following position i up to the next keyword, skipping list separators
+	clause := func(i int) ([]string, int) {
+		var values []string
+		for ; i < len(tokens) && !isKeyword(tokens[i]); i++ {
+			if tokens[i] != "," && !strings.EqualFold(tokens[i], "and") {
+				values = append(values, tokens[i])
+			}
+		}
+		return values, i
+	}
+
+	days, i := clause(1)
+	if len(days) == 0 {
+		return nil, fmt.Errorf("schedule must specify the days of the window")
+	}
+	for _, day := range days {
+		weekdays, ok := chronoWeekdays[strings.TrimSuffix(strings.ToLower(day), "s")]
+		if !ok {
+			return nil, fmt.Errorf("unknown day '%s'", day)
+		}
+		for _, weekday := range weekdays {
+			s.days[weekday] = true
+		}
+	}
+
+	seen := map[string]bool{}
+	for i < len(tokens) {
+		keyword := strings.ToLower(tokens[i])
+		if seen[keyword] {
+			return nil, fmt.Errorf("'%s' must only be specified once", keyword)
+		}
+		seen[keyword] = true
+		var values []string
+		values, i = clause(i + 1)
+		switch keyword {
+		case "from":
+			if len(values) != 3 || !strings.EqualFold(values[1], "to") {
+				return nil, fmt.Errorf("time range must be of the form 'from HH:MM to HH:MM'")
+			}
+			var err error
+			if s.start, err = parseChronoTime(values[0]); err != nil {
+				return nil, err
+			}
+			if s.end, err = parseChronoTime(values[2]); err != nil {
+				return nil, err
+			}
+			if s.start == minutesPerDay {
+				return nil, fmt.Errorf("window cannot start at 24:00")
+			}
+			if s.start == s.end {
+				return nil, fmt.Errorf("window cannot start and end at the same time")
+			}
+		case "in":
+			if len(values) != 1 {
+				return nil, fmt.Errorf("time zone must be a single IANA time zone name")
+			}
+			timeZone = values[0]
+		case "except":
+			if len(values) == 0 {
+				return nil, fmt.Errorf("'except' must be followed by one or more dates")
+			}
+			for _, value := range values {
+				date, err := parseChronoDate(value)
+				if err != nil {
+					return nil, err
+				}
+				s.except = append(s.except, date)
+			}
+		}
+	}
+
+	location, err := time.LoadLocation(timeZone)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone '%s': %w", timeZone, err)
+	}
+	s.location = location
+	return s, nil
+}
+
+func parseChronoTime(value string) (int, error) {
+	if value == "24:00" {
+		return minutesPerDay, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time '%s', must be of the form HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func parseChronoDate(value string) (chronoDate, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return chronoDate{year: t.Year(), month: t.Month(), day: t.Day()}, nil
+	}
+	if t, err := time.Parse("01-02", value); err == nil {
+		return chronoDate{month: t.Month(), day: t.Day()}, nil
+	}
+	return chronoDate{}, fmt.Errorf("invalid date '%s', must be of the form YYYY-MM-DD or MM-DD", value)
+}
+
+// Active returns whether the window is open at the given time. Windows spanning midnight belong to the day they open
+// on, which is also the day the exclusions apply to.
+func (s *ChronoSchedule) Active(t time.Time) bool {
+	t = t.In(s.location)
+	// A window opened on the previous day may still be open
+	for _, offset := range []int{0, -1} {
+		day := time.Date(t.Year(), t.Month(), t.Day()+offset, 0, 0, 0, 0, s.location)
+		if !s.days[day.Weekday()] || s.excluded(day) {
+			continue
+		}
+		end := s.end
+		if end <= s.start {
+			end += minutesPerDay
+		}
+		start := time.Date(day.Year(), day.Month(), day.Day(), 0, s.start, 0, 0, s.location)
+		stop := time.Date(day.Year(), day.Month(), day.Day(), 0, end, 0, 0, s.location)
+		if !t.Before(start) && t.Before(stop) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *ChronoSchedule) excluded(day time.Time) bool {
+	for _, date := range s.except {
+		if date.matches(day) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseChronoSchedule_Invalid(t *testing.T) {
+	for expr, expected := range map[string]string{
+		"":                                        "schedule must start with 'every'",
+		"weekday from 09:00 to 17:00":             "schedule must start with 'every'",
+		"every from 09:00 to 17:00":               "schedule must specify the days of the window",
+		"every holiday":                           "unknown day 'holiday'",
+		"every day from 09:00":                    "time range must be of the form 'from HH:MM to HH:MM'",
+		"every day from 9am to 5pm":               "invalid time '9am', must be of the form HH:MM",
+		"every day from 09:00 to 09:00":           "window cannot start and end at the same time",
+		"every day in Mars/Olympus_Mons":          "invalid time zone 'Mars/Olympus_Mons'",
+		"every day except":                        "'except' must be followed by one or more dates",
+		"every day except christmas":              "invalid date 'christmas', must be of the form YYYY-MM-DD or MM-DD",
+		"every day in UTC in Europe/Berlin":       "'in' must only be specified once",
+		"every day from 01:00 to 02:00 from 3:00": "'from' must only be specified once",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseChronoSchedule(expr, "")
+			require.ErrorContains(t, err, expected)
+		})
+	}
+}
+
+func TestChronoSchedule_Active(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	t.Run("Business hours", func(t *testing.T) {
+		s, err := ParseChronoSchedule("every weekday from 09:00 to 17:00", "")
+		require.NoError(t, err)
+		// Friday
+		assert.False(t, s.Active(time.Date(2024, 3, 1, 8, 59, 0, 0, time.UTC)))
+		assert.True(t, s.Active(time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)))
+		assert.True(t, s.Active(time.Date(2024, 3, 1, 16, 59, 0, 0, time.UTC)))
+		assert.False(t, s.Active(time.Date(2024, 3, 1, 17, 0, 0, 0, time.UTC)))
+		// Saturday
+		assert.False(t, s.Active(time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("Whole days", func(t *testing.T) {
+		s, err := ParseChronoSchedule("Every Saturdays and Sundays", "")
+		require.NoError(t, err)
+		assert.False(t, s.Active(time.Date(2024, 3, 1, 23, 59, 0, 0, time.UTC)))
+		assert.True(t, s.Active(time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, s.Active(time.Date(2024, 3, 3, 23, 59, 0, 0, time.UTC)))
+		assert.False(t, s.Active(time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("Time zone", func(t *testing.T) {
+		s, err := ParseChronoSchedule("every weekday from 09:00 to 17:00 in America/New_York", "UTC")
+		require.NoError(t, err)
+		// 09:00 in New York is 14:00 UTC before the switch to daylight saving time on 2024-03-10
+		assert.False(t, s.Active(time.Date(2024, 3, 8, 13, 59, 0, 0, time.UTC)))
+		assert.True(t, s.Active(time.Date(2024, 3, 8, 14, 0, 0, 0, time.UTC)))
+		// and 13:00 UTC after it
+		assert.True(t, s.Active(time.Date(2024, 3, 11, 13, 0, 0, 0, time.UTC)))
+		assert.True(t, s.Active(time.Date(2024, 3, 11, 9, 0, 0, 0, newYork)))
+		assert.False(t, s.Active(time.Date(2024, 3, 11, 17, 0, 0, 0, newYork)))
+	})
+
+	t.Run("Default time zone", func(t *testing.T) {
+		s, err := ParseChronoSchedule("every day from 09:00 to 17:00", "America/New_York")
+		require.NoError(t, err)
+		assert.True(t, s.Active(time.Date(2024, 3, 11, 9, 0, 0, 0, newYork)))
+		assert.False(t, s.Active(time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("Overnight", func(t *testing.T) {
+		s, err := ParseChronoSchedule("every friday from 22:00 to 06:00", "")
+		require.NoError(t, err)
+		assert.False(t, s.Active(time.Date(2024, 3, 1, 21, 59, 0, 0, time.UTC)))
+		assert.True(t, s.Active(time.Date(2024, 3, 1, 22, 0, 0, 0, time.UTC)))
+		assert.True(t, s.Active(time.Date(2024, 3, 2, 5, 59, 0, 0, time.UTC)))
+		assert.False(t, s.Active(time.Date(2024, 3, 2, 6, 0, 0, 0, time.UTC)))
+		// Thursday night is not part of the window
+		assert.False(t, s.Active(time.Date(2024, 3, 1, 1, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("Holiday exclusions", func(t *testing.T) {
+		s, err := ParseChronoSchedule("every weekday from 09:00 to 17:00 in America/New_York except 12-25, 01-01 and 2024-11-28", "")
+		require.NoError(t, err)
+		// Christmas, every year
+		assert.False(t, s.Active(time.Date(2024, 12, 25, 12, 0, 0, 0, newYork)))
+		assert.False(t, s.Active(time.Date(2025, 12, 25, 12, 0, 0, 0, newYork)))
+		assert.True(t, s.Active(time.Date(2024, 12, 24, 12, 0, 0, 0, newYork)))
+		// New year
+		assert.False(t, s.Active(time.Date(2025, 1, 1, 12, 0, 0, 0, newYork)))
+		// Thanksgiving, in 2024 only
+		assert.False(t, s.Active(time.Date(2024, 11, 28, 12, 0, 0, 0, newYork)))
+		assert.True(t, s.Active(time.Date(2024, 11, 29, 12, 0, 0, 0, newYork)))
+		assert.True(t, s.Active(time.Date(2029, 11, 28, 12, 0, 0, 0, newYork)))
+		// The date is evaluated in the time zone of the schedule: it is already Christmas in UTC, but not in New York
+		assert.True(t, s.Active(time.Date(2024, 12, 24, 16, 30, 0, 0, newYork)))
+	})
+
+	t.Run("Holiday exclusion of overnight window", func(t *testing.T) {
+		s, err := ParseChronoSchedule("every day from 22:00 to 06:00 except 12-24", "")
+		require.NoError(t, err)
+		assert.False(t, s.Active(time.Date(2024, 12, 24, 23, 0, 0, 0, time.UTC)))
+		assert.False(t, s.Active(time.Date(2024, 12, 25, 1, 0, 0, 0, time.UTC)))
+		assert.True(t, s.Active(time.Date(2024, 12, 24, 1, 0, 0, 0, time.UTC)))
+	})
+
+	t.Run("Until midnight", func(t *testing.T) {
+		s, err := ParseChronoSchedule("every mon from 18:00 to 24:00", "")
+		require.NoError(t, err)
+		assert.True(t, s.Active(time.Date(2024, 3, 4, 23, 59, 0, 0, time.UTC)))
+		assert.False(t, s.Active(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)))
+	})
+}
@@ -383,6 +383,48 @@ func validateRepo(ctx context.Context,
 	errMessage := ""
 
 	for _, source := range sources {
+		if source.VerifyAttestation != nil && source.VerifyAttestation.InToto != nil {
+			if err := ValidateInTotoAttestation(source); err != nil {
+				conditions = append(conditions, argoappv1.ApplicationCondition{
+					Type:    argoappv1.ApplicationConditionInvalidSpecError,
+					Message: fmt.Sprintf("invalid in-toto attestation: %v", err),
+				})
+				return conditions, nil
+			}
+		}
+		// Inline manifests are not read from a repository, so only their syntax is verified
+		if source.IsInline() {
+			if _, err := kube.SplitYAML([]byte(source.Inline.Manifests)); err != nil {
+				conditions = append(conditions, argoappv1.ApplicationCondition{
+					Type:    argoappv1.ApplicationConditionInvalidSpecError,
+					Message: fmt.Sprintf("invalid inline manifests: %v", err),
+				})
+				return conditions, nil
+			}
+			continue
+		}
+		// The manifests of HTTP sources are fetched when generating manifests, so only their spec is verified
+		if source.IsHTTP() {
+			if err := ValidateHTTPSource(source); err != nil {
+				conditions = append(conditions, argoappv1.ApplicationCondition{
+					Type:    argoappv1.ApplicationConditionInvalidSpecError,
+					Message: fmt.Sprintf("invalid HTTP source: %v", err),
+				})
+				return conditions, nil
+			}
+			continue
+		}
+		// The artifacts of OCI sources are fetched when generating manifests, so only their spec is verified
+		if source.IsOCI() {
+			if err := ValidateOCISource(source); err != nil {
+				conditions = append(conditions, argoappv1.ApplicationCondition{
+					Type:    argoappv1.ApplicationConditionInvalidSpecError,
+					Message: fmt.Sprintf("invalid OCI source: %v", err),
+				})
+				return conditions, nil
+			}
+			continue
+		}
 		repo, err := db.GetRepository(ctx, source.RepoURL, proj.Name)
 		if err != nil {
 			return nil, err
@@ -572,8 +614,33 @@ func ValidatePermissions(ctx context.Context, spec *argoappv1.ApplicationSpec, p
 		}
 	}
 
+	destConditions, err := validateDestinationPermissions(ctx, &spec.Destination, proj, spec.Project, db)
+	if err != nil {
+		return nil, err
+	}
+	conditions = append(conditions, destConditions...)
+
+	for i := range spec.Destinations {
+		// the additional destinations are resolved in a copy, so that the server inferred from their name is not set in
+		// the spec
+		dest := spec.Destinations[i]
+		destConditions, err := validateDestinationPermissions(ctx, &dest, proj, spec.Project, db)
+		if err != nil {
+			return nil, err
+		}
+		for _, condition := range destConditions {
+			condition.Message = fmt.Sprintf("spec.destinations[%d]: %s", i, condition.Message)
+			conditions = append(conditions, condition)
+		}
+	}
+	return conditions, nil
+}
+
+// validateDestinationPermissions checks that the destination exists and is permitted in the project
+func validateDestinationPermissions(ctx context.Context, dest *argoappv1.ApplicationDestination, proj *argoappv1.AppProject, project string, db db.ArgoDB) ([]argoappv1.ApplicationCondition, error) {
+	var conditions []argoappv1.ApplicationCondition
 	// ValidateDestination will resolve the destination's server address from its name for us, if possible
-	if err := ValidateDestination(ctx, &spec.Destination, db); err != nil {
+	if err := ValidateDestination(ctx, dest, db); err != nil {
 		conditions = append(conditions, argoappv1.ApplicationCondition{
 			Type:    argoappv1.ApplicationConditionInvalidSpecError,
 			Message: err.Error(),
@@ -581,8 +648,8 @@ func ValidatePermissions(ctx context.Context, spec *argoappv1.ApplicationSpec, p
 		return conditions, nil
 	}
 
-	if spec.Destination.Server != "" {
-		permitted, err := proj.IsDestinationPermitted(spec.Destination, func(project string) ([]*argoappv1.Cluster, error) {
+	if dest.Server != "" {
+		permitted, err := proj.IsDestinationPermitted(*dest, func(project string) ([]*argoappv1.Cluster, error) {
 			return db.GetProjectClusters(ctx, project)
 		})
 		if err != nil {
@@ -591,22 +658,22 @@ func ValidatePermissions(ctx context.Context, spec *argoappv1.ApplicationSpec, p
 		if !permitted {
 			conditions = append(conditions, argoappv1.ApplicationCondition{
 				Type:    argoappv1.ApplicationConditionInvalidSpecError,
-				Message: fmt.Sprintf("application destination server '%s' and namespace '%s' do not match any of the allowed destinations in project '%s'", spec.Destination.Server, spec.Destination.Namespace, spec.Project),
+				Message: fmt.Sprintf("application destination server '%s' and namespace '%s' do not match any of the allowed destinations in project '%s'", dest.Server, dest.Namespace, project),
 			})
 		}
 		// Ensure the k8s cluster the app is referencing, is configured in Argo CD
-		_, err = db.GetCluster(ctx, spec.Destination.Server)
+		_, err = db.GetCluster(ctx, dest.Server)
 		if err != nil {
 			if errStatus, ok := status.FromError(err); ok && errStatus.Code() == codes.NotFound {
 				conditions = append(conditions, argoappv1.ApplicationCondition{
 					Type:    argoappv1.ApplicationConditionInvalidSpecError,
-					Message: fmt.Sprintf("cluster '%s' has not been configured", spec.Destination.Server),
+					Message: fmt.Sprintf("cluster '%s' has not been configured", dest.Server),
 				})
 			} else {
 				return nil, fmt.Errorf("error getting cluster: %w", err)
 			}
 		}
-	} else if spec.Destination.Server == "" {
+	} else if dest.Server == "" {
 		conditions = append(conditions, argoappv1.ApplicationCondition{Type: argoappv1.ApplicationConditionInvalidSpecError, Message: errDestinationMissing})
 	}
 	return conditions, nil
@@ -632,6 +699,31 @@ func APIResourcesToStrings(resources []kube.APIResourceInfo, includeKinds bool)
 	return res
 }
 
+// NamespaceIsolationResources returns the group kinds of the cluster-scoped API resources, and the subset of them which is
+// explicitly permitted by the given project, both formatted as `<kind>.<group>`. Both are empty unless the project
+// enforces strict namespace isolation.
+func NamespaceIsolationResources(proj *argoappv1.AppProject, resources []kube.APIResourceInfo) ([]string, []string) {
+	if !proj.IsNamespaceIsolationStrict() {
+		return nil, nil
+	}
+	clusterScoped := map[string]bool{}
+	for _, r := range resources {
+		if !r.Meta.Namespaced {
+			clusterScoped[r.GroupKind.String()] = proj.IsClusterResourceExplicitlyPermitted(r.GroupKind)
+		}
+	}
+	var all, permitted []string
+	for gk, isPermitted := range clusterScoped {
+		all = append(all, gk)
+		if isPermitted {
+			permitted = append(permitted, gk)
+		}
+	}
+	sort.Strings(all)
+	sort.Strings(permitted)
+	return all, permitted
+}
+
 // GetAppProjectWithScopedResources returns a project from an application with scoped resources
 func GetAppProjectWithScopedResources(name string, projLister applicationsv1.AppProjectLister, ns string, settingsManager *settings.SettingsManager, db db.ArgoDB, ctx context.Context) (*argoappv1.AppProject, argoappv1.Repositories, []*argoappv1.Cluster, error) {
 	projOrig, err := projLister.AppProjects(ns).Get(name)
@@ -330,6 +330,35 @@ func TestAPIResourcesToStrings(t *testing.T) {
 		APIResourcesToStrings(resources, true))
 }
 
+func TestNamespaceIsolationResources(t *testing.T) {
+	resources := []kube.APIResourceInfo{{
+		GroupKind: schema.GroupKind{Kind: "ConfigMap"},
+		Meta:      metav1.APIResource{Namespaced: true},
+	}, {
+		GroupKind: schema.GroupKind{Kind: "Namespace"},
+	}, {
+		GroupKind: schema.GroupKind{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+	}, {
+		GroupKind: schema.GroupKind{Group: "apiextensions.k8s.io", Kind: "CustomResourceDefinition"},
+	}}
+	proj := &argoappv1.AppProject{Spec: argoappv1.AppProjectSpec{
+		ClusterResourceWhitelist: []metav1.GroupKind{
+			{Group: "rbac.authorization.k8s.io", Kind: "ClusterRole"},
+			// wildcards do not permit cluster-scoped resources explicitly
+			{Group: "apiextensions.k8s.io", Kind: "*"},
+		},
+	}}
+
+	clusterScoped, permitted := NamespaceIsolationResources(proj, resources)
+	assert.Empty(t, clusterScoped)
+	assert.Empty(t, permitted)
+
+	proj.Spec.NamespaceIsolation = argoappv1.NamespaceIsolationStrict
+	clusterScoped, permitted = NamespaceIsolationResources(proj, resources)
+	assert.Equal(t, []string{"ClusterRole.rbac.authorization.k8s.io", "CustomResourceDefinition.apiextensions.k8s.io", "Namespace"}, clusterScoped)
+	assert.Equal(t, []string{"ClusterRole.rbac.authorization.k8s.io"}, permitted)
+}
+
 func TestValidateRepo(t *testing.T) {
 	repoPath, err := filepath.Abs("./../..")
 	require.NoError(t, err)
@@ -721,6 +750,43 @@ func TestValidatePermissions(t *testing.T) {
 		assert.Contains(t, conditions[0].Message, "application destination")
 	})
 
+	t.Run("Additional destination is not permitted in project", func(t *testing.T) {
+		spec := argoappv1.ApplicationSpec{
+			Source: &argoappv1.ApplicationSource{
+				RepoURL:        "http://some/where",
+				Path:           "",
+				Chart:          "somechart",
+				TargetRevision: "1.4.1",
+			},
+			Destination: argoappv1.ApplicationDestination{
+				Server:    "https://127.0.0.1:6443",
+				Namespace: "default",
+			},
+			Destinations: []argoappv1.ApplicationDestination{{
+				Server:    "https://127.0.0.2:6443",
+				Namespace: "testns",
+			}},
+		}
+		proj := argoappv1.AppProject{
+			Spec: argoappv1.AppProjectSpec{
+				Destinations: []argoappv1.ApplicationDestination{
+					{
+						Server:    "*",
+						Namespace: "default",
+					},
+				},
+				SourceRepos: []string{"http://some/where"},
+			},
+		}
+		db := &dbmocks.ArgoDB{}
+		db.On("GetCluster", context.Background(), spec.Destination.Server).Return(&argoappv1.Cluster{Server: spec.Destination.Server}, nil)
+		db.On("GetCluster", context.Background(), spec.Destinations[0].Server).Return(&argoappv1.Cluster{Server: spec.Destinations[0].Server}, nil)
+		conditions, err := ValidatePermissions(context.Background(), &spec, &proj, db)
+		require.NoError(t, err)
+		assert.Len(t, conditions, 1)
+		assert.Contains(t, conditions[0].Message, "spec.destinations[0]: application destination server 'https://127.0.0.2:6443'")
+	})
+
 	t.Run("Destination cluster does not exist", func(t *testing.T) {
 		spec := argoappv1.ApplicationSpec{
 			Source: &argoappv1.ApplicationSource{
@@ -3,6 +3,7 @@ package argo
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -21,6 +22,14 @@ type AuditLogger struct {
 	kIf       kubernetes.Interface
 	component string
 	ns        string
+
+	// dedupWindow is the duration during which identical events are emitted only once, zero disables deduplication
+	dedupWindow time.Duration
+	// onEventDeduplicated is called with the reason of each event which is not emitted
+	onEventDeduplicated func(reason string)
+	// recentEvents is the time identical events were last emitted, keyed by event
+	recentEvents     map[string]time.Time
+	recentEventsLock sync.Mutex
 }
 
 type EventInfo struct {
@@ -36,13 +45,18 @@ type ObjectRef struct {
 }
 
 const (
-	EventReasonStatusRefreshed    = "StatusRefreshed"
-	EventReasonResourceCreated    = "ResourceCreated"
-	EventReasonResourceUpdated    = "ResourceUpdated"
-	EventReasonResourceDeleted    = "ResourceDeleted"
-	EventReasonResourceActionRan  = "ResourceActionRan"
-	EventReasonOperationStarted   = "OperationStarted"
-	EventReasonOperationCompleted = "OperationCompleted"
+	EventReasonStatusRefreshed               = "StatusRefreshed"
+	EventReasonResourceCreated               = "ResourceCreated"
+	EventReasonResourceUpdated               = "ResourceUpdated"
+	EventReasonResourceDeleted               = "ResourceDeleted"
+	EventReasonResourceActionRan             = "ResourceActionRan"
+	EventReasonOperationStarted              = "OperationStarted"
+	EventReasonOperationCompleted            = "OperationCompleted"
+	EventReasonServerSideApplyConflictForced = "ServerSideApplyConflictForced"
+	EventReasonConflictResolved              = "ConflictResolved"
+	EventReasonResourceForceDeleted          = "ResourceForceDeleted"
+	EventReasonDestinationChanged            = "DestinationChanged"
+	EventReasonApplyTimeout                  = "ApplyTimeout"
 )
 
 func (l *AuditLogger) logEvent(objMeta ObjectRef, gvk schema.GroupVersionKind, info EventInfo, message string, logFields map[string]string, eventLabels map[string]string) {
@@ -88,6 +102,10 @@ func (l *AuditLogger) logEvent(objMeta ObjectRef, gvk schema.GroupVersionKind, i
 		Reason:         info.Reason,
 	}
 	logCtx.Info(message)
+	if l.isDuplicateEvent(objMeta, gvk, info, message, t.Time) {
+		logCtx.Debug("Skipping audit event emitted within the deduplication window")
+		return
+	}
 	_, err := l.kIf.CoreV1().Events(objMeta.Namespace).Create(context.Background(), &event, metav1.CreateOptions{})
 	if err != nil {
 		logCtx.Errorf("Unable to create audit event: %v", err)
@@ -95,6 +113,65 @@ func (l *AuditLogger) logEvent(objMeta ObjectRef, gvk schema.GroupVersionKind, i
 	}
 }
 
+// isDuplicateEvent returns true if the same event was emitted for the object within the deduplication window, and
+// records the event as emitted otherwise
+func (l *AuditLogger) isDuplicateEvent(objMeta ObjectRef, gvk schema.GroupVersionKind, info EventInfo, message string, now time.Time) bool {
+	l.recentEventsLock.Lock()
+	defer l.recentEventsLock.Unlock()
+	if l.dedupWindow <= 0 {
+		return false
+	}
+	key := fmt.Sprintf("%s/%s/%s/%s|%s|%s|%s", gvk.Group, gvk.Kind, objMeta.Namespace, objMeta.Name, info.Type, info.Reason, message)
+	if emitted, ok := l.recentEvents[key]; ok && now.Sub(emitted) < l.dedupWindow {
+		if l.onEventDeduplicated != nil {
+			l.onEventDeduplicated(info.Reason)
+		}
+		return true
+	}
+	l.recentEvents[key] = now
+	return false
+}
+
+// EnableEventDeduplication makes the logger emit the events of an object with the same type, reason and message only
+// once within the window. The events which are not emitted are still logged, and onDeduplicated is called with their
+// reason.
+func (l *AuditLogger) EnableEventDeduplication(window time.Duration, onDeduplicated func(reason string)) {
+	l.recentEventsLock.Lock()
+	defer l.recentEventsLock.Unlock()
+	l.dedupWindow = window
+	l.onEventDeduplicated = onDeduplicated
+	l.recentEvents = map[string]time.Time{}
+}
+
+// flushRecentEvents forgets the events emitted before the deduplication window
+func (l *AuditLogger) flushRecentEvents(now time.Time) {
+	l.recentEventsLock.Lock()
+	defer l.recentEventsLock.Unlock()
+	for key, emitted := range l.recentEvents {
+		if now.Sub(emitted) >= l.dedupWindow {
+			delete(l.recentEvents, key)
+		}
+	}
+}
+
+// RunEventDeduplicationFlush periodically forgets the events emitted before the deduplication window, until the
+// context is done. It returns immediately if deduplication is disabled.
+func (l *AuditLogger) RunEventDeduplicationFlush(ctx context.Context) {
+	if l.dedupWindow <= 0 {
+		return
+	}
+	ticker := time.NewTicker(l.dedupWindow)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			l.flushRecentEvents(now)
+		}
+	}
+}
+
 func (l *AuditLogger) LogAppEvent(app *v1alpha1.Application, info EventInfo, message, user string, eventLabels map[string]string) {
 	objectMeta := ObjectRef{
 		Name:            app.ObjectMeta.Name,
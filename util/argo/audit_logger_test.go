@@ -2,11 +2,14 @@ package argo
 
 import (
 	"bytes"
+	"context"
 	"os"
 	"testing"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
@@ -132,3 +135,59 @@ func TestLogResourceEvent(t *testing.T) {
 	assert.Contains(t, output, "type=info")
 	assert.Contains(t, output, "msg=\"This is a test message\"")
 }
+
+func TestLogAppEvent_Deduplication(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	logger := NewAuditLogger("default", kubeClient, "somecomponent")
+	deduplicated := 0
+	logger.EnableEventDeduplication(5*time.Minute, func(reason string) {
+		assert.Equal(t, EventReasonStatusRefreshed, reason)
+		deduplicated++
+	})
+
+	app := argoappv1.Application{
+		ObjectMeta: v1.ObjectMeta{
+			Name:      "testapp",
+			Namespace: "argocd",
+		},
+	}
+	info := EventInfo{Type: "Normal", Reason: EventReasonStatusRefreshed}
+	for i := 0; i < 100; i++ {
+		logger.LogAppEvent(&app, info, "Refreshed app", "", nil)
+	}
+	events, err := kubeClient.CoreV1().Events("argocd").List(context.Background(), v1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, events.Items, 1)
+	assert.Equal(t, 99, deduplicated)
+
+	// events with another message or of another object are emitted
+	logger.LogAppEvent(&app, info, "Refreshed app again", "", nil)
+	other := app.DeepCopy()
+	other.Name = "otherapp"
+	logger.LogAppEvent(other, info, "Refreshed app", "", nil)
+	events, err = kubeClient.CoreV1().Events("argocd").List(context.Background(), v1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, events.Items, 3)
+
+	// events are emitted again once they are flushed after the window
+	logger.flushRecentEvents(time.Now())
+	assert.Len(t, logger.recentEvents, 3)
+	logger.flushRecentEvents(time.Now().Add(5 * time.Minute))
+	assert.Empty(t, logger.recentEvents)
+	logger.LogAppEvent(&app, info, "Refreshed app", "", nil)
+	events, err = kubeClient.CoreV1().Events("argocd").List(context.Background(), v1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, events.Items, 4)
+}
+
+func TestLogAppEvent_NoDeduplication(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	logger := NewAuditLogger("default", kubeClient, "somecomponent")
+	app := argoappv1.Application{ObjectMeta: v1.ObjectMeta{Name: "testapp", Namespace: "argocd"}}
+	for i := 0; i < 3; i++ {
+		logger.LogAppEvent(&app, EventInfo{Type: "Normal", Reason: EventReasonStatusRefreshed}, "Refreshed app", "", nil)
+	}
+	events, err := kubeClient.CoreV1().Events("argocd").List(context.Background(), v1.ListOptions{})
+	require.NoError(t, err)
+	assert.Len(t, events.Items, 3)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/go-logr/logr"
 	log "github.com/sirupsen/logrus"
 
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	k8smanagedfields "k8s.io/apimachinery/pkg/util/managedfields"
 
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
@@ -114,6 +115,20 @@ func (b *DiffConfigBuilder) WithIgnoreMutationWebhook(m bool) *DiffConfigBuilder
 	return b
 }
 
+// WithIgnoreAnnotationOverride defines if the ignore-diff annotation of live resources is
+// disregarded, so that the differences of the annotated paths are shown.
+func (b *DiffConfigBuilder) WithIgnoreAnnotationOverride(o bool) *DiffConfigBuilder {
+	b.diffConfig.ignoreAnnotationOverride = o
+	return b
+}
+
+// WithDiffPlugins sets the names of the diff plugins calculating the diff of the resources
+// of their kind instead of the default diff algorithm.
+func (b *DiffConfigBuilder) WithDiffPlugins(plugins map[schema.GroupKind]string) *DiffConfigBuilder {
+	b.diffConfig.diffPlugins = plugins
+	return b
+}
+
 // Build will first validate the current state of the diff config and return the
 // DiffConfig implementation if no errors are found. Will return nil and the error
 // details otherwise.
@@ -165,26 +180,34 @@ type DiffConfig interface {
 	IgnoreMutationWebhook() bool
 
 	IgnoreNormalizerOpts() normalizers.IgnoreNormalizerOpts
+	// IgnoreAnnotationOverride defines if the ignore-diff annotation of live resources
+	// is disregarded.
+	IgnoreAnnotationOverride() bool
+	// DiffPlugins returns the names of the diff plugins by the kind of the resources they
+	// diff instead of the default diff algorithm.
+	DiffPlugins() map[schema.GroupKind]string
 }
 
 // diffConfig defines the configurations used while applying diffs.
 type diffConfig struct {
-	ignores               []v1alpha1.ResourceIgnoreDifferences
-	overrides             map[string]v1alpha1.ResourceOverride
-	appLabelKey           string
-	trackingMethod        string
-	appName               string
-	noCache               bool
-	stateCache            *appstatecache.Cache
-	ignoreAggregatedRoles bool
-	logger                *logr.Logger
-	gvkParser             *k8smanagedfields.GvkParser
-	structuredMergeDiff   bool
-	manager               string
-	serverSideDiff        bool
-	serverSideDryRunner   diff.ServerSideDryRunner
-	ignoreMutationWebhook bool
-	ignoreNormalizerOpts  normalizers.IgnoreNormalizerOpts
+	ignores                  []v1alpha1.ResourceIgnoreDifferences
+	overrides                map[string]v1alpha1.ResourceOverride
+	appLabelKey              string
+	trackingMethod           string
+	appName                  string
+	noCache                  bool
+	stateCache               *appstatecache.Cache
+	ignoreAggregatedRoles    bool
+	logger                   *logr.Logger
+	gvkParser                *k8smanagedfields.GvkParser
+	structuredMergeDiff      bool
+	manager                  string
+	serverSideDiff           bool
+	serverSideDryRunner      diff.ServerSideDryRunner
+	ignoreMutationWebhook    bool
+	ignoreNormalizerOpts     normalizers.IgnoreNormalizerOpts
+	ignoreAnnotationOverride bool
+	diffPlugins              map[schema.GroupKind]string
 }
 
 func (c *diffConfig) Ignores() []v1alpha1.ResourceIgnoreDifferences {
@@ -251,6 +274,27 @@ func (c *diffConfig) IgnoreNormalizerOpts() normalizers.IgnoreNormalizerOpts {
 	return c.ignoreNormalizerOpts
 }
 
+func (c *diffConfig) IgnoreAnnotationOverride() bool {
+	return c.ignoreAnnotationOverride
+}
+
+func (c *diffConfig) DiffPlugins() map[schema.GroupKind]string {
+	return c.diffPlugins
+}
+
+// resourceIgnores returns the ignore difference configurations of the Application, and
+// of the ignore-diff annotations of the live resources unless they are disregarded.
+func resourceIgnores(lives []*unstructured.Unstructured, diffConfig DiffConfig) []v1alpha1.ResourceIgnoreDifferences {
+	ignores := diffConfig.Ignores()
+	if diffConfig.IgnoreAnnotationOverride() {
+		return ignores
+	}
+	if annotated := annotationIgnores(lives); len(annotated) > 0 {
+		ignores = append(append([]v1alpha1.ResourceIgnoreDifferences{}, ignores...), annotated...)
+	}
+	return ignores
+}
+
 // Validate will check the current state of this diffConfig and return
 // error if it finds any required configuration missing.
 func (c *diffConfig) Validate() error {
@@ -272,6 +316,9 @@ func (c *diffConfig) Validate() error {
 	if c.serverSideDiff && c.serverSideDryRunner == nil {
 		return fmt.Errorf("%s: serverSideDryRunner must be set when using server side diff", msg)
 	}
+	if _, err := resolveDiffPlugins(c.diffPlugins); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
 	return nil
 }
 
@@ -302,7 +349,7 @@ func StateDiffs(lives, configs []*unstructured.Unstructured, diffConfig DiffConf
 		return nil, fmt.Errorf("failed to perform pre-diff normalization: %w", err)
 	}
 
-	diffNormalizer, err := newDiffNormalizer(diffConfig.Ignores(), diffConfig.Overrides(), diffConfig.IgnoreNormalizerOpts())
+	diffNormalizer, err := newDiffNormalizer(resourceIgnores(lives, diffConfig), diffConfig.Overrides(), diffConfig.IgnoreNormalizerOpts())
 	if err != nil {
 		return nil, fmt.Errorf("failed to create diff normalizer: %w", err)
 	}
@@ -322,22 +369,27 @@ func StateDiffs(lives, configs []*unstructured.Unstructured, diffConfig DiffConf
 		diffOpts = append(diffOpts, diff.WithLogr(*diffConfig.Logger()))
 	}
 
+	diffPlugins, err := resolveDiffPlugins(diffConfig.DiffPlugins())
+	if err != nil {
+		return nil, err
+	}
+
 	useCache, cachedDiff := diffConfig.DiffFromCache(diffConfig.AppName())
 	if useCache && cachedDiff != nil {
-		cached, err := diffArrayCached(normResults.Targets, normResults.Lives, cachedDiff, diffOpts...)
+		cached, err := diffArrayCached(normResults.Targets, normResults.Lives, cachedDiff, diffPlugins, diffNormalizer, diffOpts...)
 		if err != nil {
 			return nil, fmt.Errorf("failed to calculate diff from cache: %w", err)
 		}
 		return cached, nil
 	}
-	array, err := diff.DiffArray(normResults.Targets, normResults.Lives, diffOpts...)
+	array, err := diffArray(normResults.Targets, normResults.Lives, diffPlugins, diffNormalizer, diffOpts...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate diff: %w", err)
 	}
 	return array, nil
 }
 
-func diffArrayCached(configArray []*unstructured.Unstructured, liveArray []*unstructured.Unstructured, cachedDiff []*v1alpha1.ResourceDiff, opts ...diff.Option) (*diff.DiffResultList, error) {
+func diffArrayCached(configArray []*unstructured.Unstructured, liveArray []*unstructured.Unstructured, cachedDiff []*v1alpha1.ResourceDiff, plugins map[schema.GroupKind]DiffPlugin, normalizer diff.Normalizer, opts ...diff.Option) (*diff.DiffResultList, error) {
 	numItems := len(configArray)
 	if len(liveArray) != numItems {
 		return nil, fmt.Errorf("left and right arrays have mismatched lengths")
@@ -371,7 +423,7 @@ func diffArrayCached(configArray []*unstructured.Unstructured, liveArray []*unst
 				Modified:       cachedDiff.Modified,
 			}
 		} else {
-			res, err := diff.Diff(configArray[i], liveArray[i], opts...)
+			res, err := diffResource(configArray[i], liveArray[i], plugins, normalizer, opts...)
 			if err != nil {
 				return nil, err
 			}
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	"github.com/argoproj/argo-cd/v2/common"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	testutil "github.com/argoproj/argo-cd/v2/test"
 	argo "github.com/argoproj/argo-cd/v2/util/argo/diff"
@@ -157,6 +158,47 @@ func TestStateDiff(t *testing.T) {
 	}
 }
 
+func TestStateDiff_IgnoreDiffAnnotation(t *testing.T) {
+	stateDiff := func(t *testing.T, annotation string, override bool) (int64, int64) {
+		t.Helper()
+		diffConfig, err := argo.NewDiffConfigBuilder().
+			WithDiffSettings([]v1alpha1.ResourceIgnoreDifferences{}, map[string]v1alpha1.ResourceOverride{}, true, normalizers.IgnoreNormalizerOpts{}).
+			WithTracking("", "").
+			WithNoCache().
+			WithIgnoreAnnotationOverride(override).
+			Build()
+		require.NoError(t, err)
+		live := testutil.YamlToUnstructured(testdata.LiveDeploymentWithManagedReplicaYaml)
+		live.SetAnnotations(map[string]string{common.AnnotationIgnoreDiff: annotation})
+
+		result, err := argo.StateDiff(live, testutil.YamlToUnstructured(testdata.DesiredDeploymentYaml), diffConfig)
+		require.NoError(t, err)
+		normalizedReplicas, _, err := unstructured.NestedFloat64(testutil.YamlToUnstructured(string(result.NormalizedLive)).Object, "spec", "replicas")
+		require.NoError(t, err)
+		predictedReplicas, _, err := unstructured.NestedFloat64(testutil.YamlToUnstructured(string(result.PredictedLive)).Object, "spec", "replicas")
+		require.NoError(t, err)
+		return int64(normalizedReplicas), int64(predictedReplicas)
+	}
+
+	for _, annotation := range []string{"spec.replicas", "metadata.labels.app, spec.replicas", `["spec.replicas"]`, "/spec/replicas"} {
+		t.Run(annotation, func(t *testing.T) {
+			normalized, predicted := stateDiff(t, annotation, false)
+			assert.Equal(t, int64(1), normalized)
+			assert.Equal(t, int64(1), predicted)
+		})
+	}
+	t.Run("Override", func(t *testing.T) {
+		normalized, predicted := stateDiff(t, "spec.replicas", true)
+		assert.Equal(t, int64(2), normalized)
+		assert.Equal(t, int64(3), predicted)
+	})
+	t.Run("Invalid", func(t *testing.T) {
+		normalized, predicted := stateDiff(t, `["spec.replicas"`, false)
+		assert.Equal(t, int64(2), normalized)
+		assert.Equal(t, int64(3), predicted)
+	})
+}
+
 func TestDiffConfigBuilder(t *testing.T) {
 	type fixture struct {
 		ignores        []v1alpha1.ResourceIgnoreDifferences
@@ -1,8 +1,14 @@
 package diff
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/argoproj/argo-cd/v2/common"
 	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/argoproj/argo-cd/v2/util/glob"
 )
@@ -105,6 +111,73 @@ func mergeIgnoreDifferences(from *IgnoreDifference, target *IgnoreDifference) {
 	}
 }
 
+// annotationIgnores returns the ignore difference configurations of the paths listed in the ignore-diff annotation
+// of the given live resources
+func annotationIgnores(lives []*unstructured.Unstructured) []v1alpha1.ResourceIgnoreDifferences {
+	var ignores []v1alpha1.ResourceIgnoreDifferences
+	for _, live := range lives {
+		if live == nil {
+			continue
+		}
+		value, ok := live.GetAnnotations()[common.AnnotationIgnoreDiff]
+		if !ok {
+			continue
+		}
+		pointers, err := ParseIgnoreDiffAnnotation(value)
+		if err != nil {
+			log.Warnf("Ignoring invalid annotation %s of %s %s/%s: %v", common.AnnotationIgnoreDiff, live.GetKind(), live.GetNamespace(), live.GetName(), err)
+			continue
+		}
+		if len(pointers) == 0 {
+			continue
+		}
+		gvk := live.GroupVersionKind()
+		ignores = append(ignores, v1alpha1.ResourceIgnoreDifferences{
+			Group:        gvk.Group,
+			Kind:         gvk.Kind,
+			Name:         live.GetName(),
+			Namespace:    live.GetNamespace(),
+			JSONPointers: pointers,
+		})
+	}
+	return ignores
+}
+
+// ParseIgnoreDiffAnnotation returns the JSON pointers of the paths listed in the value of the ignore-diff annotation.
+// The paths are either comma-separated or a JSON array, and use dots to separate fields, e.g. spec.replicas, unless
+// they are JSON pointers already, e.g. /spec/replicas.
+func ParseIgnoreDiffAnnotation(value string) ([]string, error) {
+	var paths []string
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "[") {
+		if err := json.Unmarshal([]byte(value), &paths); err != nil {
+			return nil, fmt.Errorf("failed to parse paths: %w", err)
+		}
+	} else {
+		paths = strings.Split(value, ",")
+	}
+	var pointers []string
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		if strings.HasPrefix(path, "/") {
+			pointers = append(pointers, path)
+			continue
+		}
+		fields := strings.Split(path, ".")
+		for i, field := range fields {
+			if field == "" {
+				return nil, fmt.Errorf("invalid path %q", path)
+			}
+			fields[i] = strings.NewReplacer("~", "~0", "/", "~1").Replace(field)
+		}
+		pointers = append(pointers, "/"+strings.Join(fields, "/"))
+	}
+	return pointers, nil
+}
+
 func contains(slice []string, e string) bool {
 	for _, s := range slice {
 		if s == e {
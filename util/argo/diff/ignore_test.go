@@ -219,3 +219,31 @@ func TestIgnoreDiffConfig_HasIgnoreDifference(t *testing.T) {
 		assert.ElementsMatch(t, expectedJQPath, actual.JQPathExpressions)
 	})
 }
+
+func TestParseIgnoreDiffAnnotation(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected []string
+		wantErr  bool
+	}{
+		{name: "Empty", value: "", expected: nil},
+		{name: "Single", value: "spec.replicas", expected: []string{"/spec/replicas"}},
+		{name: "CommaSeparated", value: "spec.replicas, metadata.labels.app", expected: []string{"/spec/replicas", "/metadata/labels/app"}},
+		{name: "JSONArray", value: `["spec.replicas", "/data/foo~1bar"]`, expected: []string{"/spec/replicas", "/data/foo~1bar"}},
+		{name: "EscapedSegment", value: "metadata.annotations.a/b~c", expected: []string{"/metadata/annotations/a~1b~0c"}},
+		{name: "EmptySegment", value: "spec..replicas", wantErr: true},
+		{name: "InvalidJSON", value: `["spec.replicas"`, wantErr: true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pointers, err := diff.ParseIgnoreDiffAnnotation(tc.value)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, pointers)
+		})
+	}
+}
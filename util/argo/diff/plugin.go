@@ -0,0 +1,129 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/argoproj/gitops-engine/pkg/diff"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// DiffResult is the result of the diff of a resource: whether it is modified, and its normalized live and predicted
+// live states, which are displayed as its diff.
+type DiffResult = diff.DiffResult
+
+// DiffPlugin calculates the diff of the resources of the kinds it is registered for in the argocd-diff-plugins
+// ConfigMap, instead of the default diff algorithm.
+type DiffPlugin interface {
+	// Diff returns the diff between the live and the desired states of a resource, both JSON-encoded after the
+	// normalizations of the Application and of the system-level configuration were applied.
+	Diff(live, desired []byte) (*DiffResult, error)
+}
+
+var (
+	diffPluginsLock sync.RWMutex
+	diffPlugins     = map[string]DiffPlugin{
+		RedactSecretValuesPluginName: RedactSecretValuesPlugin{},
+	}
+)
+
+// RegisterDiffPlugin registers a diff plugin under the given name, which the argocd-diff-plugins ConfigMap refers to.
+// It replaces any plugin previously registered under the same name.
+func RegisterDiffPlugin(name string, plugin DiffPlugin) {
+	diffPluginsLock.Lock()
+	defer diffPluginsLock.Unlock()
+	diffPlugins[name] = plugin
+}
+
+// GetDiffPlugin returns the diff plugin registered under the given name.
+func GetDiffPlugin(name string) (DiffPlugin, bool) {
+	diffPluginsLock.RLock()
+	defer diffPluginsLock.RUnlock()
+	plugin, ok := diffPlugins[name]
+	return plugin, ok
+}
+
+// resolveDiffPlugins returns the registered diff plugins of the given names, by the kind of the resources they diff.
+func resolveDiffPlugins(names map[schema.GroupKind]string) (map[schema.GroupKind]DiffPlugin, error) {
+	plugins := make(map[schema.GroupKind]DiffPlugin, len(names))
+	var unknown []string
+	for gk, name := range names {
+		plugin, ok := GetDiffPlugin(name)
+		if !ok {
+			unknown = append(unknown, fmt.Sprintf("%q for %s", name, gk.String()))
+			continue
+		}
+		plugins[gk] = plugin
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown diff plugins %v", unknown)
+	}
+	return plugins, nil
+}
+
+// diffResource calculates the diff of a resource with the diff plugin of its kind, or with the default diff algorithm
+// if there is none. Resources to create or to prune are always diffed with the default algorithm.
+func diffResource(config, live *unstructured.Unstructured, plugins map[schema.GroupKind]DiffPlugin, normalizer diff.Normalizer, opts ...diff.Option) (*diff.DiffResult, error) {
+	if config == nil || live == nil {
+		return diff.Diff(config, live, opts...)
+	}
+	plugin, ok := plugins[config.GroupVersionKind().GroupKind()]
+	if !ok {
+		return diff.Diff(config, live, opts...)
+	}
+	config, live = config.DeepCopy(), live.DeepCopy()
+	for _, obj := range []*unstructured.Unstructured{config, live} {
+		if err := normalizer.Normalize(obj); err != nil {
+			return nil, fmt.Errorf("failed to normalize %s: %w", resourceKeyString(obj), err)
+		}
+	}
+	liveData, err := json.Marshal(live)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal live state of %s: %w", resourceKeyString(live), err)
+	}
+	desiredData, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal desired state of %s: %w", resourceKeyString(config), err)
+	}
+	res, err := plugin.Diff(liveData, desiredData)
+	if err != nil {
+		return nil, fmt.Errorf("diff plugin failed for %s: %w", resourceKeyString(live), err)
+	}
+	if res == nil {
+		return nil, fmt.Errorf("diff plugin returned no result for %s", resourceKeyString(live))
+	}
+	return res, nil
+}
+
+// resourceKeyString returns the key of the resource in the group/kind/namespace/name format
+func resourceKeyString(obj *unstructured.Unstructured) string {
+	key := kube.GetResourceKey(obj)
+	return key.String()
+}
+
+// diffArray calculates the diffs of the resources of the config and live arrays, which must have the same length.
+func diffArray(configArray, liveArray []*unstructured.Unstructured, plugins map[schema.GroupKind]DiffPlugin, normalizer diff.Normalizer, opts ...diff.Option) (*diff.DiffResultList, error) {
+	numItems := len(configArray)
+	if len(liveArray) != numItems {
+		return nil, fmt.Errorf("left and right arrays have mismatched lengths")
+	}
+	diffResultList := diff.DiffResultList{
+		Diffs: make([]diff.DiffResult, numItems),
+	}
+	for i := 0; i < numItems; i++ {
+		res, err := diffResource(configArray[i], liveArray[i], plugins, normalizer, opts...)
+		if err != nil {
+			return nil, err
+		}
+		diffResultList.Diffs[i] = *res
+		if res.Modified {
+			diffResultList.Modified = true
+		}
+	}
+	return &diffResultList, nil
+}
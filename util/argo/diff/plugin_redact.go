@@ -0,0 +1,110 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// RedactSecretValuesPluginName is the name of the RedactSecretValuesPlugin in the argocd-diff-plugins ConfigMap
+const RedactSecretValuesPluginName = "redact-secret-values"
+
+// redactedValue replaces the secret values in the diffs of the RedactSecretValuesPlugin
+const redactedValue = "++++++++"
+
+// secretValueFields are the fields whose values are redacted by the RedactSecretValuesPlugin: the data of Secrets, and
+// the encrypted data of resources like SealedSecrets.
+var secretValueFields = [][]string{{"data"}, {"stringData"}, {"spec", "encryptedData"}}
+
+// RedactSecretValuesPlugin diffs resources holding secret values by their keys only. The values are redacted, so
+// they are neither displayed nor compared, which suits resources like SealedSecrets whose values are encrypted anew
+// every time they are generated. The predicted live state is the live state, over which the fields of the desired
+// state are merged, and whose secret data holds the keys of the desired state.
+type RedactSecretValuesPlugin struct{}
+
+// Diff implements DiffPlugin
+func (RedactSecretValuesPlugin) Diff(live, desired []byte) (*DiffResult, error) {
+	var liveObj, desiredObj map[string]interface{}
+	if err := json.Unmarshal(live, &liveObj); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal live state: %w", err)
+	}
+	if err := json.Unmarshal(desired, &desiredObj); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal desired state: %w", err)
+	}
+	// the API server merges stringData into data
+	if stringData, ok := desiredObj["stringData"].(map[string]interface{}); ok {
+		data, _ := desiredObj["data"].(map[string]interface{})
+		if data == nil {
+			data = map[string]interface{}{}
+		}
+		for key, value := range stringData {
+			data[key] = value
+		}
+		desiredObj["data"] = data
+		delete(desiredObj, "stringData")
+	}
+	redactSecretValues(liveObj)
+	redactSecretValues(desiredObj)
+
+	predictedObj := mergeObjects(liveObj, desiredObj, nil)
+	normalizedLive, err := json.Marshal(liveObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal normalized live state: %w", err)
+	}
+	predictedLive, err := json.Marshal(predictedObj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal predicted live state: %w", err)
+	}
+	return &DiffResult{
+		Modified:       !reflect.DeepEqual(liveObj, predictedObj),
+		NormalizedLive: normalizedLive,
+		PredictedLive:  predictedLive,
+	}, nil
+}
+
+// redactSecretValues replaces the values of the secret value fields of the object
+func redactSecretValues(obj map[string]interface{}) {
+	for _, field := range secretValueFields {
+		parent := obj
+		for _, name := range field[:len(field)-1] {
+			parent, _ = parent[name].(map[string]interface{})
+		}
+		values, ok := parent[field[len(field)-1]].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key := range values {
+			values[key] = redactedValue
+		}
+	}
+}
+
+// isSecretValueField returns whether the field at the given path is a secret value field, which the desired state
+// replaces as a whole rather than being merged, so that removed keys are removed from the predicted live state
+func isSecretValueField(path []string) bool {
+	for _, field := range secretValueFields {
+		if reflect.DeepEqual(field, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeObjects returns a copy of the live object over which the fields of the desired object are merged recursively
+func mergeObjects(live, desired map[string]interface{}, path []string) map[string]interface{} {
+	merged := make(map[string]interface{}, len(live))
+	for key, value := range live {
+		merged[key] = value
+	}
+	for key, value := range desired {
+		fieldPath := append(append([]string{}, path...), key)
+		desiredMap, desiredIsMap := value.(map[string]interface{})
+		liveMap, liveIsMap := merged[key].(map[string]interface{})
+		if desiredIsMap && liveIsMap && !isSecretValueField(fieldPath) {
+			merged[key] = mergeObjects(liveMap, desiredMap, fieldPath)
+		} else {
+			merged[key] = value
+		}
+	}
+	return merged
+}
@@ -0,0 +1,181 @@
+package diff_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	argo "github.com/argoproj/argo-cd/v2/util/argo/diff"
+	"github.com/argoproj/argo-cd/v2/util/argo/normalizers"
+)
+
+// recordingDiffPlugin records the states it diffs, and returns its result
+type recordingDiffPlugin struct {
+	live, desired []byte
+	result        *argo.DiffResult
+	err           error
+}
+
+func (p *recordingDiffPlugin) Diff(live, desired []byte) (*argo.DiffResult, error) {
+	p.live, p.desired = live, desired
+	return p.result, p.err
+}
+
+func newPluginTestObj(kind string, data map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "bitnami.com/v1alpha1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": "credentials", "namespace": "default"},
+		"spec":       map[string]interface{}{"encryptedData": data},
+	}}
+	return obj
+}
+
+func pluginDiffConfig(t *testing.T, ignores []v1alpha1.ResourceIgnoreDifferences, plugins map[schema.GroupKind]string) argo.DiffConfig {
+	t.Helper()
+	diffConfig, err := argo.NewDiffConfigBuilder().
+		WithDiffSettings(ignores, nil, true, normalizers.IgnoreNormalizerOpts{}).
+		WithDiffPlugins(plugins).
+		WithNoCache().
+		Build()
+	require.NoError(t, err)
+	return diffConfig
+}
+
+func TestRegisterDiffPlugin(t *testing.T) {
+	_, ok := argo.GetDiffPlugin("test-register")
+	assert.False(t, ok)
+
+	plugin := &recordingDiffPlugin{}
+	argo.RegisterDiffPlugin("test-register", plugin)
+	registered, ok := argo.GetDiffPlugin("test-register")
+	require.True(t, ok)
+	assert.Same(t, plugin, registered)
+
+	builtin, ok := argo.GetDiffPlugin(argo.RedactSecretValuesPluginName)
+	require.True(t, ok)
+	assert.IsType(t, argo.RedactSecretValuesPlugin{}, builtin)
+}
+
+func TestStateDiff_DiffPlugin(t *testing.T) {
+	sealedSecret := schema.GroupKind{Group: "bitnami.com", Kind: "SealedSecret"}
+
+	t.Run("Invoked", func(t *testing.T) {
+		plugin := &recordingDiffPlugin{result: &argo.DiffResult{Modified: true, NormalizedLive: []byte("live"), PredictedLive: []byte("predicted")}}
+		argo.RegisterDiffPlugin("test-invoked", plugin)
+		ignores := []v1alpha1.ResourceIgnoreDifferences{{Group: "bitnami.com", Kind: "SealedSecret", JSONPointers: []string{"/spec/template"}}}
+		live := newPluginTestObj("SealedSecret", map[string]interface{}{"password": "AgBy3i"})
+		require.NoError(t, unstructured.SetNestedField(live.Object, "live", "spec", "template", "type"))
+		desired := newPluginTestObj("SealedSecret", map[string]interface{}{"password": "AgCx8k"})
+
+		res, err := argo.StateDiff(live, desired, pluginDiffConfig(t, ignores, map[schema.GroupKind]string{sealedSecret: "test-invoked"}))
+		require.NoError(t, err)
+		assert.Equal(t, *plugin.result, res)
+		// the plugin gets the normalized states
+		assert.JSONEq(t, `{"apiVersion":"bitnami.com/v1alpha1","kind":"SealedSecret","metadata":{"name":"credentials","namespace":"default"},"spec":{"encryptedData":{"password":"AgBy3i"}}}`, string(plugin.live))
+		assert.JSONEq(t, `{"apiVersion":"bitnami.com/v1alpha1","kind":"SealedSecret","metadata":{"name":"credentials","namespace":"default"},"spec":{"encryptedData":{"password":"AgCx8k"}}}`, string(plugin.desired))
+	})
+
+	t.Run("OtherKinds", func(t *testing.T) {
+		plugin := &recordingDiffPlugin{err: errors.New("unexpected")}
+		argo.RegisterDiffPlugin("test-other-kinds", plugin)
+		live := newPluginTestObj("SealedSecretTemplate", map[string]interface{}{"password": "AgBy3i"})
+		desired := newPluginTestObj("SealedSecretTemplate", map[string]interface{}{"password": "AgCx8k"})
+
+		res, err := argo.StateDiff(live, desired, pluginDiffConfig(t, nil, map[schema.GroupKind]string{sealedSecret: "test-other-kinds"}))
+		require.NoError(t, err)
+		assert.True(t, res.Modified)
+		assert.Nil(t, plugin.live)
+	})
+
+	t.Run("Failed", func(t *testing.T) {
+		argo.RegisterDiffPlugin("test-failed", &recordingDiffPlugin{err: errors.New("boom")})
+		live := newPluginTestObj("SealedSecret", map[string]interface{}{"password": "AgBy3i"})
+
+		_, err := argo.StateDiff(live, live.DeepCopy(), pluginDiffConfig(t, nil, map[schema.GroupKind]string{sealedSecret: "test-failed"}))
+		assert.ErrorContains(t, err, "diff plugin failed for bitnami.com/SealedSecret/default/credentials: boom")
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := argo.NewDiffConfigBuilder().
+			WithDiffSettings(nil, nil, true, normalizers.IgnoreNormalizerOpts{}).
+			WithDiffPlugins(map[schema.GroupKind]string{sealedSecret: "missing"}).
+			WithNoCache().
+			Build()
+		assert.ErrorContains(t, err, `unknown diff plugins ["missing" for SealedSecret.bitnami.com]`)
+	})
+}
+
+func TestRedactSecretValuesPlugin(t *testing.T) {
+	diffConfig := pluginDiffConfig(t, nil, map[schema.GroupKind]string{
+		{Group: "bitnami.com", Kind: "SealedSecret"}: argo.RedactSecretValuesPluginName,
+		{Kind: "Secret"}: argo.RedactSecretValuesPluginName,
+	})
+	predicted := func(t *testing.T, res argo.DiffResult) map[string]interface{} {
+		t.Helper()
+		var obj map[string]interface{}
+		require.NoError(t, json.Unmarshal(res.PredictedLive, &obj))
+		return obj
+	}
+
+	t.Run("ValuesChanged", func(t *testing.T) {
+		live := newPluginTestObj("SealedSecret", map[string]interface{}{"password": "AgBy3i"})
+		live.SetUID("a1b2c3")
+		desired := newPluginTestObj("SealedSecret", map[string]interface{}{"password": "AgCx8k"})
+
+		res, err := argo.StateDiff(live, desired, diffConfig)
+		require.NoError(t, err)
+		assert.False(t, res.Modified)
+		assert.NotContains(t, string(res.NormalizedLive), "AgBy3i")
+		assert.NotContains(t, string(res.PredictedLive), "AgCx8k")
+	})
+
+	t.Run("KeysChanged", func(t *testing.T) {
+		live := newPluginTestObj("SealedSecret", map[string]interface{}{"password": "AgBy3i", "token": "AgDq2z"})
+		desired := newPluginTestObj("SealedSecret", map[string]interface{}{"password": "AgCx8k", "username": "AgA9mn"})
+
+		res, err := argo.StateDiff(live, desired, diffConfig)
+		require.NoError(t, err)
+		assert.True(t, res.Modified)
+		encryptedData, _, _ := unstructured.NestedMap(predicted(t, res), "spec", "encryptedData")
+		assert.Equal(t, map[string]interface{}{"password": "++++++++", "username": "++++++++"}, encryptedData)
+	})
+
+	t.Run("OtherFieldsChanged", func(t *testing.T) {
+		live := newPluginTestObj("SealedSecret", map[string]interface{}{"password": "AgBy3i"})
+		desired := newPluginTestObj("SealedSecret", map[string]interface{}{"password": "AgCx8k"})
+		desired.SetLabels(map[string]string{"team": "payments"})
+
+		res, err := argo.StateDiff(live, desired, diffConfig)
+		require.NoError(t, err)
+		assert.True(t, res.Modified)
+		labels, _, _ := unstructured.NestedStringMap(predicted(t, res), "metadata", "labels")
+		assert.Equal(t, map[string]string{"team": "payments"}, labels)
+	})
+
+	t.Run("StringData", func(t *testing.T) {
+		live := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1", "kind": "Secret",
+			"metadata": map[string]interface{}{"name": "credentials", "namespace": "default"},
+			"data":     map[string]interface{}{"password": "czNjcjN0", "username": "YWRtaW4="},
+		}}
+		desired := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1", "kind": "Secret",
+			"metadata":   map[string]interface{}{"name": "credentials", "namespace": "default"},
+			"data":       map[string]interface{}{"username": "cm9vdA=="},
+			"stringData": map[string]interface{}{"password": "rotated"},
+		}}
+
+		res, err := argo.StateDiff(live, desired, diffConfig)
+		require.NoError(t, err)
+		assert.False(t, res.Modified)
+		assert.NotContains(t, string(res.PredictedLive), "stringData")
+		assert.NotContains(t, string(res.PredictedLive), "rotated")
+	})
+}
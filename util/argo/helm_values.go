@@ -0,0 +1,88 @@
+package argo
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// GetHelmValuesFrom reads the Helm values referenced by the valuesFrom field of the given source from the ConfigMaps and
+// Secrets of the destination cluster, in merge order. The namespaces of the referenced ConfigMaps and Secrets must be
+// permitted destinations of the project, and default to the destination namespace.
+func GetHelmValuesFrom(ctx context.Context, kubeClient kubernetes.Interface, source argoappv1.ApplicationSource, dest argoappv1.ApplicationDestination, proj *argoappv1.AppProject, projectClusters func(project string) ([]*argoappv1.Cluster, error)) ([]string, error) {
+	if source.Helm == nil || source.Helm.ValuesFrom == nil {
+		return nil, nil
+	}
+	valuesFrom := source.Helm.ValuesFrom
+	var values []string
+	if ref := valuesFrom.ConfigMapKeyRef; ref != nil {
+		namespace, err := getHelmValuesNamespace(ref, dest, proj, projectClusters)
+		if err != nil {
+			return nil, err
+		}
+		cm, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting Helm values ConfigMap %s/%s: %w", namespace, ref.Name, err)
+		}
+		data, ok := cm.Data[ref.Key]
+		if !ok {
+			return nil, fmt.Errorf("key %s not found in Helm values ConfigMap %s/%s", ref.Key, namespace, ref.Name)
+		}
+		values = append(values, data)
+	}
+	if ref := valuesFrom.SecretKeyRef; ref != nil {
+		namespace, err := getHelmValuesNamespace(ref, dest, proj, projectClusters)
+		if err != nil {
+			return nil, err
+		}
+		secret, err := kubeClient.CoreV1().Secrets(namespace).Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("error getting Helm values Secret %s/%s: %w", namespace, ref.Name, err)
+		}
+		data, ok := secret.Data[ref.Key]
+		if !ok {
+			return nil, fmt.Errorf("key %s not found in Helm values Secret %s/%s", ref.Key, namespace, ref.Name)
+		}
+		values = append(values, string(data))
+	}
+	return values, nil
+}
+
+// GetHelmValueFilesSuffix returns the suffix of the Helm value files of the given source, which is the value of the label
+// of the destination cluster named by the valueFilesSuffix field of the source
+func GetHelmValueFilesSuffix(source argoappv1.ApplicationSource, cluster *argoappv1.Cluster) string {
+	if source.Helm == nil || source.Helm.ValueFilesSuffix == "" || cluster == nil {
+		return ""
+	}
+	return cluster.Labels[source.Helm.ValueFilesSuffix]
+}
+
+// GetHelmDestinationClusterName returns the name of the destination cluster if the given source selects Helm value files
+// by the name of the destination cluster with autoValuesFile
+func GetHelmDestinationClusterName(source argoappv1.ApplicationSource, cluster *argoappv1.Cluster) string {
+	if source.Helm == nil || !source.Helm.AutoValuesFile || cluster == nil {
+		return ""
+	}
+	return cluster.Name
+}
+
+// getHelmValuesNamespace returns the namespace of the given reference, and checks that it is a permitted destination
+// of the project
+func getHelmValuesNamespace(ref *argoappv1.HelmValuesKeyRef, dest argoappv1.ApplicationDestination, proj *argoappv1.AppProject, projectClusters func(project string) ([]*argoappv1.Cluster, error)) (string, error) {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = dest.Namespace
+	}
+	permitted, err := proj.IsDestinationPermitted(argoappv1.ApplicationDestination{Server: dest.Server, Name: dest.Name, Namespace: namespace}, projectClusters)
+	if err != nil {
+		return "", fmt.Errorf("error checking destination permissions of Helm values %s/%s: %w", namespace, ref.Name, err)
+	}
+	if !permitted {
+		return "", fmt.Errorf("namespace %s of the Helm values %s is not permitted in project '%s'", namespace, ref.Name, proj.Name)
+	}
+	return namespace, nil
+}
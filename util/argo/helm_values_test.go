@@ -0,0 +1,99 @@
+package argo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func TestGetHelmValuesFrom(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "values", Namespace: "default"},
+			Data:       map[string]string{"values.yaml": "image:\n  registry: registry.example.com\n"},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "values", Namespace: "secrets"},
+			Data:       map[string][]byte{"values.yaml": []byte("password: secret\n")},
+		},
+	)
+	dest := argoappv1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: "default"}
+	proj := &argoappv1.AppProject{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: argoappv1.AppProjectSpec{Destinations: []argoappv1.ApplicationDestination{
+			{Server: "*", Namespace: "default"},
+			{Server: "*", Namespace: "secrets"},
+		}},
+	}
+	projectClusters := func(project string) ([]*argoappv1.Cluster, error) {
+		return nil, nil
+	}
+	sourceWith := func(valuesFrom *argoappv1.HelmValuesFrom) argoappv1.ApplicationSource {
+		return argoappv1.ApplicationSource{Helm: &argoappv1.ApplicationSourceHelm{ValuesFrom: valuesFrom}}
+	}
+
+	t.Run("No valuesFrom", func(t *testing.T) {
+		values, err := GetHelmValuesFrom(context.Background(), kubeClient, argoappv1.ApplicationSource{}, dest, proj, projectClusters)
+		require.NoError(t, err)
+		assert.Empty(t, values)
+	})
+
+	t.Run("ConfigMap and Secret", func(t *testing.T) {
+		values, err := GetHelmValuesFrom(context.Background(), kubeClient, sourceWith(&argoappv1.HelmValuesFrom{
+			ConfigMapKeyRef: &argoappv1.HelmValuesKeyRef{Name: "values", Key: "values.yaml"},
+			SecretKeyRef:    &argoappv1.HelmValuesKeyRef{Namespace: "secrets", Name: "values", Key: "values.yaml"},
+		}), dest, proj, projectClusters)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"image:\n  registry: registry.example.com\n", "password: secret\n"}, values)
+	})
+
+	t.Run("Missing ConfigMap", func(t *testing.T) {
+		_, err := GetHelmValuesFrom(context.Background(), kubeClient, sourceWith(&argoappv1.HelmValuesFrom{
+			ConfigMapKeyRef: &argoappv1.HelmValuesKeyRef{Name: "missing", Key: "values.yaml"},
+		}), dest, proj, projectClusters)
+		require.ErrorContains(t, err, "error getting Helm values ConfigMap default/missing")
+	})
+
+	t.Run("Missing key", func(t *testing.T) {
+		_, err := GetHelmValuesFrom(context.Background(), kubeClient, sourceWith(&argoappv1.HelmValuesFrom{
+			SecretKeyRef: &argoappv1.HelmValuesKeyRef{Namespace: "secrets", Name: "values", Key: "missing.yaml"},
+		}), dest, proj, projectClusters)
+		require.ErrorContains(t, err, "key missing.yaml not found in Helm values Secret secrets/values")
+	})
+
+	t.Run("Namespace not permitted", func(t *testing.T) {
+		_, err := GetHelmValuesFrom(context.Background(), kubeClient, sourceWith(&argoappv1.HelmValuesFrom{
+			SecretKeyRef: &argoappv1.HelmValuesKeyRef{Namespace: "kube-system", Name: "values", Key: "values.yaml"},
+		}), dest, proj, projectClusters)
+		require.ErrorContains(t, err, "namespace kube-system of the Helm values values is not permitted in project 'default'")
+	})
+}
+
+func TestGetHelmValueFilesSuffix(t *testing.T) {
+	cluster := &argoappv1.Cluster{Labels: map[string]string{"region": "us-east-1"}}
+	sourceWith := func(suffix string) argoappv1.ApplicationSource {
+		return argoappv1.ApplicationSource{Helm: &argoappv1.ApplicationSourceHelm{ValueFilesSuffix: suffix}}
+	}
+	assert.Equal(t, "us-east-1", GetHelmValueFilesSuffix(sourceWith("region"), cluster))
+	assert.Empty(t, GetHelmValueFilesSuffix(sourceWith("zone"), cluster))
+	assert.Empty(t, GetHelmValueFilesSuffix(sourceWith(""), cluster))
+	assert.Empty(t, GetHelmValueFilesSuffix(argoappv1.ApplicationSource{}, cluster))
+}
+
+func TestGetHelmDestinationClusterName(t *testing.T) {
+	cluster := &argoappv1.Cluster{Name: "prod"}
+	sourceWith := func(autoValuesFile bool) argoappv1.ApplicationSource {
+		return argoappv1.ApplicationSource{Helm: &argoappv1.ApplicationSourceHelm{AutoValuesFile: autoValuesFile}}
+	}
+	assert.Equal(t, "prod", GetHelmDestinationClusterName(sourceWith(true), cluster))
+	assert.Empty(t, GetHelmDestinationClusterName(sourceWith(false), cluster))
+	assert.Empty(t, GetHelmDestinationClusterName(sourceWith(true), nil))
+	assert.Empty(t, GetHelmDestinationClusterName(argoappv1.ApplicationSource{}, cluster))
+}
@@ -0,0 +1,55 @@
+package argo
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+// sha256Regex matches a hex encoded SHA256 digest
+var sha256Regex = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// ValidateHTTPSource verifies the URL and SHA256 digest of the given HTTP source. The repository URL of an HTTP source
+// must be its URL, so that the URL is matched against the source repositories of the project.
+func ValidateHTTPSource(source argoappv1.ApplicationSource) error {
+	u, err := url.Parse(source.HTTP.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("URL %q must be an absolute http or https URL", source.HTTP.URL)
+	}
+	if source.RepoURL != source.HTTP.URL {
+		return fmt.Errorf("repoURL %q must be the URL %q of the HTTP source", source.RepoURL, source.HTTP.URL)
+	}
+	if source.HTTP.SHA256 != "" && !sha256Regex.MatchString(source.HTTP.SHA256) {
+		return fmt.Errorf("sha256 %q must be a hex encoded SHA256 digest", source.HTTP.SHA256)
+	}
+	if ref := source.HTTP.CABundleSecret; ref != nil && (ref.SecretName == "" || ref.Key == "") {
+		return fmt.Errorf("caBundleSecret must reference a key of a Secret")
+	}
+	return nil
+}
+
+// GetHTTPSourceCABundle returns the PEM encoded CA bundle referenced by the caBundleSecret of the given HTTP source,
+// or an empty string if the source does not reference one. Only the Secrets of the Argo CD namespace labeled with
+// app.kubernetes.io/part-of=argocd can be referenced.
+func GetHTTPSourceCABundle(settingsMgr *settings.SettingsManager, source argoappv1.ApplicationSource) (string, error) {
+	if !source.IsHTTP() || source.HTTP.CABundleSecret == nil {
+		return "", nil
+	}
+	ref := source.HTTP.CABundleSecret
+	secrets, err := settingsMgr.GetSecretsLister()
+	if err != nil {
+		return "", fmt.Errorf("error getting secrets lister: %w", err)
+	}
+	secret, err := secrets.Secrets(settingsMgr.GetNamespace()).Get(ref.SecretName)
+	if err != nil {
+		return "", fmt.Errorf("error getting CA bundle Secret %s: %w", ref.SecretName, err)
+	}
+	data, ok := secret.Data[ref.Key]
+	if !ok {
+		return "", fmt.Errorf("key %s not found in CA bundle Secret %s", ref.Key, ref.SecretName)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,62 @@
+package argo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/test"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+func TestValidateHTTPSource(t *testing.T) {
+	const installURL = "https://example.com/install.yaml"
+	sourceWith := func(repoURL string, http *argoappv1.ApplicationSourceHTTP) argoappv1.ApplicationSource {
+		return argoappv1.ApplicationSource{RepoURL: repoURL, HTTP: http}
+	}
+
+	require.NoError(t, ValidateHTTPSource(sourceWith(installURL, &argoappv1.ApplicationSourceHTTP{URL: installURL})))
+	require.NoError(t, ValidateHTTPSource(sourceWith(installURL, &argoappv1.ApplicationSourceHTTP{
+		URL:            installURL,
+		SHA256:         "9F86D081884C7D659A2FEAA0C55AD015A3BF4F1B2B0B822CD15D6C15B0F00A08",
+		CABundleSecret: &argoappv1.SecretRef{SecretName: "ca", Key: "ca.crt"},
+	})))
+	require.ErrorContains(t, ValidateHTTPSource(sourceWith("file:///install.yaml", &argoappv1.ApplicationSourceHTTP{URL: "file:///install.yaml"})), "must be an absolute http or https URL")
+	require.ErrorContains(t, ValidateHTTPSource(sourceWith("https://github.com/argoproj/argo-cd", &argoappv1.ApplicationSourceHTTP{URL: installURL})), "must be the URL")
+	require.ErrorContains(t, ValidateHTTPSource(sourceWith(installURL, &argoappv1.ApplicationSourceHTTP{URL: installURL, SHA256: "abc"})), "must be a hex encoded SHA256 digest")
+	require.ErrorContains(t, ValidateHTTPSource(sourceWith(installURL, &argoappv1.ApplicationSourceHTTP{URL: installURL, CABundleSecret: &argoappv1.SecretRef{SecretName: "ca"}})), "must reference a key")
+}
+
+func TestGetHTTPSourceCABundle(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(test.NewFakeConfigMap(), test.NewFakeSecret(), &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "ca",
+			Namespace: test.FakeArgoCDNamespace,
+			Labels:    map[string]string{"app.kubernetes.io/part-of": "argocd"},
+		},
+		Data: map[string][]byte{"ca.crt": []byte("-----BEGIN CERTIFICATE-----")},
+	})
+	settingsMgr := settings.NewSettingsManager(context.Background(), kubeClient, test.FakeArgoCDNamespace)
+	sourceWith := func(ref *argoappv1.SecretRef) argoappv1.ApplicationSource {
+		return argoappv1.ApplicationSource{HTTP: &argoappv1.ApplicationSourceHTTP{URL: "https://example.com/install.yaml", CABundleSecret: ref}}
+	}
+
+	caBundle, err := GetHTTPSourceCABundle(settingsMgr, sourceWith(&argoappv1.SecretRef{SecretName: "ca", Key: "ca.crt"}))
+	require.NoError(t, err)
+	assert.Equal(t, "-----BEGIN CERTIFICATE-----", caBundle)
+
+	caBundle, err = GetHTTPSourceCABundle(settingsMgr, sourceWith(nil))
+	require.NoError(t, err)
+	assert.Empty(t, caBundle)
+
+	_, err = GetHTTPSourceCABundle(settingsMgr, sourceWith(&argoappv1.SecretRef{SecretName: "ca", Key: "tls.crt"}))
+	require.ErrorContains(t, err, "key tls.crt not found")
+	_, err = GetHTTPSourceCABundle(settingsMgr, sourceWith(&argoappv1.SecretRef{SecretName: "missing", Key: "ca.crt"}))
+	require.ErrorContains(t, err, "error getting CA bundle Secret missing")
+}
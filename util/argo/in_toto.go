@@ -0,0 +1,63 @@
+package argo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+const (
+	// InTotoLayoutKey is the key of the signed in-toto layout in the ConfigMap referenced by an in-toto attestation
+	InTotoLayoutKey = "root.layout"
+	// inTotoPublicKeySuffix is the suffix of the keys of the public keys of the layout owners in the ConfigMap
+	inTotoPublicKeySuffix = ".pub"
+)
+
+// ValidateInTotoAttestation verifies the in-toto attestation of the given source. Only the link metadata files of Git
+// sources can be verified, since they are read from the repository of the source.
+func ValidateInTotoAttestation(source argoappv1.ApplicationSource) error {
+	if source.VerifyAttestation.InTotoLayout() == "" {
+		return fmt.Errorf("layout must reference a ConfigMap")
+	}
+	if source.IsHelm() || source.IsInline() || source.IsHTTP() || source.IsOCI() {
+		return fmt.Errorf("only the manifests of Git sources can be verified")
+	}
+	return nil
+}
+
+// GetInTotoLayout returns the signed in-toto layout referenced by the in-toto attestation of the given source, and the
+// PEM encoded public keys of its owners sorted by key name. Empty values are returned if the source does not require
+// an in-toto attestation. Only the ConfigMaps of the Argo CD namespace labeled with app.kubernetes.io/part-of=argocd
+// can be referenced.
+func GetInTotoLayout(settingsMgr *settings.SettingsManager, source argoappv1.ApplicationSource) (string, []string, error) {
+	name := source.VerifyAttestation.InTotoLayout()
+	if name == "" {
+		return "", nil, nil
+	}
+	cm, err := settingsMgr.GetConfigMapByName(name)
+	if err != nil {
+		return "", nil, fmt.Errorf("error getting in-toto layout ConfigMap %s: %w", name, err)
+	}
+	layout, ok := cm.Data[InTotoLayoutKey]
+	if !ok {
+		return "", nil, fmt.Errorf("key %s not found in in-toto layout ConfigMap %s", InTotoLayoutKey, name)
+	}
+	var keyNames []string
+	for key := range cm.Data {
+		if strings.HasSuffix(key, inTotoPublicKeySuffix) {
+			keyNames = append(keyNames, key)
+		}
+	}
+	if len(keyNames) == 0 {
+		return "", nil, fmt.Errorf("no public key with the %s suffix found in in-toto layout ConfigMap %s", inTotoPublicKeySuffix, name)
+	}
+	sort.Strings(keyNames)
+	keys := make([]string, 0, len(keyNames))
+	for _, key := range keyNames {
+		keys = append(keys, cm.Data[key])
+	}
+	return layout, keys, nil
+}
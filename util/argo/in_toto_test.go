@@ -0,0 +1,69 @@
+package argo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/test"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+func sourceWithInTotoLayout(layout string) argoappv1.ApplicationSource {
+	return argoappv1.ApplicationSource{
+		RepoURL:           "https://github.com/argoproj/argocd-example-apps",
+		Path:              "guestbook",
+		VerifyAttestation: &argoappv1.ApplicationSourceVerifyAttestation{InToto: &argoappv1.InTotoAttestation{Layout: layout}},
+	}
+}
+
+func TestValidateInTotoAttestation(t *testing.T) {
+	require.NoError(t, ValidateInTotoAttestation(sourceWithInTotoLayout("layout")))
+	require.ErrorContains(t, ValidateInTotoAttestation(sourceWithInTotoLayout("")), "must reference a ConfigMap")
+
+	source := sourceWithInTotoLayout("layout")
+	source.Chart = "guestbook"
+	require.ErrorContains(t, ValidateInTotoAttestation(source), "only the manifests of Git sources")
+}
+
+func TestGetInTotoLayout(t *testing.T) {
+	newLayoutConfigMap := func(name string, data map[string]string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: test.FakeArgoCDNamespace,
+				Labels:    map[string]string{"app.kubernetes.io/part-of": "argocd"},
+			},
+			Data: data,
+		}
+	}
+	kubeClient := fake.NewSimpleClientset(test.NewFakeConfigMap(), test.NewFakeSecret(),
+		newLayoutConfigMap("layout", map[string]string{"root.layout": "{}", "owner-b.pub": "key-b", "owner-a.pub": "key-a", "README": "ignored"}),
+		newLayoutConfigMap("no-keys", map[string]string{"root.layout": "{}"}),
+		newLayoutConfigMap("no-layout", map[string]string{"owner.pub": "key"}),
+	)
+	settingsMgr := settings.NewSettingsManager(context.Background(), kubeClient, test.FakeArgoCDNamespace)
+
+	layout, keys, err := GetInTotoLayout(settingsMgr, sourceWithInTotoLayout("layout"))
+	require.NoError(t, err)
+	assert.Equal(t, "{}", layout)
+	assert.Equal(t, []string{"key-a", "key-b"}, keys)
+
+	layout, keys, err = GetInTotoLayout(settingsMgr, argoappv1.ApplicationSource{})
+	require.NoError(t, err)
+	assert.Empty(t, layout)
+	assert.Empty(t, keys)
+
+	_, _, err = GetInTotoLayout(settingsMgr, sourceWithInTotoLayout("no-keys"))
+	require.ErrorContains(t, err, "no public key with the .pub suffix")
+	_, _, err = GetInTotoLayout(settingsMgr, sourceWithInTotoLayout("no-layout"))
+	require.ErrorContains(t, err, "key root.layout not found")
+	_, _, err = GetInTotoLayout(settingsMgr, sourceWithInTotoLayout("missing"))
+	require.ErrorContains(t, err, "error getting in-toto layout ConfigMap missing")
+}
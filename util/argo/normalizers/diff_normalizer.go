@@ -141,6 +141,21 @@ func NewIgnoreNormalizer(ignore []v1alpha1.ResourceIgnoreDifferences, overrides
 	patches := make([]normalizerPatch, 0)
 	for i := range ignore {
 		for _, path := range ignore[i].JSONPointers {
+			if isWildcardJSONPointer(path) {
+				segments, err := parseWildcardJSONPointer(path)
+				if err != nil {
+					return nil, err
+				}
+				patches = append(patches, &wildcardJSONPointerPatch{
+					baseNormalizerPatch: baseNormalizerPatch{
+						groupKind: schema.GroupKind{Group: ignore[i].Group, Kind: ignore[i].Kind},
+						name:      ignore[i].Name,
+						namespace: ignore[i].Namespace,
+					},
+					segments: segments,
+				})
+				continue
+			}
 			patchData, err := json.Marshal([]map[string]string{{"op": "remove", "path": path}})
 			if err != nil {
 				return nil, err
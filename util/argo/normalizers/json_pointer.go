@@ -0,0 +1,132 @@
+package normalizers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// wildcardSegment matches any single segment of a JSON pointer: a field of an object or an element of an array
+	wildcardSegment = "*"
+	// recursiveWildcardSegment matches any number of segments of a JSON pointer, including none
+	recursiveWildcardSegment = "**"
+)
+
+// isWildcardJSONPointer returns whether any segment of the JSON pointer is a wildcard
+func isWildcardJSONPointer(pointer string) bool {
+	for _, segment := range strings.Split(pointer, "/") {
+		if segment == wildcardSegment || segment == recursiveWildcardSegment {
+			return true
+		}
+	}
+	return false
+}
+
+// parseWildcardJSONPointer returns the unescaped segments of a JSON pointer with wildcards. Consecutive recursive
+// wildcards are collapsed since they match the same paths.
+func parseWildcardJSONPointer(pointer string) ([]string, error) {
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("JSON pointer %q must start with /", pointer)
+	}
+	unescape := strings.NewReplacer("~1", "/", "~0", "~")
+	var segments []string
+	for _, segment := range strings.Split(pointer[1:], "/") {
+		if segment == recursiveWildcardSegment && len(segments) > 0 && segments[len(segments)-1] == recursiveWildcardSegment {
+			continue
+		}
+		if segment != wildcardSegment && segment != recursiveWildcardSegment {
+			segment = unescape.Replace(segment)
+		}
+		segments = append(segments, segment)
+	}
+	return segments, nil
+}
+
+// wildcardJSONPointerPatch removes the fields matching a JSON pointer with wildcards. A `*` segment matches any field
+// of an object or element of an array, and a `**` segment matches any subtree, e.g. /spec/**/image matches the image
+// field at any depth under spec. Other segments match literally.
+type wildcardJSONPointerPatch struct {
+	baseNormalizerPatch
+	segments []string
+}
+
+func (np *wildcardJSONPointerPatch) Apply(data []byte) ([]byte, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	doc, removed := removeJSONPointerMatches(doc, np.segments)
+	if removed {
+		// the pointer matches the whole document, which is never removed
+		return data, nil
+	}
+	return json.Marshal(doc)
+}
+
+// removeJSONPointerMatches removes the values of the node matching the segments, and returns the updated node and
+// whether the node itself matches and must be removed by its parent
+func removeJSONPointerMatches(node interface{}, segments []string) (interface{}, bool) {
+	if len(segments) == 0 {
+		return node, true
+	}
+	segment, rest := segments[0], segments[1:]
+	switch segment {
+	case recursiveWildcardSegment:
+		// the subtree is either empty, or made of a child of the node followed by any subtree
+		n, removed := removeJSONPointerMatches(node, rest)
+		if removed {
+			return nil, true
+		}
+		return removeChildMatches(n, segments), false
+	case wildcardSegment:
+		return removeChildMatches(node, rest), false
+	}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[segment]
+		if !ok {
+			return node, false
+		}
+		if child, removed := removeJSONPointerMatches(child, rest); removed {
+			delete(n, segment)
+		} else {
+			n[segment] = child
+		}
+	case []interface{}:
+		i, err := strconv.Atoi(segment)
+		if err != nil || i < 0 || i >= len(n) || strconv.Itoa(i) != segment {
+			return node, false
+		}
+		child, removed := removeJSONPointerMatches(n[i], rest)
+		if removed {
+			return append(n[:i:i], n[i+1:]...), false
+		}
+		n[i] = child
+	}
+	return node, false
+}
+
+// removeChildMatches removes the values matching the segments from every child of the node
+func removeChildMatches(node interface{}, segments []string) interface{} {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, child := range n {
+			if child, removed := removeJSONPointerMatches(child, segments); removed {
+				delete(n, key)
+			} else {
+				n[key] = child
+			}
+		}
+	case []interface{}:
+		kept := n[:0]
+		for _, child := range n {
+			if child, removed := removeJSONPointerMatches(child, segments); !removed {
+				kept = append(kept, child)
+			}
+		}
+		return kept
+	}
+	return node
+}
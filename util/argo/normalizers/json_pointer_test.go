@@ -0,0 +1,198 @@
+package normalizers
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+const wildcardTestPod = `{
+  "apiVersion": "v1",
+  "kind": "Pod",
+  "metadata": {
+    "name": "guestbook",
+    "annotations": {
+      "a.b/c": "slash",
+      "a~b": "tilde",
+      "prometheus.io/scrape": "true",
+      "$(VAR)": "parens",
+      "x[0]+": "brackets",
+      "image*": "star",
+      "01": "number"
+    }
+  },
+  "spec": {
+    "containers": [
+      {"name": "guestbook", "image": "guestbook:v1", "ports": [{"containerPort": 80, "name": "http"}]},
+      {"name": "sidecar", "image": "sidecar:v1", "env": [{"name": "IMAGE", "value": "image"}]}
+    ],
+    "volumes": [
+      {"name": "config", "configMap": {"name": "config", "items": [{"key": "image", "path": "image"}]}}
+    ]
+  }
+}`
+
+func normalizeWildcardTestPod(t *testing.T, pointers ...string) map[string]interface{} {
+	t.Helper()
+	normalizer, err := NewIgnoreNormalizer([]v1alpha1.ResourceIgnoreDifferences{{Kind: "Pod", JSONPointers: pointers}}, nil, IgnoreNormalizerOpts{})
+	require.NoError(t, err)
+	pod := &unstructured.Unstructured{}
+	require.NoError(t, json.Unmarshal([]byte(wildcardTestPod), &pod.Object))
+	require.NoError(t, normalizer.Normalize(pod))
+	return pod.Object
+}
+
+func TestNormalizeWildcardJSONPointer(t *testing.T) {
+	t.Run("ArrayElements", func(t *testing.T) {
+		pod := normalizeWildcardTestPod(t, "/spec/containers/*/image")
+		containers, _, _ := unstructured.NestedSlice(pod, "spec", "containers")
+		require.Len(t, containers, 2)
+		for _, container := range containers {
+			assert.NotContains(t, container, "image")
+			assert.Contains(t, container, "name")
+		}
+	})
+
+	t.Run("WholeArrayElements", func(t *testing.T) {
+		pod := normalizeWildcardTestPod(t, "/spec/containers/*")
+		containers, found, _ := unstructured.NestedSlice(pod, "spec", "containers")
+		assert.True(t, found)
+		assert.Empty(t, containers)
+	})
+
+	t.Run("NestedArrays", func(t *testing.T) {
+		pod := normalizeWildcardTestPod(t, "/spec/containers/*/ports/*/containerPort")
+		ports, _, _ := unstructured.NestedSlice(pod, "spec", "containers")
+		assert.Equal(t, []interface{}{map[string]interface{}{"name": "http"}}, ports[0].(map[string]interface{})["ports"])
+	})
+
+	t.Run("ObjectFields", func(t *testing.T) {
+		pod := normalizeWildcardTestPod(t, "/metadata/*/prometheus.io~1scrape")
+		annotations, _, _ := unstructured.NestedStringMap(pod, "metadata", "annotations")
+		assert.NotContains(t, annotations, "prometheus.io/scrape")
+		assert.Len(t, annotations, 6)
+	})
+
+	t.Run("Subtree", func(t *testing.T) {
+		pod := normalizeWildcardTestPod(t, "/spec/**/image")
+		containers, _, _ := unstructured.NestedSlice(pod, "spec", "containers")
+		assert.NotContains(t, containers[0], "image")
+		assert.NotContains(t, containers[1], "image")
+		// fields named image at any depth are removed, not values
+		items, _, _ := unstructured.NestedSlice(pod, "spec", "volumes")
+		assert.Equal(t, map[string]interface{}{"key": "image", "path": "image"}, items[0].(map[string]interface{})["configMap"].(map[string]interface{})["items"].([]interface{})[0])
+		env := containers[1].(map[string]interface{})["env"]
+		assert.Equal(t, []interface{}{map[string]interface{}{"name": "IMAGE", "value": "image"}}, env)
+	})
+
+	t.Run("SubtreeMatchesNoSegment", func(t *testing.T) {
+		pod := normalizeWildcardTestPod(t, "/**/name")
+		_, found, _ := unstructured.NestedString(pod, "metadata", "name")
+		assert.False(t, found)
+		containers, _, _ := unstructured.NestedSlice(pod, "spec", "containers")
+		assert.NotContains(t, containers[0], "name")
+		volumes, _, _ := unstructured.NestedSlice(pod, "spec", "volumes")
+		assert.Equal(t, map[string]interface{}{"configMap": map[string]interface{}{"items": []interface{}{map[string]interface{}{"key": "image", "path": "image"}}}}, volumes[0])
+	})
+
+	t.Run("TrailingSubtree", func(t *testing.T) {
+		pod := normalizeWildcardTestPod(t, "/spec/containers/**")
+		_, found, _ := unstructured.NestedSlice(pod, "spec", "containers")
+		assert.False(t, found)
+		_, found, _ = unstructured.NestedSlice(pod, "spec", "volumes")
+		assert.True(t, found)
+	})
+
+	t.Run("SpecialCharacters", func(t *testing.T) {
+		pod := normalizeWildcardTestPod(t,
+			"/metadata/*/a.b~1c",
+			"/metadata/*/a~0b",
+			"/metadata/*/$(VAR)",
+			"/metadata/*/x[0]+",
+			"/metadata/*/image*",
+		)
+		annotations, _, _ := unstructured.NestedStringMap(pod, "metadata", "annotations")
+		// segments other than wildcards match literally
+		assert.Equal(t, map[string]string{"prometheus.io/scrape": "true", "01": "number"}, annotations)
+	})
+
+	t.Run("ArrayIndex", func(t *testing.T) {
+		pod := normalizeWildcardTestPod(t, "/spec/*/1/image", "/spec/*/01/name", "/metadata/*/01")
+		containers, _, _ := unstructured.NestedSlice(pod, "spec", "containers")
+		assert.Contains(t, containers[0], "image")
+		assert.NotContains(t, containers[1], "image")
+		// array indexes have no leading zeros, while object fields match literally
+		assert.Contains(t, containers[1], "name")
+		_, found, _ := unstructured.NestedString(pod, "metadata", "annotations", "01")
+		assert.False(t, found)
+	})
+
+	t.Run("NoMatch", func(t *testing.T) {
+		pod := normalizeWildcardTestPod(t, "/spec/*/missing", "/status/**/phase", "/**")
+		expected := &unstructured.Unstructured{}
+		require.NoError(t, expected.UnmarshalJSON([]byte(wildcardTestPod)))
+		assert.Equal(t, expected.Object, pod)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := NewIgnoreNormalizer([]v1alpha1.ResourceIgnoreDifferences{{Kind: "Pod", JSONPointers: []string{"spec/*/image"}}}, nil, IgnoreNormalizerOpts{})
+		assert.EqualError(t, err, `JSON pointer "spec/*/image" must start with /`)
+	})
+}
+
+func TestParseWildcardJSONPointer(t *testing.T) {
+	assert.False(t, isWildcardJSONPointer("/spec/replicas"))
+	assert.False(t, isWildcardJSONPointer("/metadata/annotations/image*"))
+	assert.True(t, isWildcardJSONPointer("/spec/containers/*/image"))
+	assert.True(t, isWildcardJSONPointer("/spec/**"))
+
+	segments, err := parseWildcardJSONPointer("/metadata/**/**/*/a~1b~01")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"metadata", "**", "*", "a/b~1"}, segments)
+}
+
+// newBenchmarkManifest returns a manifest with 100 containers, and the ignored fields of each container: a JSON
+// pointer per container for exact matching, and a single pointer for wildcard matching
+func newBenchmarkManifest(b *testing.B) (*unstructured.Unstructured, []string) {
+	b.Helper()
+	containers := make([]interface{}, 100)
+	pointers := make([]string, 100)
+	for i := range containers {
+		containers[i] = map[string]interface{}{"name": fmt.Sprintf("container-%d", i), "image": fmt.Sprintf("image:%d", i)}
+		pointers[i] = fmt.Sprintf("/spec/containers/%d/image", i)
+	}
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": "benchmark"},
+		"spec":       map[string]interface{}{"containers": containers},
+	}}, pointers
+}
+
+func benchmarkNormalizeJSONPointers(b *testing.B, pointers func(exact []string) []string) {
+	manifest, exact := newBenchmarkManifest(b)
+	normalizer, err := NewIgnoreNormalizer([]v1alpha1.ResourceIgnoreDifferences{{Kind: "Pod", JSONPointers: pointers(exact)}}, nil, IgnoreNormalizerOpts{})
+	require.NoError(b, err)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		require.NoError(b, normalizer.Normalize(manifest.DeepCopy()))
+	}
+}
+
+func BenchmarkNormalizeExactJSONPointers(b *testing.B) {
+	benchmarkNormalizeJSONPointers(b, func(exact []string) []string { return exact })
+}
+
+func BenchmarkNormalizeWildcardJSONPointer(b *testing.B) {
+	benchmarkNormalizeJSONPointers(b, func([]string) []string { return []string{"/spec/containers/*/image"} })
+}
+
+func BenchmarkNormalizeRecursiveWildcardJSONPointer(b *testing.B) {
+	benchmarkNormalizeJSONPointers(b, func([]string) []string { return []string{"/spec/**/image"} })
+}
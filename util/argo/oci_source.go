@@ -0,0 +1,40 @@
+package argo
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/opencontainers/go-digest"
+	"oras.land/oras-go/v2/registry"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// ociTagRegex matches the tags allowed by the OCI distribution spec
+var ociTagRegex = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9._-]{0,127}$`)
+
+// ValidateOCISource verifies the image and tag of the given OCI source. The repository URL of an OCI source must be
+// its image, so that the image is matched against the source repositories of the project and authenticates with the
+// credentials of the repository.
+func ValidateOCISource(source argoappv1.ApplicationSource) error {
+	ref, err := registry.ParseReference(source.OCI.Image)
+	if err != nil {
+		return fmt.Errorf("image %q must be the reference of an OCI repository: %w", source.OCI.Image, err)
+	}
+	if ref.Reference != "" {
+		return fmt.Errorf("image %q must not have a tag or digest, the tag of the artifact is set with the tag field", source.OCI.Image)
+	}
+	if source.RepoURL != source.OCI.Image {
+		return fmt.Errorf("repoURL %q must be the image %q of the OCI source", source.RepoURL, source.OCI.Image)
+	}
+	if tag := source.OCI.Tag; tag != "" && !ociTagRegex.MatchString(tag) {
+		if _, err := digest.Parse(tag); err == nil {
+			return nil
+		}
+		if _, err := semver.NewConstraint(tag); err != nil {
+			return fmt.Errorf("tag %q must be a tag, a digest or a semantic version constraint", tag)
+		}
+	}
+	return nil
+}
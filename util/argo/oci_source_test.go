@@ -0,0 +1,28 @@
+package argo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func TestValidateOCISource(t *testing.T) {
+	const image = "ghcr.io/argoproj/manifests"
+	sourceWith := func(repoURL string, oci *argoappv1.ApplicationSourceOCI) argoappv1.ApplicationSource {
+		return argoappv1.ApplicationSource{RepoURL: repoURL, OCI: oci}
+	}
+
+	require.NoError(t, ValidateOCISource(sourceWith(image, &argoappv1.ApplicationSourceOCI{Image: image})))
+	require.NoError(t, ValidateOCISource(sourceWith(image, &argoappv1.ApplicationSourceOCI{Image: image, Tag: "v1.2.3"})))
+	require.NoError(t, ValidateOCISource(sourceWith(image, &argoappv1.ApplicationSourceOCI{Image: image, Tag: ">=1.2.0 <2.0.0"})))
+	require.NoError(t, ValidateOCISource(sourceWith(image, &argoappv1.ApplicationSourceOCI{
+		Image: image,
+		Tag:   "sha256:9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08",
+	})))
+	require.ErrorContains(t, ValidateOCISource(sourceWith("https://ghcr.io/argoproj/manifests", &argoappv1.ApplicationSourceOCI{Image: "https://ghcr.io/argoproj/manifests"})), "must be the reference of an OCI repository")
+	require.ErrorContains(t, ValidateOCISource(sourceWith(image+":v1", &argoappv1.ApplicationSourceOCI{Image: image + ":v1"})), "must not have a tag or digest")
+	require.ErrorContains(t, ValidateOCISource(sourceWith("ghcr.io/argoproj", &argoappv1.ApplicationSourceOCI{Image: image})), "must be the image")
+	require.ErrorContains(t, ValidateOCISource(sourceWith(image, &argoappv1.ApplicationSourceOCI{Image: image, Tag: "not a tag"})), "must be a tag, a digest or a semantic version constraint")
+}
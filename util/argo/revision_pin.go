@@ -0,0 +1,45 @@
+package argo
+
+import (
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/util/git"
+)
+
+// SyncOptionPinRevision is the sync option which pins the target revision of the git sources of an application to the
+// commit of the first successful sync
+const SyncOptionPinRevision = "PinRevision=true"
+
+// PinSourceRevision sets the target revision of a git source to the given commit SHA. It returns false if the source
+// is not a git source, if its target revision is already a commit SHA or if the given revision is not a commit SHA.
+func PinSourceRevision(source *argoappv1.ApplicationSource, revision string) bool {
+	if source == nil || source.IsHelm() || source.IsInline() || source.IsHTTP() || source.IsOCI() {
+		return false
+	}
+	if git.IsCommitSHA(source.TargetRevision) || !git.IsCommitSHA(revision) {
+		return false
+	}
+	source.TargetRevision = revision
+	return true
+}
+
+// PinSpecRevisions pins the target revisions of the git sources of the application spec to the given commit SHAs,
+// which are the revisions of a sync result or of the sync status: revision for a single source application and
+// revisions, in the order of the sources, for a multi source application. It returns the revisions which were pinned.
+func PinSpecRevisions(spec *argoappv1.ApplicationSpec, revision string, revisions []string) []string {
+	var pinned []string
+	if !spec.HasMultipleSources() {
+		if PinSourceRevision(spec.Source, revision) {
+			pinned = append(pinned, revision)
+		}
+		return pinned
+	}
+	if len(revisions) != len(spec.Sources) {
+		return nil
+	}
+	for i := range spec.Sources {
+		if PinSourceRevision(&spec.Sources[i], revisions[i]) {
+			pinned = append(pinned, revisions[i])
+		}
+	}
+	return pinned
+}
@@ -0,0 +1,81 @@
+package argo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+const (
+	pinnedSHA = "a3b59e4b1b0b0d1d8c2e3b6b4a8c1f5e6d7c8b9a"
+	otherSHA  = "0f1e2d3c4b5a69788796a5b4c3d2e1f00f1e2d3c"
+)
+
+func TestPinSourceRevision(t *testing.T) {
+	t.Run("Branch", func(t *testing.T) {
+		source := &argoappv1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook", TargetRevision: "main"}
+		assert.True(t, PinSourceRevision(source, pinnedSHA))
+		assert.Equal(t, pinnedSHA, source.TargetRevision)
+	})
+
+	t.Run("HEAD", func(t *testing.T) {
+		source := &argoappv1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook"}
+		assert.True(t, PinSourceRevision(source, pinnedSHA))
+		assert.Equal(t, pinnedSHA, source.TargetRevision)
+	})
+
+	t.Run("AlreadyPinned", func(t *testing.T) {
+		source := &argoappv1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook", TargetRevision: otherSHA}
+		assert.False(t, PinSourceRevision(source, pinnedSHA))
+		assert.Equal(t, otherSHA, source.TargetRevision)
+	})
+
+	t.Run("UnresolvedRevision", func(t *testing.T) {
+		source := &argoappv1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", Path: "guestbook", TargetRevision: "main"}
+		assert.False(t, PinSourceRevision(source, "main"))
+		assert.Equal(t, "main", source.TargetRevision)
+	})
+
+	t.Run("HelmChart", func(t *testing.T) {
+		source := &argoappv1.ApplicationSource{RepoURL: "https://charts.bitnami.com/bitnami", Chart: "redis", TargetRevision: "17.x"}
+		assert.False(t, PinSourceRevision(source, pinnedSHA))
+		assert.Equal(t, "17.x", source.TargetRevision)
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		assert.False(t, PinSourceRevision(nil, pinnedSHA))
+	})
+}
+
+func TestPinSpecRevisions(t *testing.T) {
+	t.Run("SingleSource", func(t *testing.T) {
+		spec := &argoappv1.ApplicationSpec{Source: &argoappv1.ApplicationSource{RepoURL: "https://github.com/argoproj/argocd-example-apps", TargetRevision: "main"}}
+		assert.Equal(t, []string{pinnedSHA}, PinSpecRevisions(spec, pinnedSHA, nil))
+		assert.Equal(t, pinnedSHA, spec.Source.TargetRevision)
+		assert.Empty(t, PinSpecRevisions(spec, otherSHA, nil))
+		assert.Equal(t, pinnedSHA, spec.Source.TargetRevision)
+	})
+
+	t.Run("MultipleSources", func(t *testing.T) {
+		spec := &argoappv1.ApplicationSpec{Sources: argoappv1.ApplicationSources{
+			{RepoURL: "https://github.com/argoproj/argocd-example-apps", TargetRevision: "main"},
+			{RepoURL: "https://charts.bitnami.com/bitnami", Chart: "redis", TargetRevision: "17.x"},
+			{RepoURL: "https://github.com/argoproj/argocd-example-values", TargetRevision: "release", Ref: "values"},
+		}}
+		assert.Equal(t, []string{pinnedSHA, otherSHA}, PinSpecRevisions(spec, "", []string{pinnedSHA, "17.11.3", otherSHA}))
+		assert.Equal(t, pinnedSHA, spec.Sources[0].TargetRevision)
+		assert.Equal(t, "17.x", spec.Sources[1].TargetRevision)
+		assert.Equal(t, otherSHA, spec.Sources[2].TargetRevision)
+	})
+
+	t.Run("MismatchedRevisions", func(t *testing.T) {
+		spec := &argoappv1.ApplicationSpec{Sources: argoappv1.ApplicationSources{
+			{RepoURL: "https://github.com/argoproj/argocd-example-apps", TargetRevision: "main"},
+			{RepoURL: "https://github.com/argoproj/argocd-example-values", TargetRevision: "main"},
+		}}
+		assert.Empty(t, PinSpecRevisions(spec, "", []string{pinnedSHA}))
+		assert.Equal(t, "main", spec.Sources[0].TargetRevision)
+	})
+}
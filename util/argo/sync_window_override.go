@@ -0,0 +1,66 @@
+package argo
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// SyncWindowOverrideMaxAge is the maximum time between the recording of a sync window override by the API server and
+// the start of the sync operation overriding the sync windows, in either order to tolerate clock skews
+const SyncWindowOverrideMaxAge = 5 * time.Minute
+
+// SyncWindowOverrideRecord is the audit record of a sync overriding the sync windows of an application, written to the
+// argocd-sync-window-overrides ConfigMap by the API server once the user is authorized to override them
+type SyncWindowOverrideRecord struct {
+	User      string      `json:"user"`
+	Reason    string      `json:"reason"`
+	Project   string      `json:"project"`
+	Timestamp metav1.Time `json:"timestamp"`
+}
+
+// SyncWindowOverrideKey returns the key of the records of an application in the sync window overrides ConfigMap.
+// Neither namespaces nor application names contain underscores.
+func SyncWindowOverrideKey(app *argoappv1.Application) string {
+	return app.Namespace + "_" + app.Name
+}
+
+// GetSyncWindowOverrideRecords returns the sync window overrides of the application recorded in the ConfigMap
+func GetSyncWindowOverrideRecords(cm *v1.ConfigMap, app *argoappv1.Application) ([]SyncWindowOverrideRecord, error) {
+	var records []SyncWindowOverrideRecord
+	if data, ok := cm.Data[SyncWindowOverrideKey(app)]; ok {
+		if err := json.Unmarshal([]byte(data), &records); err != nil {
+			return nil, fmt.Errorf("error unmarshaling sync window overrides of application %s: %w", app.QualifiedName(), err)
+		}
+	}
+	return records, nil
+}
+
+// IsSyncWindowOverrideRecorded returns whether the sync operation overriding the sync windows of the application with
+// the given reason was authorized by the API server, which records the override before creating the operation
+func IsSyncWindowOverrideRecorded(records []SyncWindowOverrideRecord, app *argoappv1.Application, state *argoappv1.OperationState) bool {
+	if state == nil || state.Operation.Sync == nil {
+		return false
+	}
+	for _, record := range records {
+		if record.User == state.Operation.InitiatedBy.Username &&
+			record.Reason == state.Operation.Sync.SyncWindowOverrideReason &&
+			record.Project == app.Spec.GetProject() &&
+			absDuration(state.StartedAt.Sub(record.Timestamp.Time)) <= SyncWindowOverrideMaxAge {
+			return true
+		}
+	}
+	return false
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
@@ -0,0 +1,61 @@
+package argo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	argoappv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+func TestIsSyncWindowOverrideRecorded(t *testing.T) {
+	app := &argoappv1.Application{
+		ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd"},
+		Spec:       argoappv1.ApplicationSpec{Project: "default"},
+	}
+	now := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	records := []SyncWindowOverrideRecord{{User: "admin", Reason: "security patch", Project: "default", Timestamp: metav1.NewTime(now)}}
+	state := func(user, reason string, startedAt time.Time) *argoappv1.OperationState {
+		return &argoappv1.OperationState{
+			Operation: argoappv1.Operation{
+				Sync:        &argoappv1.SyncOperation{SyncWindowOverrideReason: reason},
+				InitiatedBy: argoappv1.OperationInitiator{Username: user},
+			},
+			StartedAt: metav1.NewTime(startedAt),
+		}
+	}
+
+	assert.True(t, IsSyncWindowOverrideRecorded(records, app, state("admin", "security patch", now.Add(time.Second))))
+	assert.True(t, IsSyncWindowOverrideRecorded(records, app, state("admin", "security patch", now.Add(-time.Second))))
+	assert.False(t, IsSyncWindowOverrideRecorded(records, app, state("alice", "security patch", now)))
+	assert.False(t, IsSyncWindowOverrideRecorded(records, app, state("admin", "other reason", now)))
+	assert.False(t, IsSyncWindowOverrideRecorded(records, app, state("admin", "security patch", now.Add(SyncWindowOverrideMaxAge+time.Second))))
+	assert.False(t, IsSyncWindowOverrideRecorded(nil, app, state("admin", "security patch", now)))
+	assert.False(t, IsSyncWindowOverrideRecorded(records, app, nil))
+
+	otherProjectApp := app.DeepCopy()
+	otherProjectApp.Spec.Project = "other"
+	assert.False(t, IsSyncWindowOverrideRecorded(records, otherProjectApp, state("admin", "security patch", now)))
+}
+
+func TestGetSyncWindowOverrideRecords(t *testing.T) {
+	app := &argoappv1.Application{ObjectMeta: metav1.ObjectMeta{Name: "guestbook", Namespace: "argocd"}}
+
+	records, err := GetSyncWindowOverrideRecords(&v1.ConfigMap{Data: map[string]string{
+		"argocd_guestbook": `[{"user":"admin","reason":"security patch","project":"default","timestamp":"2024-01-01T10:00:00Z"}]`,
+	}}, app)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, "admin", records[0].User)
+
+	records, err = GetSyncWindowOverrideRecords(&v1.ConfigMap{}, app)
+	require.NoError(t, err)
+	assert.Empty(t, records)
+
+	_, err = GetSyncWindowOverrideRecords(&v1.ConfigMap{Data: map[string]string{"argocd_guestbook": "{"}}, app)
+	require.Error(t, err)
+}
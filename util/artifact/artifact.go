@@ -0,0 +1,334 @@
+package artifact
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ArtifactType is the artifact type of the manifests of sync result artifacts
+	ArtifactType = "application/vnd.argoproj.argocd.sync-result.v1"
+	// LayerMediaType is the media type of the layer holding the gzip compressed tarball of the applied manifests
+	LayerMediaType = "application/vnd.argoproj.argocd.manifests.v1.tar+gzip"
+
+	// AnnotationAppName is the annotation holding the qualified name of the synced application
+	AnnotationAppName = "io.argoproj.argocd.app-name"
+	// AnnotationRevision is the annotation holding the synced revision
+	AnnotationRevision = "io.argoproj.argocd.revision"
+	// AnnotationSyncTime is the annotation holding the time the sync started at, in RFC 3339 format
+	AnnotationSyncTime = "io.argoproj.argocd.sync-time"
+
+	// ManifestsFileName is the name of the file holding the applied manifests in the tarball
+	ManifestsFileName = "manifests.yaml"
+
+	// tagTimeFormat is the format of the sync time in the tags of the artifacts
+	tagTimeFormat = "20060102T150405Z"
+	// maxTagLength is the maximum length of a tag allowed by the OCI distribution spec
+	maxTagLength = 128
+	// maxLayerSize is the maximum size of a manifests layer which is downloaded
+	maxLayerSize = 64 * 1024 * 1024
+)
+
+// Repository is an OCI repository the sync result artifacts are stored in
+type Repository struct {
+	// Registry is the host name of the registry
+	Registry string
+	// Repo is the path of the repository in the registry
+	Repo string
+	// Username and Password are the credentials used to authenticate against the registry. Anonymous access is used
+	// if both are empty.
+	Username string
+	Password string
+	// PlainHTTP accesses the registry over HTTP instead of HTTPS
+	PlainHTTP bool
+}
+
+// SyncResult is a successful sync of an application which is stored as an artifact
+type SyncResult struct {
+	// AppName is the qualified name of the application
+	AppName string
+	// Revision is the synced revision
+	Revision string
+	// SyncTime is the time the sync started at
+	SyncTime time.Time
+	// Manifests are the applied manifests
+	Manifests []*unstructured.Unstructured
+}
+
+// Artifact is a sync result stored in an OCI repository
+type Artifact struct {
+	// Tag is the tag of the artifact in the repository
+	Tag string `json:"tag"`
+	// Digest is the digest of the manifest of the artifact
+	Digest string `json:"digest"`
+	// AppName is the qualified name of the synced application
+	AppName string `json:"appName"`
+	// Revision is the synced revision
+	Revision string `json:"revision"`
+	// SyncTime is the time the sync started at
+	SyncTime time.Time `json:"syncTime"`
+}
+
+// newRemoteRepository returns a client of the repository, authenticating with the credentials of the repository
+func newRemoteRepository(repository Repository) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(repository.Registry + "/" + repository.Repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize repository %s/%s: %w", repository.Registry, repository.Repo, err)
+	}
+	repo.PlainHTTP = repository.PlainHTTP
+	client := &auth.Client{Client: http.DefaultClient, Cache: auth.NewCache()}
+	if repository.Username != "" || repository.Password != "" {
+		client.Credential = auth.StaticCredential(repository.Registry, auth.Credential{
+			Username: repository.Username,
+			Password: repository.Password,
+		})
+	}
+	repo.Client = client
+	return repo, nil
+}
+
+// tagPrefix returns the prefix of the tags of the artifacts of the application. Qualified application names contain
+// a slash, which is not allowed in tags, and names which are too long for a tag are shortened using a hash.
+func tagPrefix(appName string) string {
+	prefix := strings.ReplaceAll(appName, "/", "_")
+	maxPrefixLength := maxTagLength - len(tagTimeFormat) - 1
+	if len(prefix) > maxPrefixLength {
+		hash := sha256.Sum256([]byte(appName))
+		suffix := "-" + hex.EncodeToString(hash[:])[:8]
+		prefix = prefix[:maxPrefixLength-len(suffix)] + suffix
+	}
+	return prefix
+}
+
+// Tag returns the tag of the artifact of the sync of the application at the given time
+func Tag(appName string, syncTime time.Time) string {
+	return tagPrefix(appName) + "-" + syncTime.UTC().Format(tagTimeFormat)
+}
+
+// isTagOf returns true if the tag is the tag of an artifact of the application
+func isTagOf(tag string, appName string) bool {
+	timestamp, ok := strings.CutPrefix(tag, tagPrefix(appName)+"-")
+	if !ok {
+		return false
+	}
+	_, err := time.Parse(tagTimeFormat, timestamp)
+	return err == nil
+}
+
+// packManifests returns a gzip compressed tarball holding the manifests as a multi-document YAML file
+func packManifests(manifests []*unstructured.Unstructured, modTime time.Time) ([]byte, error) {
+	var documents bytes.Buffer
+	for _, manifest := range manifests {
+		data, err := yaml.Marshal(manifest.Object)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling manifest %s/%s: %w", manifest.GetKind(), manifest.GetName(), err)
+		}
+		documents.WriteString("---\n")
+		documents.Write(data)
+	}
+
+	var out bytes.Buffer
+	gzipWriter := gzip.NewWriter(&out)
+	tarWriter := tar.NewWriter(gzipWriter)
+	err := tarWriter.WriteHeader(&tar.Header{
+		Name:    ManifestsFileName,
+		Mode:    0o644,
+		Size:    int64(documents.Len()),
+		ModTime: modTime,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error writing tar header: %w", err)
+	}
+	if _, err := tarWriter.Write(documents.Bytes()); err != nil {
+		return nil, fmt.Errorf("error writing tar: %w", err)
+	}
+	if err := tarWriter.Close(); err != nil {
+		return nil, fmt.Errorf("error closing tar: %w", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return nil, fmt.Errorf("error closing gzip: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// unpackManifests returns the content of the manifests file of a gzip compressed tarball
+func unpackManifests(data []byte) ([]byte, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("error reading gzip: %w", err)
+	}
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("tarball does not contain %s", ManifestsFileName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading tar: %w", err)
+		}
+		if header.Name != ManifestsFileName {
+			continue
+		}
+		manifests, err := io.ReadAll(io.LimitReader(tarReader, maxLayerSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", ManifestsFileName, err)
+		}
+		if len(manifests) > maxLayerSize {
+			return nil, fmt.Errorf("%s exceeds the maximum size of %d bytes", ManifestsFileName, maxLayerSize)
+		}
+		return manifests, nil
+	}
+}
+
+// Push stores the applied manifests of the sync as an artifact in the repository, tagged with Tag, and returns the
+// stored artifact
+func Push(ctx context.Context, repository Repository, result SyncResult) (*Artifact, error) {
+	repo, err := newRemoteRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	layer, err := packManifests(result.Manifests, result.SyncTime)
+	if err != nil {
+		return nil, err
+	}
+	layerDesc := content.NewDescriptorFromBytes(LayerMediaType, layer)
+	layerDesc.Annotations = map[string]string{ocispec.AnnotationTitle: ManifestsFileName + ".tar.gz"}
+	if err := repo.Push(ctx, layerDesc, bytes.NewReader(layer)); err != nil && !errors.Is(err, errdef.ErrAlreadyExists) {
+		return nil, fmt.Errorf("failed to push manifests layer: %w", err)
+	}
+
+	syncTime := result.SyncTime.UTC().Format(time.RFC3339)
+	manifestDesc, err := oras.PackManifest(ctx, repo, oras.PackManifestVersion1_1, ArtifactType, oras.PackManifestOptions{
+		Layers: []ocispec.Descriptor{layerDesc},
+		ManifestAnnotations: map[string]string{
+			ocispec.AnnotationCreated: syncTime,
+			AnnotationAppName:         result.AppName,
+			AnnotationRevision:        result.Revision,
+			AnnotationSyncTime:        syncTime,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to push artifact manifest: %w", err)
+	}
+	tag := Tag(result.AppName, result.SyncTime)
+	if err := repo.Tag(ctx, manifestDesc, tag); err != nil {
+		return nil, fmt.Errorf("failed to tag artifact %s: %w", tag, err)
+	}
+	return &Artifact{
+		Tag:      tag,
+		Digest:   manifestDesc.Digest.String(),
+		AppName:  result.AppName,
+		Revision: result.Revision,
+		SyncTime: result.SyncTime.UTC().Truncate(time.Second),
+	}, nil
+}
+
+// fetchManifest returns the descriptor and the manifest of the artifact with the given tag
+func fetchManifest(ctx context.Context, repo *remote.Repository, tag string) (ocispec.Descriptor, *ocispec.Manifest, error) {
+	desc, data, err := oras.FetchBytes(ctx, repo, tag, oras.DefaultFetchBytesOptions)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to fetch manifest of artifact %s: %w", tag, err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("failed to unmarshal manifest of artifact %s: %w", tag, err)
+	}
+	if manifest.ArtifactType != ArtifactType {
+		return ocispec.Descriptor{}, nil, fmt.Errorf("%s is not a sync result artifact", tag)
+	}
+	return desc, &manifest, nil
+}
+
+// List returns the artifacts of the application stored in the repository, oldest first
+func List(ctx context.Context, repository Repository, appName string) ([]Artifact, error) {
+	repo, err := newRemoteRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	err = repo.Tags(ctx, "", func(page []string) error {
+		for _, tag := range page {
+			if isTagOf(tag, appName) {
+				tags = append(tags, tag)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags of %s/%s: %w", repository.Registry, repository.Repo, err)
+	}
+
+	artifacts := make([]Artifact, 0, len(tags))
+	for _, tag := range tags {
+		desc, manifest, err := fetchManifest(ctx, repo, tag)
+		if err != nil {
+			return nil, err
+		}
+		// tags of shortened names may collide, the annotation holds the full name
+		if manifest.Annotations[AnnotationAppName] != appName {
+			continue
+		}
+		syncTime, err := time.Parse(time.RFC3339, manifest.Annotations[AnnotationSyncTime])
+		if err != nil {
+			return nil, fmt.Errorf("artifact %s has an invalid sync time: %w", tag, err)
+		}
+		artifacts = append(artifacts, Artifact{
+			Tag:      tag,
+			Digest:   desc.Digest.String(),
+			AppName:  appName,
+			Revision: manifest.Annotations[AnnotationRevision],
+			SyncTime: syncTime,
+		})
+	}
+	sort.Slice(artifacts, func(i, j int) bool {
+		return artifacts[i].SyncTime.Before(artifacts[j].SyncTime)
+	})
+	return artifacts, nil
+}
+
+// Pull returns the applied manifests stored in the artifact with the given tag, as a multi-document YAML file
+func Pull(ctx context.Context, repository Repository, tag string) ([]byte, error) {
+	repo, err := newRemoteRepository(repository)
+	if err != nil {
+		return nil, err
+	}
+	_, manifest, err := fetchManifest(ctx, repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != LayerMediaType {
+			continue
+		}
+		if layer.Size > maxLayerSize {
+			return nil, fmt.Errorf("manifests layer of artifact %s exceeds the maximum size of %d bytes", tag, maxLayerSize)
+		}
+		data, err := content.FetchAll(ctx, repo, layer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch manifests layer of artifact %s: %w", tag, err)
+		}
+		return unpackManifests(data)
+	}
+	return nil, fmt.Errorf("artifact %s has no manifests layer", tag)
+}
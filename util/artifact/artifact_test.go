@@ -0,0 +1,228 @@
+package artifact
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// mockRegistry is an OCI registry storing pushed blobs by digest, and pushed manifests by repository and reference
+type mockRegistry struct {
+	lock      sync.Mutex
+	manifests map[string][]byte
+	blobs     map[digest.Digest][]byte
+	uploads   int
+	// username and password are the credentials required by the registry, if not empty
+	username string
+	password string
+}
+
+func (r *mockRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.username != "" {
+		if username, password, ok := req.BasicAuth(); !ok || username != r.username || password != r.password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="mock"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+	if req.URL.Path == "/v2/" {
+		return
+	}
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	switch {
+	case strings.HasSuffix(path, "/blobs/uploads/") && req.Method == http.MethodPost:
+		r.uploads++
+		w.Header().Set("Location", fmt.Sprintf("/v2/%supload-%d", path, r.uploads))
+		w.WriteHeader(http.StatusAccepted)
+	case strings.Contains(path, "/blobs/uploads/") && req.Method == http.MethodPut:
+		data, _ := io.ReadAll(req.Body)
+		dgst := digest.Digest(req.URL.Query().Get("digest"))
+		if dgst != digest.FromBytes(data) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		r.blobs[dgst] = data
+		w.WriteHeader(http.StatusCreated)
+	case strings.Contains(path, "/manifests/") && req.Method == http.MethodPut:
+		i := strings.Index(path, "/manifests/")
+		data, _ := io.ReadAll(req.Body)
+		r.manifests[path[:i]+":"+path[i+len("/manifests/"):]] = data
+		r.blobs[digest.FromBytes(data)] = data
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(data).String())
+		w.WriteHeader(http.StatusCreated)
+	case strings.HasSuffix(path, "/tags/list"):
+		repo := strings.TrimSuffix(path, "/tags/list")
+		tags := []string{}
+		for key := range r.manifests {
+			if ref, ok := strings.CutPrefix(key, repo+":"); ok && !strings.HasPrefix(ref, "sha256:") {
+				tags = append(tags, ref)
+			}
+		}
+		sort.Strings(tags)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": repo, "tags": tags})
+	default:
+		r.serveContent(w, req, path)
+	}
+}
+
+// serveContent serves a manifest by repository and reference, or a blob by digest
+func (r *mockRegistry) serveContent(w http.ResponseWriter, req *http.Request, path string) {
+	var data []byte
+	mediaType := "application/octet-stream"
+	if i := strings.Index(path, "/manifests/"); i >= 0 {
+		repo, ref := path[:i], path[i+len("/manifests/"):]
+		data = r.manifests[repo+":"+ref]
+		if data == nil {
+			data = r.blobs[digest.Digest(ref)]
+		}
+		mediaType = ocispec.MediaTypeImageManifest
+	} else if i := strings.Index(path, "/blobs/"); i >= 0 {
+		data = r.blobs[digest.Digest(path[i+len("/blobs/"):])]
+	}
+	if data == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Docker-Content-Digest", digest.FromBytes(data).String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if req.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+}
+
+func newMockRegistry(t *testing.T) (*mockRegistry, Repository) {
+	t.Helper()
+	registry := &mockRegistry{manifests: map[string][]byte{}, blobs: map[digest.Digest][]byte{}}
+	server := httptest.NewServer(registry)
+	t.Cleanup(server.Close)
+	return registry, Repository{Registry: strings.TrimPrefix(server.URL, "http://"), Repo: "argocd/sync-results", PlainHTTP: true}
+}
+
+func newConfigMap(name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": name, "namespace": "default"},
+		"data":       map[string]interface{}{"key": "value"},
+	}}
+}
+
+func TestTag(t *testing.T) {
+	syncTime := time.Date(2024, 3, 1, 12, 30, 45, 0, time.FixedZone("CET", 3600))
+	assert.Equal(t, "argocd_guestbook-20240301T113045Z", Tag("argocd/guestbook", syncTime))
+	assert.True(t, isTagOf("argocd_guestbook-20240301T113045Z", "argocd/guestbook"))
+	assert.False(t, isTagOf("argocd_guestbook-2-20240301T113045Z", "argocd/guestbook"))
+	assert.False(t, isTagOf("argocd_guestbook-latest", "argocd/guestbook"))
+
+	longName := "argocd/" + strings.Repeat("a", 200)
+	tag := Tag(longName, syncTime)
+	assert.Len(t, tag, maxTagLength)
+	assert.True(t, isTagOf(tag, longName))
+	assert.NotEqual(t, tag, Tag(longName+"b", syncTime))
+}
+
+func TestPushListPull(t *testing.T) {
+	registry, repository := newMockRegistry(t)
+	ctx := context.Background()
+	firstSync := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	secondSync := firstSync.Add(time.Hour)
+
+	first, err := Push(ctx, repository, SyncResult{AppName: "argocd/guestbook", Revision: "abc", SyncTime: firstSync, Manifests: []*unstructured.Unstructured{newConfigMap("first")}})
+	require.NoError(t, err)
+	assert.Equal(t, "argocd_guestbook-20240301T120000Z", first.Tag)
+	_, err = Push(ctx, repository, SyncResult{AppName: "argocd/guestbook", Revision: "def", SyncTime: secondSync, Manifests: []*unstructured.Unstructured{newConfigMap("second"), newConfigMap("third")}})
+	require.NoError(t, err)
+	_, err = Push(ctx, repository, SyncResult{AppName: "argocd/guestbook-2", Revision: "ghi", SyncTime: secondSync})
+	require.NoError(t, err)
+
+	var manifest ocispec.Manifest
+	require.NoError(t, json.Unmarshal(registry.manifests["argocd/sync-results:"+first.Tag], &manifest))
+	assert.Equal(t, ArtifactType, manifest.ArtifactType)
+	assert.Equal(t, "argocd/guestbook", manifest.Annotations[AnnotationAppName])
+	assert.Equal(t, "abc", manifest.Annotations[AnnotationRevision])
+	assert.Equal(t, "2024-03-01T12:00:00Z", manifest.Annotations[AnnotationSyncTime])
+	require.Len(t, manifest.Layers, 1)
+	assert.Equal(t, LayerMediaType, manifest.Layers[0].MediaType)
+
+	artifacts, err := List(ctx, repository, "argocd/guestbook")
+	require.NoError(t, err)
+	require.Len(t, artifacts, 2)
+	assert.Equal(t, *first, artifacts[0])
+	assert.Equal(t, "def", artifacts[1].Revision)
+	assert.Equal(t, secondSync, artifacts[1].SyncTime)
+
+	manifests, err := Pull(ctx, repository, artifacts[1].Tag)
+	require.NoError(t, err)
+	assert.Equal(t, `---
+apiVersion: v1
+data:
+  key: value
+kind: ConfigMap
+metadata:
+  name: second
+  namespace: default
+---
+apiVersion: v1
+data:
+  key: value
+kind: ConfigMap
+metadata:
+  name: third
+  namespace: default
+`, string(manifests))
+}
+
+func TestPush_Credentials(t *testing.T) {
+	registry, repository := newMockRegistry(t)
+	registry.username = "admin"
+	registry.password = "secret"
+	result := SyncResult{AppName: "argocd/guestbook", Revision: "abc", SyncTime: time.Now(), Manifests: []*unstructured.Unstructured{newConfigMap("cm")}}
+
+	_, err := Push(context.Background(), repository, result)
+	require.Error(t, err)
+
+	repository.Username = "admin"
+	repository.Password = "secret"
+	_, err = Push(context.Background(), repository, result)
+	require.NoError(t, err)
+	artifacts, err := List(context.Background(), repository, "argocd/guestbook")
+	require.NoError(t, err)
+	assert.Len(t, artifacts, 1)
+}
+
+func TestPull_NotSyncResult(t *testing.T) {
+	registry, repository := newMockRegistry(t)
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.DescriptorEmptyJSON,
+		Layers:    []ocispec.Descriptor{},
+	})
+	require.NoError(t, err)
+	registry.manifests["argocd/sync-results:latest"] = manifest
+
+	_, err = Pull(context.Background(), repository, "latest")
+	require.ErrorContains(t, err, "latest is not a sync result artifact")
+
+	_, err = Pull(context.Background(), repository, "missing")
+	require.ErrorContains(t, err, "failed to fetch manifest of artifact missing")
+}
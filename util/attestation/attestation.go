@@ -0,0 +1,278 @@
+package attestation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+)
+
+const (
+	// PredicateTypeCycloneDX is the in-toto predicate type of CycloneDX SBOM attestations
+	PredicateTypeCycloneDX = "https://cyclonedx.org/bom"
+	// PredicateTypeSPDX is the in-toto predicate type of SPDX SBOM attestations
+	PredicateTypeSPDX = "https://spdx.dev/Document"
+
+	// dsseEnvelopeMediaType is the media type of the layers of cosign attestations
+	dsseEnvelopeMediaType = "application/vnd.dsse.envelope.v1+json"
+	// inTotoPayloadType is the payload type of DSSE envelopes containing in-toto statements
+	inTotoPayloadType = "application/vnd.in-toto+json"
+	// attestationTagSuffix is the suffix of the tags cosign stores the attestations of an image digest at
+	attestationTagSuffix = ".att"
+	// maxLayerSize is the maximum size of an attestation layer which is downloaded
+	maxLayerSize = 64 * 1024 * 1024
+)
+
+// Reason is the reason why an image does not satisfy the attestation requirements
+type Reason string
+
+const (
+	// ReasonSBOMMissing means that no SBOM attestation is attached to the image
+	ReasonSBOMMissing Reason = "SBOMMissing"
+	// ReasonLicenseMissing means that the SBOM contains components without license
+	ReasonLicenseMissing Reason = "LicenseMissing"
+	// ReasonLicenseNotPermitted means that the SBOM contains licenses which are not in the allowlist
+	ReasonLicenseNotPermitted Reason = "LicenseNotPermitted"
+	// ReasonVerificationFailed means that the attestations of the image could not be retrieved
+	ReasonVerificationFailed Reason = "VerificationFailed"
+)
+
+// VerificationError is returned when an image does not satisfy the attestation requirements
+type VerificationError struct {
+	// Image is the image which was verified
+	Image string
+	// Reason is the reason of the failure
+	Reason Reason
+	// Details are the components without license or the licenses which are not permitted, depending on the reason
+	Details []string
+	// Err is the underlying error of VerificationFailed failures
+	Err error
+}
+
+func (e *VerificationError) Error() string {
+	switch e.Reason {
+	case ReasonSBOMMissing:
+		return fmt.Sprintf("image %s has no SBOM attestation", e.Image)
+	case ReasonLicenseMissing:
+		return fmt.Sprintf("SBOM of image %s contains components without license: %s", e.Image, strings.Join(e.Details, ", "))
+	case ReasonLicenseNotPermitted:
+		return fmt.Sprintf("SBOM of image %s contains licenses which are not permitted: %s", e.Image, strings.Join(e.Details, ", "))
+	default:
+		return fmt.Sprintf("failed to verify attestations of image %s: %v", e.Image, e.Err)
+	}
+}
+
+func (e *VerificationError) Unwrap() error {
+	return e.Err
+}
+
+// VerificationErrors are the verification errors of several images
+type VerificationErrors []*VerificationError
+
+func (e VerificationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i := range e {
+		messages[i] = e[i].Error()
+	}
+	return "SBOM attestation verification failed: " + strings.Join(messages, "; ")
+}
+
+// Statement is an in-toto statement attached to an image as an attestation
+type Statement struct {
+	Type          string          `json:"_type"`
+	PredicateType string          `json:"predicateType"`
+	Predicate     json.RawMessage `json:"predicate"`
+}
+
+type dsseEnvelope struct {
+	PayloadType string `json:"payloadType"`
+	Payload     string `json:"payload"`
+}
+
+// AttestationVerifier verifies the attestations attached to container images
+type AttestationVerifier interface {
+	// VerifySBOM verifies that an SBOM attestation is attached to the image, and that all its components have a license
+	// of allowedLicenses. The licenses are not checked if allowedLicenses is empty. A *VerificationError is returned if
+	// the image does not satisfy these requirements.
+	VerifySBOM(ctx context.Context, image string, allowedLicenses []string) error
+}
+
+type registryVerifier struct {
+	// plainHTTP accesses registries over HTTP instead of HTTPS
+	plainHTTP bool
+}
+
+// NewAttestationVerifier returns an AttestationVerifier which downloads the attestations anonymously from the registry
+// of the images. Attestations are looked up the way `cosign download attestation` does, i.e. as the layers of the
+// `sha256-<digest>.att` tag of the image repository. Their signatures are not verified.
+func NewAttestationVerifier() AttestationVerifier {
+	return &registryVerifier{}
+}
+
+func (v *registryVerifier) VerifySBOM(ctx context.Context, image string, allowedLicenses []string) error {
+	statements, err := v.downloadAttestations(ctx, image)
+	if err != nil {
+		return &VerificationError{Image: image, Reason: ReasonVerificationFailed, Err: err}
+	}
+	return VerifySBOMStatements(image, statements, allowedLicenses)
+}
+
+// downloadAttestations returns the in-toto statements attached to the image, or none if it has no attestations
+func (v *registryVerifier) downloadAttestations(ctx context.Context, image string) ([]Statement, error) {
+	repo, err := remote.NewRepository(registryReference(image))
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize repository: %w", err)
+	}
+	repo.PlainHTTP = v.plainHTTP
+
+	imageDigest, err := digest.Parse(repo.Reference.Reference)
+	if err != nil {
+		desc, err := repo.Resolve(ctx, repo.Reference.ReferenceOrDefault())
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve image digest: %w", err)
+		}
+		imageDigest = desc.Digest
+	}
+
+	attTag := imageDigest.Algorithm().String() + "-" + imageDigest.Encoded() + attestationTagSuffix
+	manifestDesc, manifestReader, err := repo.FetchReference(ctx, attTag)
+	if errors.Is(err, errdef.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch attestation manifest %s: %w", attTag, err)
+	}
+	defer manifestReader.Close()
+	manifestBytes, err := content.ReadAll(manifestReader, manifestDesc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestation manifest %s: %w", attTag, err)
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestBytes, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attestation manifest %s: %w", attTag, err)
+	}
+
+	var statements []Statement
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != dsseEnvelopeMediaType || layer.Size > maxLayerSize {
+			continue
+		}
+		statement, err := fetchStatement(ctx, repo, layer)
+		if err != nil {
+			return nil, err
+		}
+		if statement != nil {
+			statements = append(statements, *statement)
+		}
+	}
+	return statements, nil
+}
+
+// registryReference returns the fully qualified reference of an image, e.g. registry-1.docker.io/library/nginx:latest
+// for nginx:latest
+func registryReference(image string) string {
+	first, _, found := strings.Cut(image, "/")
+	switch {
+	case !found:
+		image = "docker.io/library/" + image
+	case !strings.ContainsAny(first, ".:") && first != "localhost":
+		image = "docker.io/" + image
+	}
+	if strings.HasPrefix(image, "docker.io/") {
+		// docker.io is not the host of the registry API of Docker Hub
+		image = "registry-1." + image
+	}
+	return image
+}
+
+// fetchStatement returns the in-toto statement of a DSSE envelope layer, or nil if the envelope has another payload
+func fetchStatement(ctx context.Context, repo *remote.Repository, layer ocispec.Descriptor) (*Statement, error) {
+	reader, err := repo.Blobs().Fetch(ctx, layer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch attestation %s: %w", layer.Digest, err)
+	}
+	defer reader.Close()
+	data, err := io.ReadAll(io.LimitReader(reader, maxLayerSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attestation %s: %w", layer.Digest, err)
+	}
+	var envelope dsseEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attestation %s: %w", layer.Digest, err)
+	}
+	if envelope.PayloadType != inTotoPayloadType {
+		return nil, nil
+	}
+	payload, err := base64.StdEncoding.DecodeString(envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode payload of attestation %s: %w", layer.Digest, err)
+	}
+	var statement Statement
+	if err := json.Unmarshal(payload, &statement); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal payload of attestation %s: %w", layer.Digest, err)
+	}
+	return &statement, nil
+}
+
+// VerifySBOMStatements verifies that the statements contain an SBOM whose components all have a license of
+// allowedLicenses. The licenses are not checked if allowedLicenses is empty.
+func VerifySBOMStatements(image string, statements []Statement, allowedLicenses []string) error {
+	var sbom *SBOM
+	for _, statement := range statements {
+		parsed, err := ParseSBOM(statement.PredicateType, statement.Predicate)
+		if err != nil {
+			return &VerificationError{Image: image, Reason: ReasonVerificationFailed, Err: err}
+		}
+		if parsed != nil {
+			sbom = parsed
+			break
+		}
+	}
+	if sbom == nil {
+		return &VerificationError{Image: image, Reason: ReasonSBOMMissing}
+	}
+	if len(allowedLicenses) == 0 {
+		return nil
+	}
+
+	allowed := map[string]bool{}
+	for _, license := range allowedLicenses {
+		allowed[strings.ToLower(license)] = true
+	}
+	var unlicensed []string
+	notPermitted := map[string]bool{}
+	for _, component := range sbom.Components {
+		if len(component.Licenses) == 0 {
+			unlicensed = append(unlicensed, component.Name)
+			continue
+		}
+		for _, license := range component.Licenses {
+			if !allowed[strings.ToLower(license)] {
+				notPermitted[license] = true
+			}
+		}
+	}
+	if len(notPermitted) > 0 {
+		licenses := make([]string, 0, len(notPermitted))
+		for license := range notPermitted {
+			licenses = append(licenses, license)
+		}
+		sort.Strings(licenses)
+		return &VerificationError{Image: image, Reason: ReasonLicenseNotPermitted, Details: licenses}
+	}
+	if len(unlicensed) > 0 {
+		sort.Strings(unlicensed)
+		return &VerificationError{Image: image, Reason: ReasonLicenseMissing, Details: unlicensed}
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package attestation
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// mockRegistry is an OCI registry serving manifests by repository and reference, and blobs by digest
+type mockRegistry struct {
+	manifests map[string][]byte
+	blobs     map[digest.Digest][]byte
+}
+
+func (r *mockRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.URL.Path == "/v2/" {
+		return
+	}
+	path := strings.TrimPrefix(req.URL.Path, "/v2/")
+	var data []byte
+	mediaType := "application/octet-stream"
+	if i := strings.Index(path, "/manifests/"); i >= 0 {
+		repo, ref := path[:i], path[i+len("/manifests/"):]
+		data = r.manifests[repo+":"+ref]
+		if data == nil {
+			data = r.blobs[digest.Digest(ref)]
+		}
+		mediaType = ocispec.MediaTypeImageManifest
+	} else if i := strings.Index(path, "/blobs/"); i >= 0 {
+		data = r.blobs[digest.Digest(path[i+len("/blobs/"):])]
+	}
+	if data == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", mediaType)
+	w.Header().Set("Docker-Content-Digest", digest.FromBytes(data).String())
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	if req.Method != http.MethodHead {
+		_, _ = w.Write(data)
+	}
+}
+
+// addManifest adds a manifest with the given layers, and returns its digest
+func (r *mockRegistry) addManifest(t *testing.T, repo, tag string, layers ...ocispec.Descriptor) digest.Digest {
+	t.Helper()
+	config := r.addBlob([]byte("{}"), ocispec.MediaTypeImageConfig)
+	if layers == nil {
+		layers = []ocispec.Descriptor{}
+	}
+	manifest, err := json.Marshal(ocispec.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    config,
+		Layers:    layers,
+	})
+	require.NoError(t, err)
+	r.manifests[repo+":"+tag] = manifest
+	r.blobs[digest.FromBytes(manifest)] = manifest
+	return digest.FromBytes(manifest)
+}
+
+func (r *mockRegistry) addBlob(data []byte, mediaType string) ocispec.Descriptor {
+	r.blobs[digest.FromBytes(data)] = data
+	return ocispec.Descriptor{MediaType: mediaType, Digest: digest.FromBytes(data), Size: int64(len(data))}
+}
+
+// addAttestation attaches an in-toto statement with the given predicate to the image, the way cosign attest does
+func (r *mockRegistry) addAttestation(t *testing.T, repo string, image digest.Digest, predicateType string, predicate string) {
+	t.Helper()
+	statement, err := json.Marshal(map[string]interface{}{
+		"_type":         "https://in-toto.io/Statement/v0.1",
+		"predicateType": predicateType,
+		"predicate":     json.RawMessage(predicate),
+	})
+	require.NoError(t, err)
+	envelope, err := json.Marshal(dsseEnvelope{PayloadType: inTotoPayloadType, Payload: base64.StdEncoding.EncodeToString(statement)})
+	require.NoError(t, err)
+	r.addManifest(t, repo, "sha256-"+image.Encoded()+".att", r.addBlob(envelope, dsseEnvelopeMediaType))
+}
+
+func newMockRegistry(t *testing.T) (*mockRegistry, string) {
+	t.Helper()
+	registry := &mockRegistry{manifests: map[string][]byte{}, blobs: map[digest.Digest][]byte{}}
+	server := httptest.NewServer(registry)
+	t.Cleanup(server.Close)
+	return registry, strings.TrimPrefix(server.URL, "http://")
+}
+
+const cycloneDXPredicate = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "components": [
+    {"name": "openssl", "version": "3.0.0", "licenses": [{"license": {"id": "Apache-2.0"}}]},
+    {"name": "glibc", "version": "2.36", "licenses": [{"expression": "LGPL-2.1-or-later OR GPL-2.0-only WITH GCC-exception-2.0"}]}
+  ]
+}`
+
+const spdxPredicate = `{
+  "spdxVersion": "SPDX-2.3",
+  "packages": [
+    {"name": "musl", "versionInfo": "1.2.4", "licenseConcluded": "MIT"},
+    {"name": "busybox", "versionInfo": "1.36.1", "licenseConcluded": "NOASSERTION", "licenseDeclared": "GPL-2.0-only"},
+    {"name": "unknown", "licenseConcluded": "NOASSERTION"}
+  ]
+}`
+
+func TestRegistryVerifier_VerifySBOM(t *testing.T) {
+	registry, host := newMockRegistry(t)
+	cyclonedx := registry.addManifest(t, "org/cyclonedx", "1.0.0")
+	registry.addAttestation(t, "org/cyclonedx", cyclonedx, PredicateTypeCycloneDX, cycloneDXPredicate)
+	spdx := registry.addManifest(t, "org/spdx", "1.0.0")
+	registry.addAttestation(t, "org/spdx", spdx, PredicateTypeSPDX, spdxPredicate)
+	provenance := registry.addManifest(t, "org/provenance", "1.0.0")
+	registry.addAttestation(t, "org/provenance", provenance, "https://slsa.dev/provenance/v0.2", `{}`)
+	registry.addManifest(t, "org/unattested", "1.0.0")
+
+	verifier := &registryVerifier{plainHTTP: true}
+	ctx := context.Background()
+
+	t.Run("CycloneDX", func(t *testing.T) {
+		require.NoError(t, verifier.VerifySBOM(ctx, host+"/org/cyclonedx:1.0.0", nil))
+		require.NoError(t, verifier.VerifySBOM(ctx, host+"/org/cyclonedx@"+cyclonedx.String(), []string{"Apache-2.0", "LGPL-2.1-or-later", "GPL-2.0-only"}))
+
+		err := verifier.VerifySBOM(ctx, host+"/org/cyclonedx:1.0.0", []string{"Apache-2.0"})
+		var verificationErr *VerificationError
+		require.ErrorAs(t, err, &verificationErr)
+		assert.Equal(t, ReasonLicenseNotPermitted, verificationErr.Reason)
+		assert.Equal(t, []string{"GPL-2.0-only", "LGPL-2.1-or-later"}, verificationErr.Details)
+	})
+
+	t.Run("SPDX", func(t *testing.T) {
+		require.NoError(t, verifier.VerifySBOM(ctx, host+"/org/spdx:1.0.0", nil))
+
+		err := verifier.VerifySBOM(ctx, host+"/org/spdx:1.0.0", []string{"mit", "GPL-2.0-only"})
+		var verificationErr *VerificationError
+		require.ErrorAs(t, err, &verificationErr)
+		assert.Equal(t, ReasonLicenseMissing, verificationErr.Reason)
+		assert.Equal(t, []string{"unknown"}, verificationErr.Details)
+	})
+
+	t.Run("SBOMMissing", func(t *testing.T) {
+		for _, image := range []string{host + "/org/unattested:1.0.0", host + "/org/provenance:1.0.0"} {
+			err := verifier.VerifySBOM(ctx, image, nil)
+			var verificationErr *VerificationError
+			require.ErrorAs(t, err, &verificationErr)
+			assert.Equal(t, ReasonSBOMMissing, verificationErr.Reason)
+			assert.Equal(t, "image "+image+" has no SBOM attestation", err.Error())
+		}
+	})
+
+	t.Run("ImageNotFound", func(t *testing.T) {
+		err := verifier.VerifySBOM(ctx, host+"/org/missing:1.0.0", nil)
+		var verificationErr *VerificationError
+		require.ErrorAs(t, err, &verificationErr)
+		assert.Equal(t, ReasonVerificationFailed, verificationErr.Reason)
+	})
+}
+
+func TestParseLicenseExpression(t *testing.T) {
+	assert.Equal(t, []string{"MIT"}, parseLicenseExpression("MIT"))
+	assert.Equal(t, []string{"MIT", "Apache-2.0"}, parseLicenseExpression("MIT OR Apache-2.0"))
+	assert.Equal(t, []string{"MIT", "Apache-2.0", "GPL-2.0-only"}, parseLicenseExpression("(MIT AND Apache-2.0) or GPL-2.0-only WITH Classpath-exception-2.0"))
+}
+
+func TestRegistryReference(t *testing.T) {
+	assert.Equal(t, "registry-1.docker.io/library/nginx:1.25", registryReference("nginx:1.25"))
+	assert.Equal(t, "registry-1.docker.io/argoproj/argocd:v2.12.0", registryReference("argoproj/argocd:v2.12.0"))
+	assert.Equal(t, "registry-1.docker.io/argoproj/argocd:v2.12.0", registryReference("docker.io/argoproj/argocd:v2.12.0"))
+	assert.Equal(t, "quay.io/argoproj/argocd:v2.12.0", registryReference("quay.io/argoproj/argocd:v2.12.0"))
+	assert.Equal(t, "localhost/app", registryReference("localhost/app"))
+}
+
+func TestImages(t *testing.T) {
+	deployment := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "Deployment",
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"initContainers": []interface{}{map[string]interface{}{"name": "init", "image": "busybox:1.36"}},
+					"containers":     []interface{}{map[string]interface{}{"name": "app", "image": "nginx:1.25"}},
+				},
+			},
+		},
+	}}
+	cronJob := &unstructured.Unstructured{Object: map[string]interface{}{
+		"kind": "CronJob",
+		"spec": map[string]interface{}{
+			"jobTemplate": map[string]interface{}{"spec": map[string]interface{}{"template": map[string]interface{}{"spec": map[string]interface{}{
+				"containers": []interface{}{map[string]interface{}{"name": "job", "image": "nginx:1.25"}},
+			}}}},
+		},
+	}}
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{"kind": "ConfigMap", "data": map[string]interface{}{"image": "ignored"}}}
+
+	assert.Equal(t, []string{"busybox:1.36", "nginx:1.25"}, Images([]*unstructured.Unstructured{deployment, nil, cronJob, configMap}))
+}
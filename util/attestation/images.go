@@ -0,0 +1,54 @@
+package attestation
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// containerFields are the fields of pod specs holding containers
+var containerFields = map[string]bool{
+	"containers":          true,
+	"initContainers":      true,
+	"ephemeralContainers": true,
+}
+
+// Images returns the container images referenced by the pod specs and pod templates of the objects, including those
+// of custom resources
+func Images(objs []*unstructured.Unstructured) []string {
+	images := map[string]bool{}
+	for _, obj := range objs {
+		if obj != nil {
+			collectImages(obj.Object, images)
+		}
+	}
+	result := make([]string, 0, len(images))
+	for image := range images {
+		result = append(result, image)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func collectImages(value interface{}, images map[string]bool) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if containers, ok := field.([]interface{}); ok && containerFields[key] {
+				for _, container := range containers {
+					if c, ok := container.(map[string]interface{}); ok {
+						if image, ok := c["image"].(string); ok && image != "" {
+							images[image] = true
+						}
+					}
+				}
+				continue
+			}
+			collectImages(field, images)
+		}
+	case []interface{}:
+		for _, item := range v {
+			collectImages(item, images)
+		}
+	}
+}
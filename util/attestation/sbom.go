@@ -0,0 +1,122 @@
+package attestation
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SBOM is the list of components of a software bill of materials, along with their licenses
+type SBOM struct {
+	Components []Component
+}
+
+// Component is a component of an SBOM
+type Component struct {
+	Name string
+	// Licenses are the SPDX identifiers or names of the licenses of the component
+	Licenses []string
+}
+
+type cycloneDXLicenses []struct {
+	License *struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"license"`
+	Expression string `json:"expression"`
+}
+
+type cycloneDXBOM struct {
+	Components []struct {
+		Name     string            `json:"name"`
+		Version  string            `json:"version"`
+		Licenses cycloneDXLicenses `json:"licenses"`
+	} `json:"components"`
+}
+
+type spdxDocument struct {
+	Packages []struct {
+		Name             string `json:"name"`
+		VersionInfo      string `json:"versionInfo"`
+		LicenseConcluded string `json:"licenseConcluded"`
+		LicenseDeclared  string `json:"licenseDeclared"`
+	} `json:"packages"`
+}
+
+var (
+	// spdxExpressionOperators matches the operators and parentheses of SPDX license expressions
+	spdxExpressionOperators = regexp.MustCompile(`[()]|\s+(?i:AND|OR)\s+`)
+	// spdxExpressionExceptions matches the license exceptions of SPDX license expressions
+	spdxExpressionExceptions = regexp.MustCompile(`\s+(?i:WITH)\s+[^\s()]+`)
+)
+
+// ParseSBOM returns the SBOM of an in-toto predicate, or nil if the predicate type is not an SBOM
+func ParseSBOM(predicateType string, predicate []byte) (*SBOM, error) {
+	switch predicateType {
+	case PredicateTypeCycloneDX:
+		var bom cycloneDXBOM
+		if err := json.Unmarshal(predicate, &bom); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal CycloneDX SBOM: %w", err)
+		}
+		sbom := &SBOM{}
+		for _, c := range bom.Components {
+			component := Component{Name: componentName(c.Name, c.Version)}
+			for _, l := range c.Licenses {
+				switch {
+				case l.Expression != "":
+					component.Licenses = append(component.Licenses, parseLicenseExpression(l.Expression)...)
+				case l.License != nil && l.License.ID != "":
+					component.Licenses = append(component.Licenses, l.License.ID)
+				case l.License != nil && l.License.Name != "":
+					component.Licenses = append(component.Licenses, l.License.Name)
+				}
+			}
+			sbom.Components = append(sbom.Components, component)
+		}
+		return sbom, nil
+	case PredicateTypeSPDX:
+		var doc spdxDocument
+		if err := json.Unmarshal(predicate, &doc); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal SPDX SBOM: %w", err)
+		}
+		sbom := &SBOM{}
+		for _, p := range doc.Packages {
+			expression := p.LicenseConcluded
+			if !isSPDXLicenseAsserted(expression) {
+				expression = p.LicenseDeclared
+			}
+			component := Component{Name: componentName(p.Name, p.VersionInfo)}
+			if isSPDXLicenseAsserted(expression) {
+				component.Licenses = parseLicenseExpression(expression)
+			}
+			sbom.Components = append(sbom.Components, component)
+		}
+		return sbom, nil
+	}
+	return nil, nil
+}
+
+func componentName(name, version string) string {
+	if version == "" {
+		return name
+	}
+	return name + "@" + version
+}
+
+func isSPDXLicenseAsserted(expression string) bool {
+	return expression != "" && expression != "NOASSERTION" && expression != "NONE"
+}
+
+// parseLicenseExpression returns the licenses of an SPDX license expression, e.g. MIT and Apache-2.0 for
+// `MIT OR Apache-2.0`. License exceptions are ignored.
+func parseLicenseExpression(expression string) []string {
+	var licenses []string
+	expression = spdxExpressionExceptions.ReplaceAllString(expression, "")
+	for _, license := range spdxExpressionOperators.Split(expression, -1) {
+		if license = strings.TrimSpace(license); license != "" {
+			licenses = append(licenses, license)
+		}
+	}
+	return licenses
+}
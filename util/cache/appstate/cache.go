@@ -2,10 +2,13 @@ package appstate
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"time"
 
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
 	"github.com/spf13/cobra"
 
 	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
@@ -96,6 +99,21 @@ func (c *Cache) SetAppResourcesTree(appName string, resourcesTree *appv1.Applica
 	return c.Cache.NotifyUpdated(appManagedResourcesKey(appName))
 }
 
+func appStatusKey(appName string) string {
+	return fmt.Sprintf("argocd:app-status:%s", appName)
+}
+
+// OnAppStatusChanged calls the callback each time the application controller updates the status of the application,
+// until the context is done
+func (c *Cache) OnAppStatusChanged(ctx context.Context, appName string, callback func() error) error {
+	return c.Cache.OnUpdated(ctx, appStatusKey(appName), callback)
+}
+
+// NotifyAppStatusChanged notifies the subscribers of OnAppStatusChanged that the status of the application was updated
+func (c *Cache) NotifyAppStatusChanged(appName string) error {
+	return c.Cache.NotifyUpdated(appStatusKey(appName))
+}
+
 func (c *Cache) SetClusterInfo(server string, info *appv1.ClusterInfo) error {
 	return c.SetItem(clusterInfoKey(server), info, clusterInfoCacheExpiration, info == nil)
 }
@@ -104,3 +122,71 @@ func (c *Cache) GetClusterInfo(server string, res *appv1.ClusterInfo) error {
 	err := c.GetItem(clusterInfoKey(server), &res)
 	return err
 }
+
+// ResourceHealthEvent records the health status of an application resource at a point in time
+type ResourceHealthEvent struct {
+	Timestamp time.Time               `json:"timestamp"`
+	Status    health.HealthStatusCode `json:"status"`
+	Message   string                  `json:"message,omitempty"`
+}
+
+func appResourceHealthKey(appName string, key kube.ResourceKey) string {
+	return fmt.Sprintf("app|resource-health|%s|%s", appName, key.String())
+}
+
+// AddResourceHealthEvent appends an event to the health timeline of an application resource unless the resource
+// already has the same health status. Events older than the retention are dropped and the whole timeline expires
+// once no event was recorded during the retention.
+func (c *Cache) AddResourceHealthEvent(appName string, key kube.ResourceKey, event ResourceHealthEvent, retention time.Duration) error {
+	events, err := c.GetResourceHealthEvents(appName, key)
+	if err != nil && !errors.Is(err, ErrCacheMiss) {
+		return err
+	}
+	if len(events) > 0 && events[len(events)-1].Status == event.Status {
+		return nil
+	}
+	since := event.Timestamp.Add(-retention)
+	i := sort.Search(len(events), func(i int) bool {
+		return !events[i].Timestamp.Before(since)
+	})
+	events = append(events[i:], event)
+	return c.SetItem(appResourceHealthKey(appName, key), events, retention, false)
+}
+
+// GetResourceHealthEvents returns the health timeline of an application resource, oldest event first
+func (c *Cache) GetResourceHealthEvents(appName string, key kube.ResourceKey) ([]ResourceHealthEvent, error) {
+	var events []ResourceHealthEvent
+	err := c.GetItem(appResourceHealthKey(appName, key), &events)
+	return events, err
+}
+
+// ResourceSnapshot is the live state of the resources of an application captured before a sync applied them
+type ResourceSnapshot struct {
+	CreatedAt time.Time               `json:"createdAt"`
+	Resources []ResourceSnapshotEntry `json:"resources"`
+}
+
+// ResourceSnapshotEntry is the live state of a resource captured before a sync applied it
+type ResourceSnapshotEntry struct {
+	ResourceRef     appv1.ResourceRef `json:"resourceRef"`
+	LiveManifest    string            `json:"liveManifest"`
+	ResourceVersion string            `json:"resourceVersion,omitempty"`
+}
+
+func preSyncSnapshotKey(appName string, syncID string) string {
+	return fmt.Sprintf("pre-sync-snapshot:%s:%s", appName, syncID)
+}
+
+// SetPreSyncSnapshot stores the snapshot taken before the sync with the given ID, which expires after the retention
+func (c *Cache) SetPreSyncSnapshot(appName string, syncID string, snapshot *ResourceSnapshot, retention time.Duration) error {
+	return c.SetItem(preSyncSnapshotKey(appName, syncID), snapshot, retention, false)
+}
+
+// GetPreSyncSnapshot returns the snapshot taken before the sync with the given ID
+func (c *Cache) GetPreSyncSnapshot(appName string, syncID string) (*ResourceSnapshot, error) {
+	snapshot := &ResourceSnapshot{}
+	if err := c.GetItem(preSyncSnapshotKey(appName, syncID), snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
@@ -4,6 +4,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
 	"github.com/spf13/cobra"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -64,3 +66,60 @@ func TestAddCacheFlagsToCmd(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 1*time.Hour, cache.appStateCacheExpiration)
 }
+
+func TestCache_AddResourceHealthEvent(t *testing.T) {
+	cache := newFixtures().Cache
+	key := kube.NewResourceKey("apps", "Deployment", "default", "guestbook")
+	// cache miss
+	_, err := cache.GetResourceHealthEvents("my-appname", key)
+	assert.Equal(t, ErrCacheMiss, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	healthy := ResourceHealthEvent{Timestamp: now.Add(-3 * time.Hour), Status: health.HealthStatusHealthy}
+	degraded := ResourceHealthEvent{Timestamp: now.Add(-2 * time.Hour), Status: health.HealthStatusDegraded, Message: "Back-off pulling image"}
+	stillDegraded := ResourceHealthEvent{Timestamp: now.Add(-1 * time.Hour), Status: health.HealthStatusDegraded, Message: "Back-off restarting container"}
+	recovered := ResourceHealthEvent{Timestamp: now, Status: health.HealthStatusHealthy}
+	for _, event := range []ResourceHealthEvent{healthy, degraded, stillDegraded} {
+		require.NoError(t, cache.AddResourceHealthEvent("my-appname", key, event, 24*time.Hour))
+	}
+	events, err := cache.GetResourceHealthEvents("my-appname", key)
+	require.NoError(t, err)
+	// unchanged health status is not recorded
+	assert.Equal(t, []ResourceHealthEvent{healthy, degraded}, events)
+
+	// events older than the retention are dropped
+	require.NoError(t, cache.AddResourceHealthEvent("my-appname", key, recovered, 150*time.Minute))
+	events, err = cache.GetResourceHealthEvents("my-appname", key)
+	require.NoError(t, err)
+	assert.Equal(t, []ResourceHealthEvent{degraded, recovered}, events)
+
+	// timelines are kept per application
+	_, err = cache.GetResourceHealthEvents("other-appname", key)
+	assert.Equal(t, ErrCacheMiss, err)
+}
+
+func TestCache_PreSyncSnapshot(t *testing.T) {
+	cache := newFixtures().Cache
+	// cache miss
+	_, err := cache.GetPreSyncSnapshot("my-appname", "00001-abcde")
+	assert.Equal(t, ErrCacheMiss, err)
+
+	snapshot := &ResourceSnapshot{
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Resources: []ResourceSnapshotEntry{{
+			ResourceRef:     ResourceRef{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "default", Name: "guestbook"},
+			LiveManifest:    `{"apiVersion":"apps/v1","kind":"Deployment"}`,
+			ResourceVersion: "123",
+		}},
+	}
+	require.NoError(t, cache.SetPreSyncSnapshot("my-appname", "00001-abcde", snapshot, time.Hour))
+	value, err := cache.GetPreSyncSnapshot("my-appname", "00001-abcde")
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, value)
+
+	// snapshots are kept per application and sync
+	_, err = cache.GetPreSyncSnapshot("my-appname", "00002-fghij")
+	assert.Equal(t, ErrCacheMiss, err)
+	_, err = cache.GetPreSyncSnapshot("other-appname", "00001-abcde")
+	assert.Equal(t, ErrCacheMiss, err)
+}
@@ -293,6 +293,15 @@ func (c *Cache) GetItem(key string, item interface{}) error {
 	return client.Get(key, item)
 }
 
+// ListKeys returns a page of the keys of the current cache version, as returned by the client if it supports listing keys
+func (c *Cache) ListKeys(ctx context.Context, cursor uint64, count int64) ([]KeyInfo, uint64, error) {
+	lister, ok := c.GetClient().(KeyLister)
+	if !ok {
+		return nil, 0, fmt.Errorf("cache client %T does not support listing keys", c.GetClient())
+	}
+	return lister.ListKeys(ctx, cursor, fmt.Sprintf("*|%s*", common.CacheVersion), count)
+}
+
 func (c *Cache) OnUpdated(ctx context.Context, key string, callback func() error) error {
 	return c.client.OnUpdated(ctx, c.generateFullKey(key), callback)
 }
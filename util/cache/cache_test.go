@@ -33,7 +33,7 @@ func TestCacheClient(t *testing.T) {
 	defer stopRedis()
 	redisCache := NewRedisCache(clientRedis, 5*time.Second, RedisCompressionNone)
 	clientMemCache := NewInMemoryCache(60 * time.Second)
-	twoLevelClient := NewTwoLevelClient(redisCache, 5*time.Second)
+	twoLevelClient := NewTwoLevelClient(redisCache, 5*time.Second, false)
 	// Run tests for both Redis and InMemoryCache
 	for _, client := range []CacheClient{clientMemCache, redisCache, twoLevelClient} {
 		cache := NewCache(client)
@@ -9,6 +9,7 @@ import (
 var (
 	ErrCacheMiss      = errors.New("cache: key is missing")
 	ErrCacheKeyLocked = errors.New("cache: key is locked")
+	ErrItemTooLarge   = errors.New("cache: item is too large")
 	CacheLockedValue  = "locked"
 )
 
@@ -35,3 +36,16 @@ type CacheClient interface {
 	OnUpdated(ctx context.Context, key string, callback func() error) error
 	NotifyUpdated(key string) error
 }
+
+// KeyInfo describes a key stored in a cache
+type KeyInfo struct {
+	Key string
+	// TTL is the remaining time to live of the key, negative if the key does not expire
+	TTL       time.Duration
+	SizeBytes int64
+}
+
+// KeyLister is implemented by the cache clients able to enumerate the keys they store
+type KeyLister interface {
+	ListKeys(ctx context.Context, cursor uint64, match string, count int64) ([]KeyInfo, uint64, error)
+}
@@ -0,0 +1,337 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CompressedObject is an object stored by an informer as compressed JSON. Only the metadata needed to identify the
+// object and the values of the informer indexes are kept uncompressed.
+type CompressedObject struct {
+	meta metav1.ObjectMeta
+	// indexValues are the values of the informer indexes for the object, computed when the object was compressed
+	indexValues map[string][]string
+	data        []byte
+}
+
+// GetObjectMeta returns the name, namespace, UID and resource version of the object, which are enough for the key
+// functions of informers
+func (o *CompressedObject) GetObjectMeta() metav1.Object {
+	return &o.meta
+}
+
+// Size returns the size of the compressed object in bytes
+func (o *CompressedObject) Size() int {
+	return len(o.data)
+}
+
+// CompressedObjectCache compresses the objects stored by an informer using zstd, and decompresses them when they are
+// retrieved from the informer store or passed to the event handlers of the informer. It trades some CPU for memory:
+// the store of an informer holding thousands of large objects, such as Applications with their resource statuses,
+// shrinks to a fraction of its size.
+type CompressedObjectCache struct {
+	newObject func() runtime.Object
+	encoder   *zstd.Encoder
+	decoder   *zstd.Decoder
+
+	lock     sync.RWMutex
+	indexers cache.Indexers
+}
+
+// NewCompressedObjectCache returns a cache compressing objects which are decompressed into the objects returned by
+// newObject, e.g. func() runtime.Object { return &v1alpha1.Application{} }
+func NewCompressedObjectCache(newObject func() runtime.Object) (*CompressedObjectCache, error) {
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+	}
+	decoder, err := zstd.NewReader(nil, zstd.WithDecoderConcurrency(0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+	}
+	return &CompressedObjectCache{
+		newObject: newObject,
+		encoder:   encoder,
+		decoder:   decoder,
+		indexers:  cache.Indexers{},
+	}, nil
+}
+
+// Compress returns the compressed object. It is the transform function of the informer, and computes the values of
+// the informer indexes on the uncompressed object, so that index functions modifying the object, e.g. to default some
+// of its fields, behave as they do without compression. Objects which are already compressed are compressed again,
+// since informers transform the objects of their store once more when they are resynced.
+func (c *CompressedObjectCache) Compress(obj interface{}) (interface{}, error) {
+	if compressed, ok := obj.(*CompressedObject); ok {
+		decompressed, err := c.decompress(compressed)
+		if err != nil {
+			return nil, err
+		}
+		obj = decompressed
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compress object: %w", err)
+	}
+
+	c.lock.RLock()
+	indexValues := make(map[string][]string, len(c.indexers))
+	for name, indexFunc := range c.indexers {
+		values, err := indexFunc(obj)
+		if err != nil {
+			c.lock.RUnlock()
+			return nil, fmt.Errorf("failed to compute index %s of object %s/%s: %w", name, accessor.GetNamespace(), accessor.GetName(), err)
+		}
+		indexValues[name] = values
+	}
+	c.lock.RUnlock()
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal object %s/%s: %w", accessor.GetNamespace(), accessor.GetName(), err)
+	}
+	return &CompressedObject{
+		meta: metav1.ObjectMeta{
+			Name:            accessor.GetName(),
+			Namespace:       accessor.GetNamespace(),
+			UID:             accessor.GetUID(),
+			ResourceVersion: accessor.GetResourceVersion(),
+		},
+		indexValues: indexValues,
+		data:        c.encoder.EncodeAll(data, make([]byte, 0, len(data)/4)),
+	}, nil
+}
+
+func (c *CompressedObjectCache) decompress(compressed *CompressedObject) (runtime.Object, error) {
+	data, err := c.decoder.DecodeAll(compressed.data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress object %s/%s: %w", compressed.meta.Namespace, compressed.meta.Name, err)
+	}
+	obj := c.newObject()
+	if err := json.Unmarshal(data, obj); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal object %s/%s: %w", compressed.meta.Namespace, compressed.meta.Name, err)
+	}
+	return obj, nil
+}
+
+// Decompress returns the uncompressed object, or the object itself if it is not compressed. Compressed objects of
+// tombstones are decompressed as well.
+func (c *CompressedObjectCache) Decompress(obj interface{}) (interface{}, error) {
+	switch o := obj.(type) {
+	case *CompressedObject:
+		return c.decompress(o)
+	case cache.DeletedFinalStateUnknown:
+		decompressed, err := c.Decompress(o.Obj)
+		if err != nil {
+			return nil, err
+		}
+		return cache.DeletedFinalStateUnknown{Key: o.Key, Obj: decompressed}, nil
+	}
+	return obj, nil
+}
+
+func (c *CompressedObjectCache) decompressAll(objs []interface{}) []interface{} {
+	res := make([]interface{}, 0, len(objs))
+	for _, obj := range objs {
+		decompressed, err := c.Decompress(obj)
+		if err != nil {
+			log.Warnf("Failed to read object from informer cache: %v", err)
+			continue
+		}
+		res = append(res, decompressed)
+	}
+	return res
+}
+
+// Indexers registers the indexers, whose values are computed when objects are compressed, and returns the indexers
+// to create the informer with. The returned indexers read the values stored in compressed objects, and call the
+// registered indexers for other objects, such as the objects passed to the Index method of the informer indexer.
+func (c *CompressedObjectCache) Indexers(indexers cache.Indexers) cache.Indexers {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	res := cache.Indexers{}
+	for name, indexFunc := range indexers {
+		c.indexers[name] = indexFunc
+		name, indexFunc := name, indexFunc
+		res[name] = func(obj interface{}) ([]string, error) {
+			if compressed, ok := obj.(*CompressedObject); ok {
+				return compressed.indexValues[name], nil
+			}
+			return indexFunc(obj)
+		}
+	}
+	return res
+}
+
+// Informer makes the informer store compressed objects. The informer must have been created with the indexers
+// returned by Indexers and must not be started. The returned informer must be used instead of the given one: its
+// store and event handlers only see uncompressed objects.
+func (c *CompressedObjectCache) Informer(informer cache.SharedIndexInformer) (cache.SharedIndexInformer, error) {
+	if err := informer.SetTransform(c.Compress); err != nil {
+		return nil, fmt.Errorf("failed to set informer transform: %w", err)
+	}
+	return &compressedInformer{SharedIndexInformer: informer, objects: c}, nil
+}
+
+// compressedInformer is an informer storing compressed objects, which exposes them uncompressed
+type compressedInformer struct {
+	cache.SharedIndexInformer
+	objects *CompressedObjectCache
+}
+
+func (i *compressedInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	return i.SharedIndexInformer.AddEventHandler(&compressedEventHandler{handler: handler, objects: i.objects})
+}
+
+func (i *compressedInformer) AddEventHandlerWithResyncPeriod(handler cache.ResourceEventHandler, resyncPeriod time.Duration) (cache.ResourceEventHandlerRegistration, error) {
+	return i.SharedIndexInformer.AddEventHandlerWithResyncPeriod(&compressedEventHandler{handler: handler, objects: i.objects}, resyncPeriod)
+}
+
+func (i *compressedInformer) GetStore() cache.Store {
+	return i.GetIndexer()
+}
+
+func (i *compressedInformer) GetIndexer() cache.Indexer {
+	return &compressedIndexer{Indexer: i.SharedIndexInformer.GetIndexer(), objects: i.objects}
+}
+
+func (i *compressedInformer) AddIndexers(indexers cache.Indexers) error {
+	return i.SharedIndexInformer.AddIndexers(i.objects.Indexers(indexers))
+}
+
+func (i *compressedInformer) SetTransform(transform cache.TransformFunc) error {
+	return i.SharedIndexInformer.SetTransform(func(obj interface{}) (interface{}, error) {
+		obj, err := i.objects.Decompress(obj)
+		if err != nil {
+			return nil, err
+		}
+		obj, err = transform(obj)
+		if err != nil {
+			return nil, err
+		}
+		return i.objects.Compress(obj)
+	})
+}
+
+// compressedEventHandler passes uncompressed objects to an event handler
+type compressedEventHandler struct {
+	handler cache.ResourceEventHandler
+	objects *CompressedObjectCache
+}
+
+func (h *compressedEventHandler) OnAdd(obj interface{}, isInInitialList bool) {
+	obj, err := h.objects.Decompress(obj)
+	if err != nil {
+		log.Warnf("Failed to handle added object: %v", err)
+		return
+	}
+	h.handler.OnAdd(obj, isInInitialList)
+}
+
+func (h *compressedEventHandler) OnUpdate(oldObj, newObj interface{}) {
+	oldObj, err := h.objects.Decompress(oldObj)
+	if err != nil {
+		log.Warnf("Failed to handle updated object: %v", err)
+		return
+	}
+	newObj, err = h.objects.Decompress(newObj)
+	if err != nil {
+		log.Warnf("Failed to handle updated object: %v", err)
+		return
+	}
+	h.handler.OnUpdate(oldObj, newObj)
+}
+
+func (h *compressedEventHandler) OnDelete(obj interface{}) {
+	obj, err := h.objects.Decompress(obj)
+	if err != nil {
+		log.Warnf("Failed to handle deleted object: %v", err)
+		return
+	}
+	h.handler.OnDelete(obj)
+}
+
+// compressedIndexer compresses the objects it stores and decompresses the objects it returns
+type compressedIndexer struct {
+	cache.Indexer
+	objects *CompressedObjectCache
+}
+
+func (i *compressedIndexer) Add(obj interface{}) error {
+	compressed, err := i.objects.Compress(obj)
+	if err != nil {
+		return err
+	}
+	return i.Indexer.Add(compressed)
+}
+
+func (i *compressedIndexer) Update(obj interface{}) error {
+	compressed, err := i.objects.Compress(obj)
+	if err != nil {
+		return err
+	}
+	return i.Indexer.Update(compressed)
+}
+
+func (i *compressedIndexer) Replace(objs []interface{}, resourceVersion string) error {
+	compressed := make([]interface{}, len(objs))
+	for j := range objs {
+		var err error
+		if compressed[j], err = i.objects.Compress(objs[j]); err != nil {
+			return err
+		}
+	}
+	return i.Indexer.Replace(compressed, resourceVersion)
+}
+
+func (i *compressedIndexer) List() []interface{} {
+	return i.objects.decompressAll(i.Indexer.List())
+}
+
+func (i *compressedIndexer) Get(obj interface{}) (interface{}, bool, error) {
+	return i.decompressItem(i.Indexer.Get(obj))
+}
+
+func (i *compressedIndexer) GetByKey(key string) (interface{}, bool, error) {
+	return i.decompressItem(i.Indexer.GetByKey(key))
+}
+
+func (i *compressedIndexer) decompressItem(obj interface{}, exists bool, err error) (interface{}, bool, error) {
+	if err != nil || !exists {
+		return obj, exists, err
+	}
+	obj, err = i.objects.Decompress(obj)
+	if err != nil {
+		return nil, false, err
+	}
+	return obj, true, nil
+}
+
+func (i *compressedIndexer) Index(indexName string, obj interface{}) ([]interface{}, error) {
+	objs, err := i.Indexer.Index(indexName, obj)
+	if err != nil {
+		return nil, err
+	}
+	return i.objects.decompressAll(objs), nil
+}
+
+func (i *compressedIndexer) ByIndex(indexName, indexedValue string) ([]interface{}, error) {
+	objs, err := i.Indexer.ByIndex(indexName, indexedValue)
+	if err != nil {
+		return nil, err
+	}
+	return i.objects.decompressAll(objs), nil
+}
+
+func (i *compressedIndexer) AddIndexers(indexers cache.Indexers) error {
+	return i.Indexer.AddIndexers(i.objects.Indexers(indexers))
+}
@@ -0,0 +1,227 @@
+package cache_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	fcache "k8s.io/client-go/tools/cache/testing"
+
+	"github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	cacheutil "github.com/argoproj/argo-cd/v2/util/cache"
+)
+
+func newApplication(i int) *v1alpha1.Application {
+	app := &v1alpha1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("app-%d", i),
+			Namespace:       "argocd",
+			ResourceVersion: "1",
+			Labels:          map[string]string{"team": fmt.Sprintf("team-%d", i%10)},
+		},
+		Spec: v1alpha1.ApplicationSpec{
+			Project: "default",
+			Source: &v1alpha1.ApplicationSource{
+				RepoURL:        "https://github.com/argoproj/argocd-example-apps",
+				Path:           fmt.Sprintf("apps/app-%d", i),
+				TargetRevision: "HEAD",
+			},
+			Destination: v1alpha1.ApplicationDestination{Server: "https://kubernetes.default.svc", Namespace: fmt.Sprintf("app-%d", i)},
+		},
+		Status: v1alpha1.ApplicationStatus{
+			Sync:   v1alpha1.SyncStatus{Status: v1alpha1.SyncStatusCodeSynced, Revision: "0123456789abcdef0123456789abcdef01234567"},
+			Health: v1alpha1.HealthStatus{Status: "Healthy"},
+		},
+	}
+	for j := 0; j < 50; j++ {
+		app.Status.Resources = append(app.Status.Resources, v1alpha1.ResourceStatus{
+			Group:     "apps",
+			Version:   "v1",
+			Kind:      "Deployment",
+			Namespace: app.Spec.Destination.Namespace,
+			Name:      fmt.Sprintf("deployment-%d", j),
+			Status:    v1alpha1.SyncStatusCodeSynced,
+			Health:    &v1alpha1.HealthStatus{Status: "Healthy"},
+		})
+	}
+	for j := 0; j < 10; j++ {
+		app.Status.History = append(app.Status.History, v1alpha1.RevisionHistory{
+			ID:       int64(j),
+			Revision: fmt.Sprintf("%040d", j),
+			Source:   *app.Spec.Source,
+		})
+	}
+	return app
+}
+
+func newApplicationCache(t testing.TB) *cacheutil.CompressedObjectCache {
+	t.Helper()
+	objects, err := cacheutil.NewCompressedObjectCache(func() runtime.Object { return &v1alpha1.Application{} })
+	require.NoError(t, err)
+	return objects
+}
+
+func TestCompressedObjectCache_CompressDecompress(t *testing.T) {
+	objects := newApplicationCache(t)
+	app := newApplication(1)
+
+	compressed, err := objects.Compress(app)
+	require.NoError(t, err)
+	key, err := cache.MetaNamespaceKeyFunc(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, "argocd/app-1", key)
+
+	decompressed, err := objects.Decompress(compressed)
+	require.NoError(t, err)
+	assert.Equal(t, app, decompressed)
+
+	// informers transform the compressed objects of their store again when they resync
+	recompressed, err := objects.Compress(compressed)
+	require.NoError(t, err)
+	decompressed, err = objects.Decompress(recompressed)
+	require.NoError(t, err)
+	assert.Equal(t, app, decompressed)
+
+	decompressed, err = objects.Decompress(cache.DeletedFinalStateUnknown{Key: key, Obj: compressed})
+	require.NoError(t, err)
+	assert.Equal(t, cache.DeletedFinalStateUnknown{Key: key, Obj: app}, decompressed)
+}
+
+// TestCompressedObjectCache_MemoryReduction measures the size of a synthetic dataset of 1000 applications, compressed
+// and as JSON, which is smaller than the size of the objects in memory
+func TestCompressedObjectCache_MemoryReduction(t *testing.T) {
+	objects := newApplicationCache(t)
+	uncompressedSize, compressedSize := 0, 0
+	for i := 0; i < 1000; i++ {
+		app := newApplication(i)
+		data, err := json.Marshal(app)
+		require.NoError(t, err)
+		uncompressedSize += len(data)
+		compressed, err := objects.Compress(app)
+		require.NoError(t, err)
+		compressedSize += compressed.(*cacheutil.CompressedObject).Size()
+	}
+	reduction := 1 - float64(compressedSize)/float64(uncompressedSize)
+	t.Logf("uncompressed: %d bytes, compressed: %d bytes, reduction: %.0f%%", uncompressedSize, compressedSize, reduction*100)
+	assert.Greater(t, reduction, 0.6)
+}
+
+func TestCompressedObjectCache_Informer(t *testing.T) {
+	objects := newApplicationCache(t)
+	source := fcache.NewFakeControllerSource()
+	source.Add(newApplication(1))
+	source.Add(newApplication(2))
+
+	indexers := objects.Indexers(cache.Indexers{
+		cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+		"team": func(obj interface{}) ([]string, error) {
+			app := obj.(*v1alpha1.Application)
+			// index functions may default the fields of the objects
+			app.Spec.Destination.Name = "in-cluster"
+			return []string{app.Labels["team"]}, nil
+		},
+	})
+	informer, err := objects.Informer(cache.NewSharedIndexInformer(source, &v1alpha1.Application{}, 0, indexers))
+	require.NoError(t, err)
+
+	events := make(chan string, 10)
+	_, err = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			events <- "add " + obj.(*v1alpha1.Application).Name
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			events <- fmt.Sprintf("update %s %s->%s", newObj.(*v1alpha1.Application).Name, oldObj.(*v1alpha1.Application).Status.Health.Status, newObj.(*v1alpha1.Application).Status.Health.Status)
+		},
+		DeleteFunc: func(obj interface{}) {
+			events <- "delete " + obj.(*v1alpha1.Application).Name
+		},
+	})
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go informer.Run(ctx.Done())
+	require.True(t, cache.WaitForCacheSync(ctx.Done(), informer.HasSynced))
+	assert.ElementsMatch(t, []string{"add app-1", "add app-2"}, []string{<-events, <-events})
+
+	obj, exists, err := informer.GetIndexer().GetByKey("argocd/app-1")
+	require.NoError(t, err)
+	require.True(t, exists)
+	app := obj.(*v1alpha1.Application)
+	assert.Equal(t, "in-cluster", app.Spec.Destination.Name)
+	assert.Len(t, app.Status.Resources, 50)
+
+	assert.Len(t, informer.GetStore().List(), 2)
+	objs, err := informer.GetIndexer().ByIndex("team", "team-2")
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Equal(t, "app-2", objs[0].(*v1alpha1.Application).Name)
+	objs, err = informer.GetIndexer().Index(cache.NamespaceIndex, &metav1.ObjectMeta{Namespace: "argocd"})
+	require.NoError(t, err)
+	assert.Len(t, objs, 2)
+
+	updated := newApplication(1)
+	updated.Status.Health.Status = "Degraded"
+	source.Modify(updated)
+	assert.Equal(t, "update app-1 Healthy->Degraded", <-events)
+	source.Delete(updated)
+	assert.Equal(t, "delete app-1", <-events)
+	_, exists, err = informer.GetIndexer().GetByKey("argocd/app-1")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func BenchmarkCompressedObjectCache_Compress(b *testing.B) {
+	objects := newApplicationCache(b)
+	app := newApplication(1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := objects.Compress(app)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompressedObjectCache_Decompress(b *testing.B) {
+	objects := newApplicationCache(b)
+	compressed, err := objects.Compress(newApplication(1))
+	require.NoError(b, err)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := objects.Decompress(compressed)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkCompressedObjectCache_GetByKey(b *testing.B) {
+	objects := newApplicationCache(b)
+	for _, compressed := range []bool{false, true} {
+		b.Run(fmt.Sprintf("compressed=%t", compressed), func(b *testing.B) {
+			informer := cache.NewSharedIndexInformer(fcache.NewFakeControllerSource(), &v1alpha1.Application{}, 0, cache.Indexers{})
+			if compressed {
+				var err error
+				informer, err = objects.Informer(informer)
+				require.NoError(b, err)
+			}
+			for i := 0; i < 1000; i++ {
+				require.NoError(b, informer.GetIndexer().Add(newApplication(i)))
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_, _, err := informer.GetIndexer().GetByKey(fmt.Sprintf("argocd/app-%d", i%1000))
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
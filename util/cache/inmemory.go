@@ -24,7 +24,14 @@ func init() {
 var _ CacheClient = &InMemoryCache{}
 
 type InMemoryCache struct {
-	memCache *gocache.Cache
+	memCache     *gocache.Cache
+	maxItemBytes int64
+}
+
+// SetMaxItemBytes limits the size of the encoded items stored in memory, so that a few large items do not use up the
+// memory of many small ones. Larger items are rejected with ErrItemTooLarge. No limit applies if maxItemBytes is 0.
+func (i *InMemoryCache) SetMaxItemBytes(maxItemBytes int64) {
+	i.maxItemBytes = maxItemBytes
 }
 
 func (i *InMemoryCache) Set(item *Item) error {
@@ -33,6 +40,11 @@ func (i *InMemoryCache) Set(item *Item) error {
 	if err != nil {
 		return err
 	}
+	if i.maxItemBytes > 0 && int64(buf.Len()) > i.maxItemBytes {
+		// drop the previous value of the key, which would be outdated
+		i.memCache.Delete(item.Key)
+		return fmt.Errorf("%w: item of %d bytes exceeds the limit of %d bytes", ErrItemTooLarge, buf.Len(), i.maxItemBytes)
+	}
 	if item.CacheActionOpts.DisableOverwrite {
 		// go-redis doesn't throw an error on Set with NX, so absorbing here to keep the interface consistent
 		_ = i.memCache.Add(item.Key, buf, item.CacheActionOpts.Expiration)
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -26,3 +27,17 @@ func TestInMemoryCache(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, &foo{Bar: "bar"}, obj)
 }
+
+func TestInMemoryCache_MaxItemBytes(t *testing.T) {
+	cache := NewInMemoryCache(time.Minute)
+	cache.SetMaxItemBytes(10 * 1024 * 1024)
+
+	require.NoError(t, cache.Set(&Item{Key: "small", Object: "value"}))
+	err := cache.Set(&Item{Key: "large", Object: strings.Repeat("x", 15*1024*1024)})
+	require.ErrorIs(t, err, ErrItemTooLarge)
+
+	var output string
+	require.NoError(t, cache.Get("small", &output))
+	assert.Equal(t, "value", output)
+	assert.ErrorIs(t, cache.Get("large", &output), ErrCacheMiss)
+}
@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/stretchr/testify/mock"
@@ -72,3 +73,11 @@ func (c *MockCacheClient) NotifyUpdated(key string) error {
 	}
 	return c.BaseCache.NotifyUpdated(key)
 }
+
+func (c *MockCacheClient) ListKeys(ctx context.Context, cursor uint64, match string, count int64) ([]cache.KeyInfo, uint64, error) {
+	lister, ok := c.BaseCache.(cache.KeyLister)
+	if !ok {
+		return nil, 0, fmt.Errorf("cache client %T does not support listing keys", c.BaseCache)
+	}
+	return lister.ListKeys(ctx, cursor, match, count)
+}
@@ -167,6 +167,49 @@ func (r *redisCache) NotifyUpdated(key string) error {
 	return r.client.Publish(context.TODO(), key, "").Err()
 }
 
+// ListKeys returns a page of the keys matching the given pattern, along with their remaining time to live and the size
+// of their value, and the cursor to pass to retrieve the next page. The returned cursor is 0 once all keys are listed.
+func (r *redisCache) ListKeys(ctx context.Context, cursor uint64, match string, count int64) ([]KeyInfo, uint64, error) {
+	keys, next, err := r.client.Scan(ctx, cursor, match, count).Result()
+	if err != nil {
+		return nil, 0, fmt.Errorf("error scanning keys: %w", err)
+	}
+	if len(keys) == 0 {
+		return nil, next, nil
+	}
+
+	ttls := make([]*redis.DurationCmd, len(keys))
+	sizes := make([]*redis.IntCmd, len(keys))
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			ttls[i] = pipe.PTTL(ctx, key)
+			sizes[i] = pipe.StrLen(ctx, key)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, 0, fmt.Errorf("error reading key metadata: %w", err)
+	}
+
+	infos := make([]KeyInfo, 0, len(keys))
+	for i, key := range keys {
+		ttl, err := ttls[i].Result()
+		if err != nil {
+			return nil, 0, fmt.Errorf("error reading time to live of key %s: %w", key, err)
+		}
+		// the key expired or was deleted after it was scanned
+		if ttl == -2 {
+			continue
+		}
+		size, err := sizes[i].Result()
+		if err != nil {
+			return nil, 0, fmt.Errorf("error reading size of key %s: %w", key, err)
+		}
+		infos = append(infos, KeyInfo{Key: key, TTL: ttl, SizeBytes: size})
+	}
+	return infos, next, nil
+}
+
 type MetricsRegistry interface {
 	IncRedisRequest(failed bool)
 	ObserveRedisRequestDuration(duration time.Duration)
@@ -2,6 +2,8 @@ package cache
 
 import (
 	"context"
+	"errors"
+	"sync/atomic"
 	"time"
 
 	log "github.com/sirupsen/logrus"
@@ -9,13 +11,39 @@ import (
 
 // NewTwoLevelClient creates cache client that proxies requests to given external cache and tries to minimize
 // number of requests to external client by storing cache entries in local in-memory cache.
-func NewTwoLevelClient(client CacheClient, inMemoryExpiration time.Duration) *twoLevelClient {
-	return &twoLevelClient{inMemoryCache: NewInMemoryCache(inMemoryExpiration), externalCache: client}
+//
+// By default, a value which is already in memory is not written again to the external cache, so the external cache
+// is not refreshed if its copy expired or was overwritten by another replica. With writeThrough, every value is written
+// to the external cache, which keeps it up to date at the cost of a request to the external cache for every write.
+func NewTwoLevelClient(client CacheClient, inMemoryExpiration time.Duration, writeThrough bool) *twoLevelClient {
+	return &twoLevelClient{inMemoryCache: NewInMemoryCache(inMemoryExpiration), externalCache: client, writeThrough: writeThrough}
 }
 
 type twoLevelClient struct {
 	inMemoryCache *InMemoryCache
 	externalCache CacheClient
+	writeThrough  bool
+	onOversized   func()
+	// inMemoryHits and inMemoryMisses count the reads answered and not answered by the in-memory cache
+	inMemoryHits   atomic.Int64
+	inMemoryMisses atomic.Int64
+}
+
+// SetInMemoryMaxItemBytes limits the size of the items kept in memory. Larger items are only stored in the external
+// cache, and are read from it every time. No limit applies if maxItemBytes is 0.
+func (c *twoLevelClient) SetInMemoryMaxItemBytes(maxItemBytes int64) {
+	c.inMemoryCache.SetMaxItemBytes(maxItemBytes)
+}
+
+// OnOversizedItem registers a callback invoked whenever an item is not stored in memory because of its size
+func (c *twoLevelClient) OnOversizedItem(callback func()) {
+	c.onOversized = callback
+}
+
+// InMemoryStats returns the number of reads answered by the in-memory cache and the number of reads which fell back to
+// the external cache
+func (c *twoLevelClient) InMemoryStats() (hits int64, misses int64) {
+	return c.inMemoryHits.Load(), c.inMemoryMisses.Load()
 }
 
 func (c *twoLevelClient) Rename(oldKey string, newKey string, expiration time.Duration) error {
@@ -27,17 +55,25 @@ func (c *twoLevelClient) Rename(oldKey string, newKey string, expiration time.Du
 }
 
 // Set stores the given value in both in-memory and external cache.
-// Skip storing the value in external cache if the same value already exists in memory to avoid requesting external cache.
+// Unless write-through is enabled, skip storing the value in external cache if the same value already exists in memory
+// to avoid requesting external cache.
 func (c *twoLevelClient) Set(item *Item) error {
-	has, err := c.inMemoryCache.HasSame(item.Key, item.Object)
-	if has {
-		return nil
+	if !c.writeThrough {
+		has, err := c.inMemoryCache.HasSame(item.Key, item.Object)
+		if has {
+			return nil
+		}
+		if err != nil {
+			log.Warnf("Failed to check key '%s' in in-memory cache: %v", item.Key, err)
+		}
 	}
-	if err != nil {
-		log.Warnf("Failed to check key '%s' in in-memory cache: %v", item.Key, err)
-	}
-	err = c.inMemoryCache.Set(item)
-	if err != nil {
+	err := c.inMemoryCache.Set(item)
+	if errors.Is(err, ErrItemTooLarge) {
+		log.Warnf("Key '%s' bypasses in-memory cache: %v", item.Key, err)
+		if c.onOversized != nil {
+			c.onOversized()
+		}
+	} else if err != nil {
 		log.Warnf("Failed to save key '%s' in in-memory cache: %v", item.Key, err)
 	}
 	return c.externalCache.Set(item)
@@ -48,8 +84,10 @@ func (c *twoLevelClient) Set(item *Item) error {
 func (c *twoLevelClient) Get(key string, obj interface{}) error {
 	err := c.inMemoryCache.Get(key, obj)
 	if err == nil {
+		c.inMemoryHits.Add(1)
 		return nil
 	}
+	c.inMemoryMisses.Add(1)
 
 	err = c.externalCache.Get(key, obj)
 	if err == nil {
@@ -0,0 +1,111 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type countingCacheClient struct {
+	*InMemoryCache
+	sets int
+}
+
+func (c *countingCacheClient) Set(item *Item) error {
+	c.sets++
+	return c.InMemoryCache.Set(item)
+}
+
+func TestTwoLevelClient_Set(t *testing.T) {
+	t.Run("WriteBack", func(t *testing.T) {
+		external := &countingCacheClient{InMemoryCache: NewInMemoryCache(time.Minute)}
+		client := NewTwoLevelClient(external, time.Minute, false)
+
+		require.NoError(t, client.Set(&Item{Key: "foo", Object: "bar"}))
+		require.NoError(t, client.Set(&Item{Key: "foo", Object: "bar"}))
+		assert.Equal(t, 1, external.sets, "the same value should not be written twice to the external cache")
+
+		// the external cache is not refreshed once its copy is gone
+		require.NoError(t, external.Delete("foo"))
+		require.NoError(t, client.Set(&Item{Key: "foo", Object: "bar"}))
+		var output string
+		assert.ErrorIs(t, external.Get("foo", &output), ErrCacheMiss)
+	})
+
+	t.Run("WriteThrough", func(t *testing.T) {
+		external := &countingCacheClient{InMemoryCache: NewInMemoryCache(time.Minute)}
+		client := NewTwoLevelClient(external, time.Minute, true)
+
+		require.NoError(t, client.Set(&Item{Key: "foo", Object: "bar"}))
+		require.NoError(t, client.Set(&Item{Key: "foo", Object: "bar"}))
+		assert.Equal(t, 2, external.sets, "every write should be propagated to the external cache")
+
+		require.NoError(t, external.Delete("foo"))
+		require.NoError(t, client.Set(&Item{Key: "foo", Object: "bar"}))
+		var output string
+		require.NoError(t, external.Get("foo", &output))
+		assert.Equal(t, "bar", output)
+		require.NoError(t, client.inMemoryCache.Get("foo", &output))
+		assert.Equal(t, "bar", output)
+
+		require.NoError(t, client.Set(&Item{Key: "foo", Object: "baz"}))
+		require.NoError(t, external.Get("foo", &output))
+		assert.Equal(t, "baz", output)
+		require.NoError(t, client.inMemoryCache.Get("foo", &output))
+		assert.Equal(t, "baz", output)
+	})
+}
+
+func TestTwoLevelClient_OversizedItem(t *testing.T) {
+	external := &countingCacheClient{InMemoryCache: NewInMemoryCache(time.Minute)}
+	client := NewTwoLevelClient(external, time.Minute, false)
+	client.SetInMemoryMaxItemBytes(10 * 1024 * 1024)
+	oversized := 0
+	client.OnOversizedItem(func() { oversized++ })
+
+	require.NoError(t, client.Set(&Item{Key: "foo", Object: "small"}))
+	large := strings.Repeat("x", 15*1024*1024)
+	require.NoError(t, client.Set(&Item{Key: "foo", Object: large}))
+	assert.Equal(t, 1, oversized)
+	assert.Equal(t, 2, external.sets)
+
+	// the large item is not kept in memory, and the previous value of the key is dropped
+	var output string
+	require.ErrorIs(t, client.inMemoryCache.Get("foo", &output), ErrCacheMiss)
+	require.NoError(t, client.Get("foo", &output))
+	assert.Equal(t, large, output)
+	require.ErrorIs(t, client.inMemoryCache.Get("foo", &output), ErrCacheMiss)
+
+	require.NoError(t, client.Set(&Item{Key: "bar", Object: "small"}))
+	require.NoError(t, client.inMemoryCache.Get("bar", &output))
+	assert.Equal(t, "small", output)
+	assert.Equal(t, 1, oversized)
+}
+
+func TestTwoLevelClient_InMemoryStats(t *testing.T) {
+	external := NewInMemoryCache(time.Minute)
+	require.NoError(t, external.Set(&Item{Key: "foo", Object: "bar"}))
+	client := NewTwoLevelClient(external, time.Minute, false)
+
+	var output string
+	// the first read falls back to the external cache and keeps the value in memory
+	require.NoError(t, client.Get("foo", &output))
+	hits, misses := client.InMemoryStats()
+	assert.Equal(t, int64(0), hits)
+	assert.Equal(t, int64(1), misses)
+
+	require.NoError(t, client.Get("foo", &output))
+	assert.Equal(t, "bar", output)
+	hits, misses = client.InMemoryStats()
+	assert.Equal(t, int64(1), hits)
+	assert.Equal(t, int64(1), misses)
+
+	// a miss of both caches is a miss of the in-memory cache
+	assert.ErrorIs(t, client.Get("missing", &output), ErrCacheMiss)
+	hits, misses = client.InMemoryStats()
+	assert.Equal(t, int64(1), hits)
+	assert.Equal(t, int64(2), misses)
+}
@@ -63,6 +63,12 @@ const (
 	CertificateMaxLines = 128
 	// Maximum number of certificates or known host entries in a stream
 	CertificateMaxEntriesPerStream = 256
+	// Marker of SSH known hosts entries whose key is a certificate authority. The
+	// hosts matching such an entry are trusted if they present a host certificate
+	// signed by the authority.
+	SSHKnownHostsMarkerCertAuthority = "@cert-authority"
+	// Marker of SSH known hosts entries whose key is revoked
+	SSHKnownHostsMarkerRevoked = "@revoked"
 )
 
 // Regular expression that matches a valid hostname
@@ -237,11 +243,27 @@ func IsValidSSHKnownHostsEntry(line string) bool {
 		return false
 	}
 
+	// Lines may start with a marker, followed by the three fields of the entry
+	marker, trimmedEntry := SplitSSHKnownHostsMarker(trimmedEntry)
+	if marker != "" && marker != SSHKnownHostsMarkerCertAuthority && marker != SSHKnownHostsMarkerRevoked {
+		return false
+	}
+
 	// Each line should consist of three fields: host, type, data
 	keyData := strings.SplitN(trimmedEntry, " ", 3)
 	return len(keyData) == 3
 }
 
+// Splits a known_hosts entry into its marker, e.g. @cert-authority, and the
+// entry without the marker. The marker is empty if the entry has none.
+func SplitSSHKnownHostsMarker(knownHostsEntry string) (string, string) {
+	if !strings.HasPrefix(knownHostsEntry, "@") {
+		return "", knownHostsEntry
+	}
+	marker, entry, _ := strings.Cut(knownHostsEntry, " ")
+	return marker, strings.TrimSpace(entry)
+}
+
 // Tokenize a known_hosts entry into hostname, key sub type and actual key data
 func TokenizeSSHKnownHostsEntry(knownHostsEntry string) (string, string, []byte, error) {
 	knownHostsToken := strings.SplitN(knownHostsEntry, " ", 3)
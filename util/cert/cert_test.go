@@ -309,6 +309,33 @@ func Test_SSHKnownHostsData_Tokenize(t *testing.T) {
 	}
 }
 
+func Test_SSHKnownHostsData_Markers(t *testing.T) {
+	entries, err := ParseSSHKnownHostsFromData(`
+@cert-authority *.example.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAfuCHKVTjquxvt6CM6tdG4SLp1Btn/nOeHHE5UOzRdf
+@revoked gitlab.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAfuCHKVTjquxvt6CM6tdG4SLp1Btn/nOeHHE5UOzRdf
+@unknown gitlab.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAfuCHKVTjquxvt6CM6tdG4SLp1Btn/nOeHHE5UOzRdf
+`)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	marker, entry := SplitSSHKnownHostsMarker(entries[0])
+	assert.Equal(t, SSHKnownHostsMarkerCertAuthority, marker)
+	hosts, _, err := KnownHostsLineToPublicKey(entries[0])
+	require.NoError(t, err)
+	assert.Equal(t, []string{"*.example.com"}, hosts)
+	hostname, subType, _, err := TokenizeSSHKnownHostsEntry(entry)
+	require.NoError(t, err)
+	assert.Equal(t, "*.example.com", hostname)
+	assert.Equal(t, "ssh-ed25519", subType)
+
+	marker, _ = SplitSSHKnownHostsMarker(entries[1])
+	assert.Equal(t, SSHKnownHostsMarkerRevoked, marker)
+
+	marker, entry = SplitSSHKnownHostsMarker("gitlab.com ssh-ed25519 AAAA")
+	assert.Empty(t, marker)
+	assert.Equal(t, "gitlab.com ssh-ed25519 AAAA", entry)
+}
+
 func Test_MatchHostName(t *testing.T) {
 	matchHostName := "foo.example.com"
 	assert.True(t, MatchHostName(matchHostName, "*"))
@@ -17,6 +17,8 @@ import (
 
 // A struct representing an entry in the list of SSH known hosts.
 type SSHKnownHostsEntry struct {
+	// The marker of the entry, e.g. @cert-authority, empty for plain host keys
+	Marker string
 	// Hostname the key is for
 	Host string
 	// The type of the key
@@ -65,17 +67,17 @@ func (db *db) ListRepoCertificates(ctx context.Context, selector *CertificateLis
 	certificates := make([]appsv1.RepositoryCertificate, 0)
 
 	// Get all SSH known host entries
-	if selector.CertType == "" || selector.CertType == "*" || selector.CertType == "ssh" {
+	if selector.CertType == "" || selector.CertType == "*" || selector.CertType == "ssh" || selector.CertType == "ssh-ca" {
 		sshKnownHosts, err := db.getSSHKnownHostsData()
 		if err != nil {
 			return nil, err
 		}
 
 		for _, entry := range sshKnownHosts {
-			if certutil.MatchHostName(entry.Host, selector.HostNamePattern) && (selector.CertSubType == "" || selector.CertSubType == "*" || selector.CertSubType == entry.SubType) {
+			if matchSSHKnownHostsEntry(entry, selector) {
 				certificates = append(certificates, appsv1.RepositoryCertificate{
 					ServerName:  entry.Host,
-					CertType:    "ssh",
+					CertType:    sshKnownHostsEntryCertType(entry),
 					CertSubType: entry.SubType,
 					CertInfo:    "SHA256:" + certutil.SSHFingerprintSHA256FromString(fmt.Sprintf("%s %s", entry.Host, entry.Data)),
 				})
@@ -123,16 +125,16 @@ func (db *db) ListRepoCertificates(ctx context.Context, selector *CertificateLis
 
 // Get a single certificate from the datastore
 func (db *db) GetRepoCertificate(ctx context.Context, serverType string, serverName string) (*appsv1.RepositoryCertificate, error) {
-	if serverType == "ssh" {
+	if serverType == "ssh" || serverType == "ssh-ca" {
 		sshKnownHostsList, err := db.getSSHKnownHostsData()
 		if err != nil {
 			return nil, err
 		}
 		for _, entry := range sshKnownHostsList {
-			if entry.Host == serverName {
+			if entry.Host == serverName && sshKnownHostsEntryCertType(entry) == serverType {
 				repo := &appsv1.RepositoryCertificate{
 					ServerName:  entry.Host,
-					CertType:    "ssh",
+					CertType:    serverType,
 					CertSubType: entry.SubType,
 					CertData:    []byte(entry.Data),
 					CertInfo:    entry.Fingerprint,
@@ -188,9 +190,16 @@ func (db *db) CreateRepoCertificate(ctx context.Context, certificates *appsv1.Re
 			if !certutil.IsValidHostname(hostnameToCheck, false) {
 				return nil, fmt.Errorf("Invalid hostname in request: %s", hostnameToCheck)
 			}
+		} else if certificate.CertType == "ssh-ca" {
+			// Certificate authorities are usually trusted for host name patterns,
+			// e.g. *.example.com
+			hostnameToCheck := strings.NewReplacer("*", "x", "?", "x").Replace(certificate.ServerName)
+			if !certutil.IsValidHostname(hostnameToCheck, false) {
+				return nil, fmt.Errorf("Invalid hostname pattern in request: %s", certificate.ServerName)
+			}
 		}
 
-		if certificate.CertType == "ssh" {
+		if certificate.CertType == "ssh" || certificate.CertType == "ssh-ca" {
 			// Whether we have a new certificate entry
 			newEntry := true
 			// Whether we have upserted an existing certificate entry
@@ -200,7 +209,7 @@ func (db *db) CreateRepoCertificate(ctx context.Context, certificates *appsv1.Re
 			// and the key sub type (e.g. ssh-rsa). It is considered an error if we
 			// already have a corresponding key and upsert was not specified.
 			for _, entry := range sshKnownHostsList {
-				if entry.Host == certificate.ServerName && entry.SubType == certificate.CertSubType {
+				if entry.Host == certificate.ServerName && entry.SubType == certificate.CertSubType && sshKnownHostsEntryCertType(entry) == certificate.CertType {
 					if !upsert && entry.Data != string(certificate.CertData) {
 						return nil, fmt.Errorf("Key for '%s' (subtype: '%s') already exist and upsert was not specified.", entry.Host, entry.SubType)
 					} else {
@@ -227,11 +236,15 @@ func (db *db) CreateRepoCertificate(ctx context.Context, certificates *appsv1.Re
 			}
 
 			if newEntry {
-				sshKnownHostsList = append(sshKnownHostsList, &SSHKnownHostsEntry{
+				entry := &SSHKnownHostsEntry{
 					Host:    hostnames[0],
 					Data:    string(certificate.CertData),
 					SubType: certificate.CertSubType,
-				})
+				}
+				if certificate.CertType == "ssh-ca" {
+					entry.Marker = certutil.SSHKnownHostsMarkerCertAuthority
+				}
+				sshKnownHostsList = append(sshKnownHostsList, entry)
 			}
 
 			// If we created a new entry, or if we upserted an existing one, we need
@@ -345,7 +358,7 @@ func (db *db) RemoveRepoCertificates(ctx context.Context, selector *CertificateL
 		Items: make([]appsv1.RepositoryCertificate, 0),
 	}
 
-	if selector.CertType == "" || selector.CertType == "ssh" || selector.CertType == "*" {
+	if selector.CertType == "" || selector.CertType == "ssh" || selector.CertType == "ssh-ca" || selector.CertType == "*" {
 		knownHostsOld, err = db.getSSHKnownHostsData()
 		if err != nil {
 			return nil, err
@@ -356,7 +369,7 @@ func (db *db) RemoveRepoCertificates(ctx context.Context, selector *CertificateL
 			if matchSSHKnownHostsEntry(entry, selector) {
 				removed.Items = append(removed.Items, appsv1.RepositoryCertificate{
 					ServerName:  entry.Host,
-					CertType:    "ssh",
+					CertType:    sshKnownHostsEntryCertType(entry),
 					CertSubType: entry.SubType,
 					CertData:    []byte(entry.Data),
 				})
@@ -421,7 +434,11 @@ func (db *db) RemoveRepoCertificates(ctx context.Context, selector *CertificateL
 func knownHostsDataToStrings(knownHostsList []*SSHKnownHostsEntry) []string {
 	knownHostsData := make([]string, 0)
 	for _, entry := range knownHostsList {
-		knownHostsData = append(knownHostsData, fmt.Sprintf("%s %s %s", entry.Host, entry.SubType, entry.Data))
+		line := fmt.Sprintf("%s %s %s", entry.Host, entry.SubType, entry.Data)
+		if entry.Marker != "" {
+			line = entry.Marker + " " + line
+		}
+		knownHostsData = append(knownHostsData, line)
 	}
 	return knownHostsData
 }
@@ -473,11 +490,13 @@ func (db *db) getSSHKnownHostsData() ([]*SSHKnownHostsEntry, error) {
 	}
 
 	for _, entry := range sshKnownHostsEntries {
+		marker, entry := certutil.SplitSSHKnownHostsMarker(entry)
 		hostname, subType, keyData, err := certutil.TokenizeSSHKnownHostsEntry(entry)
 		if err != nil {
 			return nil, err
 		}
 		entries = append(entries, &SSHKnownHostsEntry{
+			Marker:  marker,
 			Host:    hostname,
 			SubType: subType,
 			Data:    string(keyData),
@@ -487,6 +506,23 @@ func (db *db) getSSHKnownHostsData() ([]*SSHKnownHostsEntry, error) {
 	return entries, nil
 }
 
+// Returns the certificate type of a known hosts entry, "ssh" for host keys and
+// "ssh-ca" for certificate authorities. Revoked keys have no certificate type,
+// they are kept in the known hosts data but not managed as certificates.
+func sshKnownHostsEntryCertType(entry *SSHKnownHostsEntry) string {
+	switch entry.Marker {
+	case "":
+		return "ssh"
+	case certutil.SSHKnownHostsMarkerCertAuthority:
+		return "ssh-ca"
+	}
+	return ""
+}
+
 func matchSSHKnownHostsEntry(entry *SSHKnownHostsEntry, selector *CertificateListSelector) bool {
+	certType := sshKnownHostsEntryCertType(entry)
+	if certType == "" || (selector.CertType == "ssh" || selector.CertType == "ssh-ca") && selector.CertType != certType {
+		return false
+	}
 	return certutil.MatchHostName(entry.Host, selector.HostNamePattern) && (selector.CertSubType == "" || selector.CertSubType == "*" || selector.CertSubType == entry.SubType)
 }
@@ -734,6 +734,66 @@ func Test_RemoveSSHKnownHosts(t *testing.T) {
 	assert.Empty(t, certList.Items)
 }
 
+func Test_SSHKnownHostsCertificateAuthorities(t *testing.T) {
+	clientset := getCertClientset()
+	db := NewDB(testNamespace, settings.NewSettingsManager(context.Background(), clientset, testNamespace), clientset)
+	assert.NotNil(t, db)
+
+	// Valid certificate authority for a host name pattern
+	// Result: 1 entry added
+	certList, err := db.CreateRepoCertificate(context.Background(), &v1alpha1.RepositoryCertificateList{
+		Items: []v1alpha1.RepositoryCertificate{
+			{
+				ServerName:  "*.example.com",
+				CertType:    "ssh-ca",
+				CertSubType: "ssh-ed25519",
+				CertData:    []byte("AAAAC3NzaC1lZDI1NTE5AAAAIAfuCHKVTjquxvt6CM6tdG4SLp1Btn/nOeHHE5UOzRdf"),
+			},
+		},
+	}, false)
+	require.NoError(t, err)
+	assert.Len(t, certList.Items, 1)
+
+	// Invalid host name pattern
+	// Result: Error
+	_, err = db.CreateRepoCertificate(context.Background(), &v1alpha1.RepositoryCertificateList{
+		Items: []v1alpha1.RepositoryCertificate{
+			{
+				ServerName:  "*..example.com",
+				CertType:    "ssh-ca",
+				CertSubType: "ssh-ed25519",
+				CertData:    []byte("AAAAC3NzaC1lZDI1NTE5AAAAIAfuCHKVTjquxvt6CM6tdG4SLp1Btn/nOeHHE5UOzRdf"),
+			},
+		},
+	}, false)
+	require.Error(t, err)
+
+	// The certificate authority is stored with its marker
+	cm, err := clientset.CoreV1().ConfigMaps(testNamespace).Get(context.Background(), "argocd-ssh-known-hosts-cm", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Contains(t, cm.Data["ssh_known_hosts"], "@cert-authority *.example.com ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIAfuCHKVTjquxvt6CM6tdG4SLp1Btn/nOeHHE5UOzRdf")
+
+	// Certificate authorities are listed by their own type only
+	certList, err = db.ListRepoCertificates(context.Background(), &CertificateListSelector{CertType: "ssh-ca"})
+	require.NoError(t, err)
+	require.Len(t, certList.Items, 1)
+	assert.Equal(t, "*.example.com", certList.Items[0].ServerName)
+	assert.Equal(t, "ssh-ca", certList.Items[0].CertType)
+	certList, err = db.ListRepoCertificates(context.Background(), &CertificateListSelector{CertType: "ssh"})
+	require.NoError(t, err)
+	assert.Len(t, certList.Items, 7)
+
+	// Removing host keys keeps the certificate authorities
+	_, err = db.RemoveRepoCertificates(context.Background(), &CertificateListSelector{CertType: "ssh"})
+	require.NoError(t, err)
+	certList, err = db.ListRepoCertificates(context.Background(), &CertificateListSelector{CertType: "ssh"})
+	require.NoError(t, err)
+	assert.Empty(t, certList.Items)
+	certList, err = db.ListRepoCertificates(context.Background(), &CertificateListSelector{CertType: "ssh-ca"})
+	require.NoError(t, err)
+	assert.Len(t, certList.Items, 1)
+}
+
 func Test_RemoveTLSCertificates(t *testing.T) {
 	clientset := getCertClientset()
 	db := NewDB(testNamespace, settings.NewSettingsManager(context.Background(), clientset, testNamespace), clientset)
@@ -99,6 +99,9 @@ type db struct {
 	ns            string
 	kubeclientset kubernetes.Interface
 	settingsMgr   *settings.SettingsManager
+	// vault provides the credentials of repository secrets annotated with a Vault path, the provider configured by
+	// the environment is used if nil
+	vault *VaultCredentialProvider
 }
 
 // NewDB returns a new instance of the argo database
@@ -32,7 +32,7 @@ func (s *secretsRepositoryBackend) CreateRepository(ctx context.Context, reposit
 		},
 	}
 
-	repositoryToSecret(repository, repositorySecret)
+	RepositoryToSecret(repository, repositorySecret)
 
 	_, err := s.db.createSecret(ctx, repositorySecret)
 	if err != nil {
@@ -73,12 +73,19 @@ func (s *secretsRepositoryBackend) hasRepoTypeLabel(secretName string) (bool, er
 	return false, nil
 }
 
-func (s *secretsRepositoryBackend) GetRepoCredsBySecretName(_ context.Context, name string) (*appsv1.RepoCreds, error) {
+func (s *secretsRepositoryBackend) GetRepoCredsBySecretName(ctx context.Context, name string) (*appsv1.RepoCreds, error) {
 	secret, err := s.db.getSecret(name, map[string]*corev1.Secret{})
 	if err != nil {
 		return nil, fmt.Errorf("failed to get secret %s: %w", name, err)
 	}
-	return s.secretToRepoCred(secret)
+	repoCreds, err := s.secretToRepoCred(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.injectVaultCredentials(ctx, secret, &repoCreds.Username, &repoCreds.Password); err != nil {
+		return nil, err
+	}
+	return repoCreds, nil
 }
 
 func (s *secretsRepositoryBackend) GetRepository(ctx context.Context, repoURL, project string) (*appsv1.Repository, error) {
@@ -96,7 +103,10 @@ func (s *secretsRepositoryBackend) GetRepository(ctx context.Context, repoURL, p
 		return nil, err
 	}
 
-	return repository, err
+	if err := s.injectVaultCredentials(ctx, secret, &repository.Username, &repository.Password); err != nil {
+		return nil, err
+	}
+	return repository, nil
 }
 
 func (s *secretsRepositoryBackend) ListRepositories(ctx context.Context, repoType *string) ([]*appsv1.Repository, error) {
@@ -141,7 +151,7 @@ func (s *secretsRepositoryBackend) UpdateRepository(ctx context.Context, reposit
 		return nil, err
 	}
 
-	repositoryToSecret(repository, repositorySecret)
+	RepositoryToSecret(repository, repositorySecret)
 
 	_, err = s.db.kubeclientset.CoreV1().Secrets(s.db.ns).Update(ctx, repositorySecret, metav1.UpdateOptions{})
 	if err != nil {
@@ -209,7 +219,14 @@ func (s *secretsRepositoryBackend) GetRepoCreds(ctx context.Context, repoURL str
 		return nil, err
 	}
 
-	return s.secretToRepoCred(secret)
+	repoCreds, err := s.secretToRepoCred(secret)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.injectVaultCredentials(ctx, secret, &repoCreds.Username, &repoCreds.Password); err != nil {
+		return nil, err
+	}
+	return repoCreds, nil
 }
 
 func (s *secretsRepositoryBackend) ListRepoCreds(ctx context.Context) ([]string, error) {
@@ -291,6 +308,9 @@ func (s *secretsRepositoryBackend) GetAllHelmRepoCreds(ctx context.Context) ([]*
 			if err != nil {
 				return nil, err
 			}
+			if err := s.injectVaultCredentials(ctx, secret, &repoCreds.Username, &repoCreds.Password); err != nil {
+				return nil, err
+			}
 
 			helmRepoCreds = append(helmRepoCreds, repoCreds)
 		}
@@ -299,6 +319,19 @@ func (s *secretsRepositoryBackend) GetAllHelmRepoCreds(ctx context.Context) ([]*
 	return helmRepoCreds, nil
 }
 
+// injectVaultCredentials replaces the username and password of a secret annotated with a Vault path with the
+// credentials read from Vault
+func (s *secretsRepositoryBackend) injectVaultCredentials(ctx context.Context, secret *corev1.Secret, username *string, password *string) error {
+	vaultUsername, vaultPassword, ok, err := s.db.vaultCredentials(ctx, secret)
+	if err != nil {
+		return err
+	}
+	if ok {
+		*username, *password = vaultUsername, vaultPassword
+	}
+	return nil
+}
+
 func secretToRepository(secret *corev1.Secret) (*appsv1.Repository, error) {
 	repository := &appsv1.Repository{
 		Name:                       string(secret.Data["name"]),
@@ -309,6 +342,7 @@ func secretToRepository(secret *corev1.Secret) (*appsv1.Repository, error) {
 		TLSClientCertData:          string(secret.Data["tlsClientCertData"]),
 		TLSClientCertKey:           string(secret.Data["tlsClientCertKey"]),
 		Type:                       string(secret.Data["type"]),
+		OCIType:                    string(secret.Data["ociType"]),
 		GithubAppPrivateKey:        string(secret.Data["githubAppPrivateKey"]),
 		GitHubAppEnterpriseBaseURL: string(secret.Data["githubAppEnterpriseBaseUrl"]),
 		Proxy:                      string(secret.Data["proxy"]),
@@ -361,7 +395,8 @@ func secretToRepository(secret *corev1.Secret) (*appsv1.Repository, error) {
 	return repository, nil
 }
 
-func repositoryToSecret(repository *appsv1.Repository, secret *corev1.Secret) {
+// RepositoryToSecret writes a repository into a repository secret
+func RepositoryToSecret(repository *appsv1.Repository, secret *corev1.Secret) {
 	if secret.Data == nil {
 		secret.Data = make(map[string][]byte)
 	}
@@ -373,6 +408,7 @@ func repositoryToSecret(repository *appsv1.Repository, secret *corev1.Secret) {
 	updateSecretString(secret, "password", repository.Password)
 	updateSecretString(secret, "sshPrivateKey", repository.SSHPrivateKey)
 	updateSecretBool(secret, "enableOCI", repository.EnableOCI)
+	updateSecretString(secret, "ociType", repository.OCIType)
 	updateSecretString(secret, "tlsClientCertData", repository.TLSClientCertData)
 	updateSecretString(secret, "tlsClientCertKey", repository.TLSClientCertKey)
 	updateSecretString(secret, "type", repository.Type)
@@ -0,0 +1,239 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/util/env"
+)
+
+const (
+	// defaultVaultTokenFile is the file the Vault Agent injector writes the auto-auth token to
+	defaultVaultTokenFile = "/vault/secrets/token"
+	// vaultRequestTimeout is the timeout of requests to the Vault server
+	vaultRequestTimeout = 10 * time.Second
+)
+
+var (
+	defaultVaultProvider     *VaultCredentialProvider
+	initDefaultVaultProvider sync.Once
+)
+
+// vaultSecret is the response of Vault to reading a secret or renewing a lease
+type vaultSecret struct {
+	LeaseID       string                 `json:"lease_id"`
+	LeaseDuration int64                  `json:"lease_duration"`
+	Renewable     bool                   `json:"renewable"`
+	Data          map[string]interface{} `json:"data"`
+	Errors        []string               `json:"errors"`
+}
+
+// vaultLease holds credentials read from Vault until their lease expires
+type vaultLease struct {
+	username  string
+	password  string
+	leaseID   string
+	renewable bool
+	duration  time.Duration
+	expiresAt time.Time
+}
+
+// renewAt returns the time after which the lease is renewed, i.e. once two thirds of the lease duration have elapsed
+func (l *vaultLease) renewAt() time.Time {
+	return l.expiresAt.Add(-l.duration / 3)
+}
+
+// VaultCredentialProvider reads repository credentials from Vault, e.g. from the dynamic secrets of a database or
+// the username and password of a KV secret. The credentials are kept until their lease is about to expire, and then
+// the lease is renewed, or the credentials are read again if the lease cannot be renewed. Requests are authenticated
+// with the token written by the Vault Agent.
+type VaultCredentialProvider struct {
+	address   string
+	tokenFile string
+	client    *http.Client
+	now       func() time.Time
+
+	lock   sync.Mutex
+	leases map[string]*vaultLease
+}
+
+// NewVaultCredentialProvider returns a provider reading credentials from the Vault server at the given address
+// using the token in tokenFile
+func NewVaultCredentialProvider(address string, tokenFile string) *VaultCredentialProvider {
+	return &VaultCredentialProvider{
+		address:   strings.TrimSuffix(address, "/"),
+		tokenFile: tokenFile,
+		client:    &http.Client{Timeout: vaultRequestTimeout},
+		now:       time.Now,
+		leases:    make(map[string]*vaultLease),
+	}
+}
+
+// getDefaultVaultProvider returns the provider configured with the VAULT_ADDR and ARGOCD_VAULT_TOKEN_FILE environment variables
+func getDefaultVaultProvider() *VaultCredentialProvider {
+	initDefaultVaultProvider.Do(func() {
+		defaultVaultProvider = NewVaultCredentialProvider(os.Getenv("VAULT_ADDR"), env.StringFromEnv("ARGOCD_VAULT_TOKEN_FILE", defaultVaultTokenFile))
+	})
+	return defaultVaultProvider
+}
+
+// GetCredentials returns the username and password stored in the Vault secret at the given path
+func (p *VaultCredentialProvider) GetCredentials(ctx context.Context, path string) (string, string, error) {
+	if p.address == "" {
+		return "", "", fmt.Errorf("cannot read credentials from Vault path %s: VAULT_ADDR is not set", path)
+	}
+	path = strings.Trim(path, "/")
+
+	// the lock is only held to access the leases, so that a slow Vault server does not block the other paths
+	now := p.now()
+	if lease, ok := p.getLease(path); ok && now.Before(lease.expiresAt) {
+		if now.Before(lease.renewAt()) {
+			return lease.username, lease.password, nil
+		}
+		if lease.renewable {
+			renewed, err := p.request(ctx, http.MethodPut, "sys/leases/renew", map[string]string{"lease_id": lease.leaseID})
+			if err == nil && renewed.LeaseDuration > 0 {
+				lease.duration = time.Duration(renewed.LeaseDuration) * time.Second
+				lease.expiresAt = now.Add(lease.duration)
+				lease.renewable = renewed.Renewable
+				p.setLease(path, &lease)
+				return lease.username, lease.password, nil
+			}
+			log.Warnf("Failed to renew Vault lease of %s, reading new credentials: %v", path, err)
+		}
+	}
+
+	secret, err := p.request(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return "", "", err
+	}
+	data := secret.Data
+	// the KV version 2 secrets engine nests the secret data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	username, _ := data["username"].(string)
+	password, _ := data["password"].(string)
+	if username == "" && password == "" {
+		return "", "", fmt.Errorf("secret %s in Vault does not contain a username or password", path)
+	}
+
+	if secret.LeaseDuration > 0 {
+		duration := time.Duration(secret.LeaseDuration) * time.Second
+		p.setLease(path, &vaultLease{
+			username:  username,
+			password:  password,
+			leaseID:   secret.LeaseID,
+			renewable: secret.Renewable,
+			duration:  duration,
+			expiresAt: now.Add(duration),
+		})
+	} else {
+		p.setLease(path, nil)
+	}
+	return username, password, nil
+}
+
+// getLease returns a copy of the lease of the credentials of the path
+func (p *VaultCredentialProvider) getLease(path string) (vaultLease, bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	lease, ok := p.leases[path]
+	if !ok {
+		return vaultLease{}, false
+	}
+	return *lease, true
+}
+
+// setLease stores the lease of the credentials of the path, or deletes it if lease is nil
+func (p *VaultCredentialProvider) setLease(path string, lease *vaultLease) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if lease == nil {
+		delete(p.leases, path)
+		return
+	}
+	p.leases[path] = lease
+}
+
+func (p *VaultCredentialProvider) token() (string, error) {
+	data, err := os.ReadFile(p.tokenFile)
+	if err == nil {
+		return strings.TrimSpace(string(data)), nil
+	}
+	if token := os.Getenv("VAULT_TOKEN"); token != "" {
+		return token, nil
+	}
+	return "", fmt.Errorf("failed to read Vault token: %w", err)
+}
+
+func (p *VaultCredentialProvider) request(ctx context.Context, method string, path string, body interface{}) (*vaultSecret, error) {
+	token, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s/v1/%s", p.address, path), reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to request Vault path %s: %w", path, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	var secret vaultSecret
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil && resp.StatusCode == http.StatusOK {
+		return nil, fmt.Errorf("failed to decode Vault response of path %s: %w", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("request of Vault path %s failed with status %d: %s", path, resp.StatusCode, strings.Join(secret.Errors, ", "))
+	}
+	return &secret, nil
+}
+
+// getVaultProvider returns the provider of the credentials of repository secrets annotated with a Vault path
+func (db *db) getVaultProvider() *VaultCredentialProvider {
+	if db.vault != nil {
+		return db.vault
+	}
+	return getDefaultVaultProvider()
+}
+
+// vaultCredentials returns the credentials of the Vault path the given secret is annotated with. ok is false if the
+// secret is not annotated.
+func (db *db) vaultCredentials(ctx context.Context, secret *corev1.Secret) (username string, password string, ok bool, err error) {
+	path := secret.Annotations[common.AnnotationVaultPath]
+	if path == "" {
+		return "", "", false, nil
+	}
+	username, password, err = db.getVaultProvider().GetCredentials(ctx, path)
+	if err != nil {
+		return "", "", false, fmt.Errorf("failed to get credentials of secret %s from Vault: %w", secret.Name, err)
+	}
+	return username, password, true, nil
+}
@@ -0,0 +1,247 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/argoproj/argo-cd/v2/common"
+	"github.com/argoproj/argo-cd/v2/util/settings"
+)
+
+// fakeVault is a Vault server issuing dynamic credentials with a lease of one hour
+type fakeVault struct {
+	reads      int
+	renewals   int
+	renewFails bool
+}
+
+func (v *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != "agent-token" {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/database/creds/git":
+		v.reads++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"lease_id":       fmt.Sprintf("database/creds/git/%d", v.reads),
+			"lease_duration": 3600,
+			"renewable":      true,
+			"data":           map[string]string{"username": fmt.Sprintf("v-git-%d", v.reads), "password": "dynamic-password"},
+		})
+	case r.Method == http.MethodGet && r.URL.Path == "/v1/secret/data/git":
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": map[string]interface{}{"data": map[string]string{"username": "kv-user", "password": "kv-password"}},
+		})
+	case r.Method == http.MethodPut && r.URL.Path == "/v1/sys/leases/renew":
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if v.renewFails || body["lease_id"] != fmt.Sprintf("database/creds/git/%d", v.reads) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":["lease not found"]}`))
+			return
+		}
+		v.renewals++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"lease_id": body["lease_id"], "lease_duration": 3600, "renewable": true})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[]}`))
+	}
+}
+
+func newFakeVaultProvider(t *testing.T) (*VaultCredentialProvider, *fakeVault, *time.Time) {
+	t.Helper()
+	vault := &fakeVault{}
+	server := httptest.NewServer(vault)
+	t.Cleanup(server.Close)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("agent-token\n"), 0o600))
+	provider := NewVaultCredentialProvider(server.URL, tokenFile)
+	now := time.Now()
+	provider.now = func() time.Time { return now }
+	return provider, vault, &now
+}
+
+func TestVaultCredentialProvider_GetCredentials(t *testing.T) {
+	provider, vault, now := newFakeVaultProvider(t)
+
+	username, password, err := provider.GetCredentials(context.Background(), "/database/creds/git")
+	require.NoError(t, err)
+	assert.Equal(t, "v-git-1", username)
+	assert.Equal(t, "dynamic-password", password)
+
+	// the credentials are kept while the lease is valid
+	*now = now.Add(30 * time.Minute)
+	username, _, err = provider.GetCredentials(context.Background(), "database/creds/git")
+	require.NoError(t, err)
+	assert.Equal(t, "v-git-1", username)
+	assert.Equal(t, 1, vault.reads)
+
+	// the lease is renewed once it is about to expire
+	*now = now.Add(20 * time.Minute)
+	username, _, err = provider.GetCredentials(context.Background(), "database/creds/git")
+	require.NoError(t, err)
+	assert.Equal(t, "v-git-1", username)
+	assert.Equal(t, 1, vault.reads)
+	assert.Equal(t, 1, vault.renewals)
+
+	// the credentials are read again if the lease cannot be renewed
+	vault.renewFails = true
+	*now = now.Add(50 * time.Minute)
+	username, _, err = provider.GetCredentials(context.Background(), "database/creds/git")
+	require.NoError(t, err)
+	assert.Equal(t, "v-git-2", username)
+
+	// or once it expired
+	*now = now.Add(2 * time.Hour)
+	username, _, err = provider.GetCredentials(context.Background(), "database/creds/git")
+	require.NoError(t, err)
+	assert.Equal(t, "v-git-3", username)
+	assert.Equal(t, 1, vault.renewals)
+}
+
+func TestVaultCredentialProvider_GetCredentials_KV(t *testing.T) {
+	provider, _, _ := newFakeVaultProvider(t)
+	username, password, err := provider.GetCredentials(context.Background(), "secret/data/git")
+	require.NoError(t, err)
+	assert.Equal(t, "kv-user", username)
+	assert.Equal(t, "kv-password", password)
+}
+
+func TestVaultCredentialProvider_GetCredentials_Errors(t *testing.T) {
+	provider, _, _ := newFakeVaultProvider(t)
+	_, _, err := provider.GetCredentials(context.Background(), "database/creds/unknown")
+	require.ErrorContains(t, err, "failed with status 404")
+
+	provider.tokenFile = filepath.Join(t.TempDir(), "missing")
+	t.Setenv("VAULT_TOKEN", "wrong-token")
+	_, _, err = provider.GetCredentials(context.Background(), "database/creds/git")
+	require.ErrorContains(t, err, "permission denied")
+
+	_, _, err = NewVaultCredentialProvider("", "").GetCredentials(context.Background(), "database/creds/git")
+	require.ErrorContains(t, err, "VAULT_ADDR is not set")
+}
+
+func TestSecretsRepositoryBackend_VaultCredentials(t *testing.T) {
+	provider, _, _ := newFakeVaultProvider(t)
+	clientset := getClientset(map[string]string{},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   testNamespace,
+				Name:        "vault-repo",
+				Annotations: map[string]string{common.AnnotationVaultPath: "database/creds/git"},
+				Labels:      map[string]string{common.LabelKeySecretType: common.LabelValueSecretTypeRepository},
+			},
+			Data: map[string][]byte{
+				"url":      []byte("https://git.example.com/repo.git"),
+				"username": []byte("static"),
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   testNamespace,
+				Name:        "vault-creds",
+				Annotations: map[string]string{common.AnnotationVaultPath: "secret/data/git"},
+				Labels:      map[string]string{common.LabelKeySecretType: common.LabelValueSecretTypeRepoCreds},
+			},
+			Data: map[string][]byte{
+				"url": []byte("https://git.example.com/org"),
+			},
+		},
+	)
+	repoBackend := &secretsRepositoryBackend{db: &db{
+		ns:            testNamespace,
+		kubeclientset: clientset,
+		settingsMgr:   settings.NewSettingsManager(context.Background(), clientset, testNamespace),
+		vault:         provider,
+	}}
+
+	repo, err := repoBackend.GetRepository(context.Background(), "https://git.example.com/repo.git", "")
+	require.NoError(t, err)
+	assert.Equal(t, "v-git-1", repo.Username)
+	assert.Equal(t, "dynamic-password", repo.Password)
+
+	creds, err := repoBackend.GetRepoCreds(context.Background(), "https://git.example.com/org/other.git")
+	require.NoError(t, err)
+	require.NotNil(t, creds)
+	assert.Equal(t, "kv-user", creds.Username)
+	assert.Equal(t, "kv-password", creds.Password)
+}
+
+func TestSecretsRepositoryBackend_VaultCredentials_Error(t *testing.T) {
+	provider, _, _ := newFakeVaultProvider(t)
+	clientset := getClientset(map[string]string{},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   testNamespace,
+				Name:        "vault-repo",
+				Annotations: map[string]string{common.AnnotationVaultPath: "database/creds/unknown"},
+				Labels:      map[string]string{common.LabelKeySecretType: common.LabelValueSecretTypeRepository},
+			},
+			Data: map[string][]byte{
+				"url":      []byte("https://git.example.com/repo.git"),
+				"username": []byte("static"),
+			},
+		},
+	)
+	repoBackend := &secretsRepositoryBackend{db: &db{
+		ns:            testNamespace,
+		kubeclientset: clientset,
+		settingsMgr:   settings.NewSettingsManager(context.Background(), clientset, testNamespace),
+		vault:         provider,
+	}}
+
+	// the repository is not returned with its static credentials
+	repo, err := repoBackend.GetRepository(context.Background(), "https://git.example.com/repo.git", "")
+	require.ErrorContains(t, err, "failed to get credentials of secret vault-repo from Vault")
+	assert.Nil(t, repo)
+}
+
+func TestVaultCredentialProvider_GetCredentials_DoesNotBlockOnVault(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	vault := &fakeVault{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/database/creds/slow" {
+			close(started)
+			<-release
+		}
+		vault.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+	t.Setenv("VAULT_TOKEN", "agent-token")
+	provider := NewVaultCredentialProvider(server.URL, filepath.Join(t.TempDir(), "missing"))
+
+	_, _, err := provider.GetCredentials(context.Background(), "database/creds/git")
+	require.NoError(t, err)
+
+	go func() {
+		_, _, _ = provider.GetCredentials(context.Background(), "database/creds/slow")
+	}()
+	<-started
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		username, _, err := provider.GetCredentials(context.Background(), "database/creds/git")
+		assert.NoError(t, err)
+		assert.Equal(t, "v-git-1", username)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("cached credentials were blocked by a pending Vault request")
+	}
+}
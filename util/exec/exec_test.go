@@ -55,6 +55,16 @@ func TestRunWithExecRunOpts(t *testing.T) {
 	assert.Contains(t, err.Error(), "failed timeout after 200ms")
 }
 
+func TestRunWithExecRunOpts_Timeout(t *testing.T) {
+	t.Setenv("ARGOCD_EXEC_TIMEOUT", "1m")
+	initTimeout()
+
+	start := time.Now()
+	_, err := RunWithExecRunOpts(exec.Command("sleep", "10"), ExecRunOpts{Timeout: 100 * time.Millisecond})
+	require.ErrorContains(t, err, "timeout after 100ms")
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
 func Test_getCommandArgsToLog(t *testing.T) {
 	testCases := []struct {
 		name     string
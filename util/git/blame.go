@@ -0,0 +1,132 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// notCommittedSHA is the SHA reported by git blame for the lines which are not committed yet
+const notCommittedSHA = "0000000000000000000000000000000000000000"
+
+// BlameCommit is a commit which last modified lines of a blamed file
+type BlameCommit struct {
+	SHA         string
+	Author      string
+	AuthorEmail string
+	AuthorTime  time.Time
+	// Summary is the first line of the commit message
+	Summary string
+	// Filename is the path of the file in the commit, which differs from the blamed path if the file was renamed since
+	Filename string
+	// Boundary is set if the commit is the oldest commit blamed, e.g. the oldest commit of a shallow clone, in which
+	// case the lines may have been modified by an earlier commit
+	Boundary bool
+}
+
+// IsCommitted returns whether the commit is an actual commit, rather than the uncommitted changes of the working tree
+func (c *BlameCommit) IsCommitted() bool {
+	return c.SHA != notCommittedSHA
+}
+
+// BlameLine is a line of a blamed file along with the commit which last modified it
+type BlameLine struct {
+	Commit *BlameCommit
+	// Line is the number of the line in the blamed file, starting at 1
+	Line int
+	// OriginalLine is the number of the line in the file of the commit
+	OriginalLine int
+	Content      string
+}
+
+// ParseBlamePorcelain parses the output of `git blame --porcelain` into the lines of the blamed file. The details of
+// a commit are only output by git for the first line it modified, so the lines of the same commit share the commit.
+func ParseBlamePorcelain(out string) ([]BlameLine, error) {
+	commits := map[string]*BlameCommit{}
+	var lines []BlameLine
+	var current *BlameLine
+
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		text := scanner.Text()
+		if current == nil {
+			if text == "" {
+				continue
+			}
+			// header of a line: <sha> <original line> <final line> [<number of lines of the group>]
+			fields := strings.Fields(text)
+			if len(fields) < 3 || len(fields) > 4 || !IsCommitSHA(fields[0]) {
+				return nil, fmt.Errorf("unexpected blame header at line %d: %q", lineNo, text)
+			}
+			originalLine, err := strconv.Atoi(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid original line number at line %d: %w", lineNo, err)
+			}
+			finalLine, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return nil, fmt.Errorf("invalid final line number at line %d: %w", lineNo, err)
+			}
+			commit, ok := commits[fields[0]]
+			if !ok {
+				commit = &BlameCommit{SHA: fields[0]}
+				commits[fields[0]] = commit
+			}
+			current = &BlameLine{Commit: commit, Line: finalLine, OriginalLine: originalLine}
+			continue
+		}
+
+		// the content of the line is prefixed with a tab and ends the line
+		if content, ok := strings.CutPrefix(text, "\t"); ok {
+			current.Content = content
+			lines = append(lines, *current)
+			current = nil
+			continue
+		}
+
+		key, value, _ := strings.Cut(text, " ")
+		commit := current.Commit
+		switch key {
+		case "author":
+			commit.Author = value
+		case "author-mail":
+			commit.AuthorEmail = strings.TrimSuffix(strings.TrimPrefix(value, "<"), ">")
+		case "author-time":
+			timestamp, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid author time at line %d: %w", lineNo, err)
+			}
+			commit.AuthorTime = time.Unix(timestamp, 0).UTC()
+		case "summary":
+			commit.Summary = value
+		case "filename":
+			commit.Filename = value
+		case "boundary":
+			commit.Boundary = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read blame output: %w", err)
+	}
+	if current != nil {
+		return nil, fmt.Errorf("missing content of line %d in blame output", current.Line)
+	}
+	return lines, nil
+}
+
+// LastBlameCommit returns the most recently authored commit which modified the given lines, ignoring the uncommitted
+// lines, or nil if none of the lines is committed
+func LastBlameCommit(lines []BlameLine) *BlameCommit {
+	var last *BlameCommit
+	for _, line := range lines {
+		if !line.Commit.IsCommitted() {
+			continue
+		}
+		if last == nil || line.Commit.AuthorTime.After(last.AuthorTime) {
+			last = line.Commit
+		}
+	}
+	return last
+}
@@ -0,0 +1,237 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blameTwoCommits is the output of git blame for a file whose last line was modified by a second commit
+const blameTwoCommits = `9d2736b44e5508869fd76914e333ac3d88a6e2ed 1 1 5
+author Alice Doe
+author-mail <alice@example.com>
+author-time 1700000000
+author-tz +0100
+committer Alice Doe
+committer-mail <alice@example.com>
+committer-time 1700000000
+committer-tz +0100
+summary Add guestbook config map
+boundary
+filename cm.yaml
+	apiVersion: v1
+9d2736b44e5508869fd76914e333ac3d88a6e2ed 2 2
+	kind: ConfigMap
+9d2736b44e5508869fd76914e333ac3d88a6e2ed 3 3
+	metadata:
+9d2736b44e5508869fd76914e333ac3d88a6e2ed 4 4
+	  name: guestbook
+9d2736b44e5508869fd76914e333ac3d88a6e2ed 5 5
+	data:
+01b390cfaa2686f9f4f3c5eaa3b1771c1b09ec61 6 6 1
+author Bob
+author-mail <bob@example.com>
+author-time 1700003600
+author-tz +0000
+committer Alice Doe
+committer-mail <alice@example.com>
+committer-time 1700003600
+committer-tz +0000
+summary Change the value of the key
+previous 9d2736b44e5508869fd76914e333ac3d88a6e2ed cm.yaml
+filename cm.yaml
+	  key: other value
+`
+
+// blameInterleaved is the output of git blame for a file whose lines were modified by alternating commits, the
+// details of a commit being output only for the first group of lines it modified
+const blameInterleaved = `aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa 1 1 1
+author Alice Doe
+author-mail <alice@example.com>
+author-time 1700000000
+author-tz +0000
+committer Alice Doe
+committer-mail <alice@example.com>
+committer-time 1700000000
+committer-tz +0000
+summary Add deployment
+filename base/deployment.yaml
+	apiVersion: apps/v1
+bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb 2 2 1
+author Bob
+author-mail <bob@example.com>
+author-time 1700007200
+author-tz +0000
+committer Bob
+committer-mail <bob@example.com>
+committer-time 1700007200
+committer-tz +0000
+summary Move the deployment to the base
+previous aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa deployment.yaml
+filename base/deployment.yaml
+	kind: Deployment
+aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa 3 3 2
+	metadata:
+aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa 4 4
+		name: guestbook	# tabs are kept
+`
+
+func TestParseBlamePorcelain(t *testing.T) {
+	t.Run("TwoCommits", func(t *testing.T) {
+		lines, err := ParseBlamePorcelain(blameTwoCommits)
+		require.NoError(t, err)
+		require.Len(t, lines, 6)
+		first := lines[0].Commit
+		assert.Equal(t, "9d2736b44e5508869fd76914e333ac3d88a6e2ed", first.SHA)
+		assert.Equal(t, "Alice Doe", first.Author)
+		assert.Equal(t, "alice@example.com", first.AuthorEmail)
+		assert.Equal(t, time.Unix(1700000000, 0).UTC(), first.AuthorTime)
+		assert.Equal(t, "Add guestbook config map", first.Summary)
+		assert.Equal(t, "cm.yaml", first.Filename)
+		assert.True(t, first.Boundary)
+		for i, line := range lines[:5] {
+			assert.Same(t, first, line.Commit)
+			assert.Equal(t, i+1, line.Line)
+		}
+		assert.Equal(t, "  name: guestbook", lines[3].Content)
+
+		last := lines[5]
+		assert.Equal(t, "Bob", last.Commit.Author)
+		assert.Equal(t, "Change the value of the key", last.Commit.Summary)
+		assert.False(t, last.Commit.Boundary)
+		assert.Equal(t, 6, last.Line)
+		assert.Equal(t, "  key: other value", last.Content)
+		assert.Same(t, last.Commit, LastBlameCommit(lines))
+	})
+
+	t.Run("Interleaved", func(t *testing.T) {
+		lines, err := ParseBlamePorcelain(blameInterleaved)
+		require.NoError(t, err)
+		require.Len(t, lines, 4)
+		assert.Same(t, lines[0].Commit, lines[2].Commit)
+		assert.Same(t, lines[0].Commit, lines[3].Commit)
+		assert.Equal(t, "Alice Doe", lines[3].Commit.Author)
+		assert.Equal(t, "Move the deployment to the base", lines[1].Commit.Summary)
+		assert.Equal(t, "\tname: guestbook\t# tabs are kept", lines[3].Content)
+		assert.Equal(t, "Bob", LastBlameCommit(lines).Author)
+	})
+
+	t.Run("Renamed", func(t *testing.T) {
+		lines, err := ParseBlamePorcelain(`cccccccccccccccccccccccccccccccccccccccc 7 1 1
+author Carol
+author-mail <carol@example.com>
+author-time 1700000000
+author-tz +0000
+committer Carol
+committer-mail <carol@example.com>
+committer-time 1700000000
+committer-tz +0000
+summary Add the service
+filename old/service.yaml
+	kind: Service`)
+		require.NoError(t, err)
+		require.Len(t, lines, 1)
+		assert.Equal(t, "old/service.yaml", lines[0].Commit.Filename)
+		assert.Equal(t, 7, lines[0].OriginalLine)
+		assert.Equal(t, 1, lines[0].Line)
+	})
+
+	t.Run("NotCommitted", func(t *testing.T) {
+		lines, err := ParseBlamePorcelain(`0000000000000000000000000000000000000000 1 1 1
+author Not Committed Yet
+author-mail <not.committed.yet>
+author-time 1800000000
+author-tz +0000
+committer Not Committed Yet
+committer-mail <not.committed.yet>
+committer-time 1800000000
+committer-tz +0000
+summary Version of cm.yaml from cm.yaml
+filename cm.yaml
+	kind: ConfigMap`)
+		require.NoError(t, err)
+		require.Len(t, lines, 1)
+		assert.False(t, lines[0].Commit.IsCommitted())
+		assert.Nil(t, LastBlameCommit(lines))
+	})
+
+	t.Run("EmptyLines", func(t *testing.T) {
+		lines, err := ParseBlamePorcelain(`dddddddddddddddddddddddddddddddddddddddd 1 1 2
+author Dave
+author-mail <>
+author-time 1700000000
+author-tz +0000
+committer Dave
+committer-mail <>
+committer-time 1700000000
+committer-tz +0000
+summary
+filename empty.yaml
+	
+dddddddddddddddddddddddddddddddddddddddd 2 2
+	`)
+		require.NoError(t, err)
+		require.Len(t, lines, 2)
+		assert.Equal(t, "", lines[0].Content)
+		assert.Equal(t, "", lines[1].Content)
+		assert.Equal(t, "", lines[0].Commit.AuthorEmail)
+		assert.Equal(t, "", lines[0].Commit.Summary)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		lines, err := ParseBlamePorcelain("")
+		require.NoError(t, err)
+		assert.Empty(t, lines)
+		assert.Nil(t, LastBlameCommit(lines))
+	})
+
+	t.Run("InvalidHeader", func(t *testing.T) {
+		_, err := ParseBlamePorcelain("not a header\n\tkind: ConfigMap")
+		assert.ErrorContains(t, err, "unexpected blame header at line 1")
+		_, err = ParseBlamePorcelain("eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee one 1 1\n\tkind: ConfigMap")
+		assert.ErrorContains(t, err, "invalid original line number")
+	})
+
+	t.Run("InvalidAuthorTime", func(t *testing.T) {
+		_, err := ParseBlamePorcelain("eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee 1 1 1\nauthor-time yesterday\n\tkind: ConfigMap")
+		assert.ErrorContains(t, err, "invalid author time at line 2")
+	})
+
+	t.Run("MissingContent", func(t *testing.T) {
+		_, err := ParseBlamePorcelain("eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee 1 3 1\nauthor Eve")
+		assert.ErrorContains(t, err, "missing content of line 3")
+	})
+}
+
+func Test_nativeGitClient_Blame(t *testing.T) {
+	tempDir := t.TempDir()
+	client, err := NewClientExt(fmt.Sprintf("file://%s", tempDir), tempDir, NopCreds{}, true, false, "")
+	require.NoError(t, err)
+	require.NoError(t, client.Init())
+
+	manifest := filepath.Join(client.Root(), "cm.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte("kind: ConfigMap\ndata:\n  key: value\n"), 0o644))
+	require.NoError(t, runCmd(client.Root(), "git", "add", "cm.yaml"))
+	require.NoError(t, runCmd(client.Root(), "git", "-c", "user.name=Alice", "-c", "user.email=alice@example.com", "commit", "--date=1700000000 +0000", "-m", "Add config map"))
+	require.NoError(t, os.WriteFile(manifest, []byte("kind: ConfigMap\ndata:\n  key: other value\n"), 0o644))
+	require.NoError(t, runCmd(client.Root(), "git", "-c", "user.name=Bob", "-c", "user.email=bob@example.com", "commit", "--date=1700003600 +0000", "-am", "Change the value"))
+
+	lines, err := client.Blame("cm.yaml")
+	require.NoError(t, err)
+	require.Len(t, lines, 3)
+	assert.Equal(t, "Alice", lines[0].Commit.Author)
+	assert.Equal(t, "  key: other value", lines[2].Content)
+	last := LastBlameCommit(lines)
+	require.NotNil(t, last)
+	assert.Equal(t, "Bob", last.Author)
+	assert.Equal(t, "bob@example.com", last.AuthorEmail)
+	assert.Equal(t, "Change the value", last.Summary)
+
+	_, err = client.Blame("missing.yaml")
+	assert.Error(t, err)
+}
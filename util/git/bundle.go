@@ -0,0 +1,138 @@
+package git
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const remoteRefsPrefix = "refs/remotes/origin/"
+
+// BundleStore keeps local copies of repositories imported from git bundle files. It allows serving repositories
+// which cannot be reached from the cluster, e.g. in air-gapped environments.
+type BundleStore struct {
+	root string
+	lock sync.Mutex
+}
+
+// NewBundleStore returns a store which keeps the imported repositories in sub-directories of the given root path
+func NewBundleStore(root string) *BundleStore {
+	return &BundleStore{root: root}
+}
+
+func (s *BundleStore) repoPath(repoURL string) string {
+	return filepath.Join(s.root, fmt.Sprintf("%x", sha256.Sum256([]byte(NormalizeGitURL(repoURL)))))
+}
+
+// Path returns the path of the local copy of the given repository and whether a bundle was imported for it
+func (s *BundleStore) Path(repoURL string) (string, bool) {
+	path := s.repoPath(repoURL)
+	if _, err := os.Stat(filepath.Join(path, "HEAD")); err != nil {
+		return "", false
+	}
+	return path, true
+}
+
+// Import unpacks the git bundle file at bundlePath into the local copy of the given repository and returns the
+// imported references. Unless update is set, the local copy is replaced with the content of the bundle. Otherwise the
+// bundle is applied on top of the previously imported one, which allows importing incremental bundles created with
+// e.g. `git bundle create repo.bundle v1.0.0..main`.
+func (s *BundleStore) Import(repoURL string, bundlePath string, update bool) ([]string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	path := s.repoPath(repoURL)
+	target := path
+	if update {
+		if _, ok := s.Path(repoURL); !ok {
+			return nil, fmt.Errorf("no bundle was imported for repository %s yet", repoURL)
+		}
+	} else {
+		// the bundle is unpacked into a temporary repository so the current copy is kept if the import fails
+		if err := os.MkdirAll(s.root, 0700); err != nil {
+			return nil, fmt.Errorf("failed to create bundle store directory: %w", err)
+		}
+		tempDir, err := os.MkdirTemp(s.root, "import-")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(tempDir) }()
+		if _, err := runBundleCmd(tempDir, "init", "--bare"); err != nil {
+			return nil, fmt.Errorf("failed to initialize local copy of repository %s: %w", repoURL, err)
+		}
+		target = tempDir
+	}
+
+	// verify checks that the bundle is valid and that the local copy contains all prerequisite commits
+	if _, err := runBundleCmd(target, "bundle", "verify", bundlePath); err != nil {
+		return nil, fmt.Errorf("invalid bundle: %w", err)
+	}
+	out, err := runBundleCmd(target, "bundle", "unbundle", bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unbundle: %w", err)
+	}
+
+	refs := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 || !IsCommitSHA(fields[0]) {
+			continue
+		}
+		refs[fields[1]] = fields[0]
+	}
+	// bundles exported from a clone only contain remote tracking references which are imported as branches
+	for ref, sha := range refs {
+		if !strings.HasPrefix(ref, remoteRefsPrefix) {
+			continue
+		}
+		delete(refs, ref)
+		branch := strings.TrimPrefix(ref, remoteRefsPrefix)
+		if branch == "HEAD" {
+			continue
+		}
+		if _, ok := refs["refs/heads/"+branch]; !ok {
+			refs["refs/heads/"+branch] = sha
+		}
+	}
+
+	var imported []string
+	for ref, sha := range refs {
+		args := []string{"update-ref", ref, sha}
+		if ref == "HEAD" {
+			args = []string{"update-ref", "--no-deref", ref, sha}
+		}
+		if _, err := runBundleCmd(target, args...); err != nil {
+			return nil, fmt.Errorf("failed to update reference %s: %w", ref, err)
+		}
+		imported = append(imported, ref)
+	}
+	sort.Strings(imported)
+
+	if target != path {
+		if err := os.RemoveAll(path); err != nil {
+			return nil, fmt.Errorf("failed to remove local copy of repository %s: %w", repoURL, err)
+		}
+		if err := os.Rename(target, path); err != nil {
+			return nil, fmt.Errorf("failed to replace local copy of repository %s: %w", repoURL, err)
+		}
+	}
+	return imported, nil
+}
+
+// CreateBundle writes a git bundle containing all references of the git repository at repoPath to bundlePath
+func CreateBundle(repoPath string, bundlePath string) error {
+	if _, err := runBundleCmd(repoPath, "bundle", "create", bundlePath, "--all"); err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	return nil
+}
+
+func runBundleCmd(dir string, args ...string) (string, error) {
+	m := &nativeGitClient{root: dir}
+	return m.runCmdOutput(exec.Command("git", args...), runOpts{})
+}
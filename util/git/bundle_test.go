@@ -0,0 +1,95 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func revParse(t *testing.T, dir string, revision string) string {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", revision).Output()
+	require.NoError(t, err)
+	return strings.TrimSpace(string(out))
+}
+
+func createBundleSourceRepo(t *testing.T) string {
+	dir := t.TempDir()
+	require.NoError(t, runCmd(dir, "git", "init"))
+	require.NoError(t, runCmd(dir, "git", "checkout", "-b", "main"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README"), []byte("Hello."), 0644))
+	require.NoError(t, runCmd(dir, "git", "add", "README"))
+	require.NoError(t, runCmd(dir, "git", "commit", "-m", "Initial commit"))
+	require.NoError(t, runCmd(dir, "git", "tag", "v1.0.0"))
+	return dir
+}
+
+func TestBundleStore_Import(t *testing.T) {
+	const repoURL = "https://gitlab.example.com/app"
+	source := createBundleSourceRepo(t)
+	bundle := filepath.Join(t.TempDir(), "repo.bundle")
+	require.NoError(t, CreateBundle(source, bundle))
+
+	store := NewBundleStore(t.TempDir())
+	_, ok := store.Path(repoURL)
+	assert.False(t, ok)
+
+	t.Run("IncrementalWithoutImport", func(t *testing.T) {
+		_, err := store.Import(repoURL, bundle, true)
+		assert.ErrorContains(t, err, "no bundle was imported")
+	})
+
+	refs, err := store.Import(repoURL, bundle, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"HEAD", "refs/heads/main", "refs/tags/v1.0.0"}, refs)
+	path, ok := store.Path(repoURL)
+	require.True(t, ok)
+	// the repository URL is normalized
+	otherPath, ok := store.Path(repoURL + ".git")
+	require.True(t, ok)
+	assert.Equal(t, path, otherPath)
+	assert.Equal(t, revParse(t, source, "main"), revParse(t, path, "main"))
+
+	t.Run("Incremental", func(t *testing.T) {
+		require.NoError(t, runCmd(source, "git", "commit", "-m", "Second commit", "--allow-empty"))
+		incremental := filepath.Join(t.TempDir(), "incremental.bundle")
+		require.NoError(t, runCmd(source, "git", "bundle", "create", incremental, "v1.0.0..main"))
+
+		refs, err := store.Import(repoURL, incremental, true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"refs/heads/main"}, refs)
+		assert.Equal(t, revParse(t, source, "main"), revParse(t, path, "main"))
+		assert.Equal(t, revParse(t, source, "v1.0.0"), revParse(t, path, "v1.0.0"))
+
+		// the prerequisite commits are missing from a fresh copy
+		_, err = store.Import(repoURL, incremental, false)
+		assert.ErrorContains(t, err, "invalid bundle")
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		client, err := NewClientExt(fmt.Sprintf("file://%s", path), t.TempDir(), NopCreds{}, true, false, "")
+		require.NoError(t, err)
+		require.NoError(t, client.Init())
+		require.NoError(t, client.Fetch(""))
+		require.NoError(t, client.Checkout("main", false))
+		data, err := os.ReadFile(filepath.Join(client.Root(), "README"))
+		require.NoError(t, err)
+		assert.Equal(t, "Hello.", string(data))
+
+		// bundles exported from a clone import the remote tracking branches
+		exported := filepath.Join(t.TempDir(), "exported.bundle")
+		require.NoError(t, CreateBundle(client.Root(), exported))
+		const otherURL = "https://gitlab.example.com/other"
+		refs, err := store.Import(otherURL, exported, false)
+		require.NoError(t, err)
+		assert.Contains(t, refs, "refs/heads/main")
+		otherPath, ok := store.Path(otherURL)
+		require.True(t, ok)
+		assert.Equal(t, revParse(t, source, "main"), revParse(t, otherPath, "main"))
+	})
+}
@@ -80,11 +80,13 @@ type Client interface {
 	IsAnnotatedTag(string) bool
 	ChangedFiles(revision string, targetRevision string) ([]string, error)
 	IsRevisionPresent(revision string) bool
+	Blame(path string) ([]BlameLine, error)
 }
 
 type EventHandlers struct {
-	OnLsRemote func(repo string) func()
-	OnFetch    func(repo string) func()
+	OnLsRemote      func(repo string) func()
+	OnFetch         func(repo string) func()
+	OnShallowDeepen func(repo string)
 }
 
 // nativeGitClient implements Client interface using git CLI
@@ -107,6 +109,10 @@ type nativeGitClient struct {
 	loadRefFromCache bool
 	// HTTP/HTTPS proxy used to access repository
 	proxy string
+	// Depth of shallow fetches, zero fetches the full history
+	depth int
+	// Maximum depth a shallow clone is deepened to when a revision cannot be found, zero means the full history
+	maxDeepenDepth int
 }
 
 type runOpts struct {
@@ -152,6 +158,15 @@ func WithEventHandlers(handlers EventHandlers) ClientOpts {
 	}
 }
 
+// WithShallowClone makes the client fetch only the given number of commits, deepening the clone up to maxDeepenDepth
+// commits when a revision cannot be found
+func WithShallowClone(depth int, maxDeepenDepth int) ClientOpts {
+	return func(c *nativeGitClient) {
+		c.depth = depth
+		c.maxDeepenDepth = maxDeepenDepth
+	}
+}
+
 func NewClient(rawRepoURL string, creds Creds, insecure bool, enableLfs bool, proxy string, opts ...ClientOpts) (Client, error) {
 	r := regexp.MustCompile("(/|:)")
 	normalizedGitURL := NormalizeGitURL(rawRepoURL)
@@ -275,7 +290,7 @@ func newAuth(repoURL string, creds Creds) (transport.AuthMethod, error) {
 			// file.
 			auth.HostKeyCallback, err = knownhosts.New(certutil.GetSSHKnownHostsDataPath())
 			if err != nil {
-				log.Errorf("Could not set-up SSH known hosts callback: %v", err)
+				return nil, fmt.Errorf("could not set-up SSH known hosts callback: %w", err)
 			}
 		}
 		return auth, nil
@@ -347,13 +362,15 @@ func (m *nativeGitClient) IsLFSEnabled() bool {
 }
 
 func (m *nativeGitClient) fetch(revision string) error {
-	var err error
+	args := []string{"fetch", "origin"}
 	if revision != "" {
-		err = m.runCredentialedCmd("fetch", "origin", revision, "--tags", "--force", "--prune")
-	} else {
-		err = m.runCredentialedCmd("fetch", "origin", "--tags", "--force", "--prune")
+		args = append(args, revision)
 	}
-	return err
+	args = append(args, "--tags", "--force", "--prune")
+	if m.depth > 0 {
+		args = append(args, fmt.Sprintf("--depth=%d", m.depth))
+	}
+	return m.runCredentialedCmd(args...)
 }
 
 // IsRevisionPresent checks to see if the given revision already exists locally.
@@ -461,7 +478,7 @@ func (m *nativeGitClient) Checkout(revision string, submoduleEnabled bool) error
 	if revision == "" || revision == "HEAD" {
 		revision = "origin/HEAD"
 	}
-	if _, err := m.runCmd("checkout", "--force", revision); err != nil {
+	if err := m.checkout(revision); err != nil {
 		return err
 	}
 	// We must populate LFS content by using lfs checkout, if we have at least
@@ -495,6 +512,29 @@ func (m *nativeGitClient) Checkout(revision string, submoduleEnabled bool) error
 	return nil
 }
 
+// checkout checks out the given revision. If the revision is missing from a shallow clone, the clone is progressively
+// deepened until the revision is found or the maximum deepen depth is reached.
+func (m *nativeGitClient) checkout(revision string) error {
+	_, err := m.runCmd("checkout", "--force", revision)
+	if err == nil || m.depth <= 0 || !isRefNotFoundError(err) {
+		return err
+	}
+	for _, depth := range shallowDeepenDepths(m.depth, m.maxDeepenDepth) {
+		log.Debugf("Revision %s not found in shallow clone of %s, deepening to depth %d", revision, m.repoURL, depth)
+		if m.OnShallowDeepen != nil {
+			m.OnShallowDeepen(m.repoURL)
+		}
+		if err := m.runCredentialedCmd("fetch", "origin", "--tags", "--force", "--prune", fmt.Sprintf("--depth=%d", depth)); err != nil {
+			return fmt.Errorf("failed to deepen shallow clone to depth %d: %w", depth, err)
+		}
+		_, err = m.runCmd("checkout", "--force", revision)
+		if err == nil || !isRefNotFoundError(err) {
+			return err
+		}
+	}
+	return err
+}
+
 func (m *nativeGitClient) getRefs() ([]*plumbing.Reference, error) {
 	myLockUUID, err := uuid.NewRandom()
 	myLockId := ""
@@ -806,6 +846,15 @@ func (m *nativeGitClient) ChangedFiles(revision string, targetRevision string) (
 	return files, nil
 }
 
+// Blame returns the lines of a file of the checked out revision along with the commits which last modified them
+func (m *nativeGitClient) Blame(path string) ([]BlameLine, error) {
+	out, err := m.runCmd("blame", "--porcelain", "HEAD", "--", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to blame %s: %w", path, err)
+	}
+	return ParseBlamePorcelain(out)
+}
+
 // runWrapper runs a custom command with all the semantics of running the Git client
 func (m *nativeGitClient) runGnuPGWrapper(wrapper string, args ...string) (string, error) {
 	cmd := exec.Command(wrapper, args...)
@@ -1,15 +1,23 @@
 package git
 
 import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
 	"fmt"
+	"net"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/argoproj/argo-cd/v2/common"
 )
 
 func runCmd(workingDir string, name string, args ...string) error {
@@ -262,6 +270,122 @@ func TestNewClient_invalidSSHURL(t *testing.T) {
 	assert.ErrorIs(t, err, ErrInvalidRepoURL)
 }
 
+func newTestSSHSigner(t *testing.T) (ssh.Signer, ed25519.PrivateKey) {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	return signer, key
+}
+
+// startTestSSHServer starts an SSH server accepting any client, and returns its
+// address
+func startTestSSHServer(t *testing.T, hostKey ssh.Signer) string {
+	t.Helper()
+	config := &ssh.ServerConfig{NoClientAuth: true}
+	config.AddHostKey(hostKey)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				serverConn, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				defer serverConn.Close()
+				go ssh.DiscardRequests(reqs)
+				for newChannel := range chans {
+					_ = newChannel.Reject(ssh.Prohibited, "not supported")
+				}
+			}()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func Test_newAuth_SSHKnownHosts(t *testing.T) {
+	hostKey, _ := newTestSSHSigner(t)
+	_, clientKey := newTestSSHSigner(t)
+	clientKeyPEM, err := ssh.MarshalPrivateKey(clientKey, "")
+	require.NoError(t, err)
+	creds := NewSSHCreds(string(pem.EncodeToMemory(clientKeyPEM)), "", false, NoopCredsStore{}, "")
+
+	dial := func(t *testing.T, addr string, knownHosts string) error {
+		t.Helper()
+		sshDataPath := t.TempDir()
+		t.Setenv(common.EnvVarSSHDataPath, sshDataPath)
+		if knownHosts != "" {
+			require.NoError(t, os.WriteFile(filepath.Join(sshDataPath, common.DefaultSSHKnownHostsName), []byte(knownHosts), 0o644))
+		}
+		auth, err := newAuth("ssh://git@"+addr+"/repo.git", creds)
+		if err != nil {
+			return err
+		}
+		config, err := auth.(*PublicKeysWithOptions).ClientConfig()
+		require.NoError(t, err)
+		client, err := ssh.Dial("tcp", addr, config)
+		if err != nil {
+			return err
+		}
+		return client.Close()
+	}
+	knownHostsEntry := func(addr string, key ssh.PublicKey) string {
+		host, port, err := net.SplitHostPort(addr)
+		require.NoError(t, err)
+		return fmt.Sprintf("[%s]:%s %s", host, port, ssh.MarshalAuthorizedKey(key))
+	}
+
+	t.Run("Known host key", func(t *testing.T) {
+		addr := startTestSSHServer(t, hostKey)
+		require.NoError(t, dial(t, addr, knownHostsEntry(addr, hostKey.PublicKey())))
+	})
+
+	t.Run("Mismatching host key", func(t *testing.T) {
+		addr := startTestSSHServer(t, hostKey)
+		otherKey, _ := newTestSSHSigner(t)
+		err := dial(t, addr, knownHostsEntry(addr, otherKey.PublicKey()))
+		require.ErrorContains(t, err, "key mismatch")
+	})
+
+	t.Run("Unknown host", func(t *testing.T) {
+		addr := startTestSSHServer(t, hostKey)
+		err := dial(t, addr, knownHostsEntry("127.0.0.2:22", hostKey.PublicKey()))
+		require.ErrorContains(t, err, "key is unknown")
+	})
+
+	t.Run("Missing known hosts file", func(t *testing.T) {
+		err := dial(t, "127.0.0.1:22", "")
+		require.ErrorContains(t, err, "could not set-up SSH known hosts callback")
+	})
+
+	t.Run("Host certificate signed by certificate authority", func(t *testing.T) {
+		ca, _ := newTestSSHSigner(t)
+		cert := &ssh.Certificate{
+			Key:             hostKey.PublicKey(),
+			CertType:        ssh.HostCert,
+			ValidPrincipals: []string{"127.0.0.1"},
+			ValidBefore:     ssh.CertTimeInfinity,
+		}
+		require.NoError(t, cert.SignCert(rand.Reader, ca))
+		certSigner, err := ssh.NewCertSigner(cert, hostKey)
+		require.NoError(t, err)
+		addr := startTestSSHServer(t, certSigner)
+		require.NoError(t, dial(t, addr, "@cert-authority "+knownHostsEntry(addr, ca.PublicKey())))
+
+		otherCA, _ := newTestSSHSigner(t)
+		err = dial(t, addr, "@cert-authority "+knownHostsEntry(addr, otherCA.PublicKey()))
+		require.Error(t, err)
+	})
+}
+
 func Test_IsRevisionPresent(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -296,3 +420,49 @@ func Test_IsRevisionPresent(t *testing.T) {
 	revisionPresent = client.IsRevisionPresent("invalid-revision")
 	assert.False(t, revisionPresent)
 }
+
+func Test_nativeGitClient_ShallowCloneDeepen(t *testing.T) {
+	tempDir, err := _createEmptyGitRepo()
+	require.NoError(t, err)
+	for i := 0; i < 5; i++ {
+		err = runCmd(tempDir, "git", "commit", "-m", fmt.Sprintf("Commit %d", i), "--allow-empty")
+		require.NoError(t, err)
+	}
+	out, err := exec.Command("git", "-C", tempDir, "rev-parse", "HEAD~5").Output()
+	require.NoError(t, err)
+	revision := strings.TrimSpace(string(out))
+
+	newShallowClient := func(t *testing.T, maxDeepenDepth int, deepened *int) Client {
+		t.Helper()
+		client, err := NewClientExt(fmt.Sprintf("file://%s", tempDir), t.TempDir(), NopCreds{}, true, false, "",
+			WithShallowClone(1, maxDeepenDepth),
+			WithEventHandlers(EventHandlers{OnShallowDeepen: func(repo string) { *deepened++ }}))
+		require.NoError(t, err)
+		require.NoError(t, client.Init())
+		require.NoError(t, client.Fetch(""))
+		assert.False(t, client.IsRevisionPresent(revision))
+		return client
+	}
+
+	t.Run("Deepened", func(t *testing.T) {
+		deepened := 0
+		client := newShallowClient(t, 0, &deepened)
+
+		err := client.Checkout(revision, false)
+		require.NoError(t, err)
+		commitSHA, err := client.CommitSHA()
+		require.NoError(t, err)
+		assert.Equal(t, revision, commitSHA)
+		assert.Equal(t, 1, deepened)
+	})
+
+	t.Run("MaxDeepenDepthReached", func(t *testing.T) {
+		deepened := 0
+		client := newShallowClient(t, 3, &deepened)
+
+		err := client.Checkout(revision, false)
+		require.Error(t, err)
+		assert.True(t, isRefNotFoundError(err))
+		assert.Equal(t, 1, deepened)
+	})
+}
@@ -1,6 +1,7 @@
 package git
 
 import (
+	"math"
 	"net/url"
 	"regexp"
 	"strings"
@@ -91,3 +92,31 @@ func TestRepo(repo string, creds Creds, insecure bool, enableLfs bool, proxy str
 	_, err = clnt.LsRemote("HEAD")
 	return err
 }
+
+var refNotFoundRegex = regexp.MustCompile(`reference not found|reference is not a tree|did not match any file\(s\) known to git|unknown revision`)
+
+// isRefNotFoundError returns whether the given error reports a revision which is missing from the local repository
+func isRefNotFoundError(err error) bool {
+	return err != nil && refNotFoundRegex.MatchString(err.Error())
+}
+
+// fullHistoryDepth is the depth git treats as the full history of a repository
+const fullHistoryDepth = math.MaxInt32
+
+// shallowDeepenDepths returns the depths a shallow clone of the given depth is progressively deepened to when a
+// revision cannot be found (10, 100 and then the full history), capped at maxDepth. A maxDepth of zero allows the full history.
+func shallowDeepenDepths(depth int, maxDepth int) []int {
+	var depths []int
+	for _, next := range []int{10, 100, fullHistoryDepth} {
+		if maxDepth > 0 && next > maxDepth {
+			next = maxDepth
+		}
+		if next > depth && (len(depths) == 0 || next > depths[len(depths)-1]) {
+			depths = append(depths, next)
+		}
+		if next == maxDepth {
+			break
+		}
+	}
+	return depths
+}
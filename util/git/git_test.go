@@ -1,6 +1,7 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -506,3 +507,20 @@ func TestLsFiles(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, nilResult, lsResult)
 }
+
+func TestIsRefNotFoundError(t *testing.T) {
+	assert.False(t, isRefNotFoundError(nil))
+	assert.False(t, isRefNotFoundError(errors.New("fatal: could not read from remote repository")))
+	assert.True(t, isRefNotFoundError(errors.New("reference not found")))
+	assert.True(t, isRefNotFoundError(errors.New("`git checkout --force abc` failed exit status 128: fatal: reference is not a tree: abc")))
+	assert.True(t, isRefNotFoundError(errors.New("error: pathspec 'abc' did not match any file(s) known to git")))
+}
+
+func TestShallowDeepenDepths(t *testing.T) {
+	assert.Equal(t, []int{10, 100, fullHistoryDepth}, shallowDeepenDepths(1, 0))
+	assert.Equal(t, []int{100, fullHistoryDepth}, shallowDeepenDepths(10, 0))
+	assert.Equal(t, []int{10, 50}, shallowDeepenDepths(1, 50))
+	assert.Equal(t, []int{10, 100}, shallowDeepenDepths(1, 100))
+	assert.Equal(t, []int{5}, shallowDeepenDepths(1, 5))
+	assert.Empty(t, shallowDeepenDepths(10, 10))
+}
@@ -12,6 +12,36 @@ type Client struct {
 	mock.Mock
 }
 
+// Blame provides a mock function with given fields: path
+func (_m *Client) Blame(path string) ([]git.BlameLine, error) {
+	ret := _m.Called(path)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Blame")
+	}
+
+	var r0 []git.BlameLine
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string) ([]git.BlameLine, error)); ok {
+		return rf(path)
+	}
+	if rf, ok := ret.Get(0).(func(string) []git.BlameLine); ok {
+		r0 = rf(path)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]git.BlameLine)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(path)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ChangedFiles provides a mock function with given fields: revision, targetRevision
 func (_m *Client) ChangedFiles(revision string, targetRevision string) ([]string, error) {
 	ret := _m.Called(revision, targetRevision)
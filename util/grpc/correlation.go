@@ -0,0 +1,127 @@
+package grpc
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/uuid"
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// CorrelationIDHeader is the HTTP header carrying the correlation ID of a request
+	CorrelationIDHeader = "X-Correlation-ID"
+	// CorrelationIDMetadataKey is the gRPC metadata key carrying the correlation ID of a request
+	CorrelationIDMetadataKey = "x-correlation-id"
+	// CorrelationIDLogField is the name of the log field holding the correlation ID
+	CorrelationIDLogField = "correlation_id"
+
+	// maxCorrelationIDLength caps the length of correlation IDs accepted from callers
+	maxCorrelationIDLength = 128
+)
+
+// correlationIDRegexp matches the correlation IDs accepted from callers, such as UUIDs. Other IDs are replaced by a
+// generated one, so that caller input cannot inject arbitrary content into log lines.
+var correlationIDRegexp = regexp.MustCompile(`^[A-Za-z0-9._:-]+$`)
+
+type correlationIDContextKey struct{}
+
+// CorrelationIDFromContext returns the correlation ID stored in the context, or an empty string
+// if the request was not handled by a correlation ID interceptor
+func CorrelationIDFromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(correlationIDContextKey{}).(string); ok {
+		return id
+	}
+	return ""
+}
+
+// LoggerFromContext returns a logger for the request of the context. It writes to the standard logger, like the
+// package-level logger, and includes the correlation ID of the request, if any. Handlers should log using it so that
+// all log lines of a request can be correlated.
+func LoggerFromContext(ctx context.Context) *logrus.Entry {
+	entry := logrus.NewEntry(logrus.StandardLogger())
+	if id := CorrelationIDFromContext(ctx); id != "" {
+		entry = entry.WithField(CorrelationIDLogField, id)
+	}
+	return entry
+}
+
+// incomingCorrelationID returns the correlation ID supplied by the caller, if any
+func incomingCorrelationID(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	for _, id := range md.Get(CorrelationIDMetadataKey) {
+		if len(id) <= maxCorrelationIDLength && correlationIDRegexp.MatchString(id) {
+			return id
+		}
+	}
+	return ""
+}
+
+// withCorrelationID reuses the caller supplied correlation ID or generates a new one, then adds it
+// to the context and to the call-scoped logger so that every log line of the handler includes it.
+func withCorrelationID(ctx context.Context) (context.Context, string) {
+	id := incomingCorrelationID(ctx)
+	if id == "" {
+		id = uuid.New().String()
+	}
+	ctxlogrus.AddFields(ctx, logrus.Fields{CorrelationIDLogField: id})
+	return context.WithValue(ctx, correlationIDContextKey{}, id), id
+}
+
+// CorrelationIDUnaryServerInterceptor returns a UnaryServerInterceptor which assigns a correlation ID
+// to every call, adds it to the call-scoped logger and returns it in the response header metadata.
+// It must be chained after the grpc_logrus interceptor.
+func CorrelationIDUnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, id := withCorrelationID(ctx)
+		if err := grpc.SetHeader(ctx, metadata.Pairs(CorrelationIDMetadataKey, id)); err != nil {
+			ctxlogrus.Extract(ctx).Warnf("failed to set correlation ID header: %v", err)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// CorrelationIDStreamServerInterceptor returns a StreamServerInterceptor which assigns a correlation ID
+// to every call, adds it to the call-scoped logger and returns it in the response header metadata.
+// It must be chained after the grpc_logrus interceptor.
+func CorrelationIDStreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, id := withCorrelationID(stream.Context())
+		if err := stream.SetHeader(metadata.Pairs(CorrelationIDMetadataKey, id)); err != nil {
+			ctxlogrus.Extract(ctx).Warnf("failed to set correlation ID header: %v", err)
+		}
+		wrapped := grpc_middleware.WrapServerStream(stream)
+		wrapped.WrappedContext = ctx
+		return handler(srv, wrapped)
+	}
+}
+
+// CorrelationIDUnaryClientInterceptor returns a UnaryClientInterceptor which forwards the correlation ID
+// of the current request to the called service
+func CorrelationIDUnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingCorrelationID(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// CorrelationIDStreamClientInterceptor returns a StreamClientInterceptor which forwards the correlation ID
+// of the current request to the called service
+func CorrelationIDStreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingCorrelationID(ctx), desc, cc, method, opts...)
+	}
+}
+
+func outgoingCorrelationID(ctx context.Context) context.Context {
+	if id := CorrelationIDFromContext(ctx); id != "" {
+		return metadata.AppendToOutgoingContext(ctx, CorrelationIDMetadataKey, id)
+	}
+	return ctx
+}
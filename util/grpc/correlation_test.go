@@ -0,0 +1,210 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
+	grpc_logrus "github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/logrus/ctxlogrus"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	header metadata.MD
+}
+
+func (f *fakeServerStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeServerStream) SetHeader(md metadata.MD) error {
+	f.header = metadata.Join(f.header, md)
+	return nil
+}
+
+type fakeServerTransportStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (f *fakeServerTransportStream) SetHeader(md metadata.MD) error {
+	f.header = metadata.Join(f.header, md)
+	return nil
+}
+
+func newJSONLogger() (*logrus.Entry, *bytes.Buffer) {
+	l := logrus.New()
+	l.SetFormatter(&logrus.JSONFormatter{})
+	var buf bytes.Buffer
+	l.SetOutput(&buf)
+	return logrus.NewEntry(l), &buf
+}
+
+// logLines parses every JSON log line written to the buffer
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		fields := map[string]interface{}{}
+		require.NoError(t, json.Unmarshal([]byte(line), &fields))
+		lines = append(lines, fields)
+	}
+	return lines
+}
+
+func Test_CorrelationIDUnaryServerInterceptor(t *testing.T) {
+	entry, buf := newJSONLogger()
+	interceptor := grpc_middleware.ChainUnaryServer(
+		grpc_logrus.UnaryServerInterceptor(entry),
+		CorrelationIDUnaryServerInterceptor(),
+		PayloadUnaryServerInterceptor(entry, false, func(ctx context.Context, fullMethodName string, servingObject interface{}) bool {
+			return true
+		}),
+	)
+	var handlerID string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handlerID = CorrelationIDFromContext(ctx)
+		ctxlogrus.Extract(ctx).Info("inside handler")
+		return nil, nil
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}
+
+	t.Run("generates an ID which is included in all log lines", func(t *testing.T) {
+		buf.Reset()
+		transportStream := &fakeServerTransportStream{}
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), transportStream)
+		_, err := interceptor(ctx, nil, info, handler)
+		require.NoError(t, err)
+		require.NotEmpty(t, handlerID)
+		assert.Equal(t, []string{handlerID}, transportStream.header.Get(CorrelationIDMetadataKey))
+
+		lines := logLines(t, buf)
+		require.Len(t, lines, 3)
+		for _, line := range lines {
+			assert.Equal(t, handlerID, line[CorrelationIDLogField])
+		}
+	})
+
+	t.Run("reuses the ID supplied by the caller", func(t *testing.T) {
+		buf.Reset()
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), &fakeServerTransportStream{})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(CorrelationIDMetadataKey, "my-correlation-id"))
+		_, err := interceptor(ctx, nil, info, handler)
+		require.NoError(t, err)
+		assert.Equal(t, "my-correlation-id", handlerID)
+
+		for _, line := range logLines(t, buf) {
+			assert.Equal(t, "my-correlation-id", line[CorrelationIDLogField])
+		}
+	})
+
+	t.Run("ignores an oversized ID supplied by the caller", func(t *testing.T) {
+		buf.Reset()
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), &fakeServerTransportStream{})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(CorrelationIDMetadataKey, strings.Repeat("a", maxCorrelationIDLength+1)))
+		_, err := interceptor(ctx, nil, info, handler)
+		require.NoError(t, err)
+		assert.Len(t, handlerID, 36)
+	})
+	t.Run("ignores an ID with invalid characters supplied by the caller", func(t *testing.T) {
+		buf.Reset()
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), &fakeServerTransportStream{})
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(CorrelationIDMetadataKey, "id\nlevel=error msg=injected"))
+		_, err := interceptor(ctx, nil, info, handler)
+		require.NoError(t, err)
+		assert.Len(t, handlerID, 36)
+	})
+}
+
+func Test_LoggerFromContext(t *testing.T) {
+	entry, buf := newJSONLogger()
+	original := logrus.StandardLogger().Out
+	originalFormatter := logrus.StandardLogger().Formatter
+	logrus.SetOutput(buf)
+	logrus.SetFormatter(&logrus.JSONFormatter{})
+	defer func() {
+		logrus.SetOutput(original)
+		logrus.SetFormatter(originalFormatter)
+	}()
+
+	interceptor := grpc_middleware.ChainUnaryServer(
+		grpc_logrus.UnaryServerInterceptor(entry),
+		CorrelationIDUnaryServerInterceptor(),
+	)
+	var handlerID string
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		handlerID = CorrelationIDFromContext(ctx)
+		LoggerFromContext(ctx).WithField("application", "guestbook").Info("inside handler")
+		return nil, nil
+	}
+	ctx := grpc.NewContextWithServerTransportStream(context.Background(), &fakeServerTransportStream{})
+	_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test.Service/Method"}, handler)
+	require.NoError(t, err)
+
+	lines := logLines(t, buf)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "inside handler", lines[0]["msg"])
+	assert.Equal(t, "guestbook", lines[0]["application"])
+	for _, line := range lines {
+		assert.Equal(t, handlerID, line[CorrelationIDLogField])
+	}
+
+	buf.Reset()
+	LoggerFromContext(context.Background()).Info("outside of a request")
+	lines = logLines(t, buf)
+	require.Len(t, lines, 1)
+	assert.NotContains(t, lines[0], CorrelationIDLogField)
+}
+
+func Test_CorrelationIDStreamServerInterceptor(t *testing.T) {
+	entry, buf := newJSONLogger()
+	interceptor := grpc_middleware.ChainStreamServer(
+		grpc_logrus.StreamServerInterceptor(entry),
+		CorrelationIDStreamServerInterceptor(),
+	)
+	stream := &fakeServerStream{ctx: context.Background()}
+	var handlerID string
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		handlerID = CorrelationIDFromContext(stream.Context())
+		ctxlogrus.Extract(stream.Context()).Info("inside handler")
+		return nil
+	}
+	err := interceptor(nil, stream, &grpc.StreamServerInfo{FullMethod: "/test.Service/Stream"}, handler)
+	require.NoError(t, err)
+	require.NotEmpty(t, handlerID)
+	assert.Equal(t, []string{handlerID}, stream.header.Get(CorrelationIDMetadataKey))
+
+	lines := logLines(t, buf)
+	require.Len(t, lines, 2)
+	for _, line := range lines {
+		assert.Equal(t, handlerID, line[CorrelationIDLogField])
+	}
+}
+
+func Test_CorrelationIDUnaryClientInterceptor(t *testing.T) {
+	interceptor := CorrelationIDUnaryClientInterceptor()
+	invoke := func(ctx context.Context) []string {
+		var ids []string
+		err := interceptor(ctx, "/test.Service/Method", nil, nil, nil, func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			ids = md.Get(CorrelationIDMetadataKey)
+			return nil
+		})
+		require.NoError(t, err)
+		return ids
+	}
+
+	assert.Empty(t, invoke(context.Background()))
+	ctx := context.WithValue(context.Background(), correlationIDContextKey{}, "my-correlation-id")
+	assert.Equal(t, []string{"my-correlation-id"}, invoke(ctx))
+}
@@ -0,0 +1,126 @@
+package health
+
+import (
+	"fmt"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// CrossplaneAPIExtensionsGroup is the API group of the Crossplane resources defining composite resources
+	CrossplaneAPIExtensionsGroup = "apiextensions.crossplane.io"
+
+	CompositeResourceDefinitionKind = "CompositeResourceDefinition"
+
+	crossplaneConditionReady       = "Ready"
+	crossplaneConditionSynced      = "Synced"
+	crossplaneConditionEstablished = "Established"
+	crossplaneConditionOffered     = "Offered"
+
+	// crossplaneReasonUnavailable is the reason of the Ready condition of the composite resources whose composed
+	// resources became unavailable after being created
+	crossplaneReasonUnavailable = "Unavailable"
+)
+
+// crossplaneStatus is the part of the status of a Crossplane resource which is used to assess its health
+type crossplaneStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// getCrossplaneHealthCheckFunc returns the health check of the given Crossplane kind, or nil if the kind is not a
+// Crossplane resource with a health check. Composite resources are of kinds defined by users, and are matched by the
+// structure of their objects instead.
+func getCrossplaneHealthCheckFunc(gvk schema.GroupVersionKind) func(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if gvk.Group == CrossplaneAPIExtensionsGroup && gvk.Kind == CompositeResourceDefinitionKind {
+		return getCompositeResourceDefinitionHealth
+	}
+	return nil
+}
+
+// isCrossplaneComposite returns true if the resource is a Crossplane composite resource or claim, which reference the
+// composition they are composed with. The references of composite resources are nested in spec.crossplane since
+// Crossplane v2.
+func isCrossplaneComposite(obj *unstructured.Unstructured) bool {
+	if obj.GroupVersionKind().Group == CrossplaneAPIExtensionsGroup {
+		return false
+	}
+	for _, fields := range [][]string{
+		{"spec", "compositionRef"},
+		{"spec", "compositionSelector"},
+		{"spec", "crossplane", "compositionRef"},
+		{"spec", "crossplane", "compositionSelector"},
+	} {
+		if value, found, err := unstructured.NestedMap(obj.Object, fields...); err == nil && found && len(value) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// getCrossplaneCompositeHealth returns the health of a composite resource or claim, which is aggregated by Crossplane
+// from its composed resources into its Ready condition. It is healthy once it is both ready and synced, degraded when
+// its reconciliation failed or its composed resources became unavailable, and progressing otherwise.
+func getCrossplaneCompositeHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	var status crossplaneStatus
+	if err := convertStatus(obj, &status); err != nil {
+		return nil, err
+	}
+	synced := apimeta.FindStatusCondition(status.Conditions, crossplaneConditionSynced)
+	if synced != nil && synced.Status == metav1.ConditionFalse {
+		return &health.HealthStatus{Status: health.HealthStatusDegraded, Message: conditionMessage(synced)}, nil
+	}
+	ready := apimeta.FindStatusCondition(status.Conditions, crossplaneConditionReady)
+	if ready != nil && ready.Status == metav1.ConditionFalse && ready.Reason == crossplaneReasonUnavailable {
+		return &health.HealthStatus{Status: health.HealthStatusDegraded, Message: conditionMessage(ready)}, nil
+	}
+	if ready == nil || ready.Status != metav1.ConditionTrue {
+		message := fmt.Sprintf("Waiting for the %s to be ready", obj.GetKind())
+		if ready != nil && ready.Message != "" {
+			message = ready.Message
+		}
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: message}, nil
+	}
+	if synced == nil || synced.Status != metav1.ConditionTrue {
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: fmt.Sprintf("Waiting for the %s to be synced", obj.GetKind())}, nil
+	}
+	return &health.HealthStatus{Status: health.HealthStatusHealthy, Message: fmt.Sprintf("%s is ready and synced", obj.GetKind())}, nil
+}
+
+// getCompositeResourceDefinitionHealth returns the health of a CompositeResourceDefinition, which is healthy once the
+// CRD of its composite resource is established and, if it defines a claim, the CRD of its claim is offered
+func getCompositeResourceDefinitionHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	var status crossplaneStatus
+	if err := convertStatus(obj, &status); err != nil {
+		return nil, err
+	}
+	established := apimeta.FindStatusCondition(status.Conditions, crossplaneConditionEstablished)
+	if established != nil && established.Status == metav1.ConditionFalse {
+		return &health.HealthStatus{Status: health.HealthStatusDegraded, Message: conditionMessage(established)}, nil
+	}
+	if established == nil || established.Status != metav1.ConditionTrue {
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: fmt.Sprintf("Waiting for the %s to be established", obj.GetKind())}, nil
+	}
+	if claimNames, found, _ := unstructured.NestedMap(obj.Object, "spec", "claimNames"); found && len(claimNames) > 0 {
+		offered := apimeta.FindStatusCondition(status.Conditions, crossplaneConditionOffered)
+		if offered != nil && offered.Status == metav1.ConditionFalse {
+			return &health.HealthStatus{Status: health.HealthStatusDegraded, Message: conditionMessage(offered)}, nil
+		}
+		if offered == nil || offered.Status != metav1.ConditionTrue {
+			return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: fmt.Sprintf("Waiting for the claim of the %s to be offered", obj.GetKind())}, nil
+		}
+	}
+	return &health.HealthStatus{Status: health.HealthStatusHealthy, Message: fmt.Sprintf("%s is established", obj.GetKind())}, nil
+}
+
+// conditionMessage returns the message of the condition, or its reason if it has no message, like the conditions of
+// Crossplane resources whose reason describes the state
+func conditionMessage(condition *metav1.Condition) string {
+	if condition.Message != "" {
+		return condition.Message
+	}
+	return condition.Reason
+}
@@ -0,0 +1,123 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGetHealthCheckFunc_Crossplane(t *testing.T) {
+	for _, version := range []string{"v1", "v2"} {
+		assert.NotNil(t, GetHealthCheckFunc(schema.GroupVersionKind{Group: CrossplaneAPIExtensionsGroup, Version: version, Kind: CompositeResourceDefinitionKind}))
+	}
+	assert.Nil(t, GetHealthCheckFunc(schema.GroupVersionKind{Group: CrossplaneAPIExtensionsGroup, Version: "v1", Kind: "Composition"}))
+}
+
+func TestGetResourceHealthCheckFunc_CrossplaneComposite(t *testing.T) {
+	for _, path := range []string{
+		"testdata/crossplane/composite_healthy.yaml",
+		"testdata/crossplane/claim_healthy.yaml",
+		"testdata/crossplane/composite_v2_degraded_unavailable.yaml",
+	} {
+		assert.NotNil(t, GetResourceHealthCheckFunc(loadTestResource(t, path)), path)
+	}
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "config"},
+	}}
+	assert.Nil(t, GetResourceHealthCheckFunc(configMap))
+	composition := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apiextensions.crossplane.io/v1",
+		"kind":       "Composition",
+		"metadata":   map[string]interface{}{"name": "xpostgresqlinstances.aws.database.example.org"},
+		"spec":       map[string]interface{}{"compositionRef": map[string]interface{}{"name": "invalid"}},
+	}}
+	assert.Nil(t, GetResourceHealthCheckFunc(composition))
+}
+
+func TestGetCrossplaneCompositeHealth(t *testing.T) {
+	assertHealth(t, []healthTestCase{
+		{"testdata/crossplane/composite_healthy.yaml", health.HealthStatusHealthy, "XPostgreSQLInstance is ready and synced"},
+		{"testdata/crossplane/claim_healthy.yaml", health.HealthStatusHealthy, "PostgreSQLInstance is ready and synced"},
+		{"testdata/crossplane/composite_progressing_creating.yaml", health.HealthStatusProgressing, "Unready resources: my-db-7xqzk-8bwl2"},
+		{"testdata/crossplane/composite_degraded_reconcileError.yaml", health.HealthStatusDegraded, `cannot compose resources: cannot apply composed resource "instance": admission webhook denied the request`},
+		{"testdata/crossplane/composite_v2_degraded_unavailable.yaml", health.HealthStatusDegraded, "Unready resources: podinfo"},
+	})
+
+	condition := func(conditionType, status, reason string) interface{} {
+		if status == "" {
+			return nil
+		}
+		return map[string]interface{}{"type": conditionType, "status": status, "reason": reason, "lastTransitionTime": "2024-03-12T09:30:12Z"}
+	}
+	tests := []struct {
+		ready   string
+		reason  string
+		synced  string
+		status  health.HealthStatusCode
+		message string
+	}{
+		{"True", "Available", "True", health.HealthStatusHealthy, "XBucket is ready and synced"},
+		{"True", "Available", "False", health.HealthStatusDegraded, "ReconcileError"},
+		{"True", "Available", "Unknown", health.HealthStatusProgressing, "Waiting for the XBucket to be synced"},
+		{"True", "Available", "", health.HealthStatusProgressing, "Waiting for the XBucket to be synced"},
+		{"False", "Creating", "True", health.HealthStatusProgressing, "Waiting for the XBucket to be ready"},
+		{"False", "Creating", "False", health.HealthStatusDegraded, "ReconcileError"},
+		{"False", "Creating", "Unknown", health.HealthStatusProgressing, "Waiting for the XBucket to be ready"},
+		{"False", "Creating", "", health.HealthStatusProgressing, "Waiting for the XBucket to be ready"},
+		{"False", "Unavailable", "True", health.HealthStatusDegraded, "Unavailable"},
+		{"False", "Unavailable", "False", health.HealthStatusDegraded, "ReconcileError"},
+		{"False", "Deleting", "True", health.HealthStatusProgressing, "Waiting for the XBucket to be ready"},
+		{"Unknown", "", "True", health.HealthStatusProgressing, "Waiting for the XBucket to be ready"},
+		{"Unknown", "", "False", health.HealthStatusDegraded, "ReconcileError"},
+		{"", "", "True", health.HealthStatusProgressing, "Waiting for the XBucket to be ready"},
+		{"", "", "False", health.HealthStatusDegraded, "ReconcileError"},
+		{"", "", "", health.HealthStatusProgressing, "Waiting for the XBucket to be ready"},
+	}
+	for _, tt := range tests {
+		t.Run("Ready="+tt.ready+"/"+tt.reason+",Synced="+tt.synced, func(t *testing.T) {
+			conditions := []interface{}{}
+			for _, c := range []interface{}{condition("Ready", tt.ready, tt.reason), condition("Synced", tt.synced, "ReconcileError")} {
+				if c != nil {
+					conditions = append(conditions, c)
+				}
+			}
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "storage.example.org/v1alpha1",
+				"kind":       "XBucket",
+				"metadata":   map[string]interface{}{"name": "bucket"},
+				"spec":       map[string]interface{}{"compositionRef": map[string]interface{}{"name": "xbuckets.aws.storage.example.org"}},
+				"status":     map[string]interface{}{"conditions": conditions},
+			}}
+			healthStatus, err := getCrossplaneCompositeHealth(obj)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, healthStatus.Status)
+			assert.Equal(t, tt.message, healthStatus.Message)
+		})
+	}
+}
+
+func TestGetCompositeResourceDefinitionHealth(t *testing.T) {
+	assertHealth(t, []healthTestCase{
+		{"testdata/crossplane/xrd_healthy.yaml", health.HealthStatusHealthy, "CompositeResourceDefinition is established"},
+		{"testdata/crossplane/xrd_degraded.yaml", health.HealthStatusDegraded, "cannot render composite resource CustomResourceDefinition: spec.versions[0].schema.openAPIV3Schema: Required value"},
+		{"testdata/crossplane/xrd_progressing_offered.yaml", health.HealthStatusProgressing, "Waiting for the claim of the CompositeResourceDefinition to be offered"},
+		{"testdata/crossplane/xrd_progressing_noStatus.yaml", health.HealthStatusProgressing, "Waiting for the CompositeResourceDefinition to be established"},
+	})
+}
+
+func TestGetResourceHealth_CrossplaneComposite(t *testing.T) {
+	obj := loadTestResource(t, "testdata/crossplane/composite_progressing_creating.yaml")
+	healthStatus, err := GetResourceHealth(obj, nil, health.HealthStatusHealthy)
+	require.NoError(t, err)
+	assert.Equal(t, health.HealthStatusProgressing, healthStatus.Status)
+
+	healthStatus, err = NewExtendedHealthOverride(nil).GetResourceHealth(obj)
+	require.NoError(t, err)
+	assert.Equal(t, health.HealthStatusProgressing, healthStatus.Status)
+}
@@ -0,0 +1,145 @@
+package health
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// GatewayAPIGroup is the API group of the Kubernetes Gateway API resources
+	GatewayAPIGroup = "gateway.networking.k8s.io"
+
+	GatewayKind   = "Gateway"
+	HTTPRouteKind = "HTTPRoute"
+	GRPCRouteKind = "GRPCRoute"
+
+	gatewayConditionAccepted     = "Accepted"
+	gatewayConditionProgrammed   = "Programmed"
+	gatewayConditionResolvedRefs = "ResolvedRefs"
+	gatewayReasonPending         = "Pending"
+)
+
+// gatewayStatus is the part of the status of a Gateway which is used to assess its health
+type gatewayStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// routeParentReference identifies a parent of a route
+type routeParentReference struct {
+	Namespace   string `json:"namespace,omitempty"`
+	Name        string `json:"name"`
+	SectionName string `json:"sectionName,omitempty"`
+}
+
+func (r routeParentReference) String() string {
+	name := r.Name
+	if r.Namespace != "" {
+		name = r.Namespace + "/" + name
+	}
+	if r.SectionName != "" {
+		name = name + "/" + r.SectionName
+	}
+	return name
+}
+
+// routeStatus is the part of the status of a HTTPRoute or GRPCRoute which is used to assess its health
+type routeStatus struct {
+	Parents []struct {
+		ParentRef  routeParentReference `json:"parentRef"`
+		Conditions []metav1.Condition   `json:"conditions,omitempty"`
+	} `json:"parents,omitempty"`
+}
+
+// getGatewayAPIHealthCheckFunc returns the health check of the given Gateway API kind, or nil if the kind is not a
+// Gateway API resource with a health check
+func getGatewayAPIHealthCheckFunc(gvk schema.GroupVersionKind) func(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if gvk.Group != GatewayAPIGroup {
+		return nil
+	}
+	switch gvk.Kind {
+	case GatewayKind:
+		return getGatewayHealth
+	case HTTPRouteKind, GRPCRouteKind:
+		return getRouteHealth
+	}
+	return nil
+}
+
+// isStale returns true if the condition was not updated for the current generation of the resource
+func isStale(obj *unstructured.Unstructured, condition *metav1.Condition) bool {
+	return condition.ObservedGeneration != 0 && condition.ObservedGeneration < obj.GetGeneration()
+}
+
+// getGatewayHealth returns the health of a Gateway, which is healthy once it is programmed in the data plane
+func getGatewayHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	var status gatewayStatus
+	if err := convertStatus(obj, &status); err != nil {
+		return nil, err
+	}
+	if accepted := apimeta.FindStatusCondition(status.Conditions, gatewayConditionAccepted); accepted != nil && accepted.Status == metav1.ConditionFalse && !isStale(obj, accepted) {
+		return &health.HealthStatus{Status: health.HealthStatusDegraded, Message: accepted.Message}, nil
+	}
+	programmed := apimeta.FindStatusCondition(status.Conditions, gatewayConditionProgrammed)
+	switch {
+	case programmed == nil:
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: "Waiting for the gateway to be programmed"}, nil
+	case isStale(obj, programmed):
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: "Waiting for the controller to observe the latest generation of the gateway"}, nil
+	case programmed.Status == metav1.ConditionTrue:
+		return &health.HealthStatus{Status: health.HealthStatusHealthy, Message: programmed.Message}, nil
+	case programmed.Status == metav1.ConditionFalse && programmed.Reason != gatewayReasonPending:
+		return &health.HealthStatus{Status: health.HealthStatusDegraded, Message: programmed.Message}, nil
+	}
+	return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: programmed.Message}, nil
+}
+
+// getRouteHealth returns the health of a HTTPRoute or GRPCRoute, which is healthy once all its parents accepted it
+func getRouteHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	var status routeStatus
+	if err := convertStatus(obj, &status); err != nil {
+		return nil, err
+	}
+	parentRefs, _, _ := unstructured.NestedSlice(obj.Object, "spec", "parentRefs")
+	if len(status.Parents) == 0 || len(status.Parents) < len(parentRefs) {
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: "Waiting for the route to be accepted by its parents"}, nil
+	}
+	var degraded, progressing []string
+	for _, parent := range status.Parents {
+		accepted := apimeta.FindStatusCondition(parent.Conditions, gatewayConditionAccepted)
+		resolvedRefs := apimeta.FindStatusCondition(parent.Conditions, gatewayConditionResolvedRefs)
+		switch {
+		case accepted == nil || isStale(obj, accepted) || accepted.Status == metav1.ConditionUnknown:
+			progressing = append(progressing, fmt.Sprintf("Waiting for parent %s to accept the route", parent.ParentRef))
+		case accepted.Status == metav1.ConditionFalse:
+			degraded = append(degraded, fmt.Sprintf("Route is not accepted by parent %s: %s", parent.ParentRef, accepted.Message))
+		case resolvedRefs != nil && resolvedRefs.Status == metav1.ConditionFalse && !isStale(obj, resolvedRefs):
+			degraded = append(degraded, fmt.Sprintf("Route references of parent %s are not resolved: %s", parent.ParentRef, resolvedRefs.Message))
+		}
+	}
+	if len(degraded) > 0 {
+		return &health.HealthStatus{Status: health.HealthStatusDegraded, Message: strings.Join(degraded, "; ")}, nil
+	}
+	if len(progressing) > 0 {
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: strings.Join(progressing, "; ")}, nil
+	}
+	return &health.HealthStatus{Status: health.HealthStatusHealthy, Message: "Route is accepted by all its parents"}, nil
+}
+
+// convertStatus converts the status of the resource to the given structure
+func convertStatus(obj *unstructured.Unstructured, status interface{}) error {
+	statusObj, _, err := unstructured.NestedMap(obj.Object, "status")
+	if err != nil {
+		return fmt.Errorf("failed to get the status of %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(statusObj, status); err != nil {
+		return fmt.Errorf("failed to convert the status of %s %s: %w", obj.GetKind(), obj.GetName(), err)
+	}
+	return nil
+}
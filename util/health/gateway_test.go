@@ -0,0 +1,178 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+func newGatewayAPIResource(t *testing.T, kind string, manifest string) *unstructured.Unstructured {
+	t.Helper()
+	obj := &unstructured.Unstructured{}
+	require.NoError(t, yaml.Unmarshal([]byte("apiVersion: gateway.networking.k8s.io/v1\nkind: "+kind+"\n"+manifest), obj))
+	return obj
+}
+
+func TestGetHealthCheckFunc(t *testing.T) {
+	for _, kind := range []string{GatewayKind, HTTPRouteKind, GRPCRouteKind} {
+		assert.NotNil(t, GetHealthCheckFunc(schema.GroupVersionKind{Group: GatewayAPIGroup, Version: "v1", Kind: kind}), kind)
+	}
+	assert.Nil(t, GetHealthCheckFunc(schema.GroupVersionKind{Group: GatewayAPIGroup, Version: "v1", Kind: "GatewayClass"}))
+	assert.NotNil(t, GetHealthCheckFunc(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}))
+	assert.Nil(t, GetHealthCheckFunc(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}))
+}
+
+func TestGetGatewayHealth(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		status   health.HealthStatusCode
+		message  string
+	}{{
+		name: "Programmed",
+		manifest: `metadata: {name: gateway, generation: 2}
+status:
+  conditions:
+  - {type: Accepted, status: "True", reason: Accepted, observedGeneration: 2}
+  - {type: Programmed, status: "True", reason: Programmed, message: Gateway is programmed, observedGeneration: 2}`,
+		status:  health.HealthStatusHealthy,
+		message: "Gateway is programmed",
+	}, {
+		name:     "NoStatus",
+		manifest: `metadata: {name: gateway}`,
+		status:   health.HealthStatusProgressing,
+		message:  "Waiting for the gateway to be programmed",
+	}, {
+		name: "Pending",
+		manifest: `metadata: {name: gateway}
+status:
+  conditions:
+  - {type: Programmed, status: "False", reason: Pending, message: Waiting for controller}`,
+		status:  health.HealthStatusProgressing,
+		message: "Waiting for controller",
+	}, {
+		name: "StaleGeneration",
+		manifest: `metadata: {name: gateway, generation: 3}
+status:
+  conditions:
+  - {type: Programmed, status: "True", reason: Programmed, observedGeneration: 2}`,
+		status:  health.HealthStatusProgressing,
+		message: "Waiting for the controller to observe the latest generation of the gateway",
+	}, {
+		name: "NotProgrammed",
+		manifest: `metadata: {name: gateway}
+status:
+  conditions:
+  - {type: Programmed, status: "False", reason: AddressNotAssigned, message: No address is available}`,
+		status:  health.HealthStatusDegraded,
+		message: "No address is available",
+	}, {
+		name: "NotAccepted",
+		manifest: `metadata: {name: gateway}
+status:
+  conditions:
+  - {type: Accepted, status: "False", reason: InvalidParameters, message: Invalid gateway class parameters}
+  - {type: Programmed, status: Unknown, reason: Pending}`,
+		status:  health.HealthStatusDegraded,
+		message: "Invalid gateway class parameters",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthStatus, err := getGatewayHealth(newGatewayAPIResource(t, GatewayKind, tt.manifest))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, healthStatus.Status)
+			assert.Equal(t, tt.message, healthStatus.Message)
+		})
+	}
+}
+
+func TestGetRouteHealth(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		status   health.HealthStatusCode
+		message  string
+	}{{
+		name: "AcceptedByAllParents",
+		manifest: `metadata: {name: route, generation: 1}
+spec:
+  parentRefs: [{name: internal}, {name: external}]
+status:
+  parents:
+  - parentRef: {name: internal}
+    conditions:
+    - {type: Accepted, status: "True", reason: Accepted, observedGeneration: 1}
+    - {type: ResolvedRefs, status: "True", reason: ResolvedRefs, observedGeneration: 1}
+  - parentRef: {name: external}
+    conditions:
+    - {type: Accepted, status: "True", reason: Accepted, observedGeneration: 1}`,
+		status:  health.HealthStatusHealthy,
+		message: "Route is accepted by all its parents",
+	}, {
+		name: "NoParents",
+		manifest: `metadata: {name: route}
+spec:
+  parentRefs: [{name: internal}]`,
+		status:  health.HealthStatusProgressing,
+		message: "Waiting for the route to be accepted by its parents",
+	}, {
+		name: "MissingParent",
+		manifest: `metadata: {name: route}
+spec:
+  parentRefs: [{name: internal}, {name: external}]
+status:
+  parents:
+  - parentRef: {name: internal}
+    conditions:
+    - {type: Accepted, status: "True", reason: Accepted}`,
+		status:  health.HealthStatusProgressing,
+		message: "Waiting for the route to be accepted by its parents",
+	}, {
+		name: "StaleGeneration",
+		manifest: `metadata: {name: route, generation: 2}
+status:
+  parents:
+  - parentRef: {name: internal, namespace: gateways, sectionName: https}
+    conditions:
+    - {type: Accepted, status: "True", reason: Accepted, observedGeneration: 1}`,
+		status:  health.HealthStatusProgressing,
+		message: "Waiting for parent gateways/internal/https to accept the route",
+	}, {
+		name: "NotAccepted",
+		manifest: `metadata: {name: route}
+status:
+  parents:
+  - parentRef: {name: internal}
+    conditions:
+    - {type: Accepted, status: "True", reason: Accepted}
+  - parentRef: {name: external}
+    conditions:
+    - {type: Accepted, status: "False", reason: NotAllowedByListeners, message: No listener allows this route}`,
+		status:  health.HealthStatusDegraded,
+		message: "Route is not accepted by parent external: No listener allows this route",
+	}, {
+		name: "UnresolvedRefs",
+		manifest: `metadata: {name: route}
+status:
+  parents:
+  - parentRef: {name: internal}
+    conditions:
+    - {type: Accepted, status: "True", reason: Accepted}
+    - {type: ResolvedRefs, status: "False", reason: BackendNotFound, message: Service guestbook not found}`,
+		status:  health.HealthStatusDegraded,
+		message: "Route references of parent internal are not resolved: Service guestbook not found",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			healthStatus, err := getRouteHealth(newGatewayAPIResource(t, HTTPRouteKind, tt.manifest))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, healthStatus.Status)
+			assert.Equal(t, tt.message, healthStatus.Message)
+		})
+	}
+}
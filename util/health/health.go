@@ -0,0 +1,200 @@
+package health
+
+import (
+	"fmt"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/argoproj/argo-cd/v2/common"
+)
+
+// unknownResourceHealthStatuses are the health statuses which may be assumed for resources without a health check
+var unknownResourceHealthStatuses = []health.HealthStatusCode{
+	health.HealthStatusHealthy,
+	health.HealthStatusProgressing,
+	health.HealthStatusUnknown,
+}
+
+// overridableHealthStatuses are the health statuses which may be set by the health-override annotation of a resource
+var overridableHealthStatuses = []health.HealthStatusCode{
+	health.HealthStatusHealthy,
+	health.HealthStatusProgressing,
+	health.HealthStatusDegraded,
+	health.HealthStatusSuspended,
+}
+
+// ParseDefaultHealthForUnknownResources parses the health status assumed for resources without a built-in or custom
+// health check. An empty value means that such resources have no health status and do not affect the health of their
+// application.
+func ParseDefaultHealthForUnknownResources(value string) (health.HealthStatusCode, error) {
+	if value == "" {
+		return "", nil
+	}
+	for _, status := range unknownResourceHealthStatuses {
+		if string(status) == value {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("invalid health status %q for resources without a health check: must be one of %v", value, unknownResourceHealthStatuses)
+}
+
+// healthCheckPattern is the health check of the resources of kinds which are not known in advance, such as the kinds
+// defined by users with CRDs, which are matched by the structure of their objects
+type healthCheckPattern struct {
+	matches     func(obj *unstructured.Unstructured) bool
+	healthCheck func(obj *unstructured.Unstructured) (*health.HealthStatus, error)
+}
+
+// healthCheckPatterns are the health checks of the resources without a health check for their kind
+var healthCheckPatterns = []healthCheckPattern{
+	{matches: isCrossplaneComposite, healthCheck: getCrossplaneCompositeHealth},
+}
+
+// GetHealthCheckFunc returns the built-in health check of the given kind, including the health checks of the Gateway
+// API, Argo Rollouts, KEDA, Istio and Crossplane resources which are not built into gitops-engine
+func GetHealthCheckFunc(gvk schema.GroupVersionKind) func(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if healthCheck := getExtendedHealthCheckFunc(gvk); healthCheck != nil {
+		return healthCheck
+	}
+	return health.GetHealthCheckFunc(gvk)
+}
+
+// getExtendedHealthCheckFunc returns the health check of the given kind which is not built into gitops-engine, or nil
+// if there is none
+func getExtendedHealthCheckFunc(gvk schema.GroupVersionKind) func(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if healthCheck := getWorkloadHealthCheckFunc(gvk); healthCheck != nil {
+		return healthCheck
+	}
+	if healthCheck := getGatewayAPIHealthCheckFunc(gvk); healthCheck != nil {
+		return healthCheck
+	}
+	if healthCheck := getArgoRolloutsHealthCheckFunc(gvk); healthCheck != nil {
+		return healthCheck
+	}
+	if healthCheck := getKEDAHealthCheckFunc(gvk); healthCheck != nil {
+		return healthCheck
+	}
+	if healthCheck := getIstioHealthCheckFunc(gvk); healthCheck != nil {
+		return healthCheck
+	}
+	return getCrossplaneHealthCheckFunc(gvk)
+}
+
+// GetResourceHealthCheckFunc returns the built-in health check of the given resource like GetHealthCheckFunc, or else
+// the health check matching the structure of the resource, such as the health check of Crossplane composite resources
+func GetResourceHealthCheckFunc(obj *unstructured.Unstructured) func(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if healthCheck := GetHealthCheckFunc(obj.GroupVersionKind()); healthCheck != nil {
+		return healthCheck
+	}
+	return getPatternHealthCheckFunc(obj)
+}
+
+// getResourceExtendedHealthCheckFunc returns the health check of the given resource which is not built into
+// gitops-engine, matched by its kind or else by its structure, or nil if there is none
+func getResourceExtendedHealthCheckFunc(obj *unstructured.Unstructured) func(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if healthCheck := getExtendedHealthCheckFunc(obj.GroupVersionKind()); healthCheck != nil {
+		return healthCheck
+	}
+	return getPatternHealthCheckFunc(obj)
+}
+
+// getPatternHealthCheckFunc returns the health check of the first pattern matching the resource, or nil if there is
+// none
+func getPatternHealthCheckFunc(obj *unstructured.Unstructured) func(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	for _, pattern := range healthCheckPatterns {
+		if pattern.matches(obj) {
+			return pattern.healthCheck
+		}
+	}
+	return nil
+}
+
+// GetResourceHealth returns the health of the resource like health.GetResourceHealth, including the health of the
+// Gateway API, Argo Rollouts, KEDA, Istio and Crossplane resources and of explicitly paused workloads, but assumes the given default health for resources without a built-in or custom health check.
+// Nothing is assumed if the default health is empty.
+func GetResourceHealth(obj *unstructured.Unstructured, healthOverride health.HealthOverride, defaultHealth health.HealthStatusCode) (*health.HealthStatus, error) {
+	healthStatus, err := health.GetResourceHealth(obj, &pausedHealthOverride{healthOverride: healthOverride})
+	if err != nil || healthStatus != nil {
+		return healthStatus, err
+	}
+	if healthCheck := getResourceExtendedHealthCheckFunc(obj); healthCheck != nil {
+		return healthCheck(obj)
+	}
+	if defaultHealth == "" {
+		return nil, nil
+	}
+	return &health.HealthStatus{
+		Status:  defaultHealth,
+		Message: "No health check is registered for this resource type",
+	}, nil
+}
+
+// extendedHealthOverride is a health override which falls back to the health checks of this package for resources
+// the wrapped health override has no health check for
+type extendedHealthOverride struct {
+	healthOverride health.HealthOverride
+}
+
+// NewExtendedHealthOverride returns a health override which assesses the health of the Gateway API, Argo Rollouts,
+// KEDA, Istio and Crossplane resources without a custom or predefined health check, so that sync waves and hooks wait on the same health as
+// the application
+func NewExtendedHealthOverride(healthOverride health.HealthOverride) health.HealthOverride {
+	return &extendedHealthOverride{healthOverride: healthOverride}
+}
+
+func (o *extendedHealthOverride) GetResourceHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if o.healthOverride != nil {
+		healthStatus, err := o.healthOverride.GetResourceHealth(obj)
+		if err != nil || healthStatus != nil {
+			return healthStatus, err
+		}
+	}
+	if healthCheck := getResourceExtendedHealthCheckFunc(obj); healthCheck != nil {
+		return healthCheck(obj)
+	}
+	return nil, nil
+}
+
+// annotationHealthOverride is a health override which returns the health set by the health-override annotation of
+// a resource, and delegates to the wrapped health override for resources without the annotation
+type annotationHealthOverride struct {
+	healthOverride health.HealthOverride
+}
+
+// NewAnnotationHealthOverride returns a health override which gives the health set by the
+// argocd.argoproj.io/health-override annotation of a resource precedence over the given health override and the
+// built-in health checks
+func NewAnnotationHealthOverride(healthOverride health.HealthOverride) health.HealthOverride {
+	return &annotationHealthOverride{healthOverride: healthOverride}
+}
+
+func (o *annotationHealthOverride) GetResourceHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if value, ok := obj.GetAnnotations()[common.AnnotationHealthOverride]; ok {
+		logCtx := log.WithFields(log.Fields{"kind": obj.GetKind(), "namespace": obj.GetNamespace(), "name": obj.GetName()})
+		if status, err := parseHealthOverride(value); err != nil {
+			logCtx.Warnf("Ignoring %s annotation: %v", common.AnnotationHealthOverride, err)
+		} else {
+			logCtx.Warnf("Health of resource is overridden to %s by the %s annotation", status, common.AnnotationHealthOverride)
+			return &health.HealthStatus{
+				Status:  status,
+				Message: fmt.Sprintf("Health is overridden by the %s annotation", common.AnnotationHealthOverride),
+			}, nil
+		}
+	}
+	if o.healthOverride == nil {
+		return nil, nil
+	}
+	return o.healthOverride.GetResourceHealth(obj)
+}
+
+func parseHealthOverride(value string) (health.HealthStatusCode, error) {
+	for _, status := range overridableHealthStatuses {
+		if string(status) == value {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("invalid health status %q: must be one of %v", value, overridableHealthStatuses)
+}
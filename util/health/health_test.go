@@ -0,0 +1,186 @@
+package health
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	"github.com/argoproj/argo-cd/v2/common"
+)
+
+func TestParseDefaultHealthForUnknownResources(t *testing.T) {
+	for _, value := range []string{"", "Healthy", "Progressing", "Unknown"} {
+		status, err := ParseDefaultHealthForUnknownResources(value)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusCode(value), status)
+	}
+	for _, value := range []string{"Degraded", "Missing", "healthy"} {
+		_, err := ParseDefaultHealthForUnknownResources(value)
+		assert.Error(t, err)
+	}
+}
+
+func TestGetResourceHealth(t *testing.T) {
+	cm := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "my-config"},
+	}}
+	pod := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Pod",
+		"metadata":   map[string]interface{}{"name": "my-pod"},
+		"status":     map[string]interface{}{"phase": "Succeeded"},
+	}}
+
+	t.Run("NoDefaultHealth", func(t *testing.T) {
+		healthStatus, err := GetResourceHealth(cm, nil, "")
+		require.NoError(t, err)
+		assert.Nil(t, healthStatus)
+	})
+
+	t.Run("DefaultHealth", func(t *testing.T) {
+		healthStatus, err := GetResourceHealth(cm, nil, health.HealthStatusProgressing)
+		require.NoError(t, err)
+		require.NotNil(t, healthStatus)
+		assert.Equal(t, health.HealthStatusProgressing, healthStatus.Status)
+	})
+
+	t.Run("GatewayAPIHealthCheck", func(t *testing.T) {
+		route := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "GRPCRoute",
+			"metadata":   map[string]interface{}{"name": "my-route"},
+		}}
+		healthStatus, err := GetResourceHealth(route, nil, health.HealthStatusHealthy)
+		require.NoError(t, err)
+		require.NotNil(t, healthStatus)
+		assert.Equal(t, health.HealthStatusProgressing, healthStatus.Status)
+	})
+
+	t.Run("BuiltInHealthCheck", func(t *testing.T) {
+		healthStatus, err := GetResourceHealth(pod, nil, health.HealthStatusUnknown)
+		require.NoError(t, err)
+		require.NotNil(t, healthStatus)
+		assert.Equal(t, health.HealthStatusHealthy, healthStatus.Status)
+	})
+}
+
+type fakeHealthOverride struct {
+	status health.HealthStatusCode
+}
+
+func (o fakeHealthOverride) GetResourceHealth(_ *unstructured.Unstructured) (*health.HealthStatus, error) {
+	return &health.HealthStatus{Status: o.status}, nil
+}
+
+func TestAnnotationHealthOverride(t *testing.T) {
+	newPod := func(annotation string) *unstructured.Unstructured {
+		pod := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]interface{}{"name": "my-pod"},
+			"status":     map[string]interface{}{"phase": "Failed"},
+		}}
+		if annotation != "" {
+			pod.SetAnnotations(map[string]string{common.AnnotationHealthOverride: annotation})
+		}
+		return pod
+	}
+
+	for _, status := range []health.HealthStatusCode{health.HealthStatusHealthy, health.HealthStatusProgressing, health.HealthStatusDegraded, health.HealthStatusSuspended} {
+		t.Run(string(status), func(t *testing.T) {
+			healthStatus, err := health.GetResourceHealth(newPod(string(status)), NewAnnotationHealthOverride(fakeHealthOverride{status: health.HealthStatusUnknown}))
+			require.NoError(t, err)
+			require.NotNil(t, healthStatus)
+			assert.Equal(t, status, healthStatus.Status)
+		})
+	}
+
+	t.Run("InvalidValue", func(t *testing.T) {
+		healthStatus, err := health.GetResourceHealth(newPod("Missing"), NewAnnotationHealthOverride(nil))
+		require.NoError(t, err)
+		require.NotNil(t, healthStatus)
+		assert.Equal(t, health.HealthStatusDegraded, healthStatus.Status)
+	})
+
+	t.Run("NoAnnotation", func(t *testing.T) {
+		healthStatus, err := health.GetResourceHealth(newPod(""), NewAnnotationHealthOverride(fakeHealthOverride{status: health.HealthStatusSuspended}))
+		require.NoError(t, err)
+		require.NotNil(t, healthStatus)
+		assert.Equal(t, health.HealthStatusSuspended, healthStatus.Status)
+	})
+
+	t.Run("BuiltInHealthCheck", func(t *testing.T) {
+		healthStatus, err := health.GetResourceHealth(newPod(""), NewAnnotationHealthOverride(nil))
+		require.NoError(t, err)
+		require.NotNil(t, healthStatus)
+		assert.Equal(t, health.HealthStatusDegraded, healthStatus.Status)
+	})
+}
+
+func TestExtendedHealthOverride(t *testing.T) {
+	scaledJob := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "keda.sh/v1alpha1",
+		"kind":       "ScaledJob",
+		"metadata":   map[string]interface{}{"name": "my-job"},
+	}}
+
+	t.Run("ExtendedHealthCheck", func(t *testing.T) {
+		healthStatus, err := health.GetResourceHealth(scaledJob, NewExtendedHealthOverride(nil))
+		require.NoError(t, err)
+		require.NotNil(t, healthStatus)
+		assert.Equal(t, health.HealthStatusProgressing, healthStatus.Status)
+	})
+
+	t.Run("HealthOverride", func(t *testing.T) {
+		healthStatus, err := health.GetResourceHealth(scaledJob, NewExtendedHealthOverride(fakeHealthOverride{status: health.HealthStatusSuspended}))
+		require.NoError(t, err)
+		require.NotNil(t, healthStatus)
+		assert.Equal(t, health.HealthStatusSuspended, healthStatus.Status)
+	})
+
+	t.Run("NoHealthCheck", func(t *testing.T) {
+		cm := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "my-config"},
+		}}
+		healthStatus, err := health.GetResourceHealth(cm, NewExtendedHealthOverride(nil))
+		require.NoError(t, err)
+		assert.Nil(t, healthStatus)
+	})
+}
+
+// TestExtendedHealthChecksMatchLuaHealthChecks verifies that the health checks of this package give the same health as
+// the predefined Lua health checks of the same kinds, which take precedence over them
+func TestExtendedHealthChecksMatchLuaHealthChecks(t *testing.T) {
+	for _, dir := range []string{"argoproj.io/Rollout", "argoproj.io/AnalysisRun", "keda.sh/ScaledObject"} {
+		dir = filepath.Join("../../resource_customizations", dir)
+		data, err := os.ReadFile(filepath.Join(dir, "health_test.yaml"))
+		require.NoError(t, err)
+		var luaTests struct {
+			Tests []struct {
+				InputPath    string              `json:"inputPath"`
+				HealthStatus health.HealthStatus `json:"healthStatus"`
+			} `json:"tests"`
+		}
+		require.NoError(t, yaml.Unmarshal(data, &luaTests))
+		for _, tt := range luaTests.Tests {
+			t.Run(tt.InputPath, func(t *testing.T) {
+				obj := loadTestResource(t, filepath.Join(dir, tt.InputPath))
+				healthCheck := getExtendedHealthCheckFunc(obj.GroupVersionKind())
+				require.NotNil(t, healthCheck)
+				healthStatus, err := healthCheck(obj)
+				require.NoError(t, err)
+				assert.Equal(t, &tt.HealthStatus, healthStatus)
+			})
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package health
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// IstioNetworkingGroup is the API group of the Istio networking resources
+	IstioNetworkingGroup = "networking.istio.io"
+
+	VirtualServiceKind  = "VirtualService"
+	DestinationRuleKind = "DestinationRule"
+
+	// istioValidationLevelError is the level of the validation messages reporting an invalid configuration
+	istioValidationLevelError = "ERROR"
+	// istioValidationLevelErrorValue is the numeric value of the error level, as reported by older Istio versions
+	istioValidationLevelErrorValue = 3
+)
+
+// istioValidationMessage is a message of the analysis of an Istio resource
+type istioValidationMessage struct {
+	Level interface{} `json:"level,omitempty"`
+	Type  struct {
+		Code string `json:"code,omitempty"`
+		Name string `json:"name,omitempty"`
+	} `json:"type,omitempty"`
+}
+
+func (m istioValidationMessage) isError() bool {
+	switch level := m.Level.(type) {
+	case string:
+		return level == istioValidationLevelError
+	case int64:
+		return level == istioValidationLevelErrorValue
+	case float64:
+		return level == istioValidationLevelErrorValue
+	}
+	return false
+}
+
+func (m istioValidationMessage) String() string {
+	if m.Type.Name == "" {
+		return m.Type.Code
+	}
+	return fmt.Sprintf("%s (%s)", m.Type.Code, m.Type.Name)
+}
+
+// getIstioHealthCheckFunc returns the health check of the given Istio kind, or nil if the kind is not an Istio resource
+// with a health check. All the served versions of the networking API share the same status.
+func getIstioHealthCheckFunc(gvk schema.GroupVersionKind) func(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if gvk.Group != IstioNetworkingGroup {
+		return nil
+	}
+	switch gvk.Kind {
+	case VirtualServiceKind, DestinationRuleKind:
+		return getIstioConfigHealth
+	}
+	return nil
+}
+
+// getIstioObservedGeneration returns the generation observed by Istio, which is serialized as a string by the Istio
+// versions using the protobuf JSON encoding. It returns false if the status does not report it.
+func getIstioObservedGeneration(obj *unstructured.Unstructured) (int64, bool, error) {
+	value, found, err := unstructured.NestedFieldNoCopy(obj.Object, "status", "observedGeneration")
+	if err != nil || !found {
+		return 0, false, err
+	}
+	switch generation := value.(type) {
+	case int64:
+		return generation, true, nil
+	case float64:
+		return int64(generation), true, nil
+	case string:
+		parsed, err := strconv.ParseInt(generation, 10, 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("invalid observed generation %q of %s %s: %w", generation, obj.GetKind(), obj.GetName(), err)
+		}
+		return parsed, true, nil
+	}
+	return 0, false, fmt.Errorf("invalid observed generation %v of %s %s", value, obj.GetKind(), obj.GetName())
+}
+
+// getIstioConfigHealth returns the health of a VirtualService or DestinationRule. Istio reports neither readiness nor
+// standard conditions for them, so it is healthy once Istio observed its current generation without validation errors.
+// Its health is unknown if Istio does not report its status, which is the case of older Istio versions or when the
+// status of the configuration is disabled.
+func getIstioConfigHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	observedGeneration, found, err := getIstioObservedGeneration(obj)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return &health.HealthStatus{Status: health.HealthStatusUnknown, Message: fmt.Sprintf("Status of the %s is not reported by Istio", obj.GetKind())}, nil
+	}
+	if observedGeneration < obj.GetGeneration() {
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: fmt.Sprintf("Waiting for Istio to observe the latest generation of the %s", obj.GetKind())}, nil
+	}
+	var status struct {
+		ValidationMessages []istioValidationMessage `json:"validationMessages,omitempty"`
+	}
+	if err := convertStatus(obj, &status); err != nil {
+		return nil, err
+	}
+	var errors []string
+	for _, message := range status.ValidationMessages {
+		if message.isError() {
+			errors = append(errors, message.String())
+		}
+	}
+	if len(errors) > 0 {
+		return &health.HealthStatus{Status: health.HealthStatusDegraded, Message: "Validation failed: " + strings.Join(errors, ", ")}, nil
+	}
+	return &health.HealthStatus{Status: health.HealthStatusHealthy, Message: fmt.Sprintf("%s is observed by Istio", obj.GetKind())}, nil
+}
@@ -0,0 +1,66 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGetHealthCheckFunc_Istio(t *testing.T) {
+	for _, version := range []string{"v1alpha3", "v1beta1", "v1"} {
+		for _, kind := range []string{VirtualServiceKind, DestinationRuleKind} {
+			assert.NotNil(t, GetHealthCheckFunc(schema.GroupVersionKind{Group: IstioNetworkingGroup, Version: version, Kind: kind}), kind)
+		}
+	}
+	assert.Nil(t, GetHealthCheckFunc(schema.GroupVersionKind{Group: IstioNetworkingGroup, Version: "v1", Kind: "Gateway"}))
+}
+
+func TestGetIstioConfigHealth(t *testing.T) {
+	t.Run("Istio 1.17", func(t *testing.T) {
+		assertHealth(t, []healthTestCase{
+			{"testdata/istio/1.17/virtualservice_healthy.yaml", health.HealthStatusHealthy, "VirtualService is observed by Istio"},
+			{"testdata/istio/1.17/virtualservice_degraded.yaml", health.HealthStatusDegraded, "Validation failed: IST0101 (ReferencedResourceNotFound), IST0101 (ReferencedResourceNotFound)"},
+			{"testdata/istio/1.17/destinationrule_progressing.yaml", health.HealthStatusProgressing, "Waiting for Istio to observe the latest generation of the DestinationRule"},
+			{"testdata/istio/1.17/destinationrule_noStatus.yaml", health.HealthStatusUnknown, "Status of the DestinationRule is not reported by Istio"},
+		})
+	})
+	t.Run("Istio 1.19", func(t *testing.T) {
+		assertHealth(t, []healthTestCase{
+			{"testdata/istio/1.19/virtualservice_healthy_warning.yaml", health.HealthStatusHealthy, "VirtualService is observed by Istio"},
+			{"testdata/istio/1.19/destinationrule_healthy.yaml", health.HealthStatusHealthy, "DestinationRule is observed by Istio"},
+			{"testdata/istio/1.19/destinationrule_degraded.yaml", health.HealthStatusDegraded, "Validation failed: IST0157 (ConflictingTrafficPolicy)"},
+		})
+	})
+	t.Run("Numeric status fields", func(t *testing.T) {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "networking.istio.io/v1alpha3",
+			"kind":       VirtualServiceKind,
+			"metadata":   map[string]interface{}{"name": "reviews", "generation": int64(1)},
+			"status": map[string]interface{}{
+				"observedGeneration": int64(1),
+				"validationMessages": []interface{}{map[string]interface{}{
+					"level": int64(3),
+					"type":  map[string]interface{}{"code": "IST0101"},
+				}},
+			},
+		}}
+		healthStatus, err := getIstioConfigHealth(obj)
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusDegraded, healthStatus.Status)
+		assert.Equal(t, "Validation failed: IST0101", healthStatus.Message)
+	})
+	t.Run("Invalid observed generation", func(t *testing.T) {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "networking.istio.io/v1",
+			"kind":       DestinationRuleKind,
+			"metadata":   map[string]interface{}{"name": "reviews"},
+			"status":     map[string]interface{}{"observedGeneration": "latest"},
+		}}
+		_, err := getIstioConfigHealth(obj)
+		require.ErrorContains(t, err, `invalid observed generation "latest"`)
+	})
+}
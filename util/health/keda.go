@@ -0,0 +1,70 @@
+package health
+
+import (
+	"fmt"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	apimeta "k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// KEDAGroup is the API group of the KEDA resources
+	KEDAGroup = "keda.sh"
+
+	ScaledObjectKind = "ScaledObject"
+	ScaledJobKind    = "ScaledJob"
+
+	kedaConditionReady    = "Ready"
+	kedaConditionActive   = "Active"
+	kedaConditionFallback = "Fallback"
+	kedaConditionPaused   = "Paused"
+)
+
+// kedaStatus is the part of the status of a ScaledObject or ScaledJob which is used to assess its health
+type kedaStatus struct {
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// getKEDAHealthCheckFunc returns the health check of the given KEDA kind, or nil if the kind is not a KEDA resource
+// with a health check
+func getKEDAHealthCheckFunc(gvk schema.GroupVersionKind) func(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if gvk.Group != KEDAGroup {
+		return nil
+	}
+	switch gvk.Kind {
+	case ScaledObjectKind, ScaledJobKind:
+		return getKEDAScalingHealth
+	}
+	return nil
+}
+
+// getKEDAScalingHealth returns the health of a ScaledObject or ScaledJob. It is degraded when it is not ready or its
+// scaling fell back to the fallback replicas, suspended when its scaling is paused and healthy once it is ready.
+func getKEDAScalingHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	var status kedaStatus
+	if err := convertStatus(obj, &status); err != nil {
+		return nil, err
+	}
+	ready := apimeta.FindStatusCondition(status.Conditions, kedaConditionReady)
+	if ready != nil && ready.Status == metav1.ConditionFalse {
+		return &health.HealthStatus{Status: health.HealthStatusDegraded, Message: ready.Message}, nil
+	}
+	if apimeta.IsStatusConditionTrue(status.Conditions, kedaConditionFallback) {
+		fallback := apimeta.FindStatusCondition(status.Conditions, kedaConditionFallback)
+		return &health.HealthStatus{Status: health.HealthStatusDegraded, Message: fallback.Message}, nil
+	}
+	if ready == nil || ready.Status != metav1.ConditionTrue {
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: fmt.Sprintf("Waiting for the %s to be ready", obj.GetKind())}, nil
+	}
+	if apimeta.IsStatusConditionTrue(status.Conditions, kedaConditionPaused) {
+		paused := apimeta.FindStatusCondition(status.Conditions, kedaConditionPaused)
+		return &health.HealthStatus{Status: health.HealthStatusSuspended, Message: paused.Message}, nil
+	}
+	if active := apimeta.FindStatusCondition(status.Conditions, kedaConditionActive); active != nil && active.Status == metav1.ConditionTrue {
+		return &health.HealthStatus{Status: health.HealthStatusHealthy, Message: active.Message}, nil
+	}
+	return &health.HealthStatus{Status: health.HealthStatusHealthy, Message: ready.Message}, nil
+}
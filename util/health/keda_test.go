@@ -0,0 +1,41 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// the ScaledObject fixtures are shared with the tests of the Lua health script
+const scaledObjectTestData = "../../resource_customizations/keda.sh/ScaledObject/testdata/"
+
+func TestGetHealthCheckFunc_KEDA(t *testing.T) {
+	for _, kind := range []string{ScaledObjectKind, ScaledJobKind} {
+		assert.NotNil(t, GetHealthCheckFunc(schema.GroupVersionKind{Group: KEDAGroup, Version: "v1alpha1", Kind: kind}), kind)
+	}
+	assert.Nil(t, GetHealthCheckFunc(schema.GroupVersionKind{Group: KEDAGroup, Version: "v1alpha1", Kind: "TriggerAuthentication"}))
+}
+
+func TestGetKEDAScalingHealth(t *testing.T) {
+	t.Run("ScaledObject", func(t *testing.T) {
+		assertHealth(t, []healthTestCase{
+			{scaledObjectTestData + "keda-healthy.yaml", health.HealthStatusHealthy, "ScaledObject is defined correctly and is ready for scaling"},
+			{scaledObjectTestData + "keda-healthy-active.yaml", health.HealthStatusHealthy, "Scaling is performed because triggers are active"},
+			{scaledObjectTestData + "keda-progressing.yaml", health.HealthStatusProgressing, "Waiting for the ScaledObject to be ready"},
+			{scaledObjectTestData + "keda-suspended.yaml", health.HealthStatusSuspended, "ScaledObject is paused"},
+			{scaledObjectTestData + "keda-degraded.yaml", health.HealthStatusDegraded, "ScaledObject doesn't have correct triggers specification"},
+			{scaledObjectTestData + "keda-degraded-1.yaml", health.HealthStatusDegraded, "ScaledObject doesn't have correct Idle/Min/Max Replica Counts specification"},
+			{scaledObjectTestData + "keda-degraded-fallback.yaml", health.HealthStatusDegraded, "At least one trigger is falling back on this scaled object"},
+		})
+	})
+	t.Run("ScaledJob", func(t *testing.T) {
+		assertHealth(t, []healthTestCase{
+			{"testdata/keda/scaledjob/healthy_ready.yaml", health.HealthStatusHealthy, "ScaledJob is defined correctly and is ready to scaling"},
+			{"testdata/keda/scaledjob/progressing_noStatus.yaml", health.HealthStatusProgressing, "Waiting for the ScaledJob to be ready"},
+			{"testdata/keda/scaledjob/suspended_paused.yaml", health.HealthStatusSuspended, "ScaledJob is paused"},
+			{"testdata/keda/scaledjob/degraded_invalidTriggers.yaml", health.HealthStatusDegraded, "error parsing rabbitmq metadata: no host setting given"},
+		})
+	})
+}
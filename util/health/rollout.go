@@ -0,0 +1,204 @@
+package health
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// ArgoRolloutsGroup is the API group of the Argo Rollouts resources
+	ArgoRolloutsGroup = "argoproj.io"
+
+	RolloutKind     = "Rollout"
+	AnalysisRunKind = "AnalysisRun"
+
+	rolloutPhasePaused                    = "Paused"
+	rolloutConditionInvalidSpec           = "InvalidSpec"
+	rolloutConditionProgressing           = "Progressing"
+	rolloutReasonAborted                  = "RolloutAborted"
+	rolloutReasonProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+	rolloutWorkloadGenerationAnnotation   = "rollout.argoproj.io/workload-generation"
+
+	analysisRunPhasePending      = "Pending"
+	analysisRunPhaseRunning      = "Running"
+	analysisRunPhaseSuccessful   = "Successful"
+	analysisRunPhaseFailed       = "Failed"
+	analysisRunPhaseError        = "Error"
+	analysisRunPhaseInconclusive = "Inconclusive"
+)
+
+// getArgoRolloutsHealthCheckFunc returns the health check of the given Argo Rollouts kind, or nil if the kind is not
+// an Argo Rollouts resource with a health check
+func getArgoRolloutsHealthCheckFunc(gvk schema.GroupVersionKind) func(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if gvk.Group != ArgoRolloutsGroup {
+		return nil
+	}
+	switch gvk.Kind {
+	case RolloutKind:
+		return getRolloutHealth
+	case AnalysisRunKind:
+		return getAnalysisRunHealth
+	}
+	return nil
+}
+
+// getRolloutHealth returns the health of a Rollout. Rollouts of Argo Rollouts v1.0 and later report their phase, which
+// is surfaced as is, except for the Paused phase which is mapped to Suspended. The health of older rollouts is assessed
+// from their conditions, replicas and the pod hash of their strategy.
+func getRolloutHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if !isRolloutGenerationObserved(obj) || !isRolloutWorkloadGenerationObserved(obj) {
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: "Waiting for rollout spec update to be observed"}, nil
+	}
+
+	if phase, ok, _ := unstructured.NestedString(obj.Object, "status", "phase"); ok {
+		message, _, _ := unstructured.NestedString(obj.Object, "status", "message")
+		status := health.HealthStatusCode(phase)
+		if phase == rolloutPhasePaused {
+			status = health.HealthStatusSuspended
+		}
+		return &health.HealthStatus{Status: status, Message: message}, nil
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		reason, _, _ := unstructured.NestedString(condition, "reason")
+		if conditionType == rolloutConditionInvalidSpec || conditionType == rolloutConditionProgressing && (reason == rolloutReasonAborted || reason == rolloutReasonProgressDeadlineExceeded) {
+			message, _, _ := unstructured.NestedString(condition, "message")
+			return &health.HealthStatus{Status: health.HealthStatusDegraded, Message: message}, nil
+		}
+	}
+
+	pauseConditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "pauseConditions")
+	if paused, _, _ := unstructured.NestedBool(obj.Object, "spec", "paused"); paused || len(pauseConditions) > 0 {
+		return &health.HealthStatus{Status: health.HealthStatusSuspended, Message: "Rollout is paused"}, nil
+	}
+
+	currentPodHash, _, _ := unstructured.NestedString(obj.Object, "status", "currentPodHash")
+	if currentPodHash == "" {
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: "Waiting for rollout to finish: status has not been reconciled."}, nil
+	}
+
+	desiredReplicas := nestedInt64OrDefault(obj, 1, "spec", "replicas")
+	replicas := nestedInt64OrDefault(obj, 0, "status", "replicas")
+	updatedReplicas := nestedInt64OrDefault(obj, 0, "status", "updatedReplicas")
+	availableReplicas := nestedInt64OrDefault(obj, 0, "status", "availableReplicas")
+	if updatedReplicas < desiredReplicas {
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: "Waiting for roll out to finish: More replicas need to be updated"}, nil
+	}
+	if availableReplicas < updatedReplicas {
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: "Waiting for roll out to finish: updated replicas are still becoming available"}, nil
+	}
+
+	// Argo Rollouts v0.8 deprecated status.canary.stableRS in favor of status.stableRS
+	stableRS, ok, _ := unstructured.NestedString(obj.Object, "status", "stableRS")
+	if !ok {
+		stableRS, _, _ = unstructured.NestedString(obj.Object, "status", "canary", "stableRS")
+	}
+	if _, ok, _ := unstructured.NestedMap(obj.Object, "spec", "strategy", "blueGreen"); ok {
+		activeSelector, _, _ := unstructured.NestedString(obj.Object, "status", "blueGreen", "activeSelector")
+		if activeSelector != currentPodHash {
+			return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: "active service cutover pending"}, nil
+		}
+		// blue-green rollouts older than v0.8 do not set the stable replica set
+		if stableRS != "" && stableRS != currentPodHash {
+			return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: "waiting for analysis to complete"}, nil
+		}
+	} else if _, ok, _ := unstructured.NestedMap(obj.Object, "spec", "strategy", "canary"); ok {
+		if replicas > updatedReplicas {
+			return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: "Waiting for roll out to finish: old replicas are pending termination"}, nil
+		}
+		if stableRS != currentPodHash {
+			return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: "Waiting for rollout to finish steps"}, nil
+		}
+	}
+	return &health.HealthStatus{Status: health.HealthStatusHealthy}, nil
+}
+
+// isRolloutGenerationObserved returns true if the spec of the rollout was observed by the controller. The observed
+// generation of rollouts older than v0.10 is a hash, so their spec is always considered observed.
+func isRolloutGenerationObserved(obj *unstructured.Unstructured) bool {
+	status, ok, _ := unstructured.NestedFieldNoCopy(obj.Object, "status")
+	if !ok || status == nil {
+		return false
+	}
+	observedGeneration, _, _ := unstructured.NestedFieldNoCopy(obj.Object, "status", "observedGeneration")
+	generation, ok := toInt64(observedGeneration)
+	if !ok || generation > obj.GetGeneration() {
+		return true
+	}
+	return generation == obj.GetGeneration()
+}
+
+// isRolloutWorkloadGenerationObserved returns true if the generation of the workload referenced by the rollout was
+// observed by the controller. Rollouts older than v1.1 or without a workload reference are always considered observed.
+func isRolloutWorkloadGenerationObserved(obj *unstructured.Unstructured) bool {
+	if _, ok, _ := unstructured.NestedFieldNoCopy(obj.Object, "spec", "workloadRef"); !ok || obj.GetAnnotations() == nil {
+		return true
+	}
+	workloadGeneration, hasWorkloadGeneration := toInt64(obj.GetAnnotations()[rolloutWorkloadGenerationAnnotation])
+	observedGeneration, _, _ := unstructured.NestedFieldNoCopy(obj.Object, "status", "workloadObservedGeneration")
+	observedWorkloadGeneration, hasObservedWorkloadGeneration := toInt64(observedGeneration)
+	return hasWorkloadGeneration == hasObservedWorkloadGeneration && workloadGeneration == observedWorkloadGeneration
+}
+
+// getAnalysisRunHealth returns the health of an AnalysisRun, which is healthy once its phase is Successful
+func getAnalysisRunHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+	if phase == "" {
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: "Waiting for analysis run to finish: status has not been reconciled."}, nil
+	}
+	message, hasMessage, _ := unstructured.NestedString(obj.Object, "status", "message")
+	messageOrDefault := func(defaultMessage string) string {
+		if hasMessage {
+			return message
+		}
+		return defaultMessage
+	}
+	switch phase {
+	case analysisRunPhasePending, analysisRunPhaseRunning:
+		return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: "Analysis run is running"}, nil
+	case analysisRunPhaseSuccessful:
+		return &health.HealthStatus{Status: health.HealthStatusHealthy, Message: messageOrDefault("Analysis run completed successfully")}, nil
+	case analysisRunPhaseFailed:
+		return &health.HealthStatus{Status: health.HealthStatusDegraded, Message: messageOrDefault("Analysis run failed")}, nil
+	case analysisRunPhaseError:
+		return &health.HealthStatus{Status: health.HealthStatusDegraded, Message: messageOrDefault("Analysis run had an error")}, nil
+	case analysisRunPhaseInconclusive:
+		return &health.HealthStatus{Status: health.HealthStatusUnknown, Message: messageOrDefault("Analysis run was inconclusive")}, nil
+	}
+	return &health.HealthStatus{Status: health.HealthStatusProgressing, Message: fmt.Sprintf("Waiting for analysis run to finish: unknown phase %q", phase)}, nil
+}
+
+// nestedInt64OrDefault returns the integer at the given path of the resource, or the default if it is not set
+func nestedInt64OrDefault(obj *unstructured.Unstructured, defaultValue int64, fields ...string) int64 {
+	value, _, _ := unstructured.NestedFieldNoCopy(obj.Object, fields...)
+	if i, ok := toInt64(value); ok {
+		return i
+	}
+	return defaultValue
+}
+
+// toInt64 converts a number of an unstructured resource, or a string holding a number, to an integer
+func toInt64(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case int:
+		return int64(v), true
+	case float64:
+		return int64(v), true
+	case string:
+		i, err := strconv.ParseInt(v, 10, 64)
+		return i, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,134 @@
+package health
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+// the Argo Rollouts fixtures are shared with the tests of the Lua health scripts
+const (
+	rolloutTestData     = "../../resource_customizations/argoproj.io/Rollout/testdata/"
+	analysisRunTestData = "../../resource_customizations/argoproj.io/AnalysisRun/testdata/"
+)
+
+func loadTestResource(t *testing.T, path string) *unstructured.Unstructured {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	obj := &unstructured.Unstructured{}
+	require.NoError(t, yaml.Unmarshal(data, obj))
+	return obj
+}
+
+type healthTestCase struct {
+	path    string
+	status  health.HealthStatusCode
+	message string
+}
+
+func assertHealth(t *testing.T, tests []healthTestCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(filepath.Base(tt.path), func(t *testing.T) {
+			obj := loadTestResource(t, tt.path)
+			healthCheck := GetResourceHealthCheckFunc(obj)
+			require.NotNil(t, healthCheck)
+			healthStatus, err := healthCheck(obj)
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, healthStatus.Status)
+			assert.Equal(t, tt.message, healthStatus.Message)
+		})
+	}
+}
+
+func TestGetHealthCheckFunc_ArgoRollouts(t *testing.T) {
+	for _, kind := range []string{RolloutKind, AnalysisRunKind} {
+		assert.NotNil(t, GetHealthCheckFunc(schema.GroupVersionKind{Group: ArgoRolloutsGroup, Version: "v1alpha1", Kind: kind}), kind)
+	}
+	assert.Nil(t, GetHealthCheckFunc(schema.GroupVersionKind{Group: ArgoRolloutsGroup, Version: "v1alpha1", Kind: "AnalysisTemplate"}))
+	// the health of workflows is built into gitops-engine
+	assert.NotNil(t, GetHealthCheckFunc(schema.GroupVersionKind{Group: ArgoRolloutsGroup, Version: "v1alpha1", Kind: "Workflow"}))
+}
+
+func TestGetRolloutHealth(t *testing.T) {
+	t.Run("Canary", func(t *testing.T) {
+		assertHealth(t, []healthTestCase{
+			{rolloutTestData + "canary/progressing_setWeightStep.yaml", health.HealthStatusProgressing, "Waiting for roll out to finish: More replicas need to be updated"},
+			{rolloutTestData + "canary/progressing_killingOldReplicas.yaml", health.HealthStatusProgressing, "Waiting for roll out to finish: old replicas are pending termination"},
+			{rolloutTestData + "canary/progressing_noSteps.yaml", health.HealthStatusProgressing, "Waiting for roll out to finish: updated replicas are still becoming available"},
+			{rolloutTestData + "canary/healthy_executedAllStepsPreV0.8.yaml", health.HealthStatusHealthy, ""},
+			{rolloutTestData + "canary/healthy_executedAllSteps.yaml", health.HealthStatusHealthy, ""},
+			{rolloutTestData + "canary/healthy_noSteps.yaml", health.HealthStatusHealthy, ""},
+			{rolloutTestData + "canary/healthy_emptyStepsList.yaml", health.HealthStatusHealthy, ""},
+		})
+	})
+
+	t.Run("BlueGreen", func(t *testing.T) {
+		assertHealth(t, []healthTestCase{
+			{rolloutTestData + "bluegreen/healthy_servingActiveService.yaml", health.HealthStatusHealthy, ""},
+			{rolloutTestData + "bluegreen/progressing_addingMoreReplicas.yaml", health.HealthStatusProgressing, "Waiting for roll out to finish: More replicas need to be updated"},
+			{rolloutTestData + "bluegreen/progressing_waitingUntilAvailable.yaml", health.HealthStatusProgressing, "Waiting for roll out to finish: updated replicas are still becoming available"},
+		})
+	})
+
+	t.Run("Paused", func(t *testing.T) {
+		assertHealth(t, []healthTestCase{
+			{rolloutTestData + "suspended_controllerPause.yaml", health.HealthStatusSuspended, "Rollout is paused"},
+			{rolloutTestData + "suspended_userPause.yaml", health.HealthStatusSuspended, "Rollout is paused"},
+			// the Paused phase of rollouts of v1.0 and later is mapped to Suspended
+			{rolloutTestData + "suspended_v1.0_pausedRollout.yaml", health.HealthStatusSuspended, "CanaryPauseStep"},
+		})
+	})
+
+	t.Run("Degraded", func(t *testing.T) {
+		assertHealth(t, []healthTestCase{
+			{rolloutTestData + "degraded_statusPhaseMessage.yaml", health.HealthStatusDegraded, "InvalidSpec"},
+			{rolloutTestData + "degraded_invalidSpec.yaml", health.HealthStatusDegraded, `The Rollout "basic" is invalid: spec.strategy.strategy: Required value: Rollout has missing field '.spec.strategy.canary or .spec.strategy.blueGreen'`},
+			{rolloutTestData + "degraded_rolloutTimeout.yaml", health.HealthStatusDegraded, `ReplicaSet "guestbook-bluegreen-helm-guestbook-6b8cf6f7db" has timed out progressing.`},
+			{rolloutTestData + "degraded_abortedRollout.yaml", health.HealthStatusDegraded, "Rollout is aborted"},
+		})
+	})
+
+	t.Run("Generation", func(t *testing.T) {
+		assertHealth(t, []healthTestCase{
+			{rolloutTestData + "newRolloutWithoutStatus.yaml", health.HealthStatusProgressing, "Waiting for rollout spec update to be observed"},
+			{rolloutTestData + "progressing_newGeneration.yaml", health.HealthStatusProgressing, "Waiting for rollout spec update to be observed"},
+			{rolloutTestData + "progressing_newWorkloadGeneration.yaml", health.HealthStatusProgressing, "Waiting for rollout spec update to be observed"},
+			{rolloutTestData + "healthy_legacy_v0.9_observedGeneration.yaml", health.HealthStatusHealthy, ""},
+			{rolloutTestData + "healthy_legacy_v0.9_observedGeneration_numeric.yaml", health.HealthStatusHealthy, ""},
+			{rolloutTestData + "healthy_legacy_v1.0_newWorkloadGeneration.yaml", health.HealthStatusHealthy, ""},
+			{rolloutTestData + "healthy_newWorkloadGeneration.yaml", health.HealthStatusHealthy, ""},
+		})
+	})
+}
+
+func TestGetAnalysisRunHealth(t *testing.T) {
+	assertHealth(t, []healthTestCase{
+		{analysisRunTestData + "pendingAnalysisRun.yaml", health.HealthStatusProgressing, "Analysis run is running"},
+		{analysisRunTestData + "noStatusAnalysisRun.yaml", health.HealthStatusProgressing, "Waiting for analysis run to finish: status has not been reconciled."},
+		{analysisRunTestData + "runningAnalysisRun.yaml", health.HealthStatusProgressing, "Analysis run is running"},
+		{analysisRunTestData + "successfulAnalysisRun.yaml", health.HealthStatusHealthy, "Analysis run completed successfully"},
+		{analysisRunTestData + "terminatedAnalysisRun.yaml", health.HealthStatusHealthy, "run terminated"},
+		{analysisRunTestData + "failedAnalysisRun.yaml", health.HealthStatusDegraded, "Analysis run failed"},
+		{analysisRunTestData + "failedAnalysisRunWithStatusMessage.yaml", health.HealthStatusDegraded, "Status Message: Assessed as Failed"},
+		{analysisRunTestData + "errorAnalysisRun.yaml", health.HealthStatusDegraded, "Analysis run had an error"},
+		{analysisRunTestData + "errorAnalysisRunWithStatusMessage.yaml", health.HealthStatusDegraded, "Status Message: Assessed as Error"},
+		{analysisRunTestData + "inconclusiveAnalysisRun.yaml", health.HealthStatusUnknown, "Analysis run was inconclusive"},
+		{analysisRunTestData + "inconclusiveAnalysisRunWithStatusMessage.yaml", health.HealthStatusUnknown, "Status Message: Assessed as Inconclusive"},
+	})
+}
+
+func TestGetResourceHealth_ArgoRollouts(t *testing.T) {
+	obj := loadTestResource(t, rolloutTestData+"canary/healthy_executedAllSteps.yaml")
+	healthStatus, err := GetResourceHealth(obj, nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, health.HealthStatusHealthy, healthStatus.Status)
+}
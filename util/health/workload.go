@@ -0,0 +1,148 @@
+package health
+
+import (
+	"fmt"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// CronJobKind is the kind of the batch/v1 CronJob resources
+const CronJobKind = "CronJob"
+
+// pausedHealthChecks are the health checks of the workloads which can be explicitly paused. They return a suspended
+// health while the workload is paused, and nil otherwise.
+var pausedHealthChecks = map[schema.GroupVersionKind]func(obj *unstructured.Unstructured) (*health.HealthStatus, error){
+	appsv1.SchemeGroupVersion.WithKind(kube.DeploymentKind):  getDeploymentPausedHealth,
+	appsv1.SchemeGroupVersion.WithKind(kube.StatefulSetKind): getStatefulSetPausedHealth,
+	appsv1.SchemeGroupVersion.WithKind(kube.DaemonSetKind):   getDaemonSetPausedHealth,
+	batchv1.SchemeGroupVersion.WithKind(CronJobKind):         getCronJobPausedHealth,
+}
+
+// getWorkloadHealthCheckFunc returns the health check of the given workload kind which reports the workload as
+// suspended while it is explicitly paused, and otherwise assesses it with the built-in health check of gitops-engine,
+// or nil if the kind cannot be paused
+func getWorkloadHealthCheckFunc(gvk schema.GroupVersionKind) func(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	pausedHealthCheck, ok := pausedHealthChecks[gvk]
+	if !ok {
+		return nil
+	}
+	return func(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+		healthStatus, err := pausedHealthCheck(obj)
+		if err != nil || healthStatus != nil {
+			return healthStatus, err
+		}
+		if healthCheck := health.GetHealthCheckFunc(gvk); healthCheck != nil {
+			return healthCheck(obj)
+		}
+		return &health.HealthStatus{Status: health.HealthStatusHealthy}, nil
+	}
+}
+
+// getPausedHealth returns the suspended health of the resource if it is a workload which is explicitly paused, and nil
+// otherwise
+func getPausedHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if pausedHealthCheck, ok := pausedHealthChecks[obj.GroupVersionKind()]; ok {
+		return pausedHealthCheck(obj)
+	}
+	return nil, nil
+}
+
+func convertWorkload(obj *unstructured.Unstructured, workload interface{}) error {
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, workload); err != nil {
+		return fmt.Errorf("failed to convert unstructured %s to typed: %w", obj.GetKind(), err)
+	}
+	return nil
+}
+
+// getDeploymentPausedHealth returns a suspended health if the rollout of the Deployment is paused with spec.paused
+func getDeploymentPausedHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	var deployment appsv1.Deployment
+	if err := convertWorkload(obj, &deployment); err != nil {
+		return nil, err
+	}
+	if !deployment.Spec.Paused {
+		return nil, nil
+	}
+	return &health.HealthStatus{Status: health.HealthStatusSuspended, Message: "Deployment is paused"}, nil
+}
+
+// getStatefulSetPausedHealth returns a suspended health if the StatefulSet has an update which its update strategy
+// holds back from all its pods, because its rolling update partition is not lower than its replicas or its pods are
+// only updated when they are deleted
+func getStatefulSetPausedHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	var sts appsv1.StatefulSet
+	if err := convertWorkload(obj, &sts); err != nil {
+		return nil, err
+	}
+	if sts.Status.ObservedGeneration < sts.Generation || sts.Status.UpdateRevision == "" || sts.Status.UpdateRevision == sts.Status.CurrentRevision {
+		return nil, nil
+	}
+	replicas := int32(1)
+	if sts.Spec.Replicas != nil {
+		replicas = *sts.Spec.Replicas
+	}
+	strategy := sts.Spec.UpdateStrategy
+	switch {
+	case strategy.Type == appsv1.OnDeleteStatefulSetStrategyType:
+		return &health.HealthStatus{
+			Status:  health.HealthStatusSuspended,
+			Message: fmt.Sprintf("StatefulSet rollout is paused: %d out of %d pods have been updated, other pods are updated when they are deleted", sts.Status.UpdatedReplicas, replicas),
+		}, nil
+	case strategy.RollingUpdate != nil && strategy.RollingUpdate.Partition != nil && *strategy.RollingUpdate.Partition >= replicas:
+		return &health.HealthStatus{
+			Status:  health.HealthStatusSuspended,
+			Message: fmt.Sprintf("StatefulSet rollout is paused by partition %d: %d out of %d pods have been updated", *strategy.RollingUpdate.Partition, sts.Status.UpdatedReplicas, replicas),
+		}, nil
+	}
+	return nil, nil
+}
+
+// getDaemonSetPausedHealth returns a suspended health if the DaemonSet has an update which is held back from some of
+// its pods because they are only updated when they are deleted
+func getDaemonSetPausedHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	var daemon appsv1.DaemonSet
+	if err := convertWorkload(obj, &daemon); err != nil {
+		return nil, err
+	}
+	if daemon.Status.ObservedGeneration < daemon.Generation || daemon.Spec.UpdateStrategy.Type != appsv1.OnDeleteDaemonSetStrategyType || daemon.Status.UpdatedNumberScheduled >= daemon.Status.DesiredNumberScheduled {
+		return nil, nil
+	}
+	return &health.HealthStatus{
+		Status:  health.HealthStatusSuspended,
+		Message: fmt.Sprintf("DaemonSet rollout is paused: %d out of %d pods have been updated, other pods are updated when they are deleted", daemon.Status.UpdatedNumberScheduled, daemon.Status.DesiredNumberScheduled),
+	}, nil
+}
+
+// getCronJobPausedHealth returns a suspended health if the scheduling of the CronJob is suspended with spec.suspend
+func getCronJobPausedHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	var cronJob batchv1.CronJob
+	if err := convertWorkload(obj, &cronJob); err != nil {
+		return nil, err
+	}
+	if cronJob.Spec.Suspend == nil || !*cronJob.Spec.Suspend {
+		return nil, nil
+	}
+	return &health.HealthStatus{Status: health.HealthStatusSuspended, Message: "CronJob is suspended"}, nil
+}
+
+// pausedHealthOverride is a health override which reports explicitly paused workloads as suspended before their
+// built-in health checks, and delegates to the wrapped health override first
+type pausedHealthOverride struct {
+	healthOverride health.HealthOverride
+}
+
+func (o *pausedHealthOverride) GetResourceHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if o.healthOverride != nil {
+		healthStatus, err := o.healthOverride.GetResourceHealth(obj)
+		if err != nil || healthStatus != nil {
+			return healthStatus, err
+		}
+	}
+	return getPausedHealth(obj)
+}
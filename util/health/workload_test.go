@@ -0,0 +1,78 @@
+package health
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestGetHealthCheckFunc_Workloads(t *testing.T) {
+	for _, gvk := range []schema.GroupVersionKind{
+		{Group: "apps", Version: "v1", Kind: "Deployment"},
+		{Group: "apps", Version: "v1", Kind: "StatefulSet"},
+		{Group: "apps", Version: "v1", Kind: "DaemonSet"},
+		{Group: "batch", Version: "v1", Kind: CronJobKind},
+	} {
+		assert.NotNil(t, GetHealthCheckFunc(gvk), gvk.Kind)
+	}
+	assert.Nil(t, getWorkloadHealthCheckFunc(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}))
+}
+
+func TestGetWorkloadHealth(t *testing.T) {
+	t.Run("Deployment", func(t *testing.T) {
+		assertHealth(t, []healthTestCase{
+			{"testdata/workload/deployment-paused.yaml", health.HealthStatusSuspended, "Deployment is paused"},
+			{"testdata/workload/deployment-healthy.yaml", health.HealthStatusHealthy, ""},
+		})
+	})
+	t.Run("StatefulSet", func(t *testing.T) {
+		assertHealth(t, []healthTestCase{
+			{"testdata/workload/statefulset-paused-partition.yaml", health.HealthStatusSuspended, "StatefulSet rollout is paused by partition 3: 0 out of 3 pods have been updated"},
+			{"testdata/workload/statefulset-paused-ondelete.yaml", health.HealthStatusSuspended, "StatefulSet rollout is paused: 1 out of 3 pods have been updated, other pods are updated when they are deleted"},
+			{"testdata/workload/statefulset-healthy-partition.yaml", health.HealthStatusHealthy, "partitioned roll out complete: 3 new pods have been updated..."},
+			{"testdata/workload/statefulset-progressing-partition.yaml", health.HealthStatusProgressing, "Waiting for partitioned roll out to finish: 1 out of 2 new pods have been updated..."},
+		})
+	})
+	t.Run("DaemonSet", func(t *testing.T) {
+		assertHealth(t, []healthTestCase{
+			{"testdata/workload/daemonset-paused-ondelete.yaml", health.HealthStatusSuspended, "DaemonSet rollout is paused: 1 out of 4 pods have been updated, other pods are updated when they are deleted"},
+			{"testdata/workload/daemonset-healthy-ondelete.yaml", health.HealthStatusHealthy, "daemon set 4 out of 4 new pods have been updated"},
+			{"testdata/workload/daemonset-progressing.yaml", health.HealthStatusProgressing, `Waiting for daemon set "agent" rollout to finish: 1 out of 4 new pods have been updated...`},
+		})
+	})
+	t.Run("CronJob", func(t *testing.T) {
+		assertHealth(t, []healthTestCase{
+			{"testdata/workload/cronjob-suspended.yaml", health.HealthStatusSuspended, "CronJob is suspended"},
+			{"testdata/workload/cronjob-healthy.yaml", health.HealthStatusHealthy, ""},
+		})
+	})
+}
+
+func TestGetResourceHealth_PausedWorkload(t *testing.T) {
+	sts := loadTestResource(t, "testdata/workload/statefulset-paused-partition.yaml")
+
+	t.Run("Paused", func(t *testing.T) {
+		// the built-in health check of gitops-engine reports the partitioned StatefulSet as healthy
+		healthStatus, err := GetResourceHealth(sts, nil, "")
+		require.NoError(t, err)
+		require.NotNil(t, healthStatus)
+		assert.Equal(t, health.HealthStatusSuspended, healthStatus.Status)
+	})
+
+	t.Run("HealthOverride", func(t *testing.T) {
+		healthStatus, err := GetResourceHealth(sts, fakeHealthOverride{status: health.HealthStatusDegraded}, "")
+		require.NoError(t, err)
+		require.NotNil(t, healthStatus)
+		assert.Equal(t, health.HealthStatusDegraded, healthStatus.Status)
+	})
+
+	t.Run("ExtendedHealthOverride", func(t *testing.T) {
+		healthStatus, err := health.GetResourceHealth(sts, NewExtendedHealthOverride(nil))
+		require.NoError(t, err)
+		require.NotNil(t, healthStatus)
+		assert.Equal(t, health.HealthStatusSuspended, healthStatus.Status)
+	})
+}
@@ -10,7 +10,13 @@ import (
 // ServeHealthCheck serves the health check endpoint.
 // ServeHealthCheck relies on the provided function to return an error if unhealthy and nil otherwise.
 func ServeHealthCheck(mux *http.ServeMux, f func(r *http.Request) error) {
-	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+	ServeHealthCheckPath(mux, "/healthz", f)
+}
+
+// ServeHealthCheckPath serves a health check endpoint on the given path, e.g. to serve a secondary health check along
+// with the one served by ServeHealthCheck.
+func ServeHealthCheckPath(mux *http.ServeMux, path string, f func(r *http.Request) error) {
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
 		if err := f(r); err != nil {
 			w.WriteHeader(http.StatusServiceUnavailable)
 			log.Errorln(w, err)
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestHealthCheck(t *testing.T) {
@@ -56,3 +59,21 @@ func TestHealthCheck(t *testing.T) {
 		t.Fatalf("Was expecting status code 503 from health check, but got %d instead", resp.StatusCode)
 	}
 }
+
+func TestHealthCheckPath(t *testing.T) {
+	mux := http.NewServeMux()
+	ServeHealthCheck(mux, func(r *http.Request) error {
+		return nil
+	})
+	ServeHealthCheckPath(mux, "/healthz/queue", func(r *http.Request) error {
+		return fmt.Errorf("the queue is stuck")
+	})
+
+	rr := httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, rr.Code)
+
+	rr = httptest.NewRecorder()
+	mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz/queue", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rr.Code)
+}
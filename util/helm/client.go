@@ -51,6 +51,8 @@ type Creds struct {
 type indexCache interface {
 	SetHelmIndex(repo string, indexData []byte) error
 	GetHelmIndex(repo string, indexData *[]byte) error
+	SetHelmIndexETag(repo string, etag string, indexData []byte) error
+	GetHelmIndexETag(repo string, etag *string, indexData *[]byte) error
 }
 
 type Client interface {
@@ -236,18 +238,41 @@ func (c *nativeHelmChart) GetIndex(noCache bool, maxIndexSize int64) (*Index, er
 	}
 
 	if len(data) == 0 {
+		// the index served with the latest ETag is revalidated using a conditional request, so it is used even if
+		// the cache is bypassed
+		var etag string
+		var etagData []byte
+		if c.indexCache != nil {
+			if err := c.indexCache.GetHelmIndexETag(c.repoURL, &etag, &etagData); err != nil && !errors.Is(err, cache.ErrCacheMiss) {
+				log.Warnf("Failed to load index cache by ETag for repo: %s: %v", c.repoURL, err)
+			}
+			if len(etagData) == 0 {
+				etag = ""
+			}
+		}
+
 		start := time.Now()
-		var err error
-		data, err = c.loadRepoIndex(maxIndexSize)
+		newETag, notModified, loaded, err := c.loadRepoIndex(maxIndexSize, etag)
 		if err != nil {
 			return nil, err
 		}
-		log.WithFields(log.Fields{"seconds": time.Since(start).Seconds()}).Info("took to get index")
+		if notModified {
+			log.WithFields(log.Fields{"seconds": time.Since(start).Seconds()}).Info("took to revalidate index")
+			data = etagData
+		} else {
+			log.WithFields(log.Fields{"seconds": time.Since(start).Seconds()}).Info("took to get index")
+			data = loaded
+		}
 
 		if c.indexCache != nil {
 			if err := c.indexCache.SetHelmIndex(c.repoURL, data); err != nil {
 				log.Warnf("Failed to store index cache for repo: %s: %v", c.repoURL, err)
 			}
+			if newETag != "" {
+				if err := c.indexCache.SetHelmIndexETag(c.repoURL, newETag, data); err != nil {
+					log.Warnf("Failed to store index cache by ETag for repo: %s: %v", c.repoURL, err)
+				}
+			}
 		}
 	}
 
@@ -291,24 +316,30 @@ func (c *nativeHelmChart) TestHelmOCI() (bool, error) {
 	return true, nil
 }
 
-func (c *nativeHelmChart) loadRepoIndex(maxIndexSize int64) ([]byte, error) {
+// loadRepoIndex fetches the index.yaml of the repository. If etag is not empty, the index is only fetched if it has
+// changed since it was served with this ETag, and notModified is true otherwise. The ETag of the fetched index is
+// returned if the repository supports ETags.
+func (c *nativeHelmChart) loadRepoIndex(maxIndexSize int64, etag string) (newETag string, notModified bool, data []byte, err error) {
 	indexURL, err := getIndexURL(c.repoURL)
 	if err != nil {
-		return nil, err
+		return "", false, nil, err
 	}
 
 	req, err := http.NewRequest(http.MethodGet, indexURL, nil)
 	if err != nil {
-		return nil, err
+		return "", false, nil, err
 	}
 	if c.creds.Username != "" || c.creds.Password != "" {
 		// only basic supported
 		req.SetBasicAuth(c.creds.Username, c.creds.Password)
 	}
+	if etag != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
 
 	tlsConf, err := newTLSConfig(c.creds)
 	if err != nil {
-		return nil, err
+		return "", false, nil, err
 	}
 
 	tr := &http.Transport{
@@ -319,14 +350,21 @@ func (c *nativeHelmChart) loadRepoIndex(maxIndexSize int64) ([]byte, error) {
 	client := http.Client{Transport: tr}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return "", false, nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if etag != "" && resp.StatusCode == http.StatusNotModified {
+		return etag, true, nil, nil
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.New("failed to get index: " + resp.Status)
+		return "", false, nil, errors.New("failed to get index: " + resp.Status)
 	}
-	return io.ReadAll(io.LimitReader(resp.Body, maxIndexSize))
+	data, err = io.ReadAll(io.LimitReader(resp.Body, maxIndexSize))
+	if err != nil {
+		return "", false, nil, err
+	}
+	return resp.Header.Get("ETag"), false, data, nil
 }
 
 func newTLSConfig(creds Creds) (*tls.Config, error) {
@@ -377,6 +415,35 @@ func (c *nativeHelmChart) getCachedChartPath(chart string, version string, proje
 }
 
 // Ensures that given OCI registries URL does not have protocol
+// NewOCIRepository returns a client of the OCI repository with the given reference, e.g. ghcr.io/org/chart, which
+// authenticates against its registry with the given Helm credentials
+func NewOCIRepository(reference string, creds Creds, proxyURL string) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize repository: %w", err)
+	}
+	tlsConf, err := newTLSConfig(creds)
+	if err != nil {
+		return nil, fmt.Errorf("failed setup tlsConfig: %w", err)
+	}
+	client := &http.Client{Transport: &http.Transport{
+		Proxy:             proxy.GetCallback(proxyURL),
+		TLSClientConfig:   tlsConf,
+		DisableKeepAlives: true,
+	}}
+
+	repoHost, _, _ := strings.Cut(reference, "/")
+	repo.Client = &auth.Client{
+		Client: client,
+		Cache:  nil,
+		Credential: auth.StaticCredential(repoHost, auth.Credential{
+			Username: creds.Username,
+			Password: creds.Password,
+		}),
+	}
+	return repo, nil
+}
+
 func IsHelmOciRepo(repoURL string) bool {
 	if repoURL == "" {
 		return false
@@ -416,28 +483,9 @@ func (c *nativeHelmChart) GetTags(chart string, noCache bool) (*TagsList, error)
 	tags := &TagsList{}
 	if len(data) == 0 {
 		start := time.Now()
-		repo, err := remote.NewRepository(tagsURL)
+		repo, err := NewOCIRepository(tagsURL, c.creds, c.proxy)
 		if err != nil {
-			return nil, fmt.Errorf("failed to initialize repository: %w", err)
-		}
-		tlsConf, err := newTLSConfig(c.creds)
-		if err != nil {
-			return nil, fmt.Errorf("failed setup tlsConfig: %w", err)
-		}
-		client := &http.Client{Transport: &http.Transport{
-			Proxy:             proxy.GetCallback(c.proxy),
-			TLSClientConfig:   tlsConf,
-			DisableKeepAlives: true,
-		}}
-
-		repoHost, _, _ := strings.Cut(tagsURL, "/")
-		repo.Client = &auth.Client{
-			Client: client,
-			Cache:  nil,
-			Credential: auth.StaticCredential(repoHost, auth.Credential{
-				Username: c.creds.Username,
-				Password: c.creds.Password,
-			}),
+			return nil, err
 		}
 
 		ctx := context.Background()
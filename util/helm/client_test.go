@@ -20,7 +20,9 @@ import (
 )
 
 type fakeIndexCache struct {
-	data []byte
+	data     []byte
+	etag     string
+	etagData map[string][]byte
 }
 
 func (f *fakeIndexCache) SetHelmIndex(_ string, indexData []byte) error {
@@ -33,6 +35,21 @@ func (f *fakeIndexCache) GetHelmIndex(_ string, indexData *[]byte) error {
 	return nil
 }
 
+func (f *fakeIndexCache) SetHelmIndexETag(_ string, etag string, indexData []byte) error {
+	if f.etagData == nil {
+		f.etagData = map[string][]byte{}
+	}
+	f.etag = etag
+	f.etagData[etag] = indexData
+	return nil
+}
+
+func (f *fakeIndexCache) GetHelmIndexETag(_ string, etag *string, indexData *[]byte) error {
+	*etag = f.etag
+	*indexData = f.etagData[f.etag]
+	return nil
+}
+
 func TestIndex(t *testing.T) {
 	t.Run("Invalid", func(t *testing.T) {
 		client := NewClient("", Creds{}, false, "")
@@ -68,6 +85,58 @@ func TestIndex(t *testing.T) {
 		assert.Equal(t, fakeIndex, *index)
 	})
 
+	t.Run("ETag", func(t *testing.T) {
+		fakeIndex := Index{Entries: map[string]Entries{"fake": {}}}
+		data := bytes.Buffer{}
+		require.NoError(t, yaml.NewEncoder(&data).Encode(fakeIndex))
+		etag := `"v1"`
+		fetches, revalidations := 0, 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("If-None-Match") == etag {
+				revalidations++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			fetches++
+			w.Header().Set("ETag", etag)
+			_, _ = w.Write(data.Bytes())
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, Creds{}, false, "", WithIndexCache(&fakeIndexCache{}))
+		for i := 0; i < 5; i++ {
+			// bypass the cached index to request the repository every time
+			index, err := client.GetIndex(true, 10000)
+			require.NoError(t, err)
+			assert.Equal(t, fakeIndex, *index)
+		}
+		assert.Equal(t, 1, fetches)
+		assert.Equal(t, 4, revalidations)
+
+		// the index is fetched again once it changed
+		etag = `"v2"`
+		_, err := client.GetIndex(true, 10000)
+		require.NoError(t, err)
+		assert.Equal(t, 2, fetches)
+	})
+
+	t.Run("NoETag", func(t *testing.T) {
+		fetches := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Empty(t, r.Header.Get("If-None-Match"))
+			fetches++
+			_, _ = w.Write([]byte("entries: {}\n"))
+		}))
+		defer server.Close()
+
+		client := NewClient(server.URL, Creds{}, false, "", WithIndexCache(&fakeIndexCache{}))
+		for i := 0; i < 3; i++ {
+			_, err := client.GetIndex(true, 10000)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 3, fetches)
+	})
+
 	t.Run("Limited", func(t *testing.T) {
 		client := NewClient("https://argoproj.github.io/argo-helm", Creds{}, false, "")
 		_, err := client.GetIndex(false, 100)
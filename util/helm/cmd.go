@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 
@@ -50,6 +51,12 @@ var redactor = func(text string) string {
 }
 
 func (c Cmd) run(args ...string) (string, string, error) {
+	return c.runWithTimeout(0, args...)
+}
+
+// runWithTimeout runs helm with the given arguments, and kills it once the timeout is exceeded. The default exec
+// timeout applies if the timeout is zero.
+func (c Cmd) runWithTimeout(timeout time.Duration, args ...string) (string, string, error) {
 	cmd := exec.Command("helm", args...)
 	cmd.Dir = c.WorkDir
 	cmd.Env = os.Environ()
@@ -67,7 +74,7 @@ func (c Cmd) run(args ...string) (string, string, error) {
 
 	cmd.Env = proxy.UpsertEnv(cmd, c.proxy)
 
-	out, err := executil.RunWithRedactor(cmd, redactor)
+	out, err := executil.RunWithExecRunOpts(cmd, executil.ExecRunOpts{Redactor: redactor, Timeout: timeout})
 	fullCommand := executil.GetCommandArgsToLog(cmd)
 	return out, fullCommand, err
 }
@@ -289,6 +296,11 @@ func (c *Cmd) dependencyBuild() (string, error) {
 	return out, err
 }
 
+func (c *Cmd) dependencyUpdate(timeout time.Duration) (string, error) {
+	out, _, err := c.runWithTimeout(timeout, "dependency", "update")
+	return out, err
+}
+
 func (c *Cmd) inspectValues(values string) (string, error) {
 	out, _, err := c.run("show", "values", values)
 	return out, err
@@ -312,6 +324,8 @@ type TemplateOpts struct {
 	// spec.source.helm.values/valuesObject.
 	ExtraValues pathutil.ResolvedFilePath
 	SkipCrds    bool
+	// Timeout kills `helm template` once exceeded, the default exec timeout applies if zero
+	Timeout time.Duration
 }
 
 var (
@@ -364,7 +378,7 @@ func (c *Cmd) template(chartPath string, opts *TemplateOpts) (string, string, er
 		args = append(args, "--include-crds")
 	}
 
-	out, command, err := c.run(args...)
+	out, command, err := c.runWithTimeout(opts.Timeout, args...)
 	if err != nil {
 		msg := err.Error()
 		if strings.Contains(msg, "--api-versions") {
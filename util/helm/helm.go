@@ -8,6 +8,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"sigs.k8s.io/yaml"
@@ -37,6 +38,9 @@ type Helm interface {
 	GetParameters(valuesFiles []pathutil.ResolvedFilePath, appPath, repoRoot string) (map[string]string, error)
 	// DependencyBuild runs `helm dependency build` to download a chart's dependencies
 	DependencyBuild() error
+	// DependencyUpdate runs `helm dependency update` to download the dependencies of a chart into its charts/
+	// directory, and kills it once the timeout is exceeded
+	DependencyUpdate(timeout time.Duration) error
 	// Dispose deletes temp resources
 	Dispose()
 }
@@ -75,6 +79,21 @@ func (h *helm) Template(templateOpts *TemplateOpts) (string, string, error) {
 }
 
 func (h *helm) DependencyBuild() error {
+	return h.withRepos(func() error {
+		_, err := h.cmd.dependencyBuild()
+		return err
+	})
+}
+
+func (h *helm) DependencyUpdate(timeout time.Duration) error {
+	return h.withRepos(func() error {
+		_, err := h.cmd.dependencyUpdate(timeout)
+		return err
+	})
+}
+
+// withRepos adds the repositories of the chart, or logs in the OCI registries, before running fn
+func (h *helm) withRepos(fn func() error) error {
 	isHelmOci := h.cmd.IsHelmOci
 	defer func() {
 		h.cmd.IsHelmOci = isHelmOci
@@ -103,8 +122,7 @@ func (h *helm) DependencyBuild() error {
 		}
 	}
 	h.repos = nil
-	_, err := h.cmd.dependencyBuild()
-	return err
+	return fn()
 }
 
 func (h *helm) Dispose() {
@@ -0,0 +1,158 @@
+package kube
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// DeprecatedAPI is an API version of a kind which is deprecated, and removed in a version of Kubernetes
+type DeprecatedAPI struct {
+	// ReplacementAPIVersion is the API version of the kind to use instead, or empty if the kind was removed without
+	// replacement
+	ReplacementAPIVersion string
+	// RemovedInVersion is the first version of Kubernetes which no longer serves the API version, e.g. v1.16
+	RemovedInVersion string
+}
+
+// deprecatedAPIs are the API versions removed from Kubernetes, by the group, version and kind of the resources
+var deprecatedAPIs = map[schema.GroupVersionKind]DeprecatedAPI{
+	{Group: "extensions", Version: "v1beta1", Kind: "DaemonSet"}:                                        {"apps/v1", "v1.16"},
+	{Group: "extensions", Version: "v1beta1", Kind: "Deployment"}:                                       {"apps/v1", "v1.16"},
+	{Group: "extensions", Version: "v1beta1", Kind: "ReplicaSet"}:                                       {"apps/v1", "v1.16"},
+	{Group: "extensions", Version: "v1beta1", Kind: "NetworkPolicy"}:                                    {"networking.k8s.io/v1", "v1.16"},
+	{Group: "extensions", Version: "v1beta1", Kind: "PodSecurityPolicy"}:                                {"policy/v1beta1", "v1.16"},
+	{Group: "extensions", Version: "v1beta1", Kind: "Ingress"}:                                          {"networking.k8s.io/v1", "v1.22"},
+	{Group: "apps", Version: "v1beta1", Kind: "Deployment"}:                                             {"apps/v1", "v1.16"},
+	{Group: "apps", Version: "v1beta1", Kind: "StatefulSet"}:                                            {"apps/v1", "v1.16"},
+	{Group: "apps", Version: "v1beta1", Kind: "ControllerRevision"}:                                     {"apps/v1", "v1.16"},
+	{Group: "apps", Version: "v1beta2", Kind: "DaemonSet"}:                                              {"apps/v1", "v1.16"},
+	{Group: "apps", Version: "v1beta2", Kind: "Deployment"}:                                             {"apps/v1", "v1.16"},
+	{Group: "apps", Version: "v1beta2", Kind: "ReplicaSet"}:                                             {"apps/v1", "v1.16"},
+	{Group: "apps", Version: "v1beta2", Kind: "StatefulSet"}:                                            {"apps/v1", "v1.16"},
+	{Group: "apps", Version: "v1beta2", Kind: "ControllerRevision"}:                                     {"apps/v1", "v1.16"},
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "MutatingWebhookConfiguration"}:   {"admissionregistration.k8s.io/v1", "v1.22"},
+	{Group: "admissionregistration.k8s.io", Version: "v1beta1", Kind: "ValidatingWebhookConfiguration"}: {"admissionregistration.k8s.io/v1", "v1.22"},
+	{Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition"}:               {"apiextensions.k8s.io/v1", "v1.22"},
+	{Group: "apiregistration.k8s.io", Version: "v1beta1", Kind: "APIService"}:                           {"apiregistration.k8s.io/v1", "v1.22"},
+	{Group: "certificates.k8s.io", Version: "v1beta1", Kind: "CertificateSigningRequest"}:               {"certificates.k8s.io/v1", "v1.22"},
+	{Group: "coordination.k8s.io", Version: "v1beta1", Kind: "Lease"}:                                   {"coordination.k8s.io/v1", "v1.22"},
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "Ingress"}:                                   {"networking.k8s.io/v1", "v1.22"},
+	{Group: "networking.k8s.io", Version: "v1beta1", Kind: "IngressClass"}:                              {"networking.k8s.io/v1", "v1.22"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRole"}:                       {"rbac.authorization.k8s.io/v1", "v1.22"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "ClusterRoleBinding"}:                {"rbac.authorization.k8s.io/v1", "v1.22"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "Role"}:                              {"rbac.authorization.k8s.io/v1", "v1.22"},
+	{Group: "rbac.authorization.k8s.io", Version: "v1beta1", Kind: "RoleBinding"}:                       {"rbac.authorization.k8s.io/v1", "v1.22"},
+	{Group: "scheduling.k8s.io", Version: "v1beta1", Kind: "PriorityClass"}:                             {"scheduling.k8s.io/v1", "v1.22"},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIDriver"}:                                    {"storage.k8s.io/v1", "v1.22"},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSINode"}:                                      {"storage.k8s.io/v1", "v1.22"},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "StorageClass"}:                                 {"storage.k8s.io/v1", "v1.22"},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "VolumeAttachment"}:                             {"storage.k8s.io/v1", "v1.22"},
+	{Group: "batch", Version: "v1beta1", Kind: "CronJob"}:                                               {"batch/v1", "v1.25"},
+	{Group: "discovery.k8s.io", Version: "v1beta1", Kind: "EndpointSlice"}:                              {"discovery.k8s.io/v1", "v1.25"},
+	{Group: "events.k8s.io", Version: "v1beta1", Kind: "Event"}:                                         {"events.k8s.io/v1", "v1.25"},
+	{Group: "autoscaling", Version: "v2beta1", Kind: "HorizontalPodAutoscaler"}:                         {"autoscaling/v2", "v1.25"},
+	{Group: "autoscaling", Version: "v2beta2", Kind: "HorizontalPodAutoscaler"}:                         {"autoscaling/v2", "v1.26"},
+	{Group: "node.k8s.io", Version: "v1beta1", Kind: "RuntimeClass"}:                                    {"node.k8s.io/v1", "v1.25"},
+	{Group: "policy", Version: "v1beta1", Kind: "PodDisruptionBudget"}:                                  {"policy/v1", "v1.25"},
+	{Group: "policy", Version: "v1beta1", Kind: "PodSecurityPolicy"}:                                    {"", "v1.25"},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "FlowSchema"}:                     {"flowcontrol.apiserver.k8s.io/v1", "v1.26"},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta1", Kind: "PriorityLevelConfiguration"}:     {"flowcontrol.apiserver.k8s.io/v1", "v1.26"},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "FlowSchema"}:                     {"flowcontrol.apiserver.k8s.io/v1", "v1.29"},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta2", Kind: "PriorityLevelConfiguration"}:     {"flowcontrol.apiserver.k8s.io/v1", "v1.29"},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "FlowSchema"}:                     {"flowcontrol.apiserver.k8s.io/v1", "v1.32"},
+	{Group: "flowcontrol.apiserver.k8s.io", Version: "v1beta3", Kind: "PriorityLevelConfiguration"}:     {"flowcontrol.apiserver.k8s.io/v1", "v1.32"},
+	{Group: "storage.k8s.io", Version: "v1beta1", Kind: "CSIStorageCapacity"}:                           {"storage.k8s.io/v1", "v1.27"},
+}
+
+// GetDeprecatedAPI returns the deprecation of the API version of the given kind, if it is deprecated
+func GetDeprecatedAPI(gvk schema.GroupVersionKind) (DeprecatedAPI, bool) {
+	api, ok := deprecatedAPIs[gvk]
+	return api, ok
+}
+
+// DeprecatedAPIUsage is a resource which uses a deprecated API version
+type DeprecatedAPIUsage struct {
+	DeprecatedAPI
+	GroupVersionKind schema.GroupVersionKind
+	Namespace        string
+	Name             string
+	// Served is true if the cluster serves the deprecated API version as preferred version of the kind
+	Served bool
+}
+
+// String returns a message describing the deprecation, and the API version to use instead
+func (u DeprecatedAPIUsage) String() string {
+	name := u.Name
+	if u.Namespace != "" {
+		name = u.Namespace + "/" + name
+	}
+	message := fmt.Sprintf("%s %s uses API version %s, which is removed in Kubernetes %s", u.GroupVersionKind.Kind, name, u.GroupVersionKind.GroupVersion().String(), u.RemovedInVersion)
+	if !u.Served {
+		message += " and not served by the cluster"
+	}
+	if u.ReplacementAPIVersion == "" {
+		return message + ", without replacement"
+	}
+	return message + ", use " + u.ReplacementAPIVersion + " instead"
+}
+
+// FindDeprecatedAPIs returns the resources which use deprecated API versions, sorted by kind, namespace and name. The
+// API versions are compared to the preferred API versions of a cluster, as returned by ServerPreferredResources.
+func FindDeprecatedAPIs(objs []*unstructured.Unstructured, preferredResources []*metav1.APIResourceList) []DeprecatedAPIUsage {
+	served := map[schema.GroupVersionKind]bool{}
+	for _, list := range preferredResources {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, resource := range list.APIResources {
+			served[gv.WithKind(resource.Kind)] = true
+		}
+	}
+
+	var usages []DeprecatedAPIUsage
+	for _, obj := range objs {
+		if obj == nil {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		api, ok := GetDeprecatedAPI(gvk)
+		if !ok {
+			continue
+		}
+		usages = append(usages, DeprecatedAPIUsage{
+			DeprecatedAPI:    api,
+			GroupVersionKind: gvk,
+			Namespace:        obj.GetNamespace(),
+			Name:             obj.GetName(),
+			Served:           served[gvk],
+		})
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].GroupVersionKind.Kind != usages[j].GroupVersionKind.Kind {
+			return usages[i].GroupVersionKind.Kind < usages[j].GroupVersionKind.Kind
+		}
+		if usages[i].Namespace != usages[j].Namespace {
+			return usages[i].Namespace < usages[j].Namespace
+		}
+		return usages[i].Name < usages[j].Name
+	})
+	return usages
+}
+
+// GetServerPreferredResources returns the preferred API versions of the resources served by a cluster. The API groups
+// which failed to be discovered, e.g. because of an unavailable aggregated API server, are omitted.
+func GetServerPreferredResources(client discovery.DiscoveryInterface) ([]*metav1.APIResourceList, error) {
+	resources, err := discovery.ServerPreferredResources(client)
+	var discoveryErr *discovery.ErrGroupDiscoveryFailed
+	if err != nil && !errors.As(err, &discoveryErr) {
+		return nil, fmt.Errorf("error discovering the preferred resources of the cluster: %w", err)
+	}
+	return resources, nil
+}
@@ -0,0 +1,85 @@
+package kube
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// newDeprecationTestResources returns the API resources of a cluster which does not serve apps/v1beta1 anymore, but
+// still serves batch/v1beta1
+func newDeprecationTestResources() []*metav1.APIResourceList {
+	return []*metav1.APIResourceList{
+		{GroupVersion: "v1", APIResources: []metav1.APIResource{{Name: "configmaps", Kind: "ConfigMap"}}},
+		{GroupVersion: "apps/v1", APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment"}, {Name: "statefulsets", Kind: "StatefulSet"}}},
+		{GroupVersion: "batch/v1beta1", APIResources: []metav1.APIResource{{Name: "cronjobs", Kind: "CronJob"}}},
+	}
+}
+
+func newDeprecationTestObject(apiVersion, kind, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestGetDeprecatedAPI(t *testing.T) {
+	api, ok := GetDeprecatedAPI(schema.GroupVersionKind{Group: "apps", Version: "v1beta1", Kind: "Deployment"})
+	require.True(t, ok)
+	assert.Equal(t, DeprecatedAPI{ReplacementAPIVersion: "apps/v1", RemovedInVersion: "v1.16"}, api)
+
+	_, ok = GetDeprecatedAPI(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	assert.False(t, ok)
+}
+
+func TestFindDeprecatedAPIs(t *testing.T) {
+	objs := []*unstructured.Unstructured{
+		newDeprecationTestObject("apps/v1beta1", "StatefulSet", "default", "db"),
+		newDeprecationTestObject("apps/v1beta1", "Deployment", "default", "web"),
+		newDeprecationTestObject("apps/v1", "Deployment", "default", "api"),
+		newDeprecationTestObject("batch/v1beta1", "CronJob", "default", "backup"),
+		newDeprecationTestObject("policy/v1beta1", "PodSecurityPolicy", "", "restricted"),
+		newDeprecationTestObject("v1", "ConfigMap", "default", "config"),
+		nil,
+	}
+
+	usages := FindDeprecatedAPIs(objs, newDeprecationTestResources())
+	require.Len(t, usages, 4)
+	assert.Equal(t, DeprecatedAPIUsage{
+		DeprecatedAPI:    DeprecatedAPI{ReplacementAPIVersion: "batch/v1", RemovedInVersion: "v1.25"},
+		GroupVersionKind: schema.GroupVersionKind{Group: "batch", Version: "v1beta1", Kind: "CronJob"},
+		Namespace:        "default",
+		Name:             "backup",
+		Served:           true,
+	}, usages[0])
+	assert.Equal(t, "CronJob default/backup uses API version batch/v1beta1, which is removed in Kubernetes v1.25, use batch/v1 instead", usages[0].String())
+	assert.Equal(t, "Deployment default/web uses API version apps/v1beta1, which is removed in Kubernetes v1.16 and not served by the cluster, use apps/v1 instead", usages[1].String())
+	assert.Equal(t, "PodSecurityPolicy restricted uses API version policy/v1beta1, which is removed in Kubernetes v1.25 and not served by the cluster, without replacement", usages[2].String())
+	assert.Equal(t, "StatefulSet default/db uses API version apps/v1beta1, which is removed in Kubernetes v1.16 and not served by the cluster, use apps/v1 instead", usages[3].String())
+
+	assert.Empty(t, FindDeprecatedAPIs([]*unstructured.Unstructured{newDeprecationTestObject("apps/v1", "Deployment", "default", "api")}, newDeprecationTestResources()))
+}
+
+func TestGetServerPreferredResources(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.Resources = append(newDeprecationTestResources(),
+		&metav1.APIResourceList{GroupVersion: "apps/v1beta2", APIResources: []metav1.APIResource{{Name: "deployments", Kind: "Deployment"}}})
+
+	resources, err := GetServerPreferredResources(client.Discovery())
+	require.NoError(t, err)
+	var kinds []string
+	for _, list := range resources {
+		for _, resource := range list.APIResources {
+			kinds = append(kinds, list.GroupVersion+"/"+resource.Kind)
+		}
+	}
+	// only the preferred version of each group is returned, which is the first version of the group
+	assert.ElementsMatch(t, []string{"v1/ConfigMap", "apps/v1/Deployment", "apps/v1/StatefulSet", "batch/v1beta1/CronJob"}, kinds)
+}
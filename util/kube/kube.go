@@ -1,12 +1,18 @@
 package kube
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"regexp"
 
 	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
 
 	"github.com/argoproj/argo-cd/v2/common"
 )
@@ -174,3 +180,43 @@ func nestedNullableStringMap(obj map[string]interface{}, fields ...string) (map[
 	}
 	return m, err
 }
+
+// ServiceAccountUsername returns the username of the given service account, which is the user impersonated by
+// kubectl --as to act as the service account
+func ServiceAccountUsername(namespace, name string) string {
+	return fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name)
+}
+
+// WithImpersonation returns a copy of the config whose requests impersonate the given user, like kubectl --as
+func WithImpersonation(config *rest.Config, username string) *rest.Config {
+	config = rest.CopyConfig(config)
+	config.Impersonate = rest.ImpersonationConfig{UserName: username}
+	return config
+}
+
+// CanImpersonateServiceAccount returns an error unless the user of the client is permitted to impersonate the given
+// service account
+func CanImpersonateServiceAccount(ctx context.Context, client kubernetes.Interface, namespace, name string) error {
+	review := &authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "impersonate",
+				Resource:  "serviceaccounts",
+				Name:      name,
+			},
+		},
+	}
+	review, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to check the permission to impersonate service account %s/%s: %w", namespace, name, err)
+	}
+	if !review.Status.Allowed {
+		message := fmt.Sprintf("not permitted to impersonate service account %s/%s, the impersonate verb on serviceaccounts must be granted", namespace, name)
+		if review.Status.Reason != "" {
+			message += ": " + review.Status.Reason
+		}
+		return errors.New(message)
+	}
+	return nil
+}
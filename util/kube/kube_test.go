@@ -1,6 +1,7 @@
 package kube
 
 import (
+	"context"
 	"encoding/json"
 	"log"
 	"os"
@@ -8,9 +9,14 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	apiv1 "k8s.io/api/core/v1"
 	extv1beta1 "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	kubetesting "k8s.io/client-go/testing"
 	"sigs.k8s.io/yaml"
 
 	"github.com/argoproj/argo-cd/v2/common"
@@ -271,3 +277,36 @@ func TestRemoveLabelWithInvalidData(t *testing.T) {
 	require.Error(t, err)
 	assert.Equal(t, "failed to get labels for /v1, Kind=Service /my-service: .metadata.labels accessor error: contains non-string value in the map under key \"invalid-label\": <nil> is of the type <nil>, expected string", err.Error())
 }
+
+func TestWithImpersonation(t *testing.T) {
+	config := &rest.Config{Host: "https://kubernetes.default.svc", BearerToken: "token"}
+	impersonating := WithImpersonation(config, ServiceAccountUsername("guestbook", "deployer"))
+	assert.Equal(t, "system:serviceaccount:guestbook:deployer", impersonating.Impersonate.UserName)
+	assert.Equal(t, "token", impersonating.BearerToken)
+	// the given config is not modified
+	assert.Empty(t, config.Impersonate.UserName)
+}
+
+func TestCanImpersonateServiceAccount(t *testing.T) {
+	newClient := func(allowed bool) *fake.Clientset {
+		client := fake.NewSimpleClientset()
+		client.PrependReactor("create", "selfsubjectaccessreviews", func(action kubetesting.Action) (bool, runtime.Object, error) {
+			res := action.(kubetesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview).DeepCopy()
+			res.Status.Allowed = allowed
+			if !allowed {
+				res.Status.Reason = "no RBAC policy matched"
+			}
+			return true, res, nil
+		})
+		return client
+	}
+
+	client := newClient(true)
+	require.NoError(t, CanImpersonateServiceAccount(context.Background(), client, "guestbook", "deployer"))
+	attributes := client.Actions()[0].(kubetesting.CreateAction).GetObject().(*authorizationv1.SelfSubjectAccessReview).Spec.ResourceAttributes
+	assert.Equal(t, &authorizationv1.ResourceAttributes{Namespace: "guestbook", Verb: "impersonate", Resource: "serviceaccounts", Name: "deployer"}, attributes)
+
+	client = newClient(false)
+	err := CanImpersonateServiceAccount(context.Background(), client, "guestbook", "deployer")
+	require.EqualError(t, err, "not permitted to impersonate service account guestbook/deployer, the impersonate verb on serviceaccounts must be granted: no RBAC policy matched")
+}
@@ -0,0 +1,140 @@
+package kustomize
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+
+	executil "github.com/argoproj/argo-cd/v2/util/exec"
+)
+
+// BaseCache stores the rendered manifests of the local bases of kustomize overlays, by the path of the base relative
+// to the repository root. Implementations are scoped to a revision of a repository.
+type BaseCache interface {
+	// GetBase returns the rendered manifests of the base, and false if they are not cached
+	GetBase(basePath string) ([]byte, bool)
+	// SetBase stores the rendered manifests of the base
+	SetBase(basePath string, manifests []byte)
+}
+
+// renderedBaseFilePrefix is the prefix of the files the rendered bases are written to in the directory of an overlay
+const renderedBaseFilePrefix = ".argocd-kustomize-base-"
+
+// useRenderedBases replaces the local bases listed in the resources of the kustomization by files holding their
+// rendered manifests, which are read from the cache or rendered with `kustomize build` and then cached, so that
+// overlays sharing a base render it once. It returns the commands run to render the bases, which are killed once the
+// timeout is exceeded unless it is zero. Overlays merging or replacing the generators of their bases are left
+// untouched, since the generators are lost once the bases are rendered.
+func (k *kustomize) useRenderedBases(cache BaseCache, env []string, timeout time.Duration) ([]string, error) {
+	kustomizationPath := ""
+	for _, name := range KustomizationNames {
+		if _, err := os.Stat(filepath.Join(k.path, name)); err == nil {
+			kustomizationPath = filepath.Join(k.path, name)
+			break
+		}
+	}
+	if kustomizationPath == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(kustomizationPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", kustomizationPath, err)
+	}
+	var kustomization map[string]interface{}
+	if err := yaml.Unmarshal(data, &kustomization); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", kustomizationPath, err)
+	}
+	if mergesGenerators(kustomization) {
+		return nil, nil
+	}
+	resources, _ := kustomization["resources"].([]interface{})
+
+	var commands []string
+	modified := false
+	for i, resource := range resources {
+		basePath, ok := k.localBasePath(resource)
+		if !ok {
+			continue
+		}
+		manifests, ok := cache.GetBase(basePath)
+		if !ok {
+			cmd := exec.Command(k.getBinaryPath(), "build", filepath.Join(k.repoRoot, basePath))
+			cmd.Env = env
+			cmd.Dir = k.repoRoot
+			commands = append(commands, executil.GetCommandArgsToLog(cmd))
+			out, err := executil.RunWithExecRunOpts(cmd, executil.ExecRunOpts{Timeout: timeout})
+			if err != nil {
+				return nil, fmt.Errorf("failed to build base %s: %w", basePath, err)
+			}
+			manifests = []byte(out)
+			cache.SetBase(basePath, manifests)
+		}
+		fileName := fmt.Sprintf("%s%d.yaml", renderedBaseFilePrefix, i)
+		if err := os.WriteFile(filepath.Join(k.path, fileName), manifests, 0o600); err != nil {
+			return nil, fmt.Errorf("failed to write rendered base %s: %w", basePath, err)
+		}
+		log.Debugf("Using rendered base %s for kustomization %s", basePath, k.path)
+		resources[i] = fileName
+		modified = true
+	}
+	if !modified {
+		return commands, nil
+	}
+
+	updated, err := yaml.Marshal(kustomization)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", kustomizationPath, err)
+	}
+	info, err := os.Stat(kustomizationPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", kustomizationPath, err)
+	}
+	if err := os.WriteFile(kustomizationPath, updated, info.Mode()); err != nil {
+		return nil, fmt.Errorf("failed to write %s: %w", kustomizationPath, err)
+	}
+	return append(commands, "# the local bases of the kustomization were rendered separately and replaced by their manifests in its resources."), nil
+}
+
+// localBasePath returns the path, relative to the repository root, of the base directory referenced by a resource of
+// the kustomization, and false if the resource is a file or a remote base
+func (k *kustomize) localBasePath(resource interface{}) (string, bool) {
+	name, ok := resource.(string)
+	if !ok || strings.Contains(name, "://") || strings.HasPrefix(name, "github.com/") || strings.HasPrefix(name, "git@") {
+		return "", false
+	}
+	dir := filepath.Join(k.path, name)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return "", false
+	}
+	basePath, err := filepath.Rel(k.repoRoot, dir)
+	if err != nil || basePath == ".." || strings.HasPrefix(basePath, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	for _, name := range KustomizationNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return basePath, true
+		}
+	}
+	return "", false
+}
+
+// mergesGenerators returns true if a generator of the kustomization merges with or replaces a generator of its bases
+func mergesGenerators(kustomization map[string]interface{}) bool {
+	for _, key := range []string{"configMapGenerator", "secretGenerator"} {
+		generators, _ := kustomization[key].([]interface{})
+		for _, generator := range generators {
+			if g, ok := generator.(map[string]interface{}); ok {
+				if behavior, _ := g["behavior"].(string); behavior == "merge" || behavior == "replace" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package kustomize
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/argoproj/argo-cd/v2/util/git"
+)
+
+type fakeBaseCache struct {
+	bases map[string][]byte
+	hits  int
+}
+
+func (c *fakeBaseCache) GetBase(basePath string) ([]byte, bool) {
+	manifests, ok := c.bases[basePath]
+	if ok {
+		c.hits++
+	}
+	return manifests, ok
+}
+
+func (c *fakeBaseCache) SetBase(basePath string, manifests []byte) {
+	c.bases[basePath] = manifests
+}
+
+func TestKustomizeBuildSharedBase(t *testing.T) {
+	repoRoot, err := testDataDir(t, "shared_base")
+	require.NoError(t, err)
+	cache := &fakeBaseCache{bases: map[string][]byte{}}
+
+	for _, overlay := range []string{"overlay-a", "overlay-b"} {
+		k := NewKustomizeApp(repoRoot, filepath.Join(repoRoot, overlay), git.NopCreds{}, "", filepath.Join(repoRoot, "kustomize.fake"))
+		objs, _, commands, _, err := k.Build(nil, nil, nil, &BuildOpts{BaseCache: cache})
+		require.NoError(t, err)
+		require.Len(t, objs, 2)
+		assert.Equal(t, "guestbook-ui", objs[0].GetName())
+		assert.Equal(t, overlay, objs[1].GetName())
+		assert.Contains(t, commands[len(commands)-1], "build ./"+overlay)
+	}
+
+	// the base is rendered once, and the overlays are rendered from the cached base
+	builds, err := os.ReadFile(filepath.Join(repoRoot, "builds_output"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(repoRoot, "base"),
+		filepath.Join(repoRoot, "overlay-a"),
+		filepath.Join(repoRoot, "overlay-b"),
+	}, strings.Fields(string(builds)))
+	assert.Equal(t, 1, cache.hits)
+	assert.Contains(t, cache.bases, "base")
+}
+
+func TestKustomizeBuildSharedBase_NoCache(t *testing.T) {
+	repoRoot, err := testDataDir(t, "shared_base")
+	require.NoError(t, err)
+	k := NewKustomizeApp(repoRoot, filepath.Join(repoRoot, "overlay-a"), git.NopCreds{}, "", filepath.Join(repoRoot, "kustomize.fake"))
+	objs, _, _, _, err := k.Build(nil, nil, nil, &BuildOpts{})
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+
+	builds, err := os.ReadFile(filepath.Join(repoRoot, "builds_output"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(repoRoot, "overlay-a")}, strings.Fields(string(builds)))
+	kustomization, err := os.ReadFile(filepath.Join(repoRoot, "overlay-a", "kustomization.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(kustomization), "- ../base")
+}
+
+func TestMergesGenerators(t *testing.T) {
+	assert.False(t, mergesGenerators(map[string]interface{}{
+		"configMapGenerator": []interface{}{map[string]interface{}{"name": "config", "literals": []interface{}{"a=b"}}},
+	}))
+	assert.True(t, mergesGenerators(map[string]interface{}{
+		"configMapGenerator": []interface{}{map[string]interface{}{"name": "config", "behavior": "merge"}},
+	}))
+	assert.True(t, mergesGenerators(map[string]interface{}{
+		"secretGenerator": []interface{}{map[string]interface{}{"name": "secret", "behavior": "replace"}},
+	}))
+}
@@ -7,9 +7,11 @@ import (
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Masterminds/semver/v3"
 	"sigs.k8s.io/yaml"
@@ -30,12 +32,41 @@ type Image = string
 type BuildOpts struct {
 	KubeVersion string
 	APIVersions []string
+	// PluginHome is the directory kustomize looks up alpha plugins in when validating the manifests, the default
+	// directory of kustomize is used when empty
+	PluginHome string
+	// BaseCache caches the rendered local bases of the kustomization, which are rendered along with it when nil. It is
+	// not used with custom build options, which may change how bases are rendered.
+	BaseCache BaseCache
+	// Timeout kills `kustomize build` once exceeded, the default exec timeout applies if zero
+	Timeout time.Duration
+	// OCIBases fetches the OCI bases listed in the resources of the kustomization, which fail to build when nil
+	OCIBases OCIBaseFetcher
+}
+
+// warningPrefix is the prefix of the warnings kustomize and its plugins write to stderr
+const warningPrefix = "[WARNING]"
+
+// Warning is a warning reported while building the manifests, e.g. a schema validation warning
+type Warning struct {
+	// Resource identifies the resource the warning is about, e.g. apps/v1/Deployment/guestbook, empty if unknown
+	Resource string
+	// Message is the message of the warning
+	Message string
+}
+
+func (w Warning) String() string {
+	if w.Resource == "" {
+		return w.Message
+	}
+	return fmt.Sprintf("%s: %s", w.Resource, w.Message)
 }
 
 // Kustomize provides wrapper functionality around the `kustomize` command.
 type Kustomize interface {
-	// Build returns a list of unstructured objects from a `kustomize build` command and extract supported parameters
-	Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOptions *v1alpha1.KustomizeOptions, envVars *v1alpha1.Env, buildOpts *BuildOpts) ([]*unstructured.Unstructured, []Image, []string, error)
+	// Build returns a list of unstructured objects from a `kustomize build` command and extract supported parameters,
+	// together with the warnings reported when the source enables validation
+	Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOptions *v1alpha1.KustomizeOptions, envVars *v1alpha1.Env, buildOpts *BuildOpts) ([]*unstructured.Unstructured, []Image, []string, []Warning, error)
 }
 
 // NewKustomizeApp create a new wrapper to run commands on the `kustomize` command-line tool.
@@ -71,6 +102,48 @@ func (k *kustomize) getBinaryPath() string {
 	return "kustomize"
 }
 
+// Versions maps Kustomize versions to the path of their binary
+type Versions map[string]string
+
+// ParseVersions parses Kustomize binaries formatted as `version=path` pairs, e.g. `4.5.7=/usr/local/bin/kustomize-4.5.7`
+func ParseVersions(pairs []string) (Versions, error) {
+	versions := Versions{}
+	for _, pair := range pairs {
+		version, path, ok := strings.Cut(pair, "=")
+		version = normalizeVersion(strings.TrimSpace(version))
+		path = strings.TrimSpace(path)
+		if !ok || version == "" || path == "" {
+			return nil, fmt.Errorf("invalid kustomize version %q: must be formatted as version=path", pair)
+		}
+		if _, ok := versions[version]; ok {
+			return nil, fmt.Errorf("found duplicate kustomize version: %s", version)
+		}
+		versions[version] = path
+	}
+	return versions, nil
+}
+
+// BinaryPath returns the path of the Kustomize binary to build a source with. The binary path of the Kustomize options,
+// which is resolved from the argocd-cm ConfigMap, takes precedence over the binary of the version requested by the
+// source. An empty path, which selects the default binary, is returned when the source requests no version.
+func (v Versions) BinaryPath(source *v1alpha1.ApplicationSourceKustomize, kustomizeOptions *v1alpha1.KustomizeOptions) (string, error) {
+	if kustomizeOptions != nil && kustomizeOptions.BinaryPath != "" {
+		return kustomizeOptions.BinaryPath, nil
+	}
+	if source == nil || source.Version == "" {
+		return "", nil
+	}
+	if path, ok := v[normalizeVersion(source.Version)]; ok {
+		return path, nil
+	}
+	return "", fmt.Errorf("kustomize version %s is not registered", source.Version)
+}
+
+// normalizeVersion strips the optional "v" prefix of a version, so that "v4.5.7" and "4.5.7" select the same binary
+func normalizeVersion(version string) string {
+	return strings.TrimPrefix(version, "v")
+}
+
 // kustomize v3.8.5 patch release introduced a breaking change in "edit add <label/annotation>" commands:
 // https://github.com/kubernetes-sigs/kustomize/commit/b214fa7d5aa51d7c2ae306ec15115bf1c044fed8#diff-0328c59bcd29799e365ff0647653b886f17c8853df008cd54e7981db882c1b36
 func mapToEditAddArgs(val map[string]string) []string {
@@ -92,7 +165,7 @@ func mapToEditAddArgs(val map[string]string) []string {
 	return args
 }
 
-func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOptions *v1alpha1.KustomizeOptions, envVars *v1alpha1.Env, buildOpts *BuildOpts) ([]*unstructured.Unstructured, []Image, []string, error) {
+func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOptions *v1alpha1.KustomizeOptions, envVars *v1alpha1.Env, buildOpts *BuildOpts) ([]*unstructured.Unstructured, []Image, []string, []Warning, error) {
 	// commands stores all the commands that were run as part of this build.
 	var commands []string
 
@@ -103,7 +176,7 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 
 	closer, environ, err := k.creds.Environ()
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 	defer func() { _ = closer.Close() }()
 
@@ -137,7 +210,7 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 			commands = append(commands, executil.GetCommandArgsToLog(cmd))
 			_, err := executil.Run(cmd)
 			if err != nil {
-				return nil, nil, nil, err
+				return nil, nil, nil, nil, err
 			}
 		}
 		if opts.NameSuffix != "" {
@@ -146,7 +219,7 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 			commands = append(commands, executil.GetCommandArgsToLog(cmd))
 			_, err := executil.Run(cmd)
 			if err != nil {
-				return nil, nil, nil, err
+				return nil, nil, nil, nil, err
 			}
 		}
 		if len(opts.Images) > 0 {
@@ -163,7 +236,7 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 			commands = append(commands, executil.GetCommandArgsToLog(cmd))
 			_, err := executil.Run(cmd)
 			if err != nil {
-				return nil, nil, nil, err
+				return nil, nil, nil, nil, err
 			}
 		}
 
@@ -173,7 +246,7 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 			for _, replica := range opts.Replicas {
 				count, err := replica.GetIntCount()
 				if err != nil {
-					return nil, nil, nil, err
+					return nil, nil, nil, nil, err
 				}
 				arg := fmt.Sprintf("%s=%d", replica.Name, count)
 				args = append(args, arg)
@@ -184,7 +257,7 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 			commands = append(commands, executil.GetCommandArgsToLog(cmd))
 			_, err := executil.Run(cmd)
 			if err != nil {
-				return nil, nil, nil, err
+				return nil, nil, nil, nil, err
 			}
 		}
 
@@ -206,7 +279,7 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 			commands = append(commands, executil.GetCommandArgsToLog(cmd))
 			_, err := executil.Run(cmd)
 			if err != nil {
-				return nil, nil, nil, err
+				return nil, nil, nil, nil, err
 			}
 		}
 
@@ -230,7 +303,7 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 			commands = append(commands, executil.GetCommandArgsToLog(cmd))
 			_, err := executil.Run(cmd)
 			if err != nil {
-				return nil, nil, nil, err
+				return nil, nil, nil, nil, err
 			}
 		}
 
@@ -240,7 +313,7 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 			commands = append(commands, executil.GetCommandArgsToLog(cmd))
 			_, err := executil.Run(cmd)
 			if err != nil {
-				return nil, nil, nil, err
+				return nil, nil, nil, nil, err
 			}
 		}
 
@@ -248,23 +321,23 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 			kustomizationPath := filepath.Join(k.path, "kustomization.yaml")
 			b, err := os.ReadFile(kustomizationPath)
 			if err != nil {
-				return nil, nil, nil, fmt.Errorf("failed to load kustomization.yaml: %w", err)
+				return nil, nil, nil, nil, fmt.Errorf("failed to load kustomization.yaml: %w", err)
 			}
 			var kustomization interface{}
 			err = yaml.Unmarshal(b, &kustomization)
 			if err != nil {
-				return nil, nil, nil, fmt.Errorf("failed to unmarshal kustomization.yaml: %w", err)
+				return nil, nil, nil, nil, fmt.Errorf("failed to unmarshal kustomization.yaml: %w", err)
 			}
 			kMap, ok := kustomization.(map[string]interface{})
 			if !ok {
-				return nil, nil, nil, fmt.Errorf("expected kustomization.yaml to be type map[string]interface{}, but got %T", kMap)
+				return nil, nil, nil, nil, fmt.Errorf("expected kustomization.yaml to be type map[string]interface{}, but got %T", kMap)
 			}
 			patches, ok := kMap["patches"]
 			if ok {
 				// The kustomization.yaml already had a patches field, so we need to append to it.
 				patchesList, ok := patches.([]interface{})
 				if !ok {
-					return nil, nil, nil, fmt.Errorf("expected 'patches' field in kustomization.yaml to be []interface{}, but got %T", patches)
+					return nil, nil, nil, nil, fmt.Errorf("expected 'patches' field in kustomization.yaml to be []interface{}, but got %T", patches)
 				}
 				// Since the patches from the Application manifest are typed, we need to convert them to a type which
 				// can be appended to the existing list.
@@ -280,15 +353,15 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 			}
 			updatedKustomization, err := yaml.Marshal(kMap)
 			if err != nil {
-				return nil, nil, nil, fmt.Errorf("failed to marshal kustomization.yaml after adding patches: %w", err)
+				return nil, nil, nil, nil, fmt.Errorf("failed to marshal kustomization.yaml after adding patches: %w", err)
 			}
 			kustomizationFileInfo, err := os.Stat(kustomizationPath)
 			if err != nil {
-				return nil, nil, nil, fmt.Errorf("failed to stat kustomization.yaml: %w", err)
+				return nil, nil, nil, nil, fmt.Errorf("failed to stat kustomization.yaml: %w", err)
 			}
 			err = os.WriteFile(kustomizationPath, updatedKustomization, kustomizationFileInfo.Mode())
 			if err != nil {
-				return nil, nil, nil, fmt.Errorf("failed to write kustomization.yaml with updated 'patches' field: %w", err)
+				return nil, nil, nil, nil, fmt.Errorf("failed to write kustomization.yaml with updated 'patches' field: %w", err)
 			}
 			commands = append(commands, "# kustomization.yaml updated with patches. There is no `kustomize edit` command for adding patches. In order to generate the manifests in your local environment, you will need to copy the patches into kustomization.yaml manually.")
 		}
@@ -297,7 +370,7 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 			// components only supported in kustomize >= v3.7.0
 			// https://github.com/kubernetes-sigs/kustomize/blob/master/examples/components.md
 			if getSemverSafe().LessThan(semver.MustParse("v3.7.0")) {
-				return nil, nil, nil, fmt.Errorf("kustomize components require kustomize v3.7.0 and above")
+				return nil, nil, nil, nil, fmt.Errorf("kustomize components require kustomize v3.7.0 and above")
 			}
 
 			// add components
@@ -309,29 +382,64 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 			commands = append(commands, executil.GetCommandArgsToLog(cmd))
 			_, err := executil.Run(cmd)
 			if err != nil {
-				return nil, nil, nil, err
+				return nil, nil, nil, nil, err
 			}
 		}
 	}
 
-	var cmd *exec.Cmd
+	if buildOpts != nil && buildOpts.OCIBases != nil {
+		ociBasesDir, err := k.useOCIBases(buildOpts.OCIBases)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		if ociBasesDir != "" {
+			defer func() { _ = os.RemoveAll(ociBasesDir) }()
+			commands = append(commands, "# the OCI bases of the kustomization were pulled and replaced by the directories they were extracted to in its resources.")
+		}
+	}
+
+	if buildOpts != nil && buildOpts.BaseCache != nil && (kustomizeOptions == nil || kustomizeOptions.BuildOptions == "") {
+		baseCommands, err := k.useRenderedBases(buildOpts.BaseCache, env, buildOpts.Timeout)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		commands = append(commands, baseCommands...)
+	}
+
+	params := []string{"build", k.path}
 	if kustomizeOptions != nil && kustomizeOptions.BuildOptions != "" {
-		params := parseKustomizeBuildOptions(k.path, kustomizeOptions.BuildOptions, buildOpts)
-		cmd = exec.Command(k.getBinaryPath(), params...)
-	} else {
-		cmd = exec.Command(k.getBinaryPath(), "build", k.path)
+		params = parseKustomizeBuildOptions(k.path, kustomizeOptions.BuildOptions, buildOpts)
 	}
+	validate := opts != nil && opts.Validate
+	if validate {
+		// schema validation is performed by alpha plugins, which write the validation warnings to stderr
+		if !slices.Contains(params, "--enable-alpha-plugins") {
+			params = append(params, "--enable-alpha-plugins")
+		}
+		if buildOpts != nil && buildOpts.PluginHome != "" {
+			env = append(env, fmt.Sprintf("KUSTOMIZE_PLUGIN_HOME=%s", buildOpts.PluginHome))
+		}
+	}
+	cmd := exec.Command(k.getBinaryPath(), params...)
 	cmd.Env = env
 	cmd.Dir = k.repoRoot
 	commands = append(commands, executil.GetCommandArgsToLog(cmd))
-	out, err := executil.Run(cmd)
+	var timeout time.Duration
+	if buildOpts != nil {
+		timeout = buildOpts.Timeout
+	}
+	out, err := executil.RunWithExecRunOpts(cmd, executil.ExecRunOpts{CaptureStderr: validate, Timeout: timeout})
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
+	}
+	var warnings []Warning
+	if validate {
+		out, warnings = parseWarnings(out)
 	}
 
 	objs, err := kube.SplitYAML([]byte(out))
 	if err != nil {
-		return nil, nil, nil, err
+		return nil, nil, nil, nil, err
 	}
 
 	redactedCommands := make([]string, len(commands))
@@ -339,7 +447,29 @@ func (k *kustomize) Build(opts *v1alpha1.ApplicationSourceKustomize, kustomizeOp
 		redactedCommands[i] = strings.ReplaceAll(c, k.repoRoot, ".")
 	}
 
-	return objs, getImageParameters(objs), redactedCommands, nil
+	return objs, getImageParameters(objs), redactedCommands, warnings, nil
+}
+
+// parseWarnings extracts the warnings from the output of a kustomize build capturing stderr, and returns the remaining
+// output. Warnings are the lines prefixed with [WARNING], optionally followed by the resource they are about, e.g.
+// `[WARNING] apps/v1/Deployment/guestbook: spec.replicas: Invalid type. Expected: integer, given: string`.
+func parseWarnings(out string) (string, []Warning) {
+	var warnings []Warning
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		message, ok := strings.CutPrefix(line, warningPrefix)
+		if !ok {
+			lines = append(lines, line)
+			continue
+		}
+		warning := Warning{Message: strings.TrimSpace(message)}
+		if resource, rest, ok := strings.Cut(warning.Message, ": "); ok && strings.Contains(resource, "/") && !strings.ContainsAny(resource, " \t") {
+			warning.Resource = resource
+			warning.Message = rest
+		}
+		warnings = append(warnings, warning)
+	}
+	return strings.Join(lines, "\n"), warnings
 }
 
 func parseKustomizeBuildOptions(path string, buildOptions string, buildOpts *BuildOpts) []string {
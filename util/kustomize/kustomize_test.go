@@ -6,6 +6,7 @@ import (
 	"path"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/argoproj/pkg/exec"
 	"github.com/stretchr/testify/assert"
@@ -26,6 +27,7 @@ const (
 	kustomization5  = "kustomization_yaml_patches"
 	kustomization6  = "kustomization_yaml_components"
 	kustomization7  = "label_without_selector"
+	kustomization8  = "validate"
 )
 
 func testDataDir(tb testing.TB, testData string) (string, error) {
@@ -72,7 +74,7 @@ func TestKustomizeBuild(t *testing.T) {
 			},
 		},
 	}
-	objs, images, _, err := kustomize.Build(&kustomizeSource, nil, env, &BuildOpts{
+	objs, images, _, _, err := kustomize.Build(&kustomizeSource, nil, env, &BuildOpts{
 		KubeVersion: "1.27", APIVersions: []string{"foo", "bar"},
 	})
 	require.NoError(t, err)
@@ -137,7 +139,7 @@ func TestFailKustomizeBuild(t *testing.T) {
 			},
 		},
 	}
-	_, _, _, err = kustomize.Build(&kustomizeSource, nil, nil, nil)
+	_, _, _, _, err = kustomize.Build(&kustomizeSource, nil, nil, nil)
 	assert.EqualError(t, err, "expected integer value for count. Received: garbage")
 }
 
@@ -181,6 +183,47 @@ func TestGetSemver(t *testing.T) {
 	assert.NotEmpty(t, ver)
 }
 
+func TestParseVersions(t *testing.T) {
+	versions, err := ParseVersions([]string{"v4.5.7=/custom-tools/kustomize_4_5_7", " 5.0.0 = /custom-tools/kustomize_5 "})
+	require.NoError(t, err)
+	assert.Equal(t, Versions{"4.5.7": "/custom-tools/kustomize_4_5_7", "5.0.0": "/custom-tools/kustomize_5"}, versions)
+
+	_, err = ParseVersions([]string{"v4.5.7"})
+	require.ErrorContains(t, err, "must be formatted as version=path")
+
+	_, err = ParseVersions([]string{"v4.5.7=/a", "4.5.7=/b"})
+	require.ErrorContains(t, err, "found duplicate kustomize version: 4.5.7")
+}
+
+func TestVersionsBinaryPath(t *testing.T) {
+	versions := Versions{"4.5.7": "/custom-tools/kustomize_4_5_7"}
+
+	t.Run("DefaultVersion", func(t *testing.T) {
+		path, err := versions.BinaryPath(nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "", path)
+	})
+
+	t.Run("RegisteredVersion", func(t *testing.T) {
+		for _, version := range []string{"v4.5.7", "4.5.7"} {
+			path, err := versions.BinaryPath(&v1alpha1.ApplicationSourceKustomize{Version: version}, &v1alpha1.KustomizeOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, "/custom-tools/kustomize_4_5_7", path)
+		}
+	})
+
+	t.Run("SettingsTakePrecedence", func(t *testing.T) {
+		path, err := versions.BinaryPath(&v1alpha1.ApplicationSourceKustomize{Version: "v4.5.7"}, &v1alpha1.KustomizeOptions{BinaryPath: "/usr/local/bin/kustomize"})
+		require.NoError(t, err)
+		assert.Equal(t, "/usr/local/bin/kustomize", path)
+	})
+
+	t.Run("UnregisteredVersion", func(t *testing.T) {
+		_, err := versions.BinaryPath(&v1alpha1.ApplicationSourceKustomize{Version: "v3.5.4"}, nil)
+		require.EqualError(t, err, "kustomize version v3.5.4 is not registered")
+	})
+}
+
 func TestKustomizeBuildForceCommonLabels(t *testing.T) {
 	type testCase struct {
 		TestData        string
@@ -232,7 +275,7 @@ func TestKustomizeBuildForceCommonLabels(t *testing.T) {
 		appPath, err := testDataDir(t, tc.TestData)
 		require.NoError(t, err)
 		kustomize := NewKustomizeApp(appPath, appPath, git.NopCreds{}, "", "")
-		objs, _, _, err := kustomize.Build(&tc.KustomizeSource, nil, tc.Env, nil)
+		objs, _, _, _, err := kustomize.Build(&tc.KustomizeSource, nil, tc.Env, nil)
 		switch tc.ExpectErr {
 		case true:
 			require.Error(t, err)
@@ -324,7 +367,7 @@ func TestKustomizeBuildForceCommonAnnotations(t *testing.T) {
 		appPath, err := testDataDir(t, tc.TestData)
 		require.NoError(t, err)
 		kustomize := NewKustomizeApp(appPath, appPath, git.NopCreds{}, "", "")
-		objs, _, _, err := kustomize.Build(&tc.KustomizeSource, nil, tc.Env, nil)
+		objs, _, _, _, err := kustomize.Build(&tc.KustomizeSource, nil, tc.Env, nil)
 		switch tc.ExpectErr {
 		case true:
 			require.Error(t, err)
@@ -391,7 +434,7 @@ func TestKustomizeLabelWithoutSelector(t *testing.T) {
 		appPath, err := testDataDir(t, tc.TestData)
 		require.NoError(t, err)
 		kustomize := NewKustomizeApp(appPath, appPath, git.NopCreds{}, "", "")
-		objs, _, _, err := kustomize.Build(&tc.KustomizeSource, nil, tc.Env, nil)
+		objs, _, _, _, err := kustomize.Build(&tc.KustomizeSource, nil, tc.Env, nil)
 
 		switch tc.ExpectErr {
 		case true:
@@ -427,7 +470,7 @@ func TestKustomizeCustomVersion(t *testing.T) {
 	env := &v1alpha1.Env{
 		&v1alpha1.EnvEntry{Name: "ARGOCD_APP_NAME", Value: "argo-cd-tests"},
 	}
-	objs, images, _, err := kustomize.Build(&kustomizeSource, nil, env, nil)
+	objs, images, _, _, err := kustomize.Build(&kustomizeSource, nil, env, nil)
 	require.NoError(t, err)
 	if err != nil {
 		assert.Len(t, objs, 2)
@@ -447,7 +490,7 @@ func TestKustomizeBuildComponents(t *testing.T) {
 	kustomizeSource := v1alpha1.ApplicationSourceKustomize{
 		Components: []string{"./components"},
 	}
-	objs, _, _, err := kustomize.Build(&kustomizeSource, nil, nil, nil)
+	objs, _, _, _, err := kustomize.Build(&kustomizeSource, nil, nil, nil)
 	require.NoError(t, err)
 	obj := objs[0]
 	assert.Equal(t, "nginx-deployment", obj.GetName())
@@ -480,7 +523,7 @@ func TestKustomizeBuildPatches(t *testing.T) {
 			},
 		},
 	}
-	objs, _, _, err := kustomize.Build(&kustomizeSource, nil, nil, nil)
+	objs, _, _, _, err := kustomize.Build(&kustomizeSource, nil, nil, nil)
 	require.NoError(t, err)
 	obj := objs[0]
 	containers, found, err := unstructured.NestedSlice(obj.Object, "spec", "template", "spec", "containers")
@@ -511,3 +554,57 @@ func TestKustomizeBuildPatches(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "test", name)
 }
+
+func TestKustomizeBuildValidate(t *testing.T) {
+	appPath, err := testDataDir(t, kustomization8)
+	require.NoError(t, err)
+	kustomize := NewKustomizeApp(appPath, appPath, git.NopCreds{}, "", appPath+"/kustomize.validate")
+
+	objs, _, _, warnings, err := kustomize.Build(&v1alpha1.ApplicationSourceKustomize{Validate: true}, nil, nil, &BuildOpts{PluginHome: "/plugins"})
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Equal(t, "nginx-deployment", objs[0].GetName())
+	assert.Equal(t, []Warning{
+		{Resource: "apps/v1/Deployment/nginx-deployment", Message: "spec.replicas: Invalid type. Expected: integer, given: string"},
+		{Message: "schema of example.com/v1/Database not found, skipping validation"},
+	}, warnings)
+
+	args, err := os.ReadFile(appPath + "/args_output")
+	require.NoError(t, err)
+	assert.Equal(t, "build "+appPath+" --enable-alpha-plugins\n", string(args))
+	env, err := os.ReadFile(appPath + "/env_output")
+	require.NoError(t, err)
+	assert.Equal(t, "KUSTOMIZE_PLUGIN_HOME=/plugins\n", string(env))
+
+	// warnings are ignored, and plugins are not enabled, unless validation is requested
+	objs, _, _, warnings, err = kustomize.Build(&v1alpha1.ApplicationSourceKustomize{}, nil, nil, &BuildOpts{PluginHome: "/plugins"})
+	require.NoError(t, err)
+	require.Len(t, objs, 1)
+	assert.Empty(t, warnings)
+	args, err = os.ReadFile(appPath + "/args_output")
+	require.NoError(t, err)
+	assert.Equal(t, "build "+appPath+"\n", string(args))
+}
+
+func TestKustomizeBuildTimeout(t *testing.T) {
+	appPath, err := testDataDir(t, kustomization8)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(appPath+"/kustomize.hang", []byte("#!/bin/sh\nsleep 60\n"), 0o755))
+	kustomize := NewKustomizeApp(appPath, appPath, git.NopCreds{}, "", appPath+"/kustomize.hang")
+
+	start := time.Now()
+	_, _, _, _, err = kustomize.Build(&v1alpha1.ApplicationSourceKustomize{}, nil, nil, &BuildOpts{Timeout: 100 * time.Millisecond})
+	require.ErrorContains(t, err, "timeout after 100ms")
+	assert.Less(t, time.Since(start), 10*time.Second)
+}
+
+func TestParseWarnings(t *testing.T) {
+	out, warnings := parseWarnings("apiVersion: v1\nkind: ConfigMap\n[WARNING] v1/ConfigMap/config: data.key: Invalid type\n[WARNING]   no schema found\n")
+	assert.Equal(t, "apiVersion: v1\nkind: ConfigMap\n", out)
+	assert.Equal(t, []Warning{
+		{Resource: "v1/ConfigMap/config", Message: "data.key: Invalid type"},
+		{Message: "no schema found"},
+	}, warnings)
+	assert.Equal(t, "v1/ConfigMap/config: data.key: Invalid type", warnings[0].String())
+	assert.Equal(t, "no schema found", warnings[1].String())
+}
@@ -0,0 +1,116 @@
+package kustomize
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	"sigs.k8s.io/yaml"
+)
+
+// OCIBaseFetcher fetches the kustomizations stored as OCI artifacts, which kustomizations list in their resources as
+// oci://registry/repository[:tag|@digest] references
+type OCIBaseFetcher interface {
+	// FetchBase extracts the files of the artifact of the reference, without the oci:// prefix, into dir
+	FetchBase(reference string, dir string) error
+}
+
+const (
+	// ociBasePrefix prefixes the resources of kustomizations stored as OCI artifacts
+	ociBasePrefix = "oci://"
+	// maxOCIBaseDepth is the maximum depth of OCI bases referring to other OCI bases
+	maxOCIBaseDepth = 5
+)
+
+// findKustomization returns the path of the kustomization file of the directory, or an empty string if there is none
+func findKustomization(dir string) string {
+	for _, name := range KustomizationNames {
+		if _, err := os.Stat(filepath.Join(dir, name)); err == nil {
+			return filepath.Join(dir, name)
+		}
+	}
+	return ""
+}
+
+// useOCIBases replaces the OCI bases listed in the resources of the kustomization by the directories their artifacts
+// are extracted to, below a temporary directory, since kustomize cannot fetch them. The bases of the extracted
+// artifacts are resolved the same way. It returns the temporary directory, which the caller must remove once the
+// kustomization is built, or an empty string if the kustomization has no OCI base.
+func (k *kustomize) useOCIBases(fetcher OCIBaseFetcher) (string, error) {
+	tempDir := ""
+	var resolve func(dir string, depth int) error
+	resolve = func(dir string, depth int) error {
+		kustomizationPath := findKustomization(dir)
+		if kustomizationPath == "" {
+			return nil
+		}
+		data, err := os.ReadFile(kustomizationPath)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", kustomizationPath, err)
+		}
+		var kustomization map[string]interface{}
+		if err := yaml.Unmarshal(data, &kustomization); err != nil {
+			return fmt.Errorf("failed to unmarshal %s: %w", kustomizationPath, err)
+		}
+		resources, _ := kustomization["resources"].([]interface{})
+
+		modified := false
+		for i, resource := range resources {
+			name, ok := resource.(string)
+			if !ok || !strings.HasPrefix(name, ociBasePrefix) {
+				continue
+			}
+			if depth >= maxOCIBaseDepth {
+				return fmt.Errorf("OCI base %s exceeds the maximum depth of %d nested OCI bases", name, maxOCIBaseDepth)
+			}
+			if tempDir == "" {
+				if tempDir, err = os.MkdirTemp("", "argocd-kustomize-oci"); err != nil {
+					return fmt.Errorf("failed to create directory of OCI bases: %w", err)
+				}
+			}
+			baseDir, err := os.MkdirTemp(tempDir, strconv.Itoa(depth))
+			if err != nil {
+				return fmt.Errorf("failed to create directory of OCI base %s: %w", name, err)
+			}
+			if err := fetcher.FetchBase(strings.TrimPrefix(name, ociBasePrefix), baseDir); err != nil {
+				return fmt.Errorf("failed to fetch OCI base %s: %w", name, err)
+			}
+			if findKustomization(baseDir) == "" {
+				return fmt.Errorf("OCI base %s has no kustomization", name)
+			}
+			if err := resolve(baseDir, depth+1); err != nil {
+				return err
+			}
+			log.Debugf("Using OCI base %s extracted to %s for kustomization %s", name, baseDir, dir)
+			resources[i] = baseDir
+			modified = true
+		}
+		if !modified {
+			return nil
+		}
+
+		updated, err := yaml.Marshal(kustomization)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", kustomizationPath, err)
+		}
+		info, err := os.Stat(kustomizationPath)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", kustomizationPath, err)
+		}
+		if err := os.WriteFile(kustomizationPath, updated, info.Mode()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", kustomizationPath, err)
+		}
+		return nil
+	}
+
+	if err := resolve(k.path, 0); err != nil {
+		if tempDir != "" {
+			_ = os.RemoveAll(tempDir)
+		}
+		return "", err
+	}
+	return tempDir, nil
+}
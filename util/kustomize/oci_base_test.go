@@ -0,0 +1,149 @@
+package kustomize
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/argoproj/argo-cd/v2/util/git"
+)
+
+// fakeOCIBaseFetcher extracts the kustomizations of the given files, by OCI reference, and records the directories it
+// extracted them to
+type fakeOCIBaseFetcher struct {
+	artifacts map[string]map[string]string
+	fetched   []string
+	dirs      []string
+}
+
+func (f *fakeOCIBaseFetcher) FetchBase(reference string, dir string) error {
+	files, ok := f.artifacts[reference]
+	if !ok {
+		return fmt.Errorf("artifact %s not found", reference)
+	}
+	f.fetched = append(f.fetched, reference)
+	f.dirs = append(f.dirs, dir)
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const (
+	ociBaseKustomization = `apiVersion: kustomize.config.k8s.io/v1beta1
+kind: Kustomization
+resources:
+- deployment.yaml
+`
+	ociBaseDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: guestbook-ui
+`
+)
+
+func newFakeOCIBaseFetcher() *fakeOCIBaseFetcher {
+	return &fakeOCIBaseFetcher{artifacts: map[string]map[string]string{
+		"registry.example.com/argoproj/guestbook-base:v1": {"kustomization.yaml": ociBaseKustomization, "deployment.yaml": ociBaseDeployment},
+	}}
+}
+
+func TestKustomizeBuildOCIBase(t *testing.T) {
+	repoRoot, err := testDataDir(t, "oci_base")
+	require.NoError(t, err)
+	fetcher := newFakeOCIBaseFetcher()
+
+	k := NewKustomizeApp(repoRoot, filepath.Join(repoRoot, "overlay"), git.NopCreds{}, "", filepath.Join(repoRoot, "kustomize.fake"))
+	objs, _, commands, _, err := k.Build(nil, nil, nil, &BuildOpts{OCIBases: fetcher})
+	require.NoError(t, err)
+	require.Len(t, objs, 2)
+	assert.Equal(t, "guestbook-ui", objs[0].GetName())
+	assert.Equal(t, "overlay", objs[1].GetName())
+	assert.Contains(t, commands[0], "OCI bases of the kustomization were pulled")
+
+	// the extracted bases are removed once built
+	assert.Equal(t, []string{"registry.example.com/argoproj/guestbook-base:v1"}, fetcher.fetched)
+	require.Len(t, fetcher.dirs, 1)
+	assert.NoDirExists(t, fetcher.dirs[0])
+}
+
+func TestUseOCIBases(t *testing.T) {
+	newOverlay := func(t *testing.T, resources ...string) *kustomize {
+		t.Helper()
+		dir := t.TempDir()
+		kustomization := "resources:\n- " + strings.Join(resources, "\n- ") + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "kustomization.yaml"), []byte(kustomization), 0o644))
+		return &kustomize{repoRoot: dir, path: dir}
+	}
+	readResources := func(t *testing.T, dir string) []string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, "kustomization.yaml"))
+		require.NoError(t, err)
+		var resources []string
+		for _, line := range strings.Split(string(data), "\n") {
+			if resource, ok := strings.CutPrefix(line, "- "); ok {
+				resources = append(resources, resource)
+			}
+		}
+		return resources
+	}
+
+	t.Run("Nested", func(t *testing.T) {
+		fetcher := newFakeOCIBaseFetcher()
+		fetcher.artifacts["registry.example.com/argoproj/guestbook-prod@sha256:abc"] = map[string]string{
+			"kustomization.yaml": "resources:\n- oci://registry.example.com/argoproj/guestbook-base:v1\n",
+		}
+		k := newOverlay(t, "configmap.yaml", "oci://registry.example.com/argoproj/guestbook-prod@sha256:abc")
+
+		tempDir, err := k.useOCIBases(fetcher)
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = os.RemoveAll(tempDir) })
+		assert.Equal(t, []string{"registry.example.com/argoproj/guestbook-prod@sha256:abc", "registry.example.com/argoproj/guestbook-base:v1"}, fetcher.fetched)
+		assert.Equal(t, []string{"configmap.yaml", fetcher.dirs[0]}, readResources(t, k.path))
+		assert.Equal(t, []string{fetcher.dirs[1]}, readResources(t, fetcher.dirs[0]))
+		for _, dir := range fetcher.dirs {
+			assert.True(t, strings.HasPrefix(dir, tempDir+string(filepath.Separator)))
+		}
+	})
+
+	t.Run("NoOCIBase", func(t *testing.T) {
+		k := newOverlay(t, "../base", "https://github.com/argoproj/argocd-example-apps//guestbook")
+		tempDir, err := k.useOCIBases(newFakeOCIBaseFetcher())
+		require.NoError(t, err)
+		assert.Empty(t, tempDir)
+		assert.Equal(t, []string{"../base", "https://github.com/argoproj/argocd-example-apps//guestbook"}, readResources(t, k.path))
+	})
+
+	t.Run("NotAKustomization", func(t *testing.T) {
+		fetcher := newFakeOCIBaseFetcher()
+		fetcher.artifacts["registry.example.com/argoproj/manifests:v1"] = map[string]string{"deployment.yaml": ociBaseDeployment}
+		k := newOverlay(t, "oci://registry.example.com/argoproj/manifests:v1")
+		_, err := k.useOCIBases(fetcher)
+		require.EqualError(t, err, "OCI base oci://registry.example.com/argoproj/manifests:v1 has no kustomization")
+		assert.NoDirExists(t, filepath.Dir(fetcher.dirs[0]))
+	})
+
+	t.Run("FetchFailed", func(t *testing.T) {
+		k := newOverlay(t, "oci://registry.example.com/argoproj/missing:v1")
+		_, err := k.useOCIBases(newFakeOCIBaseFetcher())
+		require.EqualError(t, err, "failed to fetch OCI base oci://registry.example.com/argoproj/missing:v1: artifact registry.example.com/argoproj/missing:v1 not found")
+	})
+
+	t.Run("Cycle", func(t *testing.T) {
+		fetcher := newFakeOCIBaseFetcher()
+		fetcher.artifacts["registry.example.com/argoproj/cycle:v1"] = map[string]string{
+			"kustomization.yaml": "resources:\n- oci://registry.example.com/argoproj/cycle:v1\n",
+		}
+		k := newOverlay(t, "oci://registry.example.com/argoproj/cycle:v1")
+		_, err := k.useOCIBases(fetcher)
+		require.ErrorContains(t, err, "exceeds the maximum depth of 5 nested OCI bases")
+		assert.Len(t, fetcher.fetched, maxOCIBaseDepth)
+	})
+}
@@ -0,0 +1,126 @@
+package lua
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	lua "github.com/yuin/gopher-lua"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+)
+
+// HealthStatusFunction is the function the scripts of the argocd-health-overrides ConfigMap must define, which
+// returns the health status of the resource passed as its only argument
+const HealthStatusFunction = "health_status"
+
+// healthOverrideCall calls the health_status function defined by a health override script on the resource
+var healthOverrideCall = fmt.Sprintf(`
+if type(%[1]s) ~= "function" then
+  error("the health override script must define the function %[1]s(obj)")
+end
+return %[1]s(obj)`, HealthStatusFunction)
+
+// HealthOverrideScripts are the health override scripts of the argocd-health-overrides ConfigMap, by the kind of the
+// resources whose health they assess
+type HealthOverrideScripts map[schema.GroupVersionKind]string
+
+// GetHealthOverrideKey returns the key of the health override script of the given kind in the argocd-health-overrides
+// ConfigMap. Since ConfigMap keys cannot contain slashes, the group, version and kind are joined by underscores, and
+// the group is omitted for the core group, e.g. apps_v1_Deployment or v1_Pod.
+func GetHealthOverrideKey(gvk schema.GroupVersionKind) string {
+	if gvk.Group == "" {
+		return fmt.Sprintf("%s_%s", gvk.Version, gvk.Kind)
+	}
+	return fmt.Sprintf("%s_%s_%s", gvk.Group, gvk.Version, gvk.Kind)
+}
+
+// ParseHealthOverrideKey parses the kind of a key of the argocd-health-overrides ConfigMap
+func ParseHealthOverrideKey(key string) (schema.GroupVersionKind, error) {
+	parts := strings.Split(key, "_")
+	for _, part := range parts {
+		if part == "" {
+			return schema.GroupVersionKind{}, fmt.Errorf("invalid health override key %q: expected {group}_{version}_{kind}, or {version}_{kind} for the core group", key)
+		}
+	}
+	switch len(parts) {
+	case 2:
+		return schema.GroupVersionKind{Version: parts[0], Kind: parts[1]}, nil
+	case 3:
+		return schema.GroupVersionKind{Group: parts[0], Version: parts[1], Kind: parts[2]}, nil
+	}
+	return schema.GroupVersionKind{}, fmt.Errorf("invalid health override key %q: expected {group}_{version}_{kind}, or {version}_{kind} for the core group", key)
+}
+
+// ValidateHealthOverrideScript returns an error if the script does not compile, or does not define the function
+// health_status(obj)
+func ValidateHealthOverrideScript(script string) error {
+	l, err := VM{}.runLua(&unstructured.Unstructured{Object: map[string]interface{}{}}, script)
+	if err != nil {
+		return err
+	}
+	fn, ok := l.GetGlobal(HealthStatusFunction).(*lua.LFunction)
+	if !ok || fn.Proto == nil {
+		return fmt.Errorf("the health override script must define the function %s(obj)", HealthStatusFunction)
+	}
+	if fn.Proto.NumParameters != 1 || fn.Proto.IsVarArg != 0 {
+		return fmt.Errorf("the function %s must take the resource as its only parameter, it takes %d", HealthStatusFunction, fn.Proto.NumParameters)
+	}
+	return nil
+}
+
+// ParseHealthOverrideScripts parses the data of the argocd-health-overrides ConfigMap. Entries with invalid keys or
+// scripts are left out, and reported by the returned error.
+func ParseHealthOverrideScripts(data map[string]string) (HealthOverrideScripts, error) {
+	scripts := HealthOverrideScripts{}
+	var errs []error
+	for key, script := range data {
+		gvk, err := ParseHealthOverrideKey(key)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := ValidateHealthOverrideScript(script); err != nil {
+			errs = append(errs, fmt.Errorf("invalid health override %s: %w", key, err))
+			continue
+		}
+		scripts[gvk] = script
+	}
+	return scripts, errors.Join(errs...)
+}
+
+// GetResourceHealth returns the health status returned by the health_status function of the script of the kind of
+// the resource, or nil if there is no script for its kind
+func (scripts HealthOverrideScripts) GetResourceHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	script, ok := scripts[obj.GroupVersionKind()]
+	if !ok {
+		return nil, nil
+	}
+	return VM{}.ExecuteHealthLua(obj, script+healthOverrideCall)
+}
+
+// resourceHealthOverride assesses the health of resources with the scripts of the argocd-health-overrides ConfigMap,
+// and else with the health customizations of argocd-cm and the built-in health scripts
+type resourceHealthOverride struct {
+	scripts           HealthOverrideScripts
+	resourceOverrides ResourceHealthOverrides
+}
+
+// NewResourceHealthOverride returns a health override in which the scripts of the argocd-health-overrides ConfigMap take
+// precedence over the health customizations of the resource overrides and the built-in health checks
+func NewResourceHealthOverride(scripts HealthOverrideScripts, resourceOverrides map[string]appv1.ResourceOverride) health.HealthOverride {
+	if len(scripts) == 0 {
+		return ResourceHealthOverrides(resourceOverrides)
+	}
+	return &resourceHealthOverride{scripts: scripts, resourceOverrides: resourceOverrides}
+}
+
+func (o *resourceHealthOverride) GetResourceHealth(obj *unstructured.Unstructured) (*health.HealthStatus, error) {
+	if healthStatus, err := o.scripts.GetResourceHealth(obj); err != nil || healthStatus != nil {
+		return healthStatus, err
+	}
+	return o.resourceOverrides.GetResourceHealth(obj)
+}
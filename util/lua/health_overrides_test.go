@@ -0,0 +1,98 @@
+package lua
+
+import (
+	"testing"
+
+	"github.com/argoproj/gitops-engine/pkg/health"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/argo-cd/v2/test"
+)
+
+const alwaysHealthyScript = `
+function health_status(obj)
+  return {status = "Healthy", message = "Overridden for " .. obj.metadata.name}
+end
+`
+
+func TestHealthOverrideKey(t *testing.T) {
+	deployment := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	pod := schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+	assert.Equal(t, "apps_v1_Deployment", GetHealthOverrideKey(deployment))
+	assert.Equal(t, "v1_Pod", GetHealthOverrideKey(pod))
+
+	for _, gvk := range []schema.GroupVersionKind{deployment, pod, {Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}} {
+		parsed, err := ParseHealthOverrideKey(GetHealthOverrideKey(gvk))
+		require.NoError(t, err)
+		assert.Equal(t, gvk, parsed)
+	}
+	for _, key := range []string{"Deployment", "apps_v1_Deployment_extra", "apps__Deployment", "_v1_Pod"} {
+		_, err := ParseHealthOverrideKey(key)
+		assert.Error(t, err, key)
+	}
+}
+
+func TestValidateHealthOverrideScript(t *testing.T) {
+	require.NoError(t, ValidateHealthOverrideScript(alwaysHealthyScript))
+	assert.ErrorContains(t, ValidateHealthOverrideScript(`return {status = "Healthy"}`), "must define the function health_status(obj)")
+	assert.ErrorContains(t, ValidateHealthOverrideScript(`health_status = "Healthy"`), "must define the function health_status(obj)")
+	assert.ErrorContains(t, ValidateHealthOverrideScript(`function health_status() return {status = "Healthy"} end`), "only parameter")
+	assert.ErrorContains(t, ValidateHealthOverrideScript(`function health_status(obj, other) return {status = "Healthy"} end`), "only parameter")
+	assert.Error(t, ValidateHealthOverrideScript(`function health_status(obj`))
+}
+
+func TestParseHealthOverrideScripts(t *testing.T) {
+	scripts, err := ParseHealthOverrideScripts(map[string]string{
+		"apps_v1_Deployment": alwaysHealthyScript,
+		"v1_Pod":             `return {status = "Healthy"}`,
+		"Service":            alwaysHealthyScript,
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid health override v1_Pod")
+	assert.Contains(t, err.Error(), `invalid health override key "Service"`)
+	assert.Equal(t, HealthOverrideScripts{{Group: "apps", Version: "v1", Kind: "Deployment"}: alwaysHealthyScript}, scripts)
+}
+
+func TestResourceHealthOverride(t *testing.T) {
+	scripts, err := ParseHealthOverrideScripts(map[string]string{"apps_v1_Deployment": alwaysHealthyScript})
+	require.NoError(t, err)
+	// the Deployment is degraded according to the built-in health check and the health customization of argocd-cm
+	deployment := test.NewDeployment()
+	deployment.Object["status"] = map[string]interface{}{"observedGeneration": int64(1), "replicas": int64(1), "unavailableReplicas": int64(1)}
+	resourceOverrides := map[string]appv1.ResourceOverride{
+		"apps/Deployment": {HealthLua: `return {status = "Degraded"}`},
+	}
+
+	t.Run("ScriptTakesPrecedence", func(t *testing.T) {
+		healthStatus, err := health.GetResourceHealth(deployment, NewResourceHealthOverride(scripts, resourceOverrides))
+		require.NoError(t, err)
+		assert.Equal(t, &health.HealthStatus{Status: health.HealthStatusHealthy, Message: "Overridden for " + deployment.GetName()}, healthStatus)
+
+		healthStatus, err = health.GetResourceHealth(deployment, NewResourceHealthOverride(scripts, nil))
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusHealthy, healthStatus.Status)
+	})
+
+	t.Run("FallbackToResourceOverrides", func(t *testing.T) {
+		healthStatus, err := health.GetResourceHealth(deployment, NewResourceHealthOverride(nil, resourceOverrides))
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusDegraded, healthStatus.Status)
+	})
+
+	t.Run("OtherVersion", func(t *testing.T) {
+		otherVersion := deployment.DeepCopy()
+		otherVersion.SetAPIVersion("apps/v1beta2")
+		healthStatus, err := health.GetResourceHealth(otherVersion, NewResourceHealthOverride(scripts, resourceOverrides))
+		require.NoError(t, err)
+		assert.Equal(t, health.HealthStatusDegraded, healthStatus.Status)
+	})
+
+	t.Run("ScriptError", func(t *testing.T) {
+		failing := HealthOverrideScripts{{Group: "apps", Version: "v1", Kind: "Deployment"}: `function health_status(obj) error("malformed") end`}
+		_, err := health.GetResourceHealth(deployment, NewResourceHealthOverride(failing, resourceOverrides))
+		assert.ErrorContains(t, err, "malformed")
+	})
+}
@@ -14,6 +14,14 @@ import (
 	service "github.com/argoproj/argo-cd/v2/util/notification/argocd"
 )
 
+const (
+	// ApplicationSetVar is the key of the object notified by the ApplicationSet controller holding the ApplicationSet
+	ApplicationSetVar = "appset"
+	// ApplicationSetContextVar is the key of the object notified by the ApplicationSet controller holding the values
+	// added to the notification context
+	ApplicationSetContextVar = "context"
+)
+
 func GetFactorySettings(argocdService service.Service, secretName, configMapName string, selfServiceNotificationEnabled bool) api.Settings {
 	return api.Settings{
 		SecretName:    secretName,
@@ -45,6 +53,16 @@ func GetFactorySettingsForCLI(argocdService *service.Service, secretName, config
 	}
 }
 
+// GetApplicationSetFactorySettings returns the settings of the notifications sent by the ApplicationSet controller.
+// Templates and triggers refer to the ApplicationSet as `appset`.
+func GetApplicationSetFactorySettings(secretName, configMapName string) api.Settings {
+	return api.Settings{
+		SecretName:    secretName,
+		ConfigMapName: configMapName,
+		InitGetVars:   initApplicationSetGetVars,
+	}
+}
+
 func getContext(cfg *api.Config, configMap *v1.ConfigMap, secret *v1.Secret) (map[string]string, error) {
 	context := map[string]string{}
 	if contextYaml, ok := configMap.Data["context"]; ok {
@@ -86,3 +104,24 @@ func initGetVars(argocdService service.Service, cfg *api.Config, configMap *v1.C
 		})
 	}, nil
 }
+
+func initApplicationSetGetVars(cfg *api.Config, configMap *v1.ConfigMap, secret *v1.Secret) (api.GetVars, error) {
+	context, err := getContext(cfg, configMap, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(obj map[string]interface{}, dest services.Destination) map[string]interface{} {
+		notificationContext := injectLegacyVar(context, dest.Service)
+		if values, ok := obj[ApplicationSetContextVar].(map[string]string); ok {
+			for k, v := range values {
+				notificationContext[k] = v
+			}
+		}
+		return map[string]interface{}{
+			ApplicationSetVar: obj[ApplicationSetVar],
+			"context":         notificationContext,
+			"secrets":         secret.Data,
+		}
+	}, nil
+}
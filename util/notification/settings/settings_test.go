@@ -93,3 +93,34 @@ func TestInitGetVars(t *testing.T) {
 		assert.Equal(t, result["secrets"], notificationsSecret.Data)
 	})
 }
+
+func TestInitApplicationSetGetVars(t *testing.T) {
+	notificationsCm := corev1.ConfigMap{
+		Data: map[string]string{
+			"context": fmt.Sprintf("%s: %s", testContextKey, testContextKeyValue),
+		},
+	}
+	notificationsSecret := corev1.Secret{
+		Data: map[string][]byte{
+			"notification-secret": []byte("secret-value"),
+		},
+	}
+	varsProvider, err := initApplicationSetGetVars(&api.Config{}, &notificationsCm, &notificationsSecret)
+	require.NoError(t, err)
+
+	appSetData := map[string]interface{}{
+		"metadata": map[string]interface{}{"name": "appset-name"},
+	}
+	result := varsProvider(map[string]interface{}{
+		ApplicationSetVar:        appSetData,
+		ApplicationSetContextVar: map[string]string{"errorMessage": "generator failed"},
+	}, services.Destination{Service: "webhook"})
+
+	assert.Equal(t, appSetData, result["appset"])
+	assert.Equal(t, map[string]string{
+		testContextKey:     testContextKeyValue,
+		"notificationType": "webhook",
+		"errorMessage":     "generator failed",
+	}, result["context"])
+	assert.Equal(t, notificationsSecret.Data, result["secrets"])
+}
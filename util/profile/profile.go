@@ -1,13 +1,35 @@
 package profile
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/pprof"
 	"os"
+	"path/filepath"
+	"runtime"
+	rpprof "runtime/pprof"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
 
 	"github.com/argoproj/argo-cd/v2/util/env"
 )
 
+const (
+	// DefaultAddress is the default listen address of the dedicated profiling server. The server only listens on the
+	// loopback interface by default since its endpoints are not authenticated; use kubectl port-forward to reach it.
+	DefaultAddress = "127.0.0.1"
+	// DefaultPort is the default port of the dedicated profiling server
+	DefaultPort = 6060
+	// DefaultHeapTriggerMB is the default heap usage, in megabytes, above which a heap profile is dumped
+	DefaultHeapTriggerMB = 500
+	// heapCheckInterval is how often the heap usage is compared against the trigger
+	heapCheckInterval = 30 * time.Second
+)
+
 var enableProfilerFilePath = env.StringFromEnv("ARGOCD_ENABLE_PROFILER_FILE_PATH", "/home/argocd/params/profiler.enabled")
 
 func wrapHandler(handler http.HandlerFunc) http.HandlerFunc {
@@ -20,11 +42,104 @@ func wrapHandler(handler http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+func registerHandlers(mux *http.ServeMux, wrap func(http.HandlerFunc) http.HandlerFunc) {
+	mux.HandleFunc("/debug/pprof/", wrap(pprof.Index))
+	mux.HandleFunc("/debug/pprof/cmdline", wrap(pprof.Cmdline))
+	mux.HandleFunc("/debug/pprof/profile", wrap(pprof.Profile))
+	mux.HandleFunc("/debug/pprof/symbol", wrap(pprof.Symbol))
+	mux.HandleFunc("/debug/pprof/trace", wrap(pprof.Trace))
+}
+
 // RegisterProfiler adds pprof endpoints to mux.
 func RegisterProfiler(mux *http.ServeMux) {
-	mux.HandleFunc("/debug/pprof/", wrapHandler(pprof.Index))
-	mux.HandleFunc("/debug/pprof/cmdline", wrapHandler(pprof.Cmdline))
-	mux.HandleFunc("/debug/pprof/profile", wrapHandler(pprof.Profile))
-	mux.HandleFunc("/debug/pprof/symbol", wrapHandler(pprof.Symbol))
-	mux.HandleFunc("/debug/pprof/trace", wrapHandler(pprof.Trace))
+	registerHandlers(mux, wrapHandler)
+}
+
+// NewServer returns a dedicated profiling server listening on the given address and port. The pprof
+// endpoints it serves are not gated by the 'argocd-cmd-params-cm' ConfigMap since the server is
+// explicitly enabled with the --enable-pprof flag, and they are not authenticated: the address
+// should only be a non-loopback one on trusted networks.
+func NewServer(address string, port int) *http.Server {
+	mux := http.NewServeMux()
+	registerHandlers(mux, func(handler http.HandlerFunc) http.HandlerFunc {
+		return handler
+	})
+	return &http.Server{
+		Addr:              net.JoinHostPort(address, strconv.Itoa(port)),
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// HeapProfiler dumps a heap profile to disk whenever the heap usage of the process crosses a
+// threshold. A single profile is written per crossing: the profiler re-arms once the heap usage
+// has dropped back below the threshold.
+type HeapProfiler struct {
+	thresholdBytes uint64
+	dumpPath       string
+	triggered      bool
+	// heapAlloc returns the number of bytes currently allocated on the heap
+	heapAlloc func() uint64
+}
+
+// NewHeapProfiler returns a heap profiler which writes profiles to dumpPath when the heap usage
+// exceeds thresholdMB megabytes
+func NewHeapProfiler(thresholdMB int, dumpPath string) *HeapProfiler {
+	return &HeapProfiler{
+		thresholdBytes: uint64(thresholdMB) * 1024 * 1024,
+		dumpPath:       dumpPath,
+		heapAlloc: func() uint64 {
+			var stats runtime.MemStats
+			runtime.ReadMemStats(&stats)
+			return stats.HeapAlloc
+		},
+	}
+}
+
+// Run periodically checks the heap usage until the context is cancelled
+func (p *HeapProfiler) Run(ctx context.Context) {
+	ticker := time.NewTicker(heapCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if path, err := p.check(now); err != nil {
+				log.Warnf("Failed to write heap profile: %v", err)
+			} else if path != "" {
+				log.Infof("Heap usage exceeded %d MB, heap profile written to %s", p.thresholdBytes/1024/1024, path)
+			}
+		}
+	}
+}
+
+// check writes a heap profile if the heap usage just crossed the threshold and returns the path
+// of the written profile, or an empty string if no profile was written
+func (p *HeapProfiler) check(now time.Time) (string, error) {
+	if p.heapAlloc() < p.thresholdBytes {
+		p.triggered = false
+		return "", nil
+	}
+	if p.triggered {
+		return "", nil
+	}
+	p.triggered = true
+	return writeHeapProfile(p.dumpPath, now)
+}
+
+func writeHeapProfile(dir string, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("error creating heap profile directory %s: %w", dir, err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("heap-%s.pprof", now.UTC().Format("20060102T150405Z")))
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("error creating heap profile file %s: %w", path, err)
+	}
+	defer f.Close()
+	if err := rpprof.Lookup("heap").WriteTo(f, 0); err != nil {
+		return "", fmt.Errorf("error writing heap profile: %w", err)
+	}
+	return path, nil
 }
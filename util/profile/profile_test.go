@@ -4,7 +4,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -46,3 +48,49 @@ func TestRegisterProfile_FileExist(t *testing.T) {
 		_ = os.Remove(f.Name())
 	}()
 }
+
+func TestNewServer(t *testing.T) {
+	server := NewServer(DefaultAddress, DefaultPort)
+	require.Equal(t, "127.0.0.1:6060", server.Addr)
+	require.Equal(t, "[::1]:6061", NewServer("::1", 6061).Addr)
+
+	srv := httptest.NewServer(server.Handler)
+	defer srv.Close()
+
+	// endpoints of the dedicated server are not gated by the params file
+	resp, err := http.Get(srv.URL + "/debug/pprof/heap")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+func TestHeapProfiler_Check(t *testing.T) {
+	dir := t.TempDir()
+	heap := uint64(0)
+	p := NewHeapProfiler(1, dir)
+	p.heapAlloc = func() uint64 {
+		return heap
+	}
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	path, err := p.check(now)
+	require.NoError(t, err)
+	require.Empty(t, path, "no profile expected below the threshold")
+
+	heap = 2 * 1024 * 1024
+	path, err = p.check(now)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "heap-20240102T030405Z.pprof"), path)
+	require.FileExists(t, path)
+
+	path, err = p.check(now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Empty(t, path, "no profile expected until the heap drops below the threshold")
+
+	heap = 0
+	_, err = p.check(now.Add(2 * time.Minute))
+	require.NoError(t, err)
+	heap = 2 * 1024 * 1024
+	path, err = p.check(now.Add(3 * time.Minute))
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(dir, "heap-20240102T030705Z.pprof"), path)
+}
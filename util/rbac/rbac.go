@@ -240,6 +240,39 @@ func (e *Enforcer) Enforce(rvals ...interface{}) bool {
 	return enforce(e.getCabinEnforcer("", ""), e.defaultRole, e.claimsEnforcerFunc, rvals...)
 }
 
+// HasRole returns whether one of the given subjects is assigned the given role, directly or through the roles assigned
+// to it, or whether the role is the default role
+func (e *Enforcer) HasRole(role string, subjects ...string) bool {
+	if e.defaultRole == role {
+		return true
+	}
+	grouping, err := e.getCabinEnforcer("", "").GetGroupingPolicy()
+	if err != nil {
+		log.Warnf("Failed to get role assignments: %v", err)
+		return false
+	}
+	visited := map[string]bool{}
+	pending := subjects
+	for len(pending) > 0 {
+		subject := pending[0]
+		pending = pending[1:]
+		if visited[subject] {
+			continue
+		}
+		visited[subject] = true
+		for _, assignment := range grouping {
+			if len(assignment) < 2 || assignment[0] != subject {
+				continue
+			}
+			if assignment[1] == role {
+				return true
+			}
+			pending = append(pending, assignment[1])
+		}
+	}
+	return false
+}
+
 // EnforceErr is a convenience helper to wrap a failed enforcement with a detailed error about the request
 func (e *Enforcer) EnforceErr(rvals ...interface{}) error {
 	if !e.Enforce(rvals...) {
@@ -189,6 +189,25 @@ func TestDefaultRole(t *testing.T) {
 	assert.True(t, enf.Enforce("bob", "applications", "get", "foo/bar"))
 }
 
+func TestHasRole(t *testing.T) {
+	kubeclientset := fake.NewSimpleClientset()
+	enf := NewEnforcer(kubeclientset, fakeNamespace, fakeConfigMapName, nil)
+	require.NoError(t, enf.syncUpdate(fakeConfigMap(), noOpUpdate))
+	_ = enf.SetBuiltinPolicy(assets.BuiltinPolicyCSV)
+	_ = enf.SetUserPolicy("g, platform-team, role:platform\ng, role:platform, role:admin\ng, alice, role:readonly")
+
+	// built-in assignment of the admin user
+	assert.True(t, enf.HasRole("role:admin", "admin"))
+	// assignment through another role
+	assert.True(t, enf.HasRole("role:admin", "bob", "platform-team"))
+	assert.True(t, enf.HasRole("role:readonly", "platform-team"))
+	assert.False(t, enf.HasRole("role:admin", "alice"))
+	assert.False(t, enf.HasRole("role:admin"))
+
+	enf.SetDefaultRole("role:readonly")
+	assert.True(t, enf.HasRole("role:readonly", "bob"))
+}
+
 // TestURLAsObjectName tests the ability to have a URL as an object name
 func TestURLAsObjectName(t *testing.T) {
 	kubeclientset := fake.NewSimpleClientset()
@@ -22,6 +22,7 @@ const (
 	accountPasswordMtimeSuffix = "passwordMtime"
 	accountEnabledSuffix       = "enabled"
 	accountTokensSuffix        = "tokens"
+	accountManagedBySuffix     = "managedBy"
 
 	// Admin superuser password storage
 	// settingAdminPasswordHashKey designates the key for a root password hash inside a Kubernetes secret.
@@ -41,6 +42,13 @@ const (
 	AccountCapabilityApiKey AccountCapability = "apiKey"
 )
 
+const (
+	// AccountManagedBySCIM marks the accounts created by the SCIM API.
+	AccountManagedBySCIM = "scim"
+	// AccountManagedByLDAPSync marks the accounts created by the LDAP group sync.
+	AccountManagedByLDAPSync = "ldap-sync"
+)
+
 // Token holds the information about the generated auth token.
 type Token struct {
 	ID        string `json:"id"`
@@ -55,6 +63,8 @@ type Account struct {
 	Enabled       bool
 	Capabilities  []AccountCapability
 	Tokens        []Token
+	// ManagedBy is the component which created the account and manages it, if any
+	ManagedBy string
 }
 
 // FormatPasswordMtime return the formatted password modify time or empty string of password modify time is nil.
@@ -114,6 +124,18 @@ func (mgr *SettingsManager) AddAccount(name string, account Account) error {
 	return mgr.saveAccount(name, account)
 }
 
+// RemoveAccount removes the local account with the specified name. The admin account cannot be removed.
+func (mgr *SettingsManager) RemoveAccount(name string) error {
+	if name == common.ArgoCDAdminUsername {
+		return status.Errorf(codes.InvalidArgument, "account '%s' cannot be removed", name)
+	}
+	if _, err := mgr.GetAccount(name); err != nil {
+		return err
+	}
+	// saving an account with default properties removes all of its keys
+	return mgr.saveAccount(name, Account{Enabled: true, Tokens: []Token{}})
+}
+
 // GetAccount return an account info by the specified name.
 func (mgr *SettingsManager) GetAccount(name string) (*Account, error) {
 	accounts, err := mgr.GetAccounts()
@@ -198,6 +220,7 @@ func saveAccount(secret *v1.Secret, cm *v1.ConfigMap, name string, account Accou
 		updateAccountSecret(secret, fmt.Sprintf("%s.%s.%s", accountsKeyPrefix, name, accountTokensSuffix), string(tokens), "[]")
 		updateAccountMap(cm, fmt.Sprintf("%s.%s.%s", accountsKeyPrefix, name, accountEnabledSuffix), strconv.FormatBool(account.Enabled), "true")
 		updateAccountMap(cm, fmt.Sprintf("%s.%s", accountsKeyPrefix, name), account.FormatCapabilities(), "")
+		updateAccountMap(cm, fmt.Sprintf("%s.%s.%s", accountsKeyPrefix, name, accountManagedBySuffix), account.ManagedBy, "")
 	}
 	return nil
 }
@@ -287,6 +310,8 @@ func parseAccounts(secret *v1.Secret, cm *v1.ConfigMap) (map[string]Account, err
 			if err != nil {
 				return nil, err
 			}
+		case accountManagedBySuffix:
+			account.ManagedBy = val
 		}
 		accounts[accountName] = account
 	}
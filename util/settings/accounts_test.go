@@ -92,6 +92,26 @@ func TestGetAccount_WithInvalidToken(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestRemoveAccount(t *testing.T) {
+	_, settingsManager := fixtures(map[string]string{
+		"accounts.test":         "login",
+		"accounts.test.enabled": "false",
+	}, func(secret *v1.Secret) {
+		secret.Data["accounts.test.password"] = []byte("hash")
+		secret.Data["accounts.test.tokens"] = []byte(`[{"id":"123","iat":1583789194}]`)
+	})
+
+	require.NoError(t, settingsManager.RemoveAccount("test"))
+	_, err := settingsManager.GetAccount("test")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	err = settingsManager.RemoveAccount("test")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	err = settingsManager.RemoveAccount(common.ArgoCDAdminUsername)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
 func TestGetAdminAccount(t *testing.T) {
 	mTime := time.Now().Format(time.RFC3339)
 	_, settingsManager := fixtures(nil, func(secret *v1.Secret) {
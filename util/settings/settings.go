@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"path"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -26,6 +27,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	v1 "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	v1listers "k8s.io/client-go/listers/core/v1"
@@ -41,6 +43,7 @@ import (
 	"github.com/argoproj/argo-cd/v2/util"
 	"github.com/argoproj/argo-cd/v2/util/crypto"
 	"github.com/argoproj/argo-cd/v2/util/kube"
+	"github.com/argoproj/argo-cd/v2/util/lua"
 	"github.com/argoproj/argo-cd/v2/util/password"
 	tlsutil "github.com/argoproj/argo-cd/v2/util/tls"
 )
@@ -292,11 +295,10 @@ func (ks *KustomizeSettings) GetOptions(source v1alpha1.ApplicationSource) (*v1a
 				break
 			}
 		}
-		if binaryPath == "" {
-			return nil, fmt.Errorf("kustomize version %s is not registered", source.Kustomize.Version)
-		}
-	} else {
-		// add build options for the default version
+	}
+	if binaryPath == "" {
+		// add build options for the default version; versions which are not registered in argocd-cm
+		// are resolved by the repo-server using the binaries passed with --kustomize-versions
 		buildOptions = ks.BuildOptions
 	}
 	return &v1alpha1.KustomizeOptions{
@@ -380,6 +382,18 @@ type RepositoryCredentials struct {
 }
 
 // DeepLink structure
+// ParametersSchema is a JSON schema which the parameters of matching application sources must conform to
+type ParametersSchema struct {
+	// RepoURL is the URL of the repository of matching sources
+	RepoURL string `json:"repoURL"`
+	// Chart is the Helm chart name of matching sources, if sourced from a Helm repository
+	Chart string `json:"chart,omitempty"`
+	// Path is the path within the Git repository of matching sources
+	Path string `json:"path,omitempty"`
+	// Schema is the JSON schema of the Helm values or of the plugin parameters of matching sources
+	Schema string `json:"schema"`
+}
+
 type DeepLink struct {
 	// URL that the deep link will redirect to
 	URL string `json:"url"`
@@ -442,6 +456,9 @@ const (
 	settingsWebhookAzureDevOpsPasswordKey = "webhook.azuredevops.password"
 	// settingsWebhookMaxPayloadSize is the key for the maximum payload size for webhooks in MB
 	settingsWebhookMaxPayloadSizeMB = "webhook.maxPayloadSizeMB"
+	// settingsSyncWindowOverrideCooldownKey is the key for the minimum duration between two syncs of an application
+	// overriding sync windows
+	settingsSyncWindowOverrideCooldownKey = "syncWindowOverride.cooldown"
 	// settingsApplicationInstanceLabelKey is the key to configure injected app instance label key
 	settingsApplicationInstanceLabelKey = "application.instanceLabelKey"
 	// settingsResourceTrackingMethodKey is the key to configure tracking method for application resources
@@ -519,6 +536,8 @@ const (
 	// ResourceDeepLinks is the resource deep link key
 	ResourceDeepLinks = "resource.links"
 	extensionConfig   = "extension.config"
+	// parametersSchemasKey is the key to configure JSON schemas validating the parameters of application sources
+	parametersSchemasKey = "application.parametersSchemas"
 	// RespectRBAC is the key to configure argocd to respect rbac while watching for resources
 	RespectRBAC            = "resource.respectRBAC"
 	RespectRBACValueStrict = "strict"
@@ -528,6 +547,8 @@ const (
 const (
 	// default max webhook payload size is 1GB
 	defaultMaxWebhookPayloadSize = int64(1) * 1024 * 1024 * 1024
+	// default cooldown between two syncs of an application overriding sync windows
+	defaultSyncWindowOverrideCooldown = time.Hour
 )
 
 var sourceTypeToEnableGenerationKey = map[v1alpha1.ApplicationSourceType]string{
@@ -552,6 +573,10 @@ type SettingsManager struct {
 	reposCache            []Repository
 	repoCredsCache        []RepositoryCredentials
 	reposOrClusterChanged func()
+	// healthOverrideScripts are the parsed scripts of the argocd-health-overrides ConfigMap, which are parsed again
+	// only when the resource version of the ConfigMap changes, since validating the scripts runs them
+	healthOverrideScripts        lua.HealthOverrideScripts
+	healthOverrideScriptsVersion string
 }
 
 type incompleteSettingsError struct {
@@ -810,6 +835,21 @@ func (mgr *SettingsManager) GetServerRBACLogEnforceEnable() (bool, error) {
 	return strconv.ParseBool(argoCDCM.Data[settingsServerRBACLogEnforceEnableKey])
 }
 
+// GetSyncWindowOverrideCooldown returns the minimum duration between two syncs of an application overriding sync
+// windows
+func (mgr *SettingsManager) GetSyncWindowOverrideCooldown() (time.Duration, error) {
+	argoCDCM, err := mgr.getConfigMap()
+	if err != nil {
+		return defaultSyncWindowOverrideCooldown, err
+	}
+
+	if argoCDCM.Data[settingsSyncWindowOverrideCooldownKey] == "" {
+		return defaultSyncWindowOverrideCooldown, nil
+	}
+
+	return time.ParseDuration(argoCDCM.Data[settingsSyncWindowOverrideCooldownKey])
+}
+
 func (mgr *SettingsManager) GetMaxPodLogsToRender() (int64, error) {
 	argoCDCM, err := mgr.getConfigMap()
 	if err != nil {
@@ -838,6 +878,22 @@ func (mgr *SettingsManager) GetDeepLinks(deeplinkType string) ([]DeepLink, error
 	return deepLinks, nil
 }
 
+// GetParametersSchemas returns the JSON schemas validating the parameters of application sources
+func (mgr *SettingsManager) GetParametersSchemas() ([]ParametersSchema, error) {
+	argoCDCM, err := mgr.getConfigMap()
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving argocd-cm: %w", err)
+	}
+	schemas := make([]ParametersSchema, 0)
+	if value, ok := argoCDCM.Data[parametersSchemasKey]; ok {
+		err := yaml.Unmarshal([]byte(value), &schemas)
+		if err != nil {
+			return nil, fmt.Errorf("error unmarshalling parameters schemas: %w", err)
+		}
+	}
+	return schemas, nil
+}
+
 func (mgr *SettingsManager) GetEnabledSourceTypes() (map[string]bool, error) {
 	argoCDCM, err := mgr.getConfigMap()
 	if err != nil {
@@ -857,6 +913,106 @@ func (mgr *SettingsManager) GetEnabledSourceTypes() (map[string]bool, error) {
 	return res, nil
 }
 
+// GetHealthOverrideScripts returns the Lua health checks of the argocd-health-overrides ConfigMap, which take
+// precedence over the health customizations of argocd-cm and the built-in health checks. Invalid entries are logged and
+// left out.
+func (mgr *SettingsManager) GetHealthOverrideScripts() (lua.HealthOverrideScripts, error) {
+	cm, err := mgr.GetConfigMapByName(common.ArgoCDHealthOverridesConfigMapName)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error retrieving config map %s: %w", common.ArgoCDHealthOverridesConfigMapName, err)
+	}
+	mgr.mutex.Lock()
+	defer mgr.mutex.Unlock()
+	if mgr.healthOverrideScripts == nil || cm.ResourceVersion == "" || mgr.healthOverrideScriptsVersion != cm.ResourceVersion {
+		scripts, err := lua.ParseHealthOverrideScripts(cm.Data)
+		if err != nil {
+			log.Warnf("Ignoring invalid entries of %s: %v", common.ArgoCDHealthOverridesConfigMapName, err)
+		}
+		mgr.healthOverrideScripts = scripts
+		mgr.healthOverrideScriptsVersion = cm.ResourceVersion
+	}
+	return mgr.healthOverrideScripts, nil
+}
+
+// GetDiffPlugins returns the names of the diff plugins of the argocd-diff-plugins ConfigMap, by the kind of the
+// resources they diff instead of the default diff. Since ConfigMap keys cannot contain slashes, the keys are the group
+// and kind joined by an underscore, or the kind alone for the core group, e.g. bitnami.com_SealedSecret or Secret.
+// Invalid keys are logged and left out.
+func (mgr *SettingsManager) GetDiffPlugins() (map[schema.GroupKind]string, error) {
+	cm, err := mgr.GetConfigMapByName(common.ArgoCDDiffPluginsConfigMapName)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error retrieving config map %s: %w", common.ArgoCDDiffPluginsConfigMapName, err)
+	}
+	plugins := map[schema.GroupKind]string{}
+	for key, name := range cm.Data {
+		gk, err := ParseDiffPluginKey(key)
+		if err != nil {
+			log.Warnf("Ignoring invalid entry of %s: %v", common.ArgoCDDiffPluginsConfigMapName, err)
+			continue
+		}
+		plugins[gk] = strings.TrimSpace(name)
+	}
+	return plugins, nil
+}
+
+// ParseDiffPluginKey parses the kind of a key of the argocd-diff-plugins ConfigMap
+func ParseDiffPluginKey(key string) (schema.GroupKind, error) {
+	i := strings.LastIndex(key, "_")
+	gk := schema.GroupKind{Group: key[:max(i, 0)], Kind: key[i+1:]}
+	if gk.Kind == "" || (i >= 0 && gk.Group == "") {
+		return schema.GroupKind{}, fmt.Errorf("invalid diff plugin key %q: expected {group}_{kind}, or {kind} for the core group", key)
+	}
+	return gk, nil
+}
+
+// ResourceAnnotationTemplate is an entry of the argocd-resource-annotations ConfigMap, producing the annotations
+// injected into the resources applied by syncs
+type ResourceAnnotationTemplate struct {
+	// Name is the key of the entry
+	Name string `json:"-"`
+	// GVK is a glob pattern matched against the API version and kind of the resources joined by a slash, e.g.
+	// apps/v1/Deployment, v1/ConfigMap or */Deployment
+	GVK string `json:"gvk"`
+	// Template is a Go template producing the annotations as a YAML map
+	Template string `json:"template"`
+}
+
+// GetResourceAnnotationTemplates returns the entries of the argocd-resource-annotations ConfigMap, sorted by their key.
+// Invalid entries are logged and left out.
+func (mgr *SettingsManager) GetResourceAnnotationTemplates() ([]ResourceAnnotationTemplate, error) {
+	cm, err := mgr.GetConfigMapByName(common.ArgoCDResourceAnnotationsConfigMapName)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error retrieving config map %s: %w", common.ArgoCDResourceAnnotationsConfigMapName, err)
+	}
+	templates := make([]ResourceAnnotationTemplate, 0, len(cm.Data))
+	for name, data := range cm.Data {
+		var tmpl ResourceAnnotationTemplate
+		if err := yaml.UnmarshalStrict([]byte(data), &tmpl); err != nil {
+			log.Warnf("Ignoring invalid entry %s of %s: %v", name, common.ArgoCDResourceAnnotationsConfigMapName, err)
+			continue
+		}
+		if tmpl.GVK == "" || tmpl.Template == "" {
+			log.Warnf("Ignoring invalid entry %s of %s: gvk and template are required", name, common.ArgoCDResourceAnnotationsConfigMapName)
+			continue
+		}
+		tmpl.Name = name
+		templates = append(templates, tmpl)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
 func (mgr *SettingsManager) GetIgnoreResourceUpdatesOverrides() (map[string]v1alpha1.ResourceOverride, error) {
 	compareOptions, err := mgr.GetResourceCompareOptions()
 	if err != nil {
@@ -23,6 +23,7 @@ import (
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/fake"
 )
 
@@ -224,6 +225,22 @@ func TestGetServerRBACLogEnforceEnableKey(t *testing.T) {
 	assert.True(t, serverRBACLogEnforceEnable)
 }
 
+func TestGetSyncWindowOverrideCooldown(t *testing.T) {
+	_, settingsManager := fixtures(map[string]string{
+		"syncWindowOverride.cooldown": "30m",
+	})
+	cooldown, err := settingsManager.GetSyncWindowOverrideCooldown()
+	require.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, cooldown)
+}
+
+func TestGetSyncWindowOverrideCooldownDefault(t *testing.T) {
+	_, settingsManager := fixtures(nil)
+	cooldown, err := settingsManager.GetSyncWindowOverrideCooldown()
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, cooldown)
+}
+
 func TestGetResourceOverrides(t *testing.T) {
 	ignoreStatus := v1alpha1.ResourceOverride{IgnoreDifferences: v1alpha1.OverrideIgnoreDiff{
 		JSONPointers: []string{"/status"},
@@ -775,10 +792,13 @@ func TestKustomizeSettings_GetOptions(t *testing.T) {
 	}
 
 	t.Run("VersionDoesNotExist", func(t *testing.T) {
-		_, err := settings.GetOptions(v1alpha1.ApplicationSource{
+		// unregistered versions are resolved by the repo-server
+		ver, err := settings.GetOptions(v1alpha1.ApplicationSource{
 			Kustomize: &v1alpha1.ApplicationSourceKustomize{Version: "v4"},
 		})
-		require.Error(t, err)
+		require.NoError(t, err)
+		assert.Equal(t, "", ver.BinaryPath)
+		assert.Equal(t, "--opt1 val1", ver.BuildOptions)
 	})
 
 	t.Run("DefaultBuildOptions", func(t *testing.T) {
@@ -1402,6 +1422,28 @@ func TestGetEnableManifestGeneration(t *testing.T) {
 	}
 }
 
+func TestGetParametersSchemas(t *testing.T) {
+	_, settingsManager := fixtures(map[string]string{
+		"application.parametersSchemas": `
+- repoURL: https://charts.example.com
+  chart: my-chart
+  schema: |
+    {"type": "object", "required": ["replicas"]}
+`,
+	})
+	schemas, err := settingsManager.GetParametersSchemas()
+	require.NoError(t, err)
+	require.Len(t, schemas, 1)
+	assert.Equal(t, "https://charts.example.com", schemas[0].RepoURL)
+	assert.Equal(t, "my-chart", schemas[0].Chart)
+	assert.JSONEq(t, `{"type": "object", "required": ["replicas"]}`, schemas[0].Schema)
+
+	_, settingsManager = fixtures(map[string]string{})
+	schemas, err = settingsManager.GetParametersSchemas()
+	require.NoError(t, err)
+	assert.Empty(t, schemas)
+}
+
 func TestGetHelmSettings(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -1725,3 +1767,77 @@ func TestRedirectAdditionalURLs(t *testing.T) {
 		})
 	}
 }
+
+func TestSettingsManager_GetDiffPlugins(t *testing.T) {
+	_, settingsManager := fixtures(nil)
+	plugins, err := settingsManager.GetDiffPlugins()
+	require.NoError(t, err)
+	assert.Empty(t, plugins)
+
+	kubeClient, settingsManager := fixtures(nil)
+	_, err = kubeClient.CoreV1().ConfigMaps("default").Create(context.Background(), &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ArgoCDDiffPluginsConfigMapName,
+			Namespace: "default",
+			Labels:    map[string]string{"app.kubernetes.io/part-of": "argocd"},
+		},
+		Data: map[string]string{
+			"bitnami.com_SealedSecret": "redact-secret-values",
+			"Secret":                   " redact-secret-values\n",
+			"_Secret":                  "invalid",
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	plugins, err = settingsManager.GetDiffPlugins()
+	require.NoError(t, err)
+	assert.Equal(t, map[schema.GroupKind]string{
+		{Group: "bitnami.com", Kind: "SealedSecret"}: "redact-secret-values",
+		{Kind: "Secret"}: "redact-secret-values",
+	}, plugins)
+}
+
+func TestSettingsManager_GetResourceAnnotationTemplates(t *testing.T) {
+	_, settingsManager := fixtures(nil)
+	templates, err := settingsManager.GetResourceAnnotationTemplates()
+	require.NoError(t, err)
+	assert.Empty(t, templates)
+
+	kubeClient, settingsManager := fixtures(nil)
+	_, err = kubeClient.CoreV1().ConfigMaps("default").Create(context.Background(), &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      common.ArgoCDResourceAnnotationsConfigMapName,
+			Namespace: "default",
+			Labels:    map[string]string{"app.kubernetes.io/part-of": "argocd"},
+		},
+		Data: map[string]string{
+			"team":        "gvk: '*'\ntemplate: 'team: {{ .project }}'\n",
+			"cost-center": "gvk: apps/v1/Deployment\ntemplate: |\n  cost-center: {{ .appName }}\n",
+			"no-template": "gvk: '*'\n",
+			"unknown":     "gvk: '*'\ntemplate: 'a: b'\nkinds: Deployment\n",
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	templates, err = settingsManager.GetResourceAnnotationTemplates()
+	require.NoError(t, err)
+	assert.Equal(t, []ResourceAnnotationTemplate{
+		{Name: "cost-center", GVK: "apps/v1/Deployment", Template: "cost-center: {{ .appName }}\n"},
+		{Name: "team", GVK: "*", Template: "team: {{ .project }}"},
+	}, templates)
+}
+
+func TestParseDiffPluginKey(t *testing.T) {
+	gk, err := ParseDiffPluginKey("bitnami.com_SealedSecret")
+	require.NoError(t, err)
+	assert.Equal(t, schema.GroupKind{Group: "bitnami.com", Kind: "SealedSecret"}, gk)
+
+	gk, err = ParseDiffPluginKey("Secret")
+	require.NoError(t, err)
+	assert.Equal(t, schema.GroupKind{Kind: "Secret"}, gk)
+
+	for _, key := range []string{"", "_Secret", "bitnami.com_"} {
+		_, err = ParseDiffPluginKey(key)
+		assert.ErrorContains(t, err, "expected {group}_{kind}, or {kind} for the core group")
+	}
+}